@@ -20,7 +20,11 @@ PROTOC := docker run --rm -u ${shell id -u} -v${PWD}:${PWD} -w${PWD} ${DOCKER_PR
 
 PATCHED_OTEL_PROTO_DIR = proto-gen/.patched-otel-proto
 
+# The API v2 protos extended ahead of the IDL repository take precedence over the idl submodule.
+API_V2_PROTO_DIR = model/proto/api_v2
+
 PROTO_INCLUDES := \
+	-I$(API_V2_PROTO_DIR) \
 	-Iidl/proto/api_v2 \
 	-Iidl/proto/api_v3 \
 	-Imodel/proto/metrics \
@@ -88,12 +92,12 @@ proto: proto-model \
 
 .PHONY: proto-model
 proto-model:
-	$(call proto_compile, model, idl/proto/api_v2/model.proto)
+	$(call proto_compile, model, $(API_V2_PROTO_DIR)/model.proto)
 	$(PROTOC) -Imodel/proto --go_out=$(PWD)/model/ model/proto/model_test.proto
 
 .PHONY: proto-api-v2
 proto-api-v2:
-	$(call proto_compile, proto-gen/api_v2, idl/proto/api_v2/query.proto)
+	$(call proto_compile, proto-gen/api_v2, $(API_V2_PROTO_DIR)/query.proto)
 	$(call proto_compile, proto-gen/api_v2, idl/proto/api_v2/collector.proto)
 	$(call proto_compile, proto-gen/api_v2, idl/proto/api_v2/sampling.proto)
 
@@ -26,14 +26,17 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/agent/app/processors"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 )
 
 // Agent is a composition of all services / components
 type Agent struct {
 	processors []processors.Processor
 	httpServer *http.Server
-	httpAddr   atomic.Value // string, set once agent starts listening
-	logger     *zap.Logger
+	// httpAddressFamily is set by the builder, the HTTP server listens on both families by default
+	httpAddressFamily netutils.AddressFamily
+	httpAddr          atomic.Value // string, set once agent starts listening
+	logger            *zap.Logger
 }
 
 // NewAgent creates the new Agent.
@@ -60,7 +63,7 @@ func (a *Agent) GetHTTPRouter() *mux.Router {
 // It returns an error when it's immediately apparent on startup, but
 // any errors happening after starting the servers are only logged.
 func (a *Agent) Run() error {
-	listener, err := net.Listen("tcp", a.httpServer.Addr)
+	listener, err := netutils.Listen(a.httpAddressFamily, a.httpServer.Addr)
 	if err != nil {
 		return err
 	}
@@ -32,6 +32,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/agent/app/servers"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/servers/thriftudp"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/ports"
 	agentThrift "github.com/jaegertracing/jaeger/thrift-gen/agent"
 )
@@ -90,11 +91,15 @@ type ServerConfiguration struct {
 	MaxPacketSize    int    `yaml:"maxPacketSize"`
 	SocketBufferSize int    `yaml:"socketBufferSize"`
 	HostPort         string `yaml:"hostPort" validate:"nonzero"`
+	// AddressFamily restricts the addresses that the server listens on to IPv4 or IPv6
+	AddressFamily netutils.AddressFamily `yaml:"addressFamily"`
 }
 
 // HTTPServerConfiguration holds config for a server providing sampling strategies and baggage restrictions to clients
 type HTTPServerConfiguration struct {
 	HostPort string `yaml:"hostPort" validate:"nonzero"`
+	// AddressFamily restricts the addresses that the server listens on to IPv4 or IPv6
+	AddressFamily netutils.AddressFamily `yaml:"addressFamily"`
 }
 
 // WithReporter adds auxiliary reporters.
@@ -111,9 +116,15 @@ func (b *Builder) CreateAgent(primaryProxy CollectorProxy, logger *zap.Logger, m
 		return nil, fmt.Errorf("cannot create processors: %w", err)
 	}
 	server := b.HTTPServer.getHTTPServer(primaryProxy.GetManager(), mFactory, logger)
+	httpAddressFamily, err := netutils.ParseAddressFamily(string(b.HTTPServer.AddressFamily))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create HTTP server: %w", err)
+	}
 	b.publishOpts(mFactory)
 
-	return NewAgent(processors, server, logger), nil
+	agent := NewAgent(processors, server, logger)
+	agent.httpAddressFamily = httpAddressFamily
+	return agent, nil
 }
 
 func (b *Builder) getReporter(primaryProxy CollectorProxy) reporter.Reporter {
@@ -210,7 +221,11 @@ func (c *ServerConfiguration) getUDPServer(mFactory metrics.Factory) (servers.Se
 	if c.HostPort == "" {
 		return nil, fmt.Errorf("no host:port provided for udp server: %+v", *c)
 	}
-	transport, err := thriftudp.NewTUDPServerTransport(c.HostPort)
+	addressFamily, err := netutils.ParseAddressFamily(string(c.AddressFamily))
+	if err != nil {
+		return nil, err
+	}
+	transport, err := thriftudp.NewTUDPServerTransportOnNetwork(addressFamily.Network("udp"), c.HostPort)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create UDPServerTransport: %w", err)
 	}
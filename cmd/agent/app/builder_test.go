@@ -36,6 +36,7 @@ import (
 	"github.com/jaegertracing/jaeger/internal/metrics/fork"
 	"github.com/jaegertracing/jaeger/internal/metricstest"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
@@ -133,16 +134,18 @@ func TestBuilderWithExtraReporter(t *testing.T) {
 
 func TestBuilderWithProcessorErrors(t *testing.T) {
 	testCases := []struct {
-		model       Model
-		protocol    Protocol
-		hostPort    string
-		err         string
-		errContains string
+		model         Model
+		protocol      Protocol
+		hostPort      string
+		addressFamily netutils.AddressFamily
+		err           string
+		errContains   string
 	}{
 		{protocol: Protocol("bad"), err: "cannot find protocol factory for protocol bad"},
 		{protocol: compactProtocol, model: Model("bad"), err: "cannot find agent processor for data model bad"},
-		{protocol: compactProtocol, model: jaegerModel, err: "no host:port provided for udp server: {QueueSize:1000 MaxPacketSize:65000 SocketBufferSize:0 HostPort:}"},
+		{protocol: compactProtocol, model: jaegerModel, err: "no host:port provided for udp server: {QueueSize:1000 MaxPacketSize:65000 SocketBufferSize:0 HostPort: AddressFamily:}"},
 		{protocol: compactProtocol, model: zipkinModel, hostPort: "bad-host-port", errContains: "bad-host-port"},
+		{protocol: compactProtocol, model: jaegerModel, hostPort: ":0", addressFamily: "ipx", err: `invalid address family "ipx"`},
 	}
 	for _, tc := range testCases {
 		testCase := tc // capture loop var
@@ -152,7 +155,8 @@ func TestBuilderWithProcessorErrors(t *testing.T) {
 					Model:    testCase.model,
 					Protocol: testCase.protocol,
 					Server: ServerConfiguration{
-						HostPort: testCase.hostPort,
+						HostPort:      testCase.hostPort,
+						AddressFamily: testCase.addressFamily,
 					},
 				},
 			},
@@ -167,6 +171,12 @@ func TestBuilderWithProcessorErrors(t *testing.T) {
 	}
 }
 
+func TestBuilderWithInvalidHTTPAddressFamily(t *testing.T) {
+	cfg := &Builder{HTTPServer: HTTPServerConfiguration{AddressFamily: "ipx"}}
+	_, err := cfg.CreateAgent(fakeCollectorProxy{}, zap.NewNop(), metrics.NullFactory)
+	require.ErrorContains(t, err, `cannot create HTTP server: invalid address family "ipx"`)
+}
+
 func TestMultipleCollectorProxies(t *testing.T) {
 	b := Builder{}
 	ra := fakeCollectorProxy{}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/ports"
 )
 
@@ -31,9 +32,11 @@ const (
 	suffixServerMaxPacketSize    = "server-max-packet-size"
 	suffixServerSocketBufferSize = "server-socket-buffer-size"
 	suffixServerHostPort         = "server-host-port"
+	suffixServerAddressFamily    = "server-address-family"
 
-	processorPrefixFmt = "processor.%s-%s."
-	httpServerHostPort = "http-server.host-port"
+	processorPrefixFmt      = "processor.%s-%s."
+	httpServerHostPort      = "http-server.host-port"
+	httpServerAddressFamily = "http-server.address-family"
 )
 
 var defaultProcessors = []struct {
@@ -52,6 +55,7 @@ func AddFlags(flags *flag.FlagSet) {
 		httpServerHostPort,
 		defaultHTTPServerHostPort,
 		"host:port of the http server (e.g. for /sampling point and /baggageRestrictions endpoint)")
+	flags.String(httpServerAddressFamily, string(netutils.DualStack), fmt.Sprintf(netutils.AddressFamilyFlagUsage, "http server"))
 
 	for _, p := range defaultProcessors {
 		prefix := fmt.Sprintf(processorPrefixFmt, p.model, p.protocol)
@@ -60,6 +64,7 @@ func AddFlags(flags *flag.FlagSet) {
 		flags.Int(prefix+suffixServerMaxPacketSize, defaultMaxPacketSize, "max packet size for the UDP server")
 		flags.Int(prefix+suffixServerSocketBufferSize, 0, "socket buffer size for UDP packets in bytes")
 		flags.String(prefix+suffixServerHostPort, ":"+strconv.Itoa(p.port), "host:port for the UDP server")
+		flags.String(prefix+suffixServerAddressFamily, string(netutils.DualStack), fmt.Sprintf(netutils.AddressFamilyFlagUsage, "UDP server"))
 	}
 }
 
@@ -73,10 +78,12 @@ func (b *Builder) InitFromViper(v *viper.Viper) *Builder {
 		p.Server.MaxPacketSize = v.GetInt(prefix + suffixServerMaxPacketSize)
 		p.Server.SocketBufferSize = v.GetInt(prefix + suffixServerSocketBufferSize)
 		p.Server.HostPort = portNumToHostPort(v.GetString(prefix + suffixServerHostPort))
+		p.Server.AddressFamily = netutils.AddressFamily(v.GetString(prefix + suffixServerAddressFamily))
 		b.Processors = append(b.Processors, *p)
 	}
 
 	b.HTTPServer.HostPort = portNumToHostPort(v.GetString(httpServerHostPort))
+	b.HTTPServer.AddressFamily = netutils.AddressFamily(v.GetString(httpServerAddressFamily))
 	return b
 }
 
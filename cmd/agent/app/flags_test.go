@@ -23,6 +23,8 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 )
 
 func TestBindFlags(t *testing.T) {
@@ -40,6 +42,8 @@ func TestBindFlags(t *testing.T) {
 		"--processor.jaeger-binary.server-max-packet-size=4242",
 		"--processor.jaeger-binary.server-queue-size=42",
 		"--processor.jaeger-binary.workers=42",
+		"--processor.jaeger-binary.server-address-family=ipv6",
+		"--http-server.address-family=ipv4",
 	})
 	require.NoError(t, err)
 
@@ -50,4 +54,7 @@ func TestBindFlags(t *testing.T) {
 	assert.Equal(t, 4242, b.Processors[2].Server.MaxPacketSize)
 	assert.Equal(t, 42, b.Processors[2].Server.QueueSize)
 	assert.Equal(t, 42, b.Processors[2].Workers)
+	assert.Equal(t, netutils.IPv6, b.Processors[2].Server.AddressFamily)
+	assert.Equal(t, netutils.DualStack, b.Processors[0].Server.AddressFamily)
+	assert.Equal(t, netutils.IPv4, b.HTTPServer.AddressFamily)
 }
@@ -79,11 +79,17 @@ func createClient(destAddr, locAddr *net.UDPAddr) (*TUDPTransport, error) {
 //
 //	trans, err := thriftudp.NewTUDPClientTransport("localhost:9001")
 func NewTUDPServerTransport(hostPort string) (*TUDPTransport, error) {
-	addr, err := net.ResolveUDPAddr("udp", hostPort)
+	return NewTUDPServerTransportOnNetwork("udp", hostPort)
+}
+
+// NewTUDPServerTransportOnNetwork creates a net.UDPConn-backed TTransport for Thrift servers
+// listening on the network udp, udp4 (IPv4 only) or udp6 (IPv6 only).
+func NewTUDPServerTransportOnNetwork(network, hostPort string) (*TUDPTransport, error) {
+	addr, err := net.ResolveUDPAddr(network, hostPort)
 	if err != nil {
 		return nil, thrift.NewTTransportException(thrift.NOT_OPEN, err.Error())
 	}
-	conn, err := net.ListenUDP(addr.Network(), addr)
+	conn, err := net.ListenUDP(network, addr)
 	if err != nil {
 		return nil, thrift.NewTTransportException(thrift.NOT_OPEN, err.Error())
 	}
@@ -79,6 +79,16 @@ func TestNewTUDPServerTransport(t *testing.T) {
 	require.False(t, trans.IsOpen())
 }
 
+func TestNewTUDPServerTransportOnNetwork(t *testing.T) {
+	trans, err := NewTUDPServerTransportOnNetwork("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", trans.Addr().(*net.UDPAddr).IP.String())
+	require.NoError(t, trans.Close())
+
+	_, err = NewTUDPServerTransportOnNetwork("udp4", "[::1]:0")
+	require.Error(t, err)
+}
+
 func TestSetSocketBufferSize(t *testing.T) {
 	trans, err := NewTUDPServerTransport(localListenAddr.String())
 	require.NoError(t, err)
@@ -17,6 +17,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -32,22 +33,28 @@ import (
 	agentGrpcRep "github.com/jaegertracing/jaeger/cmd/agent/app/reporter/grpc"
 	"github.com/jaegertracing/jaeger/cmd/all-in-one/setupcontext"
 	collectorApp "github.com/jaegertracing/jaeger/cmd/collector/app"
+	collectorDeps "github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	collectorFlags "github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	collectorSpanLogs "github.com/jaegertracing/jaeger/cmd/collector/app/spanlogs"
+	collectorSpanMetrics "github.com/jaegertracing/jaeger/cmd/collector/app/spanmetrics"
 	"github.com/jaegertracing/jaeger/cmd/internal/docs"
 	"github.com/jaegertracing/jaeger/cmd/internal/env"
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
 	"github.com/jaegertracing/jaeger/cmd/internal/status"
+	"github.com/jaegertracing/jaeger/cmd/internal/validate"
 	queryApp "github.com/jaegertracing/jaeger/cmd/query/app"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/internal/metrics/expvar"
 	"github.com/jaegertracing/jaeger/internal/metrics/fork"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
+	"github.com/jaegertracing/jaeger/pkg/lifecycle"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	metricsPlugin "github.com/jaegertracing/jaeger/plugin/metrics"
+	embeddedMetrics "github.com/jaegertracing/jaeger/plugin/metrics/embedded"
 	ss "github.com/jaegertracing/jaeger/plugin/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
@@ -104,13 +111,13 @@ by default uses only in-memory database.`,
 			collectorMetricsFactory := baseFactory.Namespace(metrics.NSOptions{Name: "collector"})
 			queryMetricsFactory := baseFactory.Namespace(metrics.NSOptions{Name: "query"})
 
-			tracer, err := jtracer.New("jaeger-all-in-one")
+			tracer, err := svc.OTLPTelemetry.NewTracer("jaeger-all-in-one", logger)
 			if err != nil {
 				logger.Fatal("Failed to initialize tracer", zap.Error(err))
 			}
 
 			storageFactory.InitFromViper(v, logger)
-			if err := storageFactory.Initialize(baseFactory, logger); err != nil {
+			if err := storageFactory.Initialize(baseFactory, logger.Named(flags.StorageLoggerName)); err != nil {
 				logger.Fatal("Failed to init storage factory", zap.Error(err))
 			}
 
@@ -127,9 +134,26 @@ by default uses only in-memory database.`,
 				logger.Fatal("Failed to create dependency reader", zap.Error(err))
 			}
 
-			metricsQueryService, err := createMetricsQueryService(metricsReaderFactory, v, logger, queryMetricsFactory)
-			if err != nil {
-				logger.Fatal("Failed to create metrics reader", zap.Error(err))
+			// the collector writes the spans to the storage, and to the embedded metrics if enabled
+			collectorSpanWriter := spanWriter
+			var metricsQueryService querysvc.MetricsQueryService
+			if embeddedOpts := new(embeddedMetrics.Options).InitFromViper(v); embeddedOpts.Enabled {
+				redAggregator, err := embeddedMetrics.NewAggregator(*embeddedOpts)
+				if err != nil {
+					logger.Fatal("Failed to create embedded metrics", zap.Error(err))
+				}
+				logger.Info("Serving the metrics aggregated in memory from the ingested spans",
+					zap.Duration("resolution", embeddedOpts.Resolution), zap.Duration("retention", embeddedOpts.Retention))
+				collectorSpanWriter = spanstore.NewCompositeWriter(spanWriter, redAggregator)
+				metricsQueryService = metricsstoreMetrics.NewReadMetricsDecorator(
+					embeddedMetrics.NewMetricsReader(redAggregator, logger), queryMetricsFactory)
+			} else {
+				// used when the metrics are computed from the span storage
+				metricsReaderFactory.SetSpanReader(spanReader)
+				metricsQueryService, err = createMetricsQueryService(metricsReaderFactory, v, logger, queryMetricsFactory)
+				if err != nil {
+					logger.Fatal("Failed to create metrics reader", zap.Error(err))
+				}
 			}
 
 			ssFactory, err := storageFactory.CreateSamplingStoreFactory()
@@ -162,22 +186,35 @@ by default uses only in-memory database.`,
 			}
 
 			tm := tenancy.NewManager(&cOpts.GRPC.Tenancy)
+			depsAggregator, err := collectorDeps.CreateAggregator(
+				*new(collectorDeps.Options).InitFromViper(v), tm, storageFactory, collectorMetricsFactory, logger)
+			if err != nil {
+				logger.Fatal("Failed to create dependency aggregator", zap.Error(err))
+			}
+			var spanMetricsGenerator *collectorSpanMetrics.Generator
+			if spanMetricsOpts := new(collectorSpanMetrics.Options).InitFromViper(v); spanMetricsOpts.Enabled {
+				spanMetricsGenerator = collectorSpanMetrics.NewGenerator(*spanMetricsOpts, svc.MetricsFactory, collectorMetricsFactory)
+			}
+			var spanLogsCorrelator *collectorSpanLogs.Correlator
+			if spanLogsOpts := new(collectorSpanLogs.Options).InitFromViper(v); spanLogsOpts.Enabled {
+				spanLogsCorrelator = collectorSpanLogs.NewCorrelator(*spanLogsOpts, collectorMetricsFactory)
+			}
 
 			// collector
 			c := collectorApp.New(&collectorApp.CollectorParams{
 				ServiceName:    "jaeger-collector",
 				Logger:         logger,
 				MetricsFactory: collectorMetricsFactory,
-				SpanWriter:     spanWriter,
+				SpanWriter:     collectorSpanWriter,
 				StrategyStore:  strategyStore,
 				Aggregator:     aggregator,
 				HealthCheck:    svc.HC(),
 				TenancyMgr:     tm,
-			})
-			if err := c.Start(cOpts); err != nil {
-				log.Fatal(err)
-			}
 
+				DependencyAggregator: depsAggregator,
+				SpanMetricsGenerator: spanMetricsGenerator,
+				SpanLogsCorrelator:   spanLogsCorrelator,
+			})
 			// agent
 			// if the agent reporter grpc host:port was not explicitly set then use whatever the collector is listening on
 			if len(grpcBuilder.CollectorHostPorts) == 0 {
@@ -196,32 +233,69 @@ by default uses only in-memory database.`,
 			if err != nil {
 				logger.Fatal("Could not create collector proxy", zap.Error(err))
 			}
-			agent := startAgent(cp, aOpts, logger, agentMetricsFactory)
+			agent := createAgent(cp, aOpts, logger, agentMetricsFactory)
 
 			// query
-			querySrv := startQuery(
-				svc, qOpts, qOpts.BuildQueryServiceOptions(storageFactory, logger),
+			queryServiceOptions, err := qOpts.BuildQueryServiceOptions(storageFactory, logger)
+			if err != nil {
+				logger.Fatal("Failed to create query service options", zap.Error(err))
+			}
+			querySrv := createQuery(
+				svc, qOpts, queryServiceOptions,
 				spanReader, dependencyReader, metricsQueryService,
 				queryMetricsFactory, tm, tracer,
 			)
 
-			svc.RunAndThen(func() {
-				agent.Stop()
-				_ = cp.Close()
-				_ = c.Close()
-				_ = querySrv.Close()
-				if closer, ok := spanWriter.(io.Closer); ok {
-					if err := closer.Close(); err != nil {
-						logger.Error("Failed to close span writer", zap.Error(err))
+			// the agent is stopped before the collector it reports to, and the collector
+			// and the query are stopped before the storage they use
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name: "tracer",
+				Stop: tracer.Close,
+			})
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name: "storage",
+				Stop: func(context.Context) error {
+					var errs []error
+					if closer, ok := spanWriter.(io.Closer); ok {
+						errs = append(errs, closer.Close())
 					}
-				}
-				if err := storageFactory.Close(); err != nil {
-					logger.Error("Failed to close storage factory", zap.Error(err))
-				}
-				if err := tracer.Close(context.Background()); err != nil {
-					logger.Error("Error shutting down tracer provider", zap.Error(err))
-				}
+					return errors.Join(append(errs, storageFactory.Close())...)
+				},
+			})
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name:      "collector",
+				DependsOn: []string{"storage"},
+				Start: func(context.Context) error {
+					return c.Start(cOpts)
+				},
+				Stop: lifecycle.Closer(c),
+			})
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name:      "agent",
+				DependsOn: []string{"collector"},
+				Start: func(context.Context) error {
+					logger.Info("Starting agent")
+					return agent.Run()
+				},
+				Stop: func(context.Context) error {
+					agent.Stop()
+					return cp.Close()
+				},
 			})
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name:      "query",
+				DependsOn: []string{"storage", "tracer"},
+				Start: func(context.Context) error {
+					return querySrv.Start()
+				},
+				Stop: lifecycle.Closer(querySrv),
+			})
+			if err := svc.Lifecycle.Start(context.Background()); err != nil {
+				logger.Fatal("Failed to start the components", zap.Error(err))
+			}
+			svc.Admin.Handle(collectorApp.TopProducersPath, c.ServiceProducers())
+
+			svc.RunAndThen(nil)
 			return nil
 		},
 	}
@@ -231,6 +305,24 @@ by default uses only in-memory database.`,
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.CollectorAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(validate.Command(v, func(v *viper.Viper, logger *zap.Logger) error {
+		storageFactory.InitFromViper(v, logger)
+		strategyStoreFactory.InitFromViper(v, logger)
+		metricsReaderFactory.InitFromViper(v, logger)
+		if embeddedOpts := new(embeddedMetrics.Options).InitFromViper(v); embeddedOpts.Enabled {
+			if _, err := embeddedMetrics.NewAggregator(*embeddedOpts); err != nil {
+				return err
+			}
+		}
+		if _, err := agentGrpcRep.NewConnBuilder().InitFromViper(v); err != nil {
+			return err
+		}
+		if _, err := new(collectorFlags.CollectorOptions).InitFromViper(v, logger); err != nil {
+			return err
+		}
+		_, err := new(queryApp.QueryOptions).InitFromViper(v, logger)
+		return err
+	}))
 
 	config.AddFlags(
 		v,
@@ -243,7 +335,11 @@ by default uses only in-memory database.`,
 		collectorFlags.AddFlags,
 		queryApp.AddFlags,
 		strategyStoreFactory.AddFlags,
+		collectorDeps.AddFlags,
+		collectorSpanMetrics.AddFlags,
+		collectorSpanLogs.AddFlags,
 		metricsReaderFactory.AddFlags,
+		embeddedMetrics.AddFlags,
 	)
 
 	if err := command.Execute(); err != nil {
@@ -251,7 +347,7 @@ by default uses only in-memory database.`,
 	}
 }
 
-func startAgent(
+func createAgent(
 	cp agentApp.CollectorProxy,
 	b *agentApp.Builder,
 	logger *zap.Logger,
@@ -261,16 +357,10 @@ func startAgent(
 	if err != nil {
 		logger.Fatal("Unable to initialize Jaeger Agent", zap.Error(err))
 	}
-
-	logger.Info("Starting agent")
-	if err := agent.Run(); err != nil {
-		logger.Fatal("Failed to run the agent", zap.Error(err))
-	}
-
 	return agent
 }
 
-func startQuery(
+func createQuery(
 	svc *flags.Service,
 	qOpts *queryApp.QueryOptions,
 	queryOpts *querysvc.QueryServiceOptions,
@@ -287,10 +377,6 @@ func startQuery(
 	if err != nil {
 		svc.Logger.Fatal("Could not create jaeger-query", zap.Error(err))
 	}
-	if err := server.Start(); err != nil {
-		svc.Logger.Fatal("Could not start jaeger-query", zap.Error(err))
-	}
-
 	return server
 }
 
@@ -0,0 +1,54 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/jaegertracing/jaeger/model"
+	model2otel "github.com/jaegertracing/jaeger/model/converter/otlp"
+)
+
+// Exporter exports anonymized spans to an OTLP gRPC endpoint, e.g. a Jaeger collector.
+type Exporter struct {
+	client ptraceotlp.GRPCClient
+	conn   *grpc.ClientConn
+}
+
+// New creates an Exporter
+func New(addr string) (*Exporter, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect with the OTLP endpoint: %w", err)
+	}
+	return &Exporter{
+		client: ptraceotlp.NewGRPCClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// Export converts the spans to OTLP and exports them.
+func (e *Exporter) Export(ctx context.Context, spans []*model.Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	td, err := model2otel.ProtoToTraces([]*model.Batch{{Spans: spans}})
+	if err != nil {
+		return fmt.Errorf("failed to convert spans to OTLP: %w", err)
+	}
+	if _, err := e.client.Export(ctx, ptraceotlp.NewExportRequestFromTraces(td)); err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection to the OTLP endpoint.
+func (e *Exporter) Close() error {
+	return e.conn.Close()
+}
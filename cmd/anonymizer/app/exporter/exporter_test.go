@@ -0,0 +1,89 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type fakeOTLPServer struct {
+	ptraceotlp.UnimplementedGRPCServer
+	lock   sync.Mutex
+	traces []ptrace.Traces
+}
+
+func (s *fakeOTLPServer) Export(_ context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.traces = append(s.traces, req.Traces())
+	return ptraceotlp.NewExportResponse(), nil
+}
+
+func startServer(t *testing.T) (*fakeOTLPServer, string) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	fake := &fakeOTLPServer{}
+	ptraceotlp.RegisterGRPCServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return fake, listener.Addr().String()
+}
+
+func TestExport(t *testing.T) {
+	server, addr := startServer(t)
+	exporter, err := New(addr)
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	require.NoError(t, exporter.Export(context.Background(), nil))
+	require.NoError(t, exporter.Export(context.Background(), []*model.Span{
+		{
+			TraceID:       model.NewTraceID(0, 1),
+			SpanID:        model.NewSpanID(1),
+			OperationName: "a1b2c3",
+			Process:       model.NewProcess("d4e5f6", nil),
+		},
+		{
+			TraceID:       model.NewTraceID(0, 1),
+			SpanID:        model.NewSpanID(2),
+			OperationName: "a7b8c9",
+			Process:       model.NewProcess("d4e5f6", nil),
+		},
+	}))
+
+	require.Len(t, server.traces, 1)
+	assert.Equal(t, 2, server.traces[0].SpanCount())
+	resource := server.traces[0].ResourceSpans().At(0).Resource()
+	service, ok := resource.Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "d4e5f6", service.Str())
+}
+
+func TestExportError(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	// the server does not implement the OTLP service
+	go server.Serve(listener)
+	defer server.Stop()
+
+	exporter, err := New(listener.Addr().String())
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	err = exporter.Export(context.Background(), []*model.Span{{Process: model.NewProcess("svc", nil)}})
+	require.ErrorContains(t, err, "failed to export spans")
+}
@@ -15,6 +15,8 @@
 package app
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
@@ -28,6 +30,12 @@ type Options struct {
 	HashCustomTags    bool
 	HashLogs          bool
 	HashProcess       bool
+	MappingFile       string
+	ServiceName       string
+	OperationName     string
+	Lookback          time.Duration
+	MaxTraces         int
+	OTLPEndpoint      string
 }
 
 const (
@@ -39,6 +47,12 @@ const (
 	hashLogsFlag          = "hash-logs"
 	hashProcessFlag       = "hash-process"
 	maxSpansCount         = "max-spans-count"
+	mappingFileFlag       = "mapping-file"
+	serviceNameFlag       = "service"
+	operationNameFlag     = "operation"
+	lookbackFlag          = "lookback"
+	maxTracesFlag         = "max-traces"
+	otlpEndpointFlag      = "otlp-endpoint"
 )
 
 // AddFlags adds flags for anonymizer main program
@@ -57,7 +71,7 @@ func (o *Options) AddFlags(command *cobra.Command) {
 		&o.TraceID,
 		traceIDFlag,
 		"",
-		"The trace-id of trace to anonymize")
+		"The trace-id of trace to anonymize. Either --trace-id or --service must be set")
 	command.Flags().BoolVar(
 		&o.HashStandardTags,
 		hashStandardTagsFlag,
@@ -83,7 +97,35 @@ func (o *Options) AddFlags(command *cobra.Command) {
 		maxSpansCount,
 		-1,
 		"The maximum number of spans to anonymize")
-
-	// mark traceid flag as mandatory
-	command.MarkFlagRequired(traceIDFlag)
+	command.Flags().StringVar(
+		&o.MappingFile,
+		mappingFileFlag,
+		"",
+		"The file storing the mapping from original to anonymized names, reused between runs for consistent names. "+
+			"Defaults to a file in the output directory named after the trace or service")
+	command.Flags().StringVar(
+		&o.ServiceName,
+		serviceNameFlag,
+		"",
+		"The service of the traces to search for and anonymize in bulk, instead of a single trace")
+	command.Flags().StringVar(
+		&o.OperationName,
+		operationNameFlag,
+		"",
+		"The operation of the traces to search for, used with --service")
+	command.Flags().DurationVar(
+		&o.Lookback,
+		lookbackFlag,
+		time.Hour,
+		"How far back to search for traces, used with --service")
+	command.Flags().IntVar(
+		&o.MaxTraces,
+		maxTracesFlag,
+		20,
+		"The maximum number of traces to search for, used with --service")
+	command.Flags().StringVar(
+		&o.OTLPEndpoint,
+		otlpEndpointFlag,
+		"",
+		"The host:port of an OTLP gRPC endpoint (e.g. a Jaeger collector) to export the anonymized traces to")
 }
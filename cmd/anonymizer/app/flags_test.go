@@ -16,6 +16,7 @@ package app
 
 import (
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -35,6 +36,12 @@ func TestOptionsWithDefaultFlags(t *testing.T) {
 	assert.False(t, o.HashLogs)
 	assert.False(t, o.HashProcess)
 	assert.Equal(t, -1, o.MaxSpansCount)
+	assert.Empty(t, o.MappingFile)
+	assert.Empty(t, o.ServiceName)
+	assert.Empty(t, o.OperationName)
+	assert.Equal(t, time.Hour, o.Lookback)
+	assert.Equal(t, 20, o.MaxTraces)
+	assert.Empty(t, o.OTLPEndpoint)
 }
 
 func TestOptionsWithFlags(t *testing.T) {
@@ -51,6 +58,12 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--hash-logs",
 		"--hash-process",
 		"--max-spans-count=100",
+		"--mapping-file=/data/mapping.json",
+		"--service=frontend",
+		"--operation=HTTP GET /dispatch",
+		"--lookback=2h",
+		"--max-traces=5",
+		"--otlp-endpoint=localhost:4317",
 	})
 
 	assert.Equal(t, "192.168.1.10:16686", o.QueryGRPCHostPort)
@@ -61,6 +74,12 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.True(t, o.HashLogs)
 	assert.True(t, o.HashProcess)
 	assert.Equal(t, 100, o.MaxSpansCount)
+	assert.Equal(t, "/data/mapping.json", o.MappingFile)
+	assert.Equal(t, "frontend", o.ServiceName)
+	assert.Equal(t, "HTTP GET /dispatch", o.OperationName)
+	assert.Equal(t, 2*time.Hour, o.Lookback)
+	assert.Equal(t, 5, o.MaxTraces)
+	assert.Equal(t, "localhost:4317", o.OTLPEndpoint)
 }
 
 func TestMain(m *testing.M) {
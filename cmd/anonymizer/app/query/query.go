@@ -82,3 +82,28 @@ func (q *Query) QueryTrace(traceID string) ([]model.Span, error) {
 
 	return spans, nil
 }
+
+// QueryTraces searches for the traces matching the parameters and calls handle
+// with the spans of each chunk received from the query service.
+func (q *Query) QueryTraces(params *api_v2.TraceQueryParameters, handle func(spans []model.Span) error) error {
+	stream, err := q.client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+		Query: params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search for traces: %w", err)
+	}
+	for received, err := stream.Recv(); !errors.Is(err, io.EOF); received, err = stream.Recv() {
+		if err != nil {
+			return fmt.Errorf("failed to search for traces: %w", err)
+		}
+		if err := handle(received.Spans); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the connection to the query service.
+func (q *Query) Close() error {
+	return q.conn.Close()
+}
@@ -15,19 +15,24 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/anonymizer/app"
 	"github.com/jaegertracing/jaeger/cmd/anonymizer/app/anonymizer"
+	"github.com/jaegertracing/jaeger/cmd/anonymizer/app/exporter"
 	"github.com/jaegertracing/jaeger/cmd/anonymizer/app/query"
 	"github.com/jaegertracing/jaeger/cmd/anonymizer/app/uiconv"
 	"github.com/jaegertracing/jaeger/cmd/anonymizer/app/writer"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/version"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
 
 var logger, _ = zap.NewDevelopment()
@@ -38,14 +43,26 @@ func main() {
 	command := &cobra.Command{
 		Use:   "jaeger-anonymizer",
 		Short: "Jaeger anonymizer hashes fields of a trace for easy sharing",
-		Long:  `Jaeger anonymizer queries Jaeger query for a trace, anonymizes fields, and store in file`,
+		Long: `Jaeger anonymizer queries Jaeger query for a trace, or searches for the traces of a service, ` +
+			`anonymizes fields, and store in file. The anonymized spans can also be exported to an OTLP endpoint`,
 		Run: func(cmd *cobra.Command, args []string) {
-			prefix := options.OutputDir + "/" + options.TraceID
+			if options.TraceID == "" && options.ServiceName == "" {
+				logger.Fatal("either --trace-id or --service must be set")
+			}
+			name := options.TraceID
+			if name == "" {
+				name = options.ServiceName
+			}
+			prefix := options.OutputDir + "/" + name
+			mappingFile := options.MappingFile
+			if mappingFile == "" {
+				mappingFile = prefix + ".mapping.json"
+			}
 			conf := writer.Config{
 				MaxSpansCount:  options.MaxSpansCount,
 				CapturedFile:   prefix + ".original.json",
 				AnonymizedFile: prefix + ".anonymized.json",
-				MappingFile:    prefix + ".mapping.json",
+				MappingFile:    mappingFile,
 				AnonymizerOpts: anonymizer.Options{
 					HashStandardTags: options.HashStandardTags,
 					HashCustomTags:   options.HashCustomTags,
@@ -63,23 +80,70 @@ func main() {
 			if err != nil {
 				logger.Fatal("error while creating query object", zap.Error(err))
 			}
+			defer query.Close()
 
-			spans, err := query.QueryTrace(options.TraceID)
-			if err != nil {
-				logger.Fatal("error while querying for trace", zap.Error(err))
+			var otlpExporter *exporter.Exporter
+			if options.OTLPEndpoint != "" {
+				otlpExporter, err = exporter.New(options.OTLPEndpoint)
+				if err != nil {
+					logger.Fatal("error while creating OTLP exporter", zap.Error(err))
+				}
+				defer otlpExporter.Close()
 			}
 
-			for _, span := range spans {
-				if err := w.WriteSpan(&span); err != nil {
-					if errors.Is(err, writer.ErrMaxSpansCountReached) {
-						logger.Info("max spans count reached")
-						os.Exit(0)
+			// writeSpans anonymizes the spans in place, and exports them if an OTLP endpoint is configured.
+			writeSpans := func(spans []model.Span) error {
+				anonymized := make([]*model.Span, 0, len(spans))
+				var writeErr error
+				for i := range spans {
+					err := w.WriteSpan(&spans[i])
+					if err != nil && !errors.Is(err, writer.ErrMaxSpansCountReached) {
+						logger.Error("error while writing span", zap.Error(err))
+						continue
+					}
+					anonymized = append(anonymized, &spans[i])
+					if err != nil {
+						writeErr = err
+						break
+					}
+				}
+				if otlpExporter != nil {
+					if err := otlpExporter.Export(context.Background(), anonymized); err != nil {
+						logger.Error("error while exporting spans", zap.Error(err))
 					}
-					logger.Error("error while writing span", zap.Error(err))
 				}
+				return writeErr
+			}
+
+			if options.TraceID != "" {
+				spans, err := query.QueryTrace(options.TraceID)
+				if err != nil {
+					logger.Fatal("error while querying for trace", zap.Error(err))
+				}
+				err = writeSpans(spans)
+			} else {
+				now := time.Now()
+				err = query.QueryTraces(&api_v2.TraceQueryParameters{
+					ServiceName:   options.ServiceName,
+					OperationName: options.OperationName,
+					StartTimeMin:  now.Add(-options.Lookback),
+					StartTimeMax:  now,
+					SearchDepth:   int32(options.MaxTraces),
+				}, writeSpans)
+			}
+			if errors.Is(err, writer.ErrMaxSpansCountReached) {
+				logger.Info("max spans count reached")
+				return
+			}
+			if err != nil {
+				logger.Fatal("error while searching for traces", zap.Error(err))
 			}
 			w.Close()
 
+			if options.TraceID == "" {
+				logger.Sugar().Infof("Wrote anonymized spans to %s", conf.AnonymizedFile)
+				return
+			}
 			uiCfg := uiconv.Config{
 				CapturedFile: conf.AnonymizedFile,
 				UIFile:       prefix + ".anonymized-ui-trace.json",
@@ -25,11 +25,14 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/server"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/spanlogs"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/spanmetrics"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
@@ -50,10 +53,14 @@ type Collector struct {
 	spanWriter     spanstore.Writer
 	strategyStore  strategystore.StrategyStore
 	aggregator     strategystore.Aggregator
+	depsAggregator *dependencies.Aggregator
+	spanMetrics    *spanmetrics.Generator
+	spanLogs       *spanlogs.Correlator
 	hCheck         *healthcheck.HealthCheck
 	spanProcessor  processor.SpanProcessor
 	spanHandlers   *SpanHandlers
 	tenancyMgr     *tenancy.Manager
+	producers      *ServiceProducers
 
 	// state, read only
 	hServer                    *http.Server
@@ -75,6 +82,12 @@ type CollectorParams struct {
 	Aggregator     strategystore.Aggregator
 	HealthCheck    *healthcheck.HealthCheck
 	TenancyMgr     *tenancy.Manager
+	// DependencyAggregator, if not nil, aggregates the dependency links from the received spans.
+	DependencyAggregator *dependencies.Aggregator
+	// SpanMetricsGenerator, if not nil, derives the span metrics from the received spans.
+	SpanMetricsGenerator *spanmetrics.Generator
+	// SpanLogsCorrelator, if not nil, receives the OTLP log records and attaches them to their spans.
+	SpanLogsCorrelator *spanlogs.Correlator
 }
 
 // New constructs a new collector component, ready to be started
@@ -86,6 +99,9 @@ func New(params *CollectorParams) *Collector {
 		spanWriter:     params.SpanWriter,
 		strategyStore:  params.StrategyStore,
 		aggregator:     params.Aggregator,
+		depsAggregator: params.DependencyAggregator,
+		spanMetrics:    params.SpanMetricsGenerator,
+		spanLogs:       params.SpanLogsCorrelator,
 		hCheck:         params.HealthCheck,
 		tenancyMgr:     params.TenancyMgr,
 	}
@@ -93,53 +109,89 @@ func New(params *CollectorParams) *Collector {
 
 // Start the component and underlying dependencies
 func (c *Collector) Start(options *flags.CollectorOptions) error {
+	c.producers = NewServiceProducers(options.ServiceMetrics.MaxServices, options.ServiceMetrics.TopK)
 	handlerBuilder := &SpanHandlerBuilder{
-		SpanWriter:     c.spanWriter,
-		CollectorOpts:  options,
-		Logger:         c.logger,
-		MetricsFactory: c.metricsFactory,
-		TenancyMgr:     c.tenancyMgr,
+		SpanWriter:       c.spanWriter,
+		CollectorOpts:    options,
+		Logger:           c.logger,
+		MetricsFactory:   c.metricsFactory,
+		TenancyMgr:       c.tenancyMgr,
+		ServiceProducers: c.producers,
+	}
+	// the log records are attached before the spans are queued, so that the span limits apply to them
+	var logProcessor processor.LogProcessor
+	if c.spanLogs != nil {
+		c.spanLogs.Start()
+		handlerBuilder.PreProcessSpans = c.spanLogs.HandleSpans
+		logProcessor = c.spanLogs
+		if !options.OTLP.Enabled {
+			c.logger.Warn("The OTLP log records are not received, the OTLP receiver is disabled")
+		}
 	}
 
 	var additionalProcessors []ProcessSpan
 	if c.aggregator != nil {
 		additionalProcessors = append(additionalProcessors, handleRootSpan(c.aggregator, c.logger))
 	}
+	if c.depsAggregator != nil {
+		c.depsAggregator.Start()
+		additionalProcessors = append(additionalProcessors, c.depsAggregator.HandleSpan)
+	}
+	if c.spanMetrics != nil {
+		additionalProcessors = append(additionalProcessors, c.spanMetrics.HandleSpan)
+	}
 
 	c.spanProcessor = handlerBuilder.BuildSpanProcessor(additionalProcessors...)
 	c.spanHandlers = handlerBuilder.BuildHandlers(c.spanProcessor)
 
-	grpcServer, err := server.StartGRPCServer(&server.GRPCServerParams{
+	// the TLS options of the params hold the certificate watchers of the servers
+	grpcServerParams := &server.GRPCServerParams{
 		HostPort:                options.GRPC.HostPort,
+		AddressFamily:           options.GRPC.AddressFamily,
 		Handler:                 c.spanHandlers.GRPCHandler,
 		TLSConfig:               options.GRPC.TLS,
 		SamplingStore:           c.strategyStore,
 		Logger:                  c.logger,
+		MetricsFactory:          c.metricsFactory,
 		MaxReceiveMessageLength: options.GRPC.MaxReceiveMessageLength,
 		MaxConnectionAge:        options.GRPC.MaxConnectionAge,
 		MaxConnectionAgeGrace:   options.GRPC.MaxConnectionAgeGrace,
-	})
+		SocketPermissions:       options.GRPC.SocketPermissions,
+	}
+	grpcServer, err := server.StartGRPCServer(grpcServerParams)
 	if err != nil {
 		return fmt.Errorf("could not start gRPC server: %w", err)
 	}
 	c.grpcServer = grpcServer
 
-	httpServer, err := server.StartHTTPServer(&server.HTTPServerParams{
+	httpServerParams := &server.HTTPServerParams{
 		HostPort:       options.HTTP.HostPort,
+		AddressFamily:  options.HTTP.AddressFamily,
 		Handler:        c.spanHandlers.JaegerBatchesHandler,
 		TLSConfig:      options.HTTP.TLS,
 		HealthCheck:    c.hCheck,
 		MetricsFactory: c.metricsFactory,
 		SamplingStore:  c.strategyStore,
 		Logger:         c.logger,
-	})
+
+		ReadTimeout:       options.HTTP.ReadTimeout,
+		ReadHeaderTimeout: options.HTTP.ReadHeaderTimeout,
+		IdleTimeout:       options.HTTP.IdleTimeout,
+		WriteTimeout:      options.HTTP.WriteTimeout,
+		MaxHeaderBytes:    options.HTTP.MaxHeaderBytes,
+		MaxRequestSize:    options.HTTP.MaxRequestSize,
+		RateLimit:         options.HTTP.RateLimit,
+		RateLimitBurst:    options.HTTP.RateLimitBurst,
+		CORS:              options.HTTP.CORS,
+	}
+	httpServer, err := server.StartHTTPServer(httpServerParams)
 	if err != nil {
 		return fmt.Errorf("could not start HTTP server: %w", err)
 	}
 	c.hServer = httpServer
 
-	c.tlsGRPCCertWatcherCloser = &options.GRPC.TLS
-	c.tlsHTTPCertWatcherCloser = &options.HTTP.TLS
+	c.tlsGRPCCertWatcherCloser = &grpcServerParams.TLSConfig
+	c.tlsHTTPCertWatcherCloser = &httpServerParams.TLSConfig
 	c.tlsZipkinCertWatcherCloser = &options.Zipkin.TLS
 
 	if options.Zipkin.HTTPHostPort == "" {
@@ -154,7 +206,7 @@ func (c *Collector) Start(options *flags.CollectorOptions) error {
 	}
 
 	if options.OTLP.Enabled {
-		otlpReceiver, err := handler.StartOTLPReceiver(options, c.logger, c.spanProcessor, c.tenancyMgr)
+		otlpReceiver, err := handler.StartOTLPReceiver(options, c.logger, c.spanProcessor, logProcessor, c.tenancyMgr)
 		if err != nil {
 			return fmt.Errorf("could not start OTLP receiver: %w", err)
 		}
@@ -217,6 +269,19 @@ func (c *Collector) Close() error {
 		}
 	}
 
+	// closed after the span processor to include all the processed spans in the last window
+	if c.depsAggregator != nil {
+		if err := c.depsAggregator.Close(); err != nil {
+			c.logger.Error("failed to close dependency aggregator.", zap.Error(err))
+		}
+	}
+
+	if c.spanLogs != nil {
+		if err := c.spanLogs.Close(); err != nil {
+			c.logger.Error("failed to close span logs correlator.", zap.Error(err))
+		}
+	}
+
 	// watchers actually never return errors from Close
 	if c.tlsGRPCCertWatcherCloser != nil {
 		_ = c.tlsGRPCCertWatcherCloser.Close()
@@ -231,6 +296,12 @@ func (c *Collector) Close() error {
 	return nil
 }
 
+// ServiceProducers returns the counts of the spans by service of the started Collector,
+// serving the top span producers, e.g. on the admin server at TopProducersPath.
+func (c *Collector) ServiceProducers() *ServiceProducers {
+	return c.producers
+}
+
 // SpanHandlers returns span handlers used by the Collector.
 func (c *Collector) SpanHandlers() *SpanHandlers {
 	return c.spanHandlers
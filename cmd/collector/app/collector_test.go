@@ -22,14 +22,20 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/spanlogs"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/spanmetrics"
 	"github.com/jaegertracing/jaeger/internal/metrics/fork"
 	"github.com/jaegertracing/jaeger/internal/metricstest"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
@@ -70,6 +76,7 @@ func TestNewCollector(t *testing.T) {
 	collectorOpts := optionsForEphemeralPorts()
 	require.NoError(t, c.Start(collectorOpts))
 	assert.NotNil(t, c.SpanHandlers())
+	assert.NotNil(t, c.ServiceProducers())
 	require.NoError(t, c.Close())
 }
 
@@ -231,3 +238,130 @@ func TestAggregator(t *testing.T) {
 	assert.EqualValues(t, 1, agg.callCount.Load(), "aggregator was used")
 	assert.EqualValues(t, 1, agg.closeCount.Load(), "aggregator close was called")
 }
+
+type fakeDependencyWriter struct {
+	links []model.DependencyLink
+}
+
+func (w *fakeDependencyWriter) WriteDependencies(_ time.Time, links []model.DependencyLink) error {
+	w.links = append(w.links, links...)
+	return nil
+}
+
+func TestDependencyAggregator(t *testing.T) {
+	depsWriter := &fakeDependencyWriter{}
+	depsAggregator := dependencies.NewAggregator(
+		dependencies.Options{Enabled: true, Window: time.Hour, MaxSpans: 10},
+		depsWriter,
+		metrics.NullFactory,
+		zap.NewNop(),
+	)
+	c := New(&CollectorParams{
+		ServiceName:          "collector",
+		Logger:               zap.NewNop(),
+		MetricsFactory:       metrics.NullFactory,
+		SpanWriter:           &fakeSpanWriter{},
+		StrategyStore:        &mockStrategyStore{},
+		HealthCheck:          healthcheck.New(),
+		TenancyMgr:           &tenancy.Manager{},
+		DependencyAggregator: depsAggregator,
+	})
+	collectorOpts := optionsForEphemeralPorts()
+	collectorOpts.NumWorkers = 1
+	collectorOpts.QueueSize = 10
+	require.NoError(t, c.Start(collectorOpts))
+
+	traceID := model.NewTraceID(0, 1)
+	spans := []*model.Span{
+		{TraceID: traceID, SpanID: 1, Process: &model.Process{ServiceName: "frontend"}},
+		{
+			TraceID:    traceID,
+			SpanID:     2,
+			Process:    &model.Process{ServiceName: "api"},
+			References: []model.SpanRef{model.NewChildOfRef(traceID, 1)},
+		},
+	}
+	_, err := c.spanProcessor.ProcessSpans(spans, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+	assert.Equal(t, []model.DependencyLink{{
+		Parent:    "frontend",
+		Child:     "api",
+		CallCount: 1,
+		Source:    model.JaegerDependencyLinkSource,
+	}}, depsWriter.links)
+}
+
+func TestSpanLogsCorrelator(t *testing.T) {
+	correlator := spanlogs.NewCorrelator(spanlogs.Options{Enabled: true, Window: time.Hour}, metrics.NullFactory)
+	spanWriter := &fakeSpanWriter{}
+	c := New(&CollectorParams{
+		ServiceName:        "collector",
+		Logger:             zap.NewNop(),
+		MetricsFactory:     metrics.NullFactory,
+		SpanWriter:         spanWriter,
+		StrategyStore:      &mockStrategyStore{},
+		HealthCheck:        healthcheck.New(),
+		TenancyMgr:         &tenancy.Manager{},
+		SpanLogsCorrelator: correlator,
+	})
+	collectorOpts := optionsForEphemeralPorts()
+	collectorOpts.NumWorkers = 1
+	collectorOpts.QueueSize = 10
+	require.NoError(t, c.Start(collectorOpts))
+
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.SetTraceID(pcommon.TraceID([16]byte{15: 1}))
+	record.SetSpanID(pcommon.SpanID([8]byte{7: 2}))
+	record.Body().SetStr("hello")
+	require.NoError(t, correlator.ProcessLogs(logs, ""))
+
+	spans := []*model.Span{{
+		TraceID: model.NewTraceID(0, 1),
+		SpanID:  2,
+		Process: &model.Process{ServiceName: "frontend"},
+	}}
+	_, err := c.spanProcessor.ProcessSpans(spans, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+
+	require.Len(t, spanWriter.spans, 1)
+	require.Len(t, spanWriter.spans[0].Logs, 1)
+	assert.Equal(t, []model.KeyValue{model.String("message", "hello")}, spanWriter.spans[0].Logs[0].Fields)
+}
+
+func TestSpanMetricsGenerator(t *testing.T) {
+	spanMetricsFactory := metricstest.NewFactory(0)
+	c := New(&CollectorParams{
+		ServiceName:          "collector",
+		Logger:               zap.NewNop(),
+		MetricsFactory:       metrics.NullFactory,
+		SpanWriter:           &fakeSpanWriter{},
+		StrategyStore:        &mockStrategyStore{},
+		HealthCheck:          healthcheck.New(),
+		TenancyMgr:           &tenancy.Manager{},
+		SpanMetricsGenerator: spanmetrics.NewGenerator(spanmetrics.Options{Enabled: true, MaxSeries: 10}, spanMetricsFactory, metrics.NullFactory),
+	})
+	collectorOpts := optionsForEphemeralPorts()
+	collectorOpts.NumWorkers = 1
+	collectorOpts.QueueSize = 10
+	require.NoError(t, c.Start(collectorOpts))
+
+	spans := []*model.Span{
+		{TraceID: model.NewTraceID(0, 1), SpanID: 1, OperationName: "GET /", Process: &model.Process{ServiceName: "frontend"}},
+	}
+	_, err := c.spanProcessor.ProcessSpans(spans, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+	spanMetricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "calls",
+		Tags: map[string]string{
+			"service_name": "frontend",
+			"operation":    "GET /",
+			"span_kind":    "SPAN_KIND_UNSPECIFIED",
+			"status_code":  "STATUS_CODE_UNSET",
+		},
+		Value: 1,
+	})
+}
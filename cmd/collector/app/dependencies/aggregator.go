@@ -0,0 +1,215 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencies
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+)
+
+type spanKey struct {
+	tenant  string
+	traceID model.TraceID
+	spanID  model.SpanID
+}
+
+type linkKey struct {
+	parent string
+	child  string
+}
+
+// window holds the state of the aggregation over one tumbling window.
+type window struct {
+	// services holds the service of each span received in the window
+	services map[spanKey]string
+	// pending holds the services of the children received before their parent
+	pending map[spanKey][]string
+	size    int
+	links   map[linkKey]uint64
+}
+
+func newWindow() *window {
+	return &window{
+		services: make(map[spanKey]string),
+		pending:  make(map[spanKey][]string),
+		links:    make(map[linkKey]uint64),
+	}
+}
+
+// Aggregator derives the dependency links between services from the stream of spans
+// received by the collector, and writes them to storage at the end of every window.
+//
+// A parent span and its child are matched if they are received within the same or in
+// two consecutive windows. Since dependencystore.Writer is not aware of tenants, the
+// aggregation is not supported with multi-tenancy, see CreateAggregator.
+type Aggregator struct {
+	sync.Mutex
+
+	options Options
+	writer  dependencystore.Writer
+	logger  *zap.Logger
+	timeNow func() time.Time
+
+	linksCounter       metrics.Counter
+	droppedSpanCounter metrics.Counter
+	writeErrorCounter  metrics.Counter
+
+	current  *window
+	previous *window
+	started  bool
+	closed   bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewAggregator creates an Aggregator writing the dependency links to writer.
+func NewAggregator(options Options, writer dependencystore.Writer, metricsFactory metrics.Factory, logger *zap.Logger) *Aggregator {
+	return &Aggregator{
+		options:            options,
+		writer:             writer,
+		logger:             logger,
+		timeNow:            time.Now,
+		linksCounter:       metricsFactory.Counter(metrics.Options{Name: "dependencies_links"}),
+		droppedSpanCounter: metricsFactory.Counter(metrics.Options{Name: "dependencies_dropped_spans"}),
+		writeErrorCounter:  metricsFactory.Counter(metrics.Options{Name: "dependencies_write_errors"}),
+		current:            newWindow(),
+		previous:           newWindow(),
+		stop:               make(chan struct{}),
+		done:               make(chan struct{}),
+	}
+}
+
+// HandleSpan records the span, matching it with its parent and children received so far.
+// Its signature matches app.ProcessSpan.
+func (a *Aggregator) HandleSpan(span *model.Span, tenant string) {
+	service := span.Process.ServiceName
+	if service == "" {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	w := a.current
+	if w.size >= a.options.MaxSpans {
+		a.droppedSpanCounter.Inc(1)
+		return
+	}
+	key := spanKey{tenant: tenant, traceID: span.TraceID, spanID: span.SpanID}
+	w.services[key] = service
+	w.size++
+	for _, older := range []*window{a.previous, w} {
+		for _, child := range older.pending[key] {
+			w.addLink(service, child)
+		}
+		delete(older.pending, key)
+	}
+	parentID := span.ParentSpanID()
+	if parentID == model.NewSpanID(0) {
+		return
+	}
+	parentKey := spanKey{tenant: tenant, traceID: span.TraceID, spanID: parentID}
+	if parent, ok := w.services[parentKey]; ok {
+		w.addLink(parent, service)
+	} else if parent, ok := a.previous.services[parentKey]; ok {
+		w.addLink(parent, service)
+	} else {
+		w.pending[parentKey] = append(w.pending[parentKey], service)
+		w.size++
+	}
+}
+
+func (w *window) addLink(parent, child string) {
+	// calls within the same service are not dependencies
+	if parent != child {
+		w.links[linkKey{parent: parent, child: child}]++
+	}
+}
+
+// Start starts the periodic writing of the dependency links.
+func (a *Aggregator) Start() {
+	a.Lock()
+	defer a.Unlock()
+	if a.started || a.closed {
+		return
+	}
+	a.started = true
+	go a.runAggregationLoop()
+}
+
+func (a *Aggregator) runAggregationLoop() {
+	defer close(a.done)
+	ticker := time.NewTicker(a.options.Window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.flush()
+		case <-a.stop:
+			a.flush()
+			return
+		}
+	}
+}
+
+// flush writes the links of the current window and starts a new one.
+func (a *Aggregator) flush() {
+	a.Lock()
+	links := a.current.dependencyLinks()
+	a.current.links = nil
+	a.previous, a.current = a.current, newWindow()
+	a.Unlock()
+
+	if len(links) == 0 {
+		return
+	}
+	a.linksCounter.Inc(int64(len(links)))
+	if err := a.writer.WriteDependencies(a.timeNow(), links); err != nil {
+		a.writeErrorCounter.Inc(1)
+		a.logger.Error("Failed to write dependency links", zap.Error(err))
+	}
+}
+
+func (w *window) dependencyLinks() []model.DependencyLink {
+	links := make([]model.DependencyLink, 0, len(w.links))
+	for k, callCount := range w.links {
+		links = append(links, model.DependencyLink{
+			Parent:    k.parent,
+			Child:     k.child,
+			CallCount: callCount,
+			Source:    model.JaegerDependencyLinkSource,
+		})
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Parent != links[j].Parent {
+			return links[i].Parent < links[j].Parent
+		}
+		return links[i].Child < links[j].Child
+	})
+	return links
+}
+
+// Close stops the aggregation, writing the links of the current window.
+// It can be called even if the Aggregator was not started.
+func (a *Aggregator) Close() error {
+	a.Lock()
+	started, closed := a.started, a.closed
+	a.closed = true
+	a.Unlock()
+	if closed {
+		return nil
+	}
+	if !started {
+		a.flush()
+		return nil
+	}
+	close(a.stop)
+	<-a.done
+	return nil
+}
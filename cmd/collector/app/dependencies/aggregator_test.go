@@ -0,0 +1,157 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencies
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type fakeWriter struct {
+	sync.Mutex
+	writes [][]model.DependencyLink
+	err    error
+}
+
+func (w *fakeWriter) WriteDependencies(_ time.Time, links []model.DependencyLink) error {
+	w.Lock()
+	defer w.Unlock()
+	w.writes = append(w.writes, links)
+	return w.err
+}
+
+func newSpan(traceID, spanID, parentID uint64, service string) *model.Span {
+	span := &model.Span{
+		TraceID: model.NewTraceID(0, traceID),
+		SpanID:  model.NewSpanID(spanID),
+		Process: model.NewProcess(service, nil),
+	}
+	if parentID != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(span.TraceID, model.NewSpanID(parentID))}
+	}
+	return span
+}
+
+func link(parent, child string, callCount uint64) model.DependencyLink {
+	return model.DependencyLink{Parent: parent, Child: child, CallCount: callCount, Source: model.JaegerDependencyLinkSource}
+}
+
+func newTestAggregator(writer *fakeWriter, maxSpans int) (*Aggregator, *metricstest.Factory) {
+	metricsFactory := metricstest.NewFactory(0)
+	options := Options{Enabled: true, Window: time.Hour, MaxSpans: maxSpans}
+	return NewAggregator(options, writer, metricsFactory, zap.NewNop()), metricsFactory
+}
+
+func TestAggregatorLinks(t *testing.T) {
+	writer := &fakeWriter{}
+	a, metricsFactory := newTestAggregator(writer, 100)
+
+	a.HandleSpan(newSpan(1, 1, 0, "frontend"), "")
+	a.HandleSpan(newSpan(1, 2, 1, "api"), "")
+	// child received before its parent
+	a.HandleSpan(newSpan(1, 4, 3, "db"), "")
+	a.HandleSpan(newSpan(1, 3, 2, "api"), "")
+	a.HandleSpan(newSpan(2, 1, 0, "frontend"), "")
+	a.HandleSpan(newSpan(2, 2, 1, "api"), "")
+	// same span IDs in another tenant are not matched
+	a.HandleSpan(newSpan(2, 3, 2, "db"), "other")
+	a.HandleSpan(newSpan(1, 5, 0, ""), "")
+	a.flush()
+
+	require.Len(t, writer.writes, 1)
+	assert.Equal(t, []model.DependencyLink{
+		link("api", "db", 1),
+		link("frontend", "api", 2),
+	}, writer.writes[0])
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "dependencies_links", Value: 2})
+
+	// nothing to write
+	a.flush()
+	assert.Len(t, writer.writes, 1)
+}
+
+func TestAggregatorAcrossWindows(t *testing.T) {
+	writer := &fakeWriter{}
+	a, _ := newTestAggregator(writer, 100)
+
+	a.HandleSpan(newSpan(1, 1, 0, "frontend"), "")
+	a.HandleSpan(newSpan(1, 3, 2, "db"), "")
+	a.flush()
+	a.HandleSpan(newSpan(1, 2, 1, "api"), "")
+	a.flush()
+	// the parent was received two windows ago
+	a.flush()
+	a.HandleSpan(newSpan(1, 4, 1, "cache"), "")
+	a.flush()
+
+	assert.Equal(t, [][]model.DependencyLink{
+		{link("api", "db", 1), link("frontend", "api", 1)},
+	}, writer.writes)
+}
+
+func TestAggregatorMaxSpans(t *testing.T) {
+	writer := &fakeWriter{}
+	a, metricsFactory := newTestAggregator(writer, 3)
+
+	a.HandleSpan(newSpan(1, 2, 1, "api"), "")
+	a.HandleSpan(newSpan(1, 1, 0, "frontend"), "")
+	a.HandleSpan(newSpan(1, 3, 1, "db"), "")
+	a.flush()
+
+	assert.Equal(t, [][]model.DependencyLink{{link("frontend", "api", 1)}}, writer.writes)
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "dependencies_dropped_spans", Value: 1})
+}
+
+func TestAggregatorWriteError(t *testing.T) {
+	writer := &fakeWriter{err: assert.AnError}
+	a, metricsFactory := newTestAggregator(writer, 100)
+
+	a.HandleSpan(newSpan(1, 1, 0, "frontend"), "")
+	a.HandleSpan(newSpan(1, 2, 1, "api"), "")
+	a.flush()
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "dependencies_write_errors", Value: 1})
+}
+
+func TestAggregatorStartClose(t *testing.T) {
+	writer := &fakeWriter{}
+	a, _ := newTestAggregator(writer, 100)
+	a.options.Window = time.Millisecond
+	a.Start()
+
+	a.HandleSpan(newSpan(1, 1, 0, "frontend"), "")
+	a.HandleSpan(newSpan(1, 2, 1, "api"), "")
+	assert.Eventually(t, func() bool {
+		writer.Lock()
+		defer writer.Unlock()
+		return len(writer.writes) == 1
+	}, time.Second, time.Millisecond)
+
+	a.HandleSpan(newSpan(2, 1, 0, "frontend"), "")
+	a.HandleSpan(newSpan(2, 2, 1, "api"), "")
+	require.NoError(t, a.Close())
+	assert.Len(t, writer.writes, 2)
+
+	// the aggregation is stopped once
+	require.NoError(t, a.Close())
+	a.Start()
+	assert.Len(t, writer.writes, 2)
+}
+
+func TestAggregatorCloseNotStarted(t *testing.T) {
+	writer := &fakeWriter{}
+	a, _ := newTestAggregator(writer, 100)
+
+	a.HandleSpan(newSpan(1, 1, 0, "frontend"), "")
+	a.HandleSpan(newSpan(1, 2, 1, "api"), "")
+	require.NoError(t, a.Close())
+	assert.Equal(t, [][]model.DependencyLink{{link("frontend", "api", 1)}}, writer.writes)
+}
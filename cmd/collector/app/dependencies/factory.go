@@ -0,0 +1,51 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencies
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+)
+
+// CreateAggregator creates the Aggregator writing to the dependency storage of the factory.
+// It returns nil if the aggregation is disabled, or if the storage does not support writing
+// dependency links, e.g. because it derives them from the stored traces. It fails with
+// multi-tenancy, as the dependency storage would mix the links of all tenants.
+func CreateAggregator(
+	options Options,
+	tenancyMgr *tenancy.Manager,
+	storageFactory storage.Factory,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+) (*Aggregator, error) {
+	if !options.Enabled {
+		return nil, nil
+	}
+	if options.Window <= 0 {
+		return nil, errors.New("the dependency aggregation window must be positive")
+	}
+	if tenancyMgr != nil && tenancyMgr.Enabled {
+		return nil, errors.New("the dependency aggregation is not supported with multi-tenancy")
+	}
+	var writer dependencystore.Writer
+	err := storage.ErrDependencyWriterNotSupported
+	if dwFactory, ok := storageFactory.(storage.DependencyWriterFactory); ok {
+		writer, err = dwFactory.CreateDependencyWriter()
+	}
+	if errors.Is(err, storage.ErrDependencyWriterNotSupported) {
+		logger.Warn("Dependency aggregation is enabled, but the storage does not support writing dependencies")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dependency writer: %w", err)
+	}
+	return NewAggregator(options, writer, metricsFactory, logger), nil
+}
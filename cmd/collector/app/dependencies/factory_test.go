@@ -0,0 +1,63 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/mocks"
+)
+
+type writerFactory struct {
+	mocks.Factory
+	writer dependencystore.Writer
+	err    error
+}
+
+func (f *writerFactory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	return f.writer, f.err
+}
+
+func TestCreateAggregator(t *testing.T) {
+	options := Options{Enabled: true, Window: time.Minute, MaxSpans: 10}
+	create := func(options Options, factory storage.Factory) (*Aggregator, error) {
+		return CreateAggregator(options, nil, factory, metrics.NullFactory, zap.NewNop())
+	}
+
+	a, err := create(Options{}, &mocks.Factory{})
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	a, err = create(options, &mocks.Factory{})
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	a, err = create(options, &writerFactory{err: storage.ErrDependencyWriterNotSupported})
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	_, err = create(options, &writerFactory{err: assert.AnError})
+	require.ErrorIs(t, err, assert.AnError)
+
+	_, err = create(Options{Enabled: true}, &writerFactory{})
+	require.ErrorContains(t, err, "must be positive")
+
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true})
+	_, err = CreateAggregator(options, tm, &writerFactory{writer: &fakeWriter{}}, metrics.NullFactory, zap.NewNop())
+	require.EqualError(t, err, "the dependency aggregation is not supported with multi-tenancy")
+
+	writer := &fakeWriter{}
+	a, err = create(options, &writerFactory{writer: writer})
+	require.NoError(t, err)
+	assert.Equal(t, writer, a.writer)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencies
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	flagEnabled  = "collector.dependencies.enabled"
+	flagWindow   = "collector.dependencies.window"
+	flagMaxSpans = "collector.dependencies.max-spans"
+
+	defaultWindow   = 5 * time.Minute
+	defaultMaxSpans = 100_000
+)
+
+// Options holds configuration for the aggregation of the dependency links in the collector.
+type Options struct {
+	// Enabled turns on the aggregation.
+	Enabled bool
+	// Window is the duration of the tumbling windows after which the aggregated links are written.
+	Window time.Duration
+	// MaxSpans is the maximum number of spans remembered in a window to match them with their
+	// children. Spans received once the limit is reached are not aggregated.
+	MaxSpans int
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Bool(flagEnabled, false,
+		"Enables the aggregation of the service dependency links from the received spans, "+
+			"replacing the spark-dependencies job. Requires a storage backend supporting the writing of dependencies. Not supported with multi-tenancy.",
+	)
+	flagSet.Duration(flagWindow, defaultWindow,
+		"The duration of the tumbling windows after which the aggregated dependency links are written to storage.",
+	)
+	flagSet.Int(flagMaxSpans, defaultMaxSpans,
+		"The maximum number of spans remembered in a window to match them with their children.",
+	)
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts *Options) InitFromViper(v *viper.Viper) *Options {
+	opts.Enabled = v.GetBool(flagEnabled)
+	opts.Window = v.GetDuration(flagWindow)
+	opts.MaxSpans = v.GetInt(flagMaxSpans)
+	return opts
+}
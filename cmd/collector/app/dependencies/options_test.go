@@ -0,0 +1,30 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsWithFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.dependencies.enabled=true",
+		"--collector.dependencies.window=1m",
+		"--collector.dependencies.max-spans=10",
+	})
+	opts := new(Options).InitFromViper(v)
+	assert.Equal(t, Options{Enabled: true, Window: time.Minute, MaxSpans: 10}, *opts)
+}
+
+func TestOptionsDefaults(t *testing.T) {
+	v, _ := config.Viperize(AddFlags)
+	opts := new(Options).InitFromViper(v)
+	assert.Equal(t, Options{Window: defaultWindow, MaxSpans: defaultMaxSpans}, *opts)
+}
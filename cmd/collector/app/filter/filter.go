@@ -0,0 +1,114 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package filter drops the spans matching the configured rules in the collector,
+// e.g. the spans of the health checks and readiness probes.
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// Rule drops the spans matching all of its conditions, the conditions not set match all spans.
+type Rule struct {
+	// Name identifies the rule in the metrics.
+	Name string `json:"name"`
+	// Service is the service name of the spans to drop.
+	Service string `json:"service,omitempty"`
+	// Operation matches the operation names of the spans to drop.
+	Operation *regexp.Regexp `json:"operation,omitempty"`
+	// SpanKind is the kind of the spans to drop, e.g. "server".
+	SpanKind string `json:"span_kind,omitempty"`
+	// Tags are the values of the tags of the spans to drop.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// ParseRules parses the drop rules from a JSON array, e.g.
+// [{"name": "health-checks", "operation": "^GET /health", "span_kind": "server"}].
+func ParseRules(data []byte) ([]Rule, error) {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("cannot parse the filter rules: %w", err)
+	}
+	names := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("invalid filter rule #%d: %w", i, err)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate filter rule %q", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	return rules, nil
+}
+
+func (r Rule) validate() error {
+	if r.Name == "" {
+		return errors.New("the name is required")
+	}
+	if r.Service == "" && r.Operation == nil && r.SpanKind == "" && len(r.Tags) == 0 {
+		return fmt.Errorf("rule %q would drop all the spans", r.Name)
+	}
+	return nil
+}
+
+func (r Rule) matches(span *model.Span) bool {
+	if r.Service != "" && (span.Process == nil || span.Process.ServiceName != r.Service) {
+		return false
+	}
+	if r.Operation != nil && !r.Operation.MatchString(span.OperationName) {
+		return false
+	}
+	if r.SpanKind != "" {
+		if kind, ok := span.GetSpanKind(); !ok || kind.String() != r.SpanKind {
+			return false
+		}
+	}
+	for key, value := range r.Tags {
+		tag, ok := model.KeyValues(span.Tags).FindByKey(key)
+		if !ok || tag.AsString() != value {
+			return false
+		}
+	}
+	return true
+}
+
+// spanFilter drops the spans matching any of the rules
+type spanFilter struct {
+	rules   []Rule
+	dropped []metrics.Counter
+}
+
+// NewSpanFilter creates a span filter rejecting the spans matching any of the rules, in order.
+// The spans dropped by each rule are counted by the spans.filtered counter tagged with the rule name.
+func NewSpanFilter(rules []Rule, metricsFactory metrics.Factory) func(span *model.Span) bool {
+	filter := spanFilter{
+		rules:   rules,
+		dropped: make([]metrics.Counter, len(rules)),
+	}
+	for i, rule := range rules {
+		filter.dropped[i] = metricsFactory.Counter(metrics.Options{
+			Name: "spans.filtered",
+			Tags: map[string]string{"rule": rule.Name},
+		})
+	}
+	return filter.Filter
+}
+
+// Filter returns false if the span matches any of the rules and must be dropped.
+func (f *spanFilter) Filter(span *model.Span) bool {
+	for i, rule := range f.rules {
+		if rule.matches(span) {
+			f.dropped[i].Inc(1)
+			return false
+		}
+	}
+	return true
+}
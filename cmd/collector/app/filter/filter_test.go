@@ -0,0 +1,116 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func newSpan(service, operation string, tags ...model.KeyValue) *model.Span {
+	return &model.Span{
+		Process:       &model.Process{ServiceName: service},
+		OperationName: operation,
+		Tags:          tags,
+	}
+}
+
+func TestSpanFilter(t *testing.T) {
+	rules := []Rule{
+		{Name: "health-checks", Operation: regexp.MustCompile("^GET /health"), SpanKind: "server"},
+		{Name: "probes", Service: "frontend", Tags: map[string]string{"http.target": "/ready", "probe": "true"}},
+	}
+	mf := metricstest.NewFactory(0)
+	filter := NewSpanFilter(rules, mf)
+
+	testCases := []struct {
+		name string
+		span *model.Span
+		keep bool
+	}{
+		{
+			name: "health check",
+			span: newSpan("backend", "GET /healthz", model.String("span.kind", "server")),
+		},
+		{
+			name: "health check of a client",
+			span: newSpan("backend", "GET /healthz", model.String("span.kind", "client")),
+			keep: true,
+		},
+		{
+			name: "health check without span kind",
+			span: newSpan("backend", "GET /healthz"),
+			keep: true,
+		},
+		{
+			name: "probe",
+			span: newSpan("frontend", "GET", model.String("http.target", "/ready"), model.Bool("probe", true)),
+		},
+		{
+			name: "probe of another service",
+			span: newSpan("backend", "GET", model.String("http.target", "/ready"), model.Bool("probe", true)),
+			keep: true,
+		},
+		{
+			name: "probe with another tag value",
+			span: newSpan("frontend", "GET", model.String("http.target", "/ready"), model.Bool("probe", false)),
+			keep: true,
+		},
+		{
+			name: "probe without process",
+			span: &model.Span{Tags: []model.KeyValue{model.String("http.target", "/ready"), model.Bool("probe", true)}},
+			keep: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.keep, filter(tc.span))
+		})
+	}
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans.filtered", Tags: map[string]string{"rule": "health-checks"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "spans.filtered", Tags: map[string]string{"rule": "probes"}, Value: 1},
+	)
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`[
+		{"name": "health-checks", "operation": "^GET /health", "span_kind": "server"},
+		{"name": "probes", "service": "frontend", "tags": {"probe": "true"}}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "health-checks", rules[0].Name)
+	assert.Equal(t, "^GET /health", rules[0].Operation.String())
+	assert.Equal(t, "server", rules[0].SpanKind)
+	assert.Equal(t, Rule{Name: "probes", Service: "frontend", Tags: map[string]string{"probe": "true"}}, rules[1])
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	testCases := []struct {
+		data   string
+		expErr string
+	}{
+		{data: `{}`, expErr: "cannot parse the filter rules"},
+		{data: `[{"name": "bad", "operation": "("}]`, expErr: "cannot parse the filter rules"},
+		{data: `[{"service": "frontend"}]`, expErr: "invalid filter rule #0: the name is required"},
+		{data: `[{"name": "all"}]`, expErr: `rule "all" would drop all the spans`},
+		{
+			data:   `[{"name": "dup", "service": "frontend"}, {"name": "dup", "service": "backend"}]`,
+			expErr: `duplicate filter rule "dup"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expErr, func(t *testing.T) {
+			_, err := ParseRules([]byte(tc.data))
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
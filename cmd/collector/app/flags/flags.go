@@ -18,14 +18,20 @@ package flags
 import (
 	"flag"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/filter"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/validator"
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/ports"
 )
@@ -36,26 +42,62 @@ const (
 	flagQueueSize              = "collector.queue-size"
 	flagCollectorTags          = "collector.tags"
 	flagSpanSizeMetricsEnabled = "collector.enable-span-size-metrics"
-
-	flagSuffixHostPort = "host-port"
+	flagMaxBatchSpans          = "collector.max-batch-spans"
+	flagMaxSpanSize            = "collector.max-span-size"
+	flagDrainTimeout           = "collector.drain-timeout"
+
+	flagSpanLimitsPrefix                 = "collector.span-limits"
+	flagSpanLimitsMaxTags                = flagSpanLimitsPrefix + ".max-tags"
+	flagSpanLimitsMaxTagValueLength      = flagSpanLimitsPrefix + ".max-tag-value-length"
+	flagSpanLimitsMaxLogs                = flagSpanLimitsPrefix + ".max-logs"
+	flagSpanLimitsMaxLogFields           = flagSpanLimitsPrefix + ".max-log-fields"
+	flagSpanLimitsMaxLogFieldValueLength = flagSpanLimitsPrefix + ".max-log-field-value-length"
+	flagSpanLimitsServicesFile           = flagSpanLimitsPrefix + ".services-file"
+
+	flagFilterRulesFile = "collector.filter.rules-file"
+
+	flagValidationPrefix        = "collector.validation"
+	flagValidationEnabled       = flagValidationPrefix + ".enabled"
+	flagValidationActions       = flagValidationPrefix + ".actions"
+	flagValidationMaxAge        = flagValidationPrefix + ".max-age"
+	flagValidationMaxFutureSkew = flagValidationPrefix + ".max-future-skew"
+
+	flagServiceMetricsPrefix      = "collector.service-metrics"
+	flagServiceMetricsMaxServices = flagServiceMetricsPrefix + ".max-services"
+	flagServiceMetricsTopK        = flagServiceMetricsPrefix + ".top-k"
+	flagServiceMetricsAllowlist   = flagServiceMetricsPrefix + ".allowlist"
+
+	flagSuffixHostPort      = "host-port"
+	flagSuffixAddressFamily = "address-family"
 
 	flagSuffixHTTPReadTimeout       = "read-timeout"
 	flagSuffixHTTPReadHeaderTimeout = "read-header-timeout"
 	flagSuffixHTTPIdleTimeout       = "idle-timeout"
+	flagSuffixHTTPMaxRequestSize    = "max-request-size"
+	flagSuffixHTTPWriteTimeout      = "write-timeout"
+	flagSuffixHTTPMaxHeaderBytes    = "max-header-bytes"
+	flagSuffixHTTPRateLimit         = "rate-limit"
+	flagSuffixHTTPRateLimitBurst    = "rate-limit-burst"
 
 	flagSuffixGRPCMaxReceiveMessageLength = "max-message-size"
 	flagSuffixGRPCMaxConnectionAge        = "max-connection-age"
 	flagSuffixGRPCMaxConnectionAgeGrace   = "max-connection-age-grace"
+	flagSuffixGRPCUnixSocketPermissions   = "unix-socket-permissions"
 
 	flagCollectorOTLPEnabled = "collector.otlp.enabled"
 
 	flagZipkinHTTPHostPort     = "collector.zipkin.host-port"
 	flagZipkinKeepAliveEnabled = "collector.zipkin.keep-alive"
+	flagZipkinMaxRequestSize   = "collector.zipkin." + flagSuffixHTTPMaxRequestSize
 
 	// DefaultNumWorkers is the default number of workers consuming from the processor queue
 	DefaultNumWorkers = 50
 	// DefaultQueueSize is the size of the processor's queue
 	DefaultQueueSize = 2000
+	// DefaultDrainTimeout is the default maximum time to flush the processor's queue on shutdown
+	DefaultDrainTimeout = 5 * time.Second
+	// DefaultServiceMetricsMaxServices is the default maximum number of services reported by metric by service
+	DefaultServiceMetricsMaxServices = 4000
 	// DefaultGRPCMaxReceiveMessageLength is the default max receivable message size for the gRPC Collector
 	DefaultGRPCMaxReceiveMessageLength = 4 * 1024 * 1024
 )
@@ -100,6 +142,10 @@ var tlsZipkinFlagsConfig = tlscfg.ServerFlagsConfig{
 	Prefix: "collector.zipkin",
 }
 
+var corsHTTPFlags = corscfg.Flags{
+	Prefix: "collector.http-server",
+}
+
 var corsZipkinFlags = corscfg.Flags{
 	Prefix: "collector.zipkin",
 }
@@ -116,6 +162,9 @@ type CollectorOptions struct {
 	QueueSize int
 	// NumWorkers is the number of internal workers in a collector
 	NumWorkers int
+	// DrainTimeout is the maximum time to flush the spans in the queue to the storage on shutdown,
+	// the remaining spans are abandoned. The queue is not drained if 0.
+	DrainTimeout time.Duration
 	// HTTP section defines options for HTTP server
 	HTTP HTTPOptions
 	// GRPC section defines options for gRPC server
@@ -136,11 +185,38 @@ type CollectorOptions struct {
 		CORS corscfg.Options
 		// KeepAlive configures allow Keep-Alive for Zipkin HTTP server
 		KeepAlive bool
+		// MaxRequestSize is the maximum size in bytes of the body of the requests, unlimited if 0.
+		MaxRequestSize int
 	}
 	// CollectorTags is the string representing collector tags to append to each and every span
 	CollectorTags map[string]string
 	// SpanSizeMetricsEnabled determines whether to enable metrics based on processed span size
 	SpanSizeMetricsEnabled bool
+	// MaxBatchSpans is the maximum number of spans of a batch, the larger batches are rejected. Unlimited if 0.
+	MaxBatchSpans int
+	// MaxSpanSize is the maximum size in bytes of a span, the larger spans are dropped. Unlimited if 0.
+	MaxSpanSize int
+	// SpanLimits are the limits on the tags and logs of the spans, the spans exceeding them are truncated.
+	SpanLimits sanitizer.SpanLimits
+	// ServiceSpanLimits are the span limits of the services overriding the default SpanLimits.
+	ServiceSpanLimits map[string]sanitizer.SpanLimits
+	// FilterRules are the rules of the spans to drop, e.g. the spans of the health checks.
+	FilterRules []filter.Rule
+	// SpanValidation configures the validation of the spans, rejecting, fixing or tagging the invalid ones.
+	SpanValidation validator.Options
+	// ServiceMetrics bounds the services reported in the metrics by service.
+	ServiceMetrics ServiceMetricsOptions
+}
+
+// ServiceMetricsOptions bounds the services reported with their own svc tag in the metrics by service,
+// e.g. spans.received, the other services being reported as other-services.
+type ServiceMetricsOptions struct {
+	// MaxServices is the maximum number of services reported by metric.
+	MaxServices int
+	// TopK, if positive, only reports the K services receiving the most spans, in addition to the ones already reported.
+	TopK int
+	// Allowlist, if not empty, only reports the listed services, instead of the top-k ones.
+	Allowlist []string
 }
 
 type serverFlagsConfig struct {
@@ -152,6 +228,8 @@ type serverFlagsConfig struct {
 type HTTPOptions struct {
 	// HostPort is the host:port address that the server listens on
 	HostPort string
+	// AddressFamily restricts the addresses that the server listens on to IPv4 or IPv6
+	AddressFamily netutils.AddressFamily
 	// TLS configures secure transport for HTTP endpoint
 	TLS tlscfg.Options
 	// ReadTimeout sets the respective parameter of http.Server
@@ -160,6 +238,16 @@ type HTTPOptions struct {
 	ReadHeaderTimeout time.Duration
 	// IdleTimeout sets the respective parameter of http.Server
 	IdleTimeout time.Duration
+	// WriteTimeout sets the respective parameter of http.Server
+	WriteTimeout time.Duration
+	// MaxHeaderBytes sets the respective parameter of http.Server
+	MaxHeaderBytes int
+	// MaxRequestSize is the maximum size in bytes of the body of the requests, unlimited if 0.
+	MaxRequestSize int
+	// RateLimit is the maximum number of requests per second of each client IP address, unlimited if 0.
+	RateLimit float64
+	// RateLimitBurst is the maximum number of requests at once of each client IP address, RateLimit rounded up if 0.
+	RateLimitBurst int
 	// CORS allows CORS requests , sets the values for Allowed Headers and Allowed Origins.
 	CORS corscfg.Options
 }
@@ -168,6 +256,8 @@ type HTTPOptions struct {
 type GRPCOptions struct {
 	// HostPort is the host:port address that the collector service listens in on for gRPC requests
 	HostPort string
+	// AddressFamily restricts the addresses that the server listens on to IPv4 or IPv6
+	AddressFamily netutils.AddressFamily
 	// TLS configures secure transport for gRPC endpoint to collect spans
 	TLS tlscfg.Options
 	// MaxReceiveMessageLength is the maximum message size receivable by the gRPC Collector.
@@ -180,6 +270,9 @@ type GRPCOptions struct {
 	MaxConnectionAgeGrace time.Duration
 	// Tenancy configures tenancy for endpoints that collect spans
 	Tenancy tenancy.Options
+	// SocketPermissions are the permissions of the socket file when HostPort is a unix:///path endpoint,
+	// left to the umask if 0.
+	SocketPermissions os.FileMode
 }
 
 // AddFlags adds flags for CollectorOptions
@@ -189,8 +282,19 @@ func AddFlags(flags *flag.FlagSet) {
 	flags.Uint(flagDynQueueSizeMemory, 0, "(experimental) The max memory size in MiB to use for the dynamic queue.")
 	flags.String(flagCollectorTags, "", "One or more tags to be added to the Process tags of all spans passing through this collector. Ex: key1=value1,key2=${envVar:defaultValue}")
 	flags.Bool(flagSpanSizeMetricsEnabled, false, "Enables metrics based on processed span size, which are more expensive to calculate.")
+	flags.Int(flagMaxBatchSpans, 0, "The maximum number of spans in a batch, the larger batches are rejected (unlimited if 0)")
+	flags.Int(flagMaxSpanSize, 0, "The maximum size in bytes of a span, the larger spans are dropped (unlimited if 0)")
+	flags.Duration(flagDrainTimeout, DefaultDrainTimeout, "The maximum time to flush the spans in the queue to the storage on shutdown, after no longer accepting new spans (the queue is not drained if 0), shorter than the shutdown timeout")
+
+	addSpanLimitsFlags(flags)
+	flags.String(flagFilterRulesFile, "", "The path to a JSON file with the rules of the spans to drop, e.g. [{\"name\": \"health-checks\", \"operation\": \"^GET /health\", \"span_kind\": \"server\"}]")
+	addValidationFlags(flags)
+	addServiceMetricsFlags(flags)
 
 	addHTTPFlags(flags, httpServerFlagsCfg, ports.PortToHostPort(ports.CollectorHTTP))
+	corsHTTPFlags.AddFlags(flags)
+	// the OTLP/HTTP and Zipkin receivers always listen on both address families
+	flags.String(httpServerFlagsCfg.prefix+"."+flagSuffixAddressFamily, string(netutils.DualStack), fmt.Sprintf(netutils.AddressFamilyFlagUsage, "collector's HTTP server"))
 	addGRPCFlags(flags, grpcServerFlagsCfg, ports.PortToHostPort(ports.CollectorGRPC))
 
 	flags.Bool(flagCollectorOTLPEnabled, true, "Enables OpenTelemetry OTLP receiver on dedicated HTTP and gRPC ports")
@@ -200,17 +304,47 @@ func AddFlags(flags *flag.FlagSet) {
 
 	flags.String(flagZipkinHTTPHostPort, "", "The host:port (e.g. 127.0.0.1:9411 or :9411) of the collector's Zipkin server (disabled by default)")
 	flags.Bool(flagZipkinKeepAliveEnabled, true, "KeepAlive configures allow Keep-Alive for Zipkin HTTP server (enabled by default)")
+	flags.Int(flagZipkinMaxRequestSize, 0, "The maximum size in bytes of the body of the requests to the collector's Zipkin server (unlimited if 0)")
 	tlsZipkinFlagsConfig.AddFlags(flags)
 	corsZipkinFlags.AddFlags(flags)
 
 	tenancy.AddFlags(flags)
 }
 
+func addSpanLimitsFlags(flags *flag.FlagSet) {
+	flags.Int(flagSpanLimitsMaxTags, 0, "The maximum number of tags of a span, the extra tags are dropped (unlimited if 0)")
+	flags.Int(flagSpanLimitsMaxTagValueLength, 0, "The maximum length in bytes of the tag values, the longer values are truncated (unlimited if 0)")
+	flags.Int(flagSpanLimitsMaxLogs, 0, "The maximum number of logs of a span, the extra logs are dropped (unlimited if 0)")
+	flags.Int(flagSpanLimitsMaxLogFields, 0, "The maximum number of fields of a span log, the extra fields are dropped (unlimited if 0)")
+	flags.Int(flagSpanLimitsMaxLogFieldValueLength, 0, "The maximum length in bytes of the log field values, the longer values are truncated (unlimited if 0)")
+	flags.String(flagSpanLimitsServicesFile, "", "The path to a JSON file with the span limits of specific services overriding the default ones, e.g. {\"frontend\": {\"max_tags\": 128}}")
+}
+
+func addValidationFlags(flags *flag.FlagSet) {
+	flags.Bool(flagValidationEnabled, false, "Enables the validation of the spans, counting the invalid ones by service and violation in the spans.invalid metric")
+	flags.String(flagValidationActions, "", "The actions of the span violations overriding the default ones, as a comma separated list of violation=action, "+
+		"e.g. negative_duration=reject,invalid_start_time=fix. The violations are zero_trace_id and zero_span_id (rejected by default), "+
+		"negative_duration (fixed by default) and invalid_start_time (tagged by default), the actions are none, reject, fix and tag")
+	flags.Duration(flagValidationMaxAge, validator.DefaultMaxAge, "The maximum time the spans can start before they are received, the older spans having an invalid start time")
+	flags.Duration(flagValidationMaxFutureSkew, validator.DefaultMaxFutureSkew, "The maximum time the spans can start after they are received, the later spans having an invalid start time")
+}
+
+func addServiceMetricsFlags(flags *flag.FlagSet) {
+	flags.Int(flagServiceMetricsMaxServices, DefaultServiceMetricsMaxServices, "The maximum number of services reported by the metrics by service, e.g. spans.received, the other services being reported as other-services")
+	flags.Int(flagServiceMetricsTopK, 0, "The number of services receiving the most spans over the last minutes reported by the metrics by service, the services already reported being kept (all services if 0)")
+	flags.String(flagServiceMetricsAllowlist, "", "The comma separated list of the services reported by the metrics by service, instead of the top-k ones (all services if empty)")
+}
+
 func addHTTPFlags(flags *flag.FlagSet, cfg serverFlagsConfig, defaultHostPort string) {
 	flags.String(cfg.prefix+"."+flagSuffixHostPort, defaultHostPort, "The host:port (e.g. 127.0.0.1:12345 or :12345) of the collector's HTTP server")
 	flags.Duration(cfg.prefix+"."+flagSuffixHTTPIdleTimeout, 0, "See https://pkg.go.dev/net/http#Server")
 	flags.Duration(cfg.prefix+"."+flagSuffixHTTPReadTimeout, 0, "See https://pkg.go.dev/net/http#Server")
 	flags.Duration(cfg.prefix+"."+flagSuffixHTTPReadHeaderTimeout, 2*time.Second, "See https://pkg.go.dev/net/http#Server")
+	flags.Duration(cfg.prefix+"."+flagSuffixHTTPWriteTimeout, 0, "See https://pkg.go.dev/net/http#Server")
+	flags.Int(cfg.prefix+"."+flagSuffixHTTPMaxHeaderBytes, 0, "The maximum size in bytes of the headers of the requests (1 MiB if 0). See https://pkg.go.dev/net/http#Server")
+	flags.Int(cfg.prefix+"."+flagSuffixHTTPMaxRequestSize, 0, "The maximum size in bytes of the body of the requests to the collector's HTTP server (unlimited if 0)")
+	flags.Float64(cfg.prefix+"."+flagSuffixHTTPRateLimit, 0, "The maximum number of requests per second of each client IP address, the other requests are rejected with the 429 status (unlimited if 0)")
+	flags.Int(cfg.prefix+"."+flagSuffixHTTPRateLimitBurst, 0, "The maximum number of requests at once of each client IP address, above the rate limit (the rate limit rounded up if 0)")
 	cfg.tls.AddFlags(flags)
 }
 
@@ -218,7 +352,11 @@ func addGRPCFlags(flags *flag.FlagSet, cfg serverFlagsConfig, defaultHostPort st
 	flags.String(
 		cfg.prefix+"."+flagSuffixHostPort,
 		defaultHostPort,
-		"The host:port (e.g. 127.0.0.1:12345 or :12345) of the collector's gRPC server")
+		"The host:port (e.g. 127.0.0.1:12345 or :12345) of the collector's gRPC server, or unix:///path/to/socket to listen on a unix domain socket")
+	flags.String(
+		cfg.prefix+"."+flagSuffixAddressFamily,
+		string(netutils.DualStack),
+		fmt.Sprintf(netutils.AddressFamilyFlagUsage, "collector's gRPC server"))
 	flags.Int(
 		cfg.prefix+"."+flagSuffixGRPCMaxReceiveMessageLength,
 		DefaultGRPCMaxReceiveMessageLength,
@@ -231,14 +369,28 @@ func addGRPCFlags(flags *flag.FlagSet, cfg serverFlagsConfig, defaultHostPort st
 		cfg.prefix+"."+flagSuffixGRPCMaxConnectionAgeGrace,
 		0,
 		"The additive period after MaxConnectionAge after which the connection will be forcibly closed. See https://pkg.go.dev/google.golang.org/grpc/keepalive#ServerParameters")
+	flags.String(
+		cfg.prefix+"."+flagSuffixGRPCUnixSocketPermissions,
+		"",
+		"The octal permissions (e.g. 0660) of the socket file when the collector's gRPC server listens on a unix domain socket; the umask applies if empty")
 	cfg.tls.AddFlags(flags)
 }
 
 func (opts *HTTPOptions) initFromViper(v *viper.Viper, logger *zap.Logger, cfg serverFlagsConfig) error {
 	opts.HostPort = ports.FormatHostPort(v.GetString(cfg.prefix + "." + flagSuffixHostPort))
+	addressFamily, err := netutils.ParseAddressFamily(v.GetString(cfg.prefix + "." + flagSuffixAddressFamily))
+	if err != nil {
+		return fmt.Errorf("failed to parse HTTP address family: %w", err)
+	}
+	opts.AddressFamily = addressFamily
 	opts.IdleTimeout = v.GetDuration(cfg.prefix + "." + flagSuffixHTTPIdleTimeout)
 	opts.ReadTimeout = v.GetDuration(cfg.prefix + "." + flagSuffixHTTPReadTimeout)
 	opts.ReadHeaderTimeout = v.GetDuration(cfg.prefix + "." + flagSuffixHTTPReadHeaderTimeout)
+	opts.WriteTimeout = v.GetDuration(cfg.prefix + "." + flagSuffixHTTPWriteTimeout)
+	opts.MaxHeaderBytes = v.GetInt(cfg.prefix + "." + flagSuffixHTTPMaxHeaderBytes)
+	opts.MaxRequestSize = v.GetInt(cfg.prefix + "." + flagSuffixHTTPMaxRequestSize)
+	opts.RateLimit = v.GetFloat64(cfg.prefix + "." + flagSuffixHTTPRateLimit)
+	opts.RateLimitBurst = v.GetInt(cfg.prefix + "." + flagSuffixHTTPRateLimitBurst)
 	if tlsOpts, err := cfg.tls.InitFromViper(v); err == nil {
 		opts.TLS = tlsOpts
 	} else {
@@ -249,9 +401,19 @@ func (opts *HTTPOptions) initFromViper(v *viper.Viper, logger *zap.Logger, cfg s
 
 func (opts *GRPCOptions) initFromViper(v *viper.Viper, logger *zap.Logger, cfg serverFlagsConfig) error {
 	opts.HostPort = ports.FormatHostPort(v.GetString(cfg.prefix + "." + flagSuffixHostPort))
+	addressFamily, err := netutils.ParseAddressFamily(v.GetString(cfg.prefix + "." + flagSuffixAddressFamily))
+	if err != nil {
+		return fmt.Errorf("failed to parse gRPC address family: %w", err)
+	}
+	opts.AddressFamily = addressFamily
 	opts.MaxReceiveMessageLength = v.GetInt(cfg.prefix + "." + flagSuffixGRPCMaxReceiveMessageLength)
 	opts.MaxConnectionAge = v.GetDuration(cfg.prefix + "." + flagSuffixGRPCMaxConnectionAge)
 	opts.MaxConnectionAgeGrace = v.GetDuration(cfg.prefix + "." + flagSuffixGRPCMaxConnectionAgeGrace)
+	socketPermissions, err := netutils.ParseSocketPermissions(v.GetString(cfg.prefix + "." + flagSuffixGRPCUnixSocketPermissions))
+	if err != nil {
+		return fmt.Errorf("failed to parse gRPC unix socket permissions: %w", err)
+	}
+	opts.SocketPermissions = socketPermissions
 	if tlsOpts, err := cfg.tls.InitFromViper(v); err == nil {
 		opts.TLS = tlsOpts
 	} else {
@@ -262,6 +424,65 @@ func (opts *GRPCOptions) initFromViper(v *viper.Viper, logger *zap.Logger, cfg s
 	return nil
 }
 
+func (cOpts *CollectorOptions) initSpanLimitsFromViper(v *viper.Viper) error {
+	cOpts.SpanLimits = sanitizer.SpanLimits{
+		MaxTags:                v.GetInt(flagSpanLimitsMaxTags),
+		MaxTagValueLength:      v.GetInt(flagSpanLimitsMaxTagValueLength),
+		MaxLogs:                v.GetInt(flagSpanLimitsMaxLogs),
+		MaxLogFields:           v.GetInt(flagSpanLimitsMaxLogFields),
+		MaxLogFieldValueLength: v.GetInt(flagSpanLimitsMaxLogFieldValueLength),
+	}
+	path := v.GetString(flagSpanLimitsServicesFile)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the per-service span limits: %w", err)
+	}
+	cOpts.ServiceSpanLimits, err = sanitizer.ParseServiceSpanLimits(data, cOpts.SpanLimits)
+	return err
+}
+
+func (cOpts *CollectorOptions) initFilterRulesFromViper(v *viper.Viper) error {
+	path := v.GetString(flagFilterRulesFile)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the filter rules: %w", err)
+	}
+	cOpts.FilterRules, err = filter.ParseRules(data)
+	return err
+}
+
+func (cOpts *CollectorOptions) initValidationFromViper(v *viper.Viper) error {
+	actions, err := validator.ParseActions(v.GetString(flagValidationActions))
+	if err != nil {
+		return fmt.Errorf("failed to parse the span validation actions: %w", err)
+	}
+	cOpts.SpanValidation = validator.Options{
+		Enabled:       v.GetBool(flagValidationEnabled),
+		Actions:       actions,
+		MaxAge:        v.GetDuration(flagValidationMaxAge),
+		MaxFutureSkew: v.GetDuration(flagValidationMaxFutureSkew),
+	}
+	return nil
+}
+
+func (cOpts *CollectorOptions) initServiceMetricsFromViper(v *viper.Viper) {
+	cOpts.ServiceMetrics = ServiceMetricsOptions{
+		MaxServices: v.GetInt(flagServiceMetricsMaxServices),
+		TopK:        v.GetInt(flagServiceMetricsTopK),
+	}
+	for _, service := range strings.Split(v.GetString(flagServiceMetricsAllowlist), ",") {
+		if service = strings.TrimSpace(service); service != "" {
+			cOpts.ServiceMetrics.Allowlist = append(cOpts.ServiceMetrics.Allowlist, service)
+		}
+	}
+}
+
 // InitFromViper initializes CollectorOptions with properties from viper
 func (cOpts *CollectorOptions) InitFromViper(v *viper.Viper, logger *zap.Logger) (*CollectorOptions, error) {
 	cOpts.CollectorTags = flags.ParseJaegerTags(v.GetString(flagCollectorTags))
@@ -269,10 +490,28 @@ func (cOpts *CollectorOptions) InitFromViper(v *viper.Viper, logger *zap.Logger)
 	cOpts.QueueSize = v.GetInt(flagQueueSize)
 	cOpts.DynQueueSizeMemory = v.GetUint(flagDynQueueSizeMemory) * 1024 * 1024 // we receive in MiB and store in bytes
 	cOpts.SpanSizeMetricsEnabled = v.GetBool(flagSpanSizeMetricsEnabled)
+	cOpts.MaxBatchSpans = v.GetInt(flagMaxBatchSpans)
+	cOpts.MaxSpanSize = v.GetInt(flagMaxSpanSize)
+	cOpts.DrainTimeout = v.GetDuration(flagDrainTimeout)
+	// the collector must be done draining before it is abandoned, while the storage is still open
+	if shutdownTimeout := flags.ShutdownTimeout(v); shutdownTimeout > 0 && cOpts.DrainTimeout >= shutdownTimeout {
+		return cOpts, fmt.Errorf("the drain timeout %v must be shorter than the shutdown timeout %v", cOpts.DrainTimeout, shutdownTimeout)
+	}
+	if err := cOpts.initSpanLimitsFromViper(v); err != nil {
+		return cOpts, err
+	}
+	if err := cOpts.initFilterRulesFromViper(v); err != nil {
+		return cOpts, err
+	}
+	if err := cOpts.initValidationFromViper(v); err != nil {
+		return cOpts, err
+	}
+	cOpts.initServiceMetricsFromViper(v)
 
 	if err := cOpts.HTTP.initFromViper(v, logger, httpServerFlagsCfg); err != nil {
 		return cOpts, fmt.Errorf("failed to parse HTTP server options: %w", err)
 	}
+	cOpts.HTTP.CORS = corsHTTPFlags.InitFromViper(v)
 
 	if err := cOpts.GRPC.initFromViper(v, logger, grpcServerFlagsCfg); err != nil {
 		return cOpts, fmt.Errorf("failed to parse gRPC server options: %w", err)
@@ -288,6 +527,7 @@ func (cOpts *CollectorOptions) InitFromViper(v *viper.Viper, logger *zap.Logger)
 	}
 
 	cOpts.Zipkin.KeepAlive = v.GetBool(flagZipkinKeepAliveEnabled)
+	cOpts.Zipkin.MaxRequestSize = v.GetInt(flagZipkinMaxRequestSize)
 	cOpts.Zipkin.HTTPHostPort = ports.FormatHostPort(v.GetString(flagZipkinHTTPHostPort))
 	if tlsZipkin, err := tlsZipkinFlagsConfig.InitFromViper(v); err == nil {
 		cOpts.Zipkin.TLS = tlsZipkin
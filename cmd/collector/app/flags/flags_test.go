@@ -15,6 +15,8 @@
 package flags
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -22,7 +24,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/validator"
+	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 )
 
@@ -58,6 +64,55 @@ func TestCollectorOptionsWithFlags_CheckFullHostPort(t *testing.T) {
 	assert.Equal(t, "0.0.0.0:3456", c.Zipkin.HTTPHostPort)
 }
 
+func TestCollectorOptionsWithFlags_CheckAddressFamily(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.http-server.host-port=[::1]:5678",
+		"--collector.http-server.address-family=ipv6",
+		"--collector.grpc-server.address-family=ipv4",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, "[::1]:5678", c.HTTP.HostPort)
+	assert.Equal(t, netutils.IPv6, c.HTTP.AddressFamily)
+	assert.Equal(t, netutils.IPv4, c.GRPC.AddressFamily)
+	assert.Equal(t, netutils.DualStack, c.OTLP.GRPC.AddressFamily)
+	assert.Equal(t, netutils.DualStack, c.OTLP.HTTP.AddressFamily)
+}
+
+func TestCollectorOptionsWithFlags_CheckInvalidAddressFamily(t *testing.T) {
+	for _, flag := range []string{"--collector.http-server.address-family=ipx", "--collector.otlp.grpc.address-family=ipx"} {
+		c := &CollectorOptions{}
+		v, command := config.Viperize(AddFlags)
+		command.ParseFlags([]string{flag})
+		_, err := c.InitFromViper(v, zap.NewNop())
+		require.ErrorContains(t, err, `invalid address family "ipx"`)
+	}
+}
+
+func TestCollectorOptionsWithFlags_CheckUnixSocketPermissions(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.grpc-server.host-port=unix:///var/run/jaeger/collector.sock",
+		"--collector.grpc-server.unix-socket-permissions=0660",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, "unix:///var/run/jaeger/collector.sock", c.GRPC.HostPort)
+	assert.Equal(t, os.FileMode(0o660), c.GRPC.SocketPermissions)
+	assert.Zero(t, c.OTLP.GRPC.SocketPermissions)
+
+	c = &CollectorOptions{}
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--collector.otlp.grpc.unix-socket-permissions=rw"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to parse gRPC unix socket permissions")
+}
+
 func TestCollectorOptionsWithFailedTLSFlags(t *testing.T) {
 	prefixes := []string{
 		"--collector.http",
@@ -190,6 +245,185 @@ func TestCollectorOptionsWithFlags_CheckZipkinKeepAlive(t *testing.T) {
 	assert.False(t, c.Zipkin.KeepAlive)
 }
 
+func TestCollectorOptionsWithFlags_CheckLimits(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.max-batch-spans=1000",
+		"--collector.max-span-size=65536",
+		"--collector.http-server.max-request-size=1048576",
+		"--collector.otlp.http.max-request-size=2097152",
+		"--collector.zipkin.max-request-size=4194304",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1000, c.MaxBatchSpans)
+	assert.Equal(t, 65536, c.MaxSpanSize)
+	assert.Equal(t, 1048576, c.HTTP.MaxRequestSize)
+	assert.Equal(t, 2097152, c.OTLP.HTTP.MaxRequestSize)
+	assert.Equal(t, 4194304, c.Zipkin.MaxRequestSize)
+}
+
+func TestCollectorOptionsWithFlags_CheckHTTPHardening(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.http-server.write-timeout=10s",
+		"--collector.http-server.max-header-bytes=8192",
+		"--collector.http-server.rate-limit=12.5",
+		"--collector.http-server.rate-limit-burst=20",
+		"--collector.http-server.cors.allowed-origins=https://jaeger.example.com",
+		"--collector.http-server.cors.allowed-headers=Content-Type",
+		"--collector.otlp.http.rate-limit=5",
+		"--collector.zipkin.cors.allowed-origins=https://zipkin.example.com",
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, 10*time.Second, c.HTTP.WriteTimeout)
+	assert.Equal(t, 8192, c.HTTP.MaxHeaderBytes)
+	assert.InDelta(t, 12.5, c.HTTP.RateLimit, 0)
+	assert.Equal(t, 20, c.HTTP.RateLimitBurst)
+	assert.Equal(t, []string{"https://jaeger.example.com"}, c.HTTP.CORS.AllowedOrigins)
+	assert.Equal(t, []string{"Content-Type"}, c.HTTP.CORS.AllowedHeaders)
+	assert.InDelta(t, 5.0, c.OTLP.HTTP.RateLimit, 0)
+	assert.Equal(t, []string{"https://zipkin.example.com"}, c.Zipkin.CORS.AllowedOrigins)
+}
+
+func TestCollectorOptionsWithFlags_CheckDrainTimeout(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDrainTimeout, c.DrainTimeout)
+
+	command.ParseFlags([]string{"--collector.drain-timeout=30s"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, c.DrainTimeout)
+
+	// the queue is drained within the time given to the collector to stop on shutdown
+	svc := flags.NewService(0)
+	v, command = config.Viperize(AddFlags, svc.AddFlags)
+	command.ParseFlags([]string{"--collector.drain-timeout=30s"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.EqualError(t, err, "the drain timeout 30s must be shorter than the shutdown timeout 30s")
+
+	command.ParseFlags([]string{"--collector.drain-timeout=30s", "--shutdown.timeout=1m"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	command.ParseFlags([]string{"--collector.drain-timeout=30s", "--shutdown.timeout=0"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+}
+
+func TestCollectorOptionsWithFlags_CheckSpanLimits(t *testing.T) {
+	servicesFile := filepath.Join(t.TempDir(), "span-limits.json")
+	require.NoError(t, os.WriteFile(servicesFile, []byte(`{"frontend": {"max_tags": 256}}`), 0o600))
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.span-limits.max-tags=128",
+		"--collector.span-limits.max-tag-value-length=1024",
+		"--collector.span-limits.max-logs=64",
+		"--collector.span-limits.max-log-fields=16",
+		"--collector.span-limits.max-log-field-value-length=2048",
+		"--collector.span-limits.services-file=" + servicesFile,
+	})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	expected := sanitizer.SpanLimits{
+		MaxTags:                128,
+		MaxTagValueLength:      1024,
+		MaxLogs:                64,
+		MaxLogFields:           16,
+		MaxLogFieldValueLength: 2048,
+	}
+	assert.Equal(t, expected, c.SpanLimits)
+	expected.MaxTags = 256
+	assert.Equal(t, map[string]sanitizer.SpanLimits{"frontend": expected}, c.ServiceSpanLimits)
+
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--collector.span-limits.services-file=invalid.json"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to read the per-service span limits")
+}
+
+func TestCollectorOptionsWithFlags_CheckFilterRules(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "filter-rules.json")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`[{"name": "health-checks", "operation": "^GET /health"}]`), 0o600))
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--collector.filter.rules-file=" + rulesFile})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	require.Len(t, c.FilterRules, 1)
+	assert.Equal(t, "health-checks", c.FilterRules[0].Name)
+
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--collector.filter.rules-file=invalid.json"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to read the filter rules")
+}
+
+func TestCollectorOptionsWithFlags_CheckValidation(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.False(t, c.SpanValidation.Enabled)
+	assert.Equal(t, validator.DefaultActions(), c.SpanValidation.Actions)
+	assert.Equal(t, validator.DefaultMaxAge, c.SpanValidation.MaxAge)
+	assert.Equal(t, validator.DefaultMaxFutureSkew, c.SpanValidation.MaxFutureSkew)
+
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.validation.enabled=true",
+		"--collector.validation.actions=invalid_start_time=reject",
+		"--collector.validation.max-age=48h",
+		"--collector.validation.max-future-skew=5m",
+	})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.True(t, c.SpanValidation.Enabled)
+	assert.Equal(t, validator.ActionReject, c.SpanValidation.Actions[validator.ViolationInvalidStartTime])
+	assert.Equal(t, 48*time.Hour, c.SpanValidation.MaxAge)
+	assert.Equal(t, 5*time.Minute, c.SpanValidation.MaxFutureSkew)
+
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--collector.validation.actions=zero_trace_id=fix"})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to parse the span validation actions")
+}
+
+func TestCollectorOptionsWithFlags_CheckServiceMetrics(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{})
+	_, err := c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, ServiceMetricsOptions{MaxServices: DefaultServiceMetricsMaxServices}, c.ServiceMetrics)
+
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.service-metrics.max-services=100",
+		"--collector.service-metrics.top-k=10",
+		"--collector.service-metrics.allowlist=frontend, driver,,",
+	})
+	_, err = c.InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, ServiceMetricsOptions{
+		MaxServices: 100,
+		TopK:        10,
+		Allowlist:   []string{"frontend", "driver"},
+	}, c.ServiceMetrics)
+}
+
 func TestMain(m *testing.M) {
 	testutils.VerifyGoLeaks(m)
 }
@@ -95,6 +95,10 @@ func (c *batchConsumer) consume(ctx context.Context, batch *model.Batch) error {
 		if errors.Is(err, processor.ErrBusy) {
 			return status.Errorf(codes.ResourceExhausted, err.Error())
 		}
+		if errors.Is(err, processor.ErrBatchTooLarge) {
+			// not retryable, unlike the busy server
+			return status.Errorf(codes.InvalidArgument, err.Error())
+		}
 		c.logger.Error("cannot process spans", zap.Error(err))
 		return err
 	}
@@ -17,6 +17,7 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"sync"
 	"testing"
@@ -183,6 +184,10 @@ func TestPostSpansWithError(t *testing.T) {
 			processorError: processor.ErrBusy,
 			expectedError:  "server busy",
 		},
+		{
+			processorError: fmt.Errorf("%w: 3 spans", processor.ErrBatchTooLarge),
+			expectedError:  "code = InvalidArgument desc = too many spans in the batch: 3 spans",
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.expectedError, func(t *testing.T) {
@@ -16,6 +16,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"html"
 	"io"
@@ -63,6 +64,11 @@ func (aH *APIHandler) SaveSpan(w http.ResponseWriter, r *http.Request) {
 	bodyBytes, err := io.ReadAll(r.Body)
 	r.Body.Close()
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusInternalServerError)
 		return
 	}
@@ -87,6 +93,10 @@ func (aH *APIHandler) SaveSpan(w http.ResponseWriter, r *http.Request) {
 	batches := []*tJaeger.Batch{batch}
 	opts := SubmitBatchOptions{InboundTransport: processor.HTTPTransport}
 	if _, err = aH.jaegerBatchesHandler.SubmitBatches(batches, opts); err != nil {
+		if errors.Is(err, processor.ErrBatchTooLarge) {
+			http.Error(w, fmt.Sprintf("Cannot submit Jaeger batch: %v", err), http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, fmt.Sprintf("Cannot submit Jaeger batch: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -22,6 +22,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -33,6 +34,7 @@ import (
 	jaegerClient "github.com/uber/jaeger-client-go"
 	"github.com/uber/jaeger-client-go/transport"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 )
 
@@ -95,6 +97,12 @@ func TestThriftFormat(t *testing.T) {
 	require.NoError(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, statusCode)
 	assert.EqualValues(t, "Cannot submit Jaeger batch: Bad times ahead\n", resBodyStr)
+
+	handler.jaegerBatchesHandler.(*mockJaegerHandler).err = fmt.Errorf("%w: 3 spans", processor.ErrBatchTooLarge)
+	statusCode, resBodyStr, err = postBytes("application/vnd.apache.thrift.binary", server.URL+`/api/traces`, someBytes)
+	require.NoError(t, err)
+	assert.EqualValues(t, http.StatusRequestEntityTooLarge, statusCode)
+	assert.EqualValues(t, "Cannot submit Jaeger batch: too many spans in the batch: 3 spans\n", resBodyStr)
 }
 
 func TestViaClient(t *testing.T) {
@@ -169,6 +177,16 @@ func TestCannotReadBodyFromRequest(t *testing.T) {
 	assert.EqualValues(t, "Unable to process request body: Simulated error reading body\n", rw.myBody)
 }
 
+func TestBodyTooLarge(t *testing.T) {
+	handler := NewAPIHandler(&mockJaegerHandler{})
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/traces", strings.NewReader("too large"))
+	req.Body = http.MaxBytesReader(rw, req.Body, 3)
+	handler.SaveSpan(rw, req)
+	assert.EqualValues(t, http.StatusRequestEntityTooLarge, rw.Code)
+	assert.EqualValues(t, "Unable to process request body: http: request body too large\n", rw.Body.String())
+}
+
 type errReader struct{}
 
 func (e *errReader) Read(p []byte) (int, error) {
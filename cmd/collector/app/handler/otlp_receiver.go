@@ -16,15 +16,19 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"time"
 
-	otlp2jaeger "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver"
@@ -35,19 +39,29 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/model"
+	otlp2jaeger "github.com/jaegertracing/jaeger/model/converter/otlp"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 )
 
 var _ component.Host = (*otelHost)(nil) // API check
 
 // StartOTLPReceiver starts OpenTelemetry OTLP receiver listening on gRPC and HTTP ports.
-func StartOTLPReceiver(options *flags.CollectorOptions, logger *zap.Logger, spanProcessor processor.SpanProcessor, tm *tenancy.Manager) (receiver.Traces, error) {
+// The log records are received too if logProcessor is not nil.
+func StartOTLPReceiver(
+	options *flags.CollectorOptions,
+	logger *zap.Logger,
+	spanProcessor processor.SpanProcessor,
+	logProcessor processor.LogProcessor,
+	tm *tenancy.Manager,
+) (receiver.Traces, error) {
 	otlpFactory := otlpreceiver.NewFactory()
 	return startOTLPReceiver(
 		options,
 		logger,
 		spanProcessor,
+		logProcessor,
 		tm,
 		otlpFactory,
 		consumer.NewTraces,
@@ -62,6 +76,7 @@ func startOTLPReceiver(
 	options *flags.CollectorOptions,
 	logger *zap.Logger,
 	spanProcessor processor.SpanProcessor,
+	logProcessor processor.LogProcessor,
 	tm *tenancy.Manager,
 	// from here: params that can be mocked in tests
 	otlpFactory receiver.Factory,
@@ -72,6 +87,9 @@ func startOTLPReceiver(
 	otlpReceiverConfig := otlpFactory.CreateDefaultConfig().(*otlpreceiver.Config)
 	applyGRPCSettings(otlpReceiverConfig.GRPC, &options.OTLP.GRPC)
 	applyHTTPSettings(otlpReceiverConfig.HTTP.ServerConfig, &options.OTLP.HTTP)
+	if ignored := unsupportedHTTPSettings(&options.OTLP.HTTP); len(ignored) > 0 {
+		logger.Warn("The settings of the OTLP/HTTP server are not supported by the OTLP receiver and are ignored", zap.Strings("settings", ignored))
+	}
 	statusReporter := func(ev *component.StatusEvent) {
 		// TODO this could be wired into changing healthcheck.HealthCheck
 		logger.Info("OTLP receiver status change", zap.Stringer("status", ev.Status()))
@@ -100,9 +118,32 @@ func startOTLPReceiver(
 	if err != nil {
 		return nil, fmt.Errorf("could not create the OTLP receiver: %w", err)
 	}
+	if logProcessor != nil {
+		// the receivers created with the same config share the servers, the traces receiver starting them
+		logsConsumer, err := consumer.NewLogs(newLogsConsumerDelegate(logger, spanProcessor, logProcessor, tm).consume)
+		if err != nil {
+			return nil, fmt.Errorf("could not create the OTLP logs consumer: %w", err)
+		}
+		if _, err := otlpFactory.CreateLogsReceiver(context.Background(), otlpReceiverSettings, otlpReceiverConfig, logsConsumer); err != nil {
+			return nil, fmt.Errorf("could not create the OTLP logs receiver: %w", err)
+		}
+	}
+	socketPath, unixSocket := netutils.UnixSocketPath(options.OTLP.GRPC.HostPort)
+	if unixSocket {
+		if err := netutils.RemoveStaleSocket(socketPath); err != nil {
+			return nil, fmt.Errorf("could not start the OTLP receiver: %w", err)
+		}
+	}
 	if err := otlpReceiver.Start(context.Background(), &otelHost{logger: logger}); err != nil {
 		return nil, fmt.Errorf("could not start the OTLP receiver: %w", err)
 	}
+	if perm := options.OTLP.GRPC.SocketPermissions; unixSocket && perm != 0 {
+		if err := os.Chmod(socketPath, perm); err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("could not set the permissions of the OTLP receiver socket: %w", err),
+				otlpReceiver.Shutdown(context.Background()))
+		}
+	}
 	return otlpReceiver, nil
 }
 
@@ -110,6 +151,13 @@ func applyGRPCSettings(cfg *configgrpc.ServerConfig, opts *flags.GRPCOptions) {
 	if opts.HostPort != "" {
 		cfg.NetAddr.Endpoint = opts.HostPort
 	}
+	if opts.AddressFamily != "" {
+		cfg.NetAddr.Transport = confignet.TransportType(opts.AddressFamily.Network("tcp"))
+	}
+	if path, ok := netutils.UnixSocketPath(opts.HostPort); ok {
+		cfg.NetAddr.Endpoint = path
+		cfg.NetAddr.Transport = confignet.TransportTypeUnix
+	}
 	if opts.TLS.Enabled {
 		cfg.TLSSetting = applyTLSSettings(&opts.TLS)
 	}
@@ -133,6 +181,9 @@ func applyHTTPSettings(cfg *confighttp.ServerConfig, opts *flags.HTTPOptions) {
 	if opts.TLS.Enabled {
 		cfg.TLSSetting = applyTLSSettings(&opts.TLS)
 	}
+	if opts.MaxRequestSize > 0 {
+		cfg.MaxRequestBodySize = int64(opts.MaxRequestSize)
+	}
 
 	cfg.CORS = &confighttp.CORSConfig{
 		AllowedOrigins: opts.CORS.AllowedOrigins,
@@ -140,7 +191,38 @@ func applyHTTPSettings(cfg *confighttp.ServerConfig, opts *flags.HTTPOptions) {
 	}
 }
 
+// unsupportedHTTPSettings returns the names of the settings of the HTTP server that the
+// receivers of the OpenTelemetry Collector do not support, among the ones that are set.
+// Only CORS and the maximum request size can be applied to their HTTP servers.
+func unsupportedHTTPSettings(opts *flags.HTTPOptions) []string {
+	var ignored []string
+	if opts.ReadTimeout > 0 {
+		ignored = append(ignored, "read-timeout")
+	}
+	if opts.IdleTimeout > 0 {
+		ignored = append(ignored, "idle-timeout")
+	}
+	if opts.WriteTimeout > 0 {
+		ignored = append(ignored, "write-timeout")
+	}
+	if opts.MaxHeaderBytes > 0 {
+		ignored = append(ignored, "max-header-bytes")
+	}
+	if opts.RateLimit > 0 {
+		ignored = append(ignored, "rate-limit")
+	}
+	return ignored
+}
+
+// defaultCertReloadInterval is how often the receivers reload the certificates when
+// the reload interval is not set, since they do not watch the files for changes.
+const defaultCertReloadInterval = time.Minute
+
 func applyTLSSettings(opts *tlscfg.Options) *configtls.ServerConfig {
+	reloadInterval := opts.ReloadInterval
+	if reloadInterval == 0 {
+		reloadInterval = defaultCertReloadInterval
+	}
 	return &configtls.ServerConfig{
 		Config: configtls.Config{
 			CAFile:         opts.CAPath,
@@ -148,7 +230,7 @@ func applyTLSSettings(opts *tlscfg.Options) *configtls.ServerConfig {
 			KeyFile:        opts.KeyPath,
 			MinVersion:     opts.MinVersion,
 			MaxVersion:     opts.MaxVersion,
-			ReloadInterval: opts.ReloadInterval,
+			ReloadInterval: reloadInterval,
 		},
 		ClientCAFile: opts.ClientCAPath,
 	}
@@ -184,6 +266,37 @@ func (c *consumerDelegate) consume(ctx context.Context, td ptrace.Traces) error
 	return nil
 }
 
+func newLogsConsumerDelegate(
+	logger *zap.Logger,
+	spanProcessor processor.SpanProcessor,
+	logProcessor processor.LogProcessor,
+	tm *tenancy.Manager,
+) *logsConsumerDelegate {
+	return &logsConsumerDelegate{
+		// only used to validate the tenant like for the spans
+		batchConsumer: newBatchConsumer(logger,
+			spanProcessor,
+			processor.UnknownTransport,
+			processor.OTLPSpanFormat,
+			tm),
+		logProcessor: logProcessor,
+	}
+}
+
+type logsConsumerDelegate struct {
+	batchConsumer batchConsumer
+	logProcessor  processor.LogProcessor
+}
+
+func (c *logsConsumerDelegate) consume(ctx context.Context, ld plog.Logs) error {
+	tenant, err := c.batchConsumer.validateTenant(ctx)
+	if err != nil {
+		c.batchConsumer.logger.Debug("rejecting log records (tenancy)", zap.Error(err))
+		return err
+	}
+	return c.logProcessor.ProcessLogs(ld, tenant)
+}
+
 // otelHost is a mostly no-op implementation of OTEL component.Host
 type otelHost struct {
 	logger *zap.Logger
@@ -17,13 +17,18 @@ package handler
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver"
@@ -32,6 +37,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 )
@@ -51,7 +57,7 @@ func TestStartOtlpReceiver(t *testing.T) {
 	spanProcessor := &mockSpanProcessor{}
 	logger, _ := testutils.NewLogger()
 	tm := &tenancy.Manager{}
-	rec, err := StartOTLPReceiver(optionsWithPorts(":0"), logger, spanProcessor, tm)
+	rec, err := StartOTLPReceiver(optionsWithPorts(":0"), logger, spanProcessor, nil, tm)
 	require.NoError(t, err)
 	defer func() {
 		require.NoError(t, rec.Shutdown(context.Background()))
@@ -61,6 +67,53 @@ func TestStartOtlpReceiver(t *testing.T) {
 	// So we will rely on otlpreceiver being tested in the OTEL repos, and we only test the consumer function.
 }
 
+func TestStartOtlpReceiverUnsupportedHTTPSettings(t *testing.T) {
+	opts := optionsWithPorts(":0")
+	opts.OTLP.HTTP.RateLimit = 100
+	logger, buf := testutils.NewLogger()
+	rec, err := StartOTLPReceiver(opts, logger, &mockSpanProcessor{}, nil, &tenancy.Manager{})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, rec.Shutdown(context.Background()))
+	}()
+	assert.Contains(t, buf.String(), "are not supported by the OTLP receiver")
+	assert.Contains(t, buf.String(), "rate-limit")
+}
+
+func TestUnsupportedHTTPSettings(t *testing.T) {
+	assert.Empty(t, unsupportedHTTPSettings(&flags.HTTPOptions{
+		ReadHeaderTimeout: time.Second,
+		MaxRequestSize:    1024,
+	}))
+	assert.Equal(t,
+		[]string{"read-timeout", "idle-timeout", "write-timeout", "max-header-bytes", "rate-limit"},
+		unsupportedHTTPSettings(&flags.HTTPOptions{
+			ReadTimeout:    time.Second,
+			IdleTimeout:    time.Second,
+			WriteTimeout:   time.Second,
+			MaxHeaderBytes: 4096,
+			RateLimit:      1,
+		}))
+}
+
+func TestStartOtlpReceiverUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otlp.sock")
+	opts := optionsWithPorts(":0")
+	opts.OTLP.GRPC.HostPort = "unix://" + path
+	opts.OTLP.GRPC.SocketPermissions = 0o600
+	logger, _ := testutils.NewLogger()
+	rec, err := StartOTLPReceiver(opts, logger, &mockSpanProcessor{}, nil, &tenancy.Manager{})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, rec.Shutdown(context.Background()))
+	}()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSocket, info.Mode().Type())
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
 func makeTracesOneSpan() ptrace.Traces {
 	traces := ptrace.NewTraces()
 	rSpans := traces.ResourceSpans().AppendEmpty()
@@ -97,12 +150,58 @@ func TestConsumerDelegate(t *testing.T) {
 	}
 }
 
+type mockLogProcessor struct {
+	expectedError error
+	mux           sync.Mutex
+	records       int
+	tenants       map[string]bool
+}
+
+func (p *mockLogProcessor) ProcessLogs(logs plog.Logs, tenant string) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.records += logs.LogRecordCount()
+	if p.tenants == nil {
+		p.tenants = make(map[string]bool)
+	}
+	p.tenants[tenant] = true
+	return p.expectedError
+}
+
+func TestStartOtlpReceiverWithLogs(t *testing.T) {
+	logger, _ := testutils.NewLogger()
+	rec, err := StartOTLPReceiver(optionsWithPorts(":0"), logger, &mockSpanProcessor{}, &mockLogProcessor{}, &tenancy.Manager{})
+	require.NoError(t, err)
+	require.NoError(t, rec.Shutdown(context.Background()))
+}
+
+func TestLogsConsumerDelegate(t *testing.T) {
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+
+	logger, _ := testutils.NewLogger()
+	logProcessor := &mockLogProcessor{}
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true, Header: "x-tenant", Tenants: []string{"acme"}})
+	delegate := newLogsConsumerDelegate(logger, &mockSpanProcessor{}, logProcessor, tm)
+
+	ctx := withIncomingMetadata(context.Background(), "x-tenant", "acme", t)
+	require.NoError(t, delegate.consume(ctx, logs))
+	assert.Equal(t, 1, logProcessor.records)
+	assert.Equal(t, map[string]bool{"acme": true}, logProcessor.tenants)
+
+	require.Error(t, delegate.consume(context.Background(), logs))
+	assert.Equal(t, 1, logProcessor.records)
+
+	logProcessor.expectedError = errors.New("mock error")
+	require.ErrorIs(t, delegate.consume(ctx, logs), logProcessor.expectedError)
+}
+
 func TestStartOtlpReceiver_Error(t *testing.T) {
 	spanProcessor := &mockSpanProcessor{}
 	logger, _ := testutils.NewLogger()
 	opts := optionsWithPorts(":-1")
 	tm := &tenancy.Manager{}
-	_, err := StartOTLPReceiver(opts, logger, spanProcessor, tm)
+	_, err := StartOTLPReceiver(opts, logger, spanProcessor, nil, tm)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "could not start the OTLP receiver")
 
@@ -110,7 +209,7 @@ func TestStartOtlpReceiver_Error(t *testing.T) {
 		return nil, errors.New("mock error")
 	}
 	f := otlpreceiver.NewFactory()
-	_, err = startOTLPReceiver(opts, logger, spanProcessor, &tenancy.Manager{}, f, newTraces, f.CreateTracesReceiver)
+	_, err = startOTLPReceiver(opts, logger, spanProcessor, nil, &tenancy.Manager{}, f, newTraces, f.CreateTracesReceiver)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "could not create the OTLP consumer")
 
@@ -119,7 +218,7 @@ func TestStartOtlpReceiver_Error(t *testing.T) {
 	) (receiver.Traces, error) {
 		return nil, errors.New("mock error")
 	}
-	_, err = startOTLPReceiver(opts, logger, spanProcessor, &tenancy.Manager{}, f, consumer.NewTraces, createTracesReceiver)
+	_, err = startOTLPReceiver(opts, logger, spanProcessor, nil, &tenancy.Manager{}, f, consumer.NewTraces, createTracesReceiver)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "could not create the OTLP receiver")
 }
@@ -160,6 +259,7 @@ func TestApplyOTLPGRPCServerSettings(t *testing.T) {
 
 	grpcOpts := &flags.GRPCOptions{
 		HostPort:                ":54321",
+		AddressFamily:           netutils.IPv6,
 		MaxReceiveMessageLength: 42 * 1024 * 1024,
 		MaxConnectionAge:        33 * time.Second,
 		MaxConnectionAgeGrace:   37 * time.Second,
@@ -177,6 +277,7 @@ func TestApplyOTLPGRPCServerSettings(t *testing.T) {
 	applyGRPCSettings(otlpReceiverConfig.GRPC, grpcOpts)
 	out := otlpReceiverConfig.GRPC
 	assert.Equal(t, ":54321", out.NetAddr.Endpoint)
+	assert.Equal(t, confignet.TransportTypeTCP6, out.NetAddr.Transport)
 	assert.EqualValues(t, 42, out.MaxRecvMsgSizeMiB)
 	require.NotNil(t, out.Keepalive)
 	require.NotNil(t, out.Keepalive.ServerParameters)
@@ -190,6 +291,10 @@ func TestApplyOTLPGRPCServerSettings(t *testing.T) {
 	assert.Equal(t, "1.1", out.TLSSetting.MinVersion)
 	assert.Equal(t, "1.3", out.TLSSetting.MaxVersion)
 	assert.Equal(t, 24*time.Hour, out.TLSSetting.ReloadInterval)
+
+	applyGRPCSettings(otlpReceiverConfig.GRPC, &flags.GRPCOptions{HostPort: "unix:///var/run/otlp.sock"})
+	assert.Equal(t, "/var/run/otlp.sock", out.NetAddr.Endpoint)
+	assert.Equal(t, confignet.TransportTypeUnix, out.NetAddr.Transport)
 }
 
 func TestApplyOTLPHTTPServerSettings(t *testing.T) {
@@ -212,6 +317,7 @@ func TestApplyOTLPHTTPServerSettings(t *testing.T) {
 			AllowedOrigins: []string{"http://example.domain.com", "http://*.domain.com"},
 			AllowedHeaders: []string{"Content-Type", "Accept", "X-Requested-With"},
 		},
+		MaxRequestSize: 1024,
 	}
 
 	applyHTTPSettings(otlpReceiverConfig.HTTP.ServerConfig, httpOpts)
@@ -229,4 +335,10 @@ func TestApplyOTLPHTTPServerSettings(t *testing.T) {
 	assert.Equal(t, 24*time.Hour, out.TLSSetting.ReloadInterval)
 	assert.Equal(t, []string{"Content-Type", "Accept", "X-Requested-With"}, out.CORS.AllowedHeaders)
 	assert.Equal(t, []string{"http://example.domain.com", "http://*.domain.com"}, out.CORS.AllowedOrigins)
+	assert.EqualValues(t, 1024, out.MaxRequestBodySize)
+}
+
+func TestApplyTLSSettingsDefaultReloadInterval(t *testing.T) {
+	out := applyTLSSettings(&tlscfg.Options{Enabled: true, CertPath: "cert", KeyPath: "key"})
+	assert.Equal(t, defaultCertReloadInterval, out.ReloadInterval)
 }
@@ -57,7 +57,9 @@ func startZipkinReceiver(
 	applyHTTPSettings(&receiverConfig.ServerConfig, &flags.HTTPOptions{
 		HostPort: options.Zipkin.HTTPHostPort,
 		TLS:      options.Zipkin.TLS,
-		CORS:     options.HTTP.CORS,
+		CORS:     options.Zipkin.CORS,
+
+		MaxRequestSize: options.Zipkin.MaxRequestSize,
 		// TODO keepAlive not supported?
 	})
 	receiverSettings := receiver.CreateSettings{
@@ -19,6 +19,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
@@ -26,8 +27,8 @@ import (
 )
 
 const (
-	// TODO this needs to be configurable via CLI.
-	maxServiceNames = 4000
+	// maxServiceNames is the default maximum number of services reported by metric
+	maxServiceNames = flags.DefaultServiceMetricsMaxServices
 
 	// otherServices is the catch-all label when number of services exceeds maxServiceNames
 	otherServices = "other-services"
@@ -67,6 +68,12 @@ type SpanProcessorMetrics struct {
 	InQueueLatency metrics.Timer
 	// SpansDropped measures the number of spans we discarded because the queue was full
 	SpansDropped metrics.Counter
+	// SpansTooLarge measures the number of spans we discarded because they were larger than the maximum size
+	SpansTooLarge metrics.Counter
+	// SpansDrained measures the number of queued spans flushed to the storage when closing the collector
+	SpansDrained metrics.Counter
+	// SpansAbandoned measures the number of queued spans abandoned because the drain timed out
+	SpansAbandoned metrics.Counter
 	// SpansBytes records how many bytes were processed
 	SpansBytes metrics.Gauge
 	// BatchSize measures the span batch size
@@ -76,10 +83,16 @@ type SpanProcessorMetrics struct {
 	// QueueLength measures the current number of elements in the internal span queue
 	QueueLength metrics.Gauge
 	// SavedOkBySvc contains span and trace counts by service
-	SavedOkBySvc  metricsBySvc  // spans actually saved
-	SavedErrBySvc metricsBySvc  // spans failed to save
-	serviceNames  metrics.Gauge // total number of unique service name metrics reported by this collector
-	spanCounts    SpanCountsByFormat
+	SavedOkBySvc  metricsBySvc // spans actually saved
+	SavedErrBySvc metricsBySvc // spans failed to save
+	// DroppedBySvc contains the counts by service of the spans discarded because the queue was full
+	DroppedBySvc metricsBySvc
+	// BytesBySvc counts the bytes of the processed spans by service, when the span size metrics are enabled
+	BytesBySvc   spanCountsBySvc
+	serviceNames metrics.Gauge // total number of unique service name metrics reported by this collector
+	spanCounts   SpanCountsByFormat
+	// rejectedRequests counts the batches rejected for exceeding the limits, by inbound transport
+	rejectedRequests map[processor.InboundTransport]*processor.RejectedRequests
 }
 
 type countsBySvc struct {
@@ -89,6 +102,16 @@ type countsBySvc struct {
 	lock            *sync.Mutex
 	maxServiceNames int
 	category        string
+	// allow, if not nil, reports whether a service can have its own counters, the others using other-services
+	allow func(serviceName string) bool
+}
+
+// serviceLabels bounds the services reported with their own svc tag in the metrics by service,
+// the other services being reported as other-services.
+type serviceLabels struct {
+	maxServices int
+	// allow, if not nil, reports whether a service can be reported with its own svc tag
+	allow func(serviceName string) bool
 }
 
 type spanCountsBySvc struct {
@@ -121,39 +144,63 @@ type SpanCounts struct {
 
 // NewSpanProcessorMetrics returns a SpanProcessorMetrics
 func NewSpanProcessorMetrics(serviceMetrics metrics.Factory, hostMetrics metrics.Factory, otherFormatTypes []processor.SpanFormat) *SpanProcessorMetrics {
+	return newSpanProcessorMetrics(serviceMetrics, hostMetrics, otherFormatTypes, serviceLabels{maxServices: maxServiceNames})
+}
+
+func newSpanProcessorMetrics(
+	serviceMetrics metrics.Factory,
+	hostMetrics metrics.Factory,
+	otherFormatTypes []processor.SpanFormat,
+	labels serviceLabels,
+) *SpanProcessorMetrics {
 	spanCounts := SpanCountsByFormat{
-		processor.ZipkinSpanFormat:  newCountsByTransport(serviceMetrics, processor.ZipkinSpanFormat),
-		processor.JaegerSpanFormat:  newCountsByTransport(serviceMetrics, processor.JaegerSpanFormat),
-		processor.ProtoSpanFormat:   newCountsByTransport(serviceMetrics, processor.ProtoSpanFormat),
-		processor.UnknownSpanFormat: newCountsByTransport(serviceMetrics, processor.UnknownSpanFormat),
+		processor.ZipkinSpanFormat:  newCountsByTransport(serviceMetrics, processor.ZipkinSpanFormat, labels),
+		processor.JaegerSpanFormat:  newCountsByTransport(serviceMetrics, processor.JaegerSpanFormat, labels),
+		processor.ProtoSpanFormat:   newCountsByTransport(serviceMetrics, processor.ProtoSpanFormat, labels),
+		processor.UnknownSpanFormat: newCountsByTransport(serviceMetrics, processor.UnknownSpanFormat, labels),
 	}
 	for _, otherFormatType := range otherFormatTypes {
-		spanCounts[otherFormatType] = newCountsByTransport(serviceMetrics, otherFormatType)
+		spanCounts[otherFormatType] = newCountsByTransport(serviceMetrics, otherFormatType, labels)
 	}
+	bytesBySvc := newSpanCountsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "spans"}), "bytes-by-svc", labels.maxServices)
+	bytesBySvc.allow = labels.allow
 	m := &SpanProcessorMetrics{
 		SaveLatency:    hostMetrics.Timer(metrics.TimerOptions{Name: "save-latency", Tags: nil}),
 		InQueueLatency: hostMetrics.Timer(metrics.TimerOptions{Name: "in-queue-latency", Tags: nil}),
 		SpansDropped:   hostMetrics.Counter(metrics.Options{Name: "spans.dropped", Tags: nil}),
+		SpansTooLarge:  hostMetrics.Counter(metrics.Options{Name: "spans.too-large", Tags: nil}),
+		SpansDrained:   hostMetrics.Counter(metrics.Options{Name: "spans.drained", Tags: map[string]string{"result": "flushed"}}),
+		SpansAbandoned: hostMetrics.Counter(metrics.Options{Name: "spans.drained", Tags: map[string]string{"result": "abandoned"}}),
 		BatchSize:      hostMetrics.Gauge(metrics.Options{Name: "batch-size", Tags: nil}),
 		QueueCapacity:  hostMetrics.Gauge(metrics.Options{Name: "queue-capacity", Tags: nil}),
 		QueueLength:    hostMetrics.Gauge(metrics.Options{Name: "queue-length", Tags: nil}),
 		SpansBytes:     hostMetrics.Gauge(metrics.Options{Name: "spans.bytes", Tags: nil}),
-		SavedOkBySvc:   newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"result": "ok"}}), "saved-by-svc"),
-		SavedErrBySvc:  newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"result": "err"}}), "saved-by-svc"),
+		SavedOkBySvc:   newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"result": "ok"}}), "saved-by-svc", labels),
+		SavedErrBySvc:  newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"result": "err"}}), "saved-by-svc", labels),
+		DroppedBySvc:   newMetricsBySvc(serviceMetrics, "dropped-by-svc", labels),
+		BytesBySvc:     bytesBySvc,
 		spanCounts:     spanCounts,
 		serviceNames:   hostMetrics.Gauge(metrics.Options{Name: "spans.serviceNames", Tags: nil}),
+		rejectedRequests: map[processor.InboundTransport]*processor.RejectedRequests{
+			processor.HTTPTransport:    processor.NewRejectedRequests(serviceMetrics, processor.HTTPTransport),
+			processor.GRPCTransport:    processor.NewRejectedRequests(serviceMetrics, processor.GRPCTransport),
+			processor.UnknownTransport: processor.NewRejectedRequests(serviceMetrics, processor.UnknownTransport),
+		},
 	}
 
 	return m
 }
 
-func newMetricsBySvc(factory metrics.Factory, category string) metricsBySvc {
+func newMetricsBySvc(factory metrics.Factory, category string, labels serviceLabels) metricsBySvc {
 	spansFactory := factory.Namespace(metrics.NSOptions{Name: "spans", Tags: nil})
 	tracesFactory := factory.Namespace(metrics.NSOptions{Name: "traces", Tags: nil})
-	return metricsBySvc{
-		spans:  newSpanCountsBySvc(spansFactory, category, maxServiceNames),
-		traces: newTraceCountsBySvc(tracesFactory, category, maxServiceNames),
+	m := metricsBySvc{
+		spans:  newSpanCountsBySvc(spansFactory, category, labels.maxServices),
+		traces: newTraceCountsBySvc(tracesFactory, category, labels.maxServices),
 	}
+	m.spans.allow = labels.allow
+	m.traces.allow = labels.allow
+	return m
 }
 
 func newTraceCountsBySvc(factory metrics.Factory, category string, maxServices int) traceCountsBySvc {
@@ -205,23 +252,31 @@ func newSpanCountsBySvc(factory metrics.Factory, category string, maxServiceName
 	}
 }
 
-func newCountsByTransport(factory metrics.Factory, format processor.SpanFormat) SpanCountsByTransport {
+func newCountsByTransport(factory metrics.Factory, format processor.SpanFormat, labels serviceLabels) SpanCountsByTransport {
 	factory = factory.Namespace(metrics.NSOptions{Tags: map[string]string{"format": string(format)}})
 	return SpanCountsByTransport{
-		processor.HTTPTransport:    newCounts(factory, processor.HTTPTransport),
-		processor.GRPCTransport:    newCounts(factory, processor.GRPCTransport),
-		processor.UnknownTransport: newCounts(factory, processor.UnknownTransport),
+		processor.HTTPTransport:    newCounts(factory, processor.HTTPTransport, labels),
+		processor.GRPCTransport:    newCounts(factory, processor.GRPCTransport, labels),
+		processor.UnknownTransport: newCounts(factory, processor.UnknownTransport, labels),
 	}
 }
 
-func newCounts(factory metrics.Factory, transport processor.InboundTransport) SpanCounts {
+func newCounts(factory metrics.Factory, transport processor.InboundTransport, labels serviceLabels) SpanCounts {
 	factory = factory.Namespace(metrics.NSOptions{Tags: map[string]string{"transport": string(transport)}})
 	return SpanCounts{
-		RejectedBySvc: newMetricsBySvc(factory, "rejected"),
-		ReceivedBySvc: newMetricsBySvc(factory, "received"),
+		RejectedBySvc: newMetricsBySvc(factory, "rejected", labels),
+		ReceivedBySvc: newMetricsBySvc(factory, "received", labels),
 	}
 }
 
+// getRejectedRequests gets the metrics of the rejected requests for a given transport. If none exists, we use the Unknown transport.
+func (m *SpanProcessorMetrics) getRejectedRequests(transport processor.InboundTransport) *processor.RejectedRequests {
+	if r, ok := m.rejectedRequests[transport]; ok {
+		return r
+	}
+	return m.rejectedRequests[processor.UnknownTransport]
+}
+
 // GetCountsForFormat gets the SpanCounts for a given format and transport. If none exists, we use the Unknown format.
 func (m *SpanProcessorMetrics) GetCountsForFormat(spanFormat processor.SpanFormat, transport processor.InboundTransport) SpanCounts {
 	c, ok := m.spanCounts[spanFormat]
@@ -238,13 +293,7 @@ func (m *SpanProcessorMetrics) GetCountsForFormat(spanFormat processor.SpanForma
 // reportServiceNameForSpan determines the name of the service that emitted
 // the span and reports a counter stat.
 func (m metricsBySvc) ReportServiceNameForSpan(span *model.Span) {
-	var serviceName string
-	if nil == span.Process || len(span.Process.ServiceName) == 0 {
-		serviceName = "__unknown"
-	} else {
-		serviceName = span.Process.ServiceName
-	}
-
+	serviceName := spanServiceName(span)
 	m.countSpansByServiceName(serviceName, span.Flags.IsDebug())
 	if span.ParentSpanID() == 0 {
 		m.countTracesByServiceName(serviceName, span.Flags.IsDebug(), span.
@@ -252,6 +301,19 @@ func (m metricsBySvc) ReportServiceNameForSpan(span *model.Span) {
 	}
 }
 
+// spanServiceName returns the name of the service that emitted the span, __unknown if not set.
+func spanServiceName(span *model.Span) string {
+	if nil == span.Process || len(span.Process.ServiceName) == 0 {
+		return "__unknown"
+	}
+	return span.Process.ServiceName
+}
+
+// reportSizeForSpan adds the size of the span to the counter of the service that emitted it.
+func (m *spanCountsBySvc) reportSizeForSpan(span *model.Span, size int) {
+	m.addByServiceName(spanServiceName(span), span.Flags.IsDebug(), int64(size))
+}
+
 // countSpansByServiceName counts how many spans are received per service.
 func (m metricsBySvc) countSpansByServiceName(serviceName string, isDebug bool) {
 	m.spans.countByServiceName(serviceName, isDebug)
@@ -280,14 +342,15 @@ func (m *traceCountsBySvc) countByServiceName(serviceName string, isDebug bool,
 		counts = m.debugCounts
 	}
 	var counter metrics.Counter
+	allowed := m.allowed(serviceName)
 	m.lock.Lock()
 
 	// trace counter key is combination of serviceName and samplerType.
 	key := m.buildKey(serviceName, samplerType.String())
 
-	if c, ok := counts[key]; ok {
+	if c, ok := counts[key]; ok && allowed {
 		counter = c
-	} else if len(counts) < m.maxServiceNames {
+	} else if !ok && allowed && len(counts) < m.maxServiceNames {
 		debugStr := "false"
 		if isDebug {
 			debugStr = "true"
@@ -320,17 +383,23 @@ func (m *traceCountsBySvc) countByServiceName(serviceName string, isDebug bool,
 // an alert should be raised to investigate what's causing so many unique
 // service names.
 func (m *spanCountsBySvc) countByServiceName(serviceName string, isDebug bool) {
+	m.addByServiceName(serviceName, isDebug, 1)
+}
+
+// addByServiceName adds the delta to the counter of the service, like countByServiceName.
+func (m *spanCountsBySvc) addByServiceName(serviceName string, isDebug bool, delta int64) {
 	serviceName = normalizer.ServiceName(serviceName)
 	counts := m.counts
 	if isDebug {
 		counts = m.debugCounts
 	}
 	var counter metrics.Counter
+	allowed := m.allowed(serviceName)
 	m.lock.Lock()
 
-	if c, ok := counts[serviceName]; ok {
+	if c, ok := counts[serviceName]; ok && allowed {
 		counter = c
-	} else if len(counts) < m.maxServiceNames {
+	} else if !ok && allowed && len(counts) < m.maxServiceNames {
 		debugStr := "false"
 		if isDebug {
 			debugStr = "true"
@@ -343,7 +412,13 @@ func (m *spanCountsBySvc) countByServiceName(serviceName string, isDebug bool) {
 		counter = counts[otherServices]
 	}
 	m.lock.Unlock()
-	counter.Inc(1)
+	counter.Inc(delta)
+}
+
+// allowed returns whether the service can have its own counters. The services no longer allowed,
+// e.g. the services leaving the top-k ones, are counted as other-services again.
+func (m *countsBySvc) allowed(serviceName string) bool {
+	return m.allow == nil || m.allow(serviceName)
 }
 
 func (m *traceCountsBySvc) buildKey(serviceName, samplerType string) string {
@@ -120,6 +120,48 @@ func TestNewSpanCountsBySvc(t *testing.T) {
 	assert.EqualValues(t, 2, counters["not_on_my_level|debug=true|svc=other-services"])
 }
 
+func TestCountsBySvcAllowedServices(t *testing.T) {
+	baseMetrics := metricstest.NewFactory(time.Hour)
+	defer baseMetrics.Backend.Stop()
+	allowed := "fry"
+	allow := func(serviceName string) bool { return serviceName == allowed }
+	m := newMetricsBySvc(baseMetrics, "received", serviceLabels{maxServices: 10, allow: allow})
+	m.ReportServiceNameForSpan(&model.Span{Process: &model.Process{ServiceName: "fry"}})
+	m.ReportServiceNameForSpan(&model.Span{Process: &model.Process{ServiceName: "leela"}})
+
+	counters, _ := baseMetrics.Backend.Snapshot()
+	assert.EqualValues(t, 1, counters["spans.received|debug=false|svc=fry"])
+	assert.EqualValues(t, 1, counters["spans.received|debug=false|svc=other-services"])
+	assert.EqualValues(t, 1, counters["traces.received|debug=false|sampler_type=unrecognized|svc=fry"])
+	assert.EqualValues(t, 1, counters["traces.received|debug=false|sampler_type=unrecognized|svc=other-services"])
+	assert.NotContains(t, counters, "spans.received|debug=false|svc=leela")
+
+	// the services no longer allowed, e.g. leaving the top-k services, are counted as other-services
+	allowed = "leela"
+	m.ReportServiceNameForSpan(&model.Span{Process: &model.Process{ServiceName: "fry"}})
+	m.ReportServiceNameForSpan(&model.Span{Process: &model.Process{ServiceName: "leela"}})
+
+	counters, _ = baseMetrics.Backend.Snapshot()
+	assert.EqualValues(t, 1, counters["spans.received|debug=false|svc=fry"])
+	assert.EqualValues(t, 1, counters["spans.received|debug=false|svc=leela"])
+	assert.EqualValues(t, 2, counters["spans.received|debug=false|svc=other-services"])
+	assert.EqualValues(t, 1, counters["traces.received|debug=false|sampler_type=unrecognized|svc=fry"])
+	assert.EqualValues(t, 2, counters["traces.received|debug=false|sampler_type=unrecognized|svc=other-services"])
+}
+
+func TestSpanCountsBySvcReportSize(t *testing.T) {
+	baseMetrics := metricstest.NewFactory(time.Hour)
+	defer baseMetrics.Backend.Stop()
+	m := newSpanCountsBySvc(baseMetrics, "bytes-by-svc", 2)
+	m.reportSizeForSpan(&model.Span{Process: &model.Process{ServiceName: "fry"}}, 100)
+	m.reportSizeForSpan(&model.Span{Process: &model.Process{ServiceName: "fry"}}, 50)
+	m.reportSizeForSpan(&model.Span{}, 10)
+
+	counters, _ := baseMetrics.Backend.Snapshot()
+	assert.EqualValues(t, 150, counters["bytes-by-svc|debug=false|svc=fry"])
+	assert.EqualValues(t, 10, counters["bytes-by-svc|debug=false|svc=other-services"])
+}
+
 func TestBuildKey(t *testing.T) {
 	// This test checks if stringBuilder is reset every time buildKey is called.
 	tc := newTraceCountsBySvc(jaegerM.NullFactory, "received", 100)
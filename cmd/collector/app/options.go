@@ -16,6 +16,8 @@
 package app
 
 import (
+	"time"
+
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
@@ -33,6 +35,7 @@ type options struct {
 	sanitizer              sanitizer.SanitizeSpan
 	preSave                ProcessSpan
 	spanFilter             FilterSpan
+	spanValidator          FilterSpan
 	numWorkers             int
 	blockingSubmit         bool
 	queueSize              int
@@ -42,7 +45,12 @@ type options struct {
 	extraFormatTypes       []processor.SpanFormat
 	collectorTags          map[string]string
 	spanSizeMetricsEnabled bool
+	maxBatchSpans          int
+	maxSpanSize            int
+	drainTimeout           time.Duration
 	onDroppedSpan          func(span *model.Span)
+	serviceProducers       *ServiceProducers
+	serviceAllowlist       []string
 }
 
 // Option is a function that sets some option on StorageBuilder.
@@ -74,7 +82,7 @@ func (options) HostMetrics(hostMetrics metrics.Factory) Option {
 
 // PreProcessSpans creates an Option that initializes the preProcessSpans function.
 // This function can implement non-standard pre-processing of the spans when extending
-// the collector from source. Jaeger itself does not define any pre-processing.
+// the collector from source. Jaeger itself only uses it to attach the OTLP log records to their spans.
 func (options) PreProcessSpans(preProcessSpans ProcessSpans) Option {
 	return func(b *options) {
 		b.preProcessSpans = preProcessSpans
@@ -102,6 +110,13 @@ func (options) SpanFilter(spanFilter FilterSpan) Option {
 	}
 }
 
+// SpanValidator creates an Option that initializes the spanValidator function
+func (options) SpanValidator(spanValidator FilterSpan) Option {
+	return func(b *options) {
+		b.spanValidator = spanValidator
+	}
+}
+
 // NumWorkers creates an Option that initializes the number of queue consumers AKA workers
 func (options) NumWorkers(numWorkers int) Option {
 	return func(b *options) {
@@ -165,6 +180,27 @@ func (options) SpanSizeMetricsEnabled(spanSizeMetrics bool) Option {
 	}
 }
 
+// MaxBatchSpans creates an Option that initializes the maximum number of spans of a batch
+func (options) MaxBatchSpans(maxBatchSpans int) Option {
+	return func(b *options) {
+		b.maxBatchSpans = maxBatchSpans
+	}
+}
+
+// MaxSpanSize creates an Option that initializes the maximum size in bytes of a span
+func (options) MaxSpanSize(maxSpanSize int) Option {
+	return func(b *options) {
+		b.maxSpanSize = maxSpanSize
+	}
+}
+
+// DrainTimeout creates an Option that initializes the maximum time to flush the queued spans when closing
+func (options) DrainTimeout(drainTimeout time.Duration) Option {
+	return func(b *options) {
+		b.drainTimeout = drainTimeout
+	}
+}
+
 // OnDroppedSpan creates an Option that initializes the onDroppedSpan function
 func (options) OnDroppedSpan(onDroppedSpan func(span *model.Span)) Option {
 	return func(b *options) {
@@ -172,6 +208,21 @@ func (options) OnDroppedSpan(onDroppedSpan func(span *model.Span)) Option {
 	}
 }
 
+// ServiceProducers creates an Option that initializes the counts of the spans by service,
+// bounding the services reported in the metrics by service
+func (options) ServiceProducers(serviceProducers *ServiceProducers) Option {
+	return func(b *options) {
+		b.serviceProducers = serviceProducers
+	}
+}
+
+// ServiceAllowlist creates an Option that initializes the only services reported in the metrics by service
+func (options) ServiceAllowlist(serviceAllowlist []string) Option {
+	return func(b *options) {
+		b.serviceAllowlist = serviceAllowlist
+	}
+}
+
 func (o options) apply(opts ...Option) options {
 	ret := options{}
 	for _, opt := range opts {
@@ -198,8 +249,14 @@ func (o options) apply(opts ...Option) options {
 	if ret.spanFilter == nil {
 		ret.spanFilter = func(span *model.Span) bool { return true }
 	}
+	if ret.spanValidator == nil {
+		ret.spanValidator = func(span *model.Span) bool { return true }
+	}
 	if ret.numWorkers == 0 {
 		ret.numWorkers = flags.DefaultNumWorkers
 	}
+	if ret.serviceProducers == nil {
+		ret.serviceProducers = NewServiceProducers(maxServiceNames, 0)
+	}
 	return ret
 }
@@ -18,12 +18,18 @@ import (
 	"errors"
 	"io"
 
+	"go.opentelemetry.io/collector/pdata/plog"
+
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
 )
 
 // ErrBusy signalizes that processor cannot process incoming data
 var ErrBusy = errors.New("server busy")
 
+// ErrBatchTooLarge signalizes that the batch has more spans than the processor accepts
+var ErrBatchTooLarge = errors.New("too many spans in the batch")
+
 // SpansOptions additional options passed to processor along with the spans.
 type SpansOptions struct {
 	SpanFormat       SpanFormat
@@ -38,6 +44,12 @@ type SpanProcessor interface {
 	io.Closer
 }
 
+// LogProcessor handles OTLP log records
+type LogProcessor interface {
+	// ProcessLogs processes the log records of the tenant
+	ProcessLogs(logs plog.Logs, tenant string) error
+}
+
 // InboundTransport identifies the transport used to receive spans.
 type InboundTransport string
 
@@ -65,3 +77,20 @@ const (
 	// UnknownSpanFormat is the fallback/catch-all category.
 	UnknownSpanFormat SpanFormat = "unknown"
 )
+
+// RejectedRequests counts the requests rejected for exceeding the limits of the collector.
+type RejectedRequests struct {
+	// TooManySpans counts the batches rejected for having more spans than the maximum.
+	TooManySpans metrics.Counter `metric:"requests.rejected" tags:"reason=too-many-spans"`
+	// TooLarge counts the requests rejected for being larger than the maximum size.
+	TooLarge metrics.Counter `metric:"requests.rejected" tags:"reason=too-large"`
+	// RateLimited counts the requests rejected for exceeding the rate limit of their client.
+	RateLimited metrics.Counter `metric:"requests.rejected" tags:"reason=rate-limited"`
+}
+
+// NewRejectedRequests creates the metrics of the requests rejected on the transport.
+func NewRejectedRequests(factory metrics.Factory, transport InboundTransport) *RejectedRequests {
+	m := &RejectedRequests{}
+	metrics.MustInit(m, factory, map[string]string{"transport": string(transport)})
+	return m
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sanitizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// TruncatedTagKey is the key of the tag added to the spans truncated by the span limits sanitizer.
+// Its value lists the limits which were exceeded, e.g. "tags,log-fields".
+const TruncatedTagKey = "jaeger.truncated"
+
+const (
+	truncatedTags           = "tags"
+	truncatedTagValues      = "tag-values"
+	truncatedLogs           = "logs"
+	truncatedLogFields      = "log-fields"
+	truncatedLogFieldValues = "log-field-values"
+)
+
+// SpanLimits defines the limits enforced on the contents of a span, each limit is disabled if 0.
+type SpanLimits struct {
+	// MaxTags is the maximum number of tags of a span, the extra tags are dropped.
+	MaxTags int `json:"max_tags"`
+	// MaxTagValueLength is the maximum length in bytes of the string and binary values of the tags.
+	MaxTagValueLength int `json:"max_tag_value_length"`
+	// MaxLogs is the maximum number of logs of a span, the extra logs are dropped.
+	MaxLogs int `json:"max_logs"`
+	// MaxLogFields is the maximum number of fields of a log, the extra fields are dropped.
+	MaxLogFields int `json:"max_log_fields"`
+	// MaxLogFieldValueLength is the maximum length in bytes of the string and binary values of the log fields.
+	MaxLogFieldValueLength int `json:"max_log_field_value_length"`
+}
+
+// Enabled returns whether any of the limits is enabled.
+func (l SpanLimits) Enabled() bool {
+	return l != SpanLimits{}
+}
+
+// ParseServiceSpanLimits parses the per-service span limits from a JSON object keyed by service name,
+// e.g. {"frontend": {"max_tags": 128}}. The limits not set for a service are inherited from the defaults.
+func ParseServiceSpanLimits(data []byte, defaults SpanLimits) (map[string]SpanLimits, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("cannot parse the per-service span limits: %w", err)
+	}
+	services := make(map[string]SpanLimits, len(raw))
+	for service, rawLimits := range raw {
+		limits := defaults
+		if err := json.Unmarshal(rawLimits, &limits); err != nil {
+			return nil, fmt.Errorf("cannot parse the span limits of service %q: %w", service, err)
+		}
+		services[service] = limits
+	}
+	return services, nil
+}
+
+// spanLimitsSanitizer truncates the spans exceeding the span limits instead of dropping them
+type spanLimitsSanitizer struct {
+	defaults SpanLimits
+	services map[string]SpanLimits
+}
+
+// NewSpanLimitsSanitizer creates a sanitizer enforcing the default limits on all spans,
+// except for the spans of the services with their own limits.
+func NewSpanLimitsSanitizer(defaults SpanLimits, services map[string]SpanLimits) SanitizeSpan {
+	sanitizer := spanLimitsSanitizer{defaults: defaults, services: services}
+	return sanitizer.Sanitize
+}
+
+// Sanitize truncates the tags and logs of the span exceeding the limits, and marks the span as truncated.
+func (s *spanLimitsSanitizer) Sanitize(span *model.Span) *model.Span {
+	limits := s.defaults
+	if span.Process != nil {
+		if serviceLimits, ok := s.services[span.Process.ServiceName]; ok {
+			limits = serviceLimits
+		}
+	}
+	if !limits.Enabled() {
+		return span
+	}
+	var truncated []string
+	if limits.MaxTags > 0 && len(span.Tags) > limits.MaxTags {
+		span.Tags = span.Tags[:limits.MaxTags]
+		truncated = append(truncated, truncatedTags)
+	}
+	if truncateValues(span.Tags, limits.MaxTagValueLength) {
+		truncated = append(truncated, truncatedTagValues)
+	}
+	if limits.MaxLogs > 0 && len(span.Logs) > limits.MaxLogs {
+		span.Logs = span.Logs[:limits.MaxLogs]
+		truncated = append(truncated, truncatedLogs)
+	}
+	var fieldsTruncated, valuesTruncated bool
+	for i := range span.Logs {
+		log := &span.Logs[i]
+		if limits.MaxLogFields > 0 && len(log.Fields) > limits.MaxLogFields {
+			log.Fields = log.Fields[:limits.MaxLogFields]
+			fieldsTruncated = true
+		}
+		if truncateValues(log.Fields, limits.MaxLogFieldValueLength) {
+			valuesTruncated = true
+		}
+	}
+	if fieldsTruncated {
+		truncated = append(truncated, truncatedLogFields)
+	}
+	if valuesTruncated {
+		truncated = append(truncated, truncatedLogFieldValues)
+	}
+	if len(truncated) > 0 {
+		span.Tags = append(span.Tags, model.String(TruncatedTagKey, strings.Join(truncated, ",")))
+	}
+	return span
+}
+
+// truncateValues truncates the string and binary values longer than maxLength, and returns whether any was.
+func truncateValues(keyValues model.KeyValues, maxLength int) bool {
+	if maxLength <= 0 {
+		return false
+	}
+	var truncated bool
+	for i, kv := range keyValues {
+		switch {
+		case kv.VType == model.StringType && len(kv.VStr) > maxLength:
+			keyValues[i].VStr = truncateString(kv.VStr, maxLength)
+			truncated = true
+		case kv.VType == model.BinaryType && len(kv.VBinary) > maxLength:
+			keyValues[i].VBinary = kv.VBinary[:maxLength]
+			truncated = true
+		}
+	}
+	return truncated
+}
+
+// truncateString truncates s to at most maxLength bytes without splitting a UTF-8 character.
+func truncateString(s string, maxLength int) string {
+	end := maxLength
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end]
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package sanitizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func newLimitsTestSpan(service string) *model.Span {
+	return &model.Span{
+		Process: &model.Process{ServiceName: service},
+		Tags: model.KeyValues{
+			model.String("short", "abc"),
+			model.String("long", "héllo world"),
+			model.Binary("binary", []byte("0123456789")),
+			model.Int64("int", 1234567890),
+		},
+		Logs: []model.Log{
+			{Fields: model.KeyValues{model.String("event", "error"), model.String("message", "a long message")}},
+			{Fields: model.KeyValues{model.String("event", "retry")}},
+		},
+	}
+}
+
+func TestSpanLimitsSanitizer(t *testing.T) {
+	testCases := []struct {
+		name      string
+		limits    SpanLimits
+		truncated string
+		check     func(t *testing.T, span *model.Span)
+	}{
+		{
+			name:   "no limits",
+			limits: SpanLimits{},
+			check: func(t *testing.T, span *model.Span) {
+				assert.Len(t, span.Tags, 4)
+				assert.Len(t, span.Logs, 2)
+			},
+		},
+		{
+			name:   "within the limits",
+			limits: SpanLimits{MaxTags: 4, MaxTagValueLength: 20, MaxLogs: 2, MaxLogFields: 2, MaxLogFieldValueLength: 20},
+			check: func(t *testing.T, span *model.Span) {
+				assert.Len(t, span.Tags, 4)
+			},
+		},
+		{
+			name:      "tags",
+			limits:    SpanLimits{MaxTags: 2},
+			truncated: "tags",
+			check: func(t *testing.T, span *model.Span) {
+				assert.Equal(t, "short", span.Tags[0].Key)
+				assert.Equal(t, "long", span.Tags[1].Key)
+			},
+		},
+		{
+			name:      "tag values",
+			limits:    SpanLimits{MaxTagValueLength: 2},
+			truncated: "tag-values",
+			check: func(t *testing.T, span *model.Span) {
+				assert.Equal(t, "ab", span.Tags[0].VStr)
+				// the multi-byte character is not split
+				assert.Equal(t, "h", span.Tags[1].VStr)
+				assert.Equal(t, []byte("01"), span.Tags[2].VBinary)
+				assert.Equal(t, int64(1234567890), span.Tags[3].VInt64)
+			},
+		},
+		{
+			name:      "logs",
+			limits:    SpanLimits{MaxLogs: 1},
+			truncated: "logs",
+			check: func(t *testing.T, span *model.Span) {
+				require.Len(t, span.Logs, 1)
+				assert.Equal(t, "error", span.Logs[0].Fields[0].VStr)
+			},
+		},
+		{
+			name:      "log fields and values",
+			limits:    SpanLimits{MaxLogFields: 1, MaxLogFieldValueLength: 4},
+			truncated: "log-fields,log-field-values",
+			check: func(t *testing.T, span *model.Span) {
+				require.Len(t, span.Logs, 2)
+				assert.Equal(t, []model.KeyValue{model.String("event", "erro")}, span.Logs[0].Fields)
+				assert.Equal(t, []model.KeyValue{model.String("event", "retr")}, span.Logs[1].Fields)
+			},
+		},
+		{
+			name:      "all limits",
+			limits:    SpanLimits{MaxTags: 3, MaxTagValueLength: 5, MaxLogs: 1, MaxLogFields: 1, MaxLogFieldValueLength: 1},
+			truncated: "tags,tag-values,logs,log-fields,log-field-values",
+			check: func(t *testing.T, span *model.Span) {
+				assert.Len(t, span.Logs, 1)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			span := NewSpanLimitsSanitizer(tc.limits, nil)(newLimitsTestSpan("frontend"))
+			marker, ok := model.KeyValues(span.Tags).FindByKey(TruncatedTagKey)
+			if tc.truncated == "" {
+				assert.False(t, ok)
+			} else {
+				require.True(t, ok)
+				assert.Equal(t, tc.truncated, marker.VStr)
+				span.Tags = span.Tags[:len(span.Tags)-1]
+			}
+			tc.check(t, span)
+		})
+	}
+}
+
+func TestSpanLimitsSanitizerPerService(t *testing.T) {
+	services, err := ParseServiceSpanLimits(
+		[]byte(`{"frontend": {"max_tags": 1}, "backend": {"max_tags": 0, "max_logs": 1}}`),
+		SpanLimits{MaxTags: 2, MaxLogs: 2},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]SpanLimits{
+		"frontend": {MaxTags: 1, MaxLogs: 2},
+		"backend":  {MaxLogs: 1},
+	}, services)
+
+	s := NewSpanLimitsSanitizer(SpanLimits{MaxTags: 2, MaxLogs: 2}, services)
+	assert.Len(t, s(newLimitsTestSpan("frontend")).Tags, 2)
+	backend := s(newLimitsTestSpan("backend"))
+	assert.Len(t, backend.Tags, 5)
+	assert.Len(t, backend.Logs, 1)
+	assert.Len(t, s(newLimitsTestSpan("other")).Tags, 3)
+	assert.Len(t, s(&model.Span{Tags: newLimitsTestSpan("").Tags}).Tags, 3)
+}
+
+func TestParseServiceSpanLimitsErrors(t *testing.T) {
+	_, err := ParseServiceSpanLimits([]byte(`[]`), SpanLimits{})
+	require.ErrorContains(t, err, "cannot parse the per-service span limits")
+	_, err = ParseServiceSpanLimits([]byte(`{"frontend": {"max_tags": "many"}}`), SpanLimits{})
+	require.ErrorContains(t, err, `cannot parse the span limits of service "frontend"`)
+}
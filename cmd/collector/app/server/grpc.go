@@ -17,6 +17,7 @@ package server
 import (
 	"fmt"
 	"net"
+	"os"
 	"time"
 
 	"go.uber.org/zap"
@@ -28,9 +29,12 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
 
@@ -38,13 +42,17 @@ import (
 type GRPCServerParams struct {
 	TLSConfig               tlscfg.Options
 	HostPort                string
+	AddressFamily           netutils.AddressFamily
 	Handler                 *handler.GRPCHandler
 	SamplingStore           strategystore.StrategyStore
 	Logger                  *zap.Logger
+	MetricsFactory          metrics.Factory
 	OnError                 func(error)
 	MaxReceiveMessageLength int
 	MaxConnectionAge        time.Duration
 	MaxConnectionAgeGrace   time.Duration
+	// SocketPermissions are the permissions of the socket file when HostPort is a unix:///path endpoint
+	SocketPermissions os.FileMode
 
 	// Set by the server to indicate the actual host:port of the server.
 	HostPortActual string
@@ -58,6 +66,10 @@ func StartGRPCServer(params *GRPCServerParams) (*grpc.Server, error) {
 	if params.MaxReceiveMessageLength > 0 {
 		grpcOpts = append(grpcOpts, grpc.MaxRecvMsgSize(params.MaxReceiveMessageLength))
 	}
+	if params.MetricsFactory != nil {
+		rejected := processor.NewRejectedRequests(params.MetricsFactory, processor.GRPCTransport)
+		grpcOpts = append(grpcOpts, grpc.StatsHandler(&oversizedMessagesHandler{tooLarge: rejected.TooLarge}))
+	}
 	grpcOpts = append(grpcOpts, grpc.KeepaliveParams(keepalive.ServerParameters{
 		MaxConnectionAge:      params.MaxConnectionAge,
 		MaxConnectionAgeGrace: params.MaxConnectionAgeGrace,
@@ -77,10 +89,14 @@ func StartGRPCServer(params *GRPCServerParams) (*grpc.Server, error) {
 	server = grpc.NewServer(grpcOpts...)
 	reflection.Register(server)
 
-	listener, err := net.Listen("tcp", params.HostPort)
+	listener, err := netutils.Listen(params.AddressFamily, params.HostPort)
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen on gRPC port: %w", err)
 	}
+	if err := netutils.ChmodSocket(listener, params.SocketPermissions); err != nil {
+		listener.Close()
+		return nil, err
+	}
 	params.HostPortActual = listener.Addr().String()
 
 	if err := serveGRPC(server, listener, params); err != nil {
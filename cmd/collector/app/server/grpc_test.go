@@ -16,6 +16,8 @@ package server
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 
@@ -31,6 +33,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/internal/grpctest"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
@@ -96,6 +99,51 @@ func TestSpanCollector(t *testing.T) {
 	require.NotNil(t, response)
 }
 
+func TestSpanCollectorIPv4(t *testing.T) {
+	logger := zap.NewNop()
+	params := &GRPCServerParams{
+		HostPort:      "127.0.0.1:0",
+		AddressFamily: netutils.IPv4,
+		Handler:       handler.NewGRPCHandler(logger, &mockSpanProcessor{}, &tenancy.Manager{}),
+		SamplingStore: &mockSamplingStore{},
+		Logger:        logger,
+	}
+
+	server, err := StartGRPCServer(params)
+	require.NoError(t, err)
+	defer server.Stop()
+	assert.Contains(t, params.HostPortActual, "127.0.0.1:")
+
+	params.HostPort = "[::1]:0"
+	_, err = StartGRPCServer(params)
+	require.ErrorContains(t, err, "failed to listen on gRPC port")
+}
+
+func TestSpanCollectorUnixSocket(t *testing.T) {
+	logger := zap.NewNop()
+	path := filepath.Join(t.TempDir(), "collector.sock")
+	params := &GRPCServerParams{
+		HostPort:          "unix://" + path,
+		SocketPermissions: 0o660,
+		Handler:           handler.NewGRPCHandler(logger, &mockSpanProcessor{}, &tenancy.Manager{}),
+		SamplingStore:     &mockSamplingStore{},
+		Logger:            logger,
+	}
+
+	server, err := StartGRPCServer(params)
+	require.NoError(t, err)
+	defer server.Stop()
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o660), info.Mode().Perm())
+
+	conn, err := grpc.NewClient("unix://"+path, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = api_v2.NewCollectorServiceClient(conn).PostSpans(context.Background(), &api_v2.PostSpansRequest{})
+	require.NoError(t, err)
+}
+
 func TestCollectorStartWithTLS(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	params := &GRPCServerParams{
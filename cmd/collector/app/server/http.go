@@ -20,16 +20,20 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/rs/cors"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	clientcfgHandler "github.com/jaegertracing/jaeger/pkg/clientcfg/clientcfghttp"
+	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/httpmetrics"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
 )
 
@@ -37,6 +41,7 @@ import (
 type HTTPServerParams struct {
 	TLSConfig      tlscfg.Options
 	HostPort       string
+	AddressFamily  netutils.AddressFamily
 	Handler        handler.JaegerBatchesHandler
 	SamplingStore  strategystore.StrategyStore
 	MetricsFactory metrics.Factory
@@ -49,6 +54,19 @@ type HTTPServerParams struct {
 	ReadHeaderTimeout time.Duration
 	// IdleTimeout sets the respective parameter of http.Server
 	IdleTimeout time.Duration
+	// WriteTimeout sets the respective parameter of http.Server
+	WriteTimeout time.Duration
+	// MaxHeaderBytes sets the respective parameter of http.Server
+	MaxHeaderBytes int
+	// MaxRequestSize is the maximum size in bytes of the body of the requests, unlimited if 0
+	MaxRequestSize int
+	// RateLimit is the maximum number of requests per second of each client IP address, unlimited if 0
+	RateLimit float64
+	// RateLimitBurst is the maximum number of requests at once of each client IP address,
+	// RateLimit rounded up if 0
+	RateLimitBurst int
+	// CORS sets the origins and headers allowed in cross-origin requests
+	CORS corscfg.Options
 }
 
 // StartHTTPServer based on the given parameters
@@ -61,9 +79,12 @@ func StartHTTPServer(params *HTTPServerParams) (*http.Server, error) {
 		ReadTimeout:       params.ReadTimeout,
 		ReadHeaderTimeout: params.ReadHeaderTimeout,
 		IdleTimeout:       params.IdleTimeout,
+		WriteTimeout:      params.WriteTimeout,
+		MaxHeaderBytes:    params.MaxHeaderBytes,
 		ErrorLog:          errorLog,
 	}
 	if params.TLSConfig.Enabled {
+		params.TLSConfig.MetricsFactory = params.MetricsFactory
 		tlsCfg, err := params.TLSConfig.Config(params.Logger) // This checks if the certificates are correctly provided
 		if err != nil {
 			return nil, err
@@ -71,7 +92,7 @@ func StartHTTPServer(params *HTTPServerParams) (*http.Server, error) {
 		server.TLSConfig = tlsCfg
 	}
 
-	listener, err := net.Listen("tcp", params.HostPort)
+	listener, err := netutils.Listen(params.AddressFamily, params.HostPort)
 	if err != nil {
 		return nil, err
 	}
@@ -97,8 +118,17 @@ func serveHTTP(server *http.Server, listener net.Listener, params *HTTPServerPar
 	})
 	cfgHandler.RegisterRoutes(r)
 
+	var h http.Handler = r
+	rejected := processor.NewRejectedRequests(params.MetricsFactory, processor.HTTPTransport)
+	if params.MaxRequestSize > 0 {
+		h = limitRequestSize(h, int64(params.MaxRequestSize), rejected.TooLarge)
+	}
+	if params.RateLimit > 0 {
+		h = limitRate(h, newIPRateLimiter(params.RateLimit, params.RateLimitBurst), rejected.RateLimited)
+	}
+	h = allowCORS(h, params.CORS)
 	recoveryHandler := recoveryhandler.NewRecoveryHandler(params.Logger, true)
-	server.Handler = httpmetrics.Wrap(recoveryHandler(r), params.MetricsFactory, params.Logger)
+	server.Handler = httpmetrics.Wrap(recoveryHandler(h), params.MetricsFactory, params.Logger)
 	go func() {
 		var err error
 		if params.TLSConfig.Enabled {
@@ -114,3 +144,29 @@ func serveHTTP(server *http.Server, listener net.Listener, params *HTTPServerPar
 		params.HealthCheck.Set(healthcheck.Unavailable)
 	}()
 }
+
+// allowCORS returns a handler allowing the cross-origin requests of the allowed origins,
+// like the CORS settings of the OTLP/HTTP and Zipkin receivers. It returns h unchanged if
+// no origin is allowed.
+func allowCORS(h http.Handler, opts corscfg.Options) http.Handler {
+	origins := nonEmpty(opts.AllowedOrigins)
+	if len(origins) == 0 {
+		return h
+	}
+	return cors.New(cors.Options{
+		AllowedOrigins:   origins,
+		AllowedHeaders:   nonEmpty(opts.AllowedHeaders),
+		AllowCredentials: true,
+	}).Handler(h)
+}
+
+// nonEmpty drops the empty values of the comma-separated flags.
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
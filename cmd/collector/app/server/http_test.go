@@ -29,6 +29,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/pkg/config/corscfg"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/ports"
@@ -269,6 +270,8 @@ func TestStartHTTPServerParams(t *testing.T) {
 		IdleTimeout:       5 * time.Minute,
 		ReadTimeout:       6 * time.Minute,
 		ReadHeaderTimeout: 7 * time.Second,
+		WriteTimeout:      8 * time.Second,
+		MaxHeaderBytes:    8192,
 	}
 
 	server, err := StartHTTPServer(params)
@@ -277,4 +280,63 @@ func TestStartHTTPServerParams(t *testing.T) {
 	assert.Equal(t, 5*time.Minute, server.IdleTimeout)
 	assert.Equal(t, 6*time.Minute, server.ReadTimeout)
 	assert.Equal(t, 7*time.Second, server.ReadHeaderTimeout)
+	assert.Equal(t, 8*time.Second, server.WriteTimeout)
+	assert.Equal(t, 8192, server.MaxHeaderBytes)
+}
+
+func TestAllowCORS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	preflight := func(h http.Handler, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/api/traces", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	// the CORS flags are split into a single empty value when they are not set
+	h := allowCORS(next, corscfg.Options{AllowedOrigins: []string{""}, AllowedHeaders: []string{""}})
+	w := preflight(h, "https://ui.example.com")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	h = allowCORS(next, corscfg.Options{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedHeaders: []string{"Content-Type", ""},
+	})
+	w = preflight(h, "https://ui.example.com")
+	assert.Equal(t, "https://ui.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Empty(t, preflight(h, "https://evil.com").Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestSpanCollectorHTTPRateLimit(t *testing.T) {
+	mFact := metricstest.NewFactory(time.Hour)
+	defer mFact.Backend.Stop()
+	logger := zap.NewNop()
+	params := &HTTPServerParams{
+		Handler:        handler.NewJaegerSpanHandler(logger, &mockSpanProcessor{}),
+		SamplingStore:  &mockSamplingStore{},
+		MetricsFactory: mFact,
+		HealthCheck:    healthcheck.New(),
+		Logger:         logger,
+		RateLimit:      1,
+	}
+
+	server := httptest.NewServer(nil)
+	defer server.Close()
+	serveHTTP(server.Config, server.Listener, params)
+
+	post := func() int {
+		response, err := http.Post(server.URL+"/api/traces", "", nil)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		return response.StatusCode
+	}
+	assert.NotEqual(t, http.StatusTooManyRequests, post())
+	assert.Equal(t, http.StatusTooManyRequests, post())
 }
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// limitRequestSize returns a handler rejecting the requests with a body larger than maxSize
+// with the 413 status, and counting them. The requests of an unknown size are rejected
+// once their body exceeds maxSize while being read.
+func limitRequestSize(h http.Handler, maxSize int64, tooLarge metrics.Counter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxSize {
+			tooLarge.Inc(1)
+			http.Error(w, fmt.Sprintf("request body of %d bytes is larger than the maximum of %d bytes", r.ContentLength, maxSize),
+				http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = &countingMaxBytesReader{
+			ReadCloser: http.MaxBytesReader(w, r.Body, maxSize),
+			tooLarge:   tooLarge,
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// countingMaxBytesReader counts the bodies exceeding the size limit of http.MaxBytesReader.
+type countingMaxBytesReader struct {
+	io.ReadCloser
+	tooLarge metrics.Counter
+	counted  atomic.Bool
+}
+
+func (r *countingMaxBytesReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) && r.counted.CompareAndSwap(false, true) {
+		r.tooLarge.Inc(1)
+	}
+	return n, err
+}
+
+// oversizedMessagesHandler is a gRPC stats handler counting the calls rejected by
+// the server for receiving a message larger than the maximum receive message size.
+type oversizedMessagesHandler struct {
+	tooLarge metrics.Counter
+}
+
+func (*oversizedMessagesHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *oversizedMessagesHandler) HandleRPC(_ context.Context, s stats.RPCStats) {
+	end, ok := s.(*stats.End)
+	if !ok || end.Error == nil {
+		return
+	}
+	st := status.Convert(end.Error)
+	// the gRPC server does not expose the reason otherwise
+	if st.Code() == codes.ResourceExhausted &&
+		strings.HasPrefix(st.Message(), "grpc: received message") &&
+		strings.Contains(st.Message(), "larger than max") {
+		h.tooLarge.Inc(1)
+	}
+}
+
+func (*oversizedMessagesHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (*oversizedMessagesHandler) HandleConn(context.Context, stats.ConnStats) {}
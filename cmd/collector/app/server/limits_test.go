@@ -0,0 +1,78 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+)
+
+func TestLimitRequestSize(t *testing.T) {
+	mFact := metricstest.NewFactory(time.Hour)
+	defer mFact.Backend.Stop()
+	tooLarge := processor.NewRejectedRequests(mFact, processor.HTTPTransport).TooLarge
+	h := limitRequestSize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}), 10, tooLarge)
+
+	testCases := []struct {
+		name          string
+		body          io.Reader
+		contentLength int64
+		expectedCode  int
+		expectedCount int64
+	}{
+		{name: "small body", body: strings.NewReader("small"), contentLength: 5, expectedCode: http.StatusAccepted},
+		{name: "large body", body: strings.NewReader("larger than the limit"), contentLength: 21, expectedCode: http.StatusRequestEntityTooLarge, expectedCount: 1},
+		{name: "large body of unknown size", body: strings.NewReader("larger than the limit"), contentLength: -1, expectedCode: http.StatusRequestEntityTooLarge, expectedCount: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/traces", tc.body)
+			req.ContentLength = tc.contentLength
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+			assert.Equal(t, tc.expectedCode, rw.Code)
+			mFact.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "requests.rejected", Tags: map[string]string{"reason": "too-large", "transport": "http"}, Value: int(tc.expectedCount)})
+		})
+	}
+}
+
+func TestOversizedMessagesHandler(t *testing.T) {
+	mFact := metricstest.NewFactory(time.Hour)
+	defer mFact.Backend.Stop()
+	h := &oversizedMessagesHandler{tooLarge: processor.NewRejectedRequests(mFact, processor.GRPCTransport).TooLarge}
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{})
+	ctx = h.TagConn(ctx, &stats.ConnTagInfo{})
+	h.HandleConn(ctx, &stats.ConnBegin{})
+
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.End{})
+	h.HandleRPC(ctx, &stats.End{Error: errors.New("unexpected")})
+	h.HandleRPC(ctx, &stats.End{Error: status.Error(codes.ResourceExhausted, "quota exceeded")})
+	mFact.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "requests.rejected", Tags: map[string]string{"reason": "too-large", "transport": "grpc"}, Value: 0})
+
+	h.HandleRPC(ctx, &stats.End{
+		Error: status.Error(codes.ResourceExhausted, "grpc: received message larger than max (2048 vs. 1024)"),
+	})
+	mFact.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "requests.rejected", Tags: map[string]string{"reason": "too-large", "transport": "grpc"}, Value: 1})
+}
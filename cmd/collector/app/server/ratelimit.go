@@ -0,0 +1,105 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// maxIdleRateLimitedClients is the number of clients above which the state of the
+// clients that have not sent requests for a while is discarded.
+const maxIdleRateLimitedClients = 10000
+
+// tokenBucket holds the tokens of one client of an ipRateLimiter.
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// ipRateLimiter limits the rate of the requests of each client IP address separately,
+// with a token bucket per address.
+type ipRateLimiter struct {
+	sync.Mutex
+	rate    float64
+	burst   float64
+	timeNow func() time.Time
+	buckets map[string]*tokenBucket
+}
+
+// newIPRateLimiter creates an ipRateLimiter allowing each client requestsPerSecond requests
+// per second on average, and up to burst requests at once. If burst is not positive, it defaults
+// to requestsPerSecond rounded up.
+func newIPRateLimiter(requestsPerSecond float64, burst int) *ipRateLimiter {
+	if burst <= 0 {
+		burst = int(math.Ceil(requestsPerSecond))
+	}
+	return &ipRateLimiter{
+		rate:    requestsPerSecond,
+		burst:   float64(burst),
+		timeNow: time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow consumes a token of the client and returns whether it had one.
+func (l *ipRateLimiter) allow(client string) bool {
+	l.Lock()
+	defer l.Unlock()
+	now := l.timeNow()
+	bucket, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) >= maxIdleRateLimitedClients {
+			l.evictIdleClients(now)
+		}
+		bucket = &tokenBucket{tokens: l.burst, lastRefill: now}
+		l.buckets[client] = bucket
+	}
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.lastRefill).Seconds()*l.rate)
+	bucket.lastRefill = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// evictIdleClients discards the buckets that are full again, which behave like new buckets.
+func (l *ipRateLimiter) evictIdleClients(now time.Time) {
+	for client, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.lastRefill).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// limitRate returns a handler rejecting the requests of the client IP addresses exceeding
+// the rate of the limiter with the 429 status, and counting them.
+func limitRate(h http.Handler, limiter *ipRateLimiter, rateLimited metrics.Counter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.allow(clientIP(r)) {
+			rateLimited.Inc(1)
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, fmt.Sprintf("rate limit of %g requests per second exceeded", limiter.rate), http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP address of the client of a request. The forwarding headers
+// are not trusted, since they can be set by the clients themselves.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+)
+
+func TestIPRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newIPRateLimiter(2, 0)
+	limiter.timeNow = func() time.Time { return now }
+
+	assert.True(t, limiter.allow("10.0.0.1"))
+	assert.True(t, limiter.allow("10.0.0.1"))
+	assert.False(t, limiter.allow("10.0.0.1"))
+	// the other clients have their own buckets
+	assert.True(t, limiter.allow("10.0.0.2"))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, limiter.allow("10.0.0.1"))
+	assert.False(t, limiter.allow("10.0.0.1"))
+}
+
+func TestIPRateLimiterEvictIdleClients(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newIPRateLimiter(1, 1)
+	limiter.timeNow = func() time.Time { return now }
+	for i := 0; i < maxIdleRateLimitedClients; i++ {
+		limiter.allow(string(rune(i)))
+	}
+	assert.Len(t, limiter.buckets, maxIdleRateLimitedClients)
+
+	now = now.Add(time.Second)
+	limiter.allow("10.0.0.1")
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestLimitRate(t *testing.T) {
+	mFact := metricstest.NewFactory(time.Hour)
+	defer mFact.Backend.Stop()
+	rateLimited := processor.NewRejectedRequests(mFact, processor.HTTPTransport).RateLimited
+	h := limitRate(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}), newIPRateLimiter(1, 1), rateLimited)
+
+	send := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/traces", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+	assert.Equal(t, http.StatusAccepted, send("10.0.0.1:1234").Code)
+	// another connection of the same client
+	w := send("10.0.0.1:5678")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "rate limit of 1 requests per second exceeded")
+	assert.Equal(t, http.StatusAccepted, send("10.0.0.2:1234").Code)
+
+	mFact.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name:  "requests.rejected",
+		Tags:  map[string]string{"reason": "rate-limited", "transport": "http"},
+		Value: 1,
+	})
+}
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/traces", nil)
+	req.RemoteAddr = "[::1]:1234"
+	assert.Equal(t, "::1", clientIP(req))
+	req.RemoteAddr = "@"
+	assert.Equal(t, "@", clientIP(req))
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/normalizer"
+)
+
+const (
+	// TopProducersPath is the path of the top span producers on the admin server.
+	TopProducersPath = "/top-producers"
+
+	// serviceProducersWindow is the period over which the spans of the services are counted.
+	serviceProducersWindow = time.Minute
+
+	// defaultTopProducers is the number of services listed by the top span producers endpoint by default.
+	defaultTopProducers = 20
+)
+
+// ServiceProducer is the ingestion of a service.
+type ServiceProducer struct {
+	Service string `json:"service"`
+	// Spans is the number of received spans.
+	Spans uint64 `json:"spans"`
+	// Bytes is the size of the processed spans, only counted when the span size metrics are enabled.
+	Bytes uint64 `json:"bytes"`
+	// Dropped is the number of spans dropped because the queue was full.
+	Dropped uint64 `json:"dropped"`
+}
+
+// topProducersResponse is the response of the top span producers endpoint.
+type topProducersResponse struct {
+	Since    time.Time         `json:"since"`
+	Services []ServiceProducer `json:"services"`
+}
+
+// ServiceProducers counts the spans of the services over the current and the previous windows,
+// to list the top span producers and to bound the services reported in the metrics by service
+// to the top-k ones. It serves the top span producers as JSON, e.g. on the admin server.
+// The spans are counted without a shared lock, as they are counted for every span received.
+type ServiceProducers struct {
+	maxServices int
+	topK        int
+	now         func() time.Time
+
+	// lock serializes the updates of the windows and the reads of since
+	lock        sync.Mutex
+	since       time.Time // the start of the previous window
+	windowStart time.Time
+	previous    atomic.Pointer[producersWindow]
+	current     atomic.Pointer[producersWindow]
+	top         atomic.Pointer[map[string]struct{}]
+}
+
+// producerCounts are the counts of a service in a window.
+type producerCounts struct {
+	spans   atomic.Uint64
+	bytes   atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// producersWindow holds the counts of the services over a window. The counts of the services
+// already known are found without locking, the lock only serializes the addition of new services.
+type producersWindow struct {
+	services sync.Map // service name -> *producerCounts
+	lock     sync.Mutex
+	size     int
+}
+
+// counts returns the counts of the service, or of other-services once maxServices services are known.
+func (w *producersWindow) counts(serviceName string, maxServices int) *producerCounts {
+	if counts, ok := w.services.Load(serviceName); ok {
+		return counts.(*producerCounts)
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if counts, ok := w.services.Load(serviceName); ok {
+		return counts.(*producerCounts)
+	}
+	if w.size >= maxServices {
+		serviceName = otherServices
+		if counts, ok := w.services.Load(serviceName); ok {
+			return counts.(*producerCounts)
+		}
+	}
+	counts := &producerCounts{}
+	w.services.Store(serviceName, counts)
+	w.size++
+	return counts
+}
+
+// NewServiceProducers creates ServiceProducers tracking at most maxServices services, the others
+// being counted as other-services. If topK is positive, only the topK services producing the most spans
+// are reported in the metrics by service.
+func NewServiceProducers(maxServices, topK int) *ServiceProducers {
+	if maxServices <= 0 {
+		maxServices = maxServiceNames
+	}
+	p := &ServiceProducers{
+		maxServices: maxServices,
+		topK:        topK,
+		now:         time.Now,
+	}
+	p.previous.Store(&producersWindow{})
+	p.current.Store(&producersWindow{})
+	p.top.Store(&map[string]struct{}{})
+	p.since = p.now()
+	p.windowStart = p.since
+	return p
+}
+
+// record adds the spans, bytes and dropped spans of a service to the current window.
+func (p *ServiceProducers) record(serviceName string, spans, bytes, dropped uint64) {
+	counts := p.current.Load().counts(normalizer.ServiceName(serviceName), p.maxServices)
+	if spans > 0 {
+		counts.spans.Add(spans)
+	}
+	if bytes > 0 {
+		counts.bytes.Add(bytes)
+	}
+	if dropped > 0 {
+		counts.dropped.Add(dropped)
+	}
+}
+
+// update starts a new window once the current one is over, and updates the top-k services.
+func (p *ServiceProducers) update() {
+	now := p.now()
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if now.Sub(p.windowStart) >= serviceProducersWindow {
+		p.since = p.windowStart
+		p.windowStart = now
+		// the spans recorded in the current window while it is replaced are counted in the previous one
+		p.previous.Store(p.current.Swap(&producersWindow{}))
+	}
+	if p.topK <= 0 {
+		return
+	}
+	top := make(map[string]struct{}, p.topK)
+	for _, producer := range p.producers(p.topK) {
+		top[producer.Service] = struct{}{}
+	}
+	p.top.Store(&top)
+}
+
+// isTop returns whether the normalized service name is one of the top-k services at the last update.
+func (p *ServiceProducers) isTop(serviceName string) bool {
+	_, ok := (*p.top.Load())[serviceName]
+	return ok
+}
+
+// Top returns the limit services producing the most spans over the current and the previous windows,
+// or all of them if limit is not positive, and the start of the previous window.
+func (p *ServiceProducers) Top(limit int) ([]ServiceProducer, time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.producers(limit), p.since
+}
+
+func (p *ServiceProducers) producers(limit int) []ServiceProducer {
+	merged := make(map[string]ServiceProducer)
+	for _, window := range []*producersWindow{p.previous.Load(), p.current.Load()} {
+		window.services.Range(func(name, value any) bool {
+			counts := value.(*producerCounts)
+			total := merged[name.(string)]
+			total.Service = name.(string)
+			total.Spans += counts.spans.Load()
+			total.Bytes += counts.bytes.Load()
+			total.Dropped += counts.dropped.Load()
+			merged[total.Service] = total
+			return true
+		})
+	}
+	producers := make([]ServiceProducer, 0, len(merged))
+	for _, producer := range merged {
+		producers = append(producers, producer)
+	}
+	sort.Slice(producers, func(i, j int) bool {
+		if producers[i].Spans != producers[j].Spans {
+			return producers[i].Spans > producers[j].Spans
+		}
+		return producers[i].Service < producers[j].Service
+	})
+	if limit > 0 && len(producers) > limit {
+		producers = producers[:limit]
+	}
+	return producers
+}
+
+// ServeHTTP lists the top span producers, the number of services being set by the limit parameter.
+func (p *ServiceProducers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	limit := defaultTopProducers
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	services, since := p.Top(limit)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(topProducersResponse{Since: since, Services: services})
+}
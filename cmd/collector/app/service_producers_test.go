@@ -0,0 +1,133 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceProducersTop(t *testing.T) {
+	p := NewServiceProducers(3, 0)
+	p.record("frontend", 2, 200, 0)
+	p.record("driver", 5, 500, 1)
+	p.record("Route", 1, 100, 0)
+	p.record("frontend", 3, 300, 0)
+	// the services beyond the maximum are counted together
+	p.record("mysql", 1, 10, 0)
+	p.record("redis", 1, 10, 0)
+
+	top, _ := p.Top(0)
+	assert.Equal(t, []ServiceProducer{
+		{Service: "driver", Spans: 5, Bytes: 500, Dropped: 1},
+		{Service: "frontend", Spans: 5, Bytes: 500},
+		{Service: otherServices, Spans: 2, Bytes: 20},
+		{Service: "route", Spans: 1, Bytes: 100},
+	}, top)
+
+	top, _ = p.Top(1)
+	assert.Equal(t, []ServiceProducer{{Service: "driver", Spans: 5, Bytes: 500, Dropped: 1}}, top)
+}
+
+func TestServiceProducersWindows(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := NewServiceProducers(10, 1)
+	p.now = func() time.Time { return now }
+	p.since, p.windowStart = now, now
+
+	p.record("frontend", 2, 0, 0)
+	p.record("driver", 1, 0, 0)
+	assert.False(t, p.isTop("frontend"))
+	p.update()
+	assert.True(t, p.isTop("frontend"))
+	assert.False(t, p.isTop("driver"))
+
+	// the previous window is kept for another window
+	start := now
+	now = now.Add(serviceProducersWindow)
+	p.update()
+	p.record("driver", 2, 0, 0)
+	p.update()
+	top, since := p.Top(0)
+	assert.Equal(t, start, since)
+	assert.Equal(t, []ServiceProducer{{Service: "driver", Spans: 3}, {Service: "frontend", Spans: 2}}, top)
+	assert.True(t, p.isTop("driver"))
+	assert.False(t, p.isTop("frontend"))
+
+	now = now.Add(serviceProducersWindow)
+	p.update()
+	top, since = p.Top(0)
+	assert.Equal(t, start.Add(serviceProducersWindow), since)
+	assert.Equal(t, []ServiceProducer{{Service: "driver", Spans: 2}}, top)
+}
+
+func TestServiceProducersConcurrentRecords(t *testing.T) {
+	p := NewServiceProducers(2, 1)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				p.record("frontend", 1, 10, 0)
+				p.record(fmt.Sprintf("service-%d", j%3), 1, 0, 0)
+				p.update()
+			}
+		}()
+	}
+	wg.Wait()
+
+	top, _ := p.Top(0)
+	var spans uint64
+	for _, producer := range top {
+		spans += producer.Spans
+	}
+	assert.EqualValues(t, 800, spans)
+	assert.Len(t, top, 3)
+	assert.Equal(t, ServiceProducer{Service: "frontend", Spans: 400, Bytes: 4000}, top[0])
+	assert.True(t, p.isTop("frontend"))
+}
+
+func TestServiceProducersServeHTTP(t *testing.T) {
+	p := NewServiceProducers(100, 0)
+	for i := 0; i < defaultTopProducers+1; i++ {
+		p.record(string(rune('a'+i)), uint64(i+1), 0, 0)
+	}
+
+	tests := []struct {
+		query    string
+		services int
+	}{
+		{query: "", services: defaultTopProducers},
+		{query: "?limit=2", services: 2},
+		{query: "?limit=0", services: defaultTopProducers + 1},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, TopProducersPath+test.query, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			var response topProducersResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.Len(t, response.Services, test.services)
+			assert.Equal(t, ServiceProducer{Service: string(rune('a' + defaultTopProducers)), Spans: defaultTopProducers + 1}, response.Services[0])
+			assert.Equal(t, p.since.Unix(), response.Since.Unix())
+		})
+	}
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, TopProducersPath+"?limit=x", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid limit parameter")
+}
@@ -20,10 +20,13 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/filter"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	zs "github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/zipkin"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/validator"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
@@ -37,6 +40,10 @@ type SpanHandlerBuilder struct {
 	Logger         *zap.Logger
 	MetricsFactory metrics.Factory
 	TenancyMgr     *tenancy.Manager
+	// ServiceProducers, if not nil, counts the spans by service, otherwise they are counted by the span processor.
+	ServiceProducers *ServiceProducers
+	// PreProcessSpans, if not nil, is called with the received spans before they are queued.
+	PreProcessSpans ProcessSpans
 }
 
 // SpanHandlers holds instances to the span handlers built by the SpanHandlerBuilder
@@ -52,20 +59,47 @@ func (b *SpanHandlerBuilder) BuildSpanProcessor(additional ...ProcessSpan) proce
 	svcMetrics := b.metricsFactory()
 	hostMetrics := svcMetrics.Namespace(metrics.NSOptions{Tags: map[string]string{"host": hostname}})
 
-	return NewSpanProcessor(
-		b.SpanWriter,
-		additional,
+	spanFilter := defaultSpanFilter
+	if len(b.CollectorOpts.FilterRules) > 0 {
+		spanFilter = filter.NewSpanFilter(b.CollectorOpts.FilterRules, svcMetrics)
+	}
+	spanValidator := defaultSpanFilter
+	if b.CollectorOpts.SpanValidation.Enabled {
+		spanValidator = validator.NewSpanValidator(b.CollectorOpts.SpanValidation, svcMetrics)
+	}
+
+	serviceProducers := b.ServiceProducers
+	if serviceProducers == nil {
+		serviceProducers = NewServiceProducers(b.CollectorOpts.ServiceMetrics.MaxServices, b.CollectorOpts.ServiceMetrics.TopK)
+	}
+
+	opts := []Option{
 		Options.ServiceMetrics(svcMetrics),
 		Options.HostMetrics(hostMetrics),
 		Options.Logger(b.logger()),
-		Options.SpanFilter(defaultSpanFilter),
+		Options.SpanFilter(spanFilter),
+		Options.SpanValidator(spanValidator),
 		Options.NumWorkers(b.CollectorOpts.NumWorkers),
 		Options.QueueSize(b.CollectorOpts.QueueSize),
 		Options.CollectorTags(b.CollectorOpts.CollectorTags),
 		Options.DynQueueSizeWarmup(uint(b.CollectorOpts.QueueSize)), // same as queue size for now
 		Options.DynQueueSizeMemory(b.CollectorOpts.DynQueueSizeMemory),
 		Options.SpanSizeMetricsEnabled(b.CollectorOpts.SpanSizeMetricsEnabled),
-	)
+		Options.MaxBatchSpans(b.CollectorOpts.MaxBatchSpans),
+		Options.MaxSpanSize(b.CollectorOpts.MaxSpanSize),
+		Options.DrainTimeout(b.CollectorOpts.DrainTimeout),
+		Options.ServiceProducers(serviceProducers),
+		Options.ServiceAllowlist(b.CollectorOpts.ServiceMetrics.Allowlist),
+	}
+	if b.PreProcessSpans != nil {
+		opts = append(opts, Options.PreProcessSpans(b.PreProcessSpans))
+	}
+	if b.CollectorOpts.SpanLimits.Enabled() || len(b.CollectorOpts.ServiceSpanLimits) > 0 {
+		opts = append(opts, Options.Sanitizer(
+			sanitizer.NewSpanLimitsSanitizer(b.CollectorOpts.SpanLimits, b.CollectorOpts.ServiceSpanLimits),
+		))
+	}
+	return NewSpanProcessor(b.SpanWriter, additional, opts...)
 }
 
 // BuildHandlers builds span handlers (Zipkin, Jaeger)
@@ -22,8 +22,12 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/filter"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/validator"
 	cmdFlags "github.com/jaegertracing/jaeger/cmd/internal/flags"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
@@ -64,6 +68,64 @@ func TestNewSpanHandlerBuilder(t *testing.T) {
 	require.NoError(t, spanProcessor.Close())
 }
 
+func TestSpanHandlerBuilderSpanLimits(t *testing.T) {
+	v, command := config.Viperize(cmdFlags.AddFlags, flags.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--collector.span-limits.max-tags=1"}))
+	cOpts, err := new(flags.CollectorOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+
+	builder := &SpanHandlerBuilder{
+		SpanWriter:    memory.NewStore(),
+		CollectorOpts: cOpts,
+		TenancyMgr:    &tenancy.Manager{},
+	}
+	p := builder.BuildSpanProcessor()
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+	span := p.(*spanProcessor).sanitizer(&model.Span{
+		Process: &model.Process{ServiceName: "frontend"},
+		Tags:    []model.KeyValue{model.String("k1", "v1"), model.String("k2", "v2")},
+	})
+	assert.Equal(t, []model.KeyValue{
+		model.String("k1", "v1"),
+		model.String(sanitizer.TruncatedTagKey, "tags"),
+	}, span.Tags)
+}
+
+func TestSpanHandlerBuilderFilterRules(t *testing.T) {
+	builder := &SpanHandlerBuilder{
+		SpanWriter: memory.NewStore(),
+		CollectorOpts: &flags.CollectorOptions{
+			FilterRules: []filter.Rule{{Name: "frontend", Service: "frontend"}},
+		},
+		TenancyMgr: &tenancy.Manager{},
+	}
+	p := builder.BuildSpanProcessor()
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+	filterSpan := p.(*spanProcessor).filterSpan
+	assert.False(t, filterSpan(&model.Span{Process: &model.Process{ServiceName: "frontend"}}))
+	assert.True(t, filterSpan(&model.Span{Process: &model.Process{ServiceName: "backend"}}))
+}
+
+func TestSpanHandlerBuilderValidation(t *testing.T) {
+	builder := &SpanHandlerBuilder{
+		SpanWriter: memory.NewStore(),
+		CollectorOpts: &flags.CollectorOptions{
+			SpanValidation: validator.Options{Enabled: true, Actions: validator.DefaultActions()},
+		},
+		TenancyMgr: &tenancy.Manager{},
+	}
+	p := builder.BuildSpanProcessor()
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+	validateSpan := p.(*spanProcessor).validateSpan
+	assert.False(t, validateSpan(&model.Span{Process: &model.Process{ServiceName: "frontend"}}))
+}
+
 func TestDefaultSpanFilter(t *testing.T) {
 	assert.True(t, defaultSpanFilter(nil))
 }
@@ -17,6 +17,7 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,6 +27,8 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/normalizer"
 	"github.com/jaegertracing/jaeger/pkg/queue"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -43,8 +46,10 @@ type spanProcessor struct {
 	queue              *queue.BoundedQueue
 	queueResizeMu      sync.Mutex
 	metrics            *SpanProcessorMetrics
+	producers          *ServiceProducers
 	preProcessSpans    ProcessSpans
 	filterSpan         FilterSpan             // filter is called before the sanitizer but after preProcessSpans
+	validateSpan       FilterSpan             // validator is called after the filter, it may fix or tag the spans
 	sanitizer          sanitizer.SanitizeSpan // sanitizer is called before processSpan
 	processSpan        ProcessSpan
 	logger             *zap.Logger
@@ -54,6 +59,9 @@ type spanProcessor struct {
 	collectorTags      map[string]string
 	dynQueueSizeWarmup uint
 	dynQueueSizeMemory uint
+	maxBatchSpans      int
+	maxSpanSize        int
+	drainTimeout       time.Duration
 	bytesProcessed     atomic.Uint64
 	spansProcessed     atomic.Uint64
 	stopCh             chan struct{}
@@ -79,6 +87,7 @@ func NewSpanProcessor(
 	})
 
 	sp.background(1*time.Second, sp.updateGauges)
+	sp.background(1*time.Second, sp.producers.update)
 
 	if sp.dynQueueSizeMemory > 0 {
 		sp.background(1*time.Minute, sp.updateQueueSize)
@@ -89,14 +98,18 @@ func NewSpanProcessor(
 
 func newSpanProcessor(spanWriter spanstore.Writer, additional []ProcessSpan, opts ...Option) *spanProcessor {
 	options := Options.apply(opts...)
-	handlerMetrics := NewSpanProcessorMetrics(
+	handlerMetrics := newSpanProcessorMetrics(
 		options.serviceMetrics,
 		options.hostMetrics,
-		options.extraFormatTypes)
+		options.extraFormatTypes,
+		newServiceLabels(options.serviceProducers, options.serviceAllowlist))
 	droppedItemHandler := func(item interface{}) {
+		span := item.(*queueItem).span
 		handlerMetrics.SpansDropped.Inc(1)
+		handlerMetrics.DroppedBySvc.ReportServiceNameForSpan(span)
+		options.serviceProducers.record(spanServiceName(span), 0, 0, 1)
 		if options.onDroppedSpan != nil {
-			options.onDroppedSpan(item.(*queueItem).span)
+			options.onDroppedSpan(span)
 		}
 	}
 	boundedQueue := queue.NewBoundedQueue(options.queueSize, droppedItemHandler)
@@ -109,9 +122,11 @@ func newSpanProcessor(spanWriter spanstore.Writer, additional []ProcessSpan, opt
 	sp := spanProcessor{
 		queue:              boundedQueue,
 		metrics:            handlerMetrics,
+		producers:          options.serviceProducers,
 		logger:             options.logger,
 		preProcessSpans:    options.preProcessSpans,
 		filterSpan:         options.spanFilter,
+		validateSpan:       options.spanValidator,
 		sanitizer:          sanitizer.NewChainedSanitizer(sanitizers...),
 		reportBusy:         options.reportBusy,
 		numWorkers:         options.numWorkers,
@@ -120,6 +135,9 @@ func newSpanProcessor(spanWriter spanstore.Writer, additional []ProcessSpan, opt
 		stopCh:             make(chan struct{}),
 		dynQueueSizeMemory: options.dynQueueSizeMemory,
 		dynQueueSizeWarmup: options.dynQueueSizeWarmup,
+		maxBatchSpans:      options.maxBatchSpans,
+		maxSpanSize:        options.maxSpanSize,
+		drainTimeout:       options.drainTimeout,
 	}
 
 	processSpanFuncs := []ProcessSpan{options.preSave, sp.saveSpan}
@@ -139,10 +157,42 @@ func newSpanProcessor(spanWriter spanstore.Writer, additional []ProcessSpan, opt
 	return &sp
 }
 
+// newServiceLabels reports the allowlisted services in the metrics by service if any,
+// or else the top-k services of the producers if enabled.
+func newServiceLabels(producers *ServiceProducers, allowlist []string) serviceLabels {
+	labels := serviceLabels{maxServices: producers.maxServices}
+	if len(allowlist) > 0 {
+		allowed := make(map[string]struct{}, len(allowlist))
+		for _, serviceName := range allowlist {
+			allowed[normalizer.ServiceName(serviceName)] = struct{}{}
+		}
+		labels.allow = func(serviceName string) bool {
+			_, ok := allowed[serviceName]
+			return ok
+		}
+	} else if producers.topK > 0 {
+		labels.allow = producers.isTop
+	}
+	return labels
+}
+
+// Close stops the span processor. If a drain timeout is configured, the spans in the queue
+// are flushed to the storage first, for at most the drain timeout, and the rest is abandoned.
 func (sp *spanProcessor) Close() error {
 	close(sp.stopCh)
-	sp.queue.Stop()
+	if sp.drainTimeout <= 0 {
+		sp.queue.Stop()
+		return nil
+	}
 
+	sp.logger.Info("Draining the span queue", zap.Int("queue-length", sp.queue.Size()), zap.Duration("timeout", sp.drainTimeout))
+	drained, abandoned := sp.queue.StopWithDrain(sp.drainTimeout)
+	sp.metrics.SpansDrained.Inc(int64(drained))
+	sp.metrics.SpansAbandoned.Inc(int64(abandoned))
+	if abandoned > 0 {
+		sp.logger.Warn("Abandoned the spans remaining in the queue after the drain timeout",
+			zap.Int("drained", drained), zap.Int("abandoned", abandoned))
+	}
 	return nil
 }
 
@@ -166,15 +216,22 @@ func (sp *spanProcessor) saveSpan(span *model.Span, tenant string) {
 			zap.Stringer("trace-id", span.TraceID), zap.Stringer("span-id", span.SpanID))
 		sp.metrics.SavedOkBySvc.ReportServiceNameForSpan(span)
 	}
-	sp.metrics.SaveLatency.Record(time.Since(startTime))
+	metrics.RecordTimerWithExemplar(sp.metrics.SaveLatency, time.Since(startTime), span.TraceID.String())
 }
 
 func (sp *spanProcessor) countSpan(span *model.Span, tenant string) {
-	sp.bytesProcessed.Add(uint64(span.Size()))
+	size := span.Size()
+	sp.bytesProcessed.Add(uint64(size))
 	sp.spansProcessed.Add(1)
+	sp.metrics.BytesBySvc.reportSizeForSpan(span, size)
+	sp.producers.record(spanServiceName(span), 0, uint64(size), 0)
 }
 
 func (sp *spanProcessor) ProcessSpans(mSpans []*model.Span, options processor.SpansOptions) ([]bool, error) {
+	if sp.maxBatchSpans > 0 && len(mSpans) > sp.maxBatchSpans {
+		sp.metrics.getRejectedRequests(options.InboundTransport).TooManySpans.Inc(1)
+		return nil, fmt.Errorf("%w: %d spans, the maximum is %d", processor.ErrBatchTooLarge, len(mSpans), sp.maxBatchSpans)
+	}
 	sp.preProcessSpans(mSpans, options.Tenant)
 	sp.metrics.BatchSize.Update(int64(len(mSpans)))
 	retMe := make([]bool, len(mSpans))
@@ -200,7 +257,7 @@ func (sp *spanProcessor) ProcessSpans(mSpans []*model.Span, options processor.Sp
 
 func (sp *spanProcessor) processItemFromQueue(item *queueItem) {
 	sp.processSpan(sp.sanitizer(item.span), item.tenant)
-	sp.metrics.InQueueLatency.Record(time.Since(item.queuedTime))
+	metrics.RecordTimerWithExemplar(sp.metrics.InQueueLatency, time.Since(item.queuedTime), item.span.TraceID.String())
 }
 
 func (sp *spanProcessor) addCollectorTags(span *model.Span) {
@@ -229,12 +286,30 @@ func (sp *spanProcessor) addCollectorTags(span *model.Span) {
 func (sp *spanProcessor) enqueueSpan(span *model.Span, originalFormat processor.SpanFormat, transport processor.InboundTransport, tenant string) bool {
 	spanCounts := sp.metrics.GetCountsForFormat(originalFormat, transport)
 	spanCounts.ReceivedBySvc.ReportServiceNameForSpan(span)
+	sp.producers.record(spanServiceName(span), 1, 0, 0)
 
 	if !sp.filterSpan(span) {
 		spanCounts.RejectedBySvc.ReportServiceNameForSpan(span)
 		return true // as in "not dropped", because it's actively rejected
 	}
 
+	if !sp.validateSpan(span) {
+		sp.logger.Debug("Rejecting invalid span",
+			zap.Stringer("trace-id", span.TraceID), zap.Stringer("span-id", span.SpanID))
+		spanCounts.RejectedBySvc.ReportServiceNameForSpan(span)
+		return true // as in "not dropped", because it's actively rejected
+	}
+
+	if sp.maxSpanSize > 0 {
+		if size := span.Size(); size > sp.maxSpanSize {
+			sp.logger.Debug("Rejecting span larger than the maximum size",
+				zap.Stringer("trace-id", span.TraceID), zap.Stringer("span-id", span.SpanID), zap.Int("size", size))
+			sp.metrics.SpansTooLarge.Inc(1)
+			spanCounts.RejectedBySvc.ReportServiceNameForSpan(span)
+			return true // as in "not dropped", because it's actively rejected
+		}
+	}
+
 	// add format tag
 	span.Tags = append(span.Tags, model.String("internal.span.format", string(originalFormat)))
 
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -342,6 +343,73 @@ func TestSpanProcessorBusy(t *testing.T) {
 	assert.Nil(t, res)
 }
 
+func TestSpanProcessorLimits(t *testing.T) {
+	mb := metricstest.NewFactory(time.Hour)
+	defer mb.Backend.Stop()
+	serviceMetrics := mb.Namespace(metrics.NSOptions{Name: "service", Tags: nil})
+	hostMetrics := mb.Namespace(metrics.NSOptions{Name: "host", Tags: nil})
+
+	w := &fakeSpanWriter{}
+	p := NewSpanProcessor(w,
+		nil,
+		Options.ServiceMetrics(serviceMetrics),
+		Options.HostMetrics(hostMetrics),
+		Options.QueueSize(10),
+		Options.MaxBatchSpans(2),
+		Options.MaxSpanSize(100),
+	).(*spanProcessor)
+	defer func() { require.NoError(t, p.Close()) }()
+
+	newSpan := func(operationName string) *model.Span {
+		return &model.Span{OperationName: operationName, Process: &model.Process{ServiceName: "x"}}
+	}
+	res, err := p.ProcessSpans([]*model.Span{newSpan("a"), newSpan("b"), newSpan("c")},
+		processor.SpansOptions{SpanFormat: processor.ProtoSpanFormat, InboundTransport: processor.GRPCTransport})
+	require.ErrorIs(t, err, processor.ErrBatchTooLarge)
+	assert.Nil(t, res)
+
+	res, err = p.ProcessSpans([]*model.Span{newSpan("a"), newSpan(strings.Repeat("b", 200))},
+		processor.SpansOptions{SpanFormat: processor.ProtoSpanFormat, InboundTransport: processor.GRPCTransport})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true}, res)
+
+	mb.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "service.requests.rejected|reason=too-many-spans|transport=grpc", Value: 1},
+		metricstest.ExpectedMetric{Name: "host.spans.too-large", Value: 1},
+		metricstest.ExpectedMetric{Name: "service.spans.rejected|debug=false|format=proto|svc=x|transport=grpc", Value: 1},
+		metricstest.ExpectedMetric{Name: "service.spans.received|debug=false|format=proto|svc=x|transport=grpc", Value: 2},
+	)
+}
+
+func TestSpanProcessorValidation(t *testing.T) {
+	mb := metricstest.NewFactory(time.Hour)
+	defer mb.Backend.Stop()
+	serviceMetrics := mb.Namespace(metrics.NSOptions{Name: "service", Tags: nil})
+
+	w := &fakeSpanWriter{}
+	p := NewSpanProcessor(w,
+		nil,
+		Options.ServiceMetrics(serviceMetrics),
+		Options.QueueSize(10),
+		Options.SpanValidator(func(span *model.Span) bool {
+			return span.SpanID != 0
+		}),
+	).(*spanProcessor)
+	defer func() { require.NoError(t, p.Close()) }()
+
+	res, err := p.ProcessSpans([]*model.Span{
+		{SpanID: 1, Process: &model.Process{ServiceName: "x"}},
+		{Process: &model.Process{ServiceName: "x"}},
+	}, processor.SpansOptions{SpanFormat: processor.ProtoSpanFormat, InboundTransport: processor.GRPCTransport})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true}, res)
+
+	mb.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "service.spans.rejected|debug=false|format=proto|svc=x|transport=grpc", Value: 1},
+		metricstest.ExpectedMetric{Name: "service.spans.received|debug=false|format=proto|svc=x|transport=grpc", Value: 2},
+	)
+}
+
 func TestSpanProcessorWithNilProcess(t *testing.T) {
 	mb := metricstest.NewFactory(time.Hour)
 	defer mb.Backend.Stop()
@@ -713,3 +781,128 @@ func TestSpanProcessorWithOnDroppedSpanOption(t *testing.T) {
 	require.EqualError(t, err, processor.ErrBusy.Error())
 	assert.Equal(t, []string{"op3"}, droppedOperations)
 }
+
+func TestSpanProcessorDroppedBySvc(t *testing.T) {
+	mb := metricstest.NewFactory(time.Hour)
+	defer mb.Backend.Stop()
+	producers := NewServiceProducers(10, 0)
+	w := &blockingWriter{}
+	p := NewSpanProcessor(w,
+		nil,
+		Options.ServiceMetrics(mb),
+		Options.NumWorkers(1),
+		Options.QueueSize(1),
+		Options.ServiceProducers(producers),
+	).(*spanProcessor)
+	defer p.Close()
+
+	w.Lock()
+	defer w.Unlock()
+
+	opts := processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat}
+	_, err := p.ProcessSpans([]*model.Span{
+		{OperationName: "op1", Process: &model.Process{ServiceName: "fry"}},
+	}, opts)
+	require.NoError(t, err)
+	assert.Eventually(t,
+		func() bool { return w.inWriteSpan.Load() == 1 },
+		time.Second, time.Microsecond)
+
+	_, err = p.ProcessSpans([]*model.Span{
+		{OperationName: "op2", Process: &model.Process{ServiceName: "fry"}},
+		{OperationName: "op3", Process: &model.Process{ServiceName: "fry"}},
+	}, opts)
+	require.NoError(t, err)
+
+	counters, _ := mb.Backend.Snapshot()
+	assert.EqualValues(t, 1, counters["spans.dropped-by-svc|debug=false|svc=fry"])
+	assert.EqualValues(t, 1, counters["traces.dropped-by-svc|debug=false|sampler_type=unrecognized|svc=fry"])
+	top, _ := producers.Top(0)
+	assert.Equal(t, []ServiceProducer{{Service: "fry", Spans: 3, Dropped: 1}}, top)
+}
+
+func TestSpanProcessorServiceLabels(t *testing.T) {
+	makeSpan := func(serviceName string) *model.Span {
+		return &model.Span{Process: &model.Process{ServiceName: serviceName}}
+	}
+	process := func(p *spanProcessor, serviceNames ...string) {
+		for _, serviceName := range serviceNames {
+			p.processSpan(makeSpan(serviceName), "")
+			p.metrics.GetCountsForFormat(processor.JaegerSpanFormat, processor.GRPCTransport).ReceivedBySvc.ReportServiceNameForSpan(makeSpan(serviceName))
+		}
+	}
+
+	t.Run("top-k", func(t *testing.T) {
+		mb := metricstest.NewFactory(time.Hour)
+		defer mb.Backend.Stop()
+		producers := NewServiceProducers(10, 1)
+		p := newSpanProcessor(&fakeSpanWriter{}, nil,
+			Options.ServiceMetrics(mb),
+			Options.ServiceProducers(producers),
+			Options.SpanSizeMetricsEnabled(true),
+		)
+		defer p.Close()
+
+		producers.record("leela", 2, 0, 0)
+		producers.record("fry", 1, 0, 0)
+		producers.update()
+		process(p, "fry", "leela")
+
+		counters, _ := mb.Backend.Snapshot()
+		assert.EqualValues(t, 1, counters["spans.received|debug=false|format=jaeger|svc=leela|transport=grpc"])
+		assert.EqualValues(t, 1, counters["spans.received|debug=false|format=jaeger|svc=other-services|transport=grpc"])
+		assert.Positive(t, counters["spans.bytes-by-svc|debug=false|svc=leela"])
+		assert.Positive(t, counters["spans.bytes-by-svc|debug=false|svc=other-services"])
+		top, _ := producers.Top(0)
+		assert.Equal(t, "leela", top[0].Service)
+		assert.EqualValues(t, counters["spans.bytes-by-svc|debug=false|svc=leela"], top[0].Bytes)
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		mb := metricstest.NewFactory(time.Hour)
+		defer mb.Backend.Stop()
+		p := newSpanProcessor(&fakeSpanWriter{}, nil,
+			Options.ServiceMetrics(mb),
+			Options.ServiceProducers(NewServiceProducers(10, 1)),
+			Options.ServiceAllowlist([]string{"Fry"}),
+		)
+		defer p.Close()
+
+		process(p, "fry", "leela")
+
+		counters, _ := mb.Backend.Snapshot()
+		assert.EqualValues(t, 1, counters["spans.received|debug=false|format=jaeger|svc=fry|transport=grpc"])
+		assert.EqualValues(t, 1, counters["spans.received|debug=false|format=jaeger|svc=other-services|transport=grpc"])
+		assert.EqualValues(t, 1, counters["spans.saved-by-svc|debug=false|result=ok|svc=fry"])
+		assert.EqualValues(t, 1, counters["spans.saved-by-svc|debug=false|result=ok|svc=other-services"])
+	})
+}
+
+func TestSpanProcessorDrainOnClose(t *testing.T) {
+	mb := metricstest.NewFactory(time.Hour)
+	defer mb.Backend.Stop()
+	w := &fakeSpanWriter{}
+	p := NewSpanProcessor(w,
+		nil,
+		Options.HostMetrics(mb),
+		Options.NumWorkers(1),
+		Options.QueueSize(10),
+		Options.DrainTimeout(5*time.Second),
+	).(*spanProcessor)
+
+	spans := make([]*model.Span, 5)
+	for i := range spans {
+		spans[i] = &model.Span{Process: &model.Process{ServiceName: "x"}}
+	}
+	_, err := p.ProcessSpans(spans, processor.SpansOptions{SpanFormat: processor.ProtoSpanFormat})
+	require.NoError(t, err)
+	require.NoError(t, p.Close())
+
+	w.spansLock.Lock()
+	defer w.spansLock.Unlock()
+	assert.Len(t, w.spans, 5, "all the queued spans are flushed")
+	mb.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "spans.drained|result=abandoned", Value: 0})
+	_, err = p.ProcessSpans(spans[:1], processor.SpansOptions{SpanFormat: processor.ProtoSpanFormat})
+	require.NoError(t, err)
+	assert.Len(t, w.spans, 5, "no spans are accepted after closing")
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package spanlogs attaches the OTLP log records received by the collector to their spans as span logs,
+// so that the application logs are shown inline in the traces without a separate logging UI.
+package spanlogs
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+const (
+	// messageField is the field of the span logs holding the body of the log records.
+	messageField = "message"
+	// levelField is the field of the span logs holding the severity of the log records.
+	levelField = "level"
+)
+
+type spanKey struct {
+	tenant  string
+	traceID model.TraceID
+	spanID  model.SpanID
+}
+
+// correlatorMetrics counts the received log records by outcome.
+type correlatorMetrics struct {
+	// Attached counts the log records attached to their span.
+	Attached metrics.Counter `metric:"span_logs_records" tags:"result=attached"`
+	// Expired counts the log records whose span was not received in time.
+	Expired metrics.Counter `metric:"span_logs_records" tags:"result=expired"`
+	// Dropped counts the log records dropped because too many log records were waiting for their span.
+	Dropped metrics.Counter `metric:"span_logs_records" tags:"result=dropped"`
+	// Uncorrelated counts the log records ignored because they carry no trace or span ID.
+	Uncorrelated metrics.Counter `metric:"span_logs_records" tags:"result=uncorrelated"`
+}
+
+// Correlator keeps the OTLP log records carrying a trace and a span ID until their span is received,
+// and attaches them to it as span logs.
+//
+// The log records wait for their span over two tumbling windows: the log records still waiting at
+// the end of the previous window are dropped. The log records are usually received before their span,
+// which is exported once it ends, the ones received after it cannot be attached.
+type Correlator struct {
+	sync.Mutex
+
+	options Options
+	metrics correlatorMetrics
+
+	current  map[spanKey][]model.Log
+	previous map[spanKey][]model.Log
+	size     int
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewCorrelator creates a Correlator.
+func NewCorrelator(options Options, metricsFactory metrics.Factory) *Correlator {
+	if options.Window <= 0 {
+		options.Window = defaultWindow
+	}
+	c := &Correlator{
+		options:  options,
+		current:  make(map[spanKey][]model.Log),
+		previous: make(map[spanKey][]model.Log),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	metrics.MustInit(&c.metrics, metricsFactory, nil)
+	return c
+}
+
+// ProcessLogs keeps the log records of the tenant carrying a trace and a span ID until their span is received.
+// It implements processor.LogProcessor.
+func (c *Correlator) ProcessLogs(logs plog.Logs, tenant string) error {
+	c.Lock()
+	defer c.Unlock()
+	resourceLogs := logs.ResourceLogs()
+	for i := 0; i < resourceLogs.Len(); i++ {
+		scopeLogs := resourceLogs.At(i).ScopeLogs()
+		for j := 0; j < scopeLogs.Len(); j++ {
+			records := scopeLogs.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				c.addRecord(records.At(k), tenant)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Correlator) addRecord(record plog.LogRecord, tenant string) {
+	if record.TraceID().IsEmpty() || record.SpanID().IsEmpty() {
+		c.metrics.Uncorrelated.Inc(1)
+		return
+	}
+	if c.options.MaxRecords > 0 && c.size >= c.options.MaxRecords {
+		c.metrics.Dropped.Inc(1)
+		return
+	}
+	traceID := record.TraceID()
+	spanID := record.SpanID()
+	key := spanKey{
+		tenant:  tenant,
+		traceID: model.NewTraceID(binary.BigEndian.Uint64(traceID[:8]), binary.BigEndian.Uint64(traceID[8:])),
+		spanID:  model.NewSpanID(binary.BigEndian.Uint64(spanID[:])),
+	}
+	c.current[key] = append(c.current[key], spanLog(record))
+	c.size++
+}
+
+// HandleSpans attaches the waiting log records to the spans of the tenant.
+// Its signature matches app.ProcessSpans.
+func (c *Correlator) HandleSpans(spans []*model.Span, tenant string) {
+	c.Lock()
+	defer c.Unlock()
+	if c.size == 0 {
+		return
+	}
+	for _, span := range spans {
+		key := spanKey{tenant: tenant, traceID: span.TraceID, spanID: span.SpanID}
+		var logs []model.Log
+		for _, w := range []map[spanKey][]model.Log{c.previous, c.current} {
+			logs = append(logs, w[key]...)
+			delete(w, key)
+		}
+		if len(logs) == 0 {
+			continue
+		}
+		c.size -= len(logs)
+		c.metrics.Attached.Inc(int64(len(logs)))
+		span.Logs = append(span.Logs, logs...)
+		sort.SliceStable(span.Logs, func(i, j int) bool {
+			return span.Logs[i].Timestamp.Before(span.Logs[j].Timestamp)
+		})
+	}
+}
+
+// Start starts the periodic expiration of the log records whose span was not received.
+func (c *Correlator) Start() {
+	go c.runExpirationLoop()
+}
+
+func (c *Correlator) runExpirationLoop() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.options.Window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.expire()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// expire drops the log records of the previous window and starts a new one.
+func (c *Correlator) expire() {
+	c.Lock()
+	defer c.Unlock()
+	expired := 0
+	for _, logs := range c.previous {
+		expired += len(logs)
+	}
+	c.size -= expired
+	c.metrics.Expired.Inc(int64(expired))
+	c.previous, c.current = c.current, make(map[spanKey][]model.Log)
+}
+
+// Close stops the expiration of the log records.
+func (c *Correlator) Close() error {
+	close(c.stop)
+	<-c.done
+	return nil
+}
+
+// spanLog converts the log record to a span log with the body as message, the severity as level and the attributes.
+func spanLog(record plog.LogRecord) model.Log {
+	timestamp := record.Timestamp()
+	if timestamp == 0 {
+		timestamp = record.ObservedTimestamp()
+	}
+	var fields []model.KeyValue
+	if body := record.Body(); body.Type() != pcommon.ValueTypeEmpty {
+		fields = append(fields, model.String(messageField, body.AsString()))
+	}
+	if record.SeverityText() != "" {
+		fields = append(fields, model.String(levelField, record.SeverityText()))
+	} else if record.SeverityNumber() != plog.SeverityNumberUnspecified {
+		fields = append(fields, model.String(levelField, record.SeverityNumber().String()))
+	}
+	record.Attributes().Range(func(key string, value pcommon.Value) bool {
+		fields = append(fields, keyValue(key, value))
+		return true
+	})
+	return model.Log{Timestamp: timestamp.AsTime(), Fields: fields}
+}
+
+func keyValue(key string, value pcommon.Value) model.KeyValue {
+	switch value.Type() {
+	case pcommon.ValueTypeBool:
+		return model.Bool(key, value.Bool())
+	case pcommon.ValueTypeInt:
+		return model.Int64(key, value.Int())
+	case pcommon.ValueTypeDouble:
+		return model.Float64(key, value.Double())
+	case pcommon.ValueTypeBytes:
+		return model.Binary(key, value.Bytes().AsRaw())
+	default:
+		return model.String(key, value.AsString())
+	}
+}
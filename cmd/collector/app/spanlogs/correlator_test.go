@@ -0,0 +1,171 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanlogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var (
+	testTraceID = pcommon.TraceID([16]byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2})
+	testSpanID  = pcommon.SpanID([8]byte{0, 0, 0, 0, 0, 0, 0, 3})
+)
+
+func testSpan() *model.Span {
+	return &model.Span{
+		TraceID: model.NewTraceID(1, 2),
+		SpanID:  model.NewSpanID(3),
+	}
+}
+
+func addLogRecord(logs plog.Logs, timestamp time.Time, body string) plog.LogRecord {
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.SetTraceID(testTraceID)
+	record.SetSpanID(testSpanID)
+	record.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+	record.Body().SetStr(body)
+	return record
+}
+
+func TestCorrelatorAttachesLogs(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(time.Hour)
+	defer metricsFactory.Stop()
+	c := NewCorrelator(Options{}, metricsFactory)
+	assert.Equal(t, defaultWindow, c.options.Window)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	logs := plog.NewLogs()
+	addLogRecord(logs, start.Add(2*time.Second), "second")
+	addLogRecord(logs, start.Add(time.Second), "first")
+	// the log records without trace or span ID are ignored
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("none")
+	require.NoError(t, c.ProcessLogs(logs, "acme"))
+
+	// the log records of another tenant are not attached
+	other := testSpan()
+	c.HandleSpans([]*model.Span{other}, "other")
+	assert.Empty(t, other.Logs)
+
+	span := testSpan()
+	span.Logs = []model.Log{{Timestamp: start.Add(1500 * time.Millisecond)}}
+	c.HandleSpans([]*model.Span{span}, "acme")
+	require.Len(t, span.Logs, 3)
+	assert.Equal(t, []model.KeyValue{model.String(messageField, "first")}, span.Logs[0].Fields)
+	assert.Equal(t, start.Add(1500*time.Millisecond), span.Logs[1].Timestamp)
+	assert.Equal(t, []model.KeyValue{model.String(messageField, "second")}, span.Logs[2].Fields)
+	assert.Equal(t, 0, c.size)
+
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "span_logs_records", Tags: map[string]string{"result": "attached"}, Value: 2},
+		metricstest.ExpectedMetric{Name: "span_logs_records", Tags: map[string]string{"result": "uncorrelated"}, Value: 1},
+	)
+}
+
+func TestCorrelatorMaxRecords(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(time.Hour)
+	defer metricsFactory.Stop()
+	c := NewCorrelator(Options{MaxRecords: 1}, metricsFactory)
+
+	logs := plog.NewLogs()
+	addLogRecord(logs, time.Now(), "kept")
+	addLogRecord(logs, time.Now(), "dropped")
+	require.NoError(t, c.ProcessLogs(logs, ""))
+
+	span := testSpan()
+	c.HandleSpans([]*model.Span{span}, "")
+	require.Len(t, span.Logs, 1)
+	assert.Equal(t, []model.KeyValue{model.String(messageField, "kept")}, span.Logs[0].Fields)
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "span_logs_records", Tags: map[string]string{"result": "dropped"}, Value: 1},
+	)
+}
+
+func TestCorrelatorExpire(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(time.Hour)
+	defer metricsFactory.Stop()
+	c := NewCorrelator(Options{}, metricsFactory)
+
+	logs := plog.NewLogs()
+	addLogRecord(logs, time.Now(), "late")
+	require.NoError(t, c.ProcessLogs(logs, ""))
+
+	// the log records wait for their span over the previous window as well
+	c.expire()
+	span := testSpan()
+	c.HandleSpans([]*model.Span{span}, "")
+	assert.Len(t, span.Logs, 1)
+
+	require.NoError(t, c.ProcessLogs(logs, ""))
+	c.expire()
+	c.expire()
+	assert.Equal(t, 0, c.size)
+	span = testSpan()
+	c.HandleSpans([]*model.Span{span}, "")
+	assert.Empty(t, span.Logs)
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "span_logs_records", Tags: map[string]string{"result": "expired"}, Value: 1},
+	)
+}
+
+func TestCorrelatorStartClose(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(time.Hour)
+	defer metricsFactory.Stop()
+	c := NewCorrelator(Options{Window: time.Millisecond}, metricsFactory)
+	logs := plog.NewLogs()
+	addLogRecord(logs, time.Now(), "late")
+	require.NoError(t, c.ProcessLogs(logs, ""))
+
+	c.Start()
+	assert.Eventually(t, func() bool {
+		c.Lock()
+		defer c.Unlock()
+		return c.size == 0
+	}, time.Second, time.Millisecond)
+	require.NoError(t, c.Close())
+}
+
+func TestSpanLog(t *testing.T) {
+	observed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := plog.NewLogRecord()
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(observed))
+	record.SetSeverityNumber(plog.SeverityNumberWarn)
+	record.Attributes().PutBool("bool", true)
+	record.Attributes().PutInt("int", 42)
+	record.Attributes().PutDouble("double", 1.5)
+	record.Attributes().PutEmptyBytes("bytes").FromRaw([]byte{1, 2})
+	record.Attributes().PutStr("string", "value")
+	record.Attributes().PutEmptySlice("slice").AppendEmpty().SetStr("a")
+
+	assert.Equal(t, model.Log{
+		Timestamp: observed,
+		Fields: []model.KeyValue{
+			model.String(levelField, "Warn"),
+			model.Bool("bool", true),
+			model.Int64("int", 42),
+			model.Float64("double", 1.5),
+			model.Binary("bytes", []byte{1, 2}),
+			model.String("string", "value"),
+			model.String("slice", `["a"]`),
+		},
+	}, spanLog(record))
+
+	record = plog.NewLogRecord()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(observed))
+	record.SetSeverityText("ERROR")
+	record.SetSeverityNumber(plog.SeverityNumberError)
+	record.Body().SetInt(7)
+	assert.Equal(t, model.Log{
+		Timestamp: observed,
+		Fields:    []model.KeyValue{model.String(messageField, "7"), model.String(levelField, "ERROR")},
+	}, spanLog(record))
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanlogs
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	flagEnabled    = "collector.otlp.logs.enabled"
+	flagWindow     = "collector.otlp.logs.window"
+	flagMaxRecords = "collector.otlp.logs.max-records"
+
+	defaultWindow     = 30 * time.Second
+	defaultMaxRecords = 100_000
+)
+
+// Options holds configuration for the attachment of the OTLP log records to their spans.
+type Options struct {
+	// Enabled turns on the reception of the OTLP log records.
+	Enabled bool
+	// Window is how long the log records wait for their span, between one and two windows.
+	Window time.Duration
+	// MaxRecords is the maximum number of log records waiting for their span,
+	// the new log records are dropped once the limit is reached.
+	MaxRecords int
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Bool(flagEnabled, false,
+		"Enables the reception of the OTLP log records on the OTLP ports. The log records carrying a trace and a span ID "+
+			"are attached as logs to their span when it is received, to show the application logs inline in the traces.",
+	)
+	flagSet.Duration(flagWindow, defaultWindow,
+		"How long the OTLP log records wait for their span, between one and two windows, the log records whose span is not received are dropped.",
+	)
+	flagSet.Int(flagMaxRecords, defaultMaxRecords,
+		"The maximum number of OTLP log records waiting for their span, the new log records are dropped once the limit is reached.",
+	)
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts *Options) InitFromViper(v *viper.Viper) *Options {
+	opts.Enabled = v.GetBool(flagEnabled)
+	opts.Window = v.GetDuration(flagWindow)
+	opts.MaxRecords = v.GetInt(flagMaxRecords)
+	return opts
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanlogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsWithFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.otlp.logs.enabled=true",
+		"--collector.otlp.logs.window=1m",
+		"--collector.otlp.logs.max-records=10",
+	})
+	opts := new(Options).InitFromViper(v)
+	assert.Equal(t, Options{Enabled: true, Window: time.Minute, MaxRecords: 10}, *opts)
+}
+
+func TestOptionsDefaults(t *testing.T) {
+	v, _ := config.Viperize(AddFlags)
+	opts := new(Options).InitFromViper(v)
+	assert.Equal(t, Options{Window: defaultWindow, MaxRecords: defaultMaxRecords}, *opts)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package spanmetrics derives the call count and latency metrics of the services and operations
+// from the spans received by the collector, like the spanmetrics connector of the OpenTelemetry
+// Collector, so that the Monitor tab can be fed without deploying one.
+package spanmetrics
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+const (
+	statusError = "STATUS_CODE_ERROR"
+	statusOK    = "STATUS_CODE_OK"
+	statusUnset = "STATUS_CODE_UNSET"
+
+	otelStatusCodeTag = "otel.status_code"
+)
+
+// latencyBuckets are the upper bounds in milliseconds of the buckets of the latency histograms,
+// the default ones of the spanmetrics connector of the OpenTelemetry Collector.
+var latencyBuckets = []float64{2, 4, 6, 8, 10, 50, 100, 200, 400, 800, 1000, 1400, 2000, 5000, 10000, 15000}
+
+type seriesKey struct {
+	service   string
+	operation string
+	kind      string
+	status    string
+}
+
+type series struct {
+	calls   metrics.Counter
+	latency metrics.Histogram
+}
+
+// Generator records the calls and the latency in milliseconds of the received spans in the "calls"
+// counter and the "latency" histogram, labelled with service_name, operation, span_kind and status_code,
+// the metrics queried by the Prometheus metrics reader when not using the spanmetrics connector.
+type Generator struct {
+	options        Options
+	metricsFactory metrics.Factory
+
+	droppedSpanCounter metrics.Counter
+
+	mu     sync.RWMutex
+	series map[seriesKey]*series
+}
+
+// NewGenerator creates a Generator recording the span metrics with spanMetricsFactory,
+// which should not have a namespace, and its own metrics with metricsFactory.
+func NewGenerator(options Options, spanMetricsFactory metrics.Factory, metricsFactory metrics.Factory) *Generator {
+	return &Generator{
+		options:            options,
+		metricsFactory:     spanMetricsFactory,
+		droppedSpanCounter: metricsFactory.Counter(metrics.Options{Name: "span_metrics_dropped_spans"}),
+		series:             make(map[seriesKey]*series),
+	}
+}
+
+// HandleSpan records the call and the latency of the span. Its signature matches app.ProcessSpan.
+func (g *Generator) HandleSpan(span *model.Span, _ string) {
+	if span.Process == nil || span.Process.ServiceName == "" {
+		return
+	}
+	kind, _ := span.GetSpanKind()
+	key := seriesKey{
+		service:   span.Process.ServiceName,
+		operation: span.OperationName,
+		kind:      "SPAN_KIND_" + strings.ToUpper(kind.String()),
+		status:    spanStatus(span),
+	}
+	s := g.getSeries(key)
+	if s == nil {
+		g.droppedSpanCounter.Inc(1)
+		return
+	}
+	s.calls.Inc(1)
+	s.latency.Record(float64(span.Duration) / float64(time.Millisecond))
+}
+
+// getSeries returns the metrics of the key, creating them unless the maximum number of series is reached.
+func (g *Generator) getSeries(key seriesKey) *series {
+	g.mu.RLock()
+	s, ok := g.series[key]
+	g.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s, ok := g.series[key]; ok {
+		return s
+	}
+	if len(g.series) >= g.options.MaxSeries {
+		return nil
+	}
+	tags := map[string]string{
+		"service_name": key.service,
+		"operation":    key.operation,
+		"span_kind":    key.kind,
+		"status_code":  key.status,
+	}
+	s = &series{
+		calls: g.metricsFactory.Counter(metrics.Options{Name: "calls", Tags: tags}),
+		latency: g.metricsFactory.Histogram(metrics.HistogramOptions{
+			Name:    "latency",
+			Tags:    tags,
+			Buckets: latencyBuckets,
+		}),
+	}
+	g.series[key] = s
+	return s
+}
+
+func spanStatus(span *model.Span) string {
+	tags := model.KeyValues(span.Tags)
+	if tag, ok := tags.FindByKey("error"); ok && tag.Bool() {
+		return statusError
+	}
+	if tag, ok := tags.FindByKey(otelStatusCodeTag); ok {
+		switch tag.AsString() {
+		case "ERROR":
+			return statusError
+		case "OK":
+			return statusOK
+		}
+	}
+	return statusUnset
+}
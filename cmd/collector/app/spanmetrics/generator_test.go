@@ -0,0 +1,104 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	jprom "github.com/jaegertracing/jaeger/internal/metrics/prometheus"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestGenerator(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	g := NewGenerator(Options{MaxSeries: 10}, jprom.New(jprom.WithRegisterer(registry)), metricstest.NewFactory(0))
+
+	process := &model.Process{ServiceName: "frontend"}
+	g.HandleSpan(&model.Span{
+		OperationName: "GET /",
+		Process:       process,
+		Duration:      3 * time.Millisecond,
+		Tags:          model.KeyValues{model.String("span.kind", "server")},
+	}, "")
+	g.HandleSpan(&model.Span{
+		OperationName: "GET /",
+		Process:       process,
+		Duration:      30 * time.Millisecond,
+		Tags:          model.KeyValues{model.String("span.kind", "server")},
+	}, "")
+	g.HandleSpan(&model.Span{
+		OperationName: "GET /",
+		Process:       process,
+		Duration:      time.Millisecond,
+		Tags:          model.KeyValues{model.String("span.kind", "server"), model.Bool("error", true)},
+	}, "")
+	g.HandleSpan(&model.Span{OperationName: "query", Process: process}, "")
+	g.HandleSpan(&model.Span{OperationName: "no-process"}, "")
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	calls := make(map[string]float64)
+	latencyCounts := make(map[string]uint64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			assert.Equal(t, "frontend", labels["service_name"])
+			key := labels["operation"] + "|" + labels["span_kind"] + "|" + labels["status_code"]
+			switch family.GetName() {
+			case "calls_total":
+				calls[key] = m.GetCounter().GetValue()
+			case "latency":
+				latencyCounts[key] = m.GetHistogram().GetSampleCount()
+				if key == "GET /|SPAN_KIND_SERVER|STATUS_CODE_UNSET" {
+					assert.InDelta(t, 33, m.GetHistogram().GetSampleSum(), 0.001)
+				}
+			}
+		}
+	}
+	expected := map[string]float64{
+		"GET /|SPAN_KIND_SERVER|STATUS_CODE_UNSET":      2,
+		"GET /|SPAN_KIND_SERVER|STATUS_CODE_ERROR":      1,
+		"query|SPAN_KIND_UNSPECIFIED|STATUS_CODE_UNSET": 1,
+	}
+	assert.Equal(t, expected, calls)
+	for key, count := range expected {
+		assert.EqualValues(t, count, latencyCounts[key], key)
+	}
+}
+
+func TestGeneratorMaxSeries(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	g := NewGenerator(Options{MaxSeries: 1}, metricstest.NewFactory(0), metricsFactory)
+	process := &model.Process{ServiceName: "frontend"}
+	g.HandleSpan(&model.Span{OperationName: "a", Process: process}, "")
+	g.HandleSpan(&model.Span{OperationName: "a", Process: process}, "")
+	g.HandleSpan(&model.Span{OperationName: "b", Process: process}, "")
+	assert.Len(t, g.series, 1)
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "span_metrics_dropped_spans", Value: 1})
+}
+
+func TestSpanStatus(t *testing.T) {
+	tests := []struct {
+		tags   model.KeyValues
+		status string
+	}{
+		{tags: nil, status: statusUnset},
+		{tags: model.KeyValues{model.Bool("error", false)}, status: statusUnset},
+		{tags: model.KeyValues{model.Bool("error", true)}, status: statusError},
+		{tags: model.KeyValues{model.String(otelStatusCodeTag, "ERROR")}, status: statusError},
+		{tags: model.KeyValues{model.String(otelStatusCodeTag, "OK")}, status: statusOK},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.status, spanStatus(&model.Span{Tags: test.tags}))
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanmetrics
+
+import (
+	"flag"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	flagEnabled   = "collector.span-metrics.enabled"
+	flagMaxSeries = "collector.span-metrics.max-series"
+
+	defaultMaxSeries = 10_000
+)
+
+// Options holds configuration for the span metrics derived from the received spans.
+type Options struct {
+	// Enabled turns on the span metrics.
+	Enabled bool
+	// MaxSeries is the maximum number of combinations of service, operation, span kind and status
+	// with span metrics. The spans of new combinations are not counted once the limit is reached.
+	MaxSeries int
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Bool(flagEnabled, false,
+		"Enables the call count and latency metrics of the services and operations derived from the received spans, "+
+			"exposed like the internal metrics of the collector (e.g. on the Prometheus endpoint), to feed the Monitor tab "+
+			"without an OpenTelemetry Collector. Query them with --prometheus.query.support-spanmetrics-connector=false.",
+	)
+	flagSet.Int(flagMaxSeries, defaultMaxSeries,
+		"The maximum number of combinations of service, operation, span kind and status with span metrics.",
+	)
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts *Options) InitFromViper(v *viper.Viper) *Options {
+	opts.Enabled = v.GetBool(flagEnabled)
+	opts.MaxSeries = v.GetInt(flagMaxSeries)
+	return opts
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package validator checks the spans received by the collector for the errors of the
+// instrumentations, e.g. zero trace IDs or spans ending before they start, and rejects,
+// fixes or tags the invalid spans, counting them by service so that the faulty
+// instrumentations can be located.
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+const (
+	// TagKey is the key of the tags added to the spans by ActionTag, whose values are the violations.
+	TagKey = "internal.validation.violation"
+
+	// DefaultMaxAge is the default maximum age of the spans, the older ones having an invalid start time.
+	DefaultMaxAge = 7 * 24 * time.Hour
+	// DefaultMaxFutureSkew is the default maximum time the spans can start after they are received.
+	DefaultMaxFutureSkew = time.Hour
+
+	// maxServiceNames is the maximum number of services counted separately by the metrics.
+	maxServiceNames = 4000
+	// otherServices is the service of the metrics when there are more than maxServiceNames services.
+	otherServices = "other-services"
+)
+
+// Violation is a class of invalid spans.
+type Violation string
+
+const (
+	// ViolationZeroTraceID is the violation of the spans whose trace ID is zero.
+	ViolationZeroTraceID Violation = "zero_trace_id"
+	// ViolationZeroSpanID is the violation of the spans whose span ID is zero.
+	ViolationZeroSpanID Violation = "zero_span_id"
+	// ViolationNegativeDuration is the violation of the spans ending before they start.
+	// The spans are fixed by setting their duration to zero.
+	ViolationNegativeDuration Violation = "negative_duration"
+	// ViolationInvalidStartTime is the violation of the spans starting before the maximum age
+	// or after the maximum future skew, e.g. with zero or non UTC timestamps.
+	// The spans are fixed by moving them, and their logs, to the time they are received.
+	ViolationInvalidStartTime Violation = "invalid_start_time"
+)
+
+// violations lists the violations in the order they are checked.
+var violations = []Violation{
+	ViolationZeroTraceID,
+	ViolationZeroSpanID,
+	ViolationNegativeDuration,
+	ViolationInvalidStartTime,
+}
+
+// fixable are the violations that ActionFix applies to.
+var fixable = map[Violation]bool{
+	ViolationNegativeDuration: true,
+	ViolationInvalidStartTime: true,
+}
+
+// Action is what is done with the spans of a violation.
+type Action string
+
+const (
+	// ActionNone only counts the invalid spans.
+	ActionNone Action = "none"
+	// ActionReject drops the invalid spans.
+	ActionReject Action = "reject"
+	// ActionFix corrects the invalid spans, only for the violations that can be fixed.
+	ActionFix Action = "fix"
+	// ActionTag adds the TagKey tag with the violation to the invalid spans.
+	ActionTag Action = "tag"
+)
+
+// DefaultActions returns the actions of the violations when they are not configured.
+func DefaultActions() map[Violation]Action {
+	return map[Violation]Action{
+		ViolationZeroTraceID:      ActionReject,
+		ViolationZeroSpanID:       ActionReject,
+		ViolationNegativeDuration: ActionFix,
+		ViolationInvalidStartTime: ActionTag,
+	}
+}
+
+// ParseActions parses the actions of the violations from a comma separated list overriding
+// the default actions, e.g. "negative_duration=reject,invalid_start_time=fix".
+func ParseActions(s string) (map[Violation]Action, error) {
+	actions := DefaultActions()
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid validation action %q, expecting violation=action", pair)
+		}
+		violation, action := Violation(strings.TrimSpace(kv[0])), Action(strings.TrimSpace(kv[1]))
+		if _, ok := actions[violation]; !ok {
+			return nil, fmt.Errorf("unknown span violation %q", violation)
+		}
+		switch action {
+		case ActionNone, ActionReject, ActionTag:
+		case ActionFix:
+			if !fixable[violation] {
+				return nil, fmt.Errorf("the spans with violation %q cannot be fixed", violation)
+			}
+		default:
+			return nil, fmt.Errorf("unknown action %q of span violation %q", action, violation)
+		}
+		actions[violation] = action
+	}
+	return actions, nil
+}
+
+// Options configures the validation of the spans.
+type Options struct {
+	// Enabled turns the validation on.
+	Enabled bool
+	// Actions are the actions of the violations, ActionNone for the ones missing.
+	Actions map[Violation]Action
+	// MaxAge is the maximum time the spans can start before they are received.
+	MaxAge time.Duration
+	// MaxFutureSkew is the maximum time the spans can start after they are received.
+	MaxFutureSkew time.Duration
+}
+
+type spanValidator struct {
+	options Options
+	now     func() time.Time
+	factory metrics.Factory
+
+	lock     sync.Mutex
+	services map[string]bool
+	invalid  map[invalidKey]metrics.Counter
+}
+
+type invalidKey struct {
+	service   string
+	violation Violation
+}
+
+// NewSpanValidator creates a span filter validating the spans and applying the actions of their
+// violations, rejecting the spans of the violations with ActionReject. The invalid spans are counted
+// by the spans.invalid counter tagged with the service, the violation and its action.
+func NewSpanValidator(options Options, metricsFactory metrics.Factory) func(span *model.Span) bool {
+	return newSpanValidator(options, metricsFactory).Validate
+}
+
+func newSpanValidator(options Options, metricsFactory metrics.Factory) *spanValidator {
+	return &spanValidator{
+		options:  options,
+		now:      time.Now,
+		factory:  metricsFactory,
+		services: make(map[string]bool),
+		invalid:  make(map[invalidKey]metrics.Counter),
+	}
+}
+
+// Validate applies the actions of the violations of the span, and returns false if it must be rejected.
+func (v *spanValidator) Validate(span *model.Span) bool {
+	now := v.now()
+	for _, violation := range violations {
+		if !v.violates(span, violation, now) {
+			continue
+		}
+		action, ok := v.options.Actions[violation]
+		if !ok {
+			action = ActionNone
+		}
+		v.counter(span, violation, action).Inc(1)
+		switch action {
+		case ActionReject:
+			return false
+		case ActionFix:
+			fix(span, violation, now)
+		case ActionTag:
+			span.Tags = append(span.Tags, model.String(TagKey, string(violation)))
+		}
+	}
+	return true
+}
+
+func (v *spanValidator) violates(span *model.Span, violation Violation, now time.Time) bool {
+	switch violation {
+	case ViolationZeroTraceID:
+		return span.TraceID == model.TraceID{}
+	case ViolationZeroSpanID:
+		return span.SpanID == 0
+	case ViolationNegativeDuration:
+		return span.Duration < 0
+	case ViolationInvalidStartTime:
+		return span.StartTime.Before(now.Add(-v.options.MaxAge)) || span.StartTime.After(now.Add(v.options.MaxFutureSkew))
+	}
+	return false
+}
+
+func fix(span *model.Span, violation Violation, now time.Time) {
+	switch violation {
+	case ViolationNegativeDuration:
+		span.Duration = 0
+	case ViolationInvalidStartTime:
+		delta := now.Sub(span.StartTime)
+		span.StartTime = now
+		for i := range span.Logs {
+			span.Logs[i].Timestamp = span.Logs[i].Timestamp.Add(delta)
+		}
+	}
+}
+
+// counter returns the counter of the invalid spans of the service of the span, creating it
+// on first use. The services beyond maxServiceNames share the otherServices counters.
+func (v *spanValidator) counter(span *model.Span, violation Violation, action Action) metrics.Counter {
+	service := ""
+	if span.Process != nil {
+		service = span.Process.ServiceName
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if !v.services[service] {
+		if len(v.services) >= maxServiceNames {
+			service = otherServices
+		}
+		v.services[service] = true
+	}
+	key := invalidKey{service: service, violation: violation}
+	c, ok := v.invalid[key]
+	if !ok {
+		c = v.factory.Counter(metrics.Options{
+			Name: "spans.invalid",
+			Tags: map[string]string{"svc": service, "violation": string(violation), "action": string(action)},
+		})
+		v.invalid[key] = c
+	}
+	return c
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package validator
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newSpan(service string, start, duration time.Duration) *model.Span {
+	return &model.Span{
+		TraceID:   model.NewTraceID(1, 2),
+		SpanID:    model.NewSpanID(3),
+		StartTime: now.Add(start),
+		Duration:  duration,
+		Process:   &model.Process{ServiceName: service},
+	}
+}
+
+func newTestValidator(actions map[Violation]Action, mf *metricstest.Factory) *spanValidator {
+	v := newSpanValidator(Options{
+		Enabled:       true,
+		Actions:       actions,
+		MaxAge:        time.Hour,
+		MaxFutureSkew: time.Minute,
+	}, mf)
+	v.now = func() time.Time { return now }
+	return v
+}
+
+func TestParseActions(t *testing.T) {
+	actions, err := ParseActions("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultActions(), actions)
+
+	actions, err = ParseActions(" negative_duration=reject, invalid_start_time = fix,zero_span_id=none,")
+	require.NoError(t, err)
+	assert.Equal(t, map[Violation]Action{
+		ViolationZeroTraceID:      ActionReject,
+		ViolationZeroSpanID:       ActionNone,
+		ViolationNegativeDuration: ActionReject,
+		ViolationInvalidStartTime: ActionFix,
+	}, actions)
+
+	for _, s := range []string{"negative_duration", "unknown=reject", "negative_duration=drop", "zero_trace_id=fix"} {
+		t.Run(s, func(t *testing.T) {
+			_, err := ParseActions(s)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSpanValidatorDefaultActions(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	v := newTestValidator(DefaultActions(), mf)
+
+	valid := newSpan("frontend", -time.Minute, time.Second)
+	assert.True(t, v.Validate(valid))
+	assert.Equal(t, newSpan("frontend", -time.Minute, time.Second), valid)
+
+	zeroTraceID := newSpan("frontend", 0, time.Second)
+	zeroTraceID.TraceID = model.TraceID{}
+	assert.False(t, v.Validate(zeroTraceID))
+
+	zeroSpanID := newSpan("backend", 0, time.Second)
+	zeroSpanID.SpanID = 0
+	assert.False(t, v.Validate(zeroSpanID))
+
+	negative := newSpan("frontend", 0, -time.Second)
+	assert.True(t, v.Validate(negative))
+	assert.Equal(t, time.Duration(0), negative.Duration)
+
+	future := newSpan("backend", time.Hour, time.Second)
+	assert.True(t, v.Validate(future))
+	assert.Equal(t, now.Add(time.Hour), future.StartTime)
+	assert.Equal(t, []model.KeyValue{model.String(TagKey, string(ViolationInvalidStartTime))}, future.Tags)
+
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans.invalid", Tags: map[string]string{"svc": "frontend", "violation": "zero_trace_id", "action": "reject"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "spans.invalid", Tags: map[string]string{"svc": "backend", "violation": "zero_span_id", "action": "reject"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "spans.invalid", Tags: map[string]string{"svc": "frontend", "violation": "negative_duration", "action": "fix"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "spans.invalid", Tags: map[string]string{"svc": "backend", "violation": "invalid_start_time", "action": "tag"}, Value: 1},
+	)
+}
+
+func TestSpanValidatorFixStartTime(t *testing.T) {
+	v := newTestValidator(map[Violation]Action{ViolationInvalidStartTime: ActionFix}, metricstest.NewFactory(0))
+
+	span := newSpan("frontend", -2*time.Hour, time.Second)
+	span.Logs = []model.Log{{Timestamp: span.StartTime.Add(time.Millisecond)}}
+	assert.True(t, v.Validate(span))
+	assert.Equal(t, now, span.StartTime)
+	assert.Equal(t, now.Add(time.Millisecond), span.Logs[0].Timestamp)
+
+	zero := newSpan("frontend", 0, time.Second)
+	zero.StartTime = time.Time{}
+	assert.True(t, v.Validate(zero))
+	assert.Equal(t, now, zero.StartTime)
+}
+
+func TestSpanValidatorMultipleViolations(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	// the violations without action are only counted
+	v := newTestValidator(map[Violation]Action{ViolationInvalidStartTime: ActionTag}, mf)
+
+	span := newSpan("frontend", 2*time.Minute, -time.Second)
+	span.TraceID = model.TraceID{}
+	assert.True(t, v.Validate(span))
+	assert.Equal(t, -time.Second, span.Duration)
+	assert.Len(t, span.Tags, 1)
+
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans.invalid", Tags: map[string]string{"svc": "frontend", "violation": "zero_trace_id", "action": "none"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "spans.invalid", Tags: map[string]string{"svc": "frontend", "violation": "negative_duration", "action": "none"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "spans.invalid", Tags: map[string]string{"svc": "frontend", "violation": "invalid_start_time", "action": "tag"}, Value: 1},
+	)
+}
+
+func TestSpanValidatorOtherServices(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	v := newTestValidator(DefaultActions(), mf)
+	for i := 0; i < maxServiceNames+2; i++ {
+		assert.True(t, v.Validate(newSpan("svc-"+strconv.Itoa(i), 0, -time.Second)))
+	}
+	assert.True(t, v.Validate(&model.Span{TraceID: model.NewTraceID(1, 2), SpanID: 3, StartTime: now, Duration: -1}))
+
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans.invalid", Tags: map[string]string{"svc": "svc-0", "violation": "negative_duration", "action": "fix"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "spans.invalid", Tags: map[string]string{"svc": otherServices, "violation": "negative_duration", "action": "fix"}, Value: 3},
+	)
+}
+
+func TestNewSpanValidator(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	validate := NewSpanValidator(Options{Enabled: true, Actions: DefaultActions(), MaxAge: DefaultMaxAge, MaxFutureSkew: DefaultMaxFutureSkew}, mf)
+
+	span := newSpan("frontend", 0, time.Second)
+	span.StartTime = time.Now()
+	assert.True(t, validate(span))
+	span.SpanID = 0
+	assert.False(t, validate(span))
+}
@@ -16,6 +16,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -27,15 +29,20 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/flags"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/spanlogs"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/spanmetrics"
 	"github.com/jaegertracing/jaeger/cmd/internal/docs"
 	"github.com/jaegertracing/jaeger/cmd/internal/env"
 	cmdFlags "github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
 	"github.com/jaegertracing/jaeger/cmd/internal/status"
+	"github.com/jaegertracing/jaeger/cmd/internal/validate"
 	"github.com/jaegertracing/jaeger/internal/metrics/expvar"
 	"github.com/jaegertracing/jaeger/internal/metrics/fork"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/lifecycle"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
@@ -79,7 +86,7 @@ func main() {
 			version.NewInfoMetrics(metricsFactory)
 
 			storageFactory.InitFromViper(v, logger)
-			if err := storageFactory.Initialize(baseFactory, logger); err != nil {
+			if err := storageFactory.Initialize(baseFactory, logger.Named(cmdFlags.StorageLoggerName)); err != nil {
 				logger.Fatal("Failed to init storage factory", zap.Error(err))
 			}
 			spanWriter, err := storageFactory.CreateSpanWriter()
@@ -105,6 +112,19 @@ func main() {
 				logger.Fatal("Failed to initialize collector", zap.Error(err))
 			}
 			tm := tenancy.NewManager(&collectorOpts.GRPC.Tenancy)
+			depsAggregator, err := dependencies.CreateAggregator(
+				*new(dependencies.Options).InitFromViper(v), tm, storageFactory, metricsFactory, logger)
+			if err != nil {
+				logger.Fatal("Failed to create dependency aggregator", zap.Error(err))
+			}
+			var spanMetricsGenerator *spanmetrics.Generator
+			if spanMetricsOpts := new(spanmetrics.Options).InitFromViper(v); spanMetricsOpts.Enabled {
+				spanMetricsGenerator = spanmetrics.NewGenerator(*spanMetricsOpts, svc.MetricsFactory, metricsFactory)
+			}
+			var spanLogsCorrelator *spanlogs.Correlator
+			if spanLogsOpts := new(spanlogs.Options).InitFromViper(v); spanLogsOpts.Enabled {
+				spanLogsCorrelator = spanlogs.NewCorrelator(*spanLogsOpts, metricsFactory)
+			}
 
 			collector := app.New(&app.CollectorParams{
 				ServiceName:    serviceName,
@@ -115,26 +135,37 @@ func main() {
 				Aggregator:     aggregator,
 				HealthCheck:    svc.HC(),
 				TenancyMgr:     tm,
+
+				DependencyAggregator: depsAggregator,
+				SpanMetricsGenerator: spanMetricsGenerator,
+				SpanLogsCorrelator:   spanLogsCorrelator,
+			})
+			// the collector drains its queue into the storage before the storage is closed
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name: "storage",
+				Stop: func(context.Context) error {
+					var errs []error
+					if closer, ok := spanWriter.(io.Closer); ok {
+						errs = append(errs, closer.Close())
+					}
+					return errors.Join(append(errs, storageFactory.Close())...)
+				},
+			})
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name:      "collector",
+				DependsOn: []string{"storage"},
+				Start: func(context.Context) error {
+					return collector.Start(collectorOpts)
+				},
+				Stop: lifecycle.Closer(collector),
 			})
 			// Start all Collector services
-			if err := collector.Start(collectorOpts); err != nil {
+			if err := svc.Lifecycle.Start(context.Background()); err != nil {
 				logger.Fatal("Failed to start collector", zap.Error(err))
 			}
+			svc.Admin.Handle(app.TopProducersPath, collector.ServiceProducers())
 			// Wait for shutdown
-			svc.RunAndThen(func() {
-				if err := collector.Close(); err != nil {
-					logger.Error("failed to cleanly close the collector", zap.Error(err))
-				}
-				if closer, ok := spanWriter.(io.Closer); ok {
-					err := closer.Close()
-					if err != nil {
-						logger.Error("failed to close span writer", zap.Error(err))
-					}
-				}
-				if err := storageFactory.Close(); err != nil {
-					logger.Error("Failed to close storage factory", zap.Error(err))
-				}
-			})
+			svc.RunAndThen(nil)
 			return nil
 		},
 	}
@@ -144,6 +175,12 @@ func main() {
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.CollectorAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(validate.Command(v, func(v *viper.Viper, logger *zap.Logger) error {
+		storageFactory.InitFromViper(v, logger)
+		strategyStoreFactory.InitFromViper(v, logger)
+		_, err := new(flags.CollectorOptions).InitFromViper(v, logger)
+		return err
+	}))
 
 	config.AddFlags(
 		v,
@@ -152,6 +189,9 @@ func main() {
 		flags.AddFlags,
 		storageFactory.AddPipelineFlags,
 		strategyStoreFactory.AddFlags,
+		dependencies.AddFlags,
+		spanmetrics.AddFlags,
+		spanlogs.AddFlags,
 	)
 
 	if err := command.Execute(); err != nil {
@@ -26,6 +26,7 @@ const (
 	rollover           = "rollover"
 	timeout            = "timeout"
 	indexDateSeparator = "index-date-separator"
+	tenant             = "tenant"
 	username           = "es.username"
 	password           = "es.password"
 )
@@ -37,6 +38,7 @@ type Config struct {
 	Rollover                 bool
 	MasterNodeTimeoutSeconds int
 	IndexDateSeparator       string
+	Tenant                   string
 	Username                 string
 	Password                 string
 	TLSEnabled               bool
@@ -49,6 +51,7 @@ func (c *Config) AddFlags(flags *flag.FlagSet) {
 	flags.Bool(rollover, false, "Whether to remove indices created by rollover")
 	flags.Int(timeout, 120, "Number of seconds to wait for master node response")
 	flags.String(indexDateSeparator, "-", "Index date separator")
+	flags.String(tenant, "", "Remove the span and service indices of this tenant only, when the indices are split by tenant with --es.index-per-tenant. It does not work with rollover")
 	flags.String(username, "", "The username required by storage")
 	flags.String(password, "", "The password required by storage")
 }
@@ -64,6 +67,7 @@ func (c *Config) InitFromViper(v *viper.Viper) {
 	c.Rollover = v.GetBool(rollover)
 	c.MasterNodeTimeoutSeconds = v.GetInt(timeout)
 	c.IndexDateSeparator = v.GetString(indexDateSeparator)
+	c.Tenant = v.GetString(tenant)
 	c.Username = v.GetString(username)
 	c.Password = v.GetString(password)
 }
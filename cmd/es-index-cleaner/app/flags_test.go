@@ -39,6 +39,7 @@ func TestBindFlags(t *testing.T) {
 		"--archive=true",
 		"--timeout=150",
 		"--index-date-separator=@",
+		"--tenant=acme",
 		"--es.username=admin",
 		"--es.password=admin",
 	})
@@ -50,6 +51,7 @@ func TestBindFlags(t *testing.T) {
 	assert.True(t, c.Archive)
 	assert.Equal(t, 150, c.MasterNodeTimeoutSeconds)
 	assert.Equal(t, "@", c.IndexDateSeparator)
+	assert.Equal(t, "acme", c.Tenant)
 	assert.Equal(t, "admin", c.Username)
 	assert.Equal(t, "admin", c.Password)
 }
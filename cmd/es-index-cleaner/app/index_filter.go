@@ -19,6 +19,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/es/client"
 	"github.com/jaegertracing/jaeger/pkg/es/filter"
 )
@@ -33,6 +34,8 @@ type IndexFilter struct {
 	Archive bool
 	// Whether to filter rollover indices.
 	Rollover bool
+	// Tenant whose span and service indices are filtered, when the indices are split by tenant.
+	Tenant string
 	// Indices created before this date will be deleted.
 	DeleteBeforeThisDate time.Time
 }
@@ -46,6 +49,8 @@ func (i *IndexFilter) Filter(indices []client.Index) []client.Index {
 func (i *IndexFilter) filter(indices []client.Index) []client.Index {
 	var reg *regexp.Regexp
 	switch {
+	case i.Tenant != "":
+		reg, _ = regexp.Compile(fmt.Sprintf("^%sjaeger-(span|service)-%s-\\d{4}%s\\d{2}%s\\d{2}", i.IndexPrefix, regexp.QuoteMeta(es.TenantIndexName(i.Tenant)), i.IndexDateSeparator, i.IndexDateSeparator))
 	case i.Archive:
 		// archive works only for rollover
 		reg, _ = regexp.Compile(fmt.Sprintf("^%sjaeger-span-archive-\\d{6}", i.IndexPrefix))
@@ -124,6 +124,26 @@ func testIndexFilter(t *testing.T, prefix string) {
 				prefix + "jaeger-span-archive-write": true,
 			},
 		},
+		{
+			Index:        prefix + "jaeger-span-acme-2020-08-05",
+			CreationTime: time.Date(2020, time.August, 0o5, 15, 0, 0, 0, time.UTC),
+			Aliases:      map[string]bool{},
+		},
+		{
+			Index:        prefix + "jaeger-service-acme-2020-08-05",
+			CreationTime: time.Date(2020, time.August, 0o5, 15, 0, 0, 0, time.UTC),
+			Aliases:      map[string]bool{},
+		},
+		{
+			Index:        prefix + "jaeger-span-acme-2020-08-06",
+			CreationTime: time.Date(2020, time.August, 0o6, 15, 0, 0, 0, time.UTC),
+			Aliases:      map[string]bool{},
+		},
+		{
+			Index:        prefix + "jaeger-span-globex-2020-08-05",
+			CreationTime: time.Date(2020, time.August, 0o5, 15, 0, 0, 0, time.UTC),
+			Aliases:      map[string]bool{},
+		},
 		{
 			Index:        "other-jaeger-span-2020-08-05",
 			CreationTime: time.Date(2020, time.August, 0o5, 15, 0, 0, 0, time.UTC),
@@ -364,6 +384,27 @@ func testIndexFilter(t *testing.T, prefix string) {
 				},
 			},
 		},
+		{
+			name: "tenant indices, remove older 1 days",
+			filter: &IndexFilter{
+				IndexPrefix:          prefix,
+				IndexDateSeparator:   "-",
+				Tenant:               "acme",
+				DeleteBeforeThisDate: time20200807.Add(-time.Hour * 24 * time.Duration(1)),
+			},
+			expected: []client.Index{
+				{
+					Index:        prefix + "jaeger-span-acme-2020-08-05",
+					CreationTime: time.Date(2020, time.August, 0o5, 15, 0, 0, 0, time.UTC),
+					Aliases:      map[string]bool{},
+				},
+				{
+					Index:        prefix + "jaeger-service-acme-2020-08-05",
+					CreationTime: time.Date(2020, time.August, 0o5, 15, 0, 0, 0, time.UTC),
+					Aliases:      map[string]bool{},
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
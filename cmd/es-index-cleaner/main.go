@@ -93,6 +93,7 @@ func main() {
 				IndexDateSeparator:   cfg.IndexDateSeparator,
 				Archive:              cfg.Archive,
 				Rollover:             cfg.Rollover,
+				Tenant:               cfg.Tenant,
 				DeleteBeforeThisDate: deleteIndicesBefore,
 			}
 			logger.Info("Queried indices", zap.Any("indices", indices))
@@ -30,6 +30,8 @@ type IndexOption struct {
 	prefix    string
 	indexType string
 	Mapping   string
+	// RetentionPolicy is the name of the retention policy of the span indices, if any
+	RetentionPolicy string
 }
 
 // RolloverIndices return an array of indices to rollover
@@ -76,12 +78,31 @@ func RolloverIndices(archive bool, skipDependencies bool, adaptiveSampling bool,
 	return indexOptions
 }
 
+// RetentionIndices returns the span indices of the retention policies, written instead of
+// the default span index for the spans matching the policies
+func RetentionIndices(prefix string, policyNames []string) []IndexOption {
+	indexOptions := make([]IndexOption, 0, len(policyNames))
+	for _, name := range policyNames {
+		indexOptions = append(indexOptions, IndexOption{
+			prefix:          prefix,
+			Mapping:         "jaeger-span",
+			indexType:       "jaeger-span-" + name,
+			RetentionPolicy: name,
+		})
+	}
+	return indexOptions
+}
+
 func (i *IndexOption) IndexName() string {
 	return strings.TrimLeft(fmt.Sprintf("%s%s", i.prefix, i.indexType), "-")
 }
 
-// ReadAliasName returns read alias name of the index
+// ReadAliasName returns read alias name of the index, the indices of the retention policies
+// are read with the default span indices
 func (i *IndexOption) ReadAliasName() string {
+	if i.RetentionPolicy != "" {
+		return fmt.Sprintf(readAliasFormat, strings.TrimLeft(fmt.Sprintf("%s%s", i.prefix, i.Mapping), "-"))
+	}
 	return fmt.Sprintf(readAliasFormat, i.IndexName())
 }
 
@@ -97,5 +118,8 @@ func (i *IndexOption) InitialRolloverIndex() string {
 
 // TemplateName returns the prefixed template name
 func (i *IndexOption) TemplateName() string {
+	if i.RetentionPolicy != "" {
+		return i.IndexName()
+	}
 	return strings.TrimLeft(fmt.Sprintf("%s%s", i.prefix, i.Mapping), "-")
 }
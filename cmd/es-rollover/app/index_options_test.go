@@ -194,3 +194,18 @@ func TestRolloverIndices(t *testing.T) {
 		})
 	}
 }
+
+func TestRetentionIndices(t *testing.T) {
+	for _, prefix := range []string{"", "mytenant-"} {
+		t.Run(prefix, func(t *testing.T) {
+			result := RetentionIndices(prefix, []string{"payments"})
+			assert.Len(t, result, 1)
+			assert.Equal(t, "payments", result[0].RetentionPolicy)
+			assert.Equal(t, "jaeger-span", result[0].Mapping)
+			assert.Equal(t, prefix+"jaeger-span-payments", result[0].TemplateName())
+			assert.Equal(t, prefix+"jaeger-span-read", result[0].ReadAliasName())
+			assert.Equal(t, prefix+"jaeger-span-payments-write", result[0].WriteAliasName())
+			assert.Equal(t, prefix+"jaeger-span-payments-000001", result[0].InitialRolloverIndex())
+		})
+	}
+}
@@ -19,16 +19,27 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/jaegertracing/jaeger/cmd/es-rollover/app"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/es/client"
 	"github.com/jaegertracing/jaeger/pkg/es/filter"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/mappings"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
-const ilmVersionSupport = 7
+const (
+	ilmVersionSupport = 7
+
+	// retentionILMPolicy rolls over the indices of a retention policy daily,
+	// and deletes them once their spans are older than the TTL of the retention policy
+	retentionILMPolicy = `{"policy": {"phases": {` +
+		`"hot": {"min_age": "0ms", "actions": {"rollover": {"max_age": "1d"}}}, ` +
+		`"delete": {"min_age": "%ds", "actions": {"delete": {}}}}}}`
+)
 
 // Action holds the configuration and clients for init action
 type Action struct {
@@ -38,7 +49,7 @@ type Action struct {
 	ILMClient     client.IndexManagementLifecycleAPI
 }
 
-func (c Action) getMapping(version uint, templateName string) (string, error) {
+func (c Action) getMapping(version uint, indexopt app.IndexOption) (string, error) {
 	mappingBuilder := mappings.MappingBuilder{
 		TemplateBuilder:              es.TextTemplateBuilder{},
 		PrioritySpanTemplate:         int64(c.Config.PrioritySpanTemplate),
@@ -52,7 +63,17 @@ func (c Action) getMapping(version uint, templateName string) (string, error) {
 		ILMPolicyName:                c.Config.ILMPolicyName,
 		EsVersion:                    version,
 	}
-	return mappingBuilder.GetMapping(templateName)
+	if indexopt.RetentionPolicy != "" {
+		// the template of the indices of the retention policy overrides the default span template
+		mappingBuilder.RetentionPolicyName = indexopt.RetentionPolicy
+		mappingBuilder.ILMPolicyName = retentionILMPolicyName(c.Config.ILMPolicyName, indexopt.RetentionPolicy)
+		mappingBuilder.PrioritySpanTemplate++
+	}
+	return mappingBuilder.GetMapping(indexopt.Mapping)
+}
+
+func retentionILMPolicyName(ilmPolicyName, retentionPolicy string) string {
+	return ilmPolicyName + "-" + retentionPolicy
 }
 
 // Do the init action
@@ -80,6 +101,38 @@ func (c Action) Do() error {
 			return err
 		}
 	}
+	if c.Config.RetentionPoliciesFile != "" && !c.Config.Archive {
+		return c.initRetentionPolicies(version)
+	}
+	return nil
+}
+
+// initRetentionPolicies creates the ILM policies and the indices of the retention policies
+func (c Action) initRetentionPolicies(version uint) error {
+	if !c.Config.UseILM {
+		return errors.New("the retention policies require ILM, please use --es.use-ilm")
+	}
+	data, err := os.ReadFile(c.Config.RetentionPoliciesFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the retention policies: %w", err)
+	}
+	policies, err := spanstore.ParseRetentionPolicies(data)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		ilmPolicy := fmt.Sprintf(retentionILMPolicy, int64(policy.TTL/time.Second))
+		if err := c.ILMClient.Create(retentionILMPolicyName(c.Config.ILMPolicyName, policy.Name), ilmPolicy); err != nil {
+			return err
+		}
+		names = append(names, policy.Name)
+	}
+	for _, indexopt := range app.RetentionIndices(c.Config.IndexPrefix, names) {
+		if err := c.init(version, indexopt); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -111,7 +164,7 @@ func createIndexIfNotExist(c client.IndexAPI, index string) error {
 }
 
 func (c Action) init(version uint, indexopt app.IndexOption) error {
-	mapping, err := c.getMapping(version, indexopt.Mapping)
+	mapping, err := c.getMapping(version, indexopt)
 	if err != nil {
 		return err
 	}
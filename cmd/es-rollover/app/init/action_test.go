@@ -17,6 +17,8 @@ package init
 import (
 	"errors"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -271,3 +273,96 @@ func TestRolloverAction(t *testing.T) {
 		})
 	}
 }
+
+func TestRetentionPolicies(t *testing.T) {
+	policiesFile := filepath.Join(t.TempDir(), "retention.json")
+	require.NoError(t, os.WriteFile(policiesFile, []byte(`{"policies": [{"name": "payments", "ttl": "2160h", "services": ["payments"]}]}`), 0o600))
+
+	indexClient := &mocks.MockIndexAPI{}
+	clusterClient := &mocks.MockClusterAPI{}
+	ilmClient := &mocks.MockILMAPI{}
+	clusterClient.On("Version").Return(uint(8), nil)
+	ilmClient.On("Exists", "jaeger-ilm").Return(true, nil)
+	ilmClient.On("Create", "jaeger-ilm-payments", mock.MatchedBy(func(policy string) bool {
+		return strings.Contains(policy, `"delete": {"min_age": "7776000s"`)
+	})).Return(nil)
+	indexClient.On("CreateTemplate", mock.Anything, mock.Anything).Return(nil)
+	indexClient.On("CreateIndex", mock.Anything).Return(nil)
+	indexClient.On("GetJaegerIndices", "").Return([]client.Index{}, nil)
+	indexClient.On("CreateAlias", mock.Anything).Return(nil)
+	initAction := Action{
+		Config: Config{
+			Config: app.Config{
+				UseILM:           true,
+				ILMPolicyName:    "jaeger-ilm",
+				SkipDependencies: true,
+			},
+			PrioritySpanTemplate:  10,
+			RetentionPoliciesFile: policiesFile,
+		},
+		IndicesClient: indexClient,
+		ClusterClient: clusterClient,
+		ILMClient:     ilmClient,
+	}
+
+	require.NoError(t, initAction.Do())
+
+	ilmClient.AssertExpectations(t)
+	indexClient.AssertCalled(t, "CreateTemplate", mock.MatchedBy(func(template string) bool {
+		return strings.Contains(template, `"priority": 11`) &&
+			strings.Contains(template, `"index_patterns": "jaeger-span-payments-*"`) &&
+			strings.Contains(template, `"name": "jaeger-ilm-payments"`)
+	}), "jaeger-span-payments")
+	indexClient.AssertCalled(t, "CreateIndex", "jaeger-span-payments-000001")
+	indexClient.AssertCalled(t, "CreateAlias", []client.Alias{
+		{Index: "jaeger-span-payments-000001", Name: "jaeger-span-read", IsWriteIndex: false},
+		{Index: "jaeger-span-payments-000001", Name: "jaeger-span-payments-write", IsWriteIndex: true},
+	})
+}
+
+func TestRetentionPoliciesErrors(t *testing.T) {
+	invalidFile := filepath.Join(t.TempDir(), "retention.json")
+	require.NoError(t, os.WriteFile(invalidFile, []byte(`{"policies": [{"name": "payments"}]}`), 0o600))
+	validFile := filepath.Join(t.TempDir(), "retention.json")
+	require.NoError(t, os.WriteFile(validFile, []byte(`{"policies": [{"name": "payments", "ttl": "1h"}]}`), 0o600))
+
+	tests := []struct {
+		name        string
+		useILM      bool
+		file        string
+		expectedErr string
+	}{
+		{name: "without ilm", file: validFile, expectedErr: "the retention policies require ILM"},
+		{name: "missing file", useILM: true, file: "/does/not/exist.json", expectedErr: "failed to read the retention policies"},
+		{name: "invalid file", useILM: true, file: invalidFile, expectedErr: `invalid TTL of retention policy "payments"`},
+		{name: "ilm policy error", useILM: true, file: validFile, expectedErr: "error creating ilm policy"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexClient := &mocks.MockIndexAPI{}
+			clusterClient := &mocks.MockClusterAPI{}
+			ilmClient := &mocks.MockILMAPI{}
+			clusterClient.On("Version").Return(uint(7), nil)
+			ilmClient.On("Exists", "jaeger-ilm").Return(true, nil)
+			ilmClient.On("Create", "jaeger-ilm-payments", mock.Anything).Return(errors.New("error creating ilm policy"))
+			indexClient.On("CreateTemplate", mock.Anything, mock.Anything).Return(nil)
+			indexClient.On("CreateIndex", mock.Anything).Return(nil)
+			indexClient.On("GetJaegerIndices", "").Return([]client.Index{}, nil)
+			indexClient.On("CreateAlias", mock.Anything).Return(nil)
+			initAction := Action{
+				Config: Config{
+					Config: app.Config{
+						UseILM:           test.useILM,
+						ILMPolicyName:    "jaeger-ilm",
+						SkipDependencies: true,
+					},
+					RetentionPoliciesFile: test.file,
+				},
+				IndicesClient: indexClient,
+				ClusterClient: clusterClient,
+				ILMClient:     ilmClient,
+			}
+			require.ErrorContains(t, initAction.Do(), test.expectedErr)
+		})
+	}
+}
@@ -29,6 +29,7 @@ const (
 	priorityServiceTemplate      = "priority-service-template"
 	priorityDependenciesTemplate = "priority-dependencies-template"
 	prioritySamplingTemplate     = "priority-sampling-template"
+	retentionPoliciesFile        = "retention-policies-file"
 )
 
 // Config holds configuration for index cleaner binary.
@@ -40,6 +41,7 @@ type Config struct {
 	PriorityServiceTemplate      int
 	PriorityDependenciesTemplate int
 	PrioritySamplingTemplate     int
+	RetentionPoliciesFile        string
 }
 
 // AddFlags adds flags for TLS to the FlagSet.
@@ -50,6 +52,8 @@ func (c *Config) AddFlags(flags *flag.FlagSet) {
 	flags.Int(priorityServiceTemplate, 0, "Priority of jaeger-service index template (ESv8 only)")
 	flags.Int(priorityDependenciesTemplate, 0, "Priority of jaeger-dependencies index template (ESv8 only)")
 	flags.Int(prioritySamplingTemplate, 0, "Priority of jaeger-sampling index template (ESv8 only)")
+	flags.String(retentionPoliciesFile, "", "The path to the JSON file with the retention policies of the span storage, "+
+		"an ILM policy deleting the indices after the TTL of each retention policy is created (requires --es.use-ilm)")
 }
 
 // InitFromViper initializes config from viper.Viper.
@@ -60,4 +64,5 @@ func (c *Config) InitFromViper(v *viper.Viper) {
 	c.PriorityServiceTemplate = v.GetInt(priorityServiceTemplate)
 	c.PriorityDependenciesTemplate = v.GetInt(priorityDependenciesTemplate)
 	c.PrioritySamplingTemplate = v.GetInt(prioritySamplingTemplate)
+	c.RetentionPoliciesFile = v.GetString(retentionPoliciesFile)
 }
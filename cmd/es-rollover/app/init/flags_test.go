@@ -40,6 +40,7 @@ func TestBindFlags(t *testing.T) {
 		"--priority-service-template=301",
 		"--priority-dependencies-template=302",
 		"--priority-sampling-template=303",
+		"--retention-policies-file=retention.json",
 	})
 	require.NoError(t, err)
 
@@ -50,4 +51,5 @@ func TestBindFlags(t *testing.T) {
 	assert.Equal(t, 301, c.PriorityServiceTemplate)
 	assert.Equal(t, 302, c.PriorityDependenciesTemplate)
 	assert.Equal(t, 303, c.PrioritySamplingTemplate)
+	assert.Equal(t, "retention.json", c.RetentionPoliciesFile)
 }
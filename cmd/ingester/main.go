@@ -15,6 +15,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -32,7 +34,9 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
 	"github.com/jaegertracing/jaeger/cmd/internal/status"
+	"github.com/jaegertracing/jaeger/cmd/internal/validate"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/lifecycle"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	"github.com/jaegertracing/jaeger/plugin/storage"
@@ -62,7 +66,7 @@ func main() {
 			version.NewInfoMetrics(metricsFactory)
 
 			storageFactory.InitFromViper(v, logger)
-			if err := storageFactory.Initialize(baseFactory, logger); err != nil {
+			if err := storageFactory.Initialize(baseFactory, logger.Named(flags.StorageLoggerName)); err != nil {
 				logger.Fatal("Failed to init storage factory", zap.Error(err))
 			}
 			spanWriter, err := storageFactory.CreateSpanWriter()
@@ -76,25 +80,35 @@ func main() {
 			if err != nil {
 				logger.Fatal("Unable to create consumer", zap.Error(err))
 			}
-			consumer.Start()
-
-			svc.RunAndThen(func() {
-				if err := options.TLS.Close(); err != nil {
-					logger.Error("Failed to close TLS certificates watcher", zap.Error(err))
-				}
-				if err = consumer.Close(); err != nil {
-					logger.Error("Failed to close consumer", zap.Error(err))
-				}
-				if closer, ok := spanWriter.(io.Closer); ok {
-					err := closer.Close()
-					if err != nil {
-						logger.Error("Failed to close span writer", zap.Error(err))
+			// the consumer commits the offsets of the spans written to the storage before the storage is closed
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name: "storage",
+				Stop: func(context.Context) error {
+					var errs []error
+					if closer, ok := spanWriter.(io.Closer); ok {
+						errs = append(errs, closer.Close())
 					}
-				}
-				if err := storageFactory.Close(); err != nil {
-					logger.Error("Failed to close storage factory", zap.Error(err))
-				}
+					return errors.Join(append(errs, storageFactory.Close())...)
+				},
+			})
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name: "kafka-tls",
+				Stop: lifecycle.Closer(&options.TLS),
+			})
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name:      "consumer",
+				DependsOn: []string{"storage", "kafka-tls"},
+				Start: func(context.Context) error {
+					consumer.Start()
+					return nil
+				},
+				Stop: lifecycle.Closer(consumer),
 			})
+			if err := svc.Lifecycle.Start(context.Background()); err != nil {
+				logger.Fatal("Failed to start consumer", zap.Error(err))
+			}
+
+			svc.RunAndThen(nil)
 			return nil
 		},
 	}
@@ -104,6 +118,11 @@ func main() {
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.IngesterAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(validate.Command(v, func(v *viper.Viper, logger *zap.Logger) error {
+		storageFactory.InitFromViper(v, logger)
+		new(app.Options).InitFromViper(v)
+		return nil
+	}))
 
 	config.AddFlags(
 		v,
@@ -32,12 +32,14 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
 	"github.com/jaegertracing/jaeger/pkg/version"
 )
 
 const (
-	adminHTTPHostPort = "admin.http.host-port"
+	adminHTTPHostPort      = "admin.http.host-port"
+	adminHTTPAddressFamily = "admin.http.address-family"
 )
 
 var tlsAdminHTTPFlagsConfig = tlscfg.ServerFlagsConfig{
@@ -48,17 +50,20 @@ var tlsAdminHTTPFlagsConfig = tlscfg.ServerFlagsConfig{
 type AdminServer struct {
 	logger               *zap.Logger
 	adminHostPort        string
+	addressFamily        netutils.AddressFamily
 	hc                   *healthcheck.HealthCheck
 	mux                  *http.ServeMux
 	server               *http.Server
 	tlsCfg               *tls.Config
 	tlsCertWatcherCloser io.Closer
+	logLevels            *LogLevels
 }
 
 // NewAdminServer creates a new admin server.
 func NewAdminServer(hostPort string) *AdminServer {
 	return &AdminServer{
 		adminHostPort: hostPort,
+		addressFamily: netutils.DualStack,
 		logger:        zap.NewNop(),
 		hc:            healthcheck.New(),
 		mux:           http.NewServeMux(),
@@ -79,6 +84,7 @@ func (s *AdminServer) setLogger(logger *zap.Logger) {
 // AddFlags registers CLI flags.
 func (s *AdminServer) AddFlags(flagSet *flag.FlagSet) {
 	flagSet.String(adminHTTPHostPort, s.adminHostPort, fmt.Sprintf("The host:port (e.g. 127.0.0.1%s or %s) for the admin server, including health check, /metrics, etc.", s.adminHostPort, s.adminHostPort))
+	flagSet.String(adminHTTPAddressFamily, string(netutils.DualStack), fmt.Sprintf(netutils.AddressFamilyFlagUsage, "admin server"))
 	tlsAdminHTTPFlagsConfig.AddFlags(flagSet)
 }
 
@@ -87,8 +93,13 @@ func (s *AdminServer) initFromViper(v *viper.Viper, logger *zap.Logger) error {
 	s.setLogger(logger)
 
 	s.adminHostPort = v.GetString(adminHTTPHostPort)
+	addressFamily, err := netutils.ParseAddressFamily(v.GetString(adminHTTPAddressFamily))
+	if err != nil {
+		return fmt.Errorf("failed to parse admin server address family: %w", err)
+	}
+	s.addressFamily = addressFamily
 	var tlsAdminHTTP tlscfg.Options
-	tlsAdminHTTP, err := tlsAdminHTTPFlagsConfig.InitFromViper(v)
+	tlsAdminHTTP, err = tlsAdminHTTPFlagsConfig.InitFromViper(v)
 	if err != nil {
 		return fmt.Errorf("failed to parse admin server TLS options: %w", err)
 	}
@@ -112,7 +123,7 @@ func (s *AdminServer) Handle(path string, handler http.Handler) {
 
 // Serve starts HTTP server.
 func (s *AdminServer) Serve() error {
-	l, err := net.Listen("tcp", s.adminHostPort)
+	l, err := netutils.Listen(s.addressFamily, s.adminHostPort)
 	if err != nil {
 		s.logger.Error("Admin server failed to listen", zap.Error(err))
 		return err
@@ -130,6 +141,10 @@ func (s *AdminServer) serveWithListener(l net.Listener) {
 	s.logger.Info("Mounting health check on admin server", zap.String("route", "/"))
 	s.mux.Handle("/", s.hc.Handler())
 	version.RegisterHandler(s.mux, s.logger)
+	if s.logLevels != nil {
+		s.logger.Info("Mounting log level handler on admin server", zap.String("route", logLevelRoute))
+		s.mux.Handle(logLevelRoute, s.logLevels.Handler(s.logger))
+	}
 	s.registerPprofHandlers()
 	recoveryHandler := recoveryhandler.NewRecoveryHandler(s.logger, true)
 	errorLog, _ := zap.NewStdLogAt(s.logger, zapcore.ErrorLevel)
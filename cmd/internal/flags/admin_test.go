@@ -32,6 +32,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/ports"
 )
 
@@ -109,6 +110,25 @@ func TestAdminWithFailedFlags(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to parse admin server TLS options")
 }
 
+func TestAdminServerAddressFamily(t *testing.T) {
+	adminServer := NewAdminServer(":0")
+	v, command := config.Viperize(adminServer.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--admin.http.host-port=127.0.0.1:0",
+		"--admin.http.address-family=ipv4",
+	}))
+	require.NoError(t, adminServer.initFromViper(v, zap.NewNop()))
+	assert.Equal(t, netutils.IPv4, adminServer.addressFamily)
+	require.NoError(t, adminServer.Serve())
+	require.NoError(t, adminServer.Close())
+
+	adminServer = NewAdminServer(":0")
+	v, command = config.Viperize(adminServer.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--admin.http.address-family=ipv5"}))
+	err := adminServer.initFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to parse admin server address family")
+}
+
 func TestAdminServerTLS(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -165,3 +185,24 @@ func TestAdminServerTLS(t *testing.T) {
 		})
 	}
 }
+
+func TestAdminServerLogLevel(t *testing.T) {
+	adminServer := NewAdminServer(":0")
+	v, _ := config.Viperize(adminServer.AddFlags)
+	require.NoError(t, adminServer.initFromViper(v, zap.NewNop()))
+	levels, err := ParseLogLevels("info,storage=debug")
+	require.NoError(t, err)
+	adminServer.logLevels = levels
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	adminServer.serveWithListener(l)
+	defer adminServer.Close()
+
+	req, err := http.NewRequest(http.MethodPut, "http://"+l.Addr().String()+logLevelRoute, strings.NewReader(`{"level": "debug"}`))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "debug,storage=debug", levels.String())
+}
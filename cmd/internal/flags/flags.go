@@ -16,14 +16,17 @@
 package flags
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
 )
 
 const (
@@ -34,21 +37,94 @@ const (
 
 // AddConfigFileFlag adds flags for ExternalConfFlags
 func AddConfigFileFlag(flagSet *flag.FlagSet) {
-	flagSet.String(configFile, "", "Configuration file in JSON, TOML, YAML, HCL, or Java properties formats (default none). See spf13/viper for precedence.")
+	flagSet.String(configFile, "", "Configuration file in JSON, TOML, YAML, HCL, or Java properties formats (default none), "+
+		"with the options nested by the segments of the flag names, e.g. collector: {grpc-server: {host-port: :14250}}. "+
+		"The ${VAR} and ${VAR:default} references are replaced with the values of the environment variables. "+
+		"The flags and the environment variables of the options take precedence over the file, see spf13/viper.")
 }
 
-// TryLoadConfigFile initializes viper with config file specified as flag
+// envReference matches the ${VAR} and ${VAR:default} references to environment variables in the config files.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::([^}]*))?\}`)
+
+// TryLoadConfigFile initializes viper with config file specified as flag.
+// If flags are bound to viper, the options of the file must be keys of the flags.
 func TryLoadConfigFile(v *viper.Viper) error {
-	if file := v.GetString(configFile); file != "" {
-		v.SetConfigFile(file)
-		err := v.ReadInConfig()
-		if err != nil {
-			return fmt.Errorf("cannot load config file %s: %w", file, err)
+	file := v.GetString(configFile)
+	if file == "" {
+		return nil
+	}
+	configType := strings.TrimPrefix(filepath.Ext(file), ".")
+	if !slices.Contains(viper.SupportedExts, configType) {
+		return fmt.Errorf("cannot load config file %s: unsupported extension %q, expecting one of %v", file, configType, viper.SupportedExts)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("cannot load config file %s: %w", file, err)
+	}
+	data = envReference.ReplaceAllFunc(data, func(ref []byte) []byte {
+		match := envReference.FindSubmatch(ref)
+		if value, ok := os.LookupEnv(string(match[1])); ok && value != "" {
+			return []byte(value)
+		}
+		return match[2]
+	})
+
+	fileViper := viper.New()
+	fileViper.SetConfigType(configType)
+	if err := fileViper.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("cannot load config file %s: %w", file, err)
+	}
+	if knownKeys := v.AllKeys(); len(knownKeys) > 0 {
+		for _, key := range fileViper.AllKeys() {
+			if slices.Contains(knownKeys, key) {
+				continue
+			}
+			if suggestion := closestKey(key, knownKeys); suggestion != "" {
+				return fmt.Errorf("invalid config file %s: unknown option %q, did you mean %q?", file, key, suggestion)
+			}
+			return fmt.Errorf("invalid config file %s: unknown option %q", file, key)
 		}
 	}
+
+	v.SetConfigType(configType)
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("cannot load config file %s: %w", file, err)
+	}
 	return nil
 }
 
+// closestKey returns the known key with the smallest edit distance to key, if it is a likely typo.
+func closestKey(key string, knownKeys []string) string {
+	closest, minDistance := "", len(key)/3+1
+	for _, known := range knownKeys {
+		if d := editDistance(key, known); d < minDistance {
+			closest, minDistance = known, d
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
 // ParseJaegerTags parses the Jaeger tags string into a map.
 func ParseJaegerTags(jaegerTags string) map[string]string {
 	if jaegerTags == "" {
@@ -109,7 +185,7 @@ func AddFlags(flagSet *flag.FlagSet) {
 
 // AddLoggingFlag adds logging flag for SharedFlags
 func AddLoggingFlag(flagSet *flag.FlagSet) {
-	flagSet.String(logLevel, "info", "Minimal allowed log Level. For more levels see https://github.com/uber-go/zap")
+	flagSet.String(logLevel, "info", "Minimal allowed log Level, optionally followed by the levels of components, e.g. info,storage=debug,grpc=warn. For more levels see https://github.com/uber-go/zap")
 }
 
 // InitFromViper initializes SharedFlags with properties from viper
@@ -120,11 +196,20 @@ func (flags *SharedFlags) InitFromViper(v *viper.Viper) *SharedFlags {
 
 // NewLogger returns logger based on configuration in SharedFlags
 func (flags *SharedFlags) NewLogger(conf zap.Config, options ...zap.Option) (*zap.Logger, error) {
-	var level zapcore.Level
-	err := (&level).UnmarshalText([]byte(flags.Logging.Level))
+	logger, _, err := flags.NewLoggerWithLevels(conf, options...)
+	return logger, err
+}
+
+// NewLoggerWithLevels returns logger based on configuration in SharedFlags,
+// and the log levels to change its level at runtime.
+func (flags *SharedFlags) NewLoggerWithLevels(conf zap.Config, options ...zap.Option) (*zap.Logger, *LogLevels, error) {
+	levels, err := ParseLogLevels(flags.Logging.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger, err := levels.NewLogger(conf, options...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	conf.Level = zap.NewAtomicLevelAt(level)
-	return conf.Build(options...)
+	return logger, levels, nil
 }
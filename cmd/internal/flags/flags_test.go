@@ -16,10 +16,17 @@
 package flags
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
 )
 
 func TestParseJaegerTags(t *testing.T) {
@@ -56,3 +63,89 @@ func TestParseJaegerTagsPanic(t *testing.T) {
 		},
 	)
 }
+
+func newConfigFileViper(t *testing.T, name, content string, flagNames []string, args ...string) *viper.Viper {
+	file := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	v, command := config.Viperize(AddConfigFileFlag, func(flagSet *flag.FlagSet) {
+		for _, name := range flagNames {
+			flagSet.String(name, "", "")
+		}
+	})
+	require.NoError(t, command.ParseFlags(append(args, "--config-file="+file)))
+	return v
+}
+
+func TestTryLoadConfigFile(t *testing.T) {
+	t.Setenv("JAEGER_TEST_HOST_PORT", ":14250")
+	v := newConfigFileViper(t, "config.yaml", `
+collector:
+  grpc-server:
+    host-port: ${JAEGER_TEST_HOST_PORT}
+  queue-size: ${JAEGER_TEST_QUEUE_SIZE:2000}
+  tags: ${JAEGER_TEST_TAGS}
+`, []string{"collector.grpc-server.host-port", "collector.queue-size", "collector.tags"})
+	require.NoError(t, TryLoadConfigFile(v))
+	assert.Equal(t, ":14250", v.GetString("collector.grpc-server.host-port"))
+	assert.Equal(t, 2000, v.GetInt("collector.queue-size"))
+	assert.Empty(t, v.GetString("collector.tags"))
+}
+
+func TestTryLoadConfigFileOverrides(t *testing.T) {
+	v := newConfigFileViper(t, "config.yaml", `
+collector:
+  queue-size: 2000
+  num-workers: 10
+`, []string{"collector.queue-size", "collector.num-workers"}, "--collector.queue-size=3000")
+	t.Setenv("COLLECTOR_NUM_WORKERS", "20")
+	require.NoError(t, TryLoadConfigFile(v))
+	assert.Equal(t, 3000, v.GetInt("collector.queue-size"))
+	assert.Equal(t, 20, v.GetInt("collector.num-workers"))
+}
+
+func TestTryLoadConfigFileErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		content string
+		expErr  string
+	}{
+		{
+			name:    "unknown option with suggestion",
+			file:    "config.yaml",
+			content: "collector:\n  queue-sise: 2000\n",
+			expErr:  `unknown option "collector.queue-sise", did you mean "collector.queue-size"?`,
+		},
+		{
+			name:    "unknown option",
+			file:    "config.yaml",
+			content: "ingester:\n  parallelism: 10\n",
+			expErr:  `unknown option "ingester.parallelism"`,
+		},
+		{
+			name:    "unsupported extension",
+			file:    "config.xml",
+			content: "<collector/>",
+			expErr:  `unsupported extension "xml"`,
+		},
+		{
+			name:    "invalid content",
+			file:    "config.yaml",
+			content: "collector: [",
+			expErr:  "cannot load config file",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := newConfigFileViper(t, tc.file, tc.content, []string{"collector.queue-size"})
+			require.ErrorContains(t, TryLoadConfigFile(v), tc.expErr)
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("queue-size", "queue-size"))
+	assert.Equal(t, 1, editDistance("queue-sise", "queue-size"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 4, editDistance("", "size"))
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package flags
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// logLevelRoute is the route of the admin server to get and change the log levels.
+	logLevelRoute = "/loglevel"
+
+	// StorageLoggerName is the name of the logger of the storage factories,
+	// to set the log level of the storage separately.
+	StorageLoggerName = "storage"
+	// grpcLoggerName is the name of the logger of the gRPC library.
+	grpcLoggerName = "grpc"
+)
+
+// LogLevels holds the log level of the loggers created by NewLogger, and the levels of the
+// components that override it. The level of a component applies to the loggers named after
+// the component and their children, e.g. to "storage" and "storage.cassandra" for "storage".
+// The levels can be changed at runtime.
+type LogLevels struct {
+	mu         sync.RWMutex
+	level      zapcore.Level
+	components map[string]zapcore.Level
+	// minLevel is the lowest of all the levels, to skip the entries disabled for every logger
+	minLevel zap.AtomicLevel
+}
+
+// ParseLogLevels parses log levels of the form "info,storage=debug,grpc=warn", where the level
+// without a component is the default level of the loggers, and is info if omitted.
+func ParseLogLevels(spec string) (*LogLevels, error) {
+	l := &LogLevels{minLevel: zap.NewAtomicLevel()}
+	level, components, err := parseLogLevels(spec)
+	if err != nil {
+		return nil, err
+	}
+	l.set(level, components)
+	return l, nil
+}
+
+func parseLogLevels(spec string) (zapcore.Level, map[string]zapcore.Level, error) {
+	level := zapcore.InfoLevel
+	components := make(map[string]zapcore.Level)
+	var defaultSet bool
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		component, levelText, ok := strings.Cut(item, "=")
+		if !ok {
+			if defaultSet {
+				return level, nil, fmt.Errorf("log levels %q have more than one default level", spec)
+			}
+			if err := level.UnmarshalText([]byte(item)); err != nil {
+				return level, nil, err
+			}
+			defaultSet = true
+			continue
+		}
+		component = strings.TrimSpace(component)
+		if component == "" {
+			return level, nil, fmt.Errorf("log level %q has no component", item)
+		}
+		var componentLevel zapcore.Level
+		if err := componentLevel.UnmarshalText([]byte(strings.TrimSpace(levelText))); err != nil {
+			return level, nil, fmt.Errorf("invalid log level of component %s: %w", component, err)
+		}
+		components[component] = componentLevel
+	}
+	return level, components, nil
+}
+
+func (l *LogLevels) set(level zapcore.Level, components map[string]zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.setLocked(level, components)
+}
+
+func (l *LogLevels) setLocked(level zapcore.Level, components map[string]zapcore.Level) {
+	l.level = level
+	l.components = components
+	minLevel := level
+	for _, componentLevel := range components {
+		minLevel = min(minLevel, componentLevel)
+	}
+	l.minLevel.SetLevel(minLevel)
+}
+
+// String returns the log levels in the form parsed by ParseLogLevels.
+func (l *LogLevels) String() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	items := []string{l.level.String()}
+	for component, level := range l.components {
+		items = append(items, component+"="+level.String())
+	}
+	sort.Strings(items[1:])
+	return strings.Join(items, ",")
+}
+
+// enabled returns whether the entries of the level are logged by the logger of the name.
+func (l *LogLevels) enabled(loggerName string, level zapcore.Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	// the most specific component applies, e.g. storage.cassandra before storage
+	for name := loggerName; name != ""; {
+		if componentLevel, ok := l.components[name]; ok {
+			return level >= componentLevel
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return level >= l.level
+}
+
+// NewLogger builds a logger of the config filtering its entries by the log levels.
+// The level of the config is ignored.
+func (l *LogLevels) NewLogger(conf zap.Config, options ...zap.Option) (*zap.Logger, error) {
+	conf.Level = l.minLevel
+	return conf.Build(append(options, zap.WrapCore(l.wrapCore))...)
+}
+
+func (l *LogLevels) wrapCore(core zapcore.Core) zapcore.Core {
+	return &componentLevelCore{Core: core, levels: l}
+}
+
+// componentLevelCore filters the entries of a core by the log levels of their loggers.
+type componentLevelCore struct {
+	zapcore.Core
+	levels *LogLevels
+}
+
+func (c *componentLevelCore) Enabled(level zapcore.Level) bool {
+	return c.levels.minLevel.Enabled(level)
+}
+
+func (c *componentLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentLevelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *componentLevelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.enabled(entry.LoggerName, entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// logLevelsPayload is the JSON body of the requests and responses of the log level endpoint.
+type logLevelsPayload struct {
+	Level      *zapcore.Level           `json:"level,omitempty"`
+	Components map[string]zapcore.Level `json:"components,omitempty"`
+}
+
+type logLevelsErrorPayload struct {
+	Error string `json:"error"`
+}
+
+// Handler returns an HTTP handler returning the log levels on GET requests, and changing
+// them on PUT requests with a JSON body such as {"level": "info", "components": {"storage": "debug"}}.
+// The default level is unchanged if omitted from the PUT request, and the levels of the
+// components are unchanged if "components" is omitted, otherwise they are replaced.
+func (l *LogLevels) Handler(logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			if err := l.update(r.Body); err != nil {
+				writeLogLevelsResponse(w, http.StatusBadRequest, logLevelsErrorPayload{Error: err.Error()})
+				return
+			}
+			logger.Info("Log levels changed", zap.Stringer("log-levels", l))
+		default:
+			writeLogLevelsResponse(w, http.StatusMethodNotAllowed, logLevelsErrorPayload{
+				Error: "only GET and PUT are supported",
+			})
+			return
+		}
+		l.mu.RLock()
+		level := l.level
+		payload := logLevelsPayload{Level: &level, Components: l.components}
+		l.mu.RUnlock()
+		writeLogLevelsResponse(w, http.StatusOK, payload)
+	})
+}
+
+func (l *LogLevels) update(body io.Reader) error {
+	var req struct {
+		Level      *zapcore.Level            `json:"level"`
+		Components *map[string]zapcore.Level `json:"components"`
+	}
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return fmt.Errorf("invalid log levels: %w", err)
+	}
+	if req.Level == nil && req.Components == nil {
+		return errors.New("invalid log levels: either level or components must be set")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	level, components := l.level, l.components
+	if req.Level != nil {
+		level = *req.Level
+	}
+	if req.Components != nil {
+		components = make(map[string]zapcore.Level, len(*req.Components))
+		for component, componentLevel := range *req.Components {
+			if component == "" {
+				return errors.New("invalid log levels: empty component name")
+			}
+			components[component] = componentLevel
+		}
+	}
+	l.setLocked(level, components)
+	return nil
+}
+
+func writeLogLevelsResponse(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package flags
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestParseLogLevels(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected string
+		err      string
+	}{
+		{spec: "", expected: "info"},
+		{spec: "debug", expected: "debug"},
+		{spec: "storage=debug, grpc=warn", expected: "info,grpc=warn,storage=debug"},
+		{spec: "error,storage.cassandra=debug", expected: "error,storage.cassandra=debug"},
+		{spec: "verbose", err: `unrecognized level: "verbose"`},
+		{spec: "info,debug", err: "more than one default level"},
+		{spec: "=debug", err: "has no component"},
+		{spec: "storage=verbose", err: "invalid log level of component storage"},
+	}
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			levels, err := ParseLogLevels(test.spec)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, levels.String())
+		})
+	}
+}
+
+func newObservedLogger(t *testing.T, spec string) (*zap.Logger, *observer.ObservedLogs, *LogLevels) {
+	levels, err := ParseLogLevels(spec)
+	require.NoError(t, err)
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(levels.wrapCore(core)), logs, levels
+}
+
+func TestLogLevelsFilterComponents(t *testing.T) {
+	logger, logs, levels := newObservedLogger(t, "info,storage=debug,storage.cassandra=error,grpc=warn")
+
+	logger.Debug("root debug")
+	logger.Info("root info")
+	storage := logger.Named("storage")
+	storage.Debug("storage debug")
+	storage.Named("es").Debug("es debug")
+	cassandra := storage.Named("cassandra")
+	cassandra.Warn("cassandra warn")
+	cassandra.Error("cassandra error")
+	logger.Named("grpc").Info("grpc info")
+	logger.Named("grpc").With(zap.String("k", "v")).Warn("grpc warn")
+	logger.Named("storagefoo").Debug("storagefoo debug")
+
+	var messages []string
+	for _, entry := range logs.TakeAll() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"root info", "storage debug", "es debug", "cassandra error", "grpc warn"}, messages)
+	assert.True(t, logger.Core().Enabled(zapcore.DebugLevel))
+
+	// the levels are changed at runtime
+	levels.set(zapcore.WarnLevel, nil)
+	assert.False(t, logger.Core().Enabled(zapcore.InfoLevel))
+	storage.Info("storage info")
+	storage.Warn("storage warn")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "storage warn", logs.All()[0].Message)
+}
+
+func TestLogLevelsNewLogger(t *testing.T) {
+	levels, err := ParseLogLevels("warn")
+	require.NoError(t, err)
+	conf := zap.NewProductionConfig()
+	conf.OutputPaths = []string{"stdout"}
+	logger, err := levels.NewLogger(conf)
+	require.NoError(t, err)
+	assert.False(t, logger.Core().Enabled(zapcore.InfoLevel))
+	assert.True(t, logger.Core().Enabled(zapcore.WarnLevel))
+
+	sFlags := &SharedFlags{Logging: logging{Level: "storage=debug"}}
+	logger, err = sFlags.NewLogger(conf)
+	require.NoError(t, err)
+	assert.True(t, logger.Named("storage").Core().Enabled(zapcore.DebugLevel))
+
+	sFlags = &SharedFlags{Logging: logging{Level: "verbose"}}
+	_, err = sFlags.NewLogger(conf)
+	require.Error(t, err)
+}
+
+func TestLogLevelsHandler(t *testing.T) {
+	_, _, levels := newObservedLogger(t, "info,storage=debug")
+	handler := levels.Handler(zap.NewNop())
+
+	request := func(method string, body string) (int, map[string]any) {
+		req := httptest.NewRequest(method, logLevelRoute, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+
+	status, resp := request(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]any{"level": "info", "components": map[string]any{"storage": "debug"}}, resp)
+
+	// the components are unchanged when omitted
+	status, resp = request(http.MethodPut, `{"level": "warn"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]any{"level": "warn", "components": map[string]any{"storage": "debug"}}, resp)
+
+	status, resp = request(http.MethodPut, `{"components": {"grpc": "error"}}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]any{"level": "warn", "components": map[string]any{"grpc": "error"}}, resp)
+	assert.Equal(t, "warn,grpc=error", levels.String())
+
+	status, resp = request(http.MethodPut, `{"components": {}}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]any{"level": "warn"}, resp)
+
+	for _, body := range []string{`{"level": "verbose"}`, `{}`, `{"levels": {}}`, `{"components": {"": "debug"}}`} {
+		status, resp = request(http.MethodPut, body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+		assert.Contains(t, resp["error"], "invalid log levels", body)
+	}
+	assert.Equal(t, "warn", levels.String())
+
+	status, resp = request(http.MethodPost, `{"level": "debug"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+	assert.Equal(t, "only GET and PUT are supported", resp["error"])
+}
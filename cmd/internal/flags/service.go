@@ -15,23 +15,39 @@
 package flags
 
 import (
+	"context"
 	"expvar"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	grpcZap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/internal/metrics/metricsbuilder"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/lifecycle"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/ports"
 )
 
+const (
+	shutdownTimeout = "shutdown.timeout"
+
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+// ShutdownTimeout returns the maximum time given to each component of the service to stop on shutdown,
+// unlimited if 0, e.g. to check that the components can drain within this time.
+func ShutdownTimeout(v *viper.Viper) time.Duration {
+	return v.GetDuration(shutdownTimeout)
+}
+
 // Service represents an abstract Jaeger backend component with some basic shared functionality.
 type Service struct {
 	// AdminPort is the HTTP port number for admin server.
@@ -49,6 +65,13 @@ type Service struct {
 	// MetricsFactory is the root factory without a namespace.
 	MetricsFactory metrics.Factory
 
+	// OTLPTelemetry configures the export of the metrics and traces of the service over OTLP.
+	OTLPTelemetry OTLPTelemetry
+
+	// Lifecycle starts the components of the service added to it in the order of their dependencies,
+	// and stops them in the reverse order on shutdown. It is initialized by Start.
+	Lifecycle *lifecycle.Manager
+
 	signalsChannel chan os.Signal
 }
 
@@ -72,7 +95,9 @@ func (s *Service) AddFlags(flagSet *flag.FlagSet) {
 		AddFlags(flagSet)
 	}
 	metricsbuilder.AddFlags(flagSet)
+	addOTLPTelemetryFlags(flagSet)
 	s.Admin.AddFlags(flagSet)
+	flagSet.Duration(shutdownTimeout, defaultShutdownTimeout, "The maximum time given to each component, e.g. a server or the storage, to drain and stop on shutdown")
 }
 
 // Start bootstraps the service and starts the admin server.
@@ -84,9 +109,10 @@ func (s *Service) Start(v *viper.Viper) error {
 	sFlags := new(SharedFlags).InitFromViper(v)
 	newProdConfig := zap.NewProductionConfig()
 	newProdConfig.Sampling = nil
-	if logger, err := sFlags.NewLogger(newProdConfig); err == nil {
+	if logger, logLevels, err := sFlags.NewLoggerWithLevels(newProdConfig); err == nil {
 		s.Logger = logger
-		grpcZap.ReplaceGrpcLoggerV2(logger.WithOptions(
+		s.Admin.logLevels = logLevels
+		grpcZap.ReplaceGrpcLoggerV2(logger.Named(grpcLoggerName).WithOptions(
 			// grpclog is not consistent with the depth of call tree before it's dispatched to zap,
 			// but Skip(2) still shows grpclog as caller, while Skip(3) shows actual grpc packages.
 			zap.AddCallerSkip(3),
@@ -95,13 +121,38 @@ func (s *Service) Start(v *viper.Viper) error {
 		return fmt.Errorf("cannot create logger: %w", err)
 	}
 
+	s.Lifecycle = lifecycle.NewManager(s.Logger, ShutdownTimeout(v))
+
+	if err := s.OTLPTelemetry.initFromViper(v); err != nil {
+		return fmt.Errorf("cannot initialize OTLP telemetry: %w", err)
+	}
 	metricsBuilder := new(metricsbuilder.Builder).InitFromViper(v)
+	// the otel backend pushes its metrics over OTLP itself, the other ones through the Prometheus gatherer
+	otelOTLPExport := s.OTLPTelemetry.Enabled() && metricsBuilder.Backend == metricsbuilder.OTelBackend
+	if otelOTLPExport {
+		opts, err := s.OTLPTelemetry.meterProviderOptions(s.Logger)
+		if err != nil {
+			return fmt.Errorf("cannot start OTLP metrics export: %w", err)
+		}
+		metricsBuilder.MeterProviderOptions = append(metricsBuilder.MeterProviderOptions, opts...)
+	}
 	metricsFactory, err := metricsBuilder.CreateMetricsFactory("")
 	if err != nil {
 		return fmt.Errorf("cannot create metrics factory: %w", err)
 	}
 	s.MetricsFactory = metricsFactory
 
+	if otelOTLPExport {
+		s.OTLPTelemetry.meterProvider = metricsBuilder.MeterProvider()
+	} else if s.OTLPTelemetry.Enabled() {
+		if s.OTLPTelemetry.Temporality == temporalityDelta {
+			s.Logger.Warn("The delta temporality requires the otel metrics backend, the metrics are pushed with the cumulative temporality")
+		}
+		if err := s.OTLPTelemetry.startMetrics(prometheus.DefaultGatherer, s.Logger); err != nil {
+			return fmt.Errorf("cannot start OTLP metrics export: %w", err)
+		}
+	}
+
 	if err = s.Admin.initFromViper(v, s.Logger); err != nil {
 		return fmt.Errorf("cannot initialize admin server: %w", err)
 	}
@@ -130,7 +181,7 @@ func (s *Service) HC() *healthcheck.HealthCheck {
 }
 
 // RunAndThen sets the health check to Ready and blocks until SIGTERM is received.
-// If then runs the shutdown function and exits.
+// If then runs the shutdown function, stops the components of the Lifecycle and exits.
 func (s *Service) RunAndThen(shutdown func()) {
 	s.HC().Ready()
 
@@ -142,6 +193,15 @@ func (s *Service) RunAndThen(shutdown func()) {
 	if shutdown != nil {
 		shutdown()
 	}
+	if s.Lifecycle != nil {
+		// the errors are logged by the lifecycle manager
+		_ = s.Lifecycle.Stop()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), otlpTelemetryShutdownTimeout)
+	if err := s.OTLPTelemetry.shutdown(ctx); err != nil {
+		s.Logger.Error("Failed to stop OTLP telemetry", zap.Error(err))
+	}
+	cancel()
 
 	s.Admin.Close()
 	s.Logger.Info("Shutdown complete")
@@ -14,6 +14,7 @@
 package flags
 
 import (
+	"context"
 	"flag"
 	"os"
 	"reflect"
@@ -26,6 +27,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/lifecycle"
 )
 
 func TestAddFlags(t *testing.T) {
@@ -57,6 +59,26 @@ func TestStartErrors(t *testing.T) {
 			flags:  []string{"--metrics-backend=invalid-metrics-backend"},
 			expErr: "cannot create metrics factory",
 		},
+		{
+			name:   "bad OTLP telemetry headers",
+			flags:  []string{"--otlp-telemetry.headers=invalid"},
+			expErr: "cannot initialize OTLP telemetry",
+		},
+		{
+			name:   "bad OTLP telemetry TLS",
+			flags:  []string{"--otlp-telemetry.endpoint=localhost:4317", "--otlp-telemetry.tls.enabled=true", "--otlp-telemetry.tls.ca=invalid-ca"},
+			expErr: "cannot start OTLP metrics export",
+		},
+		{
+			name:   "bad OTLP telemetry TLS with otel metrics backend",
+			flags:  []string{"--metrics-backend=otel", "--otlp-telemetry.endpoint=localhost:4317", "--otlp-telemetry.tls.enabled=true", "--otlp-telemetry.tls.ca=invalid-ca"},
+			expErr: "cannot start OTLP metrics export",
+		},
+		{
+			name:   "bad OTLP telemetry temporality",
+			flags:  []string{"--otlp-telemetry.temporality=invalid"},
+			expErr: "cannot initialize OTLP telemetry",
+		},
 		{
 			name:   "bad admin TLS",
 			flags:  []string{"--admin.http.tls.enabled=true", "--admin.http.tls.cert=invalid-cert"},
@@ -71,6 +93,10 @@ func TestStartErrors(t *testing.T) {
 			name:  "clean start",
 			flags: []string{},
 		},
+		{
+			name:  "clean start with otel metrics backend",
+			flags: []string{"--metrics-backend=otel"},
+		},
 	}
 	for _, test := range scenarios {
 		t.Run(test.name, func(t *testing.T) {
@@ -102,6 +128,31 @@ func TestStartErrors(t *testing.T) {
 	}
 }
 
+func TestServiceLifecycle(t *testing.T) {
+	s := NewService( /*default port=*/ 0)
+	v, cmd := config.Viperize(s.AddFlags)
+	require.NoError(t, cmd.ParseFlags([]string{"--shutdown.timeout=1s"}))
+	require.NoError(t, s.Start(v))
+
+	var stopped atomic.Int32
+	s.Lifecycle.Add(lifecycle.Component{
+		Name: "server",
+		Stop: func(context.Context) error {
+			// the shutdown function runs first
+			stopped.CompareAndSwap(1, 2)
+			return nil
+		},
+	})
+	require.NoError(t, s.Lifecycle.Start(context.Background()))
+	go s.RunAndThen(func() {
+		stopped.Store(1)
+	})
+	waitForEqual(t, healthcheck.Ready, func() interface{} { return s.HC().Get() })
+
+	s.signalsChannel <- os.Interrupt
+	waitForEqual(t, int32(2), func() interface{} { return stopped.Load() })
+}
+
 func waitForEqual(t *testing.T, expected interface{}, getter func() interface{}) {
 	for i := 0; i < 1000; i++ {
 		value := getter()
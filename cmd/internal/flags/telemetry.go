@@ -0,0 +1,254 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package flags
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/jtracer"
+)
+
+const (
+	otlpTelemetryPrefix      = "otlp-telemetry"
+	otlpTelemetryEndpoint    = otlpTelemetryPrefix + ".endpoint"
+	otlpTelemetryInterval    = otlpTelemetryPrefix + ".interval"
+	otlpTelemetryHeaders     = otlpTelemetryPrefix + ".headers"
+	otlpTelemetryTemporality = otlpTelemetryPrefix + ".temporality"
+
+	defaultOTLPTelemetryInterval = time.Minute
+	// otlpTelemetryShutdownTimeout bounds the push of the last metrics on shutdown
+	otlpTelemetryShutdownTimeout = 5 * time.Second
+)
+
+var tlsOTLPTelemetryFlagsConfig = tlscfg.ClientFlagsConfig{
+	Prefix: otlpTelemetryPrefix,
+}
+
+// OTLPTelemetry configures the export of the internal metrics and traces of a Jaeger
+// component over OTLP/gRPC, in addition to the scraping of the metrics.
+type OTLPTelemetry struct {
+	// Endpoint is the host:port of the OTLP/gRPC receiver, the telemetry is not exported if empty.
+	Endpoint string
+	// Interval is the interval between two exports of the metrics.
+	Interval time.Duration
+	// Headers are the headers sent with the exports, e.g. for authentication.
+	Headers map[string]string
+	// Temporality is the temporality of the sums and histograms, cumulative or delta.
+	Temporality string
+	TLS         tlscfg.Options
+
+	creds         credentials.TransportCredentials
+	meterProvider *sdkmetric.MeterProvider
+}
+
+func addOTLPTelemetryFlags(flagSet *flag.FlagSet) {
+	flagSet.String(
+		otlpTelemetryEndpoint,
+		"",
+		"The host:port of an OTLP/gRPC receiver to push the internal metrics and traces to, disabled if empty")
+	flagSet.Duration(
+		otlpTelemetryInterval,
+		defaultOTLPTelemetryInterval,
+		"The interval between two pushes of the internal metrics over OTLP")
+	flagSet.String(
+		otlpTelemetryHeaders,
+		"",
+		"The headers sent with the internal metrics and traces pushed over OTLP, e.g. 'authorization=Bearer xyz,x-scope-orgid=jaeger'")
+	flagSet.String(
+		otlpTelemetryTemporality,
+		temporalityCumulative,
+		"The temporality of the sums and histograms pushed over OTLP: cumulative or delta; delta requires the otel metrics backend")
+	tlsOTLPTelemetryFlagsConfig.AddFlags(flagSet)
+}
+
+func (t *OTLPTelemetry) initFromViper(v *viper.Viper) error {
+	t.Endpoint = v.GetString(otlpTelemetryEndpoint)
+	t.Interval = v.GetDuration(otlpTelemetryInterval)
+	headers, err := parseOTLPHeaders(v.GetString(otlpTelemetryHeaders))
+	if err != nil {
+		return err
+	}
+	t.Headers = headers
+	t.TLS, err = tlsOTLPTelemetryFlagsConfig.InitFromViper(v)
+	if err != nil {
+		return fmt.Errorf("failed to parse OTLP telemetry TLS options: %w", err)
+	}
+	if t.Enabled() && t.Interval <= 0 {
+		return fmt.Errorf("the %s must be positive", otlpTelemetryInterval)
+	}
+	t.Temporality = v.GetString(otlpTelemetryTemporality)
+	if _, err := temporalitySelector(t.Temporality); err != nil {
+		return err
+	}
+	return nil
+}
+
+const (
+	temporalityCumulative = "cumulative"
+	temporalityDelta      = "delta"
+)
+
+func temporalitySelector(temporality string) (sdkmetric.TemporalitySelector, error) {
+	switch temporality {
+	case temporalityCumulative, "":
+		return sdkmetric.DefaultTemporalitySelector, nil
+	case temporalityDelta:
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			// the up-down counters and gauges are not sums of increments, they stay cumulative
+			switch kind {
+			case sdkmetric.InstrumentKindUpDownCounter, sdkmetric.InstrumentKindObservableUpDownCounter, sdkmetric.InstrumentKindObservableGauge:
+				return metricdata.CumulativeTemporality
+			default:
+				return metricdata.DeltaTemporality
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid %s %q, expected %s or %s", otlpTelemetryTemporality, temporality, temporalityCumulative, temporalityDelta)
+	}
+}
+
+// parseOTLPHeaders parses headers of the form "key1=value1,key2=value2".
+func parseOTLPHeaders(spec string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, header := range strings.Split(spec, ",") {
+		if strings.TrimSpace(header) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(header, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid OTLP telemetry header %q, expected key=value", header)
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// Enabled returns whether the telemetry is exported over OTLP.
+func (t *OTLPTelemetry) Enabled() bool {
+	return t.Endpoint != ""
+}
+
+// NewTracer returns a tracer exporting the spans to the OTLP endpoint if configured,
+// otherwise to the exporter configured by the OTEL_EXPORTER_OTLP_* environment variables.
+func (t *OTLPTelemetry) NewTracer(serviceName string, logger *zap.Logger) (*jtracer.JTracer, error) {
+	if !t.Enabled() {
+		return jtracer.New(serviceName)
+	}
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(t.Endpoint),
+		otlptracegrpc.WithHeaders(t.Headers),
+	}
+	if t.TLS.Enabled {
+		creds, err := t.credentials(logger)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(creds))
+	} else {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OTLP trace exporter: %w", err)
+	}
+	return jtracer.NewWithExporter(serviceName, exporter)
+}
+
+// startMetrics starts pushing the metrics of the gatherer to the OTLP endpoint.
+func (t *OTLPTelemetry) startMetrics(gatherer prometheus.Gatherer, logger *zap.Logger) error {
+	opts, err := t.meterProviderOptions(logger, sdkmetric.WithProducer(newGathererProducer(gatherer)))
+	if err != nil {
+		return err
+	}
+	t.meterProvider = sdkmetric.NewMeterProvider(opts...)
+	return nil
+}
+
+// meterProviderOptions returns the options of a meter provider pushing its metrics to the OTLP endpoint.
+func (t *OTLPTelemetry) meterProviderOptions(logger *zap.Logger, readerOpts ...sdkmetric.PeriodicReaderOption) ([]sdkmetric.Option, error) {
+	temporality, err := temporalitySelector(t.Temporality)
+	if err != nil {
+		return nil, err
+	}
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(t.Endpoint),
+		otlpmetricgrpc.WithHeaders(t.Headers),
+		otlpmetricgrpc.WithTemporalitySelector(temporality),
+	}
+	if t.TLS.Enabled {
+		creds, err := t.credentials(logger)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(creds))
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	ctx := context.Background()
+	exporter, err := otlpmetricgrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OTLP metric exporter: %w", err)
+	}
+	res, err := resource.New(
+		ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(semconv.ServiceNameKey.String(filepath.Base(os.Args[0]))),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the attributes above
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("cannot create OTLP telemetry resource: %w", err), exporter.Shutdown(ctx))
+	}
+	reader := sdkmetric.NewPeriodicReader(
+		exporter,
+		append([]sdkmetric.PeriodicReaderOption{sdkmetric.WithInterval(t.Interval)}, readerOpts...)...,
+	)
+	logger.Info("Pushing the metrics over OTLP",
+		zap.String("endpoint", t.Endpoint), zap.Duration("interval", t.Interval), zap.String("temporality", t.Temporality))
+	return []sdkmetric.Option{sdkmetric.WithReader(reader), sdkmetric.WithResource(res)}, nil
+}
+
+// credentials returns the TLS credentials shared by the metric and trace exporters.
+func (t *OTLPTelemetry) credentials(logger *zap.Logger) (credentials.TransportCredentials, error) {
+	if t.creds != nil {
+		return t.creds, nil
+	}
+	tlsCfg, err := t.TLS.Config(logger)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load OTLP telemetry TLS config: %w", err)
+	}
+	t.creds = credentials.NewTLS(tlsCfg)
+	return t.creds, nil
+}
+
+// shutdown pushes the last metrics and stops pushing them.
+func (t *OTLPTelemetry) shutdown(ctx context.Context) error {
+	var err error
+	if t.meterProvider != nil {
+		err = t.meterProvider.Shutdown(ctx)
+	}
+	return errors.Join(err, t.TLS.Close())
+}
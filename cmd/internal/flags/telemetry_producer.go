@@ -0,0 +1,179 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package flags
+
+import (
+	"context"
+	"encoding/hex"
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// telemetryScope is the instrumentation scope of the metrics exported over OTLP.
+const telemetryScope = "github.com/jaegertracing/jaeger"
+
+// gathererProducer produces the metrics of a Prometheus gatherer for the OpenTelemetry SDK,
+// to export the metrics of the Prometheus backend over OTLP.
+type gathererProducer struct {
+	gatherer  prometheus.Gatherer
+	startTime time.Time
+	timeNow   func() time.Time
+}
+
+func newGathererProducer(gatherer prometheus.Gatherer) *gathererProducer {
+	return &gathererProducer{
+		gatherer:  gatherer,
+		startTime: time.Now(),
+		timeNow:   time.Now,
+	}
+}
+
+// Produce implements metric.Producer.
+func (p *gathererProducer) Produce(context.Context) ([]metricdata.ScopeMetrics, error) {
+	families, err := p.gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return nil, err
+	}
+	now := p.timeNow()
+	scope := metricdata.ScopeMetrics{Scope: instrumentation.Scope{Name: telemetryScope}}
+	for _, family := range families {
+		m := metricdata.Metrics{
+			Name:        family.GetName(),
+			Description: family.GetHelp(),
+		}
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			m.Data = p.sum(family, now)
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			m.Data = p.gauge(family, now)
+		case dto.MetricType_HISTOGRAM:
+			m.Data = p.histogram(family, now)
+		case dto.MetricType_SUMMARY:
+			m.Data = p.summary(family, now)
+		default:
+			continue
+		}
+		scope.Metrics = append(scope.Metrics, m)
+	}
+	// the metrics gathered despite an error are still exported
+	return []metricdata.ScopeMetrics{scope}, err
+}
+
+func (p *gathererProducer) sum(family *dto.MetricFamily, now time.Time) metricdata.Sum[float64] {
+	sum := metricdata.Sum[float64]{Temporality: metricdata.CumulativeTemporality, IsMonotonic: true}
+	for _, m := range family.GetMetric() {
+		point := metricdata.DataPoint[float64]{
+			Attributes: labelsToAttributes(m.GetLabel()),
+			StartTime:  p.startTime,
+			Time:       now,
+			Value:      m.GetCounter().GetValue(),
+		}
+		if e := m.GetCounter().GetExemplar(); e != nil {
+			point.Exemplars = []metricdata.Exemplar[float64]{convertExemplar(e)}
+		}
+		sum.DataPoints = append(sum.DataPoints, point)
+	}
+	return sum
+}
+
+func (*gathererProducer) gauge(family *dto.MetricFamily, now time.Time) metricdata.Gauge[float64] {
+	var gauge metricdata.Gauge[float64]
+	for _, m := range family.GetMetric() {
+		value := m.GetGauge().GetValue()
+		if family.GetType() == dto.MetricType_UNTYPED {
+			value = m.GetUntyped().GetValue()
+		}
+		gauge.DataPoints = append(gauge.DataPoints, metricdata.DataPoint[float64]{
+			Attributes: labelsToAttributes(m.GetLabel()),
+			Time:       now,
+			Value:      value,
+		})
+	}
+	return gauge
+}
+
+func (p *gathererProducer) histogram(family *dto.MetricFamily, now time.Time) metricdata.Histogram[float64] {
+	histogram := metricdata.Histogram[float64]{Temporality: metricdata.CumulativeTemporality}
+	for _, m := range family.GetMetric() {
+		h := m.GetHistogram()
+		point := metricdata.HistogramDataPoint[float64]{
+			Attributes: labelsToAttributes(m.GetLabel()),
+			StartTime:  p.startTime,
+			Time:       now,
+			Count:      h.GetSampleCount(),
+			Sum:        h.GetSampleSum(),
+		}
+		// the Prometheus buckets are cumulative, while the OTLP buckets are not and
+		// have an implicit last bucket for the values above the last bound
+		var cumulativeCount uint64
+		for _, bucket := range h.GetBucket() {
+			if math.IsInf(bucket.GetUpperBound(), 1) {
+				continue
+			}
+			point.Bounds = append(point.Bounds, bucket.GetUpperBound())
+			point.BucketCounts = append(point.BucketCounts, bucket.GetCumulativeCount()-cumulativeCount)
+			cumulativeCount = bucket.GetCumulativeCount()
+			if e := bucket.GetExemplar(); e != nil {
+				point.Exemplars = append(point.Exemplars, convertExemplar(e))
+			}
+		}
+		point.BucketCounts = append(point.BucketCounts, h.GetSampleCount()-cumulativeCount)
+		histogram.DataPoints = append(histogram.DataPoints, point)
+	}
+	return histogram
+}
+
+func (p *gathererProducer) summary(family *dto.MetricFamily, now time.Time) metricdata.Summary {
+	var summary metricdata.Summary
+	for _, m := range family.GetMetric() {
+		s := m.GetSummary()
+		point := metricdata.SummaryDataPoint{
+			Attributes: labelsToAttributes(m.GetLabel()),
+			StartTime:  p.startTime,
+			Time:       now,
+			Count:      s.GetSampleCount(),
+			Sum:        s.GetSampleSum(),
+		}
+		for _, q := range s.GetQuantile() {
+			point.QuantileValues = append(point.QuantileValues, metricdata.QuantileValue{
+				Quantile: q.GetQuantile(),
+				Value:    q.GetValue(),
+			})
+		}
+		summary.DataPoints = append(summary.DataPoints, point)
+	}
+	return summary
+}
+
+func labelsToAttributes(labels []*dto.LabelPair) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, len(labels))
+	for _, label := range labels {
+		kvs = append(kvs, attribute.String(label.GetName(), label.GetValue()))
+	}
+	return attribute.NewSet(kvs...)
+}
+
+// convertExemplar converts a Prometheus exemplar, moving its trace ID label to the trace ID of the exemplar.
+func convertExemplar(e *dto.Exemplar) metricdata.Exemplar[float64] {
+	exemplar := metricdata.Exemplar[float64]{
+		Value: e.GetValue(),
+		Time:  e.GetTimestamp().AsTime(),
+	}
+	for _, label := range e.GetLabel() {
+		if label.GetName() == "trace_id" {
+			if traceID, err := hex.DecodeString(label.GetValue()); err == nil {
+				exemplar.TraceID = traceID
+				continue
+			}
+		}
+		exemplar.FilteredAttributes = append(exemplar.FilteredAttributes, attribute.String(label.GetName(), label.GetValue()))
+	}
+	return exemplar
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package flags
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jaegertracing/jaeger/internal/metrics/metricsbuilder"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+type fakeOTLPServer struct {
+	pmetricotlp.UnimplementedGRPCServer
+	lock    sync.Mutex
+	metrics []pmetric.Metrics
+	headers []string
+}
+
+func (s *fakeOTLPServer) Export(ctx context.Context, req pmetricotlp.ExportRequest) (pmetricotlp.ExportResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.metrics = append(s.metrics, req.Metrics())
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.headers = append(s.headers, md.Get("x-scope-orgid")...)
+	return pmetricotlp.NewExportResponse(), nil
+}
+
+type fakeOTLPTraceServer struct {
+	ptraceotlp.UnimplementedGRPCServer
+	lock   sync.Mutex
+	traces []ptrace.Traces
+}
+
+func (s *fakeOTLPTraceServer) Export(_ context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.traces = append(s.traces, req.Traces())
+	return ptraceotlp.NewExportResponse(), nil
+}
+
+func startOTLPServer(t *testing.T) (*fakeOTLPServer, *fakeOTLPTraceServer, string) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	fake := &fakeOTLPServer{}
+	pmetricotlp.RegisterGRPCServer(server, fake)
+	fakeTraces := &fakeOTLPTraceServer{}
+	ptraceotlp.RegisterGRPCServer(server, fakeTraces)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return fake, fakeTraces, listener.Addr().String()
+}
+
+func TestOTLPTelemetryFromViper(t *testing.T) {
+	v, command := config.Viperize(addOTLPTelemetryFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--otlp-telemetry.endpoint=otel-collector:4317",
+		"--otlp-telemetry.interval=10s",
+		"--otlp-telemetry.headers=authorization=Bearer xyz, x-scope-orgid = jaeger",
+		"--otlp-telemetry.temporality=delta",
+	}))
+	var telemetry OTLPTelemetry
+	require.NoError(t, telemetry.initFromViper(v))
+	assert.True(t, telemetry.Enabled())
+	assert.Equal(t, "otel-collector:4317", telemetry.Endpoint)
+	assert.Equal(t, 10*time.Second, telemetry.Interval)
+	assert.Equal(t, map[string]string{"authorization": "Bearer xyz", "x-scope-orgid": "jaeger"}, telemetry.Headers)
+	assert.Equal(t, "delta", telemetry.Temporality)
+
+	for _, flags := range [][]string{
+		{"--otlp-telemetry.headers=authorization"},
+		{"--otlp-telemetry.headers==value"},
+		{"--otlp-telemetry.endpoint=otel-collector:4317", "--otlp-telemetry.interval=0s"},
+		{"--otlp-telemetry.tls.enabled=false", "--otlp-telemetry.tls.ca=ca.crt"},
+		{"--otlp-telemetry.temporality=instant"},
+	} {
+		v, command := config.Viperize(addOTLPTelemetryFlags)
+		require.NoError(t, command.ParseFlags(flags))
+		require.Error(t, new(OTLPTelemetry).initFromViper(v), flags)
+	}
+}
+
+func TestOTLPTelemetryExport(t *testing.T) {
+	server, traceServer, addr := startOTLPServer(t)
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "jaeger_spans_total", Help: "spans"})
+	registry.MustRegister(counter)
+	counter.Add(3)
+
+	telemetry := OTLPTelemetry{
+		Endpoint: addr,
+		Interval: time.Hour,
+		Headers:  map[string]string{"x-scope-orgid": "jaeger"},
+	}
+	require.NoError(t, telemetry.startMetrics(registry, zap.NewNop()))
+	tracer, err := telemetry.NewTracer("jaeger-test", zap.NewNop())
+	require.NoError(t, err)
+	_, span := tracer.OTEL.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+	require.NoError(t, tracer.Close(context.Background()))
+	// the metrics are pushed on shutdown
+	require.NoError(t, telemetry.shutdown(context.Background()))
+
+	require.Len(t, server.metrics, 1)
+	assert.Equal(t, []string{"jaeger"}, server.headers)
+	rm := server.metrics[0].ResourceMetrics().At(0)
+	_, ok := rm.Resource().Attributes().Get("service.name")
+	assert.True(t, ok)
+	m := rm.ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "jaeger_spans_total", m.Name())
+	assert.Equal(t, "spans", m.Description())
+	assert.InDelta(t, 3.0, m.Sum().DataPoints().At(0).DoubleValue(), 0.01)
+
+	require.Len(t, traceServer.traces, 1)
+	assert.Equal(t, 1, traceServer.traces[0].SpanCount())
+}
+
+func TestOTLPTelemetryExportOTelBackend(t *testing.T) {
+	server, _, addr := startOTLPServer(t)
+	telemetry := OTLPTelemetry{
+		Endpoint:    addr,
+		Interval:    time.Hour,
+		Temporality: temporalityDelta,
+	}
+	builder := &metricsbuilder.Builder{Backend: metricsbuilder.OTelBackend}
+	opts, err := telemetry.meterProviderOptions(zap.NewNop())
+	require.NoError(t, err)
+	builder.MeterProviderOptions = opts
+	metricsFactory, err := builder.CreateMetricsFactory("jaeger_otlp_test")
+	require.NoError(t, err)
+	telemetry.meterProvider = builder.MeterProvider()
+	metricsFactory.Counter(metrics.Options{Name: "spans", Help: "spans"}).Inc(3)
+	metricsFactory.Gauge(metrics.Options{Name: "queue"}).Update(5)
+	require.NoError(t, telemetry.shutdown(context.Background()))
+
+	require.Len(t, server.metrics, 1)
+	sm := server.metrics[0].ResourceMetrics().At(0).ScopeMetrics().At(0)
+	exported := make(map[string]pmetric.Metric)
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		exported[sm.Metrics().At(i).Name()] = sm.Metrics().At(i)
+	}
+	require.Contains(t, exported, "jaeger_otlp_test.spans")
+	sum := exported["jaeger_otlp_test.spans"].Sum()
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, sum.AggregationTemporality())
+	assert.Equal(t, int64(3), sum.DataPoints().At(0).IntValue())
+	require.Contains(t, exported, "jaeger_otlp_test.queue")
+	assert.Equal(t, int64(5), exported["jaeger_otlp_test.queue"].Gauge().DataPoints().At(0).IntValue())
+}
+
+func TestTemporalitySelector(t *testing.T) {
+	selector, err := temporalitySelector(temporalityCumulative)
+	require.NoError(t, err)
+	assert.Equal(t, metricdata.CumulativeTemporality, selector(sdkmetric.InstrumentKindCounter))
+
+	selector, err = temporalitySelector(temporalityDelta)
+	require.NoError(t, err)
+	assert.Equal(t, metricdata.DeltaTemporality, selector(sdkmetric.InstrumentKindCounter))
+	assert.Equal(t, metricdata.DeltaTemporality, selector(sdkmetric.InstrumentKindHistogram))
+	assert.Equal(t, metricdata.CumulativeTemporality, selector(sdkmetric.InstrumentKindUpDownCounter))
+	assert.Equal(t, metricdata.CumulativeTemporality, selector(sdkmetric.InstrumentKindObservableGauge))
+
+	_, err = temporalitySelector("instant")
+	require.EqualError(t, err, `invalid otlp-telemetry.temporality "instant", expected cumulative or delta`)
+}
+
+func TestOTLPTelemetryTracerFromEnv(t *testing.T) {
+	var telemetry OTLPTelemetry
+	tracer, err := telemetry.NewTracer("jaeger-test", zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, tracer.Close(context.Background()))
+	require.NoError(t, telemetry.shutdown(context.Background()))
+}
+
+func TestOTLPTelemetryTLSError(t *testing.T) {
+	telemetry := OTLPTelemetry{Endpoint: "localhost:4317", Interval: time.Minute}
+	telemetry.TLS.Enabled = true
+	telemetry.TLS.CAPath = "invalid-ca.crt"
+	require.ErrorContains(t, telemetry.startMetrics(prometheus.NewRegistry(), zap.NewNop()), "TLS config")
+	_, err := telemetry.NewTracer("jaeger-test", zap.NewNop())
+	require.ErrorContains(t, err, "TLS config")
+}
+
+func TestGathererProducer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests_total"}, []string{"result"})
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "queue_length"})
+	untyped := prometheus.NewUntypedFunc(prometheus.UntypedOpts{Name: "untyped"}, func() float64 { return 7 })
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency", Buckets: []float64{1, 2}})
+	summary := prometheus.NewSummary(prometheus.SummaryOpts{Name: "sizes", Objectives: map[float64]float64{0.5: 0.05}})
+	registry.MustRegister(counter, gauge, untyped, histogram, summary)
+
+	counter.WithLabelValues("ok").(prometheus.ExemplarAdder).AddWithExemplar(2, prometheus.Labels{
+		"trace_id": "0102030405060708090a0b0c0d0e0f10",
+	})
+	gauge.Set(5)
+	histogram.(prometheus.ExemplarObserver).ObserveWithExemplar(0.5, prometheus.Labels{
+		"trace_id": "not-hex", "span": "x",
+	})
+	histogram.Observe(1.5)
+	histogram.Observe(3)
+	summary.Observe(10)
+
+	now := time.Unix(100, 0)
+	producer := newGathererProducer(registry)
+	producer.timeNow = func() time.Time { return now }
+	scopes, err := producer.Produce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, scopes, 1)
+	assert.Equal(t, telemetryScope, scopes[0].Scope.Name)
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range scopes[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+	require.Len(t, metrics, 5)
+
+	sum := metrics["requests_total"].(metricdata.Sum[float64])
+	assert.True(t, sum.IsMonotonic)
+	require.Len(t, sum.DataPoints, 1)
+	assert.InDelta(t, 2.0, sum.DataPoints[0].Value, 0.01)
+	assert.Equal(t, now, sum.DataPoints[0].Time)
+	result, ok := sum.DataPoints[0].Attributes.Value("result")
+	require.True(t, ok)
+	assert.Equal(t, "ok", result.AsString())
+	require.Len(t, sum.DataPoints[0].Exemplars, 1)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, sum.DataPoints[0].Exemplars[0].TraceID)
+
+	assert.InDelta(t, 5.0, metrics["queue_length"].(metricdata.Gauge[float64]).DataPoints[0].Value, 0.01)
+	assert.InDelta(t, 7.0, metrics["untyped"].(metricdata.Gauge[float64]).DataPoints[0].Value, 0.01)
+
+	h := metrics["latency"].(metricdata.Histogram[float64]).DataPoints[0]
+	assert.Equal(t, uint64(3), h.Count)
+	assert.InDelta(t, 5.0, h.Sum, 0.01)
+	assert.Equal(t, []float64{1, 2}, h.Bounds)
+	assert.Equal(t, []uint64{1, 1, 1}, h.BucketCounts)
+	require.Len(t, h.Exemplars, 1)
+	assert.Nil(t, h.Exemplars[0].TraceID)
+	assert.Len(t, h.Exemplars[0].FilteredAttributes, 2)
+
+	s := metrics["sizes"].(metricdata.Summary).DataPoints[0]
+	assert.Equal(t, uint64(1), s.Count)
+	assert.Equal(t, []metricdata.QuantileValue{{Quantile: 0.5, Value: 10}}, s.QuantileValues)
+}
@@ -97,10 +97,14 @@ func TestAllFlag(t *testing.T) {
 | test-remote.server                              default       |
 | test.tls.ca                                     default       |
 | test.tls.cert                                   default       |
+| test.tls.crl                                    default       |
 | test.tls.enabled               false            default       |
 | test.tls.key                                    default       |
+| test.tls.ocsp-require-staple   false            default       |
 | test.tls.server-name                            default       |
 | test.tls.skip-host-verify      false            default       |
+| test.tls.spiffe-ids                             default       |
+| test.tls.spiffe-socket-path                     default       |
 -----------------------------------------------------------------
 `
 
@@ -120,6 +124,7 @@ func TestPrintConfigCommand(t *testing.T) {
 | test-plugin.log-level          debug            user-assigned |
 | test-remote.connection-timeout 5s               default       |
 | test.tls.enabled               false            default       |
+| test.tls.ocsp-require-staple   false            default       |
 | test.tls.skip-host-verify      false            default       |
 -----------------------------------------------------------------
 `
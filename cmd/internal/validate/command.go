@@ -0,0 +1,128 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/internal/flags"
+)
+
+// deprecatedPrefix starts the help of the deprecated flags, e.g. "(deprecated, will be removed after 2024-03-01)".
+const deprecatedPrefix = "(deprecated"
+
+// Check initializes the components of a binary from the configuration without connecting to
+// their backends, and returns the errors of the invalid options.
+type Check func(v *viper.Viper, logger *zap.Logger) error
+
+// problem is an invalid or deprecated option.
+type problem struct {
+	key     string
+	source  string
+	message string
+}
+
+// Command creates the validate command reporting the invalid and deprecated options of the binary.
+// The command accepts the flags of the binary, e.g. jaeger-collector validate --config-file=config.yaml.
+func Command(v *viper.Viper, checks ...Check) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration options without starting the binary",
+		Long: "Validate the configuration options given by the flags, the environment variables and the config file. " +
+			"The components are initialized without connecting to the storage backends, and every invalid or deprecated " +
+			"option is reported with its source. The command exits with an error if any option is invalid.",
+		DisableFlagParsing: true,
+		SilenceUsage:       true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			binaryFlags := cmd.Root().Flags()
+			if err := binaryFlags.Parse(args); err != nil {
+				if errors.Is(err, pflag.ErrHelp) {
+					return cmd.Help()
+				}
+				return err
+			}
+			return validate(cmd, v, binaryFlags, checks)
+		},
+	}
+}
+
+func validate(cmd *cobra.Command, v *viper.Viper, binaryFlags *pflag.FlagSet, checks []Check) error {
+	var invalid, deprecated []problem
+	if err := flags.TryLoadConfigFile(v); err != nil {
+		invalid = append(invalid, problem{key: "config-file", source: source(v, binaryFlags.Lookup("config-file")), message: err.Error()})
+	}
+	binaryFlags.VisitAll(func(f *pflag.Flag) {
+		src := source(v, f)
+		if src == "" {
+			return
+		}
+		// the deprecated flags pointing to their environment variables, e.g. span-storage.type, are only deprecated as flags
+		if strings.HasPrefix(f.Usage, deprecatedPrefix) && (f.Changed || !strings.Contains(f.Usage, envName(f.Name))) {
+			deprecated = append(deprecated, problem{key: f.Name, source: src, message: f.Usage})
+		}
+		// the values of the flags are parsed by the flag set, but viper does not check the environment variables and config files
+		if !f.Changed {
+			if err := f.Value.Set(v.GetString(f.Name)); err != nil {
+				invalid = append(invalid, problem{key: f.Name, source: src, message: err.Error()})
+			}
+		}
+	})
+	// the components would be initialized from the zero values of the invalid options
+	if len(invalid) == 0 {
+		logger := zap.NewNop()
+		for _, check := range checks {
+			if err := check(v, logger); err != nil {
+				invalid = append(invalid, problem{message: err.Error()})
+			}
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	for _, p := range deprecated {
+		fmt.Fprintf(out, "DEPRECATED %s (%s): %s\n", p.key, p.source, p.message)
+	}
+	for _, p := range invalid {
+		if p.key == "" {
+			fmt.Fprintf(out, "INVALID: %s\n", p.message)
+		} else {
+			fmt.Fprintf(out, "INVALID %s (%s): %s\n", p.key, p.source, p.message)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("found %d invalid configuration option(s)", len(invalid))
+	}
+	fmt.Fprintln(out, "The configuration is valid")
+	return nil
+}
+
+// source returns where the value of the flag comes from, or an empty string for the default values.
+func source(v *viper.Viper, f *pflag.Flag) string {
+	switch {
+	case f == nil:
+		return ""
+	case f.Changed:
+		return "flag --" + f.Name
+	}
+	env := envName(f.Name)
+	if value, ok := os.LookupEnv(env); ok && value != "" {
+		return "environment variable " + env
+	}
+	if v.InConfig(f.Name) {
+		return "config file " + v.GetString("config-file")
+	}
+	return ""
+}
+
+// envName returns the environment variable of the flag, as replaced by viper.
+func envName(flagName string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(flagName))
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/internal/flags"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
+)
+
+func addFlags(flagSet *flag.FlagSet) {
+	flags.AddConfigFileFlag(flagSet)
+	flagSet.Int("test.queue-size", 100, "The size of the queue")
+	flagSet.Duration("test.timeout", time.Second, "The timeout")
+	flagSet.String("test.plugin-binary", "", "(deprecated, will be removed after 2024-03-01) The plugin binary")
+	flagSet.String("test.storage-type", "", "(deprecated) please use TEST_STORAGE_TYPE environment variable")
+}
+
+func runValidate(check Check, args ...string) (string, error) {
+	v := viper.New()
+	root := &cobra.Command{Use: "test"}
+	config.AddFlags(v, root, addFlags)
+	root.AddCommand(Command(v, check))
+	out := new(bytes.Buffer)
+	root.SetOut(out)
+	root.SetErr(out)
+	root.SetArgs(append([]string{"validate"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func noopCheck(*viper.Viper, *zap.Logger) error {
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	var queueSize int
+	out, err := runValidate(func(v *viper.Viper, _ *zap.Logger) error {
+		queueSize = v.GetInt("test.queue-size")
+		return nil
+	}, "--test.queue-size=200")
+	require.NoError(t, err)
+	assert.Equal(t, "The configuration is valid\n", out)
+	assert.Equal(t, 200, queueSize)
+}
+
+func TestValidateDeprecated(t *testing.T) {
+	t.Setenv("TEST_PLUGIN_BINARY", "plugin")
+	out, err := runValidate(noopCheck)
+	require.NoError(t, err)
+	assert.Contains(t, out, "DEPRECATED test.plugin-binary (environment variable TEST_PLUGIN_BINARY): (deprecated, will be removed after 2024-03-01)")
+	assert.Contains(t, out, "The configuration is valid")
+}
+
+func TestValidateDeprecatedFlagOfEnvVar(t *testing.T) {
+	t.Setenv("TEST_STORAGE_TYPE", "memory")
+	out, err := runValidate(noopCheck)
+	require.NoError(t, err)
+	assert.Equal(t, "The configuration is valid\n", out)
+
+	out, err = runValidate(noopCheck, "--test.storage-type=memory")
+	require.NoError(t, err)
+	assert.Contains(t, out, "DEPRECATED test.storage-type (flag --test.storage-type)")
+}
+
+func TestValidateInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("test:\n  timeout: forever\n"), 0o600))
+	t.Setenv("TEST_QUEUE_SIZE", "large")
+	called := false
+	out, err := runValidate(func(*viper.Viper, *zap.Logger) error {
+		called = true
+		return nil
+	}, "--config-file="+file)
+	require.EqualError(t, err, "found 2 invalid configuration option(s)")
+	assert.Contains(t, out, "INVALID test.queue-size (environment variable TEST_QUEUE_SIZE):")
+	assert.Contains(t, out, "INVALID test.timeout (config file "+file+"):")
+	assert.False(t, called, "the components are not initialized from the invalid options")
+}
+
+func TestValidateCheckError(t *testing.T) {
+	out, err := runValidate(func(*viper.Viper, *zap.Logger) error {
+		return errors.New("the storage type is unknown")
+	})
+	require.Error(t, err)
+	assert.Contains(t, out, "INVALID: the storage type is unknown\n")
+}
+
+func TestValidateConfigFileError(t *testing.T) {
+	out, err := runValidate(noopCheck, "--config-file=invalid-file-name.yaml")
+	require.Error(t, err)
+	assert.Contains(t, out, "INVALID config-file (flag --config-file): cannot load config file invalid-file-name.yaml")
+}
+
+func TestValidateFlagErrors(t *testing.T) {
+	_, err := runValidate(noopCheck, "--unknown-flag")
+	require.ErrorContains(t, err, "unknown flag: --unknown-flag")
+
+	out, err := runValidate(noopCheck, "--help")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Validate the configuration options")
+}
+
+func TestMain(m *testing.M) {
+	testutils.VerifyGoLeaks(m)
+}
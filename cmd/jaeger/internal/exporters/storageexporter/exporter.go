@@ -5,10 +5,8 @@ package storageexporter
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
-	otlp2jaeger "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
@@ -49,18 +47,5 @@ func (exp *storageExporter) close(_ context.Context) error {
 }
 
 func (exp *storageExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
-	batches, err := otlp2jaeger.ProtoFromTraces(td)
-	if err != nil {
-		return fmt.Errorf("cannot transform OTLP traces to Jaeger format: %w", err)
-	}
-	var errs []error
-	for _, batch := range batches {
-		for _, span := range batch.Spans {
-			if span.Process == nil {
-				span.Process = batch.Process
-			}
-			errs = append(errs, exp.spanWriter.WriteSpan(ctx, span))
-		}
-	}
-	return errors.Join(errs...)
+	return spanstore.WriteTraces(ctx, exp.spanWriter, td)
 }
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"time"
 
-	jaeger2otlp "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
@@ -16,6 +15,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/jaeger/internal/extension/jaegerstorage"
 	"github.com/jaegertracing/jaeger/model"
+	jaeger2otlp "github.com/jaegertracing/jaeger/model/converter/otlp"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
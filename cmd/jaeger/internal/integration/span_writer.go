@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 
-	jaeger2otlp "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter"
@@ -17,6 +16,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/model"
+	jaeger2otlp "github.com/jaegertracing/jaeger/model/converter/otlp"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/gogo/protobuf/types"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -28,6 +29,11 @@ import (
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
+// maxSpanCountInChunk is the maximum number of spans sent in a single message of the GetTrace
+// stream. Large traces are split into multiple messages, and are streamed directly from
+// the storage if it implements spanstore.StreamingReader.
+const maxSpanCountInChunk = 1000
+
 // Handler implements api_v3.QueryServiceServer
 type Handler struct {
 	QueryService *querysvc.QueryService
@@ -43,16 +49,19 @@ func (h *Handler) GetTrace(request *api_v3.GetTraceRequest, stream api_v3.QueryS
 		return fmt.Errorf("malform trace ID: %w", err)
 	}
 
-	trace, err := h.QueryService.GetTrace(stream.Context(), traceID)
-	if err != nil {
-		return fmt.Errorf("cannot retrieve trace: %w", err)
+	var sendErr error
+	err = h.QueryService.StreamTraceOTLP(stream.Context(), traceID, maxSpanCountInChunk, func(td ptrace.Traces) error {
+		tracesData := api_v3.TracesData(td)
+		sendErr = stream.Send(&tracesData)
+		return sendErr
+	})
+	if sendErr != nil {
+		return sendErr
 	}
-	td, err := modelToOTLP(trace.GetSpans())
 	if err != nil {
-		return err
+		return fmt.Errorf("cannot retrieve trace: %w", err)
 	}
-	tracesData := api_v3.TracesData(td)
-	return stream.Send(&tracesData)
+	return nil
 }
 
 // FindTraces implements api_v3.QueryServiceServer's FindTraces
@@ -101,17 +110,15 @@ func (h *Handler) FindTraces(request *api_v3.FindTracesRequest, stream api_v3.Qu
 		queryParams.DurationMax = durationMax
 	}
 
-	traces, err := h.QueryService.FindTraces(stream.Context(), queryParams)
+	traces, err := h.QueryService.FindTracesOTLP(stream.Context(), queryParams)
 	if err != nil {
 		return err
 	}
-	for _, t := range traces {
-		td, err := modelToOTLP(t.GetSpans())
-		if err != nil {
+	for _, td := range traces {
+		tracesData := api_v3.TracesData(td)
+		if err := stream.Send(&tracesData); err != nil {
 			return err
 		}
-		tracesData := api_v3.TracesData(td)
-		stream.Send(&tracesData)
 	}
 	return nil
 }
@@ -17,6 +17,7 @@ package apiv3
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"testing"
 
@@ -113,6 +114,30 @@ func TestGetTrace(t *testing.T) {
 		td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
 }
 
+func TestGetTraceInChunks(t *testing.T) {
+	tsc := newTestServerClient(t)
+	trace := &model.Trace{}
+	for i := 0; i < maxSpanCountInChunk+1; i++ {
+		trace.Spans = append(trace.Spans, &model.Span{OperationName: "foobar", SpanID: model.NewSpanID(uint64(i + 1))})
+	}
+	tsc.reader.On("GetTrace", matchContext, matchTraceID).Return(trace, nil).Once()
+
+	getTraceStream, err := tsc.client.GetTrace(context.Background(),
+		&api_v3.GetTraceRequest{
+			TraceId: "156",
+		},
+	)
+	require.NoError(t, err)
+	recv, err := getTraceStream.Recv()
+	require.NoError(t, err)
+	assert.EqualValues(t, maxSpanCountInChunk, recv.ToTraces().SpanCount())
+	recv, err = getTraceStream.Recv()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, recv.ToTraces().SpanCount())
+	_, err = getTraceStream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+}
+
 func TestGetTraceStorageError(t *testing.T) {
 	tsc := newTestServerClient(t)
 	tsc.reader.On("GetTrace", matchContext, matchTraceID).Return(
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
@@ -36,6 +37,7 @@ const (
 	paramNumTraces     = "query.num_traces"
 	paramDurationMin   = "query.duration_min"
 	paramDurationMax   = "query.duration_max"
+	paramAttributes    = "query.attributes"
 
 	routeGetTrace      = "/api/v3/traces/{" + paramTraceID + "}"
 	routeFindTraces    = "/api/v3/traces"
@@ -110,20 +112,7 @@ func (h *HTTPGateway) tryParamError(w http.ResponseWriter, err error, paramName
 	return h.tryHandleError(w, fmt.Errorf("malformed parameter %s: %w", paramName, err), http.StatusBadRequest)
 }
 
-func (h *HTTPGateway) returnSpans(spans []*model.Span, w http.ResponseWriter) {
-	// modelToOTLP does not easily return an error, so allow mocking it
-	h.returnSpansTestable(spans, w, modelToOTLP)
-}
-
-func (h *HTTPGateway) returnSpansTestable(
-	spans []*model.Span,
-	w http.ResponseWriter,
-	modelToOTLP func(spans []*model.Span) (ptrace.Traces, error),
-) {
-	td, err := modelToOTLP(spans)
-	if h.tryHandleError(w, err, http.StatusInternalServerError) {
-		return
-	}
+func (h *HTTPGateway) returnTraces(td ptrace.Traces, w http.ResponseWriter) {
 	tracesData := api_v3.TracesData(td)
 	response := &api_v3.GRPCGatewayWrapper{
 		Result: &tracesData,
@@ -142,11 +131,11 @@ func (h *HTTPGateway) getTrace(w http.ResponseWriter, r *http.Request) {
 	if h.tryParamError(w, err, paramTraceID) {
 		return
 	}
-	trace, err := h.QueryService.GetTrace(r.Context(), traceID)
+	td, err := h.QueryService.GetTraceOTLP(r.Context(), traceID)
 	if h.tryHandleError(w, err, http.StatusInternalServerError) {
 		return
 	}
-	h.returnSpans(trace.Spans, w)
+	h.returnTraces(td, w)
 }
 
 func (h *HTTPGateway) findTraces(w http.ResponseWriter, r *http.Request) {
@@ -155,24 +144,29 @@ func (h *HTTPGateway) findTraces(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	traces, err := h.QueryService.FindTraces(r.Context(), queryParams)
+	traces, err := h.QueryService.FindTracesOTLP(r.Context(), queryParams)
 	// TODO how do we distinguish internal error from bad parameters for FindTrace?
 	if h.tryHandleError(w, err, http.StatusInternalServerError) {
 		return
 	}
-	var spans []*model.Span
+	// unlike the gRPC stream, the HTTP response holds all the traces in a single message
+	td := ptrace.NewTraces()
 	for _, trace := range traces {
-		spans = append(spans, trace.Spans...)
+		trace.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
 	}
-	h.returnSpans(spans, w)
+	h.returnTraces(td, w)
 }
 
 func (h *HTTPGateway) parseFindTracesQuery(q url.Values, w http.ResponseWriter) (*spanstore.TraceQueryParameters, bool) {
 	queryParams := &spanstore.TraceQueryParameters{
 		ServiceName:   q.Get(paramServiceName),
 		OperationName: q.Get(paramOperationName),
-		Tags:          nil, // most curiously not supported by grpc-gateway
 	}
+	tags, err := parseAttributes(q)
+	if h.tryParamError(w, err, paramAttributes) {
+		return nil, true
+	}
+	queryParams.Tags = tags
 
 	timeMin := q.Get(paramTimeMin)
 	timeMax := q.Get(paramTimeMax)
@@ -217,6 +211,30 @@ func (h *HTTPGateway) parseFindTracesQuery(q url.Values, w http.ResponseWriter)
 	return queryParams, false
 }
 
+// parseAttributes parses the attributes of the spans to find, passed like the map
+// fields by grpc-gateway, i.e. query.attributes[http.method]=GET.
+func parseAttributes(q url.Values) (map[string]string, error) {
+	var tags map[string]string
+	for param, values := range q {
+		key, ok := strings.CutPrefix(param, paramAttributes)
+		if !ok {
+			continue
+		}
+		key, ok = strings.CutPrefix(key, "[")
+		if ok {
+			key, ok = strings.CutSuffix(key, "]")
+		}
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expecting %s[key]=value, got %s", paramAttributes, param)
+		}
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[key] = values[0]
+	}
+	return tags, nil
+}
+
 func (h *HTTPGateway) getServices(w http.ResponseWriter, r *http.Request) {
 	services, err := h.QueryService.GetServices(r.Context())
 	if h.tryHandleError(w, err, http.StatusInternalServerError) {
@@ -15,7 +15,6 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
@@ -110,20 +109,6 @@ func TestHTTPGatewayTryHandleError(t *testing.T) {
 	assert.Contains(t, string(w.Body.String()), e, "writes error message to body")
 }
 
-func TestHTTPGatewayOTLPError(t *testing.T) {
-	w := httptest.NewRecorder()
-	gw := &HTTPGateway{
-		Logger: zap.NewNop(),
-	}
-	const simErr = "simulated error"
-	gw.returnSpansTestable(nil, w,
-		func(spans []*model.Span) (ptrace.Traces, error) {
-			return ptrace.Traces{}, fmt.Errorf(simErr)
-		},
-	)
-	assert.Contains(t, w.Body.String(), simErr)
-}
-
 func TestHTTPGatewayGetTraceErrors(t *testing.T) {
 	gw := setupHTTPGatewayNoServer(t, "", tenancy.Options{})
 
@@ -161,6 +146,7 @@ func mockFindQueries() (url.Values, *spanstore.TraceQueryParameters) {
 	q.Set(paramDurationMin, "1s")
 	q.Set(paramDurationMax, "2s")
 	q.Set(paramNumTraces, "10")
+	q.Set(paramAttributes+"[http.method]", "GET")
 
 	return q, &spanstore.TraceQueryParameters{
 		ServiceName:   "foo",
@@ -170,6 +156,7 @@ func mockFindQueries() (url.Values, *spanstore.TraceQueryParameters) {
 		DurationMin:   1 * time.Second,
 		DurationMax:   2 * time.Second,
 		NumTraces:     10,
+		Tags:          map[string]string{"http.method": "GET"},
 	}
 }
 
@@ -221,6 +208,16 @@ func TestHTTPGatewayFindTracesErrors(t *testing.T) {
 			params: map[string]string{paramTimeMin: goodTime, paramTimeMax: goodTime, paramDurationMax: "NaN"},
 			expErr: paramDurationMax,
 		},
+		{
+			name:   "bad attributes",
+			params: map[string]string{paramTimeMin: goodTime, paramTimeMax: goodTime, paramAttributes: "http.method=GET"},
+			expErr: paramAttributes,
+		},
+		{
+			name:   "empty attribute key",
+			params: map[string]string{paramTimeMin: goodTime, paramTimeMax: goodTime, paramAttributes + "[]": "GET"},
+			expErr: paramAttributes,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
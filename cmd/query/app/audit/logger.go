@@ -0,0 +1,128 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package audit records who accessed which traces through the query APIs, as structured
+// records written to a file or exported as OTLP logs.
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const redacted = "[REDACTED]"
+
+// Record is an access to the query APIs.
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Principal is the name of the user, empty if the user is unknown.
+	Principal string `json:"principal,omitempty"`
+	Tenant    string `json:"tenant,omitempty"`
+	// Endpoint is the route of the HTTP request or the full method of the gRPC call.
+	Endpoint string   `json:"endpoint"`
+	TraceIDs []string `json:"trace_ids,omitempty"`
+	// Params are the search parameters of the request.
+	Params map[string][]string `json:"params,omitempty"`
+}
+
+type exporter interface {
+	io.Closer
+	export(record *Record)
+}
+
+// Logger records the accesses to the query APIs, after sampling and redacting them.
+type Logger struct {
+	options   Options
+	logger    *zap.Logger
+	sample    func() bool
+	exporters []exporter
+}
+
+// NewLogger creates a Logger writing the records to the file and exporting them to the OTLP endpoint of the options.
+func NewLogger(options Options, logger *zap.Logger) (*Logger, error) {
+	l := &Logger{
+		options: options,
+		logger:  logger,
+		sample: func() bool {
+			return options.SamplingRate >= 1 || rand.Float64() < options.SamplingRate
+		},
+	}
+	if options.File != "" {
+		f, err := os.OpenFile(options.File, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open audit log file: %w", err)
+		}
+		l.exporters = append(l.exporters, &fileExporter{file: f, logger: logger})
+	}
+	if options.OTLPEndpoint != "" {
+		e, err := newOTLPExporter(options.OTLPEndpoint, logger)
+		if err != nil {
+			return nil, errors.Join(err, l.Close())
+		}
+		l.exporters = append(l.exporters, e)
+	}
+	return l, nil
+}
+
+// Log records the access, unless it is not sampled.
+func (l *Logger) Log(record *Record) {
+	if !l.sample() {
+		return
+	}
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	for param := range record.Params {
+		if slices.Contains(l.options.RedactParams, param) {
+			record.Params[param] = []string{redacted}
+		}
+	}
+	for _, e := range l.exporters {
+		e.export(record)
+	}
+}
+
+// Close flushes the pending records and closes the file and the OTLP connection.
+func (l *Logger) Close() error {
+	var errs []error
+	for _, e := range l.exporters {
+		errs = append(errs, e.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// fileExporter appends the records to a file as JSON lines.
+type fileExporter struct {
+	logger *zap.Logger
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+func (e *fileExporter) export(record *Record) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		e.logger.Error("Cannot marshal audit record", zap.Error(err))
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := e.file.Write(append(data, '\n')); err != nil {
+		e.logger.Error("Cannot write audit record", zap.Error(err))
+	}
+}
+
+func (e *fileExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.file.Close()
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newFileLogger(t *testing.T, options Options) (*Logger, string) {
+	options.File = filepath.Join(t.TempDir(), "audit.log")
+	if options.SamplingRate == 0 {
+		options.SamplingRate = 1
+	}
+	l, err := NewLogger(options, zap.NewNop())
+	require.NoError(t, err)
+	return l, options.File
+}
+
+func readRecords(t *testing.T, path string) []Record {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestLoggerFile(t *testing.T) {
+	l, path := newFileLogger(t, Options{RedactParams: []string{"tag"}})
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	l.Log(&Record{
+		Timestamp: timestamp,
+		Principal: "alice",
+		Tenant:    "acme",
+		Endpoint:  "GET /api/traces/{traceID}",
+		TraceIDs:  []string{"1"},
+	})
+	l.Log(&Record{
+		Endpoint: "GET /api/traces",
+		Params:   map[string][]string{"service": {"frontend"}, "tag": {"user.email:alice@example.com"}},
+	})
+	require.NoError(t, l.Close())
+
+	records := readRecords(t, path)
+	require.Len(t, records, 2)
+	assert.Equal(t, Record{
+		Timestamp: timestamp,
+		Principal: "alice",
+		Tenant:    "acme",
+		Endpoint:  "GET /api/traces/{traceID}",
+		TraceIDs:  []string{"1"},
+	}, records[0])
+	assert.False(t, records[1].Timestamp.IsZero())
+	assert.Equal(t, map[string][]string{"service": {"frontend"}, "tag": {redacted}}, records[1].Params)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"trace_ids":["1"]`)
+}
+
+func TestLoggerFileAppends(t *testing.T) {
+	l, path := newFileLogger(t, Options{})
+	l.Log(&Record{Endpoint: "GET /api/services"})
+	require.NoError(t, l.Close())
+
+	l, err := NewLogger(Options{File: path, SamplingRate: 1}, zap.NewNop())
+	require.NoError(t, err)
+	l.Log(&Record{Endpoint: "GET /api/services"})
+	require.NoError(t, l.Close())
+
+	assert.Len(t, readRecords(t, path), 2)
+}
+
+func TestLoggerSampling(t *testing.T) {
+	l, path := newFileLogger(t, Options{})
+	l.sample = func() bool { return false }
+	l.Log(&Record{Endpoint: "GET /api/services"})
+	require.NoError(t, l.Close())
+	assert.Empty(t, readRecords(t, path))
+}
+
+func TestNewLoggerErrors(t *testing.T) {
+	_, err := NewLogger(Options{File: filepath.Join(t.TempDir(), "missing", "audit.log")}, zap.NewNop())
+	require.ErrorContains(t, err, "cannot open audit log file")
+
+	_, err = NewLogger(Options{File: filepath.Join(t.TempDir(), "audit.log"), OTLPEndpoint: "%zz"}, zap.NewNop())
+	require.ErrorContains(t, err, "failed to connect with the OTLP endpoint")
+}
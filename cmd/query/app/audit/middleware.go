@@ -0,0 +1,154 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/internal/api_v3"
+	"github.com/jaegertracing/jaeger/pkg/oidc"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+// traceIDParams are the path variables and query parameters of the HTTP APIs holding trace IDs.
+var traceIDParams = []string{"traceID", "trace_id", "traceA", "traceB"}
+
+// HTTPMiddleware records the requests to the HTTP APIs, i.e. the routes under /api/.
+// It must be used by the router, after the route is matched.
+func (l *Logger) HTTPMiddleware(tm *tenancy.Manager) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if route := mux.CurrentRoute(r); route != nil {
+				if template, err := route.GetPathTemplate(); err == nil && strings.Contains(template, "/api/") {
+					l.Log(l.httpRecord(r, template, tm))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (l *Logger) httpRecord(r *http.Request, endpoint string, tm *tenancy.Manager) *Record {
+	record := &Record{
+		Principal: oidc.GetClaims(r.Context()).String(l.options.PrincipalClaim),
+		Endpoint:  r.Method + " " + endpoint,
+	}
+	if record.Principal == "" && l.options.PrincipalHeader != "" {
+		record.Principal = r.Header.Get(l.options.PrincipalHeader)
+	}
+	if tm.Enabled {
+		record.Tenant = r.Header.Get(tm.Header)
+	}
+	vars := mux.Vars(r)
+	params := r.URL.Query()
+	for _, param := range traceIDParams {
+		if traceID, ok := vars[param]; ok {
+			record.TraceIDs = append(record.TraceIDs, traceID)
+		}
+		record.TraceIDs = append(record.TraceIDs, params[param]...)
+		delete(params, param)
+	}
+	if len(params) > 0 {
+		record.Params = params
+	}
+	return record
+}
+
+// UnaryServerInterceptor records the calls of the unary methods of the gRPC APIs.
+func (l *Logger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if audited(info.FullMethod) {
+			l.Log(l.grpcRecord(ctx, info.FullMethod, req))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor records the calls of the streaming methods of the gRPC APIs,
+// when their request is received.
+func (l *Logger) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if audited(info.FullMethod) {
+			ss = &auditServerStream{ServerStream: ss, logger: l, method: info.FullMethod}
+		}
+		return handler(srv, ss)
+	}
+}
+
+// audited returns false for the methods of the health and reflection services.
+func audited(fullMethod string) bool {
+	return !strings.HasPrefix(fullMethod, "/grpc.")
+}
+
+type auditServerStream struct {
+	grpc.ServerStream
+	logger   *Logger
+	method   string
+	received bool
+}
+
+func (s *auditServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && !s.received {
+		s.received = true
+		s.logger.Log(s.logger.grpcRecord(s.Context(), s.method, m))
+	}
+	return err
+}
+
+func (l *Logger) grpcRecord(ctx context.Context, method string, req any) *Record {
+	record := &Record{
+		Principal: oidc.GetClaims(ctx).String(l.options.PrincipalClaim),
+		Tenant:    tenancy.GetTenant(ctx),
+		Endpoint:  method,
+	}
+	if record.Principal == "" && l.options.PrincipalHeader != "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(l.options.PrincipalHeader); len(values) == 1 {
+				record.Principal = values[0]
+			}
+		}
+	}
+	switch req := req.(type) {
+	case *api_v2.GetTraceRequest:
+		record.TraceIDs = []string{req.TraceID.String()}
+	case *api_v2.ArchiveTraceRequest:
+		record.TraceIDs = []string{req.TraceID.String()}
+	case *api_v2.FindTracesRequest:
+		if req.Query != nil {
+			record.Params = searchParams(req.Query.ServiceName, req.Query.OperationName, req.Query.Tags)
+		}
+	case *api_v3.GetTraceRequest:
+		record.TraceIDs = []string{req.TraceId}
+	case *api_v3.FindTracesRequest:
+		if req.Query != nil {
+			record.Params = searchParams(req.Query.ServiceName, req.Query.OperationName, req.Query.Attributes)
+		}
+	}
+	return record
+}
+
+// searchParams returns the parameters of a gRPC search, named like those of the HTTP API.
+func searchParams(service, operation string, tags map[string]string) map[string][]string {
+	params := make(map[string][]string)
+	if service != "" {
+		params["service"] = []string{service}
+	}
+	if operation != "" {
+		params["operation"] = []string{operation}
+	}
+	for k, v := range tags {
+		params["tag"] = append(params["tag"], k+":"+v)
+	}
+	slices.Sort(params["tag"])
+	return params
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/internal/api_v3"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/oidc"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+type recordingExporter struct {
+	records []*Record
+}
+
+func (e *recordingExporter) export(record *Record) {
+	e.records = append(e.records, record)
+}
+
+func (*recordingExporter) Close() error {
+	return nil
+}
+
+func newRecordingLogger(options Options) (*Logger, *recordingExporter) {
+	e := &recordingExporter{}
+	return &Logger{
+		options:   options,
+		sample:    func() bool { return true },
+		exporters: []exporter{e},
+	}, e
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	l, e := newRecordingLogger(Options{PrincipalHeader: "X-Forwarded-User", PrincipalClaim: "sub"})
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true, Header: "x-tenant"})
+	r := mux.NewRouter()
+	r.Use(l.HTTPMiddleware(tm))
+	ok := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	r.HandleFunc("/api/traces/{traceID}", ok)
+	r.HandleFunc("/api/traces", ok)
+	r.HandleFunc("/static/{file}", ok)
+
+	tests := []struct {
+		name     string
+		url      string
+		header   string
+		claims   oidc.Claims
+		expected *Record
+	}{
+		{
+			name:   "trace with OIDC principal",
+			url:    "/api/traces/1?start=1",
+			header: "bob",
+			claims: oidc.Claims{"sub": "alice"},
+			expected: &Record{
+				Principal: "alice",
+				Tenant:    "acme",
+				Endpoint:  "GET /api/traces/{traceID}",
+				TraceIDs:  []string{"1"},
+				Params:    map[string][]string{"start": {"1"}},
+			},
+		},
+		{
+			name:   "search with header principal",
+			url:    "/api/traces?service=frontend&traceID=2&traceID=3",
+			header: "bob",
+			expected: &Record{
+				Principal: "bob",
+				Tenant:    "acme",
+				Endpoint:  "GET /api/traces",
+				TraceIDs:  []string{"2", "3"},
+				Params:    map[string][]string{"service": {"frontend"}},
+			},
+		},
+		{
+			name: "static files are not recorded",
+			url:  "/static/index.html",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e.records = nil
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			req.Header.Set("X-Forwarded-User", test.header)
+			req.Header.Set("x-tenant", "acme")
+			if test.claims != nil {
+				req = req.WithContext(oidc.ContextWithClaims(req.Context(), test.claims))
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			if test.expected == nil {
+				assert.Empty(t, e.records)
+				return
+			}
+			require.Len(t, e.records, 1)
+			record := e.records[0]
+			assert.False(t, record.Timestamp.IsZero())
+			record.Timestamp = test.expected.Timestamp
+			assert.Equal(t, test.expected, record)
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	l, e := newRecordingLogger(Options{PrincipalHeader: "x-forwarded-user"})
+	interceptor := l.UnaryServerInterceptor()
+	handler := func(_ context.Context, req any) (any, error) {
+		return req, nil
+	}
+	ctx := metadata.NewIncomingContext(tenancy.WithTenant(context.Background(), "acme"), metadata.Pairs("x-forwarded-user", "bob"))
+
+	tests := []struct {
+		method   string
+		req      any
+		traceIDs []string
+		params   map[string][]string
+	}{
+		{
+			method:   "/jaeger.api_v2.QueryService/GetTrace",
+			req:      &api_v2.GetTraceRequest{TraceID: model.NewTraceID(0, 1)},
+			traceIDs: []string{"0000000000000001"},
+		},
+		{
+			method:   "/jaeger.api_v2.QueryService/ArchiveTrace",
+			req:      &api_v2.ArchiveTraceRequest{TraceID: model.NewTraceID(0, 2)},
+			traceIDs: []string{"0000000000000002"},
+		},
+		{
+			method: "/jaeger.api_v2.QueryService/FindTraces",
+			req: &api_v2.FindTracesRequest{Query: &api_v2.TraceQueryParameters{
+				ServiceName: "frontend",
+				Tags:        map[string]string{"http.status_code": "500", "error": "true"},
+			}},
+			params: map[string][]string{"service": {"frontend"}, "tag": {"error:true", "http.status_code:500"}},
+		},
+		{
+			method:   "/jaeger.api_v3.QueryService/GetTrace",
+			req:      &api_v3.GetTraceRequest{TraceId: "3"},
+			traceIDs: []string{"3"},
+		},
+		{
+			method: "/jaeger.api_v3.QueryService/FindTraces",
+			req: &api_v3.FindTracesRequest{Query: &api_v3.TraceQueryParameters{
+				ServiceName:   "frontend",
+				OperationName: "GET /",
+			}},
+			params: map[string][]string{"service": {"frontend"}, "operation": {"GET /"}},
+		},
+		{
+			method: "/jaeger.api_v2.QueryService/GetServices",
+			req:    &api_v2.GetServicesRequest{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.method, func(t *testing.T) {
+			e.records = nil
+			_, err := interceptor(ctx, test.req, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+			require.NoError(t, err)
+			require.Len(t, e.records, 1)
+			record := e.records[0]
+			assert.Equal(t, "bob", record.Principal)
+			assert.Equal(t, "acme", record.Tenant)
+			assert.Equal(t, test.method, record.Endpoint)
+			assert.Equal(t, test.traceIDs, record.TraceIDs)
+			if test.params != nil {
+				assert.Equal(t, test.params, record.Params)
+			}
+		})
+	}
+
+	e.records = nil
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	require.NoError(t, err)
+	assert.Empty(t, e.records)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (*fakeServerStream) RecvMsg(m any) error {
+	m.(*api_v2.GetTraceRequest).TraceID = model.NewTraceID(0, 1)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	l, e := newRecordingLogger(Options{PrincipalClaim: "sub"})
+	interceptor := l.StreamServerInterceptor()
+	ctx := oidc.ContextWithClaims(context.Background(), oidc.Claims{"sub": "alice"})
+	handler := func(_ any, ss grpc.ServerStream) error {
+		for i := 0; i < 2; i++ {
+			if err := ss.RecvMsg(&api_v2.GetTraceRequest{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	method := "/jaeger.api_v2.QueryService/GetTrace"
+	err := interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: method}, handler)
+	require.NoError(t, err)
+	require.Len(t, e.records, 1)
+	assert.Equal(t, "alice", e.records[0].Principal)
+	assert.Equal(t, method, e.records[0].Endpoint)
+	assert.Equal(t, []string{"0000000000000001"}, e.records[0].TraceIDs)
+
+	e.records = nil
+	err = interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"}, handler)
+	require.NoError(t, err)
+	assert.Empty(t, e.records)
+}
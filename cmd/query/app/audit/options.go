@@ -0,0 +1,70 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	flagPrefix          = "query.audit"
+	flagFile            = flagPrefix + ".file"
+	flagOTLPEndpoint    = flagPrefix + ".otlp-endpoint"
+	flagSamplingRate    = flagPrefix + ".sampling-rate"
+	flagRedactParams    = flagPrefix + ".redact-params"
+	flagPrincipalHeader = flagPrefix + ".principal-header"
+	flagPrincipalClaim  = flagPrefix + ".principal-claim"
+)
+
+// Options describes the configuration of the audit log of the accesses to the query APIs.
+type Options struct {
+	// File is the path of the file the records are appended to as JSON lines.
+	File string
+	// OTLPEndpoint is the host:port of an OTLP/gRPC receiver the records are exported to as logs.
+	OTLPEndpoint string
+	// SamplingRate is the fraction of the accesses recorded, between 0 and 1.
+	SamplingRate float64
+	// RedactParams lists the request parameters whose values are not recorded.
+	RedactParams []string
+	// PrincipalHeader is the HTTP header or gRPC metadata key carrying the user name, set by an authenticating proxy.
+	PrincipalHeader string
+	// PrincipalClaim is the claim of the OIDC token holding the user name, taking precedence over the header.
+	PrincipalClaim string
+}
+
+// Enabled returns true if the records are written to a file or exported.
+func (o Options) Enabled() bool {
+	return o.File != "" || o.OTLPEndpoint != ""
+}
+
+// AddFlags adds flags for the audit log to the FlagSet.
+func AddFlags(flags *flag.FlagSet) {
+	flags.String(flagFile, "", "The path of a file where the accesses to the traces are recorded as JSON lines; the audit log is disabled if neither this nor the OTLP endpoint is set")
+	flags.String(flagOTLPEndpoint, "", "The host:port of an OTLP/gRPC receiver the accesses to the traces are exported to as logs")
+	flags.Float64(flagSamplingRate, 1, "The fraction of the accesses to the traces recorded in the audit log, between 0 and 1")
+	flags.String(flagRedactParams, "", "Comma-separated list of request parameters whose values are redacted in the audit log, e.g. tag,tags")
+	flags.String(flagPrincipalHeader, "", "The HTTP header (or gRPC metadata key) carrying the name of the user recorded in the audit log; it must be set by a trusted authenticating proxy")
+	flags.String(flagPrincipalClaim, "sub", "The OIDC token claim holding the name of the user recorded in the audit log, when authentication is enabled")
+}
+
+// InitFromViper creates audit.Options populated with values retrieved from Viper.
+func InitFromViper(v *viper.Viper) (Options, error) {
+	var o Options
+	o.File = v.GetString(flagFile)
+	o.OTLPEndpoint = v.GetString(flagOTLPEndpoint)
+	o.SamplingRate = v.GetFloat64(flagSamplingRate)
+	if o.SamplingRate < 0 || o.SamplingRate > 1 {
+		return o, fmt.Errorf("%s must be between 0 and 1, got %v", flagSamplingRate, o.SamplingRate)
+	}
+	if params := v.GetString(flagRedactParams); params != "" {
+		o.RedactParams = strings.Split(params, ",")
+	}
+	o.PrincipalHeader = v.GetString(flagPrincipalHeader)
+	o.PrincipalClaim = v.GetString(flagPrincipalClaim)
+	return o, nil
+}
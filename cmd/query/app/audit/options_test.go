@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsFromFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--query.audit.file=/var/log/jaeger/audit.log",
+		"--query.audit.otlp-endpoint=localhost:4317",
+		"--query.audit.sampling-rate=0.5",
+		"--query.audit.redact-params=tag,tags",
+		"--query.audit.principal-header=X-Forwarded-User",
+		"--query.audit.principal-claim=email",
+	})
+	options, err := InitFromViper(v)
+	require.NoError(t, err)
+	assert.Equal(t, Options{
+		File:            "/var/log/jaeger/audit.log",
+		OTLPEndpoint:    "localhost:4317",
+		SamplingRate:    0.5,
+		RedactParams:    []string{"tag", "tags"},
+		PrincipalHeader: "X-Forwarded-User",
+		PrincipalClaim:  "email",
+	}, options)
+	assert.True(t, options.Enabled())
+}
+
+func TestOptionsDefaults(t *testing.T) {
+	v, _ := config.Viperize(AddFlags)
+	options, err := InitFromViper(v)
+	require.NoError(t, err)
+	assert.Equal(t, Options{SamplingRate: 1, PrincipalClaim: "sub"}, options)
+	assert.False(t, options.Enabled())
+}
+
+func TestOptionsInvalidSamplingRate(t *testing.T) {
+	for _, rate := range []string{"-0.1", "1.5"} {
+		v, command := config.Viperize(AddFlags)
+		command.ParseFlags([]string{"--query.audit.sampling-rate=" + rate})
+		_, err := InitFromViper(v)
+		require.ErrorContains(t, err, "query.audit.sampling-rate must be between 0 and 1")
+	}
+}
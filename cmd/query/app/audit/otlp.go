@@ -0,0 +1,146 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	otlpQueueSize     = 1000
+	otlpBatchSize     = 100
+	otlpFlushInterval = time.Second
+	otlpExportTimeout = 5 * time.Second
+
+	scopeName = "jaeger-query/audit"
+)
+
+// otlpExporter exports the records as OTLP logs in batches, in the background
+// so that the requests are not slowed down by the audit log.
+type otlpExporter struct {
+	client plogotlp.GRPCClient
+	conn   *grpc.ClientConn
+	logger *zap.Logger
+
+	queue     chan *Record
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newOTLPExporter(endpoint string, logger *zap.Logger) (*otlpExporter, error) {
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect with the OTLP endpoint: %w", err)
+	}
+	e := &otlpExporter{
+		client: plogotlp.NewGRPCClient(conn),
+		conn:   conn,
+		logger: logger,
+		queue:  make(chan *Record, otlpQueueSize),
+		done:   make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+func (e *otlpExporter) export(record *Record) {
+	select {
+	case e.queue <- record:
+	default:
+		e.logger.Warn("Audit log queue is full, dropping record", zap.String("endpoint", record.Endpoint))
+	}
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+	var batch []*Record
+	for {
+		select {
+		case record, ok := <-e.queue:
+			if !ok {
+				e.flush(batch)
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= otlpBatchSize {
+				e.flush(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			e.flush(batch)
+			batch = nil
+		}
+	}
+}
+
+func (e *otlpExporter) flush(batch []*Record) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+	defer cancel()
+	if _, err := e.client.Export(ctx, plogotlp.NewExportRequestFromLogs(recordsToLogs(batch))); err != nil {
+		e.logger.Error("Failed to export audit records", zap.Int("records", len(batch)), zap.Error(err))
+	}
+}
+
+// Close exports the queued records and closes the connection.
+func (e *otlpExporter) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.queue)
+	})
+	<-e.done
+	return e.conn.Close()
+}
+
+func recordsToLogs(records []*Record) plog.Logs {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "jaeger-query")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(scopeName)
+	for _, record := range records {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(record.Timestamp))
+		lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(record.Timestamp))
+		lr.SetSeverityNumber(plog.SeverityNumberInfo)
+		lr.Body().SetStr("trace access")
+		attrs := lr.Attributes()
+		if record.Principal != "" {
+			attrs.PutStr("principal", record.Principal)
+		}
+		if record.Tenant != "" {
+			attrs.PutStr("tenant", record.Tenant)
+		}
+		attrs.PutStr("endpoint", record.Endpoint)
+		if len(record.TraceIDs) > 0 {
+			traceIDs := attrs.PutEmptySlice("trace_ids")
+			for _, traceID := range record.TraceIDs {
+				traceIDs.AppendEmpty().SetStr(traceID)
+			}
+		}
+		if len(record.Params) > 0 {
+			params := attrs.PutEmptyMap("params")
+			for name, values := range record.Params {
+				slice := params.PutEmptySlice(name)
+				for _, value := range values {
+					slice.AppendEmpty().SetStr(value)
+				}
+			}
+		}
+	}
+	return logs
+}
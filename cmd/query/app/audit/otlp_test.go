@@ -0,0 +1,91 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type fakeOTLPServer struct {
+	plogotlp.UnimplementedGRPCServer
+	lock sync.Mutex
+	logs []plog.Logs
+}
+
+func (s *fakeOTLPServer) Export(_ context.Context, req plogotlp.ExportRequest) (plogotlp.ExportResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.logs = append(s.logs, req.Logs())
+	return plogotlp.NewExportResponse(), nil
+}
+
+func startServer(t *testing.T) (*fakeOTLPServer, string) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	fake := &fakeOTLPServer{}
+	plogotlp.RegisterGRPCServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return fake, listener.Addr().String()
+}
+
+func TestLoggerOTLP(t *testing.T) {
+	server, addr := startServer(t)
+	l, err := NewLogger(Options{OTLPEndpoint: addr, SamplingRate: 1}, zap.NewNop())
+	require.NoError(t, err)
+
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	l.Log(&Record{
+		Timestamp: timestamp,
+		Principal: "alice",
+		Tenant:    "acme",
+		Endpoint:  "GET /api/traces",
+		Params:    map[string][]string{"service": {"frontend"}},
+	})
+	l.Log(&Record{Endpoint: "/jaeger.api_v3.QueryService/GetTrace", TraceIDs: []string{"1", "2"}})
+	// the queued records are exported when the logger is closed
+	require.NoError(t, l.Close())
+
+	require.Len(t, server.logs, 1)
+	logs := server.logs[0]
+	assert.Equal(t, 2, logs.LogRecordCount())
+	resource := logs.ResourceLogs().At(0)
+	service, ok := resource.Resource().Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "jaeger-query", service.Str())
+	scope := resource.ScopeLogs().At(0)
+	assert.Equal(t, scopeName, scope.Scope().Name())
+
+	first := scope.LogRecords().At(0)
+	assert.Equal(t, timestamp, first.Timestamp().AsTime())
+	assert.Equal(t, map[string]any{
+		"principal": "alice",
+		"tenant":    "acme",
+		"endpoint":  "GET /api/traces",
+		"params":    map[string]any{"service": []any{"frontend"}},
+	}, first.Attributes().AsRaw())
+	second := scope.LogRecords().At(1)
+	assert.Equal(t, map[string]any{
+		"endpoint":  "/jaeger.api_v3.QueryService/GetTrace",
+		"trace_ids": []any{"1", "2"},
+	}, second.Attributes().AsRaw())
+}
+
+func TestOTLPExporterQueueFull(t *testing.T) {
+	// the exporter is not running, so the unbuffered queue is always full
+	e := &otlpExporter{logger: zap.NewNop(), queue: make(chan *Record)}
+	e.export(&Record{Endpoint: "GET /api/services"})
+}
@@ -29,26 +29,43 @@ import (
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/audit"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model/adjuster"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
+	"github.com/jaegertracing/jaeger/pkg/oidc"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/ports"
 	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore/filestore"
 )
 
 const (
 	queryHTTPHostPort          = "query.http-server.host-port"
 	queryGRPCHostPort          = "query.grpc-server.host-port"
+	queryHTTPAddressFamily     = "query.http-server.address-family"
+	queryGRPCAddressFamily     = "query.grpc-server.address-family"
 	queryBasePath              = "query.base-path"
 	queryStaticFiles           = "query.static-files"
 	queryLogStaticAssetsAccess = "query.log-static-assets-access"
 	queryUIConfig              = "query.ui-config"
+	queryTenantUIConfigs       = "query.ui-tenant-configs"
 	queryTokenPropagation      = "query.bearer-token-propagation"
 	queryAdditionalHeaders     = "query.additional-headers"
 	queryMaxClockSkewAdjust    = "query.max-clock-skew-adjustment"
+	queryClockSkewDisabled     = "query.clock-skew-adjustment.disabled-services"
+	queryClockSkewAdjustedTag  = "query.clock-skew-adjustment.tag"
 	queryEnableTracing         = "query.enable-tracing"
+	querySavedSearchesFile     = "query.saved-searches.file"
+	queryTagMaskingConfig      = "query.tag-masking.config"
+	queryRoleHeader            = "query.role-header"
+	queryCacheEnabled          = "query.cache.enabled"
+	queryCacheTTL              = "query.cache.ttl"
+	queryCacheMaxEntries       = "query.cache.max-entries"
+	queryCacheMaxTraceSpans    = "query.cache.max-trace-spans"
+	queryMaxBulkArchiveTraces  = "query.archive.max-bulk-traces"
 )
 
 var tlsGRPCFlagsConfig = tlscfg.ServerFlagsConfig{
@@ -76,12 +93,18 @@ type QueryOptionsBase struct {
 
 	// UIConfig is the path to a configuration file for the UI
 	UIConfig string `valid:"optional" mapstructure:"ui_config"`
+	// TenantUIConfigs is the path to a JSON file with the overlays of the UI configuration of each tenant
+	TenantUIConfigs string `valid:"optional" mapstructure:"ui_tenant_configs"`
 	// BearerTokenPropagation activate/deactivate bearer token propagation to storage
 	BearerTokenPropagation bool
 	// AdditionalHeaders
 	AdditionalHeaders http.Header
 	// MaxClockSkewAdjust is the maximum duration by which jaeger-query will adjust a span
 	MaxClockSkewAdjust time.Duration
+	// ClockSkewDisabledServices are the services whose spans are never adjusted for clock skew
+	ClockSkewDisabledServices []string
+	// ClockSkewAdjustedTag is the key of the tag added to the spans adjusted for clock skew, if not empty
+	ClockSkewAdjustedTag string
 	// Tenancy configures tenancy for query
 	Tenancy tenancy.Options
 	// EnableTracing determines whether traces will be emitted by jaeger-query.
@@ -96,10 +119,30 @@ type QueryOptions struct {
 	HTTPHostPort string
 	// GRPCHostPort is the host:port address that the query service listens in on for gRPC requests
 	GRPCHostPort string
+	// HTTPAddressFamily restricts the addresses of the HTTP server to IPv4 or IPv6
+	HTTPAddressFamily netutils.AddressFamily
+	// GRPCAddressFamily restricts the addresses of the gRPC server to IPv4 or IPv6
+	GRPCAddressFamily netutils.AddressFamily
 	// TLSGRPC configures secure transport (Consumer to Query service GRPC API)
 	TLSGRPC tlscfg.Options
 	// TLSHTTP configures secure transport (Consumer to Query service HTTP API)
 	TLSHTTP tlscfg.Options
+	// SavedSearchesFile is the path to a JSON file for saved searches; if empty, they are kept in the span storage when supported
+	SavedSearchesFile string
+	// TagMasking holds the rules masking tags in the returned traces, if configured
+	TagMasking *querysvc.TagMaskingConfig
+	// RoleHeader is the HTTP header or gRPC metadata key carrying the role of the user, used by tag masking rules
+	RoleHeader string
+	// OIDC configures authentication of the UI and API users with OpenID Connect
+	OIDC oidc.Options
+	// CacheEnabled enables the caching of traces, services and operations read from the storage
+	CacheEnabled bool
+	// Cache configures the caching of query responses
+	Cache querysvc.CacheOptions
+	// MaxBulkArchiveTraces is the maximum number of traces archived by one request of the bulk archiving API
+	MaxBulkArchiveTraces int
+	// Audit configures the audit log of the accesses to the query APIs
+	Audit audit.Options
 }
 
 // AddFlags adds flags for QueryOptions
@@ -107,21 +150,46 @@ func AddFlags(flagSet *flag.FlagSet) {
 	flagSet.Var(&config.StringSlice{}, queryAdditionalHeaders, `Additional HTTP response headers.  Can be specified multiple times.  Format: "Key: Value"`)
 	flagSet.String(queryHTTPHostPort, ports.PortToHostPort(ports.QueryHTTP), "The host:port (e.g. 127.0.0.1:14268 or :14268) of the query's HTTP server")
 	flagSet.String(queryGRPCHostPort, ports.PortToHostPort(ports.QueryGRPC), "The host:port (e.g. 127.0.0.1:14250 or :14250) of the query's gRPC server")
+	flagSet.String(queryHTTPAddressFamily, string(netutils.DualStack), fmt.Sprintf(netutils.AddressFamilyFlagUsage, "query's HTTP server"))
+	flagSet.String(queryGRPCAddressFamily, string(netutils.DualStack), fmt.Sprintf(netutils.AddressFamilyFlagUsage, "query's gRPC server"))
 	flagSet.String(queryBasePath, "/", "The base path for all HTTP routes, e.g. /jaeger; useful when running behind a reverse proxy. See https://github.com/jaegertracing/jaeger/blob/main/examples/reverse-proxy/README.md")
 	flagSet.String(queryStaticFiles, "", "The directory path override for the static assets for the UI")
 	flagSet.Bool(queryLogStaticAssetsAccess, false, "Log when static assets are accessed (for debugging)")
 	flagSet.String(queryUIConfig, "", "The path to the UI configuration file in JSON format")
+	flagSet.String(queryTenantUIConfigs, "", "The path to a JSON file mapping each tenant to the overlay of the UI configuration it is served, e.g. its own menu and link patterns; the strings of the UI configurations can include the ${tenant} variable")
 	flagSet.Bool(queryTokenPropagation, false, "Allow propagation of bearer token to be used by storage plugins")
 	flagSet.Duration(queryMaxClockSkewAdjust, 0, "The maximum delta by which span timestamps may be adjusted in the UI due to clock skew; set to 0s to disable clock skew adjustments")
+	flagSet.String(queryClockSkewDisabled, "", "A comma-separated list of services whose span timestamps are never adjusted due to clock skew, e.g. because their clocks are known to be synchronized")
+	flagSet.String(queryClockSkewAdjustedTag, "", "The key of the tag added to the spans adjusted due to clock skew, with the adjustment as value; no tag is added if empty")
 	flagSet.Bool(queryEnableTracing, false, "Enables emitting jaeger-query traces")
+	flagSet.String(queryTagMaskingConfig, "", "The path to a JSON file with the rules masking or removing span tags from the traces returned to the given tenants and roles")
+	flagSet.String(queryRoleHeader, "", "The HTTP header (or gRPC metadata key) carrying the role of the user, used by tag masking rules; it must be set by a trusted authenticating proxy")
+	flagSet.Bool(queryCacheEnabled, false, "Cache the traces, services and operations read from the span storage; archiving a trace invalidates its cached copy")
+	flagSet.Duration(queryCacheTTL, time.Minute, "How long the query responses are cached")
+	flagSet.Int(queryCacheMaxEntries, 1000, "The maximum number of cached query responses; the least recently used are evicted first")
+	flagSet.Int(queryCacheMaxTraceSpans, 10000, "Traces with more spans than this are not cached; set to 0 to cache traces of any size")
+	flagSet.Int(queryMaxBulkArchiveTraces, defaultMaxBulkArchiveTraces, "The maximum number of traces matching a search that can be archived at once with the bulk archiving API")
+	flagSet.String(querySavedSearchesFile, "", "The path to a JSON file where saved trace searches are kept; if empty, they are kept in the span storage when the backend supports it")
 	tlsGRPCFlagsConfig.AddFlags(flagSet)
 	tlsHTTPFlagsConfig.AddFlags(flagSet)
+	oidc.AddFlags(flagSet)
+	audit.AddFlags(flagSet)
 }
 
 // InitFromViper initializes QueryOptions with properties from viper
 func (qOpts *QueryOptions) InitFromViper(v *viper.Viper, logger *zap.Logger) (*QueryOptions, error) {
 	qOpts.HTTPHostPort = v.GetString(queryHTTPHostPort)
 	qOpts.GRPCHostPort = v.GetString(queryGRPCHostPort)
+	httpAddressFamily, err := netutils.ParseAddressFamily(v.GetString(queryHTTPAddressFamily))
+	if err != nil {
+		return qOpts, fmt.Errorf("failed to process HTTP server address family: %w", err)
+	}
+	qOpts.HTTPAddressFamily = httpAddressFamily
+	grpcAddressFamily, err := netutils.ParseAddressFamily(v.GetString(queryGRPCAddressFamily))
+	if err != nil {
+		return qOpts, fmt.Errorf("failed to process gRPC server address family: %w", err)
+	}
+	qOpts.GRPCAddressFamily = grpcAddressFamily
 	if tlsGrpc, err := tlsGRPCFlagsConfig.InitFromViper(v); err == nil {
 		qOpts.TLSGRPC = tlsGrpc
 	} else {
@@ -136,9 +204,14 @@ func (qOpts *QueryOptions) InitFromViper(v *viper.Viper, logger *zap.Logger) (*Q
 	qOpts.StaticAssets.Path = v.GetString(queryStaticFiles)
 	qOpts.StaticAssets.LogAccess = v.GetBool(queryLogStaticAssetsAccess)
 	qOpts.UIConfig = v.GetString(queryUIConfig)
+	qOpts.TenantUIConfigs = v.GetString(queryTenantUIConfigs)
 	qOpts.BearerTokenPropagation = v.GetBool(queryTokenPropagation)
 
 	qOpts.MaxClockSkewAdjust = v.GetDuration(queryMaxClockSkewAdjust)
+	if services := v.GetString(queryClockSkewDisabled); services != "" {
+		qOpts.ClockSkewDisabledServices = strings.Split(services, ",")
+	}
+	qOpts.ClockSkewAdjustedTag = v.GetString(queryClockSkewAdjustedTag)
 	stringSlice := v.GetStringSlice(queryAdditionalHeaders)
 	headers, err := stringSliceAsHeader(stringSlice)
 	if err != nil {
@@ -148,19 +221,82 @@ func (qOpts *QueryOptions) InitFromViper(v *viper.Viper, logger *zap.Logger) (*Q
 	}
 	qOpts.Tenancy = tenancy.InitFromViper(v)
 	qOpts.EnableTracing = v.GetBool(queryEnableTracing)
+	qOpts.SavedSearchesFile = v.GetString(querySavedSearchesFile)
+	if file := v.GetString(queryTagMaskingConfig); file != "" {
+		tagMasking, err := querysvc.LoadTagMaskingConfig(file)
+		if err != nil {
+			return qOpts, err
+		}
+		qOpts.TagMasking = tagMasking
+	}
+	qOpts.RoleHeader = v.GetString(queryRoleHeader)
+	oidcOptions, err := oidc.InitFromViper(v)
+	if err != nil {
+		return qOpts, fmt.Errorf("failed to process OIDC options: %w", err)
+	}
+	oidcOptions.PropagateToken = qOpts.BearerTokenPropagation
+	qOpts.OIDC = oidcOptions
+	qOpts.CacheEnabled = v.GetBool(queryCacheEnabled)
+	qOpts.Cache.TTL = v.GetDuration(queryCacheTTL)
+	qOpts.Cache.MaxEntries = v.GetInt(queryCacheMaxEntries)
+	qOpts.Cache.MaxTraceSpans = v.GetInt(queryCacheMaxTraceSpans)
+	qOpts.MaxBulkArchiveTraces = v.GetInt(queryMaxBulkArchiveTraces)
+	auditOptions, err := audit.InitFromViper(v)
+	if err != nil {
+		return qOpts, fmt.Errorf("failed to process audit log options: %w", err)
+	}
+	qOpts.Audit = auditOptions
 	return qOpts, nil
 }
 
-// BuildQueryServiceOptions creates a QueryServiceOptions struct with appropriate adjusters and archive config
-func (qOpts *QueryOptions) BuildQueryServiceOptions(storageFactory storage.Factory, logger *zap.Logger) *querysvc.QueryServiceOptions {
+// BuildQueryServiceOptions creates a QueryServiceOptions struct with appropriate adjusters and archive config.
+// It fails if the saved searches file cannot be loaded.
+func (qOpts *QueryOptions) BuildQueryServiceOptions(storageFactory storage.Factory, logger *zap.Logger) (*querysvc.QueryServiceOptions, error) {
 	opts := &querysvc.QueryServiceOptions{}
 	if !opts.InitArchiveStorage(storageFactory, logger) {
 		logger.Info("Archive storage not initialized")
 	}
+	if qOpts.SavedSearchesFile != "" {
+		store, err := filestore.NewFileStore(qOpts.SavedSearchesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the saved searches: %w", err)
+		}
+		opts.SavedSearchStore = store
+	} else if !opts.InitSavedSearchStorage(storageFactory, logger) {
+		logger.Info("Saved search storage not initialized")
+	}
 
-	opts.Adjuster = adjuster.Sequence(querysvc.StandardAdjusters(qOpts.MaxClockSkewAdjust)...)
+	opts.Adjuster = adjuster.Sequence(querysvc.StandardAdjusters(adjuster.ClockSkewOptions{
+		MaxDelta:         qOpts.MaxClockSkewAdjust,
+		DisabledServices: qOpts.ClockSkewDisabledServices,
+		AdjustedTag:      qOpts.ClockSkewAdjustedTag,
+	})...)
+	if qOpts.TagMasking != nil {
+		opts.TagMasker = querysvc.NewTagMasker(*qOpts.TagMasking)
+	}
+	opts.Tenancy = tenancy.NewManager(&qOpts.Tenancy)
+	if qOpts.CacheEnabled {
+		opts.Cache = querysvc.NewResponseCache(qOpts.Cache)
+	}
+
+	return opts, nil
+}
 
-	return opts
+// roleSource returns where the role of the user is found, the token claims taking precedence over the header.
+func (qOpts *QueryOptions) roleSource() roleSource {
+	rs := roleSource{header: qOpts.RoleHeader}
+	if qOpts.OIDC.Enabled {
+		rs.claim = qOpts.OIDC.RoleClaim
+	}
+	return rs
+}
+
+// tenantClaim returns the token claim holding the tenant of the user, if OIDC is enabled.
+func (qOpts *QueryOptions) tenantClaim() string {
+	if !qOpts.OIDC.Enabled {
+		return ""
+	}
+	return qOpts.OIDC.TenantClaim
 }
 
 // stringSliceAsHeader parses a slice of strings and returns a http.Header.
@@ -17,6 +17,8 @@ package app
 
 import (
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -25,7 +27,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/ports"
 	"github.com/jaegertracing/jaeger/storage/mocks"
 	spanstore_mocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
@@ -37,21 +42,25 @@ func TestQueryBuilderFlags(t *testing.T) {
 		"--query.static-files=/dev/null",
 		"--query.log-static-assets-access=true",
 		"--query.ui-config=some.json",
+		"--query.ui-tenant-configs=tenants.json",
 		"--query.base-path=/jaeger",
 		"--query.http-server.host-port=127.0.0.1:8080",
 		"--query.grpc-server.host-port=127.0.0.1:8081",
 		"--query.additional-headers=access-control-allow-origin:blerg",
 		"--query.additional-headers=whatever:thing",
 		"--query.max-clock-skew-adjustment=10s",
+		"--query.archive.max-bulk-traces=50",
 	})
 	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
 	require.NoError(t, err)
 	assert.Equal(t, "/dev/null", qOpts.StaticAssets.Path)
 	assert.True(t, qOpts.StaticAssets.LogAccess)
 	assert.Equal(t, "some.json", qOpts.UIConfig)
+	assert.Equal(t, "tenants.json", qOpts.TenantUIConfigs)
 	assert.Equal(t, "/jaeger", qOpts.BasePath)
 	assert.Equal(t, "127.0.0.1:8080", qOpts.HTTPHostPort)
 	assert.Equal(t, "127.0.0.1:8081", qOpts.GRPCHostPort)
+	assert.Equal(t, 50, qOpts.MaxBulkArchiveTraces)
 	assert.Equal(t, http.Header{
 		"Access-Control-Allow-Origin": []string{"blerg"},
 		"Whatever":                    []string{"thing"},
@@ -102,11 +111,13 @@ func TestBuildQueryServiceOptions(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, qOpts)
 
-	qSvcOpts := qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop())
+	qSvcOpts, err := qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop())
+	require.NoError(t, err)
 	assert.NotNil(t, qSvcOpts)
 	assert.NotNil(t, qSvcOpts.Adjuster)
 	assert.Nil(t, qSvcOpts.ArchiveSpanReader)
 	assert.Nil(t, qSvcOpts.ArchiveSpanWriter)
+	assert.False(t, qSvcOpts.Tenancy.Enabled)
 
 	comboFactory := struct {
 		*mocks.Factory
@@ -119,13 +130,181 @@ func TestBuildQueryServiceOptions(t *testing.T) {
 	comboFactory.ArchiveFactory.On("CreateArchiveSpanReader").Return(&spanstore_mocks.Reader{}, nil)
 	comboFactory.ArchiveFactory.On("CreateArchiveSpanWriter").Return(&spanstore_mocks.Writer{}, nil)
 
-	qSvcOpts = qOpts.BuildQueryServiceOptions(comboFactory, zap.NewNop())
+	qSvcOpts, err = qOpts.BuildQueryServiceOptions(comboFactory, zap.NewNop())
+	require.NoError(t, err)
 	assert.NotNil(t, qSvcOpts)
 	assert.NotNil(t, qSvcOpts.Adjuster)
 	assert.NotNil(t, qSvcOpts.ArchiveSpanReader)
 	assert.NotNil(t, qSvcOpts.ArchiveSpanWriter)
 }
 
+func TestBuildQueryServiceOptionsSavedSearches(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	file := filepath.Join(t.TempDir(), "searches.json")
+	command.ParseFlags([]string{"--query.saved-searches.file=" + file})
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, file, qOpts.SavedSearchesFile)
+
+	qSvcOpts, err := qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, qSvcOpts.SavedSearchStore)
+
+	// the query service does not start without the saved searches it cannot load
+	qOpts.SavedSearchesFile = t.TempDir()
+	_, err = qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop())
+	require.ErrorContains(t, err, "failed to load the saved searches: cannot read saved searches file")
+}
+
+func TestQueryOptionsTagMasking(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tag-masking.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"rules": [{"roles": ["viewer"], "keys": ["http.url"]}]}`), 0o600))
+
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--query.tag-masking.config=" + file,
+		"--query.role-header=x-role",
+	})
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "x-role", qOpts.RoleHeader)
+	require.NotNil(t, qOpts.TagMasking)
+	assert.Len(t, qOpts.TagMasking.Rules, 1)
+
+	qSvcOpts, err := qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, qSvcOpts.TagMasker)
+
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--query.tag-masking.config=" + filepath.Join(t.TempDir(), "missing.json")})
+	_, err = new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "cannot read tag masking config")
+}
+
+func TestQueryOptionsClockSkew(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--query.max-clock-skew-adjustment=10s",
+		"--query.clock-skew-adjustment.disabled-services=b,c",
+		"--query.clock-skew-adjustment.tag=clock_skew.adjustment",
+	})
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, qOpts.ClockSkewDisabledServices)
+	assert.Equal(t, "clock_skew.adjustment", qOpts.ClockSkewAdjustedTag)
+
+	traceID := model.NewTraceID(0, 1)
+	span := func(id uint64, service string) *model.Span {
+		return &model.Span{
+			TraceID:    traceID,
+			SpanID:     model.NewSpanID(id),
+			References: []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(1))},
+			StartTime:  time.Unix(0, 0),
+			Duration:   time.Millisecond,
+			Process:    model.NewProcess(service, nil),
+		}
+	}
+	root := span(1, "a")
+	root.References, root.StartTime, root.Duration = nil, time.Unix(1, 0), time.Second
+	trace := &model.Trace{Spans: []*model.Span{root, span(2, "b"), span(3, "d")}}
+
+	qSvcOpts, err := qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop())
+	require.NoError(t, err)
+	trace, err = qSvcOpts.Adjuster.Adjust(trace)
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(0, 0), trace.Spans[1].StartTime)
+	assert.Empty(t, trace.Spans[1].Tags)
+	assert.True(t, trace.Spans[2].StartTime.After(time.Unix(1, 0)))
+	tag, ok := model.KeyValues(trace.Spans[2].Tags).FindByKey("clock_skew.adjustment")
+	require.True(t, ok)
+	assert.NotEmpty(t, tag.VStr)
+}
+
+func TestQueryOptionsCache(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--query.cache.enabled=true",
+		"--query.cache.ttl=30s",
+		"--query.cache.max-entries=50",
+	})
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.True(t, qOpts.CacheEnabled)
+	assert.Equal(t, querysvc.CacheOptions{TTL: 30 * time.Second, MaxEntries: 50, MaxTraceSpans: 10000}, qOpts.Cache)
+	qSvcOpts, err := qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, qSvcOpts.Cache)
+
+	qOpts.CacheEnabled = false
+	qSvcOpts, err = qOpts.BuildQueryServiceOptions(&mocks.Factory{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, qSvcOpts.Cache)
+}
+
+func TestQueryOptionsOIDC(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--query.oidc.enabled=true",
+		"--query.oidc.issuer-url=https://accounts.example.com",
+		"--query.oidc.client-id=jaeger",
+		"--query.oidc.role-claim=role",
+		"--query.oidc.tenant-claim=org",
+		"--query.role-header=x-role",
+		"--query.bearer-token-propagation=true",
+	})
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.True(t, qOpts.OIDC.Enabled)
+	assert.True(t, qOpts.OIDC.PropagateToken)
+	assert.Equal(t, roleSource{header: "x-role", claim: "role"}, qOpts.roleSource())
+	assert.Equal(t, "org", qOpts.tenantClaim())
+	qOpts.OIDC.Enabled = false
+	assert.Empty(t, qOpts.tenantClaim())
+
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--query.oidc.enabled=true"})
+	_, err = new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to process OIDC options")
+}
+
+func TestQueryOptionsAudit(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--query.audit.file=/var/log/jaeger/audit.log",
+		"--query.audit.sampling-rate=0.1",
+	})
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.True(t, qOpts.Audit.Enabled())
+	assert.Equal(t, "/var/log/jaeger/audit.log", qOpts.Audit.File)
+	assert.InDelta(t, 0.1, qOpts.Audit.SamplingRate, 1e-9)
+
+	v, command = config.Viperize(AddFlags)
+	command.ParseFlags([]string{"--query.audit.sampling-rate=2"})
+	_, err = new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to process audit log options")
+}
+
+func TestQueryOptionsAddressFamily(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--query.http-server.host-port=[::]:16686",
+		"--query.http-server.address-family=ipv6",
+	})
+	qOpts, err := new(QueryOptions).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "[::]:16686", qOpts.HTTPHostPort)
+	assert.Equal(t, netutils.IPv6, qOpts.HTTPAddressFamily)
+	assert.Equal(t, netutils.DualStack, qOpts.GRPCAddressFamily)
+
+	for _, flag := range []string{"--query.http-server.address-family=ipx", "--query.grpc-server.address-family=ipx"} {
+		v, command = config.Viperize(AddFlags)
+		command.ParseFlags([]string{flag})
+		_, err = new(QueryOptions).InitFromViper(v, zap.NewNop())
+		require.ErrorContains(t, err, `invalid address family "ipx"`)
+	}
+}
+
 func TestQueryOptionsPortAllocationFromFlags(t *testing.T) {
 	flagPortCases := []struct {
 		name                 string
@@ -151,17 +151,27 @@ func (g *GRPCHandler) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.Quer
 		DurationMin:   query.DurationMin,
 		DurationMax:   query.DurationMax,
 		NumTraces:     int(query.SearchDepth),
+		PageToken:     r.PageToken,
+	}
+	page, err := g.queryService.FindTracesPage(stream.Context(), &queryParams)
+	if errors.Is(err, spanstore.ErrInvalidPageToken) {
+		return status.Error(codes.InvalidArgument, err.Error())
 	}
-	traces, err := g.queryService.FindTraces(stream.Context(), &queryParams)
 	if err != nil {
 		g.logger.Error("failed when searching for traces", zap.Error(err))
 		return status.Errorf(codes.Internal, "failed when searching for traces: %v", err)
 	}
-	for _, trace := range traces {
+	for _, trace := range page.Traces {
 		if err := g.sendSpanChunks(trace.Spans, stream.Send); err != nil {
 			return err
 		}
 	}
+	if page.NextPageToken != "" {
+		if err := stream.Send(&api_v2.SpansResponseChunk{NextPageToken: page.NextPageToken}); err != nil {
+			g.logger.Error("failed to send response to client", zap.Error(err))
+			return err
+		}
+	}
 	return nil
 }
 
@@ -199,9 +209,16 @@ func (g *GRPCHandler) GetOperations(
 	if r == nil {
 		return nil, errNilRequest
 	}
+	if r.Offset < 0 || r.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset and limit must not be negative")
+	}
 	operations, err := g.queryService.GetOperations(ctx, spanstore.OperationQueryParameters{
-		ServiceName: r.Service,
-		SpanKind:    r.SpanKind,
+		ServiceName:  r.Service,
+		SpanKind:     r.SpanKind,
+		NamePrefix:   r.NamePrefix,
+		NameContains: r.NameContains,
+		Offset:       int(r.Offset),
+		Limit:        int(r.Limit),
 	})
 	if err != nil {
 		g.logger.Error("failed to fetch operations", zap.Error(err))
@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"testing"
 	"time"
@@ -379,6 +380,54 @@ func TestFindTracesSuccessGRPC(t *testing.T) {
 	})
 }
 
+func TestFindTracesPaginationGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		var query *spanstore.TraceQueryParameters
+		server.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+			Run(func(args mock.Arguments) {
+				query = args.Get(1).(*spanstore.TraceQueryParameters)
+			}).
+			Return([]*model.Trace{mockTraceGRPC, mockLargeTraceGRPC}, nil).Once()
+
+		token := (&spanstore.PageCursor{StartTime: time.Now()}).Token()
+		res, err := client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query: &api_v2.TraceQueryParameters{
+				ServiceName: "service",
+				SearchDepth: 1,
+			},
+			PageToken: token,
+		})
+		require.NoError(t, err)
+
+		spanResChunk, err := res.Recv()
+		require.NoError(t, err)
+		assert.Len(t, spanResChunk.Spans, len(mockTraceGRPC.Spans))
+		assert.Empty(t, spanResChunk.NextPageToken)
+		spanResChunk, err = res.Recv()
+		require.NoError(t, err)
+		assert.Empty(t, spanResChunk.Spans)
+		assert.NotEmpty(t, spanResChunk.NextPageToken)
+		_, err = res.Recv()
+		require.ErrorIs(t, err, io.EOF)
+		require.NotNil(t, query)
+		assert.Empty(t, query.PageToken)
+		assert.Equal(t, 2, query.NumTraces)
+	})
+}
+
+func TestFindTracesInvalidPageTokenGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		res, err := client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query:     &api_v2.TraceQueryParameters{ServiceName: "service"},
+			PageToken: "!!!",
+		})
+		require.NoError(t, err)
+
+		_, err = res.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
 func TestFindTracesSuccess_SpanStreamingGRPC(t *testing.T) {
 	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
 		server.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
@@ -499,6 +548,37 @@ func TestGetOperationsSuccessGRPC(t *testing.T) {
 	})
 }
 
+func TestGetOperationsFilteredGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		server.spanReader.On("GetOperations",
+			mock.AnythingOfType("*context.valueCtx"),
+			spanstore.OperationQueryParameters{ServiceName: "trifle", SpanKind: "server"},
+		).Return([]spanstore.Operation{
+			{Name: "GET /b", SpanKind: "server"},
+			{Name: "POST /a", SpanKind: "server"},
+			{Name: "GET /a", SpanKind: "server"},
+			{Name: "GET /c", SpanKind: "server"},
+		}, nil).Once()
+
+		res, err := client.GetOperations(context.Background(), &api_v2.GetOperationsRequest{
+			Service:      "trifle",
+			SpanKind:     "server",
+			NamePrefix:   "GET",
+			NameContains: "/",
+			Offset:       1,
+			Limit:        1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*api_v2.Operation{{Name: "GET /b", SpanKind: "server"}}, res.Operations)
+
+		_, err = client.GetOperations(context.Background(), &api_v2.GetOperationsRequest{
+			Service: "trifle",
+			Limit:   -1,
+		})
+		assertGRPCError(t, err, codes.InvalidArgument, "must not be negative")
+	})
+}
+
 func TestGetOperationsFailureGRPC(t *testing.T) {
 	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
 		server.spanReader.On("GetOperations",
@@ -25,6 +25,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
+	ui "github.com/jaegertracing/jaeger/model/json"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
@@ -138,3 +139,93 @@ func TestArchiveTrace_WriteErrors(t *testing.T) {
 		require.EqualError(t, err, `500 error from server: {"data":null,"total":0,"limit":0,"offset":0,"errors":[{"code":500,"msg":"cannot save\ncannot save"}]}`+"\n")
 	}, querysvc.QueryServiceOptions{ArchiveSpanWriter: mockWriter})
 }
+
+func TestBulkArchive(t *testing.T) {
+	otherTraceID := model.NewTraceID(0, 1)
+	mockWriter := &spanstoremocks.Writer{}
+	mockWriter.On("WriteSpan", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*model.Span")).
+		Return(nil).Times(2)
+	withTestServer(func(ts *testServer) {
+		ts.spanReader.On("FindTraceIDs", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+			Return([]model.TraceID{mockTraceID, otherTraceID}, nil).Times(3)
+		ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).Return(mockTrace, nil).Once()
+		ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), otherTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+
+		url := ts.server.URL + "/api/archive?service=service&start=1&end=2"
+		var dryRun struct {
+			Data bulkArchiveResponse `json:"data"`
+		}
+		require.NoError(t, postJSON(url, []string{}, &dryRun))
+		assert.False(t, dryRun.Data.Archived)
+		assert.Len(t, dryRun.Data.TraceIDs, 2)
+		assert.Equal(t, bulkArchiveToken([]model.TraceID{otherTraceID, mockTraceID}), dryRun.Data.ConfirmationToken)
+
+		var response structuredResponse
+		err := postJSON(url+"&confirm=invalid", []string{}, &response)
+		require.ErrorContains(t, err, "409 error from server")
+
+		var confirmed struct {
+			Data   bulkArchiveResponse `json:"data"`
+			Errors []structuredError   `json:"errors"`
+		}
+		require.NoError(t, postJSON(url+"&confirm="+dryRun.Data.ConfirmationToken, []string{}, &confirmed))
+		assert.True(t, confirmed.Data.Archived)
+		assert.Equal(t, []ui.TraceID{ui.TraceID(mockTraceID.String())}, confirmed.Data.TraceIDs)
+		require.Len(t, confirmed.Errors, 1)
+		assert.Contains(t, confirmed.Errors[0].Msg, "trace not found")
+	}, querysvc.QueryServiceOptions{ArchiveSpanWriter: mockWriter})
+}
+
+func TestBulkArchiveLimits(t *testing.T) {
+	ts := initializeTestServer(HandlerOptions.MaxBulkArchiveTraces(1))
+	defer ts.server.Close()
+
+	// the search looks for one more trace than the maximum, whatever the limit
+	ts.spanReader.On("FindTraceIDs", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(query *spanstore.TraceQueryParameters) bool {
+		return query.NumTraces == 2
+	})).Return([]model.TraceID{mockTraceID, model.NewTraceID(0, 1)}, nil).Once()
+	var response structuredResponse
+	err := postJSON(ts.server.URL+"/api/archive?service=service&limit=1", []string{}, &response)
+	require.ErrorContains(t, err, "more than 1 traces match the search, narrow it down")
+
+	err = postJSON(ts.server.URL+"/api/archive?traceID=1&traceID=2", []string{}, &response)
+	require.ErrorContains(t, err, "at most 1 traces can be archived at once")
+
+	err = postJSON(ts.server.URL+"/api/archive?limit=x", []string{}, &response)
+	require.ErrorContains(t, err, "400 error from server")
+	ts.spanReader.AssertExpectations(t)
+}
+
+func TestBulkArchiveNoStorage(t *testing.T) {
+	withTestServer(func(ts *testServer) {
+		var response structuredResponse
+		err := postJSON(ts.server.URL+"/api/archive?traceID="+mockTraceID.String()+"&confirm="+
+			bulkArchiveToken([]model.TraceID{mockTraceID}), []string{}, &response)
+		require.ErrorContains(t, err, "archive span storage was not configured")
+	}, querysvc.QueryServiceOptions{})
+}
+
+func TestSearchArchive(t *testing.T) {
+	mockReader := &spanstoremocks.Reader{}
+	mockReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{mockTrace}, nil).Once()
+	withTestServer(func(ts *testServer) {
+		var response structuredTraceResponse
+		err := getJSON(ts.server.URL+"/api/archive?service=service", &response)
+		require.NoError(t, err)
+		assert.Empty(t, response.Errors)
+		require.Len(t, response.Traces, 1)
+		assert.Equal(t, mockTraceID.String(), string(response.Traces[0].TraceID))
+	}, querysvc.QueryServiceOptions{ArchiveSpanReader: mockReader})
+}
+
+func TestSearchArchiveErrors(t *testing.T) {
+	withTestServer(func(ts *testServer) {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+"/api/archive?service=service", &response)
+		require.ErrorContains(t, err, "archive span storage was not configured")
+
+		err = getJSON(ts.server.URL+"/api/archive?limit=x", &response)
+		require.ErrorContains(t, err, "400 error from server")
+	}, querysvc.QueryServiceOptions{})
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 )
 
 func TestDeduplicateDependencies(t *testing.T) {
@@ -150,7 +151,7 @@ func TestDeduplicateDependencies(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		actual := handler.deduplicateDependencies(test.input)
+		actual := handler.deduplicateDependencies(annotatedLinks(test.input))
 		sort.Sort(DependencyLinks(actual))
 		expected := test.expected
 		sort.Sort(DependencyLinks(expected))
@@ -178,6 +179,36 @@ func (slice DependencyLinks) Swap(i, j int) {
 	slice[i], slice[j] = slice[j], slice[i]
 }
 
+func annotatedLinks(links []model.DependencyLink) []dependencystore.AnnotatedLink {
+	if links == nil {
+		return nil
+	}
+	result := make([]dependencystore.AnnotatedLink, len(links))
+	for i, link := range links {
+		result[i] = dependencystore.AnnotatedLink{DependencyLink: link}
+	}
+	return result
+}
+
+func TestDeduplicateDependenciesStats(t *testing.T) {
+	first := dependencystore.AnnotatedLink{
+		DependencyLink: model.DependencyLink{Parent: "a", Child: "b", CallCount: 2},
+		Stats:          &dependencystore.CallStats{ErrorCount: 1, TotalDuration: 2 * time.Second, MaxDuration: time.Second},
+	}
+	second := dependencystore.AnnotatedLink{
+		DependencyLink: model.DependencyLink{Parent: "a", Child: "b", CallCount: 2},
+		Stats:          &dependencystore.CallStats{TotalDuration: 4 * time.Second, MaxDuration: 3 * time.Second},
+	}
+	assert.Equal(t, []ui.DependencyLink{{
+		Parent:    "a",
+		Child:     "b",
+		CallCount: 4,
+		Stats:     &ui.DependencyLinkStats{ErrorCount: 1, ErrorRate: 0.25, AvgDuration: 1500000, MaxDuration: 3000000},
+	}}, (&APIHandler{}).deduplicateDependencies([]dependencystore.AnnotatedLink{first, second}))
+	// the inputs are not modified
+	assert.Equal(t, uint64(1), first.Stats.ErrorCount)
+}
+
 func TestFilterDependencies(t *testing.T) {
 	handler := &APIHandler{}
 	tests := []struct {
@@ -303,8 +334,35 @@ func TestFilterDependencies(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		actual := handler.filterDependenciesByService(test.dependencies, test.service)
-		assert.Equal(t, test.expected, actual, test.description, test.service)
+		var services []string
+		if test.service != "" {
+			services = []string{test.service}
+		}
+		actual := handler.filterDependenciesByService(annotatedLinks(test.dependencies), services, "")
+		assert.Equal(t, annotatedLinks(test.expected), actual, test.description, test.service)
+	}
+}
+
+func TestFilterDependenciesByServicesAndNamespace(t *testing.T) {
+	handler := &APIHandler{}
+	links := annotatedLinks([]model.DependencyLink{
+		{Parent: "Dáin I", Child: "Thrór", CallCount: 314},
+		{Parent: "Dáin I", Child: "Frór", CallCount: 159},
+		{Parent: "payments.api", Child: "payments.db", CallCount: 10},
+		{Parent: "frontend", Child: "payments.api", CallCount: 20},
+	})
+	tests := []struct {
+		description string
+		services    []string
+		namespace   string
+		expected    []dependencystore.AnnotatedLink
+	}{
+		{"by several services", []string{"Thrór", "frontend"}, "", []dependencystore.AnnotatedLink{links[0], links[3]}},
+		{"by namespace", nil, "payments.", links[2:]},
+		{"by service and namespace", []string{"frontend"}, "payments.", links[3:]},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, handler.filterDependenciesByService(links, test.services, test.namespace), test.description)
 	}
 }
 
@@ -354,3 +412,83 @@ func TestGetDependenciesLookbackParsingFailure(t *testing.T) {
 	err := getJSON(ts.server.URL+"/api/dependencies?endTs=1476374248550&service=testing&lookback=shazbot", &response)
 	require.Error(t, err)
 }
+
+func TestGetDependenciesFiltered(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	endTs := time.Unix(0, 1476374248550*millisToNanosMultiplier)
+	ts.dependencyReader.On("GetDependencies", endTs, defaultDependencyLookbackDuration).Return([]model.DependencyLink{
+		{Parent: "killer", Child: "queen", CallCount: 12},
+		{Parent: "killer", Child: "queen", CallCount: 3},
+		{Parent: "queen", Child: "freddie", CallCount: 1},
+		{Parent: "brian", Child: "roger", CallCount: 1},
+	}, nil).Times(1)
+
+	var response struct {
+		Data []ui.DependencyLink `json:"data"`
+	}
+	err := getJSON(ts.server.URL+"/api/dependencies?endTs=1476374248550&service=killer&service=freddie", &response)
+	require.NoError(t, err)
+	assert.Equal(t, []ui.DependencyLink{
+		{Parent: "killer", Child: "queen", CallCount: 15},
+		{Parent: "queen", Child: "freddie", CallCount: 1},
+	}, response.Data)
+}
+
+func TestGetDependenciesTimeBuckets(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	endTs := time.Unix(0, 1476374248550*millisToNanosMultiplier)
+	ts.dependencyReader.On("GetDependencies", endTs, time.Minute).
+		Return([]model.DependencyLink{{Parent: "killer", Child: "queen", CallCount: 12}}, nil).Times(1)
+	ts.dependencyReader.On("GetDependencies", endTs.Add(-time.Minute), time.Minute).
+		Return(nil, nil).Times(1)
+
+	var response struct {
+		Data []ui.DependencyGraph `json:"data"`
+	}
+	err := getJSON(ts.server.URL+"/api/dependencies?endTs=1476374248550&lookback=120000&step=60000", &response)
+	require.NoError(t, err)
+	assert.Equal(t, []ui.DependencyGraph{
+		{
+			StartTime: model.TimeAsEpochMicroseconds(endTs.Add(-2 * time.Minute)),
+			EndTime:   model.TimeAsEpochMicroseconds(endTs.Add(-time.Minute)),
+			Links:     []ui.DependencyLink{},
+		},
+		{
+			StartTime: model.TimeAsEpochMicroseconds(endTs.Add(-time.Minute)),
+			EndTime:   model.TimeAsEpochMicroseconds(endTs),
+			Links:     []ui.DependencyLink{{Parent: "killer", Child: "queen", CallCount: 12}},
+		},
+	}, response.Data)
+}
+
+func TestGetDependenciesStepParsingFailure(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	for _, step := range []string{"shazbot", "-1", "1"} {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+"/api/dependencies?endTs=1476374248550&step="+step, &response)
+		require.ErrorContains(t, err, "400 error from server", step)
+	}
+}
+
+func TestConvertDependencyLinksToUI(t *testing.T) {
+	links := []dependencystore.AnnotatedLink{
+		{
+			DependencyLink: model.DependencyLink{Parent: "a", Child: "b", CallCount: 4},
+			Stats:          &dependencystore.CallStats{ErrorCount: 1, TotalDuration: 8 * time.Millisecond, MaxDuration: 5 * time.Millisecond},
+		},
+		{DependencyLink: model.DependencyLink{Parent: "b", Child: "c", CallCount: 2}},
+	}
+	assert.Equal(t, []ui.DependencyLink{
+		{
+			Parent:    "a",
+			Child:     "b",
+			CallCount: 4,
+			Stats:     &ui.DependencyLinkStats{ErrorCount: 1, ErrorRate: 0.25, AvgDuration: 2000, MaxDuration: 5000},
+		},
+		{Parent: "b", Child: "c", CallCount: 2},
+	}, (&APIHandler{}).convertDependencyLinksToUI(links))
+}
@@ -61,6 +61,13 @@ func (handlerOptions) QueryLookbackDuration(queryLookbackDuration time.Duration)
 	}
 }
 
+// MaxBulkArchiveTraces creates a HandlerOption that limits the number of traces archived by one request
+func (handlerOptions) MaxBulkArchiveTraces(maxTraces int) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.maxBulkArchiveTraces = maxTraces
+	}
+}
+
 // Tracer creates a HandlerOption that passes the tracer to the handler
 func (handlerOptions) Tracer(tracer *jtracer.JTracer) HandlerOption {
 	return func(apiHandler *APIHandler) {
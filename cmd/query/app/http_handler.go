@@ -17,13 +17,18 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -37,43 +42,75 @@ import (
 	uiconv "github.com/jaegertracing/jaeger/model/converter/json"
 	ui "github.com/jaegertracing/jaeger/model/json"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
+	"github.com/jaegertracing/jaeger/pkg/oidc"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 const (
 	traceIDParam          = "traceID"
+	traceAParam           = "traceA"
+	traceBParam           = "traceB"
 	endTsParam            = "endTs"
 	lookbackParam         = "lookback"
 	stepParam             = "step"
 	rateParam             = "ratePer"
 	quantileParam         = "quantile"
 	groupByOperationParam = "groupByOperation"
+	savedSearchIDParam    = "savedSearchID"
+	ownerParam            = "owner"
+	confirmParam          = "confirm"
 
 	defaultAPIPrefix  = "api"
 	prettyPrintIndent = "    "
+
+	// defaultMaxBulkArchiveTraces is the default maximum number of traces archived by one request
+	defaultMaxBulkArchiveTraces = 1000
 )
 
+// errSavedSearchNameRequired occurs when a saved search is created without a name.
+var errSavedSearchNameRequired = errors.New("saved search name is required")
+
+// errSavedSearchOwner occurs when an authenticated user uses the saved searches of another owner.
+var errSavedSearchOwner = errors.New("saved searches of other owners are not allowed")
+
 // HTTPHandler handles http requests
 type HTTPHandler interface {
 	RegisterRoutes(router *mux.Router)
 }
 
 type structuredResponse struct {
-	Data   interface{}       `json:"data"`
-	Total  int               `json:"total"`
-	Limit  int               `json:"limit"`
-	Offset int               `json:"offset"`
-	Errors []structuredError `json:"errors"`
+	Data          interface{}       `json:"data"`
+	Total         int               `json:"total"`
+	Limit         int               `json:"limit"`
+	Offset        int               `json:"offset"`
+	NextPageToken string            `json:"nextPageToken,omitempty"`
+	Errors        []structuredError `json:"errors"`
+}
+
+// bulkArchiveResponse is the data of the responses of the bulk archiving API.
+type bulkArchiveResponse struct {
+	// TraceIDs are the IDs of the matching traces, or of the archived traces once confirmed.
+	TraceIDs []ui.TraceID `json:"traceIDs"`
+	// ConfirmationToken must be passed in the confirm parameter to archive the matching traces.
+	ConfirmationToken string `json:"confirmationToken,omitempty"`
+	// Archived is true if the traces were archived.
+	Archived bool `json:"archived"`
 }
 
 type structuredError struct {
 	Code    int        `json:"code,omitempty"`
 	Msg     string     `json:"msg"`
 	TraceID ui.TraceID `json:"traceID,omitempty"`
+	// Param is the name of the invalid request parameter, if any.
+	Param string `json:"param,omitempty"`
+	// Reason is the reason why the parameter is not valid, i.e. required, invalid or out_of_range.
+	Reason string `json:"reason,omitempty"`
 }
 
 // NewRouter creates and configures a Gorilla Router.
@@ -86,11 +123,14 @@ type APIHandler struct {
 	queryService        *querysvc.QueryService
 	metricsQueryService querysvc.MetricsQueryService
 	queryParser         queryParser
+	apiSpec             *apiSpec
 	tenancyMgr          *tenancy.Manager
 	basePath            string
 	apiPrefix           string
 	logger              *zap.Logger
 	tracer              *jtracer.JTracer
+	// maxBulkArchiveTraces is the maximum number of traces archived by one request
+	maxBulkArchiveTraces int
 }
 
 // NewAPIHandler returns an APIHandler
@@ -101,6 +141,7 @@ func NewAPIHandler(queryService *querysvc.QueryService, tm *tenancy.Manager, opt
 			traceQueryLookbackDuration: defaultTraceQueryLookbackDuration,
 			timeNow:                    time.Now,
 		},
+		apiSpec:    defaultAPISpec,
 		tenancyMgr: tm,
 	}
 
@@ -110,6 +151,9 @@ func NewAPIHandler(queryService *querysvc.QueryService, tm *tenancy.Manager, opt
 	if aH.apiPrefix == "" {
 		aH.apiPrefix = defaultAPIPrefix
 	}
+	if aH.maxBulkArchiveTraces <= 0 {
+		aH.maxBulkArchiveTraces = defaultMaxBulkArchiveTraces
+	}
 	if aH.logger == nil {
 		aH.logger = zap.NewNop()
 	}
@@ -121,9 +165,19 @@ func NewAPIHandler(queryService *querysvc.QueryService, tm *tenancy.Manager, opt
 
 // RegisterRoutes registers routes for this handler on the given router
 func (aH *APIHandler) RegisterRoutes(router *mux.Router) {
+	// the OpenAPI document is public, like the UI, even when the tenancy is enabled
+	router.HandleFunc(aH.formatRoute(openAPIRoute), aH.getOpenAPIDocument).Methods(http.MethodGet)
+	// must be registered before /traces/{traceID} which would otherwise match it
+	aH.handleFunc(router, aH.compareTraces, "/traces/compare").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.getTrace, "/traces/{%s}", traceIDParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.getCriticalPath, "/traces/{%s}/critical-path", traceIDParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.getTraceQuality, "/traces/{%s}/quality", traceIDParam).Methods(http.MethodGet)
 	aH.handleFunc(router, aH.archiveTrace, "/archive/{%s}", traceIDParam).Methods(http.MethodPost)
+	aH.handleFunc(router, aH.bulkArchive, "/archive").Methods(http.MethodPost)
+	aH.handleFunc(router, aH.searchArchive, "/archive").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.search, "/traces").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.flameGraph, "/flamegraph").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.getServiceQuality, "/quality").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.getServices, "/services").Methods(http.MethodGet)
 	// TODO change the UI to use this endpoint. Requires ?service= parameter.
 	aH.handleFunc(router, aH.getOperations, "/operations").Methods(http.MethodGet)
@@ -131,10 +185,17 @@ func (aH *APIHandler) RegisterRoutes(router *mux.Router) {
 	aH.handleFunc(router, aH.getOperationsLegacy, "/services/{%s}/operations", serviceParam).Methods(http.MethodGet)
 	aH.handleFunc(router, aH.transformOTLP, "/transform").Methods(http.MethodPost)
 	aH.handleFunc(router, aH.dependencies, "/dependencies").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.durations, "/durations").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.getTagKeys, "/tags").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.getTagValues, "/tags/values").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.latencies, "/metrics/latencies").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.calls, "/metrics/calls").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.errors, "/metrics/errors").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.minStep, "/metrics/minstep").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.listSavedSearches, "/saved-searches").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.createSavedSearch, "/saved-searches").Methods(http.MethodPost)
+	aH.handleFunc(router, aH.getSavedSearch, "/saved-searches/{%s}", savedSearchIDParam).Methods(http.MethodGet)
+	aH.handleFunc(router, aH.deleteSavedSearch, "/saved-searches/{%s}", savedSearchIDParam).Methods(http.MethodDelete)
 }
 
 func (aH *APIHandler) handleFunc(
@@ -144,7 +205,7 @@ func (aH *APIHandler) handleFunc(
 	args ...interface{},
 ) *mux.Route {
 	route := aH.formatRoute(routeFmt, args...)
-	var handler http.Handler = http.HandlerFunc(f)
+	var handler http.Handler = aH.validateParams(fmt.Sprintf(routeFmt, args...), http.HandlerFunc(f))
 	if aH.tenancyMgr.Enabled {
 		handler = tenancy.ExtractTenantHTTPHandler(aH.tenancyMgr, handler)
 	}
@@ -212,17 +273,9 @@ func (aH *APIHandler) transformOTLP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (aH *APIHandler) getOperations(w http.ResponseWriter, r *http.Request) {
-	service := r.FormValue(serviceParam)
-	if service == "" {
-		if aH.handleError(w, errServiceParameterRequired, http.StatusBadRequest) {
-			return
-		}
-	}
-	spanKind := r.FormValue(spanKindParam)
-	operations, err := aH.queryService.GetOperations(
-		r.Context(),
-		spanstore.OperationQueryParameters{ServiceName: service, SpanKind: spanKind},
-	)
+	query := aH.queryParser.parseOperationsQueryParams(r)
+	// the page is selected by the storage, so only the operations of the page are counted
+	operations, err := aH.queryService.GetOperations(r.Context(), query)
 
 	if aH.handleError(w, err, http.StatusInternalServerError) {
 		return
@@ -235,8 +288,10 @@ func (aH *APIHandler) getOperations(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	structuredRes := structuredResponse{
-		Data:  data,
-		Total: len(operations),
+		Data:   data,
+		Total:  len(data),
+		Limit:  query.Limit,
+		Offset: query.Offset,
 	}
 	aH.writeJSON(w, r, &structuredRes)
 }
@@ -246,22 +301,34 @@ func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
 	if aH.handleError(w, err, http.StatusBadRequest) {
 		return
 	}
+	format := parseExportFormat(r)
 
 	var uiErrors []structuredError
 	var tracesFromStorage []*model.Trace
+	var nextPageToken string
 	if len(tQuery.traceIDs) > 0 {
 		tracesFromStorage, uiErrors, err = aH.tracesByIDs(r.Context(), tQuery.traceIDs)
 		if aH.handleError(w, err, http.StatusInternalServerError) {
 			return
 		}
 	} else {
-		tracesFromStorage, err = aH.queryService.FindTraces(r.Context(), &tQuery.TraceQueryParameters)
+		page, err := aH.queryService.FindTracesPage(r.Context(), &tQuery.TraceQueryParameters)
+		if errors.Is(err, spanstore.ErrInvalidPageToken) {
+			aH.handleError(w, err, http.StatusBadRequest)
+			return
+		}
 		if aH.handleError(w, err, http.StatusInternalServerError) {
 			return
 		}
+		tracesFromStorage, nextPageToken = page.Traces, page.NextPageToken
+	}
+	if format != exportFormatJSON {
+		aH.exportTraces(w, format, tracesFromStorage)
+		return
 	}
 
 	structuredRes := aH.tracesToResponse(tracesFromStorage, true, uiErrors)
+	structuredRes.NextPageToken = nextPageToken
 	aH.writeJSON(w, r, structuredRes)
 }
 
@@ -281,6 +348,42 @@ func (aH *APIHandler) tracesToResponse(traces []*model.Trace, adjust bool, uiErr
 	}
 }
 
+// flameGraph implements the REST API /flamegraph. It accepts the same parameters
+// as the trace search and merges the matching traces into a single call-tree.
+func (aH *APIHandler) flameGraph(w http.ResponseWriter, r *http.Request) {
+	tQuery, err := aH.queryParser.parseTraceQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	if tQuery.ServiceName == "" {
+		aH.handleError(w, errServiceParameterRequired, http.StatusBadRequest)
+		return
+	}
+
+	root, err := aH.queryService.GetFlameGraph(r.Context(), &tQuery.TraceQueryParameters)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data: flameGraphToUI(root),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func flameGraphToUI(node *querysvc.FlameGraphNode) *ui.FlameGraphNode {
+	uiNode := &ui.FlameGraphNode{
+		ServiceName:   node.Service,
+		OperationName: node.Operation,
+		Count:         node.Count,
+		TotalTime:     model.DurationAsMicroseconds(node.TotalTime),
+		SelfTime:      model.DurationAsMicroseconds(node.SelfTime),
+	}
+	for _, child := range node.Children {
+		uiNode.Children = append(uiNode.Children, flameGraphToUI(child))
+	}
+	return uiNode
+}
+
 func (aH *APIHandler) tracesByIDs(ctx context.Context, traceIDs []model.TraceID) ([]*model.Trace, []structuredError, error) {
 	var traceErrors []structuredError
 	retMe := make([]*model.Trace, 0, len(traceIDs))
@@ -305,26 +408,99 @@ func (aH *APIHandler) dependencies(w http.ResponseWriter, r *http.Request) {
 	if aH.handleError(w, err, http.StatusBadRequest) {
 		return
 	}
-	service := r.FormValue(serviceParam)
 
-	dependencies, err := aH.queryService.GetDependencies(r.Context(), dqp.endTs, dqp.lookback)
+	graphs, err := aH.queryService.GetDependencyGraphs(r.Context(), dqp.DependencyGraphQuery)
 	if aH.handleError(w, err, http.StatusInternalServerError) {
 		return
 	}
 
-	filteredDependencies := aH.filterDependenciesByService(dependencies, service)
+	links := func(graph querysvc.DependencyGraph) []ui.DependencyLink {
+		return aH.deduplicateDependencies(aH.filterDependenciesByService(graph.Links, dqp.services, dqp.namespace))
+	}
+	var structuredRes structuredResponse
+	if dqp.Step > 0 {
+		data := make([]ui.DependencyGraph, len(graphs))
+		for i, graph := range graphs {
+			data[i] = ui.DependencyGraph{
+				StartTime: model.TimeAsEpochMicroseconds(graph.StartTime),
+				EndTime:   model.TimeAsEpochMicroseconds(graph.EndTime),
+				Links:     links(graph),
+			}
+		}
+		structuredRes.Data = data
+	} else {
+		structuredRes.Data = links(graphs[0])
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func (aH *APIHandler) durations(w http.ResponseWriter, r *http.Request) {
+	query, err := aH.queryParser.parseLatencyQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+
+	dist, err := aH.queryService.GetLatencyDistribution(r.Context(), query)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	data := ui.LatencyDistribution{
+		Count:   dist.Count,
+		Buckets: make([]ui.DurationBucket, len(dist.Buckets)),
+		P50:     model.DurationAsMicroseconds(dist.P50),
+		P95:     model.DurationAsMicroseconds(dist.P95),
+		P99:     model.DurationAsMicroseconds(dist.P99),
+	}
+	for i, bucket := range dist.Buckets {
+		data.Buckets[i] = ui.DurationBucket{
+			From:  model.DurationAsMicroseconds(bucket.From),
+			To:    model.DurationAsMicroseconds(bucket.To),
+			Count: bucket.Count,
+		}
+	}
 	structuredRes := structuredResponse{
-		Data: aH.deduplicateDependencies(filteredDependencies),
+		Data: data,
 	}
 	aH.writeJSON(w, r, &structuredRes)
 }
 
-func (aH *APIHandler) latencies(w http.ResponseWriter, r *http.Request) {
-	q, err := strconv.ParseFloat(r.FormValue(quantileParam), 64)
-	if err != nil {
-		aH.handleError(w, newParseError(err, quantileParam), http.StatusBadRequest)
+func (aH *APIHandler) getTagKeys(w http.ResponseWriter, r *http.Request) {
+	query, err := aH.queryParser.parseTagQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	keys, err := aH.queryService.GetTagKeys(r.Context(), query)
+	aH.writeTags(w, r, keys, err)
+}
+
+func (aH *APIHandler) getTagValues(w http.ResponseWriter, r *http.Request) {
+	query, err := aH.queryParser.parseTagQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	values, err := aH.queryService.GetTagValues(r.Context(), query)
+	aH.writeTags(w, r, values, err)
+}
+
+// writeTags writes the tag keys or values, or the error listing them. No suggestions are
+// returned when the span storage cannot list the tags, so that the UI falls back to free text.
+func (aH *APIHandler) writeTags(w http.ResponseWriter, r *http.Request, tags []string, err error) {
+	if errors.Is(err, spanstore.ErrTagsNotSupported) {
+		tags, err = []string{}, nil
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
 		return
 	}
+	structuredRes := structuredResponse{
+		Data:  tags,
+		Total: len(tags),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func (aH *APIHandler) latencies(w http.ResponseWriter, r *http.Request) {
+	q, _ := strconv.ParseFloat(r.FormValue(quantileParam), 64)
 	aH.metrics(w, r, func(ctx context.Context, baseParams metricsstore.BaseQueryParameters) (*metrics.MetricFamily, error) {
 		return aH.metricsQueryService.GetLatencies(ctx, &metricsstore.LatenciesQueryParameters{
 			BaseQueryParameters: baseParams,
@@ -362,11 +538,7 @@ func (aH *APIHandler) minStep(w http.ResponseWriter, r *http.Request) {
 }
 
 func (aH *APIHandler) metrics(w http.ResponseWriter, r *http.Request, getMetrics func(context.Context, metricsstore.BaseQueryParameters) (*metrics.MetricFamily, error)) {
-	requestParams, err := aH.queryParser.parseMetricsQueryParams(r)
-	if aH.handleError(w, err, http.StatusBadRequest) {
-		return
-	}
-	m, err := getMetrics(r.Context(), requestParams)
+	m, err := getMetrics(r.Context(), aH.queryParser.parseMetricsQueryParams(r))
 	if aH.handleError(w, err, http.StatusInternalServerError) {
 		return
 	}
@@ -393,62 +565,108 @@ func (aH *APIHandler) convertModelToUI(trace *model.Trace, adjust bool) (*ui.Tra
 	return uiTrace, uiError
 }
 
-func (aH *APIHandler) deduplicateDependencies(dependencies []model.DependencyLink) []ui.DependencyLink {
+// deduplicateDependencies merges the links between the same parent and child, sorted by parent then child.
+func (aH *APIHandler) deduplicateDependencies(dependencies []dependencystore.AnnotatedLink) []ui.DependencyLink {
 	type Key struct {
 		parent string
 		child  string
 	}
-	links := make(map[Key]uint64)
+	links := make(map[Key]*dependencystore.AnnotatedLink)
 
 	for _, l := range dependencies {
-		links[Key{l.Parent, l.Child}] += l.CallCount
+		k := Key{l.Parent, l.Child}
+		link, ok := links[k]
+		if !ok {
+			link = &dependencystore.AnnotatedLink{DependencyLink: model.DependencyLink{Parent: l.Parent, Child: l.Child}}
+			links[k] = link
+		}
+		link.CallCount += l.CallCount
+		if l.Stats != nil {
+			if link.Stats == nil {
+				link.Stats = &dependencystore.CallStats{}
+			}
+			link.Stats.Add(*l.Stats)
+		}
 	}
 
-	result := make([]ui.DependencyLink, 0, len(links))
-	for k, v := range links {
-		result = append(result, ui.DependencyLink{Parent: k.parent, Child: k.child, CallCount: v})
+	result := make([]dependencystore.AnnotatedLink, 0, len(links))
+	for _, link := range links {
+		result = append(result, *link)
 	}
-
-	return result
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Parent != result[j].Parent {
+			return result[i].Parent < result[j].Parent
+		}
+		return result[i].Child < result[j].Child
+	})
+	return aH.convertDependencyLinksToUI(result)
 }
 
-func (aH *APIHandler) filterDependenciesByService(
-	dependencies []model.DependencyLink,
-	service string,
-) []model.DependencyLink {
-	if len(service) == 0 {
+// filterDependenciesByService keeps the links whose parent or child is one of the services,
+// if any, and whose parent or child name starts with the namespace, if not empty.
+func (*APIHandler) filterDependenciesByService(
+	dependencies []dependencystore.AnnotatedLink,
+	services []string,
+	namespace string,
+) []dependencystore.AnnotatedLink {
+	if len(services) == 0 && namespace == "" {
 		return dependencies
 	}
 
-	var filteredDependencies []model.DependencyLink
+	matches := func(dependency dependencystore.AnnotatedLink, match func(service string) bool) bool {
+		return match(dependency.Parent) || match(dependency.Child)
+	}
+	var filteredDependencies []dependencystore.AnnotatedLink
 	for _, dependency := range dependencies {
-		if dependency.Parent == service || dependency.Child == service {
-			filteredDependencies = append(filteredDependencies, dependency)
+		if len(services) > 0 && !matches(dependency, func(service string) bool {
+			return slices.Contains(services, service)
+		}) {
+			continue
 		}
+		if namespace != "" && !matches(dependency, func(service string) bool {
+			return strings.HasPrefix(service, namespace)
+		}) {
+			continue
+		}
+		filteredDependencies = append(filteredDependencies, dependency)
 	}
 	return filteredDependencies
 }
 
-// Parses trace ID from URL like /traces/{trace-id}
-func (aH *APIHandler) parseTraceID(w http.ResponseWriter, r *http.Request) (model.TraceID, bool) {
-	vars := mux.Vars(r)
-	traceIDVar := vars[traceIDParam]
-	traceID, err := model.TraceIDFromString(traceIDVar)
-	if aH.handleError(w, err, http.StatusBadRequest) {
-		return traceID, false
+func (*APIHandler) convertDependencyLinksToUI(links []dependencystore.AnnotatedLink) []ui.DependencyLink {
+	result := make([]ui.DependencyLink, len(links))
+	for i, link := range links {
+		result[i] = ui.DependencyLink{Parent: link.Parent, Child: link.Child, CallCount: link.CallCount}
+		if link.Stats != nil && link.CallCount > 0 {
+			result[i].Stats = &ui.DependencyLinkStats{
+				ErrorCount:  link.Stats.ErrorCount,
+				ErrorRate:   float64(link.Stats.ErrorCount) / float64(link.CallCount),
+				AvgDuration: model.DurationAsMicroseconds(link.Stats.TotalDuration / time.Duration(link.CallCount)),
+				MaxDuration: model.DurationAsMicroseconds(link.Stats.MaxDuration),
+			}
+		}
 	}
-	return traceID, true
+	return result
+}
+
+// Parses trace ID from URL like /traces/{trace-id}
+// parseTraceID returns the trace ID of the path, validated against the OpenAPI document.
+func parseTraceID(r *http.Request) model.TraceID {
+	traceID, _ := model.TraceIDFromString(mux.Vars(r)[traceIDParam])
+	return traceID
 }
 
-// getTrace implements the REST API /traces/{trace-id}
+// getTrace implements the REST API /traces/{trace-id}?start={start}&end={end}
 // It parses trace ID from the path, fetches the trace from QueryService,
 // formats it in the UI JSON format, and responds to the client.
+// The optional start and end times in microseconds restrict the search to a time range.
 func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
-	traceID, ok := aH.parseTraceID(w, r)
-	if !ok {
+	traceID := parseTraceID(r)
+	query, err := aH.queryParser.parseGetTraceQueryParams(r, traceID)
+	if aH.handleError(w, err, http.StatusBadRequest) {
 		return
 	}
-	trace, err := aH.queryService.GetTrace(r.Context(), traceID)
+	trace, err := aH.queryService.GetTraceInTimeRange(r.Context(), query)
 	if errors.Is(err, spanstore.ErrTraceNotFound) {
 		aH.handleError(w, err, http.StatusNotFound)
 		return
@@ -462,6 +680,171 @@ func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, structuredRes)
 }
 
+// compareTraces implements the REST API /traces/compare?traceA={trace-id}&traceB={trace-id}.
+// It returns the structural differences between the two traces.
+func (aH *APIHandler) compareTraces(w http.ResponseWriter, r *http.Request) {
+	var traceIDs [2]model.TraceID
+	for i, param := range []string{traceAParam, traceBParam} {
+		traceIDs[i], _ = model.TraceIDFromString(r.FormValue(param))
+	}
+	comparison, err := aH.queryService.CompareTraces(r.Context(), traceIDs[0], traceIDs[1])
+	if errors.Is(err, spanstore.ErrTraceNotFound) {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	data := ui.TraceComparison{
+		TraceIDA:   ui.TraceID(traceIDs[0].String()),
+		TraceIDB:   ui.TraceID(traceIDs[1].String()),
+		Added:      spanPathDiffsToUI(comparison.Added),
+		Removed:    spanPathDiffsToUI(comparison.Removed),
+		Changed:    spanPathDiffsToUI(comparison.Changed),
+		Operations: make([]ui.OperationDiff, len(comparison.Operations)),
+	}
+	for i, op := range comparison.Operations {
+		data.Operations[i] = ui.OperationDiff{
+			OperationRef:  ui.OperationRef{ServiceName: op.Service, OperationName: op.Operation},
+			CountA:        op.A.Count,
+			CountB:        op.B.Count,
+			DurationA:     model.DurationAsMicroseconds(op.A.Duration),
+			DurationB:     model.DurationAsMicroseconds(op.B.Duration),
+			DurationDelta: (op.B.Duration - op.A.Duration).Microseconds(),
+		}
+	}
+	structuredRes := structuredResponse{
+		Data: data,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func spanPathDiffsToUI(diffs []querysvc.SpanPathDiff) []ui.SpanPathDiff {
+	retMe := make([]ui.SpanPathDiff, len(diffs))
+	for i, diff := range diffs {
+		path := make([]ui.OperationRef, len(diff.Path))
+		for j, key := range diff.Path {
+			path[j] = ui.OperationRef{ServiceName: key.Service, OperationName: key.Operation}
+		}
+		retMe[i] = ui.SpanPathDiff{
+			Path:          path,
+			CountA:        diff.A.Count,
+			CountB:        diff.B.Count,
+			DurationA:     model.DurationAsMicroseconds(diff.A.Duration),
+			DurationB:     model.DurationAsMicroseconds(diff.B.Duration),
+			DurationDelta: (diff.B.Duration - diff.A.Duration).Microseconds(),
+		}
+	}
+	return retMe
+}
+
+// getCriticalPath implements the REST API /traces/{trace-id}/critical-path
+func (aH *APIHandler) getCriticalPath(w http.ResponseWriter, r *http.Request) {
+	traceID := parseTraceID(r)
+	criticalPath, err := aH.queryService.GetCriticalPath(r.Context(), traceID)
+	if errors.Is(err, spanstore.ErrTraceNotFound) {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	data := ui.CriticalPath{
+		TraceID:    ui.TraceID(traceID.String()),
+		Segments:   make([]ui.CriticalPathSegment, len(criticalPath.Segments)),
+		Operations: make([]ui.OperationTime, len(criticalPath.Operations)),
+	}
+	for i, segment := range criticalPath.Segments {
+		data.Segments[i] = ui.CriticalPathSegment{
+			SpanID:       ui.SpanID(segment.SpanID.String()),
+			OperationRef: ui.OperationRef{ServiceName: segment.Service, OperationName: segment.Operation},
+			StartTime:    model.TimeAsEpochMicroseconds(segment.StartTime),
+			Duration:     model.DurationAsMicroseconds(segment.Duration),
+		}
+	}
+	for i, op := range criticalPath.Operations {
+		data.Operations[i] = ui.OperationTime{
+			OperationRef: ui.OperationRef{ServiceName: op.Service, OperationName: op.Operation},
+			Duration:     model.DurationAsMicroseconds(op.Duration),
+		}
+	}
+	structuredRes := structuredResponse{
+		Data: data,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// getTraceQuality implements the REST API /traces/{trace-id}/quality.
+// It returns the instrumentation quality report of the services of the trace.
+func (aH *APIHandler) getTraceQuality(w http.ResponseWriter, r *http.Request) {
+	traceID := parseTraceID(r)
+	report, err := aH.queryService.GetTraceQuality(r.Context(), traceID)
+	if errors.Is(err, spanstore.ErrTraceNotFound) {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data: traceQualityToUI(report),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// getServiceQuality implements the REST API /quality. It accepts the same parameters
+// as the trace search and returns the instrumentation quality report of the services
+// of the matching traces.
+func (aH *APIHandler) getServiceQuality(w http.ResponseWriter, r *http.Request) {
+	tQuery, err := aH.queryParser.parseTraceQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	if tQuery.ServiceName == "" {
+		aH.handleError(w, errServiceParameterRequired, http.StatusBadRequest)
+		return
+	}
+
+	report, err := aH.queryService.GetServiceQuality(r.Context(), &tQuery.TraceQueryParameters)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data: traceQualityToUI(report),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func traceQualityToUI(report *querysvc.TraceQualityReport) ui.TraceQualityReport {
+	data := ui.TraceQualityReport{
+		Traces:   report.Traces,
+		Services: make([]ui.ServiceQuality, len(report.Services)),
+	}
+	for i, service := range report.Services {
+		uiService := ui.ServiceQuality{
+			ServiceName: service.Service,
+			Traces:      service.Traces,
+			Score:       service.Score,
+			Checks:      make([]ui.QualityCheckResult, len(service.Checks)),
+		}
+		for j, check := range service.Checks {
+			uiCheck := ui.QualityCheckResult{
+				Check:   string(check.Check),
+				Checked: check.Checked,
+				Failed:  check.Failed,
+			}
+			for _, traceID := range check.TraceIDs {
+				uiCheck.TraceIDs = append(uiCheck.TraceIDs, ui.TraceID(traceID.String()))
+			}
+			uiService.Checks[j] = uiCheck
+		}
+		data.Services[i] = uiService
+	}
+	return data
+}
+
 func shouldAdjust(r *http.Request) bool {
 	raw := r.FormValue("raw")
 	isRaw, _ := strconv.ParseBool(raw)
@@ -471,10 +854,7 @@ func shouldAdjust(r *http.Request) bool {
 // archiveTrace implements the REST API POST:/archive/{trace-id}.
 // It passes the traceID to queryService.ArchiveTrace for writing.
 func (aH *APIHandler) archiveTrace(w http.ResponseWriter, r *http.Request) {
-	traceID, ok := aH.parseTraceID(w, r)
-	if !ok {
-		return
-	}
+	traceID := parseTraceID(r)
 
 	// QueryService.ArchiveTrace can now archive this traceID.
 	err := aH.queryService.ArchiveTrace(r.Context(), traceID)
@@ -493,23 +873,267 @@ func (aH *APIHandler) archiveTrace(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+// bulkArchive implements the REST API POST:/archive. It accepts the parameters of the trace search
+// and archives the matching traces in two steps: without the confirm parameter, it only returns the
+// IDs of the matching traces and a confirmation token; with the token in the confirm parameter, it
+// archives them, unless the matching traces have changed in the meantime. Explicit start and end
+// parameters keep the matching traces from changing as new traces are received. The limit parameter
+// is ignored, the searches matching more traces than the maximum are rejected.
+func (aH *APIHandler) bulkArchive(w http.ResponseWriter, r *http.Request) {
+	tQuery, err := aH.queryParser.parseTraceQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	traceIDs := tQuery.traceIDs
+	if len(traceIDs) == 0 {
+		// one more trace than the maximum is searched, to reject the searches matching too many traces
+		// instead of archiving some of them only
+		tQuery.NumTraces = aH.maxBulkArchiveTraces + 1
+		traceIDs, err = aH.queryService.FindTraceIDs(r.Context(), &tQuery.TraceQueryParameters)
+		if aH.handleError(w, err, http.StatusInternalServerError) {
+			return
+		}
+		if len(traceIDs) > aH.maxBulkArchiveTraces {
+			aH.handleError(w, fmt.Errorf("more than %d traces match the search, narrow it down", aH.maxBulkArchiveTraces), http.StatusBadRequest)
+			return
+		}
+	}
+	if len(traceIDs) > aH.maxBulkArchiveTraces {
+		aH.handleError(w, fmt.Errorf("at most %d traces can be archived at once", aH.maxBulkArchiveTraces), http.StatusBadRequest)
+		return
+	}
+
+	token := bulkArchiveToken(traceIDs)
+	confirm := r.FormValue(confirmParam)
+	if confirm == "" {
+		aH.writeBulkArchiveResponse(w, r, traceIDs, token, false, nil)
+		return
+	}
+	if confirm != token {
+		aH.handleError(w, errors.New("the matching traces have changed, request a new confirmation token"), http.StatusConflict)
+		return
+	}
+	archived, err := aH.queryService.ArchiveTraces(r.Context(), traceIDs)
+	if len(archived) == 0 && aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	var uiErrors []structuredError
+	if err != nil {
+		uiErrors = append(uiErrors, structuredError{Msg: err.Error()})
+	}
+	aH.writeBulkArchiveResponse(w, r, archived, "", true, uiErrors)
+}
+
+func (aH *APIHandler) writeBulkArchiveResponse(
+	w http.ResponseWriter,
+	r *http.Request,
+	traceIDs []model.TraceID,
+	token string,
+	archived bool,
+	uiErrors []structuredError,
+) {
+	data := bulkArchiveResponse{
+		TraceIDs:          make([]ui.TraceID, len(traceIDs)),
+		ConfirmationToken: token,
+		Archived:          archived,
+	}
+	for i, traceID := range traceIDs {
+		data.TraceIDs[i] = ui.TraceID(traceID.String())
+	}
+	structuredRes := structuredResponse{
+		Data:   data,
+		Total:  len(traceIDs),
+		Errors: uiErrors,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// bulkArchiveToken returns the confirmation token of the archiving of the traces.
+func bulkArchiveToken(traceIDs []model.TraceID) string {
+	ids := make([]string, len(traceIDs))
+	for i, traceID := range traceIDs {
+		ids[i] = traceID.String()
+	}
+	sort.Strings(ids)
+	sum := sha256.Sum256([]byte(strings.Join(ids, ",")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// searchArchive implements the REST API GET:/archive. It accepts the parameters of the trace search
+// and returns the matching traces of the archive storage.
+func (aH *APIHandler) searchArchive(w http.ResponseWriter, r *http.Request) {
+	tQuery, err := aH.queryParser.parseTraceQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	traces, err := aH.queryService.FindArchivedTraces(r.Context(), &tQuery.TraceQueryParameters)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := aH.tracesToResponse(traces, true, nil)
+	aH.writeJSON(w, r, structuredRes)
+}
+
+// savedSearchUser returns the authenticated user, i.e. the subject of their OIDC token, or an empty
+// string without authentication. The authenticated users are the owners of the searches they save.
+func savedSearchUser(r *http.Request) string {
+	return oidc.GetClaims(r.Context()).String("sub")
+}
+
+// savedSearchOwner returns the owner of the saved searches of the request. The owner requested
+// by the authenticated users must be empty or themselves; without authentication, it is used as is.
+func savedSearchOwner(r *http.Request, requested string) (string, error) {
+	user := savedSearchUser(r)
+	switch {
+	case user == "":
+		return requested, nil
+	case requested != "" && requested != user:
+		return "", errSavedSearchOwner
+	default:
+		return user, nil
+	}
+}
+
+// canReadSavedSearch returns whether the user can read the saved search, i.e. the search is shared
+// by the whole tenant or owned by the user. All the searches are readable without authentication.
+func canReadSavedSearch(user string, search *savedsearchstore.SavedSearch) bool {
+	return user == "" || search.Owner == "" || search.Owner == user
+}
+
+func (aH *APIHandler) listSavedSearches(w http.ResponseWriter, r *http.Request) {
+	// without an owner, the authenticated users list their searches and the ones shared by the tenant
+	owner := r.FormValue(ownerParam)
+	if owner != "" {
+		var err error
+		owner, err = savedSearchOwner(r, owner)
+		if aH.handleError(w, err, http.StatusForbidden) {
+			return
+		}
+	}
+	all, err := aH.queryService.ListSavedSearches(r.Context(), owner)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	searches := []*savedsearchstore.SavedSearch{}
+	for _, search := range all {
+		if canReadSavedSearch(savedSearchUser(r), search) {
+			searches = append(searches, search)
+		}
+	}
+	structuredRes := structuredResponse{
+		Data:  searches,
+		Total: len(searches),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func (aH *APIHandler) createSavedSearch(w http.ResponseWriter, r *http.Request) {
+	var search savedsearchstore.SavedSearch
+	if err := json.NewDecoder(r.Body).Decode(&search); err != nil {
+		aH.handleError(w, fmt.Errorf("cannot parse saved search: %w", err), http.StatusBadRequest)
+		return
+	}
+	if search.Name == "" {
+		aH.handleError(w, errSavedSearchNameRequired, http.StatusBadRequest)
+		return
+	}
+	if _, err := url.ParseQuery(search.Query); err != nil {
+		aH.handleError(w, fmt.Errorf("cannot parse saved search query: %w", err), http.StatusBadRequest)
+		return
+	}
+	owner, err := savedSearchOwner(r, search.Owner)
+	if aH.handleError(w, err, http.StatusForbidden) {
+		return
+	}
+	// the ID and the creation time are always assigned by the server
+	search.ID = ""
+	search.CreatedAt = time.Time{}
+	search.Owner = owner
+	saved, err := aH.queryService.SaveSearch(r.Context(), &search)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data: saved,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// getReadableSavedSearch returns the saved search of the request, or ErrSavedSearchNotFound
+// if the user cannot read it.
+func (aH *APIHandler) getReadableSavedSearch(r *http.Request) (*savedsearchstore.SavedSearch, error) {
+	search, err := aH.queryService.GetSavedSearch(r.Context(), mux.Vars(r)[savedSearchIDParam])
+	if err != nil {
+		return nil, err
+	}
+	if !canReadSavedSearch(savedSearchUser(r), search) {
+		return nil, savedsearchstore.ErrSavedSearchNotFound
+	}
+	return search, nil
+}
+
+func (aH *APIHandler) getSavedSearch(w http.ResponseWriter, r *http.Request) {
+	search, err := aH.getReadableSavedSearch(r)
+	if errors.Is(err, savedsearchstore.ErrSavedSearchNotFound) {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data: search,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func (aH *APIHandler) deleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	search, err := aH.getReadableSavedSearch(r)
+	if err == nil {
+		// the authenticated users only delete their searches, not the ones shared by the tenant
+		if user := savedSearchUser(r); user != "" && search.Owner != user {
+			aH.handleError(w, errSavedSearchOwner, http.StatusForbidden)
+			return
+		}
+		err = aH.queryService.DeleteSavedSearch(r.Context(), search.ID)
+	}
+	if errors.Is(err, savedsearchstore.ErrSavedSearchNotFound) {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data:   []string{},
+		Errors: []structuredError{},
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
 func (aH *APIHandler) handleError(w http.ResponseWriter, err error, statusCode int) bool {
 	if err == nil {
 		return false
 	}
-	if errors.Is(err, disabled.ErrDisabled) {
+	if errors.Is(err, disabled.ErrDisabled) || errors.Is(err, querysvc.ErrNoSavedSearchStorage) {
 		statusCode = http.StatusNotImplemented
 	}
+	if errors.Is(err, querysvc.ErrMissingTenant) || errors.Is(err, querysvc.ErrUnknownTenant) {
+		statusCode = http.StatusUnauthorized
+	}
 	if statusCode == http.StatusInternalServerError {
 		aH.logger.Error("HTTP handler, Internal Server Error", zap.Error(err))
 	}
+	structuredErr := structuredError{
+		Code: statusCode,
+		Msg:  err.Error(),
+	}
+	var paramErr *paramError
+	if errors.As(err, &paramErr) {
+		structuredErr.Param, structuredErr.Reason = paramErr.param, paramErr.reason
+	}
 	structuredResp := structuredResponse{
-		Errors: []structuredError{
-			{
-				Code: statusCode,
-				Msg:  err.Error(),
-			},
-		},
+		Errors: []structuredError{structuredErr},
 	}
 	resp, _ := json.Marshal(&structuredResp)
 	http.Error(w, string(resp), statusCode)
@@ -45,11 +45,15 @@ import (
 	"github.com/jaegertracing/jaeger/model/adjuster"
 	ui "github.com/jaegertracing/jaeger/model/json"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
+	"github.com/jaegertracing/jaeger/pkg/oidc"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
 	metricsmocks "github.com/jaegertracing/jaeger/storage/metricsstore/mocks"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore/filestore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
@@ -392,6 +396,21 @@ func TestGetTraceBadTraceID(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestGetTraceInTimeRange(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(mockTrace, nil).Once()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+`/api/traces/123456?start=1000000&end=2000000`, &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+
+	err = getJSON(ts.server.URL+`/api/traces/123456?start=2000000&end=1000000`, &response)
+	require.EqualError(t, err, parsedError(400, "'end' should not be before 'start'"))
+}
+
 func TestSearchSuccess(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
@@ -404,6 +423,385 @@ func TestSearchSuccess(t *testing.T) {
 	assert.Empty(t, response.Errors)
 }
 
+func TestSearchPagination(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	newTrace := &model.Trace{Spans: []*model.Span{
+		{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(1), StartTime: time.Unix(100, 0), Process: &model.Process{}},
+	}}
+	oldTrace := &model.Trace{Spans: []*model.Span{
+		{TraceID: model.NewTraceID(0, 2), SpanID: model.NewSpanID(1), StartTime: time.Unix(50, 0), Process: &model.Process{}},
+	}}
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{oldTrace, newTrace}, nil).Once()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+`/api/traces?service=service&limit=1`, &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	assert.Len(t, response.Data, 1)
+	assert.NotEmpty(t, response.NextPageToken)
+}
+
+func TestSearchInvalidPageToken(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+`/api/traces?service=service&pageToken=!!!`, &response)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400 error from server")
+}
+
+func TestGetDurationsSuccess(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	trace := &model.Trace{Spans: []*model.Span{
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "op", StartTime: time.Now(), Duration: 2 * time.Millisecond, Process: &model.Process{ServiceName: "service"}},
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(2), OperationName: "op", StartTime: time.Now(), Duration: 20 * time.Millisecond, Process: &model.Process{ServiceName: "service"}},
+	}}
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{trace}, nil).Once()
+
+	var response struct {
+		Data ui.LatencyDistribution `json:"data"`
+	}
+	err := getJSON(ts.server.URL+`/api/durations?service=service&operation=op&bucket=10ms`, &response)
+	require.NoError(t, err)
+	assert.Equal(t, ui.LatencyDistribution{
+		Count: 2,
+		Buckets: []ui.DurationBucket{
+			{From: 0, To: 10000, Count: 1},
+			{From: 10000, Count: 1},
+		},
+		P50: 2000,
+		P95: 20000,
+		P99: 20000,
+	}, response.Data)
+}
+
+func TestGetDurationsFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(nil, errStorage).Once()
+
+	for _, tc := range []struct {
+		query string
+		code  int
+	}{
+		{"", http.StatusBadRequest},
+		{"service=service&bucket=x", http.StatusBadRequest},
+		{"service=service&bucket=2s&bucket=1s", http.StatusBadRequest},
+		{"service=service&limit=x", http.StatusBadRequest},
+		{"service=service", http.StatusInternalServerError},
+	} {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+`/api/durations?`+tc.query, &response)
+		require.Error(t, err, tc.query)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%d error from server", tc.code), tc.query)
+	}
+}
+
+func TestGetTagsSuccess(t *testing.T) {
+	store := memory.NewStore()
+	for i, method := range []string{"GET", "POST"} {
+		require.NoError(t, store.WriteSpan(context.Background(), &model.Span{
+			TraceID:   model.NewTraceID(1, uint64(i)),
+			StartTime: time.Now(),
+			Process:   &model.Process{ServiceName: "service"},
+			Tags:      model.KeyValues{model.String("http.method", method), model.Int64("http.status_code", 200)},
+		}))
+	}
+	qs := querysvc.NewQueryService(store, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	r := NewRouter()
+	NewAPIHandler(qs, &tenancy.Manager{}).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	var response structuredResponse
+	require.NoError(t, getJSON(server.URL+`/api/tags?service=service&prefix=http.`, &response))
+	assert.Equal(t, []any{"http.method", "http.status_code"}, response.Data)
+	assert.Equal(t, 2, response.Total)
+
+	response = structuredResponse{}
+	require.NoError(t, getJSON(server.URL+`/api/tags/values?service=service&key=http.method&limit=1`, &response))
+	assert.Equal(t, []any{"GET"}, response.Data)
+	assert.Equal(t, 1, response.Total)
+}
+
+func TestGetTagsNotSupported(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	for _, path := range []string{"/api/tags?service=service", "/api/tags/values?service=service&key=http.method"} {
+		var response structuredResponse
+		require.NoError(t, getJSON(ts.server.URL+path, &response), path)
+		assert.Equal(t, []any{}, response.Data, path)
+	}
+}
+
+func TestGetTagsFailures(t *testing.T) {
+	ts := initializeTestServerWithOptions(
+		&tenancy.Manager{},
+		querysvc.QueryServiceOptions{Tenancy: tenancy.NewManager(&tenancy.Options{Enabled: true})},
+	)
+	defer ts.server.Close()
+
+	for _, tc := range []struct {
+		path string
+		code int
+	}{
+		{"/api/tags", http.StatusBadRequest},
+		{"/api/tags?service=service&limit=-1", http.StatusBadRequest},
+		{"/api/tags?service=service&start=x", http.StatusBadRequest},
+		{"/api/tags?service=service&end=x", http.StatusBadRequest},
+		{"/api/tags?service=service&start=2000&end=1000", http.StatusBadRequest},
+		{"/api/tags/values?service=service", http.StatusBadRequest},
+		// the query service requires a tenant
+		{"/api/tags?service=service", http.StatusUnauthorized},
+	} {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+tc.path, &response)
+		require.Error(t, err, tc.path)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%d error from server", tc.code), tc.path)
+	}
+}
+
+func TestCompareTracesSuccess(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	traceA := &model.Trace{Spans: []*model.Span{
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "op", Duration: 2 * time.Millisecond, Process: &model.Process{ServiceName: "service"}},
+	}}
+	traceB := &model.Trace{Spans: []*model.Span{
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "op", Duration: 5 * time.Millisecond, Process: &model.Process{ServiceName: "service"}},
+	}}
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(traceA, nil).Once()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(traceB, nil).Once()
+
+	var response struct {
+		Data ui.TraceComparison `json:"data"`
+	}
+	err := getJSON(ts.server.URL+`/api/traces/compare?traceA=1&traceB=2`, &response)
+	require.NoError(t, err)
+	op := ui.OperationRef{ServiceName: "service", OperationName: "op"}
+	assert.Equal(t, ui.TraceComparison{
+		TraceIDA: "0000000000000001",
+		TraceIDB: "0000000000000002",
+		Added:    []ui.SpanPathDiff{},
+		Removed:  []ui.SpanPathDiff{},
+		Changed: []ui.SpanPathDiff{
+			{Path: []ui.OperationRef{op}, CountA: 1, CountB: 1, DurationA: 2000, DurationB: 5000, DurationDelta: 3000},
+		},
+		Operations: []ui.OperationDiff{
+			{OperationRef: op, CountA: 1, CountB: 1, DurationA: 2000, DurationB: 5000, DurationDelta: 3000},
+		},
+	}, response.Data)
+}
+
+func TestCompareTracesFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(nil, errStorage).Once()
+
+	for _, tc := range []struct {
+		query string
+		code  int
+	}{
+		{"traceB=1", http.StatusBadRequest},
+		{"traceA=1", http.StatusBadRequest},
+		{"traceA=x&traceB=1", http.StatusBadRequest},
+		{"traceA=1&traceB=2", http.StatusNotFound},
+		{"traceA=2&traceB=1", http.StatusInternalServerError},
+	} {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+`/api/traces/compare?`+tc.query, &response)
+		require.Error(t, err, tc.query)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%d error from server", tc.code), tc.query)
+	}
+}
+
+func TestFlameGraphSuccess(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	start := time.Now().Add(-time.Minute)
+	trace := &model.Trace{Spans: []*model.Span{
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "op", StartTime: start, Duration: 5 * time.Millisecond, Process: &model.Process{ServiceName: "service"}},
+	}}
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{trace}, nil).Once()
+
+	var response struct {
+		Data ui.FlameGraphNode `json:"data"`
+	}
+	err := getJSON(ts.server.URL+`/api/flamegraph?service=service`, &response)
+	require.NoError(t, err)
+	assert.Equal(t, ui.FlameGraphNode{
+		Count:     1,
+		TotalTime: 5000,
+		Children: []*ui.FlameGraphNode{
+			{ServiceName: "service", OperationName: "op", Count: 1, TotalTime: 5000, SelfTime: 5000},
+		},
+	}, response.Data)
+}
+
+func TestFlameGraphFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(nil, errStorage).Once()
+
+	for _, tc := range []struct {
+		query string
+		code  int
+	}{
+		{"limit=x", http.StatusBadRequest},
+		{"traceID=1", http.StatusBadRequest},
+		{"service=service", http.StatusInternalServerError},
+	} {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+`/api/flamegraph?`+tc.query, &response)
+		require.Error(t, err, tc.query)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%d error from server", tc.code), tc.query)
+	}
+}
+
+func TestGetCriticalPathSuccess(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	start := time.Unix(100, 0)
+	trace := &model.Trace{Spans: []*model.Span{
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "op", StartTime: start, Duration: 5 * time.Millisecond, Process: &model.Process{ServiceName: "service"}},
+	}}
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(trace, nil).Once()
+
+	var response struct {
+		Data ui.CriticalPath `json:"data"`
+	}
+	err := getJSON(ts.server.URL+`/api/traces/`+mockTraceID.String()+`/critical-path`, &response)
+	require.NoError(t, err)
+	op := ui.OperationRef{ServiceName: "service", OperationName: "op"}
+	assert.Equal(t, ui.CriticalPath{
+		TraceID: ui.TraceID(mockTraceID.String()),
+		Segments: []ui.CriticalPathSegment{
+			{SpanID: ui.SpanID(model.NewSpanID(1).String()), OperationRef: op, StartTime: 100000000, Duration: 5000},
+		},
+		Operations: []ui.OperationTime{{OperationRef: op, Duration: 5000}},
+	}, response.Data)
+}
+
+func TestGetCriticalPathFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(nil, errStorage).Once()
+
+	for _, tc := range []struct {
+		traceID string
+		code    int
+	}{
+		{"x", http.StatusBadRequest},
+		{"1", http.StatusNotFound},
+		{"2", http.StatusInternalServerError},
+	} {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+`/api/traces/`+tc.traceID+`/critical-path`, &response)
+		require.Error(t, err, tc.traceID)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%d error from server", tc.code), tc.traceID)
+	}
+}
+
+func TestGetTraceQualitySuccess(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	trace := &model.Trace{Spans: []*model.Span{
+		{TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "op", Process: &model.Process{ServiceName: "service"}},
+	}}
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(trace, nil).Once()
+
+	var response struct {
+		Data ui.TraceQualityReport `json:"data"`
+	}
+	err := getJSON(ts.server.URL+`/api/traces/`+mockTraceID.String()+`/quality`, &response)
+	require.NoError(t, err)
+	assert.Equal(t, ui.TraceQualityReport{
+		Traces: 1,
+		Services: []ui.ServiceQuality{
+			{
+				ServiceName: "service",
+				Traces:      1,
+				Checks: []ui.QualityCheckResult{
+					{Check: "missing_span_kind", Checked: 1, Failed: 1, TraceIDs: []ui.TraceID{ui.TraceID(mockTraceID.String())}},
+				},
+			},
+		},
+	}, response.Data)
+}
+
+func TestGetTraceQualityFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 1)).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 2)).
+		Return(nil, errStorage).Once()
+
+	for _, tc := range []struct {
+		traceID string
+		code    int
+	}{
+		{"x", http.StatusBadRequest},
+		{"1", http.StatusNotFound},
+		{"2", http.StatusInternalServerError},
+	} {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+`/api/traces/`+tc.traceID+`/quality`, &response)
+		require.Error(t, err, tc.traceID)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%d error from server", tc.code), tc.traceID)
+	}
+}
+
+func TestGetServiceQuality(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{mockTrace}, nil).Once()
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(nil, errStorage).Once()
+
+	var response struct {
+		Data ui.TraceQualityReport `json:"data"`
+	}
+	err := getJSON(ts.server.URL+`/api/quality?service=service`, &response)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Data.Traces)
+	assert.NotEmpty(t, response.Data.Services)
+
+	for _, tc := range []struct {
+		query string
+		code  int
+	}{
+		{"", http.StatusBadRequest},
+		{"service=service&limit=x", http.StatusBadRequest},
+		{"service=service", http.StatusInternalServerError},
+	} {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+`/api/quality?`+tc.query, &response)
+		require.Error(t, err, tc.query)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%d error from server", tc.code), tc.query)
+	}
+}
+
 func TestSearchByTraceIDSuccess(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
@@ -576,6 +974,39 @@ func TestGetOperationsSuccess(t *testing.T) {
 	}
 }
 
+func TestGetOperationsFiltered(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On(
+		"GetOperations",
+		mock.AnythingOfType("*context.valueCtx"),
+		spanstore.OperationQueryParameters{ServiceName: "trifle"},
+	).Return([]spanstore.Operation{
+		{Name: "GET /orders", SpanKind: "server"},
+		{Name: "GET /users", SpanKind: "server"},
+		{Name: "GET /orders", SpanKind: "client"},
+		{Name: "POST /orders", SpanKind: "server"},
+	}, nil).Once()
+
+	var response struct {
+		Operations []ui.Operation `json:"data"`
+		Total      int            `json:"total"`
+		Limit      int            `json:"limit"`
+		Offset     int            `json:"offset"`
+	}
+	err := getJSON(ts.server.URL+"/api/operations?service=trifle&prefix=GET&contains=orders&offset=1&limit=5", &response)
+	require.NoError(t, err)
+	assert.Equal(t, []ui.Operation{{Name: "GET /orders", SpanKind: "server"}}, response.Operations)
+	assert.Equal(t, 1, response.Total)
+	assert.Equal(t, 5, response.Limit)
+	assert.Equal(t, 1, response.Offset)
+
+	for _, query := range []string{"limit=x", "offset=-1"} {
+		err = getJSON(ts.server.URL+"/api/operations?service=trifle&"+query, &response)
+		require.ErrorContains(t, err, "400 error from server", query)
+	}
+}
+
 func TestGetOperationsNoServiceName(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
@@ -875,6 +1306,170 @@ func TestGetMinStep(t *testing.T) {
 	assert.Equal(t, float64(5), response.Data)
 }
 
+func TestSavedSearches(t *testing.T) {
+	ts := initializeTestServerWithHandler(querysvc.QueryServiceOptions{SavedSearchStore: filestore.NewStore()})
+	defer ts.server.Close()
+
+	var created struct {
+		Data savedsearchstore.SavedSearch `json:"data"`
+	}
+	search := savedsearchstore.SavedSearch{ID: "ignored", Name: "checkout errors", Owner: "alice", Query: "service=checkout&tags=error"}
+	require.NoError(t, postJSON(ts.server.URL+"/api/saved-searches", search, &created))
+	assert.NotEqual(t, "ignored", created.Data.ID)
+	assert.Equal(t, "checkout errors", created.Data.Name)
+	assert.Equal(t, "alice", created.Data.Owner)
+	assert.False(t, created.Data.CreatedAt.IsZero())
+	require.NoError(t, postJSON(ts.server.URL+"/api/saved-searches", savedsearchstore.SavedSearch{Name: "all", Owner: "bob"}, nil))
+
+	var found struct {
+		Data savedsearchstore.SavedSearch `json:"data"`
+	}
+	require.NoError(t, getJSON(ts.server.URL+"/api/saved-searches/"+created.Data.ID, &found))
+	assert.Equal(t, created.Data.Query, found.Data.Query)
+
+	var list struct {
+		Data  []savedsearchstore.SavedSearch `json:"data"`
+		Total int                            `json:"total"`
+	}
+	require.NoError(t, getJSON(ts.server.URL+"/api/saved-searches", &list))
+	assert.Equal(t, 2, list.Total)
+	require.NoError(t, getJSON(ts.server.URL+"/api/saved-searches?owner=alice", &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, created.Data.ID, list.Data[0].ID)
+
+	deleteSearch := func(id string) error {
+		req, err := http.NewRequest(http.MethodDelete, ts.server.URL+"/api/saved-searches/"+id, nil)
+		require.NoError(t, err)
+		return execJSON(req, map[string]string{}, nil)
+	}
+	require.NoError(t, deleteSearch(created.Data.ID))
+	err := getJSON(ts.server.URL+"/api/saved-searches/"+created.Data.ID, &found)
+	require.ErrorContains(t, err, fmt.Sprintf("%d error from server", http.StatusNotFound))
+	err = deleteSearch(created.Data.ID)
+	require.ErrorContains(t, err, fmt.Sprintf("%d error from server", http.StatusNotFound))
+}
+
+func TestSavedSearchesAuthenticated(t *testing.T) {
+	store := filestore.NewStore()
+	require.NoError(t, store.SaveSearch(context.Background(), &savedsearchstore.SavedSearch{ID: "shared", Name: "shared"}))
+	ts := initializeTestServerWithHandler(querysvc.QueryServiceOptions{SavedSearchStore: store})
+	ts.server.Close()
+	// the test user stands for the subject of a verified token
+	handler := ts.server.Config.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := oidc.Claims{"sub": r.Header.Get("Test-User")}
+		handler.ServeHTTP(w, r.WithContext(oidc.ContextWithClaims(r.Context(), claims)))
+	}))
+	defer server.Close()
+
+	as := func(user string) map[string]string {
+		return map[string]string{"Test-User": user}
+	}
+	save := func(user string, search savedsearchstore.SavedSearch) (savedsearchstore.SavedSearch, error) {
+		var created struct {
+			Data savedsearchstore.SavedSearch `json:"data"`
+		}
+		body, err := json.Marshal(search)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/api/saved-searches", bytes.NewReader(body))
+		require.NoError(t, err)
+		err = execJSON(req, as(user), &created)
+		return created.Data, err
+	}
+	deleteSearch := func(user string, id string) error {
+		req, err := http.NewRequest(http.MethodDelete, server.URL+"/api/saved-searches/"+id, nil)
+		require.NoError(t, err)
+		return execJSON(req, as(user), nil)
+	}
+	forbidden := fmt.Sprintf("%d error from server", http.StatusForbidden)
+	notFound := fmt.Sprintf("%d error from server", http.StatusNotFound)
+
+	alices, err := save("alice", savedsearchstore.SavedSearch{Name: "checkout errors"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", alices.Owner)
+	_, err = save("alice", savedsearchstore.SavedSearch{Name: "mine", Owner: "alice"})
+	require.NoError(t, err)
+	_, err = save("bob", savedsearchstore.SavedSearch{Name: "not mine", Owner: "alice"})
+	require.ErrorContains(t, err, forbidden)
+	bobs, err := save("bob", savedsearchstore.SavedSearch{Name: "all"})
+	require.NoError(t, err)
+
+	var list struct {
+		Data  []savedsearchstore.SavedSearch `json:"data"`
+		Total int                            `json:"total"`
+	}
+	require.NoError(t, getJSONCustomHeaders(server.URL+"/api/saved-searches", as("bob"), &list))
+	require.Len(t, list.Data, 2)
+	assert.ElementsMatch(t, []string{"shared", bobs.ID}, []string{list.Data[0].ID, list.Data[1].ID})
+	require.NoError(t, getJSONCustomHeaders(server.URL+"/api/saved-searches?owner=alice", as("alice"), &list))
+	assert.Equal(t, 2, list.Total)
+	err = getJSONCustomHeaders(server.URL+"/api/saved-searches?owner=alice", as("bob"), &list)
+	require.ErrorContains(t, err, forbidden)
+
+	var found struct {
+		Data savedsearchstore.SavedSearch `json:"data"`
+	}
+	require.NoError(t, getJSONCustomHeaders(server.URL+"/api/saved-searches/"+alices.ID, as("alice"), &found))
+	err = getJSONCustomHeaders(server.URL+"/api/saved-searches/"+alices.ID, as("bob"), &found)
+	require.ErrorContains(t, err, notFound)
+	require.ErrorContains(t, deleteSearch("bob", alices.ID), notFound)
+	require.ErrorContains(t, deleteSearch("bob", "shared"), forbidden)
+	require.NoError(t, deleteSearch("alice", alices.ID))
+}
+
+func TestSavedSearchesEmptyList(t *testing.T) {
+	ts := initializeTestServerWithHandler(querysvc.QueryServiceOptions{SavedSearchStore: filestore.NewStore()})
+	defer ts.server.Close()
+
+	var response structuredResponse
+	require.NoError(t, getJSON(ts.server.URL+"/api/saved-searches", &response))
+	assert.Equal(t, []interface{}{}, response.Data)
+}
+
+func TestSavedSearchesFailures(t *testing.T) {
+	ts := initializeTestServerWithHandler(querysvc.QueryServiceOptions{SavedSearchStore: filestore.NewStore()})
+	defer ts.server.Close()
+
+	err := postJSON(ts.server.URL+"/api/saved-searches", savedsearchstore.SavedSearch{Query: "service=checkout"}, nil)
+	require.EqualError(t, err, parsedError(http.StatusBadRequest, errSavedSearchNameRequired.Error()))
+
+	err = postJSON(ts.server.URL+"/api/saved-searches", savedsearchstore.SavedSearch{Name: "bad", Query: "service=%zz"}, nil)
+	require.ErrorContains(t, err, "cannot parse saved search query")
+
+	err = postJSON(ts.server.URL+"/api/saved-searches", "not a saved search", nil)
+	require.ErrorContains(t, err, "cannot parse saved search")
+}
+
+func TestSavedSearchesNotConfigured(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+"/api/saved-searches", &response)
+	require.EqualError(t, err, parsedError(http.StatusNotImplemented, querysvc.ErrNoSavedSearchStorage.Error()))
+	err = getJSON(ts.server.URL+"/api/saved-searches/123", &response)
+	require.ErrorContains(t, err, fmt.Sprintf("%d error from server", http.StatusNotImplemented))
+	err = postJSON(ts.server.URL+"/api/saved-searches", savedsearchstore.SavedSearch{Name: "all"}, nil)
+	require.ErrorContains(t, err, fmt.Sprintf("%d error from server", http.StatusNotImplemented))
+	req, err := http.NewRequest(http.MethodDelete, ts.server.URL+"/api/saved-searches/123", nil)
+	require.NoError(t, err)
+	err = execJSON(req, map[string]string{}, nil)
+	require.ErrorContains(t, err, fmt.Sprintf("%d error from server", http.StatusNotImplemented))
+}
+
+func TestReadWithoutTenant(t *testing.T) {
+	ts := initializeTestServerWithHandler(querysvc.QueryServiceOptions{
+		Tenancy: tenancy.NewManager(&tenancy.Options{Enabled: true}),
+	})
+	defer ts.server.Close()
+
+	for _, path := range []string{"/api/services", "/api/flamegraph?service=service"} {
+		var response structuredResponse
+		err := getJSON(ts.server.URL+path, &response)
+		require.EqualError(t, err, parsedError(http.StatusUnauthorized, querysvc.ErrMissingTenant.Error()), path)
+	}
+}
+
 // getJSON fetches a JSON document from a server via HTTP GET
 func getJSON(url string, out interface{}) error {
 	return getJSONCustomHeaders(url, make(map[string]string), out)
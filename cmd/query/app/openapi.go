@@ -0,0 +1,335 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// openAPIDocument is the OpenAPI document of the HTTP API. The parameters of the requests
+// are validated against it before they are parsed by the handlers, which rely on the validation.
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+const (
+	openAPIRoute = "/openapi.json"
+
+	componentParametersPrefix = "#/components/parameters/"
+
+	// reasonRequired is the reason of the errors of the missing required parameters.
+	reasonRequired = "required"
+	// reasonInvalid is the reason of the errors of the parameters not matching their type, format or values.
+	reasonInvalid = "invalid"
+	// reasonOutOfRange is the reason of the errors of the parameters below their minimum or above their maximum.
+	reasonOutOfRange = "out_of_range"
+)
+
+// defaultAPISpec is the specification of the parameters of the OpenAPI document.
+var defaultAPISpec = mustLoadAPISpec(openAPIDocument)
+
+// apiSpec is the subset of an OpenAPI document describing the parameters of the requests.
+type apiSpec struct {
+	// Paths are the operations by path, e.g. /traces/{traceID}, and lower case method.
+	Paths      map[string]map[string]*apiOperation `json:"paths"`
+	Components struct {
+		Parameters map[string]*apiParameter `json:"parameters"`
+	} `json:"components"`
+}
+
+type apiOperation struct {
+	OperationID string          `json:"operationId"`
+	Parameters  []*apiParameter `json:"parameters"`
+}
+
+type apiParameter struct {
+	Ref      string     `json:"$ref"`
+	Name     string     `json:"name"`
+	In       string     `json:"in"`
+	Required bool       `json:"required"`
+	Schema   *apiSchema `json:"schema"`
+}
+
+// apiSchema is the subset of the OpenAPI schemas supported by the parameters, where the string formats
+// are the Go durations, e.g. 10ms, the trace IDs in hexadecimal, the JSON objects of strings, the key:value
+// pairs and the span kinds. The title of the items of an array names them in the errors.
+type apiSchema struct {
+	Type    string     `json:"type"`
+	Format  string     `json:"format"`
+	Title   string     `json:"title"`
+	Minimum *float64   `json:"minimum"`
+	Maximum *float64   `json:"maximum"`
+	Enum    []string   `json:"enum"`
+	Pattern string     `json:"pattern"`
+	Items   *apiSchema `json:"items"`
+
+	pattern *regexp.Regexp
+}
+
+// paramError is the error of a request parameter that does not match the OpenAPI document.
+type paramError struct {
+	param  string
+	reason string
+	err    error
+}
+
+func (e *paramError) Error() string {
+	return e.err.Error()
+}
+
+func (e *paramError) Unwrap() error {
+	return e.err
+}
+
+func mustLoadAPISpec(document []byte) *apiSpec {
+	spec, err := loadAPISpec(document)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+// loadAPISpec parses an OpenAPI document, resolving the references to the shared parameters.
+func loadAPISpec(document []byte) (*apiSpec, error) {
+	var spec apiSpec
+	if err := json.Unmarshal(document, &spec); err != nil {
+		return nil, fmt.Errorf("cannot parse OpenAPI document: %w", err)
+	}
+	for route, operations := range spec.Paths {
+		for method, operation := range operations {
+			for i, param := range operation.Parameters {
+				if param.Ref != "" {
+					shared, ok := spec.Components.Parameters[strings.TrimPrefix(param.Ref, componentParametersPrefix)]
+					if !ok || !strings.HasPrefix(param.Ref, componentParametersPrefix) {
+						return nil, fmt.Errorf("unknown parameter %s of %s %s", param.Ref, method, route)
+					}
+					param = shared
+					operation.Parameters[i] = param
+				}
+				if err := param.init(); err != nil {
+					return nil, fmt.Errorf("invalid parameter %s of %s %s: %w", param.Name, method, route, err)
+				}
+			}
+		}
+	}
+	return &spec, nil
+}
+
+func (p *apiParameter) init() error {
+	if p.In != "query" && p.In != "path" {
+		return fmt.Errorf("unsupported location %q", p.In)
+	}
+	if p.Schema == nil {
+		return errors.New("missing schema")
+	}
+	if p.Schema.Type == "array" {
+		if p.Schema.Items == nil {
+			return errors.New("missing items schema")
+		}
+		return p.Schema.Items.init()
+	}
+	return p.Schema.init()
+}
+
+func (s *apiSchema) init() error {
+	switch s.Type {
+	case "string", "integer", "number", "boolean":
+	default:
+		return fmt.Errorf("unsupported type %q", s.Type)
+	}
+	if s.Pattern != "" && s.pattern == nil {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return err
+		}
+		s.pattern = pattern
+	}
+	return nil
+}
+
+// validate checks the parameters of a request to the route, e.g. /traces/{traceID}.
+// The requests of the routes and methods missing from the specification are not checked.
+func (s *apiSpec) validate(route string, r *http.Request) error {
+	operation, ok := s.Paths[route][strings.ToLower(r.Method)]
+	if !ok {
+		return nil
+	}
+	if err := r.ParseForm(); err != nil {
+		return &paramError{reason: reasonInvalid, err: fmt.Errorf("cannot parse the parameters: %w", err)}
+	}
+	for _, param := range operation.Parameters {
+		var values []string
+		if param.In == "path" {
+			value, err := url.PathUnescape(mux.Vars(r)[param.Name])
+			if err != nil {
+				return &paramError{param: param.Name, reason: reasonInvalid, err: newParseError(err, param.Name)}
+			}
+			values = []string{value}
+		} else {
+			values = r.Form[param.Name]
+		}
+		if err := param.validate(values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks the values of the parameter. The empty values of the parameters other than arrays
+// are ignored, like missing values, while every item of an array is checked.
+func (p *apiParameter) validate(values []string) error {
+	if p.Schema.Type == "array" {
+		if len(values) == 0 && p.Required {
+			err := fmt.Errorf("please provide at least one %s", p.Schema.Items.title())
+			return &paramError{param: p.Name, reason: reasonRequired, err: newParseError(err, p.Name)}
+		}
+		for _, value := range values {
+			if reason, err := p.Schema.Items.validate(p.Name, value); err != nil {
+				return &paramError{param: p.Name, reason: reason, err: err}
+			}
+		}
+		return nil
+	}
+	set := false
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		set = true
+		if reason, err := p.Schema.validate(p.Name, value); err != nil {
+			return &paramError{param: p.Name, reason: reason, err: err}
+		}
+	}
+	if !set && p.Required {
+		return &paramError{param: p.Name, reason: reasonRequired, err: fmt.Errorf("parameter '%s' is required", p.Name)}
+	}
+	return nil
+}
+
+func (s *apiSchema) title() string {
+	if s.Title == "" {
+		return "value"
+	}
+	return s.Title
+}
+
+// validate checks a value of the parameter, returning the reason of the error if it is not valid.
+func (s *apiSchema) validate(name, value string) (string, error) {
+	var number float64
+	switch s.Type {
+	case "integer":
+		bitSize := 64
+		if s.Format == "int32" {
+			bitSize = 32
+		}
+		i, err := strconv.ParseInt(value, 10, bitSize)
+		if err != nil {
+			return reasonInvalid, newParseError(err, name)
+		}
+		number = float64(i)
+	case "number":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return reasonInvalid, newParseError(err, name)
+		}
+		number = f
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return reasonInvalid, newParseError(err, name)
+		}
+		return "", nil
+	default:
+		return s.validateString(name, value)
+	}
+	if s.Minimum != nil && number < *s.Minimum {
+		if *s.Minimum == 0 {
+			return reasonOutOfRange, fmt.Errorf("'%s' must not be negative", name)
+		}
+		return reasonOutOfRange, fmt.Errorf("'%s' must not be less than %v", name, *s.Minimum)
+	}
+	if s.Maximum != nil && number > *s.Maximum {
+		return reasonOutOfRange, fmt.Errorf("'%s' must not be greater than %v", name, *s.Maximum)
+	}
+	return "", nil
+}
+
+// validateString checks the format of a string, then its values and its pattern.
+func (s *apiSchema) validateString(name, value string) (string, error) {
+	if err := validateFormat(s.Format, name, value); err != nil {
+		return reasonInvalid, err
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, value) {
+		return reasonInvalid, fmt.Errorf("unsupported %s %q, expecting one of %s", name, value, strings.Join(s.Enum, ", "))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		return reasonInvalid, fmt.Errorf("'%s' must match the pattern %s, received: %s", name, s.Pattern, value)
+	}
+	return "", nil
+}
+
+// validateFormat checks that a value of the parameter can be parsed in the format.
+func validateFormat(format, name, value string) error {
+	switch format {
+	case "duration":
+		if _, err := time.ParseDuration(value); err != nil {
+			return newParseError(err, name)
+		}
+	case "trace-id":
+		if _, err := model.TraceIDFromString(value); err != nil {
+			return fmt.Errorf("cannot parse %s param: %w", name, err)
+		}
+	case "json":
+		var object map[string]string
+		if err := json.Unmarshal([]byte(value), &object); err != nil {
+			return fmt.Errorf("malformed '%s' parameter, cannot unmarshal JSON: %w", name, err)
+		}
+	case "key-value":
+		if !strings.Contains(value, ":") {
+			return fmt.Errorf("malformed '%s' parameter, expecting key:value, received: %s", name, value)
+		}
+	case "span-kind":
+		if _, ok := jaegerToOtelSpanKind[value]; !ok {
+			return newParseError(fmt.Errorf("unsupported span kind: '%s'", value), name)
+		}
+	}
+	return nil
+}
+
+// validateParams returns a handler rejecting the requests whose parameters do not match the specification of the route.
+func (aH *APIHandler) validateParams(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if aH.handleError(w, aH.apiSpec.validate(route, r), http.StatusBadRequest) {
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// getOpenAPIDocument implements the REST API /openapi.json. It returns the OpenAPI document of the API,
+// with the URL of the server matching the base path and the prefix of the routes, for client generation.
+func (aH *APIHandler) getOpenAPIDocument(w http.ResponseWriter, r *http.Request) {
+	var document map[string]interface{}
+	if err := json.Unmarshal(openAPIDocument, &document); aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	document["servers"] = []map[string]string{
+		{"url": path.Join("/", aH.basePath, aH.apiPrefix)},
+	}
+	aH.writeJSON(w, r, document)
+}
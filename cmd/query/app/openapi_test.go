@@ -0,0 +1,255 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+func TestOpenAPIDocumentCoversRoutes(t *testing.T) {
+	router := NewRouter()
+	NewAPIHandler(&querysvc.QueryService{}, &tenancy.Manager{}).RegisterRoutes(router)
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		require.NoError(t, err)
+		methods, err := route.GetMethods()
+		require.NoError(t, err)
+		specRoute := strings.TrimPrefix(template, "/"+defaultAPIPrefix)
+		if specRoute == openAPIRoute {
+			return nil
+		}
+		for _, method := range methods {
+			assert.Contains(t, defaultAPISpec.Paths[specRoute], strings.ToLower(method), "%s %s is not documented", method, template)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestLoadAPISpecErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{
+			name:     "malformed",
+			document: `{`,
+			err:      "cannot parse OpenAPI document",
+		},
+		{
+			name:     "unknown reference",
+			document: `{"paths": {"/x": {"get": {"parameters": [{"$ref": "#/components/parameters/y"}]}}}}`,
+			err:      "unknown parameter #/components/parameters/y of get /x",
+		},
+		{
+			name:     "unsupported location",
+			document: `{"paths": {"/x": {"get": {"parameters": [{"name": "y", "in": "header", "schema": {"type": "string"}}]}}}}`,
+			err:      `invalid parameter y of get /x: unsupported location "header"`,
+		},
+		{
+			name:     "missing schema",
+			document: `{"paths": {"/x": {"get": {"parameters": [{"name": "y", "in": "query"}]}}}}`,
+			err:      "invalid parameter y of get /x: missing schema",
+		},
+		{
+			name:     "missing items",
+			document: `{"paths": {"/x": {"get": {"parameters": [{"name": "y", "in": "query", "schema": {"type": "array"}}]}}}}`,
+			err:      "invalid parameter y of get /x: missing items schema",
+		},
+		{
+			name:     "unsupported type",
+			document: `{"paths": {"/x": {"get": {"parameters": [{"name": "y", "in": "query", "schema": {"type": "object"}}]}}}}`,
+			err:      `invalid parameter y of get /x: unsupported type "object"`,
+		},
+		{
+			name:     "invalid pattern",
+			document: `{"paths": {"/x": {"get": {"parameters": [{"name": "y", "in": "query", "schema": {"type": "string", "pattern": "("}}]}}}}`,
+			err:      "invalid parameter y of get /x: error parsing regexp",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := loadAPISpec([]byte(test.document))
+			require.ErrorContains(t, err, test.err)
+			assert.Panics(t, func() { mustLoadAPISpec([]byte(test.document)) })
+		})
+	}
+}
+
+func TestParamValidation(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	tests := []struct {
+		urlPath string
+		param   string
+		reason  string
+		msg     string
+	}{
+		{
+			urlPath: "/api/operations",
+			param:   "service",
+			reason:  reasonRequired,
+			msg:     "parameter 'service' is required",
+		},
+		{
+			urlPath: "/api/tags/values?service=svc&key=",
+			param:   "key",
+			reason:  reasonRequired,
+			msg:     "parameter 'key' is required",
+		},
+		{
+			urlPath: "/api/traces?service=svc&limit=-1",
+			param:   "limit",
+			reason:  reasonOutOfRange,
+			msg:     "'limit' must not be negative",
+		},
+		{
+			urlPath: "/api/traces?service=svc&limit=10000000000",
+			param:   "limit",
+			reason:  reasonInvalid,
+			msg:     `unable to parse param 'limit': strconv.ParseInt: parsing "10000000000": value out of range`,
+		},
+		{
+			urlPath: "/api/traces?service=svc&minDuration=1",
+			param:   "minDuration",
+			reason:  reasonInvalid,
+			msg:     `unable to parse param 'minDuration': time: missing unit in duration "1"`,
+		},
+		{
+			urlPath: "/api/traces?service=svc&tag=k",
+			param:   "tag",
+			reason:  reasonInvalid,
+			msg:     "malformed 'tag' parameter, expecting key:value, received: k",
+		},
+		{
+			urlPath: "/api/traces?service=svc&tag=k:v&tag=",
+			param:   "tag",
+			reason:  reasonInvalid,
+			msg:     "malformed 'tag' parameter, expecting key:value, received: ",
+		},
+		{
+			urlPath: "/api/metrics/calls?spanKind=server",
+			param:   "service",
+			reason:  reasonRequired,
+			msg:     "unable to parse param 'service': please provide at least one service name",
+		},
+		{
+			urlPath: "/api/traces?service=svc&tags=k",
+			param:   "tags",
+			reason:  reasonInvalid,
+			msg:     "malformed 'tags' parameter, cannot unmarshal JSON: invalid character 'k' looking for beginning of value",
+		},
+		{
+			urlPath: "/api/traces?service=svc&format=xml",
+			param:   "format",
+			reason:  reasonInvalid,
+			msg:     `unsupported format "xml", expecting one of json, otlp, csv`,
+		},
+		{
+			urlPath: "/api/traces?traceID=xyz",
+			param:   "traceID",
+			reason:  reasonInvalid,
+			msg:     `cannot parse traceID param: strconv.ParseUint: parsing "xyz": invalid syntax`,
+		},
+		{
+			urlPath: "/api/traces/xyz",
+			param:   "traceID",
+			reason:  reasonInvalid,
+			msg:     `cannot parse traceID param: strconv.ParseUint: parsing "xyz": invalid syntax`,
+		},
+		{
+			urlPath: "/api/traces/123?raw=maybe",
+			param:   "raw",
+			reason:  reasonInvalid,
+			msg:     `unable to parse param 'raw': strconv.ParseBool: parsing "maybe": invalid syntax`,
+		},
+		{
+			urlPath: "/api/dependencies?step=-1",
+			param:   "step",
+			reason:  reasonOutOfRange,
+			msg:     "'step' must not be negative",
+		},
+		{
+			urlPath: "/api/metrics/latencies?service=svc&quantile=1.5",
+			param:   "quantile",
+			reason:  reasonOutOfRange,
+			msg:     "'quantile' must not be greater than 1",
+		},
+		{
+			urlPath: "/api/metrics/latencies?service=svc&quantile=x",
+			param:   "quantile",
+			reason:  reasonInvalid,
+			msg:     `unable to parse param 'quantile': strconv.ParseFloat: parsing "x": invalid syntax`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.urlPath, func(t *testing.T) {
+			resp, err := http.Get(ts.server.URL + test.urlPath)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			var response structuredResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			assert.Equal(t, []structuredError{
+				{Code: http.StatusBadRequest, Msg: test.msg, Param: test.param, Reason: test.reason},
+			}, response.Errors)
+		})
+	}
+}
+
+func TestAPISchemaMinimum(t *testing.T) {
+	minimum := 10.0
+	schema := &apiSchema{Type: "integer", Minimum: &minimum}
+	reason, err := schema.validate("x", "9")
+	assert.Equal(t, reasonOutOfRange, reason)
+	require.EqualError(t, err, "'x' must not be less than 10")
+
+	reason, err = schema.validate("x", "10")
+	assert.Empty(t, reason)
+	require.NoError(t, err)
+}
+
+func TestGetOpenAPIDocument(t *testing.T) {
+	for _, basePath := range []string{"/", "/jaeger"} {
+		t.Run(basePath, func(t *testing.T) {
+			ts := initializeTestServer(HandlerOptions.BasePath(basePath))
+			defer ts.server.Close()
+
+			resp, err := http.Get(ts.server.URL + "/api/openapi.json")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			var document struct {
+				OpenAPI string `json:"openapi"`
+				Servers []struct {
+					URL string `json:"url"`
+				} `json:"servers"`
+				Paths map[string]interface{} `json:"paths"`
+			}
+			require.NoError(t, json.Unmarshal(body, &document))
+			assert.Equal(t, "3.0.3", document.OpenAPI)
+			require.Len(t, document.Servers, 1)
+			assert.Equal(t, strings.TrimSuffix(basePath, "/")+"/api", document.Servers[0].URL)
+			assert.Len(t, document.Paths, len(defaultAPISpec.Paths))
+		})
+	}
+}
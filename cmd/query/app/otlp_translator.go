@@ -17,10 +17,10 @@ package app
 import (
 	"fmt"
 
-	model2otel "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 
 	"github.com/jaegertracing/jaeger/model"
+	model2otel "github.com/jaegertracing/jaeger/model/converter/otlp"
 )
 
 func otlp2traces(otlpSpans []byte) ([]*model.Trace, error) {
@@ -17,13 +17,13 @@ package app
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
@@ -43,12 +43,21 @@ const (
 	serviceParam     = "service"
 	spanKindParam    = "spanKind"
 	endTimeParam     = "end"
+	pageTokenParam   = "pageToken"
+	bucketParam      = "bucket"
 	prettyPrintParam = "prettyPrint"
+	prefixParam      = "prefix"
+	containsParam    = "contains"
+	offsetParam      = "offset"
+	namespaceParam   = "namespace"
+	keyParam         = "key"
 )
 
 var (
 	errMaxDurationGreaterThanMin = fmt.Errorf("'%s' should be greater than '%s'", maxDurationParam, minDurationParam)
 
+	errEndTimeBeforeStartTime = fmt.Errorf("'%s' should not be before '%s'", endTimeParam, startTimeParam)
+
 	// errServiceParameterRequired occurs when no service name is defined.
 	errServiceParameterRequired = fmt.Errorf("parameter '%s' is required", serviceParam)
 
@@ -63,7 +72,9 @@ var (
 )
 
 type (
-	// queryParser handles the parsing of query parameters for traces.
+	// queryParser handles the parsing of query parameters for traces. The parameters are validated
+	// against the OpenAPI document before they are parsed, so the parser only checks the rules
+	// involving several parameters, which the document cannot express.
 	queryParser struct {
 		traceQueryLookbackDuration time.Duration
 		timeNow                    func() time.Time
@@ -75,8 +86,11 @@ type (
 	}
 
 	dependenciesQueryParameters struct {
-		endTs    time.Time
-		lookback time.Duration
+		querysvc.DependencyGraphQuery
+		// services, if not empty, keeps the links whose parent or child is one of the services
+		services []string
+		// namespace, if not empty, keeps the links whose parent or child name starts with it
+		namespace string
 	}
 
 	durationParser = func(s string) (time.Duration, error)
@@ -112,7 +126,7 @@ func newDurationUnitsParser(units time.Duration) durationParser {
 // Trace query syntax:
 //
 //	query ::= param | param '&' query
-//	param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags
+//	param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags | pageToken
 //	service ::= 'service=' strValue
 //	operation ::= 'operation=' strValue
 //	limit ::= 'limit=' intValue
@@ -124,64 +138,26 @@ func newDurationUnitsParser(units time.Duration) durationParser {
 //	key := strValue
 //	keyValue := strValue ':' strValue
 //	tags :== 'tags=' jsonMap
+//	pageToken ::= 'pageToken=' strValue as returned in 'nextPageToken' of the previous response
 func (p *queryParser) parseTraceQueryParams(r *http.Request) (*traceQueryParameters, error) {
-	service := r.FormValue(serviceParam)
-	operation := r.FormValue(operationParam)
-
-	startTime, err := p.parseTime(r, startTimeParam, time.Microsecond)
-	if err != nil {
-		return nil, err
-	}
-	endTime, err := p.parseTime(r, endTimeParam, time.Microsecond)
-	if err != nil {
-		return nil, err
-	}
-
-	tags, err := p.parseTags(r.Form[tagParam], r.Form[tagsParam])
-	if err != nil {
-		return nil, err
-	}
-
-	limitParam := r.FormValue(limitParam)
-	limit := defaultQueryLimit
-	if limitParam != "" {
-		limitParsed, err := strconv.ParseInt(limitParam, 10, 32)
-		if err != nil {
-			return nil, err
-		}
-		limit = int(limitParsed)
-	}
-
 	parser := newDurationStringParser()
-	minDuration, err := parseDuration(r, minDurationParam, parser, 0)
-	if err != nil {
-		return nil, err
-	}
-
-	maxDuration, err := parseDuration(r, maxDurationParam, parser, 0)
-	if err != nil {
-		return nil, err
-	}
-
 	var traceIDs []model.TraceID
 	for _, id := range r.Form[traceIDParam] {
-		if traceID, err := model.TraceIDFromString(id); err == nil {
-			traceIDs = append(traceIDs, traceID)
-		} else {
-			return nil, fmt.Errorf("cannot parse traceID param: %w", err)
-		}
+		traceID, _ := model.TraceIDFromString(id)
+		traceIDs = append(traceIDs, traceID)
 	}
 
 	traceQuery := &traceQueryParameters{
 		TraceQueryParameters: spanstore.TraceQueryParameters{
-			ServiceName:   service,
-			OperationName: operation,
-			StartTimeMin:  startTime,
-			StartTimeMax:  endTime,
-			Tags:          tags,
-			NumTraces:     limit,
-			DurationMin:   minDuration,
-			DurationMax:   maxDuration,
+			ServiceName:   r.FormValue(serviceParam),
+			OperationName: r.FormValue(operationParam),
+			StartTimeMin:  p.parseTime(r, startTimeParam, time.Microsecond),
+			StartTimeMax:  p.parseTime(r, endTimeParam, time.Microsecond),
+			Tags:          p.parseTags(r.Form[tagParam], r.Form[tagsParam]),
+			NumTraces:     parseInt(r, limitParam, defaultQueryLimit),
+			DurationMin:   parseDuration(r, minDurationParam, parser, 0),
+			DurationMax:   parseDuration(r, maxDurationParam, parser, 0),
+			PageToken:     r.FormValue(pageTokenParam),
 		},
 		traceIDs: traceIDs,
 	}
@@ -192,19 +168,68 @@ func (p *queryParser) parseTraceQueryParams(r *http.Request) (*traceQueryParamet
 	return traceQuery, nil
 }
 
+// parseLatencyQueryParams takes a request and constructs a model of latency distribution query parameters.
+// Times use the same microsecond units as trace search, while bucket bounds are duration strings
+// like those of minDuration and maxDuration.
+//
+// Latency query syntax:
+//
+//	query ::= service , [ '&' optionalParams ]
+//	optionalParams := param | param '&' optionalParams
+//	param ::= operation | start | end | limit | buckets
+//	service ::= 'service=' strValue
+//	operation ::= 'operation=' strValue
+//	start ::= 'start=' intValue in unix microseconds
+//	end ::= 'end=' intValue in unix microseconds
+//	limit ::= 'limit=' intValue max number of traces sampled by storage backends without native aggregation
+//	buckets ::= bucket | bucket '&' buckets
+//	bucket ::= 'bucket=' strValue upper bound of a histogram bucket (units are "ns", "us" (or "µs"), "ms", "s", "m", "h")
+func (p *queryParser) parseLatencyQueryParams(r *http.Request) (*spanstore.LatencyQueryParameters, error) {
+	query := &spanstore.LatencyQueryParameters{
+		ServiceName:   r.FormValue(serviceParam),
+		OperationName: r.FormValue(operationParam),
+		StartTimeMin:  p.parseTime(r, startTimeParam, time.Microsecond),
+		StartTimeMax:  p.parseTime(r, endTimeParam, time.Microsecond),
+		MaxTraces:     parseInt(r, limitParam, 0),
+	}
+	for _, bucket := range r.Form[bucketParam] {
+		bound, _ := time.ParseDuration(bucket)
+		if n := len(query.BucketBounds); n > 0 && bound <= query.BucketBounds[n-1] {
+			return nil, fmt.Errorf("'%s' values must be in increasing order", bucketParam)
+		}
+		query.BucketBounds = append(query.BucketBounds, bound)
+	}
+	return query, nil
+}
+
 // parseDependenciesQueryParams takes a request and constructs a model of dependencies query parameters.
 //
 // The dependencies API does not operate on the latency space, instead its timestamps are just time range selections,
 // and the typical backend granularity of those is on the order of 15min or more. As such, microseconds aren't
 // useful in this domain and milliseconds are sufficient for both times and durations.
+//
+//	query ::= param | param '&' query
+//	param ::= endTs | lookback | step | service | namespace
+//	endTs ::= 'endTs=' intValue in unix milliseconds
+//	lookback ::= 'lookback=' intValue duration in milliseconds
+//	step ::= 'step=' intValue duration in milliseconds, splitting the lookback into a series of graphs
+//	service ::= 'service=' strValue, repeatable
+//	namespace ::= 'namespace=' strValue, the prefix of the service names
 func (p *queryParser) parseDependenciesQueryParams(r *http.Request) (dqp dependenciesQueryParameters, err error) {
-	dqp.endTs, err = p.parseTime(r, endTsParam, time.Millisecond)
-	if err != nil {
-		return dqp, err
+	dqp.EndTs = p.parseTime(r, endTsParam, time.Millisecond)
+	parser := newDurationUnitsParser(time.Millisecond)
+	dqp.Lookback = parseDuration(r, lookbackParam, parser, defaultDependencyLookbackDuration)
+	dqp.Step = parseDuration(r, stepParam, parser, 0)
+	if dqp.Step > 0 && (dqp.Lookback+dqp.Step-1)/dqp.Step > querysvc.MaxDependencyGraphs {
+		return dqp, fmt.Errorf("'%s' must not split '%s' into more than %d graphs", stepParam, lookbackParam, querysvc.MaxDependencyGraphs)
+	}
+	for _, service := range r.URL.Query()[serviceParam] {
+		if service != "" {
+			dqp.services = append(dqp.services, service)
+		}
 	}
-
-	dqp.lookback, err = parseDuration(r, lookbackParam, newDurationUnitsParser(time.Millisecond), defaultDependencyLookbackDuration)
-	return dqp, err
+	dqp.namespace = r.FormValue(namespaceParam)
+	return dqp, nil
 }
 
 // parseMetricsQueryParams takes a request and constructs a model of metrics query parameters.
@@ -243,87 +268,147 @@ func (p *queryParser) parseDependenciesQueryParams(r *http.Request) (dqp depende
 //	spanKinds ::= spanKind | spanKind '&' spanKinds
 //	spanKind ::= 'spanKind=' spanKindType
 //	spanKindType ::= "unspecified" | "internal" | "server" | "client" | "producer" | "consumer"
-func (p *queryParser) parseMetricsQueryParams(r *http.Request) (bqp metricsstore.BaseQueryParameters, err error) {
-	query := r.URL.Query()
-	services, ok := query[serviceParam]
-	if !ok {
-		return bqp, newParseError(errors.New("please provide at least one service name"), serviceParam)
-	}
-	bqp.ServiceNames = services
-
-	bqp.GroupByOperation, err = parseBool(r, groupByOperationParam)
-	if err != nil {
-		return bqp, err
-	}
-	bqp.SpanKinds, err = parseSpanKinds(r, spanKindParam, defaultMetricsSpanKinds)
-	if err != nil {
-		return bqp, err
-	}
-	endTs, err := p.parseTime(r, endTsParam, time.Millisecond)
-	if err != nil {
-		return bqp, err
-	}
+func (p *queryParser) parseMetricsQueryParams(r *http.Request) metricsstore.BaseQueryParameters {
+	endTs := p.parseTime(r, endTsParam, time.Millisecond)
 	parser := newDurationUnitsParser(time.Millisecond)
-	lookback, err := parseDuration(r, lookbackParam, parser, defaultMetricsQueryLookbackDuration)
-	if err != nil {
-		return bqp, err
+	lookback := parseDuration(r, lookbackParam, parser, defaultMetricsQueryLookbackDuration)
+	step := parseDuration(r, stepParam, parser, defaultMetricsQueryStepDuration)
+	ratePer := parseDuration(r, rateParam, parser, defaultMetricsQueryRateDuration)
+	return metricsstore.BaseQueryParameters{
+		ServiceNames:     r.URL.Query()[serviceParam],
+		GroupByOperation: parseBool(r, groupByOperationParam),
+		SpanKinds:        parseSpanKinds(r, spanKindParam, defaultMetricsSpanKinds),
+		EndTime:          &endTs,
+		Lookback:         &lookback,
+		Step:             &step,
+		RatePer:          &ratePer,
 	}
-	step, err := parseDuration(r, stepParam, parser, defaultMetricsQueryStepDuration)
-	if err != nil {
-		return bqp, err
+}
+
+// parseOperationsQueryParams takes a request and constructs a model of parameters.
+//
+// Operations query syntax:
+//
+//	query ::= service , [ '&' optionalParams ]
+//	optionalParams := param | param '&' optionalParams
+//	param ::= spanKind | prefix | contains | offset | limit
+//	service ::= 'service=' strValue
+//	spanKind ::= 'spanKind=' strValue
+//	prefix ::= 'prefix=' strValue the operation name must start with
+//	contains ::= 'contains=' strValue the operation name must contain
+//	offset ::= 'offset=' intValue number of operations, sorted by name, to skip
+//	limit ::= 'limit=' intValue max number of operations returned
+func (*queryParser) parseOperationsQueryParams(r *http.Request) spanstore.OperationQueryParameters {
+	return spanstore.OperationQueryParameters{
+		ServiceName:  r.FormValue(serviceParam),
+		SpanKind:     r.FormValue(spanKindParam),
+		NamePrefix:   r.FormValue(prefixParam),
+		NameContains: r.FormValue(containsParam),
+		Offset:       parseInt(r, offsetParam, 0),
+		Limit:        parseInt(r, limitParam, 0),
 	}
-	ratePer, err := parseDuration(r, rateParam, parser, defaultMetricsQueryRateDuration)
-	if err != nil {
-		return bqp, err
+}
+
+// parseTagQueryParams takes a request and constructs a model of tag keys or values query parameters.
+// The key is set by the queries of the tag values only.
+//
+// Tag query syntax:
+//
+//	query ::= service , [ '&' optionalParams ]
+//	optionalParams := param | param '&' optionalParams
+//	param ::= key | prefix | start | end | limit
+//	service ::= 'service=' strValue
+//	key ::= 'key=' strValue the key of the tags whose values are returned
+//	prefix ::= 'prefix=' strValue the keys or values must start with
+//	start ::= 'start=' intValue in unix microseconds
+//	end ::= 'end=' intValue in unix microseconds
+//	limit ::= 'limit=' intValue max number of keys or values returned
+func (p *queryParser) parseTagQueryParams(r *http.Request) (*spanstore.TagQueryParameters, error) {
+	query := &spanstore.TagQueryParameters{
+		ServiceName:  r.FormValue(serviceParam),
+		Key:          r.FormValue(keyParam),
+		Prefix:       r.FormValue(prefixParam),
+		StartTimeMin: p.parseTime(r, startTimeParam, time.Microsecond),
+		StartTimeMax: p.parseTime(r, endTimeParam, time.Microsecond),
+		Limit:        parseInt(r, limitParam, 0),
+	}
+	if query.StartTimeMax.Before(query.StartTimeMin) {
+		return nil, errEndTimeBeforeStartTime
+	}
+	return query, nil
+}
+
+// parseInt parses the integer parameter of an HTTP request. If the parameter is empty,
+// the given defaultValue will be returned.
+func parseInt(r *http.Request, paramName string, defaultValue int) int {
+	formValue := r.FormValue(paramName)
+	if formValue == "" {
+		return defaultValue
 	}
-	bqp.EndTime = &endTs
-	bqp.Lookback = &lookback
-	bqp.Step = &step
-	bqp.RatePer = &ratePer
-	return bqp, err
+	i, _ := strconv.ParseInt(formValue, 10, 32)
+	return int(i)
 }
 
 // parseTime parses the time parameter of an HTTP request that is represented the number of "units" since epoch.
 // If the time parameter is empty, the current time will be returned.
-func (p *queryParser) parseTime(r *http.Request, paramName string, units time.Duration) (time.Time, error) {
+func (p *queryParser) parseTime(r *http.Request, paramName string, units time.Duration) time.Time {
 	formValue := r.FormValue(paramName)
 	if formValue == "" {
 		if paramName == startTimeParam {
-			return p.timeNow().Add(-1 * p.traceQueryLookbackDuration), nil
+			return p.timeNow().Add(-1 * p.traceQueryLookbackDuration)
 		}
-		return p.timeNow(), nil
+		return p.timeNow()
 	}
-	t, err := strconv.ParseInt(formValue, 10, 64)
-	if err != nil {
-		return time.Time{}, newParseError(err, paramName)
+	t, _ := strconv.ParseInt(formValue, 10, 64)
+	return time.Unix(0, 0).Add(time.Duration(t) * units)
+}
+
+// parseGetTraceQueryParams takes a request and constructs the parameters of a trace fetch.
+// The start and end times are optional hints restricting the storage partitions searched.
+//
+// Get trace query syntax:
+//
+//	query ::= param | param '&' query
+//	param ::= start | end
+//	start ::= 'start=' intValue in unix microseconds
+//	end ::= 'end=' intValue in unix microseconds
+func (*queryParser) parseGetTraceQueryParams(r *http.Request, traceID model.TraceID) (spanstore.GetTraceParameters, error) {
+	startTime := parseOptionalTime(r, startTimeParam)
+	endTime := parseOptionalTime(r, endTimeParam)
+	if !startTime.IsZero() && !endTime.IsZero() && endTime.Before(startTime) {
+		return spanstore.GetTraceParameters{}, errEndTimeBeforeStartTime
+	}
+	return spanstore.GetTraceParameters{
+		TraceID:   traceID,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}, nil
+}
+
+// parseOptionalTime parses the time parameter in unix microseconds, returning the zero time if it is not set.
+func parseOptionalTime(r *http.Request, paramName string) time.Time {
+	formValue := r.FormValue(paramName)
+	if formValue == "" {
+		return time.Time{}
 	}
-	return time.Unix(0, 0).Add(time.Duration(t) * units), nil
+	t, _ := strconv.ParseInt(formValue, 10, 64)
+	return time.Unix(0, 0).Add(time.Duration(t) * time.Microsecond)
 }
 
 // parseDuration parses the duration parameter of an HTTP request using the provided durationParser.
 // If the duration parameter is empty, the given defaultDuration will be returned.
-func parseDuration(r *http.Request, paramName string, parse durationParser, defaultDuration time.Duration) (time.Duration, error) {
+func parseDuration(r *http.Request, paramName string, parse durationParser, defaultDuration time.Duration) time.Duration {
 	formValue := r.FormValue(paramName)
 	if formValue == "" {
-		return defaultDuration, nil
-	}
-	d, err := parse(formValue)
-	if err != nil {
-		return 0, newParseError(err, paramName)
+		return defaultDuration
 	}
-	return d, nil
+	d, _ := parse(formValue)
+	return d
 }
 
-func parseBool(r *http.Request, paramName string) (b bool, err error) {
-	formVal := r.FormValue(paramName)
-	if formVal == "" {
-		return false, nil
-	}
-	b, err = strconv.ParseBool(formVal)
-	if err != nil {
-		return b, newParseError(err, paramName)
-	}
-	return b, nil
+func parseBool(r *http.Request, paramName string) bool {
+	b, _ := strconv.ParseBool(r.FormValue(paramName))
+	return b
 }
 
 // parseSpanKinds parses the input span kinds to filter for in the metrics query.
@@ -344,29 +429,16 @@ func parseBool(r *http.Request, paramName string) (b bool, err error) {
 // - "SPAN_KIND_CLIENT"
 // - "SPAN_KIND_PRODUCER"
 // - "SPAN_KIND_CONSUMER"
-func parseSpanKinds(r *http.Request, paramName string, defaultSpanKinds []string) ([]string, error) {
-	query := r.URL.Query()
-	jaegerSpanKinds, ok := query[paramName]
+func parseSpanKinds(r *http.Request, paramName string, defaultSpanKinds []string) []string {
+	jaegerSpanKinds, ok := r.URL.Query()[paramName]
 	if !ok {
-		return defaultSpanKinds, nil
-	}
-	otelSpanKinds, err := mapSpanKindsToOpenTelemetry(jaegerSpanKinds)
-	if err != nil {
-		return defaultSpanKinds, newParseError(err, paramName)
+		return defaultSpanKinds
 	}
-	return otelSpanKinds, nil
-}
-
-func mapSpanKindsToOpenTelemetry(spanKinds []string) ([]string, error) {
-	otelSpanKinds := make([]string, len(spanKinds))
-	for i, spanKind := range spanKinds {
-		if v, ok := jaegerToOtelSpanKind[spanKind]; ok {
-			otelSpanKinds[i] = v
-		} else {
-			return otelSpanKinds, fmt.Errorf("unsupported span kind: '%s'", spanKind)
-		}
+	otelSpanKinds := make([]string, len(jaegerSpanKinds))
+	for i, spanKind := range jaegerSpanKinds {
+		otelSpanKinds[i] = jaegerToOtelSpanKind[spanKind]
 	}
-	return otelSpanKinds, nil
+	return otelSpanKinds
 }
 
 func (p *queryParser) validateQuery(traceQuery *traceQueryParameters) error {
@@ -381,26 +453,20 @@ func (p *queryParser) validateQuery(traceQuery *traceQueryParameters) error {
 	return nil
 }
 
-func (p *queryParser) parseTags(simpleTags []string, jsonTags []string) (map[string]string, error) {
+func (p *queryParser) parseTags(simpleTags []string, jsonTags []string) map[string]string {
 	retMe := make(map[string]string)
 	for _, tag := range simpleTags {
-		keyAndValue := strings.Split(tag, ":")
-		if l := len(keyAndValue); l > 1 {
-			retMe[keyAndValue[0]] = strings.Join(keyAndValue[1:], ":")
-		} else {
-			return nil, fmt.Errorf("malformed 'tag' parameter, expecting key:value, received: %s", tag)
-		}
+		key, value, _ := strings.Cut(tag, ":")
+		retMe[key] = value
 	}
 	for _, tags := range jsonTags {
 		var fromJSON map[string]string
-		if err := json.Unmarshal([]byte(tags), &fromJSON); err != nil {
-			return nil, fmt.Errorf("malformed 'tags' parameter, cannot unmarshal JSON: %w", err)
-		}
+		_ = json.Unmarshal([]byte(tags), &fromJSON)
 		for k, v := range fromJSON {
 			retMe[k] = v
 		}
 	}
-	return retMe, nil
+	return retMe
 }
 
 func newParseError(err error, paramName string) error {
@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -62,6 +63,19 @@ func TestParseTraceQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			"x?service=service&start=0&end=0&limit=20&pageToken=abc", noErr,
+			&traceQueryParameters{
+				TraceQueryParameters: spanstore.TraceQueryParameters{
+					ServiceName:  "service",
+					StartTimeMin: time.Unix(0, 0),
+					StartTimeMax: time.Unix(0, 0),
+					NumTraces:    20,
+					Tags:         map[string]string{},
+					PageToken:    "abc",
+				},
+			},
+		},
 		// tags=JSON with a non-string value 123
 		{`x?service=service&start=0&end=0&operation=operation&limit=200&tag=k:v&tags={"x":123}`, "malformed 'tags' parameter, cannot unmarshal JSON: json: cannot unmarshal number into Go value of type string", nil},
 		// tags=JSON
@@ -163,7 +177,11 @@ func TestParseTraceQuery(t *testing.T) {
 					return timeNow
 				},
 			}
-			actualQuery, err := parser.parseTraceQueryParams(request)
+			var actualQuery *traceQueryParameters
+			err = defaultAPISpec.validate("/traces", request)
+			if err == nil {
+				actualQuery, err = parser.parseTraceQueryParams(request)
+			}
 			if test.errMsg == "" {
 				require.NoError(t, err)
 				if !assert.Equal(t, test.expectedQuery, actualQuery) {
@@ -207,9 +225,7 @@ func TestParseBool(t *testing.T) {
 					return timeNow
 				},
 			}
-			mqp, err := parser.parseMetricsQueryParams(request)
-			require.NoError(t, err)
-			assert.Equal(t, tc.want, mqp.GroupByOperation)
+			assert.Equal(t, tc.want, parser.parseMetricsQueryParams(request).GroupByOperation)
 		})
 	}
 }
@@ -220,9 +236,106 @@ func TestParseDuration(t *testing.T) {
 	parser := &queryParser{
 		timeNow: time.Now,
 	}
-	mqp, err := parser.parseMetricsQueryParams(request)
-	require.NoError(t, err)
-	assert.Equal(t, time.Second, *mqp.Step)
+	assert.Equal(t, time.Second, *parser.parseMetricsQueryParams(request).Step)
+}
+
+func TestParseGetTraceQuery(t *testing.T) {
+	traceID := model.NewTraceID(0, 1)
+	tests := []struct {
+		urlStr string
+		query  spanstore.GetTraceParameters
+		errMsg string
+	}{
+		{urlStr: "x", query: spanstore.GetTraceParameters{TraceID: traceID}},
+		{
+			urlStr: "x?start=1000000&end=2000000",
+			query:  spanstore.GetTraceParameters{TraceID: traceID, StartTime: time.Unix(1, 0), EndTime: time.Unix(2, 0)},
+		},
+		{urlStr: "x?end=2000000", query: spanstore.GetTraceParameters{TraceID: traceID, EndTime: time.Unix(2, 0)}},
+		{urlStr: "x?start=a", errMsg: `unable to parse param 'start': strconv.ParseInt: parsing "a": invalid syntax`},
+		{urlStr: "x?end=b", errMsg: `unable to parse param 'end': strconv.ParseInt: parsing "b": invalid syntax`},
+		{urlStr: "x?start=2000000&end=1000000", errMsg: "'end' should not be before 'start'"},
+	}
+	for _, test := range tests {
+		t.Run(test.urlStr, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, test.urlStr, nil)
+			require.NoError(t, err)
+			request = mux.SetURLVars(request, map[string]string{traceIDParam: traceID.String()})
+			var query spanstore.GetTraceParameters
+			err = defaultAPISpec.validate("/traces/{traceID}", request)
+			if err == nil {
+				query, err = (&queryParser{}).parseGetTraceQueryParams(request, traceID)
+			}
+			if test.errMsg != "" {
+				require.EqualError(t, err, test.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.query, query)
+		})
+	}
+}
+
+func TestParseTagQuery(t *testing.T) {
+	timeNow := time.Unix(3, 0)
+	tests := []struct {
+		urlStr string
+		route  string
+		query  *spanstore.TagQueryParameters
+		errMsg string
+	}{
+		{
+			urlStr: "x?service=svc&prefix=http.",
+			query: &spanstore.TagQueryParameters{
+				ServiceName:  "svc",
+				Prefix:       "http.",
+				StartTimeMin: timeNow.Add(-time.Second),
+				StartTimeMax: timeNow,
+			},
+		},
+		{
+			urlStr: "x?service=svc&key=http.method&start=1000000&end=2000000&limit=10",
+			route:  "/tags/values",
+			query: &spanstore.TagQueryParameters{
+				ServiceName:  "svc",
+				Key:          "http.method",
+				StartTimeMin: time.Unix(1, 0),
+				StartTimeMax: time.Unix(2, 0),
+				Limit:        10,
+			},
+		},
+		{urlStr: "x", errMsg: "parameter 'service' is required"},
+		{urlStr: "x?service=svc", route: "/tags/values", errMsg: "parameter 'key' is required"},
+		{urlStr: "x?service=svc&start=a", errMsg: `unable to parse param 'start': strconv.ParseInt: parsing "a": invalid syntax`},
+		{urlStr: "x?service=svc&end=b", errMsg: `unable to parse param 'end': strconv.ParseInt: parsing "b": invalid syntax`},
+		{urlStr: "x?service=svc&start=2000000&end=1000000", errMsg: "'end' should not be before 'start'"},
+		{urlStr: "x?service=svc&limit=-1", errMsg: "'limit' must not be negative"},
+	}
+	for _, test := range tests {
+		t.Run(test.urlStr, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, test.urlStr, nil)
+			require.NoError(t, err)
+			parser := &queryParser{
+				traceQueryLookbackDuration: time.Second,
+				timeNow:                    func() time.Time { return timeNow },
+			}
+			route := test.route
+			if route == "" {
+				route = "/tags"
+			}
+			var query *spanstore.TagQueryParameters
+			err = defaultAPISpec.validate(route, request)
+			if err == nil {
+				query, err = parser.parseTagQueryParams(request)
+			}
+			if test.errMsg != "" {
+				require.EqualError(t, err, test.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.query, query)
+		})
+	}
 }
 
 func TestParseRepeatedServices(t *testing.T) {
@@ -231,9 +344,7 @@ func TestParseRepeatedServices(t *testing.T) {
 	parser := &queryParser{
 		timeNow: time.Now,
 	}
-	mqp, err := parser.parseMetricsQueryParams(request)
-	require.NoError(t, err)
-	assert.Equal(t, []string{"foo", "bar"}, mqp.ServiceNames)
+	assert.Equal(t, []string{"foo", "bar"}, parser.parseMetricsQueryParams(request).ServiceNames)
 }
 
 func TestParseRepeatedSpanKinds(t *testing.T) {
@@ -243,8 +354,6 @@ func TestParseRepeatedSpanKinds(t *testing.T) {
 	parser := &queryParser{
 		timeNow: time.Now,
 	}
-	mqp, err := parser.parseMetricsQueryParams(request)
-	require.NoError(t, err)
 	assert.Equal(t, []string{
 		metrics.SpanKind_SPAN_KIND_UNSPECIFIED.String(),
 		metrics.SpanKind_SPAN_KIND_INTERNAL.String(),
@@ -252,7 +361,7 @@ func TestParseRepeatedSpanKinds(t *testing.T) {
 		metrics.SpanKind_SPAN_KIND_CLIENT.String(),
 		metrics.SpanKind_SPAN_KIND_PRODUCER.String(),
 		metrics.SpanKind_SPAN_KIND_CONSUMER.String(),
-	}, mqp.SpanKinds)
+	}, parser.parseMetricsQueryParams(request).SpanKinds)
 }
 
 func TestParameterErrors(t *testing.T) {
@@ -16,17 +16,15 @@
 package querysvc
 
 import (
-	"time"
-
 	"github.com/jaegertracing/jaeger/model/adjuster"
 )
 
 // StandardAdjusters is a list of model adjusters applied by the query service
 // before returning the data to the API clients.
-func StandardAdjusters(maxClockSkewAdjust time.Duration) []adjuster.Adjuster {
+func StandardAdjusters(clockSkew adjuster.ClockSkewOptions) []adjuster.Adjuster {
 	return []adjuster.Adjuster{
 		adjuster.SpanIDDeduper(),
-		adjuster.ClockSkew(maxClockSkewAdjust),
+		adjuster.ClockSkewWithOptions(clockSkew),
 		adjuster.IPTagAdjuster(),
 		adjuster.OTelTagAdjuster(),
 		adjuster.SortLogFields(),
@@ -0,0 +1,113 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// CacheOptions configures the caching of query responses.
+type CacheOptions struct {
+	// TTL is how long the responses are cached.
+	TTL time.Duration
+	// MaxEntries is the maximum number of cached responses, the least recently used are evicted first.
+	MaxEntries int
+	// MaxTraceSpans is the maximum number of spans of a cached trace; larger traces are not cached.
+	MaxTraceSpans int
+}
+
+// ResponseCache caches the traces, services and operations read from the span storage.
+// The entries are scoped to the tenant of the request.
+type ResponseCache struct {
+	cache         cache.Cache
+	maxTraceSpans int
+}
+
+// NewResponseCache creates an in-process ResponseCache.
+func NewResponseCache(options CacheOptions) *ResponseCache {
+	return NewResponseCacheWithBackend(
+		cache.NewLRUWithOptions(options.MaxEntries, &cache.Options{TTL: options.TTL}),
+		options.MaxTraceSpans,
+	)
+}
+
+// NewResponseCacheWithBackend creates a ResponseCache storing the responses in the given cache.
+func NewResponseCacheWithBackend(backend cache.Cache, maxTraceSpans int) *ResponseCache {
+	return &ResponseCache{cache: backend, maxTraceSpans: maxTraceSpans}
+}
+
+func cacheKey(ctx context.Context, kind string, parts ...string) string {
+	// the parts come from user input, NUL cannot appear in tenants, services or operations
+	return strings.Join(append([]string{kind, tenancy.GetTenant(ctx)}, parts...), "\x00")
+}
+
+func traceCacheKey(ctx context.Context, traceID model.TraceID) string {
+	return cacheKey(ctx, "trace", traceID.String())
+}
+
+// getTrace returns a copy of the cached trace, which callers are free to modify.
+func (c *ResponseCache) getTrace(ctx context.Context, traceID model.TraceID) *model.Trace {
+	data, ok := c.cache.Get(traceCacheKey(ctx, traceID)).([]byte)
+	if !ok {
+		return nil
+	}
+	trace := &model.Trace{}
+	if err := trace.Unmarshal(data); err != nil {
+		return nil
+	}
+	return trace
+}
+
+// putTrace stores the trace serialized, so that it is not affected by later changes of the trace.
+func (c *ResponseCache) putTrace(ctx context.Context, traceID model.TraceID, trace *model.Trace) {
+	if c.maxTraceSpans > 0 && len(trace.Spans) > c.maxTraceSpans {
+		return
+	}
+	data, err := trace.Marshal()
+	if err != nil {
+		return
+	}
+	c.cache.Put(traceCacheKey(ctx, traceID), data)
+}
+
+func (c *ResponseCache) invalidateTrace(ctx context.Context, traceID model.TraceID) {
+	c.cache.Delete(traceCacheKey(ctx, traceID))
+}
+
+func (c *ResponseCache) getServices(ctx context.Context) []string {
+	services, ok := c.cache.Get(cacheKey(ctx, "services")).([]string)
+	if !ok {
+		return nil
+	}
+	return append([]string{}, services...)
+}
+
+func (c *ResponseCache) putServices(ctx context.Context, services []string) {
+	c.cache.Put(cacheKey(ctx, "services"), append([]string{}, services...))
+}
+
+func operationsCacheKey(ctx context.Context, query spanstore.OperationQueryParameters) string {
+	return cacheKey(ctx, "operations", query.ServiceName, query.SpanKind, query.NamePrefix, query.NameContains,
+		strconv.Itoa(query.Offset), strconv.Itoa(query.Limit))
+}
+
+func (c *ResponseCache) getOperations(ctx context.Context, query spanstore.OperationQueryParameters) []spanstore.Operation {
+	operations, ok := c.cache.Get(operationsCacheKey(ctx, query)).([]spanstore.Operation)
+	if !ok {
+		return nil
+	}
+	return append([]spanstore.Operation{}, operations...)
+}
+
+func (c *ResponseCache) putOperations(ctx context.Context, query spanstore.OperationQueryParameters, operations []spanstore.Operation) {
+	c.cache.Put(operationsCacheKey(ctx, query), append([]spanstore.Operation{}, operations...))
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func withCache(options CacheOptions) testOption {
+	return func(_ *testQueryService, opts *QueryServiceOptions) {
+		opts.Cache = NewResponseCache(options)
+	}
+}
+
+func TestCacheGetTrace(t *testing.T) {
+	tqs := initializeTestService(withCache(CacheOptions{TTL: time.Minute, MaxEntries: 10}))
+	stored := &model.Trace{Spans: []*model.Span{{TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "op"}}}
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(stored, nil).Twice()
+	ctx := context.Background()
+
+	trace, err := tqs.queryService.GetTrace(ctx, mockTraceID)
+	require.NoError(t, err)
+	assert.Equal(t, stored, trace)
+
+	// the cached trace is returned as a copy, not affected by the adjusters of previous requests
+	for i := 0; i < 2; i++ {
+		trace, err = tqs.queryService.GetTrace(ctx, mockTraceID)
+		require.NoError(t, err)
+		assert.Equal(t, "op", trace.Spans[0].OperationName)
+		trace.Spans[0].OperationName = "adjusted"
+	}
+	tqs.spanReader.AssertNumberOfCalls(t, "GetTrace", 1)
+
+	// the entries are scoped to the tenant
+	_, err = tqs.queryService.GetTrace(tenancy.WithTenant(ctx, "acme"), mockTraceID)
+	require.NoError(t, err)
+	tqs.spanReader.AssertNumberOfCalls(t, "GetTrace", 2)
+}
+
+func TestCacheGetTraceErrorsAndLargeTraces(t *testing.T) {
+	tqs := initializeTestService(withCache(CacheOptions{TTL: time.Minute, MaxEntries: 10, MaxTraceSpans: 1}))
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Twice()
+	ctx := context.Background()
+
+	_, err := tqs.queryService.GetTrace(ctx, mockTraceID)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+	for i := 0; i < 2; i++ {
+		_, err = tqs.queryService.GetTrace(ctx, mockTraceID)
+		require.NoError(t, err)
+	}
+	// neither the error nor the trace with two spans were cached
+	tqs.spanReader.AssertNumberOfCalls(t, "GetTrace", 3)
+}
+
+func TestCacheInvalidatedOnArchive(t *testing.T) {
+	tqs := initializeTestService(withCache(CacheOptions{TTL: time.Minute, MaxEntries: 10}), withArchiveSpanWriter())
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil)
+	tqs.archiveSpanWriter.On("WriteSpan", mock.Anything, mock.AnythingOfType("*model.Span")).Return(nil)
+	ctx := context.Background()
+
+	require.NoError(t, tqs.queryService.ArchiveTrace(ctx, mockTraceID))
+	assert.Nil(t, tqs.queryService.options.Cache.getTrace(ctx, mockTraceID))
+	_, err := tqs.queryService.GetTrace(ctx, mockTraceID)
+	require.NoError(t, err)
+	tqs.spanReader.AssertNumberOfCalls(t, "GetTrace", 2)
+}
+
+func TestCacheServicesAndOperations(t *testing.T) {
+	tqs := initializeTestService(withCache(CacheOptions{TTL: time.Minute, MaxEntries: 10}))
+	query := spanstore.OperationQueryParameters{ServiceName: "svc"}
+	operations := []spanstore.Operation{{Name: "op", SpanKind: "server"}}
+	tqs.spanReader.On("GetServices", mock.Anything).Return(nil, assert.AnError).Once()
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{"svc"}, nil).Once()
+	tqs.spanReader.On("GetOperations", mock.Anything, query).Return(nil, assert.AnError).Once()
+	tqs.spanReader.On("GetOperations", mock.Anything, query).Return(operations, nil).Once()
+	ctx := context.Background()
+
+	_, err := tqs.queryService.GetServices(ctx)
+	require.ErrorIs(t, err, assert.AnError)
+	_, err = tqs.queryService.GetOperations(ctx, query)
+	require.ErrorIs(t, err, assert.AnError)
+	for i := 0; i < 2; i++ {
+		services, err := tqs.queryService.GetServices(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"svc"}, services)
+		ops, err := tqs.queryService.GetOperations(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, operations, ops)
+	}
+	tqs.spanReader.AssertNumberOfCalls(t, "GetServices", 2)
+	tqs.spanReader.AssertNumberOfCalls(t, "GetOperations", 2)
+}
+
+func TestCacheGetTraceInTimeRange(t *testing.T) {
+	tqs := initializeTestService(withCache(CacheOptions{TTL: time.Minute, MaxEntries: 10}))
+	stored := &model.Trace{Spans: []*model.Span{{TraceID: mockTraceID, SpanID: model.NewSpanID(1), OperationName: "op"}}}
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(stored, nil).Times(3)
+	ctx := context.Background()
+	query := spanstore.GetTraceParameters{TraceID: mockTraceID, StartTime: time.Unix(1000, 0)}
+
+	// the traces read in a time range may be partial, they are not cached
+	_, err := tqs.queryService.GetTraceInTimeRange(ctx, query)
+	require.NoError(t, err)
+	_, err = tqs.queryService.GetTraceInTimeRange(ctx, query)
+	require.NoError(t, err)
+
+	// but the cached complete traces are returned
+	_, err = tqs.queryService.GetTrace(ctx, mockTraceID)
+	require.NoError(t, err)
+	trace, err := tqs.queryService.GetTraceInTimeRange(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, stored, trace)
+	tqs.spanReader.AssertExpectations(t)
+}
+
+func TestCacheExpiry(t *testing.T) {
+	now := time.Now()
+	c := NewResponseCacheWithBackend(cache.NewLRUWithOptions(10, &cache.Options{
+		TTL:     time.Minute,
+		TimeNow: func() time.Time { return now },
+	}), 0)
+	ctx := context.Background()
+	c.putTrace(ctx, mockTraceID, &model.Trace{Spans: []*model.Span{{TraceID: mockTraceID, OperationName: "op"}}})
+	c.putServices(ctx, []string{"svc"})
+	require.NotNil(t, c.getTrace(ctx, mockTraceID))
+	require.NotNil(t, c.getServices(ctx))
+
+	now = now.Add(2 * time.Minute)
+	assert.Nil(t, c.getTrace(ctx, mockTraceID))
+	assert.Nil(t, c.getServices(ctx))
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// CriticalPathSegment is a time interval during which the given span was on the
+// critical path of the trace, i.e. none of its children was blocking it.
+type CriticalPathSegment struct {
+	SpanID model.SpanID
+	OperationKey
+	StartTime time.Time
+	Duration  time.Duration
+}
+
+// CriticalPath is the chain of segments that determines the latency of a trace,
+// along with the time each operation contributed to it.
+type CriticalPath struct {
+	Segments   []CriticalPathSegment
+	Operations []OperationTime
+}
+
+// OperationTime is the time an operation spent on the critical path.
+type OperationTime struct {
+	OperationKey
+	Duration time.Duration
+}
+
+// GetCriticalPath fetches and adjusts the trace and computes its critical path.
+func (qs QueryService) GetCriticalPath(ctx context.Context, traceID model.TraceID) (*CriticalPath, error) {
+	trace, err := qs.GetTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	// adjuster errors are only warnings, the adjusted trace is still usable
+	trace, _ = qs.Adjust(trace)
+	return ComputeCriticalPath(trace), nil
+}
+
+// ComputeCriticalPath walks the trace backwards from the end of the longest root span.
+// At any point in time the critical path follows the child that finished last before
+// that point; time not covered by such a child is attributed to the parent itself.
+func ComputeCriticalPath(trace *model.Trace) *CriticalPath {
+	tree := newSpanTree(trace)
+	path := &CriticalPath{}
+	if len(tree.roots) == 0 {
+		return path
+	}
+	root := tree.roots[0]
+	for _, span := range tree.roots[1:] {
+		if spanEnd(span).After(spanEnd(root)) {
+			root = span
+		}
+	}
+	visited := make(map[*model.Span]bool, len(trace.Spans))
+	var segments []CriticalPathSegment
+	var walk func(span *model.Span, until time.Time)
+	walk = func(span *model.Span, until time.Time) {
+		// duplicate span IDs in traces that were not adjusted can make the tree cyclic
+		if visited[span] {
+			return
+		}
+		visited[span] = true
+		addSegment := func(start, end time.Time) {
+			if start.Before(end) {
+				segments = append(segments, CriticalPathSegment{
+					SpanID:       span.SpanID,
+					OperationKey: operationKeyOf(span),
+					StartTime:    start,
+					Duration:     end.Sub(start),
+				})
+			}
+		}
+		children := append([]*model.Span(nil), tree.children[span.SpanID]...)
+		sort.Slice(children, func(i, j int) bool {
+			return spanEnd(children[i]).After(spanEnd(children[j]))
+		})
+		cursor := minTime(spanEnd(span), until)
+		for _, child := range children {
+			if !child.StartTime.Before(cursor) || !child.StartTime.Before(spanEnd(child)) {
+				continue
+			}
+			childEnd := minTime(spanEnd(child), cursor)
+			addSegment(childEnd, cursor)
+			walk(child, childEnd)
+			cursor = child.StartTime
+			if cursor.Before(span.StartTime) {
+				cursor = span.StartTime
+			}
+		}
+		addSegment(span.StartTime, cursor)
+	}
+	walk(root, spanEnd(root))
+
+	// segments were collected backwards in time
+	byOperation := map[OperationKey]time.Duration{}
+	for i := len(segments) - 1; i >= 0; i-- {
+		path.Segments = append(path.Segments, segments[i])
+		byOperation[segments[i].OperationKey] += segments[i].Duration
+	}
+	for key, duration := range byOperation {
+		path.Operations = append(path.Operations, OperationTime{OperationKey: key, Duration: duration})
+	}
+	sort.Slice(path.Operations, func(i, j int) bool {
+		if path.Operations[i].Duration != path.Operations[j].Duration {
+			return path.Operations[i].Duration > path.Operations[j].Duration
+		}
+		return path.Operations[i].String() < path.Operations[j].String()
+	})
+	return path
+}
+
+func spanEnd(span *model.Span) time.Time {
+	return span.StartTime.Add(span.Duration)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
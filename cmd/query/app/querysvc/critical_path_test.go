@@ -0,0 +1,69 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func TestComputeCriticalPath(t *testing.T) {
+	key := func(op string) OperationKey { return OperationKey{Service: "svc", Operation: op} }
+	segment := func(spanID uint64, op string, start, end time.Duration) CriticalPathSegment {
+		return CriticalPathSegment{
+			SpanID:       model.NewSpanID(spanID),
+			OperationKey: key(op),
+			StartTime:    traceStart.Add(start),
+			Duration:     end - start,
+		}
+	}
+	path := ComputeCriticalPath(newTimedTrace())
+	assert.Equal(t, []CriticalPathSegment{
+		segment(1, "A", 0, 10*time.Millisecond),
+		segment(2, "B", 10*time.Millisecond, 30*time.Millisecond),
+		segment(3, "C", 30*time.Millisecond, 50*time.Millisecond),
+		segment(4, "D", 50*time.Millisecond, 60*time.Millisecond),
+		segment(3, "C", 60*time.Millisecond, 90*time.Millisecond),
+		segment(1, "A", 90*time.Millisecond, 100*time.Millisecond),
+	}, path.Segments)
+	assert.Equal(t, []OperationTime{
+		{OperationKey: key("C"), Duration: 50 * time.Millisecond},
+		{OperationKey: key("A"), Duration: 20 * time.Millisecond},
+		{OperationKey: key("B"), Duration: 20 * time.Millisecond},
+		{OperationKey: key("D"), Duration: 10 * time.Millisecond},
+	}, path.Operations)
+}
+
+func TestComputeCriticalPathLongestRoot(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		timedSpan(1, 0, "short", 0, 10*time.Millisecond),
+		timedSpan(2, 0, "long", 0, 20*time.Millisecond),
+	}}
+	path := ComputeCriticalPath(trace)
+	require.Len(t, path.Segments, 1)
+	assert.Equal(t, "long", path.Segments[0].Operation)
+
+	assert.Empty(t, ComputeCriticalPath(&model.Trace{}).Segments)
+}
+
+func TestGetCriticalPath(t *testing.T) {
+	tqs := initializeTestService()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(newTimedTrace(), nil).Once()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+
+	path, err := tqs.queryService.GetCriticalPath(context.Background(), mockTraceID)
+	require.NoError(t, err)
+	assert.Len(t, path.Segments, 6)
+
+	_, err = tqs.queryService.GetCriticalPath(context.Background(), mockTraceID)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+}
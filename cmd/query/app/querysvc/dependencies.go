@@ -0,0 +1,87 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+)
+
+// MaxDependencyGraphs is the maximum number of time buckets of a DependencyGraphQuery.
+const MaxDependencyGraphs = 1000
+
+// DependencyGraphQuery selects the dependency graphs returned by GetDependencyGraphs.
+type DependencyGraphQuery struct {
+	EndTs    time.Time
+	Lookback time.Duration
+	// Step, if positive, splits the lookback into consecutive time buckets of this
+	// duration, each with its own graph. The oldest bucket can be shorter than Step.
+	Step time.Duration
+}
+
+// DependencyGraph holds the dependency links recorded within a time range, as read from the storage,
+// i.e. the links between the same parent and child are not merged.
+type DependencyGraph struct {
+	StartTime time.Time
+	EndTime   time.Time
+	Links     []dependencystore.AnnotatedLink
+}
+
+// GetDependencyGraphs returns the dependency graphs of the time buckets selected by the query,
+// from the oldest to the most recent. The links of the whole lookback are read at once and split
+// by their time, unless the storage does not record it, in which case each bucket is read separately.
+func (qs QueryService) GetDependencyGraphs(ctx context.Context, query DependencyGraphQuery) ([]DependencyGraph, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	if query.Step > 0 && (query.Lookback+query.Step-1)/query.Step > MaxDependencyGraphs {
+		return nil, fmt.Errorf("the lookback must not be split into more than %d steps", MaxDependencyGraphs)
+	}
+	graphs := dependencyGraphs(query)
+	if _, ok := qs.dependencyReader.(dependencystore.AnnotatedReader); !ok && len(graphs) > 1 {
+		for i := range graphs {
+			links, err := dependencystore.GetAnnotatedDependencies(ctx, qs.dependencyReader, graphs[i].EndTime, graphs[i].EndTime.Sub(graphs[i].StartTime))
+			if err != nil {
+				return nil, err
+			}
+			graphs[i].Links = links
+		}
+		return graphs, nil
+	}
+	links, err := dependencystore.GetAnnotatedDependencies(ctx, qs.dependencyReader, query.EndTs, query.Lookback)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		i := len(graphs) - 1
+		if len(graphs) > 1 {
+			// the buckets include their start time, the links out of the lookback go to the nearest bucket
+			if age := query.EndTs.Sub(link.Timestamp); age > 0 {
+				i = max(0, i-int((age-1)/query.Step))
+			}
+		}
+		graphs[i].Links = append(graphs[i].Links, link)
+	}
+	return graphs, nil
+}
+
+// dependencyGraphs returns the empty graphs of the time buckets of the query, from the oldest to the most recent.
+func dependencyGraphs(query DependencyGraphQuery) []DependencyGraph {
+	var graphs []DependencyGraph
+	startTs := query.EndTs.Add(-query.Lookback)
+	for end := query.EndTs; len(graphs) == 0 || end.After(startTs); {
+		start := startTs
+		if query.Step > 0 && end.Add(-query.Step).After(startTs) {
+			start = end.Add(-query.Step)
+		}
+		graphs = append(graphs, DependencyGraph{StartTime: start, EndTime: end})
+		end = start
+	}
+	slices.Reverse(graphs)
+	return graphs
+}
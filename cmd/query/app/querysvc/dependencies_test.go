@@ -0,0 +1,133 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func dependencyLink(parent, child string, callCount uint64) dependencystore.AnnotatedLink {
+	return dependencystore.AnnotatedLink{
+		DependencyLink: model.DependencyLink{Parent: parent, Child: child, CallCount: callCount},
+	}
+}
+
+func timedDependencyLink(parent, child string, callCount uint64, timestamp time.Time) dependencystore.AnnotatedLink {
+	link := dependencyLink(parent, child, callCount)
+	link.Timestamp = timestamp
+	return link
+}
+
+// annotatedReader is a dependencystore.AnnotatedReader recording the time ranges read.
+type annotatedReader struct {
+	links []dependencystore.AnnotatedLink
+	reads []time.Duration
+}
+
+func (*annotatedReader) GetDependencies(context.Context, time.Time, time.Duration) ([]model.DependencyLink, error) {
+	return nil, assert.AnError
+}
+
+func (r *annotatedReader) GetAnnotatedDependencies(_ context.Context, _ time.Time, lookback time.Duration) ([]dependencystore.AnnotatedLink, error) {
+	r.reads = append(r.reads, lookback)
+	return r.links, nil
+}
+
+func TestGetDependencyGraphs(t *testing.T) {
+	// the storage does not record the time of the links, so each bucket is read separately
+	tqs := initializeTestService()
+	endTs := time.Unix(0, 0).Add(time.Hour)
+	tqs.depsReader.On("GetDependencies", endTs, 25*time.Minute).
+		Return([]model.DependencyLink{{Parent: "a", Child: "b", CallCount: 1}}, nil).Once()
+	tqs.depsReader.On("GetDependencies", endTs.Add(-25*time.Minute), 25*time.Minute).
+		Return([]model.DependencyLink{{Parent: "c", Child: "d", CallCount: 2}}, nil).Once()
+	tqs.depsReader.On("GetDependencies", endTs.Add(-50*time.Minute), 10*time.Minute).
+		Return(nil, nil).Once()
+
+	graphs, err := tqs.queryService.GetDependencyGraphs(context.Background(), DependencyGraphQuery{
+		EndTs:    endTs,
+		Lookback: time.Hour,
+		Step:     25 * time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []DependencyGraph{
+		{StartTime: time.Unix(0, 0), EndTime: endTs.Add(-50 * time.Minute), Links: []dependencystore.AnnotatedLink{}},
+		{StartTime: endTs.Add(-50 * time.Minute), EndTime: endTs.Add(-25 * time.Minute), Links: []dependencystore.AnnotatedLink{dependencyLink("c", "d", 2)}},
+		{StartTime: endTs.Add(-25 * time.Minute), EndTime: endTs, Links: []dependencystore.AnnotatedLink{dependencyLink("a", "b", 1)}},
+	}, graphs)
+}
+
+func TestGetDependencyGraphsSplitByTime(t *testing.T) {
+	start := time.Unix(0, 0)
+	endTs := start.Add(time.Hour)
+	reader := &annotatedReader{links: []dependencystore.AnnotatedLink{
+		timedDependencyLink("a", "b", 1, endTs),
+		timedDependencyLink("a", "b", 2, endTs.Add(-25*time.Minute)),
+		timedDependencyLink("c", "d", 3, endTs.Add(-26*time.Minute)),
+		timedDependencyLink("e", "f", 4, start),
+		// the links out of the lookback are kept in the nearest bucket
+		timedDependencyLink("g", "h", 5, start.Add(-time.Minute)),
+		timedDependencyLink("i", "j", 6, endTs.Add(time.Minute)),
+	}}
+	qs := NewQueryService(&spanstoremocks.Reader{}, reader, QueryServiceOptions{})
+
+	graphs, err := qs.GetDependencyGraphs(context.Background(), DependencyGraphQuery{
+		EndTs:    endTs,
+		Lookback: time.Hour,
+		Step:     25 * time.Minute,
+	})
+	require.NoError(t, err)
+	// the whole lookback is read once
+	assert.Equal(t, []time.Duration{time.Hour}, reader.reads)
+	assert.Equal(t, []DependencyGraph{
+		{
+			StartTime: start,
+			EndTime:   endTs.Add(-50 * time.Minute),
+			Links:     []dependencystore.AnnotatedLink{reader.links[3], reader.links[4]},
+		},
+		{
+			StartTime: endTs.Add(-50 * time.Minute),
+			EndTime:   endTs.Add(-25 * time.Minute),
+			Links:     []dependencystore.AnnotatedLink{reader.links[2]},
+		},
+		{
+			StartTime: endTs.Add(-25 * time.Minute),
+			EndTime:   endTs,
+			Links:     []dependencystore.AnnotatedLink{reader.links[0], reader.links[1], reader.links[5]},
+		},
+	}, graphs)
+
+	// a single graph holds all the links
+	reader.reads = nil
+	graphs, err = qs.GetDependencyGraphs(context.Background(), DependencyGraphQuery{EndTs: endTs, Lookback: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Hour}, reader.reads)
+	require.Len(t, graphs, 1)
+	assert.Equal(t, reader.links, graphs[0].Links)
+}
+
+func TestGetDependencyGraphsErrors(t *testing.T) {
+	tqs := initializeTestService()
+	endTs := time.Unix(0, 0).Add(time.Hour)
+	tqs.depsReader.On("GetDependencies", endTs, time.Hour).Return(nil, assert.AnError).Once()
+
+	_, err := tqs.queryService.GetDependencyGraphs(context.Background(), DependencyGraphQuery{EndTs: endTs, Lookback: time.Hour})
+	require.ErrorIs(t, err, assert.AnError)
+
+	_, err = tqs.queryService.GetDependencyGraphs(context.Background(), DependencyGraphQuery{
+		EndTs:    endTs,
+		Lookback: time.Hour,
+		Step:     time.Millisecond,
+	})
+	require.ErrorContains(t, err, "more than 1000 steps")
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// FlameGraphNode is a node of the operation call-tree obtained by merging the spans
+// of many traces that share the same call path.
+type FlameGraphNode struct {
+	OperationKey
+	// Count is the number of spans merged into the node.
+	Count int
+	// TotalTime is the sum of the durations of the merged spans.
+	TotalTime time.Duration
+	// SelfTime is the part of TotalTime not covered by any child span.
+	SelfTime time.Duration
+	Children []*FlameGraphNode
+}
+
+func (n *FlameGraphNode) child(key OperationKey) *FlameGraphNode {
+	for _, c := range n.Children {
+		if c.OperationKey == key {
+			return c
+		}
+	}
+	c := &FlameGraphNode{OperationKey: key}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+func (n *FlameGraphNode) sort() {
+	sort.Slice(n.Children, func(i, j int) bool {
+		if n.Children[i].TotalTime != n.Children[j].TotalTime {
+			return n.Children[i].TotalTime > n.Children[j].TotalTime
+		}
+		return n.Children[i].String() < n.Children[j].String()
+	})
+	for _, c := range n.Children {
+		c.sort()
+	}
+}
+
+// GetFlameGraph finds the traces matching the query and merges them into a flame graph.
+// The traces are searched like in FindTraces, so the tenant is required and the tags are masked.
+func (qs QueryService) GetFlameGraph(ctx context.Context, query *spanstore.TraceQueryParameters) (*FlameGraphNode, error) {
+	traces, err := qs.FindTraces(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for i, trace := range traces {
+		// adjuster errors are only warnings, the adjusted trace is still usable
+		traces[i], _ = qs.Adjust(trace)
+	}
+	return BuildFlameGraph(traces), nil
+}
+
+// BuildFlameGraph merges the spans of the traces into a call-tree. The returned root node
+// has no operation and aggregates the root spans of all traces.
+func BuildFlameGraph(traces []*model.Trace) *FlameGraphNode {
+	root := &FlameGraphNode{}
+	for _, trace := range traces {
+		tree := newSpanTree(trace)
+		visited := make(map[*model.Span]bool, len(trace.Spans))
+		for _, span := range tree.roots {
+			addToFlameGraph(root, tree, span, visited)
+		}
+	}
+	for _, c := range root.Children {
+		root.Count += c.Count
+		root.TotalTime += c.TotalTime
+	}
+	root.sort()
+	return root
+}
+
+func addToFlameGraph(parent *FlameGraphNode, tree *spanTree, span *model.Span, visited map[*model.Span]bool) {
+	// duplicate span IDs in traces that were not adjusted can make the tree cyclic
+	if visited[span] {
+		return
+	}
+	visited[span] = true
+	node := parent.child(operationKeyOf(span))
+	node.Count++
+	node.TotalTime += span.Duration
+	node.SelfTime += selfTime(span, tree.children[span.SpanID])
+	for _, child := range tree.children[span.SpanID] {
+		addToFlameGraph(node, tree, child, visited)
+	}
+}
+
+// selfTime returns the duration of the span that is not covered by any of its children.
+func selfTime(span *model.Span, children []*model.Span) time.Duration {
+	start, end := span.StartTime, span.StartTime.Add(span.Duration)
+	intervals := make([][2]time.Time, 0, len(children))
+	for _, child := range children {
+		childStart, childEnd := child.StartTime, child.StartTime.Add(child.Duration)
+		if childStart.Before(start) {
+			childStart = start
+		}
+		if childEnd.After(end) {
+			childEnd = end
+		}
+		if childStart.Before(childEnd) {
+			intervals = append(intervals, [2]time.Time{childStart, childEnd})
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i][0].Before(intervals[j][0]) })
+	self := span.Duration
+	var coveredUntil time.Time
+	for _, interval := range intervals {
+		if interval[0].Before(coveredUntil) {
+			interval[0] = coveredUntil
+		}
+		if interval[0].Before(interval[1]) {
+			self -= interval[1].Sub(interval[0])
+			coveredUntil = interval[1]
+		}
+	}
+	return self
+}
+
+// spanTree indexes the parent/child relationships of the spans of a trace.
+type spanTree struct {
+	spans    map[model.SpanID]*model.Span
+	children map[model.SpanID][]*model.Span
+	roots    []*model.Span
+}
+
+func newSpanTree(trace *model.Trace) *spanTree {
+	tree := &spanTree{
+		spans:    make(map[model.SpanID]*model.Span, len(trace.Spans)),
+		children: make(map[model.SpanID][]*model.Span),
+	}
+	for _, span := range trace.Spans {
+		tree.spans[span.SpanID] = span
+	}
+	for _, span := range trace.Spans {
+		parentID := span.ParentSpanID()
+		if _, ok := tree.spans[parentID]; ok && parentID != span.SpanID {
+			tree.children[parentID] = append(tree.children[parentID], span)
+		} else {
+			tree.roots = append(tree.roots, span)
+		}
+	}
+	return tree
+}
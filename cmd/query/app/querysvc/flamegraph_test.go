@@ -0,0 +1,94 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var traceStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func timedSpan(spanID, parentID uint64, operation string, start, end time.Duration) *model.Span {
+	span := comparisonSpan(spanID, parentID, "svc", operation, end-start)
+	span.StartTime = traceStart.Add(start)
+	return span
+}
+
+// newTimedTrace returns a trace with the following timeline, in milliseconds:
+//
+//	A [0..100]
+//	├── B [10..40]
+//	└── C [30..90]
+//	    └── D [50..60]
+func newTimedTrace() *model.Trace {
+	return &model.Trace{Spans: []*model.Span{
+		timedSpan(1, 0, "A", 0, 100*time.Millisecond),
+		timedSpan(2, 1, "B", 10*time.Millisecond, 40*time.Millisecond),
+		timedSpan(3, 1, "C", 30*time.Millisecond, 90*time.Millisecond),
+		timedSpan(4, 3, "D", 50*time.Millisecond, 60*time.Millisecond),
+	}}
+}
+
+func TestBuildFlameGraph(t *testing.T) {
+	key := func(op string) OperationKey { return OperationKey{Service: "svc", Operation: op} }
+	root := BuildFlameGraph([]*model.Trace{newTimedTrace(), newTimedTrace()})
+	assert.Equal(t, &FlameGraphNode{
+		Count:     2,
+		TotalTime: 200 * time.Millisecond,
+		Children: []*FlameGraphNode{
+			{
+				OperationKey: key("A"),
+				Count:        2,
+				TotalTime:    200 * time.Millisecond,
+				SelfTime:     40 * time.Millisecond,
+				Children: []*FlameGraphNode{
+					{
+						OperationKey: key("C"),
+						Count:        2,
+						TotalTime:    120 * time.Millisecond,
+						SelfTime:     100 * time.Millisecond,
+						Children: []*FlameGraphNode{
+							{OperationKey: key("D"), Count: 2, TotalTime: 20 * time.Millisecond, SelfTime: 20 * time.Millisecond},
+						},
+					},
+					{OperationKey: key("B"), Count: 2, TotalTime: 60 * time.Millisecond, SelfTime: 60 * time.Millisecond},
+				},
+			},
+		},
+	}, root)
+}
+
+func TestBuildFlameGraphDuplicateSpanIDs(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		timedSpan(1, 0, "A", 0, time.Millisecond),
+		timedSpan(1, 2, "B", 0, time.Millisecond),
+		timedSpan(2, 1, "C", 0, time.Millisecond),
+	}}
+	root := BuildFlameGraph([]*model.Trace{trace})
+	assert.Equal(t, 1, root.Count)
+}
+
+func TestGetFlameGraph(t *testing.T) {
+	tqs := initializeTestService()
+	query := &spanstore.TraceQueryParameters{ServiceName: "svc"}
+	tqs.spanReader.On("FindTraces", mock.Anything, query).Return([]*model.Trace{newTimedTrace()}, nil).Once()
+	tqs.spanReader.On("FindTraces", mock.Anything, query).Return(nil, assert.AnError).Once()
+
+	root, err := tqs.queryService.GetFlameGraph(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, root.Count)
+	assert.Equal(t, 100*time.Millisecond, root.TotalTime)
+
+	_, err = tqs.queryService.GetFlameGraph(context.Background(), query)
+	require.ErrorIs(t, err, assert.AnError)
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// GetTraceOTLP returns the trace in the OTLP format. If the storage implements spanstore.OTLPReader,
+// the trace is returned as read from the storage, without a round trip through the Jaeger model.
+// Like GetTrace, it falls back to the archive storage if the trace is not found.
+func (qs QueryService) GetTraceOTLP(ctx context.Context, traceID model.TraceID) (ptrace.Traces, error) {
+	if !qs.readsOTLP() {
+		trace, err := qs.GetTrace(ctx, traceID)
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		return spanstore.TraceToOTLP(trace)
+	}
+	if err := qs.checkTenant(ctx); err != nil {
+		return ptrace.Traces{}, err
+	}
+	td, err := spanstore.GetTraceOTLP(ctx, qs.spanReader, traceID)
+	if errors.Is(err, spanstore.ErrTraceNotFound) && qs.options.ArchiveSpanReader != nil {
+		td, err = spanstore.GetTraceOTLP(ctx, qs.options.ArchiveSpanReader, traceID)
+	}
+	return td, err
+}
+
+// StreamTraceOTLP passes the trace to yield in the OTLP format, in chunks of at most batchSize spans.
+// The trace is read with GetTraceOTLP if the storage implements spanstore.OTLPReader, otherwise
+// it is streamed with StreamTrace and each batch of spans is converted.
+func (qs QueryService) StreamTraceOTLP(ctx context.Context, traceID model.TraceID, batchSize int, yield func(ptrace.Traces) error) error {
+	if !qs.readsOTLP() {
+		return qs.StreamTrace(ctx, traceID, batchSize, func(spans []*model.Span) error {
+			td, err := spanstore.TraceToOTLP(&model.Trace{Spans: spans})
+			if err != nil {
+				return err
+			}
+			return yield(td)
+		})
+	}
+	td, err := qs.GetTraceOTLP(ctx, traceID)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range splitTraces(td, batchSize) {
+		if err := yield(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindTracesOTLP returns the traces matching the query in the OTLP format, one element per trace.
+// If the storage implements spanstore.OTLPReader, the traces are returned as read from the storage.
+func (qs QueryService) FindTracesOTLP(ctx context.Context, query *spanstore.TraceQueryParameters) ([]ptrace.Traces, error) {
+	if !qs.readsOTLP() {
+		traces, err := qs.FindTraces(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return spanstore.TracesToOTLP(traces)
+	}
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	return spanstore.FindTracesOTLP(ctx, qs.spanReader, query)
+}
+
+// readsOTLP returns whether the traces can be read in the OTLP format from the storage.
+// The tag masking and the cache operate on the Jaeger model, so they require the conversion.
+func (qs QueryService) readsOTLP() bool {
+	return spanstore.ReadsOTLP(qs.spanReader) && qs.options.TagMasker == nil && qs.options.Cache == nil
+}
+
+// splitTraces splits the traces into chunks of at most maxSpans spans, copying the resources
+// and the scopes of the spans into each chunk. The traces are not split if maxSpans is not positive.
+func splitTraces(td ptrace.Traces, maxSpans int) []ptrace.Traces {
+	if maxSpans <= 0 || td.SpanCount() <= maxSpans {
+		return []ptrace.Traces{td}
+	}
+	var chunks []ptrace.Traces
+	var chunk ptrace.Traces
+	var rs ptrace.ResourceSpans
+	var ss ptrace.ScopeSpans
+	// the indexes of the resource and the scope of the last span copied to the chunk
+	lastResource, lastScope := -1, -1
+	count := maxSpans
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		sss := rss.At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if count == maxSpans {
+					chunk = ptrace.NewTraces()
+					chunks = append(chunks, chunk)
+					lastResource, lastScope = -1, -1
+					count = 0
+				}
+				if lastResource != i {
+					rs = chunk.ResourceSpans().AppendEmpty()
+					rss.At(i).Resource().CopyTo(rs.Resource())
+					rs.SetSchemaUrl(rss.At(i).SchemaUrl())
+					lastResource, lastScope = i, -1
+				}
+				if lastScope != j {
+					ss = rs.ScopeSpans().AppendEmpty()
+					sss.At(j).Scope().CopyTo(ss.Scope())
+					ss.SetSchemaUrl(sss.At(j).SchemaUrl())
+					lastScope = j
+				}
+				spans.At(k).CopyTo(ss.Spans().AppendEmpty())
+				count++
+			}
+		}
+	}
+	return chunks
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+// otlpReader is a span reader returning the traces in the OTLP format.
+type otlpReader struct {
+	*spanstoremocks.Reader
+	td  ptrace.Traces
+	err error
+}
+
+func (r otlpReader) GetTraceOTLP(context.Context, model.TraceID) (ptrace.Traces, error) {
+	return r.td, r.err
+}
+
+func (r otlpReader) FindTracesOTLP(context.Context, *spanstore.TraceQueryParameters) ([]ptrace.Traces, error) {
+	return []ptrace.Traces{r.td}, r.err
+}
+
+func newOTLPTrace(resources, scopes, spans int) ptrace.Traces {
+	td := ptrace.NewTraces()
+	for i := 0; i < resources; i++ {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutInt("resource", int64(i))
+		for j := 0; j < scopes; j++ {
+			ss := rs.ScopeSpans().AppendEmpty()
+			ss.Scope().SetName("scope")
+			for k := 0; k < spans; k++ {
+				ss.Spans().AppendEmpty().SetName("span")
+			}
+		}
+	}
+	return td
+}
+
+func TestGetTraceOTLPNative(t *testing.T) {
+	td := newOTLPTrace(1, 1, 2)
+	qs := NewQueryService(otlpReader{td: td}, &depsmocks.Reader{}, QueryServiceOptions{})
+	res, err := qs.GetTraceOTLP(context.Background(), mockTraceID)
+	require.NoError(t, err)
+	assert.Equal(t, td, res)
+
+	traces, err := qs.FindTracesOTLP(context.Background(), &spanstore.TraceQueryParameters{})
+	require.NoError(t, err)
+	assert.Equal(t, []ptrace.Traces{td}, traces)
+}
+
+func TestGetTraceOTLPFromArchiveStorage(t *testing.T) {
+	archiveReader := &spanstoremocks.Reader{}
+	archiveReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+	qs := NewQueryService(otlpReader{err: spanstore.ErrTraceNotFound}, &depsmocks.Reader{}, QueryServiceOptions{
+		ArchiveSpanReader: archiveReader,
+	})
+	td, err := qs.GetTraceOTLP(context.Background(), mockTraceID)
+	require.NoError(t, err)
+	assert.Equal(t, len(mockTrace.Spans), td.SpanCount())
+}
+
+func TestGetTraceOTLPConverted(t *testing.T) {
+	tqs := initializeTestService()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+	tqs.spanReader.On("FindTraces", mock.Anything, mock.Anything).Return([]*model.Trace{mockTrace}, nil).Once()
+
+	td, err := tqs.queryService.GetTraceOTLP(context.Background(), mockTraceID)
+	require.NoError(t, err)
+	assert.Equal(t, len(mockTrace.Spans), td.SpanCount())
+
+	traces, err := tqs.queryService.FindTracesOTLP(context.Background(), &spanstore.TraceQueryParameters{})
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, len(mockTrace.Spans), traces[0].SpanCount())
+}
+
+func TestGetTraceOTLPWithTagMasker(t *testing.T) {
+	reader := otlpReader{Reader: &spanstoremocks.Reader{}}
+	reader.Reader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+	qs := NewQueryService(reader, &depsmocks.Reader{}, QueryServiceOptions{TagMasker: &TagMasker{}})
+	// the trace is read in the Jaeger model to be masked
+	td, err := qs.GetTraceOTLP(context.Background(), mockTraceID)
+	require.NoError(t, err)
+	assert.Equal(t, len(mockTrace.Spans), td.SpanCount())
+}
+
+func TestStreamTraceOTLP(t *testing.T) {
+	collect := func(qs *QueryService) []int {
+		var sizes []int
+		err := qs.StreamTraceOTLP(context.Background(), mockTraceID, 3, func(td ptrace.Traces) error {
+			sizes = append(sizes, td.SpanCount())
+			return nil
+		})
+		require.NoError(t, err)
+		return sizes
+	}
+	native := NewQueryService(otlpReader{td: newOTLPTrace(2, 2, 2)}, &depsmocks.Reader{}, QueryServiceOptions{})
+	assert.Equal(t, []int{3, 3, 2}, collect(native))
+
+	tqs := initializeTestService()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+	assert.Equal(t, []int{2}, collect(tqs.queryService))
+}
+
+func TestStreamTraceOTLPErrors(t *testing.T) {
+	qs := NewQueryService(otlpReader{err: spanstore.ErrTraceNotFound}, &depsmocks.Reader{}, QueryServiceOptions{})
+	err := qs.StreamTraceOTLP(context.Background(), mockTraceID, 1, nil)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+	qs = NewQueryService(otlpReader{td: newOTLPTrace(1, 1, 2)}, &depsmocks.Reader{}, QueryServiceOptions{})
+	calls := 0
+	err = qs.StreamTraceOTLP(context.Background(), mockTraceID, 1, func(ptrace.Traces) error {
+		calls++
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, calls)
+}
+
+func TestSplitTraces(t *testing.T) {
+	td := newOTLPTrace(2, 2, 2)
+	assert.Equal(t, []ptrace.Traces{td}, splitTraces(td, 0))
+	assert.Equal(t, []ptrace.Traces{td}, splitTraces(td, 8))
+
+	chunks := splitTraces(td, 3)
+	require.Len(t, chunks, 3)
+	// the first chunk holds the first scope of the first resource, and a span of the second scope
+	first := chunks[0].ResourceSpans()
+	require.Equal(t, 1, first.Len())
+	require.Equal(t, 2, first.At(0).ScopeSpans().Len())
+	assert.Equal(t, 2, first.At(0).ScopeSpans().At(0).Spans().Len())
+	assert.Equal(t, 1, first.At(0).ScopeSpans().At(1).Spans().Len())
+	assert.Equal(t, "scope", first.At(0).ScopeSpans().At(1).Scope().Name())
+	// the second chunk holds spans of both resources
+	second := chunks[1].ResourceSpans()
+	require.Equal(t, 2, second.Len())
+	assert.Equal(t, map[string]any{"resource": int64(0)}, second.At(0).Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"resource": int64(1)}, second.At(1).Resource().Attributes().AsRaw())
+	assert.Equal(t, 2, chunks[2].SpanCount())
+
+	// the empty scopes are skipped
+	td = newOTLPTrace(1, 1, 2)
+	td.ResourceSpans().At(0).ScopeSpans().AppendEmpty()
+	td.ResourceSpans().At(0).ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	chunks = splitTraces(td, 1)
+	require.Len(t, chunks, 3)
+	for _, chunk := range chunks {
+		assert.Equal(t, 1, chunk.ResourceSpans().At(0).ScopeSpans().Len())
+	}
+}
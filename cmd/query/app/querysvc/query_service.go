@@ -17,14 +17,17 @@ package querysvc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -39,11 +42,20 @@ type QueryServiceOptions struct {
 	ArchiveSpanReader spanstore.Reader
 	ArchiveSpanWriter spanstore.Writer
 	Adjuster          adjuster.Adjuster
+	SavedSearchStore  savedsearchstore.Store
+	// TagMasker, if set, masks or removes tags from the traces returned by the query service.
+	TagMasker *TagMasker
+	// Tenancy, if enabled, rejects reads whose context does not carry a valid tenant.
+	Tenancy *tenancy.Manager
+	// Cache, if set, caches the traces, services and operations read from the storage.
+	Cache *ResponseCache
 }
 
 // StorageCapabilities is a feature flag for query service
 type StorageCapabilities struct {
 	ArchiveStorage bool `json:"archiveStorage"`
+	// TagSuggestions is true if the span storage can list the keys and values of the tags.
+	TagSuggestions bool `json:"tagSuggestions"`
 	// SupportRegex     bool
 	// SupportTagFilter bool
 }
@@ -64,13 +76,57 @@ func NewQueryService(spanReader spanstore.Reader, dependencyReader dependencysto
 	}
 
 	if qsvc.options.Adjuster == nil {
-		qsvc.options.Adjuster = adjuster.Sequence(StandardAdjusters(defaultMaxClockSkewAdjust)...)
+		qsvc.options.Adjuster = adjuster.Sequence(StandardAdjusters(adjuster.ClockSkewOptions{MaxDelta: defaultMaxClockSkewAdjust})...)
 	}
 	return qsvc
 }
 
 // GetTrace is the queryService implementation of spanstore.Reader.GetTrace
 func (qs QueryService) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	trace, err := qs.getTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	return qs.maskTrace(ctx, trace), nil
+}
+
+// GetTraceInTimeRange is the queryService implementation of spanstore.TimeRangeReader.GetTraceInTimeRange.
+// The storage may omit the spans started outside of the time range, so the trace is not cached.
+func (qs QueryService) GetTraceInTimeRange(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	if query.StartTime.IsZero() && query.EndTime.IsZero() {
+		return qs.GetTrace(ctx, query.TraceID)
+	}
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	if qs.options.Cache != nil {
+		if trace := qs.options.Cache.getTrace(ctx, query.TraceID); trace != nil {
+			return qs.maskTrace(ctx, trace), nil
+		}
+	}
+	trace, err := spanstore.GetTraceInTimeRange(ctx, qs.spanReader, query)
+	if errors.Is(err, spanstore.ErrTraceNotFound) {
+		if qs.options.ArchiveSpanReader == nil {
+			return nil, err
+		}
+		trace, err = spanstore.GetTraceInTimeRange(ctx, qs.options.ArchiveSpanReader, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return qs.maskTrace(ctx, trace), nil
+}
+
+// getTrace returns the trace as stored, without masking its tags.
+func (qs QueryService) getTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	if qs.options.Cache != nil {
+		if trace := qs.options.Cache.getTrace(ctx, traceID); trace != nil {
+			return trace, nil
+		}
+	}
 	trace, err := qs.spanReader.GetTrace(ctx, traceID)
 	if errors.Is(err, spanstore.ErrTraceNotFound) {
 		if qs.options.ArchiveSpanReader == nil {
@@ -78,25 +134,156 @@ func (qs QueryService) GetTrace(ctx context.Context, traceID model.TraceID) (*mo
 		}
 		trace, err = qs.options.ArchiveSpanReader.GetTrace(ctx, traceID)
 	}
+	if err == nil && qs.options.Cache != nil {
+		qs.options.Cache.putTrace(ctx, traceID, trace)
+	}
 	return trace, err
 }
 
+// StreamTrace passes the spans of the trace to yield in batches of at most batchSize spans,
+// or as returned by the storage if it implements spanstore.StreamingReader. Like GetTrace,
+// it falls back to the archive storage if the trace is not found. The spans are not adjusted.
+func (qs QueryService) StreamTrace(ctx context.Context, traceID model.TraceID, batchSize int, yield func([]*model.Span) error) error {
+	if err := qs.checkTenant(ctx); err != nil {
+		return err
+	}
+	if qs.options.TagMasker != nil {
+		unmasked := yield
+		yield = func(spans []*model.Span) error {
+			return unmasked(qs.options.TagMasker.MaskSpans(ctx, spans))
+		}
+	}
+	err := spanstore.StreamTrace(ctx, qs.spanReader, traceID, batchSize, yield)
+	if errors.Is(err, spanstore.ErrTraceNotFound) && qs.options.ArchiveSpanReader != nil {
+		err = spanstore.StreamTrace(ctx, qs.options.ArchiveSpanReader, traceID, batchSize, yield)
+	}
+	return err
+}
+
 // GetServices is the queryService implementation of spanstore.Reader.GetServices
 func (qs QueryService) GetServices(ctx context.Context) ([]string, error) {
-	return qs.spanReader.GetServices(ctx)
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	if qs.options.Cache == nil {
+		return qs.spanReader.GetServices(ctx)
+	}
+	if services := qs.options.Cache.getServices(ctx); services != nil {
+		return services, nil
+	}
+	services, err := qs.spanReader.GetServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	qs.options.Cache.putServices(ctx, services)
+	return services, nil
 }
 
-// GetOperations is the queryService implementation of spanstore.Reader.GetOperations
+// GetOperations returns the operations of the service selected by the query, see spanstore.FindOperations.
 func (qs QueryService) GetOperations(
 	ctx context.Context,
 	query spanstore.OperationQueryParameters,
 ) ([]spanstore.Operation, error) {
-	return qs.spanReader.GetOperations(ctx, query)
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	if qs.options.Cache == nil {
+		return spanstore.FindOperations(ctx, qs.spanReader, query)
+	}
+	if operations := qs.options.Cache.getOperations(ctx, query); operations != nil {
+		return operations, nil
+	}
+	operations, err := spanstore.FindOperations(ctx, qs.spanReader, query)
+	if err != nil {
+		return nil, err
+	}
+	qs.options.Cache.putOperations(ctx, query, operations)
+	return operations, nil
 }
 
 // FindTraces is the queryService implementation of spanstore.Reader.FindTraces
 func (qs QueryService) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
-	return qs.spanReader.FindTraces(ctx, query)
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	traces, err := qs.spanReader.FindTraces(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return qs.maskTraces(ctx, traces), nil
+}
+
+// FindTraceIDs is the queryService implementation of spanstore.Reader.FindTraceIDs
+func (qs QueryService) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	return qs.spanReader.FindTraceIDs(ctx, query)
+}
+
+// FindArchivedTraces searches the traces of the archive storage.
+func (qs QueryService) FindArchivedTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	if qs.options.ArchiveSpanReader == nil {
+		return nil, errNoArchiveSpanStorage
+	}
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	traces, err := qs.options.ArchiveSpanReader.FindTraces(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return qs.maskTraces(ctx, traces), nil
+}
+
+// FindTracesPage returns a single page of traces matching the query. Backends that do not
+// implement spanstore.PaginatedReader are paginated by trace start time.
+func (qs QueryService) FindTracesPage(ctx context.Context, query *spanstore.TraceQueryParameters) (*spanstore.TracesPage, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	page, err := spanstore.FindTracesPage(ctx, qs.spanReader, query)
+	if err != nil {
+		return nil, err
+	}
+	page.Traces = qs.maskTraces(ctx, page.Traces)
+	return page, nil
+}
+
+// GetLatencyDistribution returns the duration histogram and percentiles of the spans of a service
+// or operation. Backends that do not implement spanstore.LatencyReader compute it from a sample
+// of the most recent traces, which is considerably slower.
+func (qs QueryService) GetLatencyDistribution(ctx context.Context, query *spanstore.LatencyQueryParameters) (*spanstore.LatencyDistribution, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	return spanstore.GetLatencyDistribution(ctx, qs.spanReader, query)
+}
+
+// GetTagKeys returns the keys of the tags of the spans of a service, to suggest them in the search form.
+// It returns spanstore.ErrTagsNotSupported if the span storage cannot list them.
+func (qs QueryService) GetTagKeys(ctx context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	keys, err := spanstore.GetTagKeys(ctx, qs.spanReader, query)
+	if err != nil || qs.options.TagMasker == nil {
+		return keys, err
+	}
+	return qs.options.TagMasker.MaskTagKeys(ctx, keys), nil
+}
+
+// GetTagValues returns the values of the tags of the spans of a service with the key of the query.
+// It returns spanstore.ErrTagsNotSupported if the span storage cannot list them.
+func (qs QueryService) GetTagValues(ctx context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	values, err := spanstore.GetTagValues(ctx, qs.spanReader, query)
+	if err != nil || qs.options.TagMasker == nil {
+		return values, err
+	}
+	return qs.options.TagMasker.MaskTagValues(ctx, query.Key, values), nil
 }
 
 // ArchiveTrace is the queryService utility to archive traces.
@@ -104,7 +291,8 @@ func (qs QueryService) ArchiveTrace(ctx context.Context, traceID model.TraceID)
 	if qs.options.ArchiveSpanWriter == nil {
 		return errNoArchiveSpanStorage
 	}
-	trace, err := qs.GetTrace(ctx, traceID)
+	// the archived trace must keep all its tags, masking only applies to the responses
+	trace, err := qs.getTrace(ctx, traceID)
 	if err != nil {
 		return err
 	}
@@ -116,16 +304,59 @@ func (qs QueryService) ArchiveTrace(ctx context.Context, traceID model.TraceID)
 			writeErrors = append(writeErrors, err)
 		}
 	}
+	if qs.options.Cache != nil {
+		// the trace may be read from the archive storage from now on
+		qs.options.Cache.invalidateTrace(ctx, traceID)
+	}
 	return errors.Join(writeErrors...)
 }
 
+// ArchiveTraces archives the traces one by one, carrying on after failures,
+// and returns the IDs of the traces archived successfully.
+func (qs QueryService) ArchiveTraces(ctx context.Context, traceIDs []model.TraceID) ([]model.TraceID, error) {
+	if qs.options.ArchiveSpanWriter == nil {
+		return nil, errNoArchiveSpanStorage
+	}
+	archived := make([]model.TraceID, 0, len(traceIDs))
+	var errs []error
+	for _, traceID := range traceIDs {
+		if err := qs.ArchiveTrace(ctx, traceID); err != nil {
+			errs = append(errs, fmt.Errorf("cannot archive trace %v: %w", traceID, err))
+			continue
+		}
+		archived = append(archived, traceID)
+	}
+	return archived, errors.Join(errs...)
+}
+
 // Adjust applies adjusters to the trace.
 func (qs QueryService) Adjust(trace *model.Trace) (*model.Trace, error) {
 	return qs.options.Adjuster.Adjust(trace)
 }
 
+func (qs QueryService) maskTrace(ctx context.Context, trace *model.Trace) *model.Trace {
+	if qs.options.TagMasker == nil {
+		return trace
+	}
+	return qs.options.TagMasker.MaskTrace(ctx, trace)
+}
+
+func (qs QueryService) maskTraces(ctx context.Context, traces []*model.Trace) []*model.Trace {
+	if qs.options.TagMasker == nil {
+		return traces
+	}
+	masked := make([]*model.Trace, len(traces))
+	for i, trace := range traces {
+		masked[i] = qs.options.TagMasker.MaskTrace(ctx, trace)
+	}
+	return masked
+}
+
 // GetDependencies implements dependencystore.Reader.GetDependencies
 func (qs QueryService) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
 	return qs.dependencyReader.GetDependencies(ctx, endTs, lookback)
 }
 
@@ -133,6 +364,7 @@ func (qs QueryService) GetDependencies(ctx context.Context, endTs time.Time, loo
 func (qs QueryService) GetCapabilities() StorageCapabilities {
 	return StorageCapabilities{
 		ArchiveStorage: qs.options.hasArchiveStorage(),
+		TagSuggestions: spanstore.SupportsTags(qs.spanReader),
 	}
 }
 
@@ -155,6 +155,32 @@ func TestGetTraceFromArchiveStorage(t *testing.T) {
 	assert.Equal(t, res, mockTrace)
 }
 
+func TestGetTraceInTimeRange(t *testing.T) {
+	tqs := initializeTestService(withArchiveSpanReader())
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+	tqs.archiveSpanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+	query := spanstore.GetTraceParameters{TraceID: mockTraceID, StartTime: time.Unix(1000, 0)}
+
+	res, err := tqs.queryService.GetTraceInTimeRange(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, mockTrace, res)
+	res, err = tqs.queryService.GetTraceInTimeRange(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, mockTrace, res)
+}
+
+func TestGetTraceInTimeRangeNotFound(t *testing.T) {
+	tqs := initializeTestService()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+
+	_, err := tqs.queryService.GetTraceInTimeRange(context.Background(), spanstore.GetTraceParameters{
+		TraceID: mockTraceID,
+		EndTime: time.Unix(1000, 0),
+	})
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+}
+
 // Test QueryService.GetServices() for success.
 func TestGetServices(t *testing.T) {
 	tqs := initializeTestService()
@@ -207,6 +233,36 @@ func TestFindTraces(t *testing.T) {
 	assert.Len(t, traces, 1)
 }
 
+// Test QueryService.FindTracesPage() on a reader without native pagination.
+func TestFindTracesPage(t *testing.T) {
+	tqs := initializeTestService()
+	tqs.spanReader.On("FindTraces", mock.Anything, mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{mockTrace}, nil).Once()
+
+	page, err := tqs.queryService.FindTracesPage(context.Background(), &spanstore.TraceQueryParameters{
+		ServiceName: "service",
+		NumTraces:   1,
+	})
+	require.NoError(t, err)
+	assert.Len(t, page.Traces, 1)
+	assert.Empty(t, page.NextPageToken)
+}
+
+// Test QueryService.StreamTrace() falling back to the archive storage.
+func TestStreamTraceFromArchiveStorage(t *testing.T) {
+	tqs := initializeTestService(withArchiveSpanReader())
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+	tqs.archiveSpanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+
+	var batches [][]*model.Span
+	err := tqs.queryService.StreamTrace(context.Background(), mockTraceID, 1, func(spans []*model.Span) error {
+		batches = append(batches, spans)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, batches, len(mockTrace.Spans))
+}
+
 // Test QueryService.ArchiveTrace() with no ArchiveSpanWriter.
 func TestArchiveTraceNoOptions(t *testing.T) {
 	tqs := initializeTestService()
@@ -260,6 +316,49 @@ func TestArchiveTraceSuccess(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// Test QueryService.ArchiveTraces() carrying on after a trace is not found.
+func TestArchiveTraces(t *testing.T) {
+	missingTraceID := model.NewTraceID(0, 1)
+	tqs := initializeTestService(withArchiveSpanWriter())
+	tqs.spanReader.On("GetTrace", mock.Anything, missingTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+	tqs.archiveSpanWriter.On("WriteSpan", mock.Anything, mock.AnythingOfType("*model.Span")).Return(nil).Times(2)
+
+	archived, err := tqs.queryService.ArchiveTraces(context.Background(), []model.TraceID{missingTraceID, mockTraceID})
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+	assert.Equal(t, []model.TraceID{mockTraceID}, archived)
+
+	_, err = initializeTestService().queryService.ArchiveTraces(context.Background(), []model.TraceID{mockTraceID})
+	assert.Equal(t, errNoArchiveSpanStorage, err)
+}
+
+// Test QueryService.FindArchivedTraces()
+func TestFindArchivedTraces(t *testing.T) {
+	query := &spanstore.TraceQueryParameters{ServiceName: "service", NumTraces: 10}
+	_, err := initializeTestService().queryService.FindArchivedTraces(context.Background(), query)
+	assert.Equal(t, errNoArchiveSpanStorage, err)
+
+	tqs := initializeTestService(withArchiveSpanReader())
+	tqs.archiveSpanReader.On("FindTraces", mock.Anything, query).Return([]*model.Trace{mockTrace}, nil).Once()
+	traces, err := tqs.queryService.FindArchivedTraces(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Trace{mockTrace}, traces)
+
+	tqs.archiveSpanReader.On("FindTraces", mock.Anything, query).Return(nil, assert.AnError).Once()
+	_, err = tqs.queryService.FindArchivedTraces(context.Background(), query)
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+// Test QueryService.FindTraceIDs()
+func TestFindTraceIDs(t *testing.T) {
+	tqs := initializeTestService()
+	query := &spanstore.TraceQueryParameters{ServiceName: "service", NumTraces: 10}
+	tqs.spanReader.On("FindTraceIDs", mock.Anything, query).Return([]model.TraceID{mockTraceID}, nil).Once()
+	traceIDs, err := tqs.queryService.FindTraceIDs(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, []model.TraceID{mockTraceID}, traceIDs)
+}
+
 // Test QueryService.Adjust()
 func TestTraceAdjustmentFailure(t *testing.T) {
 	tqs := initializeTestService(withAdjuster())
@@ -305,6 +404,40 @@ func TestGetCapabilitiesWithSupportsArchive(t *testing.T) {
 	assert.Equal(t, expectedStorageCapabilities, tqs.queryService.GetCapabilities())
 }
 
+type tagSpanReader struct {
+	*spanstoremocks.Reader
+}
+
+func (tagSpanReader) GetTagKeys(_ context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	return []string{query.ServiceName + ".key", "user.email"}, nil
+}
+
+func (tagSpanReader) GetTagValues(_ context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	return []string{query.Key + ".value"}, nil
+}
+
+func TestGetCapabilitiesWithTagSuggestions(t *testing.T) {
+	qs := NewQueryService(tagSpanReader{&spanstoremocks.Reader{}}, &depsmocks.Reader{}, QueryServiceOptions{})
+	assert.Equal(t, StorageCapabilities{TagSuggestions: true}, qs.GetCapabilities())
+}
+
+func TestGetTags(t *testing.T) {
+	query := &spanstore.TagQueryParameters{ServiceName: "svc", Key: "http.method"}
+	qs := NewQueryService(tagSpanReader{&spanstoremocks.Reader{}}, &depsmocks.Reader{}, QueryServiceOptions{})
+	keys, err := qs.GetTagKeys(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"svc.key", "user.email"}, keys)
+	values, err := qs.GetTagValues(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http.method.value"}, values)
+
+	tqs := initializeTestService()
+	_, err = tqs.queryService.GetTagKeys(context.Background(), query)
+	require.ErrorIs(t, err, spanstore.ErrTagsNotSupported)
+	_, err = tqs.queryService.GetTagValues(context.Background(), query)
+	require.ErrorIs(t, err, spanstore.ErrTagsNotSupported)
+}
+
 type fakeStorageFactory1 struct{}
 
 type fakeStorageFactory2 struct {
@@ -0,0 +1,24 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import "context"
+
+type roleKeyType struct{}
+
+var roleKey = roleKeyType{}
+
+// ContextWithRole sets the role of the user issuing the request, used to select tag masking rules.
+func ContextWithRole(ctx context.Context, role string) context.Context {
+	if role == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// GetRole returns the role of the user issuing the request, or empty string if it is not known.
+func GetRole(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey).(string)
+	return role
+}
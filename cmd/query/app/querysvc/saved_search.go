@@ -0,0 +1,107 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
+)
+
+// ErrNoSavedSearchStorage is returned when saved searches are used without a configured store.
+var ErrNoSavedSearchStorage = errors.New("saved search storage was not configured")
+
+// SaveSearch stores the search, assigning it a random ID and the creation time if they are not set.
+func (qs QueryService) SaveSearch(ctx context.Context, search *savedsearchstore.SavedSearch) (*savedsearchstore.SavedSearch, error) {
+	if qs.options.SavedSearchStore == nil {
+		return nil, ErrNoSavedSearchStorage
+	}
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	saved := *search
+	if saved.ID == "" {
+		id, err := newSavedSearchID()
+		if err != nil {
+			return nil, err
+		}
+		saved.ID = id
+	}
+	if saved.CreatedAt.IsZero() {
+		saved.CreatedAt = time.Now().UTC()
+	}
+	if err := qs.options.SavedSearchStore.SaveSearch(ctx, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// GetSavedSearch returns the saved search with the given ID.
+func (qs QueryService) GetSavedSearch(ctx context.Context, id string) (*savedsearchstore.SavedSearch, error) {
+	if qs.options.SavedSearchStore == nil {
+		return nil, ErrNoSavedSearchStorage
+	}
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	return qs.options.SavedSearchStore.GetSavedSearch(ctx, id)
+}
+
+// ListSavedSearches returns the saved searches of the tenant, optionally restricted to one owner.
+func (qs QueryService) ListSavedSearches(ctx context.Context, owner string) ([]*savedsearchstore.SavedSearch, error) {
+	if qs.options.SavedSearchStore == nil {
+		return nil, ErrNoSavedSearchStorage
+	}
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	return qs.options.SavedSearchStore.ListSavedSearches(ctx, owner)
+}
+
+// DeleteSavedSearch deletes the saved search with the given ID.
+func (qs QueryService) DeleteSavedSearch(ctx context.Context, id string) error {
+	if qs.options.SavedSearchStore == nil {
+		return ErrNoSavedSearchStorage
+	}
+	if err := qs.checkTenant(ctx); err != nil {
+		return err
+	}
+	return qs.options.SavedSearchStore.DeleteSavedSearch(ctx, id)
+}
+
+// InitSavedSearchStorage tries to initialize the saved search store if storage factory supports it.
+func (opts *QueryServiceOptions) InitSavedSearchStorage(storageFactory storage.Factory, logger *zap.Logger) bool {
+	ssFactory, ok := storageFactory.(storage.SavedSearchStoreFactory)
+	if !ok {
+		logger.Info("Saved search storage not supported by the factory")
+		return false
+	}
+	store, err := ssFactory.CreateSavedSearchStore()
+	if errors.Is(err, storage.ErrSavedSearchStorageNotSupported) {
+		logger.Info("Saved search storage not created", zap.String("reason", err.Error()))
+		return false
+	}
+	if err != nil {
+		logger.Error("Cannot init saved search storage", zap.Error(err))
+		return false
+	}
+	opts.SavedSearchStore = store
+	return true
+}
+
+func newSavedSearchID() (string, error) {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", fmt.Errorf("cannot generate saved search ID: %w", err)
+	}
+	return hex.EncodeToString(id[:]), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/mocks"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore/filestore"
+)
+
+type fakeSavedSearchFactory struct {
+	*mocks.Factory
+	store savedsearchstore.Store
+	err   error
+}
+
+func (f fakeSavedSearchFactory) CreateSavedSearchStore() (savedsearchstore.Store, error) {
+	return f.store, f.err
+}
+
+func withSavedSearchStore() testOption {
+	return func(_ *testQueryService, options *QueryServiceOptions) {
+		options.SavedSearchStore = filestore.NewStore()
+	}
+}
+
+func TestSavedSearches(t *testing.T) {
+	tqs := initializeTestService(withSavedSearchStore())
+	ctx := context.Background()
+
+	saved, err := tqs.queryService.SaveSearch(ctx, &savedsearchstore.SavedSearch{Name: "errors", Query: "service=frontend&tags=error"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, saved.ID)
+	assert.False(t, saved.CreatedAt.IsZero())
+
+	found, err := tqs.queryService.GetSavedSearch(ctx, saved.ID)
+	require.NoError(t, err)
+	assert.Equal(t, saved, found)
+
+	searches, err := tqs.queryService.ListSavedSearches(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []*savedsearchstore.SavedSearch{saved}, searches)
+
+	require.NoError(t, tqs.queryService.DeleteSavedSearch(ctx, saved.ID))
+	_, err = tqs.queryService.GetSavedSearch(ctx, saved.ID)
+	require.ErrorIs(t, err, savedsearchstore.ErrSavedSearchNotFound)
+}
+
+func TestSavedSearchesNotConfigured(t *testing.T) {
+	tqs := initializeTestService()
+	ctx := context.Background()
+
+	_, err := tqs.queryService.SaveSearch(ctx, &savedsearchstore.SavedSearch{Name: "errors"})
+	require.ErrorIs(t, err, ErrNoSavedSearchStorage)
+	_, err = tqs.queryService.GetSavedSearch(ctx, "id")
+	require.ErrorIs(t, err, ErrNoSavedSearchStorage)
+	_, err = tqs.queryService.ListSavedSearches(ctx, "")
+	require.ErrorIs(t, err, ErrNoSavedSearchStorage)
+	require.ErrorIs(t, tqs.queryService.DeleteSavedSearch(ctx, "id"), ErrNoSavedSearchStorage)
+}
+
+func TestInitSavedSearchStorage(t *testing.T) {
+	opts := &QueryServiceOptions{}
+	assert.False(t, opts.InitSavedSearchStorage(&mocks.Factory{}, zap.NewNop()))
+
+	factory := fakeSavedSearchFactory{err: storage.ErrSavedSearchStorageNotSupported}
+	assert.False(t, opts.InitSavedSearchStorage(factory, zap.NewNop()))
+
+	factory = fakeSavedSearchFactory{err: errors.New("cannot create store")}
+	assert.False(t, opts.InitSavedSearchStorage(factory, zap.NewNop()))
+	assert.Nil(t, opts.SavedSearchStore)
+
+	store := filestore.NewStore()
+	factory = fakeSavedSearchFactory{store: store}
+	assert.True(t, opts.InitSavedSearchStorage(factory, zap.NewNop()))
+	assert.Equal(t, store, opts.SavedSearchStore)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+// MaskedTagValue replaces the value of masked tags.
+const MaskedTagValue = "***"
+
+// TagMaskingAction defines what happens to the tags matched by a TagMaskingRule.
+type TagMaskingAction string
+
+const (
+	// TagMaskingActionMask replaces the tag value with MaskedTagValue.
+	TagMaskingActionMask TagMaskingAction = "mask"
+	// TagMaskingActionRemove removes the tag.
+	TagMaskingActionRemove TagMaskingAction = "remove"
+)
+
+// TagMaskingRule masks or removes tags with the given keys from the spans returned to
+// the matching requests. Empty Tenants or Roles match all tenants or roles respectively.
+type TagMaskingRule struct {
+	Tenants []string `json:"tenants"`
+	Roles   []string `json:"roles"`
+	// ExceptRoles lists the roles the rule does not apply to, e.g. administrators.
+	ExceptRoles []string         `json:"exceptRoles"`
+	Keys        []string         `json:"keys"`
+	Action      TagMaskingAction `json:"action"`
+}
+
+// TagMaskingConfig is the content of the tag masking configuration file.
+type TagMaskingConfig struct {
+	Rules []TagMaskingRule `json:"rules"`
+}
+
+// LoadTagMaskingConfig reads and validates the tag masking configuration from a JSON file.
+func LoadTagMaskingConfig(file string) (*TagMaskingConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read tag masking config: %w", err)
+	}
+	cfg := &TagMaskingConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("cannot parse tag masking config %s: %w", file, err)
+	}
+	for i, rule := range cfg.Rules {
+		if len(rule.Keys) == 0 {
+			return nil, fmt.Errorf("tag masking rule %d has no keys", i)
+		}
+		switch rule.Action {
+		case TagMaskingActionMask, TagMaskingActionRemove:
+		case "":
+			cfg.Rules[i].Action = TagMaskingActionMask
+		default:
+			return nil, fmt.Errorf("tag masking rule %d has unknown action %q", i, rule.Action)
+		}
+	}
+	return cfg, nil
+}
+
+// TagMasker applies tag masking rules to the traces returned by the query service,
+// based on the tenant and role of the request.
+type TagMasker struct {
+	rules []TagMaskingRule
+}
+
+// NewTagMasker creates a TagMasker from the configuration.
+func NewTagMasker(cfg TagMaskingConfig) *TagMasker {
+	return &TagMasker{rules: cfg.Rules}
+}
+
+// actions returns the masking action of every tag key for the request, or nil if no rule applies.
+func (m *TagMasker) actions(ctx context.Context) map[string]TagMaskingAction {
+	tenant := tenancy.GetTenant(ctx)
+	role := GetRole(ctx)
+	var actions map[string]TagMaskingAction
+	for _, rule := range m.rules {
+		if !matches(rule.Tenants, tenant) || !matches(rule.Roles, role) ||
+			(role != "" && contains(rule.ExceptRoles, role)) {
+			continue
+		}
+		if actions == nil {
+			actions = make(map[string]TagMaskingAction)
+		}
+		for _, key := range rule.Keys {
+			// removing takes precedence over masking when several rules apply
+			if actions[key] != TagMaskingActionRemove {
+				actions[key] = rule.Action
+			}
+		}
+	}
+	return actions
+}
+
+// MaskTrace returns the trace with the tags masked according to the rules applying to the request.
+// The original trace is not modified.
+func (m *TagMasker) MaskTrace(ctx context.Context, trace *model.Trace) *model.Trace {
+	actions := m.actions(ctx)
+	if actions == nil || trace == nil {
+		return trace
+	}
+	masked := *trace
+	masked.Spans = maskSpans(actions, trace.Spans)
+	if trace.ProcessMap != nil {
+		masked.ProcessMap = make([]model.Trace_ProcessMapping, len(trace.ProcessMap))
+		for i, mapping := range trace.ProcessMap {
+			mapping.Process.Tags = maskTags(actions, mapping.Process.Tags)
+			masked.ProcessMap[i] = mapping
+		}
+	}
+	return &masked
+}
+
+// MaskSpans returns copies of the spans with the tags masked according to the rules applying to the request.
+func (m *TagMasker) MaskSpans(ctx context.Context, spans []*model.Span) []*model.Span {
+	actions := m.actions(ctx)
+	if actions == nil {
+		return spans
+	}
+	return maskSpans(actions, spans)
+}
+
+// MaskTagKeys returns the tag keys suggested to the request without the keys of the removed tags.
+func (m *TagMasker) MaskTagKeys(ctx context.Context, keys []string) []string {
+	actions := m.actions(ctx)
+	if actions == nil {
+		return keys
+	}
+	masked := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if actions[key] != TagMaskingActionRemove {
+			masked = append(masked, key)
+		}
+	}
+	return masked
+}
+
+// MaskTagValues returns the values of the tag with the key suggested to the request:
+// none if the tag is removed, and MaskedTagValue alone if it is masked.
+func (m *TagMasker) MaskTagValues(ctx context.Context, key string, values []string) []string {
+	switch m.actions(ctx)[key] {
+	case TagMaskingActionRemove:
+		return []string{}
+	case TagMaskingActionMask:
+		if len(values) == 0 {
+			return values
+		}
+		return []string{MaskedTagValue}
+	default:
+		return values
+	}
+}
+
+func maskSpans(actions map[string]TagMaskingAction, spans []*model.Span) []*model.Span {
+	masked := make([]*model.Span, len(spans))
+	for i, span := range spans {
+		maskedSpan := *span
+		maskedSpan.Tags = maskTags(actions, span.Tags)
+		if span.Process != nil {
+			process := *span.Process
+			process.Tags = maskTags(actions, span.Process.Tags)
+			maskedSpan.Process = &process
+		}
+		if span.Logs != nil {
+			maskedSpan.Logs = make([]model.Log, len(span.Logs))
+			for j, log := range span.Logs {
+				log.Fields = maskTags(actions, log.Fields)
+				maskedSpan.Logs[j] = log
+			}
+		}
+		masked[i] = &maskedSpan
+	}
+	return masked
+}
+
+func maskTags(actions map[string]TagMaskingAction, tags []model.KeyValue) []model.KeyValue {
+	if tags == nil {
+		return nil
+	}
+	masked := make([]model.KeyValue, 0, len(tags))
+	for _, tag := range tags {
+		switch actions[tag.Key] {
+		case TagMaskingActionRemove:
+			continue
+		case TagMaskingActionMask:
+			masked = append(masked, model.String(tag.Key, MaskedTagValue))
+		default:
+			masked = append(masked, tag)
+		}
+	}
+	return masked
+}
+
+// matches returns true if values is empty or contains value.
+func matches(values []string, value string) bool {
+	return len(values) == 0 || contains(values, value)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
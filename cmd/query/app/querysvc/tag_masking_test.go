@@ -0,0 +1,210 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func newTagMaskingTrace() *model.Trace {
+	return &model.Trace{
+		Spans: []*model.Span{
+			{
+				TraceID: mockTraceID,
+				SpanID:  model.NewSpanID(1),
+				Tags: model.KeyValues{
+					model.String("http.url", "/login?user=alice"),
+					model.String("user.email", "alice@example.com"),
+					model.Int64("http.status_code", 200),
+				},
+				Logs: []model.Log{
+					{Fields: model.KeyValues{model.String("user.email", "alice@example.com")}},
+				},
+				Process: model.NewProcess("frontend", []model.KeyValue{model.String("host.ip", "10.0.0.1")}),
+			},
+		},
+		ProcessMap: []model.Trace_ProcessMapping{
+			{ProcessID: "p1", Process: *model.NewProcess("frontend", []model.KeyValue{model.String("host.ip", "10.0.0.1")})},
+		},
+	}
+}
+
+func newTestTagMasker() *TagMasker {
+	return NewTagMasker(TagMaskingConfig{
+		Rules: []TagMaskingRule{
+			{Keys: []string{"user.email", "host.ip"}, Action: TagMaskingActionRemove, ExceptRoles: []string{"admin"}},
+			{Tenants: []string{"acme"}, Roles: []string{"support"}, Keys: []string{"http.url", "user.email"}, Action: TagMaskingActionMask},
+		},
+	})
+}
+
+func TestTagMaskerMaskTrace(t *testing.T) {
+	masker := newTestTagMasker()
+	trace := newTagMaskingTrace()
+
+	masked := masker.MaskTrace(context.Background(), trace)
+	span := masked.Spans[0]
+	assert.Equal(t, []model.KeyValue{
+		model.String("http.url", "/login?user=alice"),
+		model.Int64("http.status_code", 200),
+	}, span.Tags)
+	assert.Empty(t, span.Logs[0].Fields)
+	assert.Empty(t, span.Process.Tags)
+	assert.Empty(t, masked.ProcessMap[0].Process.Tags)
+	// the stored trace is not modified
+	assert.Equal(t, newTagMaskingTrace(), trace)
+
+	ctx := ContextWithRole(tenancy.WithTenant(context.Background(), "acme"), "support")
+	span = masker.MaskTrace(ctx, trace).Spans[0]
+	assert.Equal(t, []model.KeyValue{
+		model.String("http.url", MaskedTagValue),
+		model.Int64("http.status_code", 200),
+	}, span.Tags, "removing takes precedence over masking")
+
+	ctx = ContextWithRole(context.Background(), "admin")
+	assert.Same(t, trace, masker.MaskTrace(ctx, trace))
+	assert.Nil(t, masker.MaskTrace(context.Background(), nil))
+}
+
+func TestTagMaskerMaskSpans(t *testing.T) {
+	masker := NewTagMasker(TagMaskingConfig{
+		Rules: []TagMaskingRule{{Roles: []string{"viewer"}, Keys: []string{"http.url"}, Action: TagMaskingActionMask}},
+	})
+	spans := newTagMaskingTrace().Spans
+
+	assert.Equal(t, spans, masker.MaskSpans(context.Background(), spans))
+
+	masked := masker.MaskSpans(ContextWithRole(context.Background(), "viewer"), spans)
+	require.Len(t, masked, 1)
+	assert.Equal(t, model.String("http.url", MaskedTagValue), masked[0].Tags[0])
+	assert.Equal(t, "/login?user=alice", spans[0].Tags[0].VStr)
+}
+
+func TestTagMaskerMaskTags(t *testing.T) {
+	masker := newTestTagMasker()
+	keys := []string{"http.url", "user.email"}
+	assert.Equal(t, []string{"http.url"}, masker.MaskTagKeys(context.Background(), keys))
+	assert.Equal(t, keys, masker.MaskTagKeys(ContextWithRole(context.Background(), "admin"), keys))
+
+	values := []string{"/login", "/logout"}
+	assert.Empty(t, masker.MaskTagValues(context.Background(), "user.email", []string{"alice@example.com"}))
+	assert.Equal(t, values, masker.MaskTagValues(context.Background(), "http.url", values))
+
+	ctx := ContextWithRole(tenancy.WithTenant(context.Background(), "acme"), "support")
+	assert.Equal(t, []string{MaskedTagValue}, masker.MaskTagValues(ctx, "http.url", values))
+	assert.Empty(t, masker.MaskTagValues(ctx, "http.url", nil))
+}
+
+func TestQueryServiceTagSuggestionsMasking(t *testing.T) {
+	qs := NewQueryService(tagSpanReader{&spanstoremocks.Reader{}}, &depsmocks.Reader{}, QueryServiceOptions{
+		TagMasker: newTestTagMasker(),
+	})
+	keys, err := qs.GetTagKeys(context.Background(), &spanstore.TagQueryParameters{ServiceName: "svc"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"svc.key"}, keys)
+	values, err := qs.GetTagValues(context.Background(), &spanstore.TagQueryParameters{ServiceName: "svc", Key: "user.email"})
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	qs = NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, QueryServiceOptions{TagMasker: newTestTagMasker()})
+	_, err = qs.GetTagKeys(context.Background(), &spanstore.TagQueryParameters{})
+	require.ErrorIs(t, err, spanstore.ErrTagsNotSupported)
+}
+
+func TestLoadTagMaskingConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		file := filepath.Join(dir, "tag-masking.json")
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+		return file
+	}
+
+	cfg, err := LoadTagMaskingConfig(write(`{"rules": [{"keys": ["http.url"]}, {"keys": ["user.email"], "action": "remove"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, &TagMaskingConfig{Rules: []TagMaskingRule{
+		{Keys: []string{"http.url"}, Action: TagMaskingActionMask},
+		{Keys: []string{"user.email"}, Action: TagMaskingActionRemove},
+	}}, cfg)
+
+	_, err = LoadTagMaskingConfig(write(`{"rules": [{"keys": ["a"], "action": "hide"}]}`))
+	require.ErrorContains(t, err, `tag masking rule 0 has unknown action "hide"`)
+
+	_, err = LoadTagMaskingConfig(write(`{"rules": [{"action": "mask"}]}`))
+	require.ErrorContains(t, err, "tag masking rule 0 has no keys")
+
+	_, err = LoadTagMaskingConfig(write(`not json`))
+	require.ErrorContains(t, err, "cannot parse tag masking config")
+
+	_, err = LoadTagMaskingConfig(filepath.Join(dir, "missing.json"))
+	require.ErrorContains(t, err, "cannot read tag masking config")
+}
+
+func withTagMasker() testOption {
+	return func(_ *testQueryService, options *QueryServiceOptions) {
+		options.TagMasker = newTestTagMasker()
+	}
+}
+
+func TestQueryServiceTagMasking(t *testing.T) {
+	tqs := initializeTestService(withTagMasker(), withArchiveSpanWriter())
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(newTagMaskingTrace(), nil)
+	tqs.spanReader.On("FindTraces", mock.Anything, mock.Anything).Return([]*model.Trace{newTagMaskingTrace()}, nil)
+	ctx := context.Background()
+	emailRemoved := func(t *testing.T, span *model.Span) {
+		_, found := model.KeyValues(span.Tags).FindByKey("user.email")
+		assert.False(t, found)
+	}
+
+	trace, err := tqs.queryService.GetTrace(ctx, mockTraceID)
+	require.NoError(t, err)
+	emailRemoved(t, trace.Spans[0])
+
+	traces, err := tqs.queryService.FindTraces(ctx, &spanstore.TraceQueryParameters{})
+	require.NoError(t, err)
+	emailRemoved(t, traces[0].Spans[0])
+
+	page, err := tqs.queryService.FindTracesPage(ctx, &spanstore.TraceQueryParameters{})
+	require.NoError(t, err)
+	emailRemoved(t, page.Traces[0].Spans[0])
+
+	err = tqs.queryService.StreamTrace(ctx, mockTraceID, 0, func(spans []*model.Span) error {
+		emailRemoved(t, spans[0])
+		return nil
+	})
+	require.NoError(t, err)
+
+	// archived traces keep all their tags
+	tqs.archiveSpanWriter.On("WriteSpan", mock.Anything, mock.MatchedBy(func(span *model.Span) bool {
+		_, found := model.KeyValues(span.Tags).FindByKey("user.email")
+		return found
+	})).Return(nil).Once()
+	require.NoError(t, tqs.queryService.ArchiveTrace(ctx, mockTraceID))
+	tqs.archiveSpanWriter.AssertExpectations(t)
+}
+
+func TestQueryServiceTagMaskingErrors(t *testing.T) {
+	tqs := initializeTestService(withTagMasker())
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(nil, assert.AnError)
+	tqs.spanReader.On("FindTraces", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	_, err := tqs.queryService.GetTrace(context.Background(), mockTraceID)
+	require.ErrorIs(t, err, assert.AnError)
+	_, err = tqs.queryService.FindTraces(context.Background(), &spanstore.TraceQueryParameters{})
+	require.ErrorIs(t, err, assert.AnError)
+	_, err = tqs.queryService.FindTracesPage(context.Background(), &spanstore.TraceQueryParameters{})
+	require.ErrorIs(t, err, assert.AnError)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+var (
+	// ErrMissingTenant is returned when multi-tenancy is enabled and a read has no tenant in its context.
+	ErrMissingTenant = errors.New("missing tenant")
+	// ErrUnknownTenant is returned when multi-tenancy is enabled and the tenant of a read is not allowed.
+	ErrUnknownTenant = errors.New("unknown tenant")
+)
+
+// checkTenant makes sure that, with multi-tenancy enabled, storage is only read on behalf
+// of a valid tenant. The storage backends scope their reads to the tenant in the context,
+// so a read without one could return the data of every tenant.
+func (qs QueryService) checkTenant(ctx context.Context) error {
+	tm := qs.options.Tenancy
+	if tm == nil || !tm.Enabled {
+		return nil
+	}
+	tenant := tenancy.GetTenant(ctx)
+	if tenant == "" {
+		return ErrMissingTenant
+	}
+	if !tm.Valid(tenant) {
+		return ErrUnknownTenant
+	}
+	return nil
+}
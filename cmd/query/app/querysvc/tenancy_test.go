@@ -0,0 +1,100 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func withTenancy(tenants ...string) testOption {
+	return func(_ *testQueryService, options *QueryServiceOptions) {
+		options.Tenancy = tenancy.NewManager(&tenancy.Options{Enabled: true, Tenants: tenants})
+	}
+}
+
+func TestReadsRequireTenant(t *testing.T) {
+	tqs := initializeTestService(withTenancy("acme"))
+	qs := tqs.queryService
+	reads := map[string]func(ctx context.Context) error{
+		"GetTrace": func(ctx context.Context) error {
+			_, err := qs.GetTrace(ctx, mockTraceID)
+			return err
+		},
+		"StreamTrace": func(ctx context.Context) error {
+			return qs.StreamTrace(ctx, mockTraceID, 0, func([]*model.Span) error { return nil })
+		},
+		"GetServices": func(ctx context.Context) error {
+			_, err := qs.GetServices(ctx)
+			return err
+		},
+		"GetOperations": func(ctx context.Context) error {
+			_, err := qs.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: "svc"})
+			return err
+		},
+		"FindTraces": func(ctx context.Context) error {
+			_, err := qs.FindTraces(ctx, &spanstore.TraceQueryParameters{})
+			return err
+		},
+		"FindTracesPage": func(ctx context.Context) error {
+			_, err := qs.FindTracesPage(ctx, &spanstore.TraceQueryParameters{})
+			return err
+		},
+		"GetLatencyDistribution": func(ctx context.Context) error {
+			_, err := qs.GetLatencyDistribution(ctx, &spanstore.LatencyQueryParameters{})
+			return err
+		},
+		"GetTagKeys": func(ctx context.Context) error {
+			_, err := qs.GetTagKeys(ctx, &spanstore.TagQueryParameters{})
+			return err
+		},
+		"GetTagValues": func(ctx context.Context) error {
+			_, err := qs.GetTagValues(ctx, &spanstore.TagQueryParameters{})
+			return err
+		},
+		"GetDependencies": func(ctx context.Context) error {
+			_, err := qs.GetDependencies(ctx, time.Now(), time.Hour)
+			return err
+		},
+	}
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, read(context.Background()), ErrMissingTenant)
+			require.ErrorIs(t, read(tenancy.WithTenant(context.Background(), "other")), ErrUnknownTenant)
+		})
+	}
+	tqs.spanReader.AssertNotCalled(t, "GetTrace", mock.Anything, mock.Anything)
+	tqs.spanReader.AssertNotCalled(t, "FindTraces", mock.Anything, mock.Anything)
+}
+
+func TestReadWithValidTenant(t *testing.T) {
+	tqs := initializeTestService(withTenancy("acme"))
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+	tqs.spanReader.On("GetServices", mock.Anything).Return([]string{"svc"}, nil).Once()
+
+	trace, err := tqs.queryService.GetTrace(ctx, mockTraceID)
+	require.NoError(t, err)
+	assert.Equal(t, mockTrace, trace)
+	services, err := tqs.queryService.GetServices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"svc"}, services)
+}
+
+func TestSavedSearchesRequireTenant(t *testing.T) {
+	tqs := initializeTestService(withTenancy(), withSavedSearchStore())
+	_, err := tqs.queryService.SaveSearch(context.Background(), &savedsearchstore.SavedSearch{Name: "errors"})
+	require.ErrorIs(t, err, ErrMissingTenant)
+	require.ErrorIs(t, tqs.queryService.DeleteSavedSearch(context.Background(), "id"), ErrMissingTenant)
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// OperationKey identifies an operation of a service.
+type OperationKey struct {
+	Service   string
+	Operation string
+}
+
+func (k OperationKey) String() string {
+	return k.Service + "::" + k.Operation
+}
+
+// SpanStats aggregates the spans of a trace that share the same operation or call path.
+type SpanStats struct {
+	Count    int
+	Duration time.Duration
+}
+
+func (s *SpanStats) add(span *model.Span) {
+	s.Count++
+	s.Duration += span.Duration
+}
+
+// SpanPathDiff compares the spans found at the same call path, i.e. the chain of
+// operations from the root span, in both traces.
+type SpanPathDiff struct {
+	Path []OperationKey
+	A    SpanStats
+	B    SpanStats
+}
+
+// OperationDiff compares the spans of a single operation in both traces.
+type OperationDiff struct {
+	OperationKey
+	A SpanStats
+	B SpanStats
+}
+
+// TraceComparison describes the structural differences between trace A and trace B.
+type TraceComparison struct {
+	// Added lists call paths present only in trace B.
+	Added []SpanPathDiff
+	// Removed lists call paths present only in trace A.
+	Removed []SpanPathDiff
+	// Changed lists call paths present in both traces with a different number of spans or total duration.
+	Changed []SpanPathDiff
+	// Operations compares every operation found in either trace.
+	Operations []OperationDiff
+}
+
+// CompareTraces fetches both traces, adjusts them and compares their structure.
+func (qs QueryService) CompareTraces(ctx context.Context, traceIDA, traceIDB model.TraceID) (*TraceComparison, error) {
+	traces := make([]*model.Trace, 2)
+	for i, traceID := range []model.TraceID{traceIDA, traceIDB} {
+		trace, err := qs.GetTrace(ctx, traceID)
+		if err != nil {
+			return nil, err
+		}
+		// adjuster errors are only warnings, the adjusted trace is still usable
+		traces[i], _ = qs.Adjust(trace)
+	}
+	return CompareTraces(traces[0], traces[1]), nil
+}
+
+// CompareTraces matches the spans of two traces by their call path and operation.
+func CompareTraces(a, b *model.Trace) *TraceComparison {
+	pathsA, opsA := spanStatsOf(a)
+	pathsB, opsB := spanStatsOf(b)
+
+	comparison := &TraceComparison{}
+	for key, statsA := range pathsA.stats {
+		diff := SpanPathDiff{Path: pathsA.paths[key], A: *statsA}
+		statsB, ok := pathsB.stats[key]
+		if !ok {
+			comparison.Removed = append(comparison.Removed, diff)
+			continue
+		}
+		diff.B = *statsB
+		if diff.A != diff.B {
+			comparison.Changed = append(comparison.Changed, diff)
+		}
+	}
+	for key, statsB := range pathsB.stats {
+		if _, ok := pathsA.stats[key]; !ok {
+			comparison.Added = append(comparison.Added, SpanPathDiff{Path: pathsB.paths[key], B: *statsB})
+		}
+	}
+	for _, diffs := range [][]SpanPathDiff{comparison.Added, comparison.Removed, comparison.Changed} {
+		sort.Slice(diffs, func(i, j int) bool {
+			return pathString(diffs[i].Path) < pathString(diffs[j].Path)
+		})
+	}
+
+	for key, statsA := range opsA {
+		diff := OperationDiff{OperationKey: key, A: *statsA}
+		if statsB, ok := opsB[key]; ok {
+			diff.B = *statsB
+		}
+		comparison.Operations = append(comparison.Operations, diff)
+	}
+	for key, statsB := range opsB {
+		if _, ok := opsA[key]; !ok {
+			comparison.Operations = append(comparison.Operations, OperationDiff{OperationKey: key, B: *statsB})
+		}
+	}
+	sort.Slice(comparison.Operations, func(i, j int) bool {
+		return comparison.Operations[i].String() < comparison.Operations[j].String()
+	})
+	return comparison
+}
+
+type pathStats struct {
+	paths map[string][]OperationKey
+	stats map[string]*SpanStats
+}
+
+func spanStatsOf(trace *model.Trace) (pathStats, map[OperationKey]*SpanStats) {
+	spans := make(map[model.SpanID]*model.Span, len(trace.Spans))
+	for _, span := range trace.Spans {
+		spans[span.SpanID] = span
+	}
+	paths := make(map[model.SpanID][]OperationKey, len(trace.Spans))
+	var pathOf func(span *model.Span, depth int) []OperationKey
+	pathOf = func(span *model.Span, depth int) []OperationKey {
+		if path, ok := paths[span.SpanID]; ok {
+			return path
+		}
+		var path []OperationKey
+		// the depth limit protects against reference cycles in malformed traces
+		if parent, ok := spans[span.ParentSpanID()]; ok && parent != span && depth < len(spans) {
+			parentPath := pathOf(parent, depth+1)
+			path = make([]OperationKey, len(parentPath), len(parentPath)+1)
+			copy(path, parentPath)
+		}
+		path = append(path, operationKeyOf(span))
+		paths[span.SpanID] = path
+		return path
+	}
+
+	byPath := pathStats{
+		paths: map[string][]OperationKey{},
+		stats: map[string]*SpanStats{},
+	}
+	byOperation := map[OperationKey]*SpanStats{}
+	for _, span := range trace.Spans {
+		path := pathOf(span, 0)
+		key := pathString(path)
+		if _, ok := byPath.stats[key]; !ok {
+			byPath.paths[key] = path
+			byPath.stats[key] = &SpanStats{}
+		}
+		byPath.stats[key].add(span)
+
+		op := operationKeyOf(span)
+		if _, ok := byOperation[op]; !ok {
+			byOperation[op] = &SpanStats{}
+		}
+		byOperation[op].add(span)
+	}
+	return byPath, byOperation
+}
+
+func operationKeyOf(span *model.Span) OperationKey {
+	key := OperationKey{Operation: span.OperationName}
+	if span.Process != nil {
+		key.Service = span.Process.ServiceName
+	}
+	return key
+}
+
+func pathString(path []OperationKey) string {
+	parts := make([]string, len(path))
+	for i, key := range path {
+		parts[i] = key.String()
+	}
+	return strings.Join(parts, " > ")
+}
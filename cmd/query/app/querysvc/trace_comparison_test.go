@@ -0,0 +1,115 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func comparisonSpan(spanID, parentID uint64, service, operation string, duration time.Duration) *model.Span {
+	span := &model.Span{
+		TraceID:       mockTraceID,
+		SpanID:        model.NewSpanID(spanID),
+		OperationName: operation,
+		Duration:      duration,
+		Process:       &model.Process{ServiceName: service},
+	}
+	if parentID != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(mockTraceID, model.NewSpanID(parentID))}
+	}
+	return span
+}
+
+var (
+	comparisonTraceA = &model.Trace{Spans: []*model.Span{
+		comparisonSpan(1, 0, "a", "GET", 100*time.Millisecond),
+		comparisonSpan(2, 1, "b", "query", 30*time.Millisecond),
+		comparisonSpan(3, 1, "b", "query", 20*time.Millisecond),
+		comparisonSpan(4, 2, "d", "log", time.Millisecond),
+	}}
+	comparisonTraceB = &model.Trace{Spans: []*model.Span{
+		comparisonSpan(1, 0, "a", "GET", 150*time.Millisecond),
+		comparisonSpan(2, 1, "b", "query", 25*time.Millisecond),
+		comparisonSpan(3, 1, "c", "cache", 5*time.Millisecond),
+	}}
+)
+
+func TestCompareTraces(t *testing.T) {
+	root := OperationKey{Service: "a", Operation: "GET"}
+	query := OperationKey{Service: "b", Operation: "query"}
+	cache := OperationKey{Service: "c", Operation: "cache"}
+	log := OperationKey{Service: "d", Operation: "log"}
+
+	comparison := CompareTraces(comparisonTraceA, comparisonTraceB)
+	assert.Equal(t, &TraceComparison{
+		Added: []SpanPathDiff{
+			{Path: []OperationKey{root, cache}, B: SpanStats{Count: 1, Duration: 5 * time.Millisecond}},
+		},
+		Removed: []SpanPathDiff{
+			{Path: []OperationKey{root, query, log}, A: SpanStats{Count: 1, Duration: time.Millisecond}},
+		},
+		Changed: []SpanPathDiff{
+			{
+				Path: []OperationKey{root},
+				A:    SpanStats{Count: 1, Duration: 100 * time.Millisecond},
+				B:    SpanStats{Count: 1, Duration: 150 * time.Millisecond},
+			},
+			{
+				Path: []OperationKey{root, query},
+				A:    SpanStats{Count: 2, Duration: 50 * time.Millisecond},
+				B:    SpanStats{Count: 1, Duration: 25 * time.Millisecond},
+			},
+		},
+		Operations: []OperationDiff{
+			{OperationKey: root, A: SpanStats{Count: 1, Duration: 100 * time.Millisecond}, B: SpanStats{Count: 1, Duration: 150 * time.Millisecond}},
+			{OperationKey: query, A: SpanStats{Count: 2, Duration: 50 * time.Millisecond}, B: SpanStats{Count: 1, Duration: 25 * time.Millisecond}},
+			{OperationKey: cache, B: SpanStats{Count: 1, Duration: 5 * time.Millisecond}},
+			{OperationKey: log, A: SpanStats{Count: 1, Duration: time.Millisecond}},
+		},
+	}, comparison)
+}
+
+func TestCompareTracesIdentical(t *testing.T) {
+	comparison := CompareTraces(comparisonTraceA, comparisonTraceA)
+	assert.Empty(t, comparison.Added)
+	assert.Empty(t, comparison.Removed)
+	assert.Empty(t, comparison.Changed)
+	assert.Len(t, comparison.Operations, 3)
+}
+
+func TestCompareTracesWithCycle(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		comparisonSpan(1, 2, "a", "x", time.Millisecond),
+		comparisonSpan(2, 1, "a", "y", time.Millisecond),
+	}}
+	comparison := CompareTraces(trace, &model.Trace{})
+	assert.Len(t, comparison.Removed, 2)
+	assert.Len(t, comparison.Operations, 2)
+}
+
+func TestQueryServiceCompareTraces(t *testing.T) {
+	tqs := initializeTestService()
+	traceIDB := model.NewTraceID(0, 1)
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(comparisonTraceA, nil).Once()
+	tqs.spanReader.On("GetTrace", mock.Anything, traceIDB).Return(comparisonTraceB, nil).Once()
+
+	comparison, err := tqs.queryService.CompareTraces(context.Background(), mockTraceID, traceIDB)
+	require.NoError(t, err)
+	assert.Len(t, comparison.Added, 1)
+	assert.Len(t, comparison.Operations, 4)
+
+	tqs.spanReader.On("GetTrace", mock.Anything, traceIDB).Return(nil, spanstore.ErrTraceNotFound).Once()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(comparisonTraceA, nil).Once()
+	_, err = tqs.queryService.CompareTraces(context.Background(), mockTraceID, traceIDB)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+}
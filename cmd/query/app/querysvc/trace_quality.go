@@ -0,0 +1,255 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// maxQualityExamples is the maximum number of traces given as examples of a failed quality check.
+const maxQualityExamples = 5
+
+// QualityCheck identifies an instrumentation quality check of the spans.
+type QualityCheck string
+
+const (
+	// QualityCheckMissingServerSpan fails for the client spans without a server span as child,
+	// i.e. calling services that are not instrumented or do not propagate the trace context.
+	QualityCheckMissingServerSpan QualityCheck = "missing_server_span"
+	// QualityCheckMissingClientSpan fails for the server spans whose parent is not a client span.
+	QualityCheckMissingClientSpan QualityCheck = "missing_client_span"
+	// QualityCheckClockSkew fails for the spans starting before their parent from another service,
+	// and for the server spans ending after their client span.
+	QualityCheckClockSkew QualityCheck = "clock_skew"
+	// QualityCheckOrphanSpan fails for the spans whose parent is missing from the trace.
+	QualityCheckOrphanSpan QualityCheck = "orphan_span"
+	// QualityCheckMissingSpanKind fails for the spans without the span.kind tag.
+	QualityCheckMissingSpanKind QualityCheck = "missing_span_kind"
+	// QualityCheckMissingPeer fails for the client and producer spans without any of the
+	// semantic conventions attributes identifying the peer, see peerTagKeys.
+	QualityCheckMissingPeer QualityCheck = "missing_peer"
+)
+
+// qualityChecks lists the checks in the order of the reports.
+var qualityChecks = []QualityCheck{
+	QualityCheckMissingServerSpan,
+	QualityCheckMissingClientSpan,
+	QualityCheckClockSkew,
+	QualityCheckOrphanSpan,
+	QualityCheckMissingSpanKind,
+	QualityCheckMissingPeer,
+}
+
+// peerTagKeys are the tags of the OpenTracing and OpenTelemetry semantic conventions
+// identifying the peer of a client or producer span.
+var peerTagKeys = []string{
+	"peer.service",
+	"peer.hostname",
+	"peer.ipv4",
+	"server.address",
+	"net.peer.name",
+	"http.url",
+	"url.full",
+	"db.system",
+	"messaging.system",
+	"rpc.system",
+}
+
+// QualityCheckResult counts the spans of a service a quality check applied to, and the ones failing it.
+type QualityCheckResult struct {
+	Check   QualityCheck
+	Checked int
+	Failed  int
+	// TraceIDs are examples of traces with spans failing the check.
+	TraceIDs []model.TraceID
+}
+
+// ServiceQuality is the instrumentation quality report of the spans of a service.
+type ServiceQuality struct {
+	Service string
+	// Traces is the number of analyzed traces with spans of the service.
+	Traces int
+	// Score is the ratio of the checks passed by the spans of the service, between 0 and 1.
+	Score  float64
+	Checks []QualityCheckResult
+}
+
+// TraceQualityReport is the instrumentation quality report of the services of the analyzed traces.
+type TraceQualityReport struct {
+	Traces   int
+	Services []ServiceQuality
+}
+
+// GetTraceQuality fetches the trace and analyzes its instrumentation quality.
+// The trace is not adjusted, so that the clock skew can be detected.
+func (qs QueryService) GetTraceQuality(ctx context.Context, traceID model.TraceID) (*TraceQualityReport, error) {
+	trace, err := qs.GetTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzeTraceQuality([]*model.Trace{trace}), nil
+}
+
+// GetServiceQuality finds the traces matching the query and analyzes their instrumentation quality.
+func (qs QueryService) GetServiceQuality(ctx context.Context, query *spanstore.TraceQueryParameters) (*TraceQualityReport, error) {
+	traces, err := qs.FindTraces(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzeTraceQuality(traces), nil
+}
+
+// AnalyzeTraceQuality runs the quality checks on the spans of the traces and reports the results
+// of each service, sorted by name.
+func AnalyzeTraceQuality(traces []*model.Trace) *TraceQualityReport {
+	services := make(map[string]*serviceQuality)
+	for _, trace := range traces {
+		tree := newSpanTree(trace)
+		seen := make(map[string]bool)
+		for _, span := range trace.Spans {
+			service := serviceOf(span)
+			sq, ok := services[service]
+			if !ok {
+				sq = newServiceQuality()
+				services[service] = sq
+			}
+			if !seen[service] {
+				seen[service] = true
+				sq.traces++
+			}
+			checkSpanQuality(tree, span, func(check QualityCheck, passed bool) {
+				sq.record(check, passed, span.TraceID)
+			})
+		}
+	}
+
+	report := &TraceQualityReport{
+		Traces:   len(traces),
+		Services: make([]ServiceQuality, 0, len(services)),
+	}
+	for service, sq := range services {
+		report.Services = append(report.Services, sq.report(service))
+	}
+	sort.Slice(report.Services, func(i, j int) bool {
+		return report.Services[i].Service < report.Services[j].Service
+	})
+	return report
+}
+
+// checkSpanQuality runs the checks applying to the span.
+func checkSpanQuality(tree *spanTree, span *model.Span, record func(check QualityCheck, passed bool)) {
+	kind, hasKind := span.GetSpanKind()
+	record(QualityCheckMissingSpanKind, hasKind)
+
+	parentID := span.ParentSpanID()
+	parent, hasParent := tree.spans[parentID]
+	hasParent = hasParent && parentID != span.SpanID
+	if parentID != 0 {
+		record(QualityCheckOrphanSpan, hasParent)
+	}
+
+	switch kind {
+	case trace.SpanKindClient:
+		hasServer := false
+		for _, child := range tree.children[span.SpanID] {
+			hasServer = hasServer || child.IsRPCServer()
+		}
+		record(QualityCheckMissingServerSpan, hasServer)
+		record(QualityCheckMissingPeer, hasPeerTag(span))
+	case trace.SpanKindProducer:
+		record(QualityCheckMissingPeer, hasPeerTag(span))
+	case trace.SpanKindServer:
+		if hasParent {
+			record(QualityCheckMissingClientSpan, parent.IsRPCClient())
+		}
+	}
+
+	if hasParent && serviceOf(parent) != serviceOf(span) && isChildOf(span, parentID) {
+		skewed := span.StartTime.Before(parent.StartTime)
+		if kind == trace.SpanKindServer && parent.IsRPCClient() {
+			skewed = skewed || span.StartTime.Add(span.Duration).After(parent.StartTime.Add(parent.Duration))
+		}
+		record(QualityCheckClockSkew, !skewed)
+	}
+}
+
+func hasPeerTag(span *model.Span) bool {
+	for _, key := range peerTagKeys {
+		if _, ok := model.KeyValues(span.Tags).FindByKey(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isChildOf returns whether the span is a synchronous child of its parent, the spans that
+// follow from their parent being allowed to start or end at any time.
+func isChildOf(span *model.Span, parentID model.SpanID) bool {
+	for _, ref := range span.References {
+		if ref.SpanID == parentID && ref.TraceID == span.TraceID {
+			return ref.RefType == model.ChildOf
+		}
+	}
+	return true
+}
+
+func serviceOf(span *model.Span) string {
+	if span.Process == nil {
+		return ""
+	}
+	return span.Process.ServiceName
+}
+
+type serviceQuality struct {
+	traces int
+	checks map[QualityCheck]*QualityCheckResult
+}
+
+func newServiceQuality() *serviceQuality {
+	return &serviceQuality{
+		checks: make(map[QualityCheck]*QualityCheckResult, len(qualityChecks)),
+	}
+}
+
+func (sq *serviceQuality) record(check QualityCheck, passed bool, traceID model.TraceID) {
+	result, ok := sq.checks[check]
+	if !ok {
+		result = &QualityCheckResult{Check: check}
+		sq.checks[check] = result
+	}
+	result.Checked++
+	if passed {
+		return
+	}
+	result.Failed++
+	if n := len(result.TraceIDs); n < maxQualityExamples && (n == 0 || result.TraceIDs[n-1] != traceID) {
+		result.TraceIDs = append(result.TraceIDs, traceID)
+	}
+}
+
+func (sq *serviceQuality) report(service string) ServiceQuality {
+	report := ServiceQuality{
+		Service: service,
+		Traces:  sq.traces,
+		Score:   1,
+	}
+	checked, failed := 0, 0
+	for _, check := range qualityChecks {
+		if result, ok := sq.checks[check]; ok {
+			report.Checks = append(report.Checks, *result)
+			checked += result.Checked
+			failed += result.Failed
+		}
+	}
+	if checked > 0 {
+		report.Score = float64(checked-failed) / float64(checked)
+	}
+	return report
+}
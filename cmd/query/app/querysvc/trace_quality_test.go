@@ -0,0 +1,159 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package querysvc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func qualitySpan(spanID uint64, service, kind string, start, duration time.Duration, refs []model.SpanRef, tags ...model.KeyValue) *model.Span {
+	if kind != "" {
+		tags = append(tags, model.String("span.kind", kind))
+	}
+	return &model.Span{
+		TraceID:    mockTraceID,
+		SpanID:     model.NewSpanID(spanID),
+		References: refs,
+		StartTime:  traceStart.Add(start),
+		Duration:   duration,
+		Tags:       tags,
+		Process:    model.NewProcess(service, nil),
+	}
+}
+
+func childOf(spanID uint64) []model.SpanRef {
+	return []model.SpanRef{model.NewChildOfRef(mockTraceID, model.NewSpanID(spanID))}
+}
+
+func newQualityTrace() *model.Trace {
+	ms := time.Millisecond
+	return &model.Trace{Spans: []*model.Span{
+		qualitySpan(1, "frontend", "server", 0, 100*ms, nil),
+		qualitySpan(2, "frontend", "client", 10*ms, 40*ms, childOf(1), model.String("peer.service", "backend")),
+		// starts before its client span
+		qualitySpan(3, "backend", "server", 5*ms, 10*ms, childOf(2)),
+		// calls an uninstrumented peer without identifying it
+		qualitySpan(4, "frontend", "client", 60*ms, 10*ms, childOf(1)),
+		// the parent was not received
+		qualitySpan(5, "backend", "", 20*ms, 10*ms, childOf(99)),
+		// called without client span
+		qualitySpan(6, "db", "server", 70*ms, 10*ms, childOf(1)),
+		// the follows-from spans may start before their parent
+		qualitySpan(7, "worker", "consumer", 0, 5*ms, []model.SpanRef{model.NewFollowsFromRef(mockTraceID, model.NewSpanID(2))}),
+	}}
+}
+
+func TestAnalyzeTraceQuality(t *testing.T) {
+	examples := []model.TraceID{mockTraceID}
+	report := AnalyzeTraceQuality([]*model.Trace{newQualityTrace()})
+	assert.Equal(t, &TraceQualityReport{
+		Traces: 1,
+		Services: []ServiceQuality{
+			{
+				Service: "backend",
+				Traces:  1,
+				Score:   0.5,
+				Checks: []QualityCheckResult{
+					{Check: QualityCheckMissingClientSpan, Checked: 1},
+					{Check: QualityCheckClockSkew, Checked: 1, Failed: 1, TraceIDs: examples},
+					{Check: QualityCheckOrphanSpan, Checked: 2, Failed: 1, TraceIDs: examples},
+					{Check: QualityCheckMissingSpanKind, Checked: 2, Failed: 1, TraceIDs: examples},
+				},
+			},
+			{
+				Service: "db",
+				Traces:  1,
+				Score:   0.75,
+				Checks: []QualityCheckResult{
+					{Check: QualityCheckMissingClientSpan, Checked: 1, Failed: 1, TraceIDs: examples},
+					{Check: QualityCheckClockSkew, Checked: 1},
+					{Check: QualityCheckOrphanSpan, Checked: 1},
+					{Check: QualityCheckMissingSpanKind, Checked: 1},
+				},
+			},
+			{
+				Service: "frontend",
+				Traces:  1,
+				Score:   7.0 / 9,
+				Checks: []QualityCheckResult{
+					{Check: QualityCheckMissingServerSpan, Checked: 2, Failed: 1, TraceIDs: examples},
+					{Check: QualityCheckOrphanSpan, Checked: 2},
+					{Check: QualityCheckMissingSpanKind, Checked: 3},
+					{Check: QualityCheckMissingPeer, Checked: 2, Failed: 1, TraceIDs: examples},
+				},
+			},
+			{
+				Service: "worker",
+				Traces:  1,
+				Score:   1,
+				Checks: []QualityCheckResult{
+					{Check: QualityCheckOrphanSpan, Checked: 1},
+					{Check: QualityCheckMissingSpanKind, Checked: 1},
+				},
+			},
+		},
+	}, report)
+}
+
+func TestAnalyzeTraceQualityExamples(t *testing.T) {
+	var traces []*model.Trace
+	for i := 0; i < maxQualityExamples+2; i++ {
+		traceID := model.NewTraceID(0, uint64(i))
+		traces = append(traces, &model.Trace{Spans: []*model.Span{
+			{TraceID: traceID, SpanID: model.NewSpanID(1), Process: model.NewProcess("svc", nil)},
+			{TraceID: traceID, SpanID: model.NewSpanID(2), Process: model.NewProcess("svc", nil)},
+		}})
+	}
+	report := AnalyzeTraceQuality(traces)
+	require.Len(t, report.Services, 1)
+	service := report.Services[0]
+	assert.Equal(t, maxQualityExamples+2, service.Traces)
+	assert.Zero(t, service.Score)
+	require.Len(t, service.Checks, 1)
+	assert.Equal(t, QualityCheckMissingSpanKind, service.Checks[0].Check)
+	assert.Equal(t, 2*(maxQualityExamples+2), service.Checks[0].Failed)
+	assert.Len(t, service.Checks[0].TraceIDs, maxQualityExamples)
+
+	assert.Equal(t, &TraceQualityReport{Services: []ServiceQuality{}}, AnalyzeTraceQuality(nil))
+}
+
+func TestGetTraceQuality(t *testing.T) {
+	tqs := initializeTestService()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(newQualityTrace(), nil).Once()
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+
+	report, err := tqs.queryService.GetTraceQuality(context.Background(), mockTraceID)
+	require.NoError(t, err)
+	assert.Len(t, report.Services, 4)
+
+	_, err = tqs.queryService.GetTraceQuality(context.Background(), mockTraceID)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+}
+
+func TestGetServiceQuality(t *testing.T) {
+	tqs := initializeTestService()
+	query := &spanstore.TraceQueryParameters{ServiceName: "frontend"}
+	tqs.spanReader.On("FindTraces", mock.Anything, query).Return([]*model.Trace{newQualityTrace(), newQualityTrace()}, nil).Once()
+	tqs.spanReader.On("FindTraces", mock.Anything, query).Return(nil, assert.AnError).Once()
+
+	report, err := tqs.queryService.GetServiceQuality(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Traces)
+	require.Len(t, report.Services, 4)
+	assert.Equal(t, 2, report.Services[0].Traces)
+	// the traces are not repeated in the examples
+	assert.Equal(t, []model.TraceID{mockTraceID}, report.Services[0].Checks[1].TraceIDs)
+
+	_, err = tqs.queryService.GetServiceQuality(context.Background(), query)
+	require.ErrorIs(t, err, assert.AnError)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/pkg/oidc"
+)
+
+// roleSource describes where the role of the user is found: in the claims of the OIDC token
+// if claim is set, otherwise in the request header.
+type roleSource struct {
+	header string
+	claim  string
+}
+
+func (rs roleSource) enabled() bool {
+	return rs.header != "" || rs.claim != ""
+}
+
+// roleHTTPHandler stores the role of the user in the request context.
+func roleHTTPHandler(rs roleSource, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := r.Header.Get(rs.header)
+		if rs.claim != "" {
+			role = oidc.GetClaims(r.Context()).String(rs.claim)
+		}
+		h.ServeHTTP(w, r.WithContext(querysvc.ContextWithRole(r.Context(), role)))
+	})
+}
+
+func roleFromMetadata(ctx context.Context, rs roleSource) context.Context {
+	if rs.claim != "" {
+		return querysvc.ContextWithRole(ctx, oidc.GetClaims(ctx).String(rs.claim))
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	roles := md.Get(rs.header)
+	if len(roles) != 1 {
+		// an ambiguous role is treated as no role, to which the most restrictive rules apply
+		return ctx
+	}
+	return querysvc.ContextWithRole(ctx, roles[0])
+}
+
+// roleServerStream is a wrapper for ServerStream providing the context with the role
+type roleServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *roleServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// newRoleUnaryInterceptor stores the role of the user in the request context.
+func newRoleUnaryInterceptor(rs roleSource) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(roleFromMetadata(ctx, rs), req)
+	}
+}
+
+// newRoleStreamInterceptor stores the role of the user in the stream context.
+func newRoleStreamInterceptor(rs roleSource) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &roleServerStream{
+			ServerStream: ss,
+			ctx:          roleFromMetadata(ss.Context(), rs),
+		})
+	}
+}
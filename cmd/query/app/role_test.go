@@ -0,0 +1,84 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/pkg/oidc"
+)
+
+func TestRoleHTTPHandler(t *testing.T) {
+	var role string
+	handler := roleHTTPHandler(roleSource{header: "x-role"}, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		role = querysvc.GetRole(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/traces", nil)
+	req.Header.Set("x-role", "support")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "support", role)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/traces", nil))
+	assert.Empty(t, role)
+
+	handler = roleHTTPHandler(roleSource{header: "x-role", claim: "role"}, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		role = querysvc.GetRole(r.Context())
+	}))
+	req = httptest.NewRequest(http.MethodGet, "/api/traces", nil)
+	req.Header.Set("x-role", "admin")
+	req = req.WithContext(oidc.ContextWithClaims(req.Context(), oidc.Claims{"role": "support"}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "support", role, "the token claim takes precedence over the header")
+}
+
+func TestRoleUnaryInterceptor(t *testing.T) {
+	interceptor := newRoleUnaryInterceptor(roleSource{header: "x-role"})
+	getRole := func(ctx context.Context) string {
+		var role string
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			role = querysvc.GetRole(ctx)
+			return nil, nil
+		})
+		require.NoError(t, err)
+		return role
+	}
+
+	assert.Equal(t, "support", getRole(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-role", "support"))))
+	assert.Empty(t, getRole(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-role", "support", "x-role", "admin"))))
+	assert.Empty(t, getRole(context.Background()))
+
+	interceptor = newRoleUnaryInterceptor(roleSource{claim: "role"})
+	assert.Equal(t, "support", getRole(oidc.ContextWithClaims(context.Background(), oidc.Claims{"role": "support"})))
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestRoleStreamInterceptor(t *testing.T) {
+	interceptor := newRoleStreamInterceptor(roleSource{header: "x-role"})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-role", "support"))
+	var role string
+	err := interceptor(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(_ interface{}, ss grpc.ServerStream) error {
+		role = querysvc.GetRole(ss.Context())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "support", role)
+}
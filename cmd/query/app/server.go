@@ -35,12 +35,14 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/apiv3"
+	"github.com/jaegertracing/jaeger/cmd/query/app/audit"
 	"github.com/jaegertracing/jaeger/cmd/query/app/internal/api_v3"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/pkg/bearertoken"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
 	"github.com/jaegertracing/jaeger/pkg/netutils"
+	"github.com/jaegertracing/jaeger/pkg/oidc"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
@@ -62,6 +64,7 @@ type Server struct {
 	cmuxServer    cmux.CMux
 	grpcServer    *grpc.Server
 	httpServer    *httpServer
+	auditLogger   *audit.Logger
 	separatePorts bool
 	bgFinished    sync.WaitGroup
 }
@@ -80,13 +83,29 @@ func NewServer(logger *zap.Logger, healthCheck *healthcheck.HealthCheck, querySv
 	if (options.TLSHTTP.Enabled || options.TLSGRPC.Enabled) && (grpcPort == httpPort) {
 		return nil, errors.New("server with TLS enabled can not use same host ports for gRPC and HTTP.  Use dedicated HTTP and gRPC host ports instead")
 	}
+	if grpcPort == httpPort && options.GRPCAddressFamily != options.HTTPAddressFamily {
+		return nil, errors.New("server can not use different address families for gRPC and HTTP on the same host port.  Use dedicated HTTP and gRPC host ports instead")
+	}
+
+	var verifier *oidc.Verifier
+	if options.OIDC.Enabled {
+		verifier = oidc.NewVerifier(options.OIDC, nil)
+	}
 
-	grpcServer, err := createGRPCServer(querySvc, metricsQuerySvc, options, tm, logger, tracer)
+	var auditLogger *audit.Logger
+	if options.Audit.Enabled() {
+		auditLogger, err = audit.NewLogger(options.Audit, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	grpcServer, err := createGRPCServer(querySvc, metricsQuerySvc, options, tm, verifier, auditLogger, logger, tracer)
 	if err != nil {
 		return nil, err
 	}
 
-	httpServer, err := createHTTPServer(querySvc, metricsQuerySvc, options, tm, tracer, logger)
+	httpServer, err := createHTTPServer(querySvc, metricsQuerySvc, options, tm, verifier, auditLogger, tracer, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -99,11 +118,21 @@ func NewServer(logger *zap.Logger, healthCheck *healthcheck.HealthCheck, querySv
 		tracer:        tracer,
 		grpcServer:    grpcServer,
 		httpServer:    httpServer,
+		auditLogger:   auditLogger,
 		separatePorts: grpcPort != httpPort,
 	}, nil
 }
 
-func createGRPCServer(querySvc *querysvc.QueryService, metricsQuerySvc querysvc.MetricsQueryService, options *QueryOptions, tm *tenancy.Manager, logger *zap.Logger, tracer *jtracer.JTracer) (*grpc.Server, error) {
+func createGRPCServer(
+	querySvc *querysvc.QueryService,
+	metricsQuerySvc querysvc.MetricsQueryService,
+	options *QueryOptions,
+	tm *tenancy.Manager,
+	verifier *oidc.Verifier,
+	auditLogger *audit.Logger,
+	logger *zap.Logger,
+	tracer *jtracer.JTracer,
+) (*grpc.Server, error) {
 	var grpcOpts []grpc.ServerOption
 
 	if options.TLSGRPC.Enabled {
@@ -116,10 +145,32 @@ func createGRPCServer(querySvc *querysvc.QueryService, metricsQuerySvc querysvc.
 
 		grpcOpts = append(grpcOpts, grpc.Creds(creds))
 	}
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if verifier != nil {
+		unaryInterceptors = append(unaryInterceptors, oidc.NewUnaryServerInterceptor(verifier))
+		streamInterceptors = append(streamInterceptors, oidc.NewStreamServerInterceptor(verifier))
+	}
 	if tm.Enabled {
+		if claim := options.tenantClaim(); claim != "" {
+			unaryInterceptors = append(unaryInterceptors, newTenantClaimUnaryInterceptor(tm, claim))
+			streamInterceptors = append(streamInterceptors, newTenantClaimStreamInterceptor(tm, claim))
+		}
+		unaryInterceptors = append(unaryInterceptors, tenancy.NewGuardingUnaryInterceptor(tm))
+		streamInterceptors = append(streamInterceptors, tenancy.NewGuardingStreamInterceptor(tm))
+	}
+	if rs := options.roleSource(); rs.enabled() {
+		unaryInterceptors = append(unaryInterceptors, newRoleUnaryInterceptor(rs))
+		streamInterceptors = append(streamInterceptors, newRoleStreamInterceptor(rs))
+	}
+	if auditLogger != nil {
+		unaryInterceptors = append(unaryInterceptors, auditLogger.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, auditLogger.StreamServerInterceptor())
+	}
+	if len(unaryInterceptors) > 0 {
 		grpcOpts = append(grpcOpts,
-			grpc.StreamInterceptor(tenancy.NewGuardingStreamInterceptor(tm)),
-			grpc.UnaryInterceptor(tenancy.NewGuardingUnaryInterceptor(tm)),
+			grpc.ChainUnaryInterceptor(unaryInterceptors...),
+			grpc.ChainStreamInterceptor(streamInterceptors...),
 		)
 	}
 
@@ -156,13 +207,17 @@ func createHTTPServer(
 	metricsQuerySvc querysvc.MetricsQueryService,
 	queryOpts *QueryOptions,
 	tm *tenancy.Manager,
+	verifier *oidc.Verifier,
+	auditLogger *audit.Logger,
 	tracer *jtracer.JTracer,
 	logger *zap.Logger,
 ) (*httpServer, error) {
 	apiHandlerOptions := []HandlerOption{
 		HandlerOptions.Logger(logger),
 		HandlerOptions.Tracer(tracer),
+		HandlerOptions.BasePath(queryOpts.BasePath),
 		HandlerOptions.MetricsQueryService(metricsQuerySvc),
+		HandlerOptions.MaxBulkArchiveTraces(queryOpts.MaxBulkArchiveTraces),
 	}
 
 	apiHandler := NewAPIHandler(
@@ -173,6 +228,9 @@ func createHTTPServer(
 	if queryOpts.BasePath != "/" {
 		r = r.PathPrefix(queryOpts.BasePath).Subrouter()
 	}
+	if auditLogger != nil {
+		r.Use(auditLogger.HTTPMiddleware(tm))
+	}
 
 	(&apiv3.HTTPGateway{
 		QueryService: querySvc,
@@ -184,7 +242,20 @@ func createHTTPServer(
 	apiHandler.RegisterRoutes(r)
 	var handler http.Handler = r
 	handler = additionalHeadersHandler(handler, queryOpts.AdditionalHeaders)
-	if queryOpts.BearerTokenPropagation {
+	if rs := queryOpts.roleSource(); rs.enabled() {
+		handler = roleHTTPHandler(rs, handler)
+	}
+	if claim := queryOpts.tenantClaim(); tm.Enabled && claim != "" {
+		handler = tenantClaimHTTPHandler(tm, claim, handler)
+	}
+	if verifier != nil {
+		// the authenticator stores the validated token in the context if it is propagated
+		authenticator, err := oidc.NewAuthenticator(queryOpts.OIDC, verifier, logger)
+		if err != nil {
+			return nil, err
+		}
+		handler = authenticator.Handler(handler)
+	} else if queryOpts.BearerTokenPropagation {
 		handler = bearertoken.PropagationHandler(logger, handler)
 	}
 	handler = handlers.CompressHandler(handler)
@@ -208,7 +279,7 @@ func createHTTPServer(
 
 	}
 
-	server.staticHandlerCloser = RegisterStaticHandler(r, logger, queryOpts, querySvc.GetCapabilities())
+	server.staticHandlerCloser = RegisterStaticHandler(r, logger, queryOpts, querySvc.GetCapabilities(), tm)
 
 	return server, nil
 }
@@ -224,12 +295,12 @@ func (hS httpServer) Close() error {
 func (s *Server) initListener() (cmux.CMux, error) {
 	if s.separatePorts { // use separate ports and listeners each for gRPC and HTTP requests
 		var err error
-		s.grpcConn, err = net.Listen("tcp", s.queryOptions.GRPCHostPort)
+		s.grpcConn, err = netutils.Listen(s.queryOptions.GRPCAddressFamily, s.queryOptions.GRPCHostPort)
 		if err != nil {
 			return nil, err
 		}
 
-		s.httpConn, err = net.Listen("tcp", s.queryOptions.HTTPHostPort)
+		s.httpConn, err = netutils.Listen(s.queryOptions.HTTPAddressFamily, s.queryOptions.HTTPHostPort)
 		if err != nil {
 			return nil, err
 		}
@@ -242,7 +313,7 @@ func (s *Server) initListener() (cmux.CMux, error) {
 	}
 
 	//  old behavior using cmux
-	conn, err := net.Listen("tcp", s.queryOptions.HTTPHostPort)
+	conn, err := netutils.Listen(s.queryOptions.HTTPAddressFamily, s.queryOptions.HTTPHostPort)
 	if err != nil {
 		return nil, err
 	}
@@ -355,5 +426,8 @@ func (s *Server) Close() error {
 		s.cmuxServer.Close()
 	}
 	s.bgFinished.Wait()
+	if s.auditLogger != nil {
+		errs = append(errs, s.auditLogger.Close())
+	}
 	return errors.Join(errs...)
 }
@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -29,16 +32,21 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
+	"github.com/jaegertracing/jaeger/cmd/query/app/audit"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/internal/grpctest"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
+	"github.com/jaegertracing/jaeger/pkg/oidc"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/ports"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
@@ -595,6 +603,41 @@ func TestServerInUseHostPort(t *testing.T) {
 	}
 }
 
+func freeHostPort(t *testing.T, network, hostPort string) string {
+	l, err := net.Listen(network, hostPort)
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestServerAddressFamilies(t *testing.T) {
+	server, err := NewServer(zap.NewNop(), healthcheck.New(), &querysvc.QueryService{}, nil,
+		&QueryOptions{
+			HTTPHostPort:      freeHostPort(t, "tcp6", "[::1]:0"),
+			HTTPAddressFamily: netutils.IPv6,
+			GRPCHostPort:      freeHostPort(t, "tcp4", "127.0.0.1:0"),
+			GRPCAddressFamily: netutils.IPv4,
+		},
+		tenancy.NewManager(&tenancy.Options{}),
+		jtracer.NoOp())
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Close()
+	assert.Contains(t, server.httpConn.Addr().String(), "[::1]:")
+	assert.Contains(t, server.grpcConn.Addr().String(), "127.0.0.1:")
+
+	_, err = NewServer(zap.NewNop(), healthcheck.New(), &querysvc.QueryService{}, nil,
+		&QueryOptions{
+			HTTPHostPort:      ":16686",
+			HTTPAddressFamily: netutils.IPv6,
+			GRPCHostPort:      ":16686",
+			GRPCAddressFamily: netutils.IPv4,
+		},
+		tenancy.NewManager(&tenancy.Options{}),
+		jtracer.NoOp())
+	require.ErrorContains(t, err, "can not use different address families for gRPC and HTTP on the same host port")
+}
+
 func TestServerSinglePort(t *testing.T) {
 	flagsSvc := flags.NewService(ports.QueryAdminHTTP)
 	flagsSvc.Logger = zap.NewNop()
@@ -768,3 +811,69 @@ func TestServerHTTPTenancy(t *testing.T) {
 	}
 	server.Close()
 }
+
+func TestServerHTTPOIDC(t *testing.T) {
+	provider := httptest.NewServer(http.NotFoundHandler())
+	defer provider.Close()
+	serverOptions := &QueryOptions{
+		OIDC: oidc.Options{Enabled: true, IssuerURL: provider.URL, ClientID: "jaeger"},
+	}
+	querySvc := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	server, err := createHTTPServer(querySvc, nil, serverOptions, tenancy.NewManager(&tenancy.Options{}),
+		oidc.NewVerifier(serverOptions.OIDC, provider.Client()), nil, jtracer.NoOp(), zap.NewNop())
+	require.NoError(t, err)
+	defer server.Close()
+
+	for _, path := range []string{"/api/services", "/"} {
+		w := httptest.NewRecorder()
+		server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+	}
+
+	serverOptions.OIDC.RedirectURL = "://invalid"
+	_, err = createHTTPServer(querySvc, nil, serverOptions, tenancy.NewManager(&tenancy.Options{}),
+		oidc.NewVerifier(serverOptions.OIDC, provider.Client()), nil, jtracer.NoOp(), zap.NewNop())
+	require.ErrorContains(t, err, "invalid OIDC redirect URL")
+}
+
+func TestServerHTTPAudit(t *testing.T) {
+	auditFile := filepath.Join(t.TempDir(), "audit.log")
+	serverOptions := &QueryOptions{Audit: audit.Options{File: auditFile, SamplingRate: 1}}
+	auditLogger, err := audit.NewLogger(serverOptions.Audit, zap.NewNop())
+	require.NoError(t, err)
+	spanReader := &spanstoremocks.Reader{}
+	spanReader.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil)
+	querySvc := querysvc.NewQueryService(spanReader, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	server, err := createHTTPServer(querySvc, nil, serverOptions, tenancy.NewManager(&tenancy.Options{}),
+		nil, auditLogger, jtracer.NoOp(), zap.NewNop())
+	require.NoError(t, err)
+	defer server.Close()
+
+	w := httptest.NewRecorder()
+	server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/services", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, auditLogger.Close())
+
+	data, err := os.ReadFile(auditFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"endpoint":"GET /api/services"`)
+}
+
+func TestServerGRPCOIDC(t *testing.T) {
+	serverOptions := &QueryOptions{
+		HTTPHostPort: ":0",
+		GRPCHostPort: ":0",
+		OIDC:         oidc.Options{Enabled: true, IssuerURL: "http://localhost:1", ClientID: "jaeger"},
+	}
+	querySvc := querysvc.NewQueryService(&spanstoremocks.Reader{}, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	server, err := NewServer(zap.NewNop(), healthcheck.New(), querySvc, nil, serverOptions, tenancy.NewManager(&tenancy.Options{}), jtracer.NoOp())
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Close()
+
+	conn, err := grpc.Dial(server.conn.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = api_v2.NewQueryServiceClient(conn).GetServices(context.Background(), &api_v2.GetServicesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/cmd/query/app/ui"
 	"github.com/jaegertracing/jaeger/pkg/fswatcher"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 )
 
@@ -46,10 +47,12 @@ var (
 )
 
 // RegisterStaticHandler adds handler for static assets to the router.
-func RegisterStaticHandler(r *mux.Router, logger *zap.Logger, qOpts *QueryOptions, qCapabilities querysvc.StorageCapabilities) io.Closer {
+func RegisterStaticHandler(r *mux.Router, logger *zap.Logger, qOpts *QueryOptions, qCapabilities querysvc.StorageCapabilities, tm *tenancy.Manager) io.Closer {
 	staticHandler, err := NewStaticAssetsHandler(qOpts.StaticAssets.Path, StaticAssetsHandlerOptions{
 		BasePath:            qOpts.BasePath,
 		UIConfigPath:        qOpts.UIConfig,
+		TenantUIConfigsPath: qOpts.TenantUIConfigs,
+		TenancyMgr:          tm,
 		StorageCapabilities: qCapabilities,
 		Logger:              logger,
 		LogAccess:           qOpts.StaticAssets.LogAccess,
@@ -65,16 +68,19 @@ func RegisterStaticHandler(r *mux.Router, logger *zap.Logger, qOpts *QueryOption
 
 // StaticAssetsHandler handles static assets
 type StaticAssetsHandler struct {
-	options   StaticAssetsHandlerOptions
-	indexHTML atomic.Value // stores []byte
-	assetsFS  http.FileSystem
-	watcher   *fswatcher.FSWatcher
+	options         StaticAssetsHandlerOptions
+	indexHTML       atomic.Value // stores []byte
+	tenantIndexHTML atomic.Value // stores *tenantIndexHTML, nil if the UI configuration is not in JSON format
+	assetsFS        http.FileSystem
+	watcher         *fswatcher.FSWatcher
 }
 
 // StaticAssetsHandlerOptions defines options for NewStaticAssetsHandler
 type StaticAssetsHandlerOptions struct {
 	BasePath            string
 	UIConfigPath        string
+	TenantUIConfigsPath string
+	TenancyMgr          *tenancy.Manager
 	LogAccess           bool
 	StorageCapabilities querysvc.StorageCapabilities
 	Logger              *zap.Logger
@@ -83,6 +89,8 @@ type StaticAssetsHandlerOptions struct {
 type loadedConfig struct {
 	regexp *regexp.Regexp
 	config []byte
+	// object is the UI configuration in JSON format, which can be resolved for each tenant
+	object map[string]any
 }
 
 // NewStaticAssetsHandler returns a StaticAssetsHandler
@@ -101,34 +109,66 @@ func NewStaticAssetsHandler(staticAssetsRoot string, options StaticAssetsHandler
 		assetsFS: assetsFS,
 	}
 
-	indexHTML, err := h.loadAndEnrichIndexHTML(assetsFS.Open)
+	indexHTML, tenantIndexHTML, err := h.loadAndEnrichIndexHTML(assetsFS.Open)
 	if err != nil {
 		return nil, err
 	}
 
-	options.Logger.Info("Using UI configuration", zap.String("path", options.UIConfigPath))
-	watcher, err := fswatcher.New([]string{options.UIConfigPath}, h.reloadUIConfig, h.options.Logger)
+	options.Logger.Info("Using UI configuration", zap.String("path", options.UIConfigPath), zap.String("tenants-path", options.TenantUIConfigsPath))
+	watcher, err := fswatcher.New([]string{options.UIConfigPath, options.TenantUIConfigsPath}, h.reloadUIConfig, h.options.Logger)
 	if err != nil {
 		return nil, err
 	}
 	h.watcher = watcher
 
 	h.indexHTML.Store(indexHTML)
+	h.tenantIndexHTML.Store(tenantIndexHTML)
 
 	return h, nil
 }
 
-func (sH *StaticAssetsHandler) loadAndEnrichIndexHTML(open func(string) (http.File, error)) ([]byte, error) {
+// loadAndEnrichIndexHTML returns index.html with the default UI configuration, and its renderer with
+// the UI configuration of each tenant if the UI configuration is in JSON format.
+func (sH *StaticAssetsHandler) loadAndEnrichIndexHTML(open func(string) (http.File, error)) ([]byte, *tenantIndexHTML, error) {
 	indexBytes, err := loadIndexHTML(open)
 	if err != nil {
-		return nil, fmt.Errorf("cannot load index.html: %w", err)
+		return nil, nil, fmt.Errorf("cannot load index.html: %w", err)
+	}
+	indexBytes, err = sH.enrichIndexHTML(indexBytes)
+	if err != nil {
+		return nil, nil, err
 	}
 	// replace UI config
-	if configObject, err := loadUIConfig(sH.options.UIConfigPath); err != nil {
-		return nil, err
-	} else if configObject != nil {
-		indexBytes = configObject.regexp.ReplaceAll(indexBytes, configObject.config)
+	configObject, err := loadUIConfig(sH.options.UIConfigPath)
+	if err != nil {
+		return nil, nil, err
 	}
+	tenantConfigs, err := loadTenantUIConfigs(sH.options.TenantUIConfigsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tenantConfigs != nil && configObject == nil {
+		configObject = &loadedConfig{object: map[string]any{}}
+	}
+	switch {
+	case configObject == nil:
+		return indexBytes, nil, nil
+	case configObject.object == nil:
+		if tenantConfigs != nil {
+			return nil, nil, fmt.Errorf("the UI configs of the tenants require a UI config file in JSON format: %v", sH.options.UIConfigPath)
+		}
+		return configObject.regexp.ReplaceAllLiteral(indexBytes, configObject.config), nil, nil
+	default:
+		tenantIndexHTML := &tenantIndexHTML{
+			template: indexBytes,
+			config:   configObject.object,
+			overlays: tenantConfigs,
+		}
+		return tenantIndexHTML.render(""), tenantIndexHTML, nil
+	}
+}
+
+func (sH *StaticAssetsHandler) enrichIndexHTML(indexBytes []byte) ([]byte, error) {
 	// replace storage capabilities
 	capabilitiesJSON, _ := json.Marshal(sH.options.StorageCapabilities)
 	capabilitiesString := fmt.Sprintf("JAEGER_STORAGE_CAPABILITIES = %s;", string(capabilitiesJSON))
@@ -153,11 +193,12 @@ func (sH *StaticAssetsHandler) loadAndEnrichIndexHTML(open func(string) (http.Fi
 
 func (sH *StaticAssetsHandler) reloadUIConfig() {
 	sH.options.Logger.Info("reloading UI config", zap.String("filename", sH.options.UIConfigPath))
-	content, err := sH.loadAndEnrichIndexHTML(sH.assetsFS.Open)
+	content, tenantContent, err := sH.loadAndEnrichIndexHTML(sH.assetsFS.Open)
 	if err != nil {
 		sH.options.Logger.Error("error while reloading the UI config", zap.Error(err))
 	}
 	sH.indexHTML.Store(content)
+	sH.tenantIndexHTML.Store(tenantContent)
 	sH.options.Logger.Info("reloaded UI config", zap.String("filename", sH.options.UIConfigPath))
 }
 
@@ -192,11 +233,11 @@ func loadUIConfig(uiConfig string) (*loadedConfig, error) {
 		if err := json.Unmarshal(bytesConfig, &c); err != nil {
 			return nil, fmt.Errorf("cannot parse UI config file %v: %w", uiConfig, err)
 		}
-		r, _ = json.Marshal(c)
 
 		return &loadedConfig{
 			regexp: configPattern,
-			config: append([]byte("JAEGER_CONFIG = "), append(r, byte(';'))...),
+			config: uiConfigScript(c),
+			object: c,
 		}, nil
 	case ".js":
 		r = bytes.TrimSpace(bytesConfig)
@@ -237,6 +278,15 @@ func (sH *StaticAssetsHandler) RegisterRoutes(router *mux.Router) {
 
 func (sH *StaticAssetsHandler) notFound(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	tm := sH.options.TenancyMgr
+	if tenantIndexHTML := sH.tenantIndexHTML.Load().(*tenantIndexHTML); tenantIndexHTML != nil && tm != nil && tm.Enabled {
+		// the UI configuration depends on the tenant
+		w.Header().Add("Vary", tm.Header)
+		if tenant := r.Header.Get(tm.Header); tenant != "" && tm.Valid(tenant) {
+			w.Write(tenantIndexHTML.render(tenant))
+			return
+		}
+	}
 	w.Write(sH.indexHTML.Load().([]byte))
 }
 
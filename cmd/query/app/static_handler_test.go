@@ -34,6 +34,7 @@ import (
 	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 )
 
@@ -60,6 +61,7 @@ func TestRegisterStaticHandlerPanic(t *testing.T) {
 				},
 			},
 			querysvc.StorageCapabilities{ArchiveStorage: false},
+			tenancy.NewManager(&tenancy.Options{}),
 		)
 		defer closer.Close()
 	})
@@ -87,7 +89,7 @@ func TestRegisterStaticHandler(t *testing.T) {
 			logAccess:                   true,
 			UIConfigPath:                "",
 			expectedUIConfig:            "JAEGER_CONFIG=DEFAULT_CONFIG;",
-			expectedStorageCapabilities: `JAEGER_STORAGE_CAPABILITIES = {"archiveStorage":false};`,
+			expectedStorageCapabilities: `JAEGER_STORAGE_CAPABILITIES = {"archiveStorage":false,"tagSuggestions":false};`,
 		},
 		{
 			basePath:                    "/",
@@ -96,7 +98,7 @@ func TestRegisterStaticHandler(t *testing.T) {
 			expectedBaseHTML:            `<base href="/"`,
 			UIConfigPath:                "fixture/ui-config.json",
 			expectedUIConfig:            `JAEGER_CONFIG = {"x":"y"};`,
-			expectedStorageCapabilities: `JAEGER_STORAGE_CAPABILITIES = {"archiveStorage":false};`,
+			expectedStorageCapabilities: `JAEGER_STORAGE_CAPABILITIES = {"archiveStorage":false,"tagSuggestions":false};`,
 		},
 		{
 			basePath:                    "/jaeger",
@@ -106,7 +108,7 @@ func TestRegisterStaticHandler(t *testing.T) {
 			archiveStorage:              true,
 			UIConfigPath:                "fixture/ui-config.js",
 			expectedUIConfig:            "function UIConfig(){",
-			expectedStorageCapabilities: `JAEGER_STORAGE_CAPABILITIES = {"archiveStorage":true};`,
+			expectedStorageCapabilities: `JAEGER_STORAGE_CAPABILITIES = {"archiveStorage":true,"tagSuggestions":false};`,
 		},
 	}
 	httpClient = &http.Client{
@@ -130,6 +132,7 @@ func TestRegisterStaticHandler(t *testing.T) {
 				},
 			},
 				querysvc.StorageCapabilities{ArchiveStorage: testCase.archiveStorage},
+				tenancy.NewManager(&tenancy.Options{}),
 			)
 			defer closer.Close()
 
@@ -256,21 +259,24 @@ func TestLoadUIConfig(t *testing.T) {
 		expected: &loadedConfig{
 			config: []byte(`JAEGER_CONFIG = {"x":"y"};`),
 			regexp: configPattern,
+			object: map[string]any{"x": "y"},
 		},
 	})
-	c, _ := json.Marshal(map[string]interface{}{
+	menu := map[string]interface{}{
 		"menu": []interface{}{
 			map[string]interface{}{
 				"label": "GitHub",
 				"url":   "https://github.com/jaegertracing/jaeger",
 			},
 		},
-	})
+	}
+	c, _ := json.Marshal(menu)
 	run("json-menu", testCase{
 		configFile: "fixture/ui-config-menu.json",
 		expected: &loadedConfig{
 			config: append([]byte("JAEGER_CONFIG = "), append(c, byte(';'))...),
 			regexp: configPattern,
+			object: menu,
 		},
 	})
 	run("malformed js config", testCase{
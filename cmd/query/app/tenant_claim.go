@@ -0,0 +1,92 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/oidc"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+const (
+	msgMissingTenantClaim = "missing tenant claim"
+	msgTenantMismatch     = "tenant header does not match the tenant of the token"
+)
+
+// tenantFromClaim returns the tenant found in the claim of the OIDC token. A tenant requested
+// in the header must be the same, so that a user cannot read the data of another tenant.
+func tenantFromClaim(ctx context.Context, claim string, requested []string) (string, string) {
+	tenant := oidc.GetClaims(ctx).String(claim)
+	if tenant == "" {
+		return "", msgMissingTenantClaim
+	}
+	for _, r := range requested {
+		if r != tenant {
+			return "", msgTenantMismatch
+		}
+	}
+	return tenant, ""
+}
+
+// tenantClaimHTTPHandler replaces the tenant header of the request with the tenant of the
+// authenticated user, to be validated by tenancy.ExtractTenantHTTPHandler.
+func tenantClaimHTTPHandler(tm *tenancy.Manager, claim string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, msg := tenantFromClaim(r.Context(), claim, r.Header.Values(tm.Header))
+		if msg != "" {
+			http.Error(w, msg, http.StatusForbidden)
+			return
+		}
+		r.Header.Set(tm.Header, tenant)
+		h.ServeHTTP(w, r)
+	})
+}
+
+func tenantClaimContext(ctx context.Context, tm *tenancy.Manager, claim string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tenant, msg := tenantFromClaim(ctx, claim, md.Get(tm.Header))
+	if msg != "" {
+		return nil, status.Error(codes.PermissionDenied, msg)
+	}
+	return tenancy.WithTenant(ctx, tenant), nil
+}
+
+// tenantServerStream is a wrapper for ServerStream providing the context with the tenant
+type tenantServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// newTenantClaimUnaryInterceptor stores the tenant of the authenticated user in the request context.
+func newTenantClaimUnaryInterceptor(tm *tenancy.Manager, claim string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := tenantClaimContext(ctx, tm, claim)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// newTenantClaimStreamInterceptor stores the tenant of the authenticated user in the stream context.
+func newTenantClaimStreamInterceptor(tm *tenancy.Manager, claim string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := tenantClaimContext(ss.Context(), tm, claim)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tenantServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/oidc"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+func TestTenantClaimHTTPHandler(t *testing.T) {
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true, Header: "x-tenant"})
+	var tenant string
+	handler := tenantClaimHTTPHandler(tm, "org", tenancy.ExtractTenantHTTPHandler(tm,
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			tenant = tenancy.GetTenant(r.Context())
+		})))
+	serve := func(claims oidc.Claims, header string) int {
+		tenant = ""
+		req := httptest.NewRequest(http.MethodGet, "/api/traces", nil)
+		if header != "" {
+			req.Header.Set("x-tenant", header)
+		}
+		req = req.WithContext(oidc.ContextWithClaims(req.Context(), claims))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(oidc.Claims{"org": "acme"}, ""))
+	assert.Equal(t, "acme", tenant)
+	assert.Equal(t, http.StatusOK, serve(oidc.Claims{"org": "acme"}, "acme"))
+	assert.Equal(t, "acme", tenant)
+	assert.Equal(t, http.StatusForbidden, serve(oidc.Claims{"org": "acme"}, "other"))
+	assert.Empty(t, tenant)
+	assert.Equal(t, http.StatusForbidden, serve(oidc.Claims{}, "acme"))
+	assert.Empty(t, tenant)
+}
+
+func TestTenantClaimUnaryInterceptor(t *testing.T) {
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true, Header: "x-tenant"})
+	interceptor := newTenantClaimUnaryInterceptor(tm, "org")
+	getTenant := func(ctx context.Context) (string, error) {
+		var tenant string
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			tenant = tenancy.GetTenant(ctx)
+			return nil, nil
+		})
+		return tenant, err
+	}
+	ctx := oidc.ContextWithClaims(context.Background(), oidc.Claims{"org": "acme"})
+
+	tenant, err := getTenant(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+
+	_, err = getTenant(metadata.NewIncomingContext(ctx, metadata.Pairs("x-tenant", "other")))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = getTenant(context.Background())
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestTenantClaimStreamInterceptor(t *testing.T) {
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true, Header: "x-tenant"})
+	interceptor := newTenantClaimStreamInterceptor(tm, "org")
+	ctx := oidc.ContextWithClaims(context.Background(), oidc.Claims{"org": "acme"})
+	var tenant string
+	err := interceptor(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(_ interface{}, ss grpc.ServerStream) error {
+		tenant = tenancy.GetTenant(ss.Context())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+
+	err = interceptor(nil, &testServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, nil)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	model2otel "github.com/jaegertracing/jaeger/model/converter/otlp"
+)
+
+const (
+	formatParam = "format"
+
+	exportFormatJSON = "json"
+	exportFormatOTLP = "otlp"
+	exportFormatCSV  = "csv"
+
+	// otlpContentType is the media type of the OTLP export, one OTLP JSON TracesData per line and per trace,
+	// the format of the file exporter and of the otlpjson file receiver of the OpenTelemetry Collector.
+	otlpContentType = "application/x-ndjson"
+	csvContentType  = "text/csv"
+)
+
+var csvHeader = []string{
+	"trace_id",
+	"span_id",
+	"parent_span_id",
+	"service_name",
+	"operation_name",
+	"span_kind",
+	"start_time",
+	"duration_us",
+	"error",
+	"tags",
+}
+
+// parseExportFormat returns the format of the trace search results, from the format parameter,
+// or from the Accept header if the parameter is not set. The default format is the UI JSON.
+// The values of the parameter are validated against the OpenAPI document.
+func parseExportFormat(r *http.Request) string {
+	if format := r.FormValue(formatParam); format != "" {
+		return format
+	}
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(accepted)
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case otlpContentType:
+			return exportFormatOTLP
+		case csvContentType:
+			return exportFormatCSV
+		}
+	}
+	return exportFormatJSON
+}
+
+// exportTraces streams the traces in the format, flushing the response after every trace so that
+// large results are not buffered. The traces are exported as stored, without the UI adjustments.
+func (aH *APIHandler) exportTraces(w http.ResponseWriter, format string, traces []*model.Trace) {
+	var writeTrace func(*model.Trace) error
+	switch format {
+	case exportFormatOTLP:
+		w.Header().Set("Content-Type", otlpContentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="traces.jsonl"`)
+		writeTrace = func(trace *model.Trace) error {
+			return writeOTLPTrace(w, trace)
+		}
+	default:
+		w.Header().Set("Content-Type", csvContentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="traces.csv"`)
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			aH.logger.Error("Failed to export traces", zap.Error(err))
+			return
+		}
+		writeTrace = func(trace *model.Trace) error {
+			return writeCSVTrace(cw, trace)
+		}
+	}
+	flusher, _ := w.(http.Flusher)
+	for _, trace := range traces {
+		// the status is already sent, the response is truncated on errors
+		if err := writeTrace(trace); err != nil {
+			aH.logger.Error("Failed to export traces", zap.Error(err))
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+func writeOTLPTrace(w io.Writer, trace *model.Trace) error {
+	td, err := model2otel.ProtoToTraces([]*model.Batch{{Spans: trace.Spans}})
+	if err != nil {
+		return fmt.Errorf("cannot convert trace to OTLP: %w", err)
+	}
+	data, err := new(ptrace.JSONMarshaler).MarshalTraces(td)
+	if err != nil {
+		return fmt.Errorf("cannot marshal OTLP traces: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func writeCSVTrace(cw *csv.Writer, trace *model.Trace) error {
+	for _, span := range trace.Spans {
+		var service string
+		if span.Process != nil {
+			service = span.Process.ServiceName
+		}
+		var parentSpanID string
+		if parentID := span.ParentSpanID(); parentID != model.NewSpanID(0) {
+			parentSpanID = parentID.String()
+		}
+		var kind string
+		if spanKind, ok := span.GetSpanKind(); ok {
+			kind = spanKind.String()
+		}
+		tags := make([]string, 0, len(span.Tags))
+		for _, tag := range span.Tags {
+			tags = append(tags, tag.Key+"="+tag.AsString())
+		}
+		err := cw.Write([]string{
+			span.TraceID.String(),
+			span.SpanID.String(),
+			parentSpanID,
+			service,
+			span.OperationName,
+			kind,
+			span.StartTime.UTC().Format(time.RFC3339Nano),
+			strconv.FormatUint(model.DurationAsMicroseconds(span.Duration), 10),
+			strconv.FormatBool(hasErrorTag(span)),
+			strings.Join(tags, ";"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func hasErrorTag(span *model.Span) bool {
+	tag, ok := model.KeyValues(span.Tags).FindByKey("error")
+	return ok && tag.Bool()
+}
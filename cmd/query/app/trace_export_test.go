@@ -0,0 +1,131 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bufio"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var exportTrace = &model.Trace{
+	Spans: []*model.Span{
+		{
+			TraceID:       model.NewTraceID(0, 1),
+			SpanID:        model.NewSpanID(1),
+			OperationName: "GET /",
+			StartTime:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Duration:      1500 * time.Microsecond,
+			Process:       &model.Process{ServiceName: "frontend"},
+			Tags:          model.KeyValues{model.String("span.kind", "server"), model.Bool("error", true)},
+		},
+		{
+			TraceID:       model.NewTraceID(0, 1),
+			SpanID:        model.NewSpanID(2),
+			OperationName: "query",
+			References:    []model.SpanRef{model.NewChildOfRef(model.NewTraceID(0, 1), model.NewSpanID(1))},
+			StartTime:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Duration:      time.Millisecond,
+			Process:       &model.Process{ServiceName: "db"},
+			Tags:          model.KeyValues{model.String("db.system", "mysql")},
+		},
+	},
+}
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		url    string
+		accept string
+		format string
+	}{
+		{url: "/api/traces", format: exportFormatJSON},
+		{url: "/api/traces", accept: "application/json", format: exportFormatJSON},
+		{url: "/api/traces", accept: "text/csv; charset=utf-8", format: exportFormatCSV},
+		{url: "/api/traces", accept: "application/json;q=0.9, application/x-ndjson", format: exportFormatOTLP},
+		{url: "/api/traces?format=csv", accept: "application/x-ndjson", format: exportFormatCSV},
+		{url: "/api/traces?format=otlp", format: exportFormatOTLP},
+		{url: "/api/traces?format=json", accept: "text/csv", format: exportFormatJSON},
+	}
+	for _, test := range tests {
+		t.Run(test.url+" "+test.accept, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, test.url, nil)
+			r.Header.Set("Accept", test.accept)
+			assert.Equal(t, test.format, parseExportFormat(r))
+		})
+	}
+}
+
+func TestSearchExportCSV(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{exportTrace}, nil).Once()
+
+	resp, err := http.Get(ts.server.URL + `/api/traces?service=frontend&format=csv`)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, csvContentType, resp.Header.Get("Content-Type"))
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		csvHeader,
+		{
+			"0000000000000001", "0000000000000001", "", "frontend", "GET /", "server",
+			"2024-01-02T03:04:05Z", "1500", "true", "span.kind=server;error=true",
+		},
+		{
+			"0000000000000001", "0000000000000002", "0000000000000001", "db", "query", "",
+			"2024-01-02T03:04:05Z", "1000", "false", "db.system=mysql",
+		},
+	}, records)
+}
+
+func TestSearchExportOTLP(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	otherTrace := &model.Trace{Spans: []*model.Span{
+		{TraceID: model.NewTraceID(0, 2), SpanID: model.NewSpanID(1), Process: &model.Process{ServiceName: "frontend"}},
+	}}
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{exportTrace, otherTrace}, nil).Once()
+
+	req, err := http.NewRequest(http.MethodGet, ts.server.URL+`/api/traces?service=frontend`, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", otlpContentType)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, otlpContentType, resp.Header.Get("Content-Type"))
+
+	var spanCounts []int
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		td, err := new(ptrace.JSONUnmarshaler).UnmarshalTraces(scanner.Bytes())
+		require.NoError(t, err)
+		spanCounts = append(spanCounts, td.SpanCount())
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []int{2, 1}, spanCounts)
+}
+
+func TestSearchExportInvalidFormat(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+`/api/traces?service=frontend&format=xml`, &response)
+	require.ErrorContains(t, err, "400 error from server")
+}
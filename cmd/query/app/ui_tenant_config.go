@@ -0,0 +1,103 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// tenantVariable is replaced in the strings of the UI configuration with the tenant of the request,
+// e.g. in the URLs of the menu items or of the link patterns. The variables of the traces and spans
+// of the link patterns, e.g. #{trace.traceID}, are resolved by the UI itself.
+const tenantVariable = "${tenant}"
+
+// tenantUIConfigs holds the overlays of the UI configuration of each tenant.
+type tenantUIConfigs map[string]map[string]any
+
+func loadTenantUIConfigs(path string) (tenantUIConfigs, error) {
+	if path == "" {
+		return nil, nil
+	}
+	bytesConfig, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the tenant UI configs file %v: %w", path, err)
+	}
+	var configs tenantUIConfigs
+	if err := json.Unmarshal(bytesConfig, &configs); err != nil {
+		return nil, fmt.Errorf("cannot parse the tenant UI configs file %v: %w", path, err)
+	}
+	return configs, nil
+}
+
+// tenantIndexHTML renders index.html with the UI configuration of each tenant.
+type tenantIndexHTML struct {
+	// template is index.html before the UI configuration is set
+	template []byte
+	config   map[string]any
+	overlays tenantUIConfigs
+}
+
+func (t *tenantIndexHTML) render(tenant string) []byte {
+	config := resolveUIConfig(t.config, t.overlays[tenant], tenant)
+	return configPattern.ReplaceAllLiteral(t.template, uiConfigScript(config))
+}
+
+func uiConfigScript(config map[string]any) []byte {
+	r, _ := json.Marshal(config)
+	return append([]byte("JAEGER_CONFIG = "), append(r, byte(';'))...)
+}
+
+// resolveUIConfig returns the UI configuration of a tenant, i.e. its overlay merged over the
+// default configuration, with the tenant variable replaced.
+func resolveUIConfig(config, overlay map[string]any, tenant string) map[string]any {
+	return expandTenant(mergeUIConfig(config, overlay), tenant).(map[string]any)
+}
+
+// mergeUIConfig merges an overlay over a UI configuration like a JSON merge patch (RFC 7386):
+// the objects are merged recursively, the null values remove the keys, and the other values,
+// including the arrays such as the menu, replace the ones of the configuration.
+func mergeUIConfig(config, overlay map[string]any) map[string]any {
+	merged := make(map[string]any, len(config)+len(overlay))
+	for key, value := range config {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		switch value := value.(type) {
+		case nil:
+			delete(merged, key)
+		case map[string]any:
+			object, _ := merged[key].(map[string]any)
+			merged[key] = mergeUIConfig(object, value)
+		default:
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// expandTenant returns a copy of a JSON value with the tenant variable replaced in all strings.
+func expandTenant(value any, tenant string) any {
+	switch value := value.(type) {
+	case string:
+		return strings.ReplaceAll(value, tenantVariable, tenant)
+	case []any:
+		expanded := make([]any, len(value))
+		for i, v := range value {
+			expanded[i] = expandTenant(v, tenant)
+		}
+		return expanded
+	case map[string]any:
+		expanded := make(map[string]any, len(value))
+		for k, v := range value {
+			expanded[k] = expandTenant(v, tenant)
+		}
+		return expanded
+	default:
+		return value
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+func TestLoadTenantUIConfigs(t *testing.T) {
+	configs, err := loadTenantUIConfigs("")
+	require.NoError(t, err)
+	assert.Nil(t, configs)
+
+	configs, err = loadTenantUIConfigs("fixture/ui-tenant-configs.json")
+	require.NoError(t, err)
+	assert.Contains(t, configs, "acme")
+
+	_, err = loadTenantUIConfigs("invalid")
+	require.EqualError(t, err, "cannot read the tenant UI configs file invalid: open invalid: no such file or directory")
+
+	_, err = loadTenantUIConfigs("fixture/ui-config-menu.json")
+	require.ErrorContains(t, err, "cannot parse the tenant UI configs file fixture/ui-config-menu.json")
+}
+
+func TestMergeUIConfig(t *testing.T) {
+	config := map[string]any{
+		"menu":     []any{"a", "b"},
+		"tracking": map[string]any{"gaID": "UA-1", "trackErrors": true},
+		"search":   map[string]any{"maxLimit": 1500.0},
+	}
+	merged := mergeUIConfig(config, map[string]any{
+		"menu":         []any{"c"},
+		"tracking":     map[string]any{"gaID": "UA-2"},
+		"search":       nil,
+		"dependencies": map[string]any{"menuEnabled": false, "dagMaxNumServices": nil},
+	})
+	assert.Equal(t, map[string]any{
+		"menu":         []any{"c"},
+		"tracking":     map[string]any{"gaID": "UA-2", "trackErrors": true},
+		"dependencies": map[string]any{"menuEnabled": false},
+	}, merged)
+	// the configuration is not modified
+	assert.Equal(t, "UA-1", config["tracking"].(map[string]any)["gaID"])
+	assert.Len(t, config, 3)
+}
+
+func TestResolveUIConfig(t *testing.T) {
+	config := map[string]any{
+		"menu": []any{
+			map[string]any{"label": "Wiki", "url": "https://wiki.example.com/${tenant}", "anchorTarget": "_blank"},
+		},
+		"archiveEnabled": true,
+	}
+	assert.Equal(t, map[string]any{
+		"menu": []any{
+			map[string]any{"label": "Wiki", "url": "https://wiki.example.com/acme", "anchorTarget": "_blank"},
+		},
+		"archiveEnabled": true,
+	}, resolveUIConfig(config, nil, "acme"))
+	assert.Equal(t, "https://wiki.example.com/${tenant}", config["menu"].([]any)[0].(map[string]any)["url"])
+}
+
+func TestStaticHandlerTenantUIConfigs(t *testing.T) {
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true, Tenants: []string{"acme", "globex"}})
+	h, err := NewStaticAssetsHandler("fixture", StaticAssetsHandlerOptions{
+		UIConfigPath:        "fixture/ui-config-menu.json",
+		TenantUIConfigsPath: "fixture/ui-tenant-configs.json",
+		TenancyMgr:          tm,
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	get := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/search", nil)
+		if tenant != "" {
+			req.Header.Set(tm.Header, tenant)
+		}
+		w := httptest.NewRecorder()
+		h.notFound(w, req)
+		return w
+	}
+
+	w := get("acme")
+	assert.Equal(t, tm.Header, w.Header().Get("Vary"))
+	assert.Contains(t, w.Body.String(), `"label":"Dashboards of acme","url":"https://grafana.example.com/d/acme"`)
+	assert.Contains(t, w.Body.String(), `"url":"https://runbooks.example.com/acme?trace=#{trace.traceID}"`)
+	assert.NotContains(t, w.Body.String(), "GitHub")
+
+	for _, tenant := range []string{"globex", "unknown", ""} {
+		w = get(tenant)
+		assert.Contains(t, w.Body.String(), `JAEGER_CONFIG = {"menu":[{"label":"GitHub","url":"https://github.com/jaegertracing/jaeger"}]};`, tenant)
+	}
+}
+
+func TestStaticHandlerTenantUIConfigsErrors(t *testing.T) {
+	_, err := NewStaticAssetsHandler("fixture", StaticAssetsHandlerOptions{
+		UIConfigPath:        "fixture/ui-config.js",
+		TenantUIConfigsPath: "fixture/ui-tenant-configs.json",
+	})
+	require.EqualError(t, err, "the UI configs of the tenants require a UI config file in JSON format: fixture/ui-config.js")
+
+	_, err = NewStaticAssetsHandler("fixture", StaticAssetsHandlerOptions{
+		TenantUIConfigsPath: "invalid",
+	})
+	require.ErrorContains(t, err, "cannot read the tenant UI configs file")
+}
+
+func TestStaticHandlerTenantUIConfigsWithoutUIConfig(t *testing.T) {
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true})
+	h, err := NewStaticAssetsHandler("fixture", StaticAssetsHandlerOptions{
+		TenantUIConfigsPath: "fixture/ui-tenant-configs.json",
+		TenancyMgr:          tm,
+	})
+	require.NoError(t, err)
+	defer h.Close()
+
+	assert.Contains(t, string(h.indexHTML.Load().([]byte)), "JAEGER_CONFIG = {};")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(tm.Header, "acme")
+	w := httptest.NewRecorder()
+	h.notFound(w, req)
+	assert.Contains(t, w.Body.String(), "Dashboards of acme")
+}
@@ -31,11 +31,13 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
 	"github.com/jaegertracing/jaeger/cmd/internal/status"
+	"github.com/jaegertracing/jaeger/cmd/internal/validate"
 	"github.com/jaegertracing/jaeger/cmd/query/app"
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/pkg/bearertoken"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/jtracer"
+	"github.com/jaegertracing/jaeger/pkg/lifecycle"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
@@ -81,7 +83,7 @@ func main() {
 
 			jt := jtracer.NoOp()
 			if queryOpts.EnableTracing {
-				jt, err = jtracer.New("jaeger-query")
+				jt, err = svc.OTLPTelemetry.NewTracer("jaeger-query", logger)
 				if err != nil {
 					logger.Fatal("Failed to create tracer", zap.Error(err))
 				}
@@ -90,7 +92,7 @@ func main() {
 			// TODO: Need to figure out set enable/disable propagation on storage plugins.
 			v.Set(bearertoken.StoragePropagationKey, queryOpts.BearerTokenPropagation)
 			storageFactory.InitFromViper(v, logger)
-			if err := storageFactory.Initialize(baseFactory, logger); err != nil {
+			if err := storageFactory.Initialize(baseFactory, logger.Named(flags.StorageLoggerName)); err != nil {
 				logger.Fatal("Failed to init storage factory", zap.Error(err))
 			}
 			spanReader, err := storageFactory.CreateSpanReader()
@@ -103,11 +105,16 @@ func main() {
 				logger.Fatal("Failed to create dependency reader", zap.Error(err))
 			}
 
+			// used when the metrics are computed from the span storage
+			metricsReaderFactory.SetSpanReader(spanReader)
 			metricsQueryService, err := createMetricsQueryService(metricsReaderFactory, v, logger, metricsFactory)
 			if err != nil {
 				logger.Fatal("Failed to create metrics query service", zap.Error(err))
 			}
-			queryServiceOptions := queryOpts.BuildQueryServiceOptions(storageFactory, logger)
+			queryServiceOptions, err := queryOpts.BuildQueryServiceOptions(storageFactory, logger)
+			if err != nil {
+				logger.Fatal("Failed to create query service options", zap.Error(err))
+			}
 			queryService := querysvc.NewQueryService(
 				spanReader,
 				dependencyReader,
@@ -118,19 +125,28 @@ func main() {
 				logger.Fatal("Failed to create server", zap.Error(err))
 			}
 
-			if err := server.Start(); err != nil {
+			// the servers complete the queries in flight before the storage is closed
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name: "storage",
+				Stop: lifecycle.Closer(storageFactory),
+			})
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name: "tracer",
+				Stop: jt.Close,
+			})
+			svc.Lifecycle.Add(lifecycle.Component{
+				Name:      "server",
+				DependsOn: []string{"storage", "tracer"},
+				Start: func(context.Context) error {
+					return server.Start()
+				},
+				Stop: lifecycle.Closer(server),
+			})
+			if err := svc.Lifecycle.Start(context.Background()); err != nil {
 				logger.Fatal("Could not start servers", zap.Error(err))
 			}
 
-			svc.RunAndThen(func() {
-				server.Close()
-				if err := storageFactory.Close(); err != nil {
-					logger.Error("Failed to close storage factory", zap.Error(err))
-				}
-				if err = jt.Close(context.Background()); err != nil {
-					logger.Fatal("Error shutting down tracer provider", zap.Error(err))
-				}
-			})
+			svc.RunAndThen(nil)
 			return nil
 		},
 	}
@@ -140,6 +156,14 @@ func main() {
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.QueryAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(validate.Command(v, func(v *viper.Viper, logger *zap.Logger) error {
+		if _, err := new(app.QueryOptions).InitFromViper(v, logger); err != nil {
+			return err
+		}
+		storageFactory.InitFromViper(v, logger)
+		metricsReaderFactory.InitFromViper(v, logger)
+		return nil
+	}))
 
 	config.AddFlags(
 		v,
@@ -0,0 +1,350 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
+)
+
+// Permission is a class of RPCs of the remote storage that clients can be authorized to call.
+type Permission string
+
+const (
+	// PermissionRead authorizes the RPCs reading the traces and the dependencies.
+	PermissionRead Permission = "read"
+	// PermissionWrite authorizes the RPCs writing the spans, including the archive and OTLP ones.
+	PermissionWrite Permission = "write"
+	// PermissionPurge authorizes the RPCs deleting the traces.
+	PermissionPurge Permission = "purge"
+)
+
+// publicServices are the gRPC services whose RPCs are allowed to all the clients.
+var publicServices = map[string]bool{
+	"grpc.health.v1.Health":                    true,
+	"grpc.reflection.v1.ServerReflection":      true,
+	"grpc.reflection.v1alpha.ServerReflection": true,
+	"jaeger.storage.v1.PluginCapabilities":     true,
+}
+
+// servicePermissions are the permissions required by the RPCs of the gRPC services.
+// The RPCs of the services that are neither public nor listed here are denied to all the clients,
+// so that the services registered later are not exposed until they are listed.
+var servicePermissions = map[string]Permission{
+	"jaeger.storage.v1.SpanReaderPlugin":                  PermissionRead,
+	"jaeger.storage.v1.ArchiveSpanReaderPlugin":           PermissionRead,
+	"jaeger.storage.v1.DependenciesReaderPlugin":          PermissionRead,
+	"jaeger.storage.v1.SpanWriterPlugin":                  PermissionWrite,
+	"jaeger.storage.v1.StreamingSpanWriterPlugin":         PermissionWrite,
+	"jaeger.storage.v1.ArchiveSpanWriterPlugin":           PermissionWrite,
+	"opentelemetry.proto.collector.trace.v1.TraceService": PermissionWrite,
+	"jaeger.storage.v1.PurgerPlugin":                      PermissionPurge,
+}
+
+// requiredPermission returns the permission required by a method, e.g. "/jaeger.storage.v1.SpanWriterPlugin/WriteSpan",
+// or false if the method is public. The methods of the unknown services require an empty permission,
+// which cannot be granted.
+func requiredPermission(method string) (Permission, bool) {
+	service, _, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if publicServices[service] {
+		return "", false
+	}
+	return servicePermissions[service], true
+}
+
+// Authorizer decides whether the clients of the remote storage are allowed to call its RPCs.
+type Authorizer interface {
+	// Authorize returns nil if the client of the request is allowed to call the method,
+	// e.g. "/jaeger.storage.v1.SpanWriterPlugin/WriteSpan". The errors that are not gRPC
+	// status errors are returned to the client with the PermissionDenied code.
+	Authorize(ctx context.Context, method string) error
+}
+
+// AuthorizerFunc is an Authorizer implemented by a function.
+type AuthorizerFunc func(ctx context.Context, method string) error
+
+// Authorize implements Authorizer.
+func (f AuthorizerFunc) Authorize(ctx context.Context, method string) error {
+	return f(ctx, method)
+}
+
+// errUnauthenticated is returned when the client does not present any credentials known to the authorizer.
+var errUnauthenticated = status.Error(codes.Unauthenticated, "the client is not authenticated")
+
+// permissions is a set of permissions.
+type permissions map[Permission]bool
+
+func newPermissions(list []Permission) (permissions, error) {
+	p := make(permissions, len(list))
+	for _, permission := range list {
+		switch permission {
+		case PermissionRead, PermissionWrite, PermissionPurge:
+			p[permission] = true
+		default:
+			return nil, fmt.Errorf("unknown permission %q", permission)
+		}
+	}
+	return p, nil
+}
+
+// authorize checks that the permissions grant the permission required by the method.
+func (p permissions) authorize(client, method string) error {
+	permission, required := requiredPermission(method)
+	if !required || p[permission] {
+		return nil
+	}
+	if permission == "" {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s, which is not allowed to any client", client, method)
+	}
+	return status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s, which requires the %s permission", client, method, permission)
+}
+
+type sanAuthorizer struct {
+	sans map[string]permissions
+}
+
+// NewSANAuthorizer creates an Authorizer granting permissions to the clients by the subject
+// alternative names (DNS names, URIs, e.g. SPIFFE IDs, email and IP addresses) of their TLS
+// client certificates. The certificates must be verified by the server, i.e. the server must
+// be configured with a client CA.
+func NewSANAuthorizer(sans map[string][]Permission) (Authorizer, error) {
+	a := &sanAuthorizer{sans: make(map[string]permissions, len(sans))}
+	for san, list := range sans {
+		p, err := newPermissions(list)
+		if err != nil {
+			return nil, fmt.Errorf("invalid permissions of SAN %q: %w", san, err)
+		}
+		a.sans[san] = p
+	}
+	return a, nil
+}
+
+// Authorize implements Authorizer.
+func (a *sanAuthorizer) Authorize(ctx context.Context, method string) error {
+	if _, required := requiredPermission(method); !required {
+		return nil
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return errUnauthenticated
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return errUnauthenticated
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	sans := append([]string{}, cert.DNSNames...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	var err error = errUnauthenticated
+	for _, san := range sans {
+		if perms, ok := a.sans[san]; ok {
+			// any SAN granting the permission is enough
+			if err = perms.authorize("client "+san, method); err == nil {
+				return nil
+			}
+		}
+	}
+	return err
+}
+
+type tokenAuthorizer struct {
+	tokens []tokenPermissions
+}
+
+type tokenPermissions struct {
+	token       []byte
+	permissions permissions
+}
+
+// NewTokenAuthorizer creates an Authorizer granting permissions to the clients by the bearer
+// tokens sent in the authorization metadata of the requests, e.g. "Bearer <token>", or in the
+// bearer.token metadata propagated by the gRPC storage clients.
+func NewTokenAuthorizer(tokens map[string][]Permission) (Authorizer, error) {
+	a := &tokenAuthorizer{}
+	for token, list := range tokens {
+		if token == "" {
+			return nil, errors.New("the tokens must not be empty")
+		}
+		p, err := newPermissions(list)
+		if err != nil {
+			return nil, fmt.Errorf("invalid permissions of a token: %w", err)
+		}
+		a.tokens = append(a.tokens, tokenPermissions{token: []byte(token), permissions: p})
+	}
+	return a, nil
+}
+
+// Authorize implements Authorizer.
+func (a *tokenAuthorizer) Authorize(ctx context.Context, method string) error {
+	if _, required := requiredPermission(method); !required {
+		return nil
+	}
+	token := bearerToken(ctx)
+	if token == "" {
+		return errUnauthenticated
+	}
+	for _, tp := range a.tokens {
+		// the tokens are compared in constant time, so that they cannot be guessed from the response times
+		if subtle.ConstantTimeCompare(tp.token, []byte(token)) == 1 {
+			return tp.permissions.authorize("the token", method)
+		}
+	}
+	return errUnauthenticated
+}
+
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get("authorization") {
+		if scheme, token, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	if values := md.Get(shared.BearerTokenKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// anyAuthorizer authorizes the requests authorized by any of its authorizers.
+type anyAuthorizer []Authorizer
+
+// Authorize implements Authorizer, returning the error of the first authorizer for which
+// the client is authenticated if no authorizer allows the request.
+func (a anyAuthorizer) Authorize(ctx context.Context, method string) error {
+	var firstErr error
+	for _, authorizer := range a {
+		err := authorizer.Authorize(ctx, method)
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil || (status.Code(firstErr) == codes.Unauthenticated && status.Code(err) != codes.Unauthenticated) {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// AuthzConfig configures the built-in authorizers of the remote storage.
+// A request is authorized if any of the credentials of its client grants the permission.
+type AuthzConfig struct {
+	// SANs are the permissions of the clients by the subject alternative names of their TLS client certificates.
+	SANs map[string][]Permission `json:"sans,omitempty"`
+	// Tokens are the permissions of the clients by their bearer tokens.
+	Tokens map[string][]Permission `json:"tokens,omitempty"`
+}
+
+// LoadAuthzConfig reads an authorization configuration from a JSON file, e.g.
+// {"sans": {"spiffe://example.org/collector": ["write"]}, "tokens": {"s3cr3t": ["read"]}}.
+func LoadAuthzConfig(path string) (*AuthzConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization config file: %w", err)
+	}
+	var config AuthzConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse authorization config file: %w", err)
+	}
+	if len(config.SANs) == 0 && len(config.Tokens) == 0 {
+		return nil, errors.New("authorization config must grant permissions to at least one SAN or token")
+	}
+	return &config, nil
+}
+
+// Authorizer creates the authorizer of the configuration.
+func (c *AuthzConfig) Authorizer() (Authorizer, error) {
+	var authorizers anyAuthorizer
+	if len(c.SANs) > 0 {
+		a, err := NewSANAuthorizer(c.SANs)
+		if err != nil {
+			return nil, err
+		}
+		authorizers = append(authorizers, a)
+	}
+	if len(c.Tokens) > 0 {
+		a, err := NewTokenAuthorizer(c.Tokens)
+		if err != nil {
+			return nil, err
+		}
+		authorizers = append(authorizers, a)
+	}
+	if len(authorizers) == 1 {
+		return authorizers[0], nil
+	}
+	return authorizers, nil
+}
+
+// authzInterceptor rejects the requests not allowed by an Authorizer.
+type authzInterceptor struct {
+	authorizer        Authorizer
+	logger            *zap.Logger
+	unauthorizedCount metrics.Counter
+}
+
+func newAuthzInterceptor(authorizer Authorizer, metricsFactory metrics.Factory, logger *zap.Logger) *authzInterceptor {
+	return &authzInterceptor{
+		authorizer:        authorizer,
+		logger:            logger,
+		unauthorizedCount: metricsFactory.Counter(metrics.Options{Name: "unauthorized_requests"}),
+	}
+}
+
+func (i *authzInterceptor) check(ctx context.Context, method string) error {
+	err := i.authorizer.Authorize(ctx, method)
+	if err == nil {
+		return nil
+	}
+	i.unauthorizedCount.Inc(1)
+	i.logger.Debug("Request not authorized", zap.String("client", clientID(ctx)), zap.String("method", method), zap.Error(err))
+	if _, ok := status.FromError(err); !ok {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return err
+}
+
+func (i *authzInterceptor) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := i.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (i *authzInterceptor) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := i.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
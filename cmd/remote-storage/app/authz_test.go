@@ -0,0 +1,287 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+)
+
+const (
+	writeSpanMethod    = "/jaeger.storage.v1.SpanWriterPlugin/WriteSpan"
+	getTraceMethod     = "/jaeger.storage.v1.SpanReaderPlugin/GetTrace"
+	purgeAllMethod     = "/jaeger.storage.v1.PurgerPlugin/PurgeAll"
+	capabilitiesMethod = "/jaeger.storage.v1.PluginCapabilities/Capabilities"
+)
+
+func TestRequiredPermission(t *testing.T) {
+	testCases := []struct {
+		method     string
+		permission Permission
+		required   bool
+	}{
+		{method: writeSpanMethod, permission: PermissionWrite, required: true},
+		{method: "/jaeger.storage.v1.StreamingSpanWriterPlugin/WriteSpanStream", permission: PermissionWrite, required: true},
+		{method: "/opentelemetry.proto.collector.trace.v1.TraceService/Export", permission: PermissionWrite, required: true},
+		{method: getTraceMethod, permission: PermissionRead, required: true},
+		{method: "/jaeger.storage.v1.DependenciesReaderPlugin/GetDependencies", permission: PermissionRead, required: true},
+		{method: purgeAllMethod, permission: PermissionPurge, required: true},
+		{method: capabilitiesMethod},
+		{method: "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"},
+		{method: "/grpc.health.v1.Health/Check"},
+		// the services that are not listed are denied to all the clients
+		{method: "/jaeger.storage.v2.TraceReader/GetTraces", required: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.method, func(t *testing.T) {
+			permission, required := requiredPermission(tc.method)
+			assert.Equal(t, tc.permission, permission)
+			assert.Equal(t, tc.required, required)
+		})
+	}
+}
+
+func tlsPeerContext(cert *x509.Certificate) context.Context {
+	state := tls.ConnectionState{}
+	if cert != nil {
+		state.PeerCertificates = []*x509.Certificate{cert}
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234},
+		AuthInfo: credentials.TLSInfo{State: state},
+	})
+}
+
+func TestSANAuthorizer(t *testing.T) {
+	authorizer, err := NewSANAuthorizer(map[string][]Permission{
+		"spiffe://example.org/collector": {PermissionWrite},
+		"query.example.org":              {PermissionRead},
+		"10.0.0.2":                       {PermissionRead, PermissionPurge},
+	})
+	require.NoError(t, err)
+
+	collector := tlsPeerContext(&x509.Certificate{
+		DNSNames: []string{"collector.example.org"},
+		URIs:     []*url.URL{{Scheme: "spiffe", Host: "example.org", Path: "/collector"}},
+	})
+	require.NoError(t, authorizer.Authorize(collector, writeSpanMethod))
+	require.NoError(t, authorizer.Authorize(collector, capabilitiesMethod))
+	err = authorizer.Authorize(collector, getTraceMethod)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.ErrorContains(t, err, "client spiffe://example.org/collector is not allowed to call "+getTraceMethod)
+
+	// any SAN of the certificate can grant the permission
+	admin := tlsPeerContext(&x509.Certificate{
+		DNSNames:    []string{"query.example.org"},
+		IPAddresses: []net.IP{net.IPv4(10, 0, 0, 2)},
+	})
+	require.NoError(t, authorizer.Authorize(admin, getTraceMethod))
+	require.NoError(t, authorizer.Authorize(admin, purgeAllMethod))
+	assert.Equal(t, codes.PermissionDenied, status.Code(authorizer.Authorize(admin, writeSpanMethod)))
+	// the services that are not listed are denied to the authenticated clients too
+	err = authorizer.Authorize(admin, "/jaeger.storage.v2.TraceReader/GetTraces")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.ErrorContains(t, err, "which is not allowed to any client")
+	assert.Equal(t, codes.Unauthenticated, status.Code(authorizer.Authorize(context.Background(), "/jaeger.storage.v2.TraceReader/GetTraces")))
+
+	unknown := tlsPeerContext(&x509.Certificate{EmailAddresses: []string{"someone@example.org"}})
+	assert.Equal(t, codes.Unauthenticated, status.Code(authorizer.Authorize(unknown, writeSpanMethod)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(authorizer.Authorize(tlsPeerContext(nil), writeSpanMethod)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(authorizer.Authorize(context.Background(), writeSpanMethod)))
+
+	// the certificates that are not verified are ignored
+	unverified := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{DNSNames: []string{"query.example.org"}}},
+	}}})
+	assert.Equal(t, codes.Unauthenticated, status.Code(authorizer.Authorize(unverified, getTraceMethod)))
+
+	_, err = NewSANAuthorizer(map[string][]Permission{"query.example.org": {"admin"}})
+	require.ErrorContains(t, err, `invalid permissions of SAN "query.example.org": unknown permission "admin"`)
+}
+
+func tokenContext(key, value string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(key, value))
+}
+
+func TestTokenAuthorizer(t *testing.T) {
+	authorizer, err := NewTokenAuthorizer(map[string][]Permission{
+		"writer-token": {PermissionWrite},
+		"reader-token": {PermissionRead},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, authorizer.Authorize(tokenContext("authorization", "Bearer writer-token"), writeSpanMethod))
+	require.NoError(t, authorizer.Authorize(tokenContext("authorization", "bearer reader-token"), getTraceMethod))
+	require.NoError(t, authorizer.Authorize(tokenContext(shared.BearerTokenKey, "reader-token"), getTraceMethod))
+	// the methods that do not require any permission are allowed without token
+	require.NoError(t, authorizer.Authorize(context.Background(), capabilitiesMethod))
+
+	err = authorizer.Authorize(tokenContext("authorization", "Bearer writer-token"), getTraceMethod)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.ErrorContains(t, err, "the token is not allowed to call "+getTraceMethod)
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		tokenContext("authorization", "Basic dXNlcjpwYXNz"),
+		tokenContext("authorization", "Bearer unknown"),
+	} {
+		assert.Equal(t, codes.Unauthenticated, status.Code(authorizer.Authorize(ctx, writeSpanMethod)))
+	}
+
+	_, err = NewTokenAuthorizer(map[string][]Permission{"": {PermissionRead}})
+	require.ErrorContains(t, err, "the tokens must not be empty")
+	_, err = NewTokenAuthorizer(map[string][]Permission{"token": {"admin"}})
+	require.ErrorContains(t, err, `unknown permission "admin"`)
+}
+
+func TestAnyAuthorizer(t *testing.T) {
+	unauthenticated := AuthorizerFunc(func(context.Context, string) error { return errUnauthenticated })
+	denied := AuthorizerFunc(func(context.Context, string) error { return status.Error(codes.PermissionDenied, "denied") })
+	allowed := AuthorizerFunc(func(context.Context, string) error { return nil })
+
+	ctx := context.Background()
+	require.NoError(t, anyAuthorizer{denied, allowed}.Authorize(ctx, writeSpanMethod))
+	// the client is told why it is not authorized rather than that it is not authenticated
+	require.EqualError(t, anyAuthorizer{unauthenticated, denied}.Authorize(ctx, writeSpanMethod), denied(ctx, "").Error())
+	require.ErrorIs(t, anyAuthorizer{unauthenticated, unauthenticated}.Authorize(ctx, writeSpanMethod), errUnauthenticated)
+}
+
+func writeAuthzConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "authz.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadAuthzConfig(t *testing.T) {
+	config, err := LoadAuthzConfig(writeAuthzConfig(t, `{
+		"sans": {"spiffe://example.org/collector": ["write"]},
+		"tokens": {"s3cr3t": ["read", "purge"]}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, &AuthzConfig{
+		SANs:   map[string][]Permission{"spiffe://example.org/collector": {PermissionWrite}},
+		Tokens: map[string][]Permission{"s3cr3t": {PermissionRead, PermissionPurge}},
+	}, config)
+	authorizer, err := config.Authorizer()
+	require.NoError(t, err)
+	assert.Len(t, authorizer, 2)
+	require.NoError(t, authorizer.Authorize(tokenContext("authorization", "Bearer s3cr3t"), purgeAllMethod))
+
+	testCases := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{name: "not JSON", content: "sans", err: "failed to parse authorization config file"},
+		{name: "unknown field", content: `{"users": {}}`, err: "failed to parse authorization config file"},
+		{name: "empty", content: `{"sans": {}}`, err: "must grant permissions to at least one SAN or token"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadAuthzConfig(writeAuthzConfig(t, tc.content))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+	_, err = LoadAuthzConfig(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorContains(t, err, "failed to read authorization config file")
+}
+
+func TestAuthzConfigAuthorizer(t *testing.T) {
+	authorizer, err := (&AuthzConfig{Tokens: map[string][]Permission{"s3cr3t": {PermissionRead}}}).Authorizer()
+	require.NoError(t, err)
+	assert.IsType(t, &tokenAuthorizer{}, authorizer)
+
+	_, err = (&AuthzConfig{SANs: map[string][]Permission{"collector": {"admin"}}}).Authorizer()
+	require.ErrorContains(t, err, "unknown permission")
+	_, err = (&AuthzConfig{
+		SANs:   map[string][]Permission{"collector": {PermissionWrite}},
+		Tokens: map[string][]Permission{"s3cr3t": {"admin"}},
+	}).Authorizer()
+	require.ErrorContains(t, err, "unknown permission")
+}
+
+func TestServerAuthorization(t *testing.T) {
+	storageMocks := newStorageMocks()
+	storageMocks.reader.On("GetServices", mock.Anything).Return([]string{"test"}, nil)
+	storageMocks.writer.On("WriteSpan", mock.Anything, mock.Anything).Return(nil)
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+
+	tokens, err := NewTokenAuthorizer(map[string][]Permission{"writer-token": {PermissionWrite}})
+	require.NoError(t, err)
+	custom := AuthorizerFunc(func(ctx context.Context, method string) error {
+		if method == "/jaeger.storage.v1.SpanReaderPlugin/GetOperations" {
+			return errors.New("no operations")
+		}
+		return tokens.Authorize(ctx, method)
+	})
+	server, err := NewServer(
+		&Options{GRPCHostPort: ":0", Authorizer: custom},
+		storageMocks.factory,
+		tenancy.NewManager(&tenancy.Options{}),
+		metricsFactory,
+		zap.NewNop(),
+		healthcheck.New(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Close()
+
+	conn, err := grpc.NewClient(server.grpcConn.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	writerCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer writer-token")
+
+	writer := storage_v1.NewSpanWriterPluginClient(conn)
+	_, err = writer.WriteSpan(writerCtx, &storage_v1.WriteSpanRequest{})
+	require.NoError(t, err)
+	_, err = writer.WriteSpan(ctx, &storage_v1.WriteSpanRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	reader := storage_v1.NewSpanReaderPluginClient(conn)
+	_, err = reader.GetServices(writerCtx, &storage_v1.GetServicesRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = reader.GetOperations(writerCtx, &storage_v1.GetOperationsRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.ErrorContains(t, err, "no operations")
+	stream, err := reader.GetTrace(writerCtx, &storage_v1.GetTraceRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// the capabilities are available to all the clients
+	_, err = storage_v1.NewPluginCapabilitiesClient(conn).Capabilities(ctx, &storage_v1.CapabilitiesRequest{})
+	require.NoError(t, err)
+
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "unauthorized_requests", Value: 4})
+}
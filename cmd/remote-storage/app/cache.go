@@ -0,0 +1,187 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// cacheMetrics counts the cache hits and misses of a read method.
+type cacheMetrics struct {
+	Hits   metrics.Counter `metric:"query_cache_requests" tags:"result=hit"`
+	Misses metrics.Counter `metric:"query_cache_requests" tags:"result=miss"`
+}
+
+func newCacheMetrics(metricsFactory metrics.Factory, method string) cacheMetrics {
+	var m cacheMetrics
+	metrics.MustInit(&m, metricsFactory, map[string]string{"method": method})
+	return m
+}
+
+// cachingSpanReader is a spanstore.Reader caching the services, the operations and the traces read
+// from the backend by tenant, to shield slow backends from the same queries sent by every query service.
+// The searches and the traces restricted to a time range are not cached. The errors are not cached,
+// so that the traces not found yet are read again once written. The cache is flushed when traces
+// are purged, see purger.
+type cachingSpanReader struct {
+	reader spanstore.Reader
+	// cache is replaced by an empty cache when traces are purged, the reads in progress put their
+	// results in the previous cache so that the purged traces are not cached again
+	cache    atomic.Pointer[cache.LRU]
+	newCache func() *cache.LRU
+
+	servicesMetrics   cacheMetrics
+	operationsMetrics cacheMetrics
+	traceMetrics      cacheMetrics
+}
+
+var (
+	_ spanstore.PaginatedReader = (*cachingSpanReader)(nil)
+	_ spanstore.StreamingReader = (*cachingSpanReader)(nil)
+	_ spanstore.TimeRangeReader = (*cachingSpanReader)(nil)
+)
+
+// newCachingSpanReader creates a cachingSpanReader keeping up to maxEntries results of the reader
+// for the TTL of the options.
+func newCachingSpanReader(reader spanstore.Reader, maxEntries int, opts *cache.Options, metricsFactory metrics.Factory) *cachingSpanReader {
+	r := &cachingSpanReader{
+		reader: reader,
+		newCache: func() *cache.LRU {
+			return cache.NewLRUWithOptions(maxEntries, opts)
+		},
+		servicesMetrics:   newCacheMetrics(metricsFactory, "get_services"),
+		operationsMetrics: newCacheMetrics(metricsFactory, "get_operations"),
+		traceMetrics:      newCacheMetrics(metricsFactory, "get_trace"),
+	}
+	r.flush()
+	return r
+}
+
+// flush empties the cache.
+func (r *cachingSpanReader) flush() {
+	r.cache.Store(r.newCache())
+}
+
+// purger returns a spanstore.Purger flushing the cache once the traces are purged by the purger,
+// as the purged traces and the services or operations left without traces may be cached.
+func (r *cachingSpanReader) purger(purger spanstore.Purger) spanstore.Purger {
+	return &flushingPurger{Purger: purger, reader: r}
+}
+
+func get(c cache.Cache, key string, m cacheMetrics) interface{} {
+	value := c.Get(key)
+	if value == nil {
+		m.Misses.Inc(1)
+	} else {
+		m.Hits.Inc(1)
+	}
+	return value
+}
+
+func traceKey(ctx context.Context, traceID model.TraceID) string {
+	return fmt.Sprintf("trace|%q|%s", tenancy.GetTenant(ctx), traceID)
+}
+
+func (r *cachingSpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	c, key := r.cache.Load(), traceKey(ctx, traceID)
+	if trace, ok := get(c, key, r.traceMetrics).(*model.Trace); ok {
+		return trace, nil
+	}
+	trace, err := r.reader.GetTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	c.Put(key, trace)
+	return trace, nil
+}
+
+// StreamTrace streams the spans of the trace from the backend and caches the whole trace once
+// it is read, or yields the cached trace at once.
+func (r *cachingSpanReader) StreamTrace(ctx context.Context, traceID model.TraceID, yield func(spans []*model.Span) error) error {
+	c, key := r.cache.Load(), traceKey(ctx, traceID)
+	if trace, ok := get(c, key, r.traceMetrics).(*model.Trace); ok {
+		return yield(trace.Spans)
+	}
+	var spans []*model.Span
+	err := spanstore.StreamTrace(ctx, r.reader, traceID, 0, func(batch []*model.Span) error {
+		spans = append(spans, batch...)
+		return yield(batch)
+	})
+	if err != nil {
+		return err
+	}
+	c.Put(key, &model.Trace{Spans: spans})
+	return nil
+}
+
+func (r *cachingSpanReader) GetServices(ctx context.Context) ([]string, error) {
+	c, key := r.cache.Load(), fmt.Sprintf("services|%q", tenancy.GetTenant(ctx))
+	if services, ok := get(c, key, r.servicesMetrics).([]string); ok {
+		return services, nil
+	}
+	services, err := r.reader.GetServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.Put(key, services)
+	return services, nil
+}
+
+func (r *cachingSpanReader) GetOperations(ctx context.Context, query spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	c, key := r.cache.Load(), fmt.Sprintf("operations|%q|%q|%q", tenancy.GetTenant(ctx), query.ServiceName, query.SpanKind)
+	if operations, ok := get(c, key, r.operationsMetrics).([]spanstore.Operation); ok {
+		return operations, nil
+	}
+	operations, err := r.reader.GetOperations(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.Put(key, operations)
+	return operations, nil
+}
+
+func (r *cachingSpanReader) FindOperations(ctx context.Context, query spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	return spanstore.FindOperations(ctx, r.reader, query)
+}
+
+func (r *cachingSpanReader) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	return r.reader.FindTraces(ctx, query)
+}
+
+func (r *cachingSpanReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	return r.reader.FindTraceIDs(ctx, query)
+}
+
+func (r *cachingSpanReader) FindTracesPage(ctx context.Context, query *spanstore.TraceQueryParameters) (*spanstore.TracesPage, error) {
+	return spanstore.FindTracesPage(ctx, r.reader, query)
+}
+
+func (r *cachingSpanReader) GetTraceInTimeRange(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	return spanstore.GetTraceInTimeRange(ctx, r.reader, query)
+}
+
+// flushingPurger flushes the cache of the reader once the traces are purged. The cache is flushed
+// on errors as well, since some traces may have been purged.
+type flushingPurger struct {
+	spanstore.Purger
+	reader *cachingSpanReader
+}
+
+func (p *flushingPurger) PurgeTraces(ctx context.Context, query *spanstore.TraceQueryParameters) (int, error) {
+	defer p.reader.flush()
+	return p.Purger.PurgeTraces(ctx, query)
+}
+
+func (p *flushingPurger) PurgeAll(ctx context.Context) error {
+	defer p.reader.flush()
+	return p.Purger.PurgeAll(ctx)
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func TestCachingSpanReaderServices(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(time.Hour)
+	defer metricsFactory.Stop()
+	backend := new(spanStoreMocks.Reader)
+	reader := newCachingSpanReader(backend, 10, &cache.Options{}, metricsFactory)
+
+	acme := tenancy.WithTenant(context.Background(), "acme")
+	globex := tenancy.WithTenant(context.Background(), "globex")
+	backend.On("GetServices", acme).Return([]string{"frontend"}, nil).Once()
+	backend.On("GetServices", globex).Return(nil, errors.New("backend error")).Once()
+	backend.On("GetServices", globex).Return([]string{"driver"}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		services, err := reader.GetServices(acme)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend"}, services)
+	}
+	// the errors are not cached
+	_, err := reader.GetServices(globex)
+	require.ErrorContains(t, err, "backend error")
+	services, err := reader.GetServices(globex)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"driver"}, services)
+	backend.AssertExpectations(t)
+
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "query_cache_requests", Tags: map[string]string{"method": "get_services", "result": "hit"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "query_cache_requests", Tags: map[string]string{"method": "get_services", "result": "miss"}, Value: 3},
+	)
+}
+
+func TestCachingSpanReaderOperations(t *testing.T) {
+	backend := new(spanStoreMocks.Reader)
+	reader := newCachingSpanReader(backend, 10, &cache.Options{}, metrics.NullFactory)
+
+	ctx := context.Background()
+	server := spanstore.OperationQueryParameters{ServiceName: "frontend", SpanKind: "server"}
+	all := spanstore.OperationQueryParameters{ServiceName: "frontend"}
+	backend.On("GetOperations", ctx, server).Return([]spanstore.Operation{{Name: "GET", SpanKind: "server"}}, nil).Once()
+	backend.On("GetOperations", ctx, all).Return([]spanstore.Operation{{Name: "GET", SpanKind: "server"}, {Name: "query"}}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		operations, err := reader.GetOperations(ctx, server)
+		require.NoError(t, err)
+		assert.Len(t, operations, 1)
+		operations, err = reader.GetOperations(ctx, all)
+		require.NoError(t, err)
+		assert.Len(t, operations, 2)
+	}
+	backend.AssertExpectations(t)
+
+	backend.On("GetOperations", ctx, mock.Anything).Return(nil, errors.New("backend error")).Once()
+	_, err := reader.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: "driver"})
+	require.ErrorContains(t, err, "backend error")
+}
+
+func TestCachingSpanReaderTrace(t *testing.T) {
+	now := time.Now()
+	backend := new(spanStoreMocks.Reader)
+	reader := newCachingSpanReader(backend, 10, &cache.Options{
+		TTL:     time.Minute,
+		TimeNow: func() time.Time { return now },
+	}, metrics.NullFactory)
+
+	ctx := context.Background()
+	traceID := model.NewTraceID(0, 1)
+	trace := &model.Trace{Spans: []*model.Span{{TraceID: traceID, SpanID: 1}, {TraceID: traceID, SpanID: 2}}}
+	backend.On("GetTrace", ctx, traceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+	backend.On("GetTrace", ctx, traceID).Return(trace, nil).Once()
+
+	_, err := reader.GetTrace(ctx, traceID)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+	for i := 0; i < 2; i++ {
+		got, err := reader.GetTrace(ctx, traceID)
+		require.NoError(t, err)
+		assert.Equal(t, trace, got)
+	}
+
+	// the cached trace is streamed too
+	var spans []*model.Span
+	require.NoError(t, reader.StreamTrace(ctx, traceID, func(batch []*model.Span) error {
+		spans = append(spans, batch...)
+		return nil
+	}))
+	assert.Equal(t, trace.Spans, spans)
+	backend.AssertExpectations(t)
+
+	// the trace is read again once expired
+	now = now.Add(time.Minute + time.Second)
+	backend.On("GetTrace", ctx, traceID).Return(trace, nil).Once()
+	spans = nil
+	for i := 0; i < 2; i++ {
+		require.NoError(t, reader.StreamTrace(ctx, traceID, func(batch []*model.Span) error {
+			spans = append(spans, batch...)
+			return nil
+		}))
+	}
+	assert.Len(t, spans, 4)
+	backend.AssertExpectations(t)
+
+	otherID := model.NewTraceID(0, 2)
+	backend.On("GetTrace", ctx, otherID).Return(nil, spanstore.ErrTraceNotFound)
+	err = reader.StreamTrace(ctx, otherID, func([]*model.Span) error { return nil })
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+}
+
+func TestCachingSpanReaderNotCached(t *testing.T) {
+	backend := new(spanStoreMocks.Reader)
+	reader := newCachingSpanReader(backend, 10, &cache.Options{}, metrics.NullFactory)
+
+	ctx := context.Background()
+	query := &spanstore.TraceQueryParameters{ServiceName: "frontend", NumTraces: 10}
+	traceID := model.NewTraceID(0, 1)
+	trace := &model.Trace{Spans: []*model.Span{{TraceID: traceID, SpanID: 1}}}
+	backend.On("FindTraces", ctx, mock.Anything).Return([]*model.Trace{trace}, nil).Times(4)
+	backend.On("FindTraceIDs", ctx, query).Return([]model.TraceID{traceID}, nil).Twice()
+	backend.On("GetTrace", ctx, traceID).Return(trace, nil).Twice()
+	operationsQuery := spanstore.OperationQueryParameters{ServiceName: "frontend", NamePrefix: "GET"}
+	backend.On("GetOperations", ctx, spanstore.OperationQueryParameters{ServiceName: "frontend"}).
+		Return([]spanstore.Operation{{Name: "GET /"}, {Name: "POST /"}}, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		traces, err := reader.FindTraces(ctx, query)
+		require.NoError(t, err)
+		assert.Len(t, traces, 1)
+		traceIDs, err := reader.FindTraceIDs(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{traceID}, traceIDs)
+		page, err := reader.FindTracesPage(ctx, query)
+		require.NoError(t, err)
+		assert.Len(t, page.Traces, 1)
+		// the filtered operations are not cached
+		operations, err := reader.FindOperations(ctx, operationsQuery)
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{{Name: "GET /"}}, operations)
+		// the traces restricted to a time range are not cached
+		got, err := reader.GetTraceInTimeRange(ctx, spanstore.GetTraceParameters{TraceID: traceID, StartTime: time.Now()})
+		require.NoError(t, err)
+		assert.Equal(t, trace, got)
+	}
+	backend.AssertExpectations(t)
+}
+
+func TestCachingSpanReaderPurge(t *testing.T) {
+	backend := new(spanStoreMocks.Reader)
+	reader := newCachingSpanReader(backend, 10, &cache.Options{}, metrics.NullFactory)
+	backendPurger := new(spanStoreMocks.Purger)
+	purger := reader.purger(backendPurger)
+
+	ctx := context.Background()
+	traceID := model.NewTraceID(0, 1)
+	trace := &model.Trace{Spans: []*model.Span{{TraceID: traceID, SpanID: 1}}}
+	query := &spanstore.TraceQueryParameters{ServiceName: "frontend"}
+	backend.On("GetServices", ctx).Return([]string{"frontend"}, nil).Once()
+	backend.On("GetTrace", ctx, traceID).Return(trace, nil).Once()
+	backendPurger.On("PurgeTraces", ctx, query).Return(1, nil).Once()
+	backendPurger.On("PurgeAll", ctx).Return(errors.New("backend error")).Once()
+
+	_, err := reader.GetServices(ctx)
+	require.NoError(t, err)
+	_, err = reader.GetTrace(ctx, traceID)
+	require.NoError(t, err)
+
+	// the purged traces are read again from the backend
+	count, err := purger.PurgeTraces(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	backend.On("GetServices", ctx).Return([]string{}, nil).Once()
+	backend.On("GetTrace", ctx, traceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+	services, err := reader.GetServices(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, services)
+	_, err = reader.GetTrace(ctx, traceID)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+	// the cache is flushed if the purge fails, as some traces may have been purged
+	require.ErrorContains(t, purger.PurgeAll(ctx), "backend error")
+	backend.On("GetServices", ctx).Return([]string{"frontend"}, nil).Once()
+	services, err = reader.GetServices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend"}, services)
+	backend.AssertExpectations(t)
+	backendPurger.AssertExpectations(t)
+}
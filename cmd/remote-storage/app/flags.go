@@ -15,19 +15,39 @@
 package app
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/ports"
 )
 
 const (
-	flagGRPCHostPort = "grpc.host-port"
+	flagGRPCHostPort             = "grpc.host-port"
+	flagGRPCAddressFamily        = "grpc.address-family"
+	flagGRPCSocketPermissions    = "grpc.unix-socket-permissions"
+	flagGRPCMaxMessageSize       = "grpc.max-message-size"
+	flagGRPCMaxConcurrentStreams = "grpc.max-concurrent-streams"
+	flagGRPCRateLimit            = "grpc.rate-limit.requests-per-second"
+	flagGRPCRateLimitBurst       = "grpc.rate-limit.burst"
+	flagRoutingConfigFile        = "routing.config-file"
+	flagAuthzConfigFile          = "grpc.authz.config-file"
+	flagCacheMaxEntries          = "cache.max-entries"
+	flagCacheTTL                 = "cache.ttl"
+
+	// DefaultGRPCMaxMessageSize is the default max receivable message size of the gRPC server
+	DefaultGRPCMaxMessageSize = 4 * 1024 * 1024
+
+	// DefaultCacheTTL is the default time during which the results of the read queries are cached
+	DefaultCacheTTL = 30 * time.Second
 )
 
 var tlsGRPCFlagsConfig = tlscfg.ServerFlagsConfig{
@@ -38,27 +58,100 @@ var tlsGRPCFlagsConfig = tlscfg.ServerFlagsConfig{
 type Options struct {
 	// GRPCHostPort is the host:port address for gRPC server
 	GRPCHostPort string
+	// GRPCAddressFamily restricts the addresses of the gRPC server to IPv4 or IPv6
+	GRPCAddressFamily netutils.AddressFamily
+	// GRPCSocketPermissions are the permissions of the socket file when GRPCHostPort is a unix:///path endpoint
+	GRPCSocketPermissions os.FileMode
 	// TLSGRPC configures secure transport
 	TLSGRPC tlscfg.Options
+	// MaxMessageSize is the maximum size in bytes of the messages received by the gRPC server
+	MaxMessageSize int
+	// MaxConcurrentStreams is the maximum number of concurrent streams of each client connection, unlimited if 0
+	MaxConcurrentStreams uint32
+	// RateLimit is the maximum average number of requests per second of each client, unlimited if 0
+	RateLimit float64
+	// RateLimitBurst is the maximum number of requests of each client at once, defaults to RateLimit
+	RateLimitBurst int
 	// Tenancy configuration
 	Tenancy tenancy.Options
+	// RoutingConfigFile is the path of the RoutingConfig selecting the storage backend of each tenant
+	RoutingConfigFile string
+	// Authorizer rejects the requests of the clients not allowed to call the RPCs, all the requests
+	// are allowed if nil. It is created from the AuthzConfig file of the flags, or set by the programs
+	// embedding the server.
+	Authorizer Authorizer
+	// CacheMaxEntries is the maximum number of results of the read queries kept in the cache, disabled if 0
+	CacheMaxEntries int
+	// CacheTTL is the time during which the results of the read queries are cached
+	CacheTTL time.Duration
 }
 
 // AddFlags adds flags to flag set.
 func AddFlags(flagSet *flag.FlagSet) {
-	flagSet.String(flagGRPCHostPort, ports.PortToHostPort(ports.RemoteStorageGRPC), "The host:port (e.g. 127.0.0.1:17271 or :17271) of the gRPC server")
+	flagSet.String(flagGRPCHostPort, ports.PortToHostPort(ports.RemoteStorageGRPC), "The host:port (e.g. 127.0.0.1:17271 or :17271) of the gRPC server, or unix:///path/to/socket to listen on a unix domain socket")
+	flagSet.String(flagGRPCAddressFamily, string(netutils.DualStack), fmt.Sprintf(netutils.AddressFamilyFlagUsage, "gRPC server"))
+	flagSet.String(flagGRPCSocketPermissions, "", "The octal permissions (e.g. 0660) of the socket file when the gRPC server listens on a unix domain socket; the umask applies if empty")
+	flagSet.Int(flagGRPCMaxMessageSize, DefaultGRPCMaxMessageSize, "The maximum size in bytes of the messages that the gRPC server can receive")
+	flagSet.Uint(flagGRPCMaxConcurrentStreams, 0, "The maximum number of concurrent streams, i.e. requests, of each client connection to the gRPC server, or 0 for no limit")
+	flagSet.Float64(flagGRPCRateLimit, 0, "The maximum average number of requests per second of each client, identified by its tenant, its TLS client certificate or its IP address, or 0 for no limit")
+	flagSet.Int(flagGRPCRateLimitBurst, 0, "The maximum number of requests of each client at once when requests are rate limited, defaults to the requests per second")
 	tlsGRPCFlagsConfig.AddFlags(flagSet)
 	tenancy.AddFlags(flagSet)
+	flagSet.String(flagAuthzConfigFile, "", "The path of a JSON file granting the read, write and purge permissions to the clients "+
+		"by the subject alternative names of their TLS client certificates or by their bearer tokens, "+
+		`e.g. {"sans": {"spiffe://example.org/collector": ["write"]}, "tokens": {"s3cr3t": ["read"]}}. All the clients are allowed if empty`)
+	flagSet.Int(flagCacheMaxEntries, 0, "The maximum number of results of the services, operations and trace queries cached by tenant, "+
+		"to shield slow storage backends from the same queries sent by every query service, or 0 to disable the cache")
+	flagSet.Duration(flagCacheTTL, DefaultCacheTTL, "The time during which the results of the services, operations and trace queries are cached")
+	flagSet.String(flagRoutingConfigFile, "", "The path of a JSON file configuring several storage backends and the tenants stored in each of them, instead of a single backend configured by SPAN_STORAGE_TYPE. Requires multi-tenancy to be enabled")
 }
 
 // InitFromViper initializes Options with properties from CLI flags.
 func (o *Options) InitFromViper(v *viper.Viper, logger *zap.Logger) (*Options, error) {
 	o.GRPCHostPort = v.GetString(flagGRPCHostPort)
+	addressFamily, err := netutils.ParseAddressFamily(v.GetString(flagGRPCAddressFamily))
+	if err != nil {
+		return o, fmt.Errorf("failed to process gRPC address family: %w", err)
+	}
+	o.GRPCAddressFamily = addressFamily
+	socketPermissions, err := netutils.ParseSocketPermissions(v.GetString(flagGRPCSocketPermissions))
+	if err != nil {
+		return o, fmt.Errorf("failed to process gRPC unix socket permissions: %w", err)
+	}
+	o.GRPCSocketPermissions = socketPermissions
+	o.MaxMessageSize = v.GetInt(flagGRPCMaxMessageSize)
+	o.MaxConcurrentStreams = v.GetUint32(flagGRPCMaxConcurrentStreams)
+	o.RateLimit = v.GetFloat64(flagGRPCRateLimit)
+	o.RateLimitBurst = v.GetInt(flagGRPCRateLimitBurst)
+	if o.RateLimit < 0 {
+		return o, errors.New("the rate limit must not be negative")
+	}
+	o.CacheMaxEntries = v.GetInt(flagCacheMaxEntries)
+	o.CacheTTL = v.GetDuration(flagCacheTTL)
+	if o.CacheMaxEntries < 0 {
+		return o, errors.New("the maximum number of cache entries must not be negative")
+	}
 	if tlsGrpc, err := tlsGRPCFlagsConfig.InitFromViper(v); err == nil {
 		o.TLSGRPC = tlsGrpc
 	} else {
 		return o, fmt.Errorf("failed to process gRPC TLS options: %w", err)
 	}
 	o.Tenancy = tenancy.InitFromViper(v)
+	o.RoutingConfigFile = v.GetString(flagRoutingConfigFile)
+	if o.RoutingConfigFile != "" && !o.Tenancy.Enabled {
+		return o, errors.New("routing storage backends by tenant requires multi-tenancy to be enabled")
+	}
+	if path := v.GetString(flagAuthzConfigFile); path != "" {
+		authzConfig, err := LoadAuthzConfig(path)
+		if err != nil {
+			return o, err
+		}
+		if len(authzConfig.SANs) > 0 && (!o.TLSGRPC.Enabled || o.TLSGRPC.ClientCAPath == "") {
+			return o, errors.New("authorizing the clients by their certificates requires TLS with a client CA")
+		}
+		if o.Authorizer, err = authzConfig.Authorizer(); err != nil {
+			return o, fmt.Errorf("invalid authorization config: %w", err)
+		}
+	}
 	return o, nil
 }
@@ -15,13 +15,16 @@
 package app
 
 import (
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 )
 
 func TestFlags(t *testing.T) {
@@ -32,6 +35,87 @@ func TestFlags(t *testing.T) {
 	qOpts, err := new(Options).InitFromViper(v, zap.NewNop())
 	require.NoError(t, err)
 	assert.Equal(t, "127.0.0.1:8081", qOpts.GRPCHostPort)
+	assert.Equal(t, DefaultGRPCMaxMessageSize, qOpts.MaxMessageSize)
+	assert.Zero(t, qOpts.MaxConcurrentStreams)
+	assert.Zero(t, qOpts.RateLimit)
+}
+
+func TestLimitFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--grpc.max-message-size=1024",
+		"--grpc.max-concurrent-streams=100",
+		"--grpc.rate-limit.requests-per-second=50",
+		"--grpc.rate-limit.burst=200",
+	}))
+	opts, err := new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 1024, opts.MaxMessageSize)
+	assert.Equal(t, uint32(100), opts.MaxConcurrentStreams)
+	assert.InDelta(t, 50.0, opts.RateLimit, 0.01)
+	assert.Equal(t, 200, opts.RateLimitBurst)
+
+	require.NoError(t, command.ParseFlags([]string{
+		"--grpc.rate-limit.requests-per-second=-1",
+	}))
+	_, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "must not be negative")
+}
+
+func TestCacheFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{}))
+	opts, err := new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Zero(t, opts.CacheMaxEntries)
+	assert.Equal(t, DefaultCacheTTL, opts.CacheTTL)
+
+	require.NoError(t, command.ParseFlags([]string{
+		"--cache.max-entries=1000",
+		"--cache.ttl=1m",
+	}))
+	opts, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 1000, opts.CacheMaxEntries)
+	assert.Equal(t, time.Minute, opts.CacheTTL)
+
+	require.NoError(t, command.ParseFlags([]string{
+		"--cache.max-entries=-1",
+	}))
+	_, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "must not be negative")
+}
+
+func TestAddressFamilyFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--grpc.host-port=[::1]:17271",
+		"--grpc.address-family=ipv6",
+	}))
+	opts, err := new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "[::1]:17271", opts.GRPCHostPort)
+	assert.Equal(t, netutils.IPv6, opts.GRPCAddressFamily)
+
+	require.NoError(t, command.ParseFlags([]string{"--grpc.address-family=ipx"}))
+	_, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to process gRPC address family")
+}
+
+func TestUnixSocketFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--grpc.host-port=unix:///var/run/jaeger/remote-storage.sock",
+		"--grpc.unix-socket-permissions=0660",
+	}))
+	opts, err := new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "unix:///var/run/jaeger/remote-storage.sock", opts.GRPCHostPort)
+	assert.Equal(t, os.FileMode(0o660), opts.GRPCSocketPermissions)
+
+	require.NoError(t, command.ParseFlags([]string{"--grpc.unix-socket-permissions=0999"}))
+	_, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to process gRPC unix socket permissions")
 }
 
 func TestFailedTLSFlags(t *testing.T) {
@@ -45,3 +129,51 @@ func TestFailedTLSFlags(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to process gRPC TLS options")
 }
+
+func TestRoutingFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--routing.config-file=routing.json",
+	}))
+	_, err := new(Options).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "requires multi-tenancy to be enabled")
+
+	require.NoError(t, command.ParseFlags([]string{
+		"--multi-tenancy.enabled=true",
+	}))
+	opts, err := new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "routing.json", opts.RoutingConfigFile)
+}
+
+func TestAuthzFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{}))
+	opts, err := new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, opts.Authorizer)
+
+	tokensFile := writeAuthzConfig(t, `{"tokens": {"s3cr3t": ["write"]}}`)
+	v, command = config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--grpc.authz.config-file=" + tokensFile}))
+	opts, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.NoError(t, err)
+	assert.IsType(t, &tokenAuthorizer{}, opts.Authorizer)
+
+	sansFile := writeAuthzConfig(t, `{"sans": {"collector.example.org": ["write"]}}`)
+	v, command = config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--grpc.authz.config-file=" + sansFile}))
+	_, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "requires TLS with a client CA")
+
+	invalidFile := writeAuthzConfig(t, `{"tokens": {"s3cr3t": ["admin"]}}`)
+	v, command = config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--grpc.authz.config-file=" + invalidFile}))
+	_, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "invalid authorization config")
+
+	v, command = config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--grpc.authz.config-file=missing.json"}))
+	_, err = new(Options).InitFromViper(v, zap.NewNop())
+	require.ErrorContains(t, err, "failed to read authorization config file")
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/x509"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+// maxIdleRateLimitedClients is the number of clients above which the state of the
+// clients that have not sent requests for a while is discarded.
+const maxIdleRateLimitedClients = 10000
+
+// tokenBucket holds the tokens of one client of a clientRateLimiter.
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// clientRateLimiter limits the rate of the requests of each client separately,
+// with a token bucket per client.
+type clientRateLimiter struct {
+	sync.Mutex
+	rate    float64
+	burst   float64
+	timeNow func() time.Time
+	buckets map[string]*tokenBucket
+	logger  *zap.Logger
+
+	rateLimitedCounter metrics.Counter
+}
+
+// newClientRateLimiter creates a clientRateLimiter allowing each client requestsPerSecond requests
+// per second on average, and up to burst requests at once. If burst is not positive, it defaults
+// to requestsPerSecond rounded up.
+func newClientRateLimiter(requestsPerSecond float64, burst int, metricsFactory metrics.Factory, logger *zap.Logger) *clientRateLimiter {
+	if burst <= 0 {
+		burst = int(math.Ceil(requestsPerSecond))
+	}
+	return &clientRateLimiter{
+		rate:               requestsPerSecond,
+		burst:              float64(burst),
+		timeNow:            time.Now,
+		buckets:            make(map[string]*tokenBucket),
+		logger:             logger,
+		rateLimitedCounter: metricsFactory.Counter(metrics.Options{Name: "rate_limited_requests"}),
+	}
+}
+
+// allow consumes a token of the client and returns whether it had one.
+func (l *clientRateLimiter) allow(client string) bool {
+	l.Lock()
+	defer l.Unlock()
+	now := l.timeNow()
+	bucket, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) >= maxIdleRateLimitedClients {
+			l.evictIdleClients(now)
+		}
+		bucket = &tokenBucket{tokens: l.burst, lastRefill: now}
+		l.buckets[client] = bucket
+	}
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.lastRefill).Seconds()*l.rate)
+	bucket.lastRefill = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// evictIdleClients discards the buckets that are full again, which behave like new buckets.
+func (l *clientRateLimiter) evictIdleClients(now time.Time) {
+	for client, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.lastRefill).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+func (l *clientRateLimiter) check(ctx context.Context, method string) error {
+	client := clientID(ctx)
+	if l.allow(client) {
+		return nil
+	}
+	l.rateLimitedCounter.Inc(1)
+	l.logger.Debug("Request rate limited", zap.String("client", client), zap.String("method", method))
+	return status.Errorf(codes.ResourceExhausted, "rate limit of %g requests per second exceeded", l.rate)
+}
+
+func (l *clientRateLimiter) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (l *clientRateLimiter) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// clientID identifies the client of a request by its tenant, if multi-tenancy is enabled,
+// otherwise by the identity of its TLS client certificate, or else by its IP address.
+func clientID(ctx context.Context) string {
+	if tenant := tenancy.GetTenant(ctx); tenant != "" {
+		return "tenant:" + tenant
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		if id := certificateID(tlsInfo.State.PeerCertificates[0]); id != "" {
+			return id
+		}
+	}
+	if p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return "addr:" + p.Addr.String()
+	}
+	return "addr:" + host
+}
+
+// certificateID identifies a client certificate by its first URI, e.g. a SPIFFE ID, or DNS subject
+// alternative name, like the SAN authorizer, otherwise by its common name. The certificates without
+// any of them, e.g. some certificates issued by cert-manager, have no identity.
+func certificateID(cert *x509.Certificate) string {
+	switch {
+	case len(cert.URIs) > 0:
+		return "uri:" + cert.URIs[0].String()
+	case len(cert.DNSNames) > 0:
+		return "dns:" + cert.DNSNames[0]
+	case cert.Subject.CommonName != "":
+		return "cn:" + cert.Subject.CommonName
+	default:
+		return ""
+	}
+}
+
+// limitsStatsHandler is a gRPC stats handler counting the requests rejected for receiving a message
+// larger than the maximum message size, and the requests reaching the maximum number of concurrent
+// streams of their connection, after which the new requests of the connection wait.
+type limitsStatsHandler struct {
+	maxConcurrentStreams int64
+
+	oversizedCounter      metrics.Counter
+	streamsLimitedCounter metrics.Counter
+}
+
+// connStreamsKey is the key of the number of active streams of the connection in the context.
+type connStreamsKey struct{}
+
+func newLimitsStatsHandler(maxConcurrentStreams uint32, metricsFactory metrics.Factory) *limitsStatsHandler {
+	return &limitsStatsHandler{
+		maxConcurrentStreams:  int64(maxConcurrentStreams),
+		oversizedCounter:      metricsFactory.Counter(metrics.Options{Name: "oversized_requests"}),
+		streamsLimitedCounter: metricsFactory.Counter(metrics.Options{Name: "max_concurrent_streams_reached"}),
+	}
+}
+
+func (h *limitsStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	if h.maxConcurrentStreams <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, connStreamsKey{}, new(atomic.Int64))
+}
+
+func (*limitsStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+func (*limitsStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *limitsStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	streams, _ := ctx.Value(connStreamsKey{}).(*atomic.Int64)
+	switch s := s.(type) {
+	case *stats.Begin:
+		if streams != nil && streams.Add(1) >= h.maxConcurrentStreams {
+			h.streamsLimitedCounter.Inc(1)
+		}
+	case *stats.End:
+		if streams != nil {
+			streams.Add(-1)
+		}
+		if s.Error == nil {
+			return
+		}
+		st := status.Convert(s.Error)
+		// the gRPC server does not expose the reason otherwise
+		if st.Code() == codes.ResourceExhausted &&
+			strings.HasPrefix(st.Message(), "grpc: received message") &&
+			strings.Contains(st.Message(), "larger than max") {
+			h.oversizedCounter.Inc(1)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+)
+
+func TestClientRateLimiterAllow(t *testing.T) {
+	l := newClientRateLimiter(2, 3, metrics.NullFactory, zap.NewNop())
+	now := time.Unix(0, 0)
+	l.timeNow = func() time.Time { return now }
+
+	// the burst is available at once
+	for i := 0; i < 3; i++ {
+		assert.True(t, l.allow("a"))
+	}
+	assert.False(t, l.allow("a"))
+	// other clients have their own tokens
+	assert.True(t, l.allow("b"))
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, l.allow("a"))
+	assert.False(t, l.allow("a"))
+
+	// the tokens are refilled up to the burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, l.allow("a"))
+	}
+	assert.False(t, l.allow("a"))
+}
+
+func TestClientRateLimiterDefaultBurst(t *testing.T) {
+	l := newClientRateLimiter(1.5, 0, metrics.NullFactory, zap.NewNop())
+	assert.InDelta(t, 2.0, l.burst, 0.01)
+}
+
+func TestClientRateLimiterEvictIdleClients(t *testing.T) {
+	l := newClientRateLimiter(1, 1, metrics.NullFactory, zap.NewNop())
+	now := time.Unix(0, 0)
+	l.timeNow = func() time.Time { return now }
+	for i := 0; i < maxIdleRateLimitedClients; i++ {
+		l.allow(string(rune(i)))
+	}
+	now = now.Add(time.Second)
+	assert.True(t, l.allow("active"))
+	assert.False(t, l.allow("active"))
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestClientID(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}
+	tlsInfo := func(cert *x509.Certificate) credentials.TLSInfo {
+		return credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}}
+	}
+	spiffeID, err := url.Parse("spiffe://example.org/collector")
+	require.NoError(t, err)
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{
+			name:     "no peer",
+			ctx:      context.Background(),
+			expected: "",
+		},
+		{
+			name:     "tenant",
+			ctx:      tenancy.WithTenant(peer.NewContext(context.Background(), &peer.Peer{Addr: addr}), "acme"),
+			expected: "tenant:acme",
+		},
+		{
+			name: "client certificate URI",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{Addr: addr, AuthInfo: tlsInfo(&x509.Certificate{
+				URIs:     []*url.URL{spiffeID},
+				DNSNames: []string{"collector.example.org"},
+			})}),
+			expected: "uri:spiffe://example.org/collector",
+		},
+		{
+			name: "client certificate DNS name",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{Addr: addr, AuthInfo: tlsInfo(&x509.Certificate{
+				Subject:  pkix.Name{CommonName: "collector"},
+				DNSNames: []string{"collector.example.org"},
+			})}),
+			expected: "dns:collector.example.org",
+		},
+		{
+			name:     "client certificate common name",
+			ctx:      peer.NewContext(context.Background(), &peer.Peer{Addr: addr, AuthInfo: tlsInfo(&x509.Certificate{Subject: pkix.Name{CommonName: "collector"}})}),
+			expected: "cn:collector",
+		},
+		{
+			name:     "client certificate without identity",
+			ctx:      peer.NewContext(context.Background(), &peer.Peer{Addr: addr, AuthInfo: tlsInfo(&x509.Certificate{})}),
+			expected: "addr:10.0.0.1",
+		},
+		{
+			name:     "address",
+			ctx:      peer.NewContext(context.Background(), &peer.Peer{Addr: addr}),
+			expected: "addr:10.0.0.1",
+		},
+		{
+			name:     "address without port",
+			ctx:      peer.NewContext(context.Background(), &peer.Peer{Addr: &net.UnixAddr{Name: "/tmp/socket", Net: "unix"}}),
+			expected: "addr:/tmp/socket",
+		},
+		{
+			name:     "no address",
+			ctx:      peer.NewContext(context.Background(), &peer.Peer{}),
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, clientID(tt.ctx))
+		})
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	storageMocks := newStorageMocks()
+	storageMocks.reader.On("GetServices", mock.Anything).Return([]string{"test"}, nil)
+	storageMocks.reader.On("FindTraces", mock.Anything, mock.Anything).Return(nil, nil)
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	tm := tenancy.NewManager(&tenancy.Options{Enabled: true})
+
+	server, err := NewServer(
+		&Options{GRPCHostPort: ":0", RateLimit: 0.001, RateLimitBurst: 2, MaxConcurrentStreams: 10, MaxMessageSize: 1024},
+		storageMocks.factory,
+		tm,
+		metricsFactory,
+		zap.NewNop(),
+		healthcheck.New(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Close()
+
+	conn, err := grpc.NewClient(server.grpcConn.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(tenancy.NewClientUnaryInterceptor(tm)),
+		grpc.WithStreamInterceptor(tenancy.NewClientStreamInterceptor(tm)),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := storage_v1.NewSpanReaderPluginClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	acme := tenancy.WithTenant(ctx, "acme")
+	_, err = client.GetServices(acme, &storage_v1.GetServicesRequest{})
+	require.NoError(t, err)
+	stream, err := client.FindTraces(acme, &storage_v1.FindTracesRequest{Query: &storage_v1.TraceQueryParameters{ServiceName: "test"}})
+	require.NoError(t, err)
+	// no traces are found
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	_, err = client.GetServices(acme, &storage_v1.GetServicesRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	stream, err = client.FindTraces(acme, &storage_v1.FindTracesRequest{Query: &storage_v1.TraceQueryParameters{ServiceName: "test"}})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "rate_limited_requests", Value: 2})
+
+	// the requests of other tenants are not limited
+	_, err = client.GetServices(tenancy.WithTenant(ctx, "globex"), &storage_v1.GetServicesRequest{})
+	require.NoError(t, err)
+
+	// messages larger than the limit are rejected
+	_, err = client.GetOperations(acme, &storage_v1.GetOperationsRequest{Service: string(make([]byte, 2048))})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "oversized_requests", Value: 1})
+}
+
+func TestLimitsStatsHandler(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(time.Hour)
+	defer metricsFactory.Stop()
+	h := newLimitsStatsHandler(2, metricsFactory)
+	ctx := h.TagConn(context.Background(), &stats.ConnTagInfo{})
+	h.HandleConn(ctx, &stats.ConnBegin{})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{})
+
+	// the streams of each connection are counted separately
+	other := h.TagConn(context.Background(), &stats.ConnTagInfo{})
+	h.HandleRPC(other, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.End{})
+	h.HandleRPC(ctx, &stats.Begin{})
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "max_concurrent_streams_reached", Value: 2})
+
+	h.HandleRPC(ctx, &stats.End{Error: errors.New("unexpected")})
+	h.HandleRPC(ctx, &stats.End{Error: status.Error(codes.ResourceExhausted, "rate limit exceeded")})
+	h.HandleRPC(ctx, &stats.End{Error: status.Error(codes.ResourceExhausted, "grpc: received message larger than max (2048 vs. 1024)")})
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "oversized_requests", Value: 1})
+
+	// the streams are not counted without limit
+	h = newLimitsStatsHandler(0, metricsFactory)
+	ctx = h.TagConn(context.Background(), &stats.ConnTagInfo{})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.End{})
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "max_concurrent_streams_reached", Value: 2})
+}
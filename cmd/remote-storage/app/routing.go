@@ -0,0 +1,254 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	pluginstorage "github.com/jaegertracing/jaeger/plugin/storage"
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// RoutingConfig describes the storage backends of the server and which backend
+// stores the data of each tenant.
+type RoutingConfig struct {
+	// Backends are the storage backends, by name.
+	Backends map[string]BackendConfig `json:"backends"`
+	// Tenants maps the tenants to the names of their backends.
+	Tenants map[string]string `json:"tenants"`
+	// DefaultBackend is the backend of the tenants missing from Tenants. If empty,
+	// the requests of these tenants are rejected.
+	DefaultBackend string `json:"default_backend,omitempty"`
+}
+
+// BackendConfig describes a storage backend.
+type BackendConfig struct {
+	// Type is the storage type, as in SPAN_STORAGE_TYPE, e.g. elasticsearch.
+	Type string `json:"type"`
+	// Options are the values of the command line flags of the storage type,
+	// e.g. "es.server-urls". Flags that are not set keep their default values.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// LoadRoutingConfig reads and validates a routing configuration from a JSON file.
+func LoadRoutingConfig(path string) (*RoutingConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routing config file: %w", err)
+	}
+	var config RoutingConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse routing config file: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate checks that the configuration has backends and that the tenants are routed to them.
+func (c *RoutingConfig) Validate() error {
+	if len(c.Backends) == 0 {
+		return errors.New("routing config must have at least one backend")
+	}
+	for name, backend := range c.Backends {
+		if backend.Type == "" {
+			return fmt.Errorf("storage type of backend %q must not be empty", name)
+		}
+	}
+	for tenant, backend := range c.Tenants {
+		if _, ok := c.Backends[backend]; !ok {
+			return fmt.Errorf("tenant %q is routed to unknown backend %q", tenant, backend)
+		}
+	}
+	if _, ok := c.Backends[c.DefaultBackend]; c.DefaultBackend != "" && !ok {
+		return fmt.Errorf("unknown default backend %q", c.DefaultBackend)
+	}
+	return nil
+}
+
+// TenantRoutingFactory is a storage.Factory whose readers and writers send the requests
+// of each tenant to the backend of the tenant, according to a RoutingConfig.
+type TenantRoutingFactory struct {
+	config    *RoutingConfig
+	factories map[string]storage.Factory
+}
+
+var (
+	_ storage.Factory        = (*TenantRoutingFactory)(nil)
+	_ storage.ArchiveFactory = (*TenantRoutingFactory)(nil)
+	_ io.Closer              = (*TenantRoutingFactory)(nil)
+)
+
+// NewTenantRoutingFactory creates and initializes the factories of the backends of the config.
+// The metrics of each backend have a "backend" tag with its name.
+func NewTenantRoutingFactory(config *RoutingConfig, metricsFactory metrics.Factory, logger *zap.Logger) (*TenantRoutingFactory, error) {
+	f := &TenantRoutingFactory{
+		config:    config,
+		factories: make(map[string]storage.Factory),
+	}
+	// initialize the backends in a stable order, so that errors are reproducible
+	names := make([]string, 0, len(config.Backends))
+	for name := range config.Backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		factory, err := newBackendFactory(config.Backends[name], metricsFactory.Namespace(metrics.NSOptions{
+			Tags: map[string]string{"backend": name},
+		}), logger.With(zap.String("backend", name)))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to create backend %q: %w", name, err)
+		}
+		f.factories[name] = factory
+	}
+	return f, nil
+}
+
+// newBackendFactory creates a storage factory of the backend type, configured with flags
+// of their own so that several backends of the same type can have different options.
+func newBackendFactory(config BackendConfig, metricsFactory metrics.Factory, logger *zap.Logger) (*pluginstorage.Factory, error) {
+	factory, err := pluginstorage.NewFactory(pluginstorage.FactoryConfig{
+		SpanWriterTypes:         []string{config.Type},
+		SpanReaderType:          config.Type,
+		DependenciesStorageType: config.Type,
+	})
+	if err != nil {
+		return nil, err
+	}
+	flagSet := new(flag.FlagSet)
+	factory.AddFlags(flagSet)
+	flags := new(pflag.FlagSet)
+	flags.AddGoFlagSet(flagSet)
+	v := viper.New()
+	if err := v.BindPFlags(flags); err != nil {
+		return nil, err
+	}
+	for key, value := range config.Options {
+		if flagSet.Lookup(key) == nil {
+			return nil, fmt.Errorf("unknown option %q of storage type %s", key, config.Type)
+		}
+		v.Set(key, value)
+	}
+	factory.InitFromViper(v, logger)
+	if err := factory.Initialize(metricsFactory, logger); err != nil {
+		return nil, err
+	}
+	return factory, nil
+}
+
+// Initialize implements storage.Factory. The backends are initialized by NewTenantRoutingFactory.
+func (*TenantRoutingFactory) Initialize(metrics.Factory, *zap.Logger) error {
+	return nil
+}
+
+// CreateSpanReader implements storage.Factory.
+func (f *TenantRoutingFactory) CreateSpanReader() (spanstore.Reader, error) {
+	readers, err := createComponents(f, storage.Factory.CreateSpanReader)
+	if err != nil {
+		return nil, err
+	}
+	return &routingSpanReader{router: newTenantRouter(f.config, readers, nil)}, nil
+}
+
+// CreateSpanWriter implements storage.Factory.
+func (f *TenantRoutingFactory) CreateSpanWriter() (spanstore.Writer, error) {
+	writers, err := createComponents(f, storage.Factory.CreateSpanWriter)
+	if err != nil {
+		return nil, err
+	}
+	return &routingSpanWriter{router: newTenantRouter(f.config, writers, nil)}, nil
+}
+
+// CreateDependencyReader implements storage.Factory.
+func (f *TenantRoutingFactory) CreateDependencyReader() (dependencystore.Reader, error) {
+	readers, err := createComponents(f, storage.Factory.CreateDependencyReader)
+	if err != nil {
+		return nil, err
+	}
+	return &routingDependencyReader{router: newTenantRouter(f.config, readers, nil)}, nil
+}
+
+// CreateArchiveSpanReader implements storage.ArchiveFactory. The archive is available to the
+// tenants of the backends supporting it, and storage.ErrArchiveStorageNotSupported is returned
+// if none of the backends does.
+func (f *TenantRoutingFactory) CreateArchiveSpanReader() (spanstore.Reader, error) {
+	readers, err := createArchiveComponents(f, storage.ArchiveFactory.CreateArchiveSpanReader)
+	if err != nil {
+		return nil, err
+	}
+	return &routingSpanReader{router: newTenantRouter(f.config, readers, storage.ErrArchiveStorageNotSupported)}, nil
+}
+
+// CreateArchiveSpanWriter implements storage.ArchiveFactory.
+func (f *TenantRoutingFactory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
+	writers, err := createArchiveComponents(f, storage.ArchiveFactory.CreateArchiveSpanWriter)
+	if err != nil {
+		return nil, err
+	}
+	return &routingSpanWriter{router: newTenantRouter(f.config, writers, storage.ErrArchiveStorageNotSupported)}, nil
+}
+
+// Close closes the factories of the backends.
+func (f *TenantRoutingFactory) Close() error {
+	var errs []error
+	for _, factory := range f.factories {
+		if closer, ok := factory.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func createComponents[T any](f *TenantRoutingFactory, create func(storage.Factory) (T, error)) (map[string]T, error) {
+	components := make(map[string]T, len(f.factories))
+	for name, factory := range f.factories {
+		component, err := create(factory)
+		if err != nil {
+			return nil, fmt.Errorf("backend %q: %w", name, err)
+		}
+		components[name] = component
+	}
+	return components, nil
+}
+
+func createArchiveComponents[T any](f *TenantRoutingFactory, create func(storage.ArchiveFactory) (T, error)) (map[string]T, error) {
+	components := make(map[string]T, len(f.factories))
+	for name, factory := range f.factories {
+		archiveFactory, ok := factory.(storage.ArchiveFactory)
+		if !ok {
+			continue
+		}
+		component, err := create(archiveFactory)
+		if errors.Is(err, storage.ErrArchiveStorageNotConfigured) || errors.Is(err, storage.ErrArchiveStorageNotSupported) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("backend %q: %w", name, err)
+		}
+		components[name] = component
+	}
+	if len(components) == 0 {
+		return nil, storage.ErrArchiveStorageNotSupported
+	}
+	return components, nil
+}
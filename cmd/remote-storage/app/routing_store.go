@@ -0,0 +1,178 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// ErrUnknownTenant is returned for the requests of a tenant that is not routed to any backend.
+var ErrUnknownTenant = errors.New("tenant is not routed to any storage backend")
+
+// tenantRouter selects the component, e.g. a span reader, of the backend of the tenant of a request.
+type tenantRouter[T any] struct {
+	tenants        map[string]string
+	defaultBackend string
+	// components of the backends, by backend name
+	components map[string]T
+	// missingErr is returned for the tenants of the backends without the component
+	missingErr error
+}
+
+func newTenantRouter[T any](config *RoutingConfig, components map[string]T, missingErr error) *tenantRouter[T] {
+	return &tenantRouter[T]{
+		tenants:        config.Tenants,
+		defaultBackend: config.DefaultBackend,
+		components:     components,
+		missingErr:     missingErr,
+	}
+}
+
+func (r *tenantRouter[T]) route(ctx context.Context) (T, error) {
+	var zero T
+	tenant := tenancy.GetTenant(ctx)
+	backend, ok := r.tenants[tenant]
+	if !ok {
+		backend = r.defaultBackend
+	}
+	if backend == "" {
+		return zero, fmt.Errorf("%w: %q", ErrUnknownTenant, tenant)
+	}
+	component, ok := r.components[backend]
+	if !ok {
+		return zero, r.missingErr
+	}
+	return component, nil
+}
+
+// routingSpanReader is a spanstore.Reader reading the spans of each tenant from its backend.
+// It implements the optional reader interfaces used by the gRPC handler, so that the native
+// capabilities of the backends are preserved.
+type routingSpanReader struct {
+	router *tenantRouter[spanstore.Reader]
+}
+
+var (
+	_ spanstore.PaginatedReader = (*routingSpanReader)(nil)
+	_ spanstore.StreamingReader = (*routingSpanReader)(nil)
+	_ spanstore.TimeRangeReader = (*routingSpanReader)(nil)
+)
+
+func (r *routingSpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetTrace(ctx, traceID)
+}
+
+func (r *routingSpanReader) GetServices(ctx context.Context) ([]string, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetServices(ctx)
+}
+
+func (r *routingSpanReader) GetOperations(ctx context.Context, query spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetOperations(ctx, query)
+}
+
+func (r *routingSpanReader) FindOperations(ctx context.Context, query spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return spanstore.FindOperations(ctx, reader, query)
+}
+
+func (r *routingSpanReader) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.FindTraces(ctx, query)
+}
+
+func (r *routingSpanReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.FindTraceIDs(ctx, query)
+}
+
+func (r *routingSpanReader) FindTracesPage(ctx context.Context, query *spanstore.TraceQueryParameters) (*spanstore.TracesPage, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return spanstore.FindTracesPage(ctx, reader, query)
+}
+
+func (r *routingSpanReader) StreamTrace(ctx context.Context, traceID model.TraceID, yield func(spans []*model.Span) error) error {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return err
+	}
+	return spanstore.StreamTrace(ctx, reader, traceID, 0, yield)
+}
+
+func (r *routingSpanReader) GetTraceInTimeRange(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return spanstore.GetTraceInTimeRange(ctx, reader, query)
+}
+
+// routingSpanWriter is a spanstore.Writer writing the spans of each tenant to its backend.
+type routingSpanWriter struct {
+	router *tenantRouter[spanstore.Writer]
+}
+
+func (w *routingSpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	writer, err := w.router.route(ctx)
+	if err != nil {
+		return err
+	}
+	return writer.WriteSpan(ctx, span)
+}
+
+// Close closes the writers of all backends.
+func (w *routingSpanWriter) Close() error {
+	var errs []error
+	for _, writer := range w.router.components {
+		if closer, ok := writer.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// routingDependencyReader is a dependencystore.Reader reading the dependencies of each tenant from its backend.
+type routingDependencyReader struct {
+	router *tenantRouter[dependencystore.Reader]
+}
+
+func (r *routingDependencyReader) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetDependencies(ctx, endTs, lookback)
+}
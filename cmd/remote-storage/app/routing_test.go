@@ -0,0 +1,258 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const testRoutingConfig = `{
+  "backends": {
+    "small": {"type": "memory", "options": {"memory.max-traces": "10"}},
+    "large": {"type": "memory"}
+  },
+  "tenants": {"acme": "small", "globex": "large"}
+}`
+
+func writeRoutingConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "routing.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadRoutingConfig(t *testing.T) {
+	config, err := LoadRoutingConfig(writeRoutingConfigFile(t, testRoutingConfig))
+	require.NoError(t, err)
+	assert.Equal(t, &RoutingConfig{
+		Backends: map[string]BackendConfig{
+			"small": {Type: "memory", Options: map[string]string{"memory.max-traces": "10"}},
+			"large": {Type: "memory"},
+		},
+		Tenants: map[string]string{"acme": "small", "globex": "large"},
+	}, config)
+
+	_, err = LoadRoutingConfig(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorContains(t, err, "failed to read routing config file")
+
+	_, err = LoadRoutingConfig(writeRoutingConfigFile(t, `{"backend": {}}`))
+	require.ErrorContains(t, err, "failed to parse routing config file")
+
+	_, err = LoadRoutingConfig(writeRoutingConfigFile(t, `{}`))
+	require.ErrorContains(t, err, "at least one backend")
+}
+
+func TestRoutingConfigValidate(t *testing.T) {
+	backends := map[string]BackendConfig{"default": {Type: "memory"}}
+	tests := []struct {
+		name   string
+		config RoutingConfig
+		err    string
+	}{
+		{
+			name:   "empty type",
+			config: RoutingConfig{Backends: map[string]BackendConfig{"default": {}}},
+			err:    `storage type of backend "default" must not be empty`,
+		},
+		{
+			name:   "unknown tenant backend",
+			config: RoutingConfig{Backends: backends, Tenants: map[string]string{"acme": "other"}},
+			err:    `tenant "acme" is routed to unknown backend "other"`,
+		},
+		{
+			name:   "unknown default backend",
+			config: RoutingConfig{Backends: backends, DefaultBackend: "other"},
+			err:    `unknown default backend "other"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.config.Validate(), tt.err)
+		})
+	}
+}
+
+func TestNewTenantRoutingFactoryErrors(t *testing.T) {
+	_, err := NewTenantRoutingFactory(&RoutingConfig{
+		Backends: map[string]BackendConfig{"default": {Type: "unknown"}},
+	}, metrics.NullFactory, zap.NewNop())
+	require.ErrorContains(t, err, `failed to create backend "default": unknown storage type unknown`)
+
+	_, err = NewTenantRoutingFactory(&RoutingConfig{
+		Backends: map[string]BackendConfig{"default": {Type: "memory", Options: map[string]string{"es.server-urls": "http://es:9200"}}},
+	}, metrics.NullFactory, zap.NewNop())
+	require.ErrorContains(t, err, `unknown option "es.server-urls" of storage type memory`)
+}
+
+func newTestSpan(traceID uint64, service string) *model.Span {
+	return &model.Span{
+		TraceID:       model.NewTraceID(0, traceID),
+		SpanID:        model.NewSpanID(1),
+		OperationName: "op",
+		StartTime:     time.Now(),
+		Process:       model.NewProcess(service, nil),
+	}
+}
+
+func TestTenantRoutingFactory(t *testing.T) {
+	config, err := LoadRoutingConfig(writeRoutingConfigFile(t, testRoutingConfig))
+	require.NoError(t, err)
+	f, err := NewTenantRoutingFactory(config, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+	reader, err := f.CreateSpanReader()
+	require.NoError(t, err)
+	writer, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	depReader, err := f.CreateDependencyReader()
+	require.NoError(t, err)
+
+	acme := tenancy.WithTenant(context.Background(), "acme")
+	globex := tenancy.WithTenant(context.Background(), "globex")
+	unknown := tenancy.WithTenant(context.Background(), "initech")
+	require.NoError(t, writer.WriteSpan(acme, newTestSpan(1, "acme-service")))
+	require.NoError(t, writer.WriteSpan(globex, newTestSpan(2, "globex-service")))
+	require.ErrorIs(t, writer.WriteSpan(unknown, newTestSpan(3, "initech-service")), ErrUnknownTenant)
+
+	services, err := reader.GetServices(acme)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme-service"}, services)
+	services, err = reader.GetServices(globex)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"globex-service"}, services)
+
+	trace, err := reader.GetTrace(acme, model.NewTraceID(0, 1))
+	require.NoError(t, err)
+	assert.Len(t, trace.Spans, 1)
+	_, err = reader.GetTrace(globex, model.NewTraceID(0, 1))
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+	operations, err := reader.GetOperations(globex, spanstore.OperationQueryParameters{ServiceName: "globex-service"})
+	require.NoError(t, err)
+	assert.Len(t, operations, 1)
+	operations, err = spanstore.FindOperations(globex, reader, spanstore.OperationQueryParameters{ServiceName: "globex-service", Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, operations, 1)
+
+	query := &spanstore.TraceQueryParameters{ServiceName: "acme-service", NumTraces: 10}
+	traces, err := reader.FindTraces(acme, query)
+	require.NoError(t, err)
+	assert.Len(t, traces, 1)
+	page, err := spanstore.FindTracesPage(acme, reader, query)
+	require.NoError(t, err)
+	assert.Len(t, page.Traces, 1)
+	var streamed []*model.Span
+	require.NoError(t, spanstore.StreamTrace(acme, reader, model.NewTraceID(0, 1), 0, func(spans []*model.Span) error {
+		streamed = append(streamed, spans...)
+		return nil
+	}))
+	assert.Len(t, streamed, 1)
+	timeRange := spanstore.GetTraceParameters{
+		TraceID:   model.NewTraceID(0, 1),
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+	}
+	trace, err = spanstore.GetTraceInTimeRange(acme, reader, timeRange)
+	require.NoError(t, err)
+	assert.Len(t, trace.Spans, 1)
+
+	_, err = depReader.GetDependencies(globex, time.Now(), time.Hour)
+	require.NoError(t, err)
+
+	// every request of an unknown tenant is rejected
+	_, err = reader.GetTrace(unknown, model.NewTraceID(0, 1))
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	_, err = reader.GetServices(unknown)
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	_, err = reader.GetOperations(unknown, spanstore.OperationQueryParameters{})
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	_, err = spanstore.FindOperations(unknown, reader, spanstore.OperationQueryParameters{Limit: 1})
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	_, err = reader.FindTraces(unknown, query)
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	_, err = reader.FindTraceIDs(unknown, query)
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	_, err = spanstore.FindTracesPage(unknown, reader, query)
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	err = spanstore.StreamTrace(unknown, reader, model.NewTraceID(0, 1), 0, func([]*model.Span) error { return nil })
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	_, err = spanstore.GetTraceInTimeRange(unknown, reader, timeRange)
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	_, err = depReader.GetDependencies(unknown, time.Now(), time.Hour)
+	require.ErrorIs(t, err, ErrUnknownTenant)
+
+	require.NoError(t, writer.(*routingSpanWriter).Close())
+}
+
+func TestTenantRoutingFactoryDefaultBackend(t *testing.T) {
+	config, err := LoadRoutingConfig(writeRoutingConfigFile(t, testRoutingConfig))
+	require.NoError(t, err)
+	config.DefaultBackend = "large"
+	f, err := NewTenantRoutingFactory(config, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	defer f.Close()
+
+	writer, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	reader, err := f.CreateSpanReader()
+	require.NoError(t, err)
+	initech := tenancy.WithTenant(context.Background(), "initech")
+	require.NoError(t, writer.WriteSpan(initech, newTestSpan(1, "initech-service")))
+
+	services, err := reader.GetServices(initech)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"initech-service"}, services)
+}
+
+func TestTenantRoutingFactoryArchive(t *testing.T) {
+	config := &RoutingConfig{
+		Backends: map[string]BackendConfig{
+			"memory":    {Type: "memory"},
+			"blackhole": {Type: "blackhole"},
+		},
+		Tenants: map[string]string{"acme": "memory", "globex": "blackhole"},
+	}
+	f, err := NewTenantRoutingFactory(config, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	defer f.Close()
+
+	writer, err := f.CreateArchiveSpanWriter()
+	require.NoError(t, err)
+	reader, err := f.CreateArchiveSpanReader()
+	require.NoError(t, err)
+	acme := tenancy.WithTenant(context.Background(), "acme")
+	require.NoError(t, writer.WriteSpan(acme, newTestSpan(1, "acme-service")))
+	_, err = reader.GetTrace(acme, model.NewTraceID(0, 1))
+	require.NoError(t, err)
+}
+
+func TestTenantRoutingFactoryArchiveNotSupported(t *testing.T) {
+	f := &TenantRoutingFactory{
+		config:    &RoutingConfig{Tenants: map[string]string{"acme": "default"}},
+		factories: map[string]storage.Factory{},
+	}
+	_, err := f.CreateArchiveSpanReader()
+	require.ErrorIs(t, err, storage.ErrArchiveStorageNotSupported)
+	_, err = f.CreateArchiveSpanWriter()
+	require.ErrorIs(t, err, storage.ErrArchiveStorageNotSupported)
+
+	router := newTenantRouter(f.config, map[string]spanstore.Reader{}, storage.ErrArchiveStorageNotSupported)
+	_, err = (&routingSpanReader{router: router}).GetTrace(tenancy.WithTenant(context.Background(), "acme"), model.NewTraceID(0, 1))
+	require.ErrorIs(t, err, storage.ErrArchiveStorageNotSupported)
+}
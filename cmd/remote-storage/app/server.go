@@ -25,7 +25,10 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/pkg/cache"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
 	"github.com/jaegertracing/jaeger/storage"
@@ -45,13 +48,20 @@ type Server struct {
 }
 
 // NewServer creates and initializes Server.
-func NewServer(options *Options, storageFactory storage.Factory, tm *tenancy.Manager, logger *zap.Logger, healthcheck *healthcheck.HealthCheck) (*Server, error) {
-	handler, err := createGRPCHandler(storageFactory, logger)
+func NewServer(
+	options *Options,
+	storageFactory storage.Factory,
+	tm *tenancy.Manager,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+	healthcheck *healthcheck.HealthCheck,
+) (*Server, error) {
+	handler, err := createGRPCHandler(options, storageFactory, metricsFactory, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	grpcServer, err := createGRPCServer(options, tm, handler, logger)
+	grpcServer, err := createGRPCServer(options, tm, handler, metricsFactory, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -64,11 +74,16 @@ func NewServer(options *Options, storageFactory storage.Factory, tm *tenancy.Man
 	}, nil
 }
 
-func createGRPCHandler(f storage.Factory, logger *zap.Logger) (*shared.GRPCHandler, error) {
+func createGRPCHandler(opts *Options, f storage.Factory, metricsFactory metrics.Factory, logger *zap.Logger) (*shared.GRPCHandler, error) {
 	reader, err := f.CreateSpanReader()
 	if err != nil {
 		return nil, err
 	}
+	var cachingReader *cachingSpanReader
+	if opts.CacheMaxEntries > 0 {
+		cachingReader = newCachingSpanReader(reader, opts.CacheMaxEntries, &cache.Options{TTL: opts.CacheTTL}, metricsFactory)
+		reader = cachingReader
+	}
 	writer, err := f.CreateSpanWriter()
 	if err != nil {
 		return nil, err
@@ -92,14 +107,47 @@ func createGRPCHandler(f storage.Factory, logger *zap.Logger) (*shared.GRPCHandl
 	impl.ArchiveSpanReader = func() spanstore.Reader { return qOpts.ArchiveSpanReader }
 	impl.ArchiveSpanWriter = func() spanstore.Writer { return qOpts.ArchiveSpanWriter }
 
+	// the purge RPCs are unimplemented unless the storage supports them and they are enabled
+	if pf, ok := f.(storage.PurgerFactory); ok {
+		if purger, err := pf.CreatePurger(); err == nil {
+			if cachingReader != nil {
+				purger = cachingReader.purger(purger)
+			}
+			impl.Purger = func() spanstore.Purger { return purger }
+		} else {
+			logger.Info("Purging traces is not available", zap.Error(err))
+		}
+	}
+
+	// the storages writing the traces in the OTLP format advertise it to the clients
+	if otlpWriter, ok := writer.(spanstore.OTLPWriter); ok {
+		impl.OTLPSpanWriter = func() spanstore.OTLPWriter { return otlpWriter }
+	}
+
 	handler := shared.NewGRPCHandler(impl)
 	return handler, nil
 }
 
-func createGRPCServer(opts *Options, tm *tenancy.Manager, handler *shared.GRPCHandler, logger *zap.Logger) (*grpc.Server, error) {
+func createGRPCServer(
+	opts *Options,
+	tm *tenancy.Manager,
+	handler *shared.GRPCHandler,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+) (*grpc.Server, error) {
 	var grpcOpts []grpc.ServerOption
+	if opts.MaxMessageSize > 0 {
+		grpcOpts = append(grpcOpts, grpc.MaxRecvMsgSize(opts.MaxMessageSize))
+	}
+	if opts.MaxConcurrentStreams > 0 {
+		grpcOpts = append(grpcOpts, grpc.MaxConcurrentStreams(opts.MaxConcurrentStreams))
+	}
+	if opts.MaxMessageSize > 0 || opts.MaxConcurrentStreams > 0 {
+		grpcOpts = append(grpcOpts, grpc.StatsHandler(newLimitsStatsHandler(opts.MaxConcurrentStreams, metricsFactory)))
+	}
 
 	if opts.TLSGRPC.Enabled {
+		opts.TLSGRPC.MetricsFactory = metricsFactory
 		tlsCfg, err := opts.TLSGRPC.Config(logger)
 		if err != nil {
 			return nil, fmt.Errorf("invalid TLS config: %w", err)
@@ -107,12 +155,28 @@ func createGRPCServer(opts *Options, tm *tenancy.Manager, handler *shared.GRPCHa
 		creds := credentials.NewTLS(tlsCfg)
 		grpcOpts = append(grpcOpts, grpc.Creds(creds))
 	}
+	var streamInterceptors []grpc.StreamServerInterceptor
+	var unaryInterceptors []grpc.UnaryServerInterceptor
 	if tm.Enabled {
-		grpcOpts = append(grpcOpts,
-			grpc.StreamInterceptor(tenancy.NewGuardingStreamInterceptor(tm)),
-			grpc.UnaryInterceptor(tenancy.NewGuardingUnaryInterceptor(tm)),
-		)
+		streamInterceptors = append(streamInterceptors, tenancy.NewGuardingStreamInterceptor(tm))
+		unaryInterceptors = append(unaryInterceptors, tenancy.NewGuardingUnaryInterceptor(tm))
+	}
+	// the unauthorized requests are rejected before they are counted by the rate limiter
+	if opts.Authorizer != nil {
+		authz := newAuthzInterceptor(opts.Authorizer, metricsFactory, logger)
+		streamInterceptors = append(streamInterceptors, authz.streamInterceptor())
+		unaryInterceptors = append(unaryInterceptors, authz.unaryInterceptor())
 	}
+	// the rate limiter runs after the tenancy guard, to identify the clients by their tenant
+	if opts.RateLimit > 0 {
+		rateLimiter := newClientRateLimiter(opts.RateLimit, opts.RateLimitBurst, metricsFactory, logger)
+		streamInterceptors = append(streamInterceptors, rateLimiter.streamInterceptor())
+		unaryInterceptors = append(unaryInterceptors, rateLimiter.unaryInterceptor())
+	}
+	grpcOpts = append(grpcOpts,
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+	)
 
 	server := grpc.NewServer(grpcOpts...)
 	reflection.Register(server)
@@ -123,10 +187,14 @@ func createGRPCServer(opts *Options, tm *tenancy.Manager, handler *shared.GRPCHa
 
 // Start gRPC server concurrently
 func (s *Server) Start() error {
-	listener, err := net.Listen("tcp", s.opts.GRPCHostPort)
+	listener, err := netutils.Listen(s.opts.GRPCAddressFamily, s.opts.GRPCHostPort)
 	if err != nil {
 		return err
 	}
+	if err := netutils.ChmodSocket(listener, s.opts.GRPCSocketPermissions); err != nil {
+		listener.Close()
+		return err
+	}
 	s.logger.Info("Starting GRPC server", zap.Stringer("addr", listener.Addr()))
 	s.grpcConn = listener
 	s.wg.Add(1)
@@ -17,27 +17,41 @@ package app
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/internal/grpctest"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	grpcStorage "github.com/jaegertracing/jaeger/plugin/storage/grpc"
+	grpcConfig "github.com/jaegertracing/jaeger/plugin/storage/grpc/config"
+	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/ports"
 	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/storage"
 	depStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
 	factoryMocks "github.com/jaegertracing/jaeger/storage/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
@@ -57,6 +71,7 @@ func TestNewServer_CreateStorageErrors(t *testing.T) {
 			&Options{GRPCHostPort: ":0"},
 			factory,
 			tenancy.NewManager(&tenancy.Options{}),
+			metrics.NullFactory,
 			zap.NewNop(),
 			healthcheck.New(),
 		)
@@ -128,6 +143,7 @@ func TestNewServer_TLSConfigError(t *testing.T) {
 		&Options{GRPCHostPort: ":8081", TLSGRPC: tlsCfg},
 		storageMocks.factory,
 		tenancy.NewManager(&tenancy.Options{}),
+		metrics.NullFactory,
 		zap.NewNop(),
 		healthcheck.New(),
 	)
@@ -137,7 +153,7 @@ func TestNewServer_TLSConfigError(t *testing.T) {
 
 func TestCreateGRPCHandler(t *testing.T) {
 	storageMocks := newStorageMocks()
-	h, err := createGRPCHandler(storageMocks.factory, zap.NewNop())
+	h, err := createGRPCHandler(&Options{}, storageMocks.factory, metrics.NullFactory, zap.NewNop())
 	require.NoError(t, err)
 
 	storageMocks.writer.On("WriteSpan", mock.Anything, mock.Anything).Return(errors.New("writer error"))
@@ -163,6 +179,121 @@ func TestCreateGRPCHandler(t *testing.T) {
 	assert.Contains(t, err.Error(), "not implemented")
 }
 
+func TestCreateGRPCHandlerWithCache(t *testing.T) {
+	storageMocks := newStorageMocks()
+	h, err := createGRPCHandler(&Options{CacheMaxEntries: 10, CacheTTL: time.Minute}, storageMocks.factory, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+
+	storageMocks.reader.On("GetServices", mock.Anything).Return([]string{"frontend"}, nil).Once()
+	for i := 0; i < 2; i++ {
+		res, err := h.GetServices(context.Background(), &storage_v1.GetServicesRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend"}, res.Services)
+	}
+	storageMocks.reader.AssertExpectations(t)
+}
+
+type purgerFactory struct {
+	*factoryMocks.Factory
+	purger spanstore.Purger
+	err    error
+}
+
+func (f *purgerFactory) CreatePurger() (spanstore.Purger, error) {
+	return f.purger, f.err
+}
+
+func TestCreateGRPCHandlerWithPurger(t *testing.T) {
+	storageMocks := newStorageMocks()
+	purger := new(spanStoreMocks.Purger)
+	purger.On("PurgeAll", mock.Anything).Return(nil)
+
+	h, err := createGRPCHandler(&Options{}, &purgerFactory{Factory: storageMocks.factory, purger: purger}, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	_, err = h.PurgeAll(context.Background(), &storage_v1.PurgeAllRequest{})
+	require.NoError(t, err)
+	purger.AssertExpectations(t)
+
+	h, err = createGRPCHandler(&Options{}, &purgerFactory{Factory: storageMocks.factory, err: storage.ErrPurgerNotEnabled}, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	_, err = h.PurgeAll(context.Background(), &storage_v1.PurgeAllRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+type otlpWriter struct {
+	*spanStoreMocks.Writer
+}
+
+func (otlpWriter) WriteTraces(context.Context, ptrace.Traces) error {
+	return nil
+}
+
+func TestCreateGRPCHandlerWithOTLPWriter(t *testing.T) {
+	storageMocks := newStorageMocks()
+	h, err := createGRPCHandler(&Options{}, storageMocks.factory, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	negotiated, err := h.Negotiate(context.Background(), &storage_v1.NegotiateRequest{Version: shared.StorageAPIVersion})
+	require.NoError(t, err)
+	assert.NotContains(t, negotiated.Features, shared.FeatureOTLPSpanWriter)
+
+	factory := new(factoryMocks.Factory)
+	factory.On("CreateSpanReader").Return(storageMocks.reader, nil)
+	factory.On("CreateSpanWriter").Return(otlpWriter{Writer: storageMocks.writer}, nil)
+	factory.On("CreateDependencyReader").Return(storageMocks.depReader, nil)
+	h, err = createGRPCHandler(&Options{}, factory, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	negotiated, err = h.Negotiate(context.Background(), &storage_v1.NegotiateRequest{Version: shared.StorageAPIVersion})
+	require.NoError(t, err)
+	assert.Contains(t, negotiated.Features, shared.FeatureOTLPSpanWriter)
+}
+
+func TestServerOTLPWrites(t *testing.T) {
+	memoryFactory := memory.NewFactory()
+	require.NoError(t, memoryFactory.Initialize(metrics.NullFactory, zap.NewNop()))
+	server, err := NewServer(
+		&Options{GRPCHostPort: ":0"},
+		memoryFactory,
+		tenancy.NewManager(&tenancy.Options{}),
+		metrics.NullFactory,
+		zap.NewNop(),
+		healthcheck.New(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Close()
+
+	grpcFactory, err := grpcStorage.NewFactoryWithConfig(grpcConfig.Configuration{
+		RemoteServerAddr:     server.grpcConn.Addr().String(),
+		RemoteConnectTimeout: time.Second,
+		RemoteOTLPWrites:     true,
+	}, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	defer grpcFactory.Close()
+	writer, err := grpcFactory.CreateSpanWriter()
+	require.NoError(t, err)
+	// the memory storage writes the traces in the OTLP format, so the client sends them as is
+	require.Implements(t, (*spanstore.OTLPWriter)(nil), writer)
+
+	td := ptrace.NewTraces()
+	resourceSpans := td.ResourceSpans().AppendEmpty()
+	resourceSpans.Resource().Attributes().PutStr("service.name", "frontend")
+	span := resourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(pcommon.TraceID([16]byte{15: 1}))
+	span.SetSpanID(pcommon.SpanID([8]byte{7: 1}))
+	span.SetName("GET /")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, spanstore.WriteTraces(ctx, writer, td))
+
+	reader, err := memoryFactory.CreateSpanReader()
+	require.NoError(t, err)
+	trace, err := reader.GetTrace(ctx, model.NewTraceID(0, 1))
+	require.NoError(t, err)
+	require.Len(t, trace.Spans, 1)
+	assert.Equal(t, "frontend", trace.Spans[0].Process.ServiceName)
+	assert.Equal(t, "GET /", trace.Spans[0].OperationName)
+}
+
 var testCases = []struct {
 	name              string
 	TLS               tlscfg.Options
@@ -333,6 +464,7 @@ func TestServerGRPCTLS(t *testing.T) {
 				serverOptions,
 				storageMocks.factory,
 				tm,
+				metrics.NullFactory,
 				flagsSvc.Logger,
 				flagsSvc.HC(),
 			)
@@ -380,6 +512,7 @@ func TestServerHandlesPortZero(t *testing.T) {
 		&Options{GRPCHostPort: ":0"},
 		storageMocks.factory,
 		tenancy.NewManager(&tenancy.Options{}),
+		metrics.NullFactory,
 		flagsSvc.Logger,
 		flagsSvc.HC(),
 	)
@@ -400,6 +533,27 @@ func TestServerHandlesPortZero(t *testing.T) {
 	assert.Equal(t, healthcheck.Unavailable, flagsSvc.HC().Get())
 }
 
+func TestServerUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote-storage.sock")
+	storageMocks := newStorageMocks()
+	server, err := NewServer(
+		&Options{GRPCHostPort: "unix://" + path, GRPCSocketPermissions: 0o660},
+		storageMocks.factory,
+		tenancy.NewManager(&tenancy.Options{}),
+		metrics.NullFactory,
+		zap.NewNop(),
+		healthcheck.New(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Close()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o660), info.Mode().Perm())
+	validateGRPCServer(t, "unix://"+path, server.grpcServer)
+}
+
 func validateGRPCServer(t *testing.T, hostPort string, server *grpc.Server) {
 	grpctest.ReflectionServiceValidator{
 		HostPort: hostPort,
@@ -16,6 +16,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/internal/flags"
 	"github.com/jaegertracing/jaeger/cmd/internal/printconfig"
 	"github.com/jaegertracing/jaeger/cmd/internal/status"
+	"github.com/jaegertracing/jaeger/cmd/internal/validate"
 	"github.com/jaegertracing/jaeger/cmd/remote-storage/app"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
@@ -36,6 +38,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/version"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
+	storageapi "github.com/jaegertracing/jaeger/storage"
 )
 
 const serviceName = "jaeger-remote-storage"
@@ -71,13 +74,28 @@ func main() {
 				logger.Fatal("Failed to parse options", zap.Error(err))
 			}
 
-			storageFactory.InitFromViper(v, logger)
-			if err := storageFactory.Initialize(baseFactory, logger); err != nil {
-				logger.Fatal("Failed to init storage factory", zap.Error(err))
+			var backend interface {
+				storageapi.Factory
+				io.Closer
+			} = storageFactory
+			if opts.RoutingConfigFile != "" {
+				routingConfig, err := app.LoadRoutingConfig(opts.RoutingConfigFile)
+				if err != nil {
+					logger.Fatal("Failed to load routing config", zap.Error(err))
+				}
+				backend, err = app.NewTenantRoutingFactory(routingConfig, baseFactory, logger.Named(flags.StorageLoggerName))
+				if err != nil {
+					logger.Fatal("Failed to init storage backends", zap.Error(err))
+				}
+			} else {
+				storageFactory.InitFromViper(v, logger)
+				if err := storageFactory.Initialize(baseFactory, logger.Named(flags.StorageLoggerName)); err != nil {
+					logger.Fatal("Failed to init storage factory", zap.Error(err))
+				}
 			}
 
 			tm := tenancy.NewManager(&opts.Tenancy)
-			server, err := app.NewServer(opts, storageFactory, tm, svc.Logger, svc.HC())
+			server, err := app.NewServer(opts, backend, tm, metricsFactory, svc.Logger, svc.HC())
 			if err != nil {
 				logger.Fatal("Failed to create server", zap.Error(err))
 			}
@@ -88,7 +106,7 @@ func main() {
 
 			svc.RunAndThen(func() {
 				server.Close()
-				if err := storageFactory.Close(); err != nil {
+				if err := backend.Close(); err != nil {
 					logger.Error("Failed to close storage factory", zap.Error(err))
 				}
 			})
@@ -101,6 +119,18 @@ func main() {
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.QueryAdminHTTP))
 	command.AddCommand(printconfig.Command(v))
+	command.AddCommand(validate.Command(v, func(v *viper.Viper, logger *zap.Logger) error {
+		opts, err := new(app.Options).InitFromViper(v, logger)
+		if err != nil {
+			return err
+		}
+		if opts.RoutingConfigFile != "" {
+			_, err := app.LoadRoutingConfig(opts.RoutingConfigFile)
+			return err
+		}
+		storageFactory.InitFromViper(v, logger)
+		return nil
+	}))
 
 	config.AddFlags(
 		v,
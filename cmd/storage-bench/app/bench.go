@@ -0,0 +1,168 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package app runs the write and read workloads of jaeger-storage-bench against a span storage,
+// and reports their throughput and latency percentiles.
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// Bench runs the workloads of the options against a span storage.
+type Bench struct {
+	opts    *Options
+	writer  spanstore.Writer
+	reader  spanstore.Reader
+	logger  *zap.Logger
+	timeNow func() time.Time
+	traces  traceIDPool
+}
+
+// NewBench creates a benchmark of the span writer and reader.
+func NewBench(opts *Options, writer spanstore.Writer, reader spanstore.Reader, logger *zap.Logger) *Bench {
+	return &Bench{
+		opts:    opts,
+		writer:  writer,
+		reader:  reader,
+		logger:  logger,
+		timeNow: time.Now,
+	}
+}
+
+// Run runs the writers and the readers concurrently for the duration of the options,
+// or until the context is canceled, and reports the latencies of the operations.
+//
+// The latencies of the writes are the ones of the span writer, which may be lower than
+// the latencies of the storage when the writer is asynchronous, e.g. with Elasticsearch.
+func (b *Bench) Run(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, b.opts.Duration)
+	defer cancel()
+
+	b.logger.Info("Starting the benchmark",
+		zap.Duration("duration", b.opts.Duration),
+		zap.Int("writers", b.opts.Writers),
+		zap.Int("readers", b.opts.Readers))
+	start := b.timeNow()
+	var wg sync.WaitGroup
+	results := make([]map[string]*latencies, b.opts.Writers+b.opts.Readers)
+	for i := range results {
+		results[i] = make(map[string]*latencies)
+		gen := newGenerator(b.opts, b.opts.Seed+int64(i))
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i < b.opts.Writers {
+				b.write(ctx, gen, results[i])
+			} else {
+				b.read(ctx, gen, start, results[i])
+			}
+		}(i)
+	}
+	wg.Wait()
+	elapsed := b.timeNow().Sub(start)
+
+	merged := make(map[string]*latencies)
+	for _, result := range results {
+		for op, l := range result {
+			if m, ok := merged[op]; ok {
+				m.merge(l)
+			} else {
+				merged[op] = l
+			}
+		}
+	}
+	report := &Report{Elapsed: elapsed}
+	operations := []string{writeOperation}
+	for _, query := range queries {
+		operations = append(operations, string(query))
+	}
+	for _, op := range operations {
+		if l, ok := merged[op]; ok {
+			report.Operations = append(report.Operations, newOperationReport(op, l, elapsed))
+		}
+	}
+	return report
+}
+
+// write writes traces until the context is done, at the write rate divided by the number of writers.
+func (b *Bench) write(ctx context.Context, gen *generator, result map[string]*latencies) {
+	l := newLatencies()
+	result[writeOperation] = l
+
+	var tick <-chan time.Time
+	if b.opts.WriteRate > 0 {
+		interval := time.Duration(float64(time.Second) * float64(b.opts.SpansPerTrace*b.opts.Writers) / b.opts.WriteRate)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+			}
+		}
+		spans := gen.trace(b.timeNow())
+		written := true
+		for _, span := range spans {
+			if ctx.Err() != nil {
+				return
+			}
+			start := b.timeNow()
+			err := b.writer.WriteSpan(ctx, span)
+			if ctx.Err() != nil {
+				// the writes interrupted by the end of the benchmark are not counted
+				return
+			}
+			l.record(b.timeNow().Sub(start), err)
+			written = written && err == nil
+		}
+		if written {
+			b.traces.add(spans[0].TraceID)
+		}
+	}
+}
+
+// read runs the queries of the query mix until the context is done.
+func (b *Bench) read(ctx context.Context, gen *generator, benchStart time.Time, result map[string]*latencies) {
+	for ctx.Err() == nil {
+		query := gen.query()
+		start := b.timeNow()
+		var err error
+		switch query {
+		case QueryGetTrace:
+			traceID, ok := b.traces.random(gen.rnd)
+			if !ok {
+				// no trace written yet, e.g. without writers
+				query = QueryGetServices
+				_, err = b.reader.GetServices(ctx)
+			} else {
+				_, err = b.reader.GetTrace(ctx, traceID)
+			}
+		case QueryFindTraces:
+			_, err = b.reader.FindTraces(ctx, gen.findTracesQuery(benchStart, start))
+		case QueryGetServices:
+			_, err = b.reader.GetServices(ctx)
+		case QueryGetOperations:
+			_, err = b.reader.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: gen.service()})
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		l, ok := result[string(query)]
+		if !ok {
+			l = newLatencies()
+			result[string(query)] = l
+		}
+		l.record(b.timeNow().Sub(start), err)
+	}
+}
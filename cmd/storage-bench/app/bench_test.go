@@ -0,0 +1,78 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) WriteSpan(context.Context, *model.Span) error {
+	return errors.New("storage unavailable")
+}
+
+func TestBenchRun(t *testing.T) {
+	store := memory.NewStore()
+	report := NewBench(newTestOptions(), store, store, zap.NewNop()).Run(context.Background())
+
+	assert.GreaterOrEqual(t, report.Elapsed, 100*time.Millisecond)
+	require.NotEmpty(t, report.Operations)
+	assert.Equal(t, writeOperation, report.Operations[0].Operation)
+	for _, op := range report.Operations {
+		assert.Positive(t, op.Count, op.Operation)
+		assert.Zero(t, op.Errors, op.Operation)
+	}
+	services, err := store.GetServices(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, services)
+}
+
+func TestBenchRunWriteRate(t *testing.T) {
+	opts := newTestOptions()
+	opts.Readers = 0
+	opts.WriteRate = 500
+	store := memory.NewStore()
+	report := NewBench(opts, store, store, zap.NewNop()).Run(context.Background())
+
+	require.Len(t, report.Operations, 1)
+	// 500 spans/s for 100ms, i.e. 10 traces of 5 spans at most
+	assert.LessOrEqual(t, report.Operations[0].Count, int64(50))
+}
+
+func TestBenchRunErrors(t *testing.T) {
+	opts := newTestOptions()
+	opts.Readers = 1
+	opts.QueryMix = map[Query]int{QueryGetTrace: 1}
+	store := memory.NewStore()
+	report := NewBench(opts, failingWriter{}, store, zap.NewNop()).Run(context.Background())
+
+	require.Len(t, report.Operations, 2)
+	assert.Equal(t, writeOperation, report.Operations[0].Operation)
+	assert.Zero(t, report.Operations[0].Count)
+	assert.Positive(t, report.Operations[0].Errors)
+	require.EqualError(t, report.Operations[0].FirstError, "storage unavailable")
+	// the traces not written cannot be read
+	assert.Equal(t, string(QueryGetServices), report.Operations[1].Operation)
+}
+
+func TestBenchRunCanceled(t *testing.T) {
+	opts := newTestOptions()
+	opts.Duration = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	store := memory.NewStore()
+	report := NewBench(opts, store, store, zap.NewNop()).Run(ctx)
+	assert.Less(t, report.Elapsed, time.Second)
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	durationFlag       = "duration"
+	writersFlag        = "writers"
+	readersFlag        = "readers"
+	writeRateFlag      = "write-rate"
+	servicesFlag       = "services"
+	operationsFlag     = "operations"
+	spansPerTraceFlag  = "spans-per-trace"
+	minTagsFlag        = "min-tags"
+	maxTagsFlag        = "max-tags"
+	tagValueLengthFlag = "tag-value-length"
+	tagCardinalityFlag = "tag-cardinality"
+	queryMixFlag       = "query-mix"
+	seedFlag           = "seed"
+
+	defaultQueryMix = "get-trace=50,find-traces=30,get-services=10,get-operations=10"
+)
+
+// Options are the parameters of the workloads of the benchmark.
+type Options struct {
+	// Duration is how long the workloads run.
+	Duration time.Duration
+	// Writers is the number of concurrent writers.
+	Writers int
+	// Readers is the number of concurrent readers.
+	Readers int
+	// WriteRate is the maximum number of spans written per second by all the writers, unlimited if 0.
+	WriteRate float64
+	// Services is the number of services of the generated spans.
+	Services int
+	// Operations is the number of operations of each service.
+	Operations int
+	// SpansPerTrace is the number of spans of the generated traces.
+	SpansPerTrace int
+	// MinTags and MaxTags are the bounds of the uniformly distributed number of tags of the spans.
+	MinTags int
+	MaxTags int
+	// TagValueLength is the length in bytes of the tag values.
+	TagValueLength int
+	// TagCardinality is the number of distinct values of each tag key.
+	TagCardinality int
+	// QueryMix are the relative weights of the queries run by the readers.
+	QueryMix map[Query]int
+	// Seed initializes the random generators of the workloads, for repeatable runs.
+	Seed int64
+}
+
+// AddFlags adds the flags of the benchmark options.
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Duration(durationFlag, time.Minute, "How long the workloads run")
+	flagSet.Int(writersFlag, 4, "The number of concurrent writers")
+	flagSet.Int(readersFlag, 2, "The number of concurrent readers")
+	flagSet.Float64(writeRateFlag, 0, "The maximum number of spans written per second by all the writers (unlimited if 0)")
+	flagSet.Int(servicesFlag, 10, "The number of services of the generated spans")
+	flagSet.Int(operationsFlag, 20, "The number of operations of each service")
+	flagSet.Int(spansPerTraceFlag, 10, "The number of spans of the generated traces")
+	flagSet.Int(minTagsFlag, 5, "The minimum number of tags of the generated spans")
+	flagSet.Int(maxTagsFlag, 15, "The maximum number of tags of the generated spans")
+	flagSet.Int(tagValueLengthFlag, 32, "The length in bytes of the values of the tags")
+	flagSet.Int(tagCardinalityFlag, 100, "The number of distinct values of each tag key")
+	flagSet.String(queryMixFlag, defaultQueryMix, "The relative weights of the queries run by the readers, as a comma separated list of query=weight. "+
+		"The queries are get-trace, find-traces, get-services and get-operations")
+	flagSet.Int64(seedFlag, 1, "The seed of the random generators of the workloads")
+}
+
+// InitFromViper initializes the options from the flags, and validates them.
+func (o *Options) InitFromViper(v *viper.Viper) (*Options, error) {
+	o.Duration = v.GetDuration(durationFlag)
+	o.Writers = v.GetInt(writersFlag)
+	o.Readers = v.GetInt(readersFlag)
+	o.WriteRate = v.GetFloat64(writeRateFlag)
+	o.Services = v.GetInt(servicesFlag)
+	o.Operations = v.GetInt(operationsFlag)
+	o.SpansPerTrace = v.GetInt(spansPerTraceFlag)
+	o.MinTags = v.GetInt(minTagsFlag)
+	o.MaxTags = v.GetInt(maxTagsFlag)
+	o.TagValueLength = v.GetInt(tagValueLengthFlag)
+	o.TagCardinality = v.GetInt(tagCardinalityFlag)
+	o.Seed = v.GetInt64(seedFlag)
+	queryMix, err := parseQueryMix(v.GetString(queryMixFlag))
+	if err != nil {
+		return o, err
+	}
+	o.QueryMix = queryMix
+	return o, o.validate()
+}
+
+func (o *Options) validate() error {
+	switch {
+	case o.Duration <= 0:
+		return errors.New("the duration must be positive")
+	case o.Writers < 0 || o.Readers < 0:
+		return errors.New("the numbers of writers and readers cannot be negative")
+	case o.Writers == 0 && o.Readers == 0:
+		return errors.New("at least one writer or reader is required")
+	case o.Services < 1 || o.Operations < 1 || o.SpansPerTrace < 1 || o.TagCardinality < 1:
+		return errors.New("the numbers of services, operations, spans per trace and tag values must be positive")
+	case o.MinTags < 0 || o.MaxTags < o.MinTags:
+		return fmt.Errorf("invalid number of tags between %d and %d", o.MinTags, o.MaxTags)
+	}
+	return nil
+}
+
+func parseQueryMix(s string) (map[Query]int, error) {
+	mix := make(map[Query]int)
+	total := 0
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid query weight %q, expecting query=weight", pair)
+		}
+		query := Query(strings.TrimSpace(kv[0]))
+		if !query.valid() {
+			return nil, fmt.Errorf("unknown query %q", query)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight of query %q: %q", query, kv[1])
+		}
+		mix[query] = weight
+		total += weight
+	}
+	if total == 0 {
+		return nil, errors.New("the query mix requires a positive weight")
+	}
+	return mix, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsWithDefaultFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{}))
+	opts, err := new(Options).InitFromViper(v)
+	require.NoError(t, err)
+	assert.Equal(t, &Options{
+		Duration:       time.Minute,
+		Writers:        4,
+		Readers:        2,
+		Services:       10,
+		Operations:     20,
+		SpansPerTrace:  10,
+		MinTags:        5,
+		MaxTags:        15,
+		TagValueLength: 32,
+		TagCardinality: 100,
+		QueryMix: map[Query]int{
+			QueryGetTrace:      50,
+			QueryFindTraces:    30,
+			QueryGetServices:   10,
+			QueryGetOperations: 10,
+		},
+		Seed: 1,
+	}, opts)
+}
+
+func TestOptionsWithFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--duration=10s",
+		"--writers=8",
+		"--readers=0",
+		"--write-rate=1000",
+		"--spans-per-trace=3",
+		"--min-tags=0",
+		"--max-tags=0",
+		"--query-mix=get-trace=1, find-traces=0",
+		"--seed=42",
+	}))
+	opts, err := new(Options).InitFromViper(v)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, opts.Duration)
+	assert.Equal(t, 8, opts.Writers)
+	assert.Equal(t, 0, opts.Readers)
+	assert.InDelta(t, 1000.0, opts.WriteRate, 0.01)
+	assert.Equal(t, 3, opts.SpansPerTrace)
+	assert.Equal(t, 0, opts.MaxTags)
+	assert.Equal(t, map[Query]int{QueryGetTrace: 1, QueryFindTraces: 0}, opts.QueryMix)
+	assert.Equal(t, int64(42), opts.Seed)
+}
+
+func TestOptionsWithInvalidFlags(t *testing.T) {
+	testCases := []struct {
+		flag string
+		err  string
+	}{
+		{flag: "--duration=0", err: "the duration must be positive"},
+		{flag: "--writers=-1", err: "cannot be negative"},
+		{flag: "--services=0", err: "must be positive"},
+		{flag: "--min-tags=20", err: "invalid number of tags between 20 and 15"},
+		{flag: "--query-mix=get-trace", err: "expecting query=weight"},
+		{flag: "--query-mix=get-spans=1", err: `unknown query "get-spans"`},
+		{flag: "--query-mix=get-trace=-1", err: `invalid weight of query "get-trace"`},
+		{flag: "--query-mix=get-trace=0", err: "requires a positive weight"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.flag, func(t *testing.T) {
+			v, command := config.Viperize(AddFlags)
+			require.NoError(t, command.ParseFlags([]string{tc.flag}))
+			_, err := new(Options).InitFromViper(v)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	v, command := config.Viperize(AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--writers=0", "--readers=0"}))
+	_, err := new(Options).InitFromViper(v)
+	require.ErrorContains(t, err, "at least one writer or reader is required")
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// writeOperation is the name of the span writes in the reports.
+const writeOperation = "write-span"
+
+// maxLatency is the highest latency recorded precisely, the higher ones are recorded as maxLatency.
+const maxLatency = time.Minute
+
+// latencies records the latencies and errors of an operation of a worker.
+type latencies struct {
+	hist       *hdrhistogram.Histogram
+	errors     int64
+	firstError error
+}
+
+func newLatencies() *latencies {
+	return &latencies{
+		// microseconds with 3 significant figures
+		hist: hdrhistogram.New(1, maxLatency.Microseconds(), 3),
+	}
+}
+
+func (l *latencies) record(d time.Duration, err error) {
+	if err != nil {
+		l.errors++
+		if l.firstError == nil {
+			l.firstError = err
+		}
+		return
+	}
+	us := d.Microseconds()
+	if us > maxLatency.Microseconds() {
+		us = maxLatency.Microseconds()
+	}
+	l.hist.RecordValue(max(us, 1))
+}
+
+func (l *latencies) merge(other *latencies) {
+	l.hist.Merge(other.hist)
+	l.errors += other.errors
+	if l.firstError == nil {
+		l.firstError = other.firstError
+	}
+}
+
+// OperationReport is the throughput and the latency percentiles of an operation of the benchmark.
+type OperationReport struct {
+	Operation string
+	// Count is the number of successful operations.
+	Count  int64
+	Errors int64
+	// FirstError is the error of the first failed operation, if any.
+	FirstError error
+	// Throughput is the number of successful operations per second.
+	Throughput float64
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+}
+
+// Report is the result of a benchmark.
+type Report struct {
+	Elapsed    time.Duration
+	Operations []OperationReport
+}
+
+func newOperationReport(operation string, l *latencies, elapsed time.Duration) OperationReport {
+	percentile := func(p float64) time.Duration {
+		return time.Duration(l.hist.ValueAtQuantile(p)) * time.Microsecond
+	}
+	return OperationReport{
+		Operation:  operation,
+		Count:      l.hist.TotalCount(),
+		Errors:     l.errors,
+		FirstError: l.firstError,
+		Throughput: float64(l.hist.TotalCount()) / elapsed.Seconds(),
+		P50:        percentile(50),
+		P90:        percentile(90),
+		P99:        percentile(99),
+		Max:        time.Duration(l.hist.Max()) * time.Microsecond,
+	}
+}
+
+// Print writes the report as a table, followed by the first error of the failed operations.
+func (r *Report) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "operation\tcount\terrors\tops/s\tp50\tp90\tp99\tmax\t\n")
+	for _, op := range r.Operations {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%v\t%v\t%v\t%v\t\n",
+			op.Operation, op.Count, op.Errors, op.Throughput, op.P50, op.P90, op.P99, op.Max)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "elapsed: %v\n", r.Elapsed.Round(time.Millisecond))
+	for _, op := range r.Operations {
+		if op.FirstError != nil {
+			fmt.Fprintf(w, "first error of %s: %v\n", op.Operation, op.FirstError)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencies(t *testing.T) {
+	l := newLatencies()
+	for i := 1; i <= 100; i++ {
+		l.record(time.Duration(i)*time.Millisecond, nil)
+	}
+	l.record(time.Second, errors.New("timeout"))
+
+	other := newLatencies()
+	other.record(time.Hour, nil)
+	other.record(0, nil)
+	other.record(time.Second, errors.New("unavailable"))
+	l.merge(other)
+
+	report := newOperationReport("get-trace", l, 2*time.Second)
+	assert.Equal(t, "get-trace", report.Operation)
+	assert.Equal(t, int64(102), report.Count)
+	assert.Equal(t, int64(2), report.Errors)
+	require.EqualError(t, report.FirstError, "timeout")
+	assert.InDelta(t, 51.0, report.Throughput, 0.01)
+	assert.InDelta(t, float64(50*time.Millisecond), float64(report.P50), float64(2*time.Millisecond))
+	assert.InDelta(t, float64(90*time.Millisecond), float64(report.P90), float64(2*time.Millisecond))
+	assert.InDelta(t, float64(99*time.Millisecond), float64(report.P99), float64(2*time.Millisecond))
+	// the latencies are capped
+	assert.InDelta(t, float64(maxLatency), float64(report.Max), float64(100*time.Millisecond))
+}
+
+func TestReportPrint(t *testing.T) {
+	report := &Report{
+		Elapsed: 2 * time.Second,
+		Operations: []OperationReport{
+			{Operation: writeOperation, Count: 200, Throughput: 100, P50: time.Millisecond, P90: 2 * time.Millisecond, P99: 3 * time.Millisecond, Max: 4 * time.Millisecond},
+			{Operation: "get-trace", Count: 10, Errors: 1, FirstError: errors.New("trace not found"), Throughput: 5, P50: time.Millisecond},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, report.Print(&buf))
+	assert.Equal(t, ""+
+		"   operation  count  errors  ops/s  p50  p90  p99  max\n"+
+		"  write-span    200       0  100.0  1ms  2ms  3ms  4ms\n"+
+		"   get-trace     10       1    5.0  1ms   0s   0s   0s\n"+
+		"elapsed: 2s\n"+
+		"first error of get-trace: trace not found\n", buf.String())
+}
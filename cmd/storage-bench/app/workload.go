@@ -0,0 +1,164 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// maxTraceIDs is the number of the last written trace IDs kept for the get-trace queries.
+const maxTraceIDs = 10000
+
+// Query is a query of the read workload.
+type Query string
+
+const (
+	// QueryGetTrace reads one of the written traces.
+	QueryGetTrace Query = "get-trace"
+	// QueryFindTraces searches for the traces of a service and operation written since the start of the benchmark.
+	QueryFindTraces Query = "find-traces"
+	// QueryGetServices lists the services.
+	QueryGetServices Query = "get-services"
+	// QueryGetOperations lists the operations of a service.
+	QueryGetOperations Query = "get-operations"
+)
+
+// queries lists the queries in the order of the reports.
+var queries = []Query{QueryGetTrace, QueryFindTraces, QueryGetServices, QueryGetOperations}
+
+func (q Query) valid() bool {
+	for _, query := range queries {
+		if q == query {
+			return true
+		}
+	}
+	return false
+}
+
+// generator generates the traces of a writer, and the queries of a reader.
+// It is not safe for concurrent use.
+type generator struct {
+	opts *Options
+	rnd  *rand.Rand
+}
+
+func newGenerator(opts *Options, seed int64) *generator {
+	return &generator{
+		opts: opts,
+		rnd:  rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (g *generator) service() string {
+	return fmt.Sprintf("service-%02d", g.rnd.Intn(g.opts.Services))
+}
+
+func (g *generator) operation() string {
+	return fmt.Sprintf("operation-%02d", g.rnd.Intn(g.opts.Operations))
+}
+
+// trace generates the spans of a trace starting at the given time, the spans being
+// the children of random previous spans.
+func (g *generator) trace(start time.Time) []*model.Span {
+	traceID := model.NewTraceID(g.rnd.Uint64(), g.rnd.Uint64())
+	spans := make([]*model.Span, g.opts.SpansPerTrace)
+	for i := range spans {
+		span := &model.Span{
+			TraceID:       traceID,
+			SpanID:        model.NewSpanID(uint64(i + 1)),
+			OperationName: g.operation(),
+			StartTime:     start.Add(time.Duration(i) * time.Millisecond),
+			Duration:      time.Duration(1+g.rnd.Intn(1000)) * time.Millisecond,
+			Tags:          g.tags(),
+			Process:       model.NewProcess(g.service(), nil),
+		}
+		if i > 0 {
+			parent := spans[g.rnd.Intn(i)]
+			span.References = []model.SpanRef{model.NewChildOfRef(traceID, parent.SpanID)}
+		}
+		spans[i] = span
+	}
+	return spans
+}
+
+func (g *generator) tags() []model.KeyValue {
+	n := g.opts.MinTags + g.rnd.Intn(g.opts.MaxTags-g.opts.MinTags+1)
+	tags := make([]model.KeyValue, n)
+	for i := range tags {
+		tags[i] = model.String(fmt.Sprintf("tag-%02d", i), g.tagValue())
+	}
+	return tags
+}
+
+// tagValue returns one of the TagCardinality values, padded to TagValueLength.
+func (g *generator) tagValue() string {
+	value := fmt.Sprintf("value-%d", g.rnd.Intn(g.opts.TagCardinality))
+	if len(value) < g.opts.TagValueLength {
+		value += strings.Repeat("x", g.opts.TagValueLength-len(value))
+	}
+	return value
+}
+
+// query returns a random query with the probabilities of the query mix.
+func (g *generator) query() Query {
+	total := 0
+	for _, weight := range g.opts.QueryMix {
+		total += weight
+	}
+	n := g.rnd.Intn(total)
+	for _, query := range queries {
+		if n < g.opts.QueryMix[query] {
+			return query
+		}
+		n -= g.opts.QueryMix[query]
+	}
+	return QueryGetServices
+}
+
+// findTracesQuery returns the parameters of a find-traces query of the traces of a random
+// service and operation written since the start of the benchmark.
+func (g *generator) findTracesQuery(start, now time.Time) *spanstore.TraceQueryParameters {
+	return &spanstore.TraceQueryParameters{
+		ServiceName:   g.service(),
+		OperationName: g.operation(),
+		StartTimeMin:  start,
+		StartTimeMax:  now,
+		NumTraces:     20,
+	}
+}
+
+// traceIDPool keeps the last written trace IDs, read by the get-trace queries.
+type traceIDPool struct {
+	lock sync.Mutex
+	ids  []model.TraceID
+	next int
+}
+
+func (p *traceIDPool) add(traceID model.TraceID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.ids) < maxTraceIDs {
+		p.ids = append(p.ids, traceID)
+		return
+	}
+	p.ids[p.next] = traceID
+	p.next = (p.next + 1) % maxTraceIDs
+}
+
+// random returns one of the trace IDs, or false if no trace has been written yet.
+func (p *traceIDPool) random(rnd *rand.Rand) (model.TraceID, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.ids) == 0 {
+		return model.TraceID{}, false
+	}
+	return p.ids[rnd.Intn(len(p.ids))], true
+}
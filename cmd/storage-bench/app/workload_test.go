@@ -0,0 +1,101 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func newTestOptions() *Options {
+	return &Options{
+		Duration:       100 * time.Millisecond,
+		Writers:        2,
+		Readers:        2,
+		Services:       3,
+		Operations:     4,
+		SpansPerTrace:  5,
+		MinTags:        2,
+		MaxTags:        4,
+		TagValueLength: 16,
+		TagCardinality: 10,
+		QueryMix: map[Query]int{
+			QueryGetTrace:      1,
+			QueryFindTraces:    1,
+			QueryGetServices:   1,
+			QueryGetOperations: 1,
+		},
+		Seed: 1,
+	}
+}
+
+func TestGeneratorTrace(t *testing.T) {
+	opts := newTestOptions()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	spans := newGenerator(opts, 1).trace(start)
+	require.Len(t, spans, opts.SpansPerTrace)
+
+	traceID := spans[0].TraceID
+	assert.Empty(t, spans[0].References)
+	values := make(map[string]bool)
+	for i, span := range spans {
+		assert.Equal(t, traceID, span.TraceID)
+		assert.Equal(t, model.NewSpanID(uint64(i+1)), span.SpanID)
+		assert.False(t, span.StartTime.Before(start))
+		assert.Regexp(t, "^service-0[0-2]$", span.Process.ServiceName)
+		assert.Regexp(t, "^operation-0[0-3]$", span.OperationName)
+		if i > 0 {
+			require.Len(t, span.References, 1)
+			assert.Less(t, span.ParentSpanID(), span.SpanID)
+		}
+		assert.GreaterOrEqual(t, len(span.Tags), opts.MinTags)
+		assert.LessOrEqual(t, len(span.Tags), opts.MaxTags)
+		for _, tag := range span.Tags {
+			assert.Len(t, tag.VStr, opts.TagValueLength)
+			values[tag.VStr] = true
+		}
+	}
+	assert.LessOrEqual(t, len(values), opts.TagCardinality)
+
+	// the traces are repeatable
+	assert.Equal(t, spans, newGenerator(opts, 1).trace(start))
+	assert.NotEqual(t, traceID, newGenerator(opts, 2).trace(start)[0].TraceID)
+}
+
+func TestGeneratorQuery(t *testing.T) {
+	opts := newTestOptions()
+	opts.QueryMix = map[Query]int{QueryFindTraces: 3, QueryGetOperations: 1}
+	gen := newGenerator(opts, 1)
+	counts := make(map[Query]int)
+	for i := 0; i < 1000; i++ {
+		counts[gen.query()]++
+	}
+	assert.Len(t, counts, 2)
+	assert.Greater(t, counts[QueryFindTraces], 2*counts[QueryGetOperations])
+}
+
+func TestTraceIDPool(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var pool traceIDPool
+	_, ok := pool.random(rnd)
+	assert.False(t, ok)
+
+	for i := 0; i < maxTraceIDs+2; i++ {
+		pool.add(model.NewTraceID(0, uint64(i)))
+	}
+	assert.Len(t, pool.ids, maxTraceIDs)
+	// the oldest trace IDs are replaced
+	assert.Equal(t, model.NewTraceID(0, maxTraceIDs), pool.ids[0])
+	assert.Equal(t, model.NewTraceID(0, maxTraceIDs+1), pool.ids[1])
+	assert.Equal(t, model.NewTraceID(0, 2), pool.ids[2])
+
+	_, ok = pool.random(rnd)
+	assert.True(t, ok)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/internal/docs"
+	"github.com/jaegertracing/jaeger/cmd/internal/env"
+	"github.com/jaegertracing/jaeger/cmd/internal/flags"
+	"github.com/jaegertracing/jaeger/cmd/storage-bench/app"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/version"
+	"github.com/jaegertracing/jaeger/plugin/storage"
+)
+
+const serviceName = "jaeger-storage-bench"
+
+func main() {
+	if os.Getenv(storage.SpanStorageTypeEnvVar) == "" {
+		os.Setenv(storage.SpanStorageTypeEnvVar, "memory")
+	}
+	storageFactory, err := storage.NewFactory(storage.FactoryConfigFromEnvAndCLI(os.Args, os.Stderr))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot initialize storage factory: %v\n", err)
+		os.Exit(1)
+	}
+
+	v := viper.New()
+	command := &cobra.Command{
+		Use:   serviceName,
+		Short: serviceName + " benchmarks the span storage backends.",
+		Long: serviceName + ` runs concurrent write and read workloads against the span storage configured ` +
+			`like in the other Jaeger binaries, e.g. with SPAN_STORAGE_TYPE=cassandra, and reports the throughput ` +
+			`and the latency percentiles of the span writes and of the queries, to size the storage clusters.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, err := zap.NewProduction()
+			if err != nil {
+				return err
+			}
+			defer logger.Sync()
+
+			opts, err := new(app.Options).InitFromViper(v)
+			if err != nil {
+				return err
+			}
+			storageFactory.InitFromViper(v, logger)
+			if err := storageFactory.Initialize(metrics.NullFactory, logger.Named(flags.StorageLoggerName)); err != nil {
+				return fmt.Errorf("failed to init storage factory: %w", err)
+			}
+			defer func() {
+				if err := storageFactory.Close(); err != nil {
+					logger.Error("Failed to close storage factory", zap.Error(err))
+				}
+			}()
+			writer, err := storageFactory.CreateSpanWriter()
+			if err != nil {
+				return fmt.Errorf("failed to create span writer: %w", err)
+			}
+			reader, err := storageFactory.CreateSpanReader()
+			if err != nil {
+				return fmt.Errorf("failed to create span reader: %w", err)
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			report := app.NewBench(opts, writer, reader, logger).Run(ctx)
+			return report.Print(cmd.OutOrStdout())
+		},
+	}
+
+	command.AddCommand(version.Command())
+	command.AddCommand(env.Command())
+	command.AddCommand(docs.Command(v))
+
+	config.AddFlags(
+		v,
+		command,
+		storageFactory.AddFlags,
+		app.AddFlags,
+	)
+
+	if err := command.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
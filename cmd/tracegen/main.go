@@ -58,24 +58,43 @@ func main() {
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 	jaegerclientenv2otel.MapJaegerToOtelEnvVars(logger)
 
-	tracers, shutdown := createTracers(cfg, logger)
+	if cfg.TopologyFile != "" {
+		topology, err := tracegen.LoadTopology(cfg.TopologyFile)
+		if err != nil {
+			logger.Fatal("cannot load topology", zap.Error(err))
+		}
+		tracers, shutdown := createTracers(cfg, topology.ServiceNames(), logger)
+		defer shutdown(context.Background())
+
+		tracegen.RunTopology(cfg, topology, tracers, logger)
+		return
+	}
+
+	tracers, shutdown := createTracers(cfg, serviceNames(cfg), logger)
 	defer shutdown(context.Background())
 
 	tracegen.Run(cfg, tracers, logger)
 }
 
-func createTracers(cfg *tracegen.Config, logger *zap.Logger) ([]trace.Tracer, func(context.Context) error) {
+func serviceNames(cfg *tracegen.Config) []string {
 	if cfg.Services < 1 {
 		cfg.Services = 1
 	}
-	var shutdown []func(context.Context) error
-	var tracers []trace.Tracer
+	var services []string
 	for s := 0; s < cfg.Services; s++ {
 		svc := cfg.Service
 		if cfg.Services > 1 {
 			svc = fmt.Sprintf("%s-%02d", svc, s)
 		}
+		services = append(services, svc)
+	}
+	return services
+}
 
+func createTracers(cfg *tracegen.Config, services []string, logger *zap.Logger) ([]trace.Tracer, func(context.Context) error) {
+	var shutdown []func(context.Context) error
+	var tracers []trace.Tracer
+	for _, svc := range services {
 		exp, err := createOtelExporter(cfg.TraceExporter)
 		if err != nil {
 			logger.Sugar().Fatalf("cannot create trace exporter %s: %s", cfg.TraceExporter, err)
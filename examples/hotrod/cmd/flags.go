@@ -29,6 +29,9 @@ var (
 	fixDBConnDisableMutex  bool
 	fixRouteWorkerPoolSize int
 
+	faultSpecs []string
+	loadSpecs  []string
+
 	customerPort int
 	driverPort   int
 	frontendPort int
@@ -49,6 +52,14 @@ func addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&fixDBConnDisableMutex, "fix-disable-db-conn-mutex", "M", false, "Disables the mutex guarding db connection")
 	cmd.PersistentFlags().IntVarP(&fixRouteWorkerPoolSize, "fix-route-worker-pool-size", "W", 3, "Default worker pool size")
 
+	// Flags to demo the behavior of Jaeger with misbehaving services and varying load
+	cmd.PersistentFlags().StringArrayVar(&faultSpecs, "fault", nil,
+		`Injects a fault in the requests of a service (frontend|customer|driver|route), can be repeated, `+
+			`e.g. "customer:latency=500ms,latency-rate=0.2,error-rate=0.05,duration=10m". Faults can also be changed at runtime with the /faults endpoint of the frontend`)
+	cmd.PersistentFlags().StringArrayVar(&loadSpecs, "load", nil,
+		`Generates requests from the frontend to a service (frontend|customer|driver|route), can be repeated, `+
+			`e.g. "route:rps=20,profile=sine,period=1m" with the profiles constant|sine|spike`)
+
 	// Add flags to choose ports for services
 	cmd.PersistentFlags().IntVarP(&customerPort, "customer-service-port", "c", 8081, "Port for customer service")
 	cmd.PersistentFlags().IntVarP(&driverPort, "driver-service-port", "d", 8082, "Port for driver service")
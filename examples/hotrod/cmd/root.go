@@ -17,12 +17,16 @@ package cmd
 
 import (
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/fault"
+	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/load"
 	"github.com/jaegertracing/jaeger/examples/hotrod/services/config"
+	"github.com/jaegertracing/jaeger/examples/hotrod/services/frontend"
 	"github.com/jaegertracing/jaeger/internal/jaegerclientenv2otel"
 	"github.com/jaegertracing/jaeger/internal/metrics/expvar"
 	"github.com/jaegertracing/jaeger/internal/metrics/prometheus"
@@ -32,6 +36,7 @@ import (
 var (
 	logger         *zap.Logger
 	metricsFactory metrics.Factory
+	loadTargets    map[string]load.Target
 )
 
 // RootCmd represents the base command when called without any subcommands
@@ -93,6 +98,28 @@ func onInitialize() {
 		config.RouteWorkerPoolSize = fixRouteWorkerPoolSize
 	}
 
+	for _, spec := range faultSpecs {
+		service, f, err := fault.Parse(spec)
+		if err != nil {
+			logger.Fatal("invalid fault", zap.Error(err))
+		}
+		logger.Info("fault: injecting fault", zap.String("service", service), zap.String("fault", spec))
+		fault.Set(service, f)
+	}
+	for _, spec := range loadSpecs {
+		service, target, err := load.Parse(spec)
+		if err != nil {
+			logger.Fatal("invalid load", zap.Error(err))
+		}
+		if !slices.Contains(frontend.LoadServices, service) {
+			logger.Fatal("invalid load", zap.String("service", service), zap.Strings("services", frontend.LoadServices))
+		}
+		if loadTargets == nil {
+			loadTargets = make(map[string]load.Target)
+		}
+		loadTargets[service] = target
+	}
+
 	if customerPort != 8081 {
 		logger.Info("changing customer service port", zap.Int("old", 8081), zap.Int("new", customerPort))
 	}
@@ -1,4 +1,4 @@
-// Copyright (c) 2021 The Jaeger Authors.
+// Copyright (c) 2024 The Jaeger Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -12,16 +12,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package apiv3
+package fault
 
 import (
-	model2otel "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
-	"go.opentelemetry.io/collector/pdata/ptrace"
+	"testing"
 
-	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
 )
 
-func modelToOTLP(spans []*model.Span) (ptrace.Traces, error) {
-	batch := &model.Batch{Spans: spans}
-	return model2otel.ProtoToTraces([]*model.Batch{batch})
+func TestMain(m *testing.M) {
+	testutils.VerifyGoLeaks(m)
 }
@@ -0,0 +1,173 @@
+// Copyright (c) 2024 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fault injects latency spikes and error bursts in the requests served by the services,
+// e.g. to demo the monitoring and the sampling of the traces of a misbehaving service.
+package fault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrInjected is returned for the requests failed by an injected fault.
+var ErrInjected = errors.New("injected fault")
+
+// Fault is injected in the requests served by a service.
+type Fault struct {
+	// Latency is added to the delayed requests.
+	Latency time.Duration
+	// LatencyRate is the fraction of the requests delayed.
+	LatencyRate float64
+	// ErrorRate is the fraction of the requests failing.
+	ErrorRate float64
+	// Until is the time after which the fault is not injected anymore, e.g. at the end
+	// of an error burst. The fault is injected until it is cleared if zero.
+	Until time.Time
+}
+
+func (f Fault) expired(now time.Time) bool {
+	return !f.Until.IsZero() && now.After(f.Until)
+}
+
+var (
+	mux    sync.RWMutex
+	faults = make(map[string]Fault)
+)
+
+// Set injects the fault in the requests of the service, replacing its previous fault.
+func Set(service string, fault Fault) {
+	mux.Lock()
+	defer mux.Unlock()
+	faults[service] = fault
+}
+
+// Clear stops injecting the fault of the service.
+func Clear(service string) {
+	mux.Lock()
+	defer mux.Unlock()
+	delete(faults, service)
+}
+
+// All returns the faults of the services which are not expired.
+func All() map[string]Fault {
+	mux.RLock()
+	defer mux.RUnlock()
+	now := time.Now()
+	all := make(map[string]Fault, len(faults))
+	for service, fault := range faults {
+		if !fault.expired(now) {
+			all[service] = fault
+		}
+	}
+	return all
+}
+
+// Inject injects the fault of the service in a request: it blocks for the latency
+// of the fault if the request is delayed, and returns an error if the request fails.
+func Inject(ctx context.Context, service string) error {
+	mux.RLock()
+	fault, ok := faults[service]
+	mux.RUnlock()
+	if !ok || fault.expired(time.Now()) {
+		return nil
+	}
+	span := trace.SpanFromContext(ctx)
+	if fault.Latency > 0 && rand.Float64() < fault.LatencyRate {
+		span.AddEvent("injected latency", trace.WithAttributes(attribute.String("latency", fault.Latency.String())))
+		timer := time.NewTimer(fault.Latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	if rand.Float64() < fault.ErrorRate {
+		span.AddEvent("injected error")
+		return fmt.Errorf("%w in service %s", ErrInjected, service)
+	}
+	return nil
+}
+
+// Parse parses the fault of a service from a specification such as
+// "customer:latency=500ms,latency-rate=0.2,error-rate=0.05,duration=1m".
+// The latency rate defaults to 1, and the fault does not expire without duration.
+func Parse(spec string) (string, Fault, error) {
+	service, params, found := strings.Cut(spec, ":")
+	if !found || service == "" {
+		return "", Fault{}, fmt.Errorf("invalid fault %q, expecting service:key=value,...", spec)
+	}
+	values := make(map[string]string)
+	for _, param := range strings.Split(params, ",") {
+		key, value, found := strings.Cut(param, "=")
+		if !found {
+			return "", Fault{}, fmt.Errorf("invalid parameter %q of fault %q, expecting key=value", param, spec)
+		}
+		values[key] = value
+	}
+	fault, err := FromValues(values)
+	if err != nil {
+		return "", Fault{}, fmt.Errorf("invalid fault %q: %w", spec, err)
+	}
+	return service, fault, nil
+}
+
+// FromValues creates a fault from the values of the latency, latency-rate,
+// error-rate and duration parameters.
+func FromValues(values map[string]string) (Fault, error) {
+	fault := Fault{LatencyRate: 1}
+	for key, value := range values {
+		var err error
+		switch key {
+		case "latency":
+			fault.Latency, err = time.ParseDuration(value)
+		case "latency-rate":
+			fault.LatencyRate, err = parseRate(value)
+		case "error-rate":
+			fault.ErrorRate, err = parseRate(value)
+		case "duration":
+			var duration time.Duration
+			if duration, err = time.ParseDuration(value); err == nil {
+				fault.Until = time.Now().Add(duration)
+			}
+		default:
+			err = errors.New("unknown parameter")
+		}
+		if err != nil {
+			return Fault{}, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return fault, nil
+}
+
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, errors.New("must be between 0 and 1")
+	}
+	return rate, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2024 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	service, fault, err := Parse("customer:latency=500ms,latency-rate=0.2,error-rate=0.05,duration=1m")
+	require.NoError(t, err)
+	assert.Equal(t, "customer", service)
+	assert.Equal(t, 500*time.Millisecond, fault.Latency)
+	assert.InDelta(t, 0.2, fault.LatencyRate, 1e-9)
+	assert.InDelta(t, 0.05, fault.ErrorRate, 1e-9)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), fault.Until, time.Second)
+
+	_, fault, err = Parse("route:latency=1s")
+	require.NoError(t, err)
+	assert.Equal(t, Fault{Latency: time.Second, LatencyRate: 1}, fault)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"customer",
+		":latency=1s",
+		"customer:latency",
+		"customer:latency=fast",
+		"customer:error-rate=2",
+		"customer:error-rate=high",
+		"customer:duration=long",
+		"customer:color=red",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, _, err := Parse(spec)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	defer Clear("test")
+	require.NoError(t, Inject(context.Background(), "test"))
+
+	Set("test", Fault{ErrorRate: 1})
+	require.ErrorIs(t, Inject(context.Background(), "test"), ErrInjected)
+
+	Set("test", Fault{Latency: time.Hour, LatencyRate: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, Inject(ctx, "test"), context.Canceled)
+
+	Set("test", Fault{ErrorRate: 1, Until: time.Now().Add(-time.Second)})
+	require.NoError(t, Inject(context.Background(), "test"))
+	assert.NotContains(t, All(), "test")
+}
+
+func TestHandler(t *testing.T) {
+	defer Clear("route")
+	handler := Handler()
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodPost, "/faults", "service=route&latency=100ms&error-rate=0.5")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"route": {"latency": "100ms", "latencyRate": 1, "errorRate": 0.5}}`, w.Body.String())
+	assert.JSONEq(t, w.Body.String(), request(http.MethodGet, "/faults", "").Body.String())
+
+	defer Clear("driver")
+	w = request(http.MethodPost, "/faults", "service=driver&error-rate=1&duration=1m")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"driver":{"latencyRate":1,"errorRate":1,"until":`)
+	assert.Contains(t, w.Body.String(), `"route":{"latency":"100ms","latencyRate":1,"errorRate":0.5}`)
+	Clear("driver")
+
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/faults", "latency=100ms").Code)
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/faults", "service=route&latency=fast").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, request(http.MethodPut, "/faults", "").Code)
+
+	w = request(http.MethodDelete, "/faults?service=route", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{}`, w.Body.String())
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2024 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fault
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// faultJSON is the representation of a fault returned by the handler.
+type faultJSON struct {
+	Latency     string     `json:"latency,omitempty"`
+	LatencyRate float64    `json:"latencyRate,omitempty"`
+	ErrorRate   float64    `json:"errorRate,omitempty"`
+	Until       *time.Time `json:"until,omitempty"`
+}
+
+// Handler serves the faults of the services in the process:
+//   - GET returns the faults of all the services;
+//   - POST injects the fault of the service parameter, with the latency, latency-rate,
+//     error-rate and duration parameters of Parse, e.g. service=route&error-rate=0.5&duration=30s;
+//   - DELETE clears the fault of the service parameter.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost, http.MethodDelete:
+			if err := r.ParseForm(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			service := r.Form.Get("service")
+			if service == "" {
+				http.Error(w, "Missing required 'service' parameter", http.StatusBadRequest)
+				return
+			}
+			if r.Method == http.MethodDelete {
+				Clear(service)
+				break
+			}
+			values := make(map[string]string)
+			for key := range r.Form {
+				if key != "service" {
+					values[key] = r.Form.Get(key)
+				}
+			}
+			fault, err := FromValues(values)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			Set(service, fault)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		response := make(map[string]faultJSON)
+		for service, fault := range All() {
+			f := faultJSON{LatencyRate: fault.LatencyRate, ErrorRate: fault.ErrorRate}
+			if fault.Latency > 0 {
+				f.Latency = fault.Latency.String()
+			}
+			if until := fault.Until; !until.IsZero() {
+				f.Until = &until
+			}
+			response[service] = f
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	})
+}
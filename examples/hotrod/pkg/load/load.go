@@ -0,0 +1,139 @@
+// Copyright (c) 2024 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package load generates requests to the services at a rate following a profile,
+// e.g. to demo the monitoring and the adaptive sampling of the services under a varying load.
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profiles of the rate of the requests.
+const (
+	// ProfileConstant sends the requests at a constant rate.
+	ProfileConstant = "constant"
+	// ProfileSine oscillates the rate between 0 and twice the rate over each period.
+	ProfileSine = "sine"
+	// ProfileSpike multiplies the rate by spikeFactor during the first tenth of each period.
+	ProfileSpike = "spike"
+
+	spikeFactor   = 10
+	spikeFraction = 0.1
+	defaultPeriod = time.Minute
+	tickPeriod    = 10 * time.Millisecond
+)
+
+// Target is the load generated for a service.
+type Target struct {
+	// RPS is the average number of requests per second.
+	RPS float64
+	// Profile shapes the rate of the requests over each period.
+	Profile string
+	// Period is the duration of the cycles of the profile.
+	Period time.Duration
+}
+
+// Rate returns the number of requests per second after the elapsed time.
+func (t Target) Rate(elapsed time.Duration) float64 {
+	phase := float64(elapsed%t.Period) / float64(t.Period)
+	switch t.Profile {
+	case ProfileSine:
+		return t.RPS * (1 + math.Sin(2*math.Pi*phase))
+	case ProfileSpike:
+		if phase < spikeFraction {
+			return t.RPS * spikeFactor
+		}
+	}
+	return t.RPS
+}
+
+// Parse parses the load of a service from a specification such as
+// "route:rps=20,profile=sine,period=1m". The profile defaults to constant,
+// and the period to a minute.
+func Parse(spec string) (string, Target, error) {
+	service, params, found := strings.Cut(spec, ":")
+	if !found || service == "" {
+		return "", Target{}, fmt.Errorf("invalid load %q, expecting service:key=value,...", spec)
+	}
+	target := Target{Profile: ProfileConstant, Period: defaultPeriod}
+	for _, param := range strings.Split(params, ",") {
+		key, value, found := strings.Cut(param, "=")
+		if !found {
+			return "", Target{}, fmt.Errorf("invalid parameter %q of load %q, expecting key=value", param, spec)
+		}
+		var err error
+		switch key {
+		case "rps":
+			target.RPS, err = strconv.ParseFloat(value, 64)
+			if err == nil && target.RPS <= 0 {
+				err = errors.New("must be positive")
+			}
+		case "profile":
+			target.Profile = value
+			if value != ProfileConstant && value != ProfileSine && value != ProfileSpike {
+				err = fmt.Errorf("must be one of %s, %s or %s", ProfileConstant, ProfileSine, ProfileSpike)
+			}
+		case "period":
+			target.Period, err = time.ParseDuration(value)
+			if err == nil && target.Period <= 0 {
+				err = errors.New("must be positive")
+			}
+		default:
+			err = errors.New("unknown parameter")
+		}
+		if err != nil {
+			return "", Target{}, fmt.Errorf("invalid load %q: %s: %w", spec, key, err)
+		}
+	}
+	if target.RPS == 0 {
+		return "", Target{}, fmt.Errorf("invalid load %q: rps is required", spec)
+	}
+	return service, target, nil
+}
+
+// Run calls the service at the rate of the target until the context is done,
+// then waits for the pending calls. The calls are concurrent, so that the rate
+// does not drop when the service slows down.
+func Run(ctx context.Context, target Target, call func(ctx context.Context)) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ticker := time.NewTicker(tickPeriod)
+	defer ticker.Stop()
+	start := time.Now()
+	// calls is the number of calls due, accumulated at the rate of the target on each tick
+	var calls float64
+	for {
+		select {
+		case now := <-ticker.C:
+			calls += target.Rate(now.Sub(start)) * tickPeriod.Seconds()
+			for ; calls >= 1; calls-- {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					call(ctx)
+				}()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package load
+
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	service, target, err := Parse("route:rps=20,profile=sine,period=30s")
+	require.NoError(t, err)
+	assert.Equal(t, "route", service)
+	assert.Equal(t, Target{RPS: 20, Profile: ProfileSine, Period: 30 * time.Second}, target)
+
+	_, target, err = Parse("customer:rps=0.5")
+	require.NoError(t, err)
+	assert.Equal(t, Target{RPS: 0.5, Profile: ProfileConstant, Period: time.Minute}, target)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"route",
+		":rps=1",
+		"route:rps",
+		"route:rps=-1",
+		"route:rps=many",
+		"route:profile=sine",
+		"route:rps=1,profile=square",
+		"route:rps=1,period=0s",
+		"route:rps=1,color=red",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, _, err := Parse(spec)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestRate(t *testing.T) {
+	constant := Target{RPS: 10, Profile: ProfileConstant, Period: time.Minute}
+	assert.InDelta(t, 10, constant.Rate(42*time.Second), 1e-9)
+
+	sine := Target{RPS: 10, Profile: ProfileSine, Period: time.Minute}
+	assert.InDelta(t, 10, sine.Rate(0), 1e-9)
+	assert.InDelta(t, 20, sine.Rate(15*time.Second), 1e-9)
+	assert.InDelta(t, 0, sine.Rate(45*time.Second), 1e-9)
+
+	spike := Target{RPS: 10, Profile: ProfileSpike, Period: time.Minute}
+	assert.InDelta(t, 100, spike.Rate(time.Minute+time.Second), 1e-9)
+	assert.InDelta(t, 10, spike.Rate(30*time.Second), 1e-9)
+}
+
+func TestRun(t *testing.T) {
+	var calls atomic.Int64
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, Target{RPS: 1000, Profile: ProfileConstant, Period: time.Minute}, func(context.Context) {
+			calls.Add(1)
+		})
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return calls.Load() >= 10 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
@@ -24,6 +24,7 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/fault"
 	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/log"
 	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/tracing"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
@@ -71,6 +72,10 @@ func (s *Server) Run() error {
 // FindNearest implements gRPC driver interface
 func (s *Server) FindNearest(ctx context.Context, location *DriverLocationRequest) (*DriverLocationResponse, error) {
 	s.logger.For(ctx).Info("Searching for nearby drivers", zap.String("location", location.Location))
+	if err := fault.Inject(ctx, "driver"); err != nil {
+		s.logger.For(ctx).Error("Failed to find nearby drivers", zap.Error(err))
+		return nil, err
+	}
 	driverIDs := s.redis.FindDriverIDs(ctx, location.Location)
 
 	locations := make([]*DriverLocation, len(driverIDs))
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"path"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/load"
+	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/tracing"
+)
+
+// LoadServices are the services to which the frontend can generate load.
+var LoadServices = []string{"frontend", "customer", "driver", "route"}
+
+// loadCustomers are the IDs and locations of the customers of the generated requests.
+var loadCustomers = []struct {
+	id       int
+	location string
+}{
+	{id: 123, location: "115,277"},
+	{id: 567, location: "211,653"},
+	{id: 392, location: "577,322"},
+	{id: 731, location: "728,326"},
+}
+
+// runLoad generates the load of each target service until the context is done.
+func (s *Server) runLoad(ctx context.Context) {
+	for service, target := range s.load {
+		call, err := s.loadCall(service)
+		if err != nil {
+			s.logger.Bg().Error("Cannot generate load", zap.Error(err))
+			continue
+		}
+		s.logger.Bg().Info("Generating load", zap.String("service", service),
+			zap.Float64("rps", target.RPS), zap.String("profile", target.Profile), zap.Duration("period", target.Period))
+		go load.Run(ctx, target, call)
+	}
+}
+
+// loadCall returns a call to the service with the parameters of a random customer.
+func (s *Server) loadCall(service string) (func(ctx context.Context), error) {
+	var call func(ctx context.Context, customerID int, location string) error
+	switch service {
+	case "frontend":
+		client := tracing.NewHTTPClient(s.tracer)
+		url := "http://" + path.Join(s.hostPort, s.basepath, "/dispatch") + "?customer="
+		call = func(ctx context.Context, customerID int, _ string) error {
+			var response Response
+			return client.GetJSON(ctx, "/dispatch", url+strconv.Itoa(customerID), &response)
+		}
+	case "customer":
+		call = func(ctx context.Context, customerID int, _ string) error {
+			_, err := s.bestETA.customer.Get(ctx, customerID)
+			return err
+		}
+	case "driver":
+		call = func(ctx context.Context, _ int, location string) error {
+			_, err := s.bestETA.driver.FindNearest(ctx, location)
+			return err
+		}
+	case "route":
+		call = func(ctx context.Context, _ int, location string) error {
+			pickup := loadCustomers[rand.Intn(len(loadCustomers))].location
+			_, err := s.bestETA.route.FindRoute(ctx, pickup, location)
+			return err
+		}
+	default:
+		return nil, fmt.Errorf("unknown service %q, expecting one of %v", service, LoadServices)
+	}
+	return func(ctx context.Context) {
+		customer := loadCustomers[rand.Intn(len(loadCustomers))]
+		if err := call(ctx, customer.id, customer.location); err != nil {
+			s.logger.Bg().Debug("Generated request failed", zap.String("service", service), zap.Error(err))
+		}
+	}, nil
+}
@@ -16,6 +16,7 @@
 package frontend
 
 import (
+	"context"
 	"embed"
 	"encoding/json"
 	"expvar"
@@ -28,7 +29,9 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/fault"
 	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/httperr"
+	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/load"
 	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/log"
 	"github.com/jaegertracing/jaeger/examples/hotrod/pkg/tracing"
 	"github.com/jaegertracing/jaeger/pkg/httpfs"
@@ -46,6 +49,7 @@ type Server struct {
 	assetFS  http.FileSystem
 	basepath string
 	jaegerUI string
+	load     map[string]load.Target
 }
 
 // ConfigOptions used to make sure service clients
@@ -57,6 +61,8 @@ type ConfigOptions struct {
 	RouteHostPort    string
 	Basepath         string
 	JaegerUI         string
+	// Load is the load generated to each service, see LoadServices.
+	Load map[string]load.Target
 }
 
 // NewServer creates a new frontend.Server
@@ -69,12 +75,14 @@ func NewServer(options ConfigOptions, tracer trace.TracerProvider, logger log.Fa
 		assetFS:  httpfs.PrefixedFS("web_assets", http.FS(assetFS)),
 		basepath: options.Basepath,
 		jaegerUI: options.JaegerUI,
+		load:     options.Load,
 	}
 }
 
 // Run starts the frontend server
 func (s *Server) Run() error {
 	mux := s.createServeMux()
+	s.runLoad(context.Background())
 	s.logger.Bg().Info("Starting", zap.String("address", "http://"+path.Join(s.hostPort, s.basepath)))
 	server := &http.Server{
 		Addr:              s.hostPort,
@@ -90,6 +98,7 @@ func (s *Server) createServeMux() http.Handler {
 	mux.Handle(p, http.StripPrefix(p, http.FileServer(s.assetFS)))
 	mux.Handle(path.Join(p, "/dispatch"), http.HandlerFunc(s.dispatch))
 	mux.Handle(path.Join(p, "/config"), http.HandlerFunc(s.config))
+	mux.Handle(path.Join(p, "/faults"), fault.Handler())
 	mux.Handle(path.Join(p, "/debug/vars"), expvar.Handler()) // expvar
 	mux.Handle(path.Join(p, "/metrics"), promhttp.Handler())  // Prometheus
 	return mux
@@ -105,6 +114,10 @@ func (s *Server) config(w http.ResponseWriter, r *http.Request) {
 func (s *Server) dispatch(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	s.logger.For(ctx).Info("HTTP request received", zap.String("method", r.Method), zap.Stringer("url", r.URL))
+	if err := fault.Inject(ctx, "frontend"); httperr.HandleError(w, err, http.StatusInternalServerError) {
+		s.logger.For(ctx).Error("request failed", zap.Error(err))
+		return
+	}
 	if err := r.ParseForm(); httperr.HandleError(w, err, http.StatusBadRequest) {
 		s.logger.For(ctx).Error("bad request", zap.Error(err))
 		return
@@ -19,14 +19,18 @@ import (
 	"errors"
 	"expvar"
 	"flag"
+	"fmt"
 	"log"
 	"net/http"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/viper"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 
 	jexpvar "github.com/jaegertracing/jaeger/internal/metrics/expvar"
+	"github.com/jaegertracing/jaeger/internal/metrics/otelmetrics"
 	jprom "github.com/jaegertracing/jaeger/internal/metrics/prometheus"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 )
@@ -36,6 +40,9 @@ const (
 	metricsHTTPRoute      = "metrics-http-route"
 	defaultMetricsBackend = "prometheus"
 	defaultMetricsRoute   = "/metrics"
+
+	// OTelBackend is the metrics backend recording the metrics with the OpenTelemetry SDK.
+	OTelBackend = "otel"
 )
 
 var errUnknownBackend = errors.New("unknown metrics backend specified")
@@ -44,7 +51,11 @@ var errUnknownBackend = errors.New("unknown metrics backend specified")
 type Builder struct {
 	Backend   string
 	HTTPRoute string // endpoint name to expose metrics, e.g. for scraping
-	handler   http.Handler
+	// MeterProviderOptions are added to the options of the meter provider of the otel backend,
+	// e.g. the views of the metrics or the readers exporting them over OTLP.
+	MeterProviderOptions []sdkmetric.Option
+	handler              http.Handler
+	meterProvider        *sdkmetric.MeterProvider
 }
 
 const expvarDepr = "(deprecated, will be removed after 2024-01-01 or in release v1.53.0, whichever is later) "
@@ -54,7 +65,7 @@ func AddFlags(flags *flag.FlagSet) {
 	flags.String(
 		metricsBackend,
 		defaultMetricsBackend,
-		"Defines which metrics backend to use for metrics reporting: prometheus, none, or expvar "+expvarDepr)
+		"Defines which metrics backend to use for metrics reporting: prometheus, otel (the OpenTelemetry SDK, exposing the metrics in the Prometheus format as well), none, or expvar "+expvarDepr)
 	flags.String(
 		metricsHTTPRoute,
 		defaultMetricsRoute,
@@ -74,9 +85,29 @@ func (b *Builder) InitFromViper(v *viper.Viper) *Builder {
 func (b *Builder) CreateMetricsFactory(namespace string) (metrics.Factory, error) {
 	if b.Backend == "prometheus" {
 		metricsFactory := jprom.New().Namespace(metrics.NSOptions{Name: namespace, Tags: nil})
-		b.handler = promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{DisableCompression: true})
+		// the exemplars of the metrics are only exposed in the OpenMetrics format
+		b.handler = promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+			DisableCompression: true,
+			EnableOpenMetrics:  true,
+		})
 		return metricsFactory, nil
 	}
+	if b.Backend == OTelBackend {
+		// the metrics are exposed with the same names as with the prometheus backend
+		exporter, err := otelprom.New(
+			otelprom.WithoutUnits(),
+			otelprom.WithoutScopeInfo(),
+			otelprom.WithoutTargetInfo(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create the Prometheus exporter of the otel metrics backend: %w", err)
+		}
+		b.meterProvider = sdkmetric.NewMeterProvider(append([]sdkmetric.Option{sdkmetric.WithReader(exporter)}, b.MeterProviderOptions...)...)
+		b.handler = promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+			DisableCompression: true,
+		})
+		return otelmetrics.NewFactory(b.meterProvider).Namespace(metrics.NSOptions{Name: namespace, Tags: nil}), nil
+	}
 	if b.Backend == "expvar" {
 		metricsFactory := jexpvar.NewFactory(10).Namespace(metrics.NSOptions{Name: namespace, Tags: nil})
 		b.handler = expvar.Handler()
@@ -89,6 +120,12 @@ func (b *Builder) CreateMetricsFactory(namespace string) (metrics.Factory, error
 	return nil, errUnknownBackend
 }
 
+// MeterProvider returns the meter provider of the otel backend, to be shut down
+// with the service, or nil for the other backends.
+func (b *Builder) MeterProvider() *sdkmetric.MeterProvider {
+	return b.meterProvider
+}
+
 // Handler returns an http.Handler for the metrics endpoint.
 func (b *Builder) Handler() http.Handler {
 	return b.handler
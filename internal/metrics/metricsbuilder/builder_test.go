@@ -16,9 +16,13 @@
 package metricsbuilder
 
 import (
+	"context"
 	"expvar"
 	"flag"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
@@ -126,6 +130,25 @@ func TestBuilder(t *testing.T) {
 	}
 }
 
+func TestBuilderOTel(t *testing.T) {
+	b := &Builder{Backend: OTelBackend, HTTPRoute: "/metrics"}
+	mf, err := b.CreateMetricsFactory("otel")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, b.MeterProvider().Shutdown(context.Background()))
+	}()
+	mf.Counter(metrics.Options{Name: "spans.received", Tags: map[string]string{"svc": "foo"}}).Inc(2)
+	mf.Timer(metrics.TimerOptions{Name: "latency"}).Record(time.Second)
+
+	require.NotNil(t, b.Handler())
+	w := httptest.NewRecorder()
+	b.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	// the names are the same as with the prometheus backend
+	assert.Contains(t, w.Body.String(), `otel_spans_received_total{svc="foo"} 2`)
+	assert.Contains(t, w.Body.String(), `otel_latency_count 1`)
+	assert.Nil(t, new(Builder).MeterProvider())
+}
+
 func TestMain(m *testing.M) {
 	testutils.VerifyGoLeaks(m)
 }
@@ -0,0 +1,198 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmetrics
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// scopeName is the instrumentation scope of the metrics.
+const scopeName = "github.com/jaegertracing/jaeger"
+
+// defaultTimerBuckets are the default buckets of the timers in seconds, the same as the
+// default buckets of Prometheus, since the default boundaries of OpenTelemetry are meant
+// for milliseconds. They can be changed with the views of the meter provider.
+var defaultTimerBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Factory implements metrics.Factory backed by an OpenTelemetry meter provider, so that the
+// metrics can be configured with the views, readers and exporters of the OpenTelemetry SDK.
+//
+// The names of the metrics are their namespaces and names separated by dots, e.g.
+// jaeger.collector.spans.received, and their tags are the attributes of their measurements.
+type Factory struct {
+	meter metric.Meter
+	scope string
+	tags  map[string]string
+}
+
+var _ metrics.Factory = (*Factory)(nil)
+
+// NewFactory creates a Factory creating the metrics with a meter of the meter provider.
+func NewFactory(meterProvider metric.MeterProvider) *Factory {
+	return &Factory{
+		meter: meterProvider.Meter(scopeName),
+	}
+}
+
+// Counter implements Counter of metrics.Factory.
+func (f *Factory) Counter(options metrics.Options) metrics.Counter {
+	c, err := f.meter.Int64Counter(f.subScope(options.Name), metric.WithDescription(options.Help))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return &counter{
+		counter: c,
+		attrs:   f.attributes(options.Tags),
+	}
+}
+
+// Gauge implements Gauge of metrics.Factory.
+func (f *Factory) Gauge(options metrics.Options) metrics.Gauge {
+	g := &gauge{
+		attrs: f.attributes(options.Tags),
+	}
+	// the observable gauge is used since the OpenTelemetry API has no synchronous gauge
+	og, err := f.meter.Int64ObservableGauge(f.subScope(options.Name), metric.WithDescription(options.Help))
+	if err != nil {
+		otel.Handle(err)
+		return g
+	}
+	if _, err := f.meter.RegisterCallback(g.observe(og), og); err != nil {
+		otel.Handle(err)
+	}
+	return g
+}
+
+// Timer implements Timer of metrics.Factory. The durations are recorded in seconds.
+func (f *Factory) Timer(options metrics.TimerOptions) metrics.Timer {
+	buckets := defaultTimerBuckets
+	if len(options.Buckets) > 0 {
+		buckets = make([]float64, len(options.Buckets))
+		for i, bucket := range options.Buckets {
+			buckets[i] = bucket.Seconds()
+		}
+	}
+	h, err := f.meter.Float64Histogram(
+		f.subScope(options.Name),
+		metric.WithDescription(options.Help),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(buckets...),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return &timer{
+		histogram: histogram{
+			histogram: h,
+			attrs:     f.attributes(options.Tags),
+		},
+	}
+}
+
+// Histogram implements Histogram of metrics.Factory.
+func (f *Factory) Histogram(options metrics.HistogramOptions) metrics.Histogram {
+	opts := []metric.Float64HistogramOption{metric.WithDescription(options.Help)}
+	if len(options.Buckets) > 0 {
+		opts = append(opts, metric.WithExplicitBucketBoundaries(options.Buckets...))
+	}
+	h, err := f.meter.Float64Histogram(f.subScope(options.Name), opts...)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return &histogram{
+		histogram: h,
+		attrs:     f.attributes(options.Tags),
+	}
+}
+
+// Namespace implements Namespace of metrics.Factory.
+func (f *Factory) Namespace(scope metrics.NSOptions) metrics.Factory {
+	return &Factory{
+		meter: f.meter,
+		scope: f.subScope(scope.Name),
+		tags:  f.mergeTags(scope.Tags),
+	}
+}
+
+func (f *Factory) subScope(name string) string {
+	if f.scope == "" {
+		return name
+	}
+	if name == "" {
+		return f.scope
+	}
+	return f.scope + "." + name
+}
+
+func (f *Factory) mergeTags(tags map[string]string) map[string]string {
+	ret := make(map[string]string, len(f.tags)+len(tags))
+	for k, v := range f.tags {
+		ret[k] = v
+	}
+	for k, v := range tags {
+		ret[k] = v
+	}
+	return ret
+}
+
+// attributes returns the attributes of the measurements of a metric, built once
+// since the tags of the metrics do not change.
+func (f *Factory) attributes(tags map[string]string) metric.MeasurementOption {
+	merged := f.mergeTags(tags)
+	attrs := make([]attribute.KeyValue, 0, len(merged))
+	for k, v := range merged {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	return metric.WithAttributeSet(attribute.NewSet(attrs...))
+}
+
+type counter struct {
+	counter metric.Int64Counter
+	attrs   metric.MeasurementOption
+}
+
+func (c *counter) Inc(v int64) {
+	c.counter.Add(context.Background(), v, c.attrs)
+}
+
+type gauge struct {
+	value atomic.Int64
+	attrs metric.MeasurementOption
+}
+
+func (g *gauge) Update(v int64) {
+	g.value.Store(v)
+}
+
+func (g *gauge) observe(og metric.Int64ObservableGauge) metric.Callback {
+	return func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(og, g.value.Load(), g.attrs)
+		return nil
+	}
+}
+
+type histogram struct {
+	histogram metric.Float64Histogram
+	attrs     metric.MeasurementOption
+}
+
+func (h *histogram) Record(v float64) {
+	h.histogram.Record(context.Background(), v, h.attrs)
+}
+
+type timer struct {
+	histogram
+}
+
+func (t *timer) Record(v time.Duration) {
+	t.histogram.Record(v.Seconds())
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+func newTestFactory(t *testing.T, opts ...sdkmetric.Option) (*Factory, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(append(opts, sdkmetric.WithReader(reader))...)
+	t.Cleanup(func() {
+		require.NoError(t, provider.Shutdown(context.Background()))
+	})
+	return NewFactory(provider), reader
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	collected := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		assert.Equal(t, scopeName, sm.Scope.Name)
+		for _, m := range sm.Metrics {
+			collected[m.Name] = m
+		}
+	}
+	return collected
+}
+
+func TestCounter(t *testing.T) {
+	f, reader := newTestFactory(t)
+	ns := f.Namespace(metrics.NSOptions{Name: "jaeger", Tags: map[string]string{"a": "1", "b": "2"}})
+	c1 := ns.Counter(metrics.Options{Name: "spans.received", Tags: map[string]string{"b": "3"}, Help: "spans"})
+	c2 := ns.Counter(metrics.Options{Name: "spans.received", Tags: map[string]string{"b": "4"}, Help: "spans"})
+	c1.Inc(2)
+	c1.Inc(3)
+	c2.Inc(1)
+
+	m := collect(t, reader)["jaeger.spans.received"]
+	assert.Equal(t, "spans", m.Description)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	assert.True(t, sum.IsMonotonic)
+	values := make(map[string]int64)
+	for _, dp := range sum.DataPoints {
+		values[dp.Attributes.Encoded(attribute.DefaultEncoder())] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"a=1,b=3": 5, "a=1,b=4": 1}, values)
+}
+
+func TestGauge(t *testing.T) {
+	f, reader := newTestFactory(t)
+	g := f.Namespace(metrics.NSOptions{Name: "queue"}).Gauge(metrics.Options{Name: "length", Tags: map[string]string{"x": "y"}})
+	g.Update(42)
+	g.Update(7)
+
+	gauge, ok := collect(t, reader)["queue.length"].Data.(metricdata.Gauge[int64])
+	require.True(t, ok)
+	require.Len(t, gauge.DataPoints, 1)
+	assert.Equal(t, int64(7), gauge.DataPoints[0].Value)
+	assert.Equal(t, attribute.NewSet(attribute.String("x", "y")), gauge.DataPoints[0].Attributes)
+}
+
+func TestTimer(t *testing.T) {
+	f, reader := newTestFactory(t)
+	f.Timer(metrics.TimerOptions{Name: "latency"}).Record(300 * time.Millisecond)
+	f.Timer(metrics.TimerOptions{
+		Name:    "custom.latency",
+		Buckets: []time.Duration{time.Millisecond, time.Second},
+	}).Record(2 * time.Second)
+
+	collected := collect(t, reader)
+	m := collected["latency"]
+	assert.Equal(t, "s", m.Unit)
+	h, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, h.DataPoints, 1)
+	assert.Equal(t, defaultTimerBuckets, h.DataPoints[0].Bounds)
+	assert.InDelta(t, 0.3, h.DataPoints[0].Sum, 1e-9)
+
+	h, ok = collected["custom.latency"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Equal(t, []float64{0.001, 1}, h.DataPoints[0].Bounds)
+	assert.Equal(t, []uint64{0, 0, 1}, h.DataPoints[0].BucketCounts)
+}
+
+func TestHistogram(t *testing.T) {
+	f, reader := newTestFactory(t)
+	f.Histogram(metrics.HistogramOptions{Name: "size", Buckets: []float64{10, 100}}).Record(50)
+
+	h, ok := collect(t, reader)["size"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Equal(t, []float64{10, 100}, h.DataPoints[0].Bounds)
+	assert.Equal(t, []uint64{0, 1, 0}, h.DataPoints[0].BucketCounts)
+}
+
+func TestViews(t *testing.T) {
+	// the views of the meter provider apply to the metrics, e.g. to drop or rename them
+	f, reader := newTestFactory(t,
+		sdkmetric.WithView(sdkmetric.NewView(
+			sdkmetric.Instrument{Name: "dropped"},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationDrop{}},
+		)),
+		sdkmetric.WithView(sdkmetric.NewView(
+			sdkmetric.Instrument{Name: "old"},
+			sdkmetric.Stream{Name: "new"},
+		)),
+	)
+	f.Counter(metrics.Options{Name: "dropped"}).Inc(1)
+	f.Counter(metrics.Options{Name: "old"}).Inc(1)
+
+	collected := collect(t, reader)
+	assert.NotContains(t, collected, "dropped")
+	assert.NotContains(t, collected, "old")
+	assert.Contains(t, collected, "new")
+}
+
+func TestNamespace(t *testing.T) {
+	f, reader := newTestFactory(t)
+	f.Namespace(metrics.NSOptions{}).Namespace(metrics.NSOptions{Name: "a"}).
+		Namespace(metrics.NSOptions{}).Namespace(metrics.NSOptions{Name: "b"}).
+		Counter(metrics.Options{Name: "c"}).Inc(1)
+	f.Namespace(metrics.NSOptions{Name: "d"}).Counter(metrics.Options{}).Inc(1)
+
+	collected := collect(t, reader)
+	assert.Contains(t, collected, "a.b.c")
+	assert.Contains(t, collected, "d")
+}
+
+func TestInvalidNames(t *testing.T) {
+	f, reader := newTestFactory(t)
+	// the SDK reports the invalid names to the error handler, but still records the metrics
+	f.Counter(metrics.Options{Name: "1counter"}).Inc(1)
+	f.Gauge(metrics.Options{Name: "1gauge"}).Update(1)
+	f.Timer(metrics.TimerOptions{Name: "1timer"}).Record(time.Second)
+	f.Histogram(metrics.HistogramOptions{Name: "1histogram"}).Record(1)
+	assert.NotPanics(t, func() { collect(t, reader) })
+}
@@ -227,6 +227,15 @@ func (c *counter) Inc(v int64) {
 	c.counter.Add(float64(v))
 }
 
+// IncWithExemplar implements metrics.CounterWithExemplar.
+func (c *counter) IncWithExemplar(v int64, traceID string) {
+	if adder, ok := c.counter.(prometheus.ExemplarAdder); ok {
+		adder.AddWithExemplar(float64(v), traceExemplar(traceID))
+		return
+	}
+	c.Inc(v)
+}
+
 type gauge struct {
 	gauge prometheus.Gauge
 }
@@ -244,7 +253,16 @@ type timer struct {
 }
 
 func (t *timer) Record(v time.Duration) {
-	t.histogram.Observe(float64(v.Nanoseconds()) / float64(time.Second/time.Nanosecond))
+	t.histogram.Observe(seconds(v))
+}
+
+// RecordWithExemplar implements metrics.TimerWithExemplar.
+func (t *timer) RecordWithExemplar(v time.Duration, traceID string) {
+	observeWithExemplar(t.histogram, seconds(v), traceID)
+}
+
+func seconds(v time.Duration) float64 {
+	return float64(v.Nanoseconds()) / float64(time.Second/time.Nanosecond)
 }
 
 type histogram struct {
@@ -255,6 +273,27 @@ func (h *histogram) Record(v float64) {
 	h.histogram.Observe(v)
 }
 
+// RecordWithExemplar implements metrics.HistogramWithExemplar.
+func (h *histogram) RecordWithExemplar(v float64, traceID string) {
+	observeWithExemplar(h.histogram, v, traceID)
+}
+
+// exemplarTraceIDLabel is the label of the exemplars holding the ID of the trace,
+// as expected by Grafana to link the exemplars to the traces.
+const exemplarTraceIDLabel = "trace_id"
+
+func traceExemplar(traceID string) prometheus.Labels {
+	return prometheus.Labels{exemplarTraceIDLabel: traceID}
+}
+
+func observeWithExemplar(o observer, v float64, traceID string) {
+	if eo, ok := o.(prometheus.ExemplarObserver); ok {
+		eo.ObserveWithExemplar(v, traceExemplar(traceID))
+		return
+	}
+	o.Observe(v)
+}
+
 func (f *Factory) subScope(name string) string {
 	if f.scope == "" {
 		return f.normalize(name)
@@ -406,6 +406,48 @@ func TestHistogramDefaultBuckets(t *testing.T) {
 	assert.Len(t, m1.GetHistogram().GetBucket(), 1)
 }
 
+func TestExemplars(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	f1 := New(WithRegisterer(registry), WithBuckets([]float64{1, 2}))
+	c1 := f1.Counter(metrics.Options{Name: "counter"})
+	t1 := f1.Timer(metrics.TimerOptions{Name: "timer"})
+	h1 := f1.Histogram(metrics.HistogramOptions{Name: "histogram"})
+
+	metrics.IncCounterWithExemplar(c1, 2, "0123456789abcdef0123456789abcdef")
+	metrics.RecordTimerWithExemplar(t1, 1500*time.Millisecond, "0123456789abcdef0123456789abcdef")
+	metrics.RecordHistogramWithExemplar(h1, 0.5, "fedcba9876543210fedcba9876543210")
+	// recorded without exemplar
+	metrics.RecordHistogramWithExemplar(h1, 1.5, "")
+
+	snapshot, err := registry.Gather()
+	require.NoError(t, err)
+
+	exemplarTraceID := func(e *promModel.Exemplar) string {
+		require.NotNil(t, e)
+		require.Len(t, e.GetLabel(), 1)
+		assert.Equal(t, "trace_id", e.GetLabel()[0].GetName())
+		return e.GetLabel()[0].GetValue()
+	}
+
+	m1 := findMetric(t, snapshot, "counter_total", map[string]string{})
+	assert.EqualValues(t, 2, m1.GetCounter().GetValue())
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", exemplarTraceID(m1.GetCounter().GetExemplar()))
+
+	m2 := findMetric(t, snapshot, "timer", map[string]string{})
+	assert.EqualValues(t, 1, m2.GetHistogram().GetSampleCount())
+	buckets := m2.GetHistogram().GetBucket()
+	require.Len(t, buckets, 2)
+	assert.Nil(t, buckets[0].GetExemplar())
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", exemplarTraceID(buckets[1].GetExemplar()))
+
+	m3 := findMetric(t, snapshot, "histogram", map[string]string{})
+	assert.EqualValues(t, 2, m3.GetHistogram().GetSampleCount())
+	buckets = m3.GetHistogram().GetBucket()
+	require.Len(t, buckets, 2)
+	assert.Equal(t, "fedcba9876543210fedcba9876543210", exemplarTraceID(buckets[0].GetExemplar()))
+	assert.Nil(t, buckets[1].GetExemplar())
+}
+
 func findMetric(t *testing.T, snapshot []*promModel.MetricFamily, name string, tags map[string]string) *promModel.Metric {
 	for _, mf := range snapshot {
 		if mf.GetName() != name {
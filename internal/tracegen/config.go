@@ -41,6 +41,7 @@ type Config struct {
 	Duration      time.Duration
 	Service       string
 	TraceExporter string
+	TopologyFile  string
 }
 
 // Flags registers config flags.
@@ -57,11 +58,25 @@ func (c *Config) Flags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.Duration, "duration", 0, "For how long to run the test if greater than 0s (overrides -traces).")
 	fs.StringVar(&c.Service, "service", "tracegen", "Service name prefix to use")
 	fs.IntVar(&c.Services, "services", 1, "Number of unique suffixes to add to service name when generating traces, e.g. tracegen-01 (but only one service per trace)")
+	fs.StringVar(&c.TopologyFile, "topology", "", "Path to a JSON file describing the services, their call graph, latencies and error rates, to generate multi-service traces (overrides -service, -services and -spans). See https://github.com/jaegertracing/jaeger/blob/main/cmd/tracegen/README.md")
 	fs.StringVar(&c.TraceExporter, "trace-exporter", "otlp-http", "Trace exporter (otlp/otlp-http|otlp-grpc|stdout). Exporters can be additionally configured via environment variables, see https://github.com/jaegertracing/jaeger/blob/main/cmd/tracegen/README.md")
 }
 
 // Run executes the test scenario.
 func Run(c *Config, tracers []trace.Tracer, logger *zap.Logger) error {
+	return run(c, tracers, nil, logger)
+}
+
+// RunTopology executes the test scenario generating the traces of the topology.
+// The tracers must be those of the services of the topology, in the same order.
+func RunTopology(c *Config, topology *Topology, tracers []trace.Tracer, logger *zap.Logger) error {
+	if len(tracers) != len(topology.Services) {
+		return fmt.Errorf("expected %d tracers for the services of the topology, got %d", len(topology.Services), len(tracers))
+	}
+	return run(c, tracers, newTopologySimulator(topology, tracers), logger)
+}
+
+func run(c *Config, tracers []trace.Tracer, topology *topologySimulator, logger *zap.Logger) error {
 	if c.Duration > 0 {
 		c.Traces = 0
 	} else if c.Traces <= 0 {
@@ -73,12 +88,13 @@ func Run(c *Config, tracers []trace.Tracer, logger *zap.Logger) error {
 	for i := 0; i < c.Workers; i++ {
 		wg.Add(1)
 		w := worker{
-			id:      i,
-			tracers: tracers,
-			Config:  *c,
-			running: &running,
-			wg:      &wg,
-			logger:  logger.With(zap.Int("worker", i)),
+			id:       i,
+			tracers:  tracers,
+			Config:   *c,
+			running:  &running,
+			wg:       &wg,
+			logger:   logger.With(zap.Int("worker", i)),
+			topology: topology,
 		}
 
 		go w.simulateTraces()
@@ -0,0 +1,236 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Topology describes a simulated application as a set of services and the calls
+// between their operations. Each generated trace starts at the root operation.
+type Topology struct {
+	// Root is the operation receiving the requests. Defaults to the first operation of the first service.
+	Root     *CallTarget `json:"root,omitempty"`
+	Services []Service   `json:"services"`
+}
+
+// Service is a service of a Topology.
+type Service struct {
+	Name       string      `json:"name"`
+	Operations []Operation `json:"operations"`
+}
+
+// Operation is an operation of a service, calling operations of other services sequentially.
+type Operation struct {
+	Name string `json:"name"`
+	// Latency is the time spent by the operation itself, excluding its calls.
+	Latency Latency `json:"latency"`
+	// ErrorRate is the probability, between 0 and 1, that the operation fails.
+	ErrorRate float64      `json:"error_rate"`
+	Calls     []CallTarget `json:"calls,omitempty"`
+}
+
+// CallTarget identifies an operation of a service.
+type CallTarget struct {
+	Service   string `json:"service"`
+	Operation string `json:"operation"`
+}
+
+// Latency is a normal distribution of durations, truncated at zero.
+type Latency struct {
+	MeanMillis   float64 `json:"mean_ms"`
+	StdDevMillis float64 `json:"stddev_ms"`
+}
+
+func (l Latency) sample() time.Duration {
+	ms := l.MeanMillis + rand.NormFloat64()*l.StdDevMillis
+	return time.Duration(max(ms, 0) * float64(time.Millisecond))
+}
+
+// LoadTopology reads and validates a topology from a JSON file.
+func LoadTopology(path string) (*Topology, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology file: %w", err)
+	}
+	var topology Topology
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&topology); err != nil {
+		return nil, fmt.Errorf("failed to parse topology file: %w", err)
+	}
+	if err := topology.Validate(); err != nil {
+		return nil, err
+	}
+	return &topology, nil
+}
+
+// Validate checks that the services and operations are unique, that the calls target
+// existing operations, and that the call graph has no cycles.
+func (t *Topology) Validate() error {
+	if len(t.Services) == 0 || len(t.Services[0].Operations) == 0 {
+		return errors.New("topology must have at least one service with an operation")
+	}
+	services := make(map[string]bool)
+	operations := make(map[CallTarget]*Operation)
+	for i := range t.Services {
+		svc := &t.Services[i]
+		if svc.Name == "" {
+			return errors.New("service name must not be empty")
+		}
+		if services[svc.Name] {
+			return fmt.Errorf("duplicate service %q", svc.Name)
+		}
+		services[svc.Name] = true
+		for j := range svc.Operations {
+			op := &svc.Operations[j]
+			target := CallTarget{Service: svc.Name, Operation: op.Name}
+			if op.Name == "" {
+				return fmt.Errorf("operation name of service %q must not be empty", svc.Name)
+			}
+			if operations[target] != nil {
+				return fmt.Errorf("duplicate operation %q of service %q", op.Name, svc.Name)
+			}
+			if op.ErrorRate < 0 || op.ErrorRate > 1 {
+				return fmt.Errorf("error rate of operation %q of service %q must be between 0 and 1", op.Name, svc.Name)
+			}
+			if op.Latency.MeanMillis < 0 || op.Latency.StdDevMillis < 0 {
+				return fmt.Errorf("latency of operation %q of service %q must not be negative", op.Name, svc.Name)
+			}
+			operations[target] = op
+		}
+	}
+	for target, op := range operations {
+		for _, call := range op.Calls {
+			if operations[call] == nil {
+				return fmt.Errorf("operation %q of service %q calls unknown operation %q of service %q",
+					target.Operation, target.Service, call.Operation, call.Service)
+			}
+		}
+	}
+	if t.Root != nil && operations[*t.Root] == nil {
+		return fmt.Errorf("unknown root operation %q of service %q", t.Root.Operation, t.Root.Service)
+	}
+
+	// detect cycles with a depth-first search
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[CallTarget]int)
+	var visit func(target CallTarget) error
+	visit = func(target CallTarget) error {
+		switch state[target] {
+		case visiting:
+			return fmt.Errorf("call graph has a cycle through operation %q of service %q", target.Operation, target.Service)
+		case visited:
+			return nil
+		}
+		state[target] = visiting
+		for _, call := range operations[target].Calls {
+			if err := visit(call); err != nil {
+				return err
+			}
+		}
+		state[target] = visited
+		return nil
+	}
+	for target := range operations {
+		if err := visit(target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServiceNames returns the names of the services, in the order of Services.
+func (t *Topology) ServiceNames() []string {
+	names := make([]string, len(t.Services))
+	for i, svc := range t.Services {
+		names[i] = svc.Name
+	}
+	return names
+}
+
+func (t *Topology) root() CallTarget {
+	if t.Root != nil {
+		return *t.Root
+	}
+	return CallTarget{Service: t.Services[0].Name, Operation: t.Services[0].Operations[0].Name}
+}
+
+// topologySimulator generates the traces of a topology.
+type topologySimulator struct {
+	topology *Topology
+	// tracers of the services, by service name
+	tracers    map[string]trace.Tracer
+	operations map[CallTarget]*Operation
+}
+
+// newTopologySimulator creates a topologySimulator; the tracers must be in the order of topology.Services.
+func newTopologySimulator(topology *Topology, tracers []trace.Tracer) *topologySimulator {
+	s := &topologySimulator{
+		topology:   topology,
+		tracers:    make(map[string]trace.Tracer),
+		operations: make(map[CallTarget]*Operation),
+	}
+	for i := range topology.Services {
+		svc := &topology.Services[i]
+		s.tracers[svc.Name] = tracers[i]
+		for j := range svc.Operations {
+			s.operations[CallTarget{Service: svc.Name, Operation: svc.Operations[j].Name}] = &svc.Operations[j]
+		}
+	}
+	return s
+}
+
+func (s *topologySimulator) simulateTrace(opts []trace.SpanStartOption) {
+	s.simulateOperation(context.Background(), s.topology.root(), time.Now(), opts)
+}
+
+// simulateOperation generates the server span of the operation and the client spans of its calls,
+// and returns the end time of the server span and whether the operation failed.
+func (s *topologySimulator) simulateOperation(
+	ctx context.Context,
+	target CallTarget,
+	start time.Time,
+	opts []trace.SpanStartOption,
+) (time.Time, bool) {
+	op := s.operations[target]
+	tracer := s.tracers[target.Service]
+	opts = append(opts, trace.WithSpanKind(trace.SpanKindServer), trace.WithTimestamp(start))
+	ctx, span := tracer.Start(ctx, op.Name, opts...)
+
+	// the time spent by the operation itself is before its first call
+	end := start.Add(op.Latency.sample())
+	for _, call := range op.Calls {
+		callCtx, client := tracer.Start(ctx, call.Operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(end),
+		)
+		var failed bool
+		end, failed = s.simulateOperation(callCtx, call, end, nil)
+		if failed {
+			client.SetStatus(codes.Error, "call failed")
+		}
+		client.End(trace.WithTimestamp(end))
+	}
+	failed := rand.Float64() < op.ErrorRate
+	if failed {
+		span.SetStatus(codes.Error, "simulated error")
+	}
+	span.End(trace.WithTimestamp(end))
+	return end, failed
+}
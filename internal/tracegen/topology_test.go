@@ -0,0 +1,212 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const testTopology = `{
+  "services": [
+    {
+      "name": "frontend",
+      "operations": [
+        {
+          "name": "/dispatch",
+          "latency": {"mean_ms": 2},
+          "calls": [
+            {"service": "customer", "operation": "/customer"},
+            {"service": "driver", "operation": "FindNearest"}
+          ]
+        }
+      ]
+    },
+    {
+      "name": "customer",
+      "operations": [
+        {"name": "/customer", "latency": {"mean_ms": 10}}
+      ]
+    },
+    {
+      "name": "driver",
+      "operations": [
+        {"name": "FindNearest", "latency": {"mean_ms": 5}, "error_rate": 1}
+      ]
+    }
+  ]
+}`
+
+func writeTopologyFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "topology.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadTopology(t *testing.T) {
+	topology, err := LoadTopology(writeTopologyFile(t, testTopology))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend", "customer", "driver"}, topology.ServiceNames())
+	assert.Equal(t, CallTarget{Service: "frontend", Operation: "/dispatch"}, topology.root())
+
+	_, err = LoadTopology(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorContains(t, err, "failed to read topology file")
+
+	_, err = LoadTopology(writeTopologyFile(t, `{"services": [], "unknown": 1}`))
+	require.ErrorContains(t, err, "failed to parse topology file")
+
+	_, err = LoadTopology(writeTopologyFile(t, `{"services": []}`))
+	require.ErrorContains(t, err, "at least one service")
+}
+
+func TestTopologyValidate(t *testing.T) {
+	op := func(name string, calls ...CallTarget) Operation {
+		return Operation{Name: name, Calls: calls}
+	}
+	tests := []struct {
+		name     string
+		topology Topology
+		err      string
+	}{
+		{
+			name: "empty service name",
+			topology: Topology{Services: []Service{
+				{Name: "", Operations: []Operation{op("a")}},
+			}},
+			err: "service name must not be empty",
+		},
+		{
+			name: "duplicate service",
+			topology: Topology{Services: []Service{
+				{Name: "s", Operations: []Operation{op("a")}},
+				{Name: "s", Operations: []Operation{op("b")}},
+			}},
+			err: `duplicate service "s"`,
+		},
+		{
+			name: "empty operation name",
+			topology: Topology{Services: []Service{
+				{Name: "s", Operations: []Operation{op("")}},
+			}},
+			err: `operation name of service "s" must not be empty`,
+		},
+		{
+			name: "duplicate operation",
+			topology: Topology{Services: []Service{
+				{Name: "s", Operations: []Operation{op("a"), op("a")}},
+			}},
+			err: `duplicate operation "a" of service "s"`,
+		},
+		{
+			name: "invalid error rate",
+			topology: Topology{Services: []Service{
+				{Name: "s", Operations: []Operation{{Name: "a", ErrorRate: 1.5}}},
+			}},
+			err: "must be between 0 and 1",
+		},
+		{
+			name: "negative latency",
+			topology: Topology{Services: []Service{
+				{Name: "s", Operations: []Operation{{Name: "a", Latency: Latency{StdDevMillis: -1}}}},
+			}},
+			err: "must not be negative",
+		},
+		{
+			name: "unknown call",
+			topology: Topology{Services: []Service{
+				{Name: "s", Operations: []Operation{op("a", CallTarget{Service: "t", Operation: "b"})}},
+			}},
+			err: `calls unknown operation "b" of service "t"`,
+		},
+		{
+			name: "unknown root",
+			topology: Topology{
+				Root:     &CallTarget{Service: "s", Operation: "b"},
+				Services: []Service{{Name: "s", Operations: []Operation{op("a")}}},
+			},
+			err: `unknown root operation "b" of service "s"`,
+		},
+		{
+			name: "cycle",
+			topology: Topology{Services: []Service{
+				{Name: "s", Operations: []Operation{op("a", CallTarget{Service: "t", Operation: "b"})}},
+				{Name: "t", Operations: []Operation{op("b", CallTarget{Service: "s", Operation: "a"})}},
+			}},
+			err: "call graph has a cycle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.topology.Validate(), tt.err)
+		})
+	}
+}
+
+func TestTopologySimulateTrace(t *testing.T) {
+	topology, err := LoadTopology(writeTopologyFile(t, testTopology))
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	var tracers []trace.Tracer
+	for _, svc := range topology.ServiceNames() {
+		tracers = append(tracers, tp.Tracer(svc))
+	}
+	newTopologySimulator(topology, tracers).simulateTrace(nil)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.InstrumentationScope().Name+" "+span.SpanKind().String()+" "+span.Name()] = span
+	}
+	require.Len(t, spans, 5)
+	root := spans["frontend server /dispatch"]
+	customerClient := spans["frontend client /customer"]
+	customerServer := spans["customer server /customer"]
+	driverClient := spans["frontend client FindNearest"]
+	driverServer := spans["driver server FindNearest"]
+	for _, span := range []sdktrace.ReadOnlySpan{root, customerClient, customerServer, driverClient, driverServer} {
+		require.NotNil(t, span)
+		assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+
+	assert.False(t, root.Parent().IsValid())
+	assert.Equal(t, root.SpanContext().SpanID(), customerClient.Parent().SpanID())
+	assert.Equal(t, customerClient.SpanContext().SpanID(), customerServer.Parent().SpanID())
+	assert.Equal(t, root.SpanContext().SpanID(), driverClient.Parent().SpanID())
+	assert.Equal(t, driverClient.SpanContext().SpanID(), driverServer.Parent().SpanID())
+
+	// the calls are sequential, after the latency of the caller
+	assert.Equal(t, 2*time.Millisecond, customerClient.StartTime().Sub(root.StartTime()))
+	assert.Equal(t, 10*time.Millisecond, customerServer.EndTime().Sub(customerServer.StartTime()))
+	assert.Equal(t, customerClient.EndTime(), driverClient.StartTime())
+	assert.Equal(t, 17*time.Millisecond, root.EndTime().Sub(root.StartTime()))
+
+	assert.Equal(t, codes.Unset, root.Status().Code)
+	assert.Equal(t, codes.Unset, customerClient.Status().Code)
+	assert.Equal(t, codes.Error, driverClient.Status().Code)
+	assert.Equal(t, codes.Error, driverServer.Status().Code)
+}
+
+func TestRunTopology(t *testing.T) {
+	topology, err := LoadTopology(writeTopologyFile(t, testTopology))
+	require.NoError(t, err)
+	tp := sdktrace.NewTracerProvider()
+	config := &Config{Workers: 2, Traces: 3}
+
+	err = RunTopology(config, topology, []trace.Tracer{tp.Tracer("frontend")}, zap.NewNop())
+	require.ErrorContains(t, err, "expected 3 tracers")
+
+	tracers := []trace.Tracer{tp.Tracer("frontend"), tp.Tracer("customer"), tp.Tracer("driver")}
+	require.NoError(t, RunTopology(config, topology, tracers, zap.NewNop()))
+}
@@ -33,6 +33,8 @@ type worker struct {
 	Config
 	wg     *sync.WaitGroup // notify when done
 	logger *zap.Logger
+	// topology, if set, is used to generate multi-service traces instead of simple ones
+	topology *topologySimulator
 
 	// internal counters
 	traceNo   int
@@ -46,8 +48,12 @@ const (
 
 func (w *worker) simulateTraces() {
 	for atomic.LoadUint32(w.running) == 1 {
-		svcNo := w.traceNo % len(w.tracers)
-		w.simulateOneTrace(w.tracers[svcNo])
+		if w.topology != nil {
+			w.topology.simulateTrace(w.rootSpanOptions())
+		} else {
+			svcNo := w.traceNo % len(w.tracers)
+			w.simulateOneTrace(w.tracers[svcNo])
+		}
 		w.traceNo++
 		if w.Traces != 0 {
 			if w.traceNo >= w.Traces {
@@ -59,6 +65,18 @@ func (w *worker) simulateTraces() {
 	w.wg.Done()
 }
 
+// rootSpanOptions returns the options of the root span of the traces with topology.
+func (w *worker) rootSpanOptions() []trace.SpanStartOption {
+	var attrs []attribute.KeyValue
+	if w.Debug {
+		attrs = append(attrs, attribute.Bool("jaeger.debug", true))
+	}
+	if w.Firehose {
+		attrs = append(attrs, attribute.Bool("jaeger.firehose", true))
+	}
+	return []trace.SpanStartOption{trace.WithAttributes(attrs...)}
+}
+
 func (w *worker) simulateOneTrace(tracer trace.Tracer) {
 	ctx := context.Background()
 	attrs := []attribute.KeyValue{
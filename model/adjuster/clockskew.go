@@ -35,10 +35,35 @@ import (
 // This adjuster never returns any errors. Instead it records any issues
 // it encounters in Span.Warnings.
 func ClockSkew(maxDelta time.Duration) Adjuster {
+	return ClockSkewWithOptions(ClockSkewOptions{MaxDelta: maxDelta})
+}
+
+// ClockSkewOptions configures the ClockSkew adjuster.
+type ClockSkewOptions struct {
+	// MaxDelta is the maximum adjustment of the timestamps of a span, the larger skews
+	// are only reported in Span.Warnings. Zero disables the adjustments.
+	MaxDelta time.Duration
+	// DisabledServices are the services whose spans are never adjusted, e.g. because
+	// their clocks are known to be synchronized. The spans of the other services are
+	// still compared to them.
+	DisabledServices []string
+	// AdjustedTag, if not empty, is the key of the tag added to the adjusted spans,
+	// with the adjustment as value, in addition to the warning.
+	AdjustedTag string
+}
+
+// ClockSkewWithOptions returns the ClockSkew adjuster configured with the options.
+func ClockSkewWithOptions(opts ClockSkewOptions) Adjuster {
+	disabledServices := make(map[string]bool, len(opts.DisabledServices))
+	for _, service := range opts.DisabledServices {
+		disabledServices[service] = true
+	}
 	return Func(func(trace *model.Trace) (*model.Trace, error) {
 		adjuster := &clockSkewAdjuster{
-			trace:    trace,
-			maxDelta: maxDelta,
+			trace:            trace,
+			maxDelta:         opts.MaxDelta,
+			disabledServices: disabledServices,
+			adjustedTag:      opts.AdjustedTag,
 		}
 		adjuster.buildNodesMap()
 		adjuster.buildSubGraphs()
@@ -55,13 +80,16 @@ const (
 	warningFormatInvalidParentID = "invalid parent span IDs=%s; skipping clock skew adjustment"
 	warningMaxDeltaExceeded      = "max clock skew adjustment delta of %v exceeded; not applying calculated delta of %v"
 	warningSkewAdjustDisabled    = "clock skew adjustment disabled; not applying calculated delta of %v"
+	warningServiceAdjustDisabled = "clock skew adjustment disabled for service %s; not applying calculated delta of %v"
 )
 
 type clockSkewAdjuster struct {
-	trace    *model.Trace
-	spans    map[model.SpanID]*node
-	roots    map[model.SpanID]*node
-	maxDelta time.Duration
+	trace            *model.Trace
+	spans            map[model.SpanID]*node
+	roots            map[model.SpanID]*node
+	maxDelta         time.Duration
+	disabledServices map[string]bool
+	adjustedTag      string
 }
 
 type clockSkew struct {
@@ -144,6 +172,10 @@ func (a *clockSkewAdjuster) adjustNode(n *node, parent *node, skew clockSkew) {
 		}
 	}
 	a.adjustTimestamps(n, skew)
+	if a.disabledServices[n.span.Process.ServiceName] {
+		// the spans from the same host share the clock of the span, which is not adjusted
+		skew.delta = 0
+	}
 	for _, child := range n.children {
 		a.adjustNode(child, n, skew)
 	}
@@ -180,6 +212,11 @@ func (a *clockSkewAdjuster) adjustTimestamps(n *node, skew clockSkew) {
 		return
 	}
 
+	if service := n.span.Process.ServiceName; a.disabledServices[service] {
+		n.span.Warnings = append(n.span.Warnings, fmt.Sprintf(warningServiceAdjustDisabled, service, skew.delta))
+		return
+	}
+
 	if absDuration(skew.delta) > a.maxDelta {
 		if a.maxDelta == 0 {
 			n.span.Warnings = append(n.span.Warnings, fmt.Sprintf(warningSkewAdjustDisabled, skew.delta))
@@ -192,6 +229,9 @@ func (a *clockSkewAdjuster) adjustTimestamps(n *node, skew clockSkew) {
 
 	n.span.StartTime = n.span.StartTime.Add(skew.delta)
 	n.span.Warnings = append(n.span.Warnings, fmt.Sprintf("This span's timestamps were adjusted by %v", skew.delta))
+	if a.adjustedTag != "" {
+		n.span.Tags = append(n.span.Tags, model.String(a.adjustedTag, skew.delta.String()))
+	}
 
 	for i := range n.span.Logs {
 		n.span.Logs[i].Timestamp = n.span.Logs[i].Timestamp.Add(skew.delta)
@@ -235,6 +235,50 @@ func TestClockSkewAdjuster(t *testing.T) {
 	}
 }
 
+func TestClockSkewAdjusterOptions(t *testing.T) {
+	traceID := model.NewTraceID(0, 1)
+	makeSpan := func(id, parent uint64, start, duration time.Duration, host string) *model.Span {
+		return &model.Span{
+			TraceID:    traceID,
+			SpanID:     model.NewSpanID(id),
+			References: []model.SpanRef{model.NewChildOfRef(traceID, model.NewSpanID(parent))},
+			StartTime:  time.Unix(0, 0).Add(start),
+			Duration:   duration,
+			Process:    model.NewProcess(host, []model.KeyValue{model.String("ip", host)}),
+		}
+	}
+	makeTrace := func() *model.Trace {
+		return &model.Trace{Spans: []*model.Span{
+			makeSpan(1, 0, 10*time.Millisecond, 100*time.Millisecond, "a"),
+			// delta = (10 - 0) + (100 - 50) / 2 = 35
+			makeSpan(2, 1, 0, 50*time.Millisecond, "b"),
+			makeSpan(3, 2, 10*time.Millisecond, 20*time.Millisecond, "b"),
+		}}
+	}
+
+	trace, err := ClockSkewWithOptions(ClockSkewOptions{
+		MaxDelta:         time.Second,
+		DisabledServices: []string{"b"},
+	}).Adjust(makeTrace())
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(0, 0), trace.Spans[1].StartTime)
+	assert.Equal(t, []string{"clock skew adjustment disabled for service b; not applying calculated delta of 35ms"}, trace.Spans[1].Warnings)
+	// the spans from the same host are not adjusted either
+	assert.Equal(t, time.Unix(0, 0).Add(10*time.Millisecond), trace.Spans[2].StartTime)
+	assert.Empty(t, trace.Spans[2].Warnings)
+
+	trace, err = ClockSkewWithOptions(ClockSkewOptions{
+		MaxDelta:    time.Second,
+		AdjustedTag: "clock_skew.adjustment",
+	}).Adjust(makeTrace())
+	require.NoError(t, err)
+	assert.Empty(t, trace.Spans[0].Tags)
+	for _, span := range trace.Spans[1:] {
+		assert.Equal(t, model.KeyValues{model.String("clock_skew.adjustment", "35ms")}, model.KeyValues(span.Tags))
+		assert.Len(t, span.Warnings, 1)
+	}
+}
+
 func TestHostKey(t *testing.T) {
 	testCases := []struct {
 		tag     model.KeyValue
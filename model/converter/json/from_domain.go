@@ -101,11 +101,15 @@ func (fd fromDomain) convertSpanEmbedProcess(span *model.Span) *json.Span {
 func (fd fromDomain) convertReferences(span *model.Span) []json.Reference {
 	out := make([]json.Reference, 0, len(span.References))
 	for _, ref := range span.References {
-		out = append(out, json.Reference{
+		jsonRef := json.Reference{
 			RefType: fd.convertRefType(ref.RefType),
 			TraceID: json.TraceID(ref.TraceID.String()),
 			SpanID:  json.SpanID(ref.SpanID.String()),
-		})
+		}
+		if len(ref.Tags) > 0 {
+			jsonRef.Tags = fd.convertKeyValuesFunc(ref.Tags)
+		}
+		out = append(out, jsonRef)
 	}
 	return out
 }
@@ -144,6 +144,27 @@ func testJSONEncoding(t *testing.T, i int, expectedStr []byte, object interface{
 	}
 }
 
+func TestFromDomainReferenceTags(t *testing.T) {
+	span := &model.Span{
+		TraceID: model.NewTraceID(0, 1),
+		SpanID:  model.NewSpanID(2),
+		References: []model.SpanRef{
+			model.NewChildOfRef(model.NewTraceID(0, 1), model.NewSpanID(1)),
+			{
+				TraceID: model.NewTraceID(0, 3),
+				SpanID:  model.NewSpanID(4),
+				RefType: model.FollowsFrom,
+				Tags:    []model.KeyValue{model.String("link.kind", "batch")},
+			},
+		},
+		Process: &model.Process{ServiceName: "frontend"},
+	}
+	jsonSpan := FromDomainEmbedProcess(span)
+	require.Len(t, jsonSpan.References, 2)
+	assert.Nil(t, jsonSpan.References[0].Tags)
+	assert.Equal(t, []jModel.KeyValue{{Key: "link.kind", Type: jModel.StringType, Value: "batch"}}, jsonSpan.References[1].Tags)
+}
+
 func TestDependenciesFromDomain(t *testing.T) {
 	someParent := "someParent"
 	someChild := "someChild"
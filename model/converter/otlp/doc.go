@@ -0,0 +1,7 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package otlp allows converting OTLP traces to/from Jaeger batches without
+// losing the attributes of the span links, which are stored in the span references,
+// nor the map and slice attributes of the resources, which are stored in a process tag.
+package otlp
@@ -0,0 +1,300 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package otlp
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	otlp2jaeger "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+const (
+	// refTypeAttribute is the link attribute holding the type of the span reference,
+	// it is already handled by the upstream translator
+	refTypeAttribute = "opentracing.ref_type"
+	// traceStateTag is the span reference tag holding the trace state of the span link
+	traceStateTag = "w3c.tracestate"
+	// resourceTag is the process tag holding, as OTLP/JSON, the map and slice attributes of the resource,
+	// which are flattened to JSON strings in the other process tags
+	resourceTag = "otel.resource"
+)
+
+var refTypeAttributeValues = map[model.SpanRefType]string{
+	model.ChildOf:     "child_of",
+	model.FollowsFrom: "follows_from",
+}
+
+type spanKey struct {
+	traceID model.TraceID
+	spanID  model.SpanID
+}
+
+// ProtoFromTraces converts OTLP traces to Jaeger batches. Unlike the upstream translator,
+// the attributes and the trace state of the span links are kept in the span references,
+// and the map and slice attributes of the resources are kept in the process tags.
+func ProtoFromTraces(td ptrace.Traces) ([]*model.Batch, error) {
+	batches, err := otlp2jaeger.ProtoFromTraces(td)
+	if err != nil {
+		return nil, err
+	}
+	var spans map[spanKey]*model.Span
+	rss := td.ResourceSpans()
+	batch := 0
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		// the upstream translator creates a batch for each resource with attributes or spans
+		if rs.Resource().Attributes().Len() == 0 && rs.ScopeSpans().Len() == 0 {
+			continue
+		}
+		tag, ok, err := resourceToTag(rs)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			batches[batch].Process.Tags = append(batches[batch].Process.Tags, tag)
+		}
+		batch++
+		sss := rs.ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			otlpSpans := sss.At(j).Spans()
+			for k := 0; k < otlpSpans.Len(); k++ {
+				span := otlpSpans.At(k)
+				links := span.Links()
+				for l := 0; l < links.Len(); l++ {
+					link := links.At(l)
+					tags := linkToTags(link)
+					if len(tags) == 0 {
+						continue
+					}
+					if spans == nil {
+						spans = indexSpans(batches)
+					}
+					jSpan, ok := spans[spanKey{traceIDFromOTLP(span.TraceID()), spanIDFromOTLP(span.SpanID())}]
+					if !ok {
+						continue
+					}
+					if ref := findUntaggedRef(jSpan.References, traceIDFromOTLP(link.TraceID()), spanIDFromOTLP(link.SpanID())); ref != nil {
+						ref.Tags = tags
+					}
+				}
+			}
+		}
+	}
+	return batches, nil
+}
+
+// ProtoToTraces converts Jaeger batches to OTLP traces. Unlike the upstream translator,
+// the tags of the span references are restored as the attributes and the trace state of the span links,
+// and the resources are restored with their map and slice attributes.
+func ProtoToTraces(batches []*model.Batch) (ptrace.Traces, error) {
+	// the upstream translator regroups the spans, so they are matched by ID
+	var taggedRefs map[spanKey][]model.SpanRef
+	for _, batch := range batches {
+		for _, span := range batch.Spans {
+			for _, ref := range span.References {
+				if len(ref.Tags) == 0 {
+					continue
+				}
+				if taggedRefs == nil {
+					taggedRefs = make(map[spanKey][]model.SpanRef)
+				}
+				taggedRefs[spanKey{span.TraceID, span.SpanID}] = span.References
+				break
+			}
+		}
+	}
+	td, err := otlp2jaeger.ProtoToTraces(batches)
+	if err != nil {
+		return td, err
+	}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		if err := restoreResource(rss.At(i)); err != nil {
+			return td, err
+		}
+		if taggedRefs == nil {
+			continue
+		}
+		sss := rss.At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				refs, ok := taggedRefs[spanKey{traceIDFromOTLP(span.TraceID()), spanIDFromOTLP(span.SpanID())}]
+				if ok {
+					restoreLinks(refs, span.Links())
+				}
+			}
+		}
+	}
+	return td, nil
+}
+
+// resourceToTag encodes as OTLP/JSON the attributes of the resource that the upstream translator
+// flattens to JSON strings, i.e. the maps and the slices, if any.
+func resourceToTag(rs ptrace.ResourceSpans) (model.KeyValue, bool, error) {
+	td := ptrace.NewTraces()
+	kept := td.ResourceSpans().AppendEmpty().Resource().Attributes()
+	rs.Resource().Attributes().Range(func(key string, value pcommon.Value) bool {
+		if value.Type() == pcommon.ValueTypeMap || value.Type() == pcommon.ValueTypeSlice {
+			value.CopyTo(kept.PutEmpty(key))
+		}
+		return true
+	})
+	if kept.Len() == 0 {
+		return model.KeyValue{}, false, nil
+	}
+	buf, err := (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+	if err != nil {
+		return model.KeyValue{}, false, fmt.Errorf("failed to encode the resource: %w", err)
+	}
+	return model.String(resourceTag, string(buf)), true, nil
+}
+
+// restoreResource replaces the attribute holding the resource tag by the attributes it encodes.
+func restoreResource(rs ptrace.ResourceSpans) error {
+	attrs := rs.Resource().Attributes()
+	value, ok := attrs.Get(resourceTag)
+	if !ok {
+		return nil
+	}
+	encoded := value.AsString()
+	attrs.Remove(resourceTag)
+	td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces([]byte(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to decode the resource: %w", err)
+	}
+	if td.ResourceSpans().Len() == 0 {
+		return nil
+	}
+	td.ResourceSpans().At(0).Resource().Attributes().Range(func(key string, value pcommon.Value) bool {
+		value.CopyTo(attrs.PutEmpty(key))
+		return true
+	})
+	return nil
+}
+
+func indexSpans(batches []*model.Batch) map[spanKey]*model.Span {
+	spans := make(map[spanKey]*model.Span)
+	for _, batch := range batches {
+		for _, span := range batch.Spans {
+			spans[spanKey{span.TraceID, span.SpanID}] = span
+		}
+	}
+	return spans
+}
+
+func findUntaggedRef(refs []model.SpanRef, traceID model.TraceID, spanID model.SpanID) *model.SpanRef {
+	for i := range refs {
+		if refs[i].TraceID == traceID && refs[i].SpanID == spanID && len(refs[i].Tags) == 0 {
+			return &refs[i]
+		}
+	}
+	return nil
+}
+
+// restoreLinks sets the tags of the references as the attributes of the matching links. The links
+// to the parent span are added back, as the upstream translator only keeps the parent span ID.
+func restoreLinks(refs []model.SpanRef, links ptrace.SpanLinkSlice) {
+	restored := make([]bool, links.Len())
+	for _, ref := range refs {
+		if len(ref.Tags) == 0 {
+			continue
+		}
+		var link ptrace.SpanLink
+		found := false
+		for l := 0; l < links.Len() && !found; l++ {
+			link = links.At(l)
+			found = !restored[l] &&
+				traceIDFromOTLP(link.TraceID()) == ref.TraceID &&
+				spanIDFromOTLP(link.SpanID()) == ref.SpanID
+			if found {
+				restored[l] = true
+			}
+		}
+		if !found {
+			link = links.AppendEmpty()
+			link.SetTraceID(traceIDToOTLP(ref.TraceID))
+			link.SetSpanID(spanIDToOTLP(ref.SpanID))
+			link.Attributes().PutStr(refTypeAttribute, refTypeAttributeValues[ref.RefType])
+		}
+		tagsToLink(ref.Tags, link)
+	}
+}
+
+func linkToTags(link ptrace.SpanLink) []model.KeyValue {
+	var tags []model.KeyValue
+	link.Attributes().Range(func(key string, value pcommon.Value) bool {
+		if key != refTypeAttribute {
+			tags = append(tags, attributeToTag(key, value))
+		}
+		return true
+	})
+	if traceState := link.TraceState().AsRaw(); traceState != "" {
+		tags = append(tags, model.String(traceStateTag, traceState))
+	}
+	return tags
+}
+
+func tagsToLink(tags []model.KeyValue, link ptrace.SpanLink) {
+	attrs := link.Attributes()
+	for _, tag := range tags {
+		switch {
+		case tag.Key == traceStateTag:
+			link.TraceState().FromRaw(tag.VStr)
+		case tag.VType == model.BoolType:
+			attrs.PutBool(tag.Key, tag.VBool)
+		case tag.VType == model.Int64Type:
+			attrs.PutInt(tag.Key, tag.VInt64)
+		case tag.VType == model.Float64Type:
+			attrs.PutDouble(tag.Key, tag.VFloat64)
+		case tag.VType == model.BinaryType:
+			attrs.PutEmptyBytes(tag.Key).FromRaw(tag.VBinary)
+		default:
+			attrs.PutStr(tag.Key, tag.VStr)
+		}
+	}
+}
+
+func attributeToTag(key string, value pcommon.Value) model.KeyValue {
+	switch value.Type() {
+	case pcommon.ValueTypeBool:
+		return model.Bool(key, value.Bool())
+	case pcommon.ValueTypeInt:
+		return model.Int64(key, value.Int())
+	case pcommon.ValueTypeDouble:
+		return model.Float64(key, value.Double())
+	case pcommon.ValueTypeBytes:
+		return model.Binary(key, value.Bytes().AsRaw())
+	default:
+		// the maps and slices are flattened to JSON strings, like by the upstream translator
+		return model.String(key, value.AsString())
+	}
+}
+
+func traceIDFromOTLP(traceID pcommon.TraceID) model.TraceID {
+	return model.NewTraceID(binary.BigEndian.Uint64(traceID[:8]), binary.BigEndian.Uint64(traceID[8:]))
+}
+
+func spanIDFromOTLP(spanID pcommon.SpanID) model.SpanID {
+	return model.NewSpanID(binary.BigEndian.Uint64(spanID[:]))
+}
+
+func traceIDToOTLP(traceID model.TraceID) pcommon.TraceID {
+	var id pcommon.TraceID
+	binary.BigEndian.PutUint64(id[:8], traceID.High)
+	binary.BigEndian.PutUint64(id[8:], traceID.Low)
+	return id
+}
+
+func spanIDToOTLP(spanID model.SpanID) pcommon.SpanID {
+	var id pcommon.SpanID
+	binary.BigEndian.PutUint64(id[:], uint64(spanID))
+	return id
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package otlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var (
+	testTraceID       = pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	testSpanID        = pcommon.SpanID([8]byte{1, 1, 1, 1, 1, 1, 1, 1})
+	testParentSpanID  = pcommon.SpanID([8]byte{2, 2, 2, 2, 2, 2, 2, 2})
+	testLinkedTraceID = pcommon.TraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	testLinkedSpanID  = pcommon.SpanID([8]byte{3, 3, 3, 3, 3, 3, 3, 3})
+)
+
+func newTestTraces() ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "frontend")
+	rs.Resource().Attributes().PutStr("host.name", "host-1")
+	rs.Resource().Attributes().PutInt("process.pid", 42)
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(testTraceID)
+	span.SetSpanID(testSpanID)
+	span.SetParentSpanID(testParentSpanID)
+	span.SetName("operation")
+
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(testLinkedTraceID)
+	link.SetSpanID(testLinkedSpanID)
+	link.TraceState().FromRaw("vendor=value")
+	link.Attributes().PutStr("str", "value")
+	link.Attributes().PutInt("int", 1)
+	link.Attributes().PutDouble("double", 1.5)
+	link.Attributes().PutBool("bool", true)
+	link.Attributes().PutEmptyBytes("bytes").FromRaw([]byte{1, 2})
+
+	parentLink := span.Links().AppendEmpty()
+	parentLink.SetTraceID(testTraceID)
+	parentLink.SetSpanID(testParentSpanID)
+	parentLink.Attributes().PutStr("link.kind", "parent")
+
+	span.Links().AppendEmpty().SetTraceID(testLinkedTraceID)
+	return td
+}
+
+func TestProtoFromTraces(t *testing.T) {
+	batches, err := ProtoFromTraces(newTestTraces())
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, "frontend", batches[0].Process.ServiceName)
+	assert.ElementsMatch(t, []model.KeyValue{
+		model.String("host.name", "host-1"),
+		model.Int64("process.pid", 42),
+	}, batches[0].Process.Tags)
+	require.Len(t, batches[0].Spans, 1)
+	refs := batches[0].Spans[0].References
+	require.Len(t, refs, 3)
+
+	assert.Equal(t, traceIDFromOTLP(testTraceID), refs[0].TraceID)
+	assert.Equal(t, spanIDFromOTLP(testParentSpanID), refs[0].SpanID)
+	assert.Equal(t, []model.KeyValue{model.String("link.kind", "parent")}, refs[0].Tags)
+
+	assert.Equal(t, traceIDFromOTLP(testLinkedTraceID), refs[1].TraceID)
+	assert.Equal(t, spanIDFromOTLP(testLinkedSpanID), refs[1].SpanID)
+	assert.ElementsMatch(t, []model.KeyValue{
+		model.String("str", "value"),
+		model.Int64("int", 1),
+		model.Float64("double", 1.5),
+		model.Bool("bool", true),
+		model.Binary("bytes", []byte{1, 2}),
+		model.String(traceStateTag, "vendor=value"),
+	}, refs[1].Tags)
+
+	assert.Empty(t, refs[2].Tags)
+}
+
+func TestProtoToTraces(t *testing.T) {
+	expected := newTestTraces()
+	batches, err := ProtoFromTraces(newTestTraces())
+	require.NoError(t, err)
+	td, err := ProtoToTraces(batches)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, td.SpanCount())
+	expectedLinks := expected.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Links()
+	links := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Links()
+	require.Equal(t, expectedLinks.Len(), links.Len())
+	linksByID := make(map[pcommon.SpanID]ptrace.SpanLink)
+	for i := 0; i < links.Len(); i++ {
+		linksByID[links.At(i).SpanID()] = links.At(i)
+	}
+	for i := 0; i < expectedLinks.Len(); i++ {
+		expectedLink := expectedLinks.At(i)
+		link, ok := linksByID[expectedLink.SpanID()]
+		require.True(t, ok)
+		assert.Equal(t, expectedLink.TraceID(), link.TraceID())
+		assert.Equal(t, expectedLink.TraceState().AsRaw(), link.TraceState().AsRaw())
+		attrs := link.Attributes().AsRaw()
+		// the type of the reference is added by the upstream translator
+		assert.Contains(t, attrs, refTypeAttribute)
+		delete(attrs, refTypeAttribute)
+		assert.Equal(t, expectedLink.Attributes().AsRaw(), attrs)
+	}
+}
+
+func TestProtoToTracesWithoutTags(t *testing.T) {
+	batches := []*model.Batch{
+		{
+			Process: &model.Process{ServiceName: "frontend"},
+			Spans: []*model.Span{
+				{
+					TraceID:    model.NewTraceID(1, 2),
+					SpanID:     model.NewSpanID(3),
+					References: []model.SpanRef{model.NewFollowsFromRef(model.NewTraceID(1, 2), model.NewSpanID(4))},
+				},
+			},
+		},
+	}
+	td, err := ProtoToTraces(batches)
+	require.NoError(t, err)
+	links := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Links()
+	require.Equal(t, 1, links.Len())
+	assert.Equal(t, map[string]any{refTypeAttribute: "follows_from"}, links.At(0).Attributes().AsRaw())
+}
+
+func TestResourceRoundTrip(t *testing.T) {
+	td := ptrace.NewTraces()
+	// the resources without attributes nor spans are skipped
+	td.ResourceSpans().AppendEmpty()
+	rs := td.ResourceSpans().AppendEmpty()
+	attrs := rs.Resource().Attributes()
+	attrs.PutStr("service.name", "frontend")
+	attrs.PutInt("process.pid", 42)
+	attrs.PutEmptySlice("process.command_args").FromRaw([]any{"frontend", "-v"})
+	labels := attrs.PutEmptyMap("k8s.labels")
+	labels.PutStr("app", "frontend")
+	labels.PutInt("replicas", 2)
+	labels.PutDouble("ratio", 1.0)
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(testTraceID)
+	span.SetSpanID(testSpanID)
+
+	batches, err := ProtoFromTraces(td)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	tags := model.KeyValues(batches[0].Process.Tags)
+	// the map and slice attributes are searchable as strings
+	tag, ok := tags.FindByKey("process.command_args")
+	require.True(t, ok)
+	assert.Equal(t, `["frontend","-v"]`, tag.VStr)
+	_, ok = tags.FindByKey(resourceTag)
+	require.True(t, ok)
+
+	actual, err := ProtoToTraces(batches)
+	require.NoError(t, err)
+	require.Equal(t, 1, actual.ResourceSpans().Len())
+	actualRS := actual.ResourceSpans().At(0)
+	assert.Equal(t, attrs.AsRaw(), actualRS.Resource().Attributes().AsRaw())
+	actualLabels, ok := actualRS.Resource().Attributes().Get("k8s.labels")
+	require.True(t, ok)
+	ratio, ok := actualLabels.Map().Get("ratio")
+	require.True(t, ok)
+	assert.Equal(t, pcommon.ValueTypeDouble, ratio.Type())
+}
+
+func TestResourceWithScalarAttributes(t *testing.T) {
+	batches, err := ProtoFromTraces(newTestTraces())
+	require.NoError(t, err)
+	_, ok := model.KeyValues(batches[0].Process.Tags).FindByKey(resourceTag)
+	assert.False(t, ok)
+}
+
+func TestProtoToTracesInvalidResourceTag(t *testing.T) {
+	batches := []*model.Batch{
+		{
+			Process: &model.Process{ServiceName: "frontend", Tags: []model.KeyValue{model.String(resourceTag, "{")}},
+			Spans:   []*model.Span{{TraceID: model.NewTraceID(1, 2), SpanID: model.NewSpanID(3)}},
+		},
+	}
+	_, err := ProtoToTraces(batches)
+	require.ErrorContains(t, err, "failed to decode the resource")
+}
+
+func TestTagsToLink(t *testing.T) {
+	link := ptrace.NewSpanLink()
+	tagsToLink([]model.KeyValue{
+		model.String("str", "value"),
+		model.Int64("int", 1),
+		model.Float64("double", 1.5),
+		model.Bool("bool", true),
+		model.Binary("bytes", []byte{1, 2}),
+		model.String(traceStateTag, "vendor=value"),
+	}, link)
+	assert.Equal(t, "vendor=value", link.TraceState().AsRaw())
+	assert.Equal(t, map[string]any{
+		"str":    "value",
+		"int":    int64(1),
+		"double": 1.5,
+		"bool":   true,
+		"bytes":  []byte{1, 2},
+	}, link.Attributes().AsRaw())
+}
+
+func TestAttributeToTagFlattensMaps(t *testing.T) {
+	value := pcommon.NewValueMap()
+	value.Map().PutStr("key", "value")
+	assert.Equal(t, model.String("map", `{"key":"value"}`), attributeToTag("map", value))
+}
+
+func TestIDConversions(t *testing.T) {
+	assert.Equal(t, testTraceID, traceIDToOTLP(traceIDFromOTLP(testTraceID)))
+	assert.Equal(t, testSpanID, spanIDToOTLP(spanIDFromOTLP(testSpanID)))
+	assert.Equal(t, model.NewTraceID(0x0102030405060708, 0x090a0b0c0d0e0f10), traceIDFromOTLP(testTraceID))
+}
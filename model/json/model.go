@@ -82,6 +82,7 @@ type Reference struct {
 	RefType ReferenceType `json:"refType"`
 	TraceID TraceID       `json:"traceID"`
 	SpanID  SpanID        `json:"spanID"`
+	Tags    []KeyValue    `json:"tags,omitempty"`
 }
 
 // Process is the process emitting a set of spans
@@ -105,9 +106,25 @@ type KeyValue struct {
 
 // DependencyLink shows dependencies between services
 type DependencyLink struct {
-	Parent    string `json:"parent"`
-	Child     string `json:"child"`
-	CallCount uint64 `json:"callCount"`
+	Parent    string               `json:"parent"`
+	Child     string               `json:"child"`
+	CallCount uint64               `json:"callCount"`
+	Stats     *DependencyLinkStats `json:"stats,omitempty"`
+}
+
+// DependencyLinkStats are the error and latency statistics of the calls of a dependency link
+type DependencyLinkStats struct {
+	ErrorCount  uint64  `json:"errorCount"`
+	ErrorRate   float64 `json:"errorRate"`
+	AvgDuration uint64  `json:"avgDuration"` // microseconds
+	MaxDuration uint64  `json:"maxDuration"` // microseconds
+}
+
+// DependencyGraph shows dependencies between services within a time range
+type DependencyGraph struct {
+	StartTime uint64           `json:"startTime"` // microseconds since Unix epoch
+	EndTime   uint64           `json:"endTime"`   // microseconds since Unix epoch
+	Links     []DependencyLink `json:"links"`
 }
 
 // Operation defines the data in the operation response when query operation by service and span kind
@@ -115,3 +132,108 @@ type Operation struct {
 	Name     string `json:"name"`
 	SpanKind string `json:"spanKind"`
 }
+
+// LatencyDistribution is the response of the latency distribution query of a service or operation
+type LatencyDistribution struct {
+	Count   int64            `json:"count"`
+	Buckets []DurationBucket `json:"buckets"`
+	P50     uint64           `json:"p50"` // microseconds
+	P95     uint64           `json:"p95"` // microseconds
+	P99     uint64           `json:"p99"` // microseconds
+}
+
+// DurationBucket is a histogram bucket counting spans with from <= duration < to
+type DurationBucket struct {
+	From  uint64 `json:"from"`         // microseconds
+	To    uint64 `json:"to,omitempty"` // microseconds, omitted for the last unbounded bucket
+	Count int64  `json:"count"`
+}
+
+// TraceComparison is the response of the trace comparison query
+type TraceComparison struct {
+	TraceIDA   TraceID         `json:"traceA"`
+	TraceIDB   TraceID         `json:"traceB"`
+	Added      []SpanPathDiff  `json:"added"`
+	Removed    []SpanPathDiff  `json:"removed"`
+	Changed    []SpanPathDiff  `json:"changed"`
+	Operations []OperationDiff `json:"operations"`
+}
+
+// OperationRef identifies an operation of a service
+type OperationRef struct {
+	ServiceName   string `json:"serviceName"`
+	OperationName string `json:"operationName"`
+}
+
+// SpanPathDiff compares the spans found at the same call path in two traces
+type SpanPathDiff struct {
+	Path          []OperationRef `json:"path"`
+	CountA        int            `json:"countA"`
+	CountB        int            `json:"countB"`
+	DurationA     uint64         `json:"durationA"`     // microseconds
+	DurationB     uint64         `json:"durationB"`     // microseconds
+	DurationDelta int64          `json:"durationDelta"` // microseconds
+}
+
+// OperationDiff compares the spans of an operation in two traces
+type OperationDiff struct {
+	OperationRef
+	CountA        int    `json:"countA"`
+	CountB        int    `json:"countB"`
+	DurationA     uint64 `json:"durationA"`     // microseconds
+	DurationB     uint64 `json:"durationB"`     // microseconds
+	DurationDelta int64  `json:"durationDelta"` // microseconds
+}
+
+// FlameGraphNode is a node of the call-tree merged from the spans of many traces
+type FlameGraphNode struct {
+	ServiceName   string            `json:"serviceName,omitempty"`
+	OperationName string            `json:"operationName,omitempty"`
+	Count         int               `json:"count"`
+	TotalTime     uint64            `json:"totalTime"` // microseconds
+	SelfTime      uint64            `json:"selfTime"`  // microseconds
+	Children      []*FlameGraphNode `json:"children,omitempty"`
+}
+
+// CriticalPath is the response of the critical path query of a trace
+type CriticalPath struct {
+	TraceID    TraceID               `json:"traceID"`
+	Segments   []CriticalPathSegment `json:"segments"`
+	Operations []OperationTime       `json:"operations"`
+}
+
+// CriticalPathSegment is a time interval during which a span was on the critical path
+type CriticalPathSegment struct {
+	SpanID SpanID `json:"spanID"`
+	OperationRef
+	StartTime uint64 `json:"startTime"` // microseconds since Unix epoch
+	Duration  uint64 `json:"duration"`  // microseconds
+}
+
+// OperationTime is the time an operation spent on the critical path
+type OperationTime struct {
+	OperationRef
+	Duration uint64 `json:"duration"` // microseconds
+}
+
+// TraceQualityReport is the response of the trace quality queries
+type TraceQualityReport struct {
+	Traces   int              `json:"traces"`
+	Services []ServiceQuality `json:"services"`
+}
+
+// ServiceQuality is the instrumentation quality report of the spans of a service
+type ServiceQuality struct {
+	ServiceName string               `json:"serviceName"`
+	Traces      int                  `json:"traces"`
+	Score       float64              `json:"score"` // ratio of the passed checks, between 0 and 1
+	Checks      []QualityCheckResult `json:"checks"`
+}
+
+// QualityCheckResult counts the spans a quality check applied to and the ones failing it
+type QualityCheckResult struct {
+	Check    string    `json:"check"`
+	Checked  int       `json:"checked"`
+	Failed   int       `json:"failed"`
+	TraceIDs []TraceID `json:"traceIDs,omitempty"` // examples of traces failing the check
+}
@@ -239,12 +239,14 @@ func (m *Log) GetFields() []KeyValue {
 }
 
 type SpanRef struct {
-	TraceID              TraceID     `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=TraceID" json:"trace_id"`
-	SpanID               SpanID      `protobuf:"bytes,2,opt,name=span_id,json=spanId,proto3,customtype=SpanID" json:"span_id"`
-	RefType              SpanRefType `protobuf:"varint,3,opt,name=ref_type,json=refType,proto3,enum=jaeger.api_v2.SpanRefType" json:"ref_type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	TraceID TraceID     `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=TraceID" json:"trace_id"`
+	SpanID  SpanID      `protobuf:"bytes,2,opt,name=span_id,json=spanId,proto3,customtype=SpanID" json:"span_id"`
+	RefType SpanRefType `protobuf:"varint,3,opt,name=ref_type,json=refType,proto3,enum=jaeger.api_v2.SpanRefType" json:"ref_type,omitempty"`
+	// The attributes of the OTLP span link the reference was converted from.
+	Tags                 []KeyValue `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *SpanRef) Reset()         { *m = SpanRef{} }
@@ -287,6 +289,13 @@ func (m *SpanRef) GetRefType() SpanRefType {
 	return SpanRefType_CHILD_OF
 }
 
+func (m *SpanRef) GetTags() []KeyValue {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
 type Process struct {
 	ServiceName          string     `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
 	Tags                 []KeyValue `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags"`
@@ -727,66 +736,66 @@ func init() { proto.RegisterFile("model.proto", fileDescriptor_4c16552f9fdb66d8)
 
 var fileDescriptor_4c16552f9fdb66d8 = []byte{
 	// 957 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x55, 0xc1, 0x6f, 0xe3, 0xc4,
-	0x17, 0xee, 0x24, 0x76, 0x6c, 0xbf, 0xa4, 0x55, 0x34, 0xbb, 0xbf, 0xad, 0x37, 0x3f, 0xd1, 0x84,
-	0xac, 0x90, 0xc2, 0xaa, 0xa4, 0x6c, 0xd9, 0xed, 0x01, 0x21, 0xa1, 0x75, 0x4b, 0xc0, 0x90, 0x36,
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0x41, 0x6f, 0xe3, 0xc4,
+	0x17, 0xef, 0x24, 0x76, 0x6c, 0xbf, 0xa4, 0x55, 0x34, 0xbb, 0xff, 0xad, 0x37, 0x7f, 0xd1, 0x84,
+	0xac, 0x90, 0xc2, 0xaa, 0xa4, 0x6c, 0xd9, 0xed, 0x01, 0x21, 0xa1, 0x75, 0x4b, 0x20, 0x90, 0x36,
 	0x68, 0x5a, 0x81, 0xe0, 0x62, 0x4d, 0x9d, 0x89, 0xd7, 0xbb, 0x8e, 0xc7, 0xb2, 0x1d, 0xa3, 0xdc,
-	0xf8, 0x13, 0x10, 0x27, 0x8e, 0x70, 0xe5, 0x2f, 0xd9, 0x23, 0x07, 0x4e, 0x48, 0x14, 0xd4, 0xd3,
-	0xfe, 0x19, 0x68, 0xc6, 0xe3, 0x74, 0x1b, 0x56, 0xd0, 0xbd, 0x70, 0xca, 0xbc, 0x99, 0xef, 0x7b,
-	0xf3, 0xde, 0xf7, 0xbe, 0x89, 0xa1, 0x39, 0xe7, 0x53, 0x16, 0x0d, 0x93, 0x94, 0xe7, 0x1c, 0x6f,
-	0x3e, 0xa5, 0x2c, 0x60, 0xe9, 0x90, 0x26, 0xa1, 0x57, 0xec, 0x77, 0x6e, 0x07, 0x3c, 0xe0, 0xf2,
-	0x64, 0x4f, 0xac, 0x4a, 0x50, 0xa7, 0x1b, 0x70, 0x1e, 0x44, 0x6c, 0x4f, 0x46, 0xe7, 0x8b, 0xd9,
-	0x5e, 0x1e, 0xce, 0x59, 0x96, 0xd3, 0x79, 0xa2, 0x00, 0x3b, 0xeb, 0x80, 0xe9, 0x22, 0xa5, 0x79,
-	0xc8, 0xe3, 0xf2, 0xbc, 0xff, 0x2b, 0x02, 0xf3, 0x33, 0xb6, 0xfc, 0x82, 0x46, 0x0b, 0x86, 0xdb,
-	0x50, 0x7f, 0xc6, 0x96, 0x36, 0xea, 0xa1, 0x81, 0x45, 0xc4, 0x12, 0xef, 0x41, 0xa3, 0xf0, 0xf2,
-	0x65, 0xc2, 0xec, 0x5a, 0x0f, 0x0d, 0xb6, 0xf6, 0xed, 0xe1, 0xb5, 0xaa, 0x86, 0x92, 0x77, 0xb6,
-	0x4c, 0x18, 0xd1, 0x0b, 0xf1, 0x83, 0x6f, 0x81, 0x5e, 0x78, 0x59, 0x9e, 0xda, 0x75, 0x99, 0x44,
-	0x2b, 0x4e, 0xf3, 0x14, 0xff, 0x4f, 0x64, 0x39, 0xe7, 0x3c, 0xb2, 0xb5, 0x1e, 0x1a, 0x98, 0x44,
-	0x2f, 0x1c, 0xce, 0x23, 0xbc, 0x0d, 0x46, 0xe1, 0x85, 0x71, 0x7e, 0xf0, 0xd0, 0xd6, 0x7b, 0x68,
-	0x50, 0x27, 0x8d, 0xc2, 0x15, 0x11, 0xfe, 0x3f, 0x58, 0x85, 0x37, 0x8b, 0x38, 0x15, 0x47, 0x8d,
-	0x1e, 0x1a, 0x20, 0x62, 0x16, 0xa3, 0x32, 0xc6, 0x77, 0xc1, 0x2c, 0xbc, 0xf3, 0x30, 0xa6, 0xe9,
-	0xd2, 0x36, 0x7a, 0x68, 0xd0, 0x22, 0x46, 0xe1, 0xc8, 0xf0, 0x7d, 0xf3, 0xc5, 0x8f, 0x5d, 0xf4,
-	0xe2, 0xa7, 0x2e, 0xea, 0x7f, 0x8b, 0xa0, 0x3e, 0xe6, 0x01, 0x76, 0xc0, 0x5a, 0x29, 0x22, 0xfb,
-	0x6a, 0xee, 0x77, 0x86, 0xa5, 0x24, 0xc3, 0x4a, 0x92, 0xe1, 0x59, 0x85, 0x70, 0xcc, 0xe7, 0x17,
-	0xdd, 0x8d, 0xef, 0xfe, 0xe8, 0x22, 0x72, 0x45, 0xc3, 0x8f, 0xa0, 0x31, 0x0b, 0x59, 0x34, 0xcd,
-	0xec, 0x5a, 0xaf, 0x3e, 0x68, 0xee, 0x6f, 0xaf, 0x69, 0x50, 0xc9, 0xe7, 0x68, 0x82, 0x4d, 0x14,
-	0xb8, 0xff, 0x33, 0x02, 0xe3, 0x34, 0xa1, 0x31, 0x61, 0x33, 0xfc, 0x08, 0xcc, 0x3c, 0xa5, 0x3e,
-	0xf3, 0xc2, 0xa9, 0xac, 0xa2, 0xe5, 0x74, 0x04, 0xf6, 0xb7, 0x8b, 0xae, 0x71, 0x26, 0xf6, 0xdd,
-	0xa3, 0xcb, 0xab, 0x25, 0x31, 0x24, 0xd6, 0x9d, 0xe2, 0x07, 0x60, 0x64, 0x09, 0x8d, 0x05, 0xab,
-	0x26, 0x59, 0xb6, 0x62, 0x35, 0x44, 0x62, 0x49, 0x52, 0x2b, 0xd2, 0x10, 0x40, 0x77, 0x2a, 0x6e,
-	0x4a, 0xd9, 0xac, 0x1c, 0x59, 0x5d, 0x8e, 0xac, 0xb3, 0x56, 0xae, 0xaa, 0x49, 0x0e, 0xcd, 0x48,
-	0xcb, 0x45, 0xdf, 0x03, 0xe3, 0xf3, 0x94, 0xfb, 0x2c, 0xcb, 0xf0, 0x9b, 0xd0, 0xca, 0x58, 0x5a,
-	0x84, 0x3e, 0xf3, 0x62, 0x3a, 0x67, 0xca, 0x0d, 0x4d, 0xb5, 0x77, 0x42, 0xe7, 0x0c, 0x3f, 0x00,
-	0x2d, 0xa7, 0xc1, 0x0d, 0xf5, 0x90, 0xd0, 0xfe, 0xef, 0x1a, 0x68, 0xe2, 0xe6, 0xff, 0x50, 0x8a,
-	0xb7, 0x60, 0x8b, 0x27, 0xac, 0x74, 0x7b, 0xd9, 0x4a, 0xe9, 0xc9, 0xcd, 0xd5, 0xae, 0x6c, 0xe6,
-	0x03, 0x80, 0x94, 0xcd, 0x58, 0xca, 0x62, 0x9f, 0x65, 0xb6, 0x26, 0x5b, 0xba, 0xf3, 0x6a, 0xcd,
-	0x54, 0x47, 0x2f, 0xe1, 0xf1, 0x3d, 0xd0, 0x67, 0x91, 0xd0, 0x42, 0x38, 0x78, 0xd3, 0xd9, 0x54,
-	0x55, 0xe9, 0x23, 0xb1, 0x49, 0xca, 0x33, 0x7c, 0x08, 0x90, 0xe5, 0x34, 0xcd, 0x3d, 0x61, 0x2a,
-	0x69, 0xe8, 0x1b, 0xdb, 0x50, 0xf2, 0xc4, 0x09, 0xfe, 0x10, 0xcc, 0xea, 0xed, 0x4a, 0xdf, 0x37,
-	0xf7, 0xef, 0xfe, 0x2d, 0xc5, 0x91, 0x02, 0x94, 0x19, 0x7e, 0x10, 0x19, 0x56, 0xa4, 0xd5, 0xd4,
-	0xcc, 0x1b, 0x4f, 0x0d, 0xef, 0x82, 0x16, 0xf1, 0x20, 0xb3, 0x2d, 0x49, 0xc1, 0x6b, 0x94, 0x31,
-	0x0f, 0x2a, 0xb4, 0x40, 0xe1, 0x77, 0xc1, 0x48, 0x4a, 0x13, 0xd9, 0x20, 0x0b, 0x5c, 0x97, 0x51,
-	0x59, 0x8c, 0x54, 0x30, 0xbc, 0x0b, 0xa0, 0x96, 0x62, 0xb0, 0x4d, 0x31, 0x1e, 0x67, 0xf3, 0xf2,
-	0xa2, 0x6b, 0x29, 0xa4, 0x7b, 0x44, 0x2c, 0x05, 0x70, 0xa7, 0xb8, 0x03, 0xe6, 0x37, 0x34, 0x8d,
-	0xc3, 0x38, 0xc8, 0xec, 0x56, 0xaf, 0x3e, 0xb0, 0xc8, 0x2a, 0xee, 0x7f, 0x5f, 0x03, 0x5d, 0x9a,
-	0x06, 0xbf, 0x0d, 0xba, 0x30, 0x40, 0x66, 0x23, 0x59, 0xf4, 0xad, 0x57, 0x8d, 0xb2, 0x44, 0xe0,
-	0x4f, 0xa1, 0x59, 0x5d, 0x3f, 0xa7, 0x89, 0xb2, 0xf3, 0xbd, 0x35, 0x82, 0xcc, 0x5a, 0x95, 0x7e,
-	0x4c, 0x93, 0x24, 0x8c, 0xab, 0xb6, 0xab, 0xe2, 0x8f, 0x69, 0x72, 0xad, 0xb8, 0xfa, 0xf5, 0xe2,
-	0x3a, 0x05, 0x6c, 0x5d, 0xe7, 0xaf, 0x35, 0x8e, 0xfe, 0xa5, 0xf1, 0x83, 0x2b, 0x61, 0x6b, 0xff,
-	0x24, 0xac, 0x2a, 0xab, 0x02, 0xf7, 0x9f, 0x82, 0xee, 0xd0, 0xdc, 0x7f, 0xf2, 0x3a, 0x9a, 0xbc,
-	0xd6, 0x5d, 0xe8, 0xea, 0xae, 0x05, 0x6c, 0x1d, 0xb1, 0x84, 0xc5, 0x53, 0x16, 0xfb, 0xcb, 0x71,
-	0x18, 0x3f, 0xc3, 0x77, 0xa0, 0x91, 0xd0, 0x94, 0xc5, 0xb9, 0xfa, 0x0b, 0x51, 0x11, 0xbe, 0x0d,
-	0xba, 0xff, 0x24, 0x8c, 0xca, 0x87, 0x6c, 0x91, 0x32, 0xc0, 0x6f, 0x00, 0xf8, 0x34, 0x8a, 0x3c,
-	0x9f, 0x2f, 0xe2, 0x5c, 0xbe, 0x54, 0x8d, 0x58, 0x62, 0xe7, 0x50, 0x6c, 0x88, 0x64, 0x19, 0x5f,
-	0xa4, 0x3e, 0x93, 0x9f, 0x10, 0x8b, 0xa8, 0xe8, 0xfe, 0x47, 0x60, 0xad, 0xbe, 0x41, 0x18, 0xa0,
-	0x71, 0x7a, 0x46, 0xdc, 0x93, 0x8f, 0xdb, 0x1b, 0xd8, 0x04, 0xcd, 0x99, 0x4c, 0xc6, 0x6d, 0x84,
-	0x2d, 0xd0, 0xdd, 0x93, 0xb3, 0x83, 0x87, 0xed, 0x1a, 0x6e, 0x82, 0x31, 0x1a, 0x4f, 0x1e, 0x8b,
-	0xa0, 0x2e, 0xd0, 0x8e, 0x7b, 0xf2, 0x98, 0x7c, 0xd5, 0xd6, 0xee, 0xbf, 0x03, 0xcd, 0x97, 0xfe,
-	0x17, 0x71, 0x0b, 0xcc, 0xc3, 0x4f, 0xdc, 0xf1, 0x91, 0x37, 0x19, 0xb5, 0x37, 0x70, 0x1b, 0x5a,
-	0xa3, 0xc9, 0x78, 0x3c, 0xf9, 0xf2, 0xd4, 0x1b, 0x91, 0xc9, 0x71, 0x1b, 0x39, 0xbb, 0xcf, 0x2f,
-	0x77, 0xd0, 0x2f, 0x97, 0x3b, 0xe8, 0xcf, 0xcb, 0x1d, 0x04, 0xdb, 0x21, 0x57, 0x1a, 0x89, 0x7f,
-	0xab, 0x30, 0x0e, 0x94, 0x54, 0x5f, 0xeb, 0xf2, 0x7b, 0x7e, 0xde, 0x90, 0xef, 0xf3, 0xbd, 0xbf,
-	0x02, 0x00, 0x00, 0xff, 0xff, 0xaa, 0x45, 0x11, 0xa2, 0xdf, 0x07, 0x00, 0x00,
+	0xf8, 0x08, 0x88, 0x13, 0x47, 0xf8, 0x36, 0x7b, 0xe4, 0xc0, 0x09, 0x69, 0x0b, 0xea, 0x69, 0x3f,
+	0x06, 0x9a, 0xf1, 0x38, 0xd9, 0x86, 0x0a, 0xda, 0x0b, 0xa7, 0xcc, 0x9b, 0xf9, 0xfd, 0xde, 0xbc,
+	0xf7, 0x7b, 0xef, 0x8d, 0x03, 0xf5, 0x19, 0x9f, 0xb0, 0xb0, 0x1f, 0x27, 0x3c, 0xe3, 0x78, 0xf3,
+	0x39, 0x65, 0x3e, 0x4b, 0xfa, 0x34, 0x0e, 0xdc, 0x7c, 0xbf, 0x75, 0xd7, 0xe7, 0x3e, 0x97, 0x27,
+	0x7b, 0x62, 0x55, 0x80, 0x5a, 0x6d, 0x9f, 0x73, 0x3f, 0x64, 0x7b, 0xd2, 0x3a, 0x9f, 0x4f, 0xf7,
+	0xb2, 0x60, 0xc6, 0xd2, 0x8c, 0xce, 0x62, 0x05, 0xd8, 0x59, 0x07, 0x4c, 0xe6, 0x09, 0xcd, 0x02,
+	0x1e, 0x15, 0xe7, 0xdd, 0xdf, 0x10, 0x98, 0x5f, 0xb0, 0xc5, 0x57, 0x34, 0x9c, 0x33, 0xdc, 0x84,
+	0xea, 0x0b, 0xb6, 0xb0, 0x51, 0x07, 0xf5, 0x2c, 0x22, 0x96, 0x78, 0x0f, 0x6a, 0xb9, 0x9b, 0x2d,
+	0x62, 0x66, 0x57, 0x3a, 0xa8, 0xb7, 0xb5, 0x6f, 0xf7, 0xaf, 0x44, 0xd5, 0x97, 0xbc, 0xb3, 0x45,
+	0xcc, 0x88, 0x9e, 0x8b, 0x1f, 0x7c, 0x07, 0xf4, 0xdc, 0x4d, 0xb3, 0xc4, 0xae, 0x4a, 0x27, 0x5a,
+	0x7e, 0x9a, 0x25, 0xf8, 0x7f, 0xc2, 0xcb, 0x39, 0xe7, 0xa1, 0xad, 0x75, 0x50, 0xcf, 0x24, 0x7a,
+	0xee, 0x70, 0x1e, 0xe2, 0x6d, 0x30, 0x72, 0x37, 0x88, 0xb2, 0x83, 0xc7, 0xb6, 0xde, 0x41, 0xbd,
+	0x2a, 0xa9, 0xe5, 0x43, 0x61, 0xe1, 0xff, 0x83, 0x95, 0xbb, 0xd3, 0x90, 0x53, 0x71, 0x54, 0xeb,
+	0xa0, 0x1e, 0x22, 0x66, 0x3e, 0x28, 0x6c, 0x7c, 0x1f, 0xcc, 0xdc, 0x3d, 0x0f, 0x22, 0x9a, 0x2c,
+	0x6c, 0xa3, 0x83, 0x7a, 0x0d, 0x62, 0xe4, 0x8e, 0x34, 0x3f, 0x34, 0x5f, 0xff, 0xdc, 0x46, 0xaf,
+	0x7f, 0x69, 0xa3, 0xee, 0xf7, 0x08, 0xaa, 0x23, 0xee, 0x63, 0x07, 0xac, 0xa5, 0x22, 0x32, 0xaf,
+	0xfa, 0x7e, 0xab, 0x5f, 0x48, 0xd2, 0x2f, 0x25, 0xe9, 0x9f, 0x95, 0x08, 0xc7, 0x7c, 0x79, 0xd1,
+	0xde, 0xf8, 0xe1, 0x8f, 0x36, 0x22, 0x2b, 0x1a, 0x7e, 0x02, 0xb5, 0x69, 0xc0, 0xc2, 0x49, 0x6a,
+	0x57, 0x3a, 0xd5, 0x5e, 0x7d, 0x7f, 0x7b, 0x4d, 0x83, 0x52, 0x3e, 0x47, 0x13, 0x6c, 0xa2, 0xc0,
+	0xdd, 0x57, 0x08, 0x8c, 0xd3, 0x98, 0x46, 0x84, 0x4d, 0xf1, 0x13, 0x30, 0xb3, 0x84, 0x7a, 0xcc,
+	0x0d, 0x26, 0x32, 0x8a, 0x86, 0xd3, 0x12, 0xd8, 0xdf, 0x2f, 0xda, 0xc6, 0x99, 0xd8, 0x1f, 0x1e,
+	0x5d, 0xae, 0x96, 0xc4, 0x90, 0xd8, 0xe1, 0x04, 0x3f, 0x02, 0x23, 0x8d, 0x69, 0x24, 0x58, 0x15,
+	0xc9, 0xb2, 0x15, 0xab, 0x26, 0x1c, 0x4b, 0x92, 0x5a, 0x91, 0x9a, 0x00, 0x0e, 0x27, 0xe2, 0xa6,
+	0x84, 0x4d, 0x8b, 0x92, 0x55, 0x65, 0xc9, 0x5a, 0x6b, 0xe1, 0xaa, 0x98, 0x64, 0xd1, 0x8c, 0xa4,
+	0x58, 0xe0, 0x47, 0xa0, 0x65, 0xd4, 0x4f, 0x6d, 0xed, 0x26, 0x19, 0x4a, 0x68, 0xd7, 0x05, 0xe3,
+	0xcb, 0x84, 0x7b, 0x2c, 0x4d, 0xf1, 0xdb, 0xd0, 0x48, 0x59, 0x92, 0x07, 0x1e, 0x73, 0x23, 0x3a,
+	0x63, 0xaa, 0x81, 0xea, 0x6a, 0xef, 0x84, 0xce, 0x56, 0x17, 0x54, 0x6e, 0x7e, 0xc1, 0x2b, 0x0d,
+	0x34, 0x11, 0xec, 0x7f, 0xa8, 0xde, 0x3b, 0xb0, 0xc5, 0x63, 0x56, 0x0c, 0x48, 0x91, 0x4a, 0xd1,
+	0xc6, 0x9b, 0xcb, 0x5d, 0x99, 0xcc, 0x47, 0x00, 0x09, 0x9b, 0xb2, 0x84, 0x45, 0x1e, 0x2b, 0x35,
+	0xbb, 0x77, 0xbd, 0xcc, 0x2a, 0xa3, 0x37, 0xf0, 0xf8, 0x01, 0xe8, 0xd3, 0x50, 0x68, 0x21, 0x9a,
+	0x7e, 0xd3, 0xd9, 0x54, 0x51, 0xe9, 0x03, 0xb1, 0x49, 0x8a, 0x33, 0x7c, 0x08, 0x90, 0x66, 0x34,
+	0xc9, 0x5c, 0xd1, 0x87, 0x76, 0xed, 0x36, 0x9d, 0x2b, 0x79, 0xe2, 0x04, 0x7f, 0x0c, 0x66, 0x39,
+	0xee, 0x72, 0x54, 0xea, 0xfb, 0xf7, 0xff, 0xe6, 0xe2, 0x48, 0x01, 0x0a, 0x0f, 0x3f, 0x09, 0x0f,
+	0x4b, 0xd2, 0xb2, 0x6a, 0xe6, 0x8d, 0xab, 0x86, 0x77, 0x41, 0x0b, 0xb9, 0x9f, 0xda, 0x96, 0xa4,
+	0xe0, 0x35, 0xca, 0x88, 0xfb, 0x25, 0x5a, 0xa0, 0xf0, 0xfb, 0x60, 0xc4, 0x45, 0x13, 0xd9, 0xd0,
+	0x41, 0xd7, 0xc8, 0xa8, 0x5a, 0x8c, 0x94, 0x30, 0xbc, 0x0b, 0xa0, 0x96, 0xa2, 0xb0, 0x75, 0x51,
+	0x1e, 0x67, 0xf3, 0xf2, 0xa2, 0x6d, 0x29, 0xe4, 0xf0, 0x88, 0x58, 0x0a, 0x30, 0x9c, 0xe0, 0x16,
+	0x98, 0xdf, 0xd1, 0x24, 0x0a, 0x22, 0x3f, 0xb5, 0x1b, 0x9d, 0x6a, 0xcf, 0x22, 0x4b, 0xbb, 0xfb,
+	0x63, 0x05, 0x74, 0xd9, 0x34, 0xf8, 0x5d, 0xd0, 0x45, 0x03, 0xa4, 0x36, 0x92, 0x41, 0xdf, 0xb9,
+	0xae, 0x94, 0x05, 0x02, 0x7f, 0x0e, 0xf5, 0xf2, 0xfa, 0x19, 0x8d, 0x55, 0x3b, 0x3f, 0x58, 0x23,
+	0x48, 0xaf, 0x65, 0xe8, 0xc7, 0x34, 0x8e, 0x83, 0xa8, 0x4c, 0xbb, 0x0c, 0xfe, 0x98, 0xc6, 0x57,
+	0x82, 0xab, 0x5e, 0x0d, 0xae, 0x95, 0xc3, 0xd6, 0x55, 0xfe, 0x5a, 0xe2, 0xe8, 0x5f, 0x12, 0x3f,
+	0x58, 0x09, 0x5b, 0xf9, 0x27, 0x61, 0x55, 0x58, 0x25, 0xb8, 0xfb, 0x1c, 0x74, 0x87, 0x66, 0xde,
+	0xb3, 0xdb, 0x68, 0x72, 0xab, 0xbb, 0xd0, 0xea, 0xae, 0x39, 0x6c, 0x1d, 0xb1, 0x98, 0x45, 0x13,
+	0x16, 0x79, 0x8b, 0x51, 0x10, 0xbd, 0xc0, 0xf7, 0xa0, 0x16, 0xd3, 0x84, 0x45, 0x99, 0x7a, 0x42,
+	0x94, 0x85, 0xef, 0x82, 0xee, 0x3d, 0x0b, 0xc2, 0x62, 0x90, 0x2d, 0x52, 0x18, 0xf8, 0x2d, 0x00,
+	0x8f, 0x86, 0xa1, 0xeb, 0xf1, 0x79, 0x94, 0xc9, 0x49, 0xd5, 0x88, 0x25, 0x76, 0x0e, 0xc5, 0x86,
+	0x70, 0x96, 0xf2, 0x79, 0xe2, 0x31, 0xf9, 0xd5, 0xb1, 0x88, 0xb2, 0x1e, 0x7e, 0x02, 0xd6, 0xf2,
+	0xb3, 0x85, 0x01, 0x6a, 0xa7, 0x67, 0x64, 0x78, 0xf2, 0x69, 0x73, 0x03, 0x9b, 0xa0, 0x39, 0xe3,
+	0xf1, 0xa8, 0x89, 0xb0, 0x05, 0xfa, 0xf0, 0xe4, 0xec, 0xe0, 0x71, 0xb3, 0x82, 0xeb, 0x60, 0x0c,
+	0x46, 0xe3, 0xa7, 0xc2, 0xa8, 0x0a, 0xb4, 0x33, 0x3c, 0x79, 0x4a, 0xbe, 0x69, 0x6a, 0x0f, 0xdf,
+	0x83, 0xfa, 0x1b, 0x4f, 0x29, 0x6e, 0x80, 0x79, 0xf8, 0xd9, 0x70, 0x74, 0xe4, 0x8e, 0x07, 0xcd,
+	0x0d, 0xdc, 0x84, 0xc6, 0x60, 0x3c, 0x1a, 0x8d, 0xbf, 0x3e, 0x75, 0x07, 0x64, 0x7c, 0xdc, 0x44,
+	0xce, 0xee, 0xcb, 0xcb, 0x1d, 0xf4, 0xeb, 0xe5, 0x0e, 0xfa, 0xf3, 0x72, 0x07, 0xc1, 0x76, 0xc0,
+	0x95, 0x46, 0xe2, 0xb5, 0x0a, 0x22, 0x5f, 0x49, 0xf5, 0xad, 0x2e, 0xff, 0x02, 0x9c, 0xd7, 0xe4,
+	0x7c, 0x7e, 0xf0, 0xd7, 0x00, 0xff, 0xa5, 0xca, 0x5f, 0x12, 0x08, 0x00, 0x00,
 }
 
 func (this *KeyValue) Compare(that interface{}) int {
@@ -1050,6 +1059,20 @@ func (m *SpanRef) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Tags) > 0 {
+		for iNdEx := len(m.Tags) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Tags[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintModel(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if m.RefType != 0 {
 		i = encodeVarintModel(dAtA, i, uint64(m.RefType))
 		i--
@@ -1565,6 +1588,12 @@ func (m *SpanRef) Size() (n int) {
 	if m.RefType != 0 {
 		n += 1 + sovModel(uint64(m.RefType))
 	}
+	if len(m.Tags) > 0 {
+		for _, e := range m.Tags {
+			l = e.Size()
+			n += 1 + l + sovModel(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2205,6 +2234,40 @@ func (m *SpanRef) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowModel
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthModel
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthModel
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tags = append(m.Tags, KeyValue{})
+			if err := m.Tags[len(m.Tags)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipModel(dAtA[iNdEx:])
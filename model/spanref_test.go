@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -48,6 +49,26 @@ func TestSpanRefTypeToFromJSON(t *testing.T) {
 	assert.Contains(t, err.Error(), "unknown value")
 }
 
+func TestSpanRefTagsMarshal(t *testing.T) {
+	sr := model.SpanRef{
+		TraceID: model.NewTraceID(0, 0x42),
+		SpanID:  model.NewSpanID(0x43),
+		RefType: model.FollowsFrom,
+		Tags:    []model.KeyValue{model.String("link.kind", "batch"), model.Int64("link.index", 3)},
+	}
+	data, err := proto.Marshal(&sr)
+	require.NoError(t, err)
+	var sr2 model.SpanRef
+	require.NoError(t, proto.Unmarshal(data, &sr2))
+	assert.Equal(t, sr, sr2)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, new(jsonpb.Marshaler).Marshal(out, &sr))
+	var sr3 model.SpanRef
+	require.NoError(t, jsonpb.Unmarshal(out, &sr3))
+	assert.Equal(t, sr, sr3)
+}
+
 func TestMaybeAddParentSpanID(t *testing.T) {
 	span := makeSpan(model.String("k", "v"))
 	assert.Equal(t, model.NewSpanID(123), span.ParentSpanID())
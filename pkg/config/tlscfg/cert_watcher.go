@@ -24,8 +24,6 @@ import (
 	"sync"
 
 	"go.uber.org/zap"
-
-	"github.com/jaegertracing/jaeger/pkg/fswatcher"
 )
 
 const (
@@ -39,11 +37,12 @@ const (
 // The changed RootCAs and ClientCAs certificates are added to x509.CertPool without invalidating the previously used certificate.
 // The certificate and key can be obtained via certWatcher.certificate.
 // The consumers of this API should use GetCertificate or GetClientCertificate from tls.Config to supply the certificate to the config.
+// The files are watched through sharedWatchers, together with the other certWatchers of the same files.
 type certWatcher struct {
 	mu       sync.RWMutex
 	opts     Options
 	logger   *zap.Logger
-	watchers []*fswatcher.FSWatcher
+	watchers []io.Closer
 	cert     *tls.Certificate
 }
 
@@ -94,7 +93,7 @@ func (w *certWatcher) certificate() *tls.Certificate {
 }
 
 func (w *certWatcher) watchCertPair() error {
-	watcher, err := fswatcher.New(
+	watcher, err := sharedWatchers.watch(
 		[]string{w.opts.CertPath, w.opts.KeyPath},
 		w.onCertPairChange,
 		w.logger,
@@ -110,7 +109,7 @@ func (w *certWatcher) watchCertPair() error {
 func (w *certWatcher) watchCert(certPath string, certPool *x509.CertPool) error {
 	onCertChange := func() { w.onCertChange(certPath, certPool) }
 
-	watcher, err := fswatcher.New([]string{certPath}, onCertChange, w.logger)
+	watcher, err := sharedWatchers.watch([]string{certPath}, onCertChange, w.logger)
 	if err == nil {
 		w.watchers = append(w.watchers, watcher)
 		return nil
@@ -36,6 +36,11 @@ const (
 	tlsMinVersion     = tlsPrefix + ".min-version"
 	tlsMaxVersion     = tlsPrefix + ".max-version"
 	tlsReloadInterval = tlsPrefix + ".reload-interval"
+	tlsSPIFFESocket   = tlsPrefix + ".spiffe-socket-path"
+	tlsSPIFFEIDs      = tlsPrefix + ".spiffe-ids"
+	tlsCRL            = tlsPrefix + ".crl"
+	tlsOCSPStapling   = tlsPrefix + ".ocsp-stapling"
+	tlsOCSPRequire    = tlsPrefix + ".ocsp-require-staple"
 )
 
 // ClientFlagsConfig describes which CLI flags for TLS client should be generated.
@@ -57,6 +62,10 @@ func (c ClientFlagsConfig) AddFlags(flags *flag.FlagSet) {
 	flags.String(c.Prefix+tlsKey, "", "Path to a TLS Private Key file, used to identify this process to the remote server(s)")
 	flags.String(c.Prefix+tlsServerName, "", "Override the TLS server name we expect in the certificate of the remote server(s)")
 	flags.Bool(c.Prefix+tlsSkipHostVerify, false, "(insecure) Skip server's certificate chain and host name verification")
+	flags.String(c.Prefix+tlsSPIFFESocket, "", "Path to the unix socket of the SPIFFE Workload API, used to obtain the certificates identifying this process and verifying the remote server(s) instead of the TLS files")
+	flags.String(c.Prefix+tlsSPIFFEIDs, "", "Comma-separated list of the SPIFFE IDs allowed for the remote server(s) when using the SPIFFE Workload API (if unset, all servers of the trust bundle are permitted)")
+	flags.String(c.Prefix+tlsCRL, "", "Path to a file with PEM or DER encoded certificate revocation lists, used to reject remote server(s) with revoked certificates (reloaded when modified)")
+	flags.Bool(c.Prefix+tlsOCSPRequire, false, "Reject remote server(s) not stapling a valid OCSP response to the TLS handshake")
 }
 
 // AddFlags adds flags for TLS to the FlagSet.
@@ -68,8 +77,12 @@ func (c ServerFlagsConfig) AddFlags(flags *flag.FlagSet) {
 	flags.String(c.Prefix+tlsCipherSuites, "", "Comma-separated list of cipher suites for the server, values are from tls package constants (https://golang.org/pkg/crypto/tls/#pkg-constants).")
 	flags.String(c.Prefix+tlsMinVersion, "", "Minimum TLS version supported (Possible values: 1.0, 1.1, 1.2, 1.3)")
 	flags.String(c.Prefix+tlsMaxVersion, "", "Maximum TLS version supported (Possible values: 1.0, 1.1, 1.2, 1.3)")
+	flags.String(c.Prefix+tlsSPIFFESocket, "", "Path to the unix socket of the SPIFFE Workload API, used to obtain the certificates identifying this server and verifying clients instead of the TLS files")
+	flags.String(c.Prefix+tlsSPIFFEIDs, "", "Comma-separated list of the SPIFFE IDs allowed for the clients when using the SPIFFE Workload API (if unset, all clients of the trust bundle are permitted)")
+	flags.String(c.Prefix+tlsCRL, "", "Path to a file with PEM or DER encoded certificate revocation lists, used to reject clients with revoked certificates (reloaded when modified)")
+	flags.Bool(c.Prefix+tlsOCSPStapling, false, "Staple the OCSP response of the server certificate, obtained from its OCSP responder, to the TLS handshakes (the certificate file must include the issuer certificate)")
 	if c.EnableCertReloadInterval {
-		flags.Duration(c.Prefix+tlsReloadInterval, 0, "The duration after which the certificate will be reloaded (0s means will not be reloaded)")
+		flags.Duration(c.Prefix+tlsReloadInterval, 0, "The duration after which the certificate will be reloaded by the servers that do not watch the certificate files for changes, i.e. the OTLP and Zipkin receivers (0s means every minute)")
 	}
 }
 
@@ -82,6 +95,10 @@ func (c ClientFlagsConfig) InitFromViper(v *viper.Viper) (Options, error) {
 	p.KeyPath = v.GetString(c.Prefix + tlsKey)
 	p.ServerName = v.GetString(c.Prefix + tlsServerName)
 	p.SkipHostVerify = v.GetBool(c.Prefix + tlsSkipHostVerify)
+	p.SPIFFESocketPath = v.GetString(c.Prefix + tlsSPIFFESocket)
+	p.SPIFFEIDs = spiffeIDsFromViper(v, c.Prefix)
+	p.CRLPath = v.GetString(c.Prefix + tlsCRL)
+	p.OCSPRequireStaple = v.GetBool(c.Prefix + tlsOCSPRequire)
 
 	if !p.Enabled {
 		var empty Options
@@ -106,6 +123,10 @@ func (c ServerFlagsConfig) InitFromViper(v *viper.Viper) (Options, error) {
 	p.MinVersion = v.GetString(c.Prefix + tlsMinVersion)
 	p.MaxVersion = v.GetString(c.Prefix + tlsMaxVersion)
 	p.ReloadInterval = v.GetDuration(c.Prefix + tlsReloadInterval)
+	p.SPIFFESocketPath = v.GetString(c.Prefix + tlsSPIFFESocket)
+	p.SPIFFEIDs = spiffeIDsFromViper(v, c.Prefix)
+	p.CRLPath = v.GetString(c.Prefix + tlsCRL)
+	p.OCSPStapling = v.GetBool(c.Prefix + tlsOCSPStapling)
 
 	if !p.Enabled {
 		var empty Options
@@ -117,6 +138,13 @@ func (c ServerFlagsConfig) InitFromViper(v *viper.Viper) (Options, error) {
 	return p, nil
 }
 
+func spiffeIDsFromViper(v *viper.Viper, prefix string) []string {
+	if s := v.GetString(prefix + tlsSPIFFEIDs); s != "" {
+		return strings.Split(stripWhiteSpace(s), ",")
+	}
+	return nil
+}
+
 // stripWhiteSpace removes all whitespace characters from a string
 func stripWhiteSpace(str string) string {
 	return strings.ReplaceAll(str, " ", "")
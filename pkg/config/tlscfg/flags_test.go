@@ -33,6 +33,8 @@ func TestClientFlags(t *testing.T) {
 		"--prefix.tls.key=key-file",
 		"--prefix.tls.server-name=HAL1",
 		"--prefix.tls.skip-host-verify=true",
+		"--prefix.tls.crl=crl-file",
+		"--prefix.tls.ocsp-require-staple=true",
 	}
 
 	tests := []struct {
@@ -60,12 +62,14 @@ func TestClientFlags(t *testing.T) {
 			tlsOpts, err := flagCfg.InitFromViper(v)
 			require.NoError(t, err)
 			assert.Equal(t, Options{
-				Enabled:        true,
-				CAPath:         "ca-file",
-				CertPath:       "cert-file",
-				KeyPath:        "key-file",
-				ServerName:     "HAL1",
-				SkipHostVerify: true,
+				Enabled:           true,
+				CAPath:            "ca-file",
+				CertPath:          "cert-file",
+				KeyPath:           "key-file",
+				ServerName:        "HAL1",
+				SkipHostVerify:    true,
+				CRLPath:           "crl-file",
+				OCSPRequireStaple: true,
 			}, tlsOpts)
 		})
 	}
@@ -80,6 +84,8 @@ func TestServerFlags(t *testing.T) {
 		"--prefix.tls.cipher-suites=TLS_AES_256_GCM_SHA384,TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
 		"--prefix.tls.min-version=1.2",
 		"--prefix.tls.max-version=1.3",
+		"--prefix.tls.crl=crl-file",
+		"--prefix.tls.ocsp-stapling=true",
 	}
 
 	tests := []struct {
@@ -117,6 +123,8 @@ func TestServerFlags(t *testing.T) {
 				CipherSuites: []string{"TLS_AES_256_GCM_SHA384", "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA"},
 				MinVersion:   "1.2",
 				MaxVersion:   "1.3",
+				CRLPath:      "crl-file",
+				OCSPStapling: true,
 			}, tlsOpts)
 		})
 	}
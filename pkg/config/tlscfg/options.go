@@ -17,13 +17,17 @@ package tlscfg
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
 	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
 )
 
 // Options describes the configuration properties for TLS Connections.
@@ -39,7 +43,26 @@ type Options struct {
 	MaxVersion     string        `mapstructure:"max_version"`
 	SkipHostVerify bool          `mapstructure:"skip_host_verify"`
 	ReloadInterval time.Duration `mapstructure:"reload_interval"`
-	certWatcher    *certWatcher  `mapstructure:"-"`
+	// SPIFFESocketPath is the unix socket of the SPIFFE Workload API providing the certificates
+	// and trust bundle, instead of the files of CAPath, CertPath, KeyPath and ClientCAPath.
+	SPIFFESocketPath string `mapstructure:"spiffe_socket_path"`
+	// SPIFFEIDs are the SPIFFE IDs allowed for the peers when using SPIFFESocketPath.
+	// If empty, all peers with an SVID of the trust bundle are allowed.
+	SPIFFEIDs []string `mapstructure:"spiffe_ids"`
+	// CRLPath is a file with the certificate revocation lists used to reject the peers
+	// with revoked certificates. It is reloaded when it changes.
+	CRLPath string `mapstructure:"crl"`
+	// OCSPStapling makes servers staple the OCSP response of their certificate to the handshakes.
+	OCSPStapling bool `mapstructure:"ocsp_stapling"`
+	// OCSPRequireStaple makes clients reject the servers not stapling an OCSP response.
+	OCSPRequireStaple bool `mapstructure:"ocsp_require_staple"`
+	// MetricsFactory is used to count the connections rejected by the revocation checks, if set.
+	MetricsFactory metrics.Factory `mapstructure:"-"`
+
+	certWatcher       *certWatcher            `mapstructure:"-"`
+	spiffeSource      *workloadapi.X509Source `mapstructure:"-"`
+	revocationChecker *revocationChecker      `mapstructure:"-"`
+	ocspStapler       *ocspStapler            `mapstructure:"-"`
 }
 
 var systemCertPool = x509.SystemCertPool // to allow overriding in unit test
@@ -88,6 +111,13 @@ func (p *Options) Config(logger *zap.Logger) (*tls.Config, error) {
 		MaxVersion:         maxVersionId,
 	}
 
+	if p.SPIFFESocketPath != "" {
+		if p.CRLPath != "" || p.OCSPStapling || p.OCSPRequireStaple {
+			return nil, fmt.Errorf("certificate revocation checks cannot be used together with the SPIFFE Workload API")
+		}
+		return p.spiffeConfig(tlsCfg, logger)
+	}
+
 	if p.ClientCAPath != "" {
 		// TODO this should be moved to certWatcher, since it already loads key pair
 		certPool := x509.NewCertPool()
@@ -115,6 +145,22 @@ func (p *Options) Config(logger *zap.Logger) (*tls.Config, error) {
 		tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
 			return p.certWatcher.certificate(), nil
 		}
+		if p.OCSPStapling {
+			p.ocspStapler = newOCSPStapler(logger)
+			tlsCfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return p.ocspStapler.staple(p.certWatcher.certificate()), nil
+			}
+		}
+	}
+
+	if p.CRLPath != "" || p.OCSPRequireStaple {
+		checker, err := newRevocationChecker(*p, logger)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.revocationChecker = checker
+		tlsCfg.VerifyConnection = checker.verifyConnection
 	}
 
 	return tlsCfg, nil
@@ -150,10 +196,20 @@ func addCertToPool(caPath string, certPool *x509.CertPool) error {
 
 var _ io.Closer = (*Options)(nil)
 
-// Close shuts down the embedded certificate watcher.
+// Close shuts down the embedded certificate and revocation list watchers.
 func (p *Options) Close() error {
+	if p.spiffeSource != nil {
+		return p.spiffeSource.Close()
+	}
+	var errs []error
+	if p.ocspStapler != nil {
+		errs = append(errs, p.ocspStapler.Close())
+	}
+	if p.revocationChecker != nil {
+		errs = append(errs, p.revocationChecker.Close())
+	}
 	if p.certWatcher != nil {
-		return p.certWatcher.Close()
+		errs = append(errs, p.certWatcher.Close())
 	}
-	return nil
+	return errors.Join(errs...)
 }
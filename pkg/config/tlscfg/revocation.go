@@ -0,0 +1,355 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tlscfg
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+const (
+	logMsgCRLReloaded    = "Reloaded modified certificate revocation list"
+	logMsgCRLNotReloaded = "Failed to reload certificate revocation list, using previous version"
+
+	// ocspRefreshInterval is how often the stapled OCSP response is refreshed when
+	// the responder does not say when the next update is available.
+	ocspRefreshInterval = time.Hour
+	ocspRetryInterval   = time.Minute
+)
+
+// errCertificateRevoked is returned by the verification of the connections with a revoked peer certificate.
+var errCertificateRevoked = errors.New("certificate revoked")
+
+// revocationMetrics counts the connections rejected by the revocation checks, by reason.
+type revocationMetrics struct {
+	CRLRevoked   metrics.Counter `metric:"tls_rejected_connections" tags:"reason=crl_revoked"`
+	OCSPRevoked  metrics.Counter `metric:"tls_rejected_connections" tags:"reason=ocsp_revoked"`
+	OCSPInvalid  metrics.Counter `metric:"tls_rejected_connections" tags:"reason=ocsp_invalid"`
+	OCSPRequired metrics.Counter `metric:"tls_rejected_connections" tags:"reason=ocsp_missing"`
+}
+
+// revocationChecker rejects the connections of peers with revoked certificates, according to
+// a certificate revocation list (CRL) reloaded when it changes, and to the OCSP responses
+// stapled by the servers.
+type revocationChecker struct {
+	mu            sync.RWMutex
+	crlPath       string
+	crls          []*x509.RevocationList
+	requireStaple bool
+	logger        *zap.Logger
+	metrics       revocationMetrics
+	watcher       io.Closer
+}
+
+var _ io.Closer = (*revocationChecker)(nil)
+
+func newRevocationChecker(opts Options, logger *zap.Logger) (*revocationChecker, error) {
+	metricsFactory := opts.MetricsFactory
+	if metricsFactory == nil {
+		metricsFactory = metrics.NullFactory
+	}
+	c := &revocationChecker{
+		crlPath:       opts.CRLPath,
+		requireStaple: opts.OCSPRequireStaple,
+		logger:        logger,
+	}
+	metrics.MustInit(&c.metrics, metricsFactory, nil)
+	if c.crlPath == "" {
+		return c, nil
+	}
+	crls, err := loadCRLs(c.crlPath)
+	if err != nil {
+		return nil, err
+	}
+	c.crls = crls
+	watcher, err := sharedWatchers.watch([]string{c.crlPath}, c.onCRLChange, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch CRL %s: %w", c.crlPath, err)
+	}
+	c.watcher = watcher
+	return c, nil
+}
+
+func (c *revocationChecker) onCRLChange() {
+	crls, err := loadCRLs(c.crlPath)
+	if err != nil {
+		c.logger.Error(logMsgCRLNotReloaded, zap.String("crl", c.crlPath), zap.Error(err))
+		return
+	}
+	c.mu.Lock()
+	c.crls = crls
+	c.mu.Unlock()
+	c.logger.Info(logMsgCRLReloaded, zap.String("crl", c.crlPath))
+}
+
+// loadCRLs reads the revocation lists of a file, either a DER-encoded CRL or PEM-encoded CRLs.
+func loadCRLs(crlPath string) ([]*x509.RevocationList, error) {
+	data, err := os.ReadFile(filepath.Clean(crlPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CRL %s: %w", crlPath, err)
+	}
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		crl, err := x509.ParseRevocationList(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CRL %s: %w", crlPath, err)
+		}
+		return []*x509.RevocationList{crl}, nil
+	}
+	var crls []*x509.RevocationList
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "X509 CRL" {
+			continue
+		}
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CRL %s: %w", crlPath, err)
+		}
+		crls = append(crls, crl)
+	}
+	if len(crls) == 0 {
+		return nil, fmt.Errorf("no CRL found in %s", crlPath)
+	}
+	return crls, nil
+}
+
+// verifyConnection implements tls.Config.VerifyConnection.
+func (c *revocationChecker) verifyConnection(cs tls.ConnectionState) error {
+	for _, chain := range cs.VerifiedChains {
+		if err := c.checkCRLs(chain); err != nil {
+			c.metrics.CRLRevoked.Inc(1)
+			return err
+		}
+	}
+	return c.checkOCSPStaple(cs)
+}
+
+// checkCRLs returns an error if a certificate of the chain, except its root, is revoked by
+// a CRL signed by the issuer of the certificate.
+func (c *revocationChecker) checkCRLs(chain []*x509.Certificate) error {
+	c.mu.RLock()
+	crls := c.crls
+	c.mu.RUnlock()
+	for i := 0; i < len(chain)-1; i++ {
+		cert, issuer := chain[i], chain[i+1]
+		for _, crl := range crls {
+			if !bytes.Equal(crl.RawIssuer, cert.RawIssuer) || crl.CheckSignatureFrom(issuer) != nil {
+				continue
+			}
+			for _, revoked := range crl.RevokedCertificateEntries {
+				if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+					return fmt.Errorf("%w: certificate with serial number %s of %q", errCertificateRevoked, cert.SerialNumber, cert.Subject)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkOCSPStaple validates the OCSP response stapled by a server, if any.
+func (c *revocationChecker) checkOCSPStaple(cs tls.ConnectionState) error {
+	if len(cs.OCSPResponse) == 0 {
+		if c.requireStaple && len(cs.VerifiedChains) > 0 {
+			c.metrics.OCSPRequired.Inc(1)
+			return errors.New("server did not staple an OCSP response")
+		}
+		return nil
+	}
+	if len(cs.VerifiedChains) == 0 || len(cs.VerifiedChains[0]) < 2 {
+		// the certificates are not verified, or the server certificate is its own root
+		return nil
+	}
+	chain := cs.VerifiedChains[0]
+	resp, err := ocsp.ParseResponseForCert(cs.OCSPResponse, chain[0], chain[1])
+	if err != nil {
+		c.metrics.OCSPInvalid.Inc(1)
+		return fmt.Errorf("invalid stapled OCSP response: %w", err)
+	}
+	if !resp.NextUpdate.IsZero() && resp.NextUpdate.Before(time.Now()) {
+		c.metrics.OCSPInvalid.Inc(1)
+		return errors.New("stapled OCSP response has expired")
+	}
+	if resp.Status == ocsp.Revoked {
+		c.metrics.OCSPRevoked.Inc(1)
+		return fmt.Errorf("%w: OCSP status of %q is revoked", errCertificateRevoked, chain[0].Subject)
+	}
+	return nil
+}
+
+func (c *revocationChecker) Close() error {
+	if c.watcher != nil {
+		return c.watcher.Close()
+	}
+	return nil
+}
+
+// ocspStapler fetches the OCSP response of the certificate of a server from the responder
+// of the certificate, to staple it to the handshakes.
+type ocspStapler struct {
+	mu      sync.RWMutex
+	staples map[*tls.Certificate]*tls.Certificate
+	client  *http.Client
+	logger  *zap.Logger
+	timeNow func() time.Time
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newOCSPStapler(logger *zap.Logger) *ocspStapler {
+	return &ocspStapler{
+		staples: make(map[*tls.Certificate]*tls.Certificate),
+		client:  &http.Client{Timeout: 10 * time.Second},
+		logger:  logger,
+		timeNow: time.Now,
+	}
+}
+
+// staple returns a copy of cert with the stapled OCSP response, once it has been fetched.
+// The certificate is returned as is until then, or if it has no OCSP responder.
+func (s *ocspStapler) staple(cert *tls.Certificate) *tls.Certificate {
+	if cert == nil {
+		return nil
+	}
+	s.mu.RLock()
+	stapled, ok := s.staples[cert]
+	s.mu.RUnlock()
+	if ok {
+		return stapled
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stapled, ok := s.staples[cert]; ok {
+		return stapled
+	}
+	// the certificate was reloaded, the old one is not needed anymore
+	for c := range s.staples {
+		delete(s.staples, c)
+	}
+	s.staples[cert] = cert
+	if s.cancel != nil {
+		s.cancel()
+	}
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.wg.Add(1)
+	go s.refresh(ctx, cert)
+	return cert
+}
+
+func (s *ocspStapler) refresh(ctx context.Context, cert *tls.Certificate) {
+	defer s.wg.Done()
+	for {
+		next, err := s.fetch(ctx, cert)
+		if err != nil {
+			if errors.Is(err, errNoOCSPResponder) {
+				return
+			}
+			s.logger.Warn("Failed to fetch OCSP response to staple", zap.Error(err))
+			next = s.timeNow().Add(ocspRetryInterval)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(next.Sub(s.timeNow())):
+		}
+	}
+}
+
+var errNoOCSPResponder = errors.New("certificate has no OCSP responder")
+
+// fetch gets a new OCSP response of the certificate and returns when to refresh it.
+func (s *ocspStapler) fetch(ctx context.Context, cert *tls.Certificate) (time.Time, error) {
+	leaf, issuer, err := leafAndIssuer(cert)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(leaf.OCSPServer) == 0 {
+		return time.Time{}, errNoOCSPResponder
+	}
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, leaf.OCSPServer[0], bytes.NewReader(req))
+	if err != nil {
+		return time.Time{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/ocsp-request")
+	httpResp, err := s.client.Do(httpReq)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("OCSP responder returned status %d", httpResp.StatusCode)
+	}
+	raw, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp, err := ocsp.ParseResponseForCert(raw, leaf, issuer)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid OCSP response: %w", err)
+	}
+	stapled := *cert
+	stapled.OCSPStaple = raw
+	s.mu.Lock()
+	if _, ok := s.staples[cert]; ok {
+		s.staples[cert] = &stapled
+	}
+	s.mu.Unlock()
+	s.logger.Info("Fetched OCSP response to staple", zap.Stringer("next_update", resp.NextUpdate))
+
+	next := s.timeNow().Add(ocspRefreshInterval)
+	if !resp.NextUpdate.IsZero() {
+		// refresh halfway to the next update, to never staple an expired response
+		if half := resp.ThisUpdate.Add(resp.NextUpdate.Sub(resp.ThisUpdate) / 2); half.Before(next) {
+			next = half
+		}
+	}
+	return next, nil
+}
+
+func leafAndIssuer(cert *tls.Certificate) (*x509.Certificate, *x509.Certificate, error) {
+	if len(cert.Certificate) < 2 {
+		return nil, nil, errors.New("the certificate file must contain the issuer certificate to staple OCSP responses")
+	}
+	leaf := cert.Leaf
+	if leaf == nil {
+		var err error
+		if leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return nil, nil, err
+		}
+	}
+	issuer, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return leaf, issuer, nil
+}
+
+func (s *ocspStapler) Close() error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return nil
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tlscfg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+)
+
+// issue writes a certificate of the CA with its key to files of dir, and returns their paths.
+// The certificate file also contains the CA certificate.
+func (ca *testCA) issue(t *testing.T, dir string, name string, serial int64, ocspServer string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if ocspServer != "" {
+		template.OCSPServer = []string{ocspServer}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certPath := filepath.Join(dir, name+".pem")
+	certPEM := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), ca.pem()...)
+	require.NoError(t, os.WriteFile(certPath, certPEM, 0o600))
+	keyPath := filepath.Join(dir, name+"-key.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certPath, keyPath
+}
+
+func (ca *testCA) pem() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+}
+
+// crl returns a DER encoded revocation list of the CA revoking the serial numbers.
+func (ca *testCA) crl(t *testing.T, number int64, serials ...int64) []byte {
+	var entries []x509.RevocationListEntry
+	for _, serial := range serials {
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+	}
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(number),
+		ThisUpdate:                time.Now().Add(-time.Minute),
+		NextUpdate:                time.Now().Add(time.Hour),
+		RevokedCertificateEntries: entries,
+	}, ca.cert, ca.key)
+	require.NoError(t, err)
+	return crl
+}
+
+func TestCRLRevocation(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caPath, ca.pem(), 0o600))
+	serverCert, serverKey := ca.issue(t, dir, "server", 2, "")
+	clientCert, clientKey := ca.issue(t, dir, "client", 3, "")
+	crlPath := filepath.Join(dir, "crl.pem")
+	require.NoError(t, os.WriteFile(crlPath, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: ca.crl(t, 1)}), 0o600))
+
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	serverOpts := Options{
+		Enabled:        true,
+		CertPath:       serverCert,
+		KeyPath:        serverKey,
+		ClientCAPath:   caPath,
+		CRLPath:        crlPath,
+		MetricsFactory: metricsFactory,
+	}
+	serverCfg, err := serverOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer serverOpts.Close()
+	clientOpts := Options{Enabled: true, CAPath: caPath, CertPath: clientCert, KeyPath: clientKey, ServerName: "example.com"}
+	clientCfg, err := clientOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer clientOpts.Close()
+
+	require.NoError(t, handshake(serverCfg, clientCfg))
+
+	// the modified CRL, DER encoded this time, is reloaded
+	require.NoError(t, os.WriteFile(crlPath, ca.crl(t, 2, 3), 0o600))
+	require.Eventually(t, func() bool {
+		return handshake(serverCfg, clientCfg) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.ErrorContains(t, handshake(serverCfg, clientCfg), "certificate revoked")
+	counters, _ := metricsFactory.Snapshot()
+	assert.Positive(t, counters["tls_rejected_connections|reason=crl_revoked"])
+
+	// an invalid CRL is ignored
+	require.NoError(t, os.WriteFile(crlPath, []byte("invalid"), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	require.ErrorContains(t, handshake(serverCfg, clientCfg), "certificate revoked")
+
+	// the CRLs of other issuers do not apply
+	otherCA := newTestCA(t)
+	require.NoError(t, os.WriteFile(crlPath, otherCA.crl(t, 3, 3), 0o600))
+	require.Eventually(t, func() bool {
+		return handshake(serverCfg, clientCfg) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLoadCRLsErrors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := loadCRLs(filepath.Join(dir, "missing.pem"))
+	require.ErrorContains(t, err, "failed to load CRL")
+
+	invalidDER := filepath.Join(dir, "invalid.crl")
+	require.NoError(t, os.WriteFile(invalidDER, []byte("invalid"), 0o600))
+	_, err = loadCRLs(invalidDER)
+	require.ErrorContains(t, err, "failed to parse CRL")
+
+	invalidPEM := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidPEM, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: []byte("invalid")}), 0o600))
+	_, err = loadCRLs(invalidPEM)
+	require.ErrorContains(t, err, "failed to parse CRL")
+
+	noCRL := filepath.Join(dir, "cert.pem")
+	require.NoError(t, os.WriteFile(noCRL, newTestCA(t).pem(), 0o600))
+	_, err = loadCRLs(noCRL)
+	require.ErrorContains(t, err, "no CRL found")
+
+	opts := Options{Enabled: true, CRLPath: noCRL}
+	_, err = opts.Config(zap.NewNop())
+	require.ErrorContains(t, err, "no CRL found")
+
+	opts = Options{Enabled: true, SPIFFESocketPath: "/tmp/agent.sock", CRLPath: noCRL}
+	_, err = opts.Config(zap.NewNop())
+	require.ErrorContains(t, err, "cannot be used together with the SPIFFE Workload API")
+}
+
+// fakeOCSPResponder answers the OCSP requests of the certificates of the CA with its status.
+type fakeOCSPResponder struct {
+	ca       *testCA
+	status   atomic.Int32
+	requests atomic.Int32
+}
+
+func (r *fakeOCSPResponder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.requests.Add(1)
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ocspReq, err := ocsp.ParseRequest(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	resp, err := ocsp.CreateResponse(r.ca.cert, r.ca.cert, ocsp.Response{
+		Status:       int(r.status.Load()),
+		SerialNumber: ocspReq.SerialNumber,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   time.Now().Add(time.Hour),
+		RevokedAt:    time.Now().Add(-time.Minute),
+	}, r.ca.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Write(resp)
+}
+
+func TestOCSPStapling(t *testing.T) {
+	ca := newTestCA(t)
+	responder := &fakeOCSPResponder{ca: ca}
+	ocspServer := httptest.NewServer(responder)
+	defer ocspServer.Close()
+
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caPath, ca.pem(), 0o600))
+	serverCert, serverKey := ca.issue(t, dir, "server", 2, ocspServer.URL)
+
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	clientOpts := Options{Enabled: true, CAPath: caPath, ServerName: "example.com", OCSPRequireStaple: true, MetricsFactory: metricsFactory}
+	clientCfg, err := clientOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer clientOpts.Close()
+
+	// servers not stapling OCSP responses are rejected
+	plainOpts := Options{Enabled: true, CertPath: serverCert, KeyPath: serverKey}
+	plainCfg, err := plainOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer plainOpts.Close()
+	require.ErrorContains(t, handshake(plainCfg, clientCfg), "did not staple an OCSP response")
+
+	serverOpts := Options{Enabled: true, CertPath: serverCert, KeyPath: serverKey, OCSPStapling: true}
+	serverCfg, err := serverOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer serverOpts.Close()
+	// the response is fetched after the first handshake
+	require.Eventually(t, func() bool {
+		return handshake(serverCfg, clientCfg) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), responder.requests.Load())
+
+	// a revoked server certificate is rejected
+	responder.status.Store(ocsp.Revoked)
+	revokedOpts := Options{Enabled: true, CertPath: serverCert, KeyPath: serverKey, OCSPStapling: true}
+	revokedCfg, err := revokedOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer revokedOpts.Close()
+	require.Eventually(t, func() bool {
+		err := handshake(revokedCfg, clientCfg)
+		return err != nil && strings.Contains(err.Error(), "OCSP status")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	counters, _ := metricsFactory.Snapshot()
+	assert.Positive(t, counters["tls_rejected_connections|reason=ocsp_missing"])
+	assert.Positive(t, counters["tls_rejected_connections|reason=ocsp_revoked"])
+}
+
+func TestOCSPStaplingErrors(t *testing.T) {
+	ca := newTestCA(t)
+	var status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	ocspServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer ocspServer.Close()
+	dir := t.TempDir()
+	certPath, keyPath := ca.issue(t, dir, "server", 2, ocspServer.URL)
+	opts := Options{Enabled: true, CertPath: certPath, KeyPath: keyPath}
+	_, err := opts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer opts.Close()
+	cert := opts.certWatcher.certificate()
+
+	stapler := newOCSPStapler(zap.NewNop())
+	defer stapler.Close()
+	_, err = stapler.fetch(context.Background(), cert)
+	require.ErrorContains(t, err, "OCSP responder returned status 500")
+	status.Store(http.StatusOK)
+	_, err = stapler.fetch(context.Background(), cert)
+	require.ErrorContains(t, err, "invalid OCSP response")
+	assert.Nil(t, stapler.staple(nil))
+
+	noResponderCert, noResponderKey := ca.issue(t, dir, "other", 3, "")
+	otherOpts := Options{Enabled: true, CertPath: noResponderCert, KeyPath: noResponderKey}
+	_, err = otherOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer otherOpts.Close()
+	_, err = stapler.fetch(context.Background(), otherOpts.certWatcher.certificate())
+	require.ErrorIs(t, err, errNoOCSPResponder)
+
+	leafOnly := *cert
+	leafOnly.Certificate = leafOnly.Certificate[:1]
+	_, err = stapler.fetch(context.Background(), &leafOnly)
+	require.ErrorContains(t, err, "must contain the issuer certificate")
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tlscfg
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/fswatcher"
+)
+
+// sharedWatchers watches the certificate files of all the Options of the process.
+var sharedWatchers = newWatcherHub()
+
+// watcherHub shares a single fswatcher.FSWatcher among all the watches of the same files,
+// so that the servers and clients of a binary using the same certificates do not each
+// consume an inotify instance, and all pick up a rotated certificate on the same change.
+type watcherHub struct {
+	mu       sync.Mutex
+	watchers map[string]*sharedWatcher
+}
+
+func newWatcherHub() *watcherHub {
+	return &watcherHub{watchers: make(map[string]*sharedWatcher)}
+}
+
+// sharedWatcher notifies all its subscriptions of the changes of its files.
+type sharedWatcher struct {
+	key     string
+	watcher *fswatcher.FSWatcher
+
+	mu            sync.RWMutex
+	subscriptions map[*watchSubscription]struct{}
+}
+
+// watchSubscription is the io.Closer returned by watcherHub.watch.
+type watchSubscription struct {
+	hub      *watcherHub
+	shared   *sharedWatcher
+	onChange func()
+}
+
+// watch calls onChange whenever one of the files changes, until the returned io.Closer is closed.
+// The files are watched by the first of the subscriptions to the same files.
+func (h *watcherHub) watch(filepaths []string, onChange func(), logger *zap.Logger) (io.Closer, error) {
+	key := strings.Join(filepaths, "\x00")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	shared, ok := h.watchers[key]
+	if !ok {
+		shared = &sharedWatcher{
+			key:           key,
+			subscriptions: make(map[*watchSubscription]struct{}),
+		}
+		watcher, err := fswatcher.New(filepaths, shared.onChange, logger)
+		if err != nil {
+			return nil, err
+		}
+		shared.watcher = watcher
+		h.watchers[key] = shared
+	}
+	sub := &watchSubscription{hub: h, shared: shared, onChange: onChange}
+	shared.mu.Lock()
+	shared.subscriptions[sub] = struct{}{}
+	shared.mu.Unlock()
+	return sub, nil
+}
+
+func (s *sharedWatcher) onChange() {
+	s.mu.RLock()
+	subscriptions := make([]*watchSubscription, 0, len(s.subscriptions))
+	for sub := range s.subscriptions {
+		subscriptions = append(subscriptions, sub)
+	}
+	s.mu.RUnlock()
+	for _, sub := range subscriptions {
+		sub.onChange()
+	}
+}
+
+// Close stops notifying the subscription, and stops watching the files after the last subscription.
+func (sub *watchSubscription) Close() error {
+	sub.hub.mu.Lock()
+	defer sub.hub.mu.Unlock()
+	shared := sub.shared
+	shared.mu.Lock()
+	_, ok := shared.subscriptions[sub]
+	delete(shared.subscriptions, sub)
+	remaining := len(shared.subscriptions)
+	shared.mu.Unlock()
+	if !ok || remaining > 0 {
+		return nil
+	}
+	delete(sub.hub.watchers, shared.key)
+	return shared.watcher.Close()
+}
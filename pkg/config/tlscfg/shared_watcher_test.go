@@ -0,0 +1,80 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tlscfg
+
+import (
+	"bytes"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWatcherHub(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	require.NoError(t, os.WriteFile(path, []byte("first"), 0o600))
+
+	hub := newWatcherHub()
+	var first, second atomic.Int32
+	firstWatch, err := hub.watch([]string{path}, func() { first.Add(1) }, zap.NewNop())
+	require.NoError(t, err)
+	secondWatch, err := hub.watch([]string{path}, func() { second.Add(1) }, zap.NewNop())
+	require.NoError(t, err)
+	assert.Len(t, hub.watchers, 1, "the files are watched once")
+
+	require.NoError(t, os.WriteFile(path, []byte("second"), 0o600))
+	assert.Eventually(t, func() bool {
+		return first.Load() > 0 && second.Load() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the files are still watched for the remaining subscription
+	require.NoError(t, firstWatch.Close())
+	require.NoError(t, firstWatch.Close())
+	assert.Len(t, hub.watchers, 1)
+	notified := second.Load()
+	require.NoError(t, os.WriteFile(path, []byte("third"), 0o600))
+	assert.Eventually(t, func() bool {
+		return second.Load() > notified
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, secondWatch.Close())
+	assert.Empty(t, hub.watchers)
+
+	_, err = hub.watch([]string{filepath.Join(t.TempDir(), "missing.pem")}, func() {}, zap.NewNop())
+	require.Error(t, err)
+	assert.Empty(t, hub.watchers)
+}
+
+func TestSharedCertificateReload(t *testing.T) {
+	certFile, certFileCloseFn := copyToTempFile(t, "cert.crt", serverCert)
+	defer certFileCloseFn()
+	keyFile, keyFileCloseFn := copyToTempFile(t, "key.crt", serverKey)
+	defer keyFileCloseFn()
+
+	// e.g. the gRPC and HTTP servers of a binary using the same certificate
+	grpcOpts := Options{Enabled: true, CertPath: certFile.Name(), KeyPath: keyFile.Name()}
+	_, err := grpcOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer grpcOpts.Close()
+	httpOpts := grpcOpts
+	_, err = httpOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer httpOpts.Close()
+
+	rotated, err := tls.LoadX509KeyPair(clientCert, clientKey)
+	require.NoError(t, err)
+	copyFile(t, keyFile.Name(), clientKey)
+	copyFile(t, certFile.Name(), clientCert)
+	for _, opts := range []*Options{&grpcOpts, &httpOpts} {
+		assert.Eventually(t, func() bool {
+			return bytes.Equal(opts.certWatcher.certificate().Certificate[0], rotated.Certificate[0])
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tlscfg
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	"go.uber.org/zap"
+)
+
+// spiffeInitTimeout is how long Config waits for the first SVID of the Workload API
+var spiffeInitTimeout = 30 * time.Second
+
+// spiffeConfig completes tlsCfg for mutual TLS with the SVIDs of the SPIFFE Workload API.
+// The SVIDs and trust bundles are rotated by the source without restarting.
+func (p *Options) spiffeConfig(tlsCfg *tls.Config, logger *zap.Logger) (*tls.Config, error) {
+	if p.CAPath != "" || p.CertPath != "" || p.KeyPath != "" || p.ClientCAPath != "" {
+		return nil, errors.New("TLS certificate files cannot be used together with the SPIFFE Workload API")
+	}
+	authorizer, err := spiffeAuthorizer(p.SPIFFEIDs)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), spiffeInitTimeout)
+	defer cancel()
+	source, err := workloadapi.NewX509Source(ctx, workloadapi.WithClientOptions(
+		workloadapi.WithAddr("unix://"+p.SPIFFESocketPath),
+		workloadapi.WithLogger(logger.Sugar()),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("no X.509 SVID received from the SPIFFE Workload API at %s: %w", p.SPIFFESocketPath, err)
+	}
+	p.spiffeSource = source
+
+	// the same config is used by the servers and the clients, so the client fields of
+	// HookMTLSClientConfig are added to the server config: the server certificate is
+	// verified by VerifyPeerCertificate against the trust bundle instead of the host name
+	tlsconfig.HookMTLSServerConfig(tlsCfg, source, source, authorizer)
+	tlsCfg.GetClientCertificate = tlsconfig.GetClientCertificate(source)
+	tlsCfg.InsecureSkipVerify = true
+	return tlsCfg, nil
+}
+
+// spiffeAuthorizer returns the authorizer of the peers with the SPIFFE IDs, or of all the peers
+// of the trust bundle if there are none.
+func spiffeAuthorizer(allowedIDs []string) (tlsconfig.Authorizer, error) {
+	if len(allowedIDs) == 0 {
+		return tlsconfig.AuthorizeAny(), nil
+	}
+	ids := make([]spiffeid.ID, len(allowedIDs))
+	for i, allowedID := range allowedIDs {
+		id, err := spiffeid.FromString(allowedID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPIFFE ID %q: %w", allowedID, err)
+		}
+		ids[i] = id
+	}
+	return tlsconfig.AuthorizeOneOf(ids...), nil
+}
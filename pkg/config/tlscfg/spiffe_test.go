@@ -0,0 +1,213 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package tlscfg
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// svidResponse returns an X509SVIDResponse with an SVID of the SPIFFE ID issued by the CA.
+func (ca *testCA) svidResponse(t *testing.T, spiffeID string, serial int64) *workload.X509SVIDResponse {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id, err := url.Parse(spiffeID)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{id},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return &workload.X509SVIDResponse{
+		Svids: []*workload.X509SVID{{
+			SpiffeId:    spiffeID,
+			X509Svid:    der,
+			X509SvidKey: keyDER,
+			Bundle:      ca.cert.Raw,
+		}},
+	}
+}
+
+// fakeWorkloadAPI is a SPIFFE Workload API sending the responses of its channel.
+type fakeWorkloadAPI struct {
+	workload.UnimplementedSpiffeWorkloadAPIServer
+	responses chan *workload.X509SVIDResponse
+}
+
+func startFakeWorkloadAPI(t *testing.T) (string, *fakeWorkloadAPI) {
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	api := &fakeWorkloadAPI{responses: make(chan *workload.X509SVIDResponse, 10)}
+	server := grpc.NewServer()
+	workload.RegisterSpiffeWorkloadAPIServer(server, api)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return socketPath, api
+}
+
+func (api *fakeWorkloadAPI) FetchX509SVID(_ *workload.X509SVIDRequest, stream workload.SpiffeWorkloadAPI_FetchX509SVIDServer) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	if len(md.Get("workload.spiffe.io")) == 0 {
+		return status.Error(codes.InvalidArgument, "missing security header")
+	}
+	for {
+		select {
+		case resp := <-api.responses:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// serialNumber returns the serial number of the current SVID of the options.
+func serialNumber(t *testing.T, opts *Options) int64 {
+	svid, err := opts.spiffeSource.GetX509SVID()
+	require.NoError(t, err)
+	return svid.Certificates[0].SerialNumber.Int64()
+}
+
+func TestSPIFFEMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverSocket, serverAPI := startFakeWorkloadAPI(t)
+	clientSocket, clientAPI := startFakeWorkloadAPI(t)
+	serverAPI.responses <- ca.svidResponse(t, "spiffe://example.org/collector", 2)
+	clientAPI.responses <- ca.svidResponse(t, "spiffe://example.org/agent", 3)
+
+	serverOpts := Options{Enabled: true, SPIFFESocketPath: serverSocket, SPIFFEIDs: []string{"spiffe://example.org/agent"}}
+	serverCfg, err := serverOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer serverOpts.Close()
+	clientOpts := Options{Enabled: true, SPIFFESocketPath: clientSocket, SPIFFEIDs: []string{"spiffe://example.org/collector"}}
+	clientCfg, err := clientOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer clientOpts.Close()
+
+	require.NoError(t, handshake(serverCfg, clientCfg))
+
+	// the client rejects servers with other SPIFFE IDs
+	otherSocket, otherAPI := startFakeWorkloadAPI(t)
+	otherAPI.responses <- ca.svidResponse(t, "spiffe://example.org/agent", 4)
+	otherOpts := Options{Enabled: true, SPIFFESocketPath: otherSocket, SPIFFEIDs: []string{"spiffe://example.org/query"}}
+	otherCfg, err := otherOpts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer otherOpts.Close()
+	require.ErrorContains(t, handshake(serverCfg, otherCfg), `unexpected ID "spiffe://example.org/collector"`)
+
+	// the SVIDs are rotated
+	serverAPI.responses <- ca.svidResponse(t, "spiffe://example.org/collector", 5)
+	require.Eventually(t, func() bool {
+		return serialNumber(t, &serverOpts) == 5
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, handshake(serverCfg, clientCfg))
+
+	// peers of another trust domain are rejected
+	otherCA := newTestCA(t)
+	clientAPI.responses <- otherCA.svidResponse(t, "spiffe://example.org/agent", 6)
+	require.Eventually(t, func() bool {
+		return serialNumber(t, &clientOpts) == 6
+	}, 5*time.Second, 10*time.Millisecond)
+	require.ErrorContains(t, handshake(serverCfg, clientCfg), "could not verify leaf certificate")
+}
+
+// handshake runs a TLS handshake between the configs and returns the error of the client, or else of the server.
+func handshake(serverCfg, clientCfg *tls.Config) error {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- tls.Server(serverConn, serverCfg).Handshake()
+		serverConn.Close()
+	}()
+	clientErr := tls.Client(clientConn, clientCfg).Handshake()
+	clientConn.Close()
+	if err := <-serverErr; clientErr == nil && err != nil {
+		return err
+	}
+	return clientErr
+}
+
+func TestSPIFFEConfigErrors(t *testing.T) {
+	opts := Options{Enabled: true, SPIFFESocketPath: "/tmp/agent.sock", CertPath: "cert.pem"}
+	_, err := opts.Config(zap.NewNop())
+	require.ErrorContains(t, err, "cannot be used together with the SPIFFE Workload API")
+
+	opts = Options{Enabled: true, SPIFFESocketPath: "/tmp/agent.sock", SPIFFEIDs: []string{"https://example.org/agent"}}
+	_, err = opts.Config(zap.NewNop())
+	require.ErrorContains(t, err, `invalid SPIFFE ID "https://example.org/agent"`)
+
+	defer func(timeout time.Duration) { spiffeInitTimeout = timeout }(spiffeInitTimeout)
+	spiffeInitTimeout = 100 * time.Millisecond
+	opts = Options{Enabled: true, SPIFFESocketPath: filepath.Join(t.TempDir(), "missing.sock")}
+	_, err = opts.Config(zap.NewNop())
+	require.ErrorContains(t, err, "no X.509 SVID received from the SPIFFE Workload API")
+}
+
+func TestSPIFFEInvalidResponses(t *testing.T) {
+	ca := newTestCA(t)
+	socketPath, api := startFakeWorkloadAPI(t)
+	invalid := ca.svidResponse(t, "spiffe://example.org/collector", 2)
+	invalid.Svids[0].X509SvidKey = []byte{1}
+	api.responses <- invalid
+	api.responses <- ca.svidResponse(t, "spiffe://example.org/collector", 3)
+	opts := Options{Enabled: true, SPIFFESocketPath: socketPath}
+	_, err := opts.Config(zap.NewNop())
+	require.NoError(t, err)
+	defer opts.Close()
+	assert.Equal(t, int64(3), serialNumber(t, &opts))
+}
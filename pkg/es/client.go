@@ -30,6 +30,8 @@ type Client interface {
 	Index() IndexService
 	Search(indices ...string) SearchService
 	MultiSearch() MultiSearchService
+	Documents(index string) DocumentService
+	DeleteByQuery(indices ...string) DeleteByQueryService
 	io.Closer
 	GetVersion() uint
 }
@@ -60,6 +62,16 @@ type IndexService interface {
 	Add()
 }
 
+// DocumentService is an abstraction for the single document APIs of elastic.Client.
+// Unlike IndexService the writes are synchronous, and the updates and deletes are applied
+// only if the document was not modified since it was read with the given seqNo and primaryTerm.
+type DocumentService interface {
+	Get(ctx context.Context, id string) (*elastic.GetResult, error)
+	Create(ctx context.Context, id string, body interface{}) error
+	Update(ctx context.Context, id string, seqNo, primaryTerm int64, body interface{}) error
+	Delete(ctx context.Context, id string, seqNo, primaryTerm int64) error
+}
+
 // SearchService is an abstraction for elastic.SearchService
 type SearchService interface {
 	Size(size int) SearchService
@@ -69,6 +81,13 @@ type SearchService interface {
 	Do(ctx context.Context) (*elastic.SearchResult, error)
 }
 
+// DeleteByQueryService is an abstraction for elastic.DeleteByQueryService
+type DeleteByQueryService interface {
+	Query(query elastic.Query) DeleteByQueryService
+	IgnoreUnavailable(ignoreUnavailable bool) DeleteByQueryService
+	Do(ctx context.Context) (*elastic.BulkIndexByScrollResponse, error)
+}
+
 // MultiSearchService is an abstraction for elastic.MultiSearchService
 type MultiSearchService interface {
 	Add(requests ...*elastic.SearchRequest) MultiSearchService
@@ -47,3 +47,16 @@ func (i ILMClient) Exists(name string) (bool, error) {
 	}
 	return true, nil
 }
+
+// Create creates or updates an ILM policy
+func (i ILMClient) Create(name, policy string) error {
+	_, err := i.request(elasticRequest{
+		endpoint: fmt.Sprintf("_ilm/policy/%s", name),
+		method:   http.MethodPut,
+		body:     []byte(policy),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create ILM policy: %s, %w", name, err)
+	}
+	return nil
+}
@@ -30,4 +30,5 @@ type ClusterAPI interface {
 
 type IndexManagementLifecycleAPI interface {
 	Exists(name string) (bool, error)
+	Create(name, policy string) error
 }
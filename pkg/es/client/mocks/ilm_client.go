@@ -24,3 +24,8 @@ func (c *MockILMAPI) Exists(name string) (bool, error) {
 	ret := c.Called(name)
 	return ret.Get(0).(bool), ret.Error(1)
 }
+
+func (c *MockILMAPI) Create(name, policy string) error {
+	ret := c.Called(name, policy)
+	return ret.Error(0)
+}
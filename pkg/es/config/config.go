@@ -82,6 +82,7 @@ type Configuration struct {
 	UseReadWriteAliases            bool           `mapstructure:"use_aliases"`
 	CreateIndexTemplates           bool           `mapstructure:"create_mappings"`
 	UseILM                         bool           `mapstructure:"use_ilm"`
+	IndexPerTenant                 bool           `mapstructure:"index_per_tenant"`
 	Version                        uint           `mapstructure:"version"`
 	LogLevel                       string         `mapstructure:"log_level"`
 	SendGetBodyAs                  string         `mapstructure:"send_get_body_as"`
@@ -267,6 +268,9 @@ func (c *Configuration) ApplyDefaults(source *Configuration) {
 	if !c.SnifferTLSEnabled {
 		c.SnifferTLSEnabled = source.SnifferTLSEnabled
 	}
+	if !c.IndexPerTenant {
+		c.IndexPerTenant = source.IndexPerTenant
+	}
 	if !c.Tags.AllAsFields {
 		c.Tags.AllAsFields = source.Tags.AllAsFields
 	}
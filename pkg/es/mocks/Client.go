@@ -73,6 +73,44 @@ func (_m *Client) CreateTemplate(id string) es.TemplateCreateService {
 	return r0
 }
 
+// DeleteByQuery provides a mock function with given fields: indices
+func (_m *Client) DeleteByQuery(indices ...string) es.DeleteByQueryService {
+	_va := make([]interface{}, len(indices))
+	for _i := range indices {
+		_va[_i] = indices[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 es.DeleteByQueryService
+	if rf, ok := ret.Get(0).(func(...string) es.DeleteByQueryService); ok {
+		r0 = rf(indices...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.DeleteByQueryService)
+		}
+	}
+
+	return r0
+}
+
+// Documents provides a mock function with given fields: index
+func (_m *Client) Documents(index string) es.DocumentService {
+	ret := _m.Called(index)
+
+	var r0 es.DocumentService
+	if rf, ok := ret.Get(0).(func(string) es.DocumentService); ok {
+		r0 = rf(index)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.DocumentService)
+		}
+	}
+
+	return r0
+}
+
 // GetVersion provides a mock function with given fields:
 func (_m *Client) GetVersion() uint {
 	ret := _m.Called()
@@ -0,0 +1,25 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package es
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// TenantIndexName returns the name of a tenant as it appears in the names of its indices
+// when the indices are split by tenant, e.g. jaeger-span-{tenant}-2024-01-02.
+// The names made of lowercase letters, digits, underscores and dots are used as is.
+// As index names are lowercase and cannot contain characters such as / or *, the other
+// names are hex-encoded after a dash, e.g. -41434d45 for ACME, so that no two tenants
+// share an index nor an index prefix: the names used as is contain no dash.
+func TenantIndexName(tenant string) string {
+	valid := tenant != "" && strings.IndexFunc(tenant, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '.')
+	}) < 0
+	if valid {
+		return tenant
+	}
+	return "-" + hex.EncodeToString([]byte(tenant))
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package es
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantIndexName(t *testing.T) {
+	assert.Equal(t, "acme", TenantIndexName("acme"))
+	assert.Equal(t, "acme.eu_1", TenantIndexName("acme.eu_1"))
+	assert.Equal(t, "-41434d45", TenantIndexName("ACME"))
+	assert.Equal(t, "-61636d652d636f7270", TenantIndexName("acme-corp"))
+	assert.Equal(t, "-612f622a6320", TenantIndexName("a/b*c "))
+	assert.Equal(t, "-c3a9", TenantIndexName("é"))
+}
+
+func TestTenantIndexNameDistinct(t *testing.T) {
+	// tenants that used to be mapped to the same index name or index prefix
+	tenants := []string{"acme", "ACME", "Acme", "a_b", "a/b", "a*b", "a b", "a", "a-b", "-61"}
+	for _, tenant := range tenants {
+		for _, other := range tenants {
+			if tenant == other {
+				continue
+			}
+			// the indices of the other tenant do not match the index pattern of the tenant, e.g. jaeger-span-a-*
+			assert.False(t, strings.HasPrefix(TenantIndexName(other)+"-", TenantIndexName(tenant)+"-"),
+				"tenants %q and %q", tenant, other)
+		}
+	}
+}
@@ -97,6 +97,17 @@ func (c ClientWrapper) MultiSearch() es.MultiSearchService {
 	return WrapESMultiSearchService(multiSearchService)
 }
 
+// Documents returns the service of the single document APIs of the index.
+func (c ClientWrapper) Documents(index string) es.DocumentService {
+	return DocumentServiceWrapper{client: c.client, index: index}
+}
+
+// DeleteByQuery calls this function to internal client. The documents modified during the delete
+// are deleted as well, and the indices are refreshed so that the deleted documents are no longer found.
+func (c ClientWrapper) DeleteByQuery(indices ...string) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(c.client.DeleteByQuery(indices...).ProceedOnVersionConflict().Refresh("true"))
+}
+
 // Close closes ESClient and flushes all data to the storage.
 func (c ClientWrapper) Close() error {
 	c.client.Stop()
@@ -225,6 +236,43 @@ func (i IndexServiceWrapper) Add() {
 
 // ---
 
+// DocumentServiceWrapper is a wrapper around the single document APIs of elastic.Client.
+type DocumentServiceWrapper struct {
+	client *elastic.Client
+	index  string
+}
+
+// documentType is the type of the documents, accepted by all the supported versions of Elasticsearch.
+const documentType = "_doc"
+
+// Get calls this function to internal client.
+func (d DocumentServiceWrapper) Get(ctx context.Context, id string) (*elastic.GetResult, error) {
+	return d.client.Get().Index(d.index).Type(documentType).Id(id).Do(ctx)
+}
+
+// Create indexes the document only if a document with the same id does not exist.
+func (d DocumentServiceWrapper) Create(ctx context.Context, id string, body interface{}) error {
+	_, err := d.client.Index().Index(d.index).Type(documentType).Id(id).
+		OpType("create").BodyJson(body).Do(ctx)
+	return err
+}
+
+// Update indexes the document only if it was not modified since it was read.
+func (d DocumentServiceWrapper) Update(ctx context.Context, id string, seqNo, primaryTerm int64, body interface{}) error {
+	_, err := d.client.Index().Index(d.index).Type(documentType).Id(id).
+		IfSeqNo(seqNo).IfPrimaryTerm(primaryTerm).BodyJson(body).Do(ctx)
+	return err
+}
+
+// Delete deletes the document only if it was not modified since it was read.
+func (d DocumentServiceWrapper) Delete(ctx context.Context, id string, seqNo, primaryTerm int64) error {
+	_, err := d.client.Delete().Index(d.index).Type(documentType).Id(id).
+		IfSeqNo(seqNo).IfPrimaryTerm(primaryTerm).Do(ctx)
+	return err
+}
+
+// ---
+
 // SearchServiceWrapper is a wrapper around elastic.ESSearchService
 type SearchServiceWrapper struct {
 	searchService *elastic.SearchService
@@ -260,6 +308,31 @@ func (s SearchServiceWrapper) Do(ctx context.Context) (*elastic.SearchResult, er
 	return s.searchService.Do(ctx)
 }
 
+// DeleteByQueryServiceWrapper is a wrapper around elastic.DeleteByQueryService
+type DeleteByQueryServiceWrapper struct {
+	deleteByQueryService *elastic.DeleteByQueryService
+}
+
+// WrapESDeleteByQueryService creates an ESDeleteByQueryService out of *elastic.DeleteByQueryService.
+func WrapESDeleteByQueryService(deleteByQueryService *elastic.DeleteByQueryService) DeleteByQueryServiceWrapper {
+	return DeleteByQueryServiceWrapper{deleteByQueryService: deleteByQueryService}
+}
+
+// Query calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) Query(query elastic.Query) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.Query(query))
+}
+
+// IgnoreUnavailable calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) IgnoreUnavailable(ignoreUnavailable bool) es.DeleteByQueryService {
+	return WrapESDeleteByQueryService(s.deleteByQueryService.IgnoreUnavailable(ignoreUnavailable))
+}
+
+// Do calls this function to internal service.
+func (s DeleteByQueryServiceWrapper) Do(ctx context.Context) (*elastic.BulkIndexByScrollResponse, error) {
+	return s.deleteByQueryService.Do(ctx)
+}
+
 // MultiSearchServiceWrapper is a wrapper around elastic.ESMultiSearchService
 type MultiSearchServiceWrapper struct {
 	multiSearchService *elastic.MultiSearchService
@@ -20,6 +20,7 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/metrics"
@@ -46,6 +47,7 @@ func (r *statusRecorder) WriteHeader(status int) {
 // Wrap returns a handler that wraps the provided one and emits metrics based on the HTTP requests and responses.
 // It will record the HTTP response status, HTTP method, duration and path of the call.
 // The duration will be reported in metrics.Timer and the rest will be labels on that timer.
+// If the request is traced, the ID of its trace is attached to the duration as exemplar.
 //
 // Do not use with HTTP endpoints that take parameters from URL path, such as `/user/{user_id}`,
 // because they will result in high cardinality metrics.
@@ -65,6 +67,9 @@ func Wrap(h http.Handler, metricsFactory metrics.Factory, logger *zap.Logger) ht
 			},
 			duration: time.Since(start),
 		}
+		if spanCtx := trace.SpanContextFromContext(r.Context()); spanCtx.HasTraceID() {
+			req.traceID = spanCtx.TraceID().String()
+		}
 		timers.record(req)
 	})
 }
@@ -78,6 +83,7 @@ type recordedRequestKey struct {
 type recordedRequest struct {
 	key      recordedRequestKey
 	duration time.Duration
+	traceID  string
 }
 
 type requestDurations struct {
@@ -108,7 +114,7 @@ func newRequestDurations(metricsFactory metrics.Factory, logger *zap.Logger) *re
 
 func (r *requestDurations) record(request recordedRequest) {
 	timer := r.getTimer(request.key)
-	timer.Record(request.duration)
+	metrics.RecordTimerWithExemplar(timer, request.duration, request.traceID)
 }
 
 func (r *requestDurations) getTimer(cacheKey recordedRequestKey) metrics.Timer {
@@ -40,6 +40,15 @@ func New(serviceName string) (*JTracer, error) {
 	return newHelper(serviceName, initOTEL)
 }
 
+// NewWithExporter creates a tracer exporting the spans with the given exporter.
+func NewWithExporter(serviceName string, exporter sdktrace.SpanExporter) (*JTracer, error) {
+	return newHelper(serviceName, func(ctx context.Context, svc string) (*sdktrace.TracerProvider, error) {
+		return initHelper(ctx, svc, func(context.Context) (sdktrace.SpanExporter, error) {
+			return exporter, nil
+		}, otelResource)
+	})
+}
+
 func newHelper(
 	serviceName string,
 	tracerProvider func(ctx context.Context, svc string) (*sdktrace.TracerProvider, error),
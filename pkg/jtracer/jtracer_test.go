@@ -19,9 +19,11 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 )
@@ -35,6 +37,20 @@ func TestNew(t *testing.T) {
 	jt.Close(context.Background())
 }
 
+func TestNewWithExporter(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	jt, err := NewWithExporter("serviceName", exporter)
+	require.NoError(t, err)
+
+	_, span := jt.OTEL.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+	require.NoError(t, jt.OTEL.(*sdktrace.TracerProvider).ForceFlush(context.Background()))
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "operation", spans[0].Name)
+	require.NoError(t, jt.Close(context.Background()))
+}
+
 func TestNoOp(t *testing.T) {
 	jt := NoOp()
 	require.NotNil(t, jt.OTEL)
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/Shopify/sarama"
+	gometrics "github.com/rcrowley/go-metrics"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/kafka/auth"
@@ -41,6 +42,9 @@ type Configuration struct {
 	BatchMaxMessages          int                     `mapstructure:"batch_max_messages"`
 	MaxMessageBytes           int                     `mapstructure:"max_message_bytes"`
 	auth.AuthenticationConfig `mapstructure:"authentication"`
+	// MetricRegistry, if set, is the registry of the metrics recorded by the producer,
+	// e.g. the sizes of the compressed batches and the compression ratios.
+	MetricRegistry gometrics.Registry `mapstructure:"-" json:"-"`
 }
 
 // NewProducer creates a new asynchronous kafka producer
@@ -55,6 +59,9 @@ func (c *Configuration) NewProducer(logger *zap.Logger) (sarama.AsyncProducer, e
 	saramaConfig.Producer.Flush.Messages = c.BatchMinMessages
 	saramaConfig.Producer.Flush.MaxMessages = c.BatchMaxMessages
 	saramaConfig.Producer.MaxMessageBytes = c.MaxMessageBytes
+	if c.MetricRegistry != nil {
+		saramaConfig.MetricRegistry = c.MetricRegistry
+	}
 	if len(c.ProtocolVersion) > 0 {
 		ver, err := sarama.ParseKafkaVersion(c.ProtocolVersion)
 		if err != nil {
@@ -0,0 +1,219 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lifecycle starts the components of a program in the order of their dependencies,
+// and stops them in the reverse order, e.g. the receivers before the processors and the
+// processors before the storage, so that no component uses another one already stopped.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Component is a part of a program started and stopped by a Manager.
+type Component struct {
+	// Name identifies the component in the dependencies of the other components and in the logs.
+	Name string
+	// DependsOn are the names of the components started before this component and stopped after it.
+	DependsOn []string
+	// Start starts the component, it is optional for the components started when they are created.
+	Start func(ctx context.Context) error
+	// Stop drains and stops the component, it is optional. It should return once the context is done.
+	Stop func(ctx context.Context) error
+}
+
+// Closer returns a Stop function closing the closer, e.g. a storage factory.
+func Closer(closer io.Closer) func(ctx context.Context) error {
+	return func(context.Context) error {
+		return closer.Close()
+	}
+}
+
+// Manager starts the components in the order of their dependencies and stops them in the reverse order.
+type Manager struct {
+	logger *zap.Logger
+	// stopTimeout is the maximum time given to each component to stop, unlimited if 0
+	stopTimeout time.Duration
+
+	lock       sync.Mutex
+	components []Component
+	// started are the components started, in their start order
+	started []Component
+}
+
+// NewManager creates a Manager giving each component up to stopTimeout to stop.
+func NewManager(logger *zap.Logger, stopTimeout time.Duration) *Manager {
+	return &Manager{
+		logger:      logger,
+		stopTimeout: stopTimeout,
+	}
+}
+
+// StopTimeout returns the maximum time given to each component to stop, unlimited if 0.
+func (m *Manager) StopTimeout() time.Duration {
+	return m.stopTimeout
+}
+
+// Add registers a component. The components are started in the order they are added,
+// after the components they depend on.
+func (m *Manager) Add(component Component) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.components = append(m.components, component)
+}
+
+// Start starts the added components that are not started yet in the order of their dependencies.
+// If a component fails to start, the components already started are stopped and the error is returned.
+func (m *Manager) Start(ctx context.Context) error {
+	m.lock.Lock()
+	order, err := m.startOrder()
+	m.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, component := range order {
+		if component.Start != nil {
+			m.logger.Info("Starting component", zap.String("component", component.Name))
+			if err := component.Start(ctx); err != nil {
+				err = fmt.Errorf("failed to start %s: %w", component.Name, err)
+				return errors.Join(err, m.Stop())
+			}
+		}
+		m.lock.Lock()
+		m.started = append(m.started, component)
+		m.lock.Unlock()
+	}
+	return nil
+}
+
+// startOrder sorts the components not started yet after their dependencies, and otherwise in the order
+// they were added. It fails if a dependency is not registered or if the dependencies form a cycle.
+func (m *Manager) startOrder() ([]Component, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	byName := make(map[string]Component, len(m.components))
+	for _, component := range m.components {
+		if _, ok := byName[component.Name]; ok {
+			return nil, fmt.Errorf("duplicate component %s", component.Name)
+		}
+		byName[component.Name] = component
+	}
+	state := make(map[string]int, len(m.components))
+	for _, component := range m.started {
+		state[component.Name] = visited
+	}
+	var order []Component
+	var visit func(component Component, path []string) error
+	visit = func(component Component, path []string) error {
+		switch state[component.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle between the components %v", append(path, component.Name))
+		}
+		state[component.Name] = visiting
+		for _, name := range component.DependsOn {
+			dependency, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("component %s depends on the unknown component %s", component.Name, name)
+			}
+			if err := visit(dependency, append(path, component.Name)); err != nil {
+				return err
+			}
+		}
+		state[component.Name] = visited
+		order = append(order, component)
+		return nil
+	}
+	for _, component := range m.components {
+		if err := visit(component, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Stop stops the started components in the reverse order of their start, waiting for each one
+// up to the stop timeout. A component that does not stop in time keeps stopping in the background,
+// so the components it depends on, directly or not, are left running for it. The errors are logged
+// and returned together.
+func (m *Manager) Stop() error {
+	m.lock.Lock()
+	started := m.started
+	m.started = nil
+	m.lock.Unlock()
+
+	// running holds the components still running or stopping in the background,
+	// with the name of the component they are left running for, if any
+	running := make(map[string]string)
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		component := started[i]
+		if dependent, ok := m.runningDependent(component, started[i+1:], running); ok {
+			running[component.Name] = dependent
+			err := fmt.Errorf("not stopped, as %s is still stopping", dependent)
+			m.logger.Error("Failed to stop component", zap.String("component", component.Name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name, err))
+			continue
+		}
+		if component.Stop == nil {
+			continue
+		}
+		m.logger.Info("Stopping component", zap.String("component", component.Name))
+		if stopped, err := m.stop(component); err != nil {
+			if !stopped {
+				running[component.Name] = component.Name
+			}
+			m.logger.Error("Failed to stop component", zap.String("component", component.Name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runningDependent returns the component still stopping in the background that the component
+// is left running for, if one of the components depending on it is still running.
+func (*Manager) runningDependent(component Component, dependents []Component, running map[string]string) (string, bool) {
+	for _, dependent := range dependents {
+		stopping, ok := running[dependent.Name]
+		if !ok {
+			continue
+		}
+		for _, name := range dependent.DependsOn {
+			if name == component.Name {
+				return stopping, true
+			}
+		}
+	}
+	return "", false
+}
+
+// stop stops the component, it returns false if the component is still stopping after the stop timeout.
+func (m *Manager) stop(component Component) (bool, error) {
+	ctx := context.Background()
+	if m.stopTimeout <= 0 {
+		return true, component.Stop(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.stopTimeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- component.Stop(ctx)
+	}()
+	select {
+	case err := <-done:
+		return true, err
+	case <-ctx.Done():
+		// the component keeps stopping in the background
+		return false, fmt.Errorf("not stopped within %v: %w", m.stopTimeout, ctx.Err())
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recorder records the start and stop of the components.
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) component(name string, dependsOn ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start: func(context.Context) error {
+			r.events = append(r.events, "start "+name)
+			return nil
+		},
+		Stop: func(context.Context) error {
+			r.events = append(r.events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestManagerOrder(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(zap.NewNop(), time.Second)
+	m.Add(r.component("receiver", "processor"))
+	m.Add(r.component("processor", "storage"))
+	m.Add(r.component("query", "storage"))
+	m.Add(r.component("storage"))
+	m.Add(Component{Name: "static"})
+
+	require.NoError(t, m.Start(context.Background()))
+	assert.Equal(t, []string{"start storage", "start processor", "start receiver", "start query"}, r.events)
+
+	// the components added later are started on the next start
+	m.Add(r.component("agent", "receiver"))
+	require.NoError(t, m.Start(context.Background()))
+	assert.Equal(t, "start agent", r.events[len(r.events)-1])
+
+	r.events = nil
+	require.NoError(t, m.Stop())
+	assert.Equal(t, []string{"stop agent", "stop query", "stop receiver", "stop processor", "stop storage"}, r.events)
+
+	// the components are stopped once
+	r.events = nil
+	require.NoError(t, m.Stop())
+	assert.Empty(t, r.events)
+}
+
+func TestManagerInvalidDependencies(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Component
+		err        string
+	}{
+		{
+			name:       "unknown",
+			components: []Component{{Name: "receiver", DependsOn: []string{"storage"}}},
+			err:        "component receiver depends on the unknown component storage",
+		},
+		{
+			name: "cycle",
+			components: []Component{
+				{Name: "receiver", DependsOn: []string{"processor"}},
+				{Name: "processor", DependsOn: []string{"receiver"}},
+			},
+			err: "dependency cycle between the components [receiver processor receiver]",
+		},
+		{
+			name:       "duplicate",
+			components: []Component{{Name: "storage"}, {Name: "storage"}},
+			err:        "duplicate component storage",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewManager(zap.NewNop(), 0)
+			for _, component := range test.components {
+				m.Add(component)
+			}
+			require.EqualError(t, m.Start(context.Background()), test.err)
+		})
+	}
+}
+
+func TestManagerStartError(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(zap.NewNop(), 0)
+	m.Add(r.component("storage"))
+	m.Add(Component{
+		Name:      "receiver",
+		DependsOn: []string{"storage"},
+		Start: func(context.Context) error {
+			return errors.New("port in use")
+		},
+	})
+
+	err := m.Start(context.Background())
+	require.EqualError(t, err, "failed to start receiver: port in use")
+	assert.Equal(t, []string{"start storage", "stop storage"}, r.events)
+}
+
+func TestManagerStopErrors(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(zap.NewNop(), time.Second)
+	m.Add(r.component("storage"))
+	m.Add(Component{
+		Name:      "processor",
+		DependsOn: []string{"storage"},
+		Stop: func(context.Context) error {
+			return errors.New("queue not drained")
+		},
+	})
+	require.NoError(t, m.Start(context.Background()))
+
+	err := m.Stop()
+	require.EqualError(t, err, "failed to stop processor: queue not drained")
+	// the processor returned, so the storage is stopped anyway
+	assert.Equal(t, []string{"start storage", "stop storage"}, r.events)
+}
+
+func TestManagerStopTimeout(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(zap.NewNop(), 10*time.Millisecond)
+	m.Add(r.component("storage"))
+	m.Add(r.component("processor", "storage"))
+	m.Add(r.component("query", "storage"))
+	m.Add(r.component("tracer"))
+	release := make(chan struct{})
+	stopped := make(chan struct{})
+	m.Add(Component{
+		Name:      "receiver",
+		DependsOn: []string{"processor"},
+		Stop: func(ctx context.Context) error {
+			defer close(stopped)
+			// the receiver overruns its stop timeout, still using the processor
+			<-release
+			return ctx.Err()
+		},
+	})
+	require.NoError(t, m.Start(context.Background()))
+	assert.Equal(t, 10*time.Millisecond, m.StopTimeout())
+	r.events = nil
+
+	err := m.Stop()
+	require.ErrorContains(t, err, "failed to stop receiver: not stopped within 10ms: context deadline exceeded")
+	require.ErrorContains(t, err, "failed to stop processor: not stopped, as receiver is still stopping")
+	require.ErrorContains(t, err, "failed to stop storage: not stopped, as receiver is still stopping")
+	// the components the receiver depends on are left running, the other ones are stopped
+	assert.Equal(t, []string{"stop tracer", "stop query"}, r.events)
+	close(release)
+	<-stopped
+}
+
+func TestCloser(t *testing.T) {
+	m := NewManager(zap.NewNop(), 0)
+	closer := &testCloser{}
+	m.Add(Component{Name: "storage", Stop: Closer(closer)})
+	require.NoError(t, m.Start(context.Background()))
+	require.NoError(t, m.Stop())
+	assert.True(t, closer.closed)
+}
+
+type testCloser struct {
+	closed bool
+}
+
+func (c *testCloser) Close() error {
+	c.closed = true
+	return nil
+}
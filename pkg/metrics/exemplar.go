@@ -0,0 +1,59 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"time"
+)
+
+// CounterWithExemplar is an optional interface of the Counters able to attach the ID
+// of a trace to an increment, as an exemplar of the counted events.
+type CounterWithExemplar interface {
+	// IncWithExemplar adds the given value to the counter, with the trace ID as exemplar.
+	IncWithExemplar(delta int64, traceID string)
+}
+
+// TimerWithExemplar is an optional interface of the Timers able to attach the ID
+// of a trace to an observation, as an exemplar of the timed operations.
+type TimerWithExemplar interface {
+	// RecordWithExemplar records the time passed in, with the trace ID as exemplar.
+	RecordWithExemplar(d time.Duration, traceID string)
+}
+
+// HistogramWithExemplar is an optional interface of the Histograms able to attach the ID
+// of a trace to an observation, as an exemplar of the distribution.
+type HistogramWithExemplar interface {
+	// RecordWithExemplar records the value passed in, with the trace ID as exemplar.
+	RecordWithExemplar(v float64, traceID string)
+}
+
+// IncCounterWithExemplar increments the counter with the trace ID as exemplar if the counter
+// supports exemplars and the trace ID is not empty, otherwise it just increments the counter.
+func IncCounterWithExemplar(counter Counter, delta int64, traceID string) {
+	if c, ok := counter.(CounterWithExemplar); ok && traceID != "" {
+		c.IncWithExemplar(delta, traceID)
+		return
+	}
+	counter.Inc(delta)
+}
+
+// RecordTimerWithExemplar records the duration with the trace ID as exemplar if the timer
+// supports exemplars and the trace ID is not empty, otherwise it just records the duration.
+func RecordTimerWithExemplar(timer Timer, d time.Duration, traceID string) {
+	if t, ok := timer.(TimerWithExemplar); ok && traceID != "" {
+		t.RecordWithExemplar(d, traceID)
+		return
+	}
+	timer.Record(d)
+}
+
+// RecordHistogramWithExemplar records the value with the trace ID as exemplar if the histogram
+// supports exemplars and the trace ID is not empty, otherwise it just records the value.
+func RecordHistogramWithExemplar(histogram Histogram, v float64, traceID string) {
+	if h, ok := histogram.(HistogramWithExemplar); ok && traceID != "" {
+		h.RecordWithExemplar(v, traceID)
+		return
+	}
+	histogram.Record(v)
+}
@@ -69,6 +69,33 @@ func TestInitMetrics(t *testing.T) {
 	assert.Greater(t, stopwatch.ElapsedTime(), time.Duration(0))
 }
 
+type exemplarTimer struct {
+	metrics.Timer
+	traceIDs []string
+}
+
+func (t *exemplarTimer) RecordWithExemplar(d time.Duration, traceID string) {
+	t.Record(d)
+	t.traceIDs = append(t.traceIDs, traceID)
+}
+
+func TestWithExemplar(t *testing.T) {
+	f := metricstest.NewFactory(0)
+	defer f.Stop()
+
+	counter := f.Counter(metrics.Options{Name: "counter"})
+	histogram := f.Histogram(metrics.HistogramOptions{Name: "histogram"})
+	// the metrics not supporting exemplars are recorded without them
+	metrics.IncCounterWithExemplar(counter, 3, "abc")
+	metrics.RecordHistogramWithExemplar(histogram, 42, "abc")
+	f.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "counter", Value: 3})
+
+	timer := &exemplarTimer{Timer: f.Timer(metrics.TimerOptions{Name: "timer"})}
+	metrics.RecordTimerWithExemplar(timer, time.Second, "abc")
+	metrics.RecordTimerWithExemplar(timer, time.Second, "")
+	assert.Equal(t, []string{"abc"}, timer.traceIDs)
+}
+
 var (
 	noMetricTag = struct {
 		NoMetricTag metrics.Counter
@@ -0,0 +1,79 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package netutils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// AddressFamily is the IP version of the addresses a server listens on.
+type AddressFamily string
+
+const (
+	// DualStack listens on IPv4 and IPv6 addresses. With an empty host (e.g. ":16686")
+	// or the IPv6 unspecified address (e.g. "[::]:16686") a single socket accepts both.
+	DualStack AddressFamily = "dual"
+	// IPv4 listens on IPv4 addresses only.
+	IPv4 AddressFamily = "ipv4"
+	// IPv6 listens on IPv6 addresses only.
+	IPv6 AddressFamily = "ipv6"
+)
+
+// AddressFamilyFlagUsage is the usage of the flags setting the address family of a server, formatted with the server name.
+const AddressFamilyFlagUsage = "The address family of the %s listener: dual (IPv4 and IPv6), ipv4 or ipv6"
+
+// ParseAddressFamily returns the address family of a flag value, DualStack if the value is empty.
+func ParseAddressFamily(value string) (AddressFamily, error) {
+	switch family := AddressFamily(strings.ToLower(value)); family {
+	case "":
+		return DualStack, nil
+	case DualStack, IPv4, IPv6:
+		return family, nil
+	default:
+		return "", fmt.Errorf("invalid address family %q, expecting one of dual, ipv4, ipv6", value)
+	}
+}
+
+// Network returns the name of the network of the protocol (tcp or udp) restricted to the address family,
+// as expected by net.Listen.
+func (f AddressFamily) Network(protocol string) string {
+	switch f {
+	case IPv4:
+		return protocol + "4"
+	case IPv6:
+		return protocol + "6"
+	default:
+		return protocol
+	}
+}
+
+// ValidateHostPort checks that the host:port address can be listened on,
+// with a hint when an IPv6 literal is not enclosed in brackets.
+// An empty address is valid, the listener is bound to a random port.
+func ValidateHostPort(hostPort string) error {
+	if hostPort == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(hostPort); err != nil {
+		if strings.Count(hostPort, ":") > 1 && !strings.HasPrefix(hostPort, "[") {
+			return fmt.Errorf("invalid address %q: IPv6 literals must be enclosed in brackets, e.g. [::1]:16686", hostPort)
+		}
+		return fmt.Errorf("invalid address %q: %w", hostPort, err)
+	}
+	return nil
+}
+
+// Listen announces on the TCP host:port address for the address family,
+// or on the unix domain socket of a unix:///path endpoint.
+func Listen(family AddressFamily, hostPort string) (net.Listener, error) {
+	if path, ok := UnixSocketPath(hostPort); ok {
+		return ListenUnix(path)
+	}
+	if err := ValidateHostPort(hostPort); err != nil {
+		return nil, err
+	}
+	return net.Listen(family.Network("tcp"), hostPort)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package netutils
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddressFamily(t *testing.T) {
+	tests := []struct {
+		value  string
+		family AddressFamily
+	}{
+		{value: "", family: DualStack},
+		{value: "dual", family: DualStack},
+		{value: "ipv4", family: IPv4},
+		{value: "IPv6", family: IPv6},
+	}
+	for _, test := range tests {
+		family, err := ParseAddressFamily(test.value)
+		require.NoError(t, err)
+		assert.Equal(t, test.family, family)
+	}
+	_, err := ParseAddressFamily("ipx")
+	require.ErrorContains(t, err, `invalid address family "ipx"`)
+}
+
+func TestAddressFamilyNetwork(t *testing.T) {
+	assert.Equal(t, "tcp", DualStack.Network("tcp"))
+	assert.Equal(t, "udp4", IPv4.Network("udp"))
+	assert.Equal(t, "tcp6", IPv6.Network("tcp"))
+}
+
+func TestValidateHostPort(t *testing.T) {
+	for _, hostPort := range []string{"", ":16686", "localhost:16686", "0.0.0.0:16686", "[::]:16686", "[::1]:16686"} {
+		require.NoError(t, ValidateHostPort(hostPort), hostPort)
+	}
+	require.ErrorContains(t, ValidateHostPort("::1:16686"), "IPv6 literals must be enclosed in brackets")
+	require.ErrorContains(t, ValidateHostPort("localhost"), `invalid address "localhost"`)
+}
+
+func TestListen(t *testing.T) {
+	tests := []struct {
+		family   AddressFamily
+		hostPort string
+		dial     []string
+	}{
+		{family: DualStack, hostPort: ":0", dial: []string{"127.0.0.1", "::1"}},
+		{family: IPv4, hostPort: "127.0.0.1:0", dial: []string{"127.0.0.1"}},
+		{family: IPv6, hostPort: "[::1]:0", dial: []string{"::1"}},
+	}
+	for _, test := range tests {
+		t.Run(string(test.family), func(t *testing.T) {
+			l, err := Listen(test.family, test.hostPort)
+			if err != nil && test.family != IPv4 {
+				t.Skipf("IPv6 is not available: %v", err)
+			}
+			require.NoError(t, err)
+			defer l.Close()
+			port := l.Addr().(*net.TCPAddr).Port
+			for _, host := range test.dial {
+				conn, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+				require.NoError(t, err, host)
+				conn.Close()
+			}
+		})
+	}
+
+	_, err := Listen(DualStack, "::1:0")
+	require.ErrorContains(t, err, "IPv6 literals must be enclosed in brackets")
+	_, err = Listen(IPv4, "[::1]:0")
+	require.Error(t, err)
+}
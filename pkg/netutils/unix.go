@@ -0,0 +1,79 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package netutils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnixSocketPath returns the path of a unix domain socket endpoint, written like the gRPC targets
+// unix:///absolute/path or unix:relative/path, and false if the endpoint is a host:port address.
+func UnixSocketPath(endpoint string) (string, bool) {
+	path, ok := strings.CutPrefix(endpoint, "unix:")
+	if !ok {
+		return "", false
+	}
+	return strings.TrimPrefix(path, "//"), true
+}
+
+// ParseSocketPermissions parses the octal permissions of a unix domain socket file, e.g. 0660.
+// The permissions are 0, i.e. left to the umask, if the value is empty.
+func ParseSocketPermissions(value string) (os.FileMode, error) {
+	if value == "" {
+		return 0, nil
+	}
+	perm, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || perm > 0o777 {
+		return 0, fmt.Errorf("invalid unix socket permissions %q, expecting octal permissions such as 0660", value)
+	}
+	return os.FileMode(perm), nil
+}
+
+// ListenUnix creates a unix domain socket listener, removing the socket file left
+// by a previous process that did not shut down cleanly.
+func ListenUnix(path string) (net.Listener, error) {
+	if err := RemoveStaleSocket(path); err != nil {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// RemoveStaleSocket removes the socket file at path if no process accepts connections on it,
+// so that a new socket can be created at the same path.
+func RemoveStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("cannot listen on %s: the file exists and is not a unix socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("cannot listen on %s: the socket is in use", path)
+	}
+	return os.Remove(path)
+}
+
+// ChmodSocket sets the permissions of the socket file of a unix domain socket listener.
+// It does nothing for other listeners or if the permissions are 0.
+func ChmodSocket(l net.Listener, perm os.FileMode) error {
+	addr, ok := l.Addr().(*net.UnixAddr)
+	if !ok || perm == 0 {
+		return nil
+	}
+	if err := os.Chmod(addr.Name, perm); err != nil {
+		return fmt.Errorf("cannot set the permissions of the unix socket: %w", err)
+	}
+	return nil
+}
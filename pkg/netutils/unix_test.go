@@ -0,0 +1,93 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package netutils
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixSocketPath(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		path     string
+		ok       bool
+	}{
+		{endpoint: "unix:///var/run/jaeger.sock", path: "/var/run/jaeger.sock", ok: true},
+		{endpoint: "unix:jaeger.sock", path: "jaeger.sock", ok: true},
+		{endpoint: "localhost:17271"},
+		{endpoint: "[::1]:17271"},
+	}
+	for _, test := range tests {
+		path, ok := UnixSocketPath(test.endpoint)
+		assert.Equal(t, test.ok, ok, test.endpoint)
+		assert.Equal(t, test.path, path, test.endpoint)
+	}
+}
+
+func TestParseSocketPermissions(t *testing.T) {
+	perm, err := ParseSocketPermissions("0660")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o660), perm)
+
+	perm, err = ParseSocketPermissions("")
+	require.NoError(t, err)
+	assert.Zero(t, perm)
+
+	for _, value := range []string{"rw", "0999", "01777"} {
+		_, err = ParseSocketPermissions(value)
+		require.ErrorContains(t, err, "invalid unix socket permissions", value)
+	}
+}
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jaeger.sock")
+	l, err := Listen(DualStack, "unix://"+path)
+	require.NoError(t, err)
+	require.NoError(t, ChmodSocket(l, 0o600))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	conn.Close()
+
+	// the socket is in use
+	_, err = ListenUnix(path)
+	require.ErrorContains(t, err, "the socket is in use")
+	require.NoError(t, l.Close())
+}
+
+func TestListenUnixStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jaeger.sock")
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	// leave the socket file behind like a process that crashed
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, l.Close())
+
+	l, err = ListenUnix(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+}
+
+func TestListenUnixNotSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jaeger.sock")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	_, err := ListenUnix(path)
+	require.ErrorContains(t, err, "the file exists and is not a unix socket")
+}
+
+func TestChmodSocketTCP(t *testing.T) {
+	l, err := Listen(IPv4, "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	require.NoError(t, ChmodSocket(l, 0o600))
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+)
+
+// Claims are the claims of a verified token.
+type Claims map[string]interface{}
+
+// String returns the value of a string claim, or empty string if the claim is missing or not a string.
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Contains returns true if the claim equals the value, or is an array that contains the value.
+func (c Claims) Contains(name, value string) bool {
+	switch claim := c[name].(type) {
+	case string:
+		return claim == value
+	case []interface{}:
+		for _, v := range claim {
+			if fmt.Sprint(v) == value {
+				return true
+			}
+		}
+	case nil:
+		return false
+	default:
+		return fmt.Sprint(claim) == value
+	}
+	return false
+}
+
+type claimsKeyType struct{}
+
+var claimsKey = claimsKeyType{}
+
+// ContextWithClaims sets the claims of the authenticated user in the context.
+func ContextWithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// GetClaims returns the claims of the authenticated user, or nil if the request was not authenticated.
+func GetClaims(ctx context.Context) Claims {
+	claims, _ := ctx.Value(claimsKey).(Claims)
+	return claims
+}
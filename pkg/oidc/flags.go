@@ -0,0 +1,110 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	flagPrefix         = "query.oidc"
+	flagEnabled        = flagPrefix + ".enabled"
+	flagIssuerURL      = flagPrefix + ".issuer-url"
+	flagClientID       = flagPrefix + ".client-id"
+	flagClientSecret   = flagPrefix + ".client-secret"
+	flagAudience       = flagPrefix + ".audience"
+	flagRequiredClaims = flagPrefix + ".required-claims"
+	flagRedirectURL    = flagPrefix + ".redirect-url"
+	flagScopes         = flagPrefix + ".scopes"
+	flagRoleClaim      = flagPrefix + ".role-claim"
+	flagTenantClaim    = flagPrefix + ".tenant-claim"
+)
+
+// Options describes the configuration of OIDC authentication.
+type Options struct {
+	Enabled bool
+	// IssuerURL is the URL of the OpenID provider, used for discovery and to validate the iss claim.
+	IssuerURL string
+	// ClientID is the client registered with the provider and an accepted token audience.
+	ClientID string
+	// ClientSecret authenticates the client when exchanging the authorization code during UI login.
+	ClientSecret string
+	// Audience is an additional accepted token audience, typically for API access tokens.
+	Audience string
+	// RequiredClaims lists the claims and values that tokens must contain.
+	RequiredClaims map[string]string
+	// RedirectURL is the external URL of the UI login callback; UI login is disabled if it is empty.
+	RedirectURL string
+	// Scopes are requested during UI login.
+	Scopes []string
+	// RoleClaim is the claim holding the role of the user, if any.
+	RoleClaim string
+	// TenantClaim is the claim holding the tenant of the user, if any.
+	TenantClaim string
+	// PropagateToken stores the validated token in the request context for propagation to the storage,
+	// it is set from the bearer token propagation option of the query service.
+	PropagateToken bool
+}
+
+// AddFlags adds flags for OIDC authentication to the FlagSet.
+func AddFlags(flags *flag.FlagSet) {
+	flags.Bool(flagEnabled, false, "Require requests to the UI and the API to be authenticated with OpenID Connect tokens")
+	flags.String(flagIssuerURL, "", "The URL of the OpenID Connect provider, e.g. https://accounts.example.com")
+	flags.String(flagClientID, "", "The OAuth2 client ID of jaeger-query; tokens issued to this client are accepted")
+	flags.String(flagClientSecret, "", "The OAuth2 client secret, used to complete the UI login")
+	flags.String(flagAudience, "", "An additional token audience accepted from API clients")
+	flags.String(flagRequiredClaims, "", "Comma-separated list of claim=value pairs that tokens must contain, e.g. groups=jaeger-users")
+	flags.String(flagRedirectURL, "", "The external URL of the UI login callback, e.g. https://jaeger.example.com/oidc/callback; if empty, only bearer tokens are accepted")
+	flags.String(flagScopes, "openid,profile,email", "Comma-separated list of scopes requested during UI login")
+	flags.String(flagRoleClaim, "", "The token claim holding the role of the user, used by tag masking rules")
+	flags.String(flagTenantClaim, "", "The token claim holding the tenant of the user; with multi-tenancy enabled, requests are restricted to this tenant")
+}
+
+// InitFromViper creates oidc.Options populated with values retrieved from Viper.
+func InitFromViper(v *viper.Viper) (Options, error) {
+	var p Options
+	p.Enabled = v.GetBool(flagEnabled)
+	p.IssuerURL = v.GetString(flagIssuerURL)
+	p.ClientID = v.GetString(flagClientID)
+	p.ClientSecret = v.GetString(flagClientSecret)
+	p.Audience = v.GetString(flagAudience)
+	p.RedirectURL = v.GetString(flagRedirectURL)
+	p.RoleClaim = v.GetString(flagRoleClaim)
+	p.TenantClaim = v.GetString(flagTenantClaim)
+	if scopes := v.GetString(flagScopes); scopes != "" {
+		p.Scopes = strings.Split(scopes, ",")
+	}
+	if claims := v.GetString(flagRequiredClaims); claims != "" {
+		p.RequiredClaims = make(map[string]string)
+		for _, pair := range strings.Split(claims, ",") {
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok || name == "" {
+				return p, fmt.Errorf("invalid required claim %q, expecting claim=value", pair)
+			}
+			p.RequiredClaims[name] = value
+		}
+	}
+	if p.Enabled {
+		if err := p.Validate(); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// Validate checks that the options needed by enabled OIDC authentication are set.
+func (p *Options) Validate() error {
+	if p.IssuerURL == "" {
+		return errors.New("OIDC issuer URL is required")
+	}
+	if p.ClientID == "" {
+		return errors.New("OIDC client ID is required")
+	}
+	return nil
+}
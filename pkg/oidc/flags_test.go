@@ -0,0 +1,73 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsFromFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--query.oidc.enabled=true",
+		"--query.oidc.issuer-url=https://accounts.example.com",
+		"--query.oidc.client-id=jaeger",
+		"--query.oidc.client-secret=secret",
+		"--query.oidc.audience=jaeger-api",
+		"--query.oidc.required-claims=groups=jaeger-users,email_verified=true",
+		"--query.oidc.redirect-url=https://jaeger.example.com/oidc/callback",
+		"--query.oidc.role-claim=role",
+		"--query.oidc.tenant-claim=org",
+	})
+	options, err := InitFromViper(v)
+	require.NoError(t, err)
+	assert.Equal(t, Options{
+		Enabled:        true,
+		IssuerURL:      "https://accounts.example.com",
+		ClientID:       "jaeger",
+		ClientSecret:   "secret",
+		Audience:       "jaeger-api",
+		RequiredClaims: map[string]string{"groups": "jaeger-users", "email_verified": "true"},
+		RedirectURL:    "https://jaeger.example.com/oidc/callback",
+		Scopes:         []string{"openid", "profile", "email"},
+		RoleClaim:      "role",
+		TenantClaim:    "org",
+	}, options)
+}
+
+func TestOptionsFromFlagsErrors(t *testing.T) {
+	tests := []struct {
+		flags []string
+		err   string
+	}{
+		{[]string{"--query.oidc.required-claims=groups"}, `invalid required claim "groups"`},
+		{[]string{"--query.oidc.enabled=true"}, "OIDC issuer URL is required"},
+		{[]string{"--query.oidc.enabled=true", "--query.oidc.issuer-url=https://accounts.example.com"}, "OIDC client ID is required"},
+	}
+	for _, test := range tests {
+		v, command := config.Viperize(AddFlags)
+		command.ParseFlags(test.flags)
+		_, err := InitFromViper(v)
+		require.ErrorContains(t, err, test.err)
+	}
+}
+
+func TestClaims(t *testing.T) {
+	claims := Claims{
+		"sub":      "alice",
+		"groups":   []interface{}{"a", "b"},
+		"verified": true,
+	}
+	assert.Equal(t, "alice", claims.String("sub"))
+	assert.Empty(t, claims.String("groups"))
+	assert.True(t, claims.Contains("groups", "b"))
+	assert.False(t, claims.Contains("groups", "c"))
+	assert.True(t, claims.Contains("verified", "true"))
+	assert.False(t, claims.Contains("missing", ""))
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/bearertoken"
+)
+
+// authenticatedServerStream is a wrapper for ServerStream providing the authenticated context
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	context context.Context
+}
+
+func (s *authenticatedServerStream) Context() context.Context {
+	return s.context
+}
+
+func authenticate(ctx context.Context, verifier *Verifier) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	claims, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	ctx = ContextWithClaims(ctx, claims)
+	if verifier.options.PropagateToken {
+		ctx = bearertoken.ContextWithBearerToken(ctx, token)
+	}
+	return ctx, nil
+}
+
+// NewUnaryServerInterceptor rejects RPCs without a valid bearer token in the authorization metadata.
+func NewUnaryServerInterceptor(verifier *Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, verifier)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// NewStreamServerInterceptor rejects streams without a valid bearer token in the authorization metadata.
+func NewStreamServerInterceptor(verifier *Verifier) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), verifier)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedServerStream{ServerStream: ss, context: ctx})
+	}
+}
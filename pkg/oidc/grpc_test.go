@@ -0,0 +1,76 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/bearertoken"
+)
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	p := newTestProvider(t)
+	options := p.options()
+	options.PropagateToken = true
+	interceptor := NewUnaryServerInterceptor(NewVerifier(options, p.server.Client()))
+	token := p.sign(t, "RS256", "rsa", p.claims())
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		propagated, _ := bearertoken.GetBearerToken(ctx)
+		return GetClaims(ctx).String("sub") + " " + propagated, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	res, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "alice "+token, res)
+
+	// the token is not propagated unless enabled
+	res, err = NewUnaryServerInterceptor(NewVerifier(p.options(), p.server.Client()))(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "alice ", res)
+
+	for _, md := range []metadata.MD{
+		nil,
+		metadata.Pairs("authorization", token),
+		metadata.Pairs("authorization", "Bearer invalid"),
+	} {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	p := newTestProvider(t)
+	interceptor := NewStreamServerInterceptor(NewVerifier(p.options(), p.server.Client()))
+	var sub string
+	handler := func(_ interface{}, ss grpc.ServerStream) error {
+		sub = GetClaims(ss.Context()).String("sub")
+		return nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+p.sign(t, "ES256", "ec", p.claims())))
+	require.NoError(t, interceptor(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, handler))
+	assert.Equal(t, "alice", sub)
+
+	err := interceptor(nil, &testServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/bearertoken"
+)
+
+const (
+	// TokenCookie holds the ID token of the users logged in to the UI.
+	TokenCookie = "jaeger-oidc-token"
+	// stateCookie holds the state of a pending UI login and the path to return to.
+	stateCookie = "jaeger-oidc-state"
+
+	stateCookieMaxAge = 10 * time.Minute
+)
+
+// Authenticator authenticates HTTP requests with bearer tokens, or with the token obtained
+// when logging in to the UI with the authorization code flow.
+type Authenticator struct {
+	options      Options
+	verifier     *Verifier
+	logger       *zap.Logger
+	callbackPath string
+}
+
+// NewAuthenticator creates an Authenticator.
+func NewAuthenticator(options Options, verifier *Verifier, logger *zap.Logger) (*Authenticator, error) {
+	a := &Authenticator{
+		options:  options,
+		verifier: verifier,
+		logger:   logger,
+	}
+	if options.RedirectURL != "" {
+		redirectURL, err := url.Parse(options.RedirectURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OIDC redirect URL: %w", err)
+		}
+		a.callbackPath = redirectURL.Path
+	}
+	return a, nil
+}
+
+// Handler returns a http.Handler that only passes authenticated requests to h, with the claims
+// of the user in the request context, see GetClaims. If PropagateToken is set, the raw token is also
+// stored in the context for propagation to the storage, see bearertoken.GetBearerToken. Browsers are redirected to the
+// provider login page if UI login is enabled.
+func (a *Authenticator) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.callbackPath != "" && r.URL.Path == a.callbackPath {
+			a.callback(w, r)
+			return
+		}
+		token := tokenFromRequest(r)
+		if token == "" {
+			a.unauthenticated(w, r, errors.New("missing bearer token"))
+			return
+		}
+		claims, err := a.verifier.Verify(r.Context(), token)
+		if err != nil {
+			a.unauthenticated(w, r, err)
+			return
+		}
+		ctx := ContextWithClaims(r.Context(), claims)
+		if a.verifier.options.PropagateToken {
+			ctx = bearertoken.ContextWithBearerToken(ctx, token)
+		}
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func tokenFromRequest(r *http.Request) string {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return token
+	}
+	if cookie, err := r.Cookie(TokenCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+func (a *Authenticator) unauthenticated(w http.ResponseWriter, r *http.Request, err error) {
+	if a.callbackPath != "" && r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html") {
+		loginErr := a.login(w, r)
+		if loginErr == nil {
+			return
+		}
+		a.logger.Error("Cannot start OIDC login", zap.Error(loginErr))
+	}
+	a.logger.Debug("Unauthenticated request", zap.String("path", r.URL.Path), zap.Error(err))
+	w.Header().Set("WWW-Authenticate", `Bearer realm="jaeger"`)
+	http.Error(w, "unauthenticated: "+err.Error(), http.StatusUnauthorized)
+}
+
+// login redirects the browser to the authorization endpoint of the provider.
+func (a *Authenticator) login(w http.ResponseWriter, r *http.Request) error {
+	provider, err := a.verifier.getProvider(r.Context())
+	if err != nil {
+		return err
+	}
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return err
+	}
+	state := hex.EncodeToString(nonce[:])
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    state + ":" + r.URL.RequestURI(),
+		Path:     a.callbackPath,
+		MaxAge:   int(stateCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {a.options.ClientID},
+		"redirect_uri":  {a.options.RedirectURL},
+		"scope":         {strings.Join(a.options.Scopes, " ")},
+		"state":         {state},
+	}
+	http.Redirect(w, r, provider.Endpoint().AuthURL+"?"+query.Encode(), http.StatusFound)
+	return nil
+}
+
+// callback completes the UI login by exchanging the authorization code for an ID token,
+// which is stored in a cookie.
+func (a *Authenticator) callback(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(stateCookie)
+	if err != nil {
+		http.Error(w, "missing login state", http.StatusBadRequest)
+		return
+	}
+	state, returnPath, _ := strings.Cut(cookie.Value, ":")
+	if state == "" || r.FormValue("state") != state {
+		http.Error(w, "invalid login state", http.StatusBadRequest)
+		return
+	}
+	if errCode := r.FormValue("error"); errCode != "" {
+		http.Error(w, "login failed: "+errCode, http.StatusUnauthorized)
+		return
+	}
+	token, err := a.exchangeCode(r, r.FormValue("code"))
+	if err != nil {
+		a.logger.Error("Cannot complete OIDC login", zap.Error(err))
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+	claims, err := a.verifier.Verify(r.Context(), token)
+	if err != nil {
+		http.Error(w, "login failed: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	exp, _ := claims["exp"].(float64)
+	http.SetCookie(w, &http.Cookie{
+		Name:     TokenCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  time.Unix(int64(exp), 0),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.SetCookie(w, &http.Cookie{Name: stateCookie, Path: a.callbackPath, MaxAge: -1})
+	// only redirect within this server
+	if !strings.HasPrefix(returnPath, "/") || strings.HasPrefix(returnPath, "//") {
+		returnPath = "/"
+	}
+	http.Redirect(w, r, returnPath, http.StatusFound)
+}
+
+func (a *Authenticator) exchangeCode(r *http.Request, code string) (string, error) {
+	provider, err := a.verifier.getProvider(r.Context())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {a.options.RedirectURL},
+		"client_id":     {a.options.ClientID},
+		"client_secret": {a.options.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, provider.Endpoint().TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := a.verifier.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s from token endpoint", resp.Status)
+	}
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return "", fmt.Errorf("cannot parse token response: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return "", errors.New("token response has no ID token")
+	}
+	return tokens.IDToken, nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/bearertoken"
+)
+
+func newTestAuthenticator(t *testing.T, p *testProvider, redirectURL string) http.Handler {
+	options := p.options()
+	options.ClientSecret = "secret"
+	options.RedirectURL = redirectURL
+	options.Scopes = []string{"openid", "email"}
+	options.PropagateToken = true
+	a, err := NewAuthenticator(options, NewVerifier(options, p.server.Client()), zap.NewNop())
+	require.NoError(t, err)
+	return a.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := bearertoken.GetBearerToken(r.Context())
+		w.Write([]byte(GetClaims(r.Context()).String("sub") + " " + token))
+	}))
+}
+
+func TestAuthenticatorBearerToken(t *testing.T) {
+	p := newTestProvider(t)
+	handler := newTestAuthenticator(t, p, "")
+	token := p.sign(t, "RS256", "rsa", p.claims())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/services", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "alice "+token, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/services", nil)
+	req.AddCookie(&http.Cookie{Name: TokenCookie, Value: token})
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/services", nil)
+	req.Header.Set("Authorization", "Bearer invalid")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "malformed jwt")
+
+	// browsers are not redirected when UI login is not configured
+	req = httptest.NewRequest(http.MethodGet, "/search", nil)
+	req.Header.Set("Accept", "text/html")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer realm="jaeger"`, w.Header().Get("WWW-Authenticate"))
+}
+
+func TestAuthenticatorLogin(t *testing.T) {
+	p := newTestProvider(t)
+	p.idToken = p.sign(t, "RS256", "rsa", p.claims())
+	handler := newTestAuthenticator(t, p, "https://jaeger.example.com/oidc/callback")
+
+	// API clients are not redirected
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/services", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/search?service=frontend", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusFound, w.Code)
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, p.server.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	assert.Equal(t, testClientID, location.Query().Get("client_id"))
+	assert.Equal(t, "openid email", location.Query().Get("scope"))
+	assert.Equal(t, "https://jaeger.example.com/oidc/callback", location.Query().Get("redirect_uri"))
+	state := location.Query().Get("state")
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	stateCookieValue := cookies[0]
+
+	callback := func(query string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/oidc/callback?"+query, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w = callback("code=good-code&state="+state, stateCookieValue)
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/search?service=frontend", w.Header().Get("Location"))
+	var tokenCookie *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == TokenCookie {
+			tokenCookie = cookie
+		}
+	}
+	require.NotNil(t, tokenCookie)
+	assert.Equal(t, p.idToken, tokenCookie.Value)
+	assert.True(t, tokenCookie.HttpOnly)
+
+	assert.Equal(t, http.StatusBadRequest, callback("code=good-code&state="+state, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, callback("code=good-code&state=other", stateCookieValue).Code)
+	assert.Equal(t, http.StatusUnauthorized, callback("error=access_denied&state="+state, stateCookieValue).Code)
+	assert.Equal(t, http.StatusUnauthorized, callback("code=bad-code&state="+state, stateCookieValue).Code)
+
+	p.idToken = ""
+	assert.Equal(t, http.StatusUnauthorized, callback("code=good-code&state="+state, stateCookieValue).Code)
+	p.idToken = "invalid"
+	assert.Equal(t, http.StatusUnauthorized, callback("code=good-code&state="+state, stateCookieValue).Code)
+
+	// the return path never leaves the server
+	p.idToken = p.sign(t, "RS256", "rsa", p.claims())
+	w = callback("code=good-code&state="+state, &http.Cookie{Name: stateCookie, Value: state + "://evil.example.com"})
+	assert.Equal(t, "/", w.Header().Get("Location"))
+}
+
+func TestAuthenticatorLoginProviderUnavailable(t *testing.T) {
+	p := newTestProvider(t)
+	options := p.options()
+	options.IssuerURL = p.server.URL + "/missing"
+	options.RedirectURL = "https://jaeger.example.com/oidc/callback"
+	a, err := NewAuthenticator(options, NewVerifier(options, p.server.Client()), zap.NewNop())
+	require.NoError(t, err)
+	handler := a.Handler(http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/search", nil)
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/oidc/callback?code=good-code&state=s", nil)
+	req.AddCookie(&http.Cookie{Name: stateCookie, Value: "s:/"})
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestNewAuthenticatorInvalidRedirectURL(t *testing.T) {
+	_, err := NewAuthenticator(Options{RedirectURL: "://invalid"}, nil, zap.NewNop())
+	require.ErrorContains(t, err, "invalid OIDC redirect URL")
+}
+
+func TestTokenFromRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "bearer abc")
+	assert.Equal(t, "abc", tokenFromRequest(req))
+
+	req.Header.Set("Authorization", "Basic "+strings.Repeat("a", 8))
+	assert.Empty(t, tokenFromRequest(req))
+}
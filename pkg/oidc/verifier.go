@@ -0,0 +1,112 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+)
+
+var errInvalidToken = errors.New("invalid token")
+
+// supportedSigningAlgs are the accepted token signing algorithms. Only asymmetric algorithms
+// are accepted, in particular "none" and HMAC based algorithms are rejected.
+var supportedSigningAlgs = []string{
+	gooidc.RS256, gooidc.RS384, gooidc.RS512,
+	gooidc.ES256, gooidc.ES384, gooidc.ES512,
+	gooidc.PS256, gooidc.PS384, gooidc.PS512,
+}
+
+// provider is the discovered configuration of the OpenID provider, with the verifier
+// of the tokens signed with its keys.
+type provider struct {
+	*gooidc.Provider
+	verifier *gooidc.IDTokenVerifier
+}
+
+// Verifier validates the signature and the claims of JSON Web Tokens issued by an OpenID provider.
+// The provider configuration and signing keys are discovered on first use.
+type Verifier struct {
+	options Options
+	client  *http.Client
+
+	mu       sync.Mutex
+	provider *provider
+}
+
+// NewVerifier creates a Verifier for the provider configured in the options.
+func NewVerifier(options Options, client *http.Client) *Verifier {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Verifier{
+		options: options,
+		client:  client,
+	}
+}
+
+// Verify checks the token and returns its claims.
+func (v *Verifier) Verify(ctx context.Context, rawToken string) (Claims, error) {
+	provider, err := v.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := provider.verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidToken, err)
+	}
+	var claims Claims
+	if err := token.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims: %w", errInvalidToken, err)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidToken, err)
+	}
+	return claims, nil
+}
+
+// validateClaims checks the claims not checked by the go-oidc verifier: the audience, which
+// may be the API instead of the client, and the required claims.
+func (v *Verifier) validateClaims(claims Claims) error {
+	if !claims.Contains("aud", v.options.ClientID) &&
+		(v.options.Audience == "" || !claims.Contains("aud", v.options.Audience)) {
+		return errors.New("unexpected audience")
+	}
+	for name, value := range v.options.RequiredClaims {
+		if !claims.Contains(name, value) {
+			return fmt.Errorf("required claim %q is missing", name)
+		}
+	}
+	return nil
+}
+
+// getProvider returns the provider configuration, discovering it if it was not discovered yet.
+func (v *Verifier) getProvider(ctx context.Context) (*provider, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.provider != nil {
+		return v.provider, nil
+	}
+	discovered, err := gooidc.NewProvider(gooidc.ClientContext(ctx, v.client), v.options.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot discover OpenID provider configuration: %w", err)
+	}
+	// the keys are fetched in the background of the requests, so not with their context
+	keysCtx := gooidc.ClientContext(context.Background(), v.client)
+	v.provider = &provider{
+		Provider: discovered,
+		verifier: discovered.VerifierContext(keysCtx, &gooidc.Config{
+			// the audience is checked by validateClaims
+			SkipClientIDCheck:    true,
+			SupportedSigningAlgs: supportedSigningAlgs,
+		}),
+	}
+	return v.provider, nil
+}
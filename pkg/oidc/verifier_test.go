@@ -0,0 +1,253 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testClientID = "jaeger-query"
+
+// testProvider is a minimal OpenID provider signing tokens with an RSA and an EC key.
+type testProvider struct {
+	server  *httptest.Server
+	rsaKey  *rsa.PrivateKey
+	ecKey   *ecdsa.PrivateKey
+	idToken string
+
+	mu   sync.Mutex
+	keys []jose.JSONWebKey
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p := &testProvider{rsaKey: rsaKey, ecKey: ecKey}
+	p.setKeys(
+		jose.JSONWebKey{Key: rsaKey.Public(), KeyID: "rsa", Use: "sig"},
+		jose.JSONWebKey{Key: ecKey.Public(), KeyID: "ec"},
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: p.keys})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "good-code" || r.FormValue("client_secret") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": p.idToken})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// setKeys sets the public keys published by the provider.
+func (p *testProvider) setKeys(keys ...jose.JSONWebKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = keys
+}
+
+func (p *testProvider) options() Options {
+	return Options{
+		Enabled:   true,
+		IssuerURL: p.server.URL,
+		ClientID:  testClientID,
+		Audience:  "jaeger-api",
+	}
+}
+
+func (p *testProvider) claims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":    p.server.URL,
+		"aud":    testClientID,
+		"sub":    "alice",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"groups": []string{"jaeger-users"},
+	}
+}
+
+// sign signs the claims with the RSA key for the RS and PS algorithms, and with the EC key otherwise.
+func (p *testProvider) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	var key interface{} = p.ecKey
+	if alg[0] == 'R' || alg[0] == 'P' {
+		key = p.rsaKey
+	}
+	return signToken(t, jose.SignatureAlgorithm(alg), jose.JSONWebKey{Key: key, KeyID: kid}, claims)
+}
+
+func signToken(t *testing.T, alg jose.SignatureAlgorithm, key jose.JSONWebKey, claims map[string]interface{}, opts ...*jose.SignerOptions) string {
+	var options *jose.SignerOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, options)
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	jws, err := signer.Sign(payload)
+	require.NoError(t, err)
+	token, err := jws.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func TestVerifierVerify(t *testing.T) {
+	p := newTestProvider(t)
+	v := NewVerifier(p.options(), p.server.Client())
+
+	for _, alg := range []string{"RS256", "RS384", "RS512", "PS256"} {
+		claims, err := v.Verify(context.Background(), p.sign(t, alg, "rsa", p.claims()))
+		require.NoError(t, err, alg)
+		assert.Equal(t, "alice", claims.String("sub"))
+	}
+	_, err := v.Verify(context.Background(), p.sign(t, "ES256", "ec", p.claims()))
+	require.NoError(t, err)
+
+	apiClaims := p.claims()
+	apiClaims["aud"] = []string{"other", "jaeger-api"}
+	_, err = v.Verify(context.Background(), p.sign(t, "RS256", "rsa", apiClaims))
+	require.NoError(t, err)
+}
+
+func TestVerifierInvalidTokens(t *testing.T) {
+	p := newTestProvider(t)
+	options := p.options()
+	options.RequiredClaims = map[string]string{"groups": "jaeger-users"}
+	v := NewVerifier(options, p.server.Client())
+	withClaim := func(name string, value interface{}) map[string]interface{} {
+		claims := p.claims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	criticalToken := signToken(t, jose.RS256, jose.JSONWebKey{Key: p.rsaKey, KeyID: "rsa"}, p.claims(),
+		(&jose.SignerOptions{}).WithHeader("crit", []string{"exp"}).WithHeader("exp", 1))
+
+	tests := []struct {
+		name  string
+		token string
+		err   string
+	}{
+		{"malformed", "abc", "malformed jwt"},
+		{"bad signature encoding", p.sign(t, "RS256", "rsa", p.claims()) + "!", "malformed jwt"},
+		{"unsigned", encode(map[string]string{"alg": "none"}) + "." + encode(p.claims()) + ".", "malformed jwt"},
+		{"HMAC", signToken(t, jose.HS256, jose.JSONWebKey{Key: make([]byte, 32)}, p.claims()), "malformed jwt"},
+		{"critical header", criticalToken, "failed to verify signature"},
+		{"unknown key", p.sign(t, "RS256", "other", p.claims()), "failed to verify signature"},
+		{"wrong key type", p.sign(t, "RS256", "ec", p.claims()), "failed to verify signature"},
+		{"wrong curve", signToken(t, jose.ES384, jose.JSONWebKey{Key: p384Key, KeyID: "ec"}, p.claims()), "failed to verify signature"},
+		{"issuer", p.sign(t, "RS256", "rsa", withClaim("iss", "https://evil.example.com")), "issued by a different provider"},
+		{"audience", p.sign(t, "RS256", "rsa", withClaim("aud", "other")), "unexpected audience"},
+		{"no expiration", p.sign(t, "RS256", "rsa", withClaim("exp", nil)), "token is expired"},
+		{"expired", p.sign(t, "RS256", "rsa", withClaim("exp", time.Now().Add(-time.Hour).Unix())), "token is expired"},
+		{"not before", p.sign(t, "RS256", "rsa", withClaim("nbf", time.Now().Add(time.Hour).Unix())), "before the nbf"},
+		{"required claim", p.sign(t, "RS256", "rsa", withClaim("groups", []string{"others"})), `required claim "groups" is missing`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := v.Verify(context.Background(), test.token)
+			require.ErrorIs(t, err, errInvalidToken)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+
+	token := p.sign(t, "RS256", "rsa", p.claims())
+	// tamper with the signature
+	tampered := token[:len(token)-4] + "AAAA"
+	_, err = v.Verify(context.Background(), tampered)
+	require.ErrorIs(t, err, errInvalidToken)
+}
+
+func TestVerifierKeyRotation(t *testing.T) {
+	p := newTestProvider(t)
+	v := NewVerifier(p.options(), p.server.Client())
+	_, err := v.Verify(context.Background(), p.sign(t, "RS256", "rsa", p.claims()))
+	require.NoError(t, err)
+
+	// the keys are fetched again when a token is signed with an unknown key
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p.setKeys(jose.JSONWebKey{Key: newKey.Public(), KeyID: "new"})
+	_, err = v.Verify(context.Background(), signToken(t, jose.ES256, jose.JSONWebKey{Key: newKey, KeyID: "new"}, p.claims()))
+	require.NoError(t, err)
+
+	_, err = v.Verify(context.Background(), p.sign(t, "RS256", "rsa", p.claims()))
+	require.ErrorContains(t, err, "failed to verify signature")
+}
+
+func TestVerifierDiscoveryErrors(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bad-issuer/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{"issuer": "https://other.example.com", "jwks_uri": "http://" + r.Host + "/missing"})
+		case "/bad-keys/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{"issuer": "http://" + r.Host + "/bad-keys", "jwks_uri": "http://" + r.Host + "/missing"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	token := signToken(t, jose.ES256, jose.JSONWebKey{Key: key}, map[string]interface{}{
+		"iss": server.URL + "/bad-keys",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	v := NewVerifier(Options{IssuerURL: server.URL + "/missing"}, server.Client())
+	_, err = v.Verify(context.Background(), token)
+	require.ErrorContains(t, err, "cannot discover OpenID provider configuration")
+
+	v = NewVerifier(Options{IssuerURL: server.URL + "/bad-issuer"}, server.Client())
+	_, err = v.Verify(context.Background(), token)
+	require.ErrorContains(t, err, "issuer did not match")
+
+	v = NewVerifier(Options{IssuerURL: server.URL + "/bad-keys"}, server.Client())
+	_, err = v.Verify(context.Background(), token)
+	require.ErrorContains(t, err, "fetching keys")
+
+	v = NewVerifier(Options{IssuerURL: "://invalid"}, nil)
+	_, err = v.Verify(context.Background(), token)
+	require.ErrorContains(t, err, "cannot discover OpenID provider configuration")
+}
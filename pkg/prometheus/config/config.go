@@ -15,6 +15,7 @@
 package config
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
@@ -27,10 +28,26 @@ type Configuration struct {
 	TLS                      tlscfg.Options
 	TokenFilePath            string
 	TokenOverrideFromContext bool
+	// Username and PasswordFilePath configure HTTP basic authentication.
+	// They cannot be used together with TokenFilePath.
+	Username         string
+	PasswordFilePath string
+	// ExtraHeaders are added to every request to Prometheus.
+	ExtraHeaders http.Header
+	// TenantHeader, if set, is the request header (e.g. X-Scope-OrgID for Cortex, Mimir or Thanos)
+	// carrying the tenant of the incoming query to Prometheus.
+	TenantHeader string
 
 	SupportSpanmetricsConnector bool
 	MetricNamespace             string
 	LatencyUnit                 string
 	NormalizeCalls              bool
 	NormalizeDuration           bool
+
+	// QuerySupport selects the backend the metrics are queried from: "prometheus",
+	// or "storage" to compute them from the spans in the span storage.
+	QuerySupport string
+	// StorageMaxTraces limits the number of traces of each service read to compute the metrics
+	// when QuerySupport is "storage" and the span storage cannot aggregate them natively.
+	StorageMaxTraces int
 }
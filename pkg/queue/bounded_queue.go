@@ -24,6 +24,9 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 )
 
+// drainCheckInterval is how often StopWithDrain checks whether the queue is empty
+const drainCheckInterval = 10 * time.Millisecond
+
 // Consumer consumes data from a bounded queue
 type Consumer interface {
 	Consume(item interface{})
@@ -149,6 +152,29 @@ func (q *BoundedQueue) Stop() {
 	close(*q.items)
 }
 
+// StopWithDrain disables the producer and waits until the consumers have processed the items
+// of the queue, or the timeout has elapsed, before stopping them like Stop. It returns the
+// number of items processed while draining and the number of items abandoned in the queue.
+func (q *BoundedQueue) StopWithDrain(timeout time.Duration) (drained int, abandoned int) {
+	q.stopped.Store(1) // disable producer
+	queued := q.Size()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+wait:
+	for q.Size() > 0 {
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			break wait
+		}
+	}
+	q.Stop()
+	abandoned = q.Size()
+	return queued - abandoned, abandoned
+}
+
 // Size returns the current size of the queue
 func (q *BoundedQueue) Size() int {
 	return int(q.size.Load())
@@ -359,3 +359,32 @@ func BenchmarkBoundedQueueWithFactory(b *testing.B) {
 func TestMain(m *testing.M) {
 	testutils.VerifyGoLeaks(m)
 }
+
+func TestStopWithDrain(t *testing.T) {
+	q := NewBoundedQueue(10, func(item any) {})
+	var consumed atomic.Int32
+	q.StartConsumers(1, func(item any) {
+		time.Sleep(time.Millisecond)
+		consumed.Add(1)
+	})
+	for i := 0; i < 5; i++ {
+		require.True(t, q.Produce(i))
+	}
+	drained, abandoned := q.StopWithDrain(5 * time.Second)
+	// some items may have been consumed before draining
+	assert.LessOrEqual(t, drained, 5)
+	assert.Equal(t, 0, abandoned)
+	assert.EqualValues(t, 5, consumed.Load())
+	assert.False(t, q.Produce(5), "cannot push to closed queue")
+}
+
+func TestStopWithDrainTimeout(t *testing.T) {
+	// without consumers, the items are never drained
+	q := NewBoundedQueue(10, func(item any) {})
+	for i := 0; i < 3; i++ {
+		require.True(t, q.Produce(i))
+	}
+	drained, abandoned := q.StopWithDrain(10 * time.Millisecond)
+	assert.Equal(t, 0, drained)
+	assert.Equal(t, 3, abandoned)
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package embedded aggregates the RED metrics of the services in memory from the ingested spans,
+// so that the all-in-one can serve the Monitor tab without a Prometheus-compatible metrics store.
+package embedded
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/plugin/metrics/spanstorage"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var (
+	_ spanstore.Writer    = (*Aggregator)(nil)
+	_ spanstore.REDReader = (*Aggregator)(nil)
+)
+
+// latencyBounds are the upper bounds of the buckets of the latency histograms,
+// the default ones of the spanmetrics connector of the OpenTelemetry Collector.
+var latencyBounds = []time.Duration{
+	2 * time.Millisecond,
+	4 * time.Millisecond,
+	6 * time.Millisecond,
+	8 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	400 * time.Millisecond,
+	800 * time.Millisecond,
+	1 * time.Second,
+	1400 * time.Millisecond,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+}
+
+type seriesKey struct {
+	service   string
+	operation string
+	kind      string
+}
+
+// bucket aggregates the spans started within an interval.
+type bucket struct {
+	calls  int64
+	errors int64
+	// latencies counts the spans of each bucket of latencyBounds, the last one counts the slower spans.
+	latencies []int64
+}
+
+func newBucket() *bucket {
+	return &bucket{latencies: make([]int64, len(latencyBounds)+1)}
+}
+
+func (b *bucket) merge(other *bucket) {
+	b.calls += other.calls
+	b.errors += other.errors
+	for i, count := range other.latencies {
+		b.latencies[i] += count
+	}
+}
+
+// quantile estimates the quantile q of the latencies, interpolating linearly within the histogram bucket.
+func (b *bucket) quantile(q float64) time.Duration {
+	rank := q * float64(b.calls)
+	var cumulative int64
+	for i, count := range b.latencies {
+		previous := cumulative
+		cumulative += count
+		if count == 0 || float64(cumulative) < rank {
+			continue
+		}
+		if i == len(latencyBounds) {
+			break
+		}
+		var lower time.Duration
+		if i > 0 {
+			lower = latencyBounds[i-1]
+		}
+		fraction := (rank - float64(previous)) / float64(count)
+		return lower + time.Duration(fraction*float64(latencyBounds[i]-lower))
+	}
+	return latencyBounds[len(latencyBounds)-1]
+}
+
+// Aggregator is a spanstore.Writer aggregating the calls, errors and latency histograms of the spans
+// of each service, operation and span kind in buckets of a fixed resolution, kept for the retention.
+// It implements spanstore.REDReader to query the aggregated metrics.
+type Aggregator struct {
+	resolution time.Duration
+	retention  time.Duration
+	now        func() time.Time
+
+	mu     sync.Mutex
+	series map[seriesKey]map[int64]*bucket
+	// evicted is the index of the oldest bucket kept at the last eviction.
+	evicted int64
+}
+
+// NewAggregator creates an Aggregator with the resolution and retention of the options.
+func NewAggregator(options Options) (*Aggregator, error) {
+	if options.Resolution <= 0 {
+		return nil, errors.New("the resolution of the embedded metrics must be positive")
+	}
+	if options.Retention < options.Resolution {
+		return nil, errors.New("the retention of the embedded metrics must not be shorter than the resolution")
+	}
+	return &Aggregator{
+		resolution: options.Resolution,
+		retention:  options.Retention,
+		now:        time.Now,
+		series:     make(map[seriesKey]map[int64]*bucket),
+	}, nil
+}
+
+// NewMetricsReader creates a metricsstore.Reader of the metrics aggregated by the aggregator,
+// with the same metric families as the Prometheus reader.
+func NewMetricsReader(aggregator *Aggregator, logger *zap.Logger) metricsstore.Reader {
+	return spanstorage.NewREDMetricsReader(aggregator, aggregator.resolution, logger)
+}
+
+// WriteSpan implements spanstore.Writer. The spans started before the retention are ignored.
+func (a *Aggregator) WriteSpan(_ context.Context, span *model.Span) error {
+	if span.Process == nil {
+		return nil
+	}
+	now := a.now()
+	if span.StartTime.Before(now.Add(-a.retention)) {
+		return nil
+	}
+	kind, _ := span.GetSpanKind()
+	key := seriesKey{
+		service:   span.Process.ServiceName,
+		operation: span.OperationName,
+		kind:      kind.String(),
+	}
+	index := a.bucketIndex(span.StartTime)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.evict(now)
+	buckets, ok := a.series[key]
+	if !ok {
+		buckets = make(map[int64]*bucket)
+		a.series[key] = buckets
+	}
+	b, ok := buckets[index]
+	if !ok {
+		b = newBucket()
+		buckets[index] = b
+	}
+	b.calls++
+	if tag, ok := model.KeyValues(span.Tags).FindByKey("error"); ok && tag.Bool() {
+		b.errors++
+	}
+	i, _ := slices.BinarySearch(latencyBounds, span.Duration)
+	b.latencies[i]++
+	return nil
+}
+
+func (a *Aggregator) bucketIndex(t time.Time) int64 {
+	return t.UnixNano() / int64(a.resolution)
+}
+
+// evict deletes the buckets older than the retention, at most once per bucket.
+func (a *Aggregator) evict(now time.Time) {
+	oldest := a.bucketIndex(now.Add(-a.retention))
+	if oldest <= a.evicted {
+		return
+	}
+	a.evicted = oldest
+	for key, buckets := range a.series {
+		for index := range buckets {
+			if index < oldest {
+				delete(buckets, index)
+			}
+		}
+		if len(buckets) == 0 {
+			delete(a.series, key)
+		}
+	}
+}
+
+// GetREDMetrics implements spanstore.REDReader. The buckets are assigned to the step containing
+// their start, so the steps should be multiples of the resolution.
+func (a *Aggregator) GetREDMetrics(_ context.Context, query *spanstore.REDQueryParameters) ([]spanstore.REDSeries, error) {
+	if query.Step <= 0 {
+		return nil, spanstore.ErrREDStepNotPositive
+	}
+	type groupKey struct {
+		service   string
+		operation string
+	}
+	type stepKey struct {
+		groupKey
+		step int64
+	}
+	steps := make(map[stepKey]*bucket)
+
+	a.mu.Lock()
+	for key, buckets := range a.series {
+		if !slices.Contains(query.ServiceNames, key.service) {
+			continue
+		}
+		if len(query.SpanKinds) > 0 && !slices.Contains(query.SpanKinds, key.kind) {
+			continue
+		}
+		group := groupKey{service: key.service}
+		if query.GroupByOperation {
+			group.operation = key.operation
+		}
+		for index, b := range buckets {
+			start := time.Unix(0, index*int64(a.resolution))
+			if start.Before(query.StartTime) || !start.Before(query.EndTime) {
+				continue
+			}
+			sk := stepKey{groupKey: group, step: int64(start.Sub(query.StartTime) / query.Step)}
+			merged, ok := steps[sk]
+			if !ok {
+				merged = newBucket()
+				steps[sk] = merged
+			}
+			merged.merge(b)
+		}
+	}
+	a.mu.Unlock()
+
+	series := make(map[groupKey]*spanstore.REDSeries)
+	for key, b := range steps {
+		s, ok := series[key.groupKey]
+		if !ok {
+			s = &spanstore.REDSeries{ServiceName: key.service, OperationName: key.operation}
+			series[key.groupKey] = s
+		}
+		s.Points = append(s.Points, spanstore.REDPoint{
+			Timestamp: query.StartTime.Add(time.Duration(key.step+1) * query.Step),
+			Calls:     b.calls,
+			Errors:    b.errors,
+			Latency:   b.quantile(query.Quantile),
+		})
+	}
+	result := make([]spanstore.REDSeries, 0, len(series))
+	for _, s := range series {
+		sort.Slice(s.Points, func(i, j int) bool { return s.Points[i].Timestamp.Before(s.Points[j].Timestamp) })
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ServiceName != result[j].ServiceName {
+			return result[i].ServiceName < result[j].ServiceName
+		}
+		return result[i].OperationName < result[j].OperationName
+	})
+	return result, nil
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package embedded
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var now = time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+func newTestAggregator(t *testing.T) *Aggregator {
+	a, err := NewAggregator(Options{Resolution: 10 * time.Second, Retention: 10 * time.Minute})
+	require.NoError(t, err)
+	a.now = func() time.Time { return now }
+	return a
+}
+
+func newSpan(service, operation, kind string, start time.Time, duration time.Duration, tags ...model.KeyValue) *model.Span {
+	return &model.Span{
+		Process:       &model.Process{ServiceName: service},
+		OperationName: operation,
+		StartTime:     start,
+		Duration:      duration,
+		Tags:          append(tags, model.String("span.kind", kind)),
+	}
+}
+
+func writeSpans(t *testing.T, a *Aggregator, spans ...*model.Span) {
+	for _, span := range spans {
+		require.NoError(t, a.WriteSpan(context.Background(), span))
+	}
+}
+
+func TestNewAggregatorErrors(t *testing.T) {
+	_, err := NewAggregator(Options{Retention: time.Hour})
+	require.EqualError(t, err, "the resolution of the embedded metrics must be positive")
+	_, err = NewAggregator(Options{Resolution: time.Minute, Retention: time.Second})
+	require.EqualError(t, err, "the retention of the embedded metrics must not be shorter than the resolution")
+}
+
+func TestGetREDMetrics(t *testing.T) {
+	a := newTestAggregator(t)
+	start := now.Add(-time.Minute)
+	writeSpans(t, a,
+		newSpan("frontend", "/dispatch", "server", start, time.Millisecond),
+		newSpan("frontend", "/dispatch", "server", start.Add(5*time.Second), 3*time.Millisecond, model.Bool("error", true)),
+		newSpan("frontend", "/config", "server", start.Add(10*time.Second), 300*time.Millisecond),
+		newSpan("frontend", "/dispatch", "server", start.Add(30*time.Second), 20*time.Second),
+		newSpan("frontend", "HTTP GET", "client", start, time.Millisecond),
+		newSpan("driver", "/FindNearest", "server", start, time.Millisecond),
+		// ignored: before the retention, and without process
+		newSpan("frontend", "/dispatch", "server", now.Add(-time.Hour), time.Millisecond),
+		&model.Span{StartTime: start},
+	)
+
+	series, err := a.GetREDMetrics(context.Background(), &spanstore.REDQueryParameters{
+		ServiceNames: []string{"frontend"},
+		SpanKinds:    []string{"server"},
+		StartTime:    start,
+		EndTime:      now,
+		Step:         20 * time.Second,
+		Quantile:     0.5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.REDSeries{{
+		ServiceName: "frontend",
+		Points: []spanstore.REDPoint{
+			// the median of 1ms, 3ms and 300ms, interpolated within the (2ms, 4ms] bucket
+			{Timestamp: start.Add(20 * time.Second), Calls: 3, Errors: 1, Latency: 3 * time.Millisecond},
+			// the slowest bucket reports the highest bound
+			{Timestamp: start.Add(40 * time.Second), Calls: 1, Latency: 15 * time.Second},
+		},
+	}}, series)
+
+	series, err = a.GetREDMetrics(context.Background(), &spanstore.REDQueryParameters{
+		ServiceNames:     []string{"frontend", "driver"},
+		GroupByOperation: true,
+		StartTime:        start,
+		EndTime:          start.Add(20 * time.Second),
+		Step:             20 * time.Second,
+		Quantile:         1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.REDSeries{
+		{ServiceName: "driver", OperationName: "/FindNearest", Points: []spanstore.REDPoint{
+			{Timestamp: start.Add(20 * time.Second), Calls: 1, Latency: 2 * time.Millisecond},
+		}},
+		{ServiceName: "frontend", OperationName: "/config", Points: []spanstore.REDPoint{
+			{Timestamp: start.Add(20 * time.Second), Calls: 1, Latency: 400 * time.Millisecond},
+		}},
+		{ServiceName: "frontend", OperationName: "/dispatch", Points: []spanstore.REDPoint{
+			{Timestamp: start.Add(20 * time.Second), Calls: 2, Errors: 1, Latency: 4 * time.Millisecond},
+		}},
+		{ServiceName: "frontend", OperationName: "HTTP GET", Points: []spanstore.REDPoint{
+			{Timestamp: start.Add(20 * time.Second), Calls: 1, Latency: 2 * time.Millisecond},
+		}},
+	}, series)
+}
+
+func TestGetREDMetricsStepNotPositive(t *testing.T) {
+	a := newTestAggregator(t)
+	_, err := a.GetREDMetrics(context.Background(), &spanstore.REDQueryParameters{})
+	require.ErrorIs(t, err, spanstore.ErrREDStepNotPositive)
+}
+
+func TestEviction(t *testing.T) {
+	a := newTestAggregator(t)
+	writeSpans(t, a,
+		newSpan("frontend", "/dispatch", "server", now.Add(-5*time.Minute), time.Millisecond),
+		newSpan("driver", "/FindNearest", "server", now.Add(-time.Minute), time.Millisecond),
+	)
+	require.Len(t, a.series, 2)
+
+	later := now.Add(6 * time.Minute)
+	a.now = func() time.Time { return later }
+	writeSpans(t, a, newSpan("driver", "/FindNearest", "server", later, time.Millisecond))
+	assert.Len(t, a.series, 1)
+	for key, buckets := range a.series {
+		assert.Equal(t, "driver", key.service)
+		assert.Len(t, buckets, 2)
+	}
+}
+
+func TestBucketQuantile(t *testing.T) {
+	b := newBucket()
+	assert.Equal(t, 15*time.Second, b.quantile(0.5))
+	b.calls = 4
+	b.latencies[0] = 4
+	assert.Equal(t, time.Duration(0), b.quantile(0))
+	assert.Equal(t, time.Millisecond, b.quantile(0.5))
+	assert.Equal(t, 2*time.Millisecond, b.quantile(1))
+}
+
+func TestNewMetricsReader(t *testing.T) {
+	a := newTestAggregator(t)
+	writeSpans(t, a, newSpan("frontend", "/dispatch", "server", now.Add(-15*time.Second), time.Millisecond))
+	reader := NewMetricsReader(a, zap.NewNop())
+
+	minStep, err := reader.GetMinStepDuration(context.Background(), &metricsstore.MinStepDurationQueryParameters{})
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, minStep)
+
+	lookback := time.Minute
+	step := time.Second
+	family, err := reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: metricsstore.BaseQueryParameters{
+			ServiceNames: []string{"frontend"},
+			EndTime:      &now,
+			Lookback:     &lookback,
+			Step:         &step,
+			SpanKinds:    []string{"SPAN_KIND_SERVER"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, family.Metrics, 1)
+	require.Len(t, family.Metrics[0].MetricPoints, 1)
+	// the step is raised to the resolution
+	assert.InDelta(t, 0.1, family.Metrics[0].MetricPoints[0].GetGaugeValue().GetDoubleValue(), 1e-9)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package embedded
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	flagEnabled    = "embedded-metrics.enabled"
+	flagResolution = "embedded-metrics.resolution"
+	flagRetention  = "embedded-metrics.retention"
+
+	defaultResolution = 15 * time.Second
+	defaultRetention  = time.Hour
+)
+
+// Options holds configuration for the RED metrics aggregated in-process from the ingested spans.
+type Options struct {
+	// Enabled turns on the aggregation, and serves the metrics of the Monitor tab from it.
+	Enabled bool
+	// Resolution is the duration of the buckets in which the spans are aggregated.
+	Resolution time.Duration
+	// Retention is the duration for which the buckets are kept in memory.
+	Retention time.Duration
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Bool(flagEnabled, false,
+		"Enables the RED metrics aggregated in memory from the ingested spans, serving the Monitor tab "+
+			"without a Prometheus-compatible metrics store. Overrides METRICS_STORAGE_TYPE.",
+	)
+	flagSet.Duration(flagResolution, defaultResolution,
+		"The duration of the buckets in which the spans are aggregated, the smallest step of the metrics queries.",
+	)
+	flagSet.Duration(flagRetention, defaultRetention,
+		"The duration for which the aggregated metrics are kept in memory.",
+	)
+}
+
+// InitFromViper initializes Options with properties from viper
+func (opts *Options) InitFromViper(v *viper.Viper) *Options {
+	opts.Enabled = v.GetBool(flagEnabled)
+	opts.Resolution = v.GetDuration(flagResolution)
+	opts.Retention = v.GetDuration(flagRetention)
+	return opts
+}
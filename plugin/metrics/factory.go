@@ -26,6 +26,7 @@ import (
 	"github.com/jaegertracing/jaeger/plugin/metrics/prometheus"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 const (
@@ -81,6 +82,15 @@ func (f *Factory) Initialize(logger *zap.Logger) error {
 	return nil
 }
 
+// SetSpanReader implements storage.SpanReaderMetricsFactory.
+func (f *Factory) SetSpanReader(spanReader spanstore.Reader) {
+	for _, factory := range f.factories {
+		if srf, ok := factory.(storage.SpanReaderMetricsFactory); ok {
+			srf.SetSpanReader(spanReader)
+		}
+	}
+}
+
 // CreateMetricsReader implements storage.MetricsFactory.
 func (f *Factory) CreateMetricsReader() (metricsstore.Reader, error) {
 	factory, ok := f.factories[f.MetricsStorageType]
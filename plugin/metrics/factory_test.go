@@ -26,6 +26,8 @@ import (
 	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
 var _ storage.MetricsFactory = new(Factory)
@@ -114,3 +116,26 @@ func TestConfigurable(t *testing.T) {
 	assert.Equal(t, fs, mock.flagSet)
 	assert.Equal(t, v, mock.viper)
 }
+
+type spanReaderMetricsFactory struct {
+	mocks.MetricsFactory
+	spanReader spanstore.Reader
+}
+
+// SetSpanReader implements storage.SpanReaderMetricsFactory.
+func (f *spanReaderMetricsFactory) SetSpanReader(spanReader spanstore.Reader) {
+	f.spanReader = spanReader
+}
+
+func TestSetSpanReader(t *testing.T) {
+	f, err := NewFactory(withConfig(prometheusStorageType))
+	require.NoError(t, err)
+
+	mock := new(spanReaderMetricsFactory)
+	f.factories[prometheusStorageType] = mock
+	f.factories[disabledStorageType] = disabled.NewFactory()
+
+	spanReader := &spanstoremocks.Reader{}
+	f.SetSpanReader(spanReader)
+	assert.Same(t, spanReader, mock.spanReader)
+}
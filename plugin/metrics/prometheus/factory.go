@@ -15,6 +15,7 @@
 package prometheus
 
 import (
+	"errors"
 	"flag"
 
 	"github.com/spf13/viper"
@@ -24,16 +25,24 @@ import (
 
 	"github.com/jaegertracing/jaeger/plugin"
 	prometheusstore "github.com/jaegertracing/jaeger/plugin/metrics/prometheus/metricsstore"
+	"github.com/jaegertracing/jaeger/plugin/metrics/spanstorage"
+	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
-var _ plugin.Configurable = (*Factory)(nil)
+var (
+	_ plugin.Configurable              = (*Factory)(nil)
+	_ storage.SpanReaderMetricsFactory = (*Factory)(nil)
+)
 
 // Factory implements storage.Factory and creates storage components backed by memory store.
 type Factory struct {
 	options *Options
 	logger  *zap.Logger
 	tracer  trace.TracerProvider
+
+	spanReader spanstore.Reader
 }
 
 // NewFactory creates a new Factory.
@@ -62,7 +71,18 @@ func (f *Factory) Initialize(logger *zap.Logger) error {
 	return nil
 }
 
+// SetSpanReader implements storage.SpanReaderMetricsFactory.
+func (f *Factory) SetSpanReader(spanReader spanstore.Reader) {
+	f.spanReader = spanReader
+}
+
 // CreateMetricsReader implements storage.MetricsFactory.
 func (f *Factory) CreateMetricsReader() (metricsstore.Reader, error) {
+	if f.options.Primary.QuerySupport == QuerySupportStorage {
+		if f.spanReader == nil {
+			return nil, errors.New("the span reader must be set to compute the metrics from the span storage")
+		}
+		return spanstorage.NewMetricsReader(f.spanReader, f.options.Primary.StorageMaxTraces, f.logger), nil
+	}
 	return prometheusstore.NewMetricsReader(f.options.Primary.Configuration, f.logger, f.tracer)
 }
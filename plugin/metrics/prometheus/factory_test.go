@@ -16,6 +16,7 @@ package prometheus
 
 import (
 	"net"
+	"net/http"
 	"testing"
 	"time"
 
@@ -25,7 +26,9 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
+	"github.com/jaegertracing/jaeger/plugin/metrics/spanstorage"
 	"github.com/jaegertracing/jaeger/storage"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
 var _ storage.MetricsFactory = new(Factory)
@@ -48,6 +51,27 @@ func TestPrometheusFactory(t *testing.T) {
 	assert.NotNil(t, reader)
 }
 
+func TestPrometheusFactoryWithStorageQuerySupport(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{
+		"--prometheus.query.support=storage",
+		"--prometheus.query.storage-max-traces=10",
+	}))
+	f.InitFromViper(v, zap.NewNop())
+	require.NoError(t, f.Initialize(zap.NewNop()))
+	assert.Equal(t, QuerySupportStorage, f.options.Primary.QuerySupport)
+	assert.Equal(t, 10, f.options.Primary.StorageMaxTraces)
+
+	_, err := f.CreateMetricsReader()
+	require.ErrorContains(t, err, "the span reader must be set")
+
+	f.SetSpanReader(&spanstoremocks.Reader{})
+	reader, err := f.CreateMetricsReader()
+	require.NoError(t, err)
+	assert.IsType(t, &spanstorage.MetricsReader{}, reader)
+}
+
 func TestWithDefaultConfiguration(t *testing.T) {
 	f := NewFactory()
 	assert.Equal(t, "http://localhost:9090", f.options.Primary.ServerURL)
@@ -56,6 +80,8 @@ func TestWithDefaultConfiguration(t *testing.T) {
 	assert.True(t, f.options.Primary.SupportSpanmetricsConnector)
 	assert.Empty(t, f.options.Primary.MetricNamespace)
 	assert.Equal(t, "ms", f.options.Primary.LatencyUnit)
+	assert.Equal(t, QuerySupportPrometheus, f.options.Primary.QuerySupport)
+	assert.Equal(t, 1000, f.options.Primary.StorageMaxTraces)
 }
 
 func TestWithConfiguration(t *testing.T) {
@@ -95,6 +121,32 @@ func TestWithConfiguration(t *testing.T) {
 		f.InitFromViper(v, zap.NewNop())
 		assert.Equal(t, "test/ test file.txt", f.options.Primary.TokenFilePath)
 	})
+	t.Run("with authentication and headers", func(t *testing.T) {
+		f := NewFactory()
+		v, command := config.Viperize(f.AddFlags)
+		err := command.ParseFlags([]string{
+			"--prometheus.username=user",
+			"--prometheus.password-file=test/password.txt",
+			"--prometheus.extra-headers=X-Custom: a",
+			"--prometheus.extra-headers=X-Custom:b",
+			"--prometheus.tenant-header=X-Scope-OrgID",
+		})
+		require.NoError(t, err)
+		f.InitFromViper(v, zap.NewNop())
+		assert.Equal(t, "user", f.options.Primary.Username)
+		assert.Equal(t, "test/password.txt", f.options.Primary.PasswordFilePath)
+		assert.Equal(t, http.Header{"X-Custom": []string{"a", "b"}}, f.options.Primary.ExtraHeaders)
+		assert.Equal(t, "X-Scope-OrgID", f.options.Primary.TenantHeader)
+	})
+	t.Run("with malformed extra headers", func(t *testing.T) {
+		f := NewFactory()
+		v, command := config.Viperize(f.AddFlags)
+		err := command.ParseFlags([]string{
+			"--prometheus.extra-headers=X-Custom",
+		})
+		require.NoError(t, err)
+		assert.Panics(t, func() { f.InitFromViper(v, zap.NewNop()) })
+	})
 	t.Run("with custom configuration of prometheus.query", func(t *testing.T) {
 		f := NewFactory()
 		v, command := config.Viperize(f.AddFlags)
@@ -109,6 +161,17 @@ func TestWithConfiguration(t *testing.T) {
 		assert.Equal(t, "mynamespace", f.options.Primary.MetricNamespace)
 		assert.Equal(t, "ms", f.options.Primary.LatencyUnit)
 	})
+	t.Run("with invalid prometheus.query.support", func(t *testing.T) {
+		f := NewFactory()
+		v, command := config.Viperize(f.AddFlags)
+		err := command.ParseFlags([]string{
+			"--prometheus.query.support=graphite",
+		})
+		require.NoError(t, err)
+		assert.PanicsWithValue(t, "Failed to initialize metrics storage factory", func() {
+			f.InitFromViper(v, zap.NewNop())
+		})
+	})
 	t.Run("with invalid prometheus.query.duration-unit", func(t *testing.T) {
 		defer func() {
 			if r := recover(); r == nil {
@@ -17,6 +17,7 @@ package metricsstore
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -36,6 +37,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/bearertoken"
 	"github.com/jaegertracing/jaeger/pkg/prometheus/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/metrics/prometheus/metricsstore/dbmodel"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
@@ -364,17 +366,67 @@ func getHTTPRoundTripper(c *config.Configuration, logger *zap.Logger) (rt http.R
 		}
 		token = tokenFromFile
 	}
-	return bearertoken.RoundTripper{
-		Transport:       httpTransport,
-		OverrideFromCtx: c.TokenOverrideFromContext,
-		StaticToken:     token,
+	password := ""
+	if c.Username != "" {
+		if token != "" {
+			return nil, errors.New("basic authentication and bearer token file cannot be used together")
+		}
+		if c.PasswordFilePath != "" {
+			if password, err = loadSecret(c.PasswordFilePath, "password"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return headersRoundTripper{
+		Transport: bearertoken.RoundTripper{
+			Transport:       httpTransport,
+			OverrideFromCtx: c.TokenOverrideFromContext,
+			StaticToken:     token,
+		},
+		headers:      c.ExtraHeaders,
+		tenantHeader: c.TenantHeader,
+		username:     c.Username,
+		password:     password,
 	}, nil
 }
 
+// headersRoundTripper wraps another http.RoundTripper and adds the configured
+// headers, the basic authentication and the tenant of the query to the requests.
+type headersRoundTripper struct {
+	Transport    http.RoundTripper
+	headers      http.Header
+	tenantHeader string
+	username     string
+	password     string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (tr headersRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	for name, values := range tr.headers {
+		for _, value := range values {
+			r.Header.Add(name, value)
+		}
+	}
+	if tr.username != "" {
+		r.SetBasicAuth(tr.username, tr.password)
+	}
+	if tr.tenantHeader != "" {
+		if tenant := tenancy.GetTenant(r.Context()); tenant != "" {
+			r.Header.Set(tr.tenantHeader, tenant)
+		}
+	}
+	return tr.Transport.RoundTrip(r)
+}
+
 func loadToken(path string) (string, error) {
+	return loadSecret(path, "token")
+}
+
+func loadSecret(path string, kind string) (string, error) {
 	b, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
-		return "", fmt.Errorf("failed to get token from file: %w", err)
+		return "", fmt.Errorf("failed to get %s from file: %w", kind, err)
 	}
 	return strings.TrimRight(string(b), "\r\n"), nil
 }
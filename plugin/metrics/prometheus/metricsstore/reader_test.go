@@ -38,6 +38,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/bearertoken"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/prometheus/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
@@ -723,7 +724,8 @@ func TestWarningResponse(t *testing.T) {
 
 type fakePromServer struct {
 	*httptest.Server
-	authReceived atomic.Pointer[string]
+	authReceived    atomic.Pointer[string]
+	headersReceived atomic.Pointer[http.Header]
 }
 
 func newFakePromServer(t *testing.T) *fakePromServer {
@@ -734,6 +736,8 @@ func newFakePromServer(t *testing.T) *fakePromServer {
 				t.Logf("Request to fake Prometheus server %+v", r)
 				h := r.Header.Get("Authorization")
 				s.authReceived.Store(&h)
+				headers := r.Header.Clone()
+				s.headersReceived.Store(&headers)
 			},
 		),
 	)
@@ -856,6 +860,87 @@ func TestGetRoundTripperTokenFromContext(t *testing.T) {
 	assert.Equal(t, "Bearer tokenFromRequest", server.getAuth())
 }
 
+func TestGetRoundTripperHeaders(t *testing.T) {
+	rt, err := getHTTPRoundTripper(&config.Configuration{
+		ConnectTimeout: time.Second,
+		ExtraHeaders:   http.Header{"X-Custom": []string{"a", "b"}},
+		TenantHeader:   "X-Scope-OrgID",
+	}, nil)
+	require.NoError(t, err)
+
+	server := newFakePromServer(t)
+	defer server.Close()
+
+	for _, tenant := range []string{"acme", ""} {
+		req, err := http.NewRequestWithContext(
+			tenancy.WithTenant(context.Background(), tenant),
+			http.MethodGet,
+			server.URL,
+			nil,
+		)
+		require.NoError(t, err)
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		headers := *server.headersReceived.Load()
+		assert.Equal(t, []string{"a", "b"}, headers.Values("X-Custom"))
+		assert.Equal(t, tenant, headers.Get("X-Scope-OrgID"))
+		// the original request is not modified
+		assert.Empty(t, req.Header)
+	}
+}
+
+func TestGetRoundTripperBasicAuth(t *testing.T) {
+	file, err := os.CreateTemp("", "password_")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.Remove(file.Name())) }()
+	_, err = file.Write([]byte("secret\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	rt, err := getHTTPRoundTripper(&config.Configuration{
+		ConnectTimeout:   time.Second,
+		Username:         "user",
+		PasswordFilePath: file.Name(),
+	}, nil)
+	require.NoError(t, err)
+
+	server := newFakePromServer(t)
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	req.SetBasicAuth("user", "secret")
+	assert.Equal(t, req.Header.Get("Authorization"), server.getAuth())
+}
+
+func TestGetRoundTripperBasicAuthErrors(t *testing.T) {
+	_, err := getHTTPRoundTripper(&config.Configuration{
+		Username:         "user",
+		PasswordFilePath: "this file does not exist",
+	}, nil)
+	require.ErrorContains(t, err, "failed to get password from file")
+
+	file, err := os.CreateTemp("", "token_")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.Remove(file.Name())) }()
+	_, err = file.Write([]byte("token"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	_, err = getHTTPRoundTripper(&config.Configuration{
+		Username:      "user",
+		TokenFilePath: file.Name(),
+	}, nil)
+	require.EqualError(t, err, "basic authentication and bearer token file cannot be used together")
+}
+
 func TestGetRoundTripperTokenError(t *testing.T) {
 	tokenFilePath := "this file does not exist"
 
@@ -18,11 +18,13 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 
+	jconfig "github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/pkg/prometheus/config"
 )
@@ -32,12 +34,18 @@ const (
 	suffixConnectTimeout      = ".connect-timeout"
 	suffixTokenFilePath       = ".token-file"
 	suffixOverrideFromContext = ".token-override-from-context"
+	suffixUsername            = ".username"
+	suffixPasswordFilePath    = ".password-file"
+	suffixExtraHeaders        = ".extra-headers"
+	suffixTenantHeader        = ".tenant-header"
 
 	suffixSupportSpanmetricsConnector = ".query.support-spanmetrics-connector"
 	suffixMetricNamespace             = ".query.namespace"
 	suffixLatencyUnit                 = ".query.duration-unit"
 	suffixNormalizeCalls              = ".query.normalize-calls"
 	suffixNormalizeDuration           = ".query.normalize-duration"
+	suffixQuerySupport                = ".query.support"
+	suffixStorageMaxTraces            = ".query.storage-max-traces"
 
 	defaultServerURL      = "http://localhost:9090"
 	defaultConnectTimeout = 30 * time.Second
@@ -48,10 +56,19 @@ const (
 	defaultLatencyUnit                 = "ms"
 	defaultNormalizeCalls              = false
 	defaultNormalizeDuration           = false
+	defaultQuerySupport                = QuerySupportPrometheus
+	defaultStorageMaxTraces            = 1000
 
 	deprecatedSpanMetricsProcessor = "(deprecated, will be removed after 2024-01-01 or in release v1.53.0, whichever is later) "
 )
 
+const (
+	// QuerySupportPrometheus queries the metrics from a Prometheus-compatible metrics store.
+	QuerySupportPrometheus = "prometheus"
+	// QuerySupportStorage computes the metrics from the spans in the span storage.
+	QuerySupportStorage = "storage"
+)
+
 type namespaceConfig struct {
 	config.Configuration `mapstructure:",squash"`
 	namespace            string
@@ -73,6 +90,8 @@ func NewOptions(primaryNamespace string) *Options {
 		LatencyUnit:                 defaultLatencyUnit,
 		NormalizeCalls:              defaultNormalizeCalls,
 		NormalizeDuration:           defaultNormalizeCalls,
+		QuerySupport:                defaultQuerySupport,
+		StorageMaxTraces:            defaultStorageMaxTraces,
 	}
 
 	return &Options{
@@ -94,6 +113,15 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 		"The path to a file containing the bearer token which will be included when executing queries against the Prometheus API.")
 	flagSet.Bool(nsConfig.namespace+suffixOverrideFromContext, true,
 		"Whether the bearer token should be overridden from context (incoming request)")
+	flagSet.String(nsConfig.namespace+suffixUsername, "",
+		"The username for the HTTP basic authentication when executing queries against the Prometheus API.")
+	flagSet.String(nsConfig.namespace+suffixPasswordFilePath, "",
+		"The path to a file containing the password for the HTTP basic authentication.")
+	flagSet.Var(&jconfig.StringSlice{}, nsConfig.namespace+suffixExtraHeaders,
+		`Additional HTTP headers included when executing queries against the Prometheus API. Can be specified multiple times. Format: "Key: Value"`)
+	flagSet.String(nsConfig.namespace+suffixTenantHeader, "",
+		"The HTTP header carrying the tenant of the incoming request to the Prometheus API, "+
+			"e.g. X-Scope-OrgID for Cortex, Mimir or Thanos. The tenant is not propagated if empty.")
 	flagSet.Bool(
 		nsConfig.namespace+suffixSupportSpanmetricsConnector,
 		defaultSupportSpanmetricsConnector,
@@ -116,6 +144,12 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 			`https://github.com/open-telemetry/opentelemetry-collector-contrib/blob/main/pkg/translator/prometheus/README.md. `+
 			`For example: `+
 			`"duration_bucket" (not normalized) -> "duration_milliseconds_bucket (normalized)"`)
+	flagSet.String(nsConfig.namespace+suffixQuerySupport, defaultQuerySupport,
+		`The backend the metrics are queried from. It can be either "prometheus" or "storage", to compute `+
+			`the metrics directly from the spans in the span storage, without a Prometheus-compatible metrics store.`)
+	flagSet.Int(nsConfig.namespace+suffixStorageMaxTraces, defaultStorageMaxTraces,
+		`The maximum number of traces of each service read to compute the metrics when the query support is "storage" `+
+			`and the span storage cannot aggregate them natively (e.g. Elasticsearch and OpenSearch can).`)
 
 	nsConfig.getTLSFlagsConfig().AddFlags(flagSet)
 }
@@ -136,13 +170,25 @@ func (opt *Options) InitFromViper(v *viper.Viper) error {
 	cfg.NormalizeCalls = v.GetBool(cfg.namespace + suffixNormalizeCalls)
 	cfg.NormalizeDuration = v.GetBool(cfg.namespace + suffixNormalizeDuration)
 	cfg.TokenOverrideFromContext = v.GetBool(cfg.namespace + suffixOverrideFromContext)
+	cfg.Username = v.GetString(cfg.namespace + suffixUsername)
+	cfg.PasswordFilePath = v.GetString(cfg.namespace + suffixPasswordFilePath)
+	cfg.TenantHeader = v.GetString(cfg.namespace + suffixTenantHeader)
+	headers, err := parseHeaders(v.GetStringSlice(cfg.namespace + suffixExtraHeaders))
+	if err != nil {
+		return fmt.Errorf("failed to parse Prometheus extra headers: %w", err)
+	}
+	cfg.ExtraHeaders = headers
+	cfg.QuerySupport = v.GetString(cfg.namespace + suffixQuerySupport)
+	cfg.StorageMaxTraces = v.GetInt(cfg.namespace + suffixStorageMaxTraces)
 
 	isValidUnit := map[string]bool{"ms": true, "s": true}
 	if _, ok := isValidUnit[cfg.LatencyUnit]; !ok {
 		return fmt.Errorf(`duration-unit must be one of "ms" or "s", not %q`, cfg.LatencyUnit)
 	}
+	if cfg.QuerySupport != QuerySupportPrometheus && cfg.QuerySupport != QuerySupportStorage {
+		return fmt.Errorf(`query.support must be one of %q or %q, not %q`, QuerySupportPrometheus, QuerySupportStorage, cfg.QuerySupport)
+	}
 
-	var err error
 	cfg.TLS, err = cfg.getTLSFlagsConfig().InitFromViper(v)
 	if err != nil {
 		return fmt.Errorf("failed to process Prometheus TLS options: %w", err)
@@ -156,6 +202,22 @@ func (config *namespaceConfig) getTLSFlagsConfig() tlscfg.ClientFlagsConfig {
 	}
 }
 
+// parseHeaders parses the extra headers in the format "Key: Value", it returns nil if there are none.
+func parseHeaders(values []string) (http.Header, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	header := make(http.Header)
+	for _, value := range values {
+		name, val, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("malformed header %q, expected format is \"Key: Value\"", value)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(val))
+	}
+	return header, nil
+}
+
 // stripWhiteSpace removes all whitespace characters from a string.
 func stripWhiteSpace(str string) string {
 	return strings.ReplaceAll(str, " ", "")
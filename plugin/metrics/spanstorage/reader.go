@@ -0,0 +1,170 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstorage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// minStep is the smallest step of the queries, so that the span storage
+// is not asked for an excessive number of buckets.
+const minStep = time.Second
+
+// MetricsReader is a metricsstore.Reader computing the RED metrics of the services
+// from the spans in the span storage, for deployments without a Prometheus-compatible
+// metrics store. The metric families have the same names and labels as the ones
+// returned by the Prometheus reader.
+type MetricsReader struct {
+	getREDMetrics func(ctx context.Context, query *spanstore.REDQueryParameters) ([]spanstore.REDSeries, error)
+	minStep       time.Duration
+	maxTraces     int
+	logger        *zap.Logger
+}
+
+// NewMetricsReader creates a MetricsReader reading the spans from spanReader. If the reader
+// cannot aggregate the spans natively, the metrics are computed from the spans of up to
+// maxTraces traces of each service.
+func NewMetricsReader(spanReader spanstore.Reader, maxTraces int, logger *zap.Logger) *MetricsReader {
+	return &MetricsReader{
+		getREDMetrics: func(ctx context.Context, query *spanstore.REDQueryParameters) ([]spanstore.REDSeries, error) {
+			return spanstore.GetREDMetrics(ctx, spanReader, query)
+		},
+		minStep:   minStep,
+		maxTraces: maxTraces,
+		logger:    logger,
+	}
+}
+
+// NewREDMetricsReader creates a MetricsReader computing the metrics with redReader,
+// e.g. from the spans aggregated in-process, without reading the span storage.
+// The steps of the queries are at least minStepDuration, e.g. the resolution of the aggregation.
+func NewREDMetricsReader(redReader spanstore.REDReader, minStepDuration time.Duration, logger *zap.Logger) *MetricsReader {
+	return &MetricsReader{
+		getREDMetrics: redReader.GetREDMetrics,
+		minStep:       max(minStepDuration, minStep),
+		logger:        logger,
+	}
+}
+
+// GetLatencies gets the latency metrics for the given set of latency query parameters.
+func (m *MetricsReader) GetLatencies(ctx context.Context, requestParams *metricsstore.LatenciesQueryParameters) (*metrics.MetricFamily, error) {
+	return m.query(ctx, requestParams.BaseQueryParameters, requestParams.Quantile,
+		"service_latencies",
+		fmt.Sprintf("%.2fth quantile latency, grouped by service", requestParams.Quantile),
+		func(p spanstore.REDPoint, _ time.Duration) float64 {
+			// the Prometheus reader reports the latencies in milliseconds by default
+			return float64(p.Latency) / float64(time.Millisecond)
+		})
+}
+
+// GetCallRates gets the call rate metrics for the given set of call rate query parameters.
+// The rate of a point is the number of calls per second within its step.
+func (m *MetricsReader) GetCallRates(ctx context.Context, requestParams *metricsstore.CallRateQueryParameters) (*metrics.MetricFamily, error) {
+	return m.query(ctx, requestParams.BaseQueryParameters, 0,
+		"service_call_rate",
+		"calls/sec, grouped by service",
+		func(p spanstore.REDPoint, step time.Duration) float64 {
+			return float64(p.Calls) / step.Seconds()
+		})
+}
+
+// GetErrorRates gets the error rate metrics for the given set of error rate query parameters.
+func (m *MetricsReader) GetErrorRates(ctx context.Context, requestParams *metricsstore.ErrorRateQueryParameters) (*metrics.MetricFamily, error) {
+	return m.query(ctx, requestParams.BaseQueryParameters, 0,
+		"service_error_rate",
+		"error rate, computed as a fraction of errors/sec over calls/sec, grouped by service",
+		func(p spanstore.REDPoint, _ time.Duration) float64 {
+			return float64(p.Errors) / float64(p.Calls)
+		})
+}
+
+// GetMinStepDuration gets the minimum step duration (the smallest possible duration between two data points in a time series) supported.
+func (m *MetricsReader) GetMinStepDuration(_ context.Context, _ *metricsstore.MinStepDurationQueryParameters) (time.Duration, error) {
+	return m.minStep, nil
+}
+
+func (m *MetricsReader) query(
+	ctx context.Context,
+	params metricsstore.BaseQueryParameters,
+	quantile float64,
+	metricName string,
+	metricDesc string,
+	value func(p spanstore.REDPoint, step time.Duration) float64,
+) (*metrics.MetricFamily, error) {
+	if params.GroupByOperation {
+		metricName = strings.Replace(metricName, "service", "service_operation", 1)
+		metricDesc += " & operation"
+	}
+	step := max(*params.Step, m.minStep)
+	query := &spanstore.REDQueryParameters{
+		ServiceNames:     params.ServiceNames,
+		GroupByOperation: params.GroupByOperation,
+		SpanKinds:        toJaegerSpanKinds(params.SpanKinds),
+		StartTime:        params.EndTime.Add(-*params.Lookback),
+		EndTime:          *params.EndTime,
+		Step:             step,
+		Quantile:         quantile,
+		MaxTraces:        m.maxTraces,
+	}
+	series, err := m.getREDMetrics(ctx, query)
+	if err != nil {
+		m.logger.Error("Failed to compute metrics from span storage", zap.String("metric", metricName), zap.Error(err))
+		return nil, fmt.Errorf("failed computing metrics from span storage: %w", err)
+	}
+	family := &metrics.MetricFamily{
+		Name:    metricName,
+		Type:    metrics.MetricType_GAUGE,
+		Help:    metricDesc,
+		Metrics: make([]*metrics.Metric, 0, len(series)),
+	}
+	for _, s := range series {
+		labels := []*metrics.Label{{Name: "service_name", Value: s.ServiceName}}
+		if params.GroupByOperation {
+			labels = append(labels, &metrics.Label{Name: "operation", Value: s.OperationName})
+		}
+		points := make([]*metrics.MetricPoint, 0, len(s.Points))
+		for _, p := range s.Points {
+			if p.Calls == 0 {
+				continue
+			}
+			points = append(points, &metrics.MetricPoint{
+				Timestamp: toDomainTimestamp(p.Timestamp),
+				Value: &metrics.MetricPoint_GaugeValue{
+					GaugeValue: &metrics.GaugeValue{
+						Value: &metrics.GaugeValue_DoubleValue{DoubleValue: value(p, step)},
+					},
+				},
+			})
+		}
+		family.Metrics = append(family.Metrics, &metrics.Metric{Labels: labels, MetricPoints: points})
+	}
+	return family, nil
+}
+
+// toJaegerSpanKinds converts the span kinds of the metrics queries, e.g. "SPAN_KIND_SERVER",
+// to the values of the span.kind tag of the spans, e.g. "server".
+func toJaegerSpanKinds(spanKinds []string) []string {
+	kinds := make([]string, len(spanKinds))
+	for i, kind := range spanKinds {
+		kinds[i] = strings.ToLower(strings.TrimPrefix(kind, "SPAN_KIND_"))
+	}
+	return kinds
+}
+
+func toDomainTimestamp(t time.Time) *types.Timestamp {
+	return &types.Timestamp{
+		Seconds: t.Unix(),
+		Nanos:   int32(t.Nanosecond()),
+	}
+}
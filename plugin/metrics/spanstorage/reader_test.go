@@ -0,0 +1,179 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+type redReader struct {
+	spanstore.Reader
+	series []spanstore.REDSeries
+	err    error
+	query  *spanstore.REDQueryParameters
+}
+
+func (r *redReader) GetREDMetrics(_ context.Context, query *spanstore.REDQueryParameters) ([]spanstore.REDSeries, error) {
+	r.query = query
+	return r.series, r.err
+}
+
+var endTime = time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+func baseQuery(groupByOperation bool) metricsstore.BaseQueryParameters {
+	lookback := 2 * time.Minute
+	step := time.Minute
+	ratePer := 10 * time.Minute
+	return metricsstore.BaseQueryParameters{
+		ServiceNames:     []string{"frontend"},
+		GroupByOperation: groupByOperation,
+		EndTime:          &endTime,
+		Lookback:         &lookback,
+		Step:             &step,
+		RatePer:          &ratePer,
+		SpanKinds:        []string{"SPAN_KIND_SERVER", "SPAN_KIND_CLIENT"},
+	}
+}
+
+func newTestReader() (*MetricsReader, *redReader) {
+	spanReader := &redReader{series: []spanstore.REDSeries{{
+		ServiceName:   "frontend",
+		OperationName: "/dispatch",
+		Points: []spanstore.REDPoint{
+			{Timestamp: endTime.Add(-time.Minute), Calls: 120, Errors: 30, Latency: 1500 * time.Microsecond},
+			{Timestamp: endTime, Calls: 60, Latency: 2 * time.Millisecond},
+		},
+	}}}
+	return NewMetricsReader(spanReader, 10, zap.NewNop()), spanReader
+}
+
+func gaugePoint(ts time.Time, value float64) *metrics.MetricPoint {
+	return &metrics.MetricPoint{
+		Timestamp: &types.Timestamp{Seconds: ts.Unix()},
+		Value: &metrics.MetricPoint_GaugeValue{
+			GaugeValue: &metrics.GaugeValue{Value: &metrics.GaugeValue_DoubleValue{DoubleValue: value}},
+		},
+	}
+}
+
+func TestGetLatencies(t *testing.T) {
+	reader, spanReader := newTestReader()
+	family, err := reader.GetLatencies(context.Background(), &metricsstore.LatenciesQueryParameters{
+		BaseQueryParameters: baseQuery(true),
+		Quantile:            0.95,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &spanstore.REDQueryParameters{
+		ServiceNames:     []string{"frontend"},
+		GroupByOperation: true,
+		SpanKinds:        []string{"server", "client"},
+		StartTime:        endTime.Add(-2 * time.Minute),
+		EndTime:          endTime,
+		Step:             time.Minute,
+		Quantile:         0.95,
+		MaxTraces:        10,
+	}, spanReader.query)
+	assert.Equal(t, &metrics.MetricFamily{
+		Name: "service_operation_latencies",
+		Type: metrics.MetricType_GAUGE,
+		Help: "0.95th quantile latency, grouped by service & operation",
+		Metrics: []*metrics.Metric{{
+			Labels: []*metrics.Label{
+				{Name: "service_name", Value: "frontend"},
+				{Name: "operation", Value: "/dispatch"},
+			},
+			MetricPoints: []*metrics.MetricPoint{
+				gaugePoint(endTime.Add(-time.Minute), 1.5),
+				gaugePoint(endTime, 2),
+			},
+		}},
+	}, family)
+}
+
+func TestGetCallRates(t *testing.T) {
+	reader, _ := newTestReader()
+	family, err := reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: baseQuery(false),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "service_call_rate", family.Name)
+	assert.Equal(t, "calls/sec, grouped by service", family.Help)
+	require.Len(t, family.Metrics, 1)
+	assert.Equal(t, []*metrics.Label{{Name: "service_name", Value: "frontend"}}, family.Metrics[0].Labels)
+	assert.Equal(t, []*metrics.MetricPoint{
+		gaugePoint(endTime.Add(-time.Minute), 2),
+		gaugePoint(endTime, 1),
+	}, family.Metrics[0].MetricPoints)
+}
+
+func TestGetErrorRates(t *testing.T) {
+	reader, spanReader := newTestReader()
+	spanReader.series[0].Points = append(spanReader.series[0].Points, spanstore.REDPoint{Timestamp: endTime.Add(time.Minute)})
+	family, err := reader.GetErrorRates(context.Background(), &metricsstore.ErrorRateQueryParameters{
+		BaseQueryParameters: baseQuery(false),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "service_error_rate", family.Name)
+	require.Len(t, family.Metrics, 1)
+	// points without calls are skipped, points without errors have a zero error rate
+	assert.Equal(t, []*metrics.MetricPoint{
+		gaugePoint(endTime.Add(-time.Minute), 0.25),
+		gaugePoint(endTime, 0),
+	}, family.Metrics[0].MetricPoints)
+}
+
+func TestQueryMinStep(t *testing.T) {
+	reader, spanReader := newTestReader()
+	params := baseQuery(false)
+	step := time.Millisecond
+	params.Step = &step
+	_, err := reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{BaseQueryParameters: params})
+	require.NoError(t, err)
+	assert.Equal(t, minStep, spanReader.query.Step)
+
+	minStepDuration, err := reader.GetMinStepDuration(context.Background(), &metricsstore.MinStepDurationQueryParameters{})
+	require.NoError(t, err)
+	assert.Equal(t, minStep, minStepDuration)
+}
+
+func TestQueryError(t *testing.T) {
+	reader, spanReader := newTestReader()
+	spanReader.err = errors.New("storage failure")
+	_, err := reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: baseQuery(false),
+	})
+	require.EqualError(t, err, "failed computing metrics from span storage: storage failure")
+}
+
+func TestNewREDMetricsReader(t *testing.T) {
+	_, red := newTestReader()
+	reader := NewREDMetricsReader(red, 30*time.Second, zap.NewNop())
+	params := baseQuery(false)
+	step := 10 * time.Second
+	params.Step = &step
+	family, err := reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: params,
+	})
+	require.NoError(t, err)
+	require.Len(t, family.Metrics, 1)
+	assert.Len(t, family.Metrics[0].MetricPoints, 2)
+	assert.Zero(t, red.query.MaxTraces)
+	assert.Equal(t, 30*time.Second, red.query.Step)
+
+	minStepDuration, err := reader.GetMinStepDuration(context.Background(), &metricsstore.MinStepDurationQueryParameters{})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, minStepDuration)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/olivere/elastic"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+)
+
+const (
+	defaultTTL = 60 * time.Second
+
+	leasesIndex          = "jaeger-leases"
+	indexPrefixSeparator = "-"
+)
+
+var errLockOwnership = errors.New("this host does not own the resource lock")
+
+// lease is the document of a resource lock, the id of the document is the resource name.
+type lease struct {
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Lock is a distributed lock based off Elasticsearch.
+//
+// The leases are documents of the leases index, they are created only if they do not exist,
+// and updated or deleted only if they were not modified since they were read.
+// A lease expires at the time set by its owner, so the clocks of the hosts must be in sync.
+type Lock struct {
+	client   func() es.Client
+	index    string
+	tenantID string
+}
+
+// NewLock creates a new instance of a distributed locking mechanism based off Elasticsearch.
+func NewLock(client func() es.Client, indexPrefix, tenantID string) *Lock {
+	index := leasesIndex
+	if indexPrefix != "" {
+		index = indexPrefix + indexPrefixSeparator + leasesIndex
+	}
+	return &Lock{
+		client:   client,
+		index:    index,
+		tenantID: tenantID,
+	}
+}
+
+// Acquire acquires a lease around a given resource.
+func (l *Lock) Acquire(resource string, ttl time.Duration) (bool, error) {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	ctx := context.Background()
+	docs := l.client().Documents(l.index)
+	now := time.Now()
+	acquired := lease{Owner: l.tenantID, ExpiresAt: now.Add(ttl)}
+	err := docs.Create(ctx, resource, acquired)
+	if err == nil {
+		// The lock was successfully created
+		return true, nil
+	}
+	if !elastic.IsConflict(err) {
+		return false, fmt.Errorf("failed to acquire resource lock due to elasticsearch error: %w", err)
+	}
+	current, seqNo, primaryTerm, err := l.get(ctx, docs, resource)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			// The lock was forfeited since it was created, it can be acquired on the next attempt
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acquire resource lock due to elasticsearch error: %w", err)
+	}
+	if current.Owner != l.tenantID && now.Before(current.ExpiresAt) {
+		return false, nil
+	}
+	// This host already owns the lock, or the lease of the owner expired: extend or take over the lease
+	err = docs.Update(ctx, resource, seqNo, primaryTerm, acquired)
+	if elastic.IsConflict(err) {
+		// Another host acquired the lock since it was read
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to extend lease on resource lock: %w", err)
+	}
+	return true, nil
+}
+
+// Forfeit forfeits an existing lease around a given resource.
+func (l *Lock) Forfeit(resource string) (bool, error) {
+	ctx := context.Background()
+	docs := l.client().Documents(l.index)
+	current, seqNo, primaryTerm, err := l.get(ctx, docs, resource)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return false, fmt.Errorf("failed to forfeit resource lock: %w", errLockOwnership)
+		}
+		return false, fmt.Errorf("failed to forfeit resource lock due to elasticsearch error: %w", err)
+	}
+	if current.Owner != l.tenantID {
+		return false, fmt.Errorf("failed to forfeit resource lock: %w", errLockOwnership)
+	}
+	err = docs.Delete(ctx, resource, seqNo, primaryTerm)
+	if elastic.IsConflict(err) {
+		return false, fmt.Errorf("failed to forfeit resource lock: %w", errLockOwnership)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to forfeit resource lock due to elasticsearch error: %w", err)
+	}
+	// The lock was successfully deleted
+	return true, nil
+}
+
+// get reads the lease of a resource, with the sequence number and primary term of its document.
+func (*Lock) get(ctx context.Context, docs es.DocumentService, resource string) (lease, int64, int64, error) {
+	var current lease
+	res, err := docs.Get(ctx, resource)
+	if err != nil {
+		return current, 0, 0, err
+	}
+	if !res.Found || res.Source == nil {
+		return current, 0, 0, &elastic.Error{Status: 404}
+	}
+	if res.SeqNo == nil || res.PrimaryTerm == nil {
+		return current, 0, 0, errors.New("the lease has no sequence number, Elasticsearch 6.7 or later is required")
+	}
+	if err := json.Unmarshal(*res.Source, &current); err != nil {
+		return current, 0, 0, fmt.Errorf("failed to unmarshal the lease: %w", err)
+	}
+	return current, *res.SeqNo, *res.PrimaryTerm, nil
+}
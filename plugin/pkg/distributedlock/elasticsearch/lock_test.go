@@ -0,0 +1,275 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
+)
+
+const (
+	localhost    = "localhost"
+	samplingLock = "sampling_lock"
+)
+
+var (
+	errConflict = &elastic.Error{Status: 409}
+	errNotFound = &elastic.Error{Status: 404}
+)
+
+func withLock(t *testing.T, fn func(docs *mocks.DocumentService, lock *Lock)) {
+	docs := &mocks.DocumentService{}
+	client := &mocks.Client{}
+	client.On("Documents", "prefix-jaeger-leases").Return(docs)
+	fn(docs, NewLock(func() es.Client { return client }, "prefix", localhost))
+	docs.AssertExpectations(t)
+}
+
+func getResult(t *testing.T, owner string, expiresAt time.Time) *elastic.GetResult {
+	data, err := json.Marshal(lease{Owner: owner, ExpiresAt: expiresAt})
+	require.NoError(t, err)
+	source := json.RawMessage(data)
+	seqNo, primaryTerm := int64(3), int64(1)
+	return &elastic.GetResult{Found: true, Source: &source, SeqNo: &seqNo, PrimaryTerm: &primaryTerm}
+}
+
+func TestNewLockIndex(t *testing.T) {
+	assert.Equal(t, "jaeger-leases", NewLock(nil, "", localhost).index)
+	assert.Equal(t, "prefix-jaeger-leases", NewLock(nil, "prefix", localhost).index)
+}
+
+func TestAcquire(t *testing.T) {
+	later := time.Now().Add(time.Minute)
+	earlier := time.Now().Add(-time.Minute)
+	testCases := []struct {
+		caption        string
+		errCreate      error
+		getResult      func(t *testing.T) *elastic.GetResult
+		errGet         error
+		update         bool
+		errUpdate      error
+		acquired       bool
+		expectedErrMsg string
+	}{
+		{
+			caption:  "successfully created lock",
+			acquired: true,
+		},
+		{
+			caption:        "error creating lock",
+			errCreate:      errors.New("bad request"),
+			expectedErrMsg: "failed to acquire resource lock due to elasticsearch error: bad request",
+		},
+		{
+			caption:   "lock forfeited since it was created",
+			errCreate: errConflict,
+			errGet:    errNotFound,
+		},
+		{
+			caption:   "lock not found",
+			errCreate: errConflict,
+			getResult: func(*testing.T) *elastic.GetResult { return &elastic.GetResult{} },
+		},
+		{
+			caption:        "error reading lock",
+			errCreate:      errConflict,
+			errGet:         errors.New("unavailable"),
+			expectedErrMsg: "failed to acquire resource lock due to elasticsearch error: unavailable",
+		},
+		{
+			caption:   "lock owned by another host",
+			errCreate: errConflict,
+			getResult: func(t *testing.T) *elastic.GetResult { return getResult(t, "otherhost", later) },
+		},
+		{
+			caption:   "successfully extended lease",
+			errCreate: errConflict,
+			getResult: func(t *testing.T) *elastic.GetResult { return getResult(t, localhost, later) },
+			update:    true,
+			acquired:  true,
+		},
+		{
+			caption:   "successfully took over expired lease",
+			errCreate: errConflict,
+			getResult: func(t *testing.T) *elastic.GetResult { return getResult(t, "otherhost", earlier) },
+			update:    true,
+			acquired:  true,
+		},
+		{
+			caption:   "lock acquired by another host since it was read",
+			errCreate: errConflict,
+			getResult: func(t *testing.T) *elastic.GetResult { return getResult(t, "otherhost", earlier) },
+			update:    true,
+			errUpdate: errConflict,
+		},
+		{
+			caption:        "error extending lease",
+			errCreate:      errConflict,
+			getResult:      func(t *testing.T) *elastic.GetResult { return getResult(t, localhost, later) },
+			update:         true,
+			errUpdate:      errors.New("unavailable"),
+			expectedErrMsg: "failed to extend lease on resource lock: unavailable",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.caption, func(t *testing.T) {
+			withLock(t, func(docs *mocks.DocumentService, lock *Lock) {
+				docs.On("Create", mock.Anything, samplingLock, mock.AnythingOfType("lease")).Return(tc.errCreate)
+				if tc.getResult != nil || tc.errGet != nil {
+					var res *elastic.GetResult
+					if tc.getResult != nil {
+						res = tc.getResult(t)
+					}
+					docs.On("Get", mock.Anything, samplingLock).Return(res, tc.errGet)
+				}
+				if tc.update {
+					docs.On("Update", mock.Anything, samplingLock, int64(3), int64(1), mock.MatchedBy(func(l lease) bool {
+						return l.Owner == localhost && l.ExpiresAt.After(time.Now())
+					})).Return(tc.errUpdate)
+				}
+				acquired, err := lock.Acquire(samplingLock, time.Minute)
+				if tc.expectedErrMsg == "" {
+					require.NoError(t, err)
+				} else {
+					require.EqualError(t, err, tc.expectedErrMsg)
+				}
+				assert.Equal(t, tc.acquired, acquired)
+			})
+		})
+	}
+}
+
+func TestAcquireDefaultTTL(t *testing.T) {
+	withLock(t, func(docs *mocks.DocumentService, lock *Lock) {
+		docs.On("Create", mock.Anything, samplingLock, mock.MatchedBy(func(l lease) bool {
+			return l.ExpiresAt.After(time.Now().Add(defaultTTL - time.Second))
+		})).Return(nil)
+		acquired, err := lock.Acquire(samplingLock, 0)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+}
+
+func TestAcquireInvalidLease(t *testing.T) {
+	testCases := []struct {
+		caption        string
+		getResult      func(t *testing.T) *elastic.GetResult
+		expectedErrMsg string
+	}{
+		{
+			caption: "lease without sequence number",
+			getResult: func(t *testing.T) *elastic.GetResult {
+				res := getResult(t, localhost, time.Now())
+				res.SeqNo = nil
+				return res
+			},
+			expectedErrMsg: "Elasticsearch 6.7 or later is required",
+		},
+		{
+			caption: "malformed lease",
+			getResult: func(t *testing.T) *elastic.GetResult {
+				res := getResult(t, localhost, time.Now())
+				source := json.RawMessage(`{"owner": 1}`)
+				res.Source = &source
+				return res
+			},
+			expectedErrMsg: "failed to unmarshal the lease",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.caption, func(t *testing.T) {
+			withLock(t, func(docs *mocks.DocumentService, lock *Lock) {
+				docs.On("Create", mock.Anything, samplingLock, mock.Anything).Return(errConflict)
+				docs.On("Get", mock.Anything, samplingLock).Return(tc.getResult(t), nil)
+				acquired, err := lock.Acquire(samplingLock, time.Minute)
+				require.ErrorContains(t, err, tc.expectedErrMsg)
+				assert.False(t, acquired)
+			})
+		})
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	testCases := []struct {
+		caption        string
+		owner          string
+		errGet         error
+		delete         bool
+		errDelete      error
+		forfeited      bool
+		expectedErrMsg string
+	}{
+		{
+			caption:   "successfully forfeited lock",
+			owner:     localhost,
+			delete:    true,
+			forfeited: true,
+		},
+		{
+			caption:        "lock not found",
+			errGet:         errNotFound,
+			expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock",
+		},
+		{
+			caption:        "error reading lock",
+			errGet:         errors.New("unavailable"),
+			expectedErrMsg: "failed to forfeit resource lock due to elasticsearch error: unavailable",
+		},
+		{
+			caption:        "lock owned by another host",
+			owner:          "otherhost",
+			expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock",
+		},
+		{
+			caption:        "lock acquired by another host since it was read",
+			owner:          localhost,
+			delete:         true,
+			errDelete:      errConflict,
+			expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock",
+		},
+		{
+			caption:        "error deleting lock",
+			owner:          localhost,
+			delete:         true,
+			errDelete:      errors.New("unavailable"),
+			expectedErrMsg: "failed to forfeit resource lock due to elasticsearch error: unavailable",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.caption, func(t *testing.T) {
+			withLock(t, func(docs *mocks.DocumentService, lock *Lock) {
+				var res *elastic.GetResult
+				if tc.errGet == nil {
+					res = getResult(t, tc.owner, time.Now().Add(time.Minute))
+				}
+				docs.On("Get", mock.Anything, samplingLock).Return(res, tc.errGet)
+				if tc.delete {
+					docs.On("Delete", mock.Anything, samplingLock, int64(3), int64(1)).Return(tc.errDelete)
+				}
+				forfeited, err := lock.Forfeit(samplingLock)
+				if tc.expectedErrMsg == "" {
+					require.NoError(t, err)
+				} else {
+					require.EqualError(t, err, tc.expectedErrMsg)
+				}
+				assert.Equal(t, tc.forfeited, forfeited)
+			})
+		})
+	}
+}
+
+func TestMain(m *testing.M) {
+	testutils.VerifyGoLeaks(m)
+}
@@ -55,6 +55,8 @@ var ( // interface comformance checks
 	// _ storage.ArchiveFactory       = (*Factory)(nil)
 
 	_ storage.SamplingStoreFactory = (*Factory)(nil)
+	_ storage.PurgerFactory        = (*Factory)(nil)
+	_ storage.RetentionFactory     = (*Factory)(nil)
 )
 
 // Factory implements storage.Factory for Badger backend.
@@ -64,6 +66,8 @@ type Factory struct {
 	cache   *badgerStore.CacheStore
 	logger  *zap.Logger
 
+	retentionPolicies *spanstore.RetentionPolicies
+
 	tmpDir          string
 	maintenanceDone chan bool
 
@@ -186,7 +190,14 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return badgerStore.NewSpanWriter(f.store, f.cache, f.Options.Primary.SpanStoreTTL), nil
+	writer := badgerStore.NewSpanWriter(f.store, f.cache, f.Options.Primary.SpanStoreTTL)
+	writer.SetRetentionPolicies(f.retentionPolicies)
+	return writer, nil
+}
+
+// SetRetentionPolicies implements storage.RetentionFactory
+func (f *Factory) SetRetentionPolicies(policies *spanstore.RetentionPolicies) {
+	f.retentionPolicies = policies
 }
 
 // CreateDependencyReader implements storage.Factory
@@ -195,6 +206,11 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return depStore.NewDependencyStore(sr), nil
 }
 
+// CreatePurger implements storage.PurgerFactory
+func (f *Factory) CreatePurger() (spanstore.Purger, error) {
+	return badgerStore.NewPurger(f.store, badgerStore.NewTraceReader(f.store, f.cache)), nil
+}
+
 // CreateSamplingStore implements storage.SamplingStoreFactory
 func (f *Factory) CreateSamplingStore(maxBuckets int) (samplingstore.Store, error) {
 	return badgerSampling.NewSamplingStore(f.store), nil
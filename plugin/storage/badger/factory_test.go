@@ -69,6 +69,9 @@ func TestForCodecov(t *testing.T) {
 	_, err = f.CreateDependencyReader()
 	require.NoError(t, err)
 
+	_, err = f.CreatePurger()
+	require.NoError(t, err)
+
 	lock, err := f.CreateLock()
 	require.NoError(t, err)
 	assert.NotNil(t, lock)
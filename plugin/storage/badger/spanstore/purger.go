@@ -0,0 +1,91 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// purgeBatchSize is the number of traces searched at once by PurgeTraces.
+const purgeBatchSize = 1000
+
+// Purger deletes the spans and the index keys of the traces from badger
+type Purger struct {
+	store  *badger.DB
+	reader *TraceReader
+}
+
+// NewPurger returns a Purger finding the traces to delete with the reader
+func NewPurger(db *badger.DB, reader *TraceReader) *Purger {
+	return &Purger{
+		store:  db,
+		reader: reader,
+	}
+}
+
+// PurgeTraces implements spanstore.Purger
+func (p *Purger) PurgeTraces(ctx context.Context, query *spanstore.TraceQueryParameters) (int, error) {
+	if err := spanstore.ValidatePurgeQuery(query); err != nil {
+		return 0, err
+	}
+	// the reader requires a time range, the purges default to all the stored traces
+	q := *query
+	if q.StartTimeMin.IsZero() {
+		q.StartTimeMin = time.Unix(0, 0)
+	}
+	if q.StartTimeMax.IsZero() {
+		q.StartTimeMax = time.Unix(0, math.MaxInt64)
+	}
+	q.NumTraces = purgeBatchSize
+	return spanstore.PurgeFoundTraces(ctx, &q, p.reader.FindTraceIDs, p.deleteTraces)
+}
+
+// deleteTraces deletes the spans of the traces, and the index keys recreated from the spans
+func (p *Purger) deleteTraces(_ context.Context, traceIDs []model.TraceID) error {
+	batch := p.store.NewWriteBatch()
+	defer batch.Cancel()
+
+	err := p.store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for _, traceID := range traceIDs {
+			prefix := createPrimaryKeySeekPrefix(traceID)
+			for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+				item := it.Item()
+				val, err := item.ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+				span, err := decodeValue(val, item.UserMeta()&encodingTypeBits)
+				if err != nil {
+					return err
+				}
+				keys := append(createIndexKeys(span, model.TimeAsEpochMicroseconds(span.StartTime)), item.KeyCopy(nil))
+				for _, key := range keys {
+					if err := batch.Delete(key); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return batch.Flush()
+}
+
+// PurgeAll implements spanstore.Purger
+func (p *Purger) PurgeAll(context.Context) error {
+	return p.store.DropAll()
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// keysByTrace counts the span and index keys of every trace.
+func keysByTrace(t *testing.T, store *badger.DB) map[model.TraceID]int {
+	counts := make(map[model.TraceID]int)
+	err := store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := it.Item().Key()
+			if key[0] == spanKeyPrefix {
+				counts[bytesToTraceID(key[1:1+sizeOfTraceID])]++
+			} else {
+				counts[bytesToTraceID(key[len(key)-sizeOfTraceID:])]++
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return counts
+}
+
+func TestPurgeTraces(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		cache := NewCacheStore(store, time.Duration(1*time.Hour), true)
+		sw := NewSpanWriter(store, cache, time.Duration(1*time.Hour))
+		purger := NewPurger(store, NewTraceReader(store, cache))
+
+		alice, bob := model.TraceID{High: 1, Low: 1}, model.TraceID{High: 1, Low: 2}
+		for _, traceID := range []model.TraceID{alice, bob} {
+			for i := 0; i < 3; i++ {
+				span := createDummySpan()
+				span.TraceID = traceID
+				span.SpanID = model.SpanID(i)
+				span.Tags = append(span.Tags, model.String("user.id", map[model.TraceID]string{alice: "alice", bob: "bob"}[traceID]))
+				require.NoError(t, sw.WriteSpan(context.Background(), &span))
+			}
+		}
+		bobKeys := keysByTrace(t, store)[bob]
+
+		_, err := purger.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{Tags: map[string]string{"user.id": "alice"}})
+		require.ErrorIs(t, err, spanstore.ErrPurgeQueryServiceRequired)
+		purged, err := purger.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{
+			ServiceName: "service",
+			Tags:        map[string]string{"user.id": "alice"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		// the span and index keys of the purged trace are deleted
+		assert.Equal(t, map[model.TraceID]int{bob: bobKeys}, keysByTrace(t, store))
+
+		require.NoError(t, purger.PurgeAll(context.Background()))
+		assert.Empty(t, keysByTrace(t, store))
+	})
+}
+
+func TestPurgeTracesDecodeError(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		cache := NewCacheStore(store, time.Duration(1*time.Hour), true)
+		sw := NewSpanWriter(store, cache, time.Duration(1*time.Hour))
+		purger := NewPurger(store, NewTraceReader(store, cache))
+
+		span := createDummySpan()
+		require.NoError(t, sw.WriteSpan(context.Background(), &span))
+		key, _, err := createTraceKV(&span, protoEncoding, model.TimeAsEpochMicroseconds(span.StartTime))
+		require.NoError(t, err)
+		require.NoError(t, store.Update(func(txn *badger.Txn) error {
+			return txn.SetEntry(&badger.Entry{Key: key, Value: []byte("invalid"), UserMeta: protoEncoding})
+		}))
+
+		_, err = purger.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "service"})
+		require.Error(t, err)
+	})
+}
@@ -89,6 +89,46 @@ func TestEncodingTypes(t *testing.T) {
 	})
 }
 
+func TestRetentionPolicies(t *testing.T) {
+	runWithBadger(t, func(store *badger.DB, t *testing.T) {
+		cache := NewCacheStore(store, time.Duration(1*time.Hour), true)
+		sw := NewSpanWriter(store, cache, time.Duration(1*time.Hour))
+		sw.SetRetentionPolicies(spanstore.NewRetentionPolicies([]spanstore.RetentionPolicy{
+			{Name: "service", TTL: 90 * 24 * time.Hour, Services: []string{"service"}},
+		}))
+
+		testSpan := createDummySpan()
+		err := sw.WriteSpan(context.Background(), &testSpan)
+		require.NoError(t, err)
+		otherSpan := createDummySpan()
+		otherSpan.TraceID.Low = 2
+		otherSpan.Process = &model.Process{ServiceName: "other"}
+		err = sw.WriteSpan(context.Background(), &otherSpan)
+		require.NoError(t, err)
+
+		// all the keys of the span expire with the TTL of its retention policy
+		err = store.View(func(txn *badger.Txn) error {
+			it := txn.NewIterator(badger.DefaultIteratorOptions)
+			defer it.Close()
+			for it.Rewind(); it.Valid(); it.Next() {
+				key := it.Item().Key()
+				expiresAt := time.Unix(int64(it.Item().ExpiresAt()), 0)
+				traceID := key[len(key)-sizeOfTraceID:]
+				if key[0] == spanKeyPrefix {
+					traceID = key[1 : 1+sizeOfTraceID]
+				}
+				if binary.BigEndian.Uint64(traceID[8:]) == 2 {
+					assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+				} else {
+					assert.WithinDuration(t, time.Now().Add(90*24*time.Hour), expiresAt, time.Minute)
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+	})
+}
+
 func TestDecodeErrorReturns(t *testing.T) {
 	garbage := []byte{0x08}
 
@@ -25,6 +25,8 @@ import (
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 /*
@@ -52,6 +54,7 @@ type SpanWriter struct {
 	ttl          time.Duration
 	cache        *CacheStore
 	encodingType byte
+	retention    *spanstore.RetentionPolicies
 }
 
 // NewSpanWriter returns a SpawnWriter with cache
@@ -64,9 +67,15 @@ func NewSpanWriter(db *badger.DB, c *CacheStore, ttl time.Duration) *SpanWriter
 	}
 }
 
+// SetRetentionPolicies overrides the TTL of the spans matching the retention policies
+func (w *SpanWriter) SetRetentionPolicies(policies *spanstore.RetentionPolicies) {
+	w.retention = policies
+}
+
 // WriteSpan writes the encoded span as well as creates indexes with defined TTL
 func (w *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
-	expireTime := uint64(time.Now().Add(w.ttl).Unix())
+	ttl := w.retention.TTL(tenancy.GetTenant(ctx), span.Process.ServiceName, w.ttl)
+	expireTime := uint64(time.Now().Add(ttl).Unix())
 	startTime := model.TimeAsEpochMicroseconds(span.StartTime)
 
 	// Avoid doing as much as possible inside the transaction boundary, create entries here
@@ -78,28 +87,8 @@ func (w *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
 	}
 
 	entriesToStore = append(entriesToStore, trace)
-	entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(serviceNameIndexKey, []byte(span.Process.ServiceName), startTime, span.TraceID), nil, expireTime))
-	entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(operationNameIndexKey, []byte(span.Process.ServiceName+span.OperationName), startTime, span.TraceID), nil, expireTime))
-
-	// It doesn't matter if we overwrite Duration index keys, everything is read at Trace level in any case
-	durationValue := make([]byte, 8)
-	binary.BigEndian.PutUint64(durationValue, uint64(model.DurationAsMicroseconds(span.Duration)))
-	entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(durationIndexKey, durationValue, startTime, span.TraceID), nil, expireTime))
-
-	for _, kv := range span.Tags {
-		// Convert everything to string since queries are done that way also
-		// KEY: it<serviceName><tagsKey><traceId> VALUE: <tagsValue>
-		entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID), nil, expireTime))
-	}
-
-	for _, kv := range span.Process.Tags {
-		entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID), nil, expireTime))
-	}
-
-	for _, log := range span.Logs {
-		for _, kv := range log.Fields {
-			entriesToStore = append(entriesToStore, w.createBadgerEntry(createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID), nil, expireTime))
-		}
+	for _, key := range createIndexKeys(span, startTime) {
+		entriesToStore = append(entriesToStore, w.createBadgerEntry(key, nil, expireTime))
 	}
 
 	err = w.store.Update(func(txn *badger.Txn) error {
@@ -124,6 +113,35 @@ func (w *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
 	return err
 }
 
+// createIndexKeys returns the keys of the secondary indexes of the span.
+func createIndexKeys(span *model.Span, startTime uint64) [][]byte {
+	keys := make([][]byte, 0, len(span.Tags)+3+len(span.Process.Tags)+len(span.Logs)*4)
+	keys = append(keys, createIndexKey(serviceNameIndexKey, []byte(span.Process.ServiceName), startTime, span.TraceID))
+	keys = append(keys, createIndexKey(operationNameIndexKey, []byte(span.Process.ServiceName+span.OperationName), startTime, span.TraceID))
+
+	// It doesn't matter if we overwrite Duration index keys, everything is read at Trace level in any case
+	durationValue := make([]byte, 8)
+	binary.BigEndian.PutUint64(durationValue, uint64(model.DurationAsMicroseconds(span.Duration)))
+	keys = append(keys, createIndexKey(durationIndexKey, durationValue, startTime, span.TraceID))
+
+	for _, kv := range span.Tags {
+		// Convert everything to string since queries are done that way also
+		// KEY: it<serviceName><tagsKey><traceId> VALUE: <tagsValue>
+		keys = append(keys, createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID))
+	}
+
+	for _, kv := range span.Process.Tags {
+		keys = append(keys, createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID))
+	}
+
+	for _, log := range span.Logs {
+		for _, kv := range log.Fields {
+			keys = append(keys, createIndexKey(tagIndexKey, []byte(span.Process.ServiceName+kv.Key+kv.AsString()), startTime, span.TraceID))
+		}
+	}
+	return keys
+}
+
 func createIndexKey(indexPrefixKey byte, value []byte, startTime uint64, traceID model.TraceID) []byte {
 	// KEY: indexKey<indexValue><startTime><traceId> (traceId is last 16 bytes of the key)
 	key := make([]byte, 1+len(value)+8+sizeOfTraceID)
@@ -27,6 +27,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/cassandra"
 	casMetrics "github.com/jaegertracing/jaeger/pkg/cassandra/metrics"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 )
 
 // Version determines which version of the dependencies table to use.
@@ -106,6 +107,20 @@ func (s *DependencyStore) WriteDependencies(ts time.Time, dependencies []model.D
 
 // GetDependencies returns all interservice dependencies
 func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	links, err := s.GetAnnotatedDependencies(ctx, endTs, lookback)
+	if err != nil {
+		return nil, err
+	}
+	var mDependency []model.DependencyLink
+	for _, link := range links {
+		mDependency = append(mDependency, link.DependencyLink)
+	}
+	return mDependency, nil
+}
+
+// GetAnnotatedDependencies implements dependencystore.AnnotatedReader#GetAnnotatedDependencies,
+// returning the interservice dependencies along with the time they were written at.
+func (s *DependencyStore) GetAnnotatedDependencies(_ context.Context, endTs time.Time, lookback time.Duration) ([]dependencystore.AnnotatedLink, error) {
 	startTs := endTs.Add(-1 * lookback)
 	var query cassandra.Query
 	switch s.version {
@@ -116,7 +131,7 @@ func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time,
 	}
 	iter := query.Consistency(cassandra.One).Iter()
 
-	var mDependency []model.DependencyLink
+	var links []dependencystore.AnnotatedLink
 	var dependencies []Dependency
 	var ts time.Time
 	for iter.Scan(&ts, &dependencies) {
@@ -127,7 +142,7 @@ func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time,
 				CallCount: uint64(dependency.CallCount),
 				Source:    dependency.Source,
 			}.ApplyDefaults()
-			mDependency = append(mDependency, dl)
+			links = append(links, dependencystore.AnnotatedLink{DependencyLink: dl, Timestamp: ts})
 		}
 	}
 
@@ -135,7 +150,7 @@ func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time,
 		s.logger.Error("Failure to read Dependencies", zap.Time("endTs", endTs), zap.Duration("lookback", lookback), zap.Error(err))
 		return nil, fmt.Errorf("error reading dependencies from storage: %w", err)
 	}
-	return mDependency, nil
+	return links, nil
 }
 
 func getBuckets(startTs time.Time, endTs time.Time) []time.Time {
@@ -61,6 +61,8 @@ func withDepStore(version Version, fn func(s *depStorageTest)) {
 var (
 	_ dependencystore.Reader = &DependencyStore{} // check API conformance
 	_ dependencystore.Writer = &DependencyStore{} // check API conformance
+
+	_ dependencystore.AnnotatedReader = &DependencyStore{} // check API conformance
 )
 
 func TestVersionIsValid(t *testing.T) {
@@ -254,6 +256,34 @@ func TestDependencyStoreGetDependencies(t *testing.T) {
 	}
 }
 
+func TestDependencyStoreGetAnnotatedDependencies(t *testing.T) {
+	withDepStore(V2, func(s *depStorageTest) {
+		written := time.Unix(300, 0)
+		scanFunc := func(args []interface{}) bool {
+			*args[0].(*time.Time) = written
+			*args[1].(*[]Dependency) = []Dependency{{Parent: "a", Child: "b", CallCount: 1}}
+			return true
+		}
+		iter := &mocks.Iterator{}
+		iter.On("Scan", mock.MatchedBy(scanFunc)).Return(true).Once()
+		iter.On("Scan", matchEverything()).Return(false)
+		iter.On("Close").Return(nil)
+
+		query := &mocks.Query{}
+		query.On("Consistency", cassandra.One).Return(query)
+		query.On("Iter").Return(iter)
+		s.session.On("Query", mock.AnythingOfType("string"), matchEverything()).Return(query)
+
+		links, err := s.storage.GetAnnotatedDependencies(context.Background(), time.Now(), 48*time.Hour)
+		require.NoError(t, err)
+		// the links are timestamped with the time they were written at
+		assert.Equal(t, []dependencystore.AnnotatedLink{{
+			DependencyLink: model.DependencyLink{Parent: "a", Child: "b", CallCount: 1, Source: model.JaegerDependencyLinkSource},
+			Timestamp:      written,
+		}}, links)
+	})
+}
+
 func TestGetBuckets(t *testing.T) {
 	var (
 		start    = time.Date(2017, time.January, 24, 11, 15, 17, 12345, time.UTC)
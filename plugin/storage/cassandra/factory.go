@@ -51,6 +51,8 @@ var ( // interface comformance checks
 	_ storage.Factory              = (*Factory)(nil)
 	_ storage.ArchiveFactory       = (*Factory)(nil)
 	_ storage.SamplingStoreFactory = (*Factory)(nil)
+	_ storage.PurgerFactory        = (*Factory)(nil)
+	_ storage.RetentionFactory     = (*Factory)(nil)
 	_ io.Closer                    = (*Factory)(nil)
 	_ plugin.Configurable          = (*Factory)(nil)
 )
@@ -68,6 +70,11 @@ type Factory struct {
 	primarySession cassandra.Session
 	archiveConfig  config.SessionBuilder
 	archiveSession cassandra.Session
+	// keyspaceConfig returns the session builder of a tenant keyspace
+	keyspaceConfig  func(keyspace string) config.SessionBuilder
+	tenantKeyspaces *tenantKeyspaces
+
+	retentionPolicies *spanstore.RetentionPolicies
 }
 
 // NewFactory creates a new Factory.
@@ -105,6 +112,7 @@ func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 func (f *Factory) InitFromViper(v *viper.Viper, logger *zap.Logger) {
 	f.Options.InitFromViper(v)
 	f.primaryConfig = f.Options.GetPrimary()
+	f.keyspaceConfig = f.Options.keyspaceConfig
 	if cfg := f.Options.Get(archiveStorageConfig); cfg != nil {
 		f.archiveConfig = cfg // this is so stupid - see https://golang.org/doc/faq#nil_error
 	}
@@ -114,6 +122,7 @@ func (f *Factory) InitFromViper(v *viper.Viper, logger *zap.Logger) {
 func (f *Factory) InitFromOptions(o *Options) {
 	f.Options = o
 	f.primaryConfig = o.GetPrimary()
+	f.keyspaceConfig = o.keyspaceConfig
 	if cfg := f.Options.Get(archiveStorageConfig); cfg != nil {
 		f.archiveConfig = cfg // this is so stupid - see https://golang.org/doc/faq#nil_error
 	}
@@ -125,12 +134,20 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	f.archiveMetricsFactory = metricsFactory.Namespace(metrics.NSOptions{Name: "cassandra-archive", Tags: nil})
 	f.logger = logger
 
+	if err := f.Options.Tenancy.Validate(); err != nil {
+		return err
+	}
+
 	primarySession, err := f.primaryConfig.NewSession(logger)
 	if err != nil {
 		return err
 	}
 	f.primarySession = primarySession
 
+	if len(f.Options.Tenancy.Keyspaces) > 0 {
+		f.tenantKeyspaces = newTenantKeyspaces(&f.Options.Tenancy, f.Options.Primary.LocalDC, f.newKeyspaceSession, logger)
+	}
+
 	if f.archiveConfig != nil {
 		if archiveSession, err := f.archiveConfig.NewSession(logger); err == nil {
 			f.archiveSession = archiveSession
@@ -143,9 +160,25 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	return nil
 }
 
+func (f *Factory) newKeyspaceSession(keyspace string) (cassandra.Session, error) {
+	return f.keyspaceConfig(keyspace).NewSession(f.logger)
+}
+
 // CreateSpanReader implements storage.Factory
 func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
-	return cSpanStore.NewSpanReader(f.primarySession, f.primaryMetricsFactory, f.logger, f.tracer.Tracer("cSpanStore.SpanReader")), nil
+	reader := f.newSpanReader(f.primarySession)
+	if f.tenantKeyspaces == nil {
+		return reader, nil
+	}
+	return &routingSpanReader{
+		router: newKeyspaceRouter(f.tenantKeyspaces, reader, func(session cassandra.Session) (spanstore.Reader, error) {
+			return f.newSpanReader(session), nil
+		}),
+	}, nil
+}
+
+func (f *Factory) newSpanReader(session cassandra.Session) spanstore.Reader {
+	return cSpanStore.NewSpanReader(session, f.primaryMetricsFactory, f.logger, f.tracer.Tracer("cSpanStore.SpanReader"))
 }
 
 // CreateSpanWriter implements storage.Factory
@@ -154,11 +187,43 @@ func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
 	if err != nil {
 		return nil, err
 	}
-	return cSpanStore.NewSpanWriter(f.primarySession, f.Options.SpanStoreWriteCacheTTL, f.primaryMetricsFactory, f.logger, options...), nil
+	if f.retentionPolicies != nil {
+		options = append(options, cSpanStore.RetentionPolicies(f.retentionPolicies))
+	}
+	writer := cSpanStore.NewSpanWriter(f.primarySession, f.Options.SpanStoreWriteCacheTTL, f.primaryMetricsFactory, f.logger, options...)
+	if f.tenantKeyspaces == nil {
+		return writer, nil
+	}
+	return &routingSpanWriter{
+		router: newKeyspaceRouter(f.tenantKeyspaces, spanstore.Writer(writer), func(session cassandra.Session) (spanstore.Writer, error) {
+			return cSpanStore.NewSpanWriter(session, f.Options.SpanStoreWriteCacheTTL, f.primaryMetricsFactory, f.logger, options...), nil
+		}),
+	}, nil
+}
+
+// SetRetentionPolicies implements storage.RetentionFactory. The archived spans keep the default TTL.
+func (f *Factory) SetRetentionPolicies(policies *spanstore.RetentionPolicies) {
+	f.retentionPolicies = policies
 }
 
 // CreateDependencyReader implements storage.Factory
 func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
+	reader, err := f.newDependencyReader(f.primarySession)
+	if err != nil || f.tenantKeyspaces == nil {
+		return reader, err
+	}
+	return &routingDependencyReader{
+		router: newKeyspaceRouter(f.tenantKeyspaces, reader, f.newDependencyReader),
+	}, nil
+}
+
+func (f *Factory) newDependencyReader(session cassandra.Session) (dependencystore.Reader, error) {
+	version := cDepStore.GetDependencyVersion(session)
+	return cDepStore.NewDependencyStore(session, f.primaryMetricsFactory, f.logger, version)
+}
+
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
 	version := cDepStore.GetDependencyVersion(f.primarySession)
 	return cDepStore.NewDependencyStore(f.primarySession, f.primaryMetricsFactory, f.logger, version)
 }
@@ -199,6 +264,12 @@ func (f *Factory) CreateSamplingStore(maxBuckets int) (samplingstore.Store, erro
 	return cSamplingStore.New(f.primarySession, f.primaryMetricsFactory, f.logger), nil
 }
 
+// CreatePurger implements storage.PurgerFactory
+func (f *Factory) CreatePurger() (spanstore.Purger, error) {
+	reader := cSpanStore.NewSpanReader(f.primarySession, f.primaryMetricsFactory, f.logger, f.tracer.Tracer("cSpanStore.SpanReader"))
+	return cSpanStore.NewSpanPurger(f.primarySession, reader), nil
+}
+
 func writerOptions(opts *Options) ([]cSpanStore.Option, error) {
 	var tagFilters []dbmodel.TagFilter
 
@@ -238,6 +309,9 @@ func (f *Factory) Close() error {
 	if f.archiveSession != nil {
 		f.archiveSession.Close()
 	}
+	if f.tenantKeyspaces != nil {
+		f.tenantKeyspaces.close()
+	}
 
 	var errs []error
 	if cfg := f.Options.Get(archiveStorageConfig); cfg != nil {
@@ -30,6 +30,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 type mockSessionBuilder struct {
@@ -84,6 +85,9 @@ func TestCassandraFactory(t *testing.T) {
 	_, err = f.CreateDependencyReader()
 	require.NoError(t, err)
 
+	_, err = f.CreateDependencyWriter()
+	require.NoError(t, err)
+
 	_, err = f.CreateArchiveSpanReader()
 	require.EqualError(t, err, "archive storage not configured")
 
@@ -105,6 +109,13 @@ func TestCassandraFactory(t *testing.T) {
 	_, err = f.CreateSamplingStore(0)
 	require.NoError(t, err)
 
+	_, err = f.CreatePurger()
+	require.NoError(t, err)
+
+	f.SetRetentionPolicies(spanstore.NewRetentionPolicies(nil))
+	_, err = f.CreateSpanWriter()
+	require.NoError(t, err)
+
 	require.NoError(t, f.Close())
 }
 
@@ -17,7 +17,9 @@ package cassandra
 
 import (
 	"flag"
+	"fmt"
 	"log"
+	"regexp"
 	"strings"
 	"time"
 
@@ -53,6 +55,12 @@ const (
 	suffixIndexLogs              = ".index.logs"
 	suffixIndexTags              = ".index.tags"
 	suffixIndexProcessTags       = ".index.process-tags"
+
+	// tenancy settings
+	suffixTenantKeyspaces         = ".tenancy.keyspaces"
+	suffixTenantCreateSchema      = ".tenancy.create-schema"
+	suffixTenantReplicationFactor = ".tenancy.replication-factor"
+	suffixTenantTraceTTL          = ".tenancy.trace-ttl"
 )
 
 // Options contains various type of Cassandra configs and provides the ability
@@ -63,6 +71,7 @@ type Options struct {
 	others                 map[string]*namespaceConfig
 	SpanStoreWriteCacheTTL time.Duration `mapstructure:"span_store_write_cache_ttl"`
 	Index                  IndexConfig   `mapstructure:"index"`
+	Tenancy                TenancyConfig `mapstructure:"tenancy"`
 }
 
 // IndexConfig configures indexing.
@@ -75,6 +84,32 @@ type IndexConfig struct {
 	TagWhiteList string `mapstructure:"tag_whitelist"`
 }
 
+// TenancyConfig configures the keyspaces of the tenants.
+// The spans of the tenants without keyspace are stored in the primary keyspace.
+type TenancyConfig struct {
+	// Keyspaces are the keyspaces of the tenants, by tenant.
+	Keyspaces map[string]string `mapstructure:"keyspaces"`
+	// CreateSchema creates the keyspace of a tenant and its tables on first use, if they do not exist.
+	CreateSchema bool `mapstructure:"create_schema"`
+	// ReplicationFactor is the replication factor of the created keyspaces,
+	// in the local data center if it is set.
+	ReplicationFactor int `mapstructure:"replication_factor"`
+	// TraceTTL is the default time to live of the spans of the created keyspaces.
+	TraceTTL time.Duration `mapstructure:"trace_ttl"`
+}
+
+// Validate checks that the keyspaces of the tenants are valid keyspace names.
+func (c *TenancyConfig) Validate() error {
+	for tenant, keyspace := range c.Keyspaces {
+		if !keyspaceName.MatchString(keyspace) {
+			return fmt.Errorf("invalid keyspace %q of tenant %q, please use letters, digits or underscores", keyspace, tenant)
+		}
+	}
+	return nil
+}
+
+var keyspaceName = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
 // the Servers field in config.Configuration is a list, which we cannot represent with flags.
 // This struct adds a plain string field that can be bound to flags and is then parsed when
 // preparing the actual config.Configuration.
@@ -103,6 +138,10 @@ func NewOptions(primaryNamespace string, otherNamespaces ...string) *Options {
 		},
 		others:                 make(map[string]*namespaceConfig, len(otherNamespaces)),
 		SpanStoreWriteCacheTTL: time.Hour * 12,
+		Tenancy: TenancyConfig{
+			ReplicationFactor: 1,
+			TraceTTL:          48 * time.Hour,
+		},
 	}
 
 	for _, namespace := range otherNamespaces {
@@ -141,6 +180,22 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 		opt.Primary.namespace+suffixIndexProcessTags,
 		!opt.Index.ProcessTags,
 		"Controls process tag indexing. Set to false to disable.")
+	flagSet.String(
+		opt.Primary.namespace+suffixTenantKeyspaces,
+		"",
+		"The comma-separated list of the keyspaces of the tenants, as tenant=keyspace pairs. The spans of the other tenants are stored in the primary keyspace")
+	flagSet.Bool(
+		opt.Primary.namespace+suffixTenantCreateSchema,
+		opt.Tenancy.CreateSchema,
+		"Creates the keyspace of a tenant and its tables on first use, if they do not exist")
+	flagSet.Int(
+		opt.Primary.namespace+suffixTenantReplicationFactor,
+		opt.Tenancy.ReplicationFactor,
+		"The replication factor of the keyspaces created for the tenants, in the local data center if it is set")
+	flagSet.Duration(
+		opt.Primary.namespace+suffixTenantTraceTTL,
+		opt.Tenancy.TraceTTL,
+		"The default time to live of the spans in the keyspaces created for the tenants")
 }
 
 func addFlags(flagSet *flag.FlagSet, nsConfig namespaceConfig) {
@@ -227,6 +282,24 @@ func (opt *Options) InitFromViper(v *viper.Viper) {
 	opt.Index.Tags = v.GetBool(opt.Primary.namespace + suffixIndexTags)
 	opt.Index.Logs = v.GetBool(opt.Primary.namespace + suffixIndexLogs)
 	opt.Index.ProcessTags = v.GetBool(opt.Primary.namespace + suffixIndexProcessTags)
+	opt.Tenancy.Keyspaces = parseTenantKeyspaces(stripWhiteSpace(v.GetString(opt.Primary.namespace + suffixTenantKeyspaces)))
+	opt.Tenancy.CreateSchema = v.GetBool(opt.Primary.namespace + suffixTenantCreateSchema)
+	opt.Tenancy.ReplicationFactor = v.GetInt(opt.Primary.namespace + suffixTenantReplicationFactor)
+	opt.Tenancy.TraceTTL = v.GetDuration(opt.Primary.namespace + suffixTenantTraceTTL)
+}
+
+// parseTenantKeyspaces parses the tenant=keyspace pairs of the tenancy.keyspaces flag.
+// A tenant without keyspace is kept with an empty keyspace, rejected by TenancyConfig.Validate.
+func parseTenantKeyspaces(pairs string) map[string]string {
+	if pairs == "" {
+		return nil
+	}
+	keyspaces := make(map[string]string)
+	for _, pair := range strings.Split(pairs, ",") {
+		tenant, keyspace, _ := strings.Cut(pair, "=")
+		keyspaces[tenant] = keyspace
+	}
+	return keyspaces
 }
 
 func tlsFlagsConfig(namespace string) tlscfg.ClientFlagsConfig {
@@ -269,6 +342,13 @@ func (opt *Options) GetPrimary() *config.Configuration {
 	return &opt.Primary.Configuration
 }
 
+// keyspaceConfig returns the primary configuration with another keyspace.
+func (opt *Options) keyspaceConfig(keyspace string) config.SessionBuilder {
+	cfg := *opt.GetPrimary()
+	cfg.Keyspace = keyspace
+	return &cfg
+}
+
 // Get returns auxiliary named configuration.
 func (opt *Options) Get(namespace string) *config.Configuration {
 	nsCfg, ok := opt.others[namespace]
@@ -96,6 +96,33 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, 42*time.Second, aux.SocketKeepAlive)
 }
 
+func TestTenancyFlags(t *testing.T) {
+	opts := NewOptions("cas")
+	v, command := config.Viperize(opts.AddFlags)
+	command.ParseFlags([]string{})
+	opts.InitFromViper(v)
+	assert.Empty(t, opts.Tenancy.Keyspaces)
+	assert.False(t, opts.Tenancy.CreateSchema)
+	assert.Equal(t, 1, opts.Tenancy.ReplicationFactor)
+	assert.Equal(t, 48*time.Hour, opts.Tenancy.TraceTTL)
+
+	command.ParseFlags([]string{
+		"--cas.tenancy.keyspaces=acme=jaeger_acme, globex=jaeger_globex",
+		"--cas.tenancy.create-schema=true",
+		"--cas.tenancy.replication-factor=3",
+		"--cas.tenancy.trace-ttl=72h",
+	})
+	opts.InitFromViper(v)
+	assert.Equal(t, map[string]string{"acme": "jaeger_acme", "globex": "jaeger_globex"}, opts.Tenancy.Keyspaces)
+	assert.True(t, opts.Tenancy.CreateSchema)
+	assert.Equal(t, 3, opts.Tenancy.ReplicationFactor)
+	assert.Equal(t, 72*time.Hour, opts.Tenancy.TraceTTL)
+	require.NoError(t, opts.Tenancy.Validate())
+
+	opts.Tenancy.Keyspaces = parseTenantKeyspaces("acme")
+	require.EqualError(t, opts.Tenancy.Validate(), `invalid keyspace "" of tenant "acme", please use letters, digits or underscores`)
+}
+
 func TestDefaultTlsHostVerify(t *testing.T) {
 	opts := NewOptions("cas")
 	v, command := config.Viperize(opts.AddFlags)
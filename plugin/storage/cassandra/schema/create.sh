@@ -30,7 +30,7 @@ if [[ "$template" == "" ]]; then
             template=$(dirname $0)/v003.cql.tmpl
             ;;
         4)
-            template=$(dirname $0)/v004.cql.tmpl
+            template=$(dirname $0)/v005.cql.tmpl
             ;;
         *)
             template=$(ls $(dirname $0)/*cql.tmpl | sort | tail -1)
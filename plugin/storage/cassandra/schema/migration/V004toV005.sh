@@ -0,0 +1,41 @@
+#!/usr/bin/env bash
+
+# Add the tags field storing the attributes of the OTLP span links to the span_ref type
+# Sample usage: KEYSPACE=jaeger_v1 CQL_CMD='cqlsh host 9042 -u test_user -p test_password' bash
+# ./V004toV005.sh
+
+set -euo pipefail
+
+function usage {
+    >&2 echo "Error: $1"
+    >&2 echo ""
+    >&2 echo "Usage: KEYSPACE={keyspace} CQL_CMD={cql_cmd} $0"
+    >&2 echo ""
+    >&2 echo "The following parameters can be set via environment:"
+    >&2 echo "  KEYSPACE           - keyspace"
+    >&2 echo "  CQL_CMD            - cqlsh host port -u user -p password"
+    >&2 echo ""
+    exit 1
+}
+
+if [[ ${KEYSPACE:-} == "" ]]; then
+   usage "missing KEYSPACE parameter"
+fi
+
+if [[ ${KEYSPACE} =~ [^a-zA-Z0-9_] ]]; then
+    usage "invalid characters in KEYSPACE=$KEYSPACE parameter, please use letters, digits or underscores"
+fi
+
+keyspace=${KEYSPACE}
+cqlsh_cmd=${CQL_CMD:-cqlsh}
+
+echo "Using cql command: $cqlsh_cmd"
+
+if ${cqlsh_cmd} -e "DESCRIBE TYPE $keyspace.span_ref;" | grep -q "tags"; then
+    echo "The span_ref type of keyspace $keyspace already has the tags field"
+    exit 0
+fi
+
+${cqlsh_cmd} -e "ALTER TYPE $keyspace.span_ref ADD tags frozen<list<frozen<$keyspace.keyvalue>>>;"
+
+echo "Added the tags field to the span_ref type of keyspace $keyspace"
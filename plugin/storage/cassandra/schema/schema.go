@@ -0,0 +1,55 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	_ "embed"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed v005.cql.tmpl
+var v005 string
+
+var (
+	comments   = regexp.MustCompile(`--.*`)
+	parameters = regexp.MustCompile(`\$\{(\w+)\}`)
+)
+
+// Params are the parameters of the schema template, like the parameters of create.sh.
+type Params struct {
+	Keyspace string
+	// Replication is the replication strategy of the keyspace,
+	// e.g. {'class': 'SimpleStrategy', 'replication_factor': '1'}.
+	Replication     string
+	TraceTTL        time.Duration
+	DependenciesTTL time.Duration
+}
+
+// Statements returns the CQL statements creating the keyspace and the tables of the latest
+// version of the schema. The statements do nothing for the objects that already exist.
+func Statements(params Params) []string {
+	traceTTL := int(params.TraceTTL.Seconds())
+	// the compaction window is the ceiling of 1/30 of the trace TTL, in minutes
+	values := map[string]string{
+		"keyspace":               params.Keyspace,
+		"replication":            params.Replication,
+		"trace_ttl":              strconv.Itoa(traceTTL),
+		"dependencies_ttl":       strconv.Itoa(int(params.DependenciesTTL.Seconds())),
+		"compaction_window_size": strconv.Itoa((traceTTL/60 + 30 - 1) / 30),
+		"compaction_window_unit": "MINUTES",
+	}
+	cql := parameters.ReplaceAllStringFunc(comments.ReplaceAllString(v005, ""), func(p string) string {
+		return values[parameters.FindStringSubmatch(p)[1]]
+	})
+	var statements []string
+	for _, statement := range strings.Split(cql, ";") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatements(t *testing.T) {
+	statements := Statements(Params{
+		Keyspace:        "jaeger_tenant_a",
+		Replication:     "{'class': 'SimpleStrategy', 'replication_factor': '1'}",
+		TraceTTL:        48 * time.Hour,
+		DependenciesTTL: 0,
+	})
+	require.NotEmpty(t, statements)
+	assert.Equal(t,
+		"CREATE KEYSPACE IF NOT EXISTS jaeger_tenant_a WITH replication = {'class': 'SimpleStrategy', 'replication_factor': '1'}",
+		statements[0])
+	for _, statement := range statements {
+		assert.NotContains(t, statement, "${", statement)
+		assert.NotContains(t, statement, "--", statement)
+		assert.True(t, strings.HasPrefix(statement, "CREATE "), statement)
+	}
+	cql := strings.Join(statements, ";\n")
+	assert.Contains(t, cql, "CREATE TABLE IF NOT EXISTS jaeger_tenant_a.traces")
+	assert.Contains(t, cql, "CREATE TABLE IF NOT EXISTS jaeger_tenant_a.dependencies_v2")
+	// the span references store the attributes of the span links since v005
+	assert.Regexp(t, `span_ref \([^)]*tags\s+frozen<list<frozen<jaeger_tenant_a\.keyvalue>>>\s*\)`, cql)
+	assert.Contains(t, cql, "default_time_to_live = 172800")
+	assert.Contains(t, cql, "default_time_to_live = 0")
+	// 48h is 2880 minutes, i.e. a compaction window of 96 minutes
+	assert.Contains(t, cql, "'compaction_window_size': '96'")
+	assert.Contains(t, cql, "'compaction_window_unit': 'MINUTES'")
+}
@@ -198,6 +198,13 @@ func (c converter) fromDBRefs(refs []SpanRef) ([]model.SpanRef, error) {
 			TraceID: r.TraceID.ToDomain(),
 			SpanID:  model.NewSpanID(uint64(r.SpanID)),
 		}
+		if len(r.Tags) > 0 {
+			tags, err := c.fromDBTags(r.Tags)
+			if err != nil {
+				return nil, err
+			}
+			retMe[i].Tags = tags
+		}
 	}
 	return retMe, nil
 }
@@ -262,6 +269,9 @@ func (c converter) toDBRefs(refs []model.SpanRef) []SpanRef {
 			SpanID:  int64(r.SpanID),
 			RefType: domainToDBRefMap[r.RefType],
 		}
+		if len(r.Tags) > 0 {
+			retMe[i].Tags = c.toDBTags(r.Tags)
+		}
 	}
 	return retMe
 }
@@ -245,6 +245,27 @@ func TestFailingFromDBSpanBadRefs(t *testing.T) {
 	failingDBSpanTransform(t, faultyDBRefs, "invalid SpanRefType in")
 }
 
+func TestRefsWithTags(t *testing.T) {
+	jSpan := getTestJaegerSpan()
+	jSpan.References = []model.SpanRef{
+		{
+			TraceID: someTraceID,
+			SpanID:  someParentSpanID,
+			RefType: model.FollowsFrom,
+			Tags:    someTags,
+		},
+	}
+	dbSpan := FromDomain(jSpan)
+	require.Len(t, dbSpan.Refs, 1)
+	assert.Equal(t, someDBTags, dbSpan.Refs[0].Tags)
+	actualJSpan, err := ToDomain(dbSpan)
+	require.NoError(t, err)
+	assert.Equal(t, jSpan.References, actualJSpan.References)
+
+	dbSpan.Refs[0].Tags = badDBTags
+	failingDBSpanTransform(t, dbSpan, notValidTagTypeErrStr)
+}
+
 func failingDBSpanTransform(t *testing.T, dbSpan *Span, errMsg string) {
 	jSpan, err := ToDomain(dbSpan)
 	assert.Nil(t, jSpan)
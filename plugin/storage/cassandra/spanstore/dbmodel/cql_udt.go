@@ -116,6 +116,8 @@ func (s *SpanRef) MarshalUDT(name string, info gocql.TypeInfo) ([]byte, error) {
 		return gocql.Marshal(info, s.TraceID)
 	case "span_id":
 		return gocql.Marshal(info, s.SpanID)
+	case "tags":
+		return gocql.Marshal(info, s.Tags)
 	default:
 		return nil, fmt.Errorf("unknown column for position: %q", name)
 	}
@@ -130,6 +132,8 @@ func (s *SpanRef) UnmarshalUDT(name string, info gocql.TypeInfo, data []byte) er
 		return gocql.Unmarshal(info, data, &s.TraceID)
 	case "span_id":
 		return gocql.Unmarshal(info, data, &s.SpanID)
+	case "tags":
+		return gocql.Unmarshal(info, data, &s.Tags)
 	default:
 		return fmt.Errorf("unknown column for position: %q", name)
 	}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/gocql/gocql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/cassandra/gocql/testutils"
@@ -90,6 +91,7 @@ func TestDBModelUDTMarshall(t *testing.T) {
 				{Name: "ref_type", Type: gocql.TypeAscii, ValIn: []byte("childOf"), Err: false},
 				{Name: "trace_id", Type: gocql.TypeBlob, ValIn: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, Err: false},
 				{Name: "span_id", Type: gocql.TypeBigInt, ValIn: []byte{0, 0, 0, 0, 0, 0, 0, 123}, Err: false},
+				{Name: "tags", Type: gocql.TypeAscii, Err: true}, // for coverage only
 				{Name: "wrong-field", Err: true},
 			},
 		},
@@ -111,3 +113,60 @@ func TestDBModelUDTMarshall(t *testing.T) {
 	dbid := TraceID{}
 	assert.Equal(t, ErrTraceIDWrongLength, dbid.UnmarshalCQL(nil, nil))
 }
+
+func TestSpanRefUDTRoundTrip(t *testing.T) {
+	const proto = 0x03
+	keyValue := gocql.UDTTypeInfo{
+		NativeType: gocql.NewNativeType(proto, gocql.TypeUDT, ""),
+		Name:       "keyvalue",
+		Elements: []gocql.UDTField{
+			{Name: "key", Type: gocql.NewNativeType(proto, gocql.TypeText, "")},
+			{Name: "value_type", Type: gocql.NewNativeType(proto, gocql.TypeText, "")},
+			{Name: "value_string", Type: gocql.NewNativeType(proto, gocql.TypeText, "")},
+			{Name: "value_bool", Type: gocql.NewNativeType(proto, gocql.TypeBoolean, "")},
+			{Name: "value_long", Type: gocql.NewNativeType(proto, gocql.TypeBigInt, "")},
+			{Name: "value_double", Type: gocql.NewNativeType(proto, gocql.TypeDouble, "")},
+			{Name: "value_binary", Type: gocql.NewNativeType(proto, gocql.TypeBlob, "")},
+		},
+	}
+	spanRefElements := []gocql.UDTField{
+		{Name: "ref_type", Type: gocql.NewNativeType(proto, gocql.TypeText, "")},
+		{Name: "trace_id", Type: gocql.NewNativeType(proto, gocql.TypeBlob, "")},
+		{Name: "span_id", Type: gocql.NewNativeType(proto, gocql.TypeBigInt, "")},
+	}
+	withoutTags := gocql.UDTTypeInfo{
+		NativeType: gocql.NewNativeType(proto, gocql.TypeUDT, ""),
+		Name:       "span_ref",
+		Elements:   spanRefElements,
+	}
+	withTags := withoutTags
+	withTags.Elements = append(spanRefElements, gocql.UDTField{
+		Name: "tags",
+		Type: gocql.CollectionType{NativeType: gocql.NewNativeType(proto, gocql.TypeList, ""), Elem: keyValue},
+	})
+
+	spanRef := &SpanRef{
+		RefType: followsFrom,
+		TraceID: TraceIDFromDomain(model.NewTraceID(0, 1)),
+		SpanID:  123,
+		Tags: []KeyValue{
+			{Key: "str", ValueType: stringType, ValueString: "value"},
+			{Key: "int", ValueType: int64Type, ValueInt64: 42},
+		},
+	}
+
+	data, err := gocql.Marshal(withTags, spanRef)
+	require.NoError(t, err)
+	var actual SpanRef
+	require.NoError(t, gocql.Unmarshal(withTags, data, &actual))
+	assert.Equal(t, *spanRef, actual)
+
+	// the schemas created before the tags were added to the span references do not store them
+	data, err = gocql.Marshal(withoutTags, spanRef)
+	require.NoError(t, err)
+	actual = SpanRef{}
+	require.NoError(t, gocql.Unmarshal(withoutTags, data, &actual))
+	expected := *spanRef
+	expected.Tags = nil
+	assert.Equal(t, expected, actual)
+}
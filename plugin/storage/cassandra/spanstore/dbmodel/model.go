@@ -72,9 +72,10 @@ type Log struct {
 
 // SpanRef is the UDT representation of a Jaeger Span Reference.
 type SpanRef struct {
-	RefType string  `cql:"ref_type"`
-	TraceID TraceID `cql:"trace_id"`
-	SpanID  int64   `cql:"span_id"`
+	RefType string     `cql:"ref_type"`
+	TraceID TraceID    `cql:"trace_id"`
+	SpanID  int64      `cql:"span_id"`
+	Tags    []KeyValue `cql:"tags"` // not stored with the schemas created before the tags were added to span_ref
 }
 
 // Process is the UDT representation of a Jaeger Process.
@@ -0,0 +1,136 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	deleteTrace = `DELETE FROM traces WHERE trace_id = ?`
+
+	// the entries of the service and operation indexes are only keyed by their start time,
+	// they are deleted if they still point to the purged trace
+	deleteServiceNameIndex = `
+		DELETE FROM service_name_index
+		WHERE service_name = ? AND bucket = ? AND start_time = ?
+		IF trace_id = ?`
+
+	deleteServiceOperationIndex = `
+		DELETE FROM service_operation_index
+		WHERE service_name = ? AND operation_name = ? AND start_time = ?
+		IF trace_id = ?`
+
+	deleteTagIndex = `
+		DELETE FROM tag_index
+		WHERE service_name = ? AND tag_key = ? AND tag_value = ? AND start_time = ? AND trace_id = ? AND span_id = ?`
+
+	deleteDurationIndex = `
+		DELETE FROM duration_index
+		WHERE service_name = ? AND operation_name = ? AND bucket = ? AND duration = ? AND start_time = ? AND trace_id = ?`
+
+	truncateTable = `TRUNCATE %s`
+
+	// purgeBatchSize is the number of traces searched at once by PurgeTraces
+	purgeBatchSize = 1000
+
+	// defaultPurgeLookback is the time range of the purge queries without a start time,
+	// the duration index is searched hour by hour so the range cannot be unbounded
+	defaultPurgeLookback = 30 * 24 * time.Hour
+)
+
+// purgedTables are truncated by PurgeAll. The service and operation names are kept,
+// they expire with the TTL of their tables.
+var purgedTables = []string{
+	"traces",
+	"service_name_index",
+	"service_operation_index",
+	"tag_index",
+	"duration_index",
+}
+
+// SpanPurger deletes the spans of the traces and their index entries from Cassandra
+type SpanPurger struct {
+	session cassandra.Session
+	reader  *SpanReader
+}
+
+// NewSpanPurger returns a SpanPurger finding the traces to delete with the reader
+func NewSpanPurger(session cassandra.Session, reader *SpanReader) *SpanPurger {
+	return &SpanPurger{
+		session: session,
+		reader:  reader,
+	}
+}
+
+// PurgeTraces implements spanstore.Purger
+func (p *SpanPurger) PurgeTraces(ctx context.Context, query *spanstore.TraceQueryParameters) (int, error) {
+	if err := spanstore.ValidatePurgeQuery(query); err != nil {
+		return 0, err
+	}
+	q := *query
+	if q.StartTimeMax.IsZero() {
+		q.StartTimeMax = time.Now()
+	}
+	if q.StartTimeMin.IsZero() {
+		q.StartTimeMin = q.StartTimeMax.Add(-defaultPurgeLookback)
+	}
+	q.NumTraces = purgeBatchSize
+	return spanstore.PurgeFoundTraces(ctx, &q, p.reader.FindTraceIDs, p.deleteTraces)
+}
+
+func (p *SpanPurger) deleteTraces(ctx context.Context, traceIDs []model.TraceID) error {
+	for _, traceID := range traceIDs {
+		dbTraceID := dbmodel.TraceIDFromDomain(traceID)
+		// the index entries are recreated from the spans, before the spans are deleted
+		err := p.reader.scanTrace(ctx, dbTraceID, p.deleteIndexes)
+		if err != nil && !errors.Is(err, spanstore.ErrTraceNotFound) {
+			return err
+		}
+		if err := p.session.Query(deleteTrace, dbTraceID).Exec(); err != nil {
+			return fmt.Errorf("failed to delete the spans of the trace %s: %w", traceID, err)
+		}
+	}
+	return nil
+}
+
+func (p *SpanPurger) deleteIndexes(span *model.Span) error {
+	ds := dbmodel.FromDomain(span)
+	queries := []cassandra.Query{
+		p.session.Query(deleteServiceNameIndex, ds.ServiceName, uint64(ds.SpanHash)%defaultNumBuckets, ds.StartTime, ds.TraceID),
+		p.session.Query(deleteServiceOperationIndex, ds.ServiceName, ds.OperationName, ds.StartTime, ds.TraceID),
+	}
+	timeBucket := span.StartTime.Round(durationBucketSize)
+	for _, operationName := range []string{"", ds.OperationName} {
+		queries = append(queries, p.session.Query(deleteDurationIndex, ds.ServiceName, operationName, timeBucket, ds.Duration, ds.StartTime, ds.TraceID))
+	}
+	// all the tags are deleted, whatever the tag filter of the writer
+	for _, tag := range dbmodel.GetAllUniqueTags(span, dbmodel.DefaultTagFilter) {
+		queries = append(queries, p.session.Query(deleteTagIndex, tag.ServiceName, tag.TagKey, tag.TagValue, ds.StartTime, ds.TraceID, ds.SpanID))
+	}
+	for _, query := range queries {
+		if err := query.Exec(); err != nil {
+			return fmt.Errorf("failed to delete the index entries of the trace %s: %w", span.TraceID, err)
+		}
+	}
+	return nil
+}
+
+// PurgeAll implements spanstore.Purger
+func (p *SpanPurger) PurgeAll(context.Context) error {
+	for _, table := range purgedTables {
+		if err := p.session.Query(fmt.Sprintf(truncateTable, table)).Exec(); err != nil {
+			return fmt.Errorf("failed to truncate the table %s: %w", table, err)
+		}
+	}
+	return nil
+}
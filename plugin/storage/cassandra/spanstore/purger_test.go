@@ -0,0 +1,114 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/pkg/cassandra/mocks"
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// purgeSession answers the index searches with the trace, the trace with one span,
+// and records the first word and table of the executed statements.
+type purgeSession struct {
+	traceID  dbmodel.TraceID
+	execErr  error
+	executed []string
+}
+
+func (s *purgeSession) query(stmt string, _ ...any) cassandra.Query {
+	fields := strings.Fields(stmt)
+	iter := &mocks.Iterator{}
+	iter.On("Close").Return(nil)
+	switch {
+	case fields[0] == "SELECT" && fields[1] == "trace_id": // the index searches
+		iter.On("Scan", matchOnceWithSideEffect(func(args []any) {
+			*args[0].(*dbmodel.TraceID) = s.traceID
+		})).Return(true)
+		iter.On("Scan", matchEverything()).Return(false)
+	case fields[0] == "SELECT": // the spans of the trace
+		iter.On("Scan", matchOnceWithSideEffect(func(args []any) {
+			*args[0].(*dbmodel.TraceID) = s.traceID
+			*args[3].(*string) = "checkout"
+			*args[7].(*[]dbmodel.KeyValue) = []dbmodel.KeyValue{{Key: "user.id", ValueType: "string", ValueString: "42"}}
+			*args[10].(*dbmodel.Process) = dbmodel.Process{ServiceName: "frontend"}
+		})).Return(true)
+		iter.On("Scan", matchEverything()).Return(false)
+	}
+	query := &mocks.Query{}
+	query.On("PageSize", 0).Return(query)
+	query.On("Iter").Return(iter)
+	query.On("String").Return(stmt)
+	query.On("Exec").Run(func(mock.Arguments) {
+		if fields[0] == "TRUNCATE" {
+			s.executed = append(s.executed, fields[0]+" "+fields[1])
+		} else {
+			s.executed = append(s.executed, fields[0]+" "+fields[2])
+		}
+	}).Return(s.execErr)
+	return query
+}
+
+func withSpanPurger(t *testing.T, session *purgeSession, fn func(p *SpanPurger)) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		r.session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(session.query)
+		fn(NewSpanPurger(r.session, r.reader))
+	})
+}
+
+func TestSpanPurgerPurgeTraces(t *testing.T) {
+	session := &purgeSession{traceID: dbmodel.TraceIDFromDomain(model.NewTraceID(0, 1))}
+	withSpanPurger(t, session, func(p *SpanPurger) {
+		purged, err := p.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "frontend"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, []string{
+			"DELETE service_name_index",
+			"DELETE service_operation_index",
+			"DELETE duration_index",
+			"DELETE duration_index",
+			"DELETE tag_index",
+			"DELETE traces",
+		}, session.executed)
+	})
+}
+
+func TestSpanPurgerPurgeTracesErrors(t *testing.T) {
+	session := &purgeSession{traceID: dbmodel.TraceIDFromDomain(model.NewTraceID(0, 1)), execErr: assert.AnError}
+	withSpanPurger(t, session, func(p *SpanPurger) {
+		_, err := p.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{})
+		require.ErrorIs(t, err, spanstore.ErrPurgeQueryServiceRequired)
+
+		_, err = p.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "frontend"})
+		require.ErrorIs(t, err, assert.AnError)
+		require.ErrorContains(t, err, "failed to delete the index entries of the trace")
+	})
+}
+
+func TestSpanPurgerPurgeAll(t *testing.T) {
+	session := &purgeSession{}
+	withSpanPurger(t, session, func(p *SpanPurger) {
+		require.NoError(t, p.PurgeAll(context.Background()))
+		assert.Equal(t, []string{
+			"TRUNCATE traces",
+			"TRUNCATE service_name_index",
+			"TRUNCATE service_operation_index",
+			"TRUNCATE tag_index",
+			"TRUNCATE duration_index",
+		}, session.executed)
+
+		session.execErr = assert.AnError
+		require.ErrorIs(t, p.PurgeAll(context.Background()), assert.AnError)
+	})
+}
@@ -69,6 +69,8 @@ const (
 	// limitMultiple exists because many spans that are returned from indices can have the same trace, limitMultiple increases
 	// the number of responses from the index, so we can respect the user's limit value they provided.
 	limitMultiple = 3
+	// streamBatchSize is the number of spans passed on at once by StreamTrace.
+	streamBatchSize = 1000
 )
 
 var (
@@ -165,6 +167,19 @@ func (s *SpanReader) readTrace(ctx context.Context, traceID dbmodel.TraceID) (*m
 }
 
 func (s *SpanReader) readTraceInSpan(ctx context.Context, traceID dbmodel.TraceID) (*model.Trace, error) {
+	retMe := &model.Trace{}
+	err := s.scanTrace(ctx, traceID, func(span *model.Span) error {
+		retMe.Spans = append(retMe.Spans, span)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return retMe, nil
+}
+
+// scanTrace passes the spans of the trace to yield one by one, as they are read from storage.
+func (s *SpanReader) scanTrace(_ context.Context, traceID dbmodel.TraceID, yield func(*model.Span) error) error {
 	start := time.Now()
 	q := s.session.Query(querySpanByTraceID, traceID)
 	i := q.Iter()
@@ -176,7 +191,7 @@ func (s *SpanReader) readTraceInSpan(ctx context.Context, traceID dbmodel.TraceI
 	var refs []dbmodel.SpanRef
 	var tags []dbmodel.KeyValue
 	var logs []dbmodel.Log
-	retMe := &model.Trace{}
+	found := false
 	for i.Scan(&traceIDFromSpan, &spanID, &parentID, &operationName, &flags, &startTime, &duration, &tags, &logs, &refs, &dbProcess) {
 		dbSpan := dbmodel.Span{
 			TraceID:       traceIDFromSpan,
@@ -193,22 +208,26 @@ func (s *SpanReader) readTraceInSpan(ctx context.Context, traceID dbmodel.TraceI
 			ServiceName:   dbProcess.ServiceName,
 		}
 		span, err := dbmodel.ToDomain(&dbSpan)
+		if err == nil {
+			found = true
+			err = yield(span)
+		}
 		if err != nil {
+			i.Close()
 			s.metrics.readTraces.Emit(err, time.Since(start))
-			return nil, err
+			return err
 		}
-		retMe.Spans = append(retMe.Spans, span)
 	}
 
 	err := i.Close()
 	s.metrics.readTraces.Emit(err, time.Since(start))
 	if err != nil {
-		return nil, fmt.Errorf("error reading traces from storage: %w", err)
+		return fmt.Errorf("error reading traces from storage: %w", err)
 	}
-	if len(retMe.Spans) == 0 {
-		return nil, spanstore.ErrTraceNotFound
+	if !found {
+		return spanstore.ErrTraceNotFound
 	}
-	return retMe, nil
+	return nil
 }
 
 // GetTrace takes a traceID and returns a Trace associated with that traceID
@@ -216,6 +235,30 @@ func (s *SpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*mode
 	return s.readTrace(ctx, dbmodel.TraceIDFromDomain(traceID))
 }
 
+// StreamTrace implements spanstore.StreamingReader. The spans are passed on in batches
+// while the query results are paged from storage, so the trace is never fully loaded.
+func (s *SpanReader) StreamTrace(ctx context.Context, traceID model.TraceID, yield func([]*model.Span) error) error {
+	ctx, span := s.startSpanForQuery(ctx, "streamTrace", querySpanByTraceID)
+	defer span.End()
+	span.SetAttributes(attribute.Key("trace_id").String(traceID.String()))
+
+	batch := make([]*model.Span, 0, streamBatchSize)
+	err := s.scanTrace(ctx, dbmodel.TraceIDFromDomain(traceID), func(span *model.Span) error {
+		batch = append(batch, span)
+		if len(batch) < streamBatchSize {
+			return nil
+		}
+		err := yield(batch)
+		batch = make([]*model.Span, 0, streamBatchSize)
+		return err
+	})
+	if err == nil && len(batch) > 0 {
+		err = yield(batch)
+	}
+	logErrorToSpan(span, err)
+	return err
+}
+
 func validateQuery(p *spanstore.TraceQueryParameters) error {
 	if p == nil {
 		return ErrMalformedRequestObject
@@ -256,6 +299,15 @@ func (s *SpanReader) FindTraces(ctx context.Context, traceQuery *spanstore.Trace
 	return retMe, nil
 }
 
+// FindTracesPage implements spanstore.PaginatedReader. The index tables are clustered by
+// the start time of the indexed spans in descending order, so pages are cut at the start time
+// of the latest span of the service and operation of the query in the last trace on the previous
+// page. Since results are merged across several index tables, and the duration index is only
+// bucketed by hour, the ordering between pages is best-effort.
+func (s *SpanReader) FindTracesPage(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) (*spanstore.TracesPage, error) {
+	return spanstore.PaginateBySortKey(ctx, s.FindTraces, traceQuery, spanstore.ByMatchingSpanStartTime)
+}
+
 // FindTraceIDs retrieve traceIDs that match the traceQuery
 func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
 	if err := validateQuery(traceQuery); err != nil {
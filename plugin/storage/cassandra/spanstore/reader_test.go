@@ -186,6 +186,66 @@ func TestSpanReaderGetTrace_TraceNotFound(t *testing.T) {
 	})
 }
 
+func TestSpanReaderStreamTrace(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		iter := &mocks.Iterator{}
+		iter.On("Scan", matchEverything()).Return(true).Times(streamBatchSize + 1)
+		iter.On("Scan", matchEverything()).Return(false)
+		iter.On("Close").Return(nil)
+
+		query := &mocks.Query{}
+		query.On("Iter").Return(iter)
+
+		r.session.On("Query", mock.AnythingOfType("string"), matchEverything()).Return(query)
+
+		var batches []int
+		err := r.reader.StreamTrace(context.Background(), model.TraceID{}, func(spans []*model.Span) error {
+			batches = append(batches, len(spans))
+			return nil
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
+		assert.Equal(t, []int{streamBatchSize, 1}, batches)
+	})
+}
+
+func TestSpanReaderStreamTrace_Errors(t *testing.T) {
+	yieldErr := errors.New("yield error")
+	testCases := []struct {
+		caption     string
+		scans       int
+		yieldErr    error
+		expectedErr error
+	}{
+		{caption: "not found", expectedErr: spanstore.ErrTraceNotFound},
+		{caption: "yield error", scans: streamBatchSize, yieldErr: yieldErr, expectedErr: yieldErr},
+		{caption: "last batch yield error", scans: 1, yieldErr: yieldErr, expectedErr: yieldErr},
+	}
+	for _, tc := range testCases {
+		testCase := tc // capture loop var
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				iter := &mocks.Iterator{}
+				if testCase.scans > 0 {
+					iter.On("Scan", matchEverything()).Return(true).Times(testCase.scans)
+				}
+				iter.On("Scan", matchEverything()).Return(false)
+				iter.On("Close").Return(nil)
+
+				query := &mocks.Query{}
+				query.On("Iter").Return(iter)
+
+				r.session.On("Query", mock.AnythingOfType("string"), matchEverything()).Return(query)
+
+				err := r.reader.StreamTrace(context.Background(), model.TraceID{}, func([]*model.Span) error {
+					return testCase.yieldErr
+				})
+				require.ErrorIs(t, err, testCase.expectedErr)
+			})
+		})
+	}
+}
+
 func TestSpanReaderFindTracesBadRequest(t *testing.T) {
 	withSpanReader(t, func(r *spanReaderTest) {
 		_, err := r.reader.FindTraces(context.Background(), nil)
@@ -29,7 +29,9 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/cassandra"
 	casMetrics "github.com/jaegertracing/jaeger/pkg/cassandra/metrics"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 const (
@@ -60,6 +62,9 @@ const (
 		INTO duration_index(service_name, operation_name, bucket, duration, start_time, trace_id)
 		VALUES (?, ?, ?, ?, ?, ?)`
 
+	// usingTTL overrides the default TTL of the table with the TTL of a retention policy
+	usingTTL = ` USING TTL ?`
+
 	maximumTagKeyOrValueSize = 256
 
 	// DefaultNumBuckets Number of buckets for bucketed keys
@@ -98,6 +103,7 @@ type SpanWriter struct {
 	tagFilter            dbmodel.TagFilter
 	storageMode          storageMode
 	indexFilter          dbmodel.IndexFilter
+	retentionPolicies    *spanstore.RetentionPolicies
 }
 
 // NewSpanWriter returns a SpanWriter
@@ -123,11 +129,12 @@ func NewSpanWriter(
 			serviceOperationIndex: casMetrics.NewTable(metricsFactory, "service_operation_index"),
 			durationIndex:         casMetrics.NewTable(metricsFactory, "duration_index"),
 		},
-		logger:          logger,
-		tagIndexSkipped: tagIndexSkipped,
-		tagFilter:       opts.tagFilter,
-		storageMode:     opts.storageMode,
-		indexFilter:     opts.indexFilter,
+		logger:            logger,
+		tagIndexSkipped:   tagIndexSkipped,
+		tagFilter:         opts.tagFilter,
+		storageMode:       opts.storageMode,
+		indexFilter:       opts.indexFilter,
+		retentionPolicies: opts.retentionPolicies,
 	}
 }
 
@@ -140,22 +147,34 @@ func (s *SpanWriter) Close() error {
 // WriteSpan saves the span into Cassandra
 func (s *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
 	ds := dbmodel.FromDomain(span)
+	// the spans without retention policy are stored with the default TTL of the tables
+	ttl := int(s.retentionPolicies.TTL(tenancy.GetTenant(ctx), span.Process.ServiceName, 0).Seconds())
 	if s.storageMode&storeFlag == storeFlag {
-		if err := s.writeSpan(span, ds); err != nil {
+		if err := s.writeSpan(span, ds, ttl); err != nil {
 			return err
 		}
 	}
 	if s.storageMode&indexFlag == indexFlag {
-		if err := s.writeIndexes(span, ds); err != nil {
+		if err := s.writeIndexes(span, ds, ttl); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *SpanWriter) writeSpan(span *model.Span, ds *dbmodel.Span) error {
-	mainQuery := s.session.Query(
+// withTTL returns the insert statement and its values storing the rows with the ttl in seconds,
+// or with the default TTL of the table if the ttl is zero
+func withTTL(stmt string, ttl int, values ...interface{}) (string, []interface{}) {
+	if ttl == 0 {
+		return stmt, values
+	}
+	return stmt + usingTTL, append(values, ttl)
+}
+
+func (s *SpanWriter) writeSpan(span *model.Span, ds *dbmodel.Span, ttl int) error {
+	stmt, values := withTTL(
 		insertSpan,
+		ttl,
 		ds.TraceID,
 		ds.SpanID,
 		ds.SpanHash,
@@ -169,13 +188,14 @@ func (s *SpanWriter) writeSpan(span *model.Span, ds *dbmodel.Span) error {
 		ds.Refs,
 		ds.Process,
 	)
+	mainQuery := s.session.Query(stmt, values...)
 	if err := s.writerMetrics.traces.Exec(mainQuery, s.logger); err != nil {
 		return s.logError(ds, err, "Failed to insert span", s.logger)
 	}
 	return nil
 }
 
-func (s *SpanWriter) writeIndexes(span *model.Span, ds *dbmodel.Span) error {
+func (s *SpanWriter) writeIndexes(span *model.Span, ds *dbmodel.Span, ttl int) error {
 	spanKind, _ := span.GetSpanKind()
 	if err := s.saveServiceNameAndOperationName(dbmodel.Operation{
 		ServiceName:   ds.ServiceName,
@@ -187,13 +207,13 @@ func (s *SpanWriter) writeIndexes(span *model.Span, ds *dbmodel.Span) error {
 	}
 
 	if s.indexFilter(ds, dbmodel.ServiceIndex) {
-		if err := s.indexByService(ds); err != nil {
+		if err := s.indexByService(ds, ttl); err != nil {
 			return s.logError(ds, err, "Failed to index service name", s.logger)
 		}
 	}
 
 	if s.indexFilter(ds, dbmodel.OperationIndex) {
-		if err := s.indexByOperation(ds); err != nil {
+		if err := s.indexByOperation(ds, ttl); err != nil {
 			return s.logError(ds, err, "Failed to index operation name", s.logger)
 		}
 	}
@@ -202,24 +222,25 @@ func (s *SpanWriter) writeIndexes(span *model.Span, ds *dbmodel.Span) error {
 		return nil // skipping expensive indexing
 	}
 
-	if err := s.indexByTags(span, ds); err != nil {
+	if err := s.indexByTags(span, ds, ttl); err != nil {
 		return s.logError(ds, err, "Failed to index tags", s.logger)
 	}
 
 	if s.indexFilter(ds, dbmodel.DurationIndex) {
-		if err := s.indexByDuration(ds, span.StartTime); err != nil {
+		if err := s.indexByDuration(ds, span.StartTime, ttl); err != nil {
 			return s.logError(ds, err, "Failed to index duration", s.logger)
 		}
 	}
 	return nil
 }
 
-func (s *SpanWriter) indexByTags(span *model.Span, ds *dbmodel.Span) error {
+func (s *SpanWriter) indexByTags(span *model.Span, ds *dbmodel.Span, ttl int) error {
 	for _, v := range dbmodel.GetAllUniqueTags(span, s.tagFilter) {
 		// we should introduce retries or just ignore failures imo, retrying each individual tag insertion might be better
 		// we should consider bucketing.
 		if s.shouldIndexTag(v) {
-			insertTagQuery := s.session.Query(tagIndex, ds.TraceID, ds.SpanID, v.ServiceName, ds.StartTime, v.TagKey, v.TagValue)
+			stmt, values := withTTL(tagIndex, ttl, ds.TraceID, ds.SpanID, v.ServiceName, ds.StartTime, v.TagKey, v.TagValue)
+			insertTagQuery := s.session.Query(stmt, values...)
 			if err := s.writerMetrics.tagIndex.Exec(insertTagQuery, s.logger); err != nil {
 				withTagInfo := s.logger.
 					With(zap.String("tag_key", v.TagKey)).
@@ -234,12 +255,14 @@ func (s *SpanWriter) indexByTags(span *model.Span, ds *dbmodel.Span) error {
 	return nil
 }
 
-func (s *SpanWriter) indexByDuration(span *dbmodel.Span, startTime time.Time) error {
-	query := s.session.Query(durationIndex)
+func (s *SpanWriter) indexByDuration(span *dbmodel.Span, startTime time.Time, ttl int) error {
+	stmt, _ := withTTL(durationIndex, ttl)
+	query := s.session.Query(stmt)
 	timeBucket := startTime.Round(durationBucketSize)
 	var err error
 	indexByOperationName := func(operationName string) {
-		q1 := query.Bind(span.Process.ServiceName, operationName, timeBucket, span.Duration, span.StartTime, span.TraceID)
+		_, values := withTTL(durationIndex, ttl, span.Process.ServiceName, operationName, timeBucket, span.Duration, span.StartTime, span.TraceID)
+		q1 := query.Bind(values...)
 		if err2 := s.writerMetrics.durationIndex.Exec(q1, s.logger); err2 != nil {
 			_ = s.logError(span, err2, "Cannot index duration", s.logger)
 			err = err2
@@ -250,16 +273,16 @@ func (s *SpanWriter) indexByDuration(span *dbmodel.Span, startTime time.Time) er
 	return err
 }
 
-func (s *SpanWriter) indexByService(span *dbmodel.Span) error {
+func (s *SpanWriter) indexByService(span *dbmodel.Span, ttl int) error {
 	bucketNo := uint64(span.SpanHash) % defaultNumBuckets
-	query := s.session.Query(serviceNameIndex)
-	q := query.Bind(span.Process.ServiceName, bucketNo, span.StartTime, span.TraceID)
+	stmt, values := withTTL(serviceNameIndex, ttl, span.Process.ServiceName, bucketNo, span.StartTime, span.TraceID)
+	q := s.session.Query(stmt).Bind(values...)
 	return s.writerMetrics.serviceNameIndex.Exec(q, s.logger)
 }
 
-func (s *SpanWriter) indexByOperation(span *dbmodel.Span) error {
-	query := s.session.Query(serviceOperationIndex)
-	q := query.Bind(span.Process.ServiceName, span.OperationName, span.StartTime, span.TraceID)
+func (s *SpanWriter) indexByOperation(span *dbmodel.Span, ttl int) error {
+	stmt, values := withTTL(serviceOperationIndex, ttl, span.Process.ServiceName, span.OperationName, span.StartTime, span.TraceID)
+	q := s.session.Query(stmt).Bind(values...)
 	return s.writerMetrics.serviceOperationIndex.Exec(q, s.logger)
 }
 
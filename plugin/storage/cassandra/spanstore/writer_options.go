@@ -17,6 +17,7 @@ package spanstore
 
 import (
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 // Option is a function that sets some option on the writer.
@@ -27,6 +28,8 @@ type Options struct {
 	tagFilter   dbmodel.TagFilter
 	storageMode storageMode
 	indexFilter dbmodel.IndexFilter
+
+	retentionPolicies *spanstore.RetentionPolicies
 }
 
 // TagFilter can be provided to filter any tags that should not be indexed.
@@ -57,6 +60,14 @@ func IndexFilter(indexFilter dbmodel.IndexFilter) Option {
 	}
 }
 
+// RetentionPolicies can be provided to store the spans of specific services or tenants
+// with their own TTL instead of the default TTL of the tables.
+func RetentionPolicies(policies *spanstore.RetentionPolicies) Option {
+	return func(o *Options) {
+		o.retentionPolicies = policies
+	}
+}
+
 func applyOptions(opts ...Option) Options {
 	o := Options{}
 	for _, opt := range opts {
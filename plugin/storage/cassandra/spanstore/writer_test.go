@@ -435,3 +435,44 @@ func TestStorageMode_StoreWithoutIndexing(t *testing.T) {
 		w.session.AssertNotCalled(t, "Query", stringMatcher(serviceNameIndex), matchEverything())
 	}, StoreWithoutIndexing())
 }
+
+func TestSpanWriterRetentionPolicies(t *testing.T) {
+	retentionPolicies := RetentionPolicies(spanstore.NewRetentionPolicies([]spanstore.RetentionPolicy{
+		{Name: "payments", TTL: 90 * 24 * time.Hour, Services: []string{"payments"}},
+	}))
+	testCases := []struct {
+		service string
+		query   string
+		values  int
+	}{
+		{service: "payments", query: insertSpan + usingTTL, values: 13},
+		{service: "frontend", query: insertSpan, values: 12},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.service, func(t *testing.T) {
+			withSpanWriter(0, func(w *spanWriterTest) {
+				span := &model.Span{
+					TraceID: model.NewTraceID(0, 1),
+					Process: &model.Process{
+						ServiceName: testCase.service,
+					},
+				}
+				spanQuery := &mocks.Query{}
+				spanQuery.On("Exec").Return(nil)
+				var values []interface{}
+				w.session.On("Query", testCase.query, matchOnceWithSideEffect(func(v []interface{}) {
+					values = v
+				})).Return(spanQuery)
+
+				err := w.writer.WriteSpan(context.Background(), span)
+
+				require.NoError(t, err)
+				w.session.AssertExpectations(t)
+				require.Len(t, values, testCase.values)
+				if testCase.values == 13 {
+					assert.Equal(t, 90*24*3600, values[12])
+				}
+			}, StoreWithoutIndexing(), retentionPolicies)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/schema"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// tenantKeyspaces holds the sessions of the keyspaces of the tenants. The session of a keyspace
+// is opened on the first request of its tenants, after creating its schema if enabled.
+type tenantKeyspaces struct {
+	config     *TenancyConfig
+	localDC    string
+	newSession func(keyspace string) (cassandra.Session, error)
+	logger     *zap.Logger
+
+	mu       sync.Mutex
+	sessions map[string]cassandra.Session
+}
+
+func newTenantKeyspaces(
+	config *TenancyConfig,
+	localDC string,
+	newSession func(keyspace string) (cassandra.Session, error),
+	logger *zap.Logger,
+) *tenantKeyspaces {
+	return &tenantKeyspaces{
+		config:     config,
+		localDC:    localDC,
+		newSession: newSession,
+		logger:     logger,
+		sessions:   make(map[string]cassandra.Session),
+	}
+}
+
+func (t *tenantKeyspaces) session(keyspace string) (cassandra.Session, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if session, ok := t.sessions[keyspace]; ok {
+		return session, nil
+	}
+	if t.config.CreateSchema {
+		if err := t.createSchema(keyspace); err != nil {
+			return nil, err
+		}
+	}
+	session, err := t.newSession(keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the keyspace %s: %w", keyspace, err)
+	}
+	t.sessions[keyspace] = session
+	return session, nil
+}
+
+func (t *tenantKeyspaces) createSchema(keyspace string) error {
+	// the keyspace may not exist yet, the schema is created with a session without keyspace
+	session, err := t.newSession("")
+	if err != nil {
+		return fmt.Errorf("failed to connect to create the schema of the keyspace %s: %w", keyspace, err)
+	}
+	defer session.Close()
+	statements := schema.Statements(schema.Params{
+		Keyspace:    keyspace,
+		Replication: t.replication(),
+		TraceTTL:    t.config.TraceTTL,
+	})
+	for _, statement := range statements {
+		if err := session.Query(statement).Exec(); err != nil {
+			return fmt.Errorf("failed to create the schema of the keyspace %s: %w", keyspace, err)
+		}
+	}
+	t.logger.Info("Created the schema of the tenant keyspace", zap.String("keyspace", keyspace))
+	return nil
+}
+
+func (t *tenantKeyspaces) replication() string {
+	if t.localDC != "" {
+		return fmt.Sprintf("{'class': 'NetworkTopologyStrategy', '%s': '%d'}", t.localDC, t.config.ReplicationFactor)
+	}
+	return fmt.Sprintf("{'class': 'SimpleStrategy', 'replication_factor': '%d'}", t.config.ReplicationFactor)
+}
+
+func (t *tenantKeyspaces) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, session := range t.sessions {
+		session.Close()
+	}
+}
+
+// keyspaceRouter selects the component, e.g. a span reader, of the keyspace of the tenant of a request.
+// The components of the tenant keyspaces are created on first use.
+type keyspaceRouter[T any] struct {
+	keyspaces *tenantKeyspaces
+	primary   T
+	create    func(session cassandra.Session) (T, error)
+
+	mu         sync.RWMutex
+	components map[string]T
+}
+
+func newKeyspaceRouter[T any](keyspaces *tenantKeyspaces, primary T, create func(session cassandra.Session) (T, error)) *keyspaceRouter[T] {
+	return &keyspaceRouter[T]{
+		keyspaces:  keyspaces,
+		primary:    primary,
+		create:     create,
+		components: make(map[string]T),
+	}
+}
+
+func (r *keyspaceRouter[T]) route(ctx context.Context) (T, error) {
+	keyspace, ok := r.keyspaces.config.Keyspaces[tenancy.GetTenant(ctx)]
+	if !ok {
+		return r.primary, nil
+	}
+	r.mu.RLock()
+	component, ok := r.components[keyspace]
+	r.mu.RUnlock()
+	if ok {
+		return component, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if component, ok := r.components[keyspace]; ok {
+		return component, nil
+	}
+	var zero T
+	session, err := r.keyspaces.session(keyspace)
+	if err != nil {
+		return zero, err
+	}
+	if component, err = r.create(session); err != nil {
+		return zero, err
+	}
+	r.components[keyspace] = component
+	return component, nil
+}
+
+// all returns the components of the primary keyspace and of the tenant keyspaces in use.
+func (r *keyspaceRouter[T]) all() []T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	components := []T{r.primary}
+	for _, component := range r.components {
+		components = append(components, component)
+	}
+	return components
+}
+
+// routingSpanReader is a spanstore.Reader reading the spans of each tenant from its keyspace.
+type routingSpanReader struct {
+	router *keyspaceRouter[spanstore.Reader]
+}
+
+var (
+	_ spanstore.PaginatedReader = (*routingSpanReader)(nil)
+	_ spanstore.StreamingReader = (*routingSpanReader)(nil)
+)
+
+func (r *routingSpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetTrace(ctx, traceID)
+}
+
+func (r *routingSpanReader) GetServices(ctx context.Context) ([]string, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetServices(ctx)
+}
+
+func (r *routingSpanReader) GetOperations(ctx context.Context, query spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetOperations(ctx, query)
+}
+
+func (r *routingSpanReader) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.FindTraces(ctx, query)
+}
+
+func (r *routingSpanReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.FindTraceIDs(ctx, query)
+}
+
+func (r *routingSpanReader) FindTracesPage(ctx context.Context, query *spanstore.TraceQueryParameters) (*spanstore.TracesPage, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return spanstore.FindTracesPage(ctx, reader, query)
+}
+
+func (r *routingSpanReader) StreamTrace(ctx context.Context, traceID model.TraceID, yield func(spans []*model.Span) error) error {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return err
+	}
+	return spanstore.StreamTrace(ctx, reader, traceID, 0, yield)
+}
+
+// routingSpanWriter is a spanstore.Writer writing the spans of each tenant to its keyspace.
+type routingSpanWriter struct {
+	router *keyspaceRouter[spanstore.Writer]
+}
+
+func (w *routingSpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	writer, err := w.router.route(ctx)
+	if err != nil {
+		return err
+	}
+	return writer.WriteSpan(ctx, span)
+}
+
+// Close closes the writers of all keyspaces.
+func (w *routingSpanWriter) Close() error {
+	var errs []error
+	for _, writer := range w.router.all() {
+		if closer, ok := writer.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// routingDependencyReader is a dependencystore.Reader reading the dependencies of each tenant from its keyspace.
+type routingDependencyReader struct {
+	router *keyspaceRouter[dependencystore.Reader]
+}
+
+func (r *routingDependencyReader) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetDependencies(ctx, endTs, lookback)
+}
+
+func (r *routingDependencyReader) GetAnnotatedDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]dependencystore.AnnotatedLink, error) {
+	reader, err := r.router.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dependencystore.GetAnnotatedDependencies(ctx, reader, endTs, lookback)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	cassandraCfg "github.com/jaegertracing/jaeger/pkg/cassandra/config"
+	"github.com/jaegertracing/jaeger/pkg/cassandra/mocks"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+)
+
+// newKeyspaceSession returns a session whose queries fail with the name of the keyspace,
+// telling which keyspace a request is routed to.
+func newKeyspaceSession(keyspace string) *mocks.Session {
+	session := &mocks.Session{}
+	query := &mocks.Query{}
+	iter := &mocks.Iterator{}
+	session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
+	session.On("Close").Return()
+	query.On("Exec").Return(nil)
+	query.On("Consistency", mock.Anything).Return(query)
+	query.On("Iter").Return(iter)
+	iter.On("Scan", mock.Anything).Return(false)
+	iter.On("Close").Return(errors.New(keyspace))
+	return session
+}
+
+type keyspaceSessions struct {
+	sessions map[string]*mocks.Session
+	opened   []string
+	err      error
+}
+
+func (k *keyspaceSessions) newSession(keyspace string) (cassandra.Session, error) {
+	if k.err != nil {
+		return nil, k.err
+	}
+	k.opened = append(k.opened, keyspace)
+	session, ok := k.sessions[keyspace]
+	if !ok {
+		session = newKeyspaceSession(keyspace)
+		k.sessions[keyspace] = session
+	}
+	return session, nil
+}
+
+func (k *keyspaceSessions) sessionBuilder(keyspace string) cassandraCfg.SessionBuilder {
+	session, err := k.newSession(keyspace)
+	mockSession, _ := session.(*mocks.Session)
+	return newMockSessionBuilder(mockSession, err)
+}
+
+func TestKeyspaceRouter(t *testing.T) {
+	sessions := &keyspaceSessions{sessions: make(map[string]*mocks.Session)}
+	keyspaces := newTenantKeyspaces(&TenancyConfig{
+		Keyspaces: map[string]string{"acme": "jaeger_acme", "acme-eu": "jaeger_acme"},
+	}, "", sessions.newSession, zap.NewNop())
+	primary := newKeyspaceSession("jaeger_v1_test")
+	router := newKeyspaceRouter[cassandra.Session](keyspaces, primary, func(session cassandra.Session) (cassandra.Session, error) {
+		return session, nil
+	})
+
+	session, err := router.route(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, primary, session)
+
+	session, err = router.route(tenancy.WithTenant(context.Background(), "other"))
+	require.NoError(t, err)
+	assert.Same(t, primary, session)
+
+	for _, tenant := range []string{"acme", "acme", "acme-eu"} {
+		session, err = router.route(tenancy.WithTenant(context.Background(), tenant))
+		require.NoError(t, err)
+		assert.Same(t, sessions.sessions["jaeger_acme"], session)
+	}
+	// the session of the keyspace is opened once
+	assert.Equal(t, []string{"jaeger_acme"}, sessions.opened)
+	assert.Len(t, router.all(), 2)
+
+	keyspaces.close()
+	sessions.sessions["jaeger_acme"].AssertCalled(t, "Close")
+}
+
+func TestKeyspaceRouterCreateSchema(t *testing.T) {
+	sessions := &keyspaceSessions{sessions: make(map[string]*mocks.Session)}
+	keyspaces := newTenantKeyspaces(&TenancyConfig{
+		Keyspaces:         map[string]string{"acme": "jaeger_acme"},
+		CreateSchema:      true,
+		ReplicationFactor: 3,
+		TraceTTL:          48 * time.Hour,
+	}, "dc1", sessions.newSession, zap.NewNop())
+
+	_, err := keyspaces.session("jaeger_acme")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "jaeger_acme"}, sessions.opened)
+	schemaSession := sessions.sessions[""]
+	schemaSession.AssertCalled(t, "Query",
+		"CREATE KEYSPACE IF NOT EXISTS jaeger_acme WITH replication = {'class': 'NetworkTopologyStrategy', 'dc1': '3'}",
+		mock.Anything)
+	schemaSession.AssertCalled(t, "Close")
+
+	keyspaces.localDC = ""
+	assert.Equal(t, "{'class': 'SimpleStrategy', 'replication_factor': '3'}", keyspaces.replication())
+}
+
+func TestKeyspaceRouterErrors(t *testing.T) {
+	sessions := &keyspaceSessions{sessions: make(map[string]*mocks.Session), err: errors.New("made-up error")}
+	config := &TenancyConfig{Keyspaces: map[string]string{"acme": "jaeger_acme"}}
+	keyspaces := newTenantKeyspaces(config, "", sessions.newSession, zap.NewNop())
+	router := newKeyspaceRouter[cassandra.Session](keyspaces, nil, func(session cassandra.Session) (cassandra.Session, error) {
+		return nil, errors.New("cannot create")
+	})
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+
+	_, err := router.route(ctx)
+	require.EqualError(t, err, "failed to connect to the keyspace jaeger_acme: made-up error")
+
+	config.CreateSchema = true
+	_, err = router.route(ctx)
+	require.EqualError(t, err, "failed to connect to create the schema of the keyspace jaeger_acme: made-up error")
+
+	sessions.err = nil
+	schemaSession := &mocks.Session{}
+	query := &mocks.Query{}
+	schemaSession.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
+	schemaSession.On("Close").Return()
+	query.On("Exec").Return(errors.New("made-up error"))
+	sessions.sessions[""] = schemaSession
+	_, err = router.route(ctx)
+	require.EqualError(t, err, "failed to create the schema of the keyspace jaeger_acme: made-up error")
+
+	config.CreateSchema = false
+	_, err = router.route(ctx)
+	require.EqualError(t, err, "cannot create")
+}
+
+func TestFactoryTenantKeyspaces(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	command.ParseFlags([]string{"--cassandra.tenancy.keyspaces=acme=jaeger_acme"})
+	f.InitFromViper(v, zap.NewNop())
+
+	sessions := &keyspaceSessions{sessions: make(map[string]*mocks.Session)}
+	f.primaryConfig = newMockSessionBuilder(newKeyspaceSession("jaeger_v1_test"), nil)
+	f.keyspaceConfig = sessions.sessionBuilder
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+	spanReader, err := f.CreateSpanReader()
+	require.NoError(t, err)
+	assert.IsType(t, &routingSpanReader{}, spanReader)
+
+	spanWriter, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	assert.IsType(t, &routingSpanWriter{}, spanWriter)
+
+	depReader, err := f.CreateDependencyReader()
+	require.NoError(t, err)
+	_, err = depReader.GetDependencies(context.Background(), time.Now(), time.Hour)
+	require.ErrorContains(t, err, "jaeger_v1_test")
+	_, err = depReader.GetDependencies(tenancy.WithTenant(context.Background(), "acme"), time.Now(), time.Hour)
+	require.ErrorContains(t, err, "jaeger_acme")
+	_, err = dependencystore.GetAnnotatedDependencies(tenancy.WithTenant(context.Background(), "acme"), depReader, time.Now(), time.Hour)
+	require.ErrorContains(t, err, "jaeger_acme")
+
+	require.NoError(t, spanWriter.(*routingSpanWriter).Close())
+	require.NoError(t, f.Close())
+}
+
+func TestFactoryInvalidTenantKeyspace(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	command.ParseFlags([]string{"--cassandra.tenancy.keyspaces=acme=jaeger-acme,globex"})
+	f.InitFromViper(v, zap.NewNop())
+	f.primaryConfig = newMockSessionBuilder(newKeyspaceSession("jaeger_v1_test"), nil)
+
+	err := f.Initialize(metrics.NullFactory, zap.NewNop())
+	require.ErrorContains(t, err, "invalid keyspace")
+}
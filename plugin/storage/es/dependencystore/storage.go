@@ -28,6 +28,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 )
 
 const (
@@ -101,6 +102,19 @@ func (s *DependencyStore) writeDependencies(indexName string, ts time.Time, depe
 
 // GetDependencies returns all interservice dependencies
 func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	links, err := s.GetAnnotatedDependencies(ctx, endTs, lookback)
+	if err != nil {
+		return nil, err
+	}
+	var retDependencies []model.DependencyLink
+	for _, link := range links {
+		retDependencies = append(retDependencies, link.DependencyLink)
+	}
+	return retDependencies, nil
+}
+
+// GetAnnotatedDependencies returns all interservice dependencies along with the time they were written at.
+func (s *DependencyStore) GetAnnotatedDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]dependencystore.AnnotatedLink, error) {
 	indices := s.getReadIndices(endTs, lookback)
 	searchResult, err := s.client().Search(indices...).
 		Size(s.maxDocCount).
@@ -111,7 +125,7 @@ func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time,
 		return nil, fmt.Errorf("failed to search for dependencies: %w", err)
 	}
 
-	var retDependencies []dbmodel.DependencyLink
+	var retDependencies []dependencystore.AnnotatedLink
 	hits := searchResult.Hits.Hits
 	for _, hit := range hits {
 		source := hit.Source
@@ -119,9 +133,11 @@ func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time,
 		if err := json.Unmarshal(*source, &tToD); err != nil {
 			return nil, errors.New("unmarshalling ElasticSearch documents failed")
 		}
-		retDependencies = append(retDependencies, tToD.Dependencies...)
+		for _, dependency := range dbmodel.ToDomainDependencies(tToD.Dependencies) {
+			retDependencies = append(retDependencies, dependencystore.AnnotatedLink{DependencyLink: dependency, Timestamp: tToD.Timestamp})
+		}
 	}
-	return dbmodel.ToDomainDependencies(retDependencies), nil
+	return retDependencies, nil
 }
 
 func buildTSQuery(endTs time.Time, lookback time.Duration) elastic.Query {
@@ -66,6 +66,8 @@ func withDepStorage(indexPrefix, indexDateLayout string, maxDocCount int, fn fun
 var (
 	_ dependencystore.Reader = &DependencyStore{} // check API conformance
 	_ dependencystore.Writer = &DependencyStore{} // check API conformance
+
+	_ dependencystore.AnnotatedReader = &DependencyStore{} // check API conformance
 )
 
 func TestNewSpanReaderIndexPrefix(t *testing.T) {
@@ -205,6 +207,29 @@ func TestGetDependencies(t *testing.T) {
 	}
 }
 
+func TestGetAnnotatedDependencies(t *testing.T) {
+	withDepStorage("", "2006-01-02", 0, func(r *depStorageTest) {
+		fixedTime := time.Date(1995, time.April, 21, 4, 21, 19, 95, time.UTC)
+		searchService := &mocks.SearchService{}
+		r.client.On("Search", mock.Anything, mock.Anything).Return(searchService)
+		searchService.On("Size", mock.Anything).Return(searchService)
+		searchService.On("Query", mock.Anything).Return(searchService)
+		searchService.On("IgnoreUnavailable", mock.AnythingOfType("bool")).Return(searchService)
+		searchService.On("Do", mock.Anything).Return(createSearchResult(`{
+			"timestamp": "1995-04-21T04:00:00Z",
+			"dependencies": [{ "parent": "hello", "child": "world", "callCount": 12 }]
+		}`), nil)
+
+		links, err := r.storage.GetAnnotatedDependencies(context.Background(), fixedTime, 24*time.Hour)
+		require.NoError(t, err)
+		// the links are timestamped with the time they were written at
+		assert.Equal(t, []dependencystore.AnnotatedLink{{
+			DependencyLink: model.DependencyLink{Parent: "hello", Child: "world", CallCount: 12},
+			Timestamp:      time.Date(1995, time.April, 21, 4, 0, 0, 0, time.UTC),
+		}}, links)
+	})
+}
+
 func createSearchResult(dependencyLink string) *elastic.SearchResult {
 	dependencyLinkRaw := []byte(dependencyLink)
 	hits := make([]*elastic.SearchHit, 1)
@@ -16,6 +16,7 @@
 package es
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -30,11 +31,14 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/es/config"
 	"github.com/jaegertracing/jaeger/pkg/fswatcher"
+	"github.com/jaegertracing/jaeger/pkg/hostname"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin"
+	esLock "github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/elasticsearch"
 	esDepStore "github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/mappings"
 	esSampleStore "github.com/jaegertracing/jaeger/plugin/storage/es/samplingstore"
@@ -51,10 +55,15 @@ const (
 )
 
 var ( // interface comformance checks
-	_ storage.Factory        = (*Factory)(nil)
-	_ storage.ArchiveFactory = (*Factory)(nil)
-	_ io.Closer              = (*Factory)(nil)
-	_ plugin.Configurable    = (*Factory)(nil)
+	_ storage.Factory              = (*Factory)(nil)
+	_ storage.ArchiveFactory       = (*Factory)(nil)
+	_ storage.SamplingStoreFactory = (*Factory)(nil)
+	_ storage.PurgerFactory        = (*Factory)(nil)
+	_ storage.RetentionFactory     = (*Factory)(nil)
+
+	_ storage.RetentionPoliciesValidator = (*Factory)(nil)
+	_ io.Closer                          = (*Factory)(nil)
+	_ plugin.Configurable                = (*Factory)(nil)
 )
 
 // Factory implements storage.Factory for Elasticsearch backend.
@@ -74,6 +83,8 @@ type Factory struct {
 	archiveClient atomic.Pointer[es.Client]
 
 	watchers []*fswatcher.FSWatcher
+
+	retentionPolicies *spanstore.RetentionPolicies
 }
 
 // NewFactory creates a new Factory.
@@ -195,7 +206,33 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return createSpanWriter(f.getPrimaryClient, f.primaryConfig, false, f.metricsFactory, f.logger)
+	return createSpanWriter(f.getPrimaryClient, f.primaryConfig, false, f.retentionPolicies, f.metricsFactory, f.logger)
+}
+
+// SetRetentionPolicies implements storage.RetentionFactory. The spans of the retention policies are written
+// to their own indices, managed by the ILM policies created by es-rollover init.
+func (f *Factory) SetRetentionPolicies(policies *spanstore.RetentionPolicies) {
+	f.retentionPolicies = policies
+}
+
+// ValidateRetentionPolicies implements storage.RetentionPoliciesValidator. The write aliases of the policies
+// must have been created by es-rollover init, otherwise ES would create an index with the name of the alias,
+// never rolled over nor deleted by the ILM policy.
+func (f *Factory) ValidateRetentionPolicies(ctx context.Context, policies []spanstore.RetentionPolicy) error {
+	if !f.primaryConfig.UseReadWriteAliases {
+		return nil
+	}
+	for i := range policies {
+		alias := esSpanStore.RetentionWriteAlias(f.primaryConfig.IndexPrefix, &policies[i])
+		exists, err := f.getPrimaryClient().IndexExists(alias).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check the write alias %s of the retention policy %q: %w", alias, policies[i].Name, err)
+		}
+		if !exists {
+			return fmt.Errorf("the write alias %s of the retention policy %q does not exist, run es-rollover init to create it", alias, policies[i].Name)
+		}
+	}
+	return nil
 }
 
 // CreateDependencyReader implements storage.Factory
@@ -203,6 +240,11 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return createDependencyReader(f.getPrimaryClient, f.primaryConfig, f.logger)
 }
 
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	return newDependencyStore(f.getPrimaryClient, f.primaryConfig, f.logger), nil
+}
+
 // CreateArchiveSpanReader implements storage.ArchiveFactory
 func (f *Factory) CreateArchiveSpanReader() (spanstore.Reader, error) {
 	if !f.archiveConfig.Enabled {
@@ -216,7 +258,16 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 	if !f.archiveConfig.Enabled {
 		return nil, nil
 	}
-	return createSpanWriter(f.getArchiveClient, f.archiveConfig, true, f.metricsFactory, f.logger)
+	return createSpanWriter(f.getArchiveClient, f.archiveConfig, true, nil, f.metricsFactory, f.logger)
+}
+
+// CreatePurger implements storage.PurgerFactory
+func (f *Factory) CreatePurger() (spanstore.Purger, error) {
+	reader, err := createSpanReader(f.getPrimaryClient, f.primaryConfig, false, f.metricsFactory, f.logger, f.tracer)
+	if err != nil {
+		return nil, err
+	}
+	return esSpanStore.NewSpanPurger(reader.(*esSpanStore.SpanReader)), nil
 }
 
 func createSpanReader(
@@ -230,6 +281,9 @@ func createSpanReader(
 	if cfg.UseILM && !cfg.UseReadWriteAliases {
 		return nil, fmt.Errorf("--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
 	}
+	if cfg.IndexPerTenant && cfg.UseReadWriteAliases {
+		return nil, errors.New("--es.index-per-tenant is not supported with --es.use-aliases, the read and write aliases are not created for each tenant")
+	}
 	return esSpanStore.NewSpanReader(esSpanStore.SpanReaderParams{
 		Client:                        clientFn,
 		MaxDocCount:                   cfg.MaxDocCount,
@@ -243,6 +297,7 @@ func createSpanReader(
 		UseReadWriteAliases:           cfg.UseReadWriteAliases,
 		Archive:                       archive,
 		RemoteReadClusters:            cfg.RemoteReadClusters,
+		IndexPerTenant:                cfg.IndexPerTenant,
 		Logger:                        logger,
 		MetricsFactory:                mFactory,
 		Tracer:                        tp.Tracer("esSpanStore.SpanReader"),
@@ -253,6 +308,7 @@ func createSpanWriter(
 	clientFn func() es.Client,
 	cfg *config.Configuration,
 	archive bool,
+	retentionPolicies *spanstore.RetentionPolicies,
 	mFactory metrics.Factory,
 	logger *zap.Logger,
 ) (spanstore.Writer, error) {
@@ -261,6 +317,12 @@ func createSpanWriter(
 	if cfg.UseILM && !cfg.UseReadWriteAliases {
 		return nil, fmt.Errorf("--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
 	}
+	if retentionPolicies != nil && !cfg.UseILM {
+		return nil, fmt.Errorf("the retention policies require --es.use-ilm, so that the indices of each policy are deleted by its ILM policy")
+	}
+	if cfg.IndexPerTenant && cfg.UseReadWriteAliases {
+		return nil, errors.New("--es.index-per-tenant is not supported with --es.use-aliases, the read and write aliases are not created for each tenant")
+	}
 	if tags, err = cfg.TagKeysAsFields(); err != nil {
 		logger.Error("failed to get tag keys", zap.Error(err))
 		return nil, err
@@ -293,6 +355,8 @@ func createSpanWriter(
 		TagDotReplacement:      cfg.Tags.DotReplacement,
 		Archive:                archive,
 		UseReadWriteAliases:    cfg.UseReadWriteAliases,
+		RetentionPolicies:      retentionPolicies,
+		IndexPerTenant:         cfg.IndexPerTenant,
 		Logger:                 logger,
 		MetricsFactory:         mFactory,
 	})
@@ -307,6 +371,18 @@ func createSpanWriter(
 	return writer, nil
 }
 
+// CreateLock implements storage.SamplingStoreFactory
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	hostname, err := hostname.AsIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	f.logger.Info("Using unique participantName in the distributed lock", zap.String("participantName", hostname))
+
+	return esLock.NewLock(f.getPrimaryClient, f.primaryConfig.IndexPrefix, hostname), nil
+}
+
+// CreateSamplingStore implements storage.SamplingStoreFactory
 func (f *Factory) CreateSamplingStore(maxBuckets int) (samplingstore.Store, error) {
 	store := esSampleStore.NewSamplingStore(esSampleStore.SamplingStoreParams{
 		Client:                 f.getPrimaryClient,
@@ -325,7 +401,15 @@ func createDependencyReader(
 	cfg *config.Configuration,
 	logger *zap.Logger,
 ) (dependencystore.Reader, error) {
-	reader := esDepStore.NewDependencyStore(esDepStore.DependencyStoreParams{
+	return newDependencyStore(clientFn, cfg, logger), nil
+}
+
+func newDependencyStore(
+	clientFn func() es.Client,
+	cfg *config.Configuration,
+	logger *zap.Logger,
+) *esDepStore.DependencyStore {
+	return esDepStore.NewDependencyStore(esDepStore.DependencyStoreParams{
 		Client:              clientFn,
 		Logger:              logger,
 		IndexPrefix:         cfg.IndexPrefix,
@@ -333,7 +417,6 @@ func createDependencyReader(
 		MaxDocCount:         cfg.MaxDocCount,
 		UseReadWriteAliases: cfg.UseReadWriteAliases,
 	})
-	return reader, nil
 }
 
 var _ io.Closer = (*Factory)(nil)
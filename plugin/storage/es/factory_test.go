@@ -101,6 +101,9 @@ func TestElasticsearchFactory(t *testing.T) {
 	_, err = f.CreateDependencyReader()
 	require.NoError(t, err)
 
+	_, err = f.CreateDependencyWriter()
+	require.NoError(t, err)
+
 	_, err = f.CreateArchiveSpanReader()
 	require.NoError(t, err)
 
@@ -110,6 +113,13 @@ func TestElasticsearchFactory(t *testing.T) {
 	_, err = f.CreateSamplingStore(1)
 	require.NoError(t, err)
 
+	_, err = f.CreatePurger()
+	require.NoError(t, err)
+
+	lock, err := f.CreateLock()
+	require.NoError(t, err)
+	assert.NotNil(t, lock)
+
 	require.NoError(t, f.Close())
 }
 
@@ -145,6 +155,29 @@ func TestElasticsearchILMUsedWithoutReadWriteAliases(t *testing.T) {
 	r, err := f.CreateSpanReader()
 	require.EqualError(t, err, "--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
 	assert.Nil(t, r)
+
+	p, err := f.CreatePurger()
+	require.EqualError(t, err, "--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
+	assert.Nil(t, p)
+}
+
+func TestElasticsearchIndexPerTenantWithReadWriteAliases(t *testing.T) {
+	f := NewFactory()
+	f.primaryConfig = &escfg.Configuration{
+		IndexPerTenant:      true,
+		UseReadWriteAliases: true,
+	}
+	f.archiveConfig = &escfg.Configuration{}
+	f.newClientFn = (&mockClientBuilder{}).NewClient
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	defer f.Close()
+	w, err := f.CreateSpanWriter()
+	require.ErrorContains(t, err, "--es.index-per-tenant is not supported with --es.use-aliases")
+	assert.Nil(t, w)
+
+	r, err := f.CreateSpanReader()
+	require.ErrorContains(t, err, "--es.index-per-tenant is not supported with --es.use-aliases")
+	assert.Nil(t, r)
 }
 
 func TestTagKeysAsFields(t *testing.T) {
@@ -229,6 +262,52 @@ func TestILMDisableTemplateCreation(t *testing.T) {
 	require.NoError(t, err) // as the createTemplate is not called, CreateSpanWriter should not return an error
 }
 
+func TestRetentionPoliciesRequireILM(t *testing.T) {
+	f := NewFactory()
+	f.primaryConfig = &escfg.Configuration{UseReadWriteAliases: true}
+	f.archiveConfig = &escfg.Configuration{}
+	f.newClientFn = (&mockClientBuilder{}).NewClient
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	defer f.Close()
+	f.SetRetentionPolicies(spanstore.NewRetentionPolicies(nil))
+	_, err := f.CreateSpanWriter()
+	require.ErrorContains(t, err, "the retention policies require --es.use-ilm")
+
+	f.primaryConfig.UseILM = true
+	_, err = f.CreateSpanWriter()
+	require.NoError(t, err)
+}
+
+func TestValidateRetentionPolicies(t *testing.T) {
+	f := NewFactory()
+	f.primaryConfig = &escfg.Configuration{IndexPrefix: "foo"}
+	c := &mocks.Client{}
+	var client es.Client = c
+	f.primaryClient.Store(&client)
+	existsService := func(exists bool, err error) *mocks.IndicesExistsService {
+		service := &mocks.IndicesExistsService{}
+		service.On("Do", mock.Anything).Return(exists, err)
+		return service
+	}
+	c.On("IndexExists", "foo-jaeger-span-payments-write").Return(existsService(true, nil))
+	c.On("IndexExists", "foo-jaeger-span-audit-write").Return(existsService(false, nil))
+	c.On("IndexExists", "foo-jaeger-span-broken-write").Return(existsService(false, errors.New("unavailable")))
+	payments := spanstore.RetentionPolicy{Name: "payments"}
+	audit := spanstore.RetentionPolicy{Name: "audit"}
+	broken := spanstore.RetentionPolicy{Name: "broken"}
+
+	// the policies are not used without the aliases
+	require.NoError(t, f.ValidateRetentionPolicies(context.Background(), []spanstore.RetentionPolicy{audit}))
+	c.AssertNotCalled(t, "IndexExists", mock.Anything)
+
+	f.primaryConfig.UseReadWriteAliases = true
+	require.NoError(t, f.ValidateRetentionPolicies(context.Background(), []spanstore.RetentionPolicy{payments}))
+	err := f.ValidateRetentionPolicies(context.Background(), []spanstore.RetentionPolicy{payments, audit})
+	require.EqualError(t, err, `the write alias foo-jaeger-span-audit-write of the retention policy "audit" does not exist, run es-rollover init to create it`)
+	err = f.ValidateRetentionPolicies(context.Background(), []spanstore.RetentionPolicy{broken})
+	require.EqualError(t, err, `failed to check the write alias foo-jaeger-span-broken-write of the retention policy "broken": unavailable`)
+}
+
 func TestArchiveDisabled(t *testing.T) {
 	f := NewFactory()
 	f.archiveConfig = &escfg.Configuration{Enabled: false}
@@ -40,6 +40,9 @@ type MappingBuilder struct {
 	IndexPrefix                  string
 	UseILM                       bool
 	ILMPolicyName                string
+	// RetentionPolicyName renders the span template of the indices of a retention policy, overriding
+	// the default span template. It requires UseILM and a higher PrioritySpanTemplate on ESv8.
+	RetentionPolicyName string
 }
 
 // GetMapping returns the rendered mapping based on elasticsearch version
@@ -16,10 +16,12 @@ package mappings
 
 import (
 	"embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"testing"
 	"text/template"
 
@@ -79,6 +81,40 @@ func TestMappingBuilder_GetMapping(t *testing.T) {
 	}
 }
 
+func TestMappingBuilder_GetRetentionPolicyMapping(t *testing.T) {
+	for _, esVersion := range []uint{7, 8} {
+		t.Run(fmt.Sprintf("v%d", esVersion), func(t *testing.T) {
+			mb := &MappingBuilder{
+				TemplateBuilder:      es.TextTemplateBuilder{},
+				Shards:               3,
+				Replicas:             3,
+				PrioritySpanTemplate: 501,
+				EsVersion:            esVersion,
+				IndexPrefix:          "test-",
+				UseILM:               true,
+				ILMPolicyName:        "jaeger-test-policy-payments",
+				RetentionPolicyName:  "payments",
+			}
+			got, err := mb.GetMapping("jaeger-span")
+			require.NoError(t, err)
+			var template map[string]any
+			require.NoError(t, json.Unmarshal([]byte(got), &template))
+			assert.Equal(t, "test-jaeger-span-payments-*", strings.TrimPrefix(template["index_patterns"].(string), "*"))
+			if esVersion == 8 {
+				assert.Equal(t, 501.0, template["priority"])
+				template = template["template"].(map[string]any)
+			} else {
+				assert.Equal(t, 1.0, template["order"])
+			}
+			assert.Contains(t, template["aliases"], "test-jaeger-span-read")
+			assert.Equal(t, map[string]any{
+				"name":           "jaeger-test-policy-payments",
+				"rollover_alias": "test-jaeger-span-payments-write",
+			}, template["settings"].(map[string]any)["lifecycle"])
+		})
+	}
+}
+
 func TestMappingBuilder_loadMapping(t *testing.T) {
 	tests := []struct {
 		name string
@@ -61,6 +61,7 @@ const (
 	suffixTagDeDotChar                   = suffixTagsAsFields + ".dot-replacement"
 	suffixReadAlias                      = ".use-aliases"
 	suffixUseILM                         = ".use-ilm"
+	suffixIndexPerTenant                 = ".index-per-tenant"
 	suffixCreateIndexTemplate            = ".create-index-templates"
 	suffixEnabled                        = ".enabled"
 	suffixVersion                        = ".version"
@@ -280,6 +281,12 @@ func addFlags(flagSet *flag.FlagSet, nsConfig *namespaceConfig) {
 		"(experimental) Option to enable ILM for jaeger span & service indices. Use this option with  "+nsConfig.namespace+suffixReadAlias+". "+
 			"It requires an external component to create aliases before startup and then performing its management. "+
 			"ILM policy must be manually created in ES before startup. Supported only for elasticsearch version 7+.")
+	flagSet.Bool(
+		nsConfig.namespace+suffixIndexPerTenant,
+		nsConfig.IndexPerTenant,
+		"Write the spans of each tenant to its own span and service indices, e.g. jaeger-span-{tenant}-2024-01-02, and read them "+
+			"from the indices of the tenant of the request, so that the retention of each tenant can be managed separately. "+
+			"Use this option with multi-tenancy, the spans without tenant are stored in the default indices. Not supported with "+nsConfig.namespace+suffixReadAlias)
 	flagSet.Bool(
 		nsConfig.namespace+suffixCreateIndexTemplate,
 		nsConfig.CreateIndexTemplates,
@@ -366,6 +373,7 @@ func initFromViper(cfg *namespaceConfig, v *viper.Viper) {
 
 	cfg.MaxDocCount = v.GetInt(cfg.namespace + suffixMaxDocCount)
 	cfg.UseILM = v.GetBool(cfg.namespace + suffixUseILM)
+	cfg.IndexPerTenant = v.GetBool(cfg.namespace + suffixIndexPerTenant)
 
 	// TODO: Need to figure out a better way for do this.
 	cfg.AllowTokenFromContext = v.GetBool(bearertoken.StoragePropagationKey)
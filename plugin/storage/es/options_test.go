@@ -77,6 +77,7 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--es.tags-as-fields.config-file=./file.txt",
 		"--es.tags-as-fields.dot-replacement=!",
 		"--es.use-ilm=true",
+		"--es.index-per-tenant=true",
 		"--es.send-get-body-as=POST",
 	})
 	require.NoError(t, err)
@@ -115,6 +116,8 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, "2006.01.02", aux.IndexDateLayoutServices)
 	assert.Equal(t, "2006.01.02.15", aux.IndexDateLayoutSpans)
 	assert.True(t, primary.UseILM)
+	assert.True(t, primary.IndexPerTenant)
+	assert.True(t, aux.IndexPerTenant)
 	assert.Equal(t, "POST", aux.SendGetBodyAs)
 }
 
@@ -69,10 +69,15 @@ func (fd FromDomain) convertSpanEmbedProcess(span *model.Span) *Span {
 func (fd FromDomain) convertReferences(span *model.Span) []Reference {
 	out := make([]Reference, 0, len(span.References))
 	for _, ref := range span.References {
+		var tags []KeyValue
+		for _, kv := range ref.Tags {
+			tags = append(tags, convertKeyValue(kv))
+		}
 		out = append(out, Reference{
 			RefType: fd.convertRefType(ref.RefType),
 			TraceID: TraceID(ref.TraceID.String()),
 			SpanID:  SpanID(ref.SpanID.String()),
+			Tags:    tags,
 		})
 	}
 	return out
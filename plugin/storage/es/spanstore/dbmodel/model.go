@@ -71,6 +71,8 @@ type Reference struct {
 	RefType ReferenceType `json:"refType"`
 	TraceID TraceID       `json:"traceID"`
 	SpanID  SpanID        `json:"spanID"`
+	// Tags are only stored in the source of the documents, they are not indexed
+	Tags []KeyValue `json:"tags,omitempty"`
 }
 
 // Process is the process emitting a set of spans
@@ -131,6 +131,13 @@ func (td ToDomain) convertRefs(refs []Reference) ([]model.SpanRef, error) {
 			TraceID: traceID,
 			SpanID:  model.NewSpanID(spanID),
 		}
+		if len(r.Tags) > 0 {
+			tags, err := td.convertKeyValues(r.Tags)
+			if err != nil {
+				return nil, err
+			}
+			retMe[i].Tags = tags
+		}
 	}
 	return retMe, nil
 }
@@ -243,6 +243,43 @@ func TestFailureBadSpanIDRefs(t *testing.T) {
 	failingSpanTransformAnyMsg(t, &badRefsESSpan)
 }
 
+func TestFailureBadTagsRefs(t *testing.T) {
+	badRefsESSpan, err := loadESSpanFixture(1)
+	require.NoError(t, err)
+	badRefsESSpan.References = []Reference{
+		{
+			RefType: "FOLLOWS_FROM",
+			TraceID: "1",
+			SpanID:  "1",
+			Tags:    []KeyValue{{Key: "link.index", Type: "int64", Value: "not a number"}},
+		},
+	}
+	failingSpanTransformAnyMsg(t, &badRefsESSpan)
+}
+
+func TestRefsWithTags(t *testing.T) {
+	span, err := loadESSpanFixture(1)
+	require.NoError(t, err)
+	jSpan, err := NewToDomain(":").SpanToDomain(&span)
+	require.NoError(t, err)
+	jSpan.References = []model.SpanRef{
+		{
+			TraceID: model.NewTraceID(0, 1),
+			SpanID:  model.NewSpanID(2),
+			RefType: model.FollowsFrom,
+			Tags:    []model.KeyValue{model.String("link.kind", "batch"), model.Int64("link.index", 3)},
+		},
+	}
+	esSpan := NewFromDomain(false, nil, ":").FromDomainEmbedProcess(jSpan)
+	assert.Equal(t, []KeyValue{
+		{Key: "link.kind", Type: StringType, Value: "batch"},
+		{Key: "link.index", Type: Int64Type, Value: "3"},
+	}, esSpan.References[0].Tags)
+	actual, err := NewToDomain(":").SpanToDomain(esSpan)
+	require.NoError(t, err)
+	assert.Equal(t, jSpan.References, actual.References)
+}
+
 func TestFailureBadProcess(t *testing.T) {
 	badProcessESSpan, err := loadESSpanFixture(1)
 	require.NoError(t, err)
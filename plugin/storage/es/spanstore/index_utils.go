@@ -16,6 +16,8 @@ package spanstore
 
 import (
 	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
 )
 
 // returns index name with date
@@ -24,6 +26,15 @@ func indexWithDate(indexPrefix, indexDateLayout string, date time.Time) string {
 	return indexPrefix + spanDate
 }
 
+// returns the index prefix of the tenant when the indices are split by tenant,
+// e.g. jaeger-span-acme- for the tenant acme, or the index prefix if the tenant is empty
+func tenantIndexPrefix(indexPrefix, tenant string) string {
+	if tenant == "" {
+		return indexPrefix
+	}
+	return indexPrefix + es.TenantIndexName(tenant) + indexPrefixSeparator
+}
+
 // returns archive index name
 func archiveIndex(indexPrefix, archiveSuffix string) string {
 	return indexPrefix + archiveSuffix
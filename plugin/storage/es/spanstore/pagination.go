@@ -0,0 +1,275 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/olivere/elastic"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// searchAfterBatchSize is the number of the matching spans read per request when searching for the trace IDs of a page.
+const searchAfterBatchSize = 1000
+
+// searchAfterCursor is the position of a trace in the matching spans sorted by start time and trace ID,
+// in descending order, i.e. the sort values of the most recent matching span of the trace.
+type searchAfterCursor struct {
+	StartTime uint64 `json:"t"`
+	TraceID   string `json:"tid"`
+}
+
+// parseSearchAfterToken decodes a token produced by searchAfterCursor.token.
+// It returns nil cursor for an empty token, i.e. the first page.
+func parseSearchAfterToken(token string) (*searchAfterCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", spanstore.ErrInvalidPageToken, err)
+	}
+	var cursor searchAfterCursor
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, fmt.Errorf("%w: %w", spanstore.ErrInvalidPageToken, err)
+	}
+	if cursor.TraceID == "" {
+		return nil, fmt.Errorf("%w: missing trace ID", spanstore.ErrInvalidPageToken)
+	}
+	return &cursor, nil
+}
+
+func (c *searchAfterCursor) token() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// beforeQuery matches the spans sorted before the cursor, i.e. more recent, or as recent with a greater trace ID.
+func (c *searchAfterCursor) beforeQuery() elastic.Query {
+	return elastic.NewBoolQuery().
+		Should(
+			elastic.NewRangeQuery(startTimeField).Gt(c.StartTime),
+			elastic.NewBoolQuery().Must(
+				elastic.NewTermQuery(startTimeField, c.StartTime),
+				elastic.NewRangeQuery(traceIDField).Gt(c.TraceID),
+			),
+		).
+		MinimumNumberShouldMatch(1)
+}
+
+// FindTracesPage implements spanstore.PaginatedReader. Unlike FindTraces, which aggregates the trace IDs
+// of the most recent matching spans, it reads the matching spans sorted by start time with search_after,
+// so that the pages of very broad searches are complete and are not limited by index.max_result_window.
+// The traces are ordered by their most recent matching span.
+func (s *SpanReader) FindTracesPage(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) (*spanstore.TracesPage, error) {
+	ctx, span := s.tracer.Start(ctx, "FindTracesPage")
+	defer span.End()
+
+	if err := validateQuery(traceQuery); err != nil {
+		return nil, err
+	}
+	cursor, err := parseSearchAfterToken(traceQuery.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	numTraces := traceQuery.NumTraces
+	if numTraces <= 0 {
+		numTraces = defaultNumTraces
+	}
+	esTraceIDs, next, err := s.findTraceIDsPage(ctx, traceQuery, cursor, numTraces)
+	if err != nil {
+		return nil, es.DetailedError(err)
+	}
+	traceIDs, err := convertTraceIDsStringsToModels(esTraceIDs)
+	if err != nil {
+		return nil, err
+	}
+	traces, err := s.multiRead(ctx, traceIDs, traceQuery.StartTimeMin, traceQuery.StartTimeMax)
+	if err != nil {
+		return nil, err
+	}
+	// multiRead does not keep the order of the trace IDs
+	byID := make(map[model.TraceID]*model.Trace, len(traces))
+	for _, trace := range traces {
+		byID[trace.Spans[0].TraceID] = trace
+	}
+	page := &spanstore.TracesPage{Traces: make([]*model.Trace, 0, len(traceIDs))}
+	for _, traceID := range traceIDs {
+		if trace, ok := byID[traceID]; ok {
+			page.Traces = append(page.Traces, trace)
+		}
+	}
+	if next != nil {
+		page.NextPageToken = next.token()
+	}
+	return page, nil
+}
+
+// findTraceIDsPage returns the IDs of the numTraces traces whose most recent matching span is sorted after
+// the cursor, and the cursor of the next page, or nil if there are no more matching traces.
+func (s *SpanReader) findTraceIDsPage(
+	ctx context.Context,
+	traceQuery *spanstore.TraceQueryParameters,
+	cursor *searchAfterCursor,
+	numTraces int,
+) ([]string, *searchAfterCursor, error) {
+	ctx, childSpan := s.tracer.Start(ctx, "findTraceIDsPage")
+	defer childSpan.End()
+
+	boolQuery := s.buildFindTraceIDsQuery(traceQuery)
+	jaegerIndices := s.timeRangeIndices(s.tenantIndexPrefix(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, traceQuery.StartTimeMin, traceQuery.StartTimeMax, s.spanIndexRolloverFrequency)
+	batchSize := searchAfterBatchSize
+	if s.maxDocCount > 0 && s.maxDocCount < batchSize {
+		batchSize = s.maxDocCount
+	}
+
+	var traceIDs []string
+	var positions []*searchAfterCursor
+	seen := make(map[string]bool)
+	after := cursor
+	// one more trace than requested is searched for to find out if there is a next page
+	for len(traceIDs) <= numTraces {
+		hits, err := s.searchSpansAfter(ctx, jaegerIndices, boolQuery, after, batchSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(hits) == 0 {
+			break
+		}
+		// the first hit of a trace is its most recent matching span
+		var candidates []*searchAfterCursor
+		for _, hit := range hits {
+			if !seen[hit.TraceID] {
+				seen[hit.TraceID] = true
+				candidates = append(candidates, hit)
+			}
+		}
+		returned := map[string]bool{}
+		if cursor != nil && len(candidates) > 0 {
+			// the traces with a matching span sorted before the cursor were returned by the previous pages
+			if returned, err = s.findTraceIDsBefore(ctx, jaegerIndices, boolQuery, cursor, candidates); err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, candidate := range candidates {
+			if !returned[candidate.TraceID] {
+				traceIDs = append(traceIDs, candidate.TraceID)
+				positions = append(positions, candidate)
+			}
+		}
+		if len(hits) < batchSize {
+			break
+		}
+		after = hits[len(hits)-1]
+	}
+	if len(traceIDs) <= numTraces {
+		return traceIDs, nil, nil
+	}
+	return traceIDs[:numTraces], positions[numTraces-1], nil
+}
+
+// searchSpansAfter returns the sort values of the matching spans sorted after the cursor.
+func (s *SpanReader) searchSpansAfter(
+	ctx context.Context,
+	indices []string,
+	query elastic.Query,
+	after *searchAfterCursor,
+	size int,
+) ([]*searchAfterCursor, error) {
+	source := elastic.NewSearchSource().
+		Query(query).
+		Size(size).
+		FetchSourceContext(elastic.NewFetchSourceContext(true).Include(traceIDField, startTimeField)).
+		Sort(startTimeField, false).
+		Sort(traceIDField, false)
+	if after != nil {
+		source.SearchAfter(after.StartTime, after.TraceID)
+	}
+	results, err := s.client().MultiSearch().
+		Add(elastic.NewSearchRequest().IgnoreUnavailable(true).Source(source)).
+		Index(indices...).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Responses) == 0 || results.Responses[0] == nil {
+		return nil, nil
+	}
+	result := results.Responses[0]
+	if result.Error != nil {
+		return nil, fmt.Errorf("search spans failed: %s", result.Error.Reason)
+	}
+	if result.Hits == nil {
+		return nil, nil
+	}
+	hits := make([]*searchAfterCursor, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		if hit.Source == nil {
+			return nil, errors.New("search spans failed: missing source of the span")
+		}
+		esSpan, err := s.unmarshalJSONSpan(hit)
+		if err != nil {
+			return nil, fmt.Errorf("search spans failed: %w", err)
+		}
+		hits[i] = &searchAfterCursor{StartTime: esSpan.StartTime, TraceID: string(esSpan.TraceID)}
+	}
+	return hits, nil
+}
+
+// findTraceIDsBefore returns the trace IDs having a matching span sorted before the cursor.
+func (s *SpanReader) findTraceIDsBefore(
+	ctx context.Context,
+	indices []string,
+	query elastic.Query,
+	cursor *searchAfterCursor,
+	candidates []*searchAfterCursor,
+) (map[string]bool, error) {
+	values := make([]any, len(candidates))
+	for i, candidate := range candidates {
+		values[i] = candidate.TraceID
+	}
+	boolQuery := elastic.NewBoolQuery().Must(
+		query,
+		elastic.NewTermsQuery(traceIDField, values...),
+		cursor.beforeQuery(),
+	)
+	searchResult, err := s.client().Search(indices...).
+		Size(0). // set to 0 because we don't want actual documents.
+		Aggregation(traceIDAggregation, elastic.NewTermsAggregation().Field(traceIDField).Size(len(candidates))).
+		IgnoreUnavailable(true).
+		Query(boolQuery).
+		Do(ctx)
+	if err != nil {
+		err = es.DetailedError(err)
+		s.logger.Info("es search trace IDs before cursor failed", zap.Error(err))
+		return nil, fmt.Errorf("search trace IDs failed: %w", err)
+	}
+	found := make(map[string]bool)
+	if searchResult.Aggregations == nil {
+		return found, nil
+	}
+	bucket, ok := searchResult.Aggregations.Terms(traceIDAggregation)
+	if !ok {
+		return nil, ErrUnableToFindTraceIDAggregation
+	}
+	ids, err := bucketToStringArray(bucket.Buckets)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		found[id] = true
+	}
+	return found, nil
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func TestSearchAfterToken(t *testing.T) {
+	cursor := &searchAfterCursor{StartTime: 1234, TraceID: "abc"}
+	parsed, err := parseSearchAfterToken(cursor.token())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	parsed, err = parseSearchAfterToken("")
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	for _, token := range []string{
+		"not base64!",
+		encode("{"),
+		// the tokens of the other readers are not valid
+		(&spanstore.PageCursor{StartTime: time.Now(), TraceIDs: []model.TraceID{model.NewTraceID(0, 1)}}).Token(),
+		encode(`{"t": 1234}`),
+	} {
+		_, err := parseSearchAfterToken(token)
+		require.ErrorIs(t, err, spanstore.ErrInvalidPageToken, token)
+	}
+}
+
+func esSpanHit(traceID string, spanID int, startTime uint64) *elastic.SearchHit {
+	source := json.RawMessage(fmt.Sprintf(
+		`{"traceID": %q, "spanID": "%x", "operationName": "op", "startTime": %d, "duration": 1, "process": {"serviceName": "svc"}}`,
+		traceID, spanID, startTime))
+	return &elastic.SearchHit{Source: &source}
+}
+
+func searchResult(hits ...*elastic.SearchHit) *elastic.MultiSearchResult {
+	return &elastic.MultiSearchResult{
+		Responses: []*elastic.SearchResult{
+			{Hits: &elastic.SearchHits{Hits: hits, TotalHits: int64(len(hits))}},
+		},
+	}
+}
+
+// mockPaginationMultiSearch returns the multi search service of a reader searching a single index,
+// and the bodies of the search requests.
+func mockPaginationMultiSearch(r *spanReaderTest) (*mocks.MultiSearchService, *[]string) {
+	var bodies []string
+	multiSearchService := &mocks.MultiSearchService{}
+	addRequests := func(args mock.Arguments) {
+		for _, arg := range args {
+			if body, err := arg.(*elastic.SearchRequest).Body(); err == nil {
+				bodies = append(bodies, body)
+			}
+		}
+	}
+	// multiRead adds a search request per trace
+	multiSearchService.On("Add", mock.Anything).Run(addRequests).Return(multiSearchService)
+	multiSearchService.On("Add", mock.Anything, mock.Anything).Run(addRequests).Return(multiSearchService)
+	multiSearchService.On("Index", mock.AnythingOfType("string")).Return(multiSearchService)
+	r.client.On("MultiSearch").Return(multiSearchService)
+	return multiSearchService, &bodies
+}
+
+func mockTraceIDsBeforeSearch(r *spanReaderTest) *mock.Call {
+	searchService := &mocks.SearchService{}
+	searchService.On("Query", mock.Anything).Return(searchService)
+	searchService.On("IgnoreUnavailable", true).Return(searchService)
+	searchService.On("Size", 0).Return(searchService)
+	searchService.On("Aggregation", traceIDAggregation, mock.AnythingOfType("*elastic.TermsAggregation")).Return(searchService)
+	r.client.On("Search", mock.AnythingOfType("string")).Return(searchService)
+	return searchService.On("Do", mock.Anything)
+}
+
+func traceIDBuckets(ids ...string) *elastic.SearchResult {
+	buckets := `{"buckets": [`
+	for i, id := range ids {
+		if i > 0 {
+			buckets += ","
+		}
+		buckets += fmt.Sprintf(`{"key": %q, "doc_count": 1}`, id)
+	}
+	raw := json.RawMessage(buckets + `]}`)
+	return &elastic.SearchResult{Aggregations: elastic.Aggregations{traceIDAggregation: &raw}}
+}
+
+func pageQuery(numTraces int, token string) *spanstore.TraceQueryParameters {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	return &spanstore.TraceQueryParameters{
+		ServiceName:  "svc",
+		StartTimeMin: start,
+		StartTimeMax: start.Add(time.Hour),
+		NumTraces:    numTraces,
+		PageToken:    token,
+	}
+}
+
+func traceIDsOf(traces []*model.Trace) []model.TraceID {
+	ids := make([]model.TraceID, len(traces))
+	for i, trace := range traces {
+		ids[i] = trace.Spans[0].TraceID
+	}
+	return ids
+}
+
+func TestSpanReader_FindTracesPage(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		multiSearch, bodies := mockPaginationMultiSearch(r)
+		// the matching spans, the trace 3 having two of them
+		multiSearch.On("Do", mock.Anything).Return(searchResult(
+			esSpanHit("3", 1, 300), esSpanHit("2", 1, 200), esSpanHit("3", 2, 150), esSpanHit("1", 1, 100),
+		), nil).Once()
+		// the traces of the page, in another order
+		multiSearch.On("Do", mock.Anything).Return(&elastic.MultiSearchResult{
+			Responses: []*elastic.SearchResult{
+				{Hits: &elastic.SearchHits{Hits: []*elastic.SearchHit{esSpanHit("2", 1, 200)}, TotalHits: 1}},
+				{Hits: &elastic.SearchHits{Hits: []*elastic.SearchHit{esSpanHit("3", 1, 300), esSpanHit("3", 2, 150)}, TotalHits: 2}},
+			},
+		}, nil).Once()
+
+		page, err := r.reader.FindTracesPage(context.Background(), pageQuery(2, ""))
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{model.NewTraceID(0, 3), model.NewTraceID(0, 2)}, traceIDsOf(page.Traces))
+		assert.Equal(t, (&searchAfterCursor{StartTime: 200, TraceID: "2"}).token(), page.NextPageToken)
+		require.NotEmpty(t, *bodies)
+		assert.Contains(t, (*bodies)[0], `"sort":[{"startTime":{"order":"desc"}},{"traceID":{"order":"desc"}}]`)
+		assert.NotContains(t, (*bodies)[0], "search_after")
+	})
+
+	withSpanReader(t, func(r *spanReaderTest) {
+		multiSearch, bodies := mockPaginationMultiSearch(r)
+		// the matching spans after the trace 2
+		multiSearch.On("Do", mock.Anything).Return(searchResult(
+			esSpanHit("3", 2, 150), esSpanHit("1", 1, 100),
+		), nil).Once()
+		multiSearch.On("Do", mock.Anything).Return(searchResult(esSpanHit("1", 1, 100)), nil).Once()
+		// the trace 3 was returned by the first page
+		mockTraceIDsBeforeSearch(r).Return(traceIDBuckets("3"), nil)
+
+		token := (&searchAfterCursor{StartTime: 200, TraceID: "2"}).token()
+		page, err := r.reader.FindTracesPage(context.Background(), pageQuery(2, token))
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{model.NewTraceID(0, 1)}, traceIDsOf(page.Traces))
+		assert.Empty(t, page.NextPageToken)
+		require.NotEmpty(t, *bodies)
+		assert.Contains(t, (*bodies)[0], `"search_after":[200,"2"]`)
+	})
+}
+
+func TestSpanReader_FindTracesPageBatches(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		r.reader.maxDocCount = 2
+		multiSearch, bodies := mockPaginationMultiSearch(r)
+		// the batches of the matching spans
+		multiSearch.On("Do", mock.Anything).Return(searchResult(esSpanHit("4", 1, 400), esSpanHit("4", 2, 350)), nil).Once()
+		multiSearch.On("Do", mock.Anything).Return(searchResult(esSpanHit("3", 1, 300), esSpanHit("2", 1, 200)), nil).Once()
+		// the traces of the page
+		multiSearch.On("Do", mock.Anything).Return(&elastic.MultiSearchResult{
+			Responses: []*elastic.SearchResult{
+				{Hits: &elastic.SearchHits{Hits: []*elastic.SearchHit{esSpanHit("4", 1, 400)}, TotalHits: 1}},
+			},
+		}, nil).Once()
+
+		page, err := r.reader.FindTracesPage(context.Background(), pageQuery(1, ""))
+		require.NoError(t, err)
+		assert.Equal(t, []model.TraceID{model.NewTraceID(0, 4)}, traceIDsOf(page.Traces))
+		assert.Equal(t, (&searchAfterCursor{StartTime: 400, TraceID: "4"}).token(), page.NextPageToken)
+		require.Len(t, *bodies, 3)
+		assert.Contains(t, (*bodies)[1], `"search_after":[350,"4"]`)
+	})
+}
+
+func TestSpanReader_FindTracesPageErrors(t *testing.T) {
+	t.Run("invalid query", func(t *testing.T) {
+		withSpanReader(t, func(r *spanReaderTest) {
+			_, err := r.reader.FindTracesPage(context.Background(), &spanstore.TraceQueryParameters{})
+			require.ErrorIs(t, err, ErrStartAndEndTimeNotSet)
+		})
+	})
+	t.Run("invalid token", func(t *testing.T) {
+		withSpanReader(t, func(r *spanReaderTest) {
+			_, err := r.reader.FindTracesPage(context.Background(), pageQuery(1, "invalid!"))
+			require.ErrorIs(t, err, spanstore.ErrInvalidPageToken)
+		})
+	})
+	t.Run("search failure", func(t *testing.T) {
+		withSpanReader(t, func(r *spanReaderTest) {
+			multiSearch, _ := mockPaginationMultiSearch(r)
+			multiSearch.On("Do", mock.Anything).Return(nil, errors.New("search failed"))
+			_, err := r.reader.FindTracesPage(context.Background(), pageQuery(1, ""))
+			require.ErrorContains(t, err, "search failed")
+		})
+	})
+	t.Run("search response error", func(t *testing.T) {
+		withSpanReader(t, func(r *spanReaderTest) {
+			multiSearch, _ := mockPaginationMultiSearch(r)
+			multiSearch.On("Do", mock.Anything).Return(&elastic.MultiSearchResult{
+				Responses: []*elastic.SearchResult{{Error: &elastic.ErrorDetails{Reason: "too many buckets"}}},
+			}, nil)
+			_, err := r.reader.FindTracesPage(context.Background(), pageQuery(1, ""))
+			require.EqualError(t, err, "search spans failed: too many buckets")
+		})
+	})
+	t.Run("malformed span", func(t *testing.T) {
+		withSpanReader(t, func(r *spanReaderTest) {
+			multiSearch, _ := mockPaginationMultiSearch(r)
+			source := json.RawMessage(`{"traceID": 1}`)
+			multiSearch.On("Do", mock.Anything).Return(searchResult(&elastic.SearchHit{Source: &source}), nil)
+			_, err := r.reader.FindTracesPage(context.Background(), pageQuery(1, ""))
+			require.ErrorContains(t, err, "search spans failed")
+		})
+	})
+	t.Run("trace IDs before the cursor failure", func(t *testing.T) {
+		withSpanReader(t, func(r *spanReaderTest) {
+			multiSearch, _ := mockPaginationMultiSearch(r)
+			multiSearch.On("Do", mock.Anything).Return(searchResult(esSpanHit("1", 1, 100)), nil)
+			mockTraceIDsBeforeSearch(r).Return(nil, errors.New("aggregation failed"))
+			token := (&searchAfterCursor{StartTime: 200, TraceID: "2"}).token()
+			_, err := r.reader.FindTracesPage(context.Background(), pageQuery(1, token))
+			require.ErrorContains(t, err, "search trace IDs failed: aggregation failed")
+		})
+	})
+	t.Run("missing aggregation", func(t *testing.T) {
+		withSpanReader(t, func(r *spanReaderTest) {
+			multiSearch, _ := mockPaginationMultiSearch(r)
+			multiSearch.On("Do", mock.Anything).Return(searchResult(esSpanHit("1", 1, 100)), nil)
+			mockTraceIDsBeforeSearch(r).Return(&elastic.SearchResult{Aggregations: elastic.Aggregations{}}, nil)
+			token := (&searchAfterCursor{StartTime: 200, TraceID: "2"}).token()
+			_, err := r.reader.FindTracesPage(context.Background(), pageQuery(1, token))
+			require.ErrorIs(t, err, ErrUnableToFindTraceIDAggregation)
+		})
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/olivere/elastic"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// purgeBatchSize is the number of traces searched at once by PurgeTraces.
+const purgeBatchSize = 1000
+
+// SpanPurger deletes the spans of the traces from the span indices with delete by query requests
+type SpanPurger struct {
+	reader *SpanReader
+}
+
+// NewSpanPurger returns a SpanPurger finding the traces to delete with the reader
+func NewSpanPurger(reader *SpanReader) *SpanPurger {
+	return &SpanPurger{reader: reader}
+}
+
+// PurgeTraces implements spanstore.Purger
+func (p *SpanPurger) PurgeTraces(ctx context.Context, query *spanstore.TraceQueryParameters) (int, error) {
+	if err := spanstore.ValidatePurgeQuery(query); err != nil {
+		return 0, err
+	}
+	// the reader requires a time range, the purges default to the retained traces
+	q := *query
+	if q.StartTimeMax.IsZero() {
+		q.StartTimeMax = time.Now()
+	}
+	if q.StartTimeMin.IsZero() {
+		q.StartTimeMin = q.StartTimeMax.Add(-p.reader.maxSpanAge)
+	}
+	q.NumTraces = purgeBatchSize
+	return spanstore.PurgeFoundTraces(ctx, &q, p.reader.FindTraceIDs, p.deleteTraces)
+}
+
+func (p *SpanPurger) deleteTraces(ctx context.Context, traceIDs []model.TraceID) error {
+	ids := make([]any, 0, 2*len(traceIDs))
+	for _, traceID := range traceIDs {
+		ids = append(ids, traceID.String())
+		if legacyID := legacyTraceID(traceID); legacyID != traceID.String() {
+			ids = append(ids, legacyID)
+		}
+	}
+	_, err := p.reader.client().DeleteByQuery(p.reader.tenantIndexPrefix(ctx, p.reader.spanIndexPrefix) + "*").
+		Query(elastic.NewTermsQuery(traceIDField, ids...)).
+		IgnoreUnavailable(true).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete the spans of the traces: %w", err)
+	}
+	return nil
+}
+
+// PurgeAll implements spanstore.Purger
+func (p *SpanPurger) PurgeAll(ctx context.Context) error {
+	_, err := p.reader.client().DeleteByQuery(
+		p.reader.tenantIndexPrefix(ctx, p.reader.spanIndexPrefix)+"*",
+		p.reader.tenantIndexPrefix(ctx, p.reader.serviceIndexPrefix)+"*").
+		Query(elastic.NewMatchAllQuery()).
+		IgnoreUnavailable(true).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete all the spans: %w", err)
+	}
+	return nil
+}
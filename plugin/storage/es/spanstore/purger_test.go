@@ -0,0 +1,102 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func traceIDsSearchResult(traceIDs ...string) *elastic.SearchResult {
+	var buckets []map[string]any
+	for _, traceID := range traceIDs {
+		buckets = append(buckets, map[string]any{"key": traceID, "doc_count": 1})
+	}
+	raw, _ := json.Marshal(map[string]any{"buckets": buckets})
+	rawMessage := json.RawMessage(raw)
+	return &elastic.SearchResult{Aggregations: elastic.Aggregations{traceIDAggregation: &rawMessage}}
+}
+
+// mockTraceIDsSearch answers the searches with the trace IDs of the results, in order
+func mockTraceIDsSearch(r *spanReaderTest, results ...[]string) {
+	var searches int
+	searchService := &mocks.SearchService{}
+	searchService.On("Query", mock.Anything).Return(searchService)
+	searchService.On("IgnoreUnavailable", mock.AnythingOfType("bool")).Return(searchService)
+	searchService.On("Size", 0).Return(searchService)
+	searchService.On("Aggregation", traceIDAggregation, mock.Anything).Return(searchService)
+	searchService.On("Do", mock.Anything).Return(func(context.Context) *elastic.SearchResult {
+		searches++
+		return traceIDsSearchResult(results[min(searches, len(results))-1]...)
+	}, nil)
+	r.client.On("Search", mock.Anything).Return(searchService)
+}
+
+func mockDeleteByQueryService(r *spanReaderTest, indices ...any) (*mocks.DeleteByQueryService, *mock.Call) {
+	deleteService := &mocks.DeleteByQueryService{}
+	deleteService.On("Query", mock.Anything).Return(deleteService)
+	deleteService.On("IgnoreUnavailable", true).Return(deleteService)
+	r.client.On("DeleteByQuery", indices...).Return(deleteService)
+	return deleteService, deleteService.On("Do", mock.Anything)
+}
+
+func TestSpanPurgerPurgeTraces(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		mockTraceIDsSearch(r, []string{"1", "00000000000000000000000000000002"}, []string{"1"})
+		deleteService, deleteCall := mockDeleteByQueryService(r, "jaeger-span-*")
+		deleteCall.Return(&elastic.BulkIndexByScrollResponse{}, nil)
+
+		now := time.Now()
+		purged, err := NewSpanPurger(r.reader).PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{
+			ServiceName:  "frontend",
+			Tags:         map[string]string{"user.id": "42"},
+			StartTimeMin: now,
+			StartTimeMax: now,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, purged)
+
+		// the legacy IDs without the leading zeros are deleted as well
+		deleteService.AssertCalled(t, "Query", elastic.NewTermsQuery(traceIDField,
+			"0000000000000001", "1", "0000000000000002", "2"))
+	})
+}
+
+func TestSpanPurgerPurgeTracesErrors(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		purger := NewSpanPurger(r.reader)
+		_, err := purger.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{})
+		require.ErrorIs(t, err, spanstore.ErrPurgeQueryServiceRequired)
+
+		mockTraceIDsSearch(r, []string{"1"})
+		_, deleteCall := mockDeleteByQueryService(r, "jaeger-span-*")
+		deleteCall.Return(nil, assert.AnError)
+		_, err = purger.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "frontend"})
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to delete the spans of the traces")
+	})
+}
+
+func TestSpanPurgerPurgeAll(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		deleteService, deleteCall := mockDeleteByQueryService(r, "jaeger-span-*", "jaeger-service-*")
+		deleteCall.Return(&elastic.BulkIndexByScrollResponse{}, nil).Once()
+		deleteService.On("Do", mock.Anything).Return(nil, assert.AnError).Once()
+
+		purger := NewSpanPurger(r.reader)
+		require.NoError(t, purger.PurgeAll(context.Background()))
+		deleteService.AssertCalled(t, "Query", elastic.NewMatchAllQuery())
+		require.ErrorIs(t, purger.PurgeAll(context.Background()), assert.AnError)
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/olivere/elastic"
@@ -32,6 +33,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
@@ -43,6 +45,16 @@ const (
 	archiveReadIndexSuffix  = archiveIndexSuffix + "-read"
 	archiveWriteIndexSuffix = archiveIndexSuffix + "-write"
 	traceIDAggregation      = "traceIDs"
+	durationPercentilesAgg  = "durationPercentiles"
+	durationHistogramAgg    = "durationHistogram"
+	redServicesAgg          = "redServices"
+	redOperationsAgg        = "redOperations"
+	redStepsAgg             = "redSteps"
+	redLatencyAgg           = "redLatency"
+	redErrorsAgg            = "redErrors"
+	tagsAgg                 = "tags"
+	tagsFilterAgg           = "tagsFilter"
+	tagsTermsAgg            = "tagsTerms"
 	indexPrefixSeparator    = "-"
 
 	traceIDField           = "traceID"
@@ -58,8 +70,12 @@ const (
 	nestedLogFieldsField   = "logs.fields"
 	tagKeyField            = "key"
 	tagValueField          = "value"
+	spanKindTag            = "span.kind"
+	errorTag               = "error"
 
 	defaultNumTraces = 100
+	// maxREDOperations is the maximum number of operations of each service aggregated by GetREDMetrics.
+	maxREDOperations = 1000
 
 	rolloverMaxSpanAge = time.Hour * 24 * 365 * 50
 )
@@ -83,6 +99,15 @@ var (
 	// ErrUnableToFindTraceIDAggregation occurs when an aggregation query for TraceIDs fail.
 	ErrUnableToFindTraceIDAggregation = errors.New("could not find aggregation of traceIDs")
 
+	// ErrUnableToFindDurationAggregation occurs when an aggregation query for span durations fail.
+	ErrUnableToFindDurationAggregation = errors.New("could not find aggregation of durations")
+
+	// ErrUnableToFindREDAggregation occurs when an aggregation query for RED metrics fail.
+	ErrUnableToFindREDAggregation = errors.New("could not find aggregation of RED metrics")
+
+	// ErrUnableToFindTagsAggregation occurs when an aggregation query for tag keys or values fail.
+	ErrUnableToFindTagsAggregation = errors.New("could not find aggregation of tags")
+
 	defaultMaxDuration = model.DurationAsMicroseconds(time.Hour * 24)
 
 	objectTagFieldList = []string{objectTagsField, objectProcessTagsField}
@@ -108,6 +133,7 @@ type SpanReader struct {
 	sourceFn                      sourceFn
 	maxDocCount                   int
 	useReadWriteAliases           bool
+	indexPerTenant                bool
 	logger                        *zap.Logger
 	tracer                        trace.Tracer
 }
@@ -126,9 +152,12 @@ type SpanReaderParams struct {
 	Archive                       bool
 	UseReadWriteAliases           bool
 	RemoteReadClusters            []string
-	MetricsFactory                metrics.Factory
-	Logger                        *zap.Logger
-	Tracer                        trace.Tracer
+	// IndexPerTenant reads the spans of the tenant of each request from its own indices,
+	// see SpanWriterParams.IndexPerTenant.
+	IndexPerTenant bool
+	MetricsFactory metrics.Factory
+	Logger         *zap.Logger
+	Tracer         trace.Tracer
 }
 
 // NewSpanReader returns a new SpanReader with a metrics.
@@ -154,6 +183,7 @@ func NewSpanReader(p SpanReaderParams) *SpanReader {
 		sourceFn:                      getSourceFn(p.Archive, p.MaxDocCount),
 		maxDocCount:                   p.MaxDocCount,
 		useReadWriteAliases:           p.UseReadWriteAliases,
+		indexPerTenant:                p.IndexPerTenant,
 		logger:                        p.Logger,
 		tracer:                        p.Tracer,
 	}
@@ -239,6 +269,14 @@ func indexNames(prefix, index string) string {
 	return index
 }
 
+// tenantIndexPrefix returns the index prefix of the tenant of the request when the indices are split by tenant
+func (s *SpanReader) tenantIndexPrefix(ctx context.Context, indexPrefix string) string {
+	if !s.indexPerTenant {
+		return indexPrefix
+	}
+	return tenantIndexPrefix(indexPrefix, tenancy.GetTenant(ctx))
+}
+
 // GetTrace takes a traceID and returns a Trace associated with that traceID
 func (s *SpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
 	ctx, span := s.tracer.Start(ctx, "GetTrace")
@@ -254,6 +292,29 @@ func (s *SpanReader) GetTrace(ctx context.Context, traceID model.TraceID) (*mode
 	return traces[0], nil
 }
 
+// GetTraceInTimeRange implements spanstore.TimeRangeReader. Only the indices covering the
+// time range of the query are searched, an unbounded side defaulting to the max span age.
+func (s *SpanReader) GetTraceInTimeRange(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	ctx, span := s.tracer.Start(ctx, "GetTraceInTimeRange")
+	defer span.End()
+	startTime, endTime := query.StartTime, query.EndTime
+	currentTime := time.Now()
+	if endTime.IsZero() {
+		endTime = currentTime
+	}
+	if startTime.IsZero() {
+		startTime = currentTime.Add(-s.maxSpanAge)
+	}
+	traces, err := s.multiRead(ctx, []model.TraceID{query.TraceID}, startTime, endTime)
+	if err != nil {
+		return nil, es.DetailedError(err)
+	}
+	if len(traces) == 0 {
+		return nil, spanstore.ErrTraceNotFound
+	}
+	return traces[0], nil
+}
+
 func (s *SpanReader) collectSpans(esSpansRaw []*elastic.SearchHit) ([]*model.Span, error) {
 	spans := make([]*model.Span, len(esSpansRaw))
 
@@ -289,7 +350,7 @@ func (s *SpanReader) GetServices(ctx context.Context) ([]string, error) {
 	ctx, span := s.tracer.Start(ctx, "GetService")
 	defer span.End()
 	currentTime := time.Now()
-	jaegerIndices := s.timeRangeIndices(s.serviceIndexPrefix, s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
+	jaegerIndices := s.timeRangeIndices(s.tenantIndexPrefix(ctx, s.serviceIndexPrefix), s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
 	return s.serviceOperationStorage.getServices(ctx, jaegerIndices, s.maxDocCount)
 }
 
@@ -300,9 +361,30 @@ func (s *SpanReader) GetOperations(
 ) ([]spanstore.Operation, error) {
 	ctx, span := s.tracer.Start(ctx, "GetOperations")
 	defer span.End()
+	return s.findOperations(ctx, spanstore.OperationQueryParameters{
+		ServiceName: query.ServiceName,
+		SpanKind:    query.SpanKind,
+	})
+}
+
+// FindOperations implements spanstore.OperationFilterReader. The operation names are filtered
+// and paged by the terms aggregation of the service index.
+func (s *SpanReader) FindOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+) ([]spanstore.Operation, error) {
+	ctx, span := s.tracer.Start(ctx, "FindOperations")
+	defer span.End()
+	return s.findOperations(ctx, query)
+}
+
+func (s *SpanReader) findOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+) ([]spanstore.Operation, error) {
 	currentTime := time.Now()
-	jaegerIndices := s.timeRangeIndices(s.serviceIndexPrefix, s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
-	operations, err := s.serviceOperationStorage.getOperations(ctx, jaegerIndices, query.ServiceName, s.maxDocCount)
+	jaegerIndices := s.timeRangeIndices(s.tenantIndexPrefix(ctx, s.serviceIndexPrefix), s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
+	operations, err := s.serviceOperationStorage.getOperations(ctx, jaegerIndices, query, s.maxDocCount)
 	if err != nil {
 		return nil, err
 	}
@@ -342,6 +424,322 @@ func (s *SpanReader) FindTraces(ctx context.Context, traceQuery *spanstore.Trace
 	return s.multiRead(ctx, uniqueTraceIDs, traceQuery.StartTimeMin, traceQuery.StartTimeMax)
 }
 
+// GetLatencyDistribution implements spanstore.LatencyReader by aggregating span
+// durations with percentiles and range aggregations.
+func (s *SpanReader) GetLatencyDistribution(ctx context.Context, query *spanstore.LatencyQueryParameters) (*spanstore.LatencyDistribution, error) {
+	ctx, span := s.tracer.Start(ctx, "GetLatencyDistribution")
+	defer span.End()
+
+	bounds := query.BucketBounds
+	if len(bounds) == 0 {
+		bounds = spanstore.DefaultLatencyBucketBounds
+	}
+	boolQuery := elastic.NewBoolQuery().
+		Must(s.buildStartTimeQuery(query.StartTimeMin, query.StartTimeMax)).
+		Must(s.buildServiceNameQuery(query.ServiceName))
+	if query.OperationName != "" {
+		boolQuery.Must(s.buildOperationNameQuery(query.OperationName))
+	}
+	jaegerIndices := s.timeRangeIndices(s.tenantIndexPrefix(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, query.StartTimeMin, query.StartTimeMax, s.spanIndexRolloverFrequency)
+
+	searchResult, err := s.client().Search(jaegerIndices...).
+		Size(0). // set to 0 because we don't want actual documents.
+		Aggregation(durationPercentilesAgg, elastic.NewPercentilesAggregation().Field(durationField).Percentiles(50, 95, 99)).
+		Aggregation(durationHistogramAgg, s.buildDurationHistogramAggregation(bounds)).
+		IgnoreUnavailable(true).
+		Query(boolQuery).
+		Do(ctx)
+	if err != nil {
+		err = es.DetailedError(err)
+		s.logger.Info("es search durations failed", zap.Any("latencyQuery", query), zap.Error(err))
+		return nil, fmt.Errorf("search durations failed: %w", err)
+	}
+
+	dist := &spanstore.LatencyDistribution{Buckets: spanstore.NewDurationBuckets(bounds)}
+	if searchResult.Aggregations == nil {
+		return dist, nil
+	}
+	percentiles, found := searchResult.Aggregations.Percentiles(durationPercentilesAgg)
+	if !found {
+		return nil, ErrUnableToFindDurationAggregation
+	}
+	dist.P50 = percentileValue(percentiles, "50.0")
+	dist.P95 = percentileValue(percentiles, "95.0")
+	dist.P99 = percentileValue(percentiles, "99.0")
+	histogram, found := searchResult.Aggregations.Range(durationHistogramAgg)
+	if !found || len(histogram.Buckets) != len(dist.Buckets) {
+		return nil, ErrUnableToFindDurationAggregation
+	}
+	for i, bucket := range histogram.Buckets {
+		dist.Buckets[i].Count = bucket.DocCount
+		dist.Count += bucket.DocCount
+	}
+	return dist, nil
+}
+
+func (*SpanReader) buildDurationHistogramAggregation(bounds []time.Duration) elastic.Aggregation {
+	agg := elastic.NewRangeAggregation().Field(durationField)
+	var from uint64
+	for _, bound := range bounds {
+		to := model.DurationAsMicroseconds(bound)
+		agg.AddRange(from, to)
+		from = to
+	}
+	return agg.AddUnboundedTo(from)
+}
+
+func percentileValue(percentiles *elastic.AggregationPercentilesMetric, key string) time.Duration {
+	// durations are stored in microseconds
+	return model.MicrosecondsAsDuration(uint64(percentiles.Values[key]))
+}
+
+// GetREDMetrics implements spanstore.REDReader by aggregating the spans of the services
+// per operation and per step, without loading them.
+func (s *SpanReader) GetREDMetrics(ctx context.Context, query *spanstore.REDQueryParameters) ([]spanstore.REDSeries, error) {
+	ctx, span := s.tracer.Start(ctx, "GetREDMetrics")
+	defer span.End()
+
+	if len(query.ServiceNames) == 0 {
+		return []spanstore.REDSeries{}, nil
+	}
+	services := make([]any, len(query.ServiceNames))
+	for i, service := range query.ServiceNames {
+		services[i] = service
+	}
+	boolQuery := elastic.NewBoolQuery().
+		Must(s.buildStartTimeQuery(query.StartTime, query.EndTime)).
+		Must(elastic.NewTermsQuery(serviceNameField, services...))
+	if len(query.SpanKinds) > 0 {
+		kindQueries := make([]elastic.Query, len(query.SpanKinds))
+		for i, kind := range query.SpanKinds {
+			kindQueries[i] = s.buildTagQuery(spanKindTag, kind)
+		}
+		boolQuery.Must(elastic.NewBoolQuery().Should(kindQueries...))
+	}
+	stepsAgg := s.buildREDStepsAggregation(query)
+	var seriesAgg elastic.Aggregation = stepsAgg
+	if query.GroupByOperation {
+		seriesAgg = elastic.NewTermsAggregation().Field(operationNameField).Size(maxREDOperations).
+			SubAggregation(redStepsAgg, stepsAgg)
+	}
+	servicesAgg := elastic.NewTermsAggregation().Field(serviceNameField).Size(len(query.ServiceNames))
+	if query.GroupByOperation {
+		servicesAgg.SubAggregation(redOperationsAgg, seriesAgg)
+	} else {
+		servicesAgg.SubAggregation(redStepsAgg, seriesAgg)
+	}
+	jaegerIndices := s.timeRangeIndices(s.tenantIndexPrefix(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, query.StartTime, query.EndTime, s.spanIndexRolloverFrequency)
+
+	searchResult, err := s.client().Search(jaegerIndices...).
+		Size(0). // set to 0 because we don't want actual documents.
+		Aggregation(redServicesAgg, servicesAgg).
+		IgnoreUnavailable(true).
+		Query(boolQuery).
+		Do(ctx)
+	if err != nil {
+		err = es.DetailedError(err)
+		s.logger.Info("es search RED metrics failed", zap.Any("redQuery", query), zap.Error(err))
+		return nil, fmt.Errorf("search RED metrics failed: %w", err)
+	}
+
+	result := []spanstore.REDSeries{}
+	if searchResult.Aggregations == nil {
+		return result, nil
+	}
+	servicesBuckets, found := searchResult.Aggregations.Terms(redServicesAgg)
+	if !found {
+		return nil, ErrUnableToFindREDAggregation
+	}
+	for _, serviceBucket := range servicesBuckets.Buckets {
+		service, _ := serviceBucket.Key.(string)
+		if !query.GroupByOperation {
+			points, err := s.toREDPoints(serviceBucket.Aggregations, query)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, spanstore.REDSeries{ServiceName: service, Points: points})
+			continue
+		}
+		operationsBuckets, found := serviceBucket.Terms(redOperationsAgg)
+		if !found {
+			return nil, ErrUnableToFindREDAggregation
+		}
+		for _, operationBucket := range operationsBuckets.Buckets {
+			operation, _ := operationBucket.Key.(string)
+			points, err := s.toREDPoints(operationBucket.Aggregations, query)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, spanstore.REDSeries{ServiceName: service, OperationName: operation, Points: points})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ServiceName != result[j].ServiceName {
+			return result[i].ServiceName < result[j].ServiceName
+		}
+		return result[i].OperationName < result[j].OperationName
+	})
+	return result, nil
+}
+
+func (s *SpanReader) buildREDStepsAggregation(query *spanstore.REDQueryParameters) *elastic.DateHistogramAggregation {
+	stepMillis := query.Step.Milliseconds()
+	// align the buckets on the start of the query rather than on the epoch
+	offsetMillis := query.StartTime.UnixMilli() % stepMillis
+	return elastic.NewDateHistogramAggregation().
+		Field(startTimeMillisField).
+		Interval(fmt.Sprintf("%dms", stepMillis)).
+		Offset(fmt.Sprintf("%dms", offsetMillis)).
+		MinDocCount(1).
+		SubAggregation(redLatencyAgg, elastic.NewPercentilesAggregation().Field(durationField).Percentiles(query.Quantile*100)).
+		SubAggregation(redErrorsAgg, elastic.NewFilterAggregation().Filter(s.buildTagQuery(errorTag, "true")))
+}
+
+func (*SpanReader) toREDPoints(aggregations elastic.Aggregations, query *spanstore.REDQueryParameters) ([]spanstore.REDPoint, error) {
+	steps, found := aggregations.DateHistogram(redStepsAgg)
+	if !found {
+		return nil, ErrUnableToFindREDAggregation
+	}
+	points := make([]spanstore.REDPoint, 0, len(steps.Buckets))
+	for _, step := range steps.Buckets {
+		point := spanstore.REDPoint{
+			Timestamp: time.UnixMilli(int64(step.Key)).UTC().Add(query.Step),
+			Calls:     step.DocCount,
+		}
+		if errorsBucket, found := step.Filter(redErrorsAgg); found {
+			point.Errors = errorsBucket.DocCount
+		}
+		if latency, found := step.Percentiles(redLatencyAgg); found {
+			for _, value := range latency.Values {
+				// durations are stored in microseconds
+				point.Latency = model.MicrosecondsAsDuration(uint64(value))
+			}
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// GetTagKeys implements spanstore.TagReader by aggregating the keys of the nested tags.
+// The keys of the tags stored as object fields (--es.tags-as-fields.all) are not listed,
+// since they are the names of fields rather than values.
+func (s *SpanReader) GetTagKeys(ctx context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	ctx, span := s.tracer.Start(ctx, "GetTagKeys")
+	defer span.End()
+
+	var aggs []elastic.Aggregation
+	for _, field := range nestedTagFieldList {
+		keyField := fmt.Sprintf("%s.%s", field, tagKeyField)
+		filter := s.buildTagPrefixQuery(keyField, query.Prefix)
+		aggs = append(aggs, elastic.NewNestedAggregation().Path(field).
+			SubAggregation(tagsFilterAgg, s.buildTagTermsAggregation(filter, keyField, query)))
+	}
+	return s.searchTags(ctx, query, aggs, nil)
+}
+
+// GetTagValues implements spanstore.TagReader by aggregating the values of the tags with the key,
+// whether they are nested or object fields.
+func (s *SpanReader) GetTagValues(ctx context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	ctx, span := s.tracer.Start(ctx, "GetTagValues")
+	defer span.End()
+
+	var nestedAggs, objectAggs []elastic.Aggregation
+	for _, field := range nestedTagFieldList {
+		valueField := fmt.Sprintf("%s.%s", field, tagValueField)
+		filter := elastic.NewBoolQuery().
+			Must(elastic.NewTermQuery(fmt.Sprintf("%s.%s", field, tagKeyField), query.Key)).
+			Must(s.buildTagPrefixQuery(valueField, query.Prefix))
+		nestedAggs = append(nestedAggs, elastic.NewNestedAggregation().Path(field).
+			SubAggregation(tagsFilterAgg, s.buildTagTermsAggregation(filter, valueField, query)))
+	}
+	for _, field := range objectTagFieldList {
+		valueField := fmt.Sprintf("%s.%s", field, s.spanConverter.ReplaceDot(query.Key))
+		objectAggs = append(objectAggs, s.buildTagTermsAggregation(s.buildTagPrefixQuery(valueField, query.Prefix), valueField, query))
+	}
+	return s.searchTags(ctx, query, nestedAggs, objectAggs)
+}
+
+func (*SpanReader) buildTagPrefixQuery(field string, prefix string) elastic.Query {
+	if prefix == "" {
+		return elastic.NewMatchAllQuery()
+	}
+	return elastic.NewPrefixQuery(field, prefix)
+}
+
+func (*SpanReader) buildTagTermsAggregation(filter elastic.Query, field string, query *spanstore.TagQueryParameters) *elastic.FilterAggregation {
+	return elastic.NewFilterAggregation().Filter(filter).
+		SubAggregation(tagsTermsAgg, elastic.NewTermsAggregation().Field(field).Size(query.EffectiveLimit()))
+}
+
+// searchTags runs the aggregations of the tags of the spans of the service, the nested aggregations
+// wrapping the filter aggregations of the tags, and returns the selected keys or values.
+func (s *SpanReader) searchTags(ctx context.Context, query *spanstore.TagQueryParameters, nestedAggs, filterAggs []elastic.Aggregation) ([]string, error) {
+	startTimeMax := query.StartTimeMax
+	if startTimeMax.IsZero() {
+		startTimeMax = time.Now()
+	}
+	startTimeMin := query.StartTimeMin
+	if startTimeMin.IsZero() {
+		startTimeMin = startTimeMax.Add(-s.maxSpanAge)
+	}
+	boolQuery := elastic.NewBoolQuery().
+		Must(s.buildStartTimeQuery(startTimeMin, startTimeMax)).
+		Must(s.buildServiceNameQuery(query.ServiceName))
+	jaegerIndices := s.timeRangeIndices(s.tenantIndexPrefix(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, startTimeMin, startTimeMax, s.spanIndexRolloverFrequency)
+
+	search := s.client().Search(jaegerIndices...).
+		Size(0). // set to 0 because we don't want actual documents.
+		IgnoreUnavailable(true).
+		Query(boolQuery)
+	for i, agg := range append(nestedAggs, filterAggs...) {
+		search = search.Aggregation(fmt.Sprintf("%s%d", tagsAgg, i), agg)
+	}
+	searchResult, err := search.Do(ctx)
+	if err != nil {
+		err = es.DetailedError(err)
+		s.logger.Info("es search tags failed", zap.Any("tagQuery", query), zap.Error(err))
+		return nil, fmt.Errorf("search tags failed: %w", err)
+	}
+
+	tags := make(map[string]struct{})
+	if searchResult.Aggregations == nil {
+		return spanstore.SelectTags(tags, query), nil
+	}
+	for i := range nestedAggs {
+		nested, found := searchResult.Aggregations.Nested(fmt.Sprintf("%s%d", tagsAgg, i))
+		if !found {
+			return nil, ErrUnableToFindTagsAggregation
+		}
+		if err := collectTags(nested.Aggregations, tagsFilterAgg, tags); err != nil {
+			return nil, err
+		}
+	}
+	for i := range filterAggs {
+		if err := collectTags(searchResult.Aggregations, fmt.Sprintf("%s%d", tagsAgg, len(nestedAggs)+i), tags); err != nil {
+			return nil, err
+		}
+	}
+	return spanstore.SelectTags(tags, query), nil
+}
+
+// collectTags adds the keys of the buckets of the terms aggregation of a filter aggregation to the tags.
+func collectTags(aggregations elastic.Aggregations, filterAgg string, tags map[string]struct{}) error {
+	filter, found := aggregations.Filter(filterAgg)
+	if !found {
+		return ErrUnableToFindTagsAggregation
+	}
+	terms, found := filter.Terms(tagsTermsAgg)
+	if !found {
+		return ErrUnableToFindTagsAggregation
+	}
+	for _, bucket := range terms.Buckets {
+		if tag, ok := bucket.Key.(string); ok {
+			tags[tag] = struct{}{}
+		}
+	}
+	return nil
+}
+
 // FindTraceIDs retrieves traces IDs that match the traceQuery
 func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
 	ctx, span := s.tracer.Start(ctx, "FindTraceIDs")
@@ -380,7 +778,7 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 
 	// Add an hour in both directions so that traces that straddle two indexes are retrieved.
 	// i.e starts in one and ends in another.
-	indices := s.timeRangeIndices(s.spanIndexPrefix, s.spanIndexDateLayout, startTime.Add(-time.Hour), endTime.Add(time.Hour), s.spanIndexRolloverFrequency)
+	indices := s.timeRangeIndices(s.tenantIndexPrefix(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, startTime.Add(-time.Hour), endTime.Add(time.Hour), s.spanIndexRolloverFrequency)
 	nextTime := model.TimeAsEpochMicroseconds(startTime.Add(-time.Hour))
 	searchAfterTime := make(map[model.TraceID]uint64)
 	totalDocumentsFetched := make(map[model.TraceID]int)
@@ -462,15 +860,17 @@ func buildTraceByIDQuery(traceID model.TraceID) elastic.Query {
 	// https://github.com/jaegertracing/jaeger/pull/1956 added leading zeros to IDs
 	// So we need to also read IDs without leading zeros for compatibility with previously saved data.
 	// TODO remove in newer versions, added in Jaeger 1.16
-	var legacyTraceID string
-	if traceID.High == 0 {
-		legacyTraceID = fmt.Sprintf("%x", traceID.Low)
-	} else {
-		legacyTraceID = fmt.Sprintf("%x%016x", traceID.High, traceID.Low)
-	}
 	return elastic.NewBoolQuery().Should(
 		elastic.NewTermQuery(traceIDField, traceIDStr).Boost(2),
-		elastic.NewTermQuery(traceIDField, legacyTraceID))
+		elastic.NewTermQuery(traceIDField, legacyTraceID(traceID)))
+}
+
+// legacyTraceID returns the trace ID without the leading zeros, as saved before Jaeger 1.16
+func legacyTraceID(traceID model.TraceID) string {
+	if traceID.High == 0 {
+		return fmt.Sprintf("%x", traceID.Low)
+	}
+	return fmt.Sprintf("%x%016x", traceID.High, traceID.Low)
 }
 
 func convertTraceIDsStringsToModels(traceIDs []string) ([]model.TraceID, error) {
@@ -572,7 +972,7 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	//  }
 	aggregation := s.buildTraceIDAggregation(traceQuery.NumTraces)
 	boolQuery := s.buildFindTraceIDsQuery(traceQuery)
-	jaegerIndices := s.timeRangeIndices(s.spanIndexPrefix, s.spanIndexDateLayout, traceQuery.StartTimeMin, traceQuery.StartTimeMax, s.spanIndexRolloverFrequency)
+	jaegerIndices := s.timeRangeIndices(s.tenantIndexPrefix(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, traceQuery.StartTimeMin, traceQuery.StartTimeMax, s.spanIndexRolloverFrequency)
 
 	searchService := s.client().Search(jaegerIndices...).
 		Size(0). // set to 0 because we don't want actual documents.
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,6 +39,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -340,6 +342,38 @@ func TestSpanReader_GetTrace(t *testing.T) {
 	})
 }
 
+func TestSpanReader_GetTraceInTimeRange(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		r.reader.spanIndexDateLayout = "2006-01-02"
+		r.reader.spanIndexRolloverFrequency = -24 * time.Hour
+		hits := []*elastic.SearchHit{{Source: (*json.RawMessage)(&exampleESSpan)}}
+		multiSearchService := &mocks.MultiSearchService{}
+		multiSearchService.On("Add", mock.Anything).Return(multiSearchService)
+		// only the index of the day of the time range is searched
+		multiSearchService.On("Index", spanIndex+"2019-10-10").Return(multiSearchService)
+		multiSearchService.On("Do", mock.Anything).Return(&elastic.MultiSearchResult{
+			Responses: []*elastic.SearchResult{{Hits: &elastic.SearchHits{Hits: hits}}},
+		}, nil).Once()
+		multiSearchService.On("Do", mock.Anything).Return(&elastic.MultiSearchResult{
+			Responses: []*elastic.SearchResult{{Hits: &elastic.SearchHits{}}},
+		}, nil).Once()
+		r.client.On("MultiSearch").Return(multiSearchService)
+
+		query := spanstore.GetTraceParameters{
+			TraceID:   model.NewTraceID(0, 1),
+			StartTime: time.Date(2019, 10, 10, 5, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2019, 10, 10, 6, 0, 0, 0, time.UTC),
+		}
+		trace, err := r.reader.GetTraceInTimeRange(context.Background(), query)
+		require.NoError(t, err)
+		require.NotEmpty(t, r.traceBuffer.GetSpans(), "Spans recorded")
+		require.Len(t, trace.Spans, 1)
+
+		_, err = r.reader.GetTraceInTimeRange(context.Background(), query)
+		require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+	})
+}
+
 func TestSpanReader_multiRead_followUp_query(t *testing.T) {
 	withSpanReader(t, func(r *spanReaderTest) {
 		date := time.Date(2019, 10, 10, 5, 0, 0, 0, time.UTC)
@@ -1019,6 +1053,367 @@ func mockSearchService(r *spanReaderTest) *mock.Call {
 	return searchService.On("Do", mock.Anything)
 }
 
+func TestSpanReader_GetLatencyDistribution(t *testing.T) {
+	aggregations := map[string]*json.RawMessage{}
+	percentilesRaw := []byte(`{"values": {"50.0": 1000, "95.0": 5000, "99.0": 9000}}`)
+	aggregations[durationPercentilesAgg] = (*json.RawMessage)(&percentilesRaw)
+	histogramRaw := []byte(`{"buckets": [{"to": 1000, "doc_count": 3}, {"from": 1000, "to": 10000, "doc_count": 5}, {"from": 10000, "doc_count": 1}]}`)
+	aggregations[durationHistogramAgg] = (*json.RawMessage)(&histogramRaw)
+
+	testCases := []struct {
+		caption       string
+		searchResult  *elastic.SearchResult
+		searchError   error
+		expectedError string
+		expected      *spanstore.LatencyDistribution
+	}{
+		{
+			caption:      "full behavior",
+			searchResult: &elastic.SearchResult{Aggregations: elastic.Aggregations(aggregations)},
+			expected: &spanstore.LatencyDistribution{
+				Count: 9,
+				Buckets: []spanstore.DurationBucket{
+					{From: 0, To: time.Millisecond, Count: 3},
+					{From: time.Millisecond, To: 10 * time.Millisecond, Count: 5},
+					{From: 10 * time.Millisecond, Count: 1},
+				},
+				P50: time.Millisecond,
+				P95: 5 * time.Millisecond,
+				P99: 9 * time.Millisecond,
+			},
+		},
+		{
+			caption:      "no aggregations",
+			searchResult: &elastic.SearchResult{},
+			expected: &spanstore.LatencyDistribution{
+				Buckets: spanstore.NewDurationBuckets([]time.Duration{time.Millisecond, 10 * time.Millisecond}),
+			},
+		},
+		{
+			caption:       "missing aggregation",
+			searchResult:  &elastic.SearchResult{Aggregations: elastic.Aggregations{}},
+			expectedError: ErrUnableToFindDurationAggregation.Error(),
+		},
+		{
+			caption:       "search error",
+			searchError:   errors.New("Search failure"),
+			expectedError: "search durations failed: Search failure",
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				searchService := &mocks.SearchService{}
+				searchService.On("Query", mock.Anything).Return(searchService)
+				searchService.On("IgnoreUnavailable", true).Return(searchService)
+				searchService.On("Size", 0).Return(searchService)
+				searchService.On("Aggregation", durationPercentilesAgg, mock.AnythingOfType("*elastic.PercentilesAggregation")).Return(searchService)
+				searchService.On("Aggregation", durationHistogramAgg, mock.AnythingOfType("*elastic.RangeAggregation")).Return(searchService)
+				searchService.On("Do", mock.Anything).Return(testCase.searchResult, testCase.searchError)
+				r.client.On("Search", mock.AnythingOfType("string")).Return(searchService)
+
+				startTime := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+				dist, err := r.reader.GetLatencyDistribution(context.Background(), &spanstore.LatencyQueryParameters{
+					ServiceName:   "svc",
+					OperationName: "op",
+					StartTimeMin:  startTime,
+					StartTimeMax:  startTime.Add(time.Hour),
+					BucketBounds:  []time.Duration{time.Millisecond, 10 * time.Millisecond},
+				})
+				if testCase.expectedError != "" {
+					require.EqualError(t, err, testCase.expectedError)
+					assert.Nil(t, dist)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, testCase.expected, dist)
+				}
+			})
+		})
+	}
+}
+
+func TestSpanReader_GetTagKeys(t *testing.T) {
+	tagsRaw := func(keys ...string) *json.RawMessage {
+		buckets := make([]string, len(keys))
+		for i, key := range keys {
+			buckets[i] = fmt.Sprintf(`{"key": %q, "doc_count": 1}`, key)
+		}
+		raw := []byte(fmt.Sprintf(`{"tagsFilter": {"tagsTerms": {"buckets": [%s]}}}`, strings.Join(buckets, ",")))
+		return (*json.RawMessage)(&raw)
+	}
+	aggregations := elastic.Aggregations{
+		"tags0": tagsRaw("http.method", "http.url"),
+		"tags1": tagsRaw("hostname", "http.method"),
+		"tags2": tagsRaw("event"),
+	}
+	emptyRaw := []byte(`{}`)
+
+	testCases := []struct {
+		caption       string
+		searchResult  *elastic.SearchResult
+		searchError   error
+		expectedError string
+		expected      []string
+	}{
+		{
+			caption:      "full behavior",
+			searchResult: &elastic.SearchResult{Aggregations: aggregations},
+			expected:     []string{"http.method", "http.url"},
+		},
+		{
+			caption:      "no aggregations",
+			searchResult: &elastic.SearchResult{},
+			expected:     []string{},
+		},
+		{
+			caption:       "missing aggregation",
+			searchResult:  &elastic.SearchResult{Aggregations: elastic.Aggregations{}},
+			expectedError: ErrUnableToFindTagsAggregation.Error(),
+		},
+		{
+			caption: "missing filter aggregation",
+			searchResult: &elastic.SearchResult{Aggregations: elastic.Aggregations{
+				"tags0": (*json.RawMessage)(&emptyRaw),
+			}},
+			expectedError: ErrUnableToFindTagsAggregation.Error(),
+		},
+		{
+			caption:       "search error",
+			searchError:   errors.New("Search failure"),
+			expectedError: "search tags failed: Search failure",
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				searchService := &mocks.SearchService{}
+				searchService.On("Query", mock.Anything).Return(searchService)
+				searchService.On("IgnoreUnavailable", true).Return(searchService)
+				searchService.On("Size", 0).Return(searchService)
+				searchService.On("Aggregation", mock.AnythingOfType("string"), mock.AnythingOfType("*elastic.NestedAggregation")).Return(searchService)
+				searchService.On("Do", mock.Anything).Return(testCase.searchResult, testCase.searchError)
+				r.client.On("Search", mock.AnythingOfType("string")).Return(searchService)
+
+				startTime := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+				keys, err := r.reader.GetTagKeys(context.Background(), &spanstore.TagQueryParameters{
+					ServiceName:  "svc",
+					Prefix:       "http.",
+					StartTimeMin: startTime,
+					StartTimeMax: startTime.Add(time.Hour),
+				})
+				if testCase.expectedError != "" {
+					require.EqualError(t, err, testCase.expectedError)
+					assert.Nil(t, keys)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, testCase.expected, keys)
+					searchService.AssertNumberOfCalls(t, "Aggregation", len(nestedTagFieldList))
+				}
+			})
+		})
+	}
+}
+
+func TestSpanReader_GetTagValues(t *testing.T) {
+	nestedRaw := []byte(`{"tagsFilter": {"tagsTerms": {"buckets": [{"key": "GET", "doc_count": 3}]}}}`)
+	objectRaw := []byte(`{"tagsTerms": {"buckets": [{"key": "POST", "doc_count": 1}, {"key": "GET", "doc_count": 1}]}}`)
+	emptyRaw := []byte(`{"tagsFilter": {"tagsTerms": {"buckets": []}}}`)
+	emptyObjectRaw := []byte(`{}`)
+	aggregations := elastic.Aggregations{
+		"tags0": (*json.RawMessage)(&nestedRaw),
+		"tags1": (*json.RawMessage)(&emptyRaw),
+		"tags2": (*json.RawMessage)(&emptyRaw),
+		"tags3": (*json.RawMessage)(&objectRaw),
+		"tags4": (*json.RawMessage)(&objectRaw),
+	}
+
+	testCases := []struct {
+		caption       string
+		aggregations  elastic.Aggregations
+		expectedError string
+		expected      []string
+	}{
+		{
+			caption:      "full behavior",
+			aggregations: aggregations,
+			expected:     []string{"GET", "POST"},
+		},
+		{
+			caption: "missing terms aggregation",
+			aggregations: elastic.Aggregations{
+				"tags0": (*json.RawMessage)(&emptyRaw),
+				"tags1": (*json.RawMessage)(&emptyRaw),
+				"tags2": (*json.RawMessage)(&emptyRaw),
+				"tags3": (*json.RawMessage)(&objectRaw),
+				"tags4": (*json.RawMessage)(&emptyObjectRaw),
+			},
+			expectedError: ErrUnableToFindTagsAggregation.Error(),
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				var nestedAggs, filterAggs []elastic.Aggregation
+				searchService := &mocks.SearchService{}
+				searchService.On("Query", mock.Anything).Return(searchService)
+				searchService.On("IgnoreUnavailable", true).Return(searchService)
+				searchService.On("Size", 0).Return(searchService)
+				searchService.On("Aggregation", mock.AnythingOfType("string"), mock.AnythingOfType("*elastic.NestedAggregation")).
+					Run(func(args mock.Arguments) {
+						nestedAggs = append(nestedAggs, args.Get(1).(elastic.Aggregation))
+					}).Return(searchService)
+				searchService.On("Aggregation", mock.AnythingOfType("string"), mock.AnythingOfType("*elastic.FilterAggregation")).
+					Run(func(args mock.Arguments) {
+						filterAggs = append(filterAggs, args.Get(1).(elastic.Aggregation))
+					}).Return(searchService)
+				searchService.On("Do", mock.Anything).Return(&elastic.SearchResult{Aggregations: testCase.aggregations}, nil)
+				r.client.On("Search", mock.AnythingOfType("string")).Return(searchService)
+
+				values, err := r.reader.GetTagValues(context.Background(), &spanstore.TagQueryParameters{
+					ServiceName: "svc",
+					Key:         "http.method",
+				})
+				if testCase.expectedError != "" {
+					require.EqualError(t, err, testCase.expectedError)
+					assert.Nil(t, values)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expected, values)
+				require.Len(t, nestedAggs, len(nestedTagFieldList))
+				require.Len(t, filterAggs, len(objectTagFieldList))
+
+				source, err := filterAggs[0].Source()
+				require.NoError(t, err)
+				js, err := json.Marshal(source)
+				require.NoError(t, err)
+				assert.Contains(t, string(js), `"field":"tag.http@method"`)
+				assert.Contains(t, string(js), `"match_all":{}`)
+			})
+		})
+	}
+}
+
+func TestSpanReader_GetREDMetrics(t *testing.T) {
+	startTime := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	stepsRaw := `{"buckets": [{"key": %d, "doc_count": 4, "redErrors": {"doc_count": 1}, "redLatency": {"values": {"95.0": 2000}}}]}`
+	servicesRaw := []byte(fmt.Sprintf(`{"buckets": [
+		{"key": "svc2", "doc_count": 4, "redSteps": `+stepsRaw+`},
+		{"key": "svc1", "doc_count": 4, "redSteps": `+stepsRaw+`}
+	]}`, startTime.UnixMilli(), startTime.UnixMilli()))
+	operationsRaw := []byte(fmt.Sprintf(`{"buckets": [
+		{"key": "svc1", "doc_count": 4, "redOperations": {"buckets": [{"key": "op", "doc_count": 4, "redSteps": `+stepsRaw+`}]}}
+	]}`, startTime.UnixMilli()))
+	expectedPoints := []spanstore.REDPoint{
+		{Timestamp: startTime.Add(time.Minute), Calls: 4, Errors: 1, Latency: 2 * time.Millisecond},
+	}
+
+	testCases := []struct {
+		caption          string
+		groupByOperation bool
+		searchResult     *elastic.SearchResult
+		searchError      error
+		expectedError    string
+		expected         []spanstore.REDSeries
+	}{
+		{
+			caption:      "by service",
+			searchResult: &elastic.SearchResult{Aggregations: elastic.Aggregations{redServicesAgg: (*json.RawMessage)(&servicesRaw)}},
+			expected: []spanstore.REDSeries{
+				{ServiceName: "svc1", Points: expectedPoints},
+				{ServiceName: "svc2", Points: expectedPoints},
+			},
+		},
+		{
+			caption:          "by operation",
+			groupByOperation: true,
+			searchResult:     &elastic.SearchResult{Aggregations: elastic.Aggregations{redServicesAgg: (*json.RawMessage)(&operationsRaw)}},
+			expected: []spanstore.REDSeries{
+				{ServiceName: "svc1", OperationName: "op", Points: expectedPoints},
+			},
+		},
+		{
+			caption:      "no aggregations",
+			searchResult: &elastic.SearchResult{},
+			expected:     []spanstore.REDSeries{},
+		},
+		{
+			caption:       "missing aggregation",
+			searchResult:  &elastic.SearchResult{Aggregations: elastic.Aggregations{}},
+			expectedError: ErrUnableToFindREDAggregation.Error(),
+		},
+		{
+			caption:          "missing operations aggregation",
+			groupByOperation: true,
+			searchResult:     &elastic.SearchResult{Aggregations: elastic.Aggregations{redServicesAgg: (*json.RawMessage)(&servicesRaw)}},
+			expectedError:    ErrUnableToFindREDAggregation.Error(),
+		},
+		{
+			caption:       "search error",
+			searchError:   errors.New("Search failure"),
+			expectedError: "search RED metrics failed: Search failure",
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanReader(t, func(r *spanReaderTest) {
+				searchService := &mocks.SearchService{}
+				searchService.On("Query", mock.Anything).Return(searchService)
+				searchService.On("IgnoreUnavailable", true).Return(searchService)
+				searchService.On("Size", 0).Return(searchService)
+				searchService.On("Aggregation", redServicesAgg, mock.AnythingOfType("*elastic.TermsAggregation")).Return(searchService)
+				searchService.On("Do", mock.Anything).Return(testCase.searchResult, testCase.searchError)
+				r.client.On("Search", mock.AnythingOfType("string")).Return(searchService)
+
+				series, err := r.reader.GetREDMetrics(context.Background(), &spanstore.REDQueryParameters{
+					ServiceNames:     []string{"svc1", "svc2"},
+					GroupByOperation: testCase.groupByOperation,
+					SpanKinds:        []string{"server"},
+					StartTime:        startTime,
+					EndTime:          startTime.Add(time.Hour),
+					Step:             time.Minute,
+					Quantile:         0.95,
+				})
+				if testCase.expectedError != "" {
+					require.EqualError(t, err, testCase.expectedError)
+					assert.Nil(t, series)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, testCase.expected, series)
+				}
+			})
+		})
+	}
+}
+
+func TestSpanReader_GetREDMetricsWithoutServices(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		series, err := r.reader.GetREDMetrics(context.Background(), &spanstore.REDQueryParameters{Step: time.Minute})
+		require.NoError(t, err)
+		assert.Empty(t, series)
+	})
+}
+
+func TestSpanReader_BuildREDStepsAggregation(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		agg := r.reader.buildREDStepsAggregation(&spanstore.REDQueryParameters{
+			StartTime: time.UnixMilli(90_500),
+			Step:      time.Minute,
+			Quantile:  0.5,
+		})
+		source, err := agg.Source()
+		require.NoError(t, err)
+		histogram := source.(map[string]any)["date_histogram"].(map[string]any)
+		assert.Equal(t, "60000ms", histogram["interval"])
+		assert.Equal(t, "30500ms", histogram["offset"])
+		assert.Equal(t, startTimeMillisField, histogram["field"])
+	})
+}
+
 func TestTraceQueryParameterValidation(t *testing.T) {
 	var malformedtqp *spanstore.TraceQueryParameters
 	err := validateQuery(malformedtqp)
@@ -1292,6 +1687,34 @@ func TestSpanReader_ArchiveTraces_ReadAlias(t *testing.T) {
 	})
 }
 
+func TestSpanReader_ArchiveTracesIndexPerTenant(t *testing.T) {
+	withArchiveSpanReader(t, false, func(r *spanReaderTest) {
+		r.reader.indexPerTenant = true
+		mockSearchService(r).
+			Return(&elastic.SearchResult{}, nil)
+		mockArchiveMultiSearchService(r, "jaeger-span-acme-archive").
+			Return(&elastic.MultiSearchResult{
+				Responses: []*elastic.SearchResult{},
+			}, nil)
+
+		trace, err := r.reader.GetTrace(tenancy.WithTenant(context.Background(), "acme"), model.TraceID{})
+		require.Nil(t, trace)
+		require.EqualError(t, err, "trace not found")
+	})
+}
+
+func TestSpanReaderTenantIndices(t *testing.T) {
+	reader := NewSpanReader(SpanReaderParams{IndexPrefix: "foo", IndexPerTenant: true})
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	assert.Equal(t, "foo-jaeger-span-acme-", reader.tenantIndexPrefix(ctx, reader.spanIndexPrefix))
+	ctx = tenancy.WithTenant(context.Background(), "ACME")
+	assert.Equal(t, "foo-jaeger-span--41434d45-", reader.tenantIndexPrefix(ctx, reader.spanIndexPrefix))
+	assert.Equal(t, "foo-jaeger-service-", reader.tenantIndexPrefix(context.Background(), reader.serviceIndexPrefix))
+
+	reader = NewSpanReader(SpanReaderParams{IndexPrefix: "foo"})
+	assert.Equal(t, "foo-jaeger-span-", reader.tenantIndexPrefix(ctx, reader.spanIndexPrefix))
+}
+
 func TestConvertTraceIDsStringsToModels(t *testing.T) {
 	ids, err := convertTraceIDsStringsToModels([]string{"1", "2", "01", "02", "001", "002"})
 	require.NoError(t, err)
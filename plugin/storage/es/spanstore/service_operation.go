@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"strings"
 	"time"
 
 	"github.com/olivere/elastic"
@@ -28,6 +29,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/cache"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 const (
@@ -70,9 +72,11 @@ func (s *ServiceOperationStorage) Write(indexName string, jsonSpan *dbmodel.Span
 		OperationName: jsonSpan.OperationName,
 	}
 
-	cacheKey := hashCode(service)
+	id := hashCode(service)
+	// the same service and operation are written to the index of each tenant when the indices are split by tenant
+	cacheKey := indexName + ":" + id
 	if !keyInCache(cacheKey, s.serviceCache) {
-		s.client().Index().Index(indexName).Type(serviceType).Id(cacheKey).BodyJson(service).Add()
+		s.client().Index().Index(indexName).Type(serviceType).Id(id).BodyJson(service).Add()
 		writeCache(cacheKey, s.serviceCache)
 	}
 }
@@ -106,9 +110,9 @@ func getServicesAggregation(maxDocCount int) elastic.Query {
 		Size(maxDocCount) // ES deprecated size omission for aggregating all. https://github.com/elastic/elasticsearch/issues/18838
 }
 
-func (s *ServiceOperationStorage) getOperations(context context.Context, indices []string, service string, maxDocCount int) ([]string, error) {
-	serviceQuery := elastic.NewTermQuery(serviceName, service)
-	serviceFilter := getOperationsAggregation(maxDocCount)
+func (s *ServiceOperationStorage) getOperations(context context.Context, indices []string, query spanstore.OperationQueryParameters, maxDocCount int) ([]string, error) {
+	serviceQuery := elastic.NewTermQuery(serviceName, query.ServiceName)
+	serviceFilter := getOperationsAggregation(query, maxDocCount)
 
 	searchService := s.client().Search(indices...).
 		Size(0).
@@ -128,13 +132,43 @@ func (s *ServiceOperationStorage) getOperations(context context.Context, indices
 		return nil, errors.New("could not find aggregation of " + operationsAggregation)
 	}
 	operationNamesBucket := bucket.Buckets
-	return bucketToStringArray(operationNamesBucket)
+	operations, err := bucketToStringArray(operationNamesBucket)
+	if err != nil {
+		return nil, err
+	}
+	// the terms aggregation cannot skip buckets, the offset is applied to the first offset+limit operations
+	return operations[min(max(query.Offset, 0), len(operations)):], nil
 }
 
-func getOperationsAggregation(maxDocCount int) elastic.Query {
-	return elastic.NewTermsAggregation().
+// getOperationsAggregation returns the aggregation of the operation names selected by the name filters
+// of the query. The names are sorted when the query is filtered or paged, so that the pages are stable.
+func getOperationsAggregation(query spanstore.OperationQueryParameters, maxDocCount int) *elastic.TermsAggregation {
+	aggregation := elastic.NewTermsAggregation().
 		Field(operationNameField).
 		Size(maxDocCount) // ES deprecated size omission for aggregating all. https://github.com/elastic/elasticsearch/issues/18838
+	if query.NamePrefix == "" && query.NameContains == "" && query.Offset <= 0 && query.Limit <= 0 {
+		return aggregation
+	}
+	if query.NamePrefix != "" || query.NameContains != "" {
+		// the intersection of the Lucene regular expressions, the prefix and the substring may overlap
+		aggregation.Include(regexpQuote(query.NamePrefix) + ".*&.*" + regexpQuote(query.NameContains) + ".*")
+	}
+	if query.Limit > 0 {
+		aggregation.Size(min(max(query.Offset, 0)+query.Limit, maxDocCount))
+	}
+	return aggregation.OrderByKeyAsc()
+}
+
+// regexpQuote escapes the operators of the Lucene regular expressions in s.
+func regexpQuote(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`.?+*|{}[]()"\#@&<>~`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
 }
 
 func hashCode(s dbmodel.Service) string {
@@ -17,6 +17,7 @@ package spanstore
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/olivere/elastic"
@@ -100,6 +101,85 @@ func TestSpanReader_GetOperations(t *testing.T) {
 	testGet(operationsAggregation, t)
 }
 
+func TestSpanReader_FindOperations(t *testing.T) {
+	withSpanReader(t, func(r *spanReaderTest) {
+		aggregations := map[string]*json.RawMessage{}
+		rawMessage := []byte(`{"buckets": [{"key": "get /a","doc_count": 1},{"key": "get /b","doc_count": 1},{"key": "get /c","doc_count": 1}]}`)
+		aggregations[operationsAggregation] = (*json.RawMessage)(&rawMessage)
+
+		var aggregation *elastic.TermsAggregation
+		searchService := &mocks.SearchService{}
+		searchService.On("Query", mock.Anything).Return(searchService)
+		searchService.On("IgnoreUnavailable", true).Return(searchService)
+		searchService.On("Size", 0).Return(searchService)
+		searchService.On("Aggregation", operationsAggregation, mock.AnythingOfType("*elastic.TermsAggregation")).
+			Run(func(args mock.Arguments) {
+				aggregation = args.Get(1).(*elastic.TermsAggregation)
+			}).
+			Return(searchService)
+		searchService.On("Do", mock.Anything).Return(&elastic.SearchResult{Aggregations: aggregations}, nil)
+		r.client.On("Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(searchService)
+
+		operations, err := r.reader.FindOperations(context.Background(), spanstore.OperationQueryParameters{
+			ServiceName: "service",
+			NamePrefix:  "get",
+			Offset:      1,
+			Limit:       2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{{Name: "get /b"}, {Name: "get /c"}}, operations)
+		require.NotNil(t, aggregation)
+		source, err := aggregation.Source()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"field":   operationNameField,
+			"size":    3,
+			"include": "get.*&.*.*",
+			"order":   []interface{}{map[string]string{"_key": "asc"}},
+		}, source.(map[string]interface{})["terms"])
+	})
+}
+
+func TestGetOperationsAggregation(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    spanstore.OperationQueryParameters
+		expected map[string]interface{}
+	}{
+		{
+			name:     "all operations",
+			query:    spanstore.OperationQueryParameters{ServiceName: "service"},
+			expected: map[string]interface{}{"field": operationNameField, "size": 100},
+		},
+		{
+			name:  "escaped name filters",
+			query: spanstore.OperationQueryParameters{NamePrefix: "GET /a.b", NameContains: "{id}"},
+			expected: map[string]interface{}{
+				"field":   operationNameField,
+				"size":    100,
+				"include": `GET /a\.b.*&.*\{id\}.*`,
+				"order":   []interface{}{map[string]string{"_key": "asc"}},
+			},
+		},
+		{
+			name:  "page beyond the maximum",
+			query: spanstore.OperationQueryParameters{Offset: 90, Limit: 20},
+			expected: map[string]interface{}{
+				"field": operationNameField,
+				"size":  100,
+				"order": []interface{}{map[string]string{"_key": "asc"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source, err := getOperationsAggregation(test.query, 100).Source()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, source.(map[string]interface{})["terms"])
+		})
+	}
+}
+
 func TestSpanReader_GetServicesEmptyIndex(t *testing.T) {
 	withSpanReader(t, func(r *spanReaderTest) {
 		mockSearchService(r).
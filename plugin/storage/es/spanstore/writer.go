@@ -27,7 +27,9 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/cache"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 	storageMetrics "github.com/jaegertracing/jaeger/storage/spanstore/metrics"
 )
 
@@ -53,6 +55,8 @@ type SpanWriter struct {
 	serviceWriter    serviceWriter
 	spanConverter    dbmodel.FromDomain
 	spanServiceIndex spanAndServiceIndexFn
+	retentionIndex   retentionIndexFn
+	indexPerTenant   bool
 }
 
 // SpanWriterParams holds constructor parameters for NewSpanWriter
@@ -70,6 +74,12 @@ type SpanWriterParams struct {
 	UseReadWriteAliases    bool
 	ServiceCacheTTL        time.Duration
 	IndexCacheTTL          time.Duration
+	// RetentionPolicies route the spans of specific services or tenants to the indices of their
+	// retention policy, whose lifecycle is managed by ILM. They require UseReadWriteAliases.
+	RetentionPolicies *spanstore.RetentionPolicies
+	// IndexPerTenant writes the spans of each tenant to its own span and service indices,
+	// e.g. jaeger-span-{tenant}-2024-01-02. The spans without tenant are written to the default indices.
+	IndexPerTenant bool
 }
 
 // NewSpanWriter creates a new SpanWriter for use
@@ -100,10 +110,13 @@ func NewSpanWriter(p SpanWriterParams) *SpanWriter {
 		),
 		spanConverter:    dbmodel.NewFromDomain(p.AllTagsAsFields, p.TagKeysAsFields, p.TagDotReplacement),
 		spanServiceIndex: getSpanAndServiceIndexFn(p.Archive, p.UseReadWriteAliases, p.IndexPrefix, p.SpanIndexDateLayout, p.ServiceIndexDateLayout),
+		retentionIndex:   getRetentionIndexFn(p.Archive, p.UseReadWriteAliases, p.IndexPrefix, p.RetentionPolicies),
+		indexPerTenant:   p.IndexPerTenant,
 	}
 }
 
 // CreateTemplates creates index templates.
+// The index patterns of the templates also match the indices of the tenants when the indices are split by tenant.
 func (s *SpanWriter) CreateTemplates(spanTemplate, serviceTemplate, indexPrefix string) error {
 	if indexPrefix != "" && !strings.HasSuffix(indexPrefix, "-") {
 		indexPrefix += "-"
@@ -119,8 +132,8 @@ func (s *SpanWriter) CreateTemplates(spanTemplate, serviceTemplate, indexPrefix
 	return nil
 }
 
-// spanAndServiceIndexFn returns names of span and service indices
-type spanAndServiceIndexFn func(spanTime time.Time) (string, string)
+// spanAndServiceIndexFn returns names of span and service indices of the tenant, or the default ones if the tenant is empty
+type spanAndServiceIndexFn func(tenant string, spanTime time.Time) (string, string)
 
 func getSpanAndServiceIndexFn(archive, useReadWriteAliases bool, prefix, spanDateLayout string, serviceDateLayout string) spanAndServiceIndexFn {
 	if prefix != "" {
@@ -129,27 +142,59 @@ func getSpanAndServiceIndexFn(archive, useReadWriteAliases bool, prefix, spanDat
 	spanIndexPrefix := prefix + spanIndex
 	serviceIndexPrefix := prefix + serviceIndex
 	if archive {
-		return func(date time.Time) (string, string) {
+		return func(tenant string, date time.Time) (string, string) {
 			if useReadWriteAliases {
-				return archiveIndex(spanIndexPrefix, archiveWriteIndexSuffix), ""
+				return archiveIndex(tenantIndexPrefix(spanIndexPrefix, tenant), archiveWriteIndexSuffix), ""
 			}
-			return archiveIndex(spanIndexPrefix, archiveIndexSuffix), ""
+			return archiveIndex(tenantIndexPrefix(spanIndexPrefix, tenant), archiveIndexSuffix), ""
 		}
 	}
 
 	if useReadWriteAliases {
-		return func(spanTime time.Time) (string, string) {
-			return spanIndexPrefix + "write", serviceIndexPrefix + "write"
+		return func(tenant string, spanTime time.Time) (string, string) {
+			return tenantIndexPrefix(spanIndexPrefix, tenant) + "write", tenantIndexPrefix(serviceIndexPrefix, tenant) + "write"
 		}
 	}
-	return func(date time.Time) (string, string) {
-		return indexWithDate(spanIndexPrefix, spanDateLayout, date), indexWithDate(serviceIndexPrefix, serviceDateLayout, date)
+	return func(tenant string, date time.Time) (string, string) {
+		return indexWithDate(tenantIndexPrefix(spanIndexPrefix, tenant), spanDateLayout, date),
+			indexWithDate(tenantIndexPrefix(serviceIndexPrefix, tenant), serviceDateLayout, date)
 	}
 }
 
+// retentionIndexFn returns the name of the span index of the retention policy matching the span,
+// or an empty string if the span must be written to the default span index
+type retentionIndexFn func(ctx context.Context, span *model.Span) string
+
+func getRetentionIndexFn(archive, useReadWriteAliases bool, prefix string, policies *spanstore.RetentionPolicies) retentionIndexFn {
+	if archive || !useReadWriteAliases || policies == nil {
+		return func(context.Context, *model.Span) string {
+			return ""
+		}
+	}
+	return func(ctx context.Context, span *model.Span) string {
+		policy := policies.Match(tenancy.GetTenant(ctx), span.Process.ServiceName)
+		if policy == nil {
+			return ""
+		}
+		return RetentionWriteAlias(prefix, policy)
+	}
+}
+
+// RetentionWriteAlias returns the write alias of the span indices of the retention policy, created by es-rollover init
+func RetentionWriteAlias(prefix string, policy *spanstore.RetentionPolicy) string {
+	return indexNames(prefix, spanIndex) + policy.Name + "-write"
+}
+
 // WriteSpan writes a span and its corresponding service:operation in ElasticSearch
-func (s *SpanWriter) WriteSpan(_ context.Context, span *model.Span) error {
-	spanIndexName, serviceIndexName := s.spanServiceIndex(span.StartTime)
+func (s *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	var tenant string
+	if s.indexPerTenant {
+		tenant = tenancy.GetTenant(ctx)
+	}
+	spanIndexName, serviceIndexName := s.spanServiceIndex(tenant, span.StartTime)
+	if retentionIndexName := s.retentionIndex(ctx, span); retentionIndexName != "" {
+		spanIndexName = retentionIndexName
+	}
 	jsonSpan := s.spanConverter.FromDomainEmbedProcess(span)
 	if serviceIndexName != "" {
 		s.writeService(serviceIndexName, jsonSpan)
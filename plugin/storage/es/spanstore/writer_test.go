@@ -31,6 +31,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -124,11 +125,111 @@ func TestSpanWriterIndices(t *testing.T) {
 	}
 	for _, testCase := range testCases {
 		w := NewSpanWriter(testCase.params)
-		spanIndexName, serviceIndexName := w.spanServiceIndex(date)
+		spanIndexName, serviceIndexName := w.spanServiceIndex("", date)
 		assert.Equal(t, []string{spanIndexName, serviceIndexName}, testCase.indices)
 	}
 }
 
+func TestSpanWriterTenantIndices(t *testing.T) {
+	date, err := time.Parse(time.RFC3339, "1995-04-21T22:08:41+00:00")
+	require.NoError(t, err)
+	testCases := []struct {
+		params  SpanWriterParams
+		indices []string
+	}{
+		{
+			params:  SpanWriterParams{SpanIndexDateLayout: "2006-01-02", ServiceIndexDateLayout: "2006-01-02"},
+			indices: []string{spanIndex + "acme-1995-04-21", serviceIndex + "acme-1995-04-21"},
+		},
+		{
+			params:  SpanWriterParams{IndexPrefix: "foo", SpanIndexDateLayout: "2006-01-02", ServiceIndexDateLayout: "2006-01-02"},
+			indices: []string{"foo-" + spanIndex + "acme-1995-04-21", "foo-" + serviceIndex + "acme-1995-04-21"},
+		},
+		{
+			params:  SpanWriterParams{Archive: true},
+			indices: []string{spanIndex + "acme-" + archiveIndexSuffix, ""},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase.params.MetricsFactory = metricstest.NewFactory(0)
+		w := NewSpanWriter(testCase.params)
+		spanIndexName, serviceIndexName := w.spanServiceIndex("acme", date)
+		assert.Equal(t, testCase.indices, []string{spanIndexName, serviceIndexName})
+	}
+}
+
+func TestSpanWriter_WriteSpanIndexPerTenant(t *testing.T) {
+	client := &mocks.Client{}
+	indexService := &mocks.IndexService{}
+	for _, index := range []string{"jaeger-span-acme-1995-04-21", "jaeger-service-acme-1995-04-21", "jaeger-service-globex-1995-04-21"} {
+		indexService.On("Index", stringMatcher(index)).Return(indexService).Once()
+	}
+	indexService.On("Type", mock.AnythingOfType("string")).Return(indexService)
+	indexService.On("Id", mock.AnythingOfType("string")).Return(indexService)
+	indexService.On("BodyJson", mock.Anything).Return(indexService)
+	indexService.On("Add")
+	client.On("Index").Return(indexService)
+	w := NewSpanWriter(SpanWriterParams{
+		Client:                 func() es.Client { return client },
+		Logger:                 zap.NewNop(),
+		MetricsFactory:         metricstest.NewFactory(0),
+		SpanIndexDateLayout:    "2006-01-02",
+		ServiceIndexDateLayout: "2006-01-02",
+		IndexPerTenant:         true,
+	})
+
+	date, err := time.Parse(time.RFC3339, "1995-04-21T22:08:41+00:00")
+	require.NoError(t, err)
+	span := &model.Span{OperationName: "operation", Process: &model.Process{ServiceName: "service"}, StartTime: date}
+	require.NoError(t, w.WriteSpan(tenancy.WithTenant(context.Background(), "acme"), span))
+	// the service of the span is written again to the service index of another tenant
+	indexService.On("Index", stringMatcher("jaeger-span-globex-1995-04-21")).Return(indexService).Once()
+	require.NoError(t, w.WriteSpan(tenancy.WithTenant(context.Background(), "globex"), span))
+	indexService.AssertExpectations(t)
+	indexService.AssertNumberOfCalls(t, "Add", 4)
+}
+
+func TestSpanWriterRetentionIndices(t *testing.T) {
+	policies := spanstore.NewRetentionPolicies([]spanstore.RetentionPolicy{
+		{Name: "payments", TTL: time.Hour, Services: []string{"payments"}},
+	})
+	payments := &model.Span{Process: &model.Process{ServiceName: "payments"}}
+	frontend := &model.Span{Process: &model.Process{ServiceName: "frontend"}}
+	testCases := []struct {
+		params  SpanWriterParams
+		indices []string
+	}{
+		{
+			params:  SpanWriterParams{IndexPrefix: "foo", UseReadWriteAliases: true, RetentionPolicies: policies},
+			indices: []string{"foo-" + spanIndex + "payments-write", ""},
+		},
+		{
+			params:  SpanWriterParams{UseReadWriteAliases: true, RetentionPolicies: policies},
+			indices: []string{spanIndex + "payments-write", ""},
+		},
+		{
+			params:  SpanWriterParams{RetentionPolicies: policies},
+			indices: []string{"", ""},
+		},
+		{
+			params:  SpanWriterParams{UseReadWriteAliases: true, Archive: true, RetentionPolicies: policies},
+			indices: []string{"", ""},
+		},
+		{
+			params:  SpanWriterParams{UseReadWriteAliases: true},
+			indices: []string{"", ""},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase.params.MetricsFactory = metricstest.NewFactory(0)
+		w := NewSpanWriter(testCase.params)
+		assert.Equal(t, testCase.indices, []string{
+			w.retentionIndex(context.Background(), payments),
+			w.retentionIndex(context.Background(), frontend),
+		})
+	}
+}
+
 func TestClientClose(t *testing.T) {
 	withSpanWriter(func(w *spanWriterTest) {
 		w.client.On("Close").Return(nil)
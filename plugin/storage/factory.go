@@ -16,14 +16,18 @@
 package storage
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"os"
+	"sync"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/fswatcher"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/plugin"
 	"github.com/jaegertracing/jaeger/plugin/storage/badger"
@@ -35,6 +39,7 @@ import (
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -50,7 +55,10 @@ const (
 
 	downsamplingRatio    = "downsampling.ratio"
 	downsamplingHashSalt = "downsampling.hashsalt"
+	downsamplingServices = "downsampling.services-file"
 	spanStorageType      = "span-storage-type"
+	purgeEnabled         = "span-storage.purge.enabled"
+	retentionPolicies    = "span-storage.retention.policies-file"
 
 	// defaultDownsamplingRatio is the default downsampling ratio.
 	defaultDownsamplingRatio = 1.0
@@ -84,18 +92,26 @@ func AllSamplingStorageTypes() []string {
 }
 
 var ( // interface comformance checks
-	_ storage.Factory        = (*Factory)(nil)
-	_ storage.ArchiveFactory = (*Factory)(nil)
-	_ io.Closer              = (*Factory)(nil)
-	_ plugin.Configurable    = (*Factory)(nil)
+	_ storage.Factory                 = (*Factory)(nil)
+	_ storage.ArchiveFactory          = (*Factory)(nil)
+	_ storage.SavedSearchStoreFactory = (*Factory)(nil)
+	_ storage.PurgerFactory           = (*Factory)(nil)
+	_ io.Closer                       = (*Factory)(nil)
+	_ plugin.Configurable             = (*Factory)(nil)
 )
 
 // Factory implements storage.Factory interface as a meta-factory for storage components.
 type Factory struct {
 	FactoryConfig
 	metricsFactory         metrics.Factory
+	logger                 *zap.Logger
 	factories              map[string]storage.Factory
 	downsamplingFlagsAdded bool
+	downsamplingWatcher    *fswatcher.FSWatcher
+	retentionPolicies      *spanstore.RetentionPolicies
+	retentionWatcher       *fswatcher.FSWatcher
+	retentionLock          sync.Mutex
+	retentionValidators    []storage.RetentionPoliciesValidator
 }
 
 // NewFactory creates the meta-factory.
@@ -147,6 +163,7 @@ func (f *Factory) getFactoryOfType(factoryType string) (storage.Factory, error)
 // Initialize implements storage.Factory.
 func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
 	f.metricsFactory = metricsFactory
+	f.logger = logger
 	for _, factory := range f.factories {
 		if err := factory.Initialize(metricsFactory, logger); err != nil {
 			return err
@@ -174,6 +191,9 @@ func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
 		if !ok {
 			return nil, fmt.Errorf("no %s backend registered for span store", storageType)
 		}
+		if err := f.setRetentionPolicies(storageType, factory); err != nil {
+			return nil, err
+		}
 		writer, err := factory.CreateSpanWriter()
 		if err != nil {
 			return nil, err
@@ -187,14 +207,134 @@ func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
 		spanWriter = spanstore.NewCompositeWriter(writers...)
 	}
 	// Turn off DownsamplingWriter entirely if ratio == defaultDownsamplingRatio.
-	if f.DownsamplingRatio == defaultDownsamplingRatio {
+	if f.DownsamplingRatio == defaultDownsamplingRatio && f.DownsamplingServicesFile == "" {
 		return spanWriter, nil
 	}
-	return spanstore.NewDownsamplingWriter(spanWriter, spanstore.DownsamplingOptions{
+	serviceRatios, err := loadServiceDownsamplingRatios(f.DownsamplingServicesFile)
+	if err != nil {
+		return nil, err
+	}
+	downsamplingWriter := spanstore.NewDownsamplingWriter(spanWriter, spanstore.DownsamplingOptions{
 		Ratio:          f.DownsamplingRatio,
 		HashSalt:       f.DownsamplingHashSalt,
+		ServiceRatios:  serviceRatios,
 		MetricsFactory: f.metricsFactory.Namespace(metrics.NSOptions{Name: "downsampling_writer"}),
-	}), nil
+	})
+	if f.DownsamplingServicesFile != "" {
+		if err := f.watchServiceDownsamplingRatios(downsamplingWriter); err != nil {
+			return nil, err
+		}
+	}
+	return downsamplingWriter, nil
+}
+
+// watchServiceDownsamplingRatios reloads the downsampling ratios of the services when their file changes.
+// The previous ratios are kept if the file cannot be loaded.
+func (f *Factory) watchServiceDownsamplingRatios(writer *spanstore.DownsamplingWriter) error {
+	logger := f.logger.With(zap.String("path", f.DownsamplingServicesFile))
+	watcher, err := fswatcher.New([]string{f.DownsamplingServicesFile}, func() {
+		serviceRatios, err := loadServiceDownsamplingRatios(f.DownsamplingServicesFile)
+		if err != nil {
+			logger.Error("Failed to reload the downsampling ratios", zap.Error(err))
+			return
+		}
+		writer.SetServiceRatios(serviceRatios)
+		logger.Info("Reloaded the downsampling ratios")
+	}, logger)
+	if err != nil {
+		return fmt.Errorf("failed to watch the downsampling ratios: %w", err)
+	}
+	f.downsamplingWatcher = watcher
+	return nil
+}
+
+func loadServiceDownsamplingRatios(path string) (map[string]spanstore.ServiceDownsamplingRatios, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the downsampling ratios: %w", err)
+	}
+	return spanstore.ParseServiceDownsamplingRatios(data)
+}
+
+// setRetentionPolicies passes the retention policies to the backend creating the span writer.
+// The policies are loaded and watched once, and shared by all the backends.
+func (f *Factory) setRetentionPolicies(storageType string, factory storage.Factory) error {
+	if f.RetentionPoliciesFile == "" {
+		return nil
+	}
+	rf, ok := factory.(storage.RetentionFactory)
+	if !ok {
+		f.logger.Warn("The span storage does not support retention policies, the spans are stored with its default TTL",
+			zap.String("type", storageType))
+		return nil
+	}
+	if f.retentionPolicies == nil {
+		policies, err := loadRetentionPolicies(f.RetentionPoliciesFile)
+		if err != nil {
+			return err
+		}
+		f.retentionPolicies = spanstore.NewRetentionPolicies(policies)
+		if err := f.watchRetentionPolicies(); err != nil {
+			return err
+		}
+	}
+	if validator, ok := factory.(storage.RetentionPoliciesValidator); ok {
+		f.retentionLock.Lock()
+		defer f.retentionLock.Unlock()
+		if err := validator.ValidateRetentionPolicies(context.Background(), f.retentionPolicies.Policies()); err != nil {
+			return fmt.Errorf("invalid retention policies for %s: %w", storageType, err)
+		}
+		f.retentionValidators = append(f.retentionValidators, validator)
+	}
+	rf.SetRetentionPolicies(f.retentionPolicies)
+	return nil
+}
+
+// validateRetentionPolicies checks that the backends using the retention policies can store their spans.
+func (f *Factory) validateRetentionPolicies(policies []spanstore.RetentionPolicy) error {
+	f.retentionLock.Lock()
+	defer f.retentionLock.Unlock()
+	for _, validator := range f.retentionValidators {
+		if err := validator.ValidateRetentionPolicies(context.Background(), policies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchRetentionPolicies reloads the retention policies when their file changes.
+// The previous policies are kept if the file cannot be loaded, or if a backend
+// cannot store the spans of the new policies yet.
+func (f *Factory) watchRetentionPolicies() error {
+	logger := f.logger.With(zap.String("path", f.RetentionPoliciesFile))
+	watcher, err := fswatcher.New([]string{f.RetentionPoliciesFile}, func() {
+		policies, err := loadRetentionPolicies(f.RetentionPoliciesFile)
+		if err == nil {
+			err = f.validateRetentionPolicies(policies)
+		}
+		if err != nil {
+			logger.Error("Failed to reload the retention policies", zap.Error(err))
+			return
+		}
+		f.retentionPolicies.SetPolicies(policies)
+		logger.Info("Reloaded the retention policies")
+	}, logger)
+	if err != nil {
+		return fmt.Errorf("failed to watch the retention policies: %w", err)
+	}
+	f.retentionWatcher = watcher
+	return nil
+}
+
+func loadRetentionPolicies(path string) ([]spanstore.RetentionPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the retention policies: %w", err)
+	}
+	return spanstore.ParseRetentionPolicies(data)
 }
 
 // CreateSamplingStoreFactory creates a distributedlock.Lock and samplingstore.Store for use with adaptive sampling
@@ -234,6 +374,19 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return factory.CreateDependencyReader()
 }
 
+// CreateDependencyWriter implements storage.DependencyWriterFactory
+func (f *Factory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	factory, ok := f.factories[f.DependenciesStorageType]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered for span store", f.DependenciesStorageType)
+	}
+	dwf, ok := factory.(storage.DependencyWriterFactory)
+	if !ok {
+		return nil, storage.ErrDependencyWriterNotSupported
+	}
+	return dwf.CreateDependencyWriter()
+}
+
 // AddFlags implements plugin.Configurable
 func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 	for _, factory := range f.factories {
@@ -241,6 +394,18 @@ func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 			conf.AddFlags(flagSet)
 		}
 	}
+	flagSet.Bool(
+		purgeEnabled,
+		false,
+		"Allow the traces to be permanently deleted from the span storage with the purge API, e.g. to isolate the integration tests or to delete the traces of a user.",
+	)
+	flagSet.String(
+		retentionPolicies,
+		"",
+		"The path to a JSON file with the retention policies of specific services or tenants overriding the default TTL of the span storage, "+
+			"e.g. {\"policies\": [{\"name\": \"payments\", \"ttl\": \"2160h\", \"services\": [\"payments\"]}]}. The file is reloaded when it changes, "+
+			"unless the storage is not prepared for the new policies, e.g. their Elasticsearch write aliases are not created yet.",
+	)
 }
 
 // AddPipelineFlags adds all the standard flags as well as the downsampling
@@ -264,6 +429,12 @@ func (f *Factory) addDownsamplingFlags(flagSet *flag.FlagSet) {
 		defaultDownsamplingHashSalt,
 		"Salt used when hashing trace id for downsampling.",
 	)
+	flagSet.String(
+		downsamplingServices,
+		"",
+		"The path to a JSON file with the downsampling ratios of specific services and operations overriding the default ratio, "+
+			"e.g. {\"frontend\": {\"ratio\": 0.1, \"operations\": {\"GET /health\": 0.01}}}. The file is reloaded when it changes.",
+	)
 }
 
 // InitFromViper implements plugin.Configurable
@@ -274,6 +445,8 @@ func (f *Factory) InitFromViper(v *viper.Viper, logger *zap.Logger) {
 		}
 	}
 	f.initDownsamplingFromViper(v)
+	f.FactoryConfig.PurgeEnabled = v.GetBool(purgeEnabled)
+	f.FactoryConfig.RetentionPoliciesFile = v.GetString(retentionPolicies)
 }
 
 func (f *Factory) initDownsamplingFromViper(v *viper.Viper) {
@@ -291,6 +464,7 @@ func (f *Factory) initDownsamplingFromViper(v *viper.Viper) {
 		f.FactoryConfig.DownsamplingRatio = 1.0
 	}
 	f.FactoryConfig.DownsamplingHashSalt = v.GetString(downsamplingHashSalt)
+	f.FactoryConfig.DownsamplingServicesFile = v.GetString(downsamplingServices)
 }
 
 // CreateArchiveSpanReader implements storage.ArchiveFactory
@@ -319,11 +493,46 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 	return archive.CreateArchiveSpanWriter()
 }
 
+// CreateSavedSearchStore implements storage.SavedSearchStoreFactory
+func (f *Factory) CreateSavedSearchStore() (savedsearchstore.Store, error) {
+	factory, ok := f.factories[f.SpanReaderType]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered for span store", f.SpanReaderType)
+	}
+	ss, ok := factory.(storage.SavedSearchStoreFactory)
+	if !ok {
+		return nil, storage.ErrSavedSearchStorageNotSupported
+	}
+	return ss.CreateSavedSearchStore()
+}
+
+// CreatePurger implements storage.PurgerFactory. The purges must be enabled explicitly.
+func (f *Factory) CreatePurger() (spanstore.Purger, error) {
+	if !f.PurgeEnabled {
+		return nil, storage.ErrPurgerNotEnabled
+	}
+	factory, ok := f.factories[f.SpanReaderType]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered for span store", f.SpanReaderType)
+	}
+	pf, ok := factory.(storage.PurgerFactory)
+	if !ok {
+		return nil, storage.ErrPurgerNotSupported
+	}
+	return pf.CreatePurger()
+}
+
 var _ io.Closer = (*Factory)(nil)
 
 // Close closes the resources held by the factory
 func (f *Factory) Close() error {
 	var errs []error
+	if f.downsamplingWatcher != nil {
+		errs = append(errs, f.downsamplingWatcher.Close())
+	}
+	if f.retentionWatcher != nil {
+		errs = append(errs, f.retentionWatcher.Close())
+	}
 	for _, storageType := range f.SpanWriterTypes {
 		if factory, ok := f.factories[storageType]; ok {
 			if closer, ok := factory.(io.Closer); ok {
@@ -43,6 +43,12 @@ type FactoryConfig struct {
 	DependenciesStorageType string
 	DownsamplingRatio       float64
 	DownsamplingHashSalt    string
+	// DownsamplingServicesFile is the path to a file with the downsampling ratios of specific services.
+	DownsamplingServicesFile string
+	// PurgeEnabled allows CreatePurger to return a purger deleting the traces from the span storage.
+	PurgeEnabled bool
+	// RetentionPoliciesFile is the path to a file with the retention policies of specific services or tenants.
+	RetentionPoliciesFile string
 }
 
 // FactoryConfigFromEnvAndCLI reads the desired types of storage backends from SPAN_STORAGE_TYPE and
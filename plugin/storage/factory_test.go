@@ -16,10 +16,13 @@
 package storage
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -27,13 +30,16 @@ import (
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	mocklib "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/internal/metrics/fork"
 	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	depStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
@@ -195,6 +201,41 @@ func TestCreateDownsamplingWriter(t *testing.T) {
 	}
 }
 
+func TestCreateDownsamplingWriterWithServicesFile(t *testing.T) {
+	servicesFile := filepath.Join(t.TempDir(), "downsampling.json")
+	require.NoError(t, os.WriteFile(servicesFile, []byte(`{"frontend": {"ratio": 0}}`), 0o600))
+
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+	mock := new(mocks.Factory)
+	f.factories[cassandraStorageType] = mock
+	spanWriter := new(spanStoreMocks.Writer)
+	spanWriter.On("WriteSpan", context.Background(), mocklib.Anything).Return(nil)
+	mock.On("CreateSpanWriter").Return(spanWriter, nil)
+	mock.On("Initialize", metrics.NullFactory, zap.NewNop()).Return(nil)
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+
+	f.DownsamplingServicesFile = "invalid.json"
+	_, err = f.CreateSpanWriter()
+	require.ErrorContains(t, err, "failed to read the downsampling ratios")
+
+	f.DownsamplingServicesFile = servicesFile
+	w, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	span := &model.Span{Process: &model.Process{ServiceName: "frontend"}}
+	require.NoError(t, w.WriteSpan(context.Background(), span))
+	spanWriter.AssertNotCalled(t, "WriteSpan", context.Background(), span)
+
+	// the ratios are reloaded when the file changes
+	require.NoError(t, os.WriteFile(servicesFile, []byte(`{"frontend": {"ratio": 1}}`), 0o600))
+	assert.Eventually(t, func() bool {
+		return w.WriteSpan(context.Background(), span) == nil && len(spanWriter.Calls) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func TestCreateMulti(t *testing.T) {
 	cfg := defaultCfg()
 	cfg.SpanWriterTypes = append(cfg.SpanWriterTypes, elasticsearchStorageType)
@@ -253,6 +294,183 @@ func TestCreateArchive(t *testing.T) {
 	require.EqualError(t, err, "archive-span-writer-error")
 }
 
+func TestCreateSavedSearchStore(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+
+	_, err = f.CreateSavedSearchStore()
+	require.ErrorIs(t, err, storage.ErrSavedSearchStorageNotSupported)
+
+	f, err = NewFactory(FactoryConfig{
+		SpanWriterTypes:         []string{memoryStorageType},
+		SpanReaderType:          memoryStorageType,
+		DependenciesStorageType: memoryStorageType,
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.factories[memoryStorageType].Initialize(metrics.NullFactory, zap.NewNop()))
+	ss, err := f.CreateSavedSearchStore()
+	require.NoError(t, err)
+	assert.NotNil(t, ss)
+
+	delete(f.factories, memoryStorageType)
+	_, err = f.CreateSavedSearchStore()
+	require.EqualError(t, err, "no memory backend registered for span store")
+}
+
+func TestCreatePurger(t *testing.T) {
+	f, err := NewFactory(FactoryConfig{
+		SpanWriterTypes:         []string{memoryStorageType},
+		SpanReaderType:          memoryStorageType,
+		DependenciesStorageType: memoryStorageType,
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.factories[memoryStorageType].Initialize(metrics.NullFactory, zap.NewNop()))
+	_, err = f.CreatePurger()
+	require.ErrorIs(t, err, storage.ErrPurgerNotEnabled)
+
+	v, command := config.Viperize(f.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--span-storage.purge.enabled=true"}))
+	f.InitFromViper(v, zap.NewNop())
+	purger, err := f.CreatePurger()
+	require.NoError(t, err)
+	assert.NotNil(t, purger)
+
+	f.factories[memoryStorageType] = new(mocks.Factory)
+	_, err = f.CreatePurger()
+	require.ErrorIs(t, err, storage.ErrPurgerNotSupported)
+
+	delete(f.factories, memoryStorageType)
+	_, err = f.CreatePurger()
+	require.EqualError(t, err, "no memory backend registered for span store")
+}
+
+type retentionFactory struct {
+	mocks.Factory
+	policies *spanstore.RetentionPolicies
+}
+
+func (f *retentionFactory) SetRetentionPolicies(policies *spanstore.RetentionPolicies) {
+	f.policies = policies
+}
+
+func TestCreateSpanWriterWithRetentionPolicies(t *testing.T) {
+	policiesFile := filepath.Join(t.TempDir(), "retention.json")
+	require.NoError(t, os.WriteFile(policiesFile, []byte(`{"policies": [{"name": "payments", "ttl": "2160h", "services": ["payments"]}]}`), 0o600))
+
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = append(cfg.SpanWriterTypes, memoryStorageType)
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+	rf := &retentionFactory{}
+	f.factories[cassandraStorageType] = rf
+	other := new(mocks.Factory)
+	f.factories[memoryStorageType] = other
+	for _, mock := range []*mocks.Factory{&rf.Factory, other} {
+		mock.On("CreateSpanWriter").Return(new(spanStoreMocks.Writer), nil)
+		mock.On("Initialize", metrics.NullFactory, mocklib.Anything).Return(nil)
+	}
+	logger, logBuf := testutils.NewLogger()
+	require.NoError(t, f.Initialize(metrics.NullFactory, logger))
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+
+	v, command := config.Viperize(f.AddFlags)
+	require.NoError(t, command.ParseFlags([]string{"--span-storage.retention.policies-file=invalid.json"}))
+	f.InitFromViper(v, zap.NewNop())
+	_, err = f.CreateSpanWriter()
+	require.ErrorContains(t, err, "failed to read the retention policies")
+
+	f.RetentionPoliciesFile = policiesFile
+	_, err = f.CreateSpanWriter()
+	require.NoError(t, err)
+	require.NotNil(t, rf.policies)
+	assert.Equal(t, 2160*time.Hour, rf.policies.Match("", "payments").TTL)
+	assert.Contains(t, logBuf.String(), "The span storage does not support retention policies")
+
+	// the policies are reloaded when the file changes
+	require.NoError(t, os.WriteFile(policiesFile, []byte(`{"policies": [{"name": "payments", "ttl": "24h", "services": ["payments"]}]}`), 0o600))
+	assert.Eventually(t, func() bool {
+		return rf.policies.Match("", "payments").TTL == 24*time.Hour
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// validatingRetentionFactory rejects the retention policies with the name of a policy not prepared yet.
+type validatingRetentionFactory struct {
+	retentionFactory
+	unprepared string
+}
+
+func (f *validatingRetentionFactory) ValidateRetentionPolicies(_ context.Context, policies []spanstore.RetentionPolicy) error {
+	for _, policy := range policies {
+		if policy.Name == f.unprepared {
+			return fmt.Errorf("policy %s not prepared", policy.Name)
+		}
+	}
+	return nil
+}
+
+func TestCreateSpanWriterValidatesRetentionPolicies(t *testing.T) {
+	policiesFile := filepath.Join(t.TempDir(), "retention.json")
+	require.NoError(t, os.WriteFile(policiesFile, []byte(`{"policies": [{"name": "audit", "ttl": "2160h"}]}`), 0o600))
+
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+	rf := &validatingRetentionFactory{unprepared: "audit"}
+	f.factories[cassandraStorageType] = rf
+	rf.On("CreateSpanWriter").Return(new(spanStoreMocks.Writer), nil)
+	rf.On("Initialize", metrics.NullFactory, mocklib.Anything).Return(nil)
+	logger, logBuf := testutils.NewLogger()
+	require.NoError(t, f.Initialize(metrics.NullFactory, logger))
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	f.RetentionPoliciesFile = policiesFile
+
+	_, err = f.CreateSpanWriter()
+	require.EqualError(t, err, "invalid retention policies for cassandra: policy audit not prepared")
+
+	rf.unprepared = "payments"
+	_, err = f.CreateSpanWriter()
+	require.NoError(t, err)
+	assert.Equal(t, 2160*time.Hour, rf.policies.Match("", "frontend").TTL)
+
+	// the reloaded policies are rejected if the backend cannot store their spans yet
+	require.NoError(t, os.WriteFile(policiesFile, []byte(`{"policies": [{"name": "payments", "ttl": "24h"}]}`), 0o600))
+	assert.Eventually(t, func() bool {
+		return strings.Contains(logBuf.String(), "Failed to reload the retention policies")
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, logBuf.String(), "policy payments not prepared")
+	assert.Equal(t, 2160*time.Hour, rf.policies.Match("", "frontend").TTL)
+}
+
+type dependencyWriterFactory struct {
+	mocks.Factory
+	writer dependencystore.Writer
+}
+
+func (f *dependencyWriterFactory) CreateDependencyWriter() (dependencystore.Writer, error) {
+	return f.writer, nil
+}
+
+func TestCreateDependencyWriter(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+	writer := &struct{ dependencystore.Writer }{}
+	f.factories[cassandraStorageType] = &dependencyWriterFactory{writer: writer}
+	w, err := f.CreateDependencyWriter()
+	require.NoError(t, err)
+	assert.Equal(t, writer, w)
+
+	f.factories[cassandraStorageType] = &mocks.Factory{}
+	_, err = f.CreateDependencyWriter()
+	require.ErrorIs(t, err, storage.ErrDependencyWriterNotSupported)
+
+	delete(f.factories, cassandraStorageType)
+	_, err = f.CreateDependencyWriter()
+	require.EqualError(t, err, "no cassandra backend registered for span store")
+}
+
 func TestCreateError(t *testing.T) {
 	f, err := NewFactory(defaultCfg())
 	require.NoError(t, err)
@@ -294,7 +512,7 @@ func TestCreateError(t *testing.T) {
 }
 
 func TestAllSamplingStorageTypes(t *testing.T) {
-	assert.Equal(t, []string{"cassandra", "memory", "badger"}, AllSamplingStorageTypes())
+	assert.Equal(t, []string{"cassandra", "opensearch", "elasticsearch", "memory", "badger"}, AllSamplingStorageTypes())
 }
 
 func TestCreateSamplingStoreFactory(t *testing.T) {
@@ -316,14 +534,21 @@ func TestCreateSamplingStoreFactory(t *testing.T) {
 
 	// if an incompatible factory is specified return err
 	cfg := defaultCfg()
-	cfg.SamplingStorageType = "elasticsearch"
+	cfg.SamplingStorageType = "kafka"
 	f, err = NewFactory(cfg)
 	require.NoError(t, err)
 	ssFactory, err = f.CreateSamplingStoreFactory()
 	assert.Nil(t, ssFactory)
-	require.EqualError(t, err, "storage factory of type elasticsearch does not support sampling store")
+	require.EqualError(t, err, "storage factory of type kafka does not support sampling store")
 
 	// if a compatible factory is specified then return it
+	cfg.SamplingStorageType = "elasticsearch"
+	f, err = NewFactory(cfg)
+	require.NoError(t, err)
+	ssFactory, err = f.CreateSamplingStoreFactory()
+	assert.Equal(t, ssFactory, f.factories["elasticsearch"])
+	require.NoError(t, err)
+
 	cfg.SamplingStorageType = "cassandra"
 	f, err = NewFactory(cfg)
 	require.NoError(t, err)
@@ -375,12 +600,14 @@ func TestParsingDownsamplingRatio(t *testing.T) {
 	err := command.ParseFlags([]string{
 		"--downsampling.ratio=1.5",
 		"--downsampling.hashsalt=jaeger",
+		"--downsampling.services-file=downsampling.json",
 	})
 	require.NoError(t, err)
 	f.InitFromViper(v, zap.NewNop())
 
 	assert.Equal(t, 1.0, f.FactoryConfig.DownsamplingRatio)
 	assert.Equal(t, "jaeger", f.FactoryConfig.DownsamplingHashSalt)
+	assert.Equal(t, "downsampling.json", f.FactoryConfig.DownsamplingServicesFile)
 
 	err = command.ParseFlags([]string{
 		"--downsampling.ratio=0.5",
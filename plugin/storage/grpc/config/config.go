@@ -16,6 +16,8 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"os/exec"
 	"time"
@@ -30,6 +32,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
 )
@@ -43,13 +46,19 @@ type Configuration struct {
 	PluginLogLevel          string `yaml:"log-level" mapstructure:"log_level"`
 	RemoteServerAddr        string `yaml:"server" mapstructure:"server"`
 	RemoteTLS               tlscfg.Options
-	RemoteConnectTimeout    time.Duration `yaml:"connection-timeout" mapstructure:"connection-timeout"`
+	RemoteConnectTimeout    time.Duration              `yaml:"connection-timeout" mapstructure:"connection-timeout"`
+	RemoteHedging           HedgingConfig              `yaml:"hedging" mapstructure:"hedging"`
+	RemoteTenantTLS         map[string]TenantTLSConfig `yaml:"tenant-tls" mapstructure:"tenant_tls"`
+	RemoteConnections       int                        `yaml:"connections" mapstructure:"connections"`
+	RemoteOTLPWrites        bool                       `yaml:"otlp-writes" mapstructure:"otlp_writes"`
 	TenancyOpts             tenancy.Options
 
 	pluginHealthCheck     *time.Ticker
 	pluginHealthCheckDone chan bool
 	pluginRPCClient       plugin.ClientProtocol
 	remoteConn            *grpc.ClientConn
+	tenantConns           map[string]*grpc.ClientConn
+	pool                  *connPool
 }
 
 // ClientPluginServices defines services plugin can expose and its capabilities
@@ -68,16 +77,16 @@ func (c *ClientPluginServices) Close() error {
 
 // PluginBuilder is used to create storage plugins. Implemented by Configuration.
 type PluginBuilder interface {
-	Build(logger *zap.Logger, tracerProvider trace.TracerProvider) (*ClientPluginServices, error)
+	Build(logger *zap.Logger, metricsFactory metrics.Factory, tracerProvider trace.TracerProvider) (*ClientPluginServices, error)
 	Close() error
 }
 
 // Build instantiates a PluginServices
-func (c *Configuration) Build(logger *zap.Logger, tracerProvider trace.TracerProvider) (*ClientPluginServices, error) {
+func (c *Configuration) Build(logger *zap.Logger, metricsFactory metrics.Factory, tracerProvider trace.TracerProvider) (*ClientPluginServices, error) {
 	if c.PluginBinary != "" {
 		return c.buildPlugin(logger, tracerProvider)
 	} else {
-		return c.buildRemote(logger, tracerProvider)
+		return c.buildRemote(logger, metricsFactory, tracerProvider)
 	}
 }
 
@@ -89,34 +98,68 @@ func (c *Configuration) Close() error {
 	if c.remoteConn != nil {
 		c.remoteConn.Close()
 	}
+	for _, conn := range c.tenantConns {
+		conn.Close()
+	}
+	if c.pool != nil {
+		c.pool.close()
+	}
 
 	return c.RemoteTLS.Close()
 }
 
-func (c *Configuration) buildRemote(logger *zap.Logger, tracerProvider trace.TracerProvider) (*ClientPluginServices, error) {
-	opts := []grpc.DialOption{
+func (c *Configuration) buildRemote(logger *zap.Logger, metricsFactory metrics.Factory, tracerProvider trace.TracerProvider) (*ClientPluginServices, error) {
+	baseOpts := []grpc.DialOption{
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(tracerProvider))),
 		grpc.WithBlock(),
 	}
+	var tlsCfg *tls.Config
+	transportOpt := grpc.WithTransportCredentials(insecure.NewCredentials())
 	if c.RemoteTLS.Enabled {
-		tlsCfg, err := c.RemoteTLS.Config(logger)
+		var err error
+		tlsCfg, err = c.RemoteTLS.Config(logger)
 		if err != nil {
 			return nil, err
 		}
-		creds := credentials.NewTLS(tlsCfg)
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		transportOpt = grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg))
 	}
+	opts := append(append([]grpc.DialOption{}, baseOpts...), transportOpt)
 
 	ctx, cancel := context.WithTimeout(context.Background(), c.RemoteConnectTimeout)
 	defer cancel()
 
 	tenancyMgr := tenancy.NewManager(&c.TenancyOpts)
+	if len(c.RemoteTenantTLS) > 0 && (!c.RemoteTLS.Enabled || !tenancyMgr.Enabled) {
+		return nil, errors.New("the client certificates of the tenants require TLS and multi-tenancy to be enabled")
+	}
 	if tenancyMgr.Enabled {
 		opts = append(opts, grpc.WithUnaryInterceptor(tenancy.NewClientUnaryInterceptor(tenancyMgr)))
 		opts = append(opts, grpc.WithStreamInterceptor(tenancy.NewClientStreamInterceptor(tenancyMgr)))
 	}
+	if c.RemoteHedging.Delay > 0 {
+		hedger := newHedger(c.RemoteHedging, metricsFactory)
+		opts = append(opts, grpc.WithChainUnaryInterceptor(hedger.unaryInterceptor))
+		opts = append(opts, grpc.WithChainStreamInterceptor(hedger.streamInterceptor))
+	}
+	if len(c.RemoteTenantTLS) > 0 {
+		// the router follows the other interceptors, the tenant connections do not repeat them
+		router, err := c.dialTenants(ctx, tlsCfg, baseOpts)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithChainUnaryInterceptor(router.unaryInterceptor))
+		opts = append(opts, grpc.WithChainStreamInterceptor(router.streamInterceptor))
+	}
+	if c.RemoteConnections > 1 {
+		// the pool is the last interceptor, it only spreads the requests not routed to a tenant connection
+		var err error
+		c.pool, err = c.dialPool(ctx, c.RemoteConnections, append(append([]grpc.DialOption{}, baseOpts...), transportOpt))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithChainUnaryInterceptor(c.pool.unaryInterceptor))
+		opts = append(opts, grpc.WithChainStreamInterceptor(c.pool.streamInterceptor))
+	}
 	var err error
 	// TODO: Need to replace grpc.DialContext with grpc.NewClient and pass test
 	c.remoteConn, err = grpc.DialContext(ctx, c.RemoteServerAddr, opts...)
@@ -130,11 +173,30 @@ func (c *Configuration) buildRemote(logger *zap.Logger, tracerProvider trace.Tra
 			Store:               grpcClient,
 			ArchiveStore:        grpcClient,
 			StreamingSpanWriter: grpcClient,
+			OTLPSpanWriter:      grpcClient,
 		},
 		Capabilities: grpcClient,
 	}, nil
 }
 
+// dialTenants connects to the remote storage with the client certificate of every tenant of RemoteTenantTLS.
+func (c *Configuration) dialTenants(ctx context.Context, tlsCfg *tls.Config, baseOpts []grpc.DialOption) (*tenantRouter, error) {
+	c.tenantConns = make(map[string]*grpc.ClientConn, len(c.RemoteTenantTLS))
+	for tenant, cert := range c.RemoteTenantTLS {
+		creds, err := newTenantCredentials(tenant, cert, tlsCfg)
+		if err != nil {
+			return nil, err
+		}
+		opts := append(append([]grpc.DialOption{}, baseOpts...), grpc.WithTransportCredentials(creds))
+		conn, err := grpc.DialContext(ctx, c.RemoteServerAddr, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to remote storage for the tenant %q: %w", tenant, err)
+		}
+		c.tenantConns[tenant] = conn
+	}
+	return &tenantRouter{conns: c.tenantConns}, nil
+}
+
 func (c *Configuration) buildPlugin(logger *zap.Logger, tracerProvider trace.TracerProvider) (*ClientPluginServices, error) {
 	opts := []grpc.DialOption{
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(tracerProvider))),
@@ -193,6 +255,9 @@ func (c *Configuration) buildPlugin(logger *zap.Logger, tracerProvider trace.Tra
 			raw, shared.StoragePluginIdentifier)
 	}
 
+	// the OTLP span writer is optional for the plugins built with older versions of this package
+	otlpSpanWriterPlugin, _ := raw.(shared.OTLPSpanWriterPlugin)
+
 	if err := c.startPluginHealthCheck(rpcClient, logger); err != nil {
 		return nil, fmt.Errorf("initial plugin health check failed: %w", err)
 	}
@@ -202,6 +267,7 @@ func (c *Configuration) buildPlugin(logger *zap.Logger, tracerProvider trace.Tra
 			Store:               storagePlugin,
 			ArchiveStore:        archiveStoragePlugin,
 			StreamingSpanWriter: streamingSpanWriterPlugin,
+			OTLPSpanWriter:      otlpSpanWriterPlugin,
 		},
 		Capabilities:     capabilities,
 		killPluginClient: client.Kill,
@@ -0,0 +1,257 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// HedgingConfig configures the hedged reads of the remote storage: a read request is sent a
+// second time if the first attempt has not answered after the delay, and the first success is used.
+type HedgingConfig struct {
+	// Delay is the delay before sending the second attempt, zero disables the hedged reads.
+	Delay time.Duration `yaml:"delay" mapstructure:"delay"`
+}
+
+// hedgedMethods are the idempotent read RPCs of the storage API.
+var hedgedMethods = map[string]bool{
+	"/jaeger.storage.v1.SpanReaderPlugin/GetTrace":                true,
+	"/jaeger.storage.v1.SpanReaderPlugin/GetServices":             true,
+	"/jaeger.storage.v1.SpanReaderPlugin/GetOperations":           true,
+	"/jaeger.storage.v1.SpanReaderPlugin/FindOperations":          true,
+	"/jaeger.storage.v1.SpanReaderPlugin/FindTraces":              true,
+	"/jaeger.storage.v1.SpanReaderPlugin/FindTracesPage":          true,
+	"/jaeger.storage.v1.SpanReaderPlugin/FindTraceIDs":            true,
+	"/jaeger.storage.v1.ArchiveSpanReaderPlugin/GetArchiveTrace":  true,
+	"/jaeger.storage.v1.DependenciesReaderPlugin/GetDependencies": true,
+}
+
+type hedgingMetrics struct {
+	// Requests counts the read requests eligible to hedging.
+	Requests metrics.Counter `metric:"requests"`
+	// Hedges counts the second attempts sent after the delay, the hedge rate is hedges/requests.
+	Hedges metrics.Counter `metric:"hedges"`
+	// HedgeWins counts the read requests answered first by the second attempt.
+	HedgeWins metrics.Counter `metric:"hedge_wins"`
+}
+
+type hedger struct {
+	delay   time.Duration
+	metrics hedgingMetrics
+}
+
+func newHedger(cfg HedgingConfig, metricsFactory metrics.Factory) *hedger {
+	h := &hedger{delay: cfg.Delay}
+	metrics.MustInit(&h.metrics, metricsFactory.Namespace(metrics.NSOptions{Name: "hedged_reads"}), nil)
+	return h
+}
+
+// attempt is the outcome of an attempt of a hedged request.
+type attempt struct {
+	reply  interface{}
+	stream grpc.ClientStream
+	cancel context.CancelFunc
+	hedge  bool
+	err    error
+}
+
+// race runs the first attempt, and a second one if the first has not finished after the delay.
+// It returns the first successful attempt, or the last failure, and cancels the other attempt.
+func (h *hedger) race(ctx context.Context, run func(ctx context.Context, hedge bool) attempt) attempt {
+	h.metrics.Requests.Inc(1)
+	// the results of the canceled attempts are dropped in the buffered channel
+	results := make(chan attempt, 2)
+	var cancels []context.CancelFunc
+	start := func(hedge bool) {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		go func() {
+			result := run(attemptCtx, hedge)
+			result.cancel = cancel
+			results <- result
+		}()
+	}
+	start(false)
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+	select {
+	case result := <-results:
+		// the errors are not slow answers, they are not hedged
+		return result
+	case <-timer.C:
+	}
+	h.metrics.Hedges.Inc(1)
+	start(true)
+	result := <-results
+	if result.err != nil {
+		result.cancel()
+		result = <-results
+	}
+	if result.err != nil {
+		return result
+	}
+	if result.hedge {
+		h.metrics.HedgeWins.Inc(1)
+		cancels[0]()
+	} else {
+		cancels[1]()
+	}
+	return result
+}
+
+func (h *hedger) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if !hedgedMethods[method] {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	replyType := reflect.TypeOf(reply).Elem()
+	result := h.race(ctx, func(ctx context.Context, hedge bool) attempt {
+		attemptReply := reflect.New(replyType).Interface()
+		return attempt{reply: attemptReply, hedge: hedge, err: invoker(ctx, method, req, attemptReply, cc, opts...)}
+	})
+	// the unary calls are finished, their contexts are no longer needed
+	result.cancel()
+	if result.err != nil {
+		return result.err
+	}
+	reflect.ValueOf(reply).Elem().Set(reflect.ValueOf(result.reply).Elem())
+	return nil
+}
+
+func (h *hedger) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	if !hedgedMethods[method] || desc.ClientStreams {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	return &hedgedStream{
+		hedger: h,
+		ctx:    ctx,
+		open: func(ctx context.Context) (grpc.ClientStream, error) {
+			return streamer(ctx, desc, cc, method, opts...)
+		},
+	}, nil
+}
+
+// hedgedStream is a server streaming call whose attempts are raced until the first message is received.
+// The request is buffered to be sent by the second attempt, and the winner serves the rest of the stream.
+type hedgedStream struct {
+	hedger *hedger
+	ctx    context.Context
+	open   func(ctx context.Context) (grpc.ClientStream, error)
+
+	request interface{}
+	winner  grpc.ClientStream
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+}
+
+// SendMsg buffers the request, it is sent by the attempts started by the first RecvMsg.
+func (s *hedgedStream) SendMsg(m interface{}) error {
+	if s.request != nil {
+		return errors.New("hedged reads only support a single request message")
+	}
+	s.request = m
+	return nil
+}
+
+// CloseSend is a no-op, the attempts close their send direction after sending the request.
+func (s *hedgedStream) CloseSend() error {
+	return nil
+}
+
+func (s *hedgedStream) RecvMsg(m interface{}) error {
+	winner := s.current()
+	if winner == nil {
+		return s.start(m)
+	}
+	err := winner.RecvMsg(m)
+	if err != nil {
+		s.cancel()
+	}
+	return err
+}
+
+// start races the attempts until the first message or the end of a stream, which is a success as well.
+func (s *hedgedStream) start(m interface{}) error {
+	messageType := reflect.TypeOf(m).Elem()
+	result := s.hedger.race(s.ctx, func(ctx context.Context, hedge bool) attempt {
+		stream, err := s.open(ctx)
+		if err == nil {
+			err = stream.SendMsg(s.request)
+		}
+		if err == nil {
+			err = stream.CloseSend()
+		}
+		if err != nil {
+			return attempt{hedge: hedge, err: err}
+		}
+		message := reflect.New(messageType).Interface()
+		err = stream.RecvMsg(message)
+		if errors.Is(err, io.EOF) {
+			return attempt{stream: stream, hedge: hedge, err: nil}
+		}
+		return attempt{reply: message, stream: stream, hedge: hedge, err: err}
+	})
+	if result.err != nil {
+		result.cancel()
+		return result.err
+	}
+	s.mu.Lock()
+	s.winner, s.cancel = result.stream, result.cancel
+	s.mu.Unlock()
+	if result.reply == nil {
+		s.cancel()
+		return io.EOF
+	}
+	reflect.ValueOf(m).Elem().Set(reflect.ValueOf(result.reply).Elem())
+	return nil
+}
+
+func (s *hedgedStream) current() grpc.ClientStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.winner
+}
+
+// Header returns the header metadata of the winning attempt, or nil before the first message.
+func (s *hedgedStream) Header() (metadata.MD, error) {
+	if winner := s.current(); winner != nil {
+		return winner.Header()
+	}
+	return nil, nil
+}
+
+// Trailer returns the trailer metadata of the winning attempt, or nil before the first message.
+func (s *hedgedStream) Trailer() metadata.MD {
+	if winner := s.current(); winner != nil {
+		return winner.Trailer()
+	}
+	return nil
+}
+
+func (s *hedgedStream) Context() context.Context {
+	return s.ctx
+}
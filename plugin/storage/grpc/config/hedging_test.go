@@ -0,0 +1,191 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+)
+
+// slowReader answers the first call after the delay of the test, or when the call is canceled.
+type slowReader struct {
+	storage_v1.UnimplementedSpanReaderPluginServer
+	calls    atomic.Int32
+	canceled chan struct{}
+	chunks   int
+	err      error
+}
+
+func (r *slowReader) wait(ctx context.Context) bool {
+	if r.calls.Add(1) > 1 {
+		return true
+	}
+	select {
+	case <-ctx.Done():
+		r.canceled <- struct{}{}
+		return false
+	case <-time.After(5 * time.Second):
+		return true
+	}
+}
+
+func (r *slowReader) GetServices(ctx context.Context, _ *storage_v1.GetServicesRequest) (*storage_v1.GetServicesResponse, error) {
+	if r.err != nil {
+		r.calls.Add(1)
+		return nil, r.err
+	}
+	if !r.wait(ctx) {
+		return nil, ctx.Err()
+	}
+	return &storage_v1.GetServicesResponse{Services: []string{"frontend"}}, nil
+}
+
+func (r *slowReader) GetTrace(request *storage_v1.GetTraceRequest, stream storage_v1.SpanReaderPlugin_GetTraceServer) error {
+	if !r.wait(stream.Context()) {
+		return stream.Context().Err()
+	}
+	for i := 0; i < r.chunks; i++ {
+		span := model.Span{TraceID: request.TraceID, SpanID: model.SpanID(i)}
+		if err := stream.Send(&storage_v1.SpansResponseChunk{Spans: []model.Span{span}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startHedgedClient(t *testing.T, reader *slowReader, delay time.Duration) (storage_v1.SpanReaderPluginClient, *metricstest.Factory) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	storage_v1.RegisterSpanReaderPluginServer(server, reader)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	metricsFactory := metricstest.NewFactory(0)
+	t.Cleanup(metricsFactory.Stop)
+	h := newHedger(HedgingConfig{Delay: delay}, metricsFactory)
+	conn, err := grpc.Dial(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(h.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(h.streamInterceptor),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return storage_v1.NewSpanReaderPluginClient(conn), metricsFactory
+}
+
+func expectedHedgingMetrics(requests, hedges, wins int) []metricstest.ExpectedMetric {
+	return []metricstest.ExpectedMetric{
+		{Name: "hedged_reads.requests", Value: requests},
+		{Name: "hedged_reads.hedges", Value: hedges},
+		{Name: "hedged_reads.hedge_wins", Value: wins},
+	}
+}
+
+func TestHedgedUnaryRead(t *testing.T) {
+	reader := &slowReader{canceled: make(chan struct{}, 1)}
+	client, metricsFactory := startHedgedClient(t, reader, 10*time.Millisecond)
+
+	res, err := client.GetServices(context.Background(), &storage_v1.GetServicesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend"}, res.Services)
+	// the slow attempt is canceled
+	<-reader.canceled
+	metricsFactory.AssertCounterMetrics(t, expectedHedgingMetrics(1, 1, 1)...)
+}
+
+func TestHedgedUnaryReadNotHedged(t *testing.T) {
+	reader := &slowReader{err: status.Error(codes.Internal, "storage error")}
+	client, metricsFactory := startHedgedClient(t, reader, time.Minute)
+
+	_, err := client.GetServices(context.Background(), &storage_v1.GetServicesRequest{})
+	require.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, int32(1), reader.calls.Load())
+	metricsFactory.AssertCounterMetrics(t, expectedHedgingMetrics(1, 0, 0)...)
+}
+
+func TestHedgedStreamRead(t *testing.T) {
+	reader := &slowReader{canceled: make(chan struct{}, 1), chunks: 3}
+	client, metricsFactory := startHedgedClient(t, reader, 10*time.Millisecond)
+
+	traceID := model.NewTraceID(1, 2)
+	stream, err := client.GetTrace(context.Background(), &storage_v1.GetTraceRequest{TraceID: traceID})
+	require.NoError(t, err)
+	var spanIDs []model.SpanID
+	for chunk, err := stream.Recv(); !errors.Is(err, io.EOF); chunk, err = stream.Recv() {
+		require.NoError(t, err)
+		for _, span := range chunk.Spans {
+			assert.Equal(t, traceID, span.TraceID)
+			spanIDs = append(spanIDs, span.SpanID)
+		}
+	}
+	assert.Equal(t, []model.SpanID{0, 1, 2}, spanIDs)
+	<-reader.canceled
+	metricsFactory.AssertCounterMetrics(t, expectedHedgingMetrics(1, 1, 1)...)
+}
+
+func TestHedgedStreamReadEmpty(t *testing.T) {
+	reader := &slowReader{canceled: make(chan struct{}, 1)}
+	client, _ := startHedgedClient(t, reader, 10*time.Millisecond)
+
+	stream, err := client.GetTrace(context.Background(), &storage_v1.GetTraceRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+	<-reader.canceled
+}
+
+func TestHedgedStreamReadError(t *testing.T) {
+	h := newHedger(HedgingConfig{Delay: time.Minute}, metricstest.NewFactory(0))
+	stream, err := h.streamInterceptor(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil,
+		"/jaeger.storage.v1.SpanReaderPlugin/GetTrace",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, assert.AnError
+		})
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(&storage_v1.GetTraceRequest{}))
+	require.Error(t, stream.SendMsg(&storage_v1.GetTraceRequest{}))
+	require.NoError(t, stream.CloseSend())
+	header, err := stream.Header()
+	require.NoError(t, err)
+	assert.Nil(t, header)
+	assert.Nil(t, stream.Trailer())
+	assert.NotNil(t, stream.Context())
+	require.ErrorIs(t, stream.RecvMsg(&storage_v1.SpansResponseChunk{}), assert.AnError)
+}
+
+func TestHedgingSkipsWrites(t *testing.T) {
+	h := newHedger(HedgingConfig{Delay: time.Nanosecond}, metricstest.NewFactory(0))
+	calls := 0
+	err := h.unaryInterceptor(context.Background(), "/jaeger.storage.v1.SpanWriterPlugin/WriteSpan", nil, nil, nil,
+		func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+			calls++
+			return nil
+		})
+	require.NoError(t, err)
+	_, err = h.streamInterceptor(context.Background(), &grpc.StreamDesc{ClientStreams: true}, nil,
+		"/jaeger.storage.v1.StreamingSpanWriterPlugin/WriteSpanStream",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			calls++
+			return nil, nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// connPool spreads the requests over several connections to the remote storage in turn,
+// so that they are not limited by the concurrent streams of a single HTTP/2 connection.
+// The first connection of the pool is the connection the requests are sent on, the pool
+// holds the other ones.
+type connPool struct {
+	conns []*grpc.ClientConn
+	next  atomic.Uint64
+}
+
+// dialPool opens the size-1 other connections of a pool of size connections.
+func (c *Configuration) dialPool(ctx context.Context, size int, opts []grpc.DialOption) (*connPool, error) {
+	p := &connPool{}
+	for i := 1; i < size; i++ {
+		conn, err := grpc.DialContext(ctx, c.RemoteServerAddr, opts...)
+		if err != nil {
+			p.close()
+			return nil, fmt.Errorf("error connecting to remote storage with the connection %d of the pool: %w", i+1, err)
+		}
+		p.conns = append(p.conns, conn)
+	}
+	return p, nil
+}
+
+// pick returns the connection of the next request, nil for the connection it is sent on.
+func (p *connPool) pick() *grpc.ClientConn {
+	i := (p.next.Add(1) - 1) % uint64(len(p.conns)+1)
+	if i == 0 {
+		return nil
+	}
+	return p.conns[i-1]
+}
+
+func (p *connPool) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if conn := p.pick(); conn != nil {
+		return conn.Invoke(ctx, method, req, reply, opts...)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (p *connPool) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	if conn := p.pick(); conn != nil {
+		return conn.NewStream(ctx, desc, method, opts...)
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+func (p *connPool) close() {
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+)
+
+// peerReader counts the requests received from each client connection.
+type peerReader struct {
+	storage_v1.UnimplementedSpanReaderPluginServer
+	mu    sync.Mutex
+	peers map[string]int
+}
+
+func (r *peerReader) record(ctx context.Context) {
+	p, _ := peer.FromContext(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.peers[p.Addr.String()]++
+}
+
+func (r *peerReader) GetServices(ctx context.Context, _ *storage_v1.GetServicesRequest) (*storage_v1.GetServicesResponse, error) {
+	r.record(ctx)
+	return &storage_v1.GetServicesResponse{}, nil
+}
+
+func (r *peerReader) GetTrace(request *storage_v1.GetTraceRequest, stream storage_v1.SpanReaderPlugin_GetTraceServer) error {
+	r.record(stream.Context())
+	return stream.Send(&storage_v1.SpansResponseChunk{Spans: []model.Span{{TraceID: request.TraceID}}})
+}
+
+func TestConnectionPool(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	reader := &peerReader{peers: make(map[string]int)}
+	server := grpc.NewServer()
+	storage_v1.RegisterSpanReaderPluginServer(server, reader)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	cfg := &Configuration{
+		RemoteServerAddr:     lis.Addr().String(),
+		RemoteConnectTimeout: 5 * time.Second,
+		RemoteConnections:    3,
+	}
+	services, err := cfg.Build(zap.NewNop(), metrics.NullFactory, noop.NewTracerProvider())
+	require.NoError(t, err)
+	defer cfg.Close()
+
+	spanReader := services.Store.SpanReader()
+	for i := 0; i < 3; i++ {
+		_, err := spanReader.GetServices(context.Background())
+		require.NoError(t, err)
+		_, err = spanReader.GetTrace(context.Background(), model.NewTraceID(0, 1))
+		require.NoError(t, err)
+	}
+
+	reader.mu.Lock()
+	defer reader.mu.Unlock()
+	assert.Len(t, reader.peers, 3)
+	for addr, requests := range reader.peers {
+		assert.Equal(t, 2, requests, addr)
+	}
+}
+
+func TestConnectionPoolDialError(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	cfg := &Configuration{
+		RemoteServerAddr:     addr,
+		RemoteConnectTimeout: 100 * time.Millisecond,
+		RemoteConnections:    2,
+	}
+	_, err = cfg.Build(zap.NewNop(), metrics.NullFactory, noop.NewTracerProvider())
+	require.ErrorContains(t, err, "error connecting to remote storage with the connection 2 of the pool")
+	require.NoError(t, cfg.Close())
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+// TenantTLSConfig is the client certificate presented to the remote storage server for the requests of a tenant.
+// The tenants without one use the client certificate of RemoteTLS, if any.
+type TenantTLSConfig struct {
+	CertPath string `yaml:"cert" mapstructure:"cert"`
+	KeyPath  string `yaml:"key" mapstructure:"key"`
+}
+
+// tenantCredentials are the TLS transport credentials of the connection of a tenant. The certificate
+// of the tenant is read at every handshake, so that the new connections use the rotated certificates.
+type tenantCredentials struct {
+	tenant    string
+	cert      TenantTLSConfig
+	tlsConfig *tls.Config
+}
+
+func newTenantCredentials(tenant string, cert TenantTLSConfig, tlsConfig *tls.Config) (*tenantCredentials, error) {
+	if cert.CertPath == "" || cert.KeyPath == "" {
+		return nil, fmt.Errorf("both the client certificate and key must be supplied for the tenant %q", tenant)
+	}
+	creds := &tenantCredentials{tenant: tenant, cert: cert, tlsConfig: tlsConfig}
+	if _, err := creds.certificate(); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+func (c *tenantCredentials) certificate() (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Clean(c.cert.CertPath), filepath.Clean(c.cert.KeyPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the client certificate of the tenant %q: %w", c.tenant, err)
+	}
+	return &cert, nil
+}
+
+func (c *tenantCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	cert, err := c.certificate()
+	if err != nil {
+		return nil, nil, err
+	}
+	tlsConfig := c.tlsConfig.Clone()
+	tlsConfig.GetCertificate = nil
+	tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return cert, nil
+	}
+	return credentials.NewTLS(tlsConfig).ClientHandshake(ctx, authority, rawConn)
+}
+
+func (*tenantCredentials) ServerHandshake(net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("the tenant credentials are only used by the clients")
+}
+
+func (c *tenantCredentials) Info() credentials.ProtocolInfo {
+	return credentials.NewTLS(c.tlsConfig).Info()
+}
+
+func (c *tenantCredentials) Clone() credentials.TransportCredentials {
+	return &tenantCredentials{tenant: c.tenant, cert: c.cert, tlsConfig: c.tlsConfig.Clone()}
+}
+
+// OverrideServerName is deprecated by gRPC and kept to implement credentials.TransportCredentials.
+func (c *tenantCredentials) OverrideServerName(serverNameOverride string) error {
+	c.tlsConfig.ServerName = serverNameOverride
+	return nil
+}
+
+// tenantRouter sends the requests of the tenants with a client certificate to the connections of
+// these tenants, and the requests of the other tenants to the default connection.
+type tenantRouter struct {
+	conns map[string]*grpc.ClientConn
+}
+
+func (r *tenantRouter) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if conn, ok := r.conns[tenancy.GetTenant(ctx)]; ok {
+		return conn.Invoke(ctx, method, req, reply, opts...)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (r *tenantRouter) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	if conn, ok := r.conns[tenancy.GetTenant(ctx)]; ok {
+		return conn.NewStream(ctx, desc, method, opts...)
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+)
+
+const testCertDir = "../../../../pkg/config/tlscfg/testdata"
+
+// certReader answers the serial numbers of the client certificates as the services.
+type certReader struct {
+	storage_v1.UnimplementedSpanReaderPluginServer
+}
+
+func (*certReader) GetServices(ctx context.Context, _ *storage_v1.GetServicesRequest) (*storage_v1.GetServicesResponse, error) {
+	p, _ := peer.FromContext(ctx)
+	tlsInfo := p.AuthInfo.(credentials.TLSInfo)
+	return &storage_v1.GetServicesResponse{Services: []string{tlsInfo.State.PeerCertificates[0].SerialNumber.String()}}, nil
+}
+
+func serialNumber(t *testing.T, certPath, keyPath string) string {
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return leaf.SerialNumber.String()
+}
+
+func startTLSServer(t *testing.T) string {
+	cert, err := tls.LoadX509KeyPair(testCertDir+"/example-server-cert.pem", testCertDir+"/example-server-key.pem")
+	require.NoError(t, err)
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAnyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})))
+	storage_v1.RegisterSpanReaderPluginServer(server, &certReader{})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func tenantTLSConfiguration(addr string) *Configuration {
+	return &Configuration{
+		RemoteServerAddr: addr,
+		RemoteTLS: tlscfg.Options{
+			Enabled:    true,
+			CAPath:     testCertDir + "/example-CA-cert.pem",
+			CertPath:   testCertDir + "/example-client-cert.pem",
+			KeyPath:    testCertDir + "/example-client-key.pem",
+			ServerName: "example.com",
+		},
+		RemoteConnectTimeout: 5 * time.Second,
+		RemoteTenantTLS: map[string]TenantTLSConfig{
+			"acme": {
+				CertPath: testCertDir + "/example-server-cert.pem",
+				KeyPath:  testCertDir + "/example-server-key.pem",
+			},
+		},
+		TenancyOpts: tenancy.Options{Enabled: true},
+	}
+}
+
+func TestTenantClientCertificates(t *testing.T) {
+	cfg := tenantTLSConfiguration(startTLSServer(t))
+	services, err := cfg.Build(zap.NewNop(), metrics.NullFactory, noop.NewTracerProvider())
+	require.NoError(t, err)
+	defer cfg.Close()
+
+	tests := []struct {
+		tenant   string
+		certPath string
+		keyPath  string
+	}{
+		{tenant: "acme", certPath: "/example-server-cert.pem", keyPath: "/example-server-key.pem"},
+		{tenant: "globex", certPath: "/example-client-cert.pem", keyPath: "/example-client-key.pem"},
+	}
+	for _, test := range tests {
+		t.Run(test.tenant, func(t *testing.T) {
+			ctx := tenancy.WithTenant(context.Background(), test.tenant)
+			serials, err := services.Store.SpanReader().GetServices(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{serialNumber(t, testCertDir+test.certPath, testCertDir+test.keyPath)}, serials)
+		})
+	}
+}
+
+func TestTenantClientCertificatesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(cfg *Configuration)
+		err    string
+	}{
+		{
+			name:   "TLS disabled",
+			update: func(cfg *Configuration) { cfg.RemoteTLS = tlscfg.Options{} },
+			err:    "require TLS and multi-tenancy to be enabled",
+		},
+		{
+			name:   "tenancy disabled",
+			update: func(cfg *Configuration) { cfg.TenancyOpts = tenancy.Options{} },
+			err:    "require TLS and multi-tenancy to be enabled",
+		},
+		{
+			name:   "missing key",
+			update: func(cfg *Configuration) { cfg.RemoteTenantTLS["acme"] = TenantTLSConfig{CertPath: "cert.pem"} },
+			err:    `both the client certificate and key must be supplied for the tenant "acme"`,
+		},
+		{
+			name: "invalid certificate",
+			update: func(cfg *Configuration) {
+				cfg.RemoteTenantTLS["acme"] = TenantTLSConfig{CertPath: "missing.pem", KeyPath: "missing.pem"}
+			},
+			err: `failed to load the client certificate of the tenant "acme"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := tenantTLSConfiguration("localhost:0")
+			test.update(cfg)
+			_, err := cfg.Build(zap.NewNop(), metrics.NullFactory, noop.NewTracerProvider())
+			require.ErrorContains(t, err, test.err)
+			require.NoError(t, cfg.Close())
+		})
+	}
+}
+
+func TestTenantCredentials(t *testing.T) {
+	creds, err := newTenantCredentials("acme", TenantTLSConfig{
+		CertPath: testCertDir + "/example-client-cert.pem",
+		KeyPath:  testCertDir + "/example-client-key.pem",
+	}, &tls.Config{ServerName: "example.com", MinVersion: tls.VersionTLS12})
+	require.NoError(t, err)
+
+	assert.Equal(t, "tls", creds.Info().SecurityProtocol)
+	_, _, err = creds.ServerHandshake(nil)
+	require.Error(t, err)
+	clone := creds.Clone()
+	require.NoError(t, clone.OverrideServerName("example.org"))
+	assert.Equal(t, "example.org", clone.Info().ServerName)
+	assert.Equal(t, "example.com", creds.Info().ServerName)
+}
@@ -19,6 +19,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel"
@@ -37,6 +38,7 @@ import (
 var ( // interface comformance checks
 	_ storage.Factory        = (*Factory)(nil)
 	_ storage.ArchiveFactory = (*Factory)(nil)
+	_ storage.PurgerFactory  = (*Factory)(nil)
 	_ io.Closer              = (*Factory)(nil)
 	_ plugin.Configurable    = (*Factory)(nil)
 )
@@ -53,8 +55,13 @@ type Factory struct {
 	store               shared.StoragePlugin
 	archiveStore        shared.ArchiveStoragePlugin
 	streamingSpanWriter shared.StreamingSpanWriterPlugin
+	otlpSpanWriter      shared.OTLPSpanWriterPlugin
 	capabilities        shared.PluginCapabilities
 
+	// negotiated are the capabilities negotiated with the plugin on first use
+	negotiated     *shared.Capabilities
+	negotiatedLock sync.Mutex
+
 	servicesCloser io.Closer
 }
 
@@ -102,7 +109,7 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	f.metricsFactory, f.logger = metricsFactory, logger
 	f.tracerProvider = otel.GetTracerProvider()
 
-	services, err := f.builder.Build(logger, f.tracerProvider)
+	services, err := f.builder.Build(logger, metricsFactory, f.tracerProvider)
 	if err != nil {
 		return fmt.Errorf("grpc-plugin builder failed to create a store: %w", err)
 	}
@@ -111,6 +118,9 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	f.archiveStore = services.ArchiveStore
 	f.capabilities = services.Capabilities
 	f.streamingSpanWriter = services.StreamingSpanWriter
+	if f.options.Configuration.RemoteOTLPWrites {
+		f.otlpSpanWriter = services.OTLPSpanWriter
+	}
 	f.servicesCloser = services
 	logger.Info("External plugin storage configuration", zap.Any("configuration", f.options.Configuration))
 	return nil
@@ -123,12 +133,21 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	if f.capabilities != nil && f.streamingSpanWriter != nil {
-		if capabilities, err := f.capabilities.Capabilities(); err == nil && capabilities.StreamingSpanWriter {
-			return f.streamingSpanWriter.StreamingSpanWriter(), nil
-		}
+	if f.streamingSpanWriter == nil && f.otlpSpanWriter == nil {
+		return f.store.SpanWriter(), nil
+	}
+	writer := f.store.SpanWriter()
+	capabilities, err := f.getCapabilities()
+	if err != nil || capabilities == nil {
+		return writer, nil
+	}
+	if f.streamingSpanWriter != nil && capabilities.StreamingSpanWriter {
+		writer = f.streamingSpanWriter.StreamingSpanWriter()
+	}
+	if f.otlpSpanWriter != nil && capabilities.OTLPSpanWriter {
+		writer = &otlpSpanWriter{Writer: writer, OTLPWriter: f.otlpSpanWriter.OTLPSpanWriter()}
 	}
-	return f.store.SpanWriter(), nil
+	return writer, nil
 }
 
 // CreateDependencyReader implements storage.Factory
@@ -138,10 +157,7 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 
 // CreateArchiveSpanReader implements storage.ArchiveFactory
 func (f *Factory) CreateArchiveSpanReader() (spanstore.Reader, error) {
-	if f.capabilities == nil {
-		return nil, storage.ErrArchiveStorageNotSupported
-	}
-	capabilities, err := f.capabilities.Capabilities()
+	capabilities, err := f.getCapabilities()
 	if err != nil {
 		return nil, err
 	}
@@ -153,10 +169,7 @@ func (f *Factory) CreateArchiveSpanReader() (spanstore.Reader, error) {
 
 // CreateArchiveSpanWriter implements storage.ArchiveFactory
 func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
-	if f.capabilities == nil {
-		return nil, storage.ErrArchiveStorageNotSupported
-	}
-	capabilities, err := f.capabilities.Capabilities()
+	capabilities, err := f.getCapabilities()
 	if err != nil {
 		return nil, err
 	}
@@ -166,6 +179,45 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 	return f.archiveStore.ArchiveSpanWriter(), nil
 }
 
+// CreatePurger implements storage.PurgerFactory
+func (f *Factory) CreatePurger() (spanstore.Purger, error) {
+	purger, ok := f.store.(shared.PurgerPlugin)
+	if !ok {
+		return nil, storage.ErrPurgerNotSupported
+	}
+	capabilities, err := f.getCapabilities()
+	if err != nil {
+		return nil, err
+	}
+	if capabilities == nil || !capabilities.Purger {
+		return nil, storage.ErrPurgerNotSupported
+	}
+	return purger.Purger(), nil
+}
+
+// getCapabilities negotiates the capabilities with the plugin on first use, and logs the disabled features.
+// It returns nil if the plugin does not expose its capabilities.
+func (f *Factory) getCapabilities() (*shared.Capabilities, error) {
+	if f.capabilities == nil {
+		return nil, nil
+	}
+	f.negotiatedLock.Lock()
+	defer f.negotiatedLock.Unlock()
+	if f.negotiated != nil {
+		return f.negotiated, nil
+	}
+	capabilities, err := f.capabilities.Capabilities()
+	if err != nil || capabilities == nil {
+		return nil, err
+	}
+	f.negotiated = capabilities
+	f.logger.Info("Negotiated the capabilities of the storage plugin",
+		zap.String("version", capabilities.Version),
+		zap.Strings("features", capabilities.Features()),
+		zap.Strings("disabled-features", capabilities.DisabledFeatures()))
+	return capabilities, nil
+}
+
 // Close closes the resources held by the factory
 func (f *Factory) Close() error {
 	errs := []error{}
@@ -175,3 +227,18 @@ func (f *Factory) Close() error {
 	errs = append(errs, f.builder.Close())
 	return errors.Join(errs...)
 }
+
+// otlpSpanWriter writes the spans with the span writer of the plugin, and the traces
+// in the OTLP format with its OTLP span writer.
+type otlpSpanWriter struct {
+	spanstore.Writer
+	spanstore.OTLPWriter
+}
+
+// Close closes the span writer of the plugin if it is closable.
+func (w *otlpSpanWriter) Close() error {
+	if closer, ok := w.Writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
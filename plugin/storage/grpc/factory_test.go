@@ -15,21 +15,26 @@
 package grpc
 
 import (
+	"context"
 	"errors"
+	"io"
 	"log"
 	"net"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
 	"github.com/jaegertracing/jaeger/pkg/config"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
+	"github.com/jaegertracing/jaeger/pkg/testutils"
 	grpcConfig "github.com/jaegertracing/jaeger/plugin/storage/grpc/config"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/mocks"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
@@ -46,7 +51,7 @@ type mockPluginBuilder struct {
 	err        error
 }
 
-func (b *mockPluginBuilder) Build(logger *zap.Logger, tracer trace.TracerProvider) (*grpcConfig.ClientPluginServices, error) {
+func (b *mockPluginBuilder) Build(logger *zap.Logger, metricsFactory metrics.Factory, tracer trace.TracerProvider) (*grpcConfig.ClientPluginServices, error) {
 	if b.err != nil {
 		return nil, b.err
 	}
@@ -60,6 +65,9 @@ func (b *mockPluginBuilder) Build(logger *zap.Logger, tracer trace.TracerProvide
 	if b.writerType == "streaming" {
 		services.PluginServices.StreamingSpanWriter = b.plugin
 	}
+	if b.plugin.otlpSpanWriter != nil {
+		services.PluginServices.OTLPSpanWriter = b.plugin
+	}
 	if b.plugin.capabilities != nil {
 		services.Capabilities = b.plugin
 	}
@@ -79,6 +87,8 @@ type mockPlugin struct {
 	streamingSpanWriter spanstore.Writer
 	capabilities        shared.PluginCapabilities
 	dependencyReader    dependencystore.Reader
+	purger              spanstore.Purger
+	otlpSpanWriter      spanstore.OTLPWriter
 }
 
 func (mp *mockPlugin) Capabilities() (*shared.Capabilities, error) {
@@ -109,6 +119,14 @@ func (mp *mockPlugin) DependencyReader() dependencystore.Reader {
 	return mp.dependencyReader
 }
 
+func (mp *mockPlugin) Purger() spanstore.Purger {
+	return mp.purger
+}
+
+func (mp *mockPlugin) OTLPSpanWriter() spanstore.OTLPWriter {
+	return mp.otlpSpanWriter
+}
+
 func TestGRPCStorageFactory(t *testing.T) {
 	f := NewFactory()
 	v := viper.New()
@@ -170,18 +188,43 @@ func TestGRPCStorageFactoryWithConfig(t *testing.T) {
 	require.NoError(t, f.Close())
 }
 
+func TestGRPCStorageFactoryWithUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remote-storage.sock")
+	lis, err := net.Listen("unix", path)
+	require.NoError(t, err, "failed to listen")
+
+	s := grpc.NewServer()
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			log.Fatalf("Server exited with error: %v", err)
+		}
+	}()
+	defer s.Stop()
+
+	cfg := grpcConfig.Configuration{
+		RemoteServerAddr:     "unix://" + path,
+		RemoteConnectTimeout: 1 * time.Second,
+	}
+	f, err := NewFactoryWithConfig(cfg, metrics.NullFactory, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
 func TestGRPCStorageFactory_Capabilities(t *testing.T) {
 	f := NewFactory()
 	v := viper.New()
 	f.InitFromViper(v, zap.NewNop())
 
 	capabilities := new(mocks.PluginCapabilities)
+	// the capabilities are negotiated once
 	capabilities.On("Capabilities").
 		Return(&shared.Capabilities{
+			Version:             shared.StorageAPIVersion,
 			ArchiveSpanReader:   true,
 			ArchiveSpanWriter:   true,
 			StreamingSpanWriter: true,
-		}, nil).Times(3)
+			Purger:              true,
+		}, nil).Once()
 
 	f.builder = &mockPluginBuilder{
 		plugin: &mockPlugin{
@@ -189,10 +232,12 @@ func TestGRPCStorageFactory_Capabilities(t *testing.T) {
 			archiveWriter:       new(spanStoreMocks.Writer),
 			archiveReader:       new(spanStoreMocks.Reader),
 			streamingSpanWriter: new(spanStoreMocks.Writer),
+			purger:              new(spanStoreMocks.Purger),
 		},
 		writerType: "streaming",
 	}
-	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	logger, logBuf := testutils.NewLogger()
+	require.NoError(t, f.Initialize(metrics.NullFactory, logger))
 
 	assert.NotNil(t, f.store)
 	reader, err := f.CreateArchiveSpanReader()
@@ -204,6 +249,11 @@ func TestGRPCStorageFactory_Capabilities(t *testing.T) {
 	writer, err = f.CreateSpanWriter()
 	require.NoError(t, err)
 	assert.Equal(t, f.streamingSpanWriter.StreamingSpanWriter(), writer)
+	purger, err := f.CreatePurger()
+	require.NoError(t, err)
+	assert.NotNil(t, purger)
+	capabilities.AssertExpectations(t)
+	assert.Contains(t, logBuf.String(), "Negotiated the capabilities of the storage plugin")
 }
 
 func TestGRPCStorageFactory_CapabilitiesDisabled(t *testing.T) {
@@ -236,6 +286,9 @@ func TestGRPCStorageFactory_CapabilitiesDisabled(t *testing.T) {
 	writer, err := f.CreateArchiveSpanWriter()
 	require.EqualError(t, err, storage.ErrArchiveStorageNotSupported.Error())
 	assert.Nil(t, writer)
+	purger, err := f.CreatePurger()
+	require.ErrorIs(t, err, storage.ErrPurgerNotSupported)
+	assert.Nil(t, purger)
 	writer, err = f.CreateSpanWriter()
 	require.NoError(t, err)
 	assert.Equal(t, f.store.SpanWriter(), writer)
@@ -268,6 +321,9 @@ func TestGRPCStorageFactory_CapabilitiesError(t *testing.T) {
 	writer, err := f.CreateArchiveSpanWriter()
 	require.EqualError(t, err, customError.Error())
 	assert.Nil(t, writer)
+	purger, err := f.CreatePurger()
+	require.EqualError(t, err, customError.Error())
+	assert.Nil(t, purger)
 	writer, err = f.CreateSpanWriter()
 	require.NoError(t, err)
 	assert.Equal(t, f.store.SpanWriter(), writer)
@@ -375,3 +431,85 @@ func TestStreamingSpanWriterFactory_Capabilities(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, f.store.SpanWriter(), writer) // get unary writer when Capabilities return false
 }
+
+type mockOTLPWriter struct{}
+
+func (mockOTLPWriter) WriteTraces(context.Context, ptrace.Traces) error {
+	return nil
+}
+
+type closableWriter struct {
+	*spanStoreMocks.Writer
+	closed bool
+}
+
+func (w *closableWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestOTLPSpanWriterFactory(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		otlpWrites   bool
+		capabilities *shared.Capabilities
+		otlp         bool
+		streaming    bool
+	}{
+		{
+			name:         "disabled",
+			capabilities: &shared.Capabilities{OTLPSpanWriter: true},
+		},
+		{
+			name:         "not supported",
+			otlpWrites:   true,
+			capabilities: &shared.Capabilities{StreamingSpanWriter: true},
+			streaming:    true,
+		},
+		{
+			name:         "supported",
+			otlpWrites:   true,
+			capabilities: &shared.Capabilities{OTLPSpanWriter: true},
+			otlp:         true,
+		},
+		{
+			name:         "supported with streaming",
+			otlpWrites:   true,
+			capabilities: &shared.Capabilities{OTLPSpanWriter: true, StreamingSpanWriter: true},
+			otlp:         true,
+			streaming:    true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewFactory()
+			f.InitFromOptions(Options{Configuration: grpcConfig.Configuration{RemoteOTLPWrites: test.otlpWrites}})
+			capabilities := new(mocks.PluginCapabilities)
+			capabilities.On("Capabilities").Return(test.capabilities, nil)
+			streamingSpanWriter := &closableWriter{Writer: new(spanStoreMocks.Writer)}
+			f.builder = &mockPluginBuilder{
+				plugin: &mockPlugin{
+					spanWriter:          new(spanStoreMocks.Writer),
+					streamingSpanWriter: streamingSpanWriter,
+					capabilities:        capabilities,
+					otlpSpanWriter:      mockOTLPWriter{},
+				},
+				writerType: "streaming",
+			}
+			require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+			writer, err := f.CreateSpanWriter()
+			require.NoError(t, err)
+			expectedWriter := f.store.SpanWriter()
+			if test.streaming {
+				expectedWriter = streamingSpanWriter
+			}
+			if !test.otlp {
+				assert.Equal(t, expectedWriter, writer)
+				return
+			}
+			assert.Equal(t, &otlpSpanWriter{Writer: expectedWriter, OTLPWriter: mockOTLPWriter{}}, writer)
+			require.NoError(t, writer.(io.Closer).Close())
+			assert.Equal(t, test.streaming, streamingSpanWriter.closed)
+		})
+	}
+}
@@ -34,6 +34,9 @@ const (
 	remotePrefix             = "grpc-storage"
 	remoteServer             = remotePrefix + ".server"
 	remoteConnectionTimeout  = remotePrefix + ".connection-timeout"
+	remoteHedgingDelay       = remotePrefix + ".hedging.delay"
+	remoteConnections        = remotePrefix + ".connections"
+	remoteOTLPWrites         = remotePrefix + ".otlp-writes"
 	defaultPluginLogLevel    = "warn"
 	defaultConnectionTimeout = time.Duration(5 * time.Second)
 
@@ -59,8 +62,14 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 	flagSet.String(pluginBinary, "", deprecatedSidecar+"The location of the plugin binary")
 	flagSet.String(pluginConfigurationFile, "", deprecatedSidecar+"A path pointing to the plugin's configuration file, made available to the plugin with the --config arg")
 	flagSet.String(pluginLogLevel, defaultPluginLogLevel, "Set the log level of the plugin's logger")
-	flagSet.String(remoteServer, "", "The remote storage gRPC server address as host:port")
+	flagSet.String(remoteServer, "", "The remote storage gRPC server address as host:port, or unix:///path/to/socket for a unix domain socket")
 	flagSet.Duration(remoteConnectionTimeout, defaultConnectionTimeout, "The remote storage gRPC server connection timeout")
+	flagSet.Duration(remoteHedgingDelay, 0, "The delay after which the read requests to the remote storage gRPC server are sent a second time, "+
+		"using the first successful answer (hedged reads). Zero disables the hedged reads")
+	flagSet.Int(remoteConnections, 1, "The number of connections to the remote storage gRPC server, used in turn by the requests "+
+		"to avoid the limit of concurrent streams of a single connection")
+	flagSet.Bool(remoteOTLPWrites, false, "Whether to send the traces received in the OTLP format to the remote storage gRPC server "+
+		"without converting them to the Jaeger model, when the server advertises that it prefers them")
 }
 
 // InitFromViper initializes Options with properties from viper
@@ -75,6 +84,9 @@ func (opt *Options) InitFromViper(v *viper.Viper) error {
 		return fmt.Errorf("failed to parse gRPC storage TLS options: %w", err)
 	}
 	opt.Configuration.RemoteConnectTimeout = v.GetDuration(remoteConnectionTimeout)
+	opt.Configuration.RemoteHedging.Delay = v.GetDuration(remoteHedgingDelay)
+	opt.Configuration.RemoteConnections = v.GetInt(remoteConnections)
+	opt.Configuration.RemoteOTLPWrites = v.GetBool(remoteOTLPWrites)
 	opt.Configuration.TenancyOpts = tenancy.InitFromViper(v)
 	if opt.Configuration.PluginBinary != "" {
 		log.Printf(deprecatedSidecar + "using sidecar model of grpc-plugin storage, please upgrade to 'remote' gRPC storage. https://github.com/jaegertracing/jaeger/issues/4647")
@@ -51,6 +51,8 @@ func TestRemoteOptionsWithFlags(t *testing.T) {
 		"--grpc-storage.server=localhost:2001",
 		"--grpc-storage.tls.enabled=true",
 		"--grpc-storage.connection-timeout=60s",
+		"--grpc-storage.connections=4",
+		"--grpc-storage.otlp-writes=true",
 	})
 	require.NoError(t, err)
 	opts.InitFromViper(v)
@@ -59,6 +61,8 @@ func TestRemoteOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, "localhost:2001", opts.Configuration.RemoteServerAddr)
 	assert.True(t, opts.Configuration.RemoteTLS.Enabled)
 	assert.Equal(t, 60*time.Second, opts.Configuration.RemoteConnectTimeout)
+	assert.Equal(t, 4, opts.Configuration.RemoteConnections)
+	assert.True(t, opts.Configuration.RemoteOTLPWrites)
 }
 
 func TestRemoteOptionsNoTLSWithFlags(t *testing.T) {
@@ -76,6 +80,8 @@ func TestRemoteOptionsNoTLSWithFlags(t *testing.T) {
 	assert.Equal(t, "localhost:2001", opts.Configuration.RemoteServerAddr)
 	assert.False(t, opts.Configuration.RemoteTLS.Enabled)
 	assert.Equal(t, 60*time.Second, opts.Configuration.RemoteConnectTimeout)
+	assert.Equal(t, 1, opts.Configuration.RemoteConnections)
+	assert.False(t, opts.Configuration.RemoteOTLPWrites)
 }
 
 func TestFailedTLSFlags(t *testing.T) {
@@ -0,0 +1,114 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package shared
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// StorageAPIVersion is the semantic version of the storage API implemented by this package.
+	// The minor version is incremented when optional RPCs are added, and the major version
+	// when the existing RPCs are changed incompatibly.
+	StorageAPIVersion = "1.4.0"
+	// legacyStorageAPIVersion is the version implemented by the plugins without the Negotiate RPC.
+	legacyStorageAPIVersion = "1.0.0"
+)
+
+// The optional features negotiated between the client and the plugin.
+const (
+	FeatureArchiveSpanReader   = "archive_span_reader"
+	FeatureArchiveSpanWriter   = "archive_span_writer"
+	FeatureStreamingSpanWriter = "streaming_span_writer"
+	FeaturePurger              = "purger"
+	FeatureOTLPSpanWriter      = "otlp_span_writer"
+)
+
+// knownFeatures are the optional features supported by the client.
+var knownFeatures = []string{FeatureArchiveSpanReader, FeatureArchiveSpanWriter, FeatureStreamingSpanWriter, FeaturePurger, FeatureOTLPSpanWriter}
+
+// Features returns the optional features enabled by the capabilities.
+func (c *Capabilities) Features() []string {
+	var features []string
+	if c.ArchiveSpanReader {
+		features = append(features, FeatureArchiveSpanReader)
+	}
+	if c.ArchiveSpanWriter {
+		features = append(features, FeatureArchiveSpanWriter)
+	}
+	if c.StreamingSpanWriter {
+		features = append(features, FeatureStreamingSpanWriter)
+	}
+	if c.Purger {
+		features = append(features, FeaturePurger)
+	}
+	if c.OTLPSpanWriter {
+		features = append(features, FeatureOTLPSpanWriter)
+	}
+	return features
+}
+
+// DisabledFeatures returns the optional features known by the client but not enabled by the capabilities.
+func (c *Capabilities) DisabledFeatures() []string {
+	enabled := c.Features()
+	var disabled []string
+	for _, feature := range knownFeatures {
+		if !slices.Contains(enabled, feature) {
+			disabled = append(disabled, feature)
+		}
+	}
+	return disabled
+}
+
+// capabilitiesFromFeatures returns the capabilities of a plugin supporting the features,
+// the features unknown to this version of the client are ignored.
+func capabilitiesFromFeatures(version string, features []string) *Capabilities {
+	capabilities := &Capabilities{Version: version}
+	for _, feature := range features {
+		switch feature {
+		case FeatureArchiveSpanReader:
+			capabilities.ArchiveSpanReader = true
+		case FeatureArchiveSpanWriter:
+			capabilities.ArchiveSpanWriter = true
+		case FeatureStreamingSpanWriter:
+			capabilities.StreamingSpanWriter = true
+		case FeaturePurger:
+			capabilities.Purger = true
+		case FeatureOTLPSpanWriter:
+			capabilities.OTLPSpanWriter = true
+		}
+	}
+	return capabilities
+}
+
+// checkCompatibleVersion returns an error if the version of the peer is not compatible with StorageAPIVersion.
+func checkCompatibleVersion(version string) error {
+	major, err := majorVersion(version)
+	if err != nil {
+		return err
+	}
+	ownMajor, _ := majorVersion(StorageAPIVersion)
+	if major != ownMajor {
+		return fmt.Errorf("incompatible storage API versions %s and %s", version, StorageAPIVersion)
+	}
+	return nil
+}
+
+// majorVersion parses the major version of a MAJOR.MINOR.PATCH semantic version.
+func majorVersion(version string) (int, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid storage API version %q, expecting MAJOR.MINOR.PATCH", version)
+	}
+	for _, part := range parts {
+		if n, err := strconv.Atoi(part); err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid storage API version %q, expecting MAJOR.MINOR.PATCH", version)
+		}
+	}
+	major, _ := strconv.Atoi(parts[0])
+	return major, nil
+}
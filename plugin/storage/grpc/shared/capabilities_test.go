@@ -0,0 +1,31 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilitiesFeatures(t *testing.T) {
+	capabilities := capabilitiesFromFeatures("1.1.0", []string{FeatureStreamingSpanWriter, "unknown"})
+	assert.Equal(t, &Capabilities{Version: "1.1.0", StreamingSpanWriter: true}, capabilities)
+	assert.Equal(t, []string{FeatureStreamingSpanWriter}, capabilities.Features())
+	assert.Equal(t, []string{FeatureArchiveSpanReader, FeatureArchiveSpanWriter, FeaturePurger, FeatureOTLPSpanWriter}, capabilities.DisabledFeatures())
+
+	all := capabilitiesFromFeatures("1.1.0", knownFeatures)
+	assert.Equal(t, knownFeatures, all.Features())
+	assert.Empty(t, all.DisabledFeatures())
+}
+
+func TestCheckCompatibleVersion(t *testing.T) {
+	require.NoError(t, checkCompatibleVersion("1.0.0"))
+	require.NoError(t, checkCompatibleVersion("1.42.3"))
+	require.EqualError(t, checkCompatibleVersion("2.0.0"), "incompatible storage API versions 2.0.0 and "+StorageAPIVersion)
+	for _, version := range []string{"", "1", "1.0", "v1.0.0", "1.0.-1", "1.0.0.0"} {
+		require.ErrorContains(t, checkCompatibleVersion(version), "invalid storage API version", version)
+	}
+}
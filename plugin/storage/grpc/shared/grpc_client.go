@@ -21,6 +21,8 @@ import (
 	"io"
 	"time"
 
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -40,6 +42,8 @@ var (
 	_ StoragePlugin        = (*grpcClient)(nil)
 	_ ArchiveStoragePlugin = (*grpcClient)(nil)
 	_ PluginCapabilities   = (*grpcClient)(nil)
+	_ PurgerPlugin         = (*grpcClient)(nil)
+	_ OTLPSpanWriterPlugin = (*grpcClient)(nil)
 
 	// upgradeContext composites several steps of upgrading context
 	upgradeContext = composeContextUpgradeFuncs(upgradeContextWithBearerToken)
@@ -54,6 +58,8 @@ type grpcClient struct {
 	capabilitiesClient  storage_v1.PluginCapabilitiesClient
 	depsReaderClient    storage_v1.DependenciesReaderPluginClient
 	streamWriterClient  storage_v1.StreamingSpanWriterPluginClient
+	purgerClient        storage_v1.PurgerPluginClient
+	otlpTraceClient     ptraceotlp.GRPCClient
 }
 
 func NewGRPCClient(c *grpc.ClientConn) *grpcClient {
@@ -65,6 +71,8 @@ func NewGRPCClient(c *grpc.ClientConn) *grpcClient {
 		capabilitiesClient:  storage_v1.NewPluginCapabilitiesClient(c),
 		depsReaderClient:    storage_v1.NewDependenciesReaderPluginClient(c),
 		streamWriterClient:  storage_v1.NewStreamingSpanWriterPluginClient(c),
+		purgerClient:        storage_v1.NewPurgerPluginClient(c),
+		otlpTraceClient:     ptraceotlp.NewGRPCClient(c),
 	}
 }
 
@@ -117,6 +125,11 @@ func (c *grpcClient) StreamingSpanWriter() spanstore.Writer {
 	return newStreamingSpanWriter(c.streamWriterClient)
 }
 
+// OTLPSpanWriter implements shared.OTLPSpanWriterPlugin.
+func (c *grpcClient) OTLPSpanWriter() spanstore.OTLPWriter {
+	return &otlpSpanWriter{client: c.otlpTraceClient}
+}
+
 func (c *grpcClient) ArchiveSpanReader() spanstore.Reader {
 	return &archiveReader{client: c.archiveReaderClient}
 }
@@ -125,11 +138,27 @@ func (c *grpcClient) ArchiveSpanWriter() spanstore.Writer {
 	return &archiveWriter{client: c.archiveWriterClient}
 }
 
+// Purger implements shared.PurgerPlugin.
+func (c *grpcClient) Purger() spanstore.Purger {
+	return &purger{client: c.purgerClient}
+}
+
 // GetTrace takes a traceID and returns a Trace associated with that traceID
 func (c *grpcClient) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
-	stream, err := c.readerClient.GetTrace(upgradeContext(ctx), &storage_v1.GetTraceRequest{
-		TraceID: traceID,
-	})
+	return c.GetTraceInTimeRange(ctx, spanstore.GetTraceParameters{TraceID: traceID})
+}
+
+// GetTraceInTimeRange implements spanstore.TimeRangeReader, passing on the time range
+// to the plugin, which ignores it if it does not support it.
+func (c *grpcClient) GetTraceInTimeRange(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	request := &storage_v1.GetTraceRequest{TraceID: query.TraceID}
+	if !query.StartTime.IsZero() {
+		request.StartTime = &query.StartTime
+	}
+	if !query.EndTime.IsZero() {
+		request.EndTime = &query.EndTime
+	}
+	stream, err := c.readerClient.GetTrace(upgradeContext(ctx), request)
 	if status.Code(err) == codes.NotFound {
 		return nil, spanstore.ErrTraceNotFound
 	}
@@ -140,6 +169,38 @@ func (c *grpcClient) GetTrace(ctx context.Context, traceID model.TraceID) (*mode
 	return readTrace(stream)
 }
 
+// StreamTrace implements spanstore.StreamingReader, passing on each chunk received from the plugin
+func (c *grpcClient) StreamTrace(ctx context.Context, traceID model.TraceID, yield func([]*model.Span) error) error {
+	stream, err := c.readerClient.GetTrace(upgradeContext(ctx), &storage_v1.GetTraceRequest{
+		TraceID: traceID,
+	})
+	if status.Code(err) == codes.NotFound {
+		return spanstore.ErrTraceNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("plugin error: %w", err)
+	}
+
+	for received, err := stream.Recv(); !errors.Is(err, io.EOF); received, err = stream.Recv() {
+		if err != nil {
+			if s, _ := status.FromError(err); s != nil {
+				if s.Message() == spanstore.ErrTraceNotFound.Error() {
+					return spanstore.ErrTraceNotFound
+				}
+			}
+			return fmt.Errorf("grpc stream error: %w", err)
+		}
+		spans := make([]*model.Span, len(received.Spans))
+		for i := range received.Spans {
+			spans[i] = &received.Spans[i]
+		}
+		if err := yield(spans); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetServices returns a list of all known services
 func (c *grpcClient) GetServices(ctx context.Context) ([]string, error) {
 	resp, err := c.readerClient.GetServices(upgradeContext(ctx), &storage_v1.GetServicesRequest{})
@@ -162,7 +223,37 @@ func (c *grpcClient) GetOperations(
 	if err != nil {
 		return nil, fmt.Errorf("plugin error: %w", err)
 	}
+	return operationsFromResponse(resp), nil
+}
+
+// FindOperations implements spanstore.OperationFilterReader. If the remote server does not
+// support filtering the operations, all operations of the service are filtered in memory.
+func (c *grpcClient) FindOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+) ([]spanstore.Operation, error) {
+	resp, err := c.readerClient.FindOperations(upgradeContext(ctx), &storage_v1.GetOperationsRequest{
+		Service:      query.ServiceName,
+		SpanKind:     query.SpanKind,
+		NamePrefix:   query.NamePrefix,
+		NameContains: query.NameContains,
+		Offset:       int32(query.Offset),
+		Limit:        int32(query.Limit),
+	})
+	if status.Code(err) == codes.Unimplemented {
+		operations, err := c.GetOperations(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return spanstore.FilterOperations(operations, query), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+	return operationsFromResponse(resp), nil
+}
 
+func operationsFromResponse(resp *storage_v1.GetOperationsResponse) []spanstore.Operation {
 	var operations []spanstore.Operation
 	if resp.Operations != nil {
 		for _, operation := range resp.Operations {
@@ -178,7 +269,7 @@ func (c *grpcClient) GetOperations(
 			})
 		}
 	}
-	return operations, nil
+	return operations
 }
 
 // FindTraces retrieves traces that match the traceQuery
@@ -199,26 +290,50 @@ func (c *grpcClient) FindTraces(ctx context.Context, query *spanstore.TraceQuery
 		return nil, fmt.Errorf("plugin error: %w", err)
 	}
 
-	var traces []*model.Trace
-	var trace *model.Trace
-	var traceID model.TraceID
-	for received, err := stream.Recv(); !errors.Is(err, io.EOF); received, err = stream.Recv() {
-		if err != nil {
-			return nil, fmt.Errorf("stream error: %w", err)
-		}
-
-		for i, span := range received.Spans {
-			if span.TraceID != traceID {
-				trace = &model.Trace{}
-				traceID = span.TraceID
-				traces = append(traces, trace)
-			}
-			trace.Spans = append(trace.Spans, &received.Spans[i])
-		}
+	traces, _, err := readTraces(stream)
+	if err != nil {
+		return nil, fmt.Errorf("stream error: %w", err)
 	}
 	return traces, nil
 }
 
+// FindTracesPage implements spanstore.PaginatedReader. If the remote server does not
+// support pagination, the page is built from FindTraces results by the start time of the
+// spans matching the query, which the remote storage searches by.
+func (c *grpcClient) FindTracesPage(ctx context.Context, query *spanstore.TraceQueryParameters) (*spanstore.TracesPage, error) {
+	stream, err := c.readerClient.FindTracesPage(upgradeContext(ctx), &storage_v1.FindTracesRequest{
+		Query: &storage_v1.TraceQueryParameters{
+			ServiceName:   query.ServiceName,
+			OperationName: query.OperationName,
+			Tags:          query.Tags,
+			StartTimeMin:  query.StartTimeMin,
+			StartTimeMax:  query.StartTimeMax,
+			DurationMin:   query.DurationMin,
+			DurationMax:   query.DurationMax,
+			NumTraces:     int32(query.NumTraces),
+			PageToken:     query.PageToken,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+
+	traces, nextPageToken, err := readTraces(stream)
+	if status.Code(err) == codes.Unimplemented {
+		return spanstore.PaginateBySortKey(ctx, c.FindTraces, query, spanstore.ByMatchingSpanStartTime)
+	}
+	if status.Code(err) == codes.InvalidArgument {
+		return nil, fmt.Errorf("%w: %s", spanstore.ErrInvalidPageToken, status.Convert(err).Message())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("stream error: %w", err)
+	}
+	return &spanstore.TracesPage{
+		Traces:        traces,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
 // FindTraceIDs retrieves traceIDs that match the traceQuery
 func (c *grpcClient) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
 	resp, err := c.readerClient.FindTraceIDs(upgradeContext(ctx), &storage_v1.FindTraceIDsRequest{
@@ -274,22 +389,73 @@ func (c *grpcClient) GetDependencies(ctx context.Context, endTs time.Time, lookb
 	return resp.Dependencies, nil
 }
 
+// Capabilities negotiates the version of the storage API and the optional features with the plugin.
+// The optional features are disabled for the plugins implementing an older version without negotiation.
 func (c *grpcClient) Capabilities() (*Capabilities, error) {
+	negotiated, err := c.capabilitiesClient.Negotiate(context.Background(), &storage_v1.NegotiateRequest{
+		Version:  StorageAPIVersion,
+		Features: knownFeatures,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return c.legacyCapabilities()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+	if err := checkCompatibleVersion(negotiated.Version); err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+	return capabilitiesFromFeatures(negotiated.Version, negotiated.Features), nil
+}
+
+func (c *grpcClient) legacyCapabilities() (*Capabilities, error) {
 	capabilities, err := c.capabilitiesClient.Capabilities(context.Background(), &storage_v1.CapabilitiesRequest{})
 	if status.Code(err) == codes.Unimplemented {
-		return &Capabilities{}, nil
+		return &Capabilities{Version: legacyStorageAPIVersion}, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("plugin error: %w", err)
 	}
 
 	return &Capabilities{
+		Version:             legacyStorageAPIVersion,
 		ArchiveSpanReader:   capabilities.ArchiveSpanReader,
 		ArchiveSpanWriter:   capabilities.ArchiveSpanWriter,
 		StreamingSpanWriter: capabilities.StreamingSpanWriter,
 	}, nil
 }
 
+// readTraces groups received spans into traces, relying on the server sending
+// spans of the same trace consecutively. It also returns the next page token if
+// the server included one in the stream.
+func readTraces(stream interface {
+	Recv() (*storage_v1.SpansResponseChunk, error)
+},
+) ([]*model.Trace, string, error) {
+	var traces []*model.Trace
+	var trace *model.Trace
+	var traceID model.TraceID
+	var nextPageToken string
+	for received, err := stream.Recv(); !errors.Is(err, io.EOF); received, err = stream.Recv() {
+		if err != nil {
+			return nil, "", err
+		}
+		if received.NextPageToken != "" {
+			nextPageToken = received.NextPageToken
+		}
+
+		for i, span := range received.Spans {
+			if span.TraceID != traceID {
+				trace = &model.Trace{}
+				traceID = span.TraceID
+				traces = append(traces, trace)
+			}
+			trace.Spans = append(trace.Spans, &received.Spans[i])
+		}
+	}
+	return traces, nextPageToken, nil
+}
+
 func readTrace(stream storage_v1.SpanReaderPlugin_GetTraceClient) (*model.Trace, error) {
 	trace := model.Trace{}
 	for received, err := stream.Recv(); !errors.Is(err, io.EOF); received, err = stream.Recv() {
@@ -309,3 +475,20 @@ func readTrace(stream storage_v1.SpanReaderPlugin_GetTraceClient) (*model.Trace,
 
 	return &trace, nil
 }
+
+// otlpSpanWriter sends the traces in the OTLP format to the OTLP trace service of the plugin.
+type otlpSpanWriter struct {
+	client ptraceotlp.GRPCClient
+}
+
+// WriteTraces implements spanstore.OTLPWriter.
+func (w *otlpSpanWriter) WriteTraces(ctx context.Context, td ptrace.Traces) error {
+	resp, err := w.client.Export(ctx, ptraceotlp.NewExportRequestFromTraces(td))
+	if err != nil {
+		return fmt.Errorf("plugin error: %w", err)
+	}
+	if rejected := resp.PartialSuccess().RejectedSpans(); rejected > 0 {
+		return fmt.Errorf("plugin error: %d spans rejected: %s", rejected, resp.PartialSuccess().ErrorMessage())
+	}
+	return nil
+}
@@ -18,14 +18,18 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
@@ -125,6 +129,9 @@ func TestNewGRPCClient(t *testing.T) {
 	assert.Implements(t, (*storage_v1.PluginCapabilitiesClient)(nil), client.capabilitiesClient)
 	assert.Implements(t, (*storage_v1.DependenciesReaderPluginClient)(nil), client.depsReaderClient)
 	assert.Implements(t, (*storage_v1.StreamingSpanWriterPluginClient)(nil), client.streamWriterClient)
+	assert.Implements(t, (*storage_v1.PurgerPluginClient)(nil), client.purgerClient)
+	assert.Equal(t, &purger{client: client.purgerClient}, client.Purger())
+	assert.Equal(t, &otlpSpanWriter{client: client.otlpTraceClient}, client.OTLPSpanWriter())
 }
 
 func TestContextUpgradeWithToken(t *testing.T) {
@@ -184,6 +191,60 @@ func TestGRPCClientGetOperationsV2(t *testing.T) {
 	})
 }
 
+func TestGRPCClientFindOperations(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.spanReader.On("FindOperations", mock.Anything, &storage_v1.GetOperationsRequest{
+			Service:      "service-a",
+			NamePrefix:   "operation",
+			NameContains: "a",
+			Offset:       1,
+			Limit:        2,
+		}).Return(&storage_v1.GetOperationsResponse{
+			Operations: []*storage_v1.Operation{{Name: "operation-a", SpanKind: "server"}},
+		}, nil)
+
+		s, err := r.client.FindOperations(context.Background(), spanstore.OperationQueryParameters{
+			ServiceName:  "service-a",
+			NamePrefix:   "operation",
+			NameContains: "a",
+			Offset:       1,
+			Limit:        2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{{Name: "operation-a", SpanKind: "server"}}, s)
+	})
+}
+
+func TestGRPCClientFindOperations_Unimplemented(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.spanReader.On("FindOperations", mock.Anything, mock.Anything).
+			Return(nil, status.Error(codes.Unimplemented, "method FindOperations not implemented"))
+		r.spanReader.On("GetOperations", mock.Anything, &storage_v1.GetOperationsRequest{
+			Service: "service-a",
+		}).Return(&storage_v1.GetOperationsResponse{
+			Operations: []*storage_v1.Operation{{Name: "operation-b"}, {Name: "operation-a"}, {Name: "other"}},
+		}, nil)
+
+		s, err := r.client.FindOperations(context.Background(), spanstore.OperationQueryParameters{
+			ServiceName: "service-a",
+			NamePrefix:  "operation",
+			Limit:       1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{{Name: "operation-a"}}, s)
+	})
+}
+
+func TestGRPCClientFindOperations_Error(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.spanReader.On("FindOperations", mock.Anything, mock.Anything).
+			Return(nil, status.Error(codes.Internal, "internal error"))
+
+		_, err := r.client.FindOperations(context.Background(), spanstore.OperationQueryParameters{ServiceName: "service-a", Limit: 1})
+		require.ErrorContains(t, err, "plugin error")
+	})
+}
+
 func TestGRPCClientGetTrace(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
 		traceClient := new(grpcMocks.SpanReaderPlugin_GetTraceClient)
@@ -208,6 +269,95 @@ func TestGRPCClientGetTrace(t *testing.T) {
 	})
 }
 
+func TestGRPCClientGetTraceInTimeRange(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		startTime := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+		traceClient := new(grpcMocks.SpanReaderPlugin_GetTraceClient)
+		traceClient.On("Recv").Return(&storage_v1.SpansResponseChunk{
+			Spans: mockTraceSpans,
+		}, nil).Once()
+		traceClient.On("Recv").Return(nil, io.EOF)
+		r.spanReader.On("GetTrace", mock.Anything, &storage_v1.GetTraceRequest{
+			TraceID:   mockTraceID,
+			StartTime: &startTime,
+		}).Return(traceClient, nil)
+
+		s, err := r.client.GetTraceInTimeRange(context.Background(), spanstore.GetTraceParameters{
+			TraceID:   mockTraceID,
+			StartTime: startTime,
+		})
+		require.NoError(t, err)
+		assert.Len(t, s.Spans, len(mockTraceSpans))
+	})
+}
+
+func TestGRPCClientStreamTrace(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		traceClient := new(grpcMocks.SpanReaderPlugin_GetTraceClient)
+		traceClient.On("Recv").Return(&storage_v1.SpansResponseChunk{
+			Spans: mockTraceSpans[:1],
+		}, nil).Once()
+		traceClient.On("Recv").Return(&storage_v1.SpansResponseChunk{
+			Spans: mockTraceSpans[1:],
+		}, nil).Once()
+		traceClient.On("Recv").Return(nil, io.EOF)
+		r.spanReader.On("GetTrace", mock.Anything, &storage_v1.GetTraceRequest{
+			TraceID: mockTraceID,
+		}).Return(traceClient, nil)
+
+		var batches [][]*model.Span
+		err := r.client.StreamTrace(context.Background(), mockTraceID, func(spans []*model.Span) error {
+			batches = append(batches, spans)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, batches, 2)
+		assert.Equal(t, []*model.Span{&mockTraceSpans[0]}, batches[0])
+		assert.Len(t, batches[1], len(mockTraceSpans)-1)
+	})
+}
+
+func TestGRPCClientStreamTrace_Errors(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		notFoundClient := new(grpcMocks.SpanReaderPlugin_GetTraceClient)
+		notFoundClient.On("Recv").Return(nil, status.Errorf(codes.NotFound, spanstore.ErrTraceNotFound.Error()))
+		r.spanReader.On("GetTrace", mock.Anything, &storage_v1.GetTraceRequest{
+			TraceID: mockTraceID,
+		}).Return(notFoundClient, nil).Once()
+		err := r.client.StreamTrace(context.Background(), mockTraceID, nil)
+		require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+		r.spanReader.On("GetTrace", mock.Anything, &storage_v1.GetTraceRequest{
+			TraceID: mockTraceID,
+		}).Return(nil, status.Error(codes.NotFound, "")).Once()
+		err = r.client.StreamTrace(context.Background(), mockTraceID, nil)
+		require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+		errorClient := new(grpcMocks.SpanReaderPlugin_GetTraceClient)
+		errorClient.On("Recv").Return(nil, errors.New("an error"))
+		r.spanReader.On("GetTrace", mock.Anything, &storage_v1.GetTraceRequest{
+			TraceID: mockTraceID,
+		}).Return(errorClient, nil).Once()
+		err = r.client.StreamTrace(context.Background(), mockTraceID, nil)
+		require.ErrorContains(t, err, "grpc stream error")
+
+		r.spanReader.On("GetTrace", mock.Anything, &storage_v1.GetTraceRequest{
+			TraceID: mockTraceID,
+		}).Return(nil, errors.New("an error")).Once()
+		err = r.client.StreamTrace(context.Background(), mockTraceID, nil)
+		require.ErrorContains(t, err, "plugin error")
+
+		yieldErr := errors.New("yield error")
+		traceClient := new(grpcMocks.SpanReaderPlugin_GetTraceClient)
+		traceClient.On("Recv").Return(&storage_v1.SpansResponseChunk{Spans: mockTraceSpans}, nil)
+		r.spanReader.On("GetTrace", mock.Anything, &storage_v1.GetTraceRequest{
+			TraceID: mockTraceID,
+		}).Return(traceClient, nil).Once()
+		err = r.client.StreamTrace(context.Background(), mockTraceID, func([]*model.Span) error { return yieldErr })
+		require.ErrorIs(t, err, yieldErr)
+	})
+}
+
 func TestGRPCClientGetTrace_StreamError(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
 		traceClient := new(grpcMocks.SpanReaderPlugin_GetTraceClient)
@@ -294,6 +444,61 @@ func TestGRPCClientFindTraces_RecvError(t *testing.T) {
 	})
 }
 
+func TestGRPCClientFindTracesPage(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		traceClient := new(grpcMocks.SpanReaderPlugin_FindTracesPageClient)
+		traceClient.On("Recv").Return(&storage_v1.SpansResponseChunk{
+			Spans: mockTracesSpans,
+		}, nil).Once()
+		traceClient.On("Recv").Return(&storage_v1.SpansResponseChunk{
+			NextPageToken: "next",
+		}, nil).Once()
+		traceClient.On("Recv").Return(nil, io.EOF)
+		r.spanReader.On("FindTracesPage", mock.Anything, &storage_v1.FindTracesRequest{
+			Query: &storage_v1.TraceQueryParameters{PageToken: "current"},
+		}).Return(traceClient, nil)
+
+		page, err := r.client.FindTracesPage(context.Background(), &spanstore.TraceQueryParameters{PageToken: "current"})
+		require.NoError(t, err)
+		assert.Len(t, page.Traces, 2)
+		assert.Equal(t, "next", page.NextPageToken)
+	})
+}
+
+func TestGRPCClientFindTracesPage_Unimplemented(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		pageClient := new(grpcMocks.SpanReaderPlugin_FindTracesPageClient)
+		pageClient.On("Recv").Return(nil, status.Error(codes.Unimplemented, "method not implemented"))
+		r.spanReader.On("FindTracesPage", mock.Anything, mock.Anything).Return(pageClient, nil)
+
+		traceClient := new(grpcMocks.SpanReaderPlugin_FindTracesClient)
+		traceClient.On("Recv").Return(&storage_v1.SpansResponseChunk{
+			Spans: mockTracesSpans,
+		}, nil).Once()
+		traceClient.On("Recv").Return(nil, io.EOF)
+		r.spanReader.On("FindTraces", mock.Anything, &storage_v1.FindTracesRequest{
+			Query: &storage_v1.TraceQueryParameters{},
+		}).Return(traceClient, nil)
+
+		page, err := r.client.FindTracesPage(context.Background(), &spanstore.TraceQueryParameters{})
+		require.NoError(t, err)
+		assert.Len(t, page.Traces, 2)
+		assert.Empty(t, page.NextPageToken)
+	})
+}
+
+func TestGRPCClientFindTracesPage_InvalidToken(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		pageClient := new(grpcMocks.SpanReaderPlugin_FindTracesPageClient)
+		pageClient.On("Recv").Return(nil, status.Error(codes.InvalidArgument, "invalid page token"))
+		r.spanReader.On("FindTracesPage", mock.Anything, mock.Anything).Return(pageClient, nil)
+
+		page, err := r.client.FindTracesPage(context.Background(), &spanstore.TraceQueryParameters{PageToken: "bad"})
+		require.ErrorIs(t, err, spanstore.ErrInvalidPageToken)
+		assert.Nil(t, page)
+	})
+}
+
 func TestGRPCClientFindTraceIDs(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
 		r.spanReader.On("FindTraceIDs", mock.Anything, &storage_v1.FindTraceIDsRequest{
@@ -457,12 +662,55 @@ func TestGrpcClientGetArchiveTrace_StreamErrorTraceNotFound(t *testing.T) {
 
 func TestGrpcClientCapabilities(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
+		r.capabilities.On("Negotiate", mock.Anything, &storage_v1.NegotiateRequest{Version: StorageAPIVersion, Features: knownFeatures}).
+			Return(&storage_v1.NegotiateResponse{
+				Version:  "1.2.0",
+				Features: []string{FeatureArchiveSpanReader, FeatureStreamingSpanWriter, "sampling_store"},
+			}, nil)
+
+		capabilities, err := r.client.Capabilities()
+		require.NoError(t, err)
+		assert.Equal(t, &Capabilities{
+			Version:             "1.2.0",
+			ArchiveSpanReader:   true,
+			StreamingSpanWriter: true,
+		}, capabilities)
+	})
+}
+
+func TestGrpcClientCapabilities_IncompatibleVersion(t *testing.T) {
+	for _, version := range []string{"2.0.0", "1.1", ""} {
+		withGRPCClient(func(r *grpcClientTest) {
+			r.capabilities.On("Negotiate", mock.Anything, mock.Anything).
+				Return(&storage_v1.NegotiateResponse{Version: version}, nil)
+
+			_, err := r.client.Capabilities()
+			require.ErrorContains(t, err, "storage API version")
+		})
+	}
+}
+
+func TestGrpcClientCapabilities_NegotiateError(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.capabilities.On("Negotiate", mock.Anything, mock.Anything).
+			Return(nil, status.Error(codes.FailedPrecondition, "incompatible storage API versions"))
+
+		_, err := r.client.Capabilities()
+		require.ErrorContains(t, err, "incompatible storage API versions")
+	})
+}
+
+func TestGrpcClientCapabilities_Legacy(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.capabilities.On("Negotiate", mock.Anything, mock.Anything).
+			Return(nil, status.Error(codes.Unimplemented, "method not found"))
 		r.capabilities.On("Capabilities", mock.Anything, &storage_v1.CapabilitiesRequest{}).
 			Return(&storage_v1.CapabilitiesResponse{ArchiveSpanReader: true, ArchiveSpanWriter: true, StreamingSpanWriter: true}, nil)
 
 		capabilities, err := r.client.Capabilities()
 		require.NoError(t, err)
 		assert.Equal(t, &Capabilities{
+			Version:             legacyStorageAPIVersion,
 			ArchiveSpanReader:   true,
 			ArchiveSpanWriter:   true,
 			StreamingSpanWriter: true,
@@ -472,12 +720,15 @@ func TestGrpcClientCapabilities(t *testing.T) {
 
 func TestGrpcClientCapabilities_NotSupported(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
+		r.capabilities.On("Negotiate", mock.Anything, mock.Anything).
+			Return(nil, status.Error(codes.Unimplemented, "method not found"))
 		r.capabilities.On("Capabilities", mock.Anything, &storage_v1.CapabilitiesRequest{}).
 			Return(&storage_v1.CapabilitiesResponse{}, nil)
 
 		capabilities, err := r.client.Capabilities()
 		require.NoError(t, err)
 		assert.Equal(t, &Capabilities{
+			Version:             legacyStorageAPIVersion,
 			ArchiveSpanReader:   false,
 			ArchiveSpanWriter:   false,
 			StreamingSpanWriter: false,
@@ -487,17 +738,21 @@ func TestGrpcClientCapabilities_NotSupported(t *testing.T) {
 
 func TestGrpcClientCapabilities_MissingMethod(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
+		r.capabilities.On("Negotiate", mock.Anything, mock.Anything).
+			Return(nil, status.Error(codes.Unimplemented, "method not found"))
 		r.capabilities.On("Capabilities", mock.Anything, &storage_v1.CapabilitiesRequest{}).
 			Return(nil, status.Error(codes.Unimplemented, "method not found"))
 
 		capabilities, err := r.client.Capabilities()
 		require.NoError(t, err)
-		assert.Equal(t, &Capabilities{}, capabilities)
+		assert.Equal(t, &Capabilities{Version: legacyStorageAPIVersion}, capabilities)
 	})
 }
 
 func TestGrpcClientArchiveSupported_CommonGrpcError(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
+		r.capabilities.On("Negotiate", mock.Anything, mock.Anything).
+			Return(nil, status.Error(codes.Unimplemented, "method not found"))
 		r.capabilities.On("Capabilities", mock.Anything, &storage_v1.CapabilitiesRequest{}).
 			Return(nil, status.Error(codes.Internal, "internal error"))
 
@@ -505,3 +760,54 @@ func TestGrpcClientArchiveSupported_CommonGrpcError(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+// partialSuccessServer rejects the spans of every request.
+type partialSuccessServer struct {
+	ptraceotlp.UnimplementedGRPCServer
+}
+
+func (*partialSuccessServer) Export(_ context.Context, r ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	resp := ptraceotlp.NewExportResponse()
+	resp.PartialSuccess().SetRejectedSpans(int64(r.Traces().SpanCount()))
+	resp.PartialSuccess().SetErrorMessage("invalid spans")
+	return resp, nil
+}
+
+// withOTLPSpanWriter runs fn with the OTLP span writer of a client connected to a server registered by register.
+func withOTLPSpanWriter(t *testing.T, register func(*grpc.Server), fn func(spanstore.OTLPWriter)) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	register(server)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	fn(NewGRPCClient(conn).OTLPSpanWriter())
+}
+
+func TestGrpcClientWriteTraces(t *testing.T) {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+
+	writer := &testOTLPWriter{}
+	handler := NewGRPCHandler(&GRPCHandlerStorageImpl{
+		OTLPSpanWriter: func() spanstore.OTLPWriter { return writer },
+	})
+	withOTLPSpanWriter(t, func(s *grpc.Server) { require.NoError(t, handler.Register(s)) }, func(w spanstore.OTLPWriter) {
+		require.NoError(t, w.WriteTraces(context.Background(), td))
+		require.Len(t, writer.traces, 1)
+		assert.Equal(t, "span", writer.traces[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+
+		writer.err = errors.New("storage error")
+		require.ErrorContains(t, w.WriteTraces(context.Background(), td), "plugin error: rpc error: code = Unknown desc = storage error")
+	})
+
+	withOTLPSpanWriter(t, func(s *grpc.Server) { ptraceotlp.RegisterGRPCServer(s, &partialSuccessServer{}) }, func(w spanstore.OTLPWriter) {
+		require.EqualError(t, w.WriteTraces(context.Background(), td), "plugin error: 1 spans rejected: invalid spans")
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -50,6 +51,12 @@ type GRPCHandlerStorageImpl struct {
 	ArchiveSpanWriter func() spanstore.Writer
 
 	StreamingSpanWriter func() spanstore.Writer
+
+	// Purger is optional, the purge RPCs are unimplemented if it is nil or returns nil.
+	Purger func() spanstore.Purger
+
+	// OTLPSpanWriter is optional, the OTLP trace service is unimplemented if it is nil or returns nil.
+	OTLPSpanWriter func() spanstore.OTLPWriter
 }
 
 // NewGRPCHandler creates a handler given individual storage implementations.
@@ -79,6 +86,12 @@ func NewGRPCHandlerWithPlugins(
 	if streamImpl != nil {
 		impl.StreamingSpanWriter = streamImpl.StreamingSpanWriter
 	}
+	if purgerImpl, ok := mainImpl.(PurgerPlugin); ok {
+		impl.Purger = purgerImpl.Purger
+	}
+	if otlpImpl, ok := mainImpl.(OTLPSpanWriterPlugin); ok {
+		impl.OTLPSpanWriter = otlpImpl.OTLPSpanWriter
+	}
 	return NewGRPCHandler(impl)
 }
 
@@ -91,6 +104,8 @@ func (s *GRPCHandler) Register(ss *grpc.Server) error {
 	storage_v1.RegisterPluginCapabilitiesServer(ss, s)
 	storage_v1.RegisterDependenciesReaderPluginServer(ss, s)
 	storage_v1.RegisterStreamingSpanWriterPluginServer(ss, s)
+	storage_v1.RegisterPurgerPluginServer(ss, s)
+	ptraceotlp.RegisterGRPCServer(ss, &otlpTraceServer{handler: s})
 	return nil
 }
 
@@ -148,22 +163,32 @@ func (s *GRPCHandler) Close(ctx context.Context, r *storage_v1.CloseWriterReques
 	}
 }
 
-// GetTrace takes a traceID and streams a Trace associated with that traceID
+// GetTrace takes a traceID and streams a Trace associated with that traceID,
+// restricting the search to the time range of the request if it has one.
 func (s *GRPCHandler) GetTrace(r *storage_v1.GetTraceRequest, stream storage_v1.SpanReaderPlugin_GetTraceServer) error {
-	trace, err := s.impl.SpanReader().GetTrace(stream.Context(), r.TraceID)
+	var err error
+	if r.StartTime == nil && r.EndTime == nil {
+		err = spanstore.StreamTrace(stream.Context(), s.impl.SpanReader(), r.TraceID, spanBatchSize, func(spans []*model.Span) error {
+			return s.sendSpans(spans, stream.Send)
+		})
+	} else {
+		query := spanstore.GetTraceParameters{TraceID: r.TraceID}
+		if r.StartTime != nil {
+			query.StartTime = *r.StartTime
+		}
+		if r.EndTime != nil {
+			query.EndTime = *r.EndTime
+		}
+		var trace *model.Trace
+		trace, err = spanstore.GetTraceInTimeRange(stream.Context(), s.impl.SpanReader(), query)
+		if err == nil {
+			err = s.sendSpans(trace.Spans, stream.Send)
+		}
+	}
 	if errors.Is(err, spanstore.ErrTraceNotFound) {
 		return status.Errorf(codes.NotFound, spanstore.ErrTraceNotFound.Error())
 	}
-	if err != nil {
-		return err
-	}
-
-	err = s.sendSpans(trace.Spans, stream.Send)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return err
 }
 
 // GetServices returns a list of all known services
@@ -189,6 +214,29 @@ func (s *GRPCHandler) GetOperations(
 	if err != nil {
 		return nil, err
 	}
+	return operationsResponse(operations), nil
+}
+
+// FindOperations returns the operations of a given service selected by the name filters and the page of the request
+func (s *GRPCHandler) FindOperations(
+	ctx context.Context,
+	r *storage_v1.GetOperationsRequest,
+) (*storage_v1.GetOperationsResponse, error) {
+	operations, err := spanstore.FindOperations(ctx, s.impl.SpanReader(), spanstore.OperationQueryParameters{
+		ServiceName:  r.Service,
+		SpanKind:     r.SpanKind,
+		NamePrefix:   r.NamePrefix,
+		NameContains: r.NameContains,
+		Offset:       int(r.Offset),
+		Limit:        int(r.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return operationsResponse(operations), nil
+}
+
+func operationsResponse(operations []spanstore.Operation) *storage_v1.GetOperationsResponse {
 	grpcOperation := make([]*storage_v1.Operation, len(operations))
 	for i, operation := range operations {
 		grpcOperation[i] = &storage_v1.Operation{
@@ -198,7 +246,7 @@ func (s *GRPCHandler) GetOperations(
 	}
 	return &storage_v1.GetOperationsResponse{
 		Operations: grpcOperation,
-	}, nil
+	}
 }
 
 // FindTraces streams traces that match the traceQuery
@@ -247,6 +295,42 @@ func (s *GRPCHandler) FindTraceIDs(ctx context.Context, r *storage_v1.FindTraceI
 	}, nil
 }
 
+// FindTracesPage streams a page of traces that match the query, followed by
+// a chunk carrying the token of the next page if there are more results.
+func (s *GRPCHandler) FindTracesPage(r *storage_v1.FindTracesRequest, stream storage_v1.SpanReaderPlugin_FindTracesPageServer) error {
+	page, err := spanstore.FindTracesPage(stream.Context(), s.impl.SpanReader(), &spanstore.TraceQueryParameters{
+		ServiceName:   r.Query.ServiceName,
+		OperationName: r.Query.OperationName,
+		Tags:          r.Query.Tags,
+		StartTimeMin:  r.Query.StartTimeMin,
+		StartTimeMax:  r.Query.StartTimeMax,
+		DurationMin:   r.Query.DurationMin,
+		DurationMax:   r.Query.DurationMax,
+		NumTraces:     int(r.Query.NumTraces),
+		PageToken:     r.Query.PageToken,
+	})
+	if errors.Is(err, spanstore.ErrInvalidPageToken) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, trace := range page.Traces {
+		err = s.sendSpans(trace.Spans, stream.Send)
+		if err != nil {
+			return err
+		}
+	}
+	if page.NextPageToken != "" {
+		if err := stream.Send(&storage_v1.SpansResponseChunk{NextPageToken: page.NextPageToken}); err != nil {
+			return fmt.Errorf("grpc plugin failed to send response: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (s *GRPCHandler) sendSpans(spans []*model.Span, sendFn func(*storage_v1.SpansResponseChunk) error) error {
 	chunk := make([]model.Span, 0, len(spans))
 	for i := 0; i < len(spans); i += spanBatchSize {
@@ -270,6 +354,27 @@ func (s *GRPCHandler) Capabilities(ctx context.Context, request *storage_v1.Capa
 	}, nil
 }
 
+// Negotiate returns the version of the storage API and the optional features supported by the plugin.
+func (s *GRPCHandler) Negotiate(ctx context.Context, request *storage_v1.NegotiateRequest) (*storage_v1.NegotiateResponse, error) {
+	if err := checkCompatibleVersion(request.Version); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	capabilities, err := s.Capabilities(ctx, &storage_v1.CapabilitiesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.NegotiateResponse{
+		Version: StorageAPIVersion,
+		Features: (&Capabilities{
+			ArchiveSpanReader:   capabilities.ArchiveSpanReader,
+			ArchiveSpanWriter:   capabilities.ArchiveSpanWriter,
+			StreamingSpanWriter: capabilities.StreamingSpanWriter,
+			Purger:              s.purger() != nil,
+			OTLPSpanWriter:      s.otlpSpanWriter() != nil,
+		}).Features(),
+	}, nil
+}
+
 func (s *GRPCHandler) GetArchiveTrace(r *storage_v1.GetTraceRequest, stream storage_v1.ArchiveSpanReaderPlugin_GetArchiveTraceServer) error {
 	reader := s.impl.ArchiveSpanReader()
 	if reader == nil {
@@ -302,3 +407,76 @@ func (s *GRPCHandler) WriteArchiveSpan(ctx context.Context, r *storage_v1.WriteS
 	}
 	return &storage_v1.WriteSpanResponse{}, nil
 }
+
+func (s *GRPCHandler) purger() spanstore.Purger {
+	if s.impl.Purger == nil {
+		return nil
+	}
+	return s.impl.Purger()
+}
+
+// PurgeTraces deletes the traces matching the query
+func (s *GRPCHandler) PurgeTraces(ctx context.Context, r *storage_v1.PurgeTracesRequest) (*storage_v1.PurgeTracesResponse, error) {
+	purger := s.purger()
+	if purger == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	query := &spanstore.TraceQueryParameters{}
+	if r.Query != nil {
+		query = &spanstore.TraceQueryParameters{
+			ServiceName:   r.Query.ServiceName,
+			OperationName: r.Query.OperationName,
+			Tags:          r.Query.Tags,
+			StartTimeMin:  r.Query.StartTimeMin,
+			StartTimeMax:  r.Query.StartTimeMax,
+			DurationMin:   r.Query.DurationMin,
+			DurationMax:   r.Query.DurationMax,
+		}
+	}
+	purged, err := purger.PurgeTraces(ctx, query)
+	if errors.Is(err, spanstore.ErrPurgeQueryServiceRequired) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.PurgeTracesResponse{PurgedTraces: int64(purged)}, nil
+}
+
+// PurgeAll deletes all the traces
+func (s *GRPCHandler) PurgeAll(ctx context.Context, r *storage_v1.PurgeAllRequest) (*storage_v1.PurgeAllResponse, error) {
+	purger := s.purger()
+	if purger == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	if err := purger.PurgeAll(ctx); err != nil {
+		return nil, err
+	}
+	return &storage_v1.PurgeAllResponse{}, nil
+}
+
+func (s *GRPCHandler) otlpSpanWriter() spanstore.OTLPWriter {
+	if s.impl.OTLPSpanWriter == nil {
+		return nil
+	}
+	return s.impl.OTLPSpanWriter()
+}
+
+// otlpTraceServer serves the OTLP trace service with the OTLP span writer of the handler,
+// so that the clients can send the traces without converting them to the Jaeger model.
+type otlpTraceServer struct {
+	ptraceotlp.UnimplementedGRPCServer
+	handler *GRPCHandler
+}
+
+// Export writes the traces of the request.
+func (s *otlpTraceServer) Export(ctx context.Context, r ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	writer := s.handler.otlpSpanWriter()
+	if writer == nil {
+		return ptraceotlp.NewExportResponse(), status.Error(codes.Unimplemented, "not implemented")
+	}
+	if err := writer.WriteTraces(ctx, r.Traces()); err != nil {
+		return ptraceotlp.NewExportResponse(), err
+	}
+	return ptraceotlp.NewExportResponse(), nil
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -43,6 +45,18 @@ type mockStoragePlugin struct {
 	archiveWriter *spanStoreMocks.Writer
 	depsReader    *dependencyStoreMocks.Reader
 	streamWriter  *spanStoreMocks.Writer
+	purger        *spanStoreMocks.Purger
+	otlpWriter    spanstore.OTLPWriter
+}
+
+type testOTLPWriter struct {
+	traces []ptrace.Traces
+	err    error
+}
+
+func (w *testOTLPWriter) WriteTraces(_ context.Context, td ptrace.Traces) error {
+	w.traces = append(w.traces, td)
+	return w.err
 }
 
 func (plugin *mockStoragePlugin) ArchiveSpanReader() spanstore.Reader {
@@ -69,6 +83,17 @@ func (plugin *mockStoragePlugin) StreamingSpanWriter() spanstore.Writer {
 	return plugin.streamWriter
 }
 
+func (plugin *mockStoragePlugin) Purger() spanstore.Purger {
+	if plugin.purger == nil {
+		return nil
+	}
+	return plugin.purger
+}
+
+func (plugin *mockStoragePlugin) OTLPSpanWriter() spanstore.OTLPWriter {
+	return plugin.otlpWriter
+}
+
 type grpcServerTest struct {
 	server *GRPCHandler
 	impl   *mockStoragePlugin
@@ -81,6 +106,7 @@ func withGRPCServer(fn func(r *grpcServerTest)) {
 	archiveWriter := new(spanStoreMocks.Writer)
 	depReader := new(dependencyStoreMocks.Reader)
 	streamWriter := new(spanStoreMocks.Writer)
+	purger := new(spanStoreMocks.Purger)
 
 	impl := &mockStoragePlugin{
 		spanReader:    spanReader,
@@ -89,6 +115,7 @@ func withGRPCServer(fn func(r *grpcServerTest)) {
 		archiveWriter: archiveWriter,
 		depsReader:    depReader,
 		streamWriter:  streamWriter,
+		purger:        purger,
 	}
 
 	handler := NewGRPCHandlerWithPlugins(impl, impl, impl)
@@ -136,6 +163,29 @@ func TestGRPCServerGetOperations(t *testing.T) {
 	})
 }
 
+func TestGRPCServerFindOperations(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		r.impl.spanReader.On("GetOperations",
+			mock.Anything,
+			spanstore.OperationQueryParameters{ServiceName: "service-a"}).
+			Return([]spanstore.Operation{
+				{Name: "operation-c", SpanKind: "client"},
+				{Name: "operation-a", SpanKind: "server"},
+				{Name: "operation-b", SpanKind: "client"},
+				{Name: "other", SpanKind: "client"},
+			}, nil)
+
+		resp, err := r.server.FindOperations(context.Background(), &storage_v1.GetOperationsRequest{
+			Service:    "service-a",
+			NamePrefix: "operation",
+			Offset:     1,
+			Limit:      1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*storage_v1.Operation{{Name: "operation-b", SpanKind: "client"}}, resp.Operations)
+	})
+}
+
 func TestGRPCServerGetTrace(t *testing.T) {
 	withGRPCServer(func(r *grpcServerTest) {
 		traceSteam := new(grpcMocks.SpanReaderPlugin_GetTraceServer)
@@ -157,6 +207,34 @@ func TestGRPCServerGetTrace(t *testing.T) {
 	})
 }
 
+func TestGRPCServerGetTraceInTimeRange(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		traceSteam := new(grpcMocks.SpanReaderPlugin_GetTraceServer)
+		traceSteam.On("Context").Return(context.Background())
+		traceSteam.On("Send", &storage_v1.SpansResponseChunk{Spans: mockTraceSpans}).
+			Return(nil)
+
+		var traceSpans []*model.Span
+		for i := range mockTraceSpans {
+			traceSpans = append(traceSpans, &mockTraceSpans[i])
+		}
+		r.impl.spanReader.On("GetTrace", mock.Anything, mockTraceID).
+			Return(&model.Trace{Spans: traceSpans}, nil).Once()
+		r.impl.spanReader.On("GetTrace", mock.Anything, mockTraceID).
+			Return(nil, spanstore.ErrTraceNotFound).Once()
+
+		startTime, endTime := time.Unix(1000, 0), time.Unix(2000, 0)
+		request := &storage_v1.GetTraceRequest{
+			TraceID:   mockTraceID,
+			StartTime: &startTime,
+			EndTime:   &endTime,
+		}
+		require.NoError(t, r.server.GetTrace(request, traceSteam))
+		err := r.server.GetTrace(request, traceSteam)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
 func TestGRPCServerGetTrace_NotFound(t *testing.T) {
 	withGRPCServer(func(r *grpcServerTest) {
 		traceSteam := new(grpcMocks.SpanReaderPlugin_GetTraceServer)
@@ -203,6 +281,43 @@ func TestGRPCServerFindTraces(t *testing.T) {
 	})
 }
 
+func TestGRPCServerFindTracesPage(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		traceSteam := new(grpcMocks.SpanReaderPlugin_FindTracesPageServer)
+		traceSteam.On("Context").Return(context.Background())
+		traceSteam.On("Send", &storage_v1.SpansResponseChunk{Spans: mockTracesSpans[:2]}).
+			Return(nil).Once()
+		traceSteam.On("Send", mock.MatchedBy(func(chunk *storage_v1.SpansResponseChunk) bool {
+			return len(chunk.Spans) == 0 && chunk.NextPageToken != ""
+		})).Return(nil).Once()
+
+		traces := []*model.Trace{
+			{Spans: []*model.Span{&mockTracesSpans[0], &mockTracesSpans[1]}},
+			{Spans: []*model.Span{&mockTracesSpans[2]}},
+		}
+		r.impl.spanReader.On("FindTraces", mock.Anything, &spanstore.TraceQueryParameters{NumTraces: 2}).
+			Return(traces, nil)
+
+		err := r.server.FindTracesPage(&storage_v1.FindTracesRequest{
+			Query: &storage_v1.TraceQueryParameters{NumTraces: 1},
+		}, traceSteam)
+		require.NoError(t, err)
+		traceSteam.AssertExpectations(t)
+	})
+}
+
+func TestGRPCServerFindTracesPage_InvalidToken(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		traceSteam := new(grpcMocks.SpanReaderPlugin_FindTracesPageServer)
+		traceSteam.On("Context").Return(context.Background())
+
+		err := r.server.FindTracesPage(&storage_v1.FindTracesRequest{
+			Query: &storage_v1.TraceQueryParameters{PageToken: "!!!"},
+		}, traceSteam)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
 func TestGRPCServerFindTraceIDs(t *testing.T) {
 	withGRPCServer(func(r *grpcServerTest) {
 		r.impl.spanReader.On("FindTraceIDs", mock.Anything, &spanstore.TraceQueryParameters{}).
@@ -445,6 +560,106 @@ func TestGRPCServerCapabilities_NoStreamWriter(t *testing.T) {
 	})
 }
 
+func TestGRPCServerNegotiate(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		r.server.impl.ArchiveSpanWriter = func() spanstore.Writer { return nil }
+
+		negotiated, err := r.server.Negotiate(context.Background(), &storage_v1.NegotiateRequest{Version: "1.0.0"})
+		require.NoError(t, err)
+		expected := &storage_v1.NegotiateResponse{
+			Version:  StorageAPIVersion,
+			Features: []string{FeatureArchiveSpanReader, FeatureStreamingSpanWriter, FeaturePurger},
+		}
+		assert.Equal(t, expected, negotiated)
+	})
+}
+
+func TestGRPCServerNegotiate_OTLPSpanWriter(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		r.impl.otlpWriter = &testOTLPWriter{}
+
+		negotiated, err := r.server.Negotiate(context.Background(), &storage_v1.NegotiateRequest{Version: StorageAPIVersion})
+		require.NoError(t, err)
+		assert.Contains(t, negotiated.Features, FeatureOTLPSpanWriter)
+	})
+}
+
+func TestGRPCServerExport(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		writer := &testOTLPWriter{}
+		r.impl.otlpWriter = writer
+		server := &otlpTraceServer{handler: r.server}
+
+		td := ptrace.NewTraces()
+		td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+		_, err := server.Export(context.Background(), ptraceotlp.NewExportRequestFromTraces(td))
+		require.NoError(t, err)
+		assert.Equal(t, []ptrace.Traces{td}, writer.traces)
+
+		writer.err = assert.AnError
+		_, err = server.Export(context.Background(), ptraceotlp.NewExportRequestFromTraces(td))
+		require.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestGRPCServerExport_Unimplemented(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		server := &otlpTraceServer{handler: r.server}
+		_, err := server.Export(context.Background(), ptraceotlp.NewExportRequest())
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
+func TestGRPCServerPurgeTraces(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		query := &spanstore.TraceQueryParameters{ServiceName: "frontend", Tags: map[string]string{"user.id": "42"}}
+		r.impl.purger.On("PurgeTraces", mock.Anything, query).Return(2, nil)
+		r.impl.purger.On("PurgeTraces", mock.Anything, &spanstore.TraceQueryParameters{}).
+			Return(0, spanstore.ErrPurgeQueryServiceRequired)
+
+		resp, err := r.server.PurgeTraces(context.Background(), &storage_v1.PurgeTracesRequest{
+			Query: &storage_v1.TraceQueryParameters{ServiceName: "frontend", Tags: map[string]string{"user.id": "42"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &storage_v1.PurgeTracesResponse{PurgedTraces: 2}, resp)
+
+		_, err = r.server.PurgeTraces(context.Background(), &storage_v1.PurgeTracesRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGRPCServerPurgeAll(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		r.impl.purger.On("PurgeAll", mock.Anything).Return(nil).Once()
+		r.impl.purger.On("PurgeAll", mock.Anything).Return(assert.AnError).Once()
+
+		resp, err := r.server.PurgeAll(context.Background(), &storage_v1.PurgeAllRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, &storage_v1.PurgeAllResponse{}, resp)
+
+		_, err = r.server.PurgeAll(context.Background(), &storage_v1.PurgeAllRequest{})
+		require.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestGRPCServerPurge_NoImpl(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		r.server.impl.Purger = nil
+
+		_, err := r.server.PurgeTraces(context.Background(), &storage_v1.PurgeTracesRequest{})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+		_, err = r.server.PurgeAll(context.Background(), &storage_v1.PurgeAllRequest{})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
+func TestGRPCServerNegotiate_IncompatibleVersion(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		_, err := r.server.Negotiate(context.Background(), &storage_v1.NegotiateRequest{Version: "2.0.0"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
 func TestNewGRPCHandlerWithPlugins_Nils(t *testing.T) {
 	spanReader := new(spanStoreMocks.Reader)
 	spanWriter := new(spanStoreMocks.Writer)
@@ -460,4 +675,6 @@ func TestNewGRPCHandlerWithPlugins_Nils(t *testing.T) {
 	assert.Nil(t, handler.impl.ArchiveSpanReader())
 	assert.Nil(t, handler.impl.ArchiveSpanWriter())
 	assert.Nil(t, handler.impl.StreamingSpanWriter())
+	assert.Nil(t, handler.purger())
+	assert.Nil(t, handler.otlpSpanWriter())
 }
@@ -53,6 +53,17 @@ type StreamingSpanWriterPlugin interface {
 	StreamingSpanWriter() spanstore.Writer
 }
 
+// PurgerPlugin is the optional interface of the StoragePlugin deleting the traces.
+type PurgerPlugin interface {
+	Purger() spanstore.Purger
+}
+
+// OTLPSpanWriterPlugin is the optional interface of the StoragePlugin writing the traces
+// in the OTLP format, without converting them to the Jaeger model.
+type OTLPSpanWriterPlugin interface {
+	OTLPSpanWriter() spanstore.OTLPWriter
+}
+
 // PluginCapabilities allow expose plugin its capabilities.
 type PluginCapabilities interface {
 	Capabilities() (*Capabilities, error)
@@ -60,9 +71,14 @@ type PluginCapabilities interface {
 
 // Capabilities contains information about plugin capabilities
 type Capabilities struct {
+	// Version is the semantic version of the storage API implemented by the plugin.
+	Version             string
 	ArchiveSpanReader   bool
 	ArchiveSpanWriter   bool
 	StreamingSpanWriter bool
+	Purger              bool
+	// OTLPSpanWriter is set when the plugin prefers the traces in the OTLP format.
+	OTLPSpanWriter bool
 }
 
 // PluginServices defines services plugin can expose
@@ -70,4 +86,5 @@ type PluginServices struct {
 	Store               StoragePlugin
 	ArchiveStore        ArchiveStoragePlugin
 	StreamingSpanWriter StreamingSpanWriterPlugin
+	OTLPSpanWriter      OTLPSpanWriterPlugin
 }
@@ -0,0 +1,49 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package shared
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+var _ spanstore.Purger = (*purger)(nil)
+
+// purger wraps storage_v1.PurgerPluginClient into spanstore.Purger
+type purger struct {
+	client storage_v1.PurgerPluginClient
+}
+
+// PurgeTraces deletes the traces matching the query from the plugin storage
+func (p *purger) PurgeTraces(ctx context.Context, query *spanstore.TraceQueryParameters) (int, error) {
+	if err := spanstore.ValidatePurgeQuery(query); err != nil {
+		return 0, err
+	}
+	resp, err := p.client.PurgeTraces(upgradeContext(ctx), &storage_v1.PurgeTracesRequest{
+		Query: &storage_v1.TraceQueryParameters{
+			ServiceName:   query.ServiceName,
+			OperationName: query.OperationName,
+			Tags:          query.Tags,
+			StartTimeMin:  query.StartTimeMin,
+			StartTimeMax:  query.StartTimeMax,
+			DurationMin:   query.DurationMin,
+			DurationMax:   query.DurationMax,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("plugin error: %w", err)
+	}
+	return int(resp.PurgedTraces), nil
+}
+
+// PurgeAll deletes all the traces from the plugin storage
+func (p *purger) PurgeAll(ctx context.Context) error {
+	if _, err := p.client.PurgeAll(upgradeContext(ctx), &storage_v1.PurgeAllRequest{}); err != nil {
+		return fmt.Errorf("plugin error: %w", err)
+	}
+	return nil
+}
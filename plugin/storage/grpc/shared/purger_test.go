@@ -0,0 +1,47 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package shared
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+func TestPurger_PurgeTraces(t *testing.T) {
+	purgerClient := new(mocks.PurgerPluginClient)
+	purgerClient.On("PurgeTraces", mock.Anything, &storage_v1.PurgeTracesRequest{
+		Query: &storage_v1.TraceQueryParameters{ServiceName: "frontend", Tags: map[string]string{"user.id": "42"}},
+	}).Return(&storage_v1.PurgeTracesResponse{PurgedTraces: 2}, nil).Once()
+	purgerClient.On("PurgeTraces", mock.Anything, mock.Anything).Return(nil, assert.AnError).Once()
+	p := &purger{client: purgerClient}
+
+	_, err := p.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{})
+	require.ErrorIs(t, err, spanstore.ErrPurgeQueryServiceRequired)
+
+	query := &spanstore.TraceQueryParameters{ServiceName: "frontend", Tags: map[string]string{"user.id": "42"}, NumTraces: 10}
+	purged, err := p.PurgeTraces(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+
+	_, err = p.PurgeTraces(context.Background(), query)
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+func TestPurger_PurgeAll(t *testing.T) {
+	purgerClient := new(mocks.PurgerPluginClient)
+	purgerClient.On("PurgeAll", mock.Anything, &storage_v1.PurgeAllRequest{}).Return(&storage_v1.PurgeAllResponse{}, nil).Once()
+	purgerClient.On("PurgeAll", mock.Anything, &storage_v1.PurgeAllRequest{}).Return(nil, assert.AnError).Once()
+	p := &purger{client: purgerClient}
+
+	require.NoError(t, p.PurgeAll(context.Background()))
+	require.ErrorIs(t, p.PurgeAll(context.Background()), assert.AnError)
+}
@@ -39,7 +39,7 @@ func StartNewRemoteMemoryStorage(t *testing.T, logger *zap.Logger) *RemoteMemory
 	storageFactory.InitFromViper(v, logger)
 	require.NoError(t, storageFactory.Initialize(metrics.NullFactory, logger))
 
-	server, err := app.NewServer(opts, storageFactory, tm, logger, healthcheck.New())
+	server, err := app.NewServer(opts, storageFactory, tm, metrics.NullFactory, logger, healthcheck.New())
 	require.NoError(t, err)
 	require.NoError(t, server.Start())
 
@@ -20,6 +20,7 @@ import (
 	"io"
 
 	"github.com/Shopify/sarama"
+	gometrics "github.com/rcrowley/go-metrics"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
@@ -44,8 +45,9 @@ type Factory struct {
 	metricsFactory metrics.Factory
 	logger         *zap.Logger
 
-	producer   sarama.AsyncProducer
-	marshaller Marshaller
+	producer        sarama.AsyncProducer
+	producerMetrics *producerMetrics
+	marshaller      Marshaller
 	producer.Builder
 }
 
@@ -85,11 +87,15 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	default:
 		return errors.New("kafka encoding is not one of '" + EncodingJSON + "' or '" + EncodingProto + "'")
 	}
+	registry := gometrics.NewRegistry()
+	f.options.Config.MetricRegistry = registry
 	p, err := f.NewProducer(logger)
 	if err != nil {
 		return err
 	}
 	f.producer = p
+	f.producerMetrics = newProducerMetrics(registry, metricsFactory)
+	f.producerMetrics.start(producerMetricsUpdateInterval)
 	return nil
 }
 
@@ -113,6 +119,9 @@ var _ io.Closer = (*Factory)(nil)
 // Close closes the resources held by the factory
 func (f *Factory) Close() error {
 	var errs []error
+	if f.producerMetrics != nil {
+		f.producerMetrics.stop()
+	}
 	if f.producer != nil {
 		errs = append(errs, f.producer.Close())
 	}
@@ -59,6 +59,8 @@ func TestKafkaFactory(t *testing.T) {
 	f.Builder = &mockProducerBuilder{t: t}
 	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
 	assert.IsType(t, &protobufMarshaller{}, f.marshaller)
+	assert.NotNil(t, f.options.Config.MetricRegistry)
+	assert.NotNil(t, f.producerMetrics)
 
 	_, err := f.CreateSpanWriter()
 	require.NoError(t, err)
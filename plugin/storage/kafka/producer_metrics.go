@@ -0,0 +1,88 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"time"
+
+	gometrics "github.com/rcrowley/go-metrics"
+
+	"github.com/jaegertracing/jaeger/pkg/metrics"
+)
+
+// producerMetricsUpdateInterval is the interval at which the metrics recorded by the producer are reported.
+const producerMetricsUpdateInterval = 15 * time.Second
+
+// producerGauges are the metrics of the producer reported by producerMetrics.
+type producerGauges struct {
+	BatchSizeMean     metrics.Gauge `metric:"kafka_producer_batch_size_bytes" tags:"stat=mean" help:"Mean size of the compressed batches of spans sent per partition per request"`
+	BatchSizeP99      metrics.Gauge `metric:"kafka_producer_batch_size_bytes" tags:"stat=p99" help:"99th percentile of the size of the compressed batches of spans sent per partition per request"`
+	CompressionRatio  metrics.Gauge `metric:"kafka_producer_compression_ratio_percent" tags:"stat=mean" help:"Mean ratio, times 100, of the uncompressed to the compressed size of the batches"`
+	RecordsPerRequest metrics.Gauge `metric:"kafka_producer_records_per_request" tags:"stat=mean" help:"Mean number of spans sent per request"`
+	RequestSizeMean   metrics.Gauge `metric:"kafka_producer_request_size_bytes" tags:"stat=mean" help:"Mean size of the requests sent to the brokers"`
+}
+
+// producerMetrics reports the metrics recorded by the sarama producer in its registry,
+// so that the effect of the compression and batching options on the size of the requests
+// sent to the brokers can be observed.
+type producerMetrics struct {
+	gauges   producerGauges
+	registry gometrics.Registry
+	done     chan struct{}
+}
+
+func newProducerMetrics(registry gometrics.Registry, factory metrics.Factory) *producerMetrics {
+	m := &producerMetrics{
+		registry: registry,
+		done:     make(chan struct{}),
+	}
+	metrics.MustInit(&m.gauges, factory, nil)
+	return m
+}
+
+// start reports the metrics at every interval until stop is called.
+func (m *producerMetrics) start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.done:
+				return
+			case <-ticker.C:
+				m.update()
+			}
+		}
+	}()
+}
+
+func (m *producerMetrics) stop() {
+	close(m.done)
+}
+
+// update reports the current values of the histograms of the registry.
+// The histograms are registered by the producer when it sends the first request.
+func (m *producerMetrics) update() {
+	if h := m.histogram("batch-size"); h != nil {
+		m.gauges.BatchSizeMean.Update(int64(h.Mean()))
+		m.gauges.BatchSizeP99.Update(int64(h.Percentile(0.99)))
+	}
+	if h := m.histogram("compression-ratio"); h != nil {
+		m.gauges.CompressionRatio.Update(int64(h.Mean()))
+	}
+	if h := m.histogram("records-per-request"); h != nil {
+		m.gauges.RecordsPerRequest.Update(int64(h.Mean()))
+	}
+	if h := m.histogram("request-size"); h != nil {
+		m.gauges.RequestSizeMean.Update(int64(h.Mean()))
+	}
+}
+
+func (m *producerMetrics) histogram(name string) gometrics.Histogram {
+	h, ok := m.registry.Get(name).(gometrics.Histogram)
+	if !ok || h.Count() == 0 {
+		return nil
+	}
+	return h.Snapshot()
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	gometrics "github.com/rcrowley/go-metrics"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+)
+
+func TestProducerMetricsUpdate(t *testing.T) {
+	registry := gometrics.NewRegistry()
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	m := newProducerMetrics(registry, metricsFactory)
+
+	// no request sent yet
+	m.update()
+	metricsFactory.AssertGaugeMetrics(t, metricstest.ExpectedMetric{
+		Name: "kafka_producer_batch_size_bytes", Tags: map[string]string{"stat": "mean"}, Value: 0,
+	})
+
+	update := func(name string, values ...int64) {
+		h := gometrics.GetOrRegisterHistogram(name, registry, gometrics.NewUniformSample(100))
+		for _, v := range values {
+			h.Update(v)
+		}
+	}
+	update("batch-size", 1000, 3000)
+	update("compression-ratio", 400, 600)
+	update("records-per-request", 10, 30)
+	update("request-size", 2000, 4000)
+	m.update()
+	metricsFactory.AssertGaugeMetrics(t,
+		metricstest.ExpectedMetric{Name: "kafka_producer_batch_size_bytes", Tags: map[string]string{"stat": "mean"}, Value: 2000},
+		metricstest.ExpectedMetric{Name: "kafka_producer_batch_size_bytes", Tags: map[string]string{"stat": "p99"}, Value: 3000},
+		metricstest.ExpectedMetric{Name: "kafka_producer_compression_ratio_percent", Tags: map[string]string{"stat": "mean"}, Value: 500},
+		metricstest.ExpectedMetric{Name: "kafka_producer_records_per_request", Tags: map[string]string{"stat": "mean"}, Value: 20},
+		metricstest.ExpectedMetric{Name: "kafka_producer_request_size_bytes", Tags: map[string]string{"stat": "mean"}, Value: 3000},
+	)
+}
+
+func TestProducerMetricsStartStop(t *testing.T) {
+	registry := gometrics.NewRegistry()
+	metricsFactory := metricstest.NewFactory(0)
+	defer metricsFactory.Stop()
+	m := newProducerMetrics(registry, metricsFactory)
+	gometrics.GetOrRegisterHistogram("batch-size", registry, gometrics.NewUniformSample(100)).Update(100)
+
+	m.start(time.Millisecond)
+	defer m.stop()
+	for i := 0; i < 1000; i++ {
+		_, gauges := metricsFactory.Snapshot()
+		if gauges["kafka_producer_batch_size_bytes|stat=mean"] == 100 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("the producer metrics were not updated")
+}
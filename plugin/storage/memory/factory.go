@@ -28,14 +28,18 @@ import (
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/samplingstore"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore/filestore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 var ( // interface comformance checks
-	_ storage.Factory              = (*Factory)(nil)
-	_ storage.ArchiveFactory       = (*Factory)(nil)
-	_ storage.SamplingStoreFactory = (*Factory)(nil)
-	_ plugin.Configurable          = (*Factory)(nil)
+	_ storage.Factory                 = (*Factory)(nil)
+	_ storage.ArchiveFactory          = (*Factory)(nil)
+	_ storage.SamplingStoreFactory    = (*Factory)(nil)
+	_ storage.SavedSearchStoreFactory = (*Factory)(nil)
+	_ storage.PurgerFactory           = (*Factory)(nil)
+	_ plugin.Configurable             = (*Factory)(nil)
 )
 
 // Factory implements storage.Factory and creates storage components backed by memory store.
@@ -44,6 +48,7 @@ type Factory struct {
 	metricsFactory metrics.Factory
 	logger         *zap.Logger
 	store          *Store
+	savedSearches  *filestore.Store
 }
 
 // NewFactory creates a new Factory.
@@ -82,6 +87,7 @@ func (f *Factory) InitFromOptions(opts Options) {
 func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
 	f.metricsFactory, f.logger = metricsFactory, logger
 	f.store = WithConfiguration(f.options.Configuration)
+	f.savedSearches = filestore.NewStore()
 	logger.Info("Memory storage initialized", zap.Any("configuration", f.store.defaultConfig))
 	f.publishOpts()
 
@@ -123,6 +129,16 @@ func (f *Factory) CreateLock() (distributedlock.Lock, error) {
 	return &lock{}, nil
 }
 
+// CreateSavedSearchStore implements storage.SavedSearchStoreFactory
+func (f *Factory) CreateSavedSearchStore() (savedsearchstore.Store, error) {
+	return f.savedSearches, nil
+}
+
+// CreatePurger implements storage.PurgerFactory
+func (f *Factory) CreatePurger() (spanstore.Purger, error) {
+	return f.store, nil
+}
+
 func (f *Factory) publishOpts() {
 	internalFactory := f.metricsFactory.Namespace(metrics.NSOptions{Name: "internal"})
 	internalFactory.Gauge(metrics.Options{Name: limit}).
@@ -51,6 +51,12 @@ func TestMemoryStorageFactory(t *testing.T) {
 	lock, err := f.CreateLock()
 	require.NoError(t, err)
 	assert.NotNil(t, lock)
+	savedSearchStore, err := f.CreateSavedSearchStore()
+	require.NoError(t, err)
+	assert.Equal(t, f.savedSearches, savedSearchStore)
+	purger, err := f.CreatePurger()
+	require.NoError(t, err)
+	assert.Equal(t, f.store, purger)
 }
 
 func TestWithConfiguration(t *testing.T) {
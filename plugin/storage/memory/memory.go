@@ -18,16 +18,22 @@ package memory
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/model/converter/otlp"
 	"github.com/jaegertracing/jaeger/pkg/memory/config"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -95,11 +101,34 @@ func (st *Store) getTenant(tenantID string) *Tenant {
 
 // GetDependencies returns dependencies between services
 func (st *Store) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	links, err := st.GetAnnotatedDependencies(ctx, endTs, lookback)
+	if err != nil {
+		return nil, err
+	}
+	deps := map[string]*model.DependencyLink{}
+	for _, link := range links {
+		depKey := link.Parent + "&&&" + link.Child
+		if _, ok := deps[depKey]; !ok {
+			deps[depKey] = &model.DependencyLink{Parent: link.Parent, Child: link.Child}
+		}
+		deps[depKey].CallCount += link.CallCount
+	}
+	retMe := make([]model.DependencyLink, 0, len(deps))
+	for _, dep := range deps {
+		retMe = append(retMe, *dep)
+	}
+	return retMe, nil
+}
+
+// GetAnnotatedDependencies returns dependencies between services along with
+// the error count and latency of the calls, measured by the child spans.
+// The calls are timestamped with the start time of the child spans.
+func (st *Store) GetAnnotatedDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]dependencystore.AnnotatedLink, error) {
 	m := st.getTenant(tenancy.GetTenant(ctx))
 	// deduper used below can modify the spans, so we take an exclusive lock
 	m.Lock()
 	defer m.Unlock()
-	deps := map[string]*model.DependencyLink{}
+	deps := map[string]*dependencystore.AnnotatedLink{}
 	startTs := endTs.Add(-1 * lookback)
 	for _, orig := range m.traces {
 		// SpanIDDeduper never returns an err
@@ -111,27 +140,40 @@ func (st *Store) GetDependencies(ctx context.Context, endTs time.Time, lookback
 					if parentSpan.Process.ServiceName == s.Process.ServiceName {
 						continue
 					}
-					depKey := parentSpan.Process.ServiceName + "&&&" + s.Process.ServiceName
+					depKey := parentSpan.Process.ServiceName + "&&&" + s.Process.ServiceName + "&&&" + strconv.FormatInt(s.StartTime.UnixNano(), 10)
 					if _, ok := deps[depKey]; !ok {
-						deps[depKey] = &model.DependencyLink{
-							Parent:    parentSpan.Process.ServiceName,
-							Child:     s.Process.ServiceName,
-							CallCount: 1,
+						deps[depKey] = &dependencystore.AnnotatedLink{
+							DependencyLink: model.DependencyLink{
+								Parent: parentSpan.Process.ServiceName,
+								Child:  s.Process.ServiceName,
+							},
+							Timestamp: s.StartTime,
+							Stats:     &dependencystore.CallStats{},
 						}
-					} else {
-						deps[depKey].CallCount++
 					}
+					dep := deps[depKey]
+					dep.CallCount++
+					stats := dependencystore.CallStats{TotalDuration: s.Duration, MaxDuration: s.Duration}
+					if isErrorSpan(s) {
+						stats.ErrorCount = 1
+					}
+					dep.Stats.Add(stats)
 				}
 			}
 		}
 	}
-	retMe := make([]model.DependencyLink, 0, len(deps))
+	retMe := make([]dependencystore.AnnotatedLink, 0, len(deps))
 	for _, dep := range deps {
 		retMe = append(retMe, *dep)
 	}
 	return retMe, nil
 }
 
+func isErrorSpan(span *model.Span) bool {
+	tag, ok := model.KeyValues(span.Tags).FindByKey("error")
+	return ok && tag.Bool()
+}
+
 func findSpan(trace *model.Trace, spanID model.SpanID) *model.Span {
 	for _, s := range trace.Spans {
 		if s.SpanID == spanID {
@@ -155,6 +197,33 @@ func (st *Store) WriteSpan(ctx context.Context, span *model.Span) error {
 	m := st.getTenant(tenancy.GetTenant(ctx))
 	m.Lock()
 	defer m.Unlock()
+	m.writeSpan(span)
+	return nil
+}
+
+// WriteTraces implements spanstore.OTLPWriter. The spans of the traces are written at once,
+// so that the readers never see a batch partially written.
+func (st *Store) WriteTraces(ctx context.Context, td ptrace.Traces) error {
+	batches, err := otlp.ProtoFromTraces(td)
+	if err != nil {
+		return fmt.Errorf("cannot transform OTLP traces to Jaeger format: %w", err)
+	}
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.Lock()
+	defer m.Unlock()
+	for _, batch := range batches {
+		for _, span := range batch.Spans {
+			if span.Process == nil {
+				span.Process = batch.Process
+			}
+			m.writeSpan(span)
+		}
+	}
+	return nil
+}
+
+// writeSpan writes the span, the tenant must be locked
+func (m *Tenant) writeSpan(span *model.Span) {
 	if _, ok := m.operations[span.Process.ServiceName]; !ok {
 		m.operations[span.Process.ServiceName] = map[spanstore.Operation]struct{}{}
 	}
@@ -190,8 +259,6 @@ func (st *Store) WriteSpan(ctx context.Context, span *model.Span) error {
 
 	}
 	m.traces[span.TraceID].Spans = append(m.traces[span.TraceID].Spans, span)
-
-	return nil
 }
 
 // GetTrace gets a trace
@@ -206,6 +273,19 @@ func (st *Store) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Tr
 	return copyTrace(trace)
 }
 
+// GetTraceOTLP implements spanstore.OTLPReader. The trace is converted while the tenant
+// is locked, so it is not copied first.
+func (st *Store) GetTraceOTLP(ctx context.Context, traceID model.TraceID) (ptrace.Traces, error) {
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.RLock()
+	defer m.RUnlock()
+	trace, ok := m.traces[traceID]
+	if !ok {
+		return ptrace.Traces{}, spanstore.ErrTraceNotFound
+	}
+	return spanstore.TraceToOTLP(trace)
+}
+
 // Spans may still be added to traces after they are returned to user code, so make copies.
 func copyTrace(trace *model.Trace) (*model.Trace, error) {
 	bytes, err := proto.Marshal(trace)
@@ -249,20 +329,57 @@ func (st *Store) GetOperations(
 	return retMe, nil
 }
 
+// FindOperations returns the operations of the service selected by the name filters and the page of the query.
+func (st *Store) FindOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+) ([]spanstore.Operation, error) {
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.RLock()
+	defer m.RUnlock()
+	var retMe []spanstore.Operation
+	for operation := range m.operations[query.ServiceName] {
+		if query.SpanKind != "" && query.SpanKind != operation.SpanKind {
+			continue
+		}
+		if strings.HasPrefix(operation.Name, query.NamePrefix) && strings.Contains(operation.Name, query.NameContains) {
+			retMe = append(retMe, operation)
+		}
+	}
+	return spanstore.FilterOperations(retMe, query), nil
+}
+
 // FindTraces returns all traces in the query parameters are satisfied by a trace's span
 func (st *Store) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
 	m := st.getTenant(tenancy.GetTenant(ctx))
 	m.RLock()
 	defer m.RUnlock()
+	var retMe []*model.Trace
+	for _, trace := range m.findTraces(query) {
+		copied, err := copyTrace(trace)
+		if err != nil {
+			return nil, err
+		}
+		retMe = append(retMe, copied)
+	}
+	return retMe, nil
+}
+
+// FindTracesOTLP implements spanstore.OTLPReader. The traces are converted while the tenant
+// is locked, so they are not copied first.
+func (st *Store) FindTracesOTLP(ctx context.Context, query *spanstore.TraceQueryParameters) ([]ptrace.Traces, error) {
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.RLock()
+	defer m.RUnlock()
+	return spanstore.TracesToOTLP(m.findTraces(query))
+}
+
+// findTraces returns the stored traces matching the query, the tenant must be locked
+func (m *Tenant) findTraces(query *spanstore.TraceQueryParameters) []*model.Trace {
 	var retMe []*model.Trace
 	for _, trace := range m.traces {
 		if validTrace(trace, query) {
-			copied, err := copyTrace(trace)
-			if err != nil {
-				return nil, err
-			}
-
-			retMe = append(retMe, copied)
+			retMe = append(retMe, trace)
 		}
 	}
 
@@ -274,8 +391,73 @@ func (st *Store) FindTraces(ctx context.Context, query *spanstore.TraceQueryPara
 		})
 		retMe = retMe[len(retMe)-query.NumTraces:]
 	}
+	return retMe
+}
 
-	return retMe, nil
+// FindTracesPage implements spanstore.PaginatedReader.
+func (st *Store) FindTracesPage(ctx context.Context, query *spanstore.TraceQueryParameters) (*spanstore.TracesPage, error) {
+	cursor, err := spanstore.ParsePageToken(query.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.RLock()
+	defer m.RUnlock()
+	var matching []*model.Trace
+	for _, trace := range m.traces {
+		if validTrace(trace, query) && cursor.Includes(trace) {
+			matching = append(matching, trace)
+		}
+	}
+	page := spanstore.NewTracesPage(matching, query.NumTraces, cursor)
+	for i, trace := range page.Traces {
+		copied, err := copyTrace(trace)
+		if err != nil {
+			return nil, err
+		}
+		page.Traces[i] = copied
+	}
+	return page, nil
+}
+
+// GetTagKeys implements spanstore.TagReader.
+func (st *Store) GetTagKeys(ctx context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	return st.getTags(ctx, query, func(kv model.KeyValue) (string, bool) {
+		return kv.Key, true
+	}), nil
+}
+
+// GetTagValues implements spanstore.TagReader.
+func (st *Store) GetTagValues(ctx context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	return st.getTags(ctx, query, func(kv model.KeyValue) (string, bool) {
+		return kv.AsString(), kv.Key == query.Key
+	}), nil
+}
+
+// getTags selects the keys or values returned by tag from the searchable tags of the spans
+// of the service of the query, started within its time range.
+func (st *Store) getTags(ctx context.Context, query *spanstore.TagQueryParameters, tag func(model.KeyValue) (string, bool)) []string {
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.RLock()
+	defer m.RUnlock()
+	tags := make(map[string]struct{})
+	for _, trace := range m.traces {
+		for _, span := range trace.Spans {
+			if span.Process.ServiceName != query.ServiceName {
+				continue
+			}
+			if (!query.StartTimeMin.IsZero() && span.StartTime.Before(query.StartTimeMin)) ||
+				(!query.StartTimeMax.IsZero() && span.StartTime.After(query.StartTimeMax)) {
+				continue
+			}
+			for _, kv := range flattenTags(span) {
+				if value, ok := tag(kv); ok {
+					tags[value] = struct{}{}
+				}
+			}
+		}
+	}
+	return spanstore.SelectTags(tags, query)
 }
 
 // FindTraceIDs is not implemented.
@@ -283,6 +465,41 @@ func (m *Store) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryPar
 	return nil, errors.New("not implemented")
 }
 
+// PurgeTraces implements spanstore.Purger.
+func (st *Store) PurgeTraces(ctx context.Context, query *spanstore.TraceQueryParameters) (int, error) {
+	if err := spanstore.ValidatePurgeQuery(query); err != nil {
+		return 0, err
+	}
+	m := st.getTenant(tenancy.GetTenant(ctx))
+	m.Lock()
+	defer m.Unlock()
+	purged := 0
+	for traceID, trace := range m.traces {
+		if validTrace(trace, query) {
+			delete(m.traces, traceID)
+			purged++
+		}
+	}
+	// the ring must not evict the purged traces written again later
+	for i, traceID := range m.ids {
+		if traceID == nil {
+			continue
+		}
+		if _, ok := m.traces[*traceID]; !ok {
+			m.ids[i] = nil
+		}
+	}
+	return purged, nil
+}
+
+// PurgeAll implements spanstore.Purger, deleting the traces of all the tenants.
+func (st *Store) PurgeAll(context.Context) error {
+	st.Lock()
+	defer st.Unlock()
+	st.perTenant = make(map[string]*Tenant)
+	return nil
+}
+
 func validTrace(trace *model.Trace, query *spanstore.TraceQueryParameters) bool {
 	for _, span := range trace.Spans {
 		if validSpan(span, query) {
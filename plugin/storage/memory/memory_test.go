@@ -22,10 +22,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/memory/config"
 	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -161,6 +164,32 @@ func TestStoreGetDependencies(t *testing.T) {
 	})
 }
 
+func TestStoreGetAnnotatedDependencies(t *testing.T) {
+	withMemoryStore(func(store *Store) {
+		failedSpan := *childSpan2
+		failedSpan.Duration = 7 * time.Second
+		failedSpan.Tags = model.KeyValues{model.Bool("error", true)}
+		require.NoError(t, store.WriteSpan(context.Background(), testingSpan))
+		require.NoError(t, store.WriteSpan(context.Background(), childSpan1))
+		require.NoError(t, store.WriteSpan(context.Background(), &failedSpan))
+		links, err := store.GetAnnotatedDependencies(context.Background(), time.Unix(0, 0).Add(time.Hour), time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []dependencystore.AnnotatedLink{{
+			DependencyLink: model.DependencyLink{
+				Parent:    "serviceName",
+				Child:     "childService",
+				CallCount: 2,
+			},
+			Timestamp: time.Unix(300, 0),
+			Stats: &dependencystore.CallStats{
+				ErrorCount:    1,
+				TotalDuration: 12 * time.Second,
+				MaxDuration:   7 * time.Second,
+			},
+		}}, links)
+	})
+}
+
 func TestStoreWriteSpan(t *testing.T) {
 	withMemoryStore(func(store *Store) {
 		err := store.WriteSpan(context.Background(), testingSpan)
@@ -168,6 +197,39 @@ func TestStoreWriteSpan(t *testing.T) {
 	})
 }
 
+func TestStoreWriteTraces(t *testing.T) {
+	td := ptrace.NewTraces()
+	for i, service := range []string{"frontend", "driver"} {
+		resourceSpans := td.ResourceSpans().AppendEmpty()
+		resourceSpans.Resource().Attributes().PutStr("service.name", service)
+		span := resourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetTraceID(pcommon.TraceID([16]byte{15: 1}))
+		span.SetSpanID(pcommon.SpanID([8]byte{7: byte(i + 1)}))
+		span.SetName("GET /" + service)
+		span.SetKind(ptrace.SpanKindServer)
+	}
+	withMemoryStore(func(store *Store) {
+		ctx := tenancy.WithTenant(context.Background(), "acme")
+		require.NoError(t, store.WriteTraces(ctx, td))
+
+		trace, err := store.GetTrace(ctx, model.NewTraceID(0, 1))
+		require.NoError(t, err)
+		require.Len(t, trace.Spans, 2)
+		assert.Equal(t, "frontend", trace.Spans[0].Process.ServiceName)
+		assert.Equal(t, "GET /driver", trace.Spans[1].OperationName)
+		services, err := store.GetServices(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"frontend", "driver"}, services)
+		operations, err := store.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: "driver"})
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{{Name: "GET /driver", SpanKind: "server"}}, operations)
+
+		// the traces are written in the tenant of the context only
+		_, err = store.GetTrace(context.Background(), model.NewTraceID(0, 1))
+		require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+	})
+}
+
 func TestStoreWithLimit(t *testing.T) {
 	maxTraces := 100
 	store := WithConfiguration(config.Configuration{MaxTraces: maxTraces})
@@ -197,6 +259,107 @@ func TestStoreWithLimit(t *testing.T) {
 	assert.Len(t, store.getTenant("").ids, maxTraces)
 }
 
+func TestStorePurgeTraces(t *testing.T) {
+	store := WithConfiguration(config.Configuration{MaxTraces: 2})
+	writeSpan := func(id uint64, userID string) {
+		require.NoError(t, store.WriteSpan(context.Background(), &model.Span{
+			TraceID: model.NewTraceID(1, id),
+			Process: &model.Process{ServiceName: "frontend"},
+			Tags:    model.KeyValues{model.String("user.id", userID)},
+		}))
+	}
+	writeSpan(1, "alice")
+	writeSpan(2, "bob")
+
+	_, err := store.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{Tags: map[string]string{"user.id": "alice"}})
+	require.ErrorIs(t, err, spanstore.ErrPurgeQueryServiceRequired)
+	purged, err := store.PurgeTraces(context.Background(), &spanstore.TraceQueryParameters{
+		ServiceName: "frontend",
+		Tags:        map[string]string{"user.id": "alice"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	_, err = store.GetTrace(context.Background(), model.NewTraceID(1, 1))
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+	// the purged trace written again is not evicted by the next trace
+	writeSpan(1, "alice")
+	writeSpan(3, "carol")
+	_, err = store.GetTrace(context.Background(), model.NewTraceID(1, 1))
+	require.NoError(t, err)
+	_, err = store.GetTrace(context.Background(), model.NewTraceID(1, 2))
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+}
+
+func TestStoreGetTags(t *testing.T) {
+	store := NewStore()
+	start := time.Unix(1000, 0)
+	writeSpan := func(id uint64, service string, startTime time.Time, tags ...model.KeyValue) {
+		require.NoError(t, store.WriteSpan(context.Background(), &model.Span{
+			TraceID:   model.NewTraceID(1, id),
+			StartTime: startTime,
+			Process:   &model.Process{ServiceName: service, Tags: model.KeyValues{model.String("hostname", "host-1")}},
+			Tags:      tags,
+			Logs:      []model.Log{{Fields: model.KeyValues{model.String("event", "retry")}}},
+		}))
+	}
+	writeSpan(1, "frontend", start, model.String("http.method", "GET"), model.Int64("http.status_code", 200))
+	writeSpan(2, "frontend", start.Add(time.Hour), model.String("http.method", "POST"))
+	writeSpan(3, "backend", start, model.String("db.system", "mysql"))
+
+	keys, err := store.GetTagKeys(context.Background(), &spanstore.TagQueryParameters{ServiceName: "frontend"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"event", "hostname", "http.method", "http.status_code"}, keys)
+
+	keys, err = store.GetTagKeys(context.Background(), &spanstore.TagQueryParameters{ServiceName: "frontend", Prefix: "http.", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http.method"}, keys)
+
+	values, err := store.GetTagValues(context.Background(), &spanstore.TagQueryParameters{ServiceName: "frontend", Key: "http.method"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET", "POST"}, values)
+
+	values, err = store.GetTagValues(context.Background(), &spanstore.TagQueryParameters{
+		ServiceName:  "frontend",
+		Key:          "http.method",
+		StartTimeMax: start.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET"}, values)
+
+	values, err = store.GetTagValues(context.Background(), &spanstore.TagQueryParameters{
+		ServiceName:  "frontend",
+		Key:          "http.method",
+		Prefix:       "P",
+		StartTimeMin: start.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"POST"}, values)
+
+	values, err = store.GetTagValues(context.Background(), &spanstore.TagQueryParameters{ServiceName: "frontend", Key: "http.status_code"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"200"}, values)
+
+	keys, err = store.GetTagKeys(tenancy.WithTenant(context.Background(), "acme"), &spanstore.TagQueryParameters{ServiceName: "frontend"})
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestStorePurgeAll(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		ctx := tenancy.WithTenant(context.Background(), "acme")
+		require.NoError(t, store.WriteSpan(ctx, testingSpan2))
+		require.NoError(t, store.PurgeAll(context.Background()))
+		_, err := store.GetTrace(context.Background(), testingSpan.TraceID)
+		require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+		_, err = store.GetTrace(ctx, testingSpan2.TraceID)
+		require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+		services, err := store.GetServices(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, services)
+	})
+}
+
 func TestStoreGetTraceSuccess(t *testing.T) {
 	withPopulatedMemoryStore(func(store *Store) {
 		trace, err := store.GetTrace(context.Background(), testingSpan.TraceID)
@@ -287,6 +450,31 @@ func TestStoreGetServerOperationsFound(t *testing.T) {
 	})
 }
 
+func TestStoreFindOperations(t *testing.T) {
+	withMemoryStore(func(store *Store) {
+		for _, name := range []string{"GET /users", "GET /orders", "POST /orders"} {
+			span := *testingSpan
+			span.OperationName = name
+			span.SpanID = model.NewSpanID(uint64(len(name)))
+			require.NoError(t, store.WriteSpan(context.Background(), &span))
+		}
+		query := spanstore.OperationQueryParameters{ServiceName: testingSpan.Process.ServiceName, NameContains: "orders"}
+		operations, err := store.FindOperations(context.Background(), query)
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{{Name: "GET /orders", SpanKind: "client"}, {Name: "POST /orders", SpanKind: "client"}}, operations)
+
+		query = spanstore.OperationQueryParameters{ServiceName: testingSpan.Process.ServiceName, NamePrefix: "GET", Offset: 1, Limit: 1}
+		operations, err = store.FindOperations(context.Background(), query)
+		require.NoError(t, err)
+		assert.Equal(t, []spanstore.Operation{{Name: "GET /users", SpanKind: "client"}}, operations)
+
+		query = spanstore.OperationQueryParameters{ServiceName: testingSpan.Process.ServiceName, SpanKind: "server", Limit: 1}
+		operations, err = store.FindOperations(context.Background(), query)
+		require.NoError(t, err)
+		assert.Empty(t, operations)
+	})
+}
+
 func TestStoreGetOperationsNotFound(t *testing.T) {
 	withPopulatedMemoryStore(func(store *Store) {
 		operations, err := store.GetOperations(
@@ -364,6 +552,44 @@ func TestStoreFindTracesLimitGetsMostRecent(t *testing.T) {
 	}
 }
 
+func TestStoreFindTracesPage(t *testing.T) {
+	memStore := NewStore()
+	for i := 0; i < 5; i++ {
+		memStore.WriteSpan(context.Background(), &model.Span{
+			TraceID:       model.NewTraceID(1, uint64(i)),
+			SpanID:        model.NewSpanID(1),
+			OperationName: "operationName",
+			StartTime:     time.Unix(int64(i*60), 0),
+			Process: &model.Process{
+				ServiceName: "serviceName",
+			},
+		})
+	}
+
+	query := &spanstore.TraceQueryParameters{
+		ServiceName: "serviceName",
+		NumTraces:   2,
+	}
+	var pages [][]int64
+	for {
+		page, err := memStore.FindTracesPage(context.Background(), query)
+		require.NoError(t, err)
+		var startTimes []int64
+		for _, trace := range page.Traces {
+			startTimes = append(startTimes, trace.Spans[0].StartTime.Unix())
+		}
+		pages = append(pages, startTimes)
+		if page.NextPageToken == "" {
+			break
+		}
+		query.PageToken = page.NextPageToken
+	}
+	assert.Equal(t, [][]int64{{240, 180}, {120, 60}, {0}}, pages)
+
+	_, err := memStore.FindTracesPage(context.Background(), &spanstore.TraceQueryParameters{PageToken: "!"})
+	require.ErrorIs(t, err, spanstore.ErrInvalidPageToken)
+}
+
 func TestStoreGetTrace(t *testing.T) {
 	testStruct := []struct {
 		query      *spanstore.TraceQueryParameters
@@ -443,6 +669,27 @@ func TestStoreGetTrace(t *testing.T) {
 	}
 }
 
+func TestStoreOTLPReads(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		td, err := store.GetTraceOTLP(context.Background(), testingSpan.TraceID)
+		require.NoError(t, err)
+		require.Equal(t, 1, td.SpanCount())
+		assert.Equal(t, testingSpan.OperationName, td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+		_, err = store.GetTraceOTLP(context.Background(), model.NewTraceID(0, 9))
+		require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+		traces, err := store.FindTracesOTLP(context.Background(), &spanstore.TraceQueryParameters{
+			ServiceName: testingSpan.Process.ServiceName,
+		})
+		require.NoError(t, err)
+		require.Len(t, traces, 1)
+		assert.Equal(t, 1, traces[0].SpanCount())
+		traces, err = store.FindTracesOTLP(context.Background(), &spanstore.TraceQueryParameters{ServiceName: "unknown"})
+		require.NoError(t, err)
+		assert.Empty(t, traces)
+	})
+}
+
 func TestStore_FindTraceIDs(t *testing.T) {
 	withMemoryStore(func(store *Store) {
 		traceIDs, err := store.FindTraceIDs(context.Background(), nil)
@@ -93,10 +93,13 @@ func (m *GetTraceRequest) GetEndTime() *time.Time {
 }
 
 type SpansResponseChunk struct {
-	Spans                []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Spans []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
+	// The token of the next page of FindTraces, set on the last chunk of the stream
+	// when more traces may match the query.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SpansResponseChunk) Reset()         { *m = SpansResponseChunk{} }
@@ -139,6 +142,13 @@ func (m *SpansResponseChunk) GetSpans() []model.Span {
 	return nil
 }
 
+func (m *SpansResponseChunk) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type ArchiveTraceRequest struct {
 	TraceID github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id"`
 	// Optional. The start time to search trace ID.
@@ -352,10 +362,12 @@ func (m *TraceQueryParameters) GetSearchDepth() int32 {
 }
 
 type FindTracesRequest struct {
-	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	Query *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Optional. The next_page_token of the previous page, to continue the search.
+	PageToken            string   `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *FindTracesRequest) Reset()         { *m = FindTracesRequest{} }
@@ -398,6 +410,13 @@ func (m *FindTracesRequest) GetQuery() *TraceQueryParameters {
 	return nil
 }
 
+func (m *FindTracesRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
 type GetServicesRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -485,8 +504,16 @@ func (m *GetServicesResponse) GetServices() []string {
 }
 
 type GetOperationsRequest struct {
-	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
-	SpanKind             string   `protobuf:"bytes,2,opt,name=span_kind,json=spanKind,proto3" json:"span_kind,omitempty"`
+	Service  string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	SpanKind string `protobuf:"bytes,2,opt,name=span_kind,json=spanKind,proto3" json:"span_kind,omitempty"`
+	// Optional. Restricts the operations to the names starting with the prefix.
+	NamePrefix string `protobuf:"bytes,3,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	// Optional. Restricts the operations to the names containing the string.
+	NameContains string `protobuf:"bytes,4,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"`
+	// Optional. The number of operations, sorted by name and span kind, to skip.
+	Offset int32 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Optional. The maximum number of operations to return.
+	Limit                int32    `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -539,6 +566,34 @@ func (m *GetOperationsRequest) GetSpanKind() string {
 	return ""
 }
 
+func (m *GetOperationsRequest) GetNamePrefix() string {
+	if m != nil {
+		return m.NamePrefix
+	}
+	return ""
+}
+
+func (m *GetOperationsRequest) GetNameContains() string {
+	if m != nil {
+		return m.NameContains
+	}
+	return ""
+}
+
+func (m *GetOperationsRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *GetOperationsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
 type Operation struct {
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	SpanKind             string   `protobuf:"bytes,2,opt,name=span_kind,json=spanKind,proto3" json:"span_kind,omitempty"`
@@ -771,69 +826,75 @@ func init() {
 func init() { proto.RegisterFile("query.proto", fileDescriptor_5c6ac9b241082464) }
 
 var fileDescriptor_5c6ac9b241082464 = []byte{
-	// 983 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x56, 0x4f, 0x73, 0xdb, 0x44,
-	0x14, 0x47, 0x8e, 0x1d, 0xdb, 0x4f, 0x76, 0x4b, 0xd7, 0x4e, 0x2b, 0x54, 0xb0, 0x1d, 0x85, 0x76,
-	0x32, 0xcc, 0x44, 0x2a, 0xe6, 0x40, 0x29, 0xcc, 0x94, 0xa6, 0x69, 0x3d, 0x05, 0x5a, 0x40, 0xcd,
-	0x09, 0x0e, 0x9e, 0x8d, 0xb5, 0xc8, 0xc2, 0xf1, 0xca, 0x95, 0xd6, 0x21, 0x1e, 0x86, 0x0b, 0x9f,
-	0x80, 0x19, 0x2e, 0x9c, 0xb8, 0x72, 0xe2, 0x7b, 0xf4, 0xc8, 0x0c, 0x37, 0x0e, 0x81, 0xc9, 0x70,
-	0xe4, 0xc0, 0x47, 0x60, 0xf6, 0x8f, 0x14, 0x49, 0xce, 0xa4, 0x69, 0xae, 0x9c, 0xac, 0x7d, 0xfb,
-	0xde, 0xef, 0xfd, 0xfd, 0xbd, 0x35, 0xe8, 0xcf, 0xe6, 0x24, 0x5a, 0xd8, 0xb3, 0x28, 0x64, 0x21,
-	0x6a, 0x7e, 0x8d, 0x89, 0x4f, 0x22, 0x1b, 0xcf, 0x82, 0xe1, 0x41, 0xdf, 0xd4, 0xa7, 0xa1, 0x47,
-	0xf6, 0xe5, 0x9d, 0xd9, 0xf6, 0x43, 0x3f, 0x14, 0x9f, 0x0e, 0xff, 0x52, 0xd2, 0xd7, 0xfd, 0x30,
-	0xf4, 0xf7, 0x89, 0x83, 0x67, 0x81, 0x83, 0x29, 0x0d, 0x19, 0x66, 0x41, 0x48, 0x63, 0x75, 0xdb,
-	0x55, 0xb7, 0xe2, 0xb4, 0x37, 0xff, 0xca, 0x61, 0xc1, 0x94, 0xc4, 0x0c, 0x4f, 0x67, 0x4a, 0xa1,
-	0x53, 0x54, 0xf0, 0xe6, 0x91, 0x40, 0x90, 0xf7, 0xd6, 0x3f, 0x1a, 0x5c, 0x1e, 0x10, 0xb6, 0x1b,
-	0xe1, 0x11, 0x71, 0xc9, 0xb3, 0x39, 0x89, 0x19, 0xfa, 0x12, 0x6a, 0x8c, 0x9f, 0x87, 0x81, 0x67,
-	0x68, 0x3d, 0x6d, 0xb3, 0xb1, 0xfd, 0xe1, 0xf3, 0xa3, 0xee, 0x2b, 0x7f, 0x1c, 0x75, 0xb7, 0xfc,
-	0x80, 0x8d, 0xe7, 0x7b, 0xf6, 0x28, 0x9c, 0x3a, 0x32, 0x13, 0xae, 0x18, 0x50, 0x5f, 0x9d, 0x1c,
-	0x99, 0x8f, 0x40, 0x7b, 0xb4, 0x73, 0x7c, 0xd4, 0xad, 0xaa, 0x4f, 0xb7, 0x2a, 0x10, 0x1f, 0x79,
-	0xe8, 0x2e, 0x40, 0xcc, 0x70, 0xc4, 0x86, 0x3c, 0x52, 0xa3, 0xd4, 0xd3, 0x36, 0xf5, 0xbe, 0x69,
-	0xcb, 0x28, 0xed, 0x24, 0x4a, 0x7b, 0x37, 0x49, 0x63, 0xbb, 0xfc, 0xc3, 0x9f, 0x5d, 0xcd, 0xad,
-	0x0b, 0x1b, 0x2e, 0x45, 0xef, 0x43, 0x8d, 0x50, 0x4f, 0x9a, 0xaf, 0x9c, 0xd3, 0xbc, 0x4a, 0xa8,
-	0xc7, 0x65, 0xd6, 0x03, 0x40, 0x4f, 0x67, 0x98, 0xc6, 0x2e, 0x89, 0x67, 0x21, 0x8d, 0xc9, 0xfd,
-	0xf1, 0x9c, 0x4e, 0x90, 0x03, 0x95, 0x98, 0x4b, 0x0d, 0xad, 0xb7, 0xb2, 0xa9, 0xf7, 0x5b, 0x76,
-	0xae, 0x4b, 0x36, 0xb7, 0xd8, 0x2e, 0xf3, 0x12, 0xb8, 0x52, 0xcf, 0xfa, 0x57, 0x83, 0xd6, 0xbd,
-	0x68, 0x34, 0x0e, 0x0e, 0xc8, 0xff, 0xa5, 0x72, 0x57, 0xa1, 0x9d, 0xcf, 0x58, 0x16, 0xd0, 0xfa,
-	0xa5, 0x0c, 0x6d, 0x21, 0xf9, 0x9c, 0x8f, 0xf9, 0x67, 0x38, 0xc2, 0x53, 0xc2, 0x48, 0x14, 0xa3,
-	0x75, 0x68, 0xc4, 0x24, 0x3a, 0x08, 0x46, 0x64, 0x48, 0xf1, 0x94, 0x88, 0x7a, 0xd4, 0x5d, 0x5d,
-	0xc9, 0x9e, 0xe0, 0x29, 0x41, 0x37, 0xe0, 0x52, 0x38, 0x23, 0x72, 0x1e, 0xa5, 0x52, 0x49, 0x28,
-	0x35, 0x53, 0xa9, 0x50, 0xbb, 0x07, 0x65, 0x86, 0xfd, 0xd8, 0x58, 0x11, 0xdd, 0xd9, 0x2a, 0x74,
-	0xe7, 0x34, 0xe7, 0xf6, 0x2e, 0xf6, 0xe3, 0x07, 0x94, 0x45, 0x0b, 0x57, 0x98, 0xa2, 0x8f, 0xe0,
-	0xd2, 0x49, 0xed, 0x86, 0xd3, 0x80, 0x1a, 0xe5, 0x17, 0x16, 0xa0, 0xc6, 0x5b, 0x27, 0x8a, 0xd0,
-	0x48, 0x6b, 0xf8, 0x38, 0xa0, 0x45, 0x2c, 0x7c, 0x68, 0x54, 0x2e, 0x86, 0x85, 0x0f, 0xd1, 0x43,
-	0x68, 0x24, 0x84, 0x14, 0x51, 0xad, 0x0a, 0xa4, 0xd7, 0x96, 0x90, 0x76, 0x94, 0x92, 0x04, 0xfa,
-	0x89, 0x03, 0xe9, 0x89, 0x21, 0x8f, 0x29, 0x87, 0x83, 0x0f, 0x8d, 0xea, 0x45, 0x70, 0xf0, 0xa1,
-	0x6c, 0x1a, 0x8e, 0x46, 0xe3, 0xa1, 0x47, 0x66, 0x6c, 0x6c, 0xd4, 0x7a, 0xda, 0x66, 0x85, 0x37,
-	0x8d, 0xcb, 0x76, 0xb8, 0xc8, 0x7c, 0x17, 0xea, 0x69, 0x75, 0xd1, 0xab, 0xb0, 0x32, 0x21, 0x0b,
-	0xd5, 0x5b, 0xfe, 0x89, 0xda, 0x50, 0x39, 0xc0, 0xfb, 0xf3, 0xa4, 0x95, 0xf2, 0x70, 0xa7, 0x74,
-	0x5b, 0xb3, 0x9e, 0xc0, 0x95, 0x87, 0x01, 0xf5, 0x44, 0xbf, 0xe2, 0x84, 0x31, 0xef, 0x41, 0x45,
-	0xec, 0x47, 0x01, 0xa1, 0xf7, 0x37, 0xce, 0xd1, 0x5c, 0x57, 0x5a, 0x58, 0x6d, 0x40, 0x03, 0xc2,
-	0x9e, 0xca, 0x79, 0x4a, 0x00, 0xad, 0xb7, 0xa1, 0x95, 0x93, 0xca, 0x31, 0x45, 0x26, 0xd4, 0xd4,
-	0xe4, 0x49, 0x96, 0xd7, 0xdd, 0xf4, 0x6c, 0x3d, 0x86, 0xf6, 0x80, 0xb0, 0x4f, 0x93, 0x99, 0x4b,
-	0x63, 0x33, 0xa0, 0xaa, 0x74, 0x54, 0x82, 0xc9, 0x11, 0x5d, 0x87, 0x3a, 0x5f, 0x04, 0xc3, 0x49,
-	0x40, 0x3d, 0x95, 0x68, 0x8d, 0x0b, 0x3e, 0x0e, 0xa8, 0x67, 0x7d, 0x00, 0xf5, 0x14, 0x0b, 0x21,
-	0x28, 0x67, 0xa6, 0x5f, 0x7c, 0x9f, 0x6d, 0xbd, 0x80, 0xb5, 0x42, 0x30, 0x2a, 0x83, 0x9b, 0x19,
-	0xb2, 0x70, 0x5a, 0x24, 0x79, 0x14, 0xa4, 0xe8, 0x36, 0x40, 0x2a, 0x89, 0x8d, 0x92, 0xe0, 0x8c,
-	0x51, 0x28, 0x6b, 0x0a, 0xef, 0x66, 0x74, 0xad, 0x9f, 0x35, 0xb8, 0x3a, 0x20, 0x6c, 0x87, 0xcc,
-	0x08, 0xf5, 0x08, 0x1d, 0x05, 0x27, 0x6d, 0xba, 0x9f, 0xdb, 0x3d, 0xda, 0x4b, 0xcc, 0x7b, 0x66,
-	0xff, 0xdc, 0xcd, 0xec, 0x9f, 0xd2, 0x4b, 0x40, 0xa4, 0x3b, 0x68, 0x0f, 0xae, 0x2d, 0xc5, 0xa7,
-	0xaa, 0x33, 0x80, 0x86, 0x97, 0x91, 0xab, 0x4d, 0xfe, 0x46, 0x21, 0xef, 0xd4, 0x74, 0xf1, 0x49,
-	0x40, 0x27, 0x6a, 0xa7, 0xe7, 0x0c, 0xfb, 0xbf, 0x56, 0xa0, 0x21, 0x06, 0x4e, 0x8d, 0x10, 0x9a,
-	0x40, 0x2d, 0x79, 0x20, 0x51, 0xa7, 0x80, 0x57, 0x78, 0x39, 0xcd, 0xf5, 0x53, 0x5e, 0x8e, 0xfc,
-	0x5b, 0x63, 0x99, 0xdf, 0xff, 0xfe, 0xf7, 0x8f, 0xa5, 0x36, 0x42, 0x8e, 0xd8, 0xeb, 0xb1, 0xf3,
-	0x6d, 0xf2, 0x62, 0x7c, 0x77, 0x4b, 0x43, 0x0c, 0x1a, 0xd9, 0x2d, 0x8b, 0xac, 0x02, 0xe0, 0x29,
-	0x8f, 0x8e, 0xb9, 0x71, 0xa6, 0x8e, 0x5a, 0xd3, 0xd7, 0x85, 0xdb, 0x35, 0xab, 0xe5, 0x60, 0x79,
-	0x9d, 0xf1, 0x8b, 0x7c, 0x80, 0x13, 0x66, 0xa2, 0x5e, 0x01, 0x6f, 0x89, 0xb4, 0xe7, 0x49, 0x13,
-	0x09, 0x7f, 0x0d, 0xab, 0xea, 0xc8, 0xdd, 0x71, 0x47, 0x7b, 0xeb, 0x96, 0x86, 0x7c, 0xd0, 0x33,
-	0xe4, 0x44, 0xeb, 0xcb, 0xe5, 0x2c, 0xd0, 0xd9, 0xb4, 0xce, 0x52, 0x51, 0xb9, 0x5d, 0x11, 0xbe,
-	0x74, 0x54, 0x77, 0x12, 0x4a, 0xa3, 0x10, 0x9a, 0x39, 0x16, 0xa1, 0x8d, 0x65, 0x9c, 0x25, 0xc2,
-	0x9b, 0x6f, 0x9e, 0xad, 0xa4, 0xdc, 0xb5, 0x84, 0xbb, 0x26, 0xd2, 0x9d, 0x13, 0xee, 0xa0, 0x6f,
-	0xc4, 0xdf, 0xa8, 0xec, 0x68, 0xa2, 0x1b, 0xcb, 0x68, 0xa7, 0x50, 0xcb, 0xbc, 0xf9, 0x22, 0x35,
-	0xe5, 0x76, 0x4d, 0xb8, 0xbd, 0x8c, 0x9a, 0x4e, 0x76, 0x5e, 0xb7, 0xb7, 0x9e, 0x1f, 0x77, 0xb4,
-	0xdf, 0x8e, 0x3b, 0xda, 0x5f, 0xc7, 0x1d, 0x0d, 0xae, 0x05, 0xa1, 0x9d, 0xfb, 0x7b, 0xa1, 0x50,
-	0xbf, 0x58, 0x95, 0xbf, 0x7b, 0xab, 0x82, 0x69, 0xef, 0xfc, 0x17, 0x00, 0x00, 0xff, 0xff, 0xe1,
-	0x8b, 0x00, 0xa9, 0x96, 0x0a, 0x00, 0x00,
+	// 1080 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x56, 0xcf, 0x73, 0xdb, 0xc4,
+	0x17, 0xff, 0x2a, 0xb1, 0x63, 0xeb, 0xc9, 0x4e, 0xbe, 0xdd, 0x38, 0xa9, 0x50, 0x69, 0x9c, 0x28,
+	0x34, 0x93, 0x61, 0x26, 0x56, 0x09, 0x07, 0x4a, 0x61, 0xa6, 0x34, 0x09, 0xcd, 0x94, 0x9f, 0x41,
+	0xcd, 0x09, 0x0e, 0x9e, 0x8d, 0xb5, 0x51, 0x16, 0x47, 0x2b, 0x55, 0x5a, 0x07, 0x67, 0x18, 0x2e,
+	0xfc, 0x05, 0xcc, 0x70, 0xe1, 0xc4, 0x95, 0x13, 0x7f, 0x04, 0xb7, 0x1e, 0x99, 0xe1, 0xc6, 0x21,
+	0x30, 0x19, 0x8e, 0x1c, 0xf8, 0x13, 0x98, 0xfd, 0x21, 0xc5, 0x96, 0x33, 0x69, 0xda, 0x2b, 0x27,
+	0x6b, 0x3f, 0xfb, 0xde, 0xe7, 0xbd, 0x7d, 0x3f, 0x0d, 0xd6, 0xd3, 0x01, 0x49, 0x4f, 0x3b, 0x49,
+	0x1a, 0xf3, 0x18, 0x35, 0xbf, 0xc4, 0x24, 0x24, 0x69, 0x07, 0x27, 0xb4, 0x7b, 0xb2, 0xe9, 0x58,
+	0x51, 0x1c, 0x90, 0x63, 0x75, 0xe7, 0xb4, 0xc2, 0x38, 0x8c, 0xe5, 0xa7, 0x27, 0xbe, 0x34, 0xfa,
+	0x6a, 0x18, 0xc7, 0xe1, 0x31, 0xf1, 0x70, 0x42, 0x3d, 0xcc, 0x58, 0xcc, 0x31, 0xa7, 0x31, 0xcb,
+	0xf4, 0x6d, 0x5b, 0xdf, 0xca, 0xd3, 0xc1, 0xe0, 0xd0, 0xe3, 0x34, 0x22, 0x19, 0xc7, 0x51, 0xa2,
+	0x05, 0x96, 0xca, 0x02, 0xc1, 0x20, 0x95, 0x0c, 0xea, 0xde, 0xfd, 0xdb, 0x80, 0xb9, 0x5d, 0xc2,
+	0xf7, 0x53, 0xdc, 0x23, 0x3e, 0x79, 0x3a, 0x20, 0x19, 0x47, 0x5f, 0x40, 0x9d, 0x8b, 0x73, 0x97,
+	0x06, 0xb6, 0xb1, 0x6c, 0xac, 0x37, 0xb6, 0xde, 0x7b, 0x76, 0xd6, 0xfe, 0xdf, 0xef, 0x67, 0xed,
+	0x8d, 0x90, 0xf2, 0xa3, 0xc1, 0x41, 0xa7, 0x17, 0x47, 0x9e, 0x7a, 0x89, 0x10, 0xa4, 0x2c, 0xd4,
+	0x27, 0x4f, 0xbd, 0x47, 0xb2, 0x3d, 0xde, 0x39, 0x3f, 0x6b, 0xd7, 0xf4, 0xa7, 0x5f, 0x93, 0x8c,
+	0x8f, 0x03, 0xf4, 0x00, 0x20, 0xe3, 0x38, 0xe5, 0x5d, 0xe1, 0xa9, 0x3d, 0xb5, 0x6c, 0xac, 0x5b,
+	0x9b, 0x4e, 0x47, 0x79, 0xd9, 0xc9, 0xbd, 0xec, 0xec, 0xe7, 0xcf, 0xd8, 0xaa, 0x7c, 0xf7, 0x47,
+	0xdb, 0xf0, 0x4d, 0xa9, 0x23, 0x50, 0xf4, 0x0e, 0xd4, 0x09, 0x0b, 0x94, 0xfa, 0xf4, 0x35, 0xd5,
+	0x6b, 0x84, 0x05, 0x02, 0x73, 0x23, 0x40, 0x4f, 0x12, 0xcc, 0x32, 0x9f, 0x64, 0x49, 0xcc, 0x32,
+	0xb2, 0x7d, 0x34, 0x60, 0x7d, 0xe4, 0x41, 0x35, 0x13, 0xa8, 0x6d, 0x2c, 0x4f, 0xaf, 0x5b, 0x9b,
+	0xf3, 0x9d, 0xb1, 0x2c, 0x75, 0x84, 0xc6, 0x56, 0x45, 0x84, 0xc0, 0x57, 0x72, 0x68, 0x0d, 0xe6,
+	0x18, 0x19, 0xf2, 0x6e, 0x82, 0x43, 0xd2, 0xe5, 0x71, 0x9f, 0x30, 0xf9, 0x12, 0xd3, 0x6f, 0x0a,
+	0x78, 0x0f, 0x87, 0x64, 0x5f, 0x80, 0xee, 0x3f, 0x06, 0xcc, 0x3f, 0x4c, 0x7b, 0x47, 0xf4, 0x84,
+	0xfc, 0x57, 0x22, 0xbc, 0x08, 0xad, 0xf1, 0x17, 0xab, 0x40, 0xbb, 0x3f, 0x55, 0xa0, 0x25, 0x91,
+	0xcf, 0x44, 0x3b, 0xec, 0xe1, 0x14, 0x47, 0x84, 0x93, 0x34, 0x43, 0x2b, 0xd0, 0xc8, 0x48, 0x7a,
+	0x42, 0x7b, 0xa4, 0xcb, 0x70, 0x44, 0x64, 0x3c, 0x4c, 0xdf, 0xd2, 0xd8, 0x27, 0x38, 0x22, 0xe8,
+	0x0e, 0xcc, 0xc6, 0x09, 0x51, 0x75, 0xab, 0x84, 0x74, 0xb4, 0x0b, 0x54, 0x8a, 0x3d, 0x84, 0x0a,
+	0xc7, 0x61, 0x66, 0x4f, 0xcb, 0x2c, 0x6e, 0x94, 0xb2, 0x78, 0x99, 0xf1, 0xce, 0x3e, 0x0e, 0xb3,
+	0xf7, 0x19, 0x4f, 0x4f, 0x7d, 0xa9, 0x8a, 0x3e, 0x80, 0xd9, 0x8b, 0xd8, 0x75, 0x23, 0xca, 0xec,
+	0xca, 0x73, 0x03, 0x50, 0x17, 0xa9, 0x93, 0x41, 0x68, 0x14, 0x31, 0xfc, 0x98, 0xb2, 0x32, 0x17,
+	0x1e, 0xda, 0xd5, 0x97, 0xe3, 0xc2, 0x43, 0xf4, 0x08, 0x1a, 0x79, 0xe3, 0x4a, 0xaf, 0x66, 0x24,
+	0xd3, 0x2b, 0x13, 0x4c, 0x3b, 0x5a, 0x48, 0x11, 0xfd, 0x20, 0x88, 0xac, 0x5c, 0x51, 0xf8, 0x34,
+	0xc6, 0x83, 0x87, 0x76, 0xed, 0x65, 0x78, 0xf0, 0x50, 0x25, 0x0d, 0xa7, 0xbd, 0xa3, 0x6e, 0x40,
+	0x12, 0x7e, 0x64, 0xd7, 0x97, 0x8d, 0xf5, 0xaa, 0x6f, 0x29, 0x6c, 0x47, 0x40, 0xce, 0x5b, 0x60,
+	0x16, 0xd1, 0x45, 0xff, 0x87, 0xe9, 0x3e, 0x39, 0xd5, 0xb9, 0x15, 0x9f, 0xa8, 0x05, 0xd5, 0x13,
+	0x7c, 0x3c, 0xc8, 0x53, 0xa9, 0x0e, 0xf7, 0xa7, 0xee, 0x19, 0x6e, 0x04, 0x37, 0x1e, 0x51, 0x16,
+	0xc8, 0x7c, 0x65, 0x79, 0xc7, 0xbc, 0x0d, 0x55, 0x39, 0x47, 0x25, 0x85, 0xb5, 0xb9, 0x7a, 0x8d,
+	0xe4, 0xfa, 0x4a, 0x03, 0xdd, 0x06, 0x98, 0xe8, 0x53, 0x33, 0x29, 0x7a, 0xb4, 0x05, 0x68, 0x97,
+	0xf0, 0x27, 0xaa, 0xdc, 0x72, 0x7b, 0xee, 0x1b, 0x30, 0x3f, 0x86, 0xaa, 0x2a, 0x46, 0x0e, 0xd4,
+	0x75, 0x61, 0xaa, 0x61, 0x61, 0xfa, 0xc5, 0xd9, 0xfd, 0xc5, 0x80, 0xd6, 0x2e, 0xe1, 0x9f, 0xe6,
+	0x35, 0x59, 0xf8, 0x6e, 0x43, 0x4d, 0x0b, 0xe9, 0x00, 0xe4, 0x47, 0x74, 0x0b, 0x4c, 0x31, 0x50,
+	0xba, 0x7d, 0xca, 0x02, 0xed, 0x59, 0x5d, 0x00, 0x1f, 0x52, 0x16, 0xa0, 0x36, 0x58, 0xa2, 0xd6,
+	0xbb, 0x49, 0x4a, 0x0e, 0xe9, 0x50, 0x76, 0xa2, 0xe9, 0x83, 0x80, 0xf6, 0x24, 0x82, 0x56, 0xa1,
+	0x29, 0x05, 0x7a, 0x31, 0xe3, 0x98, 0xb2, 0x4c, 0xd6, 0xaa, 0xe9, 0x37, 0x04, 0xb8, 0xad, 0x31,
+	0xb4, 0x08, 0x33, 0xf1, 0xe1, 0x61, 0x46, 0xb8, 0xac, 0xbe, 0xaa, 0xaf, 0x4f, 0x22, 0xfe, 0xc7,
+	0x34, 0xa2, 0x5c, 0x96, 0x52, 0xd5, 0x57, 0x07, 0xf7, 0x5d, 0x30, 0x0b, 0xff, 0x11, 0x82, 0xca,
+	0x48, 0x47, 0xca, 0xef, 0x2b, 0x3d, 0x76, 0x4f, 0x61, 0xa1, 0x14, 0x00, 0x1d, 0xb6, 0x35, 0x98,
+	0x1d, 0x6b, 0xd5, 0x3c, 0x78, 0x25, 0x14, 0xdd, 0x03, 0x28, 0x90, 0xcc, 0x9e, 0x92, 0x7d, 0x6c,
+	0x97, 0x52, 0x5d, 0xd0, 0xfb, 0x23, 0xb2, 0xee, 0x8f, 0x06, 0x2c, 0xee, 0x12, 0xbe, 0x43, 0x12,
+	0xc2, 0x02, 0xc2, 0x7a, 0xf4, 0xa2, 0x74, 0xb6, 0xc7, 0xe6, 0xa1, 0xf1, 0x02, 0x3d, 0x38, 0x32,
+	0x13, 0x1f, 0x8c, 0xcc, 0xc4, 0xa9, 0x17, 0xa0, 0x28, 0xe6, 0xe2, 0x01, 0xdc, 0x9c, 0xf0, 0x4f,
+	0x47, 0x67, 0x17, 0x1a, 0xc1, 0x08, 0xae, 0xb7, 0xd0, 0xed, 0xd2, 0xbb, 0x0b, 0xd5, 0xd3, 0x8f,
+	0x28, 0xeb, 0xeb, 0x7d, 0x34, 0xa6, 0xb8, 0xf9, 0x73, 0x15, 0x1a, 0xb2, 0x09, 0x74, 0xdd, 0xa2,
+	0x3e, 0xd4, 0xf3, 0xe5, 0x8e, 0x96, 0x4a, 0x7c, 0xa5, 0xad, 0xef, 0xac, 0x5c, 0xb2, 0xf5, 0xc6,
+	0xf7, 0xa4, 0xeb, 0x7c, 0xfb, 0xdb, 0x5f, 0xdf, 0x4f, 0xb5, 0x10, 0xf2, 0xe4, 0xae, 0xc9, 0xbc,
+	0xaf, 0xf3, 0x2d, 0xf6, 0xcd, 0x5d, 0x03, 0x71, 0x68, 0x8c, 0x4e, 0x7e, 0xe4, 0x96, 0x08, 0x2f,
+	0x59, 0x84, 0xce, 0xea, 0x95, 0x32, 0x7a, 0x75, 0xdc, 0x92, 0x66, 0x17, 0xdc, 0x79, 0x0f, 0xab,
+	0xeb, 0x11, 0xbb, 0x28, 0x04, 0xb8, 0x98, 0x16, 0x68, 0xb9, 0xc4, 0x37, 0x31, 0x48, 0xae, 0xf3,
+	0x4c, 0x24, 0xed, 0x35, 0xdc, 0x9a, 0xa7, 0xe6, 0xd9, 0x7d, 0xe3, 0xf5, 0xbb, 0x06, 0x0a, 0xc1,
+	0x1a, 0x99, 0x08, 0x68, 0x65, 0x32, 0x9c, 0xa5, 0x19, 0xe2, 0xb8, 0x57, 0x89, 0xe8, 0xb7, 0xdd,
+	0x90, 0xb6, 0x2c, 0x64, 0x7a, 0xf9, 0x1c, 0x41, 0x31, 0x34, 0xc7, 0xba, 0x08, 0xad, 0x4e, 0xf2,
+	0x4c, 0x0c, 0x19, 0xe7, 0xb5, 0xab, 0x85, 0xb4, 0xb9, 0x79, 0x69, 0xae, 0x89, 0x2c, 0xef, 0xa2,
+	0x77, 0xd0, 0x57, 0xf2, 0x2f, 0xe0, 0x68, 0x69, 0xa2, 0x3b, 0x93, 0x6c, 0x97, 0xb4, 0x96, 0xb3,
+	0xf6, 0x3c, 0x31, 0x6d, 0x76, 0x41, 0x9a, 0x9d, 0x43, 0x4d, 0x6f, 0xb4, 0x5e, 0xb7, 0x36, 0x9e,
+	0x9d, 0x2f, 0x19, 0xbf, 0x9e, 0x2f, 0x19, 0x7f, 0x9e, 0x2f, 0x19, 0x70, 0x93, 0xc6, 0x9d, 0xb1,
+	0xbf, 0x3c, 0x9a, 0xf5, 0xf3, 0x19, 0xf5, 0x7b, 0x30, 0x23, 0x3b, 0xed, 0xcd, 0x7f, 0x07, 0x00,
+	0xff, 0xb2, 0x92, 0x48, 0x52, 0x0b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1232,6 +1293,13 @@ func (m *SpansResponseChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.NextPageToken) > 0 {
+		i -= len(m.NextPageToken)
+		copy(dAtA[i:], m.NextPageToken)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.NextPageToken)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.Spans) > 0 {
 		for iNdEx := len(m.Spans) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1454,6 +1522,13 @@ func (m *FindTracesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.PageToken) > 0 {
+		i -= len(m.PageToken)
+		copy(dAtA[i:], m.PageToken)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.PageToken)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if m.Query != nil {
 		{
 			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
@@ -1556,6 +1631,30 @@ func (m *GetOperationsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Limit != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Offset != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.NameContains) > 0 {
+		i -= len(m.NameContains)
+		copy(dAtA[i:], m.NameContains)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.NameContains)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.NamePrefix) > 0 {
+		i -= len(m.NamePrefix)
+		copy(dAtA[i:], m.NamePrefix)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.NamePrefix)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.SpanKind) > 0 {
 		i -= len(m.SpanKind)
 		copy(dAtA[i:], m.SpanKind)
@@ -1793,6 +1892,10 @@ func (m *SpansResponseChunk) Size() (n int) {
 			n += 1 + l + sovQuery(uint64(l))
 		}
 	}
+	l = len(m.NextPageToken)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1882,6 +1985,10 @@ func (m *FindTracesRequest) Size() (n int) {
 		l = m.Query.Size()
 		n += 1 + l + sovQuery(uint64(l))
 	}
+	l = len(m.PageToken)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1932,6 +2039,20 @@ func (m *GetOperationsRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovQuery(uint64(l))
 	}
+	l = len(m.NamePrefix)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.NameContains)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovQuery(uint64(m.Offset))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovQuery(uint64(m.Limit))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2241,6 +2362,38 @@ func (m *SpansResponseChunk) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextPageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextPageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -2928,6 +3081,38 @@ func (m *FindTracesRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -3177,6 +3362,108 @@ func (m *GetOperationsRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.SpanKind = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NamePrefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NamePrefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NameContains", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NameContains = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -46,3 +46,33 @@ func (_m *PluginCapabilitiesClient) Capabilities(ctx context.Context, in *storag
 
 	return r0, r1
 }
+
+// Negotiate provides a mock function with given fields: ctx, in, opts
+func (_m *PluginCapabilitiesClient) Negotiate(ctx context.Context, in *storage_v1.NegotiateRequest, opts ...grpc.CallOption) (*storage_v1.NegotiateResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *storage_v1.NegotiateResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *storage_v1.NegotiateRequest, ...grpc.CallOption) *storage_v1.NegotiateResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage_v1.NegotiateResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *storage_v1.NegotiateRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -36,3 +36,26 @@ func (_m *PluginCapabilitiesServer) Capabilities(_a0 context.Context, _a1 *stora
 
 	return r0, r1
 }
+
+// Negotiate provides a mock function with given fields: _a0, _a1
+func (_m *PluginCapabilitiesServer) Negotiate(_a0 context.Context, _a1 *storage_v1.NegotiateRequest) (*storage_v1.NegotiateResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *storage_v1.NegotiateResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *storage_v1.NegotiateRequest) *storage_v1.NegotiateResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage_v1.NegotiateResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *storage_v1.NegotiateRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
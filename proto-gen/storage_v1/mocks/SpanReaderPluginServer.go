@@ -14,6 +14,29 @@ type SpanReaderPluginServer struct {
 	mock.Mock
 }
 
+// FindOperations provides a mock function with given fields: _a0, _a1
+func (_m *SpanReaderPluginServer) FindOperations(_a0 context.Context, _a1 *storage_v1.GetOperationsRequest) (*storage_v1.GetOperationsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *storage_v1.GetOperationsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *storage_v1.GetOperationsRequest) *storage_v1.GetOperationsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage_v1.GetOperationsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *storage_v1.GetOperationsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindTraceIDs provides a mock function with given fields: _a0, _a1
 func (_m *SpanReaderPluginServer) FindTraceIDs(_a0 context.Context, _a1 *storage_v1.FindTraceIDsRequest) (*storage_v1.FindTraceIDsResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -51,6 +74,20 @@ func (_m *SpanReaderPluginServer) FindTraces(_a0 *storage_v1.FindTracesRequest,
 	return r0
 }
 
+// FindTracesPage provides a mock function with given fields: _a0, _a1
+func (_m *SpanReaderPluginServer) FindTracesPage(_a0 *storage_v1.FindTracesRequest, _a1 storage_v1.SpanReaderPlugin_FindTracesPageServer) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*storage_v1.FindTracesRequest, storage_v1.SpanReaderPlugin_FindTracesPageServer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetOperations provides a mock function with given fields: _a0, _a1
 func (_m *SpanReaderPluginServer) GetOperations(_a0 context.Context, _a1 *storage_v1.GetOperationsRequest) (*storage_v1.GetOperationsResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -303,10 +303,16 @@ func (m *CloseWriterResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_CloseWriterResponse proto.InternalMessageInfo
 
 type GetTraceRequest struct {
-	TraceID              github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id"`
-	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
-	XXX_unrecognized     []byte                                        `json:"-"`
-	XXX_sizecache        int32                                         `json:"-"`
+	TraceID github_com_jaegertracing_jaeger_model.TraceID `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3,customtype=github.com/jaegertracing/jaeger/model.TraceID" json:"trace_id"`
+	// Optional hint that the spans of the trace started at or after start_time,
+	// allowing the storage to skip the older partitions. Ignored if not set.
+	StartTime *time.Time `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3,stdtime" json:"start_time,omitempty"`
+	// Optional hint that the spans of the trace started at or before end_time,
+	// allowing the storage to skip the newer partitions. Ignored if not set.
+	EndTime              *time.Time `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3,stdtime" json:"end_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *GetTraceRequest) Reset()         { *m = GetTraceRequest{} }
@@ -342,6 +348,20 @@ func (m *GetTraceRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_GetTraceRequest proto.InternalMessageInfo
 
+func (m *GetTraceRequest) GetStartTime() *time.Time {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *GetTraceRequest) GetEndTime() *time.Time {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
 type GetServicesRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -429,8 +449,16 @@ func (m *GetServicesResponse) GetServices() []string {
 }
 
 type GetOperationsRequest struct {
-	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
-	SpanKind             string   `protobuf:"bytes,2,opt,name=span_kind,json=spanKind,proto3" json:"span_kind,omitempty"`
+	Service  string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	SpanKind string `protobuf:"bytes,2,opt,name=span_kind,json=spanKind,proto3" json:"span_kind,omitempty"`
+	// Optional, used by FindOperations. Restricts the operations to the names starting with the prefix.
+	NamePrefix string `protobuf:"bytes,3,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	// Optional, used by FindOperations. Restricts the operations to the names containing the string.
+	NameContains string `protobuf:"bytes,4,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"`
+	// Optional, used by FindOperations. The number of operations, sorted by name and span kind, to skip.
+	Offset int32 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Optional, used by FindOperations. The maximum number of operations to return.
+	Limit                int32    `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -483,6 +511,34 @@ func (m *GetOperationsRequest) GetSpanKind() string {
 	return ""
 }
 
+func (m *GetOperationsRequest) GetNamePrefix() string {
+	if m != nil {
+		return m.NamePrefix
+	}
+	return ""
+}
+
+func (m *GetOperationsRequest) GetNameContains() string {
+	if m != nil {
+		return m.NameContains
+	}
+	return ""
+}
+
+func (m *GetOperationsRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *GetOperationsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
 type Operation struct {
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	SpanKind             string   `protobuf:"bytes,2,opt,name=span_kind,json=spanKind,proto3" json:"span_kind,omitempty"`
@@ -594,17 +650,19 @@ func (m *GetOperationsResponse) GetOperations() []*Operation {
 }
 
 type TraceQueryParameters struct {
-	ServiceName          string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
-	OperationName        string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
-	Tags                 map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	StartTimeMin         time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
-	StartTimeMax         time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
-	DurationMin          time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
-	DurationMax          time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
-	NumTraces            int32             `protobuf:"varint,8,opt,name=num_traces,json=numTraces,proto3" json:"num_traces,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	ServiceName   string            `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	OperationName string            `protobuf:"bytes,2,opt,name=operation_name,json=operationName,proto3" json:"operation_name,omitempty"`
+	Tags          map[string]string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StartTimeMin  time.Time         `protobuf:"bytes,4,opt,name=start_time_min,json=startTimeMin,proto3,stdtime" json:"start_time_min"`
+	StartTimeMax  time.Time         `protobuf:"bytes,5,opt,name=start_time_max,json=startTimeMax,proto3,stdtime" json:"start_time_max"`
+	DurationMin   time.Duration     `protobuf:"bytes,6,opt,name=duration_min,json=durationMin,proto3,stdduration" json:"duration_min"`
+	DurationMax   time.Duration     `protobuf:"bytes,7,opt,name=duration_max,json=durationMax,proto3,stdduration" json:"duration_max"`
+	NumTraces     int32             `protobuf:"varint,8,opt,name=num_traces,json=numTraces,proto3" json:"num_traces,omitempty"`
+	// Opaque cursor returned in SpansResponseChunk.next_page_token of FindTracesPage.
+	PageToken            string   `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TraceQueryParameters) Reset()         { *m = TraceQueryParameters{} }
@@ -696,6 +754,13 @@ func (m *TraceQueryParameters) GetNumTraces() int32 {
 	return 0
 }
 
+func (m *TraceQueryParameters) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
 type FindTracesRequest struct {
 	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -744,10 +809,12 @@ func (m *FindTracesRequest) GetQuery() *TraceQueryParameters {
 }
 
 type SpansResponseChunk struct {
-	Spans                []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Spans []model.Span `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans"`
+	// Set by FindTracesPage on the last chunk of the stream when more results are available.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SpansResponseChunk) Reset()         { *m = SpansResponseChunk{} }
@@ -790,6 +857,13 @@ func (m *SpansResponseChunk) GetSpans() []model.Span {
 	return nil
 }
 
+func (m *SpansResponseChunk) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type FindTraceIDsRequest struct {
 	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -877,6 +951,181 @@ func (m *FindTraceIDsResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_FindTraceIDsResponse proto.InternalMessageInfo
 
+type PurgeTracesRequest struct {
+	// The query selecting the traces to delete, the service name is required and num_traces is ignored.
+	Query                *TraceQueryParameters `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *PurgeTracesRequest) Reset()         { *m = PurgeTracesRequest{} }
+func (m *PurgeTracesRequest) String() string { return proto.CompactTextString(m) }
+func (*PurgeTracesRequest) ProtoMessage()    {}
+func (*PurgeTracesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{17}
+}
+func (m *PurgeTracesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PurgeTracesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PurgeTracesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PurgeTracesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeTracesRequest.Merge(m, src)
+}
+func (m *PurgeTracesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PurgeTracesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeTracesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeTracesRequest proto.InternalMessageInfo
+
+func (m *PurgeTracesRequest) GetQuery() *TraceQueryParameters {
+	if m != nil {
+		return m.Query
+	}
+	return nil
+}
+
+type PurgeTracesResponse struct {
+	PurgedTraces         int64    `protobuf:"varint,1,opt,name=purged_traces,json=purgedTraces,proto3" json:"purged_traces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeTracesResponse) Reset()         { *m = PurgeTracesResponse{} }
+func (m *PurgeTracesResponse) String() string { return proto.CompactTextString(m) }
+func (*PurgeTracesResponse) ProtoMessage()    {}
+func (*PurgeTracesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{18}
+}
+func (m *PurgeTracesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PurgeTracesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PurgeTracesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PurgeTracesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeTracesResponse.Merge(m, src)
+}
+func (m *PurgeTracesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PurgeTracesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeTracesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeTracesResponse proto.InternalMessageInfo
+
+func (m *PurgeTracesResponse) GetPurgedTraces() int64 {
+	if m != nil {
+		return m.PurgedTraces
+	}
+	return 0
+}
+
+// empty; extensible in the future
+type PurgeAllRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeAllRequest) Reset()         { *m = PurgeAllRequest{} }
+func (m *PurgeAllRequest) String() string { return proto.CompactTextString(m) }
+func (*PurgeAllRequest) ProtoMessage()    {}
+func (*PurgeAllRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{19}
+}
+func (m *PurgeAllRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PurgeAllRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PurgeAllRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PurgeAllRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeAllRequest.Merge(m, src)
+}
+func (m *PurgeAllRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PurgeAllRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeAllRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeAllRequest proto.InternalMessageInfo
+
+// empty; extensible in the future
+type PurgeAllResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeAllResponse) Reset()         { *m = PurgeAllResponse{} }
+func (m *PurgeAllResponse) String() string { return proto.CompactTextString(m) }
+func (*PurgeAllResponse) ProtoMessage()    {}
+func (*PurgeAllResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{20}
+}
+func (m *PurgeAllResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PurgeAllResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PurgeAllResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PurgeAllResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeAllResponse.Merge(m, src)
+}
+func (m *PurgeAllResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PurgeAllResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeAllResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeAllResponse proto.InternalMessageInfo
+
 // empty; extensible in the future
 type CapabilitiesRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -888,7 +1137,7 @@ func (m *CapabilitiesRequest) Reset()         { *m = CapabilitiesRequest{} }
 func (m *CapabilitiesRequest) String() string { return proto.CompactTextString(m) }
 func (*CapabilitiesRequest) ProtoMessage()    {}
 func (*CapabilitiesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{17}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{21}
 }
 func (m *CapabilitiesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -930,7 +1179,7 @@ func (m *CapabilitiesResponse) Reset()         { *m = CapabilitiesResponse{} }
 func (m *CapabilitiesResponse) String() string { return proto.CompactTextString(m) }
 func (*CapabilitiesResponse) ProtoMessage()    {}
 func (*CapabilitiesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0d2c4ccf1453ffdb, []int{18}
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{22}
 }
 func (m *CapabilitiesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -980,6 +1229,121 @@ func (m *CapabilitiesResponse) GetStreamingSpanWriter() bool {
 	return false
 }
 
+type NegotiateRequest struct {
+	// Semantic version of the storage API implemented by the client, e.g. "1.1.0".
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Optional features known by the client, e.g. "streaming_span_writer".
+	Features             []string `protobuf:"bytes,2,rep,name=features,proto3" json:"features,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NegotiateRequest) Reset()         { *m = NegotiateRequest{} }
+func (m *NegotiateRequest) String() string { return proto.CompactTextString(m) }
+func (*NegotiateRequest) ProtoMessage()    {}
+func (*NegotiateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{23}
+}
+func (m *NegotiateRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NegotiateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NegotiateRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NegotiateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NegotiateRequest.Merge(m, src)
+}
+func (m *NegotiateRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *NegotiateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NegotiateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NegotiateRequest proto.InternalMessageInfo
+
+func (m *NegotiateRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *NegotiateRequest) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+type NegotiateResponse struct {
+	// Semantic version of the storage API implemented by the plugin.
+	// The client and the plugin are compatible if their major versions are equal.
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Optional features supported by the plugin, the features unknown to the client are ignored.
+	Features             []string `protobuf:"bytes,2,rep,name=features,proto3" json:"features,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NegotiateResponse) Reset()         { *m = NegotiateResponse{} }
+func (m *NegotiateResponse) String() string { return proto.CompactTextString(m) }
+func (*NegotiateResponse) ProtoMessage()    {}
+func (*NegotiateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{24}
+}
+func (m *NegotiateResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NegotiateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NegotiateResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NegotiateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NegotiateResponse.Merge(m, src)
+}
+func (m *NegotiateResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *NegotiateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NegotiateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NegotiateResponse proto.InternalMessageInfo
+
+func (m *NegotiateResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *NegotiateResponse) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*GetDependenciesRequest)(nil), "jaeger.storage.v1.GetDependenciesRequest")
 	proto.RegisterType((*GetDependenciesResponse)(nil), "jaeger.storage.v1.GetDependenciesResponse")
@@ -999,84 +1363,109 @@ func init() {
 	proto.RegisterType((*SpansResponseChunk)(nil), "jaeger.storage.v1.SpansResponseChunk")
 	proto.RegisterType((*FindTraceIDsRequest)(nil), "jaeger.storage.v1.FindTraceIDsRequest")
 	proto.RegisterType((*FindTraceIDsResponse)(nil), "jaeger.storage.v1.FindTraceIDsResponse")
+	proto.RegisterType((*PurgeTracesRequest)(nil), "jaeger.storage.v1.PurgeTracesRequest")
+	proto.RegisterType((*PurgeTracesResponse)(nil), "jaeger.storage.v1.PurgeTracesResponse")
+	proto.RegisterType((*PurgeAllRequest)(nil), "jaeger.storage.v1.PurgeAllRequest")
+	proto.RegisterType((*PurgeAllResponse)(nil), "jaeger.storage.v1.PurgeAllResponse")
 	proto.RegisterType((*CapabilitiesRequest)(nil), "jaeger.storage.v1.CapabilitiesRequest")
 	proto.RegisterType((*CapabilitiesResponse)(nil), "jaeger.storage.v1.CapabilitiesResponse")
+	proto.RegisterType((*NegotiateRequest)(nil), "jaeger.storage.v1.NegotiateRequest")
+	proto.RegisterType((*NegotiateResponse)(nil), "jaeger.storage.v1.NegotiateResponse")
 }
 
 func init() { proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 
 var fileDescriptor_0d2c4ccf1453ffdb = []byte{
-	// 1117 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x6f, 0xdc, 0xc4,
-	0x1b, 0xfe, 0x39, 0xd9, 0x6d, 0x76, 0xdf, 0xdd, 0xb4, 0xc9, 0xec, 0xf6, 0x57, 0xd7, 0xd0, 0x24,
-	0x18, 0x9a, 0x04, 0x04, 0xde, 0x66, 0x39, 0x80, 0xa0, 0x08, 0x9a, 0x3f, 0x8d, 0x02, 0x14, 0x8a,
-	0x13, 0xb5, 0x12, 0x85, 0xac, 0x66, 0xe3, 0xc1, 0x19, 0xb2, 0x1e, 0x6f, 0xed, 0xf1, 0x2a, 0x11,
-	0xe2, 0xc6, 0x07, 0xe0, 0xc8, 0x89, 0x13, 0x12, 0xdf, 0x83, 0x53, 0x8f, 0x9c, 0x39, 0x04, 0x94,
-	0x2b, 0x5f, 0x02, 0x79, 0x66, 0xec, 0xd8, 0x6b, 0x2b, 0x49, 0xa3, 0xdc, 0x3c, 0xef, 0x3c, 0xf3,
-	0xbc, 0xff, 0x66, 0x9e, 0xd7, 0x30, 0x1d, 0x72, 0x3f, 0xc0, 0x2e, 0xb1, 0x86, 0x81, 0xcf, 0x7d,
-	0x34, 0xfb, 0x3d, 0x26, 0x2e, 0x09, 0xac, 0xc4, 0x3a, 0x5a, 0x31, 0xda, 0xae, 0xef, 0xfa, 0x62,
-	0xb7, 0x13, 0x7f, 0x49, 0xa0, 0x31, 0xef, 0xfa, 0xbe, 0x3b, 0x20, 0x1d, 0xb1, 0xea, 0x47, 0xdf,
-	0x75, 0x38, 0xf5, 0x48, 0xc8, 0xb1, 0x37, 0x54, 0x80, 0xb9, 0x71, 0x80, 0x13, 0x05, 0x98, 0x53,
-	0x9f, 0xa9, 0xfd, 0x86, 0xe7, 0x3b, 0x64, 0x20, 0x17, 0xe6, 0xaf, 0x1a, 0xfc, 0x7f, 0x93, 0xf0,
-	0x75, 0x32, 0x24, 0xcc, 0x21, 0x6c, 0x8f, 0x92, 0xd0, 0x26, 0xcf, 0x23, 0x12, 0x72, 0xb4, 0x06,
-	0x10, 0x72, 0x1c, 0xf0, 0x5e, 0xec, 0x40, 0xd7, 0x16, 0xb4, 0xe5, 0x46, 0xd7, 0xb0, 0x24, 0xb9,
-	0x95, 0x90, 0x5b, 0x3b, 0x89, 0xf7, 0xd5, 0xda, 0x8b, 0xe3, 0xf9, 0xff, 0xfd, 0xfc, 0xf7, 0xbc,
-	0x66, 0xd7, 0xc5, 0xb9, 0x78, 0x07, 0x7d, 0x0c, 0x35, 0xc2, 0x1c, 0x49, 0x31, 0xf1, 0x12, 0x14,
-	0x53, 0x84, 0x39, 0xb1, 0xdd, 0xec, 0xc3, 0xad, 0x42, 0x7c, 0xe1, 0xd0, 0x67, 0x21, 0x41, 0x9b,
-	0xd0, 0x74, 0x32, 0x76, 0x5d, 0x5b, 0x98, 0x5c, 0x6e, 0x74, 0xef, 0x58, 0xaa, 0x92, 0x78, 0x48,
-	0x7b, 0xa3, 0xae, 0x95, 0x1e, 0x3d, 0xfa, 0x9c, 0xb2, 0x83, 0xd5, 0x4a, 0xec, 0xc2, 0xce, 0x1d,
-	0x34, 0x3f, 0x84, 0x99, 0xa7, 0x01, 0xe5, 0x64, 0x7b, 0x88, 0x59, 0x92, 0xfd, 0x12, 0x54, 0xc2,
-	0x21, 0x66, 0x2a, 0xef, 0xd6, 0x18, 0xa9, 0x40, 0x0a, 0x80, 0xd9, 0x82, 0xd9, 0xcc, 0x61, 0x19,
-	0x9a, 0xd9, 0x06, 0xb4, 0x36, 0xf0, 0x43, 0x22, 0x76, 0x02, 0xc5, 0x69, 0xde, 0x84, 0x56, 0xce,
-	0xaa, 0xc0, 0x0c, 0x6e, 0x6c, 0x12, 0xbe, 0x13, 0xe0, 0x3d, 0x92, 0x78, 0x7f, 0x06, 0x35, 0x1e,
-	0xaf, 0x7b, 0xd4, 0x11, 0x11, 0x34, 0x57, 0x3f, 0x89, 0xe3, 0xfe, 0xeb, 0x78, 0xfe, 0x1d, 0x97,
-	0xf2, 0xfd, 0xa8, 0x6f, 0xed, 0xf9, 0x5e, 0x47, 0xc6, 0x14, 0x03, 0x29, 0x73, 0xd5, 0xaa, 0x23,
-	0xbb, 0x2b, 0xd8, 0xb6, 0xd6, 0x4f, 0x8e, 0xe7, 0xa7, 0xd4, 0xa7, 0x3d, 0x25, 0x18, 0xb7, 0x9c,
-	0x38, 0xb8, 0x4d, 0xc2, 0xb7, 0x49, 0x30, 0xa2, 0x7b, 0x69, 0xbb, 0xcd, 0x15, 0x68, 0xe5, 0xac,
-	0xaa, 0xc8, 0x06, 0xd4, 0x42, 0x65, 0x13, 0x05, 0xae, 0xdb, 0xe9, 0xda, 0x7c, 0x04, 0xed, 0x4d,
-	0xc2, 0xbf, 0x1c, 0x12, 0x79, 0xbf, 0xd2, 0x9b, 0xa3, 0xc3, 0x94, 0xc2, 0x88, 0xe0, 0xeb, 0x76,
-	0xb2, 0x44, 0xaf, 0x40, 0x3d, 0x2e, 0x5a, 0xef, 0x80, 0x32, 0x47, 0xdc, 0x87, 0x98, 0x6e, 0x88,
-	0xd9, 0x67, 0x94, 0x39, 0xe6, 0x7d, 0xa8, 0xa7, 0x5c, 0x08, 0x41, 0x85, 0x61, 0x2f, 0x21, 0x10,
-	0xdf, 0x67, 0x9f, 0xfe, 0x11, 0x6e, 0x8e, 0x05, 0xa3, 0x32, 0x58, 0x84, 0xeb, 0x7e, 0x62, 0xfd,
-	0x02, 0x7b, 0x69, 0x1e, 0x63, 0x56, 0x74, 0x1f, 0x20, 0xb5, 0x84, 0xfa, 0x84, 0xb8, 0x4c, 0xaf,
-	0x5a, 0x85, 0x67, 0x69, 0xa5, 0x2e, 0xec, 0x0c, 0xde, 0xfc, 0xbd, 0x02, 0x6d, 0x51, 0xe9, 0xaf,
-	0x22, 0x12, 0x1c, 0x3d, 0xc6, 0x01, 0xf6, 0x08, 0x27, 0x41, 0x88, 0x5e, 0x83, 0xa6, 0xca, 0xbe,
-	0x97, 0x49, 0xa8, 0xa1, 0x6c, 0xb1, 0x6b, 0x74, 0x37, 0x13, 0xa1, 0x04, 0xc9, 0xe4, 0xa6, 0x73,
-	0x11, 0xa2, 0x0d, 0xa8, 0x70, 0xec, 0x86, 0xfa, 0xa4, 0x08, 0x6d, 0xa5, 0x24, 0xb4, 0xb2, 0x00,
-	0xac, 0x1d, 0xec, 0x86, 0x1b, 0x8c, 0x07, 0x47, 0xb6, 0x38, 0x8e, 0x3e, 0x85, 0xeb, 0xa7, 0xef,
-	0xba, 0xe7, 0x51, 0xa6, 0x57, 0x5e, 0xe2, 0x61, 0x36, 0xd3, 0xb7, 0xfd, 0x88, 0xb2, 0x71, 0x2e,
-	0x7c, 0xa8, 0x57, 0x2f, 0xc7, 0x85, 0x0f, 0xd1, 0x43, 0x68, 0x26, 0x4a, 0x25, 0xa2, 0xba, 0x26,
-	0x98, 0x6e, 0x17, 0x98, 0xd6, 0x15, 0x48, 0x12, 0xfd, 0x12, 0x13, 0x35, 0x92, 0x83, 0x71, 0x4c,
-	0x39, 0x1e, 0x7c, 0xa8, 0x4f, 0x5d, 0x86, 0x07, 0x1f, 0xa2, 0x3b, 0x00, 0x2c, 0xf2, 0x7a, 0xe2,
-	0xd5, 0x84, 0x7a, 0x6d, 0x41, 0x5b, 0xae, 0xda, 0x75, 0x16, 0x79, 0xa2, 0xc8, 0xa1, 0xf1, 0x1e,
-	0xd4, 0xd3, 0xca, 0xa2, 0x19, 0x98, 0x3c, 0x20, 0x47, 0xaa, 0xb7, 0xf1, 0x27, 0x6a, 0x43, 0x75,
-	0x84, 0x07, 0x51, 0xd2, 0x4a, 0xb9, 0xf8, 0x60, 0xe2, 0x7d, 0xcd, 0xb4, 0x61, 0xf6, 0x21, 0x65,
-	0x8e, 0xa4, 0x49, 0x9e, 0xcc, 0x47, 0x50, 0x7d, 0x1e, 0xf7, 0x4d, 0xe9, 0xcd, 0xd2, 0x05, 0x9b,
-	0x6b, 0xcb, 0x53, 0xe6, 0x06, 0xa0, 0x58, 0x7f, 0xd2, 0x4b, 0xbf, 0xb6, 0x1f, 0xb1, 0x03, 0xd4,
-	0x81, 0x6a, 0xfc, 0x3c, 0x12, 0x65, 0x2c, 0x13, 0x31, 0xa5, 0x87, 0x12, 0x67, 0xee, 0x40, 0x2b,
-	0x0d, 0x6d, 0x6b, 0xfd, 0xaa, 0x82, 0x1b, 0x41, 0x3b, 0xcf, 0xaa, 0x1e, 0xe6, 0x2e, 0xd4, 0x13,
-	0x91, 0x93, 0x21, 0x36, 0x57, 0x1f, 0x5c, 0x56, 0xe5, 0x6a, 0x29, 0x7b, 0x4d, 0xc9, 0x5c, 0x28,
-	0xe4, 0x16, 0x0f, 0x71, 0x9f, 0x0e, 0x28, 0x3f, 0x9d, 0x6b, 0xe6, 0x6f, 0x1a, 0xb4, 0xf3, 0x76,
-	0x15, 0xcf, 0xdb, 0x30, 0x8b, 0x83, 0xbd, 0x7d, 0x3a, 0x52, 0x5a, 0x8e, 0x1d, 0x12, 0x88, 0x94,
-	0x6b, 0x76, 0x71, 0x63, 0x0c, 0x2d, 0x25, 0x5d, 0x34, 0x3b, 0x8f, 0x96, 0x1b, 0xe8, 0x1e, 0xb4,
-	0x42, 0x1e, 0x10, 0xec, 0x51, 0xe6, 0x66, 0xf0, 0x93, 0x02, 0x5f, 0xb6, 0xd5, 0xfd, 0x43, 0x83,
-	0x99, 0xd3, 0xe5, 0xe3, 0x41, 0xe4, 0x52, 0x86, 0x9e, 0x40, 0x3d, 0x1d, 0x36, 0xe8, 0xf5, 0x92,
-	0x3e, 0x8c, 0xcf, 0x31, 0xe3, 0x8d, 0xb3, 0x41, 0x2a, 0xf5, 0x27, 0x50, 0x15, 0x93, 0x09, 0xdd,
-	0x2d, 0x81, 0x17, 0x27, 0x99, 0xb1, 0x78, 0x1e, 0x4c, 0xf2, 0x76, 0x7f, 0x80, 0xdb, 0xdb, 0xc5,
-	0xdc, 0x54, 0x32, 0xbb, 0x70, 0x23, 0x8d, 0x44, 0xa2, 0xae, 0x30, 0xa5, 0x65, 0xad, 0xfb, 0xef,
-	0xa4, 0xac, 0xa0, 0x6c, 0x98, 0x72, 0xfa, 0x14, 0x6a, 0xc9, 0xb0, 0x45, 0x66, 0x09, 0xd1, 0xd8,
-	0x24, 0x36, 0xca, 0x0a, 0x52, 0x7c, 0x6a, 0xf7, 0x34, 0xf4, 0x0d, 0x34, 0x32, 0xf3, 0xb3, 0xb4,
-	0x90, 0xc5, 0xa9, 0x5b, 0x5a, 0xc8, 0xb2, 0x31, 0xdc, 0x87, 0xe9, 0xdc, 0x74, 0x43, 0x4b, 0xe5,
-	0x07, 0x0b, 0xc3, 0xd8, 0x58, 0x3e, 0x1f, 0xa8, 0x7c, 0x3c, 0x03, 0x38, 0x15, 0x26, 0x54, 0x56,
-	0xe5, 0x82, 0x6e, 0x5d, 0xbc, 0x3c, 0x3d, 0x68, 0x66, 0x45, 0x00, 0x2d, 0x9e, 0x45, 0x7f, 0xaa,
-	0x3d, 0xc6, 0xd2, 0xb9, 0x38, 0x75, 0xd5, 0x0e, 0xe1, 0xd6, 0x83, 0xf1, 0x67, 0xa7, 0x7a, 0xfe,
-	0xad, 0xfa, 0xbf, 0xcb, 0xec, 0x5f, 0xe1, 0x4d, 0xeb, 0x1e, 0xe5, 0x3c, 0xe7, 0x6e, 0xdb, 0xae,
-	0xf8, 0xb5, 0x53, 0xbb, 0x57, 0x7f, 0xe9, 0xba, 0x3f, 0x69, 0xa0, 0xe7, 0xff, 0x8d, 0x33, 0xce,
-	0xf7, 0x85, 0xf3, 0xec, 0x36, 0x7a, 0xb3, 0xdc, 0x79, 0xc9, 0xef, 0xbf, 0xf1, 0xd6, 0x45, 0xa0,
-	0xaa, 0x02, 0x11, 0x20, 0xe9, 0x33, 0xab, 0xab, 0x71, 0xcb, 0x73, 0xeb, 0x52, 0xd1, 0x28, 0x0a,
-	0x74, 0x69, 0xcb, 0xcb, 0x04, 0x7b, 0x55, 0x7f, 0x71, 0x32, 0xa7, 0xfd, 0x79, 0x32, 0xa7, 0xfd,
-	0x73, 0x32, 0xa7, 0x7d, 0x0d, 0x0a, 0xde, 0x1b, 0xad, 0xf4, 0xaf, 0x89, 0x29, 0xff, 0xee, 0x7f,
-	0x01, 0x00, 0x00, 0xff, 0xff, 0x6c, 0xa3, 0xc0, 0x72, 0x65, 0x0d, 0x00, 0x00,
+	// 1411 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xcd, 0x72, 0x1b, 0xc5,
+	0x16, 0xbe, 0x63, 0x4b, 0xb6, 0xe6, 0x48, 0xfe, 0x6b, 0x29, 0xc9, 0x64, 0xee, 0x8d, 0x9d, 0x3b,
+	0x4e, 0x6c, 0x43, 0x81, 0x1c, 0x8b, 0x05, 0x54, 0x12, 0x2a, 0xc4, 0x76, 0x62, 0xcc, 0x4f, 0x30,
+	0x63, 0x57, 0x52, 0x45, 0x42, 0x54, 0x6d, 0xa9, 0x3d, 0x1e, 0x2c, 0xf5, 0x28, 0x33, 0x2d, 0x95,
+	0x5c, 0x14, 0x3b, 0x1e, 0x80, 0x25, 0x2b, 0x56, 0xbc, 0x00, 0x8f, 0x10, 0x16, 0x54, 0x36, 0x54,
+	0xb1, 0x66, 0x11, 0x28, 0xaf, 0x79, 0x08, 0xaa, 0x7f, 0x66, 0x34, 0xa3, 0x99, 0xb2, 0x8c, 0xcb,
+	0xec, 0xa6, 0x4f, 0x7f, 0xfd, 0x9d, 0x73, 0xfa, 0x9c, 0xee, 0xaf, 0x25, 0x98, 0x0a, 0x98, 0xe7,
+	0x63, 0x87, 0x54, 0x3b, 0xbe, 0xc7, 0x3c, 0x34, 0xf7, 0x15, 0x26, 0x0e, 0xf1, 0xab, 0xa1, 0xb5,
+	0xb7, 0x66, 0x56, 0x1c, 0xcf, 0xf1, 0xc4, 0xec, 0x2a, 0xff, 0x92, 0x40, 0x73, 0xc1, 0xf1, 0x3c,
+	0xa7, 0x45, 0x56, 0xc5, 0x68, 0xbf, 0x7b, 0xb0, 0xca, 0xdc, 0x36, 0x09, 0x18, 0x6e, 0x77, 0x14,
+	0x60, 0x7e, 0x18, 0xd0, 0xec, 0xfa, 0x98, 0xb9, 0x1e, 0x55, 0xf3, 0xc5, 0xb6, 0xd7, 0x24, 0x2d,
+	0x39, 0xb0, 0x7e, 0xd0, 0xe0, 0xf2, 0x16, 0x61, 0x9b, 0xa4, 0x43, 0x68, 0x93, 0xd0, 0x86, 0x4b,
+	0x02, 0x9b, 0xbc, 0xe8, 0x92, 0x80, 0xa1, 0x0d, 0x80, 0x80, 0x61, 0x9f, 0xd5, 0xb9, 0x03, 0x43,
+	0xbb, 0xae, 0xad, 0x14, 0x6b, 0x66, 0x55, 0x92, 0x57, 0x43, 0xf2, 0xea, 0x5e, 0xe8, 0x7d, 0xbd,
+	0xf0, 0xea, 0xf5, 0xc2, 0x7f, 0xbe, 0xfb, 0x63, 0x41, 0xb3, 0x75, 0xb1, 0x8e, 0xcf, 0xa0, 0x7b,
+	0x50, 0x20, 0xb4, 0x29, 0x29, 0xc6, 0xfe, 0x01, 0xc5, 0x24, 0xa1, 0x4d, 0x6e, 0xb7, 0xf6, 0xe1,
+	0x4a, 0x2a, 0xbe, 0xa0, 0xe3, 0xd1, 0x80, 0xa0, 0x2d, 0x28, 0x35, 0x63, 0x76, 0x43, 0xbb, 0x3e,
+	0xbe, 0x52, 0xac, 0x5d, 0xab, 0xaa, 0x9d, 0xc4, 0x1d, 0xb7, 0xde, 0xab, 0x55, 0xa3, 0xa5, 0xc7,
+	0x9f, 0xb8, 0xf4, 0x68, 0x3d, 0xc7, 0x5d, 0xd8, 0x89, 0x85, 0xd6, 0x1d, 0x98, 0x7d, 0xe2, 0xbb,
+	0x8c, 0xec, 0x76, 0x30, 0x0d, 0xb3, 0x5f, 0x86, 0x5c, 0xd0, 0xc1, 0x54, 0xe5, 0x5d, 0x1e, 0x22,
+	0x15, 0x48, 0x01, 0xb0, 0xca, 0x30, 0x17, 0x5b, 0x2c, 0x43, 0xb3, 0x2a, 0x80, 0x36, 0x5a, 0x5e,
+	0x40, 0xc4, 0x8c, 0xaf, 0x38, 0xad, 0x4b, 0x50, 0x4e, 0x58, 0x15, 0xf8, 0x2f, 0x0d, 0x66, 0xb6,
+	0x08, 0xdb, 0xf3, 0x71, 0x83, 0x84, 0xee, 0x9f, 0x42, 0x81, 0xf1, 0x71, 0xdd, 0x6d, 0x8a, 0x10,
+	0x4a, 0xeb, 0x1f, 0xf0, 0xc0, 0x7f, 0x7f, 0xbd, 0xf0, 0xb6, 0xe3, 0xb2, 0xc3, 0xee, 0x7e, 0xb5,
+	0xe1, 0xb5, 0x57, 0x65, 0x50, 0x1c, 0xe8, 0x52, 0x47, 0x8d, 0x56, 0x65, 0x79, 0x05, 0xdb, 0xf6,
+	0xe6, 0xc9, 0xeb, 0x85, 0x49, 0xf5, 0x69, 0x4f, 0x0a, 0xc6, 0xed, 0x26, 0xba, 0x97, 0xa8, 0xec,
+	0xe8, 0xb2, 0xe4, 0x86, 0xab, 0x7a, 0x27, 0x56, 0xd5, 0xf1, 0x33, 0x2e, 0x8f, 0x2a, 0x5a, 0x01,
+	0xb4, 0x45, 0xd8, 0x2e, 0xf1, 0x7b, 0x6e, 0x23, 0xea, 0x36, 0x6b, 0x0d, 0xca, 0x09, 0xab, 0xaa,
+	0xb1, 0x09, 0x85, 0x40, 0xd9, 0x44, 0x7d, 0x75, 0x3b, 0x1a, 0x5b, 0x2f, 0x35, 0xa8, 0x6c, 0x11,
+	0xf6, 0x59, 0x87, 0xc8, 0xfe, 0x8e, 0x3a, 0xd7, 0x80, 0x49, 0x05, 0x12, 0x7b, 0xa7, 0xdb, 0xe1,
+	0x10, 0xfd, 0x17, 0x74, 0x5e, 0xb4, 0xfa, 0x91, 0x4b, 0x9b, 0x22, 0x71, 0xce, 0xd7, 0xc1, 0xf4,
+	0x63, 0x97, 0x36, 0xd1, 0x02, 0x14, 0x29, 0x6e, 0x93, 0x7a, 0xc7, 0x27, 0x07, 0x6e, 0x5f, 0x24,
+	0xa6, 0xdb, 0xc0, 0x4d, 0x3b, 0xc2, 0x82, 0x16, 0x61, 0x4a, 0x00, 0x1a, 0x1e, 0x65, 0xd8, 0xa5,
+	0x81, 0x91, 0x13, 0x90, 0x12, 0x37, 0x6e, 0x28, 0x1b, 0xba, 0x0c, 0x13, 0xde, 0xc1, 0x41, 0x40,
+	0x98, 0x91, 0xbf, 0xae, 0xad, 0xe4, 0x6d, 0x35, 0x42, 0x15, 0xc8, 0xb7, 0xdc, 0xb6, 0xcb, 0x8c,
+	0x09, 0x61, 0x96, 0x03, 0xeb, 0x2e, 0xe8, 0x51, 0xfc, 0x08, 0x41, 0x8e, 0x53, 0xa9, 0xa0, 0xc5,
+	0xf7, 0xa9, 0x11, 0x5b, 0xdf, 0xc0, 0xa5, 0xa1, 0x0d, 0x50, 0xdb, 0xb6, 0x04, 0xd3, 0x5e, 0x68,
+	0x7d, 0x84, 0xdb, 0xd1, 0xe6, 0x0d, 0x59, 0xd1, 0x5d, 0x80, 0xc8, 0x12, 0x18, 0x63, 0xe2, 0x00,
+	0xfd, 0xaf, 0x9a, 0xba, 0x8a, 0xaa, 0x91, 0x0b, 0x3b, 0x86, 0xb7, 0x7e, 0xc9, 0x41, 0x45, 0x34,
+	0xd7, 0xe7, 0x5d, 0xe2, 0x1f, 0xef, 0x60, 0x1f, 0xb7, 0x09, 0x23, 0x7e, 0x80, 0xfe, 0x0f, 0x25,
+	0xb5, 0xe3, 0xf5, 0x58, 0x42, 0x45, 0x65, 0xe3, 0xae, 0xd1, 0xcd, 0x58, 0x84, 0x12, 0x24, 0x93,
+	0x9b, 0x4a, 0x44, 0x88, 0x1e, 0x40, 0x8e, 0x61, 0x27, 0x30, 0xc6, 0x45, 0x68, 0x6b, 0x19, 0xa1,
+	0x65, 0x05, 0x50, 0xdd, 0xc3, 0x4e, 0xf0, 0x80, 0x32, 0xff, 0xd8, 0x16, 0xcb, 0xd1, 0x47, 0x30,
+	0x3d, 0xe8, 0xf8, 0x7a, 0xdb, 0xa5, 0x46, 0x6e, 0x64, 0xdb, 0x0e, 0x2e, 0xa3, 0x52, 0xd4, 0xf9,
+	0x9f, 0xba, 0x74, 0x98, 0x0b, 0xf7, 0x8d, 0xfc, 0xf9, 0xb8, 0x70, 0x1f, 0x3d, 0x84, 0x52, 0x78,
+	0x3b, 0x8b, 0xa8, 0x26, 0x04, 0xd3, 0xd5, 0x14, 0xd3, 0xa6, 0x02, 0x49, 0xa2, 0xef, 0x39, 0x51,
+	0x31, 0x5c, 0xc8, 0x63, 0x4a, 0xf0, 0xe0, 0xbe, 0x31, 0x79, 0x1e, 0x1e, 0xdc, 0x47, 0xd7, 0x00,
+	0x68, 0xb7, 0x5d, 0x17, 0x17, 0x45, 0x60, 0x14, 0x44, 0xa7, 0xea, 0xb4, 0xdb, 0x16, 0x9b, 0x1c,
+	0xf0, 0xe9, 0x0e, 0x76, 0x48, 0x9d, 0x79, 0x47, 0x84, 0x1a, 0xba, 0x28, 0x98, 0xce, 0x2d, 0x7b,
+	0xdc, 0x60, 0xbe, 0x0b, 0x7a, 0xb4, 0xf1, 0x68, 0x16, 0xc6, 0x8f, 0xc8, 0xb1, 0x2a, 0x3d, 0xff,
+	0xe4, 0x27, 0xa0, 0x87, 0x5b, 0xdd, 0xb0, 0xd2, 0x72, 0x70, 0x7b, 0xec, 0x3d, 0xcd, 0xb2, 0x61,
+	0xee, 0xa1, 0x4b, 0x9b, 0xd2, 0x4b, 0x78, 0x8a, 0xdf, 0x87, 0xfc, 0x0b, 0x5e, 0x56, 0x75, 0x05,
+	0x2f, 0x9f, 0xb1, 0xf6, 0xb6, 0x5c, 0x65, 0xb5, 0x01, 0xf1, 0x2b, 0x39, 0x3a, 0x13, 0x1b, 0x87,
+	0x5d, 0x7a, 0x84, 0x56, 0x21, 0xcf, 0x4f, 0x4f, 0x28, 0x16, 0x59, 0xf7, 0xba, 0x92, 0x08, 0x89,
+	0x43, 0x4b, 0x30, 0x43, 0x49, 0x9f, 0xd5, 0x63, 0x79, 0xab, 0x46, 0xe5, 0xe6, 0x9d, 0x30, 0x77,
+	0x6b, 0x0f, 0xca, 0x51, 0x0a, 0xdb, 0x9b, 0x17, 0x95, 0x44, 0x0f, 0x2a, 0x49, 0x56, 0x75, 0xbe,
+	0x9f, 0x83, 0x1e, 0xca, 0x83, 0x4c, 0xa5, 0xb4, 0x7e, 0xff, 0xbc, 0xfa, 0x50, 0x88, 0xd8, 0x0b,
+	0x4a, 0x20, 0x02, 0x6b, 0x17, 0xd0, 0x4e, 0xd7, 0x77, 0xc8, 0x85, 0x56, 0xe4, 0x36, 0x94, 0x13,
+	0xa4, 0x2a, 0x97, 0x45, 0x98, 0xea, 0x70, 0x73, 0x33, 0x6c, 0x3b, 0xce, 0x3e, 0x6e, 0x97, 0xa4,
+	0x51, 0x82, 0xad, 0x39, 0x98, 0x11, 0x6b, 0xef, 0xb7, 0x5a, 0xa1, 0x62, 0x20, 0x98, 0x1d, 0x98,
+	0x94, 0x94, 0x72, 0x85, 0xc5, 0x1d, 0xbc, 0xef, 0xb6, 0x5c, 0x36, 0x78, 0xca, 0x58, 0x3f, 0x6a,
+	0x50, 0x49, 0xda, 0x95, 0xef, 0xb7, 0x60, 0x0e, 0xfb, 0x8d, 0x43, 0xb7, 0xa7, 0xe4, 0x1b, 0x37,
+	0x89, 0x2f, 0xfc, 0x17, 0xec, 0xf4, 0xc4, 0x10, 0x5a, 0xaa, 0xb8, 0x31, 0x96, 0x42, 0xcb, 0x09,
+	0x74, 0x0b, 0xca, 0x01, 0xf3, 0x09, 0x6e, 0xbb, 0xd4, 0x89, 0xe1, 0xc7, 0x05, 0x3e, 0x6b, 0xca,
+	0xfa, 0x10, 0x66, 0x1f, 0x11, 0xc7, 0x63, 0x2e, 0x66, 0x24, 0xa6, 0x65, 0x3d, 0xe2, 0x07, 0xae,
+	0x47, 0x43, 0x2d, 0x53, 0x43, 0x2e, 0x8d, 0x07, 0x04, 0xb3, 0xae, 0x4f, 0xe4, 0xcd, 0xad, 0xdb,
+	0xd1, 0xd8, 0xda, 0x86, 0xb9, 0x18, 0x93, 0x4a, 0xf6, 0x5c, 0x54, 0xb5, 0x9f, 0x35, 0x98, 0x1d,
+	0xc4, 0xb8, 0xd3, 0xea, 0x3a, 0x2e, 0x45, 0x8f, 0x41, 0x8f, 0x1e, 0x3d, 0x68, 0x31, 0xa3, 0x0f,
+	0x86, 0xdf, 0x53, 0xe6, 0x8d, 0xd3, 0x41, 0x2a, 0xc4, 0xc7, 0x90, 0x17, 0x2f, 0x24, 0x74, 0x33,
+	0x03, 0x9e, 0x7e, 0x51, 0x99, 0x4b, 0xa3, 0x60, 0x92, 0xb7, 0xf6, 0x35, 0x5c, 0xdd, 0x4d, 0x6f,
+	0xb8, 0x4a, 0xe6, 0x39, 0xcc, 0x44, 0x91, 0x48, 0xd4, 0x05, 0xa6, 0xb4, 0xa2, 0xd5, 0x7e, 0xca,
+	0xc3, 0xec, 0xa0, 0x8b, 0x94, 0xd3, 0x27, 0x50, 0x08, 0xdf, 0x7c, 0xc8, 0xca, 0x20, 0x1a, 0x7a,
+	0x10, 0x9a, 0x59, 0x1b, 0x92, 0xbe, 0xdf, 0x6e, 0x69, 0xe8, 0x19, 0x14, 0x63, 0x0f, 0xa9, 0xcc,
+	0x8d, 0x4c, 0x3f, 0xbf, 0xcc, 0xa5, 0x51, 0x30, 0x55, 0xa0, 0x7d, 0x98, 0x4a, 0xbc, 0x38, 0xd0,
+	0x72, 0xf6, 0xc2, 0xd4, 0xa3, 0xcc, 0x5c, 0x19, 0x0d, 0x54, 0x3e, 0x1a, 0x30, 0xcd, 0x2f, 0xbd,
+	0x7f, 0xd7, 0xc9, 0x53, 0x80, 0x81, 0xe4, 0xa0, 0xac, 0x52, 0xa6, 0x14, 0xe9, 0xec, 0x35, 0xa8,
+	0xc3, 0xf4, 0x60, 0x35, 0xd7, 0x88, 0x8b, 0x77, 0x50, 0x8a, 0xeb, 0x02, 0x5a, 0x3a, 0x8d, 0x7e,
+	0x20, 0x47, 0xe6, 0xf2, 0x48, 0x9c, 0x3a, 0x30, 0x7d, 0xb8, 0x72, 0x7f, 0xf8, 0x46, 0x53, 0x9d,
+	0xfb, 0xa5, 0xfa, 0xb5, 0x14, 0x9b, 0xbf, 0xc0, 0xf3, 0x52, 0x3b, 0x4e, 0x78, 0x4e, 0x9c, 0x99,
+	0xe7, 0xe2, 0x77, 0x92, 0x9a, 0xbd, 0xf8, 0xa3, 0x53, 0x7b, 0xa9, 0x41, 0x49, 0x48, 0x4a, 0xe8,
+	0xf0, 0x19, 0x14, 0x63, 0x8a, 0x95, 0x79, 0x96, 0xd2, 0x32, 0x69, 0x2e, 0x8d, 0x82, 0xa9, 0x16,
+	0xdc, 0x85, 0x42, 0x28, 0x60, 0x99, 0x79, 0x0c, 0x09, 0x9e, 0xb9, 0x78, 0x2a, 0x46, 0x6d, 0xdf,
+	0xb7, 0x1a, 0x18, 0xc9, 0x5f, 0xcb, 0xb1, 0x0d, 0x3c, 0x14, 0x1b, 0x18, 0x9f, 0x46, 0x6f, 0x64,
+	0x6f, 0x60, 0xc6, 0x1f, 0x02, 0xe6, 0x9b, 0x67, 0x81, 0xaa, 0x30, 0x7e, 0xd5, 0x00, 0x49, 0xa7,
+	0x71, 0xdd, 0xe5, 0x7d, 0x9b, 0x18, 0x67, 0xde, 0xdf, 0x69, 0x01, 0x37, 0x97, 0x47, 0xe2, 0x22,
+	0x01, 0xd1, 0x23, 0xe1, 0xcb, 0xec, 0xca, 0x61, 0x81, 0x35, 0x6f, 0x9c, 0x0e, 0x92, 0xbc, 0xeb,
+	0xc6, 0xab, 0x93, 0x79, 0xed, 0xb7, 0x93, 0x79, 0xed, 0xcf, 0x93, 0x79, 0xed, 0x0b, 0x50, 0xd8,
+	0x7a, 0x6f, 0x6d, 0x7f, 0x42, 0x3c, 0xae, 0xdf, 0xf9, 0x7b, 0x00, 0x02, 0x80, 0x00, 0x0b, 0xd0,
+	0x11, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1311,7 +1700,9 @@ type SpanReaderPluginClient interface {
 	GetTrace(ctx context.Context, in *GetTraceRequest, opts ...grpc.CallOption) (SpanReaderPlugin_GetTraceClient, error)
 	GetServices(ctx context.Context, in *GetServicesRequest, opts ...grpc.CallOption) (*GetServicesResponse, error)
 	GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error)
+	FindOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error)
 	FindTraces(ctx context.Context, in *FindTracesRequest, opts ...grpc.CallOption) (SpanReaderPlugin_FindTracesClient, error)
+	FindTracesPage(ctx context.Context, in *FindTracesRequest, opts ...grpc.CallOption) (SpanReaderPlugin_FindTracesPageClient, error)
 	FindTraceIDs(ctx context.Context, in *FindTraceIDsRequest, opts ...grpc.CallOption) (*FindTraceIDsResponse, error)
 }
 
@@ -1373,6 +1764,15 @@ func (c *spanReaderPluginClient) GetOperations(ctx context.Context, in *GetOpera
 	return out, nil
 }
 
+func (c *spanReaderPluginClient) FindOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error) {
+	out := new(GetOperationsResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.storage.v1.SpanReaderPlugin/FindOperations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *spanReaderPluginClient) FindTraces(ctx context.Context, in *FindTracesRequest, opts ...grpc.CallOption) (SpanReaderPlugin_FindTracesClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_SpanReaderPlugin_serviceDesc.Streams[1], "/jaeger.storage.v1.SpanReaderPlugin/FindTraces", opts...)
 	if err != nil {
@@ -1405,22 +1805,56 @@ func (x *spanReaderPluginFindTracesClient) Recv() (*SpansResponseChunk, error) {
 	return m, nil
 }
 
-func (c *spanReaderPluginClient) FindTraceIDs(ctx context.Context, in *FindTraceIDsRequest, opts ...grpc.CallOption) (*FindTraceIDsResponse, error) {
-	out := new(FindTraceIDsResponse)
-	err := c.cc.Invoke(ctx, "/jaeger.storage.v1.SpanReaderPlugin/FindTraceIDs", in, out, opts...)
+func (c *spanReaderPluginClient) FindTracesPage(ctx context.Context, in *FindTracesRequest, opts ...grpc.CallOption) (SpanReaderPlugin_FindTracesPageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SpanReaderPlugin_serviceDesc.Streams[2], "/jaeger.storage.v1.SpanReaderPlugin/FindTracesPage", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &spanReaderPluginFindTracesPageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-// SpanReaderPluginServer is the server API for SpanReaderPlugin service.
-type SpanReaderPluginServer interface {
-	// spanstore/Reader
-	GetTrace(*GetTraceRequest, SpanReaderPlugin_GetTraceServer) error
+type SpanReaderPlugin_FindTracesPageClient interface {
+	Recv() (*SpansResponseChunk, error)
+	grpc.ClientStream
+}
+
+type spanReaderPluginFindTracesPageClient struct {
+	grpc.ClientStream
+}
+
+func (x *spanReaderPluginFindTracesPageClient) Recv() (*SpansResponseChunk, error) {
+	m := new(SpansResponseChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *spanReaderPluginClient) FindTraceIDs(ctx context.Context, in *FindTraceIDsRequest, opts ...grpc.CallOption) (*FindTraceIDsResponse, error) {
+	out := new(FindTraceIDsResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.storage.v1.SpanReaderPlugin/FindTraceIDs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SpanReaderPluginServer is the server API for SpanReaderPlugin service.
+type SpanReaderPluginServer interface {
+	// spanstore/Reader
+	GetTrace(*GetTraceRequest, SpanReaderPlugin_GetTraceServer) error
 	GetServices(context.Context, *GetServicesRequest) (*GetServicesResponse, error)
 	GetOperations(context.Context, *GetOperationsRequest) (*GetOperationsResponse, error)
+	FindOperations(context.Context, *GetOperationsRequest) (*GetOperationsResponse, error)
 	FindTraces(*FindTracesRequest, SpanReaderPlugin_FindTracesServer) error
+	FindTracesPage(*FindTracesRequest, SpanReaderPlugin_FindTracesPageServer) error
 	FindTraceIDs(context.Context, *FindTraceIDsRequest) (*FindTraceIDsResponse, error)
 }
 
@@ -1437,9 +1871,15 @@ func (*UnimplementedSpanReaderPluginServer) GetServices(ctx context.Context, req
 func (*UnimplementedSpanReaderPluginServer) GetOperations(ctx context.Context, req *GetOperationsRequest) (*GetOperationsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOperations not implemented")
 }
+func (*UnimplementedSpanReaderPluginServer) FindOperations(ctx context.Context, req *GetOperationsRequest) (*GetOperationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindOperations not implemented")
+}
 func (*UnimplementedSpanReaderPluginServer) FindTraces(req *FindTracesRequest, srv SpanReaderPlugin_FindTracesServer) error {
 	return status.Errorf(codes.Unimplemented, "method FindTraces not implemented")
 }
+func (*UnimplementedSpanReaderPluginServer) FindTracesPage(req *FindTracesRequest, srv SpanReaderPlugin_FindTracesPageServer) error {
+	return status.Errorf(codes.Unimplemented, "method FindTracesPage not implemented")
+}
 func (*UnimplementedSpanReaderPluginServer) FindTraceIDs(ctx context.Context, req *FindTraceIDsRequest) (*FindTraceIDsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindTraceIDs not implemented")
 }
@@ -1505,6 +1945,24 @@ func _SpanReaderPlugin_GetOperations_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SpanReaderPlugin_FindOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpanReaderPluginServer).FindOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.storage.v1.SpanReaderPlugin/FindOperations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpanReaderPluginServer).FindOperations(ctx, req.(*GetOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _SpanReaderPlugin_FindTraces_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(FindTracesRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1526,6 +1984,27 @@ func (x *spanReaderPluginFindTracesServer) Send(m *SpansResponseChunk) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _SpanReaderPlugin_FindTracesPage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FindTracesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SpanReaderPluginServer).FindTracesPage(m, &spanReaderPluginFindTracesPageServer{stream})
+}
+
+type SpanReaderPlugin_FindTracesPageServer interface {
+	Send(*SpansResponseChunk) error
+	grpc.ServerStream
+}
+
+type spanReaderPluginFindTracesPageServer struct {
+	grpc.ServerStream
+}
+
+func (x *spanReaderPluginFindTracesPageServer) Send(m *SpansResponseChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _SpanReaderPlugin_FindTraceIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FindTraceIDsRequest)
 	if err := dec(in); err != nil {
@@ -1556,6 +2035,10 @@ var _SpanReaderPlugin_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetOperations",
 			Handler:    _SpanReaderPlugin_GetOperations_Handler,
 		},
+		{
+			MethodName: "FindOperations",
+			Handler:    _SpanReaderPlugin_FindOperations_Handler,
+		},
 		{
 			MethodName: "FindTraceIDs",
 			Handler:    _SpanReaderPlugin_FindTraceIDs_Handler,
@@ -1572,6 +2055,11 @@ var _SpanReaderPlugin_serviceDesc = grpc.ServiceDesc{
 			Handler:       _SpanReaderPlugin_FindTraces_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "FindTracesPage",
+			Handler:       _SpanReaderPlugin_FindTracesPage_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "storage.proto",
 }
@@ -1751,6 +2239,116 @@ var _ArchiveSpanReaderPlugin_serviceDesc = grpc.ServiceDesc{
 	Metadata: "storage.proto",
 }
 
+// PurgerPluginClient is the client API for PurgerPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PurgerPluginClient interface {
+	// spanstore/Purger
+	PurgeTraces(ctx context.Context, in *PurgeTracesRequest, opts ...grpc.CallOption) (*PurgeTracesResponse, error)
+	PurgeAll(ctx context.Context, in *PurgeAllRequest, opts ...grpc.CallOption) (*PurgeAllResponse, error)
+}
+
+type purgerPluginClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPurgerPluginClient(cc *grpc.ClientConn) PurgerPluginClient {
+	return &purgerPluginClient{cc}
+}
+
+func (c *purgerPluginClient) PurgeTraces(ctx context.Context, in *PurgeTracesRequest, opts ...grpc.CallOption) (*PurgeTracesResponse, error) {
+	out := new(PurgeTracesResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.storage.v1.PurgerPlugin/PurgeTraces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *purgerPluginClient) PurgeAll(ctx context.Context, in *PurgeAllRequest, opts ...grpc.CallOption) (*PurgeAllResponse, error) {
+	out := new(PurgeAllResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.storage.v1.PurgerPlugin/PurgeAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PurgerPluginServer is the server API for PurgerPlugin service.
+type PurgerPluginServer interface {
+	// spanstore/Purger
+	PurgeTraces(context.Context, *PurgeTracesRequest) (*PurgeTracesResponse, error)
+	PurgeAll(context.Context, *PurgeAllRequest) (*PurgeAllResponse, error)
+}
+
+// UnimplementedPurgerPluginServer can be embedded to have forward compatible implementations.
+type UnimplementedPurgerPluginServer struct {
+}
+
+func (*UnimplementedPurgerPluginServer) PurgeTraces(ctx context.Context, req *PurgeTracesRequest) (*PurgeTracesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeTraces not implemented")
+}
+func (*UnimplementedPurgerPluginServer) PurgeAll(ctx context.Context, req *PurgeAllRequest) (*PurgeAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeAll not implemented")
+}
+
+func RegisterPurgerPluginServer(s *grpc.Server, srv PurgerPluginServer) {
+	s.RegisterService(&_PurgerPlugin_serviceDesc, srv)
+}
+
+func _PurgerPlugin_PurgeTraces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeTracesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurgerPluginServer).PurgeTraces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.storage.v1.PurgerPlugin/PurgeTraces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurgerPluginServer).PurgeTraces(ctx, req.(*PurgeTracesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PurgerPlugin_PurgeAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PurgerPluginServer).PurgeAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.storage.v1.PurgerPlugin/PurgeAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PurgerPluginServer).PurgeAll(ctx, req.(*PurgeAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PurgerPlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "jaeger.storage.v1.PurgerPlugin",
+	HandlerType: (*PurgerPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PurgeTraces",
+			Handler:    _PurgerPlugin_PurgeTraces_Handler,
+		},
+		{
+			MethodName: "PurgeAll",
+			Handler:    _PurgerPlugin_PurgeAll_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "storage.proto",
+}
+
 // DependenciesReaderPluginClient is the client API for DependenciesReaderPlugin service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
@@ -1830,6 +2428,8 @@ var _DependenciesReaderPlugin_serviceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type PluginCapabilitiesClient interface {
 	Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
+	// Negotiate supersedes Capabilities, the plugins not implementing it implement the version 1.0.0.
+	Negotiate(ctx context.Context, in *NegotiateRequest, opts ...grpc.CallOption) (*NegotiateResponse, error)
 }
 
 type pluginCapabilitiesClient struct {
@@ -1849,9 +2449,20 @@ func (c *pluginCapabilitiesClient) Capabilities(ctx context.Context, in *Capabil
 	return out, nil
 }
 
+func (c *pluginCapabilitiesClient) Negotiate(ctx context.Context, in *NegotiateRequest, opts ...grpc.CallOption) (*NegotiateResponse, error) {
+	out := new(NegotiateResponse)
+	err := c.cc.Invoke(ctx, "/jaeger.storage.v1.PluginCapabilities/Negotiate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PluginCapabilitiesServer is the server API for PluginCapabilities service.
 type PluginCapabilitiesServer interface {
 	Capabilities(context.Context, *CapabilitiesRequest) (*CapabilitiesResponse, error)
+	// Negotiate supersedes Capabilities, the plugins not implementing it implement the version 1.0.0.
+	Negotiate(context.Context, *NegotiateRequest) (*NegotiateResponse, error)
 }
 
 // UnimplementedPluginCapabilitiesServer can be embedded to have forward compatible implementations.
@@ -1861,6 +2472,9 @@ type UnimplementedPluginCapabilitiesServer struct {
 func (*UnimplementedPluginCapabilitiesServer) Capabilities(ctx context.Context, req *CapabilitiesRequest) (*CapabilitiesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Capabilities not implemented")
 }
+func (*UnimplementedPluginCapabilitiesServer) Negotiate(ctx context.Context, req *NegotiateRequest) (*NegotiateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Negotiate not implemented")
+}
 
 func RegisterPluginCapabilitiesServer(s *grpc.Server, srv PluginCapabilitiesServer) {
 	s.RegisterService(&_PluginCapabilities_serviceDesc, srv)
@@ -1884,6 +2498,24 @@ func _PluginCapabilities_Capabilities_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PluginCapabilities_Negotiate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NegotiateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginCapabilitiesServer).Negotiate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jaeger.storage.v1.PluginCapabilities/Negotiate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginCapabilitiesServer).Negotiate(ctx, req.(*NegotiateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _PluginCapabilities_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "jaeger.storage.v1.PluginCapabilities",
 	HandlerType: (*PluginCapabilitiesServer)(nil),
@@ -1892,6 +2524,10 @@ var _PluginCapabilities_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Capabilities",
 			Handler:    _PluginCapabilities_Capabilities_Handler,
 		},
+		{
+			MethodName: "Negotiate",
+			Handler:    _PluginCapabilities_Negotiate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "storage.proto",
@@ -2125,6 +2761,26 @@ func (m *GetTraceRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.EndTime != nil {
+		n4, err4 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.EndTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.EndTime):])
+		if err4 != nil {
+			return 0, err4
+		}
+		i -= n4
+		i = encodeVarintStorage(dAtA, i, uint64(n4))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.StartTime != nil {
+		n5, err5 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.StartTime, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.StartTime):])
+		if err5 != nil {
+			return 0, err5
+		}
+		i -= n5
+		i = encodeVarintStorage(dAtA, i, uint64(n5))
+		i--
+		dAtA[i] = 0x12
+	}
 	{
 		size := m.TraceID.Size()
 		i -= size
@@ -2225,6 +2881,30 @@ func (m *GetOperationsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Limit != 0 {
+		i = encodeVarintStorage(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Offset != 0 {
+		i = encodeVarintStorage(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.NameContains) > 0 {
+		i -= len(m.NameContains)
+		copy(dAtA[i:], m.NameContains)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.NameContains)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.NamePrefix) > 0 {
+		i -= len(m.NamePrefix)
+		copy(dAtA[i:], m.NamePrefix)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.NamePrefix)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.SpanKind) > 0 {
 		i -= len(m.SpanKind)
 		copy(dAtA[i:], m.SpanKind)
@@ -2357,42 +3037,49 @@ func (m *TraceQueryParameters) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.PageToken) > 0 {
+		i -= len(m.PageToken)
+		copy(dAtA[i:], m.PageToken)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.PageToken)))
+		i--
+		dAtA[i] = 0x4a
+	}
 	if m.NumTraces != 0 {
 		i = encodeVarintStorage(dAtA, i, uint64(m.NumTraces))
 		i--
 		dAtA[i] = 0x40
 	}
-	n4, err4 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMax, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMax):])
-	if err4 != nil {
-		return 0, err4
-	}
-	i -= n4
-	i = encodeVarintStorage(dAtA, i, uint64(n4))
-	i--
-	dAtA[i] = 0x3a
-	n5, err5 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMin, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMin):])
-	if err5 != nil {
-		return 0, err5
-	}
-	i -= n5
-	i = encodeVarintStorage(dAtA, i, uint64(n5))
-	i--
-	dAtA[i] = 0x32
-	n6, err6 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMax, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMax):])
+	n6, err6 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMax, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMax):])
 	if err6 != nil {
 		return 0, err6
 	}
 	i -= n6
 	i = encodeVarintStorage(dAtA, i, uint64(n6))
 	i--
-	dAtA[i] = 0x2a
-	n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMin, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMin):])
+	dAtA[i] = 0x3a
+	n7, err7 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.DurationMin, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.DurationMin):])
 	if err7 != nil {
 		return 0, err7
 	}
 	i -= n7
 	i = encodeVarintStorage(dAtA, i, uint64(n7))
 	i--
+	dAtA[i] = 0x32
+	n8, err8 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMax, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMax):])
+	if err8 != nil {
+		return 0, err8
+	}
+	i -= n8
+	i = encodeVarintStorage(dAtA, i, uint64(n8))
+	i--
+	dAtA[i] = 0x2a
+	n9, err9 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.StartTimeMin, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTimeMin):])
+	if err9 != nil {
+		return 0, err9
+	}
+	i -= n9
+	i = encodeVarintStorage(dAtA, i, uint64(n9))
+	i--
 	dAtA[i] = 0x22
 	if len(m.Tags) > 0 {
 		for k := range m.Tags {
@@ -2493,6 +3180,13 @@ func (m *SpansResponseChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.NextPageToken) > 0 {
+		i -= len(m.NextPageToken)
+		copy(dAtA[i:], m.NextPageToken)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.NextPageToken)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.Spans) > 0 {
 		for iNdEx := len(m.Spans) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -2590,7 +3284,7 @@ func (m *FindTraceIDsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *CapabilitiesRequest) Marshal() (dAtA []byte, err error) {
+func (m *PurgeTracesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -2600,12 +3294,12 @@ func (m *CapabilitiesRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *CapabilitiesRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *PurgeTracesRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *CapabilitiesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *PurgeTracesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -2614,10 +3308,22 @@ func (m *CapabilitiesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Query != nil {
+		{
+			size, err := m.Query.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintStorage(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
 	return len(dAtA) - i, nil
 }
 
-func (m *CapabilitiesResponse) Marshal() (dAtA []byte, err error) {
+func (m *PurgeTracesResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -2627,12 +3333,12 @@ func (m *CapabilitiesResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *CapabilitiesResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *PurgeTracesResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *CapabilitiesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *PurgeTracesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -2641,60 +3347,259 @@ func (m *CapabilitiesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.StreamingSpanWriter {
-		i--
-		if m.StreamingSpanWriter {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.ArchiveSpanWriter {
-		i--
-		if m.ArchiveSpanWriter {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x10
-	}
-	if m.ArchiveSpanReader {
-		i--
-		if m.ArchiveSpanReader {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
+	if m.PurgedTraces != 0 {
+		i = encodeVarintStorage(dAtA, i, uint64(m.PurgedTraces))
 		i--
 		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintStorage(dAtA []byte, offset int, v uint64) int {
-	offset -= sovStorage(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *PurgeAllRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *GetDependenciesRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTime)
-	n += 1 + l + sovStorage(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)
-	n += 1 + l + sovStorage(uint64(l))
+
+func (m *PurgeAllRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PurgeAllRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PurgeAllResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PurgeAllResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PurgeAllResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CapabilitiesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CapabilitiesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CapabilitiesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CapabilitiesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CapabilitiesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CapabilitiesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.StreamingSpanWriter {
+		i--
+		if m.StreamingSpanWriter {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.ArchiveSpanWriter {
+		i--
+		if m.ArchiveSpanWriter {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ArchiveSpanReader {
+		i--
+		if m.ArchiveSpanReader {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *NegotiateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NegotiateRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NegotiateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Features) > 0 {
+		for iNdEx := len(m.Features) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Features[iNdEx])
+			copy(dAtA[i:], m.Features[iNdEx])
+			i = encodeVarintStorage(dAtA, i, uint64(len(m.Features[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Version) > 0 {
+		i -= len(m.Version)
+		copy(dAtA[i:], m.Version)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.Version)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *NegotiateResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NegotiateResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NegotiateResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Features) > 0 {
+		for iNdEx := len(m.Features) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Features[iNdEx])
+			copy(dAtA[i:], m.Features[iNdEx])
+			i = encodeVarintStorage(dAtA, i, uint64(len(m.Features[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Version) > 0 {
+		i -= len(m.Version)
+		copy(dAtA[i:], m.Version)
+		i = encodeVarintStorage(dAtA, i, uint64(len(m.Version)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintStorage(dAtA []byte, offset int, v uint64) int {
+	offset -= sovStorage(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *GetDependenciesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.StartTime)
+	n += 1 + l + sovStorage(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.EndTime)
+	n += 1 + l + sovStorage(uint64(l))
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2779,6 +3684,14 @@ func (m *GetTraceRequest) Size() (n int) {
 	_ = l
 	l = m.TraceID.Size()
 	n += 1 + l + sovStorage(uint64(l))
+	if m.StartTime != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.StartTime)
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if m.EndTime != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.EndTime)
+		n += 1 + l + sovStorage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2829,6 +3742,20 @@ func (m *GetOperationsRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovStorage(uint64(l))
 	}
+	l = len(m.NamePrefix)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	l = len(m.NameContains)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovStorage(uint64(m.Offset))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovStorage(uint64(m.Limit))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2912,6 +3839,10 @@ func (m *TraceQueryParameters) Size() (n int) {
 	if m.NumTraces != 0 {
 		n += 1 + sovStorage(uint64(m.NumTraces))
 	}
+	l = len(m.PageToken)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2946,6 +3877,10 @@ func (m *SpansResponseChunk) Size() (n int) {
 			n += 1 + l + sovStorage(uint64(l))
 		}
 	}
+	l = len(m.NextPageToken)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2986,49 +3921,148 @@ func (m *FindTraceIDsResponse) Size() (n int) {
 	return n
 }
 
-func (m *CapabilitiesRequest) Size() (n int) {
+func (m *PurgeTracesRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
+	if m.Query != nil {
+		l = m.Query.Size()
+		n += 1 + l + sovStorage(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
 	return n
 }
 
-func (m *CapabilitiesResponse) Size() (n int) {
+func (m *PurgeTracesResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.ArchiveSpanReader {
-		n += 2
+	if m.PurgedTraces != 0 {
+		n += 1 + sovStorage(uint64(m.PurgedTraces))
 	}
-	if m.ArchiveSpanWriter {
-		n += 2
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
 	}
-	if m.StreamingSpanWriter {
-		n += 2
+	return n
+}
+
+func (m *PurgeAllRequest) Size() (n int) {
+	if m == nil {
+		return 0
 	}
+	var l int
+	_ = l
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
 	return n
 }
 
-func sovStorage(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozStorage(x uint64) (n int) {
-	return sovStorage(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (m *GetDependenciesRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
+func (m *PurgeAllResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CapabilitiesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CapabilitiesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ArchiveSpanReader {
+		n += 2
+	}
+	if m.ArchiveSpanWriter {
+		n += 2
+	}
+	if m.StreamingSpanWriter {
+		n += 2
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *NegotiateRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			l = len(s)
+			n += 1 + l + sovStorage(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *NegotiateResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovStorage(uint64(l))
+	}
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			l = len(s)
+			n += 1 + l + sovStorage(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovStorage(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozStorage(x uint64) (n int) {
+	return sovStorage(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *GetDependenciesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
 		preIndex := iNdEx
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -3529,6 +4563,78 @@ func (m *GetTraceRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StartTime == nil {
+				m.StartTime = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.StartTime, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndTime", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.EndTime == nil {
+				m.EndTime = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.EndTime, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -3778,6 +4884,108 @@ func (m *GetOperationsRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.SpanKind = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NamePrefix", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NamePrefix = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NameContains", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NameContains = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -4403,14 +5611,46 @@ func (m *TraceQueryParameters) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipStorage(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageToken", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthStorage
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStorage
 			}
 			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
@@ -4575,6 +5815,38 @@ func (m *SpansResponseChunk) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextPageToken", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextPageToken = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -4770,7 +6042,7 @@ func (m *FindTraceIDsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CapabilitiesRequest) Unmarshal(dAtA []byte) error {
+func (m *PurgeTracesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4793,12 +6065,48 @@ func (m *CapabilitiesRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CapabilitiesRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: PurgeTracesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CapabilitiesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PurgeTracesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Query == nil {
+				m.Query = &TraceQueryParameters{}
+			}
+			if err := m.Query.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -4821,7 +6129,7 @@ func (m *CapabilitiesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CapabilitiesResponse) Unmarshal(dAtA []byte) error {
+func (m *PurgeTracesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4844,17 +6152,17 @@ func (m *CapabilitiesResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CapabilitiesResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: PurgeTracesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CapabilitiesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PurgeTracesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ArchiveSpanReader", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PurgedTraces", wireType)
 			}
-			var v int
+			m.PurgedTraces = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStorage
@@ -4864,52 +6172,505 @@ func (m *CapabilitiesResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				m.PurgedTraces |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.ArchiveSpanReader = bool(v != 0)
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ArchiveSpanWriter", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowStorage
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStorage
 			}
-			m.ArchiveSpanWriter = bool(v != 0)
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StreamingSpanWriter", wireType)
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowStorage
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PurgeAllRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorage
 			}
-			m.StreamingSpanWriter = bool(v != 0)
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PurgeAllRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PurgeAllRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PurgeAllResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorage
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PurgeAllResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PurgeAllResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CapabilitiesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorage
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CapabilitiesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CapabilitiesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CapabilitiesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorage
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CapabilitiesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CapabilitiesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArchiveSpanReader", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ArchiveSpanReader = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArchiveSpanWriter", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ArchiveSpanWriter = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StreamingSpanWriter", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.StreamingSpanWriter = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NegotiateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorage
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NegotiateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NegotiateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Features", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Features = append(m.Features, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorage(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NegotiateResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorage
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NegotiateResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NegotiateResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Features", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStorage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Features = append(m.Features, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -0,0 +1,62 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencystore
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// CallStats summarizes the calls represented by a dependency link.
+type CallStats struct {
+	// ErrorCount is the number of calls that failed.
+	ErrorCount uint64
+	// TotalDuration is the sum of the durations of the calls.
+	TotalDuration time.Duration
+	// MaxDuration is the duration of the longest call.
+	MaxDuration time.Duration
+}
+
+// Add merges the statistics of other calls into s.
+func (s *CallStats) Add(other CallStats) {
+	s.ErrorCount += other.ErrorCount
+	s.TotalDuration += other.TotalDuration
+	s.MaxDuration = max(s.MaxDuration, other.MaxDuration)
+}
+
+// AnnotatedLink is a dependency link along with the time and the statistics of its calls.
+type AnnotatedLink struct {
+	model.DependencyLink
+	// Timestamp is the time the calls were recorded at, zero if the storage does not record it.
+	Timestamp time.Time
+	// Stats is nil if the storage does not record the statistics of the calls.
+	Stats *CallStats
+}
+
+// AnnotatedReader is an additional interface that can be implemented by a Reader
+// able to report the time of the dependency links, and possibly the error and latency
+// statistics of their calls. The links recorded at different times are not merged,
+// so that the links of a lookback can be split by time once read.
+type AnnotatedReader interface {
+	GetAnnotatedDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]AnnotatedLink, error)
+}
+
+// GetAnnotatedDependencies loads the dependency links from the reader along with their time and statistics.
+// If the reader does not implement AnnotatedReader, the links are returned without time nor statistics.
+func GetAnnotatedDependencies(ctx context.Context, reader Reader, endTs time.Time, lookback time.Duration) ([]AnnotatedLink, error) {
+	if annotated, ok := reader.(AnnotatedReader); ok {
+		return annotated.GetAnnotatedDependencies(ctx, endTs, lookback)
+	}
+	links, err := reader.GetDependencies(ctx, endTs, lookback)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]AnnotatedLink, len(links))
+	for i, link := range links {
+		result[i] = AnnotatedLink{DependencyLink: link}
+	}
+	return result, nil
+}
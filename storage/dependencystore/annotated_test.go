@@ -0,0 +1,54 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencystore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type linksReader struct {
+	links []model.DependencyLink
+	err   error
+}
+
+func (r linksReader) GetDependencies(context.Context, time.Time, time.Duration) ([]model.DependencyLink, error) {
+	return r.links, r.err
+}
+
+type annotatedReader struct {
+	linksReader
+	annotated []AnnotatedLink
+}
+
+func (r annotatedReader) GetAnnotatedDependencies(context.Context, time.Time, time.Duration) ([]AnnotatedLink, error) {
+	return r.annotated, nil
+}
+
+func TestGetAnnotatedDependencies(t *testing.T) {
+	link := model.DependencyLink{Parent: "a", Child: "b", CallCount: 3}
+	links, err := GetAnnotatedDependencies(context.Background(), linksReader{links: []model.DependencyLink{link}}, time.Now(), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []AnnotatedLink{{DependencyLink: link}}, links)
+
+	annotated := []AnnotatedLink{{DependencyLink: link, Stats: &CallStats{ErrorCount: 1}}}
+	links, err = GetAnnotatedDependencies(context.Background(), annotatedReader{annotated: annotated}, time.Now(), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, annotated, links)
+
+	_, err = GetAnnotatedDependencies(context.Background(), linksReader{err: assert.AnError}, time.Now(), time.Hour)
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+func TestCallStatsAdd(t *testing.T) {
+	stats := CallStats{ErrorCount: 1, TotalDuration: time.Second, MaxDuration: time.Second}
+	stats.Add(CallStats{ErrorCount: 2, TotalDuration: 3 * time.Second, MaxDuration: 2 * time.Second})
+	assert.Equal(t, CallStats{ErrorCount: 3, TotalDuration: 4 * time.Second, MaxDuration: 2 * time.Second}, stats)
+}
@@ -16,6 +16,7 @@
 package storage
 
 import (
+	"context"
 	"errors"
 
 	"go.uber.org/zap"
@@ -25,6 +26,7 @@ import (
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	metricsstore "github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/storage/samplingstore"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -58,7 +60,56 @@ type SamplingStoreFactory interface {
 	CreateSamplingStore(maxBuckets int) (samplingstore.Store, error)
 }
 
+// SavedSearchStoreFactory is an additional interface that can be implemented by a factory
+// to persist the saved trace searches of jaeger-query.
+type SavedSearchStoreFactory interface {
+	// CreateSavedSearchStore creates a savedsearchstore.Store.
+	CreateSavedSearchStore() (savedsearchstore.Store, error)
+}
+
+// DependencyWriterFactory is an additional interface that can be implemented by a factory
+// whose backend stores the dependency links computed outside of it, e.g. by the collector.
+type DependencyWriterFactory interface {
+	// CreateDependencyWriter creates a dependencystore.Writer.
+	CreateDependencyWriter() (dependencystore.Writer, error)
+}
+
+// PurgerFactory is an additional interface that can be implemented by a factory
+// whose backend can permanently delete the stored traces.
+type PurgerFactory interface {
+	// CreatePurger creates a spanstore.Purger.
+	CreatePurger() (spanstore.Purger, error)
+}
+
+// RetentionFactory is an additional interface that can be implemented by a factory
+// whose backend can store the spans of specific services or tenants with their own TTL.
+type RetentionFactory interface {
+	// SetRetentionPolicies sets the retention policies of the span writers created afterwards.
+	// The policies can be replaced while the writers are running.
+	SetRetentionPolicies(policies *spanstore.RetentionPolicies)
+}
+
+// RetentionPoliciesValidator is an additional interface that can be implemented by a RetentionFactory
+// whose backend must be prepared before storing the spans of a retention policy, e.g. by creating its indices.
+type RetentionPoliciesValidator interface {
+	// ValidateRetentionPolicies returns an error if the spans of a policy cannot be stored yet.
+	ValidateRetentionPolicies(ctx context.Context, policies []spanstore.RetentionPolicy) error
+}
+
 var (
+	// ErrPurgerNotSupported can be returned by the PurgerFactory when the backend cannot delete traces.
+	ErrPurgerNotSupported = errors.New("purging traces not supported")
+
+	// ErrPurgerNotEnabled can be returned by the PurgerFactory when the deletes are not explicitly enabled.
+	ErrPurgerNotEnabled = errors.New("purging traces not enabled")
+
+	// ErrDependencyWriterNotSupported can be returned by the DependencyWriterFactory when the backend
+	// does not store dependency links, e.g. because it derives them from the stored traces.
+	ErrDependencyWriterNotSupported = errors.New("writing dependencies not supported")
+
+	// ErrSavedSearchStorageNotSupported can be returned by the SavedSearchStoreFactory when saved searches are not supported by the backend.
+	ErrSavedSearchStorageNotSupported = errors.New("saved search storage not supported")
+
 	// ErrArchiveStorageNotConfigured can be returned by the ArchiveFactory when the archive storage is not configured.
 	ErrArchiveStorageNotConfigured = errors.New("archive storage not configured")
 
@@ -89,3 +140,11 @@ type MetricsFactory interface {
 	// CreateMetricsReader creates a metricsstore.Reader.
 	CreateMetricsReader() (metricsstore.Reader, error)
 }
+
+// SpanReaderMetricsFactory is an additional interface that can be implemented by a MetricsFactory
+// whose metrics reader can compute the metrics from the spans in the span storage.
+type SpanReaderMetricsFactory interface {
+	// SetSpanReader sets the span reader used by the metrics reader.
+	// It must be called before CreateMetricsReader.
+	SetSpanReader(spanReader spanstore.Reader)
+}
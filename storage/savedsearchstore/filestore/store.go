@@ -0,0 +1,153 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package filestore implements a saved search store kept in memory and optionally persisted
+// to a JSON file, for the deployments whose span storage cannot store the saved searches.
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
+)
+
+// Store is an in-memory store of saved searches, optionally persisted to a JSON file.
+type Store struct {
+	sync.RWMutex
+	// perTenant maps tenant to saved search ID to saved search
+	perTenant map[string]map[string]*savedsearchstore.SavedSearch
+	// file is the path of the file the searches are persisted to, if not empty
+	file string
+}
+
+// NewStore creates an in-memory saved search store.
+func NewStore() *Store {
+	return &Store{
+		perTenant: map[string]map[string]*savedsearchstore.SavedSearch{},
+	}
+}
+
+// NewFileStore creates a saved search store that keeps its content in the given
+// JSON file, so that saved searches survive restarts. The file is created if it does not exist.
+func NewFileStore(file string) (*Store, error) {
+	ss := NewStore()
+	ss.file = file
+	data, err := os.ReadFile(filepath.Clean(file))
+	if errors.Is(err, os.ErrNotExist) {
+		return ss, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read saved searches file: %w", err)
+	}
+	if len(data) == 0 {
+		return ss, nil
+	}
+	if err := json.Unmarshal(data, &ss.perTenant); err != nil {
+		return nil, fmt.Errorf("cannot parse saved searches file %s: %w", file, err)
+	}
+	return ss, nil
+}
+
+// SaveSearch implements savedsearchstore.Store#SaveSearch.
+func (ss *Store) SaveSearch(ctx context.Context, search *savedsearchstore.SavedSearch) error {
+	ss.Lock()
+	defer ss.Unlock()
+	tenant := tenancy.GetTenant(ctx)
+	searches, ok := ss.perTenant[tenant]
+	if !ok {
+		searches = map[string]*savedsearchstore.SavedSearch{}
+		ss.perTenant[tenant] = searches
+	}
+	stored := *search
+	previous := searches[search.ID]
+	searches[search.ID] = &stored
+	if err := ss.persist(); err != nil {
+		// keep the memory consistent with the file
+		if previous != nil {
+			searches[search.ID] = previous
+		} else {
+			delete(searches, search.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetSavedSearch implements savedsearchstore.Store#GetSavedSearch.
+func (ss *Store) GetSavedSearch(ctx context.Context, id string) (*savedsearchstore.SavedSearch, error) {
+	ss.RLock()
+	defer ss.RUnlock()
+	search, ok := ss.perTenant[tenancy.GetTenant(ctx)][id]
+	if !ok {
+		return nil, savedsearchstore.ErrSavedSearchNotFound
+	}
+	found := *search
+	return &found, nil
+}
+
+// ListSavedSearches implements savedsearchstore.Store#ListSavedSearches.
+func (ss *Store) ListSavedSearches(ctx context.Context, owner string) ([]*savedsearchstore.SavedSearch, error) {
+	ss.RLock()
+	defer ss.RUnlock()
+	var result []*savedsearchstore.SavedSearch
+	for _, search := range ss.perTenant[tenancy.GetTenant(ctx)] {
+		if owner != "" && search.Owner != owner {
+			continue
+		}
+		found := *search
+		result = append(result, &found)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// DeleteSavedSearch implements savedsearchstore.Store#DeleteSavedSearch.
+func (ss *Store) DeleteSavedSearch(ctx context.Context, id string) error {
+	ss.Lock()
+	defer ss.Unlock()
+	searches := ss.perTenant[tenancy.GetTenant(ctx)]
+	search, ok := searches[id]
+	if !ok {
+		return savedsearchstore.ErrSavedSearchNotFound
+	}
+	delete(searches, id)
+	if err := ss.persist(); err != nil {
+		searches[id] = search
+		return err
+	}
+	return nil
+}
+
+// persist writes all saved searches to the file, if configured. The content is written
+// to a temporary file first and then renamed, so that a failed write does not lose data.
+// Must be called with the lock held.
+func (ss *Store) persist() error {
+	if ss.file == "" {
+		return nil
+	}
+	data, err := json.Marshal(ss.perTenant)
+	if err != nil {
+		return fmt.Errorf("cannot serialize saved searches: %w", err)
+	}
+	tmp := ss.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("cannot write saved searches file: %w", err)
+	}
+	if err := os.Rename(tmp, ss.file); err != nil {
+		return fmt.Errorf("cannot write saved searches file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package filestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/savedsearchstore"
+)
+
+func newSavedSearch(id, name, owner string) *savedsearchstore.SavedSearch {
+	return &savedsearchstore.SavedSearch{
+		ID:        id,
+		Name:      name,
+		Owner:     owner,
+		Query:     "service=frontend&limit=20",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStore(t *testing.T) {
+	ss := NewStore()
+	ctx := context.Background()
+
+	require.NoError(t, ss.SaveSearch(ctx, newSavedSearch("1", "slow requests", "alice")))
+	require.NoError(t, ss.SaveSearch(ctx, newSavedSearch("2", "errors", "bob")))
+
+	search, err := ss.GetSavedSearch(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, newSavedSearch("1", "slow requests", "alice"), search)
+
+	searches, err := ss.ListSavedSearches(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	assert.Equal(t, "errors", searches[0].Name)
+	assert.Equal(t, "slow requests", searches[1].Name)
+
+	searches, err = ss.ListSavedSearches(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, searches, 1)
+	assert.Equal(t, "1", searches[0].ID)
+
+	require.NoError(t, ss.DeleteSavedSearch(ctx, "1"))
+	_, err = ss.GetSavedSearch(ctx, "1")
+	require.ErrorIs(t, err, savedsearchstore.ErrSavedSearchNotFound)
+	require.ErrorIs(t, ss.DeleteSavedSearch(ctx, "1"), savedsearchstore.ErrSavedSearchNotFound)
+}
+
+func TestStoreTenancy(t *testing.T) {
+	ss := NewStore()
+	ctxA := tenancy.WithTenant(context.Background(), "acme")
+	ctxB := tenancy.WithTenant(context.Background(), "megacorp")
+
+	require.NoError(t, ss.SaveSearch(ctxA, newSavedSearch("1", "slow requests", "")))
+
+	_, err := ss.GetSavedSearch(ctxB, "1")
+	require.ErrorIs(t, err, savedsearchstore.ErrSavedSearchNotFound)
+	searches, err := ss.ListSavedSearches(ctxB, "")
+	require.NoError(t, err)
+	assert.Empty(t, searches)
+	require.ErrorIs(t, ss.DeleteSavedSearch(ctxB, "1"), savedsearchstore.ErrSavedSearchNotFound)
+}
+
+func TestFileStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "searches.json")
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+
+	ss, err := NewFileStore(file)
+	require.NoError(t, err)
+	require.NoError(t, ss.SaveSearch(ctx, newSavedSearch("1", "slow requests", "alice")))
+	require.NoError(t, ss.SaveSearch(ctx, newSavedSearch("2", "errors", "bob")))
+	require.NoError(t, ss.DeleteSavedSearch(ctx, "2"))
+
+	reloaded, err := NewFileStore(file)
+	require.NoError(t, err)
+	searches, err := reloaded.ListSavedSearches(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []*savedsearchstore.SavedSearch{newSavedSearch("1", "slow requests", "alice")}, searches)
+}
+
+func TestFileStoreErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(file, []byte("not json"), 0o600))
+	_, err := NewFileStore(file)
+	require.ErrorContains(t, err, "cannot parse saved searches file")
+
+	_, err = NewFileStore(dir)
+	require.ErrorContains(t, err, "cannot read saved searches file")
+
+	empty := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(empty, nil, 0o600))
+	ss, err := NewFileStore(empty)
+	require.NoError(t, err)
+
+	// the file cannot be written once its directory is gone
+	ss.file = filepath.Join(dir, "missing", "searches.json")
+	ctx := context.Background()
+	require.ErrorContains(t, ss.SaveSearch(ctx, newSavedSearch("1", "slow requests", "")), "cannot write saved searches file")
+	_, err = ss.GetSavedSearch(ctx, "1")
+	require.ErrorIs(t, err, savedsearchstore.ErrSavedSearchNotFound)
+
+	ss.file = ""
+	require.NoError(t, ss.SaveSearch(ctx, newSavedSearch("1", "slow requests", "")))
+	ss.file = filepath.Join(dir, "missing", "searches.json")
+	require.Error(t, ss.SaveSearch(ctx, newSavedSearch("1", "renamed", "")))
+	require.Error(t, ss.DeleteSavedSearch(ctx, "1"))
+	search, err := ss.GetSavedSearch(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "slow requests", search.Name)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package savedsearchstore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSavedSearchNotFound is returned by Store when the saved search does not exist.
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+// SavedSearch is a named trace search that can be shared between users of the same tenant.
+type SavedSearch struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Owner identifies the user who saved the search; it may be empty for searches shared by the whole tenant.
+	Owner       string `json:"owner,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Query holds the search parameters in URL query string format, as accepted by /api/traces.
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Store persists saved searches. Implementations keep the searches of different
+// tenants apart using the tenant in the context, see tenancy.GetTenant.
+type Store interface {
+	// SaveSearch creates the saved search, or replaces an existing one with the same ID.
+	SaveSearch(ctx context.Context, search *SavedSearch) error
+
+	// GetSavedSearch returns the saved search with the given ID, or ErrSavedSearchNotFound.
+	GetSavedSearch(ctx context.Context, id string) (*SavedSearch, error)
+
+	// ListSavedSearches returns saved searches ordered by name. If owner is not empty,
+	// only the searches of that owner are returned.
+	ListSavedSearches(ctx context.Context, owner string) ([]*SavedSearch, error)
+
+	// DeleteSavedSearch deletes the saved search with the given ID, or returns ErrSavedSearchNotFound.
+	DeleteSavedSearch(ctx context.Context, id string) error
+}
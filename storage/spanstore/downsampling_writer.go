@@ -16,11 +16,14 @@ package spanstore
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"hash"
 	"hash/fnv"
 	"math"
 	"math/big"
 	"sync"
+	"sync/atomic"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
@@ -43,33 +46,101 @@ type downsamplingWriterMetrics struct {
 }
 
 // DownsamplingWriter is a span Writer that drops spans with a predefined downsamplingRatio.
+// The spans of specific services and operations can be downsampled with their own ratios.
 type DownsamplingWriter struct {
 	spanWriter Writer
 	metrics    downsamplingWriterMetrics
 	sampler    *Sampler
+	ratio      float64
+	thresholds atomic.Pointer[map[string]serviceThresholds]
 }
 
 // DownsamplingOptions contains the options for constructing a DownsamplingWriter.
 type DownsamplingOptions struct {
-	Ratio          float64
-	HashSalt       string
+	Ratio    float64
+	HashSalt string
+	// ServiceRatios are the downsampling ratios of specific services, overriding the Ratio.
+	ServiceRatios  map[string]ServiceDownsamplingRatios
 	MetricsFactory metrics.Factory
 }
 
+// ServiceDownsamplingRatios are the downsampling ratios of the spans of a service.
+type ServiceDownsamplingRatios struct {
+	// Ratio is the ratio of the spans of the service, defaults to the global ratio if not set.
+	Ratio *float64 `json:"ratio,omitempty"`
+	// Operations are the ratios of the spans of specific operations, overriding the Ratio.
+	Operations map[string]float64 `json:"operations,omitempty"`
+}
+
+// serviceThresholds are the hash thresholds of the spans of a service to keep
+type serviceThresholds struct {
+	threshold  uint64
+	operations map[string]uint64
+}
+
+// ParseServiceDownsamplingRatios parses the downsampling ratios of the services from a JSON object
+// keyed by service name, e.g. {"frontend": {"ratio": 0.1, "operations": {"GET /health": 0.01}}}.
+func ParseServiceDownsamplingRatios(data []byte) (map[string]ServiceDownsamplingRatios, error) {
+	var ratios map[string]ServiceDownsamplingRatios
+	if err := json.Unmarshal(data, &ratios); err != nil {
+		return nil, fmt.Errorf("cannot parse the downsampling ratios: %w", err)
+	}
+	for service, serviceRatios := range ratios {
+		if serviceRatios.Ratio != nil && !validRatio(*serviceRatios.Ratio) {
+			return nil, fmt.Errorf("invalid downsampling ratio %v of service %q, expecting a value between 0 and 1", *serviceRatios.Ratio, service)
+		}
+		for operation, ratio := range serviceRatios.Operations {
+			if !validRatio(ratio) {
+				return nil, fmt.Errorf("invalid downsampling ratio %v of operation %q of service %q, expecting a value between 0 and 1", ratio, operation, service)
+			}
+		}
+	}
+	return ratios, nil
+}
+
+func validRatio(ratio float64) bool {
+	return ratio >= 0 && ratio <= 1
+}
+
 // NewDownsamplingWriter creates a DownsamplingWriter.
 func NewDownsamplingWriter(spanWriter Writer, downsamplingOptions DownsamplingOptions) *DownsamplingWriter {
 	writeMetrics := &downsamplingWriterMetrics{}
 	metrics.Init(writeMetrics, downsamplingOptions.MetricsFactory, nil)
-	return &DownsamplingWriter{
+	ds := &DownsamplingWriter{
 		sampler:    NewSampler(downsamplingOptions.Ratio, downsamplingOptions.HashSalt),
 		spanWriter: spanWriter,
 		metrics:    *writeMetrics,
+		ratio:      downsamplingOptions.Ratio,
 	}
+	ds.SetServiceRatios(downsamplingOptions.ServiceRatios)
+	return ds
+}
+
+// SetServiceRatios replaces the downsampling ratios of the services, e.g. when their configuration is reloaded.
+// The spans are still sampled by their trace ID, so that the spans of a trace are kept or dropped together
+// as long as the ratios of their services are the same.
+func (ds *DownsamplingWriter) SetServiceRatios(ratios map[string]ServiceDownsamplingRatios) {
+	thresholds := make(map[string]serviceThresholds, len(ratios))
+	for service, serviceRatios := range ratios {
+		ratio := ds.ratio
+		if serviceRatios.Ratio != nil {
+			ratio = *serviceRatios.Ratio
+		}
+		st := serviceThresholds{threshold: calculateThreshold(ratio)}
+		if len(serviceRatios.Operations) > 0 {
+			st.operations = make(map[string]uint64, len(serviceRatios.Operations))
+			for operation, operationRatio := range serviceRatios.Operations {
+				st.operations[operation] = calculateThreshold(operationRatio)
+			}
+		}
+		thresholds[service] = st
+	}
+	ds.thresholds.Store(&thresholds)
 }
 
 // WriteSpan calls WriteSpan on wrapped span writer.
 func (ds *DownsamplingWriter) WriteSpan(ctx context.Context, span *model.Span) error {
-	if !ds.sampler.ShouldSample(span) {
+	if ds.sampler.hashTraceID(span.TraceID) > ds.threshold(span) {
 		// Drops spans when hashVal falls beyond computed threshold.
 		ds.metrics.SpansDropped.Inc(1)
 		return nil
@@ -78,6 +149,22 @@ func (ds *DownsamplingWriter) WriteSpan(ctx context.Context, span *model.Span) e
 	return ds.spanWriter.WriteSpan(ctx, span)
 }
 
+// threshold returns the hash threshold of the span, depending on its service and operation.
+func (ds *DownsamplingWriter) threshold(span *model.Span) uint64 {
+	thresholds := *ds.thresholds.Load()
+	if len(thresholds) == 0 || span.Process == nil {
+		return ds.sampler.threshold
+	}
+	st, ok := thresholds[span.Process.ServiceName]
+	if !ok {
+		return ds.sampler.threshold
+	}
+	if threshold, ok := st.operations[span.OperationName]; ok {
+		return threshold
+	}
+	return st.threshold
+}
+
 // hashBytes returns the uint64 hash value of byte slice.
 func (h *hasher) hashBytes() uint64 {
 	h.hash.Reset()
@@ -127,11 +214,15 @@ func calculateThreshold(ratio float64) uint64 {
 
 // ShouldSample decides if a span should be sampled
 func (s *Sampler) ShouldSample(span *model.Span) bool {
+	return s.hashTraceID(span.TraceID) <= s.threshold
+}
+
+func (s *Sampler) hashTraceID(traceID model.TraceID) uint64 {
 	hasherInstance := s.hasherPool.Get().(*hasher)
 	// Currently MarshalTo will only return err if size of traceIDBytes is smaller than 16
 	// Since we force traceIDBytes to be size of 16 metrics is not necessary here.
-	_, _ = span.TraceID.MarshalTo(hasherInstance.buffer[s.lengthOfSalt:])
+	_, _ = traceID.MarshalTo(hasherInstance.buffer[s.lengthOfSalt:])
 	hashVal := hasherInstance.hashBytes()
 	s.hasherPool.Put(hasherInstance)
-	return hashVal <= s.threshold
+	return hashVal
 }
@@ -88,3 +88,50 @@ func TestDownsamplingWriter_calculateThreshold(t *testing.T) {
 	var maxUint64 uint64 = math.MaxUint64
 	assert.Equal(t, maxUint64, calculateThreshold(1.0))
 }
+
+func TestDownsamplingWriter_ServiceRatios(t *testing.T) {
+	noisy, critical := 0.0, 1.0
+	c := NewDownsamplingWriter(&errorWriteSpanStore{}, DownsamplingOptions{
+		Ratio: 0.5,
+		ServiceRatios: map[string]ServiceDownsamplingRatios{
+			"noisy":    {Ratio: &noisy, Operations: map[string]float64{"critical": 1}},
+			"critical": {Ratio: &critical},
+			"default":  {Operations: map[string]float64{"noisy": 0}},
+		},
+	})
+	span := func(service, operation string) *model.Span {
+		return &model.Span{
+			TraceID:       model.NewTraceID(1, 2),
+			OperationName: operation,
+			Process:       &model.Process{ServiceName: service},
+		}
+	}
+	require.NoError(t, c.WriteSpan(context.Background(), span("noisy", "other")))
+	require.Error(t, c.WriteSpan(context.Background(), span("noisy", "critical")))
+	require.Error(t, c.WriteSpan(context.Background(), span("critical", "other")))
+	require.NoError(t, c.WriteSpan(context.Background(), span("default", "noisy")))
+	assert.Equal(t, calculateThreshold(0.5), c.threshold(span("default", "other")))
+	assert.Equal(t, calculateThreshold(0.5), c.threshold(span("other", "other")))
+	assert.Equal(t, calculateThreshold(0.5), c.threshold(&model.Span{}))
+
+	// the spans of the services without their own ratio are still sampled consistently by trace ID
+	c.SetServiceRatios(nil)
+	assert.Equal(t, c.sampler.ShouldSample(span("noisy", "other")), c.WriteSpan(context.Background(), span("noisy", "other")) != nil)
+}
+
+func TestParseServiceDownsamplingRatios(t *testing.T) {
+	ratios, err := ParseServiceDownsamplingRatios([]byte(`{"frontend": {"ratio": 0.1, "operations": {"GET /health": 0.01}}, "backend": {}}`))
+	require.NoError(t, err)
+	ratio := 0.1
+	assert.Equal(t, map[string]ServiceDownsamplingRatios{
+		"frontend": {Ratio: &ratio, Operations: map[string]float64{"GET /health": 0.01}},
+		"backend":  {},
+	}, ratios)
+
+	_, err = ParseServiceDownsamplingRatios([]byte(`[]`))
+	require.ErrorContains(t, err, "cannot parse the downsampling ratios")
+	_, err = ParseServiceDownsamplingRatios([]byte(`{"frontend": {"ratio": 2}}`))
+	require.ErrorContains(t, err, `invalid downsampling ratio 2 of service "frontend"`)
+	_, err = ParseServiceDownsamplingRatios([]byte(`{"frontend": {"operations": {"GET": -1}}}`))
+	require.ErrorContains(t, err, `invalid downsampling ratio -1 of operation "GET" of service "frontend"`)
+}
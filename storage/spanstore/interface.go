@@ -71,12 +71,23 @@ type TraceQueryParameters struct {
 	DurationMin   time.Duration
 	DurationMax   time.Duration
 	NumTraces     int
+	// PageToken is an opaque cursor returned by PaginatedReader.FindTracesPage
+	// that selects the page of results to return. It is ignored by FindTraces.
+	PageToken string
 }
 
 // OperationQueryParameters contains parameters of query operations, empty spanKind means get operations for all kinds of span.
 type OperationQueryParameters struct {
 	ServiceName string
 	SpanKind    string
+	// NamePrefix and NameContains restrict the operations to the names that start with,
+	// respectively contain, the given strings. They are ignored by GetOperations.
+	NamePrefix   string
+	NameContains string
+	// Offset and Limit select a page of the operations sorted by name and span kind;
+	// a non-positive Limit does not limit the operations. They are ignored by GetOperations.
+	Offset int
+	Limit  int
 }
 
 // Operation contains operation name and span kind
@@ -0,0 +1,174 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// defaultLatencyScanTraces is the number of traces scanned by the in-process
+// fallback when LatencyQueryParameters.MaxTraces is not set.
+const defaultLatencyScanTraces = 1000
+
+// ErrLatencyServiceNameNotSet is returned when a latency query does not specify a service.
+var ErrLatencyServiceNameNotSet = errors.New("service name must be set")
+
+// DefaultLatencyBucketBounds are the histogram bucket bounds used when a query does not specify any.
+var DefaultLatencyBucketBounds = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyQueryParameters contains the parameters of a latency distribution query.
+type LatencyQueryParameters struct {
+	ServiceName   string
+	OperationName string
+	StartTimeMin  time.Time
+	StartTimeMax  time.Time
+	// BucketBounds are the boundaries between histogram buckets, in increasing order.
+	// If empty, DefaultLatencyBucketBounds are used.
+	BucketBounds []time.Duration
+	// MaxTraces limits the number of traces read by readers that cannot aggregate
+	// durations natively and compute the distribution from a sample instead.
+	MaxTraces int
+}
+
+// DurationBucket is a single bucket of a duration histogram containing spans
+// with From <= duration < To. A zero To denotes the unbounded last bucket.
+type DurationBucket struct {
+	From  time.Duration
+	To    time.Duration
+	Count int64
+}
+
+// LatencyDistribution describes the durations of the spans matching a latency query.
+type LatencyDistribution struct {
+	Count   int64
+	Buckets []DurationBucket
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+}
+
+// LatencyReader is an additional interface that can be implemented by a Reader
+// whose backend is able to aggregate span durations without loading the spans.
+type LatencyReader interface {
+	GetLatencyDistribution(ctx context.Context, query *LatencyQueryParameters) (*LatencyDistribution, error)
+}
+
+// NewDurationBuckets returns empty histogram buckets for the given bounds.
+func NewDurationBuckets(bounds []time.Duration) []DurationBucket {
+	buckets := make([]DurationBucket, 0, len(bounds)+1)
+	var from time.Duration
+	for _, bound := range bounds {
+		buckets = append(buckets, DurationBucket{From: from, To: bound})
+		from = bound
+	}
+	return append(buckets, DurationBucket{From: from})
+}
+
+// NewLatencyDistribution computes the histogram and percentiles of the given durations.
+func NewLatencyDistribution(durations []time.Duration, bounds []time.Duration) *LatencyDistribution {
+	if len(bounds) == 0 {
+		bounds = DefaultLatencyBucketBounds
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	dist := &LatencyDistribution{
+		Count:   int64(len(sorted)),
+		Buckets: NewDurationBuckets(bounds),
+		P50:     percentile(sorted, 50),
+		P95:     percentile(sorted, 95),
+		P99:     percentile(sorted, 99),
+	}
+	i := 0
+	for _, d := range sorted {
+		for i < len(bounds) && d >= bounds[i] {
+			i++
+		}
+		dist.Buckets[i].Count++
+	}
+	return dist
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// GetLatencyDistribution returns the latency distribution of the spans matching the query.
+// If the reader implements LatencyReader it is used directly, otherwise the distribution
+// is computed from the spans of up to query.MaxTraces most recent matching traces.
+func GetLatencyDistribution(ctx context.Context, reader Reader, query *LatencyQueryParameters) (*LatencyDistribution, error) {
+	if query.ServiceName == "" {
+		return nil, ErrLatencyServiceNameNotSet
+	}
+	if latencyReader, ok := reader.(LatencyReader); ok {
+		return latencyReader.GetLatencyDistribution(ctx, query)
+	}
+	numTraces := query.MaxTraces
+	if numTraces <= 0 {
+		numTraces = defaultLatencyScanTraces
+	}
+	traces, err := reader.FindTraces(ctx, &TraceQueryParameters{
+		ServiceName:   query.ServiceName,
+		OperationName: query.OperationName,
+		StartTimeMin:  query.StartTimeMin,
+		StartTimeMax:  query.StartTimeMax,
+		NumTraces:     numTraces,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var durations []time.Duration
+	for _, trace := range traces {
+		for _, span := range trace.Spans {
+			if matchesLatencyQuery(span, query) {
+				durations = append(durations, span.Duration)
+			}
+		}
+	}
+	return NewLatencyDistribution(durations, query.BucketBounds), nil
+}
+
+func matchesLatencyQuery(span *model.Span, query *LatencyQueryParameters) bool {
+	if span.Process == nil || span.Process.ServiceName != query.ServiceName {
+		return false
+	}
+	if query.OperationName != "" && span.OperationName != query.OperationName {
+		return false
+	}
+	if !query.StartTimeMin.IsZero() && span.StartTime.Before(query.StartTimeMin) {
+		return false
+	}
+	if !query.StartTimeMax.IsZero() && span.StartTime.After(query.StartTimeMax) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestNewLatencyDistribution(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i > 0; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	dist := NewLatencyDistribution(durations, []time.Duration{10 * time.Millisecond, 50 * time.Millisecond})
+	assert.EqualValues(t, 100, dist.Count)
+	assert.Equal(t, 50*time.Millisecond, dist.P50)
+	assert.Equal(t, 95*time.Millisecond, dist.P95)
+	assert.Equal(t, 99*time.Millisecond, dist.P99)
+	assert.Equal(t, []DurationBucket{
+		{From: 0, To: 10 * time.Millisecond, Count: 9},
+		{From: 10 * time.Millisecond, To: 50 * time.Millisecond, Count: 40},
+		{From: 50 * time.Millisecond, Count: 51},
+	}, dist.Buckets)
+	assert.Equal(t, 100*time.Millisecond, durations[0], "input must not be modified")
+}
+
+func TestNewLatencyDistributionEmpty(t *testing.T) {
+	dist := NewLatencyDistribution(nil, nil)
+	assert.Zero(t, dist.Count)
+	assert.Zero(t, dist.P99)
+	assert.Len(t, dist.Buckets, len(DefaultLatencyBucketBounds)+1)
+}
+
+type tracesReader struct {
+	Reader
+	traces []*model.Trace
+	err    error
+	query  *TraceQueryParameters
+}
+
+func (r *tracesReader) FindTraces(_ context.Context, query *TraceQueryParameters) ([]*model.Trace, error) {
+	r.query = query
+	return r.traces, r.err
+}
+
+func TestGetLatencyDistributionFallback(t *testing.T) {
+	svcA := &model.Process{ServiceName: "a"}
+	svcB := &model.Process{ServiceName: "b"}
+	reader := &tracesReader{traces: []*model.Trace{
+		{Spans: []*model.Span{
+			{Process: svcA, OperationName: "op1", Duration: time.Millisecond},
+			{Process: svcA, OperationName: "op2", Duration: time.Second},
+			{Process: svcB, OperationName: "op1", Duration: time.Minute},
+		}},
+		{Spans: []*model.Span{
+			{Process: svcA, OperationName: "op1", Duration: 3 * time.Millisecond},
+		}},
+	}}
+
+	dist, err := GetLatencyDistribution(context.Background(), reader, &LatencyQueryParameters{
+		ServiceName:   "a",
+		OperationName: "op1",
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, dist.Count)
+	assert.Equal(t, time.Millisecond, dist.P50)
+	assert.Equal(t, 3*time.Millisecond, dist.P99)
+	assert.Equal(t, defaultLatencyScanTraces, reader.query.NumTraces)
+	assert.Equal(t, "op1", reader.query.OperationName)
+
+	dist, err = GetLatencyDistribution(context.Background(), reader, &LatencyQueryParameters{
+		ServiceName: "a",
+		MaxTraces:   10,
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, dist.Count)
+	assert.Equal(t, time.Second, dist.P99)
+	assert.Equal(t, 10, reader.query.NumTraces)
+}
+
+func TestGetLatencyDistributionErrors(t *testing.T) {
+	_, err := GetLatencyDistribution(context.Background(), &tracesReader{}, &LatencyQueryParameters{})
+	require.ErrorIs(t, err, ErrLatencyServiceNameNotSet)
+
+	readerErr := errors.New("reader error")
+	_, err = GetLatencyDistribution(context.Background(), &tracesReader{err: readerErr}, &LatencyQueryParameters{ServiceName: "a"})
+	require.ErrorIs(t, err, readerErr)
+}
+
+type latencyReader struct {
+	Reader
+	dist *LatencyDistribution
+}
+
+func (r latencyReader) GetLatencyDistribution(context.Context, *LatencyQueryParameters) (*LatencyDistribution, error) {
+	return r.dist, nil
+}
+
+func TestGetLatencyDistributionUsesLatencyReader(t *testing.T) {
+	expected := &LatencyDistribution{Count: 42}
+	dist, err := GetLatencyDistribution(context.Background(), latencyReader{dist: expected}, &LatencyQueryParameters{ServiceName: "a"})
+	require.NoError(t, err)
+	assert.Same(t, expected, dist)
+}
@@ -19,6 +19,9 @@ import (
 	"context"
 	"time"
 
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/metrics"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -32,6 +35,10 @@ type ReadMetricsDecorator struct {
 	getTraceMetrics      *queryMetrics
 	getServicesMetrics   *queryMetrics
 	getOperationsMetrics *queryMetrics
+	getLatencyMetrics    *queryMetrics
+	getREDMetrics        *queryMetrics
+	getTagKeysMetrics    *queryMetrics
+	getTagValuesMetrics  *queryMetrics
 }
 
 type queryMetrics struct {
@@ -42,13 +49,18 @@ type queryMetrics struct {
 	OKLatency  metrics.Timer   `metric:"latency" tags:"result=ok"`
 }
 
-func (q *queryMetrics) emit(err error, latency time.Duration, responses int) {
+// emit records the metrics of a read operation, with the trace of the operation as exemplar of the latency.
+func (q *queryMetrics) emit(ctx context.Context, err error, latency time.Duration, responses int) {
+	var traceID string
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		traceID = spanCtx.TraceID().String()
+	}
 	if err != nil {
 		q.Errors.Inc(1)
-		q.ErrLatency.Record(latency)
+		metrics.RecordTimerWithExemplar(q.ErrLatency, latency, traceID)
 	} else {
 		q.Successes.Inc(1)
-		q.OKLatency.Record(latency)
+		metrics.RecordTimerWithExemplar(q.OKLatency, latency, traceID)
 		q.Responses.Record(time.Duration(responses))
 	}
 }
@@ -62,6 +74,10 @@ func NewReadMetricsDecorator(spanReader spanstore.Reader, metricsFactory metrics
 		getTraceMetrics:      buildQueryMetrics("get_trace", metricsFactory),
 		getServicesMetrics:   buildQueryMetrics("get_services", metricsFactory),
 		getOperationsMetrics: buildQueryMetrics("get_operations", metricsFactory),
+		getLatencyMetrics:    buildQueryMetrics("get_latency_distribution", metricsFactory),
+		getREDMetrics:        buildQueryMetrics("get_red_metrics", metricsFactory),
+		getTagKeysMetrics:    buildQueryMetrics("get_tag_keys", metricsFactory),
+		getTagValuesMetrics:  buildQueryMetrics("get_tag_values", metricsFactory),
 	}
 }
 
@@ -76,7 +92,19 @@ func buildQueryMetrics(operation string, metricsFactory metrics.Factory) *queryM
 func (m *ReadMetricsDecorator) FindTraces(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
 	start := time.Now()
 	retMe, err := m.spanReader.FindTraces(ctx, traceQuery)
-	m.findTracesMetrics.emit(err, time.Since(start), len(retMe))
+	m.findTracesMetrics.emit(ctx, err, time.Since(start), len(retMe))
+	return retMe, err
+}
+
+// FindTracesPage implements spanstore.PaginatedReader#FindTracesPage
+func (m *ReadMetricsDecorator) FindTracesPage(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) (*spanstore.TracesPage, error) {
+	start := time.Now()
+	retMe, err := spanstore.FindTracesPage(ctx, m.spanReader, traceQuery)
+	var responses int
+	if retMe != nil {
+		responses = len(retMe.Traces)
+	}
+	m.findTracesMetrics.emit(ctx, err, time.Since(start), responses)
 	return retMe, err
 }
 
@@ -84,7 +112,7 @@ func (m *ReadMetricsDecorator) FindTraces(ctx context.Context, traceQuery *spans
 func (m *ReadMetricsDecorator) FindTraceIDs(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
 	start := time.Now()
 	retMe, err := m.spanReader.FindTraceIDs(ctx, traceQuery)
-	m.findTraceIDsMetrics.emit(err, time.Since(start), len(retMe))
+	m.findTraceIDsMetrics.emit(ctx, err, time.Since(start), len(retMe))
 	return retMe, err
 }
 
@@ -92,7 +120,44 @@ func (m *ReadMetricsDecorator) FindTraceIDs(ctx context.Context, traceQuery *spa
 func (m *ReadMetricsDecorator) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
 	start := time.Now()
 	retMe, err := m.spanReader.GetTrace(ctx, traceID)
-	m.getTraceMetrics.emit(err, time.Since(start), 1)
+	m.getTraceMetrics.emit(ctx, err, time.Since(start), 1)
+	return retMe, err
+}
+
+// StreamTrace implements spanstore.StreamingReader#StreamTrace
+func (m *ReadMetricsDecorator) StreamTrace(ctx context.Context, traceID model.TraceID, yield func([]*model.Span) error) error {
+	start := time.Now()
+	err := spanstore.StreamTrace(ctx, m.spanReader, traceID, 0, yield)
+	m.getTraceMetrics.emit(ctx, err, time.Since(start), 1)
+	return err
+}
+
+// GetTraceInTimeRange implements spanstore.TimeRangeReader#GetTraceInTimeRange
+func (m *ReadMetricsDecorator) GetTraceInTimeRange(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	start := time.Now()
+	retMe, err := spanstore.GetTraceInTimeRange(ctx, m.spanReader, query)
+	m.getTraceMetrics.emit(ctx, err, time.Since(start), 1)
+	return retMe, err
+}
+
+// ReadsOTLP returns whether the decorated reader implements spanstore.OTLPReader.
+func (m *ReadMetricsDecorator) ReadsOTLP() bool {
+	return spanstore.ReadsOTLP(m.spanReader)
+}
+
+// GetTraceOTLP implements spanstore.OTLPReader#GetTraceOTLP
+func (m *ReadMetricsDecorator) GetTraceOTLP(ctx context.Context, traceID model.TraceID) (ptrace.Traces, error) {
+	start := time.Now()
+	retMe, err := spanstore.GetTraceOTLP(ctx, m.spanReader, traceID)
+	m.getTraceMetrics.emit(ctx, err, time.Since(start), 1)
+	return retMe, err
+}
+
+// FindTracesOTLP implements spanstore.OTLPReader#FindTracesOTLP
+func (m *ReadMetricsDecorator) FindTracesOTLP(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]ptrace.Traces, error) {
+	start := time.Now()
+	retMe, err := spanstore.FindTracesOTLP(ctx, m.spanReader, traceQuery)
+	m.findTracesMetrics.emit(ctx, err, time.Since(start), len(retMe))
 	return retMe, err
 }
 
@@ -100,7 +165,7 @@ func (m *ReadMetricsDecorator) GetTrace(ctx context.Context, traceID model.Trace
 func (m *ReadMetricsDecorator) GetServices(ctx context.Context) ([]string, error) {
 	start := time.Now()
 	retMe, err := m.spanReader.GetServices(ctx)
-	m.getServicesMetrics.emit(err, time.Since(start), len(retMe))
+	m.getServicesMetrics.emit(ctx, err, time.Since(start), len(retMe))
 	return retMe, err
 }
 
@@ -111,6 +176,60 @@ func (m *ReadMetricsDecorator) GetOperations(
 ) ([]spanstore.Operation, error) {
 	start := time.Now()
 	retMe, err := m.spanReader.GetOperations(ctx, query)
-	m.getOperationsMetrics.emit(err, time.Since(start), len(retMe))
+	m.getOperationsMetrics.emit(ctx, err, time.Since(start), len(retMe))
+	return retMe, err
+}
+
+// FindOperations implements spanstore.OperationFilterReader#FindOperations
+func (m *ReadMetricsDecorator) FindOperations(
+	ctx context.Context,
+	query spanstore.OperationQueryParameters,
+) ([]spanstore.Operation, error) {
+	start := time.Now()
+	retMe, err := spanstore.FindOperations(ctx, m.spanReader, query)
+	m.getOperationsMetrics.emit(ctx, err, time.Since(start), len(retMe))
+	return retMe, err
+}
+
+// GetLatencyDistribution implements spanstore.LatencyReader#GetLatencyDistribution
+func (m *ReadMetricsDecorator) GetLatencyDistribution(
+	ctx context.Context,
+	query *spanstore.LatencyQueryParameters,
+) (*spanstore.LatencyDistribution, error) {
+	start := time.Now()
+	retMe, err := spanstore.GetLatencyDistribution(ctx, m.spanReader, query)
+	m.getLatencyMetrics.emit(ctx, err, time.Since(start), 1)
+	return retMe, err
+}
+
+// GetREDMetrics implements spanstore.REDReader#GetREDMetrics
+func (m *ReadMetricsDecorator) GetREDMetrics(
+	ctx context.Context,
+	query *spanstore.REDQueryParameters,
+) ([]spanstore.REDSeries, error) {
+	start := time.Now()
+	retMe, err := spanstore.GetREDMetrics(ctx, m.spanReader, query)
+	m.getREDMetrics.emit(ctx, err, time.Since(start), len(retMe))
+	return retMe, err
+}
+
+// SupportsTags returns whether the decorated reader implements spanstore.TagReader.
+func (m *ReadMetricsDecorator) SupportsTags() bool {
+	return spanstore.SupportsTags(m.spanReader)
+}
+
+// GetTagKeys implements spanstore.TagReader#GetTagKeys
+func (m *ReadMetricsDecorator) GetTagKeys(ctx context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	start := time.Now()
+	retMe, err := spanstore.GetTagKeys(ctx, m.spanReader, query)
+	m.getTagKeysMetrics.emit(ctx, err, time.Since(start), len(retMe))
+	return retMe, err
+}
+
+// GetTagValues implements spanstore.TagReader#GetTagValues
+func (m *ReadMetricsDecorator) GetTagValues(ctx context.Context, query *spanstore.TagQueryParameters) ([]string, error) {
+	start := time.Now()
+	retMe, err := spanstore.GetTagValues(ctx, m.spanReader, query)
+	m.getTagValuesMetrics.emit(ctx, err, time.Since(start), len(retMe))
 	return retMe, err
 }
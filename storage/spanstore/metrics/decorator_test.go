@@ -19,9 +19,16 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/trace"
 
+	jprom "github.com/jaegertracing/jaeger/internal/metrics/prometheus"
 	"github.com/jaegertracing/jaeger/internal/metricstest"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -143,3 +150,227 @@ func TestFailingUnderlyingCalls(t *testing.T) {
 
 	checkExpectedExistingAndNonExistentCounters(t, counters, expecteds, gauges, existingKeys, nonExistentKeys)
 }
+
+func TestFindTracesPage(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mockReader := mocks.Reader{}
+	mrs := NewReadMetricsDecorator(&mockReader, mf)
+	mockReader.On("FindTraces", context.Background(), &spanstore.TraceQueryParameters{}).
+		Return([]*model.Trace{}, nil).Once()
+	mockReader.On("FindTraces", context.Background(), &spanstore.TraceQueryParameters{}).
+		Return(nil, errors.New("Failure")).Once()
+	page, err := mrs.FindTracesPage(context.Background(), &spanstore.TraceQueryParameters{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Traces)
+	_, err = mrs.FindTracesPage(context.Background(), &spanstore.TraceQueryParameters{})
+	require.Error(t, err)
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=find_traces|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=find_traces|result=err"])
+}
+
+func TestGetLatencyDistribution(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mockReader := mocks.Reader{}
+	mrs := NewReadMetricsDecorator(&mockReader, mf)
+	mockReader.On("FindTraces", context.Background(), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{}, nil)
+	dist, err := mrs.GetLatencyDistribution(context.Background(), &spanstore.LatencyQueryParameters{ServiceName: "svc"})
+	require.NoError(t, err)
+	assert.Zero(t, dist.Count)
+	_, err = mrs.GetLatencyDistribution(context.Background(), &spanstore.LatencyQueryParameters{})
+	require.Error(t, err)
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=get_latency_distribution|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=get_latency_distribution|result=err"])
+}
+
+func TestFindOperations(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mockReader := mocks.Reader{}
+	mrs := NewReadMetricsDecorator(&mockReader, mf)
+	mockReader.On("GetOperations", context.Background(), spanstore.OperationQueryParameters{ServiceName: "svc"}).
+		Return([]spanstore.Operation{{Name: "b"}, {Name: "a"}}, nil).Once()
+	mockReader.On("GetOperations", context.Background(), spanstore.OperationQueryParameters{ServiceName: "svc"}).
+		Return(nil, errors.New("failure")).Once()
+	operations, err := mrs.FindOperations(context.Background(), spanstore.OperationQueryParameters{ServiceName: "svc", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []spanstore.Operation{{Name: "a"}}, operations)
+	_, err = mrs.FindOperations(context.Background(), spanstore.OperationQueryParameters{ServiceName: "svc", Limit: 1})
+	require.Error(t, err)
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=get_operations|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=get_operations|result=err"])
+}
+
+func TestStreamTrace(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mockReader := mocks.Reader{}
+	mrs := NewReadMetricsDecorator(&mockReader, mf)
+	mockReader.On("GetTrace", context.Background(), model.TraceID{}).
+		Return(&model.Trace{Spans: []*model.Span{{}}}, nil).Once()
+	mockReader.On("GetTrace", context.Background(), model.TraceID{}).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	var spans []*model.Span
+	err := mrs.StreamTrace(context.Background(), model.TraceID{}, func(batch []*model.Span) error {
+		spans = append(spans, batch...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, spans, 1)
+	err = mrs.StreamTrace(context.Background(), model.TraceID{}, nil)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=get_trace|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=get_trace|result=err"])
+}
+
+func TestGetTraceInTimeRange(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mockReader := mocks.Reader{}
+	mrs := NewReadMetricsDecorator(&mockReader, mf)
+	mockReader.On("GetTrace", context.Background(), model.TraceID{}).
+		Return(&model.Trace{}, nil).Once()
+	mockReader.On("GetTrace", context.Background(), model.TraceID{}).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	query := spanstore.GetTraceParameters{StartTime: time.Unix(1000, 0), EndTime: time.Unix(2000, 0)}
+	_, err := mrs.GetTraceInTimeRange(context.Background(), query)
+	require.NoError(t, err)
+	_, err = mrs.GetTraceInTimeRange(context.Background(), query)
+	require.ErrorIs(t, err, spanstore.ErrTraceNotFound)
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=get_trace|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=get_trace|result=err"])
+}
+
+type otlpReader struct {
+	*mocks.Reader
+}
+
+func (otlpReader) GetTraceOTLP(context.Context, model.TraceID) (ptrace.Traces, error) {
+	return ptrace.NewTraces(), nil
+}
+
+func (otlpReader) FindTracesOTLP(context.Context, *spanstore.TraceQueryParameters) ([]ptrace.Traces, error) {
+	return nil, errors.New("failure")
+}
+
+func TestOTLPReads(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mrs := NewReadMetricsDecorator(otlpReader{Reader: &mocks.Reader{}}, mf)
+	assert.True(t, mrs.ReadsOTLP())
+	assert.True(t, spanstore.ReadsOTLP(mrs))
+	_, err := mrs.GetTraceOTLP(context.Background(), model.TraceID{})
+	require.NoError(t, err)
+	_, err = mrs.FindTracesOTLP(context.Background(), &spanstore.TraceQueryParameters{})
+	require.Error(t, err)
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=get_trace|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=find_traces|result=err"])
+
+	// the traces of the other readers are converted
+	mockReader := mocks.Reader{}
+	mockReader.On("GetTrace", context.Background(), model.TraceID{}).
+		Return(&model.Trace{Spans: []*model.Span{{OperationName: "converted"}}}, nil).Once()
+	unsupported := NewReadMetricsDecorator(&mockReader, mf)
+	assert.False(t, spanstore.ReadsOTLP(unsupported))
+	td, err := unsupported.GetTraceOTLP(context.Background(), model.TraceID{})
+	require.NoError(t, err)
+	assert.Equal(t, "converted", td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+}
+
+func TestGetREDMetrics(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mockReader := mocks.Reader{}
+	mrs := NewReadMetricsDecorator(&mockReader, mf)
+	mockReader.On("FindTraces", context.Background(), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return([]*model.Trace{}, nil)
+	series, err := mrs.GetREDMetrics(context.Background(), &spanstore.REDQueryParameters{ServiceNames: []string{"svc"}, Step: time.Minute})
+	require.NoError(t, err)
+	assert.Empty(t, series)
+	_, err = mrs.GetREDMetrics(context.Background(), &spanstore.REDQueryParameters{})
+	require.Error(t, err)
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=get_red_metrics|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=get_red_metrics|result=err"])
+}
+
+type tagReader struct {
+	*mocks.Reader
+}
+
+func (tagReader) GetTagKeys(context.Context, *spanstore.TagQueryParameters) ([]string, error) {
+	return []string{"http.method"}, nil
+}
+
+func (tagReader) GetTagValues(context.Context, *spanstore.TagQueryParameters) ([]string, error) {
+	return nil, errors.New("failure")
+}
+
+func TestGetTags(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	mrs := NewReadMetricsDecorator(tagReader{Reader: &mocks.Reader{}}, mf)
+	assert.True(t, mrs.SupportsTags())
+	keys, err := mrs.GetTagKeys(context.Background(), &spanstore.TagQueryParameters{ServiceName: "svc"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http.method"}, keys)
+	_, err = mrs.GetTagValues(context.Background(), &spanstore.TagQueryParameters{ServiceName: "svc", Key: "http.method"})
+	require.Error(t, err)
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=get_tag_keys|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=get_tag_values|result=err"])
+
+	unsupported := NewReadMetricsDecorator(&mocks.Reader{}, mf)
+	assert.False(t, unsupported.SupportsTags())
+	assert.False(t, spanstore.SupportsTags(unsupported))
+	_, err = unsupported.GetTagKeys(context.Background(), &spanstore.TagQueryParameters{ServiceName: "svc"})
+	require.ErrorIs(t, err, spanstore.ErrTagsNotSupported)
+}
+
+func TestLatencyExemplars(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	mockReader := mocks.Reader{}
+	mrs := NewReadMetricsDecorator(&mockReader, jprom.New(jprom.WithRegisterer(registry)))
+
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	}))
+	mockReader.On("GetServices", ctx).Return([]string{}, nil)
+	_, err := mrs.GetServices(ctx)
+	require.NoError(t, err)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	var exemplars []string
+	for _, family := range families {
+		if family.GetName() != "latency" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, bucket := range m.GetHistogram().GetBucket() {
+				for _, label := range bucket.GetExemplar().GetLabel() {
+					exemplars = append(exemplars, label.GetName()+"="+label.GetValue())
+				}
+			}
+		}
+	}
+	assert.Equal(t, []string{"trace_id=" + traceID.String()}, exemplars)
+}
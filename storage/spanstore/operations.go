@@ -0,0 +1,66 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// OperationFilterReader is an additional interface that can be implemented by a Reader
+// able to filter and paginate the operations of a service in the storage.
+type OperationFilterReader interface {
+	// FindOperations returns the operations selected by all the fields of the query,
+	// sorted by name and span kind.
+	FindOperations(ctx context.Context, query OperationQueryParameters) ([]Operation, error)
+}
+
+// FindOperations returns the operations selected by all the fields of the query, sorted by
+// name and span kind. If the reader does not implement OperationFilterReader, all operations
+// of the service are loaded with GetOperations and filtered in memory.
+func FindOperations(ctx context.Context, reader Reader, query OperationQueryParameters) ([]Operation, error) {
+	if !query.filtered() {
+		return reader.GetOperations(ctx, query)
+	}
+	if filtering, ok := reader.(OperationFilterReader); ok {
+		return filtering.FindOperations(ctx, query)
+	}
+	operations, err := reader.GetOperations(ctx, OperationQueryParameters{
+		ServiceName: query.ServiceName,
+		SpanKind:    query.SpanKind,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FilterOperations(operations, query), nil
+}
+
+// FilterOperations applies the name filters and the pagination of the query to the operations
+// of the service, which are sorted by name and span kind. The given slice is not modified.
+func FilterOperations(operations []Operation, query OperationQueryParameters) []Operation {
+	filtered := make([]Operation, 0, len(operations))
+	for _, operation := range operations {
+		if strings.HasPrefix(operation.Name, query.NamePrefix) && strings.Contains(operation.Name, query.NameContains) {
+			filtered = append(filtered, operation)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		if filtered[i].Name != filtered[j].Name {
+			return filtered[i].Name < filtered[j].Name
+		}
+		return filtered[i].SpanKind < filtered[j].SpanKind
+	})
+	if query.Offset > 0 {
+		filtered = filtered[min(query.Offset, len(filtered)):]
+	}
+	if query.Limit > 0 && query.Limit < len(filtered) {
+		filtered = filtered[:query.Limit]
+	}
+	return filtered
+}
+
+func (query OperationQueryParameters) filtered() bool {
+	return query.NamePrefix != "" || query.NameContains != "" || query.Offset > 0 || query.Limit > 0
+}
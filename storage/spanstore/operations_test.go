@@ -0,0 +1,107 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type operationsReader struct {
+	Reader
+	operations []Operation
+	queries    []OperationQueryParameters
+	err        error
+}
+
+func (r *operationsReader) GetOperations(_ context.Context, query OperationQueryParameters) ([]Operation, error) {
+	r.queries = append(r.queries, query)
+	return r.operations, r.err
+}
+
+type filteringReader struct {
+	operationsReader
+}
+
+func (r *filteringReader) FindOperations(_ context.Context, query OperationQueryParameters) ([]Operation, error) {
+	return FilterOperations(r.operations[:1], query), nil
+}
+
+var testOperations = []Operation{
+	{Name: "GET /users", SpanKind: "server"},
+	{Name: "GET /orders", SpanKind: "server"},
+	{Name: "GET /orders", SpanKind: "client"},
+	{Name: "POST /orders", SpanKind: "server"},
+	{Name: "db.query", SpanKind: "client"},
+}
+
+func TestFilterOperations(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    OperationQueryParameters
+		expected []Operation
+	}{
+		{
+			name:     "sorted",
+			query:    OperationQueryParameters{},
+			expected: []Operation{testOperations[2], testOperations[1], testOperations[0], testOperations[3], testOperations[4]},
+		},
+		{
+			name:     "prefix",
+			query:    OperationQueryParameters{NamePrefix: "GET "},
+			expected: []Operation{testOperations[2], testOperations[1], testOperations[0]},
+		},
+		{
+			name:     "contains",
+			query:    OperationQueryParameters{NameContains: "orders"},
+			expected: []Operation{testOperations[2], testOperations[1], testOperations[3]},
+		},
+		{
+			name:     "page",
+			query:    OperationQueryParameters{Offset: 1, Limit: 2},
+			expected: []Operation{testOperations[1], testOperations[0]},
+		},
+		{
+			name:     "offset past the end",
+			query:    OperationQueryParameters{Offset: 10},
+			expected: []Operation{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, FilterOperations(testOperations, test.query))
+		})
+	}
+	assert.Equal(t, "GET /users", testOperations[0].Name, "the input is not modified")
+}
+
+func TestFindOperations(t *testing.T) {
+	reader := &operationsReader{operations: testOperations}
+	operations, err := FindOperations(context.Background(), reader, OperationQueryParameters{ServiceName: "svc"})
+	require.NoError(t, err)
+	assert.Equal(t, testOperations, operations, "unfiltered queries are passed to GetOperations")
+
+	operations, err = FindOperations(context.Background(), reader, OperationQueryParameters{
+		ServiceName: "svc",
+		SpanKind:    "server",
+		NamePrefix:  "POST",
+		Limit:       1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Operation{testOperations[3]}, operations)
+	assert.Equal(t, OperationQueryParameters{ServiceName: "svc", SpanKind: "server"}, reader.queries[1])
+
+	reader.err = assert.AnError
+	_, err = FindOperations(context.Background(), reader, OperationQueryParameters{Limit: 1})
+	require.ErrorIs(t, err, assert.AnError)
+
+	filtering := &filteringReader{operationsReader{operations: testOperations}}
+	operations, err = FindOperations(context.Background(), filtering, OperationQueryParameters{Limit: 5})
+	require.NoError(t, err)
+	assert.Equal(t, testOperations[:1], operations)
+	assert.Empty(t, filtering.queries)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/converter/otlp"
+)
+
+// OTLPReader is an additional interface that can be implemented by a Reader storing
+// the traces in the OTLP format, or able to convert them cheaply, to return them
+// without converting them to the Jaeger model and back.
+type OTLPReader interface {
+	// GetTraceOTLP retrieves the trace with a given id in the OTLP format.
+	//
+	// If no spans are stored for this trace, it returns ErrTraceNotFound.
+	GetTraceOTLP(ctx context.Context, traceID model.TraceID) (ptrace.Traces, error)
+
+	// FindTracesOTLP returns the traces matching the query parameters in the OTLP format,
+	// one element per trace, with the same semantics as Reader.FindTraces.
+	FindTracesOTLP(ctx context.Context, query *TraceQueryParameters) ([]ptrace.Traces, error)
+}
+
+// otlpSupport is implemented by the decorators of the readers, which implement OTLPReader
+// whether the readers they decorate do or not.
+type otlpSupport interface {
+	ReadsOTLP() bool
+}
+
+// ReadsOTLP returns whether the reader returns the traces in the OTLP format without converting them.
+func ReadsOTLP(reader Reader) bool {
+	if s, ok := reader.(otlpSupport); ok {
+		return s.ReadsOTLP()
+	}
+	_, ok := reader.(OTLPReader)
+	return ok
+}
+
+// OTLPWriter is an additional interface that can be implemented by a Writer storing
+// the traces in the OTLP format, to write them without converting them to the Jaeger model.
+type OTLPWriter interface {
+	// WriteTraces writes the spans of the traces in the OTLP format.
+	WriteTraces(ctx context.Context, td ptrace.Traces) error
+}
+
+// WriteTraces writes the traces in the OTLP format with the writer. If the writer does not
+// implement OTLPWriter, the traces are converted to the Jaeger model and written span by span.
+func WriteTraces(ctx context.Context, writer Writer, td ptrace.Traces) error {
+	if otlpWriter, ok := writer.(OTLPWriter); ok {
+		return otlpWriter.WriteTraces(ctx, td)
+	}
+	batches, err := otlp.ProtoFromTraces(td)
+	if err != nil {
+		return fmt.Errorf("cannot transform OTLP traces to Jaeger format: %w", err)
+	}
+	var errs []error
+	for _, batch := range batches {
+		for _, span := range batch.Spans {
+			if span.Process == nil {
+				span.Process = batch.Process
+			}
+			errs = append(errs, writer.WriteSpan(ctx, span))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetTraceOTLP returns the trace from the reader in the OTLP format. If the reader does not
+// implement OTLPReader, the trace is loaded with GetTrace and converted.
+func GetTraceOTLP(ctx context.Context, reader Reader, traceID model.TraceID) (ptrace.Traces, error) {
+	if otlpReader, ok := reader.(OTLPReader); ok {
+		return otlpReader.GetTraceOTLP(ctx, traceID)
+	}
+	trace, err := reader.GetTrace(ctx, traceID)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	return TraceToOTLP(trace)
+}
+
+// FindTracesOTLP returns the traces matching the query from the reader in the OTLP format.
+// If the reader does not implement OTLPReader, the traces are found with FindTraces and converted.
+func FindTracesOTLP(ctx context.Context, reader Reader, query *TraceQueryParameters) ([]ptrace.Traces, error) {
+	if otlpReader, ok := reader.(OTLPReader); ok {
+		return otlpReader.FindTracesOTLP(ctx, query)
+	}
+	traces, err := reader.FindTraces(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return TracesToOTLP(traces)
+}
+
+// TraceToOTLP converts a trace of the Jaeger model to the OTLP format.
+func TraceToOTLP(trace *model.Trace) (ptrace.Traces, error) {
+	return otlp.ProtoToTraces([]*model.Batch{{Spans: trace.Spans}})
+}
+
+// TracesToOTLP converts traces of the Jaeger model to the OTLP format, one element per trace.
+func TracesToOTLP(traces []*model.Trace) ([]ptrace.Traces, error) {
+	out := make([]ptrace.Traces, 0, len(traces))
+	for _, trace := range traces {
+		td, err := TraceToOTLP(trace)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, td)
+	}
+	return out, nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type otlpReader struct {
+	Reader
+	td ptrace.Traces
+}
+
+func (r otlpReader) GetTraceOTLP(context.Context, model.TraceID) (ptrace.Traces, error) {
+	return r.td, nil
+}
+
+func (r otlpReader) FindTracesOTLP(context.Context, *TraceQueryParameters) ([]ptrace.Traces, error) {
+	return []ptrace.Traces{r.td}, nil
+}
+
+type findTracesReader struct {
+	Reader
+	traces []*model.Trace
+	err    error
+}
+
+func (r findTracesReader) FindTraces(context.Context, *TraceQueryParameters) ([]*model.Trace, error) {
+	return r.traces, r.err
+}
+
+func newOTLPTraces(name string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(name)
+	return td
+}
+
+func spanName(td ptrace.Traces) string {
+	return td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name()
+}
+
+func TestGetTraceOTLP(t *testing.T) {
+	td, err := GetTraceOTLP(context.Background(), otlpReader{td: newOTLPTraces("native")}, model.TraceID{})
+	require.NoError(t, err)
+	assert.Equal(t, "native", spanName(td))
+
+	reader := traceReader{trace: &model.Trace{Spans: []*model.Span{{OperationName: "converted"}}}}
+	td, err = GetTraceOTLP(context.Background(), reader, model.TraceID{})
+	require.NoError(t, err)
+	assert.Equal(t, "converted", spanName(td))
+
+	_, err = GetTraceOTLP(context.Background(), traceReader{err: ErrTraceNotFound}, model.TraceID{})
+	require.ErrorIs(t, err, ErrTraceNotFound)
+}
+
+func TestReadsOTLP(t *testing.T) {
+	assert.True(t, ReadsOTLP(otlpReader{}))
+	assert.False(t, ReadsOTLP(traceReader{}))
+}
+
+func TestFindTracesOTLP(t *testing.T) {
+	traces, err := FindTracesOTLP(context.Background(), otlpReader{td: newOTLPTraces("native")}, &TraceQueryParameters{})
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+	assert.Equal(t, "native", spanName(traces[0]))
+
+	reader := findTracesReader{traces: []*model.Trace{
+		{Spans: []*model.Span{{OperationName: "first"}}},
+		{Spans: []*model.Span{{OperationName: "second"}}},
+	}}
+	traces, err = FindTracesOTLP(context.Background(), reader, &TraceQueryParameters{})
+	require.NoError(t, err)
+	require.Len(t, traces, 2)
+	assert.Equal(t, "first", spanName(traces[0]))
+	assert.Equal(t, "second", spanName(traces[1]))
+
+	findErr := errors.New("find error")
+	_, err = FindTracesOTLP(context.Background(), findTracesReader{err: findErr}, &TraceQueryParameters{})
+	require.ErrorIs(t, err, findErr)
+}
+
+type otlpWriter struct {
+	Writer
+	traces []ptrace.Traces
+}
+
+func (w *otlpWriter) WriteTraces(_ context.Context, td ptrace.Traces) error {
+	w.traces = append(w.traces, td)
+	return nil
+}
+
+type spanWriter struct {
+	spans []*model.Span
+	err   error
+}
+
+func (w *spanWriter) WriteSpan(_ context.Context, span *model.Span) error {
+	w.spans = append(w.spans, span)
+	return w.err
+}
+
+func TestWriteTraces(t *testing.T) {
+	native := &otlpWriter{}
+	require.NoError(t, WriteTraces(context.Background(), native, newOTLPTraces("native")))
+	require.Len(t, native.traces, 1)
+	assert.Equal(t, "native", spanName(native.traces[0]))
+
+	td := newOTLPTraces("converted")
+	td.ResourceSpans().At(0).Resource().Attributes().PutStr("service.name", "svc")
+	writer := &spanWriter{}
+	require.NoError(t, WriteTraces(context.Background(), writer, td))
+	require.Len(t, writer.spans, 1)
+	assert.Equal(t, "converted", writer.spans[0].OperationName)
+	assert.Equal(t, "svc", writer.spans[0].Process.ServiceName)
+
+	writeErr := errors.New("write error")
+	err := WriteTraces(context.Background(), &spanWriter{err: writeErr}, td)
+	require.ErrorIs(t, err, writeErr)
+}
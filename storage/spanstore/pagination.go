@@ -0,0 +1,261 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// ErrInvalidPageToken is returned when a page token cannot be decoded.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PaginatedReader is an additional interface that can be implemented by a Reader
+// to support cursor-based pagination of trace search results.
+type PaginatedReader interface {
+	// FindTracesPage returns up to query.NumTraces traces matching the query, ordered from
+	// newest to oldest, starting after the position identified by query.PageToken.
+	// The returned page contains the token for the next page, or an empty token when
+	// there are no more results.
+	FindTracesPage(ctx context.Context, query *TraceQueryParameters) (*TracesPage, error)
+}
+
+// TracesPage is a single page of trace search results.
+type TracesPage struct {
+	Traces        []*model.Trace
+	NextPageToken string
+}
+
+// PageCursor identifies a position in a list of traces ordered by a sort key, e.g. their start time,
+// newest first. The sort keys are compared in microseconds, the precision of the tokens.
+type PageCursor struct {
+	// StartTime is the sort key of the oldest trace returned so far.
+	StartTime time.Time
+	// TraceIDs are the already returned traces whose sort key is StartTime.
+	TraceIDs []model.TraceID
+}
+
+// SortKey returns the time by which a backend orders the traces matching the query, newest first,
+// and to which it applies the start time range of the query.
+type SortKey func(trace *model.Trace, query *TraceQueryParameters) time.Time
+
+type pageCursorJSON struct {
+	StartTime int64    `json:"t"`
+	TraceIDs  []string `json:"ids,omitempty"`
+}
+
+// ParsePageToken decodes a token produced by PageCursor.Token.
+// It returns nil cursor for an empty token, i.e. the first page.
+func ParsePageToken(token string) (*PageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPageToken, err)
+	}
+	var raw pageCursorJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPageToken, err)
+	}
+	cursor := &PageCursor{
+		StartTime: model.EpochMicrosecondsAsTime(uint64(raw.StartTime)),
+	}
+	for _, id := range raw.TraceIDs {
+		traceID, err := model.TraceIDFromString(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPageToken, err)
+		}
+		cursor.TraceIDs = append(cursor.TraceIDs, traceID)
+	}
+	return cursor, nil
+}
+
+// Token encodes the cursor as an opaque URL-safe string.
+func (c *PageCursor) Token() string {
+	raw := pageCursorJSON{
+		StartTime: int64(model.TimeAsEpochMicroseconds(c.StartTime)),
+	}
+	for _, id := range c.TraceIDs {
+		raw.TraceIDs = append(raw.TraceIDs, id.String())
+	}
+	data, _ := json.Marshal(raw)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Includes returns true if the trace comes after the cursor position in the traces ordered
+// by start time, i.e. it has not been returned on any of the previous pages.
+func (c *PageCursor) Includes(trace *model.Trace) bool {
+	return c.includes(TraceStartTime(trace), trace.Spans[0].TraceID)
+}
+
+// includes returns true if the trace with the sort key comes after the cursor position.
+func (c *PageCursor) includes(sortKey time.Time, traceID model.TraceID) bool {
+	if c == nil {
+		return true
+	}
+	sortKey = sortKey.Truncate(time.Microsecond)
+	if sortKey.Before(c.StartTime) {
+		return true
+	}
+	if !sortKey.Equal(c.StartTime) {
+		return false
+	}
+	for _, id := range c.TraceIDs {
+		if id == traceID {
+			return false
+		}
+	}
+	return true
+}
+
+// TraceStartTime returns the earliest start time of the spans in the trace.
+func TraceStartTime(trace *model.Trace) time.Time {
+	var startTime time.Time
+	for i, span := range trace.Spans {
+		if i == 0 || span.StartTime.Before(startTime) {
+			startTime = span.StartTime
+		}
+	}
+	return startTime
+}
+
+// ByMatchingSpanStartTime is the SortKey of the backends searching the spans, e.g. through indexes
+// of the spans by service and start time: a trace is ordered by the start time of its latest span
+// of the service and operation of the query started within the time range of the query, or by its
+// start time if none is found, e.g. when the trace matched on the tags of another span.
+func ByMatchingSpanStartTime(trace *model.Trace, query *TraceQueryParameters) time.Time {
+	var sortKey time.Time
+	for _, span := range trace.Spans {
+		if query.ServiceName != "" && (span.Process == nil || span.Process.ServiceName != query.ServiceName) {
+			continue
+		}
+		if query.OperationName != "" && span.OperationName != query.OperationName {
+			continue
+		}
+		if !query.StartTimeMin.IsZero() && span.StartTime.Before(query.StartTimeMin) {
+			continue
+		}
+		if !query.StartTimeMax.IsZero() && span.StartTime.After(query.StartTimeMax) {
+			continue
+		}
+		if span.StartTime.After(sortKey) {
+			sortKey = span.StartTime
+		}
+	}
+	if sortKey.IsZero() {
+		return TraceStartTime(trace)
+	}
+	return sortKey
+}
+
+// NewTracesPage sorts traces from newest to oldest and returns the first limit of them
+// as a page. If more traces remain, the page is given a token pointing right after
+// the last trace on the page, and on the traces of the previous pages returned at the same
+// start time, given the cursor of the page if any. A non-positive limit returns all traces
+// as a single page.
+func NewTracesPage(traces []*model.Trace, limit int, cursor *PageCursor) *TracesPage {
+	return newTracesPage(traces, limit, cursor, TraceStartTime)
+}
+
+// newTracesPage builds the page like NewTracesPage, the traces being ordered by the sort key.
+func newTracesPage(traces []*model.Trace, limit int, cursor *PageCursor, sortKey func(trace *model.Trace) time.Time) *TracesPage {
+	type keyedTrace struct {
+		trace   *model.Trace
+		sortKey time.Time
+	}
+	var nonEmpty []keyedTrace
+	for _, trace := range traces {
+		if len(trace.Spans) > 0 {
+			nonEmpty = append(nonEmpty, keyedTrace{trace: trace, sortKey: sortKey(trace)})
+		}
+	}
+	sort.SliceStable(nonEmpty, func(i, j int) bool {
+		return nonEmpty[i].sortKey.After(nonEmpty[j].sortKey)
+	})
+	page := make([]*model.Trace, 0, len(nonEmpty))
+	for _, keyed := range nonEmpty {
+		page = append(page, keyed.trace)
+	}
+	if limit <= 0 || len(page) <= limit {
+		return &TracesPage{Traces: page}
+	}
+	page = page[:limit]
+	next := &PageCursor{StartTime: nonEmpty[limit-1].sortKey.Truncate(time.Microsecond)}
+	for i := limit - 1; i >= 0 && nonEmpty[i].sortKey.Truncate(time.Microsecond).Equal(next.StartTime); i-- {
+		next.TraceIDs = append(next.TraceIDs, page[i].Spans[0].TraceID)
+	}
+	if cursor != nil && cursor.StartTime.Equal(next.StartTime) {
+		// the traces at the same time span several pages
+		next.TraceIDs = append(next.TraceIDs, cursor.TraceIDs...)
+	}
+	return &TracesPage{
+		Traces:        page,
+		NextPageToken: next.Token(),
+	}
+}
+
+// FindTracesPage returns a page of traces from the reader. If the reader implements
+// PaginatedReader it is used directly, otherwise the page is built with PaginateBySortKey,
+// the traces being searched by the start time of their spans.
+func FindTracesPage(ctx context.Context, reader Reader, query *TraceQueryParameters) (*TracesPage, error) {
+	if paginated, ok := reader.(PaginatedReader); ok {
+		return paginated.FindTracesPage(ctx, query)
+	}
+	return PaginateBySortKey(ctx, reader.FindTraces, query, ByMatchingSpanStartTime)
+}
+
+// PaginateBySortKey implements pagination on top of a plain FindTraces function by
+// moving query.StartTimeMax to the cursor position and filtering out the traces that
+// were already returned. It relies on the backend returning the most recent traces
+// by the sort key when the number of matches exceeds query.NumTraces.
+func PaginateBySortKey(
+	ctx context.Context,
+	findTraces func(context.Context, *TraceQueryParameters) ([]*model.Trace, error),
+	query *TraceQueryParameters,
+	sortKey SortKey,
+) (*TracesPage, error) {
+	cursor, err := ParsePageToken(query.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	pageQuery := *query
+	pageQuery.PageToken = ""
+	if cursor != nil {
+		// the traces of the microsecond of the cursor are searched again and filtered, as the sort keys
+		// are truncated to microseconds and some backends exclude the spans started at StartTimeMax
+		startTimeMax := cursor.StartTime.Add(time.Microsecond)
+		if pageQuery.StartTimeMax.IsZero() || startTimeMax.Before(pageQuery.StartTimeMax) {
+			pageQuery.StartTimeMax = startTimeMax
+		}
+	}
+	if pageQuery.NumTraces > 0 {
+		// fetch one more trace than requested to find out if there is a next page
+		pageQuery.NumTraces++
+		if cursor != nil {
+			pageQuery.NumTraces += len(cursor.TraceIDs)
+		}
+	}
+	traces, err := findTraces(ctx, &pageQuery)
+	if err != nil {
+		return nil, err
+	}
+	traceSortKey := func(trace *model.Trace) time.Time {
+		return sortKey(trace, query)
+	}
+	var remaining []*model.Trace
+	for _, trace := range traces {
+		if len(trace.Spans) > 0 && cursor.includes(traceSortKey(trace), trace.Spans[0].TraceID) {
+			remaining = append(remaining, trace)
+		}
+	}
+	return newTracesPage(remaining, query.NumTraces, cursor, traceSortKey), nil
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func makeTrace(traceID uint64, startTime time.Time) *model.Trace {
+	return &model.Trace{
+		Spans: []*model.Span{
+			{TraceID: model.NewTraceID(0, traceID), SpanID: 2, StartTime: startTime.Add(time.Second)},
+			{TraceID: model.NewTraceID(0, traceID), SpanID: 1, StartTime: startTime},
+		},
+	}
+}
+
+func traceIDs(traces []*model.Trace) []uint64 {
+	var ids []uint64
+	for _, trace := range traces {
+		ids = append(ids, trace.Spans[0].TraceID.Low)
+	}
+	return ids
+}
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	cursor := &PageCursor{
+		StartTime: time.Unix(1700000000, 123000).UTC(),
+		TraceIDs:  []model.TraceID{model.NewTraceID(1, 2), model.NewTraceID(0, 3)},
+	}
+	parsed, err := ParsePageToken(cursor.Token())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	parsed, err = ParsePageToken("")
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+}
+
+func TestParsePageTokenErrors(t *testing.T) {
+	for _, token := range []string{
+		"!!!",
+		"bm90LWpzb24",                // not-json
+		"eyJ0IjoxLCJpZHMiOlsieiJdfQ", // {"t":1,"ids":["z"]}
+	} {
+		_, err := ParsePageToken(token)
+		require.ErrorIs(t, err, ErrInvalidPageToken, token)
+	}
+}
+
+func TestNewTracesPage(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	traces := []*model.Trace{
+		makeTrace(1, base.Add(1*time.Minute)),
+		makeTrace(2, base.Add(3*time.Minute)),
+		makeTrace(3, base.Add(2*time.Minute)),
+		makeTrace(4, base.Add(2*time.Minute)),
+		{},
+	}
+
+	page := NewTracesPage(traces, 0, nil)
+	assert.Equal(t, []uint64{2, 3, 4, 1}, traceIDs(page.Traces))
+	assert.Empty(t, page.NextPageToken)
+
+	page = NewTracesPage(traces, 4, nil)
+	assert.Len(t, page.Traces, 4)
+	assert.Empty(t, page.NextPageToken)
+
+	page = NewTracesPage(traces, 3, nil)
+	assert.Equal(t, []uint64{2, 3, 4}, traceIDs(page.Traces))
+	cursor, err := ParsePageToken(page.NextPageToken)
+	require.NoError(t, err)
+	assert.True(t, cursor.StartTime.Equal(base.Add(2*time.Minute)))
+	assert.ElementsMatch(t, []model.TraceID{model.NewTraceID(0, 3), model.NewTraceID(0, 4)}, cursor.TraceIDs)
+	assert.False(t, cursor.Includes(traces[1]))
+	assert.False(t, cursor.Includes(traces[2]))
+	assert.True(t, cursor.Includes(traces[0]))
+	assert.True(t, cursor.Includes(makeTrace(5, base.Add(2*time.Minute))))
+}
+
+// makeSearchedTrace makes a trace started by a frontend span, with a driver span started at driverStart.
+func makeSearchedTrace(traceID uint64, startTime, driverStart time.Time) *model.Trace {
+	return &model.Trace{
+		Spans: []*model.Span{
+			{TraceID: model.NewTraceID(0, traceID), SpanID: 1, StartTime: startTime, Process: &model.Process{ServiceName: "frontend"}},
+			{TraceID: model.NewTraceID(0, traceID), SpanID: 2, StartTime: driverStart, Process: &model.Process{ServiceName: "driver"}},
+		},
+	}
+}
+
+func TestByMatchingSpanStartTime(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	trace := makeSearchedTrace(1, base, base.Add(time.Minute))
+	trace.Spans = append(trace.Spans, &model.Span{StartTime: base.Add(time.Hour), Process: &model.Process{ServiceName: "driver"}})
+
+	query := &TraceQueryParameters{ServiceName: "driver", StartTimeMax: base.Add(time.Hour - time.Second)}
+	assert.Equal(t, base.Add(time.Minute), ByMatchingSpanStartTime(trace, query))
+	query.StartTimeMax = time.Time{}
+	assert.Equal(t, base.Add(time.Hour), ByMatchingSpanStartTime(trace, query))
+	query.StartTimeMin = base.Add(2 * time.Hour)
+	assert.Equal(t, base, ByMatchingSpanStartTime(trace, query), "the trace start time without matching span")
+	query = &TraceQueryParameters{ServiceName: "frontend", OperationName: "GET"}
+	assert.Equal(t, base, ByMatchingSpanStartTime(trace, query))
+}
+
+// fakeBackend searches the traces by the start time of their driver spans, like the span indexes.
+type fakeBackend struct {
+	traces []*model.Trace
+	// excludeMax excludes the spans started at StartTimeMax, like the Cassandra indexes
+	excludeMax bool
+	queries    []TraceQueryParameters
+}
+
+func (b *fakeBackend) findTraces(_ context.Context, query *TraceQueryParameters) ([]*model.Trace, error) {
+	b.queries = append(b.queries, *query)
+	var matching []*model.Trace
+	for _, trace := range b.traces {
+		driverStart := trace.Spans[1].StartTime
+		if driverStart.After(query.StartTimeMax) || b.excludeMax && driverStart.Equal(query.StartTimeMax) {
+			continue
+		}
+		matching = append(matching, trace)
+	}
+	return newTracesPage(matching, query.NumTraces, nil, func(trace *model.Trace) time.Time {
+		return trace.Spans[1].StartTime
+	}).Traces, nil
+}
+
+func TestPaginateBySortKey(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	traces := []*model.Trace{
+		makeSearchedTrace(1, base, base.Add(10*time.Minute)),
+		makeSearchedTrace(2, base.Add(5*time.Minute), base.Add(6*time.Minute)),
+		makeSearchedTrace(3, base.Add(1*time.Minute), base.Add(8*time.Minute)),
+		makeSearchedTrace(4, base.Add(2*time.Minute), base.Add(8*time.Minute)),
+		// the sort keys of the traces 5 and 6 are in the same microsecond
+		makeSearchedTrace(5, base.Add(3*time.Minute), base.Add(4*time.Minute+500)),
+		makeSearchedTrace(6, base.Add(4*time.Minute), base.Add(4*time.Minute+200)),
+	}
+	tests := []struct {
+		name       string
+		numTraces  int
+		excludeMax bool
+		pages      [][]uint64
+		// cursor is the sort key of the cursor of the first page
+		cursor time.Duration
+	}{
+		{name: "pages of 2", numTraces: 2, pages: [][]uint64{{1, 3}, {4, 2}, {5, 6}}, cursor: 8 * time.Minute},
+		{name: "pages of 2 excluding max", numTraces: 2, excludeMax: true, pages: [][]uint64{{1, 3}, {4, 2}, {5, 6}}, cursor: 8 * time.Minute},
+		{name: "pages of 1", numTraces: 1, excludeMax: true, pages: [][]uint64{{1}, {3}, {4}, {2}, {5}, {6}}, cursor: 10 * time.Minute},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := &fakeBackend{traces: traces, excludeMax: test.excludeMax}
+			query := &TraceQueryParameters{
+				ServiceName:  "driver",
+				StartTimeMax: base.Add(time.Hour),
+				NumTraces:    test.numTraces,
+			}
+			var pages [][]uint64
+			for {
+				page, err := PaginateBySortKey(context.Background(), backend.findTraces, query, ByMatchingSpanStartTime)
+				require.NoError(t, err)
+				pages = append(pages, traceIDs(page.Traces))
+				if page.NextPageToken == "" {
+					break
+				}
+				query.PageToken = page.NextPageToken
+			}
+			assert.Equal(t, test.pages, pages)
+			assert.Equal(t, test.numTraces+1, backend.queries[0].NumTraces)
+			assert.Equal(t, base.Add(time.Hour), backend.queries[0].StartTimeMax)
+			assert.True(t, backend.queries[1].StartTimeMax.Equal(base.Add(test.cursor+time.Microsecond)))
+			assert.Empty(t, backend.queries[1].PageToken)
+		})
+	}
+}
+
+func TestPaginateBySortKeyErrors(t *testing.T) {
+	_, err := PaginateBySortKey(context.Background(), nil, &TraceQueryParameters{PageToken: "!!!"}, ByMatchingSpanStartTime)
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+
+	backendErr := errors.New("backend error")
+	_, err = PaginateBySortKey(context.Background(), func(context.Context, *TraceQueryParameters) ([]*model.Trace, error) {
+		return nil, backendErr
+	}, &TraceQueryParameters{}, ByMatchingSpanStartTime)
+	require.ErrorIs(t, err, backendErr)
+}
+
+type paginatedReader struct {
+	Reader
+	page *TracesPage
+}
+
+func (r paginatedReader) FindTracesPage(context.Context, *TraceQueryParameters) (*TracesPage, error) {
+	return r.page, nil
+}
+
+func TestFindTracesPageUsesPaginatedReader(t *testing.T) {
+	expected := &TracesPage{NextPageToken: "next"}
+	page, err := FindTracesPage(context.Background(), paginatedReader{page: expected}, &TraceQueryParameters{})
+	require.NoError(t, err)
+	assert.Same(t, expected, page)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// ErrPurgeQueryServiceRequired is returned by PurgeTraces when the query does not select a service.
+var ErrPurgeQueryServiceRequired = errors.New("the service name of the purge query is required, use PurgeAll to delete all the traces")
+
+// Purger permanently deletes the traces from the storage, e.g. to isolate the integration tests
+// or to delete the traces containing the identifiers of a user.
+type Purger interface {
+	// PurgeTraces deletes all the traces matching the query, ignoring query.NumTraces,
+	// and returns the number of deleted traces. The query requires a service name.
+	PurgeTraces(ctx context.Context, query *TraceQueryParameters) (int, error)
+
+	// PurgeAll deletes all the traces of the storage.
+	PurgeAll(ctx context.Context) error
+}
+
+// ValidatePurgeQuery returns an error if the query cannot be used by PurgeTraces.
+func ValidatePurgeQuery(query *TraceQueryParameters) error {
+	if query == nil || query.ServiceName == "" {
+		return ErrPurgeQueryServiceRequired
+	}
+	return nil
+}
+
+// PurgeFoundTraces deletes the traces found by findTraceIDs for the query, and returns the number of
+// deleted traces. As the results of the searches are limited, it searches again after each delete
+// until no new trace is found.
+func PurgeFoundTraces(
+	ctx context.Context,
+	query *TraceQueryParameters,
+	findTraceIDs func(ctx context.Context, query *TraceQueryParameters) ([]model.TraceID, error),
+	deleteTraces func(ctx context.Context, traceIDs []model.TraceID) error,
+) (int, error) {
+	if err := ValidatePurgeQuery(query); err != nil {
+		return 0, err
+	}
+	// the deleted traces can still be found by the backends with eventually consistent indexes
+	deleted := make(map[model.TraceID]struct{})
+	for {
+		traceIDs, err := findTraceIDs(ctx, query)
+		if err != nil {
+			return len(deleted), err
+		}
+		var found []model.TraceID
+		for _, traceID := range traceIDs {
+			if _, ok := deleted[traceID]; !ok {
+				found = append(found, traceID)
+			}
+		}
+		if len(found) == 0 {
+			return len(deleted), nil
+		}
+		if err := deleteTraces(ctx, found); err != nil {
+			return len(deleted), err
+		}
+		for _, traceID := range found {
+			deleted[traceID] = struct{}{}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestValidatePurgeQuery(t *testing.T) {
+	require.ErrorIs(t, ValidatePurgeQuery(nil), ErrPurgeQueryServiceRequired)
+	require.ErrorIs(t, ValidatePurgeQuery(&TraceQueryParameters{Tags: map[string]string{"user.id": "42"}}), ErrPurgeQueryServiceRequired)
+	require.NoError(t, ValidatePurgeQuery(&TraceQueryParameters{ServiceName: "frontend"}))
+}
+
+func TestPurgeFoundTraces(t *testing.T) {
+	// the searches are limited to 2 traces, and the first deleted trace is still found by the second search
+	results := [][]model.TraceID{
+		{model.NewTraceID(0, 1), model.NewTraceID(0, 2)},
+		{model.NewTraceID(0, 1), model.NewTraceID(0, 3)},
+		{model.NewTraceID(0, 1)},
+	}
+	var searches int
+	var deleted []model.TraceID
+	query := &TraceQueryParameters{ServiceName: "frontend", NumTraces: 2}
+	count, err := PurgeFoundTraces(context.Background(), query,
+		func(_ context.Context, q *TraceQueryParameters) ([]model.TraceID, error) {
+			assert.Same(t, query, q)
+			searches++
+			return results[searches-1], nil
+		},
+		func(_ context.Context, traceIDs []model.TraceID) error {
+			deleted = append(deleted, traceIDs...)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 3, searches)
+	assert.Equal(t, []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2), model.NewTraceID(0, 3)}, deleted)
+}
+
+func TestPurgeFoundTracesErrors(t *testing.T) {
+	found := func(context.Context, *TraceQueryParameters) ([]model.TraceID, error) {
+		return []model.TraceID{model.NewTraceID(0, 1)}, nil
+	}
+	failed := func(context.Context, *TraceQueryParameters) ([]model.TraceID, error) {
+		return nil, assert.AnError
+	}
+	deleted := func(context.Context, []model.TraceID) error { return nil }
+	notDeleted := func(context.Context, []model.TraceID) error { return assert.AnError }
+	query := &TraceQueryParameters{ServiceName: "frontend"}
+
+	_, err := PurgeFoundTraces(context.Background(), &TraceQueryParameters{}, found, deleted)
+	require.ErrorIs(t, err, ErrPurgeQueryServiceRequired)
+	_, err = PurgeFoundTraces(context.Background(), query, failed, deleted)
+	require.ErrorIs(t, err, assert.AnError)
+	count, err := PurgeFoundTraces(context.Background(), query, found, notDeleted)
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// defaultREDScanTraces is the number of traces of each service scanned by the in-process
+// fallback when REDQueryParameters.MaxTraces is not set.
+const defaultREDScanTraces = 1000
+
+// ErrREDStepNotPositive is returned when a RED metrics query does not have a positive step.
+var ErrREDStepNotPositive = errors.New("step must be positive")
+
+// REDQueryParameters contains the parameters of a RED (rate, errors, duration) metrics query.
+type REDQueryParameters struct {
+	ServiceNames     []string
+	GroupByOperation bool
+	// SpanKinds are the span kinds to include, e.g. "server". All spans are included if empty.
+	SpanKinds []string
+	StartTime time.Time
+	EndTime   time.Time
+	// Step is the duration covered by each point of the series.
+	Step time.Duration
+	// Quantile of the span durations reported as the latency of the points, between 0 and 1.
+	Quantile float64
+	// MaxTraces limits the number of traces of each service read by readers that cannot
+	// aggregate spans natively and compute the metrics from a sample instead.
+	MaxTraces int
+}
+
+// REDSeries are the RED metrics of the spans of a service, or of an operation of a service.
+type REDSeries struct {
+	ServiceName string
+	// OperationName is only set if the query groups the metrics by operation.
+	OperationName string
+	Points        []REDPoint
+}
+
+// REDPoint holds the RED metrics of the spans started within a step of the query.
+// Steps without spans have no point.
+type REDPoint struct {
+	// Timestamp is the end of the step.
+	Timestamp time.Time
+	Calls     int64
+	Errors    int64
+	Latency   time.Duration
+}
+
+// REDReader is an additional interface that can be implemented by a Reader
+// whose backend is able to aggregate RED metrics without loading the spans.
+type REDReader interface {
+	GetREDMetrics(ctx context.Context, query *REDQueryParameters) ([]REDSeries, error)
+}
+
+// GetREDMetrics returns the RED metrics of the spans matching the query, sorted by service then operation.
+// If the reader implements REDReader it is used directly, otherwise the metrics are computed
+// from the spans of up to query.MaxTraces most recent traces of each service.
+func GetREDMetrics(ctx context.Context, reader Reader, query *REDQueryParameters) ([]REDSeries, error) {
+	if query.Step <= 0 {
+		return nil, ErrREDStepNotPositive
+	}
+	if redReader, ok := reader.(REDReader); ok {
+		return redReader.GetREDMetrics(ctx, query)
+	}
+	numTraces := query.MaxTraces
+	if numTraces <= 0 {
+		numTraces = defaultREDScanTraces
+	}
+	type seriesKey struct {
+		service   string
+		operation string
+	}
+	type stepKey struct {
+		seriesKey
+		step int64
+	}
+	durations := make(map[stepKey][]time.Duration)
+	errorCounts := make(map[stepKey]int64)
+	for _, service := range query.ServiceNames {
+		traces, err := reader.FindTraces(ctx, &TraceQueryParameters{
+			ServiceName:  service,
+			StartTimeMin: query.StartTime,
+			StartTimeMax: query.EndTime,
+			NumTraces:    numTraces,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range traces {
+			for _, span := range trace.Spans {
+				if !matchesREDQuery(span, service, query) {
+					continue
+				}
+				key := stepKey{seriesKey: seriesKey{service: service}, step: redStepOf(span.StartTime, query)}
+				if query.GroupByOperation {
+					key.operation = span.OperationName
+				}
+				durations[key] = append(durations[key], span.Duration)
+				if isErrorSpan(span) {
+					errorCounts[key]++
+				}
+			}
+		}
+	}
+	series := make(map[seriesKey]*REDSeries)
+	for key, d := range durations {
+		s, ok := series[key.seriesKey]
+		if !ok {
+			s = &REDSeries{ServiceName: key.service, OperationName: key.operation}
+			series[key.seriesKey] = s
+		}
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		s.Points = append(s.Points, REDPoint{
+			Timestamp: query.StartTime.Add(time.Duration(key.step+1) * query.Step),
+			Calls:     int64(len(d)),
+			Errors:    errorCounts[key],
+			Latency:   quantile(d, query.Quantile),
+		})
+	}
+	result := make([]REDSeries, 0, len(series))
+	for _, s := range series {
+		sort.Slice(s.Points, func(i, j int) bool { return s.Points[i].Timestamp.Before(s.Points[j].Timestamp) })
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ServiceName != result[j].ServiceName {
+			return result[i].ServiceName < result[j].ServiceName
+		}
+		return result[i].OperationName < result[j].OperationName
+	})
+	return result, nil
+}
+
+func matchesREDQuery(span *model.Span, service string, query *REDQueryParameters) bool {
+	if span.Process == nil || span.Process.ServiceName != service {
+		return false
+	}
+	if span.StartTime.Before(query.StartTime) || !span.StartTime.Before(query.EndTime) {
+		return false
+	}
+	if len(query.SpanKinds) > 0 {
+		kind, _ := span.GetSpanKind()
+		if !slices.Contains(query.SpanKinds, kind.String()) {
+			return false
+		}
+	}
+	return true
+}
+
+// redStepOf returns the index of the step of the query containing t.
+func redStepOf(t time.Time, query *REDQueryParameters) int64 {
+	return int64(t.Sub(query.StartTime) / query.Step)
+}
+
+func isErrorSpan(span *model.Span) bool {
+	tag, ok := model.KeyValues(span.Tags).FindByKey("error")
+	return ok && tag.Bool()
+}
+
+// quantile returns the nearest-rank quantile q of the sorted durations.
+func quantile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	rank = max(1, min(rank, len(sorted)))
+	return sorted[rank-1]
+}
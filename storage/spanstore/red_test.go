@@ -0,0 +1,109 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestGetREDMetricsFallback(t *testing.T) {
+	start := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	svcA := &model.Process{ServiceName: "a"}
+	svcB := &model.Process{ServiceName: "b"}
+	server := model.String("span.kind", "server")
+	failed := model.Bool("error", true)
+	reader := &tracesReader{traces: []*model.Trace{
+		{Spans: []*model.Span{
+			{Process: svcA, OperationName: "op1", StartTime: start, Duration: time.Millisecond, Tags: model.KeyValues{server}},
+			{Process: svcA, OperationName: "op2", StartTime: start.Add(30 * time.Second), Duration: 3 * time.Millisecond, Tags: model.KeyValues{server, failed}},
+			{Process: svcA, OperationName: "op1", StartTime: start.Add(90 * time.Second), Duration: 2 * time.Millisecond, Tags: model.KeyValues{server}},
+			// a client span
+			{Process: svcA, OperationName: "op1", StartTime: start, Duration: time.Second},
+			// another service
+			{Process: svcB, OperationName: "op1", StartTime: start, Duration: time.Second, Tags: model.KeyValues{server}},
+			// out of the query range
+			{Process: svcA, OperationName: "op1", StartTime: start.Add(time.Hour), Duration: time.Second, Tags: model.KeyValues{server}},
+		}},
+	}}
+	query := &REDQueryParameters{
+		ServiceNames: []string{"a"},
+		SpanKinds:    []string{"server"},
+		StartTime:    start,
+		EndTime:      start.Add(2 * time.Minute),
+		Step:         time.Minute,
+		Quantile:     0.5,
+	}
+
+	series, err := GetREDMetrics(context.Background(), reader, query)
+	require.NoError(t, err)
+	assert.Equal(t, []REDSeries{{
+		ServiceName: "a",
+		Points: []REDPoint{
+			{Timestamp: start.Add(time.Minute), Calls: 2, Errors: 1, Latency: time.Millisecond},
+			{Timestamp: start.Add(2 * time.Minute), Calls: 1, Latency: 2 * time.Millisecond},
+		},
+	}}, series)
+	assert.Equal(t, defaultREDScanTraces, reader.query.NumTraces)
+	assert.Equal(t, "a", reader.query.ServiceName)
+
+	query.GroupByOperation = true
+	query.MaxTraces = 10
+	series, err = GetREDMetrics(context.Background(), reader, query)
+	require.NoError(t, err)
+	assert.Equal(t, []REDSeries{
+		{ServiceName: "a", OperationName: "op1", Points: []REDPoint{
+			{Timestamp: start.Add(time.Minute), Calls: 1, Latency: time.Millisecond},
+			{Timestamp: start.Add(2 * time.Minute), Calls: 1, Latency: 2 * time.Millisecond},
+		}},
+		{ServiceName: "a", OperationName: "op2", Points: []REDPoint{
+			{Timestamp: start.Add(time.Minute), Calls: 1, Errors: 1, Latency: 3 * time.Millisecond},
+		}},
+	}, series)
+	assert.Equal(t, 10, reader.query.NumTraces)
+}
+
+func TestGetREDMetricsErrors(t *testing.T) {
+	_, err := GetREDMetrics(context.Background(), &tracesReader{}, &REDQueryParameters{})
+	require.ErrorIs(t, err, ErrREDStepNotPositive)
+
+	readerErr := errors.New("reader error")
+	_, err = GetREDMetrics(context.Background(), &tracesReader{err: readerErr}, &REDQueryParameters{
+		ServiceNames: []string{"a"},
+		Step:         time.Minute,
+	})
+	require.ErrorIs(t, err, readerErr)
+}
+
+type redReader struct {
+	Reader
+	series []REDSeries
+}
+
+func (r redReader) GetREDMetrics(context.Context, *REDQueryParameters) ([]REDSeries, error) {
+	return r.series, nil
+}
+
+func TestGetREDMetricsUsesREDReader(t *testing.T) {
+	expected := []REDSeries{{ServiceName: "a"}}
+	series, err := GetREDMetrics(context.Background(), redReader{series: expected}, &REDQueryParameters{Step: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, expected, series)
+}
+
+func TestQuantile(t *testing.T) {
+	durations := []time.Duration{1, 2, 3, 4}
+	assert.Equal(t, time.Duration(0), quantile(nil, 0.5))
+	assert.Equal(t, time.Duration(1), quantile(durations, 0))
+	assert.Equal(t, time.Duration(2), quantile(durations, 0.5))
+	assert.Equal(t, time.Duration(4), quantile(durations, 0.99))
+	assert.Equal(t, time.Duration(4), quantile(durations, 1))
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"time"
+)
+
+// retentionPolicyName restricts the names of the policies to the characters allowed in the index names.
+var retentionPolicyName = regexp.MustCompile(`^[a-z0-9][a-z0-9_]*$`)
+
+// RetentionPolicy stores the spans of specific services or tenants with their own TTL,
+// instead of the default TTL of the storage backend.
+type RetentionPolicy struct {
+	// Name identifies the policy, e.g. in the names of the indices of the backends using one index per policy.
+	Name string
+	// TTL is the duration after which the spans are deleted.
+	TTL time.Duration
+	// Services are the names of the services whose spans match the policy, all the services if empty.
+	Services []string
+	// Tenants are the tenants whose spans match the policy, all the tenants if empty.
+	Tenants []string
+}
+
+type retentionPolicyJSON struct {
+	Name     string   `json:"name"`
+	TTL      string   `json:"ttl"`
+	Services []string `json:"services,omitempty"`
+	Tenants  []string `json:"tenants,omitempty"`
+}
+
+// ParseRetentionPolicies parses the retention policies from a JSON object, e.g.
+// {"policies": [{"name": "payments", "ttl": "2160h", "services": ["payments"], "tenants": ["acme"]}]}.
+// The spans are stored with the first matching policy, or with the default TTL of the backend.
+func ParseRetentionPolicies(data []byte) ([]RetentionPolicy, error) {
+	var file struct {
+		Policies []retentionPolicyJSON `json:"policies"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cannot parse the retention policies: %w", err)
+	}
+	names := make(map[string]struct{}, len(file.Policies))
+	policies := make([]RetentionPolicy, 0, len(file.Policies))
+	for _, p := range file.Policies {
+		if !retentionPolicyName.MatchString(p.Name) {
+			return nil, fmt.Errorf("invalid retention policy name %q, expecting lowercase letters, digits and underscores", p.Name)
+		}
+		if _, ok := names[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate retention policy %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		ttl, err := time.ParseDuration(p.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TTL of retention policy %q: %w", p.Name, err)
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("invalid TTL %v of retention policy %q, expecting a positive duration", ttl, p.Name)
+		}
+		policies = append(policies, RetentionPolicy{
+			Name:     p.Name,
+			TTL:      ttl,
+			Services: p.Services,
+			Tenants:  p.Tenants,
+		})
+	}
+	return policies, nil
+}
+
+func (p *RetentionPolicy) matches(tenant, service string) bool {
+	return matchesAny(p.Tenants, tenant) && matchesAny(p.Services, service)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// RetentionPolicies holds the retention policies shared by the span writers, so that they can be
+// replaced when their configuration is reloaded. A nil *RetentionPolicies has no policies.
+type RetentionPolicies struct {
+	policies atomic.Pointer[[]RetentionPolicy]
+}
+
+// NewRetentionPolicies creates RetentionPolicies.
+func NewRetentionPolicies(policies []RetentionPolicy) *RetentionPolicies {
+	r := &RetentionPolicies{}
+	r.SetPolicies(policies)
+	return r
+}
+
+// SetPolicies replaces the retention policies. The spans already written keep their previous TTL.
+func (r *RetentionPolicies) SetPolicies(policies []RetentionPolicy) {
+	r.policies.Store(&policies)
+}
+
+// Policies returns the current retention policies.
+func (r *RetentionPolicies) Policies() []RetentionPolicy {
+	if r == nil {
+		return nil
+	}
+	return *r.policies.Load()
+}
+
+// Match returns the first retention policy matching the tenant and the service, or nil
+// if the spans must be stored with the default TTL of the backend.
+func (r *RetentionPolicies) Match(tenant, service string) *RetentionPolicy {
+	policies := r.Policies()
+	for i := range policies {
+		if policies[i].matches(tenant, service) {
+			return &policies[i]
+		}
+	}
+	return nil
+}
+
+// TTL returns the TTL of the first retention policy matching the tenant and the service,
+// or defaultTTL if no policy matches.
+func (r *RetentionPolicies) TTL(tenant, service string, defaultTTL time.Duration) time.Duration {
+	if policy := r.Match(tenant, service); policy != nil {
+		return policy.TTL
+	}
+	return defaultTTL
+}
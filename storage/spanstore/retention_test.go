@@ -0,0 +1,68 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetentionPolicies(t *testing.T) {
+	policies, err := ParseRetentionPolicies([]byte(`{"policies": [
+		{"name": "payments", "ttl": "2160h", "services": ["payments"]},
+		{"name": "acme", "ttl": "720h", "tenants": ["acme"]}
+	]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []RetentionPolicy{
+		{Name: "payments", TTL: 2160 * time.Hour, Services: []string{"payments"}},
+		{Name: "acme", TTL: 720 * time.Hour, Tenants: []string{"acme"}},
+	}, policies)
+}
+
+func TestParseRetentionPoliciesErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{data: `[]`, err: "cannot parse the retention policies"},
+		{data: `{"policies": [{"name": "Payments", "ttl": "1h"}]}`, err: `invalid retention policy name "Payments"`},
+		{data: `{"policies": [{"ttl": "1h"}]}`, err: `invalid retention policy name ""`},
+		{data: `{"policies": [{"name": "a", "ttl": "1h"}, {"name": "a", "ttl": "2h"}]}`, err: `duplicate retention policy "a"`},
+		{data: `{"policies": [{"name": "a", "ttl": "1 day"}]}`, err: `invalid TTL of retention policy "a"`},
+		{data: `{"policies": [{"name": "a", "ttl": "-1h"}]}`, err: `invalid TTL -1h0m0s of retention policy "a"`},
+	}
+	for _, test := range tests {
+		t.Run(test.data, func(t *testing.T) {
+			_, err := ParseRetentionPolicies([]byte(test.data))
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func TestRetentionPoliciesMatch(t *testing.T) {
+	r := NewRetentionPolicies([]RetentionPolicy{
+		{Name: "acme_payments", TTL: 3 * time.Hour, Services: []string{"payments"}, Tenants: []string{"acme"}},
+		{Name: "payments", TTL: 2 * time.Hour, Services: []string{"payments", "billing"}},
+		{Name: "acme", TTL: time.Hour, Tenants: []string{"acme"}},
+	})
+	assert.Equal(t, "acme_payments", r.Match("acme", "payments").Name)
+	assert.Equal(t, "payments", r.Match("other", "billing").Name)
+	assert.Equal(t, "acme", r.Match("acme", "frontend").Name)
+	assert.Nil(t, r.Match("other", "frontend"))
+	assert.Equal(t, 2*time.Hour, r.TTL("", "payments", time.Minute))
+	assert.Equal(t, time.Minute, r.TTL("", "frontend", time.Minute))
+
+	r.SetPolicies(nil)
+	assert.Nil(t, r.Match("acme", "payments"))
+}
+
+func TestNilRetentionPolicies(t *testing.T) {
+	var r *RetentionPolicies
+	assert.Nil(t, r.Policies())
+	assert.Nil(t, r.Match("acme", "payments"))
+	assert.Equal(t, time.Minute, r.TTL("acme", "payments", time.Minute))
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// StreamingReader is an additional interface that can be implemented by a Reader
+// able to return the spans of a trace incrementally, without holding the whole
+// trace in memory.
+type StreamingReader interface {
+	// StreamTrace calls yield with consecutive batches of the spans of the trace.
+	// It returns ErrTraceNotFound, before yield is first called, if the trace does
+	// not exist, and stops at the first error returned by yield.
+	StreamTrace(ctx context.Context, traceID model.TraceID, yield func(spans []*model.Span) error) error
+}
+
+// StreamTrace streams the spans of the trace from the reader, passing them to yield in
+// batches of at most batchSize spans; a non-positive batchSize does not limit the batches.
+// If the reader does not implement StreamingReader, the trace is loaded with GetTrace.
+func StreamTrace(
+	ctx context.Context,
+	reader Reader,
+	traceID model.TraceID,
+	batchSize int,
+	yield func(spans []*model.Span) error,
+) error {
+	yieldBatches := func(spans []*model.Span) error {
+		for len(spans) > 0 {
+			n := len(spans)
+			if batchSize > 0 && batchSize < n {
+				n = batchSize
+			}
+			if err := yield(spans[:n]); err != nil {
+				return err
+			}
+			spans = spans[n:]
+		}
+		return nil
+	}
+	if streaming, ok := reader.(StreamingReader); ok {
+		return streaming.StreamTrace(ctx, traceID, yieldBatches)
+	}
+	trace, err := reader.GetTrace(ctx, traceID)
+	if err != nil {
+		return err
+	}
+	return yieldBatches(trace.Spans)
+}
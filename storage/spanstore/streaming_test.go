@@ -0,0 +1,84 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type traceReader struct {
+	Reader
+	trace *model.Trace
+	err   error
+}
+
+func (r traceReader) GetTrace(context.Context, model.TraceID) (*model.Trace, error) {
+	return r.trace, r.err
+}
+
+type streamingReader struct {
+	Reader
+	batches [][]*model.Span
+}
+
+func (r streamingReader) StreamTrace(_ context.Context, _ model.TraceID, yield func([]*model.Span) error) error {
+	for _, batch := range r.batches {
+		if err := yield(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newSpans(n int) []*model.Span {
+	spans := make([]*model.Span, n)
+	for i := range spans {
+		spans[i] = &model.Span{SpanID: model.NewSpanID(uint64(i + 1))}
+	}
+	return spans
+}
+
+func collectBatchSizes(t *testing.T, reader Reader, batchSize int) []int {
+	var sizes []int
+	err := StreamTrace(context.Background(), reader, model.TraceID{}, batchSize, func(spans []*model.Span) error {
+		sizes = append(sizes, len(spans))
+		return nil
+	})
+	require.NoError(t, err)
+	return sizes
+}
+
+func TestStreamTraceFromGetTrace(t *testing.T) {
+	reader := traceReader{trace: &model.Trace{Spans: newSpans(5)}}
+	assert.Equal(t, []int{2, 2, 1}, collectBatchSizes(t, reader, 2))
+	assert.Equal(t, []int{5}, collectBatchSizes(t, reader, 0))
+	assert.Empty(t, collectBatchSizes(t, traceReader{trace: &model.Trace{}}, 2))
+}
+
+func TestStreamTraceFromStreamingReader(t *testing.T) {
+	reader := streamingReader{batches: [][]*model.Span{newSpans(3), newSpans(1)}}
+	assert.Equal(t, []int{2, 1, 1}, collectBatchSizes(t, reader, 2))
+	assert.Equal(t, []int{3, 1}, collectBatchSizes(t, reader, 0))
+}
+
+func TestStreamTraceErrors(t *testing.T) {
+	err := StreamTrace(context.Background(), traceReader{err: ErrTraceNotFound}, model.TraceID{}, 2, nil)
+	require.ErrorIs(t, err, ErrTraceNotFound)
+
+	yieldErr := errors.New("yield error")
+	calls := 0
+	err = StreamTrace(context.Background(), traceReader{trace: &model.Trace{Spans: newSpans(5)}}, model.TraceID{}, 2, func([]*model.Span) error {
+		calls++
+		return yieldErr
+	})
+	require.ErrorIs(t, err, yieldErr)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultTagLimit is the number of tag keys or values returned when TagQueryParameters.Limit is not set.
+const DefaultTagLimit = 100
+
+// ErrTagsNotSupported is returned by GetTagKeys and GetTagValues for the readers not implementing TagReader.
+var ErrTagsNotSupported = errors.New("the span storage does not support listing the tags")
+
+// TagQueryParameters contains the parameters of the queries of the tag keys and values,
+// used to suggest them in the search form.
+type TagQueryParameters struct {
+	ServiceName string
+	// Key is the key of the tags whose values are returned. It is ignored by GetTagKeys.
+	Key string
+	// Prefix restricts the keys, respectively the values, to the ones starting with it.
+	Prefix       string
+	StartTimeMin time.Time
+	StartTimeMax time.Time
+	// Limit is the maximum number of keys or values returned, DefaultTagLimit if it is not positive.
+	Limit int
+}
+
+// EffectiveLimit returns the maximum number of keys or values returned by the query.
+func (q *TagQueryParameters) EffectiveLimit() int {
+	if q.Limit <= 0 {
+		return DefaultTagLimit
+	}
+	return q.Limit
+}
+
+// TagReader is an additional interface that can be implemented by a Reader able to list
+// the keys and values of the tags of the spans of a service without loading the spans.
+// The tags are the searchable ones, i.e. the span tags, the process tags and the log fields.
+type TagReader interface {
+	// GetTagKeys returns the sorted keys of the tags of the spans of the service.
+	GetTagKeys(ctx context.Context, query *TagQueryParameters) ([]string, error)
+
+	// GetTagValues returns the sorted values of the tags of the spans of the service with the key.
+	GetTagValues(ctx context.Context, query *TagQueryParameters) ([]string, error)
+}
+
+// tagSupport is implemented by the decorators of the readers, which implement TagReader
+// whether the readers they decorate do or not.
+type tagSupport interface {
+	SupportsTags() bool
+}
+
+// SupportsTags returns whether the reader is able to list the tags.
+func SupportsTags(reader Reader) bool {
+	if s, ok := reader.(tagSupport); ok {
+		return s.SupportsTags()
+	}
+	_, ok := reader.(TagReader)
+	return ok
+}
+
+// GetTagKeys returns the keys of the tags from the reader, or ErrTagsNotSupported
+// if the reader does not implement TagReader.
+func GetTagKeys(ctx context.Context, reader Reader, query *TagQueryParameters) ([]string, error) {
+	if tagReader, ok := reader.(TagReader); ok {
+		return tagReader.GetTagKeys(ctx, query)
+	}
+	return nil, ErrTagsNotSupported
+}
+
+// GetTagValues returns the values of the tags from the reader, or ErrTagsNotSupported
+// if the reader does not implement TagReader.
+func GetTagValues(ctx context.Context, reader Reader, query *TagQueryParameters) ([]string, error) {
+	if tagReader, ok := reader.(TagReader); ok {
+		return tagReader.GetTagValues(ctx, query)
+	}
+	return nil, ErrTagsNotSupported
+}
+
+// SelectTags returns the sorted keys or values starting with the prefix of the query,
+// up to the limit of the query.
+func SelectTags(tags map[string]struct{}, query *TagQueryParameters) []string {
+	selected := make([]string, 0, len(tags))
+	for tag := range tags {
+		if strings.HasPrefix(tag, query.Prefix) {
+			selected = append(selected, tag)
+		}
+	}
+	sort.Strings(selected)
+	if limit := query.EffectiveLimit(); len(selected) > limit {
+		selected = selected[:limit]
+	}
+	return selected
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tagReader struct {
+	Reader
+}
+
+func (tagReader) GetTagKeys(context.Context, *TagQueryParameters) ([]string, error) {
+	return []string{"http.method"}, nil
+}
+
+func (tagReader) GetTagValues(_ context.Context, query *TagQueryParameters) ([]string, error) {
+	return []string{query.Key + "=GET"}, nil
+}
+
+type tagDecorator struct {
+	tagReader
+	supported bool
+}
+
+func (d tagDecorator) SupportsTags() bool {
+	return d.supported
+}
+
+func TestSupportsTags(t *testing.T) {
+	assert.True(t, SupportsTags(tagReader{}))
+	assert.False(t, SupportsTags(traceReader{}))
+	assert.True(t, SupportsTags(tagDecorator{supported: true}))
+	assert.False(t, SupportsTags(tagDecorator{supported: false}))
+}
+
+func TestGetTagKeysAndValues(t *testing.T) {
+	query := &TagQueryParameters{ServiceName: "svc", Key: "http.method"}
+	keys, err := GetTagKeys(context.Background(), tagReader{}, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http.method"}, keys)
+	values, err := GetTagValues(context.Background(), tagReader{}, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http.method=GET"}, values)
+
+	_, err = GetTagKeys(context.Background(), traceReader{}, query)
+	require.ErrorIs(t, err, ErrTagsNotSupported)
+	_, err = GetTagValues(context.Background(), traceReader{}, query)
+	require.ErrorIs(t, err, ErrTagsNotSupported)
+}
+
+func TestSelectTags(t *testing.T) {
+	tags := map[string]struct{}{"http.url": {}, "http.method": {}, "error": {}, "http.status_code": {}}
+	assert.Equal(t, []string{"error", "http.method", "http.status_code", "http.url"}, SelectTags(tags, &TagQueryParameters{}))
+	assert.Equal(t, []string{"http.method", "http.status_code"}, SelectTags(tags, &TagQueryParameters{Prefix: "http.", Limit: 2}))
+	assert.Empty(t, SelectTags(tags, &TagQueryParameters{Prefix: "db."}))
+}
+
+func TestTagQueryEffectiveLimit(t *testing.T) {
+	assert.Equal(t, DefaultTagLimit, (&TagQueryParameters{}).EffectiveLimit())
+	assert.Equal(t, 5, (&TagQueryParameters{Limit: 5}).EffectiveLimit())
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// GetTraceParameters contains the parameters of a trace fetch. StartTime and EndTime are
+// optional hints of the time range of the spans of the trace, a zero value meaning unbounded.
+type GetTraceParameters struct {
+	TraceID   model.TraceID
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// TimeRangeReader is an additional interface that can be implemented by a Reader
+// able to restrict the partitions or indices searched for a trace to a time range.
+type TimeRangeReader interface {
+	// GetTraceInTimeRange returns the trace like GetTrace, searching only the spans
+	// started within the time range of the query; the spans outside of it may be omitted.
+	GetTraceInTimeRange(ctx context.Context, query GetTraceParameters) (*model.Trace, error)
+}
+
+// GetTraceInTimeRange returns the trace from the reader, restricted to the time range of the query
+// if the reader implements TimeRangeReader. Otherwise, or if the query has no time range, the whole
+// trace is loaded with GetTrace.
+func GetTraceInTimeRange(ctx context.Context, reader Reader, query GetTraceParameters) (*model.Trace, error) {
+	if reader, ok := reader.(TimeRangeReader); ok && !(query.StartTime.IsZero() && query.EndTime.IsZero()) {
+		return reader.GetTraceInTimeRange(ctx, query)
+	}
+	return reader.GetTrace(ctx, query.TraceID)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2024 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+type timeRangeReader struct {
+	traceReader
+	query GetTraceParameters
+}
+
+func (r *timeRangeReader) GetTraceInTimeRange(_ context.Context, query GetTraceParameters) (*model.Trace, error) {
+	r.query = query
+	return &model.Trace{Spans: newSpans(1)}, nil
+}
+
+func TestGetTraceInTimeRange(t *testing.T) {
+	start := time.Unix(1000, 0)
+	end := time.Unix(2000, 0)
+	reader := &timeRangeReader{traceReader: traceReader{trace: &model.Trace{Spans: newSpans(3)}}}
+
+	trace, err := GetTraceInTimeRange(context.Background(), reader, GetTraceParameters{TraceID: model.NewTraceID(0, 1)})
+	require.NoError(t, err)
+	assert.Len(t, trace.Spans, 3, "a query without time range uses GetTrace")
+
+	query := GetTraceParameters{TraceID: model.NewTraceID(0, 1), StartTime: start, EndTime: end}
+	trace, err = GetTraceInTimeRange(context.Background(), reader, query)
+	require.NoError(t, err)
+	assert.Len(t, trace.Spans, 1)
+	assert.Equal(t, query, reader.query)
+
+	trace, err = GetTraceInTimeRange(context.Background(), reader.traceReader, query)
+	require.NoError(t, err)
+	assert.Len(t, trace.Spans, 3, "a reader without TimeRangeReader uses GetTrace")
+}